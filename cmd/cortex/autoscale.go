@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/autoscale"
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+// runAutoscaler steps the concurrency limits every autoscale interval while
+// autoscaling is enabled, recording each change as a concurrency_scaled
+// health event.
+func runAutoscaler(ctx context.Context, st *store.Store, cfgManager config.ConfigManager, ctrl *autoscale.Controller, logger *slog.Logger) {
+	ticker := time.NewTicker(cfgManager.Get().General.Autoscale.Interval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cfg := cfgManager.Get()
+			if cfg.General.Autoscale.Enabled {
+				stepAutoscaler(st, cfg, ctrl, time.Now(), logger)
+			}
+		}
+	}
+}
+
+func stepAutoscaler(st *store.Store, cfg *config.Config, ctrl *autoscale.Controller, now time.Time, logger *slog.Logger) {
+	signals, err := autoscaleSignals(st, cfg, now)
+	if err != nil {
+		logger.Warn("failed to read autoscale signals", "error", err)
+		return
+	}
+	d := ctrl.Step(cfg.General.Autoscale, signals, now)
+	if !d.Changed() {
+		return
+	}
+	details := fmt.Sprintf("coders %d -> %d, reviewers %d -> %d: %s", d.From.Coders, d.To.Coders, d.From.Reviewers, d.To.Reviewers, d.Reason)
+	logger.Info("concurrency limits scaled", "coders", d.To.Coders, "reviewers", d.To.Reviewers, "reason", d.Reason)
+	if err := st.RecordHealthEvent("concurrency_scaled", details); err != nil {
+		logger.Warn("failed to record concurrency scaling", "error", err)
+	}
+}
+
+// autoscaleSignals gathers the overflow queue by role, the failure rate over
+// the autoscale window and today's spend against the daily cost cap.
+func autoscaleSignals(st *store.Store, cfg *config.Config, now time.Time) (autoscale.Signals, error) {
+	s := autoscale.Signals{Queued: map[string]int{}}
+	queued, err := st.ListOverflowQueue()
+	if err != nil {
+		return s, err
+	}
+	for _, item := range queued {
+		s.Queued[item.Role]++
+	}
+
+	since := now.Add(-cfg.General.Autoscale.Window.Duration)
+	if s.Dispatches, err = st.CountDispatchesSince(since, nil); err != nil {
+		return s, err
+	}
+	if s.Failed, err = st.CountDispatchesSince(since, []string{"failed"}); err != nil {
+		return s, err
+	}
+
+	if dailyCap := cfg.Dispatch.CostControl.DailyCostCapUSD; dailyCap > 0 {
+		y, m, d := now.UTC().Date()
+		if s.SpentToday, err = st.GetTotalCostSince("", time.Date(y, m, d, 0, 0, 0, 0, time.UTC)); err != nil {
+			return s, err
+		}
+		s.DailyCap = dailyCap
+	}
+	return s, nil
+}
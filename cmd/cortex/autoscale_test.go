@@ -0,0 +1,58 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/autoscale"
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestStepAutoscaler(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{General: config.General{
+		MaxConcurrentCoders:    2,
+		MaxConcurrentReviewers: 1,
+		Autoscale: config.GeneralAutoscale{
+			Enabled: true, Step: 1, QueueDepth: 1, Window: config.Duration{Duration: time.Hour},
+			MinCoders: 1, MaxCoders: 4, MinReviewers: 1, MaxReviewers: 1,
+			MaxFailureRate: 0.5, MaxCostBurn: 0.8,
+		},
+	}}
+	ctrl := autoscale.New(cfg.General)
+	if _, err := st.EnqueueOverflowItem("b-1", "api", "coder", "api-coder", 1, "role_limit"); err != nil {
+		t.Fatal(err)
+	}
+
+	stepAutoscaler(st, cfg, ctrl, time.Now(), logger)
+	if limits, _, _ := ctrl.Current(cfg.General); limits != (autoscale.Limits{Coders: 3, Reviewers: 1}) {
+		t.Fatalf("limits = %+v, want coders raised to 3", limits)
+	}
+	events, err := st.GetRecentHealthEvents(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].EventType != "concurrency_scaled" || !strings.Contains(events[0].Details, "coders 2 -> 3, reviewers 1 -> 1: 1 coder item(s) queued") {
+		t.Fatalf("events = %+v", events)
+	}
+
+	// Reviewers are already at their minimum, so an empty queue only moves
+	// the coders back.
+	if _, err := st.RemoveOverflowItem("b-1"); err != nil {
+		t.Fatal(err)
+	}
+	stepAutoscaler(st, cfg, ctrl, time.Now(), logger)
+	if limits, reason, _ := ctrl.Current(cfg.General); limits != (autoscale.Limits{Coders: 2, Reviewers: 1}) || !strings.Contains(reason, "no coder work queued") {
+		t.Fatalf("limits = %+v (%s), want coders back to 2", limits, reason)
+	}
+}
@@ -1,11 +1,18 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/beads"
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
 )
 
 const truncatedSuffix = "... [truncated by cortex normalize-beads]"
@@ -17,6 +24,7 @@ type normalizeBeadsResult struct {
 	ChangedRows   int
 	BytesBefore   int
 	BytesAfter    int
+	TrimmedIDs    []string // bead IDs of the changed rows
 }
 
 type normalizePolicy struct {
@@ -60,6 +68,12 @@ func normalizeOversizedBeadsJSONL(path string, maxBytes int, dryRun bool) (norma
 		}
 		if changed {
 			result.ChangedRows++
+			var row struct {
+				ID string `json:"id"`
+			}
+			if json.Unmarshal([]byte(normalizedLine), &row) == nil {
+				result.TrimmedIDs = append(result.TrimmedIDs, row.ID)
+			}
 			out = append(out, normalizedLine)
 			continue
 		}
@@ -216,3 +230,89 @@ func truncateText(s string, maxChars int) string {
 func issuesJSONLPath(beadsDir string) string {
 	return filepath.Join(strings.TrimSpace(beadsDir), "issues.jsonl")
 }
+
+// runBeadsGuard keeps every project's issues.jsonl free of oversized rows, the
+// background counterpart of -normalize-beads-project. Every interval it
+// rescans the files that changed size since the last pass. It backs up a
+// file with oversized rows into backupDir, trims the rows, re-imports the
+// file so bd's database matches, and records what was trimmed as a
+// beads_normalized health event.
+func runBeadsGuard(ctx context.Context, st *store.Store, cfgManager config.ConfigManager, interval time.Duration, backupDir string, logger *slog.Logger) {
+	sizes := map[string]int64{} // issues.jsonl size at the last pass, by path
+	check := func() {
+		cfg := cfgManager.Get().ShardProjects()
+		for name, project := range cfg.Projects {
+			beadsDir := config.ExpandHome(strings.TrimSpace(project.BeadsDir))
+			if !project.Enabled || beadsDir == "" {
+				continue
+			}
+			path := filepath.Clean(issuesJSONLPath(beadsDir))
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			if last, ok := sizes[path]; ok && last == info.Size() {
+				continue
+			}
+			sizes[path] = info.Size()
+
+			result, backup, err := guardBeadsJSONL(path, cfg.General.BeadsGuardMaxRowBytes, filepath.Join(backupDir, name))
+			if err != nil {
+				logger.Warn("beads guard failed", "project", name, "path", path, "error", err)
+				continue
+			}
+			if result.ChangedRows == 0 {
+				continue
+			}
+			if err := beads.SyncImportCtx(ctx, beadsDir); err != nil {
+				logger.Warn("beads guard re-import failed", "project", name, "error", err)
+			}
+			if info, err := os.Stat(path); err == nil {
+				sizes[path] = info.Size()
+			}
+			details := fmt.Sprintf("%s: trimmed %d oversized row(s) in %s (%s), %d -> %d bytes; backup at %s",
+				name, result.ChangedRows, path, strings.Join(result.TrimmedIDs, ", "), result.BytesBefore, result.BytesAfter, backup)
+			logger.Warn("trimmed oversized beads rows", "project", name, "rows", result.ChangedRows, "ids", result.TrimmedIDs, "backup", backup)
+			if err := st.RecordHealthEvent("beads_normalized", details); err != nil {
+				logger.Warn("failed to record beads normalization", "error", err)
+			}
+		}
+	}
+
+	check()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			check()
+		}
+	}
+}
+
+// guardBeadsJSONL normalizes path when it has rows over maxBytes, first
+// copying it into backupDir. It returns the backup's path, or "" when
+// nothing needed trimming.
+func guardBeadsJSONL(path string, maxBytes int, backupDir string) (normalizeBeadsResult, string, error) {
+	preview, err := normalizeOversizedBeadsJSONL(path, maxBytes, true)
+	if err != nil || preview.ChangedRows == 0 {
+		return preview, "", err
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return preview, "", fmt.Errorf("read issues file: %w", err)
+	}
+	if err := os.MkdirAll(backupDir, 0o755); err != nil {
+		return preview, "", fmt.Errorf("create backup dir: %w", err)
+	}
+	backup := filepath.Join(backupDir, "issues-"+time.Now().UTC().Format("20060102T150405Z")+".jsonl")
+	if err := os.WriteFile(backup, raw, 0o644); err != nil {
+		return preview, "", fmt.Errorf("write backup: %w", err)
+	}
+
+	result, err := normalizeOversizedBeadsJSONL(path, maxBytes, false)
+	return result, backup, err
+}
@@ -106,3 +106,50 @@ func TestNormalizeOversizedBeadsJSONLDryRunDoesNotWrite(t *testing.T) {
 		t.Fatalf("dry-run should not modify file")
 	}
 }
+
+func TestGuardBeadsJSONLBacksUpAndTrims(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "issues.jsonl")
+	backupDir := filepath.Join(t.TempDir(), "backups", "proj")
+
+	small := `{"id":"main-small","title":"Small issue","status":"open"}` + "\n"
+	if err := os.WriteFile(path, []byte(small), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	result, backup, err := guardBeadsJSONL(path, 1000, backupDir)
+	if err != nil || result.ChangedRows != 0 || backup != "" {
+		t.Fatalf("clean file: %+v, %q, %v", result, backup, err)
+	}
+	if _, err := os.Stat(backupDir); !os.IsNotExist(err) {
+		t.Fatalf("clean file was backed up: %v", err)
+	}
+
+	big, err := json.Marshal(map[string]any{"id": "main-big", "status": "open", "notes": strings.Repeat("notes-", 1000)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := string(big) + "\n" + small
+	if err := os.WriteFile(path, []byte(original), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	result, backup, err = guardBeadsJSONL(path, 1000, backupDir)
+	if err != nil {
+		t.Fatalf("guardBeadsJSONL: %v", err)
+	}
+	if result.ChangedRows != 1 || len(result.TrimmedIDs) != 1 || result.TrimmedIDs[0] != "main-big" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	saved, err := os.ReadFile(backup)
+	if err != nil || string(saved) != original {
+		t.Fatalf("backup does not hold the original file: %v", err)
+	}
+	updated, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, line := range strings.Split(strings.TrimSpace(string(updated)), "\n") {
+		if len(line) > 1000 {
+			t.Fatalf("line %d remains oversized (%d bytes)", i+1, len(line))
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/email"
+	"github.com/antigravity-dev/cortex/internal/matrix"
+	"github.com/antigravity-dev/cortex/internal/notify"
+	"github.com/antigravity-dev/cortex/internal/oncall"
+	"github.com/antigravity-dev/cortex/internal/store"
+	"github.com/antigravity-dev/cortex/internal/webhook"
+)
+
+// healthRoutingBatch bounds how many health events one routing pass sends.
+const healthRoutingBatch = 100
+
+// runHealthEventRouting follows the health event stream from the newest
+// event at startup and sends each new event to the destinations
+// health.events routes its severity to, paging the on-call user on critical
+// ones. It runs on the active instance only, so HA standbys do not send
+// every event twice.
+func runHealthEventRouting(ctx context.Context, st *store.Store, cfgManager config.ConfigManager, logger *slog.Logger) {
+	lastID, err := st.LatestHealthEventID()
+	if err != nil {
+		logger.Warn("health event routing disabled", "error", err)
+		return
+	}
+
+	// One throttle for the whole stream, so its room limits hold across passes.
+	mail := email.NewSender(cfgManager.Get().Reporter.Email, func(route string) []string {
+		return cfgManager.Get().EmailRecipients(route)
+	})
+	go mail.Run(ctx, logger)
+	throttle := notify.NewThrottle(cfgManager.Get().Reporter.Throttle, configuredReporterSender{cfgManager, mail})
+	go throttle.Run(ctx, logger)
+
+	route := func() {
+		cfg := cfgManager.Get()
+		routes := cfg.Health.Events
+		if len(routes.Matrix) == 0 && len(routes.Webhooks) == 0 && cfg.OnCall.PagerDuty.RoutingKey == "" {
+			// Nothing is routed; skip what was recorded meanwhile.
+			if id, err := st.LatestHealthEventID(); err == nil {
+				lastID = id
+			}
+			return
+		}
+		events, err := st.GetHealthEventsAfter(lastID, healthRoutingBatch)
+		if err != nil {
+			logger.Warn("failed to read health events for routing", "error", err)
+			return
+		}
+		if len(events) == 0 {
+			return
+		}
+		var sender matrix.Sender
+		if len(routes.Matrix) > 0 {
+			sender = throttle
+		}
+		hooks, err := webhook.NewSender(cfg)
+		if err != nil {
+			logger.Warn("health event webhooks disabled", "error", err)
+		}
+		roster := oncall.New(cfg.OnCall)
+		for _, e := range events {
+			if err := routeHealthEvent(ctx, cfg, e, sender, hooks, roster); err != nil {
+				logger.Warn("failed to route health event", "id", e.ID, "type", e.EventType, "severity", e.Severity, "error", err)
+			}
+			lastID = e.ID
+		}
+	}
+
+	ticker := time.NewTicker(cfgManager.Get().General.TickInterval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			route()
+		}
+	}
+}
+
+// matrixSender is the Matrix sender the reporter would use: the E2E proxy
+// when one is configured, OpenClaw otherwise.
+func matrixSender(cfg *config.Config) matrix.Sender {
+	if cfg.Matrix.E2EProxy != "" {
+		return matrix.NewE2ESender(nil, cfg.Reporter.MatrixBotAccount, cfg.Matrix.E2EProxy)
+	}
+	return matrix.NewOpenClawSender(nil, cfg.Reporter.MatrixBotAccount)
+}
+
+// configuredReporterSender sends through the reporter channel of the
+// current config, so a reload takes effect without restarting the routing
+// loop: email when reporter.channel is email, Matrix otherwise.
+type configuredReporterSender struct {
+	cfgManager config.ConfigManager
+	email      *email.Sender
+}
+
+func (s configuredReporterSender) SendMessage(ctx context.Context, room, message string) error {
+	cfg := s.cfgManager.Get()
+	if cfg.Reporter.Channel == "email" {
+		return s.email.SendMessage(ctx, room, message)
+	}
+	return matrixSender(cfg).SendMessage(ctx, room, message)
+}
+
+// routeHealthEvent sends e to the reporter channel and the webhooks when
+// health.events routes its severity there. The channel gets the project's
+// room, or reporter.default_room for events without a project or room; with
+// reporter.channel = "email" it gets the project's email route instead. A
+// critical event mentions whoever is on call in the channel and pages them; with nobody on
+// call it is posted without a mention and nobody is paged. A nil sender or
+// hooks skips that destination.
+func routeHealthEvent(ctx context.Context, cfg *config.Config, e store.HealthEvent, sender matrix.Sender, hooks *webhook.Sender, roster *oncall.Roster) error {
+	routes := cfg.Health.Events
+	msg := healthEventMessage(e)
+	room := cfg.ResolveRoom(e.Project)
+
+	var errs []error
+	var onCall oncall.User
+	paged := false
+	if e.Severity == store.HealthSeverityCritical {
+		user, ok, err := roster.Current(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("oncall: %w", err))
+		}
+		onCall, paged = user, ok
+	}
+
+	if sender != nil && room != "" && slices.Contains(routes.Matrix, e.Severity) {
+		matrixMsg := msg
+		if paged {
+			matrixMsg = onCall.Mention + " (on call) " + msg
+		}
+		if err := sender.SendMessage(ctx, room, matrixMsg); err != nil {
+			errs = append(errs, fmt.Errorf("matrix: %w", err))
+		}
+	}
+	if hooks != nil && slices.Contains(routes.Webhooks, e.Severity) {
+		err := hooks.SendEvent(ctx, webhook.Event{
+			Project:   e.Project,
+			Room:      room,
+			Message:   msg,
+			Severity:  e.Severity,
+			Timestamp: e.CreatedAt.UTC(),
+		})
+		errs = append(errs, err)
+	}
+	if paged {
+		details := map[string]string{"event_type": e.EventType, "project": e.Project, "bead_id": e.BeadID, "on_call": onCall.Mention}
+		// One incident per event type and project, however often it recurs.
+		if err := roster.Page(ctx, msg, "cortex/"+e.EventType+"/"+e.Project, details); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// healthEventMessage renders e as a one-line report.
+func healthEventMessage(e store.HealthEvent) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] %s", strings.ToUpper(e.Severity), e.EventType)
+	if e.Project != "" {
+		fmt.Fprintf(&b, " (%s)", e.Project)
+	}
+	if e.BeadID != "" {
+		fmt.Fprintf(&b, " bead %s", e.BeadID)
+	}
+	if details := strings.TrimSpace(e.Details); details != "" {
+		b.WriteString(": " + truncateText(details, 500))
+	}
+	return b.String()
+}
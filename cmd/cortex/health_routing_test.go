@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/oncall"
+	"github.com/antigravity-dev/cortex/internal/store"
+	"github.com/antigravity-dev/cortex/internal/webhook"
+)
+
+type recordingSender struct {
+	rooms, messages []string
+}
+
+func (s *recordingSender) SendMessage(_ context.Context, room, message string) error {
+	s.rooms = append(s.rooms, room)
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+func TestRouteHealthEvent(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		posted []webhook.Event
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var e webhook.Event
+		json.Unmarshal(body, &e)
+		mu.Lock()
+		posted = append(posted, e)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{
+		Projects: map[string]config.Project{"api": {Enabled: true, MatrixRoom: "!api:example.org"}},
+		Reporter: config.Reporter{DefaultRoom: "!ops:example.org", Webhooks: []config.ReporterWebhook{{URL: srv.URL}}},
+		Health: config.Health{Events: config.HealthEvents{
+			Matrix:   []string{store.HealthSeverityWarn, store.HealthSeverityCritical},
+			Webhooks: []string{store.HealthSeverityCritical},
+		}},
+		// Two shifts covering the whole day.
+		OnCall: config.OnCall{Shifts: []config.OnCallShift{
+			{User: "@alice:example.org", Hours: "00:00-12:00"},
+			{User: "@alice:example.org", Hours: "12:00-00:00"},
+		}},
+	}
+	hooks, err := webhook.NewSender(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := &recordingSender{}
+
+	for _, e := range []store.HealthEvent{
+		{EventType: "cost_report", Severity: store.HealthSeverityInfo, Details: "monthly report"},
+		{EventType: "health_check_failed", Severity: store.HealthSeverityWarn, Project: "api", Details: "api-smoke failed"},
+		{EventType: "escalation_required", Severity: store.HealthSeverityCritical, BeadID: "b-1", Details: "needs a human"},
+	} {
+		if err := routeHealthEvent(context.Background(), cfg, e, sender, hooks, oncall.New(cfg.OnCall)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if strings.Join(sender.rooms, ",") != "!api:example.org,!ops:example.org" {
+		t.Fatalf("matrix rooms = %v", sender.rooms)
+	}
+	if sender.messages[0] != "[WARN] health_check_failed (api): api-smoke failed" {
+		t.Fatalf("matrix message = %q", sender.messages[0])
+	}
+	if sender.messages[1] != "@alice:example.org (on call) [CRITICAL] escalation_required bead b-1: needs a human" {
+		t.Fatalf("matrix message = %q", sender.messages[1])
+	}
+	if len(posted) != 1 || posted[0].Severity != store.HealthSeverityCritical || posted[0].Room != "!ops:example.org" ||
+		posted[0].Message != "[CRITICAL] escalation_required bead b-1: needs a human" {
+		t.Fatalf("webhook events = %+v", posted)
+	}
+}
+
+func TestRouteHealthEventByEmail(t *testing.T) {
+	cfg := &config.Config{
+		Projects: map[string]config.Project{
+			"api": {Enabled: true, MatrixRoom: "!api:example.org", EmailRecipients: []string{"api@example.org"}},
+		},
+		Reporter: config.Reporter{
+			Channel:     "email",
+			DefaultRoom: "!ops:example.org",
+			Email:       config.ReporterEmail{DefaultRecipients: []string{"ops@example.org"}},
+		},
+		Health: config.Health{Events: config.HealthEvents{Matrix: []string{store.HealthSeverityWarn}}},
+	}
+	sender := &recordingSender{}
+
+	for _, e := range []store.HealthEvent{
+		{EventType: "health_check_failed", Severity: store.HealthSeverityWarn, Project: "api", Details: "api-smoke failed"},
+		{EventType: "host_resources_low", Severity: store.HealthSeverityWarn, Details: "disk 95%"},
+	} {
+		if err := routeHealthEvent(context.Background(), cfg, e, sender, nil, oncall.New(cfg.OnCall)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if strings.Join(sender.rooms, ",") != "email:api,email:" {
+		t.Fatalf("email routes = %v", sender.rooms)
+	}
+	if got := cfg.EmailRecipients(sender.rooms[1]); len(got) != 1 || got[0] != "ops@example.org" {
+		t.Fatalf("event without a project emailed to %v", got)
+	}
+}
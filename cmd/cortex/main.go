@@ -8,7 +8,9 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -18,12 +20,25 @@ import (
 	tclient "go.temporal.io/sdk/client"
 
 	"github.com/antigravity-dev/cortex/internal/api"
+	"github.com/antigravity-dev/cortex/internal/autoscale"
 	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/dispatch"
+	"github.com/antigravity-dev/cortex/internal/health"
+	"github.com/antigravity-dev/cortex/internal/journal"
+	"github.com/antigravity-dev/cortex/internal/lease"
+	"github.com/antigravity-dev/cortex/internal/redact"
 	"github.com/antigravity-dev/cortex/internal/store"
 	"github.com/antigravity-dev/cortex/internal/temporal"
 )
 
-func configureLogger(logLevel string, useDev bool) *slog.Logger {
+// Build metadata, injected at link time via -ldflags "-X main.version=...".
+var (
+	version   = "dev"
+	commit    = "unknown"
+	buildTime = "unknown"
+)
+
+func configureLogger(logLevel string, useDev bool, redactor *redact.Redactor) *slog.Logger {
 	level := slog.LevelInfo
 	switch strings.ToLower(strings.TrimSpace(logLevel)) {
 	case "debug":
@@ -34,13 +49,29 @@ func configureLogger(logLevel string, useDev bool) *slog.Logger {
 		level = slog.LevelError
 	}
 
-	opts := &slog.HandlerOptions{Level: level}
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: redactSecretAttr(redactor)}
 	if useDev {
 		return slog.New(slog.NewTextHandler(os.Stderr, opts))
 	}
 	return slog.New(slog.NewJSONHandler(os.Stderr, opts))
 }
 
+// redactSecretAttr scrubs resolved config secrets, and whatever else the
+// output redactor matches, from string and error log values.
+func redactSecretAttr(redactor *redact.Redactor) func([]string, slog.Attr) slog.Attr {
+	return func(_ []string, a slog.Attr) slog.Attr {
+		switch a.Value.Kind() {
+		case slog.KindString:
+			a.Value = slog.StringValue(redactor.String(a.Value.String()))
+		case slog.KindAny:
+			if err, ok := a.Value.Any().(error); ok {
+				a.Value = slog.StringValue(redactor.String(err.Error()))
+			}
+		}
+		return a
+	}
+}
+
 func validateRuntimeConfigReload(oldCfg, newCfg *config.Config) error {
 	if oldCfg == nil || newCfg == nil {
 		return fmt.Errorf("invalid config state during reload")
@@ -69,12 +100,23 @@ func main() {
 	normalizeBeadsProject := flag.String("normalize-beads-project", "", "normalize oversized .beads/issues.jsonl rows for the given project and exit")
 	normalizeBeadsMaxBytes := flag.Int("normalize-beads-max-bytes", 60000, "maximum bytes allowed per issues.jsonl row in -normalize-beads-project mode")
 	normalizeBeadsDryRun := flag.Bool("normalize-beads-dry-run", false, "preview normalize-beads changes without writing files")
+	showVersion := flag.Bool("version", false, "print version information and exit")
+	printDefaultConfig := flag.Bool("print-default-config", false, "print a commented reference config with every option and its default, then exit")
 	flag.Parse()
 
+	if *showVersion {
+		fmt.Printf("cortex %s (commit %s, built %s)\n", version, commit, buildTime)
+		return
+	}
+	if *printDefaultConfig {
+		fmt.Print(config.DefaultConfigTOML())
+		return
+	}
+
 	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo}))
 	slog.SetDefault(logger)
 
-	logger.Info("cortex starting", "config", *configPath)
+	logger.Info("cortex starting", "config", *configPath, "version", version, "commit", commit)
 
 	if *disableAnthropic {
 		changed, err := disableAnthropicInConfigFile(*configPath, *fallbackModel)
@@ -136,9 +178,26 @@ func main() {
 		return
 	}
 
-	logger = configureLogger(cfg.General.LogLevel, *dev)
+	redactor, err := redact.New(cfg.Dispatch.Redaction)
+	if err != nil {
+		logger.Error("failed to set up output redaction", "error", err)
+		os.Exit(1)
+	}
+	logger = configureLogger(cfg.General.LogLevel, *dev, redactor)
 	slog.SetDefault(logger)
 
+	// Single-instance lock
+	lockHeld := false
+	if lockPath := config.ExpandHome(strings.TrimSpace(cfg.General.LockFile)); lockPath != "" {
+		lock, err := health.AcquireFlock(lockPath)
+		if err != nil {
+			logger.Error("failed to acquire instance lock", "path", lockPath, "error", err)
+			os.Exit(1)
+		}
+		defer lock.Release()
+		lockHeld = true
+	}
+
 	// Open store
 	dbPath := config.ExpandHome(cfg.General.StateDB)
 	st, err := store.Open(dbPath)
@@ -147,10 +206,43 @@ func main() {
 		os.Exit(1)
 	}
 	defer st.Close()
+	st.SetOutputRedactor(redactor.Redact)
+	st.SetHealthSeverities(cfg.Health.Events.Severities)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	if interval := cfg.General.WALCheckpointInterval.Duration; interval > 0 {
+		go runWALCheckpoints(ctx, st, interval, logger)
+	}
+	go runLogJanitor(ctx, st, cfgManager, logger)
+
+	jr, err := journal.Open(cfg.Dispatch.Journal.Dir, cfg.Dispatch.Journal.RetentionDays)
+	if err != nil {
+		logger.Error("failed to open decision journal", "dir", cfg.Dispatch.Journal.Dir, "error", err)
+		os.Exit(1)
+	}
+	defer jr.Close()
+
+	claims, err := lease.New(cfg.Dispatch.Claims, st)
+	if err != nil {
+		logger.Error("failed to set up bead claims", "backend", cfg.Dispatch.Claims.Backend, "error", err)
+		os.Exit(1)
+	}
+
+	hostGuard := &health.HostGuard{}
+	ticks := &health.Heartbeat{}
+	go runHostChecks(ctx, st, cfgManager, hostGuard, ticks, logger)
+	depMonitor := &health.DependencyMonitor{}
+	go runDependencyChecks(ctx, st, cfgManager, depMonitor, logger)
+	projectChecks := &health.ProjectCheckMonitor{}
+	go runProjectHealthChecks(ctx, st, cfgManager, projectChecks, logger)
+	go runQueueWaitChecks(ctx, st, cfgManager, logger)
+	stuck := make(chan error, 1)
+	go runStuckCommandChecks(ctx, st, cfgManager, stuck, logger)
+	autoscaler := autoscale.New(cfg.General)
+	go runAutoscaler(ctx, st, cfgManager, autoscaler, logger)
+
 	// SIGHUP config reload
 	applyReload := func() error {
 		updatedCfg, err := config.Reload(*configPath)
@@ -160,74 +252,88 @@ func main() {
 		if err := validateRuntimeConfigReload(cfg, updatedCfg); err != nil {
 			return err
 		}
+		updatedRedactor, err := redact.New(updatedCfg.Dispatch.Redaction)
+		if err != nil {
+			return err
+		}
 		cfgManager.Set(updatedCfg)
 		cfg = updatedCfg
-		logger = configureLogger(cfg.General.LogLevel, *dev)
+		redactor = updatedRedactor
+		st.SetOutputRedactor(redactor.Redact)
+		st.SetHealthSeverities(cfg.Health.Events.Severities)
+		logger = configureLogger(cfg.General.LogLevel, *dev, redactor)
 		slog.SetDefault(logger)
 		return nil
 	}
 
-	// Start Temporal worker
-	go func() {
-		logger.Info("starting temporal worker")
-		if err := temporal.StartWorker(st, cfg.Tiers); err != nil {
-			logger.Error("temporal worker error", "error", err)
-		}
-	}()
-
-	// Start strategic groom cron schedules for each enabled project
-	go func() {
-		// Let the worker register workflows before we start cron executions
-		time.Sleep(5 * time.Second)
-
-		c, err := tclient.Dial(tclient.Options{
-			HostPort: "127.0.0.1:7233",
-		})
+	var elector *lease.Elector
+	if cfg.HA.Enabled {
+		elector, err = newElector(cfg, st)
 		if err != nil {
-			logger.Error("failed to create temporal client for strategic cron", "error", err)
-			return
+			logger.Error("failed to set up leader election", "backend", cfg.Dispatch.Claims.Backend, "error", err)
+			os.Exit(1)
 		}
-		defer c.Close()
-
-		for name, project := range cfg.Projects {
-			if !project.Enabled {
-				continue
-			}
-
-			workflowID := fmt.Sprintf("strategic-groom-%s", name)
-			req := temporal.StrategicGroomRequest{
-				Project:  name,
-				WorkDir:  config.ExpandHome(project.Workspace),
-				BeadsDir: config.ExpandHome(project.BeadsDir),
-				Tier:     "premium",
-			}
+	}
 
-			_, err := c.ExecuteWorkflow(ctx, tclient.StartWorkflowOptions{
-				ID:           workflowID,
-				TaskQueue:    "cortex-task-queue",
-				CronSchedule: "0 5 * * *",
-			}, temporal.StrategicGroomWorkflow, req)
-			if err != nil {
-				var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
-				if errors.As(err, &alreadyStarted) {
-					logger.Info("strategic cron already running", "project", name, "workflow_id", workflowID)
-					continue
-				}
-				logger.Error("failed to start strategic cron", "project", name, "error", err)
-				continue
-			}
-			logger.Info("strategic cron registered", "project", name, "workflow_id", workflowID, "schedule", "0 5 * * *")
-		}
-	}()
+	workerDone := make(chan struct{})
 
-	// Start API server
+	// The API server is built before the worker and crons start, so the
+	// Matrix poller can dispatch and cancel through its gates. It starts
+	// serving below.
 	apiSrv, err := api.NewServer(cfg, st, logger.With("component", "api"))
 	if err != nil {
 		logger.Error("failed to create api server", "error", err)
 		os.Exit(1)
 	}
+	apiSrv.SetHostGuard(hostGuard)
+	apiSrv.SetDependencies(depMonitor)
+	apiSrv.SetProjectChecks(projectChecks)
+	apiSrv.SetAutoscaler(autoscaler)
+	apiSrv.SetReadiness(api.Readiness{LockHeld: lockHeld, Ticks: ticks, WorkerDone: workerDone})
+	apiSrv.SetJournal(jr)
+	apiSrv.SetClaims(claims)
+	apiSrv.SetElector(elector)
 	defer apiSrv.Close()
 
+	// The worker and crons only run on the active instance. Without HA that
+	// is always this one; with HA it is whichever instance is elected.
+	var active atomic.Bool
+	becomeActive := func() {
+		active.Store(true)
+		if elector != nil {
+			recordTakeover(st, elector.Holder, logger)
+		}
+
+		// Start Temporal worker
+		go func() {
+			defer close(workerDone)
+			logger.Info("starting temporal worker")
+			if err := temporal.StartWorker(ctx, st, cfg, jr, claims, autoscaler); err != nil {
+				logger.Error("temporal worker error", "error", err)
+			}
+		}()
+
+		go startCrons(ctx, cfg, dbPath, logger)
+		go ensureTeams(cfg, st, logger)
+		go runMatrixPoller(ctx, cfg, st, apiSrv, logger)
+		go runMaintenanceWindows(ctx, st, cfgManager, logger)
+		go runHealthEventRouting(ctx, st, cfgManager, logger)
+		if interval := cfg.General.BeadsGuardInterval.Duration; interval > 0 {
+			go runBeadsGuard(ctx, st, cfgManager, interval, filepath.Join(filepath.Dir(dbPath), "beads-backups"), logger)
+		}
+	}
+	deposed := make(chan error, 1)
+	if elector == nil {
+		becomeActive()
+	} else {
+		logger.Info("standing by for leadership", "instance", elector.Holder, "lease_ttl", cfg.HA.LeaseTTL.Duration.String())
+		go func() {
+			if err := elector.Run(ctx, becomeActive); err != nil {
+				deposed <- err
+			}
+		}()
+	}
+
 	go func() {
 		if err := apiSrv.Start(ctx); err != nil {
 			logger.Error("api server error", "error", err)
@@ -237,23 +343,66 @@ func main() {
 	logger.Info("cortex running",
 		"bind", cfg.API.Bind,
 	)
+	if err := health.SdNotify("READY=1"); err != nil {
+		logger.Warn("failed to notify systemd", "error", err)
+	}
 
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM)
 
 	for {
-		sig := <-sigCh
+		var sig os.Signal
+		select {
+		case sig = <-sigCh:
+		case err := <-deposed:
+			// Another instance may already be running this one's work, so
+			// stop as if asked to; a supervisor restarts it as a standby.
+			logger.Error("lost leadership, shutting down", "error", err)
+			if err := st.RecordHealthEvent("ha_deposed", fmt.Sprintf("%s lost leadership: %v", elector.Holder, err)); err != nil {
+				logger.Warn("failed to record lost leadership", "error", err)
+			}
+			sig = syscall.SIGTERM
+		case err := <-stuck:
+			// Every stuck run was killed at its timeout, but the command
+			// keeps hanging; shut down as if asked to, so the supervisor
+			// starts cortex afresh.
+			logger.Error("restarting for stuck commands", "error", err)
+			sig = syscall.SIGTERM
+		}
 		switch sig {
 		case syscall.SIGHUP:
 			if err := applyReload(); err != nil {
 				logger.Error(fmt.Sprintf("config reload failed: %v", err))
 				continue
 			}
-			logger.Info("config reloaded")
+			logger.Info("config reloaded", "included_files", len(cfg.IncludedFiles))
 		case syscall.SIGINT, syscall.SIGTERM:
 			shutdownStart := time.Now()
-			logger.Info("received signal, shutting down", "signal", sig)
+			drain := cfg.General.ShutdownDrain.Duration
+			logger.Info("received signal, shutting down", "signal", sig, "drain_window", drain.String())
+			// Cancelling stops the worker polling, so nothing new is admitted
+			// while running dispatches drain.
 			cancel()
+			result := temporal.DrainAgents(drain)
+			logger.Info("shutdown drain finished",
+				"in_flight", result.InFlight,
+				"completed", result.Completed,
+				"interrupted", len(result.Interrupted),
+				"elapsed", result.Elapsed.String(),
+			)
+			if err := st.RecordHealthEvent("shutdown_drain", result.String()); err != nil {
+				logger.Warn("failed to record shutdown drain", "error", err)
+			}
+			if active.Load() {
+				select {
+				case <-workerDone:
+				case <-time.After(30 * time.Second):
+					logger.Warn("temporal worker did not stop in time")
+				}
+			}
+			if err := elector.Resign(context.Background()); err != nil {
+				logger.Warn("failed to resign leadership", "error", err)
+			}
 			logger.Info("cortex stopped", "shutdown_duration", time.Since(shutdownStart).String())
 			return
 		default:
@@ -265,3 +414,851 @@ func main() {
 		}
 	}
 }
+
+// startCrons registers the Temporal crons once the worker has had time to
+// register its workflows. It runs on the active instance only, and with
+// sharding only over the projects of this instance's shard.
+func startCrons(ctx context.Context, cfg *config.Config, dbPath string, logger *slog.Logger) {
+	cfg = cfg.ShardProjects()
+
+	// Let the worker register workflows before we start cron executions
+	time.Sleep(5 * time.Second)
+
+	c, err := temporal.Dial(cfg.Temporal)
+	if err != nil {
+		logger.Error("failed to create temporal client for crons", "error", err)
+		return
+	}
+	defer c.Close()
+
+	for name, project := range cfg.Projects {
+		if project.Enabled {
+			startStrategicGroom(ctx, c, cfg, logger, name, project)
+			startChief(ctx, c, cfg, logger, name, project)
+		}
+	}
+
+	// Weekly cross-project failure clustering report
+	reportReq := temporal.FailureClusterReportRequest{
+		WindowDays: 7,
+		Limit:      10,
+		ReportDir:  filepath.Join(filepath.Dir(dbPath), "reports"),
+	}
+	_, err = c.ExecuteWorkflow(ctx, tclient.StartWorkflowOptions{
+		ID:           cfg.ShardWorkflowID("failure-cluster-report"),
+		TaskQueue:    cfg.Temporal.TaskQueue,
+		CronSchedule: cfg.Temporal.Schedules.FailureClusterReport,
+	}, temporal.FailureClusterReportWorkflow, reportReq)
+	if err != nil {
+		var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+		if errors.As(err, &alreadyStarted) {
+			logger.Info("failure cluster report cron already running", "workflow_id", cfg.ShardWorkflowID("failure-cluster-report"))
+		} else {
+			logger.Error("failed to start failure cluster report cron", "error", err)
+		}
+	} else {
+		logger.Info("failure cluster report cron registered", "schedule", cfg.Temporal.Schedules.FailureClusterReport, "report_dir", reportReq.ReportDir)
+	}
+
+	startProviderWarmups(ctx, c, cfg, logger)
+	startStalledReviewCheck(ctx, c, cfg, logger)
+	startStageSLACheck(ctx, c, cfg, logger)
+	startEpicRollup(ctx, c, cfg, logger)
+	startBacklogTriage(ctx, c, cfg, logger)
+	startFileOverlapCheck(ctx, c, cfg, logger)
+	startStandupDigest(ctx, c, cfg, logger)
+	startBranchJanitor(ctx, c, cfg, logger)
+	startBurnInReports(ctx, c, cfg, logger)
+	startCostReports(ctx, c, cfg, logger)
+	startRolloutCompletion(ctx, c, cfg, logger)
+}
+
+// newElector returns the elector this instance campaigns for leadership
+// with, over the dispatch.claims backend.
+func newElector(cfg *config.Config, st *store.Store) (*lease.Elector, error) {
+	backend, err := lease.NewBackend(cfg.Dispatch.Claims, st)
+	if err != nil {
+		return nil, err
+	}
+	holder, err := lease.InstanceID(cfg.Dispatch.Claims)
+	if err != nil {
+		return nil, err
+	}
+	return &lease.Elector{Backend: backend, Holder: holder, TTL: cfg.HA.LeaseTTL.Duration}, nil
+}
+
+// recordTakeover records that this instance became active, with the running
+// dispatches and bead claims it resumes. Their workflows carry on from
+// Temporal history once the worker starts; nothing is dispatched again.
+func recordTakeover(st *store.Store, instance string, logger *slog.Logger) {
+	running, err := st.GetRunningDispatches()
+	if err != nil {
+		logger.Warn("failed to list running dispatches", "error", err)
+	}
+	claims, err := st.ListClaimLeases()
+	if err != nil {
+		logger.Warn("failed to list claim leases", "error", err)
+	}
+	details := fmt.Sprintf("%s is active; resuming %d running dispatch(es) and %d claim lease(s)", instance, len(running), len(claims))
+	logger.Info("elected active instance", "instance", instance, "running_dispatches", len(running), "claim_leases", len(claims))
+	if err := st.RecordHealthEvent("ha_active", details); err != nil {
+		logger.Warn("failed to record takeover", "error", err)
+	}
+}
+
+// runWALCheckpoints truncates the state DB write-ahead log every interval so
+// it stays small and readers do not slow down as it grows.
+func runWALCheckpoints(ctx context.Context, st *store.Store, interval time.Duration, logger *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cp, err := st.CheckpointWAL()
+			switch {
+			case err != nil:
+				logger.Warn("wal checkpoint failed", "error", err)
+			case cp.Busy:
+				logger.Warn("wal checkpoint incomplete: database busy", "log_frames", cp.LogFrames, "checkpointed", cp.Checkpointed)
+			default:
+				logger.Debug("wal checkpoint complete", "frames", cp.Checkpointed)
+			}
+		}
+	}
+}
+
+// logJanitorInterval is how often the dispatch log directory is swept.
+const logJanitorInterval = time.Hour
+
+// runLogJanitor compresses and expires dispatch logs on every backend's
+// behalf, once at startup and then every logJanitorInterval. Streamed output
+// left behind by workflows that never recorded an outcome expires with the
+// logs.
+func runLogJanitor(ctx context.Context, st *store.Store, cfgManager config.ConfigManager, logger *slog.Logger) {
+	sweep := func() {
+		cfg := cfgManager.Get()
+		cutoff := time.Now().AddDate(0, 0, -cfg.Dispatch.LogRetentionDays)
+		if n, err := st.PruneOutputChunks(cutoff); err != nil {
+			logger.Warn("output chunk prune failed", "error", err)
+		} else if n > 0 {
+			logger.Info("abandoned output chunks pruned", "chunks", n)
+		}
+		res, err := dispatch.SweepLogs(cfg.Dispatch.LogDir, cfg.Dispatch.LogRetentionDays, time.Now())
+		if err != nil {
+			logger.Warn("dispatch log sweep failed", "dir", cfg.Dispatch.LogDir, "error", err)
+			return
+		}
+		if res.Compressed+res.Deleted > 0 {
+			logger.Info("dispatch logs swept", "compressed", res.Compressed, "deleted", res.Deleted, "files", res.Files, "bytes", res.Bytes)
+		}
+	}
+	sweep()
+	ticker := time.NewTicker(logJanitorInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sweep()
+		}
+	}
+}
+
+// runHostChecks checks the host's free disk, memory and load every tick and
+// records a health event whenever it crosses into or out of the configured
+// limits. While a limit is breached the guard holds new dispatches, so
+// agents are not started only to fail with ENOSPC. Each tick beats ticks and
+// pets the systemd watchdog, which /readyz and WatchdogSec= rely on.
+func runHostChecks(ctx context.Context, st *store.Store, cfgManager config.ConfigManager, guard *health.HostGuard, ticks *health.Heartbeat, logger *slog.Logger) {
+	check := func() {
+		ticks.Beat()
+		if err := health.SdNotify("WATCHDOG=1"); err != nil {
+			logger.Warn("failed to notify systemd watchdog", "error", err)
+		}
+		cfg := cfgManager.Get()
+		status := health.CheckHost(hostLimits(cfg))
+		if !guard.Update(status) {
+			return
+		}
+		if status.OK() {
+			logger.Info("host resources recovered, dispatching resumed")
+			if err := st.RecordHealthEvent("host_resources_ok", "host resources back within limits"); err != nil {
+				logger.Warn("failed to record host recovery", "error", err)
+			}
+			return
+		}
+		details := strings.Join(status.Breaches, "; ")
+		logger.Warn("host resources low, dispatching paused", "breaches", details)
+		if err := st.RecordHealthEvent("host_resources_low", details); err != nil {
+			logger.Warn("failed to record host resource breach", "error", err)
+		}
+	}
+
+	check()
+	ticker := time.NewTicker(cfgManager.Get().General.TickInterval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			check()
+		}
+	}
+}
+
+// runMaintenanceWindows watches general.maintenance_windows every tick and
+// records a health event as the scheduler pauses for a window and again as
+// it resumes. Dispatches are held by the API while a window is open; this
+// loop only reports the transitions.
+func runMaintenanceWindows(ctx context.Context, st *store.Store, cfgManager config.ConfigManager, logger *slog.Logger) {
+	open := ""
+	check := func() {
+		w, until, ok := cfgManager.Get().General.MaintenanceAt(time.Now())
+		switch {
+		case ok && open == "":
+			open = w.Label()
+			details := fmt.Sprintf("scheduler paused for maintenance window %s until %s", open, until.Format(time.RFC3339))
+			logger.Info("maintenance window open, dispatching paused", "window", open, "until", until)
+			if err := st.RecordHealthEvent("maintenance_started", details); err != nil {
+				logger.Warn("failed to record maintenance start", "error", err)
+			}
+		case !ok && open != "":
+			logger.Info("maintenance window closed, dispatching resumed", "window", open)
+			if err := st.RecordHealthEvent("maintenance_ended", "scheduler resumed after maintenance window "+open); err != nil {
+				logger.Warn("failed to record maintenance end", "error", err)
+			}
+			open = ""
+		}
+	}
+
+	check()
+	ticker := time.NewTicker(cfgManager.Get().General.TickInterval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			check()
+		}
+	}
+}
+
+// runDependencyChecks probes the external tools and services cortex relies on
+// at startup and every health.check_interval, and records a health event
+// whenever one goes down or comes back, so a missing CLI or an unreachable
+// service shows up before dispatches fail on it.
+func runDependencyChecks(ctx context.Context, st *store.Store, cfgManager config.ConfigManager, monitor *health.DependencyMonitor, logger *slog.Logger) {
+	check := func() {
+		report := health.CheckDependencies(ctx, dependencies(cfgManager.Get()), 5*time.Second)
+		down, up := monitor.Update(report)
+		for _, d := range report.Dependencies {
+			if !slices.Contains(down, d.Name) {
+				continue
+			}
+			details := fmt.Sprintf("%s (%s) unavailable: %s", d.Name, d.Target, d.Error)
+			if d.Required {
+				logger.Error("required dependency unavailable", "dependency", d.Name, "target", d.Target, "error", d.Error)
+			} else {
+				logger.Warn("dependency unavailable", "dependency", d.Name, "target", d.Target, "error", d.Error)
+			}
+			if err := st.RecordHealthEvent("dependency_down", details); err != nil {
+				logger.Warn("failed to record dependency failure", "error", err)
+			}
+		}
+		for _, name := range up {
+			logger.Info("dependency recovered", "dependency", name)
+			if err := st.RecordHealthEvent("dependency_ok", name+" available again"); err != nil {
+				logger.Warn("failed to record dependency recovery", "error", err)
+			}
+		}
+	}
+
+	check()
+	ticker := time.NewTicker(cfgManager.Get().Health.CheckInterval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			check()
+		}
+	}
+}
+
+// dependencies lists what the configuration relies on: the bd, git and gh
+// CLIs, tmux and glab when configured, the Temporal frontend, the Matrix
+// homeserver or proxy, the provider CLIs and the agent gateway unit.
+func dependencies(cfg *config.Config) []health.Dependency {
+	deps := []health.Dependency{
+		health.CommandDependency("bd", "bd", true),
+		health.CommandDependency("git", "git", true),
+		health.TCPDependency("temporal", cfg.Temporal.Address(), true),
+	}
+
+	branches := false
+	for _, project := range cfg.Projects {
+		branches = branches || (project.Enabled && project.UseBranches)
+	}
+	deps = append(deps, health.CommandDependency("gh", "gh", branches))
+	if cfg.API.ForgeWebhooks.GitLabToken != "" {
+		deps = append(deps, health.CommandDependency("glab", "glab", false))
+	}
+
+	r := cfg.Dispatch.Routing
+	if slices.Contains([]string{r.FastBackend, r.BalancedBackend, r.PremiumBackend, r.CommsBackend}, "tmux") {
+		deps = append(deps, health.CommandDependency("tmux", "tmux", true))
+	}
+
+	homeserver := cfg.Matrix.E2EProxy
+	if homeserver == "" {
+		homeserver = cfg.Matrix.MediaBaseURL
+	}
+	if homeserver = strings.TrimRight(homeserver, "/"); homeserver != "" {
+		deps = append(deps, health.HTTPDependency("matrix", homeserver+"/_matrix/client/versions", cfg.Matrix.Enabled))
+	}
+
+	seen := map[string]bool{}
+	for _, name := range slices.Concat(cfg.Tiers.Fast, cfg.Tiers.Balanced, cfg.Tiers.Premium) {
+		cli, ok := cfg.Dispatch.CLI[cfg.Providers[name].CLI]
+		if !ok || cli.Cmd == "" || seen[cli.Cmd] {
+			continue
+		}
+		seen[cli.Cmd] = true
+		deps = append(deps, health.CommandDependency("cli:"+cli.Cmd, cli.Cmd, true))
+	}
+	if unit := cfg.Health.GatewayUnit; unit != "" {
+		deps = append(deps, health.SystemdDependency("gateway", unit, cfg.Health.GatewayUserService, false))
+	}
+	return deps
+}
+
+// hostLimits gathers the configured host limits and the directories whose
+// volumes dispatches write to.
+func hostLimits(cfg *config.Config) health.HostLimits {
+	h := cfg.Health.Host
+	paths := []string{filepath.Dir(cfg.General.StateDB), cfg.Dispatch.LogDir}
+	for _, project := range cfg.Projects {
+		if project.Enabled {
+			paths = append(paths, config.ExpandHome(project.Workspace))
+		}
+	}
+	return health.HostLimits{
+		MinFreeDiskMB:   h.MinFreeDiskMB,
+		MinFreeMemoryMB: h.MinFreeMemoryMB,
+		MaxLoadPerCPU:   h.MaxLoadPerCPU,
+		Paths:           append(paths, h.Paths...),
+	}
+}
+
+// startStalledReviewCheck registers the cron that nudges Cortex PRs waiting
+// on review longer than dispatch.stalled_review.threshold.
+func startStalledReviewCheck(ctx context.Context, c tclient.Client, cfg *config.Config, logger *slog.Logger) {
+	sr := cfg.Dispatch.StalledReview
+	if !sr.Enabled {
+		return
+	}
+
+	projects := make(map[string]temporal.ReviewProject, len(cfg.Projects))
+	for name, project := range cfg.Projects {
+		if !project.Enabled {
+			continue
+		}
+		projects[name] = temporal.ReviewProject{
+			Workspace: config.ExpandHome(project.Workspace),
+			Room:      cfg.ResolveRoom(name),
+		}
+	}
+
+	req := temporal.StalledReviewRequest{
+		Threshold: sr.Threshold.Duration,
+		Reassign:  sr.Reassign,
+		Projects:  projects,
+	}
+	_, err := c.ExecuteWorkflow(ctx, tclient.StartWorkflowOptions{
+		ID:           cfg.ShardWorkflowID("stalled-review-check"),
+		TaskQueue:    cfg.Temporal.TaskQueue,
+		CronSchedule: sr.Schedule,
+	}, temporal.StalledReviewWorkflow, req)
+	if err != nil {
+		var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+		if errors.As(err, &alreadyStarted) {
+			logger.Info("stalled review cron already running", "workflow_id", cfg.ShardWorkflowID("stalled-review-check"))
+			return
+		}
+		logger.Error("failed to start stalled review cron", "error", err)
+		return
+	}
+	logger.Info("stalled review cron registered", "schedule", sr.Schedule, "threshold", sr.Threshold.Duration.String())
+}
+
+// startStageSLACheck registers the cron that flags beads sitting in a
+// workflow stage past the stage's max_duration. It only runs when some stage
+// sets one.
+func startStageSLACheck(ctx context.Context, c tclient.Client, cfg *config.Config, logger *slog.Logger) {
+	if !cfg.StageSLAEnabled() {
+		return
+	}
+
+	limits := make(map[string]map[string]temporal.StageLimit, len(cfg.Workflows))
+	for name, wf := range cfg.Workflows {
+		for _, stage := range wf.Stages {
+			if stage.MaxDuration.Duration <= 0 {
+				continue
+			}
+			if limits[name] == nil {
+				limits[name] = make(map[string]temporal.StageLimit)
+			}
+			limits[name][stage.Name] = temporal.StageLimit{
+				MaxDuration: stage.MaxDuration.Duration,
+				Escalate:    stage.EscalateOnBreach,
+			}
+		}
+	}
+	projects := make(map[string]string, len(cfg.Projects))
+	for name, project := range cfg.Projects {
+		if project.Enabled {
+			projects[name] = cfg.ResolveRoom(name)
+		}
+	}
+
+	schedule := cfg.Dispatch.StageSLA.Schedule
+	_, err := c.ExecuteWorkflow(ctx, tclient.StartWorkflowOptions{
+		ID:           cfg.ShardWorkflowID("stage-sla-check"),
+		TaskQueue:    cfg.Temporal.TaskQueue,
+		CronSchedule: schedule,
+	}, temporal.StageSLAWorkflow, temporal.StageSLARequest{Limits: limits, Projects: projects})
+	if err != nil {
+		var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+		if errors.As(err, &alreadyStarted) {
+			logger.Info("stage SLA cron already running", "workflow_id", cfg.ShardWorkflowID("stage-sla-check"))
+			return
+		}
+		logger.Error("failed to start stage SLA cron", "error", err)
+		return
+	}
+	logger.Info("stage SLA cron registered", "schedule", schedule)
+}
+
+// startEpicRollup registers the cron that closes epics whose children are
+// done and posts epic progress to each project's room.
+func startEpicRollup(ctx context.Context, c tclient.Client, cfg *config.Config, logger *slog.Logger) {
+	er := cfg.Dispatch.EpicRollup
+	if !er.Enabled {
+		return
+	}
+
+	projects := make(map[string]temporal.BacklogProject, len(cfg.Projects))
+	for name, project := range cfg.Projects {
+		if !project.Enabled {
+			continue
+		}
+		projects[name] = temporal.BacklogProject{
+			BeadsDir: config.ExpandHome(project.BeadsDir),
+			Room:     cfg.ResolveRoom(name),
+		}
+	}
+
+	_, err := c.ExecuteWorkflow(ctx, tclient.StartWorkflowOptions{
+		ID:           cfg.ShardWorkflowID("epic-rollup"),
+		TaskQueue:    cfg.Temporal.TaskQueue,
+		CronSchedule: er.Schedule,
+	}, temporal.EpicRollupWorkflow, temporal.EpicRollupRequest{Threshold: er.Threshold, Projects: projects})
+	if err != nil {
+		var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+		if errors.As(err, &alreadyStarted) {
+			logger.Info("epic rollup cron already running", "workflow_id", cfg.ShardWorkflowID("epic-rollup"))
+			return
+		}
+		logger.Error("failed to start epic rollup cron", "error", err)
+		return
+	}
+	logger.Info("epic rollup cron registered", "schedule", er.Schedule, "threshold", er.Threshold)
+}
+
+// startBacklogTriage registers the cron that labels duplicate and stale
+// beads so they stop crowding the dispatch pool.
+func startBacklogTriage(ctx context.Context, c tclient.Client, cfg *config.Config, logger *slog.Logger) {
+	tr := cfg.Dispatch.Triage
+	if !tr.Enabled {
+		return
+	}
+
+	projects := make(map[string]temporal.BacklogProject, len(cfg.Projects))
+	for name, project := range cfg.Projects {
+		if !project.Enabled {
+			continue
+		}
+		projects[name] = temporal.BacklogProject{
+			BeadsDir: config.ExpandHome(project.BeadsDir),
+			Room:     cfg.ResolveRoom(name),
+		}
+	}
+
+	req := temporal.TriageRequest{Similarity: tr.Similarity, StaleAfter: tr.StaleAfter.Duration, Projects: projects}
+	_, err := c.ExecuteWorkflow(ctx, tclient.StartWorkflowOptions{
+		ID:           cfg.ShardWorkflowID("backlog-triage"),
+		TaskQueue:    cfg.Temporal.TaskQueue,
+		CronSchedule: tr.Schedule,
+	}, temporal.BacklogTriageWorkflow, req)
+	if err != nil {
+		var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+		if errors.As(err, &alreadyStarted) {
+			logger.Info("backlog triage cron already running", "workflow_id", cfg.ShardWorkflowID("backlog-triage"))
+			return
+		}
+		logger.Error("failed to start backlog triage cron", "error", err)
+		return
+	}
+	logger.Info("backlog triage cron registered", "schedule", tr.Schedule, "similarity", tr.Similarity, "stale_after", tr.StaleAfter.Duration.String())
+}
+
+// startFileOverlapCheck registers the cron that flags ready beads touching
+// the same files, and in serialize mode makes them run one at a time.
+func startFileOverlapCheck(ctx context.Context, c tclient.Client, cfg *config.Config, logger *slog.Logger) {
+	fo := cfg.Dispatch.FileOverlap
+	if !fo.Enabled {
+		return
+	}
+
+	req := temporal.FileOverlapRequest{Mode: fo.Mode, LookbackDays: fo.LookbackDays, Projects: janitorProjects(cfg)}
+	_, err := c.ExecuteWorkflow(ctx, tclient.StartWorkflowOptions{
+		ID:           cfg.ShardWorkflowID("file-overlap"),
+		TaskQueue:    cfg.Temporal.TaskQueue,
+		CronSchedule: fo.Schedule,
+	}, temporal.FileOverlapWorkflow, req)
+	if err != nil {
+		var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+		if errors.As(err, &alreadyStarted) {
+			logger.Info("file overlap cron already running", "workflow_id", cfg.ShardWorkflowID("file-overlap"))
+			return
+		}
+		logger.Error("failed to start file overlap cron", "error", err)
+		return
+	}
+	logger.Info("file overlap cron registered", "schedule", fo.Schedule, "mode", fo.Mode)
+}
+
+// startStandupDigest registers the daily cron that posts each project's
+// standup digest. It only runs when reporter.daily_digest_time is set.
+func startStandupDigest(ctx context.Context, c tclient.Client, cfg *config.Config, logger *slog.Logger) {
+	schedule, err := cfg.Reporter.DailyDigestSchedule()
+	if err != nil || schedule == "" {
+		return
+	}
+
+	projects := make(map[string]temporal.StandupProject, len(cfg.Projects))
+	for name, project := range cfg.Projects {
+		if !project.Enabled {
+			continue
+		}
+		projects[name] = temporal.StandupProject{
+			BeadsDir: config.ExpandHome(project.BeadsDir),
+			Room:     cfg.ResolveRoom(name),
+		}
+	}
+
+	_, err = c.ExecuteWorkflow(ctx, tclient.StartWorkflowOptions{
+		ID:           cfg.ShardWorkflowID("standup-digest"),
+		TaskQueue:    cfg.Temporal.TaskQueue,
+		CronSchedule: schedule,
+	}, temporal.StandupDigestWorkflow, temporal.StandupDigestRequest{Window: 24 * time.Hour, Projects: projects})
+	if err != nil {
+		var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+		if errors.As(err, &alreadyStarted) {
+			logger.Info("standup digest cron already running", "workflow_id", cfg.ShardWorkflowID("standup-digest"))
+			return
+		}
+		logger.Error("failed to start standup digest cron", "error", err)
+		return
+	}
+	logger.Info("standup digest cron registered", "schedule", schedule)
+}
+
+// startBranchJanitor registers the cron that deletes feature branches left
+// behind by closed or abandoned beads.
+func startBranchJanitor(ctx context.Context, c tclient.Client, cfg *config.Config, logger *slog.Logger) {
+	bj := cfg.Dispatch.BranchJanitor
+	if !bj.Enabled {
+		return
+	}
+
+	req := temporal.BranchJanitorRequest{
+		GracePeriod:  bj.GracePeriod.Duration,
+		Protected:    bj.Protected,
+		DeleteRemote: bj.DeleteRemote,
+		DryRun:       bj.DryRun,
+		Projects:     janitorProjects(cfg),
+	}
+	_, err := c.ExecuteWorkflow(ctx, tclient.StartWorkflowOptions{
+		ID:           cfg.ShardWorkflowID("branch-janitor"),
+		TaskQueue:    cfg.Temporal.TaskQueue,
+		CronSchedule: bj.Schedule,
+	}, temporal.BranchJanitorWorkflow, req)
+	if err != nil {
+		var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+		if errors.As(err, &alreadyStarted) {
+			logger.Info("branch janitor cron already running", "workflow_id", cfg.ShardWorkflowID("branch-janitor"))
+			return
+		}
+		logger.Error("failed to start branch janitor cron", "error", err)
+		return
+	}
+	logger.Info("branch janitor cron registered", "schedule", bj.Schedule, "grace_period", bj.GracePeriod.Duration.String(), "dry_run", bj.DryRun)
+}
+
+// janitorProjects returns the workspace, branch prefixes and room of each
+// enabled project, for the crons that inspect feature branches.
+func janitorProjects(cfg *config.Config) map[string]temporal.JanitorProject {
+	projects := make(map[string]temporal.JanitorProject, len(cfg.Projects))
+	for name, project := range cfg.Projects {
+		if !project.Enabled {
+			continue
+		}
+		prefixes := []string{project.BranchPrefix}
+		if p := cfg.Dispatch.Git.BranchPrefix; p != "" && p != project.BranchPrefix {
+			prefixes = append(prefixes, p)
+		}
+		projects[name] = temporal.JanitorProject{
+			Workspace:  config.ExpandHome(project.Workspace),
+			BeadsDir:   config.ExpandHome(project.BeadsDir),
+			BaseBranch: project.BaseBranch,
+			Prefixes:   prefixes,
+			Room:       cfg.ResolveRoom(name),
+		}
+	}
+	return projects
+}
+
+// startBurnInReports registers the cron that scores enabled projects against
+// their burn-in SLO gates and writes the report artifacts.
+func startBurnInReports(ctx context.Context, c tclient.Client, cfg *config.Config, logger *slog.Logger) {
+	bi := cfg.Reporter.BurnIn
+	if !bi.Enabled {
+		return
+	}
+
+	slos := make(map[string]config.BurnInSLO, len(cfg.Projects))
+	for name, project := range cfg.Projects {
+		if project.Enabled {
+			slos[name] = cfg.BurnInSLOFor(name)
+		}
+	}
+
+	req := temporal.BurnInReportRequest{
+		Window:     bi.Window.Duration,
+		ReportsDir: bi.ReportsDir,
+		SLOs:       slos,
+		Room:       cfg.Reporter.DefaultRoom,
+	}
+	_, err := c.ExecuteWorkflow(ctx, tclient.StartWorkflowOptions{
+		ID:           cfg.ShardWorkflowID("burnin-report"),
+		TaskQueue:    cfg.Temporal.TaskQueue,
+		CronSchedule: bi.Schedule,
+	}, temporal.BurnInReportWorkflow, req)
+	if err != nil {
+		var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+		if errors.As(err, &alreadyStarted) {
+			logger.Info("burn-in report cron already running", "workflow_id", cfg.ShardWorkflowID("burnin-report"))
+			return
+		}
+		logger.Error("failed to start burn-in report cron", "error", err)
+		return
+	}
+	logger.Info("burn-in report cron registered", "schedule", bi.Schedule, "window", bi.Window.Duration.String(), "reports_dir", bi.ReportsDir)
+}
+
+// startCostReports registers the cron that totals the previous month's
+// spend per project and writes the cost report artifacts.
+func startCostReports(ctx context.Context, c tclient.Client, cfg *config.Config, logger *slog.Logger) {
+	cr := cfg.Reporter.Cost
+	if !cr.Enabled {
+		return
+	}
+
+	_, err := c.ExecuteWorkflow(ctx, tclient.StartWorkflowOptions{
+		ID:           cfg.ShardWorkflowID("cost-report"),
+		TaskQueue:    cfg.Temporal.TaskQueue,
+		CronSchedule: cr.Schedule,
+	}, temporal.CostReportWorkflow, temporal.CostReportRequest{ReportsDir: cr.ReportsDir})
+	if err != nil {
+		var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+		if errors.As(err, &alreadyStarted) {
+			logger.Info("cost report cron already running", "workflow_id", cfg.ShardWorkflowID("cost-report"))
+			return
+		}
+		logger.Error("failed to start cost report cron", "error", err)
+		return
+	}
+	logger.Info("cost report cron registered", "schedule", cr.Schedule, "reports_dir", cr.ReportsDir)
+}
+
+// startStrategicGroom starts a project's strategic groom controller. A
+// controller that is already running is sent the configured schedule; a
+// pre-controller cron workflow under the same ID is terminated and replaced,
+// since it cannot be signalled or queried between runs.
+func startStrategicGroom(ctx context.Context, c tclient.Client, cfg *config.Config, logger *slog.Logger, name string, project config.Project) {
+	workflowID := temporal.StrategicGroomWorkflowID(name)
+	schedule := cfg.Temporal.Schedules.StrategicGroom
+	opts := tclient.StartWorkflowOptions{
+		ID:        workflowID,
+		TaskQueue: cfg.Temporal.TaskQueue,
+	}
+	req := temporal.StrategicGroomControlRequest{
+		Groom: temporal.StrategicGroomRequest{
+			Project:  name,
+			WorkDir:  config.ExpandHome(project.Workspace),
+			BeadsDir: config.ExpandHome(project.BeadsDir),
+			Tier:     "premium",
+		},
+		Schedule: schedule,
+	}
+
+	_, err := c.ExecuteWorkflow(ctx, opts, temporal.StrategicGroomControlWorkflow, req)
+	var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+	if errors.As(err, &alreadyStarted) {
+		desc, derr := c.DescribeWorkflowExecution(ctx, workflowID, "")
+		if derr != nil {
+			logger.Error("failed to describe strategic groom workflow", "project", name, "error", derr)
+			return
+		}
+		if desc.GetWorkflowExecutionInfo().GetType().GetName() != "StrategicGroomWorkflow" {
+			if err := c.SignalWorkflow(ctx, workflowID, "", temporal.GroomScheduleSignal, schedule); err != nil {
+				logger.Error("failed to update strategic groom schedule", "project", name, "error", err)
+				return
+			}
+			logger.Info("strategic groom controller already running", "project", name, "workflow_id", workflowID, "schedule", schedule)
+			return
+		}
+		if err := c.TerminateWorkflow(ctx, workflowID, "", "replaced by strategic groom controller"); err != nil {
+			logger.Error("failed to terminate legacy strategic cron", "project", name, "error", err)
+			return
+		}
+		_, err = c.ExecuteWorkflow(ctx, opts, temporal.StrategicGroomControlWorkflow, req)
+	}
+	if err != nil {
+		logger.Error("failed to start strategic groom controller", "project", name, "error", err)
+		return
+	}
+	logger.Info("strategic groom controller started", "project", name, "workflow_id", workflowID, "schedule", schedule)
+}
+
+// startChief registers a project's chief cron, which dispatches the chief to
+// groom the backlog, split epics and propose a sprint. It only runs when the
+// chief is enabled and the project sets a chief_schedule.
+func startChief(ctx context.Context, c tclient.Client, cfg *config.Config, logger *slog.Logger, name string, project config.Project) {
+	if !cfg.Chief.Enabled || project.ChiefSchedule == "" {
+		return
+	}
+
+	workflowID := temporal.ChiefWorkflowID(name)
+	req := temporal.ChiefRequest{
+		Project:        name,
+		WorkDir:        config.ExpandHome(project.Workspace),
+		BeadsDir:       config.ExpandHome(project.BeadsDir),
+		Agent:          "claude",
+		SprintCapacity: project.SprintCapacity,
+	}
+	_, err := c.ExecuteWorkflow(ctx, tclient.StartWorkflowOptions{
+		ID:           workflowID,
+		TaskQueue:    cfg.Temporal.TaskQueue,
+		CronSchedule: project.ChiefSchedule,
+	}, temporal.ChiefWorkflow, req)
+	if err != nil {
+		var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+		if errors.As(err, &alreadyStarted) {
+			logger.Info("chief cron already running", "project", name, "workflow_id", workflowID)
+			return
+		}
+		logger.Error("failed to start chief cron", "project", name, "error", err)
+		return
+	}
+	logger.Info("chief cron registered", "project", name, "workflow_id", workflowID, "schedule", project.ChiefSchedule)
+}
+
+// startRolloutCompletion registers the cron that evaluates the rollout
+// completion criteria and records the result as a health event.
+func startRolloutCompletion(ctx context.Context, c tclient.Client, cfg *config.Config, logger *slog.Logger) {
+	ro := cfg.Health.Rollout
+	if !ro.Enabled {
+		return
+	}
+
+	req := temporal.RolloutCompletionRequest{
+		Criteria:  ro,
+		BeadsDir:  cfg.Projects[ro.Project].BeadsDir,
+		BurnInDir: cfg.Reporter.BurnIn.ReportsDir,
+	}
+	_, err := c.ExecuteWorkflow(ctx, tclient.StartWorkflowOptions{
+		ID:           cfg.ShardWorkflowID("rollout-completion"),
+		TaskQueue:    cfg.Temporal.TaskQueue,
+		CronSchedule: ro.Schedule,
+	}, temporal.RolloutCompletionWorkflow, req)
+	if err != nil {
+		var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+		if errors.As(err, &alreadyStarted) {
+			logger.Info("rollout completion cron already running", "workflow_id", cfg.ShardWorkflowID("rollout-completion"))
+			return
+		}
+		logger.Error("failed to start rollout completion cron", "error", err)
+		return
+	}
+	logger.Info("rollout completion cron registered", "schedule", ro.Schedule, "critical_beads", len(ro.CriticalBeads))
+}
+
+// startProviderWarmups pings warmup-enabled providers once at startup and
+// registers a cron that re-warms providers idle longer than dispatch.warmup.idle_after.
+func startProviderWarmups(ctx context.Context, c tclient.Client, cfg *config.Config, logger *slog.Logger) {
+	providers := cfg.WarmupProviders()
+	if len(providers) == 0 {
+		return
+	}
+
+	clis := make(map[string]string, len(providers))
+	for _, name := range providers {
+		if cli := strings.TrimSpace(cfg.Providers[name].CLI); cli != "" {
+			clis[name] = cli
+		}
+	}
+
+	startupReq := temporal.WarmupRequest{
+		Providers: providers,
+		CLIs:      clis,
+		Reason:    "startup",
+		Timeout:   cfg.Dispatch.Warmup.Timeout.Duration,
+	}
+	startupID := fmt.Sprintf("provider-warmup-startup-%d", time.Now().Unix())
+	if _, err := c.ExecuteWorkflow(ctx, tclient.StartWorkflowOptions{
+		ID:        startupID,
+		TaskQueue: cfg.Temporal.TaskQueue,
+	}, temporal.ProviderWarmupWorkflow, startupReq); err != nil {
+		logger.Error("failed to start provider warmup", "error", err)
+	} else {
+		logger.Info("provider warmup started", "providers", providers, "workflow_id", startupID)
+	}
+
+	idleReq := startupReq
+	idleReq.Reason = "idle"
+	idleReq.IdleAfter = cfg.Dispatch.Warmup.IdleAfter.Duration
+	schedule := cfg.Dispatch.Warmup.Schedule
+	_, err := c.ExecuteWorkflow(ctx, tclient.StartWorkflowOptions{
+		ID:           cfg.ShardWorkflowID("provider-warmup"),
+		TaskQueue:    cfg.Temporal.TaskQueue,
+		CronSchedule: schedule,
+	}, temporal.ProviderWarmupWorkflow, idleReq)
+	if err != nil {
+		var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+		if errors.As(err, &alreadyStarted) {
+			logger.Info("provider warmup cron already running", "workflow_id", cfg.ShardWorkflowID("provider-warmup"))
+			return
+		}
+		logger.Error("failed to start provider warmup cron", "error", err)
+		return
+	}
+	logger.Info("provider warmup cron registered", "providers", providers, "schedule", schedule)
+}
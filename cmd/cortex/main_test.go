@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/health"
 )
 
 func TestValidateRuntimeConfigReloadAllowsLogLevelChange(t *testing.T) {
@@ -136,3 +137,36 @@ func TestValidateRuntimeConfigReloadRejectsNilConfig(t *testing.T) {
 		t.Fatal("expected nil new config to be invalid")
 	}
 }
+
+func TestDependenciesFollowConfig(t *testing.T) {
+	cfg := &config.Config{
+		Projects:  map[string]config.Project{"p": {Enabled: true, UseBranches: true}},
+		Providers: map[string]config.Provider{"a": {CLI: "claude"}, "b": {CLI: "claude"}},
+		Tiers:     config.Tiers{Fast: []string{"a"}, Premium: []string{"b"}},
+		Temporal:  config.Temporal{Host: "127.0.0.1", Port: 7233},
+		Matrix:    config.Matrix{Enabled: true, E2EProxy: "http://127.0.0.1:8009/"},
+		Dispatch: config.Dispatch{
+			CLI:     map[string]config.CLIConfig{"claude": {Cmd: "claude"}},
+			Routing: config.DispatchRouting{PremiumBackend: "tmux"},
+		},
+	}
+
+	got := map[string]health.Dependency{}
+	for _, d := range dependencies(cfg) {
+		got[d.Name] = d
+	}
+	for name, required := range map[string]bool{"bd": true, "git": true, "gh": true, "temporal": true, "tmux": true, "matrix": true, "cli:claude": true} {
+		if d, ok := got[name]; !ok || d.Required != required {
+			t.Errorf("%s: %+v, present %v; want required %v", name, d, ok, required)
+		}
+	}
+	if got["matrix"].Target != "http://127.0.0.1:8009/_matrix/client/versions" || got["temporal"].Target != "127.0.0.1:7233" {
+		t.Fatalf("targets: matrix %q temporal %q", got["matrix"].Target, got["temporal"].Target)
+	}
+	if _, ok := got["glab"]; ok {
+		t.Fatal("glab probed without gitlab webhooks")
+	}
+	if _, ok := got["gateway"]; ok {
+		t.Fatal("gateway probed without a gateway unit")
+	}
+}
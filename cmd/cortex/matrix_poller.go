@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/antigravity-dev/cortex/internal/api"
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/dispatch"
+	"github.com/antigravity-dev/cortex/internal/matrix"
+	"github.com/antigravity-dev/cortex/internal/store"
+	"github.com/antigravity-dev/cortex/internal/temporal"
+)
+
+// newMatrixPoller builds the poller that reads project rooms for messages
+// and /cortex commands. /cortex approve and deny answer approval prompts
+// through a ChatApprover, which signals the waiting workflow; /cortex
+// dispatch and cancel go through srv's dispatch gates.
+func newMatrixPoller(cfg *config.Config, st *store.Store, srv *api.Server, logger *slog.Logger) *matrix.Poller {
+	return matrix.NewPoller(matrix.PollerConfig{
+		Enabled:        cfg.Matrix.Enabled,
+		PollInterval:   cfg.Matrix.PollInterval.Duration,
+		BotUser:        cfg.Matrix.BotUser,
+		RoomToProject:  matrix.BuildRoomProjectMap(cfg),
+		Projects:       cfg.Projects,
+		Sender:         matrixSender(cfg),
+		Store:          st,
+		CommandSenders: cfg.Matrix.CommandSenders,
+		CommandACL:     cfg.Matrix.CommandACL,
+		Pauser:         st,
+		Canceler:       srv,
+		BeadDispatcher: srv,
+		Approver:       &temporal.ChatApprover{Store: st, Temporal: cfg.Temporal},
+		MediaBaseURL:   cfg.Matrix.MediaBaseURL,
+	}, matrixClient(cfg), dispatch.NewDispatcher(), logger.With("component", "matrix"))
+}
+
+// matrixClient is the reader matching matrixSender: the E2E proxy when one
+// is configured, OpenClaw otherwise.
+func matrixClient(cfg *config.Config) matrix.Client {
+	if cfg.Matrix.E2EProxy != "" {
+		return matrix.NewE2EClient(nil, cfg.Reporter.MatrixBotAccount, cfg.Matrix.E2EProxy, cfg.Matrix.ReadLimit)
+	}
+	return matrix.NewOpenClawClient(nil, cfg.Matrix.ReadLimit)
+}
+
+// runMatrixPoller polls the project rooms until ctx ends. It returns at once
+// when matrix.enabled is off.
+func runMatrixPoller(ctx context.Context, cfg *config.Config, st *store.Store, srv *api.Server, logger *slog.Logger) {
+	newMatrixPoller(cfg, st, srv, logger).Run(ctx)
+}
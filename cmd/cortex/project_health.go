@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/health"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+// runProjectHealthChecks runs the projects' custom health checks every tick
+// that one is due. A check that starts failing records a project health
+// event and, with pause set, holds the project's dispatches until it passes
+// again.
+func runProjectHealthChecks(ctx context.Context, st *store.Store, cfgManager config.ConfigManager, monitor *health.ProjectCheckMonitor, logger *slog.Logger) {
+	lastRun := map[string]time.Time{}
+	check := func() {
+		cfg := cfgManager.Get()
+		due := dueProjectChecks(cfg, st, lastRun, time.Now(), logger)
+		monitor.Forget(func(project, name string) bool {
+			_, ok := cfg.Projects[project].HealthChecks[name]
+			return ok
+		})
+		if len(due) == 0 {
+			return
+		}
+
+		results := make([]health.ProjectCheckStatus, len(due))
+		var wg sync.WaitGroup
+		for i, c := range due {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i] = health.RunProjectCheck(ctx, c)
+			}()
+		}
+		wg.Wait()
+		if ctx.Err() != nil {
+			return
+		}
+
+		for i, c := range due {
+			lastRun[c.Project+"/"+c.Name] = results[i].CheckedAt
+			status, failed, recovered := monitor.Update(c, results[i])
+			switch {
+			case failed:
+				handleProjectCheckFailed(st, cfg.Projects[c.Project].HealthChecks[c.Name], status, logger)
+			case recovered:
+				handleProjectCheckRecovered(st, status, logger)
+			}
+		}
+	}
+
+	check()
+	ticker := time.NewTicker(cfgManager.Get().General.TickInterval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			check()
+		}
+	}
+}
+
+// dueProjectChecks lists the checks of enabled projects whose interval has
+// passed since their last run, and the after_dispatch checks of projects
+// with a dispatch completed since then.
+func dueProjectChecks(cfg *config.Config, st *store.Store, lastRun map[string]time.Time, now time.Time, logger *slog.Logger) []health.ProjectCheck {
+	var due []health.ProjectCheck
+	for projectName, project := range cfg.Projects {
+		if !project.Enabled {
+			continue
+		}
+		var completed time.Time
+		looked := false
+		for name, hc := range project.HealthChecks {
+			last := lastRun[projectName+"/"+name]
+			run := now.Sub(last) >= hc.Interval.Duration
+			if !run && hc.AfterDispatch {
+				if !looked {
+					var err error
+					if completed, err = st.GetLastCompletionAt(projectName); err != nil {
+						logger.Warn("failed to read last dispatch completion", "project", projectName, "error", err)
+					}
+					looked = true
+				}
+				run = completed.After(last)
+			}
+			if run {
+				due = append(due, health.ProjectCheck{
+					Project:  projectName,
+					Name:     name,
+					Command:  hc.Command,
+					Dir:      project.Workspace,
+					Timeout:  hc.Timeout.Duration,
+					Failures: hc.Failures,
+				})
+			}
+		}
+	}
+	return due
+}
+
+// projectCheckPauser is who a health check's dispatch pause is recorded as,
+// so only that check lifts it.
+func projectCheckPauser(name string) string {
+	return "health-check:" + name
+}
+
+func handleProjectCheckFailed(st *store.Store, hc config.ProjectHealthCheck, status health.ProjectCheckStatus, logger *slog.Logger) {
+	details := fmt.Sprintf("%s failed %d time(s) in a row (exit %d): %s",
+		status.Name, status.ConsecutiveFailures, status.ExitCode, truncateText(status.Output, 500))
+	logger.Warn("project health check failing", "project", status.Project, "check", status.Name,
+		"exit_code", status.ExitCode, "failures", status.ConsecutiveFailures)
+	if err := st.RecordProjectHealthEvent(status.Project, "health_check_failed", details); err != nil {
+		logger.Warn("failed to record project health check failure", "error", err)
+	}
+	if !hc.Pause {
+		return
+	}
+	if err := st.PauseProject(status.Project, projectCheckPauser(status.Name)); err != nil {
+		logger.Warn("failed to pause project for health check", "project", status.Project, "error", err)
+		return
+	}
+	logger.Warn("project dispatches paused by health check", "project", status.Project, "check", status.Name)
+}
+
+func handleProjectCheckRecovered(st *store.Store, status health.ProjectCheckStatus, logger *slog.Logger) {
+	logger.Info("project health check recovered", "project", status.Project, "check", status.Name)
+	if err := st.RecordProjectHealthEvent(status.Project, "health_check_ok", status.Name+" passing again"); err != nil {
+		logger.Warn("failed to record project health check recovery", "error", err)
+	}
+	// A pause someone else set, an operator's or another check's, stays.
+	pause, err := st.GetProjectPause(status.Project)
+	if err != nil || pause == nil || pause.PausedBy != projectCheckPauser(status.Name) {
+		return
+	}
+	if _, err := st.ResumeProject(status.Project); err != nil {
+		logger.Warn("failed to resume project after health check", "project", status.Project, "error", err)
+		return
+	}
+	logger.Info("project dispatches resumed by health check", "project", status.Project, "check", status.Name)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+// runQueueWaitChecks compares each role's overflow queue wait with its SLO
+// every tick. A role that goes over records one queue_wait_slo_breach event,
+// and one queue_wait_ok once it is back under.
+func runQueueWaitChecks(ctx context.Context, st *store.Store, cfgManager config.ConfigManager, logger *slog.Logger) {
+	breached := map[string]bool{}
+	ticker := time.NewTicker(cfgManager.Get().General.TickInterval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			checkQueueWaits(st, cfgManager.Get(), breached, time.Now(), logger)
+		}
+	}
+}
+
+func checkQueueWaits(st *store.Store, cfg *config.Config, breached map[string]bool, now time.Time, logger *slog.Logger) {
+	qw := cfg.Health.QueueWait
+	stats, err := st.GetQueueWaitStats(now.Add(-qw.Window.Duration), now)
+	if err != nil {
+		logger.Warn("failed to read overflow queue waits", "error", err)
+		return
+	}
+	for role := range breached {
+		if _, ok := stats[role]; !ok {
+			stats[role] = store.QueueWaitStats{Role: role}
+		}
+	}
+
+	for role, wait := range stats {
+		slo := qw.RoleSLO(role)
+		over := slo > 0 && (wait.P95 > slo || wait.Oldest > slo)
+		switch {
+		case over && !breached[role]:
+			details := queueWaitBreachDetails(cfg, wait, slo)
+			logger.Warn("overflow queue wait over SLO", "role", role, "p95", wait.P95, "oldest", wait.Oldest, "slo", slo)
+			if err := st.RecordHealthEvent("queue_wait_slo_breach", details); err != nil {
+				logger.Warn("failed to record queue wait SLO breach", "error", err)
+			}
+		case !over && breached[role]:
+			logger.Info("overflow queue wait back within SLO", "role", role)
+			if err := st.RecordHealthEvent("queue_wait_ok", fmt.Sprintf("%s queue wait back within %s", role, slo)); err != nil {
+				logger.Warn("failed to record queue wait recovery", "error", err)
+			}
+		}
+		if over {
+			breached[role] = true
+		} else {
+			delete(breached, role)
+		}
+	}
+}
+
+// queueWaitBreachDetails describes a breach and names the concurrency limit
+// that holds the role's work back: with autoscaling on, the bound the
+// controller may not grow past.
+func queueWaitBreachDetails(cfg *config.Config, wait store.QueueWaitStats, slo time.Duration) string {
+	limit, value := "general.max_concurrent_total", cfg.General.MaxConcurrentTotal
+	autoscaled := cfg.General.Autoscale.Enabled
+	switch {
+	case wait.Role == "coder" && autoscaled:
+		limit, value = "general.autoscale.max_coders", cfg.General.Autoscale.MaxCoders
+	case wait.Role == "coder":
+		limit, value = "general.max_concurrent_coders", cfg.General.MaxConcurrentCoders
+	case wait.Role == "reviewer" && autoscaled:
+		limit, value = "general.autoscale.max_reviewers", cfg.General.Autoscale.MaxReviewers
+	case wait.Role == "reviewer":
+		limit, value = "general.max_concurrent_reviewers", cfg.General.MaxConcurrentReviewers
+	}
+	return fmt.Sprintf("%s p95 queue wait %s exceeds SLO %s (%d waits, %d queued, oldest %s); consider raising %s above %d",
+		wait.Role, wait.P95, slo, wait.Waits, wait.Queued, wait.Oldest, limit, value)
+}
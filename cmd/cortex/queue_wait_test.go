@@ -0,0 +1,68 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestCheckQueueWaits(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{
+		General: config.General{MaxConcurrentCoders: 4},
+		Health: config.Health{QueueWait: config.HealthQueueWait{
+			SLO:    config.Duration{Duration: 10 * time.Minute},
+			Window: config.Duration{Duration: time.Hour},
+		}},
+	}
+	if _, err := st.EnqueueOverflowItem("b-1", "api", "coder", "api-coder", 1, "role_limit"); err != nil {
+		t.Fatal(err)
+	}
+
+	breached := map[string]bool{}
+	events := func() []store.HealthEvent {
+		t.Helper()
+		evs, err := st.GetRecentHealthEvents(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return evs
+	}
+
+	checkQueueWaits(st, cfg, breached, time.Now(), logger)
+	if len(events()) != 0 || breached["coder"] {
+		t.Fatalf("fresh queue item should be within SLO: %+v", events())
+	}
+
+	// Twenty minutes on, the queued item is over the SLO; a second check
+	// does not repeat the event.
+	later := time.Now().Add(20 * time.Minute)
+	checkQueueWaits(st, cfg, breached, later, logger)
+	checkQueueWaits(st, cfg, breached, later, logger)
+	evs := events()
+	if len(evs) != 1 || evs[0].EventType != "queue_wait_slo_breach" || evs[0].Severity != store.HealthSeverityWarn {
+		t.Fatalf("events = %+v", evs)
+	}
+	if !strings.Contains(evs[0].Details, "coder p95 queue wait 20m") || !strings.Contains(evs[0].Details, "general.max_concurrent_coders above 4") {
+		t.Fatalf("details = %q", evs[0].Details)
+	}
+
+	if _, err := st.RemoveOverflowItem("b-1"); err != nil {
+		t.Fatal(err)
+	}
+	checkQueueWaits(st, cfg, breached, time.Now(), logger)
+	if evs := events(); len(evs) != 2 || evs[0].EventType != "queue_wait_ok" || breached["coder"] {
+		t.Fatalf("events after recovery = %+v", evs)
+	}
+}
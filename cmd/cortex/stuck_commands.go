@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/cmdexec"
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/health"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+// runStuckCommandChecks looks every tick for external commands whose last
+// health.stuck_commands.breaches runs all timed out. Each of those runs was
+// already killed at its timeout; a command that keeps hitting it records one
+// command_stuck event, and one command_ok once a run finishes in time. With
+// health.stuck_commands.restart set, a stuck command is also sent on
+// restart, which shuts cortex down for its supervisor to start it afresh.
+func runStuckCommandChecks(ctx context.Context, st *store.Store, cfgManager config.ConfigManager, restart chan<- error, logger *slog.Logger) {
+	watchdog := &health.CommandWatchdog{}
+	ticker := time.NewTicker(cfgManager.Get().General.TickInterval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := checkStuckCommands(st, cfgManager.Get(), watchdog, cmdexec.Stats(), logger); err != nil {
+				select {
+				case restart <- err:
+				default:
+				}
+			}
+		}
+	}
+}
+
+// checkStuckCommands records the commands that became stuck or recovered
+// since the last check. It returns an error naming the stuck commands when
+// cortex should restart.
+func checkStuckCommands(st *store.Store, cfg *config.Config, watchdog *health.CommandWatchdog, stats []cmdexec.Stat, logger *slog.Logger) error {
+	policy := cfg.Health.StuckCommands
+	stuck, recovered := watchdog.Update(stats, policy.Breaches)
+	var names []string
+	for _, s := range stuck {
+		details := fmt.Sprintf("%s timed out %d runs in a row (%d of %d runs timed out, longest %s)",
+			s.Command, s.Streak, s.Timeouts, s.Runs, s.Max.Round(time.Second))
+		if policy.Restart {
+			details += "; restarting cortex"
+		}
+		logger.Error("external command stuck", "command", s.Command, "streak", s.Streak, "restart", policy.Restart)
+		if err := st.RecordHealthEvent("command_stuck", details); err != nil {
+			logger.Warn("failed to record stuck command", "error", err)
+		}
+		names = append(names, s.Command)
+	}
+	for _, name := range recovered {
+		logger.Info("external command recovered", "command", name)
+		if err := st.RecordHealthEvent("command_ok", name+" finishing within its timeout again"); err != nil {
+			logger.Warn("failed to record command recovery", "error", err)
+		}
+	}
+	if policy.Restart && len(names) > 0 {
+		return fmt.Errorf("stuck commands: %v", names)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/cmdexec"
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/health"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestCheckStuckCommands(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{Health: config.Health{StuckCommands: config.HealthStuck{Breaches: 3}}}
+	watchdog := &health.CommandWatchdog{}
+	events := func() []store.HealthEvent {
+		t.Helper()
+		evs, err := st.GetRecentHealthEvents(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return evs
+	}
+
+	stats := []cmdexec.Stat{{Command: "bd list", Runs: 5, Failures: 3, Timeouts: 3, Streak: 3, Max: 2 * time.Minute}}
+	if err := checkStuckCommands(st, cfg, watchdog, stats, logger); err != nil {
+		t.Fatalf("expected no restart without restart set, got %v", err)
+	}
+	evs := events()
+	if len(evs) != 1 || evs[0].EventType != "command_stuck" || !strings.Contains(evs[0].Details, "bd list timed out 3 runs in a row") {
+		t.Fatalf("expected one command_stuck event, got %+v", evs)
+	}
+	if err := checkStuckCommands(st, cfg, watchdog, stats, logger); err != nil || len(events()) != 1 {
+		t.Fatalf("a command still stuck must not be reported again: %v %+v", err, events())
+	}
+
+	stats[0].Streak = 0
+	if err := checkStuckCommands(st, cfg, watchdog, stats, logger); err != nil {
+		t.Fatal(err)
+	}
+	if evs := events(); len(evs) != 2 || (evs[0].EventType != "command_ok" && evs[1].EventType != "command_ok") {
+		t.Fatalf("expected a command_ok event, got %+v", evs)
+	}
+
+	cfg.Health.StuckCommands.Restart = true
+	stats = []cmdexec.Stat{{Command: "gh pr", Runs: 3, Failures: 3, Timeouts: 3, Streak: 3}}
+	if err := checkStuckCommands(st, cfg, watchdog, stats, logger); err == nil || !strings.Contains(err.Error(), "gh pr") {
+		t.Fatalf("expected a restart for gh pr, got %v", err)
+	}
+}
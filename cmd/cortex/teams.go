@@ -0,0 +1,52 @@
+package main
+
+import (
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+	"github.com/antigravity-dev/cortex/internal/team"
+)
+
+// ensureTeams creates the missing role agents of every enabled project and
+// records each in the team roster. Agents retired in the roster are left
+// alone. It runs when the instance becomes active, before any dispatch.
+func ensureTeams(cfg *config.Config, st *store.Store, logger *slog.Logger) {
+	model := teamModel(cfg)
+	for _, name := range slices.Sorted(maps.Keys(cfg.Projects)) {
+		project := cfg.Projects[name]
+		workspace := config.ExpandHome(strings.TrimSpace(project.Workspace))
+		if !project.Enabled || workspace == "" {
+			continue
+		}
+		roles := slices.Clone(config.BuiltinRoles)
+		custom := make(map[string]string, len(project.Roles))
+		for _, role := range slices.Sorted(maps.Keys(project.Roles)) {
+			roles = append(roles, role)
+			custom[role] = project.Roles[role].Description
+		}
+
+		created, err := team.EnsureTeam(name, workspace, model, roles, custom, st, logger)
+		if err != nil {
+			logger.Warn("failed to ensure project team", "project", name, "error", err)
+			continue
+		}
+		if len(created) > 0 {
+			logger.Info("project team agents created", "project", name, "agents", created)
+		}
+	}
+}
+
+// teamModel is the model team agents are created with: that of the first
+// balanced-tier provider, else the chief's.
+func teamModel(cfg *config.Config) string {
+	for _, name := range cfg.Tiers.Balanced {
+		if model := cfg.Providers[name].Model; model != "" {
+			return model
+		}
+	}
+	return cfg.Chief.Model
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestEnsureTeamsRecordsRoster(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	fakeBin := t.TempDir()
+	// openclaw agents add <name> ... creates the agent's directory.
+	script := "#!/bin/sh\nmkdir -p \"$HOME/.openclaw/agents/$3\"\n"
+	if err := os.WriteFile(filepath.Join(fakeBin, "openclaw"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	st, err := store.Open(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	if err := st.RecordTeamAgent(store.TeamAgent{Name: "api-ops", Project: "api", Role: "ops"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.RetireTeamAgent("api-ops", "alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Tiers:     config.Tiers{Balanced: []string{"claude"}},
+		Providers: map[string]config.Provider{"claude": {Model: "claude-sonnet"}},
+		Projects: map[string]config.Project{
+			"api":  {Enabled: true, Workspace: t.TempDir(), Roles: map[string]config.RoleConfig{"auditor": {Description: "# Auditor"}}},
+			"idle": {Enabled: false, Workspace: t.TempDir()},
+		},
+	}
+	ensureTeams(cfg, st, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	agents, err := st.ListTeamAgents("", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, a := range agents {
+		names = append(names, a.Name)
+		if a.Model != "claude-sonnet" {
+			t.Errorf("%s model = %q, want claude-sonnet", a.Name, a.Model)
+		}
+	}
+	want := []string{"api-auditor", "api-coder", "api-planner", "api-reviewer", "api-scrum"}
+	if len(names) != len(want) {
+		t.Fatalf("roster = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("roster = %v, want %v", names, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(home, ".openclaw", "agents", "api-ops")); !os.IsNotExist(err) {
+		t.Error("retired agent api-ops was recreated")
+	}
+	role, err := os.ReadFile(filepath.Join(home, ".openclaw", "agents", "api-auditor", "ROLE.md"))
+	if err != nil || string(role) != "# Auditor" {
+		t.Errorf("api-auditor ROLE.md = %q, %v", role, err)
+	}
+}
@@ -0,0 +1,128 @@
+// Command cortexctl holds one-shot operator commands that run outside the
+// cortex daemon.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/importer"
+)
+
+const usage = `usage: cortexctl <command> [flags]
+
+commands:
+  import   convert an existing issue backlog into beads
+  status   show schedules, workflows, dispatch queues and failures (--all adds api.endpoints, e.g. chum)
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	switch os.Args[1] {
+	case "import":
+		runImport(ctx, os.Args[2:])
+	case "status":
+		runStatus(ctx, os.Args[2:])
+	case "-h", "--help", "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+}
+
+func runImport(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	var (
+		from       = fs.String("from", "github-issues", "source tracker (github-issues)")
+		project    = fs.String("project", "", "cortex project to import into (required)")
+		configPath = fs.String("config", "cortex.toml", "path to config file")
+		repo       = fs.String("repo", "", "GitHub owner/name (default: repo of the project workspace)")
+		rulesPath  = fs.String("rules", "", "TOML file mapping labels/titles to bead type, priority, labels and estimates")
+		state      = fs.String("state", "open", "issue state to import: open, closed or all")
+		limit      = fs.Int("limit", 500, "maximum number of issues to fetch")
+		dryRun     = fs.Bool("dry-run", false, "print the planned beads without creating them")
+	)
+	fs.Parse(args)
+
+	if *project == "" {
+		die("--project is required")
+	}
+	if *from != "github-issues" {
+		die("unsupported --from %q (supported: github-issues)", *from)
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		die("load config: %v", err)
+	}
+	proj, ok := cfg.Projects[*project]
+	if !ok {
+		die("project %q not found in %s", *project, *configPath)
+	}
+
+	rules := importer.DefaultRules()
+	if *rulesPath != "" {
+		if rules, err = importer.LoadRules(config.ExpandHome(*rulesPath)); err != nil {
+			die("%v", err)
+		}
+	}
+
+	issues, err := importer.FetchGitHubIssues(ctx, proj.Workspace, importer.GitHubQuery{Repo: *repo, State: *state, Limit: *limit})
+	if err != nil {
+		die("fetch issues: %v", err)
+	}
+
+	results, err := importer.Import(ctx, proj.BeadsDir, issues, rules, *dryRun)
+	if err != nil {
+		die("import: %v", err)
+	}
+
+	var created, skipped, failed int
+	for _, r := range results {
+		m := r.Mapping
+		switch {
+		case r.Err != nil:
+			failed++
+			fmt.Printf("FAIL  #%d %s: %v\n", r.Issue.Number, r.Issue.Title, r.Err)
+		case r.Skipped:
+			skipped++
+			fmt.Printf("SKIP  #%d -> %s (already imported)\n", r.Issue.Number, r.BeadID)
+		default:
+			created++
+			target := r.BeadID
+			if *dryRun {
+				target = "(dry run)"
+			}
+			fmt.Printf("NEW   #%d -> %s [%s p%d est=%dm labels=%s] %s\n",
+				r.Issue.Number, target, m.Type, m.Priority, m.EstimateMinutes, strings.Join(m.Labels, ","), r.Issue.Title)
+		}
+	}
+
+	verb := "Imported"
+	if *dryRun {
+		verb = "Would import"
+	}
+	fmt.Printf("%s %d issue(s) into %s; %d already imported, %d failed\n", verb, created, *project, skipped, failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func die(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
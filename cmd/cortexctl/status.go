@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/api"
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+// endpointFlags collects repeated --endpoint name=url flags.
+type endpointFlags map[string]string
+
+func (e endpointFlags) String() string { return fmt.Sprint(map[string]string(e)) }
+
+func (e endpointFlags) Set(v string) error {
+	name, url, ok := strings.Cut(v, "=")
+	if !ok || name == "" || url == "" {
+		return fmt.Errorf("want name=url, got %q", v)
+	}
+	e[name] = url
+	return nil
+}
+
+// endpointStatus is one endpoint's /status?detail=1 response, or why it
+// could not be fetched.
+type endpointStatus struct {
+	Name   string            `json:"name"`
+	URL    string            `json:"url"`
+	Status *api.StatusDetail `json:"status,omitempty"`
+	Error  string            `json:"error,omitempty"`
+}
+
+func runStatus(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	var (
+		configPath = fs.String("config", "cortex.toml", "path to config file")
+		all        = fs.Bool("all", false, "also query every endpoint in api.endpoints (e.g. chum)")
+		asJSON     = fs.Bool("json", false, "print the raw per-endpoint status as JSON")
+		timeout    = fs.Duration("timeout", 10*time.Second, "per-endpoint request timeout")
+		overrides  = endpointFlags{}
+	)
+	fs.Var(overrides, "endpoint", "name=url of an API to query; repeatable, overrides the config")
+	fs.Parse(args)
+
+	endpoints := map[string]string{}
+	cfg, err := config.Load(*configPath)
+	switch {
+	case err == nil:
+		endpoints["cortex"] = bindURL(cfg.API.Bind)
+		if *all {
+			for name, url := range cfg.API.Endpoints {
+				endpoints[name] = url
+			}
+		}
+	case len(overrides) == 0:
+		die("load config: %v", err)
+	}
+	for name, url := range overrides {
+		endpoints[name] = url
+	}
+
+	results := fetchStatuses(ctx, &http.Client{Timeout: *timeout}, endpoints)
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(results)
+	} else {
+		renderStatus(os.Stdout, results)
+	}
+	for _, r := range results {
+		if r.Error != "" {
+			os.Exit(1)
+		}
+	}
+}
+
+// bindURL turns an api.bind listen address into a base URL to query.
+func bindURL(bind string) string {
+	if strings.HasPrefix(bind, ":") {
+		bind = "127.0.0.1" + bind
+	}
+	return "http://" + bind
+}
+
+// fetchStatuses queries every endpoint concurrently, returning results
+// sorted by name.
+func fetchStatuses(ctx context.Context, hc *http.Client, endpoints map[string]string) []endpointStatus {
+	results := make([]endpointStatus, 0, len(endpoints))
+	for name, url := range endpoints {
+		results = append(results, endpointStatus{Name: name, URL: strings.TrimRight(url, "/")})
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(r *endpointStatus) {
+			defer wg.Done()
+			status, err := fetchStatus(ctx, hc, r.URL)
+			if err != nil {
+				r.Error = err.Error()
+				return
+			}
+			r.Status = status
+		}(&results[i])
+	}
+	wg.Wait()
+	return results
+}
+
+func fetchStatus(ctx context.Context, hc *http.Client, baseURL string) (*api.StatusDetail, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/status?detail=1", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET /status: %s", resp.Status)
+	}
+	var status api.StatusDetail
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("decode /status: %w", err)
+	}
+	return &status, nil
+}
+
+// renderStatus prints one combined view, each row tagged with the endpoint
+// it came from.
+func renderStatus(out io.Writer, results []endpointStatus) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "ENDPOINT\tSTATE\tUPTIME\tRUNNING\tPENDING RETRY\tOVERFLOW")
+	for _, r := range results {
+		if r.Status == nil {
+			fmt.Fprintf(w, "%s\tunreachable: %s\t\t\t\t\n", r.Name, r.Error)
+			continue
+		}
+		s := r.Status
+		fmt.Fprintf(w, "%s\tok\t%s\t%d\t%d\t%d\n", r.Name,
+			(time.Duration(s.UptimeS) * time.Second).String(), s.RunningCount, s.Queue.PendingRetry, s.Queue.Overflow)
+	}
+
+	fmt.Fprintln(w, "\nSCHEDULES")
+	fmt.Fprintln(w, "ENDPOINT\tNAME\tPROJECT\tSCHEDULE")
+	for _, r := range results {
+		if r.Status == nil {
+			continue
+		}
+		for _, sc := range r.Status.Schedules {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Name, sc.Name, dash(sc.Project), sc.Schedule)
+		}
+	}
+
+	fmt.Fprintln(w, "\nRUNNING WORKFLOWS")
+	fmt.Fprintln(w, "ENDPOINT\tWORKFLOW ID\tTYPE\tSTARTED")
+	for _, r := range results {
+		if r.Status == nil {
+			continue
+		}
+		if r.Status.WorkflowsError != "" {
+			fmt.Fprintf(w, "%s\t(unavailable: %s)\t\t\n", r.Name, r.Status.WorkflowsError)
+		}
+		for _, wf := range r.Status.Workflows {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Name, wf.WorkflowID, wf.Type, wf.StartTime)
+		}
+	}
+
+	fmt.Fprintln(w, "\nDISPATCH QUEUE")
+	fmt.Fprintln(w, "ENDPOINT\tID\tBEAD\tPROJECT\tAGENT\tSTAGE\tDISPATCHED")
+	for _, r := range results {
+		if r.Status == nil {
+			continue
+		}
+		for _, d := range r.Status.Queue.Running {
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t%s\n", r.Name, d.ID, d.BeadID, d.Project, d.Agent, dash(d.Stage), d.DispatchedAt)
+		}
+	}
+
+	type failure struct {
+		endpoint string
+		api.StatusDispatch
+	}
+	var failures []failure
+	for _, r := range results {
+		if r.Status == nil {
+			continue
+		}
+		for _, d := range r.Status.RecentFailures {
+			failures = append(failures, failure{r.Name, d})
+		}
+	}
+	sort.SliceStable(failures, func(i, j int) bool {
+		ti, _ := time.Parse(time.RFC3339, failures[i].DispatchedAt)
+		tj, _ := time.Parse(time.RFC3339, failures[j].DispatchedAt)
+		return ti.After(tj)
+	})
+
+	fmt.Fprintln(w, "\nRECENT FAILURES")
+	fmt.Fprintln(w, "ENDPOINT\tID\tBEAD\tPROJECT\tAGENT\tDISPATCHED\tSUMMARY")
+	for _, f := range failures {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t%s\n", f.endpoint, f.ID, f.BeadID, f.Project, f.Agent, f.DispatchedAt, dash(f.FailureSummary))
+	}
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
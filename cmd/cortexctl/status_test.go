@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/antigravity-dev/cortex/internal/api"
+)
+
+func TestStatusCombinesEndpoints(t *testing.T) {
+	cortex := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/status" || r.URL.Query().Get("detail") != "1" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(api.StatusDetail{
+			UptimeS:      3600,
+			RunningCount: 1,
+			Schedules:    []api.StatusSchedule{{Name: "failure-cluster-report", Schedule: "0 6 * * 1"}},
+			Workflows:    []api.StatusWorkflow{{WorkflowID: "stalled-review-check", Type: "StalledReviewWorkflow", StartTime: "2026-10-15T08:00:00Z"}},
+			Queue: api.StatusQueue{
+				Running:      []api.StatusDispatch{{ID: 7, BeadID: "cx-1", Project: "cortex", Agent: "claude", Stage: "running", DispatchedAt: "2026-10-15T09:00:00Z"}},
+				PendingRetry: 2,
+			},
+			RecentFailures: []api.StatusDispatch{{ID: 5, BeadID: "cx-old", Project: "cortex", Agent: "codex", DispatchedAt: "2026-10-14T09:00:00Z", FailureSummary: "tests failed"}},
+		})
+	}))
+	defer cortex.Close()
+	chum := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(api.StatusDetail{
+			WorkflowsError: "connect to temporal: refused",
+			RecentFailures: []api.StatusDispatch{{ID: 9, BeadID: "ch-new", Project: "chum", Agent: "claude", DispatchedAt: "2026-10-15T10:00:00Z"}},
+		})
+	}))
+	defer chum.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer down.Close()
+
+	results := fetchStatuses(context.Background(), http.DefaultClient, map[string]string{
+		"cortex": cortex.URL + "/",
+		"chum":   chum.URL,
+		"spare":  down.URL,
+	})
+	if len(results) != 3 || results[0].Name != "chum" || results[2].Name != "spare" {
+		t.Fatalf("results not sorted by name: %+v", results)
+	}
+	if results[2].Error == "" || results[2].Status != nil {
+		t.Fatalf("expected spare to fail, got %+v", results[2])
+	}
+
+	var out strings.Builder
+	renderStatus(&out, results)
+	got := out.String()
+	for _, want := range []string{
+		"cortex    ok",
+		"spare     unreachable: GET /status: 500",
+		"failure-cluster-report",
+		"stalled-review-check",
+		"chum      (unavailable: connect to temporal: refused)",
+		"cx-1",
+		"tests failed",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Index(got, "ch-new") > strings.Index(got, "cx-old") {
+		t.Errorf("recent failures not newest first:\n%s", got)
+	}
+}
+
+func TestBindURL(t *testing.T) {
+	if got := bindURL(":8900"); got != "http://127.0.0.1:8900" {
+		t.Fatalf("bindURL(:8900) = %q", got)
+	}
+	if got := bindURL("10.0.0.5:8900"); got != "http://10.0.0.5:8900" {
+		t.Fatalf("bindURL = %q", got)
+	}
+}
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"bytes"
 	"database/sql"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -19,6 +22,7 @@ func main() {
 		verify     = flag.Bool("verify", true, "verify restore integrity")
 		dryRun     = flag.Bool("dry-run", false, "validate backup without actually restoring")
 		force      = flag.Bool("force", false, "overwrite existing database")
+		reportURL  = flag.String("report-url", "", "cortex API base URL to report the restore outcome to as a health event (token from CORTEX_API_TOKEN)")
 	)
 	flag.Parse()
 
@@ -87,6 +91,7 @@ func main() {
 			}
 			fmt.Printf("Rollback completed\n")
 		}
+		reportHealthEvent(*reportURL, "db_restore_failed", fmt.Sprintf("restore of %s from %s failed: %v", *dbPath, *backupPath, err))
 		die("restore failed: %v", err)
 	}
 	
@@ -97,6 +102,7 @@ func main() {
 	if *verify {
 		fmt.Printf("Verifying restored database...\n")
 		if err := verifyRestoredDatabase(*dbPath); err != nil {
+			reportHealthEvent(*reportURL, "db_restore_failed", fmt.Sprintf("restored %s failed verification: %v", *dbPath, err))
 			die("restored database verification failed: %v", err)
 		}
 		fmt.Printf("Restored database verification successful\n")
@@ -111,9 +117,42 @@ func main() {
 		}
 	}
 
+	reportHealthEvent(*reportURL, "db_restore_completed", fmt.Sprintf("restored %s from %s in %v", *dbPath, *backupPath, duration.Round(time.Millisecond)))
 	fmt.Printf("✅ Restore completed successfully\n")
 }
 
+// reportHealthEvent posts the restore outcome to cortex's health event
+// ingestion API. Reporting is best effort: a failure is printed, never fatal.
+func reportHealthEvent(baseURL, eventType, details string) {
+	if baseURL == "" {
+		return
+	}
+	body, _ := json.Marshal(map[string]any{
+		"source": "db-restore",
+		"events": []map[string]string{{"event_type": eventType, "details": details}},
+	})
+	req, err := http.NewRequest(http.MethodPost, strings.TrimRight(baseURL, "/")+"/health/events", bytes.NewReader(body))
+	if err != nil {
+		fmt.Printf("Warning: could not report health event: %v\n", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token := os.Getenv("CORTEX_API_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := (&http.Client{Timeout: 10 * time.Second}).Do(req)
+	if err != nil {
+		fmt.Printf("Warning: could not report health event: %v\n", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		fmt.Printf("Warning: health event report rejected: %s\n", resp.Status)
+		return
+	}
+	fmt.Printf("Reported %s to %s\n", eventType, baseURL)
+}
+
 func verifyBackupIntegrity(backupPath string) (map[string]interface{}, error) {
 	db, err := sql.Open("sqlite", backupPath+"?mode=ro")
 	if err != nil {
@@ -8,13 +8,24 @@ import (
 	"log/slog"
 	"net"
 	"net/http"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"go.temporal.io/sdk/client"
 
+	"github.com/antigravity-dev/cortex/internal/assets"
+	"github.com/antigravity-dev/cortex/internal/autoscale"
+	"github.com/antigravity-dev/cortex/internal/beads"
+	"github.com/antigravity-dev/cortex/internal/cmdexec"
 	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/dispatch"
+	"github.com/antigravity-dev/cortex/internal/health"
+	"github.com/antigravity-dev/cortex/internal/journal"
+	"github.com/antigravity-dev/cortex/internal/lease"
+	"github.com/antigravity-dev/cortex/internal/learner"
 	"github.com/antigravity-dev/cortex/internal/store"
 	"github.com/antigravity-dev/cortex/internal/temporal"
 )
@@ -27,6 +38,16 @@ type Server struct {
 	startTime      time.Time
 	httpServer     *http.Server
 	authMiddleware *AuthMiddleware
+	rateLimiter    func() *dispatch.RateLimiter // the worker's limiter; nil until it starts
+	hostGuard      *health.HostGuard             // nil when host checks are not running
+	dependencies   *health.DependencyMonitor     // nil when dependency probes are not running
+	projectChecks  *health.ProjectCheckMonitor   // nil when project health checks are not running
+	autoscaler     *autoscale.Controller         // nil reports the configured concurrency limits
+	journal        *journal.Journal              // nil disables decision journaling
+	claims         *lease.Claims                 // nil disables bead claims
+	elector        *lease.Elector                // nil when HA is off; the instance is then always active
+	readiness      Readiness
+	complexity     complexityCache
 }
 
 // NewServer creates a new API server.
@@ -42,9 +63,45 @@ func NewServer(cfg *config.Config, s *store.Store, logger *slog.Logger) (*Server
 		logger:         logger,
 		startTime:      time.Now(),
 		authMiddleware: authMiddleware,
+		rateLimiter:    temporal.ProviderRateLimiter,
 	}, nil
 }
 
+// SetHostGuard makes new dispatches wait while g reports the host short of
+// resources.
+func (s *Server) SetHostGuard(g *health.HostGuard) {
+	s.hostGuard = g
+}
+
+// SetDependencies serves m's latest probes at /api/v1/health/dependencies.
+func (s *Server) SetDependencies(m *health.DependencyMonitor) {
+	s.dependencies = m
+}
+
+// SetProjectChecks reports m's project health checks in /health.
+func (s *Server) SetProjectChecks(m *health.ProjectCheckMonitor) {
+	s.projectChecks = m
+}
+
+// SetAutoscaler reports c's concurrency limits while autoscaling is enabled.
+func (s *Server) SetAutoscaler(c *autoscale.Controller) {
+	s.autoscaler = c
+}
+
+// SetJournal makes the server record its dispatch admissions and denials
+// in j.
+func (s *Server) SetJournal(j *journal.Journal) {
+	s.journal = j
+}
+
+// journalDecision records a dispatch decision. A journal write failure is
+// logged and never fails the request.
+func (s *Server) journalDecision(e journal.Entry) {
+	if err := s.journal.Record(e); err != nil {
+		s.logger.Warn("failed to journal dispatch decision", "bead", e.BeadID, "decision", e.Decision, "error", err)
+	}
+}
+
 // Close closes the server and cleans up resources
 func (s *Server) Close() error {
 	if s.authMiddleware != nil {
@@ -62,9 +119,30 @@ func (s *Server) Start(ctx context.Context) error {
 	mux.HandleFunc("/projects", s.handleProjects)
 	mux.HandleFunc("/projects/", s.handleProjectDetail)
 	mux.HandleFunc("/health", s.handleHealth)
+	mux.HandleFunc("/healthz", s.handleHealthz)
+	mux.HandleFunc("/readyz", s.handleReadyz)
+	mux.HandleFunc("/api/v1/health/dependencies", s.handleDependencies)
+	mux.HandleFunc("/health/events", s.authMiddleware.RequireAuth(s.handleHealthEvents))
 	mux.HandleFunc("/metrics", s.handleMetrics)
 	mux.HandleFunc("/recommendations", s.handleRecommendations)
-	mux.HandleFunc("/dispatches/", s.handleDispatchDetail)
+	mux.HandleFunc("/learner/failure-clusters", s.handleFailureClusters)
+	mux.HandleFunc("/learner/experiments", s.handleExperiments)
+	mux.HandleFunc("/learner/velocity", s.handleVelocity)
+	mux.HandleFunc("/planning/scans", s.handleCandidateScans)
+	mux.HandleFunc("/planning/scans/", s.handleCandidateScanDetail)
+	mux.HandleFunc("/dispatches/", s.authMiddleware.RequireAuth(s.handleDispatchDetail))
+	mux.HandleFunc("/api/v1/reports/burnin", s.handleBurnInReport)
+	mux.HandleFunc("/api/v1/reports/cost", s.handleCostReport)
+	mux.HandleFunc("/api/v1/reports/standup", s.handleStandupDigests)
+	mux.HandleFunc("/api/v1/rollout/completion", s.handleRolloutCompletion)
+	mux.HandleFunc("/api/v1/sprints/timeline", s.handleSprintTimeline)
+	mux.HandleFunc("/api/v1/providers", s.handleProviders)
+	mux.HandleFunc("/api/v1/breakers", s.handleBreakers)
+	mux.HandleFunc("/api/v1/team", s.handleTeam)
+	mux.HandleFunc("/api/v1/dispatches", s.handleDispatchList)
+	mux.HandleFunc("/api/v1/export/dispatches", s.authMiddleware.RequireAuth(s.handleDispatchExport))
+	mux.HandleFunc("/api/v1/search", s.authMiddleware.RequireAuth(s.handleSearch))
+	mux.Handle("/dashboard/", http.StripPrefix("/dashboard/", http.FileServer(http.FS(assets.Dashboards()))))
 
 	// Temporal workflow endpoints
 	mux.HandleFunc("/workflows/start", s.authMiddleware.RequireAuth(s.handleWorkflowStart))
@@ -74,6 +152,29 @@ func (s *Server) Start(ctx context.Context) error {
 	mux.HandleFunc("/planning/start", s.authMiddleware.RequireAuth(s.handlePlanningStart))
 	mux.HandleFunc("/planning/", s.authMiddleware.RequireAuth(s.routePlanning))
 
+	// Strategic groom control endpoints
+	mux.HandleFunc("/groom/", s.authMiddleware.RequireAuth(s.routeGroom))
+
+	// Execution plan gate endpoints
+	mux.HandleFunc("/scheduler/plan", s.handlePlanGate)
+	mux.HandleFunc("/scheduler/plan/", s.authMiddleware.RequireAuth(s.handlePlanGateControl))
+
+	// Dispatch control endpoints
+	mux.HandleFunc("/api/v1/dispatches/", s.authMiddleware.RequireAuth(s.routeDispatches))
+
+	// Circuit breaker control endpoints
+	mux.HandleFunc("/api/v1/breakers/", s.authMiddleware.RequireAuth(s.handleBreakerReset))
+
+	// Team roster detail and retirement endpoints
+	mux.HandleFunc("/api/v1/team/", s.authMiddleware.RequireAuth(s.routeTeam))
+
+	// Bead stage control and timeline endpoints
+	mux.HandleFunc("/api/v1/beads/", s.authMiddleware.RequireAuth(s.routeBeads))
+
+	// Forge webhooks authenticate with the forge's signature, not API tokens
+	mux.HandleFunc("/api/v1/webhooks/github", s.handleGitHubWebhook)
+	mux.HandleFunc("/api/v1/webhooks/gitlab", s.handleGitLabWebhook)
+
 	s.httpServer = &http.Server{
 		Addr:        s.cfg.API.Bind,
 		Handler:     mux,
@@ -100,20 +201,49 @@ func writeJSON(w http.ResponseWriter, v any) {
 	json.NewEncoder(w).Encode(v)
 }
 
+func writeJSONStatus(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
 func writeError(w http.ResponseWriter, code int, msg string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(code)
 	json.NewEncoder(w).Encode(map[string]string{"error": msg})
 }
 
-// GET /status
+// GET /status (?detail=1 adds schedules, workflows, dispatch queue and recent failures)
+//
+// With sharding, the plain status also lists every shard's status and the
+// running count across all of them, unless ?local=1 asks for this shard's
+// alone.
 func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if statusDetailRequested(r) {
+		writeJSON(w, s.statusDetail(r.Context()))
+		return
+	}
+
 	running, _ := s.store.GetRunningDispatches()
 
 	resp := map[string]any{
 		"uptime_s":      time.Since(s.startTime).Seconds(),
 		"running_count": len(running),
 	}
+	if s.cfg.Sharding.Enabled && r.URL.Query().Get("local") != "1" {
+		shards := s.shardStatuses(r.Context(), ShardStatus{
+			Shard:        s.cfg.Sharding.Shard,
+			UptimeS:      time.Since(s.startTime).Seconds(),
+			RunningCount: len(running),
+		})
+		total := 0
+		for _, shard := range shards {
+			total += shard.RunningCount
+		}
+		resp["shard"] = s.cfg.Sharding.Shard
+		resp["shards"] = shards
+		resp["total_running_count"] = total
+	}
 	writeJSON(w, resp)
 }
 
@@ -123,14 +253,23 @@ func (s *Server) handleProjects(w http.ResponseWriter, r *http.Request) {
 		Name     string `json:"name"`
 		Enabled  bool   `json:"enabled"`
 		Priority int    `json:"priority"`
+		Shard    string `json:"shard,omitempty"`
+		Paused   bool   `json:"paused,omitempty"`
 	}
 	var projects []projectInfo
 	for name, proj := range s.cfg.Projects {
-		projects = append(projects, projectInfo{
+		info := projectInfo{
 			Name:     name,
 			Enabled:  proj.Enabled,
 			Priority: proj.Priority,
-		})
+			Shard:    proj.Shard,
+		}
+		if status, err := s.projectPauseStatus(name, proj); err != nil {
+			s.logger.Warn("failed to read project pause", "project", name, "error", err)
+		} else {
+			info.Paused = status.Paused
+		}
+		projects = append(projects, info)
 	}
 	writeJSON(w, projects)
 }
@@ -142,6 +281,16 @@ func (s *Server) handleProjectDetail(w http.ResponseWriter, r *http.Request) {
 		s.handleProjects(w, r)
 		return
 	}
+	if project, rest, found := strings.Cut(id, "/"); found {
+		if rest == "pause" || rest == "resume" {
+			s.authMiddleware.RequireAuth(func(w http.ResponseWriter, r *http.Request) {
+				s.handleProjectPause(w, r, project, rest)
+			})(w, r)
+			return
+		}
+		s.routeProjectBeads(w, r, project, rest)
+		return
+	}
 
 	proj, ok := s.cfg.Projects[id]
 	if !ok {
@@ -156,11 +305,23 @@ func (s *Server) handleProjectDetail(w http.ResponseWriter, r *http.Request) {
 		"workspace": proj.Workspace,
 		"beads_dir": proj.BeadsDir,
 	}
+	status, err := s.projectPauseStatus(id, proj)
+	if err != nil {
+		s.logger.Warn("failed to read project pause", "project", id, "error", err)
+	} else {
+		resp["pause"] = status
+	}
 	writeJSON(w, resp)
 }
 
-// GET /health
+// GET /health — ?severity=warn,critical limits recent_events to those
+// severities.
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	severities, err := parseSeverities(r.URL.Query().Get("severity"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	events, err := s.store.GetRecentHealthEvents(1)
 	healthy := true
 	var recentEvents []map[string]any
@@ -170,11 +331,17 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 			if e.EventType == "gateway_critical" {
 				healthy = false
 			}
+			if len(severities) > 0 && !slices.Contains(severities, e.Severity) {
+				continue
+			}
 			recentEvents = append(recentEvents, map[string]any{
 				"type":        e.EventType,
 				"details":     e.Details,
 				"dispatch_id": e.DispatchID,
 				"bead_id":     e.BeadID,
+				"source":      e.Source,
+				"project":     e.Project,
+				"severity":    e.Severity,
 				"time":        e.CreatedAt.Format(time.RFC3339),
 			})
 		}
@@ -189,11 +356,40 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 		"events_1h":     len(recentEvents),
 		"recent_events": recentEvents,
 	}
+	if s.hostGuard != nil {
+		resp["host"] = s.hostGuard.Status()
+	}
+	if checks := s.projectChecks.Statuses(); len(checks) > 0 {
+		resp["project_checks"] = checks
+	}
+	if s.elector != nil {
+		resp["ha"] = map[string]any{"instance": s.elector.Holder, "leading": s.elector.Leading()}
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
 }
 
+// GET /api/v1/health/dependencies - latest probes of the external tools and
+// services cortex relies on; 503 while a required one is unavailable.
+func (s *Server) handleDependencies(w http.ResponseWriter, r *http.Request) {
+	report, ok := s.dependencies.Report()
+	if !ok {
+		writeError(w, http.StatusServiceUnavailable, "dependencies not probed yet")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if !report.OK() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(map[string]any{
+		"healthy":      report.OK(),
+		"checked_at":   report.CheckedAt.Format(time.RFC3339),
+		"failing":      report.Failing(),
+		"dependencies": report.Dependencies,
+	})
+}
+
 // GET /metrics - Prometheus-compatible text format
 func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
@@ -236,6 +432,123 @@ func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Warmup pings are tracked separately so they never inflate dispatch counts.
+	warmupsOK, warmupsFailed, err := s.store.CountProviderWarmups(time.Time{})
+	if err != nil {
+		s.logger.Warn("failed to count provider warmups", "error", err)
+	} else {
+		fmt.Fprintf(&b, "# HELP cortex_provider_warmups_total Total number of provider warmup pings by outcome\n")
+		fmt.Fprintf(&b, "# TYPE cortex_provider_warmups_total counter\n")
+		fmt.Fprintf(&b, "cortex_provider_warmups_total{result=\"success\"} %d\n", warmupsOK)
+		fmt.Fprintf(&b, "cortex_provider_warmups_total{result=\"failure\"} %d\n", warmupsFailed)
+	}
+
+	// Review latency for Cortex-opened PRs over the last 30 days.
+	reviewStats, err := s.store.GetReviewLatencyStats(time.Now().Add(-30 * 24 * time.Hour))
+	if err != nil {
+		s.logger.Warn("failed to query review latency", "error", err)
+	} else if len(reviewStats) > 0 {
+		fmt.Fprintf(&b, "# HELP cortex_review_latency_seconds_avg Average time from PR open to first review (30d)\n")
+		fmt.Fprintf(&b, "# TYPE cortex_review_latency_seconds_avg gauge\n")
+		for _, rs := range reviewStats {
+			fmt.Fprintf(&b, "cortex_review_latency_seconds_avg{project=%q} %.0f\n", rs.Project, rs.AvgLatencyS)
+		}
+		fmt.Fprintf(&b, "# HELP cortex_review_latency_seconds_max Longest time from PR open to first review (30d)\n")
+		fmt.Fprintf(&b, "# TYPE cortex_review_latency_seconds_max gauge\n")
+		for _, rs := range reviewStats {
+			fmt.Fprintf(&b, "cortex_review_latency_seconds_max{project=%q} %.0f\n", rs.Project, rs.MaxLatencyS)
+		}
+		fmt.Fprintf(&b, "# HELP cortex_reviews_pending PRs awaiting first review\n")
+		fmt.Fprintf(&b, "# TYPE cortex_reviews_pending gauge\n")
+		for _, rs := range reviewStats {
+			fmt.Fprintf(&b, "cortex_reviews_pending{project=%q} %d\n", rs.Project, rs.Pending)
+		}
+		fmt.Fprintf(&b, "# HELP cortex_review_nudges_total Stalled review nudges sent (30d)\n")
+		fmt.Fprintf(&b, "# TYPE cortex_review_nudges_total counter\n")
+		for _, rs := range reviewStats {
+			fmt.Fprintf(&b, "cortex_review_nudges_total{project=%q} %d\n", rs.Project, rs.Nudges)
+		}
+	}
+
+	limits := s.concurrency()
+	fmt.Fprintf(&b, "# HELP cortex_concurrency_limit Concurrency limit in force by role\n")
+	fmt.Fprintf(&b, "# TYPE cortex_concurrency_limit gauge\n")
+	fmt.Fprintf(&b, "cortex_concurrency_limit{role=\"coder\"} %d\n", limits.Coders)
+	fmt.Fprintf(&b, "cortex_concurrency_limit{role=\"reviewer\"} %d\n", limits.Reviewers)
+
+	if waits, err := s.queueWaits(time.Now()); err != nil {
+		s.logger.Warn("failed to query overflow queue waits", "error", err)
+	} else if len(waits) > 0 {
+		fmt.Fprintf(&b, "# HELP cortex_overflow_wait_seconds Overflow queue wait by role over health.queue_wait.window\n")
+		fmt.Fprintf(&b, "# TYPE cortex_overflow_wait_seconds gauge\n")
+		for _, qw := range waits {
+			fmt.Fprintf(&b, "cortex_overflow_wait_seconds{role=%q,quantile=\"0.5\"} %.0f\n", qw.Role, qw.P50S)
+			fmt.Fprintf(&b, "cortex_overflow_wait_seconds{role=%q,quantile=\"0.95\"} %.0f\n", qw.Role, qw.P95S)
+		}
+	}
+
+	// SQLite lock contention absorbed by the store's busy retries.
+	lock := s.store.LockStats()
+	fmt.Fprintf(&b, "# HELP cortex_store_lock_wait_seconds_total Time spent in store statements that hit SQLITE_BUSY, including retries\n")
+	fmt.Fprintf(&b, "# TYPE cortex_store_lock_wait_seconds_total counter\n")
+	fmt.Fprintf(&b, "cortex_store_lock_wait_seconds_total %.3f\n", lock.Wait.Seconds())
+	fmt.Fprintf(&b, "# HELP cortex_store_busy_retries_total Store statements retried after SQLITE_BUSY\n")
+	fmt.Fprintf(&b, "# TYPE cortex_store_busy_retries_total counter\n")
+	fmt.Fprintf(&b, "cortex_store_busy_retries_total %d\n", lock.Retries)
+	fmt.Fprintf(&b, "# HELP cortex_store_busy_failures_total Store statements still busy after every retry\n")
+	fmt.Fprintf(&b, "# TYPE cortex_store_busy_failures_total counter\n")
+	fmt.Fprintf(&b, "cortex_store_busy_failures_total %d\n", lock.Exhausted)
+
+	// External CLIs (bd, gh, git) run through cmdexec, keyed by subcommand.
+	commands := cmdexec.Stats()
+	fmt.Fprintf(&b, "# HELP cortex_command_runs_total External command runs\n")
+	fmt.Fprintf(&b, "# TYPE cortex_command_runs_total counter\n")
+	for _, c := range commands {
+		fmt.Fprintf(&b, "cortex_command_runs_total{command=%q} %d\n", c.Command, c.Runs)
+	}
+	fmt.Fprintf(&b, "# HELP cortex_command_failures_total External command runs that failed, including timeouts\n")
+	fmt.Fprintf(&b, "# TYPE cortex_command_failures_total counter\n")
+	for _, c := range commands {
+		fmt.Fprintf(&b, "cortex_command_failures_total{command=%q} %d\n", c.Command, c.Failures)
+	}
+	fmt.Fprintf(&b, "# HELP cortex_command_timeouts_total External command runs killed at their timeout\n")
+	fmt.Fprintf(&b, "# TYPE cortex_command_timeouts_total counter\n")
+	for _, c := range commands {
+		fmt.Fprintf(&b, "cortex_command_timeouts_total{command=%q} %d\n", c.Command, c.Timeouts)
+	}
+	fmt.Fprintf(&b, "# HELP cortex_command_duration_seconds_sum Total time spent in external command runs\n")
+	fmt.Fprintf(&b, "# TYPE cortex_command_duration_seconds_sum counter\n")
+	for _, c := range commands {
+		fmt.Fprintf(&b, "cortex_command_duration_seconds_sum{command=%q} %.3f\n", c.Command, c.Total.Seconds())
+	}
+	fmt.Fprintf(&b, "# HELP cortex_command_duration_seconds_max Longest external command run\n")
+	fmt.Fprintf(&b, "# TYPE cortex_command_duration_seconds_max gauge\n")
+	for _, c := range commands {
+		fmt.Fprintf(&b, "cortex_command_duration_seconds_max{command=%q} %.3f\n", c.Command, c.Max.Seconds())
+	}
+
+	beadCache := beads.ListCacheStats()
+	fmt.Fprintf(&b, "# HELP cortex_beads_list_cache_hits_total Bead lists served from memory\n")
+	fmt.Fprintf(&b, "# TYPE cortex_beads_list_cache_hits_total counter\n")
+	fmt.Fprintf(&b, "cortex_beads_list_cache_hits_total %d\n", beadCache.Hits)
+	fmt.Fprintf(&b, "# HELP cortex_beads_list_cache_misses_total Bead lists read from issues.jsonl or bd\n")
+	fmt.Fprintf(&b, "# TYPE cortex_beads_list_cache_misses_total counter\n")
+	fmt.Fprintf(&b, "cortex_beads_list_cache_misses_total %d\n", beadCache.Misses)
+
+	logs := dispatch.CurrentLogStats()
+	fmt.Fprintf(&b, "# HELP cortex_dispatch_log_bytes Size of the dispatch log directory at the last sweep\n")
+	fmt.Fprintf(&b, "# TYPE cortex_dispatch_log_bytes gauge\n")
+	fmt.Fprintf(&b, "cortex_dispatch_log_bytes %d\n", logs.Bytes)
+	fmt.Fprintf(&b, "# HELP cortex_dispatch_log_files Files in the dispatch log directory at the last sweep\n")
+	fmt.Fprintf(&b, "# TYPE cortex_dispatch_log_files gauge\n")
+	fmt.Fprintf(&b, "cortex_dispatch_log_files %d\n", logs.Files)
+	fmt.Fprintf(&b, "# HELP cortex_dispatch_logs_compressed_total Dispatch logs gzipped by the log janitor\n")
+	fmt.Fprintf(&b, "# TYPE cortex_dispatch_logs_compressed_total counter\n")
+	fmt.Fprintf(&b, "cortex_dispatch_logs_compressed_total %d\n", logs.Compressed)
+	fmt.Fprintf(&b, "# HELP cortex_dispatch_logs_deleted_total Dispatch logs deleted past retention\n")
+	fmt.Fprintf(&b, "# TYPE cortex_dispatch_logs_deleted_total counter\n")
+	fmt.Fprintf(&b, "cortex_dispatch_logs_deleted_total %d\n", logs.Deleted)
+
 	fmt.Fprintf(&b, "# HELP cortex_uptime_seconds Uptime in seconds\n")
 	fmt.Fprintf(&b, "# TYPE cortex_uptime_seconds gauge\n")
 	fmt.Fprintf(&b, "cortex_uptime_seconds %.0f\n", time.Since(s.startTime).Seconds())
@@ -244,8 +557,13 @@ func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
 }
 
 // GET /dispatches/{bead_id} — dispatch history for a bead
+// GET /dispatches/{bead_id}/prompts — coder prompts per attempt with diffs between retries
 func (s *Server) handleDispatchDetail(w http.ResponseWriter, r *http.Request) {
 	beadID := strings.TrimPrefix(r.URL.Path, "/dispatches/")
+	if id, ok := strings.CutSuffix(beadID, "/prompts"); ok {
+		s.handlePromptAttempts(w, r, id)
+		return
+	}
 	if beadID == "" {
 		writeError(w, http.StatusBadRequest, "bead_id required")
 		return
@@ -270,6 +588,7 @@ func (s *Server) handleDispatchDetail(w http.ResponseWriter, r *http.Request) {
 		DispatchedAt    string  `json:"dispatched_at"`
 		SessionName     string  `json:"session_name"`
 		OutputTail      string  `json:"output_tail"`
+		Redactions      int     `json:"redactions,omitempty"`
 		FailureCategory string  `json:"failure_category,omitempty"`
 		FailureSummary  string  `json:"failure_summary,omitempty"`
 	}
@@ -280,6 +599,10 @@ func (s *Server) handleDispatchDetail(w http.ResponseWriter, r *http.Request) {
 		if err != nil {
 			outputTail = ""
 		}
+		redactions, err := s.store.GetOutputRedactions(d.ID)
+		if err != nil {
+			redactions = 0
+		}
 
 		dispatchList = append(dispatchList, dispatchResponse{
 			ID:              d.ID,
@@ -293,6 +616,7 @@ func (s *Server) handleDispatchDetail(w http.ResponseWriter, r *http.Request) {
 			DispatchedAt:    d.DispatchedAt.Format(time.RFC3339),
 			SessionName:     d.SessionName,
 			OutputTail:      outputTail,
+			Redactions:      redactions,
 			FailureCategory: d.FailureCategory,
 			FailureSummary:  d.FailureSummary,
 		})
@@ -336,6 +660,152 @@ func (s *Server) handleRecommendations(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, resp)
 }
 
+// GET /learner/experiments - per-variant outcomes and significant winners
+func (s *Server) handleExperiments(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	results, err := learner.QueryExperimentResults(s.store.DB())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to query experiments")
+		return
+	}
+	if results == nil {
+		results = []learner.ExperimentResult{}
+	}
+
+	writeJSON(w, map[string]any{
+		"experiments":  results,
+		"count":        len(results),
+		"generated_at": time.Now(),
+	})
+}
+
+// GET /planning/scans?project=x&limit=20 - recent backlog candidate scans
+func (s *Server) handleCandidateScans(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	limit := 20
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= 200 {
+			limit = n
+		}
+	}
+
+	scans, err := s.store.ListCandidateScans(r.URL.Query().Get("project"), limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to query candidate scans")
+		return
+	}
+	if scans == nil {
+		scans = []store.CandidateScan{}
+	}
+
+	writeJSON(w, map[string]any{
+		"scans": scans,
+		"count": len(scans),
+	})
+}
+
+// GET /planning/scans/{id} - one scan with every candidate, its rank and reasoning
+func (s *Server) handleCandidateScanDetail(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	id, err := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, "/planning/scans/"), 10, 64)
+	if err != nil || id <= 0 {
+		writeError(w, http.StatusBadRequest, "invalid scan id")
+		return
+	}
+
+	scan, err := s.store.GetCandidateScan(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to query candidate scan")
+		return
+	}
+	if scan == nil {
+		writeError(w, http.StatusNotFound, "scan not found")
+		return
+	}
+	writeJSON(w, scan)
+}
+
+// GET /learner/failure-clusters?days=7&limit=10 - recurring failed-dispatch clusters
+func (s *Server) handleFailureClusters(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	days := 7
+	if v := r.URL.Query().Get("days"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= 90 {
+			days = n
+		}
+	}
+	limit := 10
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= 100 {
+			limit = n
+		}
+	}
+
+	since := time.Now().Add(-time.Duration(days) * 24 * time.Hour)
+	clusters, err := learner.TopFailureClusters(s.store.DB(), since, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to cluster failures")
+		return
+	}
+	if clusters == nil {
+		clusters = []learner.FailureCluster{}
+	}
+
+	writeJSON(w, map[string]any{
+		"days":         days,
+		"clusters":     clusters,
+		"count":        len(clusters),
+		"generated_at": time.Now(),
+	})
+}
+
+// GET /learner/velocity?project=name&sprints=6 - per-project sprint velocity, newest sprint first
+func (s *Server) handleVelocity(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	project := r.URL.Query().Get("project")
+	sprints := 6
+	if v := r.URL.Query().Get("sprints"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= 52 {
+			sprints = n
+		}
+	}
+
+	velocity, err := s.store.ListSprintVelocity(project, sprints)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load velocity")
+		return
+	}
+	if velocity == nil {
+		velocity = []store.SprintVelocity{}
+	}
+
+	writeJSON(w, map[string]any{
+		"project":  project,
+		"sprints":  sprints,
+		"velocity": velocity,
+	})
+}
+
 // --- Temporal Workflow Endpoints ---
 
 // POST /workflows/start — submit a task to Temporal
@@ -355,14 +825,22 @@ func (s *Server) handleWorkflowStart(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "bead_id and prompt are required")
 		return
 	}
+	s.routeDispatch(r.Context(), &req)
 	if req.Agent == "" {
 		req.Agent = "claude"
 	}
 	if req.WorkDir == "" {
 		req.WorkDir = "/tmp/workspace"
 	}
+	decision := journal.Entry{Source: "api", Project: req.Project, BeadID: req.BeadID, Agent: req.Agent, Provider: req.Provider}
+	if reason := s.dispatchHeld(r.Context(), req); reason != "" {
+		decision.Decision, decision.Reason = journal.Deny, reason
+		s.journalDecision(decision)
+		writeError(w, http.StatusConflict, "dispatch window closed for "+req.Project+": "+reason)
+		return
+	}
 
-	c, err := client.Dial(client.Options{HostPort: "127.0.0.1:7233"})
+	c, err := temporal.Dial(s.cfg.Temporal)
 	if err != nil {
 		s.logger.Error("failed to connect to temporal", "error", err)
 		writeError(w, http.StatusInternalServerError, "failed to connect to temporal")
@@ -370,18 +848,28 @@ func (s *Server) handleWorkflowStart(w http.ResponseWriter, r *http.Request) {
 	}
 	defer c.Close()
 
+	if reason := s.claimBead(r.Context(), &req); reason != "" {
+		decision.Decision, decision.Reason = journal.Deny, reason
+		s.journalDecision(decision)
+		writeError(w, http.StatusConflict, "bead "+req.BeadID+" not dispatched: "+reason)
+		return
+	}
+
 	wo := client.StartWorkflowOptions{
 		ID:        req.BeadID,
-		TaskQueue: "cortex-task-queue",
+		TaskQueue: s.cfg.Temporal.TaskQueue,
 	}
 
 	we, err := c.ExecuteWorkflow(context.Background(), wo, temporal.CortexAgentWorkflow, req)
 	if err != nil {
+		s.releaseClaim(r.Context(), req)
 		s.logger.Error("failed to start workflow", "error", err)
 		writeError(w, http.StatusInternalServerError, "failed to start workflow")
 		return
 	}
 
+	decision.Decision, decision.Workflow = journal.Admit, we.GetID()
+	s.journalDecision(decision)
 	s.logger.Info("workflow started", "workflow_id", we.GetID(), "run_id", we.GetRunID())
 
 	writeJSON(w, map[string]any{
@@ -391,6 +879,60 @@ func (s *Server) handleWorkflowStart(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// dispatchHeld returns why standing by for leadership, the project belonging
+// to another shard, low host resources, a maintenance window, a paused project, a missing approved plan or the project's schedule holds
+// a new dispatch, or "" if it may start. For the schedule, the bead is only looked up when the window is
+// closed and urgent priority-0 bugs may override it.
+func (s *Server) dispatchHeld(ctx context.Context, req temporal.TaskRequest) string {
+	if !s.elector.Leading() {
+		return "standby instance; dispatch through the active one"
+	}
+	if p, ok := s.cfg.Projects[req.Project]; ok && !s.cfg.OwnsProject(req.Project) {
+		return fmt.Sprintf("project runs on shard %s; dispatch through it", p.Shard)
+	}
+	if reason := s.hostGuard.Held(); reason != "" {
+		return reason
+	}
+	if w, until, ok := s.cfg.General.MaintenanceAt(time.Now()); ok {
+		return fmt.Sprintf("scheduler paused for maintenance window %s until %s", w.Label(), until.Format(time.RFC3339))
+	}
+	proj, ok := s.cfg.Projects[req.Project]
+	if !ok {
+		return ""
+	}
+	if proj.Paused {
+		return "project paused in config"
+	}
+	if pause, err := s.store.GetProjectPause(req.Project); err != nil {
+		s.logger.Warn("failed to read project pause", "project", req.Project, "error", err)
+	} else if pause != nil {
+		if pause.PausedBy == "" {
+			return "project paused"
+		}
+		return "project paused by " + pause.PausedBy
+	}
+	if s.cfg.Chief.RequireApprovedPlan {
+		if reason := s.planGateHeld(ctx, proj, req); reason != "" {
+			return reason
+		}
+	}
+	now := time.Now()
+	allowed, reason := proj.Schedule.DispatchAllowed(now, false)
+	if allowed || !proj.Schedule.UrgentOverride {
+		return reason
+	}
+	bead, err := beads.ShowBeadCtx(ctx, proj.BeadsDir, req.BeadID)
+	if err != nil {
+		s.logger.Warn("failed to read bead for urgent override", "project", req.Project, "bead", req.BeadID, "error", err)
+		return reason
+	}
+	if allowed, _ := proj.Schedule.DispatchAllowed(now, bead.Priority == 0 && bead.Type == "bug"); allowed {
+		s.logger.Info("urgent bug dispatched outside project schedule", "project", req.Project, "bead", req.BeadID, "held_for", reason)
+		return ""
+	}
+	return reason
+}
+
 // routeWorkflows routes /workflows/{id}/* to the appropriate handler
 func (s *Server) routeWorkflows(w http.ResponseWriter, r *http.Request) {
 	path := strings.TrimPrefix(r.URL.Path, "/workflows/")
@@ -408,6 +950,20 @@ func (s *Server) routeWorkflows(w http.ResponseWriter, r *http.Request) {
 	s.handleWorkflowStatus(w, r)
 }
 
+// routeDispatches dispatches /api/v1/dispatches/{id}/{action}.
+func (s *Server) routeDispatches(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/dispatches/"), "/")
+	if _, rest, _ := strings.Cut(path, "/"); rest == "artifacts" || strings.HasPrefix(rest, "artifacts/") {
+		s.handleDispatchArtifacts(w, r)
+		return
+	}
+	if _, rest, _ := strings.Cut(path, "/"); rest == "report" {
+		s.handleDispatchReport(w, r)
+		return
+	}
+	s.handleDispatchRetry(w, r)
+}
+
 // POST /workflows/{id}/approve — send human-approval signal
 func (s *Server) handleWorkflowApprove(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -418,7 +974,7 @@ func (s *Server) handleWorkflowApprove(w http.ResponseWriter, r *http.Request) {
 	path := strings.TrimPrefix(r.URL.Path, "/workflows/")
 	workflowID := strings.TrimSuffix(path, "/approve")
 
-	c, err := client.Dial(client.Options{HostPort: "127.0.0.1:7233"})
+	c, err := temporal.Dial(s.cfg.Temporal)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "failed to connect to temporal")
 		return
@@ -445,7 +1001,7 @@ func (s *Server) handleWorkflowReject(w http.ResponseWriter, r *http.Request) {
 	path := strings.TrimPrefix(r.URL.Path, "/workflows/")
 	workflowID := strings.TrimSuffix(path, "/reject")
 
-	c, err := client.Dial(client.Options{HostPort: "127.0.0.1:7233"})
+	c, err := temporal.Dial(s.cfg.Temporal)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "failed to connect to temporal")
 		return
@@ -475,7 +1031,7 @@ func (s *Server) handleWorkflowStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	c, err := client.Dial(client.Options{HostPort: "127.0.0.1:7233"})
+	c, err := temporal.Dial(s.cfg.Temporal)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "failed to connect to temporal")
 		return
@@ -531,7 +1087,7 @@ func (s *Server) handlePlanningStart(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	c, err := client.Dial(client.Options{HostPort: "127.0.0.1:7233"})
+	c, err := temporal.Dial(s.cfg.Temporal)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "failed to connect to temporal")
 		return
@@ -541,7 +1097,7 @@ func (s *Server) handlePlanningStart(w http.ResponseWriter, r *http.Request) {
 	sessionID := fmt.Sprintf("planning-%s-%d", req.Project, time.Now().Unix())
 	wo := client.StartWorkflowOptions{
 		ID:        sessionID,
-		TaskQueue: "cortex-task-queue",
+		TaskQueue: s.cfg.Temporal.TaskQueue,
 	}
 
 	we, err := c.ExecuteWorkflow(context.Background(), wo, temporal.PlanningCeremonyWorkflow, req)
@@ -603,7 +1159,7 @@ func (s *Server) handlePlanningSignal(w http.ResponseWriter, r *http.Request, si
 		return
 	}
 
-	c, err := client.Dial(client.Options{HostPort: "127.0.0.1:7233"})
+	c, err := temporal.Dial(s.cfg.Temporal)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "failed to connect to temporal")
 		return
@@ -636,7 +1192,7 @@ func (s *Server) handlePlanningStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	c, err := client.Dial(client.Options{HostPort: "127.0.0.1:7233"})
+	c, err := temporal.Dial(s.cfg.Temporal)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "failed to connect to temporal")
 		return
@@ -3,16 +3,22 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/antigravity-dev/cortex/internal/cmdexec"
 	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/health"
+	"github.com/antigravity-dev/cortex/internal/journal"
 	"github.com/antigravity-dev/cortex/internal/store"
+	"github.com/antigravity-dev/cortex/internal/temporal"
 )
 
 func setupTestServer(t *testing.T) *Server {
@@ -32,6 +38,13 @@ func setupTestServer(t *testing.T) *Server {
 		General: config.General{
 			TickInterval: config.Duration{Duration: 60 * time.Second},
 		},
+		Temporal: config.Temporal{
+			Host:      "127.0.0.1",
+			Port:      7233,
+			Namespace: "default",
+			TaskQueue: "cortex-task-queue",
+			Schedules: config.TemporalSchedules{StrategicGroom: "0 5 * * *", FailureClusterReport: "0 6 * * 1"},
+		},
 	}
 
 	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
@@ -133,13 +146,58 @@ func TestHandleHealth(t *testing.T) {
 	}
 }
 
+func TestHandleHealthSeverityFilter(t *testing.T) {
+	srv := setupTestServer(t)
+	srv.store.RecordHealthEvent("dependency_down", "gh unavailable")
+	srv.store.RecordHealthEvent("cost_report", "monthly report")
+	srv.store.RecordHealthEvent("escalation_required", "bead needs a human")
+
+	get := func(query string) (int, []map[string]any) {
+		w := httptest.NewRecorder()
+		srv.handleHealth(w, httptest.NewRequest(http.MethodGet, "/health"+query, nil))
+		var resp struct {
+			RecentEvents []map[string]any `json:"recent_events"`
+		}
+		json.NewDecoder(w.Body).Decode(&resp)
+		return w.Code, resp.RecentEvents
+	}
+
+	if _, events := get(""); len(events) != 3 {
+		t.Fatalf("unfiltered events = %v", events)
+	}
+	_, events := get("?severity=warn,critical")
+	if len(events) != 2 {
+		t.Fatalf("warn and critical events = %v", events)
+	}
+	for _, e := range events {
+		if e["severity"] == "info" {
+			t.Fatalf("info event %v not filtered out", e)
+		}
+	}
+	if code, _ := get("?severity=page"); code != http.StatusBadRequest {
+		t.Fatalf("unknown severity: expected 400, got %d", code)
+	}
+}
+
 func TestHandleMetrics(t *testing.T) {
 	srv := setupTestServer(t)
 
-	_, err := srv.store.RecordDispatch("metric-bead", "test-proj", "test-proj-coder", "claude-sonnet-4", "balanced", 777, "metric-sess", "prompt", "", "", "")
+	id, err := srv.store.RecordDispatch("metric-bead", "test-proj", "test-proj-coder", "claude-sonnet-4", "balanced", 777, "metric-sess", "prompt", "", "", "")
 	if err != nil {
 		t.Fatal(err)
 	}
+	if err := srv.store.UpdateDispatchPR(id, "https://github.com/o/r/pull/3", 3); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := srv.store.ListPendingPRReviews(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cmdexec.Run(context.Background(), cmdexec.Cmd{Name: "true"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := srv.store.EnqueueOverflowItem("queued-bead", "test-proj", "coder", "test-proj-coder", 1, "role_limit"); err != nil {
+		t.Fatal(err)
+	}
 
 	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
 	w := httptest.NewRecorder()
@@ -156,6 +214,27 @@ func TestHandleMetrics(t *testing.T) {
 	if !strings.Contains(body, "cortex_uptime_seconds") {
 		t.Fatal("missing cortex_uptime_seconds metric")
 	}
+	if !strings.Contains(body, `cortex_reviews_pending{project="test-proj"} 1`) {
+		t.Fatal("missing cortex_reviews_pending metric")
+	}
+	if !strings.Contains(body, "cortex_store_lock_wait_seconds_total 0.000") || !strings.Contains(body, "cortex_store_busy_retries_total 0") {
+		t.Fatal("missing store lock wait metrics")
+	}
+	if !strings.Contains(body, `cortex_command_runs_total{command="true"}`) {
+		t.Fatal("missing cortex_command_runs_total metric")
+	}
+	if !strings.Contains(body, "cortex_beads_list_cache_hits_total") {
+		t.Fatal("missing cortex_beads_list_cache_hits_total metric")
+	}
+	if !strings.Contains(body, "cortex_dispatch_log_bytes") {
+		t.Fatal("missing cortex_dispatch_log_bytes metric")
+	}
+	if !strings.Contains(body, `cortex_overflow_wait_seconds{role="coder",quantile="0.95"}`) {
+		t.Fatal("missing cortex_overflow_wait_seconds metric")
+	}
+	if !strings.Contains(body, `cortex_concurrency_limit{role="coder"}`) {
+		t.Fatal("missing cortex_concurrency_limit metric")
+	}
 }
 
 func TestServerStartStop(t *testing.T) {
@@ -177,3 +256,438 @@ func TestServerStartStop(t *testing.T) {
 		t.Fatalf("server error: %v", err)
 	}
 }
+
+func TestBeadAttachmentsUploadListDownload(t *testing.T) {
+	srv := setupTestServer(t)
+	beadsDir := t.TempDir()
+	srv.cfg.Projects["test-proj"] = config.Project{Enabled: true, BeadsDir: beadsDir}
+
+	req := httptest.NewRequest(http.MethodPost, "/projects/test-proj/beads/cortex-1/attachments?name=trace.log", strings.NewReader("panic: boom"))
+	w := httptest.NewRecorder()
+	srv.handleProjectDetail(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("upload: expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/projects/test-proj/beads/cortex-1/attachments", nil)
+	w = httptest.NewRecorder()
+	srv.handleProjectDetail(w, req)
+	var list []map[string]any
+	if err := json.NewDecoder(w.Body).Decode(&list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0]["name"] != "trace.log" {
+		t.Fatalf("unexpected list: %v", list)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/projects/test-proj/beads/cortex-1/attachments/trace.log", nil)
+	w = httptest.NewRecorder()
+	srv.handleProjectDetail(w, req)
+	if w.Code != http.StatusOK || w.Body.String() != "panic: boom" {
+		t.Fatalf("download: got %d %q", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/projects/test-proj/beads/cortex-1/attachments/missing.png", nil)
+	w = httptest.NewRecorder()
+	srv.handleProjectDetail(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("missing attachment: expected 404, got %d", w.Code)
+	}
+}
+
+func TestBeadAttachmentDownloadOnlyShowsImagesInline(t *testing.T) {
+	srv := setupTestServer(t)
+	beadsDir := t.TempDir()
+	srv.cfg.Projects["test-proj"] = config.Project{Enabled: true, BeadsDir: beadsDir}
+
+	tests := []struct {
+		name, body, contentType, disposition string
+	}{
+		{"page.html", "<script>alert(1)</script>", "application/octet-stream", "attachment"},
+		{"logo.svg", "<svg onload=\"alert(1)\"/>", "application/octet-stream", "attachment"},
+		{"shot.png", "\x89PNG\r\n", "image/png", "inline"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/projects/test-proj/beads/cortex-1/attachments?name="+tt.name, strings.NewReader(tt.body))
+		w := httptest.NewRecorder()
+		srv.handleProjectDetail(w, req)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("upload %s: expected 201, got %d: %s", tt.name, w.Code, w.Body.String())
+		}
+
+		req = httptest.NewRequest(http.MethodGet, "/projects/test-proj/beads/cortex-1/attachments/"+tt.name, nil)
+		w = httptest.NewRecorder()
+		srv.handleProjectDetail(w, req)
+		if got := w.Header().Get("Content-Type"); got != tt.contentType {
+			t.Fatalf("%s: Content-Type = %q, want %q", tt.name, got, tt.contentType)
+		}
+		if got := w.Header().Get("Content-Disposition"); !strings.HasPrefix(got, tt.disposition+";") {
+			t.Fatalf("%s: Content-Disposition = %q, want %s", tt.name, got, tt.disposition)
+		}
+		if got := w.Header().Get("Content-Security-Policy"); got != "sandbox" {
+			t.Fatalf("%s: Content-Security-Policy = %q, want sandbox", tt.name, got)
+		}
+	}
+}
+
+func TestHandleExperiments(t *testing.T) {
+	srv := setupTestServer(t)
+	for _, variant := range []string{"control", "terse"} {
+		id, err := srv.store.RecordExperimentAssignment("exec-prompt", variant, "coder", "bead-1", "proj")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := srv.store.CompleteExperimentRun(id, 1, variant == "terse", true, 30, 0.1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/learner/experiments", nil)
+	w := httptest.NewRecorder()
+	srv.handleExperiments(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var resp struct {
+		Count       int `json:"count"`
+		Experiments []struct {
+			Experiment  string `json:"experiment"`
+			Significant bool   `json:"significant"`
+			Variants    []struct {
+				Variant string `json:"variant"`
+				Runs    int    `json:"runs"`
+			} `json:"variants"`
+		} `json:"experiments"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Count != 1 || resp.Experiments[0].Experiment != "exec-prompt" || resp.Experiments[0].Significant {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if len(resp.Experiments[0].Variants) != 2 || resp.Experiments[0].Variants[0].Variant != "terse" {
+		t.Fatalf("expected terse ranked first: %+v", resp.Experiments[0].Variants)
+	}
+}
+
+func TestHandleCandidateScans(t *testing.T) {
+	srv := setupTestServer(t)
+	id, err := srv.store.RecordCandidateScan(store.CandidateScan{
+		Project:   "test-proj",
+		SessionID: "planning-test-proj-1",
+		Candidates: []store.ScannedCandidate{
+			{ItemID: "auth", Recommended: true, Rationale: "blocks logins"},
+			{ItemID: "docs", Rationale: "nobody waiting"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.store.RecordScanSelection(id, "auth"); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/planning/scans?project=test-proj", nil)
+	w := httptest.NewRecorder()
+	srv.handleCandidateScans(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var list struct {
+		Count int `json:"count"`
+		Scans []struct {
+			ID         int64  `json:"id"`
+			SelectedID string `json:"selected_id"`
+		} `json:"scans"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&list); err != nil {
+		t.Fatal(err)
+	}
+	if list.Count != 1 || list.Scans[0].ID != id || list.Scans[0].SelectedID != "auth" {
+		t.Fatalf("unexpected list response: %+v", list)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/planning/scans/%d", id), nil)
+	w = httptest.NewRecorder()
+	srv.handleCandidateScanDetail(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var scan store.CandidateScan
+	if err := json.NewDecoder(w.Body).Decode(&scan); err != nil {
+		t.Fatal(err)
+	}
+	if len(scan.Candidates) != 2 || !scan.Candidates[0].Selected || scan.Candidates[1].Rationale != "nobody waiting" {
+		t.Fatalf("unexpected scan detail: %+v", scan)
+	}
+
+	for path, code := range map[string]int{"/planning/scans/999": http.StatusNotFound, "/planning/scans/abc": http.StatusBadRequest} {
+		req = httptest.NewRequest(http.MethodGet, path, nil)
+		w = httptest.NewRecorder()
+		srv.handleCandidateScanDetail(w, req)
+		if w.Code != code {
+			t.Errorf("%s: expected %d, got %d", path, code, w.Code)
+		}
+	}
+}
+
+func TestHandleFailureClusters(t *testing.T) {
+	srv := setupTestServer(t)
+	for _, project := range []string{"a", "b"} {
+		id, err := srv.store.RecordDispatch("bead-"+project, project, "claude", "claude", "temporal", 0, "", "", "", "", "temporal")
+		if err != nil {
+			t.Fatal(err)
+		}
+		srv.store.UpdateDispatchStatus(id, "failed", 1, 1)
+		srv.store.RecordDoDResult(id, "bead-"+project, project, false, "error: build failed", "")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/learner/failure-clusters?days=1&limit=3", nil)
+	w := httptest.NewRecorder()
+	srv.handleFailureClusters(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var resp struct {
+		Days     int `json:"days"`
+		Count    int `json:"count"`
+		Clusters []struct {
+			Count    int      `json:"count"`
+			Projects []string `json:"projects"`
+		} `json:"clusters"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Days != 1 || resp.Count != 1 || resp.Clusters[0].Count != 2 || len(resp.Clusters[0].Projects) != 2 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestHandleVelocity(t *testing.T) {
+	srv := setupTestServer(t)
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	for sprint := 1; sprint <= 3; sprint++ {
+		for _, project := range []string{"test-proj", "other"} {
+			if err := srv.store.UpsertSprintVelocity(store.SprintVelocity{
+				Project: project, SprintNumber: sprint, SprintStart: start, SprintEnd: start, BeadsClosed: sprint,
+			}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	w := httptest.NewRecorder()
+	srv.handleVelocity(w, httptest.NewRequest(http.MethodGet, "/learner/velocity?project=test-proj&sprints=2", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var resp struct {
+		Sprints  int                    `json:"sprints"`
+		Velocity []store.SprintVelocity `json:"velocity"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Sprints != 2 || len(resp.Velocity) != 2 || resp.Velocity[0].SprintNumber != 3 || resp.Velocity[0].BeadsClosed != 3 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestHandleStatusDetail(t *testing.T) {
+	srv := setupTestServer(t)
+	srv.cfg.Projects["test-proj"] = config.Project{Enabled: true, SprintPlanningDay: "Monday", SprintPlanningTime: "09:00"}
+
+	running, err := srv.store.RecordDispatch("bead-run", "test-proj", "agent-1", "claude", "fast", 100, "", "prompt", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	failed, err := srv.store.RecordDispatch("bead-fail", "test-proj", "agent-1", "claude", "fast", 101, "", "prompt", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.store.UpdateDispatchStatus(failed, "failed", 1, 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.store.UpdateFailureDiagnosis(failed, "test_failure", "tests failed"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := srv.store.EnqueueOverflowItem("bead-queued", "test-proj", "reviewer", "agent-1", 1, "role_limit"); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/status?detail=1", nil)
+	w := httptest.NewRecorder()
+	srv.handleStatus(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var resp StatusDetail
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.RunningCount != 1 || len(resp.Queue.Running) != 1 || resp.Queue.Running[0].ID != running {
+		t.Fatalf("unexpected dispatch queue: %+v", resp.Queue)
+	}
+	if len(resp.RecentFailures) != 1 || resp.RecentFailures[0].FailureSummary != "tests failed" {
+		t.Fatalf("unexpected recent failures: %+v", resp.RecentFailures)
+	}
+	if resp.Queue.Overflow != 1 || len(resp.Queue.OverflowWaits) != 1 || resp.Queue.OverflowWaits[0].Role != "reviewer" || resp.Queue.OverflowWaits[0].Queued != 1 {
+		t.Fatalf("unexpected overflow waits: %+v", resp.Queue)
+	}
+	if c := resp.Concurrency; c.Autoscaled || c.Coders != srv.cfg.General.MaxConcurrentCoders || c.Reviewers != srv.cfg.General.MaxConcurrentReviewers {
+		t.Fatalf("unexpected concurrency: %+v", c)
+	}
+	names := map[string]string{}
+	for _, sc := range resp.Schedules {
+		names[sc.Name] = sc.Schedule
+	}
+	if names["strategic-groom-test-proj"] != "0 5 * * *" || names["sprint-planning"] != "Monday 09:00" {
+		t.Fatalf("unexpected schedules: %+v", resp.Schedules)
+	}
+	if resp.Workflows == nil && resp.WorkflowsError == "" {
+		t.Fatal("expected workflows or workflows_error")
+	}
+}
+
+func TestHandleWorkflowStartHeldBySchedule(t *testing.T) {
+	srv := setupTestServer(t)
+	proj := srv.cfg.Projects["test-proj"]
+	proj.Schedule = config.ProjectSchedule{
+		Blackouts: []config.BlackoutWindow{{
+			Start:  time.Now().UTC().Add(-time.Hour).Format("2006-01-02 15:04"),
+			End:    time.Now().UTC().Add(time.Hour).Format("2006-01-02 15:04"),
+			Reason: "maintenance",
+		}},
+	}
+	srv.cfg.Projects["test-proj"] = proj
+	journalDir := t.TempDir()
+	jr, err := journal.Open(journalDir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer jr.Close()
+	srv.SetJournal(jr)
+
+	body := `{"bead_id":"cx-1","project":"test-proj","prompt":"fix it"}`
+	w := httptest.NewRecorder()
+	srv.handleWorkflowStart(w, httptest.NewRequest(http.MethodPost, "/workflows/start", strings.NewReader(body)))
+	if w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), "maintenance") {
+		t.Fatalf("expected dispatch held by blackout, got %d: %s", w.Code, w.Body.String())
+	}
+	data, err := os.ReadFile(filepath.Join(journalDir, "decisions-"+time.Now().UTC().Format(time.DateOnly)+".jsonl"))
+	if err != nil {
+		t.Fatalf("read journal: %v", err)
+	}
+	var denial journal.Entry
+	if err := json.Unmarshal(data, &denial); err != nil || denial.Decision != journal.Deny || denial.BeadID != "cx-1" || !strings.Contains(denial.Reason, "maintenance") {
+		t.Fatalf("expected journaled denial, got %s (%v)", data, err)
+	}
+
+	// Priority-0 bugs bypass the schedule when the project allows it.
+	fakeBin := t.TempDir()
+	script := "#!/bin/sh\necho '{\"id\":\"cx-1\",\"priority\":0,\"issue_type\":\"bug\"}'\n"
+	if err := os.WriteFile(fakeBin+"/bd", []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+	held := func() string {
+		return srv.dispatchHeld(context.Background(), temporal.TaskRequest{BeadID: "cx-1", Project: "test-proj"})
+	}
+	if reason := held(); !strings.Contains(reason, "maintenance") {
+		t.Fatalf("expected hold without urgent_override, got %q", reason)
+	}
+	proj.Schedule.UrgentOverride = true
+	srv.cfg.Projects["test-proj"] = proj
+	if reason := held(); reason != "" {
+		t.Fatalf("expected urgent bug to bypass the schedule, got %q", reason)
+	}
+	if reason := srv.dispatchHeld(context.Background(), temporal.TaskRequest{BeadID: "cx-1", Project: "unknown"}); reason != "" {
+		t.Fatalf("expected unknown project to be unrestricted, got %q", reason)
+	}
+}
+
+func TestDispatchHeldWhilePaused(t *testing.T) {
+	srv := setupTestServer(t)
+	req := temporal.TaskRequest{BeadID: "cx-1", Project: "test-proj"}
+
+	if err := srv.store.PauseProject("test-proj", "@alice:example.org"); err != nil {
+		t.Fatal(err)
+	}
+	if reason := srv.dispatchHeld(context.Background(), req); reason != "project paused by @alice:example.org" {
+		t.Fatalf("expected paused project to hold dispatch, got %q", reason)
+	}
+	if _, err := srv.store.ResumeProject("test-proj"); err != nil {
+		t.Fatal(err)
+	}
+	if reason := srv.dispatchHeld(context.Background(), req); reason != "" {
+		t.Fatalf("expected resumed project to dispatch, got %q", reason)
+	}
+}
+
+func TestDispatchHeldOnLowHostResources(t *testing.T) {
+	srv := setupTestServer(t)
+	req := temporal.TaskRequest{BeadID: "cx-1", Project: "test-proj"}
+	guard := &health.HostGuard{}
+	srv.SetHostGuard(guard)
+
+	guard.Update(health.HostStatus{Breaches: []string{"disk /tmp/ws has 10 MB free, below 2048 MB"}})
+	if reason := srv.dispatchHeld(context.Background(), req); !strings.HasPrefix(reason, "host resources low: disk /tmp/ws") {
+		t.Fatalf("expected low disk to hold dispatch, got %q", reason)
+	}
+	guard.Update(health.HostStatus{})
+	if reason := srv.dispatchHeld(context.Background(), req); reason != "" {
+		t.Fatalf("expected recovered host to dispatch, got %q", reason)
+	}
+}
+
+func TestDispatchHeldDuringMaintenanceWindow(t *testing.T) {
+	srv := setupTestServer(t)
+	req := temporal.TaskRequest{BeadID: "cx-1", Project: "test-proj"}
+
+	srv.cfg.General.MaintenanceWindows = []config.MaintenanceWindow{{Name: "host upgrade", Cron: "* * * * *", Duration: config.Duration{Duration: time.Hour}}}
+	if reason := srv.dispatchHeld(context.Background(), req); !strings.HasPrefix(reason, "scheduler paused for maintenance window host upgrade until ") {
+		t.Fatalf("expected maintenance window to hold dispatch, got %q", reason)
+	}
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1).Weekday().String()
+	srv.cfg.General.MaintenanceWindows = []config.MaintenanceWindow{{Days: []string{tomorrow}, Hours: "00:00-23:59"}}
+	if reason := srv.dispatchHeld(context.Background(), req); reason != "" {
+		t.Fatalf("expected dispatch outside the window, got %q", reason)
+	}
+}
+
+func TestHandleDependencies(t *testing.T) {
+	srv := setupTestServer(t)
+	get := func() (int, map[string]any) {
+		w := httptest.NewRecorder()
+		srv.handleDependencies(w, httptest.NewRequest(http.MethodGet, "/api/v1/health/dependencies", nil))
+		var body map[string]any
+		json.NewDecoder(w.Body).Decode(&body)
+		return w.Code, body
+	}
+
+	if code, _ := get(); code != http.StatusServiceUnavailable {
+		t.Fatalf("before any probe: code = %d, want 503", code)
+	}
+
+	monitor := &health.DependencyMonitor{}
+	srv.SetDependencies(monitor)
+	missing := func(context.Context) error { return fmt.Errorf("not found") }
+	monitor.Update(health.CheckDependencies(context.Background(), []health.Dependency{
+		{Name: "bd", Required: true, Probe: func(context.Context) error { return nil }},
+		{Name: "glab", Probe: missing},
+	}, time.Second))
+	if code, body := get(); code != http.StatusOK || body["healthy"] != true || len(body["failing"].([]any)) != 1 {
+		t.Fatalf("optional dependency down: code = %d, body = %v", code, body)
+	}
+
+	monitor.Update(health.CheckDependencies(context.Background(), []health.Dependency{
+		{Name: "bd", Required: true, Probe: missing},
+	}, time.Second))
+	if code, body := get(); code != http.StatusServiceUnavailable || body["healthy"] != false {
+		t.Fatalf("required dependency down: code = %d, body = %v", code, body)
+	}
+}
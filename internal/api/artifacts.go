@@ -0,0 +1,72 @@
+package api
+
+import (
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+// GET /api/v1/dispatches/{id}/artifacts
+// GET /api/v1/dispatches/{id}/artifacts/{artifact_id}
+// Lists the files collected from a dispatch's workspace, or downloads one.
+func (s *Server) handleDispatchArtifacts(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/dispatches/"), "/"), "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[1] != "artifacts" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	dispatchID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || dispatchID <= 0 {
+		writeError(w, http.StatusBadRequest, "dispatch id must be a positive integer")
+		return
+	}
+
+	if len(parts) == 2 {
+		artifacts, err := s.store.ListArtifacts(dispatchID)
+		if err != nil {
+			s.logger.Error("failed to list artifacts", "dispatch", dispatchID, "error", err)
+			writeError(w, http.StatusInternalServerError, "failed to list artifacts")
+			return
+		}
+		if artifacts == nil {
+			artifacts = []store.Artifact{}
+		}
+		writeJSON(w, map[string]any{
+			"dispatch_id": dispatchID,
+			"artifacts":   artifacts,
+		})
+		return
+	}
+
+	artifactID, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || artifactID <= 0 {
+		writeError(w, http.StatusBadRequest, "artifact id must be a positive integer")
+		return
+	}
+	a, err := s.store.GetArtifact(artifactID)
+	if err != nil {
+		s.logger.Error("failed to get artifact", "artifact", artifactID, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to get artifact")
+		return
+	}
+	if a == nil || a.DispatchID != dispatchID {
+		writeError(w, http.StatusNotFound, "artifact not found")
+		return
+	}
+	f, err := os.Open(a.StoredPath)
+	if err != nil {
+		writeError(w, http.StatusGone, "artifact file is no longer stored")
+		return
+	}
+	defer f.Close()
+	w.Header().Set("Content-Disposition", `attachment; filename="`+strings.ReplaceAll(path.Base(a.Path), `"`, "")+`"`)
+	http.ServeContent(w, r, path.Base(a.Path), a.CollectedAt, f)
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestHandleDispatchArtifacts(t *testing.T) {
+	srv := setupTestServer(t)
+	stored := filepath.Join(t.TempDir(), "coverage.out")
+	if err := os.WriteFile(stored, []byte("mode: set"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	id, err := srv.store.RecordArtifact(store.Artifact{DispatchID: 5, Project: "test-proj", BeadID: "cx-1", Path: "coverage.out", StoredPath: stored, SizeBytes: 9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gone, err := srv.store.RecordArtifact(store.Artifact{DispatchID: 5, Project: "test-proj", BeadID: "cx-1", Path: "old.xml", StoredPath: stored + ".missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	srv.routeDispatches(w, httptest.NewRequest(http.MethodGet, "/api/v1/dispatches/5/artifacts", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Artifacts []store.Artifact `json:"artifacts"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Artifacts) != 2 || resp.Artifacts[0].Path != "coverage.out" {
+		t.Fatalf("unexpected artifacts %+v", resp.Artifacts)
+	}
+
+	w = httptest.NewRecorder()
+	srv.routeDispatches(w, httptest.NewRequest(http.MethodGet, "/api/v1/dispatches/5/artifacts/"+strconv.FormatInt(id, 10), nil))
+	if w.Code != http.StatusOK || w.Body.String() != "mode: set" {
+		t.Fatalf("expected the file, got %d: %s", w.Code, w.Body.String())
+	}
+
+	cases := []struct {
+		method, path string
+		want         int
+	}{
+		{http.MethodPost, "/api/v1/dispatches/5/artifacts", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/api/v1/dispatches/x/artifacts", http.StatusBadRequest},
+		{http.MethodGet, "/api/v1/dispatches/6/artifacts/" + strconv.FormatInt(id, 10), http.StatusNotFound},
+		{http.MethodGet, "/api/v1/dispatches/5/artifacts/999", http.StatusNotFound},
+		{http.MethodGet, "/api/v1/dispatches/5/artifacts/" + strconv.FormatInt(gone, 10), http.StatusGone},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		srv.routeDispatches(w, httptest.NewRequest(tc.method, tc.path, nil))
+		if w.Code != tc.want {
+			t.Errorf("%s %s: expected %d, got %d", tc.method, tc.path, tc.want, w.Code)
+		}
+	}
+}
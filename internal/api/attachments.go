@@ -0,0 +1,122 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/antigravity-dev/cortex/internal/beads"
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+// routeProjectBeads handles /projects/{project}/beads/{bead_id}/attachments[/{name}].
+func (s *Server) routeProjectBeads(w http.ResponseWriter, r *http.Request, project, rest string) {
+	parts := strings.Split(strings.Trim(rest, "/"), "/")
+	if len(parts) < 3 || parts[0] != "beads" || parts[2] != "attachments" || len(parts) > 4 {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	proj, ok := s.cfg.Projects[project]
+	if !ok {
+		writeError(w, http.StatusNotFound, "project not found")
+		return
+	}
+	beadsDir := config.ExpandHome(strings.TrimSpace(proj.BeadsDir))
+	if beadsDir == "" {
+		writeError(w, http.StatusNotFound, "project has no beads_dir")
+		return
+	}
+	beadID := parts[1]
+
+	if len(parts) == 4 {
+		s.handleAttachmentDownload(w, r, beadsDir, beadID, parts[3])
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.handleAttachmentList(w, beadsDir, beadID)
+	case http.MethodPost:
+		s.authMiddleware.RequireAuth(func(w http.ResponseWriter, r *http.Request) {
+			s.handleAttachmentUpload(w, r, beadsDir, beadID)
+		})(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// GET /projects/{project}/beads/{bead_id}/attachments
+func (s *Server) handleAttachmentList(w http.ResponseWriter, beadsDir, beadID string) {
+	atts, err := beads.ListAttachments(beadsDir, beadID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if atts == nil {
+		atts = []beads.Attachment{}
+	}
+	writeJSON(w, atts)
+}
+
+// GET /projects/{project}/beads/{bead_id}/attachments/{name}
+func (s *Server) handleAttachmentDownload(w http.ResponseWriter, r *http.Request, beadsDir, beadID, name string) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	att, err := beads.GetAttachment(beadsDir, beadID, name)
+	if errors.Is(err, beads.ErrAttachmentNotFound) {
+		writeError(w, http.StatusNotFound, "attachment not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	// Only raster images are shown inline. Anything else, HTML and SVG
+	// included, is downloaded as opaque bytes so an uploaded file cannot run
+	// script on the API origin, and the sandbox policy backs that up.
+	contentType, disposition := "application/octet-stream", "attachment"
+	if inlineImageTypes[att.ContentType] {
+		contentType, disposition = att.ContentType, "inline"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "sandbox")
+	w.Header().Set("Content-Disposition", disposition+`; filename="`+filepath.Base(att.Path)+`"`)
+	http.ServeFile(w, r, att.Path)
+}
+
+// inlineImageTypes are the attachment types served inline.
+var inlineImageTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// POST /projects/{project}/beads/{bead_id}/attachments?name=<file name>
+// The request body is the raw file content.
+func (s *Server) handleAttachmentUpload(w http.ResponseWriter, r *http.Request, beadsDir, beadID string) {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "name query parameter is required")
+		return
+	}
+	if r.ContentLength > beads.MaxAttachmentBytes {
+		writeError(w, http.StatusRequestEntityTooLarge, "attachment too large")
+		return
+	}
+	att, err := beads.SaveAttachment(beadsDir, beadID, name, r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.logger.Info("bead attachment stored", "bead", beadID, "name", att.Name, "size", att.Size)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(att)
+}
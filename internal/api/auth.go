@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -162,22 +163,75 @@ func isControlEndpoint(method, path string) bool {
 	}
 
 	// Check for dispatch control endpoints with patterns
-	if strings.HasPrefix(path, "/dispatches/") {
+	if strings.HasPrefix(path, "/dispatches/") || strings.HasPrefix(path, "/api/v1/dispatches/") {
 		if strings.HasSuffix(path, "/cancel") || strings.HasSuffix(path, "/retry") {
 			return true
 		}
 	}
 
+	// Health events posted by external monitors feed alerting and routing
+	if path == "/health/events" {
+		return true
+	}
+
+	// Manual stage transitions move beads through their workflow
+	if strings.HasPrefix(path, "/api/v1/beads/") && strings.HasSuffix(path, "/stage") {
+		return true
+	}
+
+	// Resetting a circuit breaker lets dispatches reach a failing provider again
+	if strings.HasPrefix(path, "/api/v1/breakers/") && strings.HasSuffix(path, "/reset") {
+		return true
+	}
+
+	// Retiring an agent removes it from its project's team
+	if strings.HasPrefix(path, "/api/v1/team/") && strings.HasSuffix(path, "/retire") {
+		return true
+	}
+
+	// Project pauses hold or release a project's dispatches
+	if strings.HasPrefix(path, "/projects/") && (strings.HasSuffix(path, "/pause") || strings.HasSuffix(path, "/resume")) {
+		return true
+	}
+
+	// Strategic groom trigger, pause and resume run or hold a project's grooms
+	if strings.HasPrefix(path, "/groom/") {
+		return true
+	}
+
+	// Bead attachment uploads write into project beads dirs
+	if strings.HasPrefix(path, "/projects/") && strings.HasSuffix(path, "/attachments") {
+		return true
+	}
+
 	return false
 }
 
-// RequireAuth creates middleware that enforces authentication for control endpoints
+// isSensitiveRead checks if this is a read endpoint exposing more than
+// monitoring data, so it is protected like a control endpoint
+func isSensitiveRead(method string, u *url.URL) bool {
+	if method != http.MethodGet {
+		return false
+	}
+
+	// Bulk export carries the full dispatch history with costs, and search
+	// snippets expose captured agent output
+	if u.Path == "/api/v1/export/dispatches" || u.Path == "/api/v1/search" {
+		return true
+	}
+
+	// Full prompt bodies carry the task context sent to agents
+	return strings.HasPrefix(u.Path, "/dispatches/") && strings.HasSuffix(u.Path, "/prompts") && fullPrompts(u)
+}
+
+// RequireAuth creates middleware that enforces authentication for control
+// endpoints and sensitive reads
 func (am *AuthMiddleware) RequireAuth(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 
-		// Check if this is a control endpoint
-		if !isControlEndpoint(r.Method, r.URL.Path) {
+		// Check if this is a protected endpoint
+		if !isControlEndpoint(r.Method, r.URL.Path) && !isSensitiveRead(r.Method, r.URL) {
 			next(w, r)
 			return
 		}
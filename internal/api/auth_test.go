@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
 	"testing"
@@ -141,6 +142,105 @@ func TestAuthMiddleware_RequireAuth_TokenAuth(t *testing.T) {
 	}
 }
 
+// tokenAuthStatus sends an unauthenticated request through a token-protected
+// RequireAuth wrapper and returns the response status.
+func tokenAuthStatus(t *testing.T, method, path string) int {
+	t.Helper()
+	middleware, err := NewAuthMiddleware(&config.APISecurity{
+		Enabled:       true,
+		AllowedTokens: []string{"valid-token-123456"},
+	}, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	if err != nil {
+		t.Fatalf("failed to create auth middleware: %v", err)
+	}
+	defer middleware.Close()
+
+	handler := middleware.RequireAuth(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = "192.168.1.100:12345"
+	w := httptest.NewRecorder()
+	handler(w, req)
+	return w.Code
+}
+
+func TestRequireAuthRejectsUnauthenticatedHealthEvents(t *testing.T) {
+	if code := tokenAuthStatus(t, http.MethodPost, "/health/events"); code != http.StatusUnauthorized {
+		t.Fatalf("POST /health/events without token: expected 401, got %d", code)
+	}
+}
+
+func TestRequireAuthRejectsUnauthenticatedBreakerReset(t *testing.T) {
+	if code := tokenAuthStatus(t, http.MethodPost, "/api/v1/breakers/provider/codex/reset"); code != http.StatusUnauthorized {
+		t.Fatalf("POST /api/v1/breakers/provider/codex/reset without token: expected 401, got %d", code)
+	}
+}
+
+func TestRequireAuthRejectsUnauthenticatedDispatchRetry(t *testing.T) {
+	if code := tokenAuthStatus(t, http.MethodPost, "/api/v1/dispatches/42/retry"); code != http.StatusUnauthorized {
+		t.Fatalf("POST /api/v1/dispatches/42/retry without token: expected 401, got %d", code)
+	}
+}
+
+func TestRequireAuthRejectsUnauthenticatedGroomControl(t *testing.T) {
+	for _, action := range []string{"trigger", "pause", "resume"} {
+		path := "/groom/cortex/" + action
+		if code := tokenAuthStatus(t, http.MethodPost, path); code != http.StatusUnauthorized {
+			t.Fatalf("POST %s without token: expected 401, got %d", path, code)
+		}
+	}
+}
+
+func TestRequireAuthRejectsUnauthenticatedExport(t *testing.T) {
+	if code := tokenAuthStatus(t, http.MethodGet, "/api/v1/export/dispatches"); code != http.StatusUnauthorized {
+		t.Fatalf("GET /api/v1/export/dispatches without token: expected 401, got %d", code)
+	}
+}
+
+func TestRequireAuthRejectsUnauthenticatedSearch(t *testing.T) {
+	if code := tokenAuthStatus(t, http.MethodGet, "/api/v1/search?q=panic"); code != http.StatusUnauthorized {
+		t.Fatalf("GET /api/v1/search without token: expected 401, got %d", code)
+	}
+}
+
+func TestRequireAuthRejectsUnauthenticatedFullPrompts(t *testing.T) {
+	if code := tokenAuthStatus(t, http.MethodGet, "/dispatches/cx-1/prompts?full=1"); code != http.StatusUnauthorized {
+		t.Fatalf("GET /dispatches/cx-1/prompts?full=1 without token: expected 401, got %d", code)
+	}
+	if code := tokenAuthStatus(t, http.MethodGet, "/dispatches/cx-1/prompts"); code != http.StatusOK {
+		t.Fatalf("GET /dispatches/cx-1/prompts without token: expected 200, got %d", code)
+	}
+}
+
+func TestIsSensitiveRead(t *testing.T) {
+	tests := []struct {
+		method   string
+		path     string
+		expected bool
+	}{
+		{"GET", "/api/v1/export/dispatches", true},
+		{"POST", "/api/v1/export/dispatches", false},
+		{"GET", "/api/v1/dispatches", false},
+		{"GET", "/api/v1/search", true},
+		{"GET", "/status", false},
+		{"GET", "/dispatches/cx-1/prompts", false},
+		{"GET", "/dispatches/cx-1/prompts?full=1", true},
+		{"GET", "/dispatches/cx-1/prompts?full=true", true},
+		{"GET", "/dispatches/cx-1?full=1", false},
+	}
+
+	for _, tt := range tests {
+		u, err := url.Parse(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := isSensitiveRead(tt.method, u); actual != tt.expected {
+			t.Errorf("isSensitiveRead(%s, %s) = %v, expected %v", tt.method, tt.path, actual, tt.expected)
+		}
+	}
+}
+
 func TestAuthMiddleware_NonControlEndpoint(t *testing.T) {
 	cfg := &config.APISecurity{
 		Enabled: true,
@@ -257,6 +357,23 @@ func TestIsControlEndpoint(t *testing.T) {
 		{"POST", "/status", false},
 		{"POST", "/dispatches/123", false},
 		{"GET", "/dispatches/123/cancel", false},
+		{"POST", "/projects/p/beads/b-1/attachments", true},
+		{"POST", "/projects/p/pause", true},
+		{"POST", "/projects/p/resume", true},
+		{"POST", "/groom/p/trigger", true},
+		{"GET", "/groom/p", false},
+		{"GET", "/projects/p/beads/b-1/attachments", false},
+		{"POST", "/api/v1/beads/p/b-1/stage", true},
+		{"GET", "/api/v1/beads/p/b-1/stage", false},
+		{"POST", "/api/v1/team/p-coder/retire", true},
+		{"GET", "/api/v1/team/p-coder", false},
+		{"POST", "/health/events", true},
+		{"GET", "/health/events", false},
+		{"POST", "/api/v1/breakers/provider/codex/reset", true},
+		{"GET", "/api/v1/breakers", false},
+		{"POST", "/api/v1/dispatches/42/retry", true},
+		{"POST", "/api/v1/dispatches/42/cancel", true},
+		{"GET", "/api/v1/dispatches/42/report", false},
 	}
 	
 	for _, tt := range tests {
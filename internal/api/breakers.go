@@ -0,0 +1,55 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/antigravity-dev/cortex/internal/dispatch"
+)
+
+// GET /api/v1/breakers
+// Lists every failure-category breaker and each provider's breaker.
+func (s *Server) handleBreakers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	limiter := s.rateLimiter()
+	if limiter == nil {
+		writeError(w, http.StatusServiceUnavailable, "temporal worker not started")
+		return
+	}
+	writeJSON(w, limiter.CircuitBreakers(s.cfg.Providers))
+}
+
+// POST /api/v1/breakers/{provider|category}/{name}/reset
+// Closes a breaker and forgets its failures.
+func (s *Server) handleBreakerReset(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/breakers/"), "/"), "/")
+	if len(parts) != 3 || parts[2] != "reset" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	scope, name := parts[0], parts[1]
+	if scope == dispatch.BreakerScopeProvider {
+		if _, ok := s.cfg.Providers[name]; !ok {
+			writeError(w, http.StatusNotFound, "unknown provider")
+			return
+		}
+	}
+	limiter := s.rateLimiter()
+	if limiter == nil {
+		writeError(w, http.StatusServiceUnavailable, "temporal worker not started")
+		return
+	}
+	if !limiter.ResetCircuitBreaker(scope, name) {
+		writeError(w, http.StatusNotFound, "unknown circuit breaker")
+		return
+	}
+	s.logger.Info("circuit breaker reset", "scope", scope, "name", name)
+	writeJSON(w, map[string]string{"status": "reset", "scope": scope, "name": name})
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/dispatch"
+)
+
+func TestHandleBreakers(t *testing.T) {
+	srv := setupTestServer(t)
+	srv.cfg.Providers = map[string]config.Provider{"claude": {Model: "opus"}}
+
+	srv.rateLimiter = func() *dispatch.RateLimiter { return nil }
+	w := httptest.NewRecorder()
+	srv.handleBreakers(w, httptest.NewRequest(http.MethodGet, "/api/v1/breakers", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 before the worker starts, got %d", w.Code)
+	}
+
+	hour := config.Duration{Duration: time.Hour}
+	limiter := dispatch.NewRateLimiter(srv.store, srv.cfg.RateLimits)
+	limiter.SetCircuitBreakers(config.DispatchCircuitBreakers{
+		Provider:   config.CircuitBreaker{Threshold: 1, Window: hour, Cooldown: hour},
+		Categories: map[string]config.CircuitBreaker{dispatch.FailureGatewayClosed: {Threshold: 1, Window: hour, Cooldown: hour}},
+	})
+	limiter.RecordProviderOutcome("claude", srv.cfg.Providers["claude"], dispatch.FailureGatewayClosed)
+	srv.rateLimiter = func() *dispatch.RateLimiter { return limiter }
+
+	list := func() []dispatch.BreakerStatus {
+		t.Helper()
+		w := httptest.NewRecorder()
+		srv.handleBreakers(w, httptest.NewRequest(http.MethodGet, "/api/v1/breakers", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var statuses []dispatch.BreakerStatus
+		if err := json.NewDecoder(w.Body).Decode(&statuses); err != nil {
+			t.Fatal(err)
+		}
+		return statuses
+	}
+	statuses := list()
+	if len(statuses) != 2 || !statuses[0].Open || !statuses[1].Open {
+		t.Fatalf("expected open category and provider breakers, got %+v", statuses)
+	}
+
+	reset := func(path string) int {
+		w := httptest.NewRecorder()
+		srv.handleBreakerReset(w, httptest.NewRequest(http.MethodPost, path, nil))
+		return w.Code
+	}
+	if code := reset("/api/v1/breakers/provider/unknown/reset"); code != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown provider, got %d", code)
+	}
+	if code := reset("/api/v1/breakers/category/auth/reset"); code != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unconfigured category, got %d", code)
+	}
+	if code := reset("/api/v1/breakers/provider/claude/reset"); code != http.StatusOK {
+		t.Fatalf("expected provider reset to succeed, got %d", code)
+	}
+	if code := reset("/api/v1/breakers/category/gateway_closed/reset"); code != http.StatusOK {
+		t.Fatalf("expected category reset to succeed, got %d", code)
+	}
+	for _, st := range list() {
+		if st.Open || st.RecentFailures != 0 {
+			t.Fatalf("expected every breaker closed after reset, got %+v", st)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/burnin"
+)
+
+// GET /api/v1/reports/burnin?date=YYYY-MM-DD&format=json|md
+// Serves the latest burn-in report, or the one for date. The daemon writes
+// reports on reporter.burnin.schedule; this endpoint only reads them.
+func (s *Server) handleBurnInReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	dir := s.cfg.Reporter.BurnIn.ReportsDir
+	if dir == "" {
+		writeError(w, http.StatusNotFound, "burn-in reports not configured")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "md" {
+		writeError(w, http.StatusBadRequest, "format must be json or md")
+		return
+	}
+
+	date := r.URL.Query().Get("date")
+	if date != "" {
+		if _, err := time.Parse(time.DateOnly, date); err != nil {
+			writeError(w, http.StatusBadRequest, "date must be YYYY-MM-DD")
+			return
+		}
+	}
+
+	var report *burnin.Report
+	var err error
+	if date != "" {
+		report, err = burnin.Load(dir, date)
+	} else {
+		report, err = burnin.Latest(dir)
+	}
+	if errors.Is(err, burnin.ErrNoReport) {
+		writeError(w, http.StatusNotFound, "no burn-in report found")
+		return
+	}
+	if err != nil {
+		s.logger.Error("failed to load burn-in report", "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to load burn-in report")
+		return
+	}
+
+	if format == "md" {
+		w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+		w.Write([]byte(report.Markdown()))
+		return
+	}
+	writeJSON(w, report)
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/burnin"
+)
+
+func TestHandleBurnInReport(t *testing.T) {
+	srv := setupTestServer(t)
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		srv.handleBurnInReport(w, httptest.NewRequest(http.MethodGet, "/api/v1/reports/burnin"+query, nil))
+		return w
+	}
+
+	if w := get(""); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without a reports dir, got %d", w.Code)
+	}
+
+	dir := t.TempDir()
+	srv.cfg.Reporter.BurnIn.ReportsDir = dir
+	if w := get(""); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 with no reports, got %d", w.Code)
+	}
+
+	day := time.Date(2026, 3, 7, 7, 0, 0, 0, time.UTC)
+	for i, pass := range []bool{true, false} {
+		r := &burnin.Report{To: day.Add(time.Duration(i) * 24 * time.Hour), Pass: pass,
+			Projects: []burnin.ProjectReport{{Project: "test-proj", Pass: pass}}}
+		if _, _, err := burnin.Write(dir, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w := get("")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var latest burnin.Report
+	if err := json.NewDecoder(w.Body).Decode(&latest); err != nil {
+		t.Fatal(err)
+	}
+	if latest.Date() != "2026-03-08" || latest.Pass {
+		t.Fatalf("expected the failing 2026-03-08 report, got %+v", latest)
+	}
+
+	w = get("?date=2026-03-07&format=md")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "# Cortex Burn-in Report - 2026-03-07") {
+		t.Fatalf("unexpected markdown response %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/markdown") {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+
+	for query, code := range map[string]int{
+		"?date=2026-01-01": http.StatusNotFound,
+		"?date=../x":       http.StatusBadRequest,
+		"?format=pdf":      http.StatusBadRequest,
+	} {
+		if w := get(query); w.Code != code {
+			t.Errorf("%s: expected %d, got %d", query, code, w.Code)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.temporal.io/sdk/client"
+
+	"github.com/antigravity-dev/cortex/internal/beads"
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/journal"
+	"github.com/antigravity-dev/cortex/internal/temporal"
+)
+
+// DispatchBead starts an agent workflow on a bead of project for /cortex
+// dispatch, through the same gates as POST /workflows/start. It implements
+// matrix.BeadDispatcher and returns the workflow ID.
+func (s *Server) DispatchBead(ctx context.Context, project, beadID string) (string, error) {
+	proj, ok := s.cfg.Projects[project]
+	if !ok {
+		return "", fmt.Errorf("unknown project %q", project)
+	}
+	beadsDir := config.ExpandHome(strings.TrimSpace(proj.BeadsDir))
+	if beadsDir == "" {
+		return "", fmt.Errorf("project %q is missing beads_dir", project)
+	}
+	bead, err := beads.ShowBeadCtx(ctx, beadsDir, beadID)
+	if err != nil {
+		return "", fmt.Errorf("read bead %s: %w", beadID, err)
+	}
+
+	req := temporal.TaskRequest{
+		BeadID:    beadID,
+		Project:   project,
+		Prompt:    strings.TrimSpace(bead.Title + "\n\n" + bead.Description),
+		WorkDir:   proj.Workspace,
+		DoDChecks: append([]string(nil), proj.DoD.Checks...),
+	}
+	s.routeDispatch(ctx, &req)
+	if req.Agent == "" {
+		req.Agent = "claude"
+	}
+	decision := journal.Entry{Source: "matrix", Project: req.Project, BeadID: req.BeadID, Agent: req.Agent, Provider: req.Provider}
+	if reason := s.dispatchHeld(ctx, req); reason != "" {
+		decision.Decision, decision.Reason = journal.Deny, reason
+		s.journalDecision(decision)
+		return "", fmt.Errorf("dispatch window closed for %s: %s", project, reason)
+	}
+
+	c, err := temporal.Dial(s.cfg.Temporal)
+	if err != nil {
+		return "", fmt.Errorf("connect to temporal: %w", err)
+	}
+	defer c.Close()
+
+	if reason := s.claimBead(ctx, &req); reason != "" {
+		decision.Decision, decision.Reason = journal.Deny, reason
+		s.journalDecision(decision)
+		return "", fmt.Errorf("bead %s not dispatched: %s", beadID, reason)
+	}
+	wo := client.StartWorkflowOptions{ID: req.BeadID, TaskQueue: s.cfg.Temporal.TaskQueue}
+	we, err := c.ExecuteWorkflow(ctx, wo, temporal.CortexAgentWorkflow, req)
+	if err != nil {
+		s.releaseClaim(ctx, req)
+		return "", fmt.Errorf("start workflow: %w", err)
+	}
+
+	decision.Decision, decision.Workflow = journal.Admit, we.GetID()
+	s.journalDecision(decision)
+	s.logger.Info("workflow started from chat", "workflow_id", we.GetID(), "run_id", we.GetRunID())
+	return we.GetID(), nil
+}
+
+// CancelDispatch cancels a running dispatch's agent workflow for /cortex
+// cancel and marks the dispatch cancelled. It implements the Matrix
+// poller's canceler.
+func (s *Server) CancelDispatch(id int64) error {
+	d, err := s.store.GetDispatchByID(id)
+	if err != nil {
+		return fmt.Errorf("dispatch %d not found", id)
+	}
+	if d.Status != "running" {
+		return fmt.Errorf("dispatch %d is not running (%s)", id, d.Status)
+	}
+
+	c, err := temporal.Dial(s.cfg.Temporal)
+	if err != nil {
+		return fmt.Errorf("connect to temporal: %w", err)
+	}
+	defer c.Close()
+	if err := c.CancelWorkflow(context.Background(), d.BeadID, ""); err != nil {
+		return fmt.Errorf("cancel workflow %s: %w", d.BeadID, err)
+	}
+	if err := s.store.UpdateDispatchStatus(id, "cancelled", -1, d.DurationS); err != nil {
+		s.logger.Warn("failed to mark dispatch cancelled", "dispatch", id, "error", err)
+	}
+	s.logger.Info("dispatch cancelled from chat", "dispatch", id, "workflow_id", d.BeadID)
+	return nil
+}
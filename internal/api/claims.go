@@ -0,0 +1,51 @@
+package api
+
+import (
+	"context"
+	"errors"
+
+	"github.com/antigravity-dev/cortex/internal/lease"
+	"github.com/antigravity-dev/cortex/internal/temporal"
+)
+
+// SetClaims makes the server claim each bead before dispatching it, so
+// cortex instances sharing a project never dispatch the same bead twice.
+func (s *Server) SetClaims(c *lease.Claims) {
+	s.claims = c
+}
+
+// SetElector makes the server hold dispatches while e has not elected this
+// instance active.
+func (s *Server) SetElector(e *lease.Elector) {
+	s.elector = e
+}
+
+// claimBead claims req's bead for this instance and records the claim on
+// req for the workflow to renew and release. It returns why the bead may not
+// be dispatched, or "" when claims are disabled or the claim was taken. A
+// claim that cannot be checked holds the dispatch rather than risk a double
+// claim.
+func (s *Server) claimBead(ctx context.Context, req *temporal.TaskRequest) string {
+	if s.claims == nil {
+		return ""
+	}
+	if err := s.claims.Claim(ctx, req.BeadID); err != nil {
+		if errors.Is(err, lease.ErrHeld) {
+			return "claimed by another cortex instance"
+		}
+		s.logger.Warn("failed to claim bead", "bead", req.BeadID, "error", err)
+		return "claim could not be checked"
+	}
+	req.ClaimHolder, req.ClaimTTL = s.claims.Holder, s.claims.TTL
+	return ""
+}
+
+// releaseClaim gives up the claim on a bead whose workflow failed to start.
+func (s *Server) releaseClaim(ctx context.Context, req temporal.TaskRequest) {
+	if req.ClaimHolder == "" {
+		return
+	}
+	if err := s.claims.Release(ctx, req.BeadID); err != nil {
+		s.logger.Warn("failed to release bead claim", "bead", req.BeadID, "error", err)
+	}
+}
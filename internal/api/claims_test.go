@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/lease"
+	"github.com/antigravity-dev/cortex/internal/temporal"
+)
+
+func TestClaimBead(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+
+	req := temporal.TaskRequest{BeadID: "cx-1"}
+	if reason := srv.claimBead(ctx, &req); reason != "" || req.ClaimHolder != "" {
+		t.Fatalf("claims disabled: reason %q, holder %q", reason, req.ClaimHolder)
+	}
+
+	backend := lease.NewStoreBackend(srv.store)
+	srv.SetClaims(&lease.Claims{Backend: backend, Holder: "host-a", TTL: time.Minute})
+	if err := backend.Acquire(ctx, "cx-1", "host-b", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if reason := srv.claimBead(ctx, &req); reason != "claimed by another cortex instance" {
+		t.Fatalf("reason = %q, want the other instance's claim", reason)
+	}
+
+	if err := backend.Release(ctx, "cx-1", "host-b"); err != nil {
+		t.Fatal(err)
+	}
+	if reason := srv.claimBead(ctx, &req); reason != "" {
+		t.Fatalf("reason = %q after release", reason)
+	}
+	if req.ClaimHolder != "host-a" || req.ClaimTTL != time.Minute {
+		t.Fatalf("claim not recorded on request: %+v", req)
+	}
+
+	// A workflow that failed to start gives the claim back.
+	srv.releaseClaim(ctx, req)
+	if err := backend.Acquire(ctx, "cx-1", "host-b", time.Minute); err != nil {
+		t.Fatalf("claim not released: %v", err)
+	}
+}
+
+func TestDispatchHeldOnStandby(t *testing.T) {
+	srv := setupTestServer(t)
+	elector := &lease.Elector{Backend: lease.NewStoreBackend(srv.store), Holder: "host-a", TTL: 3 * time.Second}
+	srv.SetElector(elector)
+	req := temporal.TaskRequest{BeadID: "cx-1", Project: "unknown"}
+
+	if reason := srv.dispatchHeld(context.Background(), req); reason != "standby instance; dispatch through the active one" {
+		t.Fatalf("standby reason = %q", reason)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	elected := make(chan struct{})
+	go elector.Run(ctx, func() { close(elected) })
+	<-elected
+	if reason := srv.dispatchHeld(context.Background(), req); reason != "" {
+		t.Fatalf("active instance held: %q", reason)
+	}
+}
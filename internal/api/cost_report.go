@@ -0,0 +1,73 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/costreport"
+)
+
+// GET /api/v1/reports/cost?month=YYYY-MM&project=name&format=json|md
+// Serves the latest monthly cost report, or the one for month. With project
+// set, the report is narrowed to that project's invoice. The daemon writes
+// reports on reporter.cost.schedule; this endpoint only reads them.
+func (s *Server) handleCostReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	dir := s.cfg.Reporter.Cost.ReportsDir
+	if dir == "" {
+		writeError(w, http.StatusNotFound, "cost reports not configured")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "md" {
+		writeError(w, http.StatusBadRequest, "format must be json or md")
+		return
+	}
+
+	month := r.URL.Query().Get("month")
+	if month != "" {
+		if _, err := time.Parse(costreport.MonthLayout, month); err != nil {
+			writeError(w, http.StatusBadRequest, "month must be YYYY-MM")
+			return
+		}
+	}
+
+	var report *costreport.Report
+	var err error
+	if month != "" {
+		report, err = costreport.Load(dir, month)
+	} else {
+		report, err = costreport.Latest(dir)
+	}
+	if errors.Is(err, costreport.ErrNoReport) {
+		writeError(w, http.StatusNotFound, "no cost report found")
+		return
+	}
+	if err != nil {
+		s.logger.Error("failed to load cost report", "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to load cost report")
+		return
+	}
+
+	if project := r.URL.Query().Get("project"); project != "" {
+		invoice, ok := report.ForProject(project)
+		if !ok {
+			writeError(w, http.StatusNotFound, "no spend for project in "+report.Month)
+			return
+		}
+		report = invoice
+	}
+
+	if format == "md" {
+		w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+		w.Write([]byte(report.Markdown()))
+		return
+	}
+	writeJSON(w, report)
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/antigravity-dev/cortex/internal/costreport"
+)
+
+func TestHandleCostReport(t *testing.T) {
+	srv := setupTestServer(t)
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		srv.handleCostReport(w, httptest.NewRequest(http.MethodGet, "/api/v1/reports/cost"+query, nil))
+		return w
+	}
+
+	srv.cfg.Reporter.Cost.ReportsDir = ""
+	if w := get(""); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without a reports dir, got %d", w.Code)
+	}
+
+	dir := t.TempDir()
+	srv.cfg.Reporter.Cost.ReportsDir = dir
+	if w := get(""); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 with no reports, got %d", w.Code)
+	}
+
+	for _, month := range []string{"2026-01", "2026-02"} {
+		r := &costreport.Report{Month: month, CostUSD: 3, Dispatches: 3, Projects: []costreport.ProjectCost{
+			{Project: "alpha", Dispatches: 2, CostUSD: 2},
+			{Project: "test-proj", Dispatches: 1, CostUSD: 1},
+		}}
+		if _, _, err := costreport.Write(dir, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w := get("")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var latest costreport.Report
+	if err := json.NewDecoder(w.Body).Decode(&latest); err != nil {
+		t.Fatal(err)
+	}
+	if latest.Month != "2026-02" || len(latest.Projects) != 2 {
+		t.Fatalf("expected the full 2026-02 report, got %+v", latest)
+	}
+
+	w = get("?month=2026-01&project=test-proj")
+	var invoice costreport.Report
+	if err := json.NewDecoder(w.Body).Decode(&invoice); err != nil {
+		t.Fatal(err)
+	}
+	if invoice.CostUSD != 1 || len(invoice.Projects) != 1 || invoice.Projects[0].Project != "test-proj" {
+		t.Fatalf("expected the test-proj invoice, got %+v", invoice)
+	}
+
+	w = get("?project=alpha&format=md")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "## alpha: $2.00") || strings.Contains(w.Body.String(), "test-proj") {
+		t.Fatalf("unexpected markdown response %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/markdown") {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+
+	for query, code := range map[string]int{
+		"?month=2025-12": http.StatusNotFound,
+		"?project=gamma": http.StatusNotFound,
+		"?month=../x":    http.StatusBadRequest,
+		"?format=pdf":    http.StatusBadRequest,
+	} {
+		if w := get(query); w.Code != code {
+			t.Errorf("%s: expected %d, got %d", query, code, w.Code)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+// dispatchListItem is one dispatch in GET /api/v1/dispatches.
+type dispatchListItem struct {
+	ID              int64   `json:"id"`
+	BeadID          string  `json:"bead_id"`
+	Project         string  `json:"project"`
+	Agent           string  `json:"agent"`
+	Provider        string  `json:"provider"`
+	Tier            string  `json:"tier"`
+	Status          string  `json:"status"`
+	Stage           string  `json:"stage"`
+	DispatchedAt    string  `json:"dispatched_at"`
+	CompletedAt     string  `json:"completed_at,omitempty"`
+	DurationS       float64 `json:"duration_s"`
+	ExitCode        int     `json:"exit_code"`
+	Retries         int     `json:"retries"`
+	CostUSD         float64 `json:"cost_usd"`
+	FailureCategory string  `json:"failure_category,omitempty"`
+	FailureSummary  string  `json:"failure_summary,omitempty"`
+	PRURL           string  `json:"pr_url,omitempty"`
+}
+
+// GET /api/v1/dispatches?project=&status=&agent=&tier=&failure_category=&from=&to=&sort=&order=&limit=&cursor=
+// Lists dispatches a page at a time, newest first unless sort and order say
+// otherwise. next_cursor, when set, fetches the following page with the same
+// filters.
+func (s *Server) handleDispatchList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+	opts := store.DispatchListOptions{
+		Project:         q.Get("project"),
+		Status:          q.Get("status"),
+		Agent:           q.Get("agent"),
+		Tier:            q.Get("tier"),
+		FailureCategory: q.Get("failure_category"),
+		Cursor:          q.Get("cursor"),
+		Desc:            true,
+	}
+	var err error
+	if opts.From, err = parseExportTime("from", q.Get("from")); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if opts.To, err = parseExportTime("to", q.Get("to")); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	switch sort := q.Get("sort"); sort {
+	case "", store.DispatchSortDispatchedAt, store.DispatchSortDuration, store.DispatchSortCost:
+		opts.Sort = sort
+	default:
+		writeError(w, http.StatusBadRequest, "sort must be dispatched_at, duration_s or cost_usd")
+		return
+	}
+	switch q.Get("order") {
+	case "", "desc":
+	case "asc":
+		opts.Desc = false
+	default:
+		writeError(w, http.StatusBadRequest, "order must be asc or desc")
+		return
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		opts.Limit = min(n, store.MaxDispatchListLimit)
+	}
+	if opts.Cursor != "" {
+		if _, err := strconv.ParseInt(opts.Cursor, 10, 64); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid cursor")
+			return
+		}
+	}
+
+	page, err := s.store.ListDispatches(opts)
+	if err != nil {
+		s.logger.Error("failed to list dispatches", "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to list dispatches")
+		return
+	}
+	items := make([]dispatchListItem, 0, len(page.Dispatches))
+	for _, d := range page.Dispatches {
+		item := dispatchListItem{
+			ID:              d.ID,
+			BeadID:          d.BeadID,
+			Project:         d.Project,
+			Agent:           d.AgentID,
+			Provider:        d.Provider,
+			Tier:            d.Tier,
+			Status:          d.Status,
+			Stage:           d.Stage,
+			DispatchedAt:    d.DispatchedAt.UTC().Format(time.RFC3339),
+			DurationS:       d.DurationS,
+			ExitCode:        d.ExitCode,
+			Retries:         d.Retries,
+			CostUSD:         d.CostUSD,
+			FailureCategory: d.FailureCategory,
+			FailureSummary:  d.FailureSummary,
+			PRURL:           d.PRURL,
+		}
+		if d.CompletedAt.Valid {
+			item.CompletedAt = d.CompletedAt.Time.UTC().Format(time.RFC3339)
+		}
+		items = append(items, item)
+	}
+	writeJSON(w, map[string]any{
+		"dispatches":  items,
+		"next_cursor": page.NextCursor,
+	})
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDispatchList(t *testing.T) {
+	srv := setupTestServer(t)
+	for _, bead := range []string{"cx-1", "cx-2", "cx-3"} {
+		if _, err := srv.store.RecordDispatch(bead, "test-proj", "coder", "p", "fast", 1, "", "", "", "", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := srv.store.RecordDispatch("cx-4", "other", "coder", "p", "fast", 1, "", "", "", "", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	list := func(query string) (int, []dispatchListItem, string) {
+		w := httptest.NewRecorder()
+		srv.handleDispatchList(w, httptest.NewRequest(http.MethodGet, "/api/v1/dispatches"+query, nil))
+		var resp struct {
+			Dispatches []dispatchListItem `json:"dispatches"`
+			NextCursor string             `json:"next_cursor"`
+		}
+		json.NewDecoder(w.Body).Decode(&resp)
+		return w.Code, resp.Dispatches, resp.NextCursor
+	}
+
+	code, items, cursor := list("?project=test-proj&limit=2")
+	if code != http.StatusOK || len(items) != 2 || cursor == "" || items[0].BeadID != "cx-3" {
+		t.Fatalf("first page = %d %+v cursor %q", code, items, cursor)
+	}
+	code, items, cursor = list("?project=test-proj&limit=2&cursor=" + cursor)
+	if code != http.StatusOK || len(items) != 1 || cursor != "" || items[0].BeadID != "cx-1" {
+		t.Fatalf("second page = %d %+v cursor %q", code, items, cursor)
+	}
+
+	for _, bad := range []string{"?sort=prompt", "?order=up", "?limit=0", "?cursor=abc", "?from=yesterday"} {
+		if code, _, _ := list(bad); code != http.StatusBadRequest {
+			t.Errorf("%s: code = %d, want 400", bad, code)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// GET /api/v1/dispatches/{id}/report
+// Returns the completion block the dispatch's agent emitted.
+func (s *Server) handleDispatchReport(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/dispatches/"), "/"), "/")
+	if len(parts) != 2 || parts[1] != "report" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	dispatchID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || dispatchID <= 0 {
+		writeError(w, http.StatusBadRequest, "dispatch id must be a positive integer")
+		return
+	}
+
+	report, err := s.store.GetDispatchReport(dispatchID)
+	if err != nil {
+		s.logger.Error("failed to get dispatch report", "dispatch", dispatchID, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to get dispatch report")
+		return
+	}
+	if report == nil {
+		writeError(w, http.StatusNotFound, "dispatch has no report")
+		return
+	}
+	writeJSON(w, report)
+}
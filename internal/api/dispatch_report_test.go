@@ -0,0 +1,56 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestHandleDispatchReport(t *testing.T) {
+	srv := setupTestServer(t)
+	confidence := 0.75
+	if err := srv.store.RecordDispatchReport(store.DispatchReport{
+		DispatchID:   7,
+		BeadID:       "cx-7",
+		Project:      "test-proj",
+		Agent:        "claude",
+		FilesChanged: []string{"main.go"},
+		TestsRun:     []store.ReportedTest{{Command: "go test ./...", Passed: false}},
+		Confidence:   &confidence,
+		FollowUps:    []string{"fix flaky test"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	srv.routeDispatches(w, httptest.NewRequest(http.MethodGet, "/api/v1/dispatches/7/report", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var report store.DispatchReport
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatal(err)
+	}
+	if report.BeadID != "cx-7" || len(report.TestsRun) != 1 || report.TestsRun[0].Passed || report.Confidence == nil || *report.Confidence != 0.75 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	for path, code := range map[string]int{
+		"/api/v1/dispatches/8/report":   http.StatusNotFound,
+		"/api/v1/dispatches/abc/report": http.StatusBadRequest,
+	} {
+		w := httptest.NewRecorder()
+		srv.routeDispatches(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != code {
+			t.Fatalf("%s: expected %d, got %d", path, code, w.Code)
+		}
+	}
+	w = httptest.NewRecorder()
+	srv.routeDispatches(w, httptest.NewRequest(http.MethodPost, "/api/v1/dispatches/7/report", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", w.Code)
+	}
+}
@@ -0,0 +1,223 @@
+package api
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+const (
+	exportFlushRows    = 500 // CSV rows between flushes to the client
+	exportParquetBatch = 1000
+)
+
+// dispatchExportRow is one dispatch as exported to CSV and Parquet. Prompts,
+// PIDs and session names stay out: they are operational detail, not history.
+type dispatchExportRow struct {
+	ID                int64      `parquet:"id"`
+	BeadID            string     `parquet:"bead_id"`
+	Project           string     `parquet:"project"`
+	Agent             string     `parquet:"agent"`
+	Provider          string     `parquet:"provider"`
+	Tier              string     `parquet:"tier"`
+	Backend           string     `parquet:"backend"`
+	Status            string     `parquet:"status"`
+	Stage             string     `parquet:"stage"`
+	Labels            string     `parquet:"labels"`
+	DispatchedAt      time.Time  `parquet:"dispatched_at,timestamp(millisecond)"`
+	CompletedAt       *time.Time `parquet:"completed_at,optional"` // null while running
+	DurationS         float64    `parquet:"duration_s"`
+	ExitCode          int64      `parquet:"exit_code"`
+	Retries           int64      `parquet:"retries"`
+	EscalatedFromTier string     `parquet:"escalated_from_tier"`
+	InputTokens       int64      `parquet:"input_tokens"`
+	OutputTokens      int64      `parquet:"output_tokens"`
+	CostUSD           float64    `parquet:"cost_usd"`
+	FailureCategory   string     `parquet:"failure_category"`
+	FailureSummary    string     `parquet:"failure_summary"`
+	PRURL             string     `parquet:"pr_url"`
+	PRNumber          int64      `parquet:"pr_number"`
+	Branch            string     `parquet:"branch"`
+}
+
+var dispatchExportHeader = []string{
+	"id", "bead_id", "project", "agent", "provider", "tier", "backend", "status", "stage", "labels",
+	"dispatched_at", "completed_at", "duration_s", "exit_code", "retries", "escalated_from_tier",
+	"input_tokens", "output_tokens", "cost_usd", "failure_category", "failure_summary",
+	"pr_url", "pr_number", "branch",
+}
+
+func newDispatchExportRow(d store.Dispatch) dispatchExportRow {
+	row := dispatchExportRow{
+		ID:                d.ID,
+		BeadID:            d.BeadID,
+		Project:           d.Project,
+		Agent:             d.AgentID,
+		Provider:          d.Provider,
+		Tier:              d.Tier,
+		Backend:           d.Backend,
+		Status:            d.Status,
+		Stage:             d.Stage,
+		Labels:            d.Labels,
+		DispatchedAt:      d.DispatchedAt.UTC(),
+		DurationS:         d.DurationS,
+		ExitCode:          int64(d.ExitCode),
+		Retries:           int64(d.Retries),
+		EscalatedFromTier: d.EscalatedFromTier,
+		InputTokens:       int64(d.InputTokens),
+		OutputTokens:      int64(d.OutputTokens),
+		CostUSD:           d.CostUSD,
+		FailureCategory:   d.FailureCategory,
+		FailureSummary:    d.FailureSummary,
+		PRURL:             d.PRURL,
+		PRNumber:          int64(d.PRNumber),
+		Branch:            d.Branch,
+	}
+	if d.CompletedAt.Valid {
+		t := d.CompletedAt.Time.UTC()
+		row.CompletedAt = &t
+	}
+	return row
+}
+
+func (r dispatchExportRow) csvRecord() []string {
+	completed := ""
+	if r.CompletedAt != nil {
+		completed = r.CompletedAt.Format(time.RFC3339)
+	}
+	return []string{
+		strconv.FormatInt(r.ID, 10), r.BeadID, r.Project, r.Agent, r.Provider, r.Tier, r.Backend, r.Status, r.Stage, r.Labels,
+		r.DispatchedAt.Format(time.RFC3339), completed,
+		strconv.FormatFloat(r.DurationS, 'f', -1, 64),
+		strconv.FormatInt(r.ExitCode, 10), strconv.FormatInt(r.Retries, 10), r.EscalatedFromTier,
+		strconv.FormatInt(r.InputTokens, 10), strconv.FormatInt(r.OutputTokens, 10),
+		strconv.FormatFloat(r.CostUSD, 'f', -1, 64),
+		r.FailureCategory, r.FailureSummary, r.PRURL, strconv.FormatInt(r.PRNumber, 10), r.Branch,
+	}
+}
+
+// parseExportTime accepts RFC3339 timestamps or plain YYYY-MM-DD dates (UTC
+// midnight). An empty value leaves that end of the range open.
+func parseExportTime(name, v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, v); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%s must be RFC3339 or YYYY-MM-DD", name)
+}
+
+// GET /api/v1/export/dispatches?from=&to=&format=csv|parquet
+func (s *Server) handleDispatchExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+	from, err := parseExportTime("from", q.Get("from"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	to, err := parseExportTime("to", q.Get("to"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		writeError(w, http.StatusBadRequest, "from must be before to")
+		return
+	}
+
+	format := q.Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	switch format {
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	case "parquet":
+		w.Header().Set("Content-Type", "application/vnd.apache.parquet")
+	default:
+		writeError(w, http.StatusBadRequest, "format must be csv or parquet")
+		return
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="dispatches.%s"`, format))
+
+	// Headers are committed once the first row is written, so failures past
+	// this point can only be logged; the client sees a truncated body.
+	if format == "csv" {
+		err = s.exportDispatchesCSV(w, from, to)
+	} else {
+		err = s.exportDispatchesParquet(w, from, to)
+	}
+	if err != nil {
+		s.logger.Error("dispatch export failed", "format", format, "error", err)
+	}
+}
+
+func (s *Server) exportDispatchesCSV(w http.ResponseWriter, from, to time.Time) error {
+	flusher, _ := w.(http.Flusher)
+	cw := csv.NewWriter(w)
+	if err := cw.Write(dispatchExportHeader); err != nil {
+		return err
+	}
+
+	n := 0
+	err := s.store.EachDispatch(from, to, func(d store.Dispatch) error {
+		if err := cw.Write(newDispatchExportRow(d).csvRecord()); err != nil {
+			return err
+		}
+		if n++; n%exportFlushRows == 0 {
+			cw.Flush()
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		return cw.Error()
+	})
+	cw.Flush()
+	if err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+func (s *Server) exportDispatchesParquet(w http.ResponseWriter, from, to time.Time) error {
+	pw := parquet.NewGenericWriter[dispatchExportRow](w)
+	batch := make([]dispatchExportRow, 0, exportParquetBatch)
+	write := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		_, err := pw.Write(batch)
+		batch = batch[:0]
+		return err
+	}
+
+	err := s.store.EachDispatch(from, to, func(d store.Dispatch) error {
+		batch = append(batch, newDispatchExportRow(d))
+		if len(batch) == cap(batch) {
+			return write()
+		}
+		return nil
+	})
+	if err == nil {
+		err = write()
+	}
+	if err != nil {
+		return err
+	}
+	return pw.Close()
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+func seedExportDispatches(t *testing.T, srv *Server) {
+	t.Helper()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, bead := range []string{"b-0", "b-1", "b-2"} {
+		id, err := srv.store.RecordDispatch(bead, "test-proj", "agent", "claude", "balanced", 100+i, "", "prompt", "", "feat/"+bead, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := srv.store.SetDispatchTime(id, base.Add(time.Duration(i)*24*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+		if err := srv.store.RecordDispatchCost(id, 1000, 200, 0.25); err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			if err := srv.store.UpdateDispatchStatus(id, "failed", 1, 42); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestDispatchExportCSV(t *testing.T) {
+	srv := setupTestServer(t)
+	seedExportDispatches(t, srv)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/export/dispatches?from=2026-03-02&to=2026-03-04", nil)
+	w := httptest.NewRecorder()
+	srv.handleDispatchExport(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/csv; charset=utf-8" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+
+	records, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected header + 2 rows, got %d: %v", len(records), records)
+	}
+	if len(records[0]) != len(dispatchExportHeader) || records[0][0] != "id" {
+		t.Fatalf("unexpected header %v", records[0])
+	}
+	col := map[string]int{}
+	for i, name := range records[0] {
+		col[name] = i
+	}
+	if records[1][col["bead_id"]] != "b-1" || records[2][col["bead_id"]] != "b-2" {
+		t.Fatalf("expected b-1 and b-2, got %v", records[1:])
+	}
+	if records[1][col["cost_usd"]] != "0.25" || records[1][col["input_tokens"]] != "1000" || records[1][col["branch"]] != "feat/b-1" {
+		t.Fatalf("unexpected row %v", records[1])
+	}
+	if records[1][col["dispatched_at"]] != "2026-03-02T12:00:00Z" || records[1][col["completed_at"]] != "" {
+		t.Fatalf("unexpected timestamps %v", records[1])
+	}
+}
+
+func TestDispatchExportParquet(t *testing.T) {
+	srv := setupTestServer(t)
+	seedExportDispatches(t, srv)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/export/dispatches?format=parquet", nil)
+	w := httptest.NewRecorder()
+	srv.handleDispatchExport(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	body := w.Body.Bytes()
+	rows, err := parquet.Read[dispatchExportRow](bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		t.Fatalf("read parquet: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(rows))
+	}
+	if rows[0].BeadID != "b-0" || rows[0].CostUSD != 0.25 || rows[0].OutputTokens != 200 || rows[0].ExitCode != 1 || rows[0].CompletedAt == nil {
+		t.Fatalf("unexpected first row %+v", rows[0])
+	}
+	if !rows[2].DispatchedAt.Equal(time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC)) || rows[2].CompletedAt != nil {
+		t.Fatalf("unexpected timestamps %+v", rows[2])
+	}
+}
+
+func TestDispatchExportRejectsBadParams(t *testing.T) {
+	srv := setupTestServer(t)
+	for _, q := range []string{
+		"?format=xlsx",
+		"?from=yesterday",
+		"?to=2026-13-01",
+		"?from=2026-03-02&to=2026-03-01",
+	} {
+		w := httptest.NewRecorder()
+		srv.handleDispatchExport(w, httptest.NewRequest(http.MethodGet, "/api/v1/export/dispatches"+q, nil))
+		if w.Code != http.StatusBadRequest {
+			body, _ := io.ReadAll(w.Body)
+			t.Errorf("%s: expected 400, got %d: %s", q, w.Code, body)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	srv.handleDispatchExport(w, httptest.NewRequest(http.MethodPost, "/api/v1/export/dispatches", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", w.Code)
+	}
+}
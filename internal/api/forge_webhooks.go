@@ -0,0 +1,346 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.temporal.io/sdk/client"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/scheduler"
+	"github.com/antigravity-dev/cortex/internal/temporal"
+	"github.com/antigravity-dev/cortex/internal/webhook"
+)
+
+// maxForgeWebhookBody caps inbound webhook payloads.
+const maxForgeWebhookBody = 5 << 20
+
+// forgeEvent is a review, merge, or check event on a pull request, normalized
+// across forges.
+type forgeEvent struct {
+	Forge  string // github or gitlab
+	Repo   string // org/repo, matched against projects' forge_repo
+	PR     int
+	Kind   string // review, merged, closed, or check
+	State  string // review: approved, changes_requested, commented; check: success, failure, ...
+	Actor  string
+	Commit string // merged: the merge commit on the base branch, when the forge sends it
+}
+
+func (e forgeEvent) String() string {
+	s := fmt.Sprintf("%s %s", e.Forge, e.Kind)
+	if e.State != "" {
+		s += " " + e.State
+	}
+	if e.Actor != "" {
+		s += " by " + e.Actor
+	}
+	return s
+}
+
+// POST /api/v1/webhooks/github
+// Receives pull_request_review, pull_request, and check_suite deliveries
+// signed with api.forge_webhooks.github_secret.
+func (s *Server) handleGitHubWebhook(w http.ResponseWriter, r *http.Request) {
+	body, ok := s.readForgeWebhook(w, r, s.cfg.API.ForgeWebhooks.GitHubSecret)
+	if !ok {
+		return
+	}
+	want := webhook.Sign(s.cfg.API.ForgeWebhooks.GitHubSecret, body)
+	if !hmac.Equal([]byte(r.Header.Get("X-Hub-Signature-256")), []byte(want)) {
+		writeError(w, http.StatusUnauthorized, "invalid signature")
+		return
+	}
+	kind := r.Header.Get("X-GitHub-Event")
+	if kind == "ping" {
+		writeJSON(w, map[string]any{"events": []any{}})
+		return
+	}
+	events, err := parseGitHubEvent(kind, body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.applyForgeEvents(w, r, events)
+}
+
+// POST /api/v1/webhooks/gitlab
+// Receives Merge Request, Note, and Pipeline hooks carrying
+// api.forge_webhooks.gitlab_token.
+func (s *Server) handleGitLabWebhook(w http.ResponseWriter, r *http.Request) {
+	body, ok := s.readForgeWebhook(w, r, s.cfg.API.ForgeWebhooks.GitLabToken)
+	if !ok {
+		return
+	}
+	token := r.Header.Get("X-Gitlab-Token")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.cfg.API.ForgeWebhooks.GitLabToken)) != 1 {
+		writeError(w, http.StatusUnauthorized, "invalid token")
+		return
+	}
+	events, err := parseGitLabEvent(r.Header.Get("X-Gitlab-Event"), body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.applyForgeEvents(w, r, events)
+}
+
+// readForgeWebhook checks the method and that the forge is configured, and
+// reads the body for signature verification.
+func (s *Server) readForgeWebhook(w http.ResponseWriter, r *http.Request, secret string) ([]byte, bool) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return nil, false
+	}
+	if secret == "" {
+		writeError(w, http.StatusNotFound, "webhook not configured")
+		return nil, false
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxForgeWebhookBody))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, "payload too large")
+		return nil, false
+	}
+	return body, true
+}
+
+func parseGitHubEvent(kind string, body []byte) ([]forgeEvent, error) {
+	type user struct {
+		Login string `json:"login"`
+	}
+	var p struct {
+		Action string `json:"action"`
+		Sender user   `json:"sender"`
+		Review struct {
+			State string `json:"state"`
+			User  user   `json:"user"`
+		} `json:"review"`
+		PullRequest struct {
+			Number         int    `json:"number"`
+			Merged         bool   `json:"merged"`
+			MergeCommitSHA string `json:"merge_commit_sha"`
+		} `json:"pull_request"`
+		CheckSuite struct {
+			Conclusion   string `json:"conclusion"`
+			PullRequests []struct {
+				Number int `json:"number"`
+			} `json:"pull_requests"`
+		} `json:"check_suite"`
+		Repository struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("invalid JSON body")
+	}
+	base := forgeEvent{Forge: "github", Repo: p.Repository.FullName, PR: p.PullRequest.Number, Actor: p.Sender.Login}
+
+	switch {
+	case kind == "pull_request_review" && p.Action == "submitted":
+		base.Kind, base.State, base.Actor = "review", strings.ToLower(p.Review.State), p.Review.User.Login
+		return []forgeEvent{base}, nil
+	case kind == "pull_request" && p.Action == "closed":
+		base.Kind = "closed"
+		if p.PullRequest.Merged {
+			base.Kind, base.Commit = "merged", p.PullRequest.MergeCommitSHA
+		}
+		return []forgeEvent{base}, nil
+	case kind == "check_suite" && p.Action == "completed":
+		var events []forgeEvent
+		for _, pr := range p.CheckSuite.PullRequests {
+			ev := base
+			ev.PR, ev.Kind, ev.State, ev.Actor = pr.Number, "check", p.CheckSuite.Conclusion, ""
+			events = append(events, ev)
+		}
+		return events, nil
+	}
+	return nil, nil
+}
+
+func parseGitLabEvent(kind string, body []byte) ([]forgeEvent, error) {
+	var p struct {
+		User struct {
+			Username string `json:"username"`
+		} `json:"user"`
+		Project struct {
+			PathWithNamespace string `json:"path_with_namespace"`
+		} `json:"project"`
+		ObjectAttributes struct {
+			IID            int    `json:"iid"`
+			Action         string `json:"action"`
+			Status         string `json:"status"`
+			NoteableType   string `json:"noteable_type"`
+			MergeCommitSHA string `json:"merge_commit_sha"`
+		} `json:"object_attributes"`
+		MergeRequest *struct {
+			IID int `json:"iid"`
+		} `json:"merge_request"`
+	}
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("invalid JSON body")
+	}
+	ev := forgeEvent{Forge: "gitlab", Repo: p.Project.PathWithNamespace, Actor: p.User.Username}
+	attrs := p.ObjectAttributes
+
+	switch kind {
+	case "Merge Request Hook":
+		ev.PR = attrs.IID
+		switch attrs.Action {
+		case "approved":
+			ev.Kind, ev.State = "review", "approved"
+		case "merge":
+			ev.Kind, ev.Commit = "merged", attrs.MergeCommitSHA
+		case "close":
+			ev.Kind = "closed"
+		default:
+			return nil, nil
+		}
+	case "Note Hook":
+		if attrs.NoteableType != "MergeRequest" || p.MergeRequest == nil {
+			return nil, nil
+		}
+		ev.PR, ev.Kind, ev.State = p.MergeRequest.IID, "review", "commented"
+	case "Pipeline Hook":
+		if p.MergeRequest == nil {
+			return nil, nil
+		}
+		switch attrs.Status {
+		case "success", "failed", "canceled":
+		default:
+			return nil, nil
+		}
+		ev.PR, ev.Kind, ev.State, ev.Actor = p.MergeRequest.IID, "check", attrs.Status, ""
+	default:
+		return nil, nil
+	}
+	return []forgeEvent{ev}, nil
+}
+
+// applyForgeEvents acts on each event and responds with one result per
+// event. Events for unmapped repos or PRs Cortex did not open are ignored.
+func (s *Server) applyForgeEvents(w http.ResponseWriter, r *http.Request, events []forgeEvent) {
+	results := make([]map[string]any, 0, len(events))
+	for _, ev := range events {
+		results = append(results, s.applyForgeEvent(r, ev))
+	}
+	writeJSON(w, map[string]any{"events": results})
+}
+
+// applyForgeEvent records the event against the PR's bead:
+//   - any review ends the stalled-review wait for the PR; an approval also
+//     moves a bead in the review stage on to its next stage
+//   - a merge closes review tracking, moves the bead to done and starts
+//     the project's post-merge checks
+//   - a close without merge only closes review tracking
+//   - a completed check run is recorded as a health event
+func (s *Server) applyForgeEvent(r *http.Request, ev forgeEvent) map[string]any {
+	result := map[string]any{"forge": ev.Forge, "kind": ev.Kind, "pr": ev.PR}
+	project, ok := s.cfg.ProjectForRepo(ev.Repo)
+	if !ok {
+		result["ignored"] = fmt.Sprintf("repository %q is not a project's forge_repo", ev.Repo)
+		return result
+	}
+	result["project"] = project
+	review, err := s.store.GetPRReviewByNumber(project, ev.PR)
+	if err != nil {
+		s.logger.Error("forge webhook: PR lookup failed", "project", project, "pr", ev.PR, "error", err)
+		result["error"] = "PR lookup failed"
+		return result
+	}
+	if review == nil {
+		result["ignored"] = "PR was not opened by a dispatch"
+		return result
+	}
+	result["bead_id"] = review.BeadID
+
+	details := fmt.Sprintf("%s/%s PR #%d: %s", project, review.BeadID, ev.PR, ev)
+	if err := s.store.RecordHealthEventWithDispatch("forge_event", details, review.DispatchID, review.BeadID); err != nil {
+		s.logger.Warn("forge webhook: failed to record event", "bead", review.BeadID, "error", err)
+	}
+
+	now := time.Now()
+	var to string
+	switch ev.Kind {
+	case "review":
+		if err := s.store.RecordPRFirstReview(review.ID, now); err != nil {
+			s.logger.Warn("forge webhook: record first review failed", "pr", ev.PR, "error", err)
+		}
+		if ev.State != "approved" {
+			return result
+		}
+		stage, err := s.store.GetBeadStage(project, review.BeadID)
+		if err != nil || stage.CurrentStage != scheduler.ReviewStage {
+			return result
+		}
+		if to, err = scheduler.NextStage(s.cfg.Workflows, stage); err != nil {
+			result["error"] = err.Error()
+			return result
+		}
+	case "merged":
+		if err := s.store.ClosePRReview(review.ID, now); err != nil {
+			s.logger.Warn("forge webhook: close review failed", "pr", ev.PR, "error", err)
+		}
+		if id := s.startPostMerge(r.Context(), project, review.BeadID, ev); id != "" {
+			result["post_merge_workflow"] = id
+		}
+		stage, err := s.store.GetBeadStage(project, review.BeadID)
+		if err != nil || stage.CurrentStage == config.TerminalStage {
+			return result
+		}
+		to = config.TerminalStage
+	case "closed":
+		if err := s.store.ClosePRReview(review.ID, now); err != nil {
+			s.logger.Warn("forge webhook: close review failed", "pr", ev.PR, "error", err)
+		}
+		return result
+	default:
+		return result
+	}
+
+	approvedBy := ""
+	if ev.Kind == "review" {
+		approvedBy = ev.Actor
+	}
+	move, err := s.moveBeadStage(r.Context(), project, review.BeadID, to, approvedBy, ev.String())
+	if err != nil {
+		result["stage_error"] = err.Error()
+		return result
+	}
+	result["stage"] = move
+	return result
+}
+
+// startPostMerge starts PostMergeWorkflow for a merged PR when the project
+// has post_merge_checks, and returns its workflow ID. A failure to start is
+// logged; the merge is still recorded.
+func (s *Server) startPostMerge(ctx context.Context, project, beadID string, ev forgeEvent) string {
+	if len(s.cfg.Projects[project].PostMergeChecks) == 0 {
+		return ""
+	}
+	c, err := temporal.Dial(s.cfg.Temporal)
+	if err != nil {
+		s.logger.Error("forge webhook: failed to connect to temporal", "error", err)
+		return ""
+	}
+	defer c.Close()
+
+	wo := client.StartWorkflowOptions{
+		ID:        fmt.Sprintf("%s-post-merge-%d", beadID, ev.PR),
+		TaskQueue: s.cfg.Temporal.TaskQueue,
+	}
+	we, err := c.ExecuteWorkflow(ctx, wo, temporal.PostMergeWorkflow, temporal.PostMergeRequest{
+		Project: project, BeadID: beadID, PR: ev.PR, Commit: ev.Commit,
+	})
+	if err != nil {
+		s.logger.Error("forge webhook: failed to start post-merge checks", "project", project, "pr", ev.PR, "error", err)
+		return ""
+	}
+	return we.GetID()
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+	"github.com/antigravity-dev/cortex/internal/webhook"
+)
+
+type forgeResult struct {
+	Kind       string         `json:"kind"`
+	Project    string         `json:"project"`
+	BeadID     string         `json:"bead_id"`
+	Ignored    string         `json:"ignored"`
+	StageError string         `json:"stage_error"`
+	Stage      map[string]any `json:"stage"`
+}
+
+// setupForgeServer returns a server whose test-proj maps to org/repo, with
+// bead cx-1 in the review stage and PR 7 opened by its dispatch.
+func setupForgeServer(t *testing.T) *Server {
+	t.Helper()
+	srv := setupTestServer(t)
+	proj := srv.cfg.Projects["test-proj"]
+	proj.ForgeRepo = "org/repo"
+	srv.cfg.Projects["test-proj"] = proj
+	srv.cfg.API.ForgeWebhooks = config.APIForgeWebhooks{GitHubSecret: "gh-secret", GitLabToken: "gl-token"}
+	srv.cfg.Workflows = map[string]config.WorkflowConfig{
+		"dev": {Stages: []config.StageConfig{
+			{Name: "implement", Role: "coder"},
+			{Name: "review", Role: "reviewer", RequireApproval: true},
+		}},
+	}
+	if err := srv.store.UpsertBeadStage(&store.BeadStage{Project: "test-proj", BeadID: "cx-1", Workflow: "dev", CurrentStage: "review", StageIndex: 1, TotalStages: 2}); err != nil {
+		t.Fatal(err)
+	}
+	id, err := srv.store.RecordDispatch("cx-1", "test-proj", "claude", "claude", "fast", 0, "", "", "", "feat/cx-1", "headless_cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.store.UpdateDispatchPR(id, "https://github.com/org/repo/pull/7", 7); err != nil {
+		t.Fatal(err)
+	}
+
+	fakeBin := t.TempDir()
+	script := "#!/bin/sh\n" +
+		"if [ \"$1\" = show ]; then echo '{\"id\":\"cx-1\",\"status\":\"open\",\"labels\":[\"stage:review\"]}'; fi\n"
+	if err := os.WriteFile(filepath.Join(fakeBin, "bd"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+	return srv
+}
+
+func postGitHub(t *testing.T, srv *Server, event, body, signature string) ([]forgeResult, int) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/github", strings.NewReader(body))
+	req.Header.Set("X-GitHub-Event", event)
+	req.Header.Set("X-Hub-Signature-256", signature)
+	w := httptest.NewRecorder()
+	srv.handleGitHubWebhook(w, req)
+	return decodeForgeResults(t, w), w.Code
+}
+
+func decodeForgeResults(t *testing.T, w *httptest.ResponseRecorder) []forgeResult {
+	t.Helper()
+	if w.Code != http.StatusOK {
+		return nil
+	}
+	var resp struct {
+		Events []forgeResult `json:"events"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp.Events
+}
+
+func TestGitHubWebhookApprovalAdvancesReview(t *testing.T) {
+	srv := setupForgeServer(t)
+	body := `{"action":"submitted","review":{"state":"APPROVED","user":{"login":"alice"}},"pull_request":{"number":7},"repository":{"full_name":"Org/Repo"}}`
+
+	if _, code := postGitHub(t, srv, "pull_request_review", body, "sha256=bad"); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for a bad signature, got %d", code)
+	}
+
+	events, code := postGitHub(t, srv, "pull_request_review", body, webhook.Sign("gh-secret", []byte(body)))
+	if code != http.StatusOK || len(events) != 1 {
+		t.Fatalf("expected one event, got %d %+v", code, events)
+	}
+	if ev := events[0]; ev.BeadID != "cx-1" || ev.Stage["to"] != config.TerminalStage || ev.StageError != "" {
+		t.Fatalf("unexpected result %+v", ev)
+	}
+
+	stage, err := srv.store.GetBeadStage("test-proj", "cx-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stage.CurrentStage != config.TerminalStage || stage.StageHistory[len(stage.StageHistory)-1].ApprovedBy != "alice" {
+		t.Fatalf("unexpected stage %+v", stage)
+	}
+	pending, err := srv.store.ListPendingPRReviews()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("expected the review wait to end, got %+v", pending)
+	}
+}
+
+func TestGitHubWebhookIgnoresUnknownTargets(t *testing.T) {
+	srv := setupForgeServer(t)
+	for _, body := range []string{
+		`{"action":"closed","pull_request":{"number":7,"merged":false},"repository":{"full_name":"other/repo"}}`,
+		`{"action":"closed","pull_request":{"number":99,"merged":true},"repository":{"full_name":"org/repo"}}`,
+	} {
+		events, code := postGitHub(t, srv, "pull_request", body, webhook.Sign("gh-secret", []byte(body)))
+		if code != http.StatusOK || len(events) != 1 || events[0].Ignored == "" {
+			t.Fatalf("expected an ignored event for %s, got %d %+v", body, code, events)
+		}
+	}
+
+	events, code := postGitHub(t, srv, "ping", `{}`, webhook.Sign("gh-secret", []byte(`{}`)))
+	if code != http.StatusOK || len(events) != 0 {
+		t.Fatalf("expected an empty ping response, got %d %+v", code, events)
+	}
+
+	srv.cfg.API.ForgeWebhooks.GitHubSecret = ""
+	if _, code := postGitHub(t, srv, "ping", `{}`, ""); code != http.StatusNotFound {
+		t.Fatalf("expected 404 without a secret, got %d", code)
+	}
+}
+
+func TestGitLabWebhookMergeAndPipeline(t *testing.T) {
+	srv := setupForgeServer(t)
+	post := func(event, token, body string) ([]forgeResult, int) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/gitlab", strings.NewReader(body))
+		req.Header.Set("X-Gitlab-Event", event)
+		req.Header.Set("X-Gitlab-Token", token)
+		w := httptest.NewRecorder()
+		srv.handleGitLabWebhook(w, req)
+		return decodeForgeResults(t, w), w.Code
+	}
+
+	pipeline := `{"project":{"path_with_namespace":"org/repo"},"object_attributes":{"status":"failed"},"merge_request":{"iid":7}}`
+	if _, code := post("Pipeline Hook", "wrong", pipeline); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for a bad token, got %d", code)
+	}
+	events, code := post("Pipeline Hook", "gl-token", pipeline)
+	if code != http.StatusOK || len(events) != 1 || events[0].Kind != "check" || events[0].Stage != nil {
+		t.Fatalf("unexpected pipeline result %d %+v", code, events)
+	}
+
+	merge := `{"user":{"username":"bob"},"project":{"path_with_namespace":"org/repo"},"object_attributes":{"iid":7,"action":"merge"}}`
+	events, code = post("Merge Request Hook", "gl-token", merge)
+	if code != http.StatusOK || len(events) != 1 || events[0].Kind != "merged" {
+		t.Fatalf("unexpected merge result %d %+v", code, events)
+	}
+	// The review stage requires approval, so a merge without one is recorded
+	// but the bead stays put.
+	if events[0].StageError == "" {
+		t.Fatalf("expected the approval guard to hold the bead, got %+v", events[0])
+	}
+
+	health, err := srv.store.GetRecentHealthEvents(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := 0
+	for _, ev := range health {
+		if ev.EventType == "forge_event" {
+			found++
+		}
+	}
+	if found != 2 {
+		t.Fatalf("expected two forge events, got %+v", health)
+	}
+}
+
+func TestForgeMergeEventsCarryMergeCommit(t *testing.T) {
+	gh, err := parseGitHubEvent("pull_request", []byte(`{"action":"closed","pull_request":{"number":7,"merged":true,"merge_commit_sha":"abc123"},"repository":{"full_name":"org/repo"}}`))
+	if err != nil || len(gh) != 1 || gh[0].Kind != "merged" || gh[0].Commit != "abc123" {
+		t.Fatalf("github merge = %+v, %v", gh, err)
+	}
+	gl, err := parseGitLabEvent("Merge Request Hook", []byte(`{"project":{"path_with_namespace":"org/repo"},"object_attributes":{"iid":7,"action":"merge","merge_commit_sha":"def456"}}`))
+	if err != nil || len(gl) != 1 || gl[0].Kind != "merged" || gl[0].Commit != "def456" {
+		t.Fatalf("gitlab merge = %+v, %v", gl, err)
+	}
+}
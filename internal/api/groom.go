@@ -0,0 +1,103 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"go.temporal.io/api/serviceerror"
+
+	"github.com/antigravity-dev/cortex/internal/temporal"
+)
+
+var groomSignals = map[string]string{
+	"trigger": temporal.GroomTriggerSignal,
+	"pause":   temporal.GroomPauseSignal,
+	"resume":  temporal.GroomResumeSignal,
+}
+
+// routeGroom controls a project's strategic groom controller:
+//
+//	GET  /groom/{project}          current state (groom-status query)
+//	POST /groom/{project}/trigger  run a groom now, even while paused
+//	POST /groom/{project}/pause    skip scheduled runs
+//	POST /groom/{project}/resume   resume scheduled runs
+func (s *Server) routeGroom(w http.ResponseWriter, r *http.Request) {
+	project, action, _ := strings.Cut(strings.Trim(strings.TrimPrefix(r.URL.Path, "/groom/"), "/"), "/")
+	if p, ok := s.cfg.Projects[project]; !ok || !p.Enabled {
+		writeError(w, http.StatusNotFound, "unknown project")
+		return
+	}
+
+	if action == "" {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		s.handleGroomStatus(w, r, project)
+		return
+	}
+
+	signal, ok := groomSignals[action]
+	if !ok {
+		writeError(w, http.StatusNotFound, "unknown groom action")
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	c, err := temporal.Dial(s.cfg.Temporal)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to connect to temporal")
+		return
+	}
+	defer c.Close()
+
+	workflowID := temporal.StrategicGroomWorkflowID(project)
+	if err := c.SignalWorkflow(r.Context(), workflowID, "", signal, nil); err != nil {
+		var notFound *serviceerror.NotFound
+		if errors.As(err, &notFound) {
+			writeError(w, http.StatusNotFound, "strategic groom controller not running")
+			return
+		}
+		s.logger.Error("failed to signal strategic groom", "project", project, "signal", signal, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to send signal")
+		return
+	}
+
+	writeJSON(w, map[string]any{
+		"project":     project,
+		"workflow_id": workflowID,
+		"signal":      signal,
+	})
+}
+
+// handleGroomStatus queries the controller's state.
+func (s *Server) handleGroomStatus(w http.ResponseWriter, r *http.Request, project string) {
+	c, err := temporal.Dial(s.cfg.Temporal)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to connect to temporal")
+		return
+	}
+	defer c.Close()
+
+	val, err := c.QueryWorkflow(r.Context(), temporal.StrategicGroomWorkflowID(project), "", temporal.GroomStatusQuery)
+	if err != nil {
+		var notFound *serviceerror.NotFound
+		if errors.As(err, &notFound) {
+			writeError(w, http.StatusNotFound, "strategic groom controller not running")
+			return
+		}
+		s.logger.Error("failed to query strategic groom", "project", project, "error", err)
+		writeError(w, http.StatusBadGateway, "failed to query strategic groom")
+		return
+	}
+	var state temporal.StrategicGroomState
+	if err := val.Get(&state); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to decode groom status")
+		return
+	}
+	writeJSON(w, state)
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouteGroomRejectsBadRequests(t *testing.T) {
+	srv := setupTestServer(t)
+	for _, tc := range []struct {
+		method, path string
+		want         int
+	}{
+		{http.MethodGet, "/groom/missing", http.StatusNotFound},
+		{http.MethodPost, "/groom/missing/trigger", http.StatusNotFound},
+		{http.MethodPost, "/groom/test-proj/explode", http.StatusNotFound},
+		{http.MethodGet, "/groom/test-proj/trigger", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/groom/test-proj", http.StatusMethodNotAllowed},
+	} {
+		w := httptest.NewRecorder()
+		srv.routeGroom(w, httptest.NewRequest(tc.method, tc.path, nil))
+		if w.Code != tc.want {
+			t.Errorf("%s %s = %d, want %d", tc.method, tc.path, w.Code, tc.want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+const (
+	// maxHealthEventBatch bounds one ingestion request so a misbehaving
+	// exporter cannot flood the health_events stream in a single call.
+	maxHealthEventBatch   = 500
+	maxHealthEventBody    = 1 << 20
+	maxHealthEventDetails = 4096
+)
+
+type healthEventInput struct {
+	EventType  string `json:"event_type"`
+	Details    string `json:"details"`
+	Source     string `json:"source"`
+	DispatchID int64  `json:"dispatch_id"`
+	BeadID     string `json:"bead_id"`
+	Project    string `json:"project"`
+	Severity   string `json:"severity"` // info, warn or critical; defaults to the event type's
+	Time       string `json:"time"`     // RFC3339; defaults to receipt time
+}
+
+type healthEventBatch struct {
+	Source string             `json:"source"` // default for events without their own source
+	Events []healthEventInput `json:"events"`
+}
+
+// POST /health/events — ingest a batch of health events from an external
+// monitor (node exporters, backup jobs, db-restore). Every event must carry a
+// source, either its own or the batch default, so external signals stay
+// distinguishable from scheduler-internal ones. The batch is stored atomically.
+func (s *Server) handleHealthEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var batch healthEventBatch
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxHealthEventBody)).Decode(&batch); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json request body")
+		return
+	}
+	if len(batch.Events) == 0 {
+		writeError(w, http.StatusBadRequest, "events must not be empty")
+		return
+	}
+	if len(batch.Events) > maxHealthEventBatch {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("at most %d events per batch", maxHealthEventBatch))
+		return
+	}
+
+	events, err := healthEventsFromBatch(batch)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	n, err := s.store.RecordHealthEvents(events)
+	if err != nil {
+		s.logger.Error("failed to record health events", "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to record health events")
+		return
+	}
+	s.logger.Info("health events ingested", "count", n, "source", batch.Source)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]any{"accepted": n})
+}
+
+func healthEventsFromBatch(batch healthEventBatch) ([]store.HealthEvent, error) {
+	events := make([]store.HealthEvent, 0, len(batch.Events))
+	for i, in := range batch.Events {
+		e := store.HealthEvent{
+			EventType:  strings.TrimSpace(in.EventType),
+			Details:    in.Details,
+			DispatchID: in.DispatchID,
+			BeadID:     strings.TrimSpace(in.BeadID),
+			Source:     strings.TrimSpace(in.Source),
+			Project:    strings.TrimSpace(in.Project),
+			Severity:   strings.ToLower(strings.TrimSpace(in.Severity)),
+		}
+		if e.Source == "" {
+			e.Source = strings.TrimSpace(batch.Source)
+		}
+		switch {
+		case e.EventType == "":
+			return nil, fmt.Errorf("event %d: event_type is required", i)
+		case e.Source == "":
+			return nil, fmt.Errorf("event %d: source is required", i)
+		case e.DispatchID < 0:
+			return nil, fmt.Errorf("event %d: dispatch_id must not be negative", i)
+		case e.Severity != "" && !slices.Contains(store.HealthSeverities, e.Severity):
+			return nil, fmt.Errorf("event %d: severity must be one of %s", i, strings.Join(store.HealthSeverities, ", "))
+		case len(e.Details) > maxHealthEventDetails:
+			return nil, fmt.Errorf("event %d: details longer than %d bytes", i, maxHealthEventDetails)
+		}
+		if in.Time != "" {
+			t, err := time.Parse(time.RFC3339, in.Time)
+			if err != nil {
+				return nil, fmt.Errorf("event %d: time must be RFC3339", i)
+			}
+			e.CreatedAt = t
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+// parseSeverities reads a comma-separated severity filter, such as
+// ?severity=warn,critical. An empty filter matches every severity.
+func parseSeverities(raw string) ([]string, error) {
+	var severities []string
+	for _, severity := range strings.Split(raw, ",") {
+		severity = strings.ToLower(strings.TrimSpace(severity))
+		if severity == "" {
+			continue
+		}
+		if !slices.Contains(store.HealthSeverities, severity) {
+			return nil, fmt.Errorf("severity must be one of %s", strings.Join(store.HealthSeverities, ", "))
+		}
+		severities = append(severities, severity)
+	}
+	return severities, nil
+}
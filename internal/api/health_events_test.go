@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleHealthEventsIngestsBatch(t *testing.T) {
+	srv := setupTestServer(t)
+
+	body := `{"source":"node-exporter","events":[
+		{"event_type":"disk_pressure","details":"/var at 92%","severity":"critical","project":"api"},
+		{"event_type":"restore_verified","source":"db-restore","time":"2026-01-02T03:04:05Z"}
+	]}`
+	req := httptest.NewRequest(http.MethodPost, "/health/events", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	srv.handleHealthEvents(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp map[string]any
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp["accepted"] != float64(2) {
+		t.Fatalf("accepted = %v, want 2", resp["accepted"])
+	}
+
+	events, err := srv.store.GetRecentHealthEvents(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Source != "node-exporter" || events[0].Severity != "critical" || events[0].Project != "api" {
+		t.Fatalf("recent events = %+v, want one critical node-exporter event for api", events)
+	}
+}
+
+func TestHandleHealthEventsRejectsInvalidBatches(t *testing.T) {
+	srv := setupTestServer(t)
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		code   int
+	}{
+		{"wrong method", http.MethodGet, "", http.StatusMethodNotAllowed},
+		{"bad json", http.MethodPost, "{", http.StatusBadRequest},
+		{"empty batch", http.MethodPost, `{"source":"x","events":[]}`, http.StatusBadRequest},
+		{"missing source", http.MethodPost, `{"events":[{"event_type":"a"}]}`, http.StatusBadRequest},
+		{"missing type", http.MethodPost, `{"source":"x","events":[{"details":"a"}]}`, http.StatusBadRequest},
+		{"bad severity", http.MethodPost, `{"source":"x","events":[{"event_type":"a","severity":"page"}]}`, http.StatusBadRequest},
+		{"bad time", http.MethodPost, `{"source":"x","events":[{"event_type":"a","time":"yesterday"}]}`, http.StatusBadRequest},
+		{"too many", http.MethodPost, `{"source":"x","events":[` + strings.Repeat(`{"event_type":"a"},`, maxHealthEventBatch) + `{"event_type":"a"}]}`, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/health/events", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			srv.handleHealthEvents(w, req)
+			if w.Code != tt.code {
+				t.Fatalf("expected %d, got %d: %s", tt.code, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	events, _ := srv.store.GetRecentHealthEvents(24 * 365)
+	if len(events) != 0 {
+		t.Fatalf("rejected batches stored %d events", len(events))
+	}
+}
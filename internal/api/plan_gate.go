@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/antigravity-dev/cortex/internal/beads"
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+	"github.com/antigravity-dev/cortex/internal/temporal"
+)
+
+// planGateRequest selects a plan gate scope: a whole project, or one epic
+// of it.
+type planGateRequest struct {
+	Project    string `json:"project"`
+	EpicID     string `json:"epic_id,omitempty"`
+	PlanID     string `json:"plan_id,omitempty"`
+	ApprovedBy string `json:"approved_by,omitempty"`
+}
+
+// GET /scheduler/plan
+// Lists the active approved plans and whether dispatch requires one.
+func (s *Server) handlePlanGate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	plans, err := s.store.ListActiveApprovedPlans()
+	if err != nil {
+		s.logger.Error("failed to list approved plans", "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to list approved plans")
+		return
+	}
+	if plans == nil {
+		plans = []store.ExecutionPlanGate{}
+	}
+	writeJSON(w, map[string]any{
+		"required": s.cfg.Chief.RequireApprovedPlan,
+		"plans":    plans,
+	})
+}
+
+// POST /scheduler/plan/activate and /scheduler/plan/clear
+// Opens or closes the plan gate of one project, or of one epic when epic_id
+// is set.
+func (s *Server) handlePlanGateControl(w http.ResponseWriter, r *http.Request) {
+	action := strings.TrimPrefix(strings.TrimSuffix(r.URL.Path, "/"), "/scheduler/plan/")
+	if action != "activate" && action != "clear" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var req planGateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json request body")
+		return
+	}
+	req.Project, req.EpicID = strings.TrimSpace(req.Project), strings.TrimSpace(req.EpicID)
+	if _, ok := s.cfg.Projects[req.Project]; !ok {
+		writeError(w, http.StatusBadRequest, "project must name a configured project")
+		return
+	}
+
+	if action == "clear" {
+		cleared, err := s.store.ClearActiveApprovedPlan(req.Project, req.EpicID)
+		if err != nil {
+			s.logger.Error("failed to clear approved plan", "project", req.Project, "epic", req.EpicID, "error", err)
+			writeError(w, http.StatusInternalServerError, "failed to clear approved plan")
+			return
+		}
+		s.logger.Info("plan gate cleared", "project", req.Project, "epic", req.EpicID, "was_active", cleared)
+		writeJSON(w, map[string]any{"status": "cleared", "project": req.Project, "epic_id": req.EpicID, "was_active": cleared})
+		return
+	}
+
+	if strings.TrimSpace(req.PlanID) == "" {
+		writeError(w, http.StatusBadRequest, "plan_id is required")
+		return
+	}
+	if err := s.store.SetActiveApprovedPlan(req.Project, req.EpicID, req.PlanID, req.ApprovedBy); err != nil {
+		s.logger.Error("failed to activate approved plan", "project", req.Project, "epic", req.EpicID, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to activate approved plan")
+		return
+	}
+	plan, err := s.store.GetActiveApprovedPlan(req.Project, req.EpicID)
+	if err != nil || plan == nil {
+		writeError(w, http.StatusInternalServerError, "failed to read activated plan")
+		return
+	}
+	s.logger.Info("plan gate activated", "project", req.Project, "epic", req.EpicID, "plan", plan.PlanID, "approved_by", plan.ApprovedBy)
+	writeJSON(w, plan)
+}
+
+// planGateHeld returns why no approved plan opens dispatch for the task's
+// bead, or "". The bead is only looked up, for its epic, when the project
+// has no project-wide plan.
+func (s *Server) planGateHeld(ctx context.Context, proj config.Project, req temporal.TaskRequest) string {
+	open, _, err := s.store.HasActiveApprovedPlan(req.Project, "")
+	if err != nil {
+		s.logger.Warn("failed to read plan gate", "project", req.Project, "error", err)
+		return ""
+	}
+	if open {
+		return ""
+	}
+	if bead, err := beads.ShowBeadCtx(ctx, proj.BeadsDir, req.BeadID); err == nil && bead.ParentID != "" {
+		open, _, err := s.store.HasActiveApprovedPlan(req.Project, bead.ParentID)
+		if err != nil {
+			s.logger.Warn("failed to read plan gate", "project", req.Project, "epic", bead.ParentID, "error", err)
+			return ""
+		}
+		if open {
+			return ""
+		}
+		return "no approved plan for " + req.Project + " or epic " + bead.ParentID
+	}
+	return "no approved plan for " + req.Project
+}
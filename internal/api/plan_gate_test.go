@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/temporal"
+)
+
+func TestPlanGateScopedPerProject(t *testing.T) {
+	srv := setupTestServer(t)
+	srv.cfg.Chief.RequireApprovedPlan = true
+	srv.cfg.Projects["other-proj"] = config.Project{Enabled: true, Workspace: "/tmp/other", BeadsDir: "/tmp/other/.beads"}
+	fakeBin := t.TempDir()
+	script := "#!/bin/sh\necho '{\"id\":\"cx-1\",\"parent_id\":\"cx-epic\",\"issue_type\":\"task\"}'\n"
+	if err := os.WriteFile(fakeBin+"/bd", []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		srv.handlePlanGateControl(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return w
+	}
+	held := func(project string) string {
+		return srv.dispatchHeld(context.Background(), temporal.TaskRequest{BeadID: "cx-1", Project: project})
+	}
+
+	if reason := held("test-proj"); reason != "no approved plan for test-proj or epic cx-epic" {
+		t.Fatalf("expected dispatch held without a plan, got %q", reason)
+	}
+
+	if w := post("/scheduler/plan/activate", `{"project":"other-proj","plan_id":"plan-b"}`); w.Code != http.StatusOK {
+		t.Fatalf("activate other-proj: %d %s", w.Code, w.Body.String())
+	}
+	if reason := held("test-proj"); reason == "" {
+		t.Fatal("a plan for other-proj opened test-proj")
+	}
+	if reason := held("other-proj"); reason != "" {
+		t.Fatalf("expected other-proj open, got %q", reason)
+	}
+
+	if w := post("/scheduler/plan/activate", `{"project":"test-proj","epic_id":"cx-epic","plan_id":"plan-a","approved_by":"simon"}`); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"epic_id":"cx-epic"`) {
+		t.Fatalf("activate epic: %d %s", w.Code, w.Body.String())
+	}
+	if reason := held("test-proj"); reason != "" {
+		t.Fatalf("expected epic plan to open its bead, got %q", reason)
+	}
+
+	w := httptest.NewRecorder()
+	srv.handlePlanGate(w, httptest.NewRequest(http.MethodGet, "/scheduler/plan", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"required":true`) || strings.Count(w.Body.String(), `"plan_id"`) != 2 {
+		t.Fatalf("plan status: %d %s", w.Code, w.Body.String())
+	}
+
+	if w := post("/scheduler/plan/clear", `{"project":"test-proj","epic_id":"cx-epic"}`); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"was_active":true`) {
+		t.Fatalf("clear epic: %d %s", w.Code, w.Body.String())
+	}
+	if reason := held("test-proj"); reason == "" {
+		t.Fatal("expected dispatch held after clearing the epic plan")
+	}
+
+	cases := []struct {
+		path, body string
+		want       int
+	}{
+		{"/scheduler/plan/activate", `{"project":"nope","plan_id":"p"}`, http.StatusBadRequest},
+		{"/scheduler/plan/activate", `{"project":"test-proj"}`, http.StatusBadRequest},
+		{"/scheduler/plan/bogus", `{}`, http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		if w := post(tc.path, tc.body); w.Code != tc.want {
+			t.Errorf("POST %s %s: got %d, want %d", tc.path, tc.body, w.Code, tc.want)
+		}
+	}
+}
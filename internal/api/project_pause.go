@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+// ProjectPauseStatus reports whether a project's new dispatches are held.
+type ProjectPauseStatus struct {
+	Project  string     `json:"project"`
+	Paused   bool       `json:"paused"`
+	Config   bool       `json:"config"` // paused by the project's paused flag, which only a config change lifts
+	PausedBy string     `json:"paused_by,omitempty"`
+	PausedAt *time.Time `json:"paused_at,omitempty"`
+}
+
+// POST /projects/{project}/pause   hold new dispatches for the project
+// POST /projects/{project}/resume  lift a pause made here or with /cortex pause
+// The pause body is optional: {"paused_by": "release-manager"}. Running
+// dispatches finish, and other projects keep dispatching.
+func (s *Server) handleProjectPause(w http.ResponseWriter, r *http.Request, project, action string) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	proj, ok := s.cfg.Projects[project]
+	if !ok {
+		writeError(w, http.StatusNotFound, "project not found")
+		return
+	}
+
+	switch action {
+	case "pause":
+		var req struct {
+			PausedBy string `json:"paused_by"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		pausedBy := strings.TrimSpace(req.PausedBy)
+		if pausedBy == "" {
+			pausedBy = "api"
+		}
+		if err := s.store.PauseProject(project, pausedBy); err != nil {
+			s.logger.Error("failed to pause project", "project", project, "error", err)
+			writeError(w, http.StatusInternalServerError, "failed to pause project")
+			return
+		}
+		s.logger.Info("project paused", "project", project, "paused_by", pausedBy)
+	case "resume":
+		if _, err := s.store.ResumeProject(project); err != nil {
+			s.logger.Error("failed to resume project", "project", project, "error", err)
+			writeError(w, http.StatusInternalServerError, "failed to resume project")
+			return
+		}
+		if proj.Paused {
+			writeError(w, http.StatusConflict, "project is still paused in config; set paused = false and reload")
+			return
+		}
+		s.logger.Info("project resumed", "project", project)
+	}
+
+	status, err := s.projectPauseStatus(project, proj)
+	if err != nil {
+		s.logger.Error("failed to read project pause", "project", project, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to read project pause")
+		return
+	}
+	writeJSON(w, status)
+}
+
+func (s *Server) projectPauseStatus(name string, proj config.Project) (ProjectPauseStatus, error) {
+	status := ProjectPauseStatus{Project: name, Paused: proj.Paused, Config: proj.Paused}
+	pause, err := s.store.GetProjectPause(name)
+	if err != nil {
+		return status, err
+	}
+	if pause != nil {
+		status.Paused = true
+		status.PausedBy = pause.PausedBy
+		status.PausedAt = &pause.PausedAt
+	}
+	return status, nil
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/antigravity-dev/cortex/internal/temporal"
+)
+
+func TestHandleProjectPause(t *testing.T) {
+	srv := setupTestServer(t)
+	post := func(path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		srv.handleProjectDetail(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return w
+	}
+	held := func() string {
+		return srv.dispatchHeld(context.Background(), temporal.TaskRequest{BeadID: "cx-1", Project: "test-proj"})
+	}
+
+	w := post("/projects/test-proj/pause", `{"paused_by": "release-manager"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var status ProjectPauseStatus
+	if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+		t.Fatal(err)
+	}
+	if !status.Paused || status.Config || status.PausedBy != "release-manager" || status.PausedAt == nil {
+		t.Fatalf("unexpected pause status: %+v", status)
+	}
+	if reason := held(); reason != "project paused by release-manager" {
+		t.Fatalf("expected paused project to hold dispatch, got %q", reason)
+	}
+
+	w = httptest.NewRecorder()
+	srv.handleProjectDetail(w, httptest.NewRequest(http.MethodGet, "/projects/test-proj", nil))
+	if !strings.Contains(w.Body.String(), `"paused_by":"release-manager"`) {
+		t.Fatalf("expected project detail to report the pause: %s", w.Body.String())
+	}
+
+	if w := post("/projects/test-proj/resume", ""); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"paused":false`) {
+		t.Fatalf("unexpected resume response %d: %s", w.Code, w.Body.String())
+	}
+	if reason := held(); reason != "" {
+		t.Fatalf("expected resumed project to dispatch, got %q", reason)
+	}
+
+	// A pause with no body is attributed to the API.
+	if w := post("/projects/test-proj/pause", ""); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"paused_by":"api"`) {
+		t.Fatalf("unexpected pause response %d: %s", w.Code, w.Body.String())
+	}
+
+	proj := srv.cfg.Projects["test-proj"]
+	proj.Paused = true
+	srv.cfg.Projects["test-proj"] = proj
+	if w := post("/projects/test-proj/resume", ""); w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 while paused in config, got %d: %s", w.Code, w.Body.String())
+	}
+	if reason := held(); reason != "project paused in config" {
+		t.Fatalf("expected config pause to hold dispatch, got %q", reason)
+	}
+
+	for path, code := range map[string]int{
+		"/projects/nope/pause":     http.StatusNotFound,
+		"/projects/test-proj/stop": http.StatusNotFound,
+	} {
+		if w := post(path, ""); w.Code != code {
+			t.Errorf("%s: expected %d, got %d", path, code, w.Code)
+		}
+	}
+	w = httptest.NewRecorder()
+	srv.handleProjectDetail(w, httptest.NewRequest(http.MethodGet, "/projects/test-proj/pause", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405 for GET, got %d", w.Code)
+	}
+}
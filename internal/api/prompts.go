@@ -0,0 +1,72 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// promptAttemptResponse is one execution attempt's coder prompt. Diff is
+// against the previous attempt of the same workflow.
+type promptAttemptResponse struct {
+	WorkflowID string   `json:"workflow_id"`
+	Attempt    int      `json:"attempt"`
+	Agent      string   `json:"agent"`
+	Provider   string   `json:"provider,omitempty"`
+	Tier       string   `json:"tier"`
+	CreatedAt  string   `json:"created_at"`
+	Changes    []string `json:"changes,omitempty"`
+	Diff       string   `json:"diff,omitempty"`
+	Prompt     string   `json:"prompt,omitempty"`
+}
+
+// handlePromptAttempts serves GET /dispatches/{bead_id}/prompts. Full prompt
+// bodies are large, so they are only included with ?full=1.
+func (s *Server) handlePromptAttempts(w http.ResponseWriter, r *http.Request, beadID string) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if beadID == "" {
+		writeError(w, http.StatusBadRequest, "bead_id required")
+		return
+	}
+
+	attempts, err := s.store.GetPromptAttempts(beadID)
+	if err != nil {
+		s.logger.Error("failed to query prompt attempts", "bead_id", beadID, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to query prompt attempts")
+		return
+	}
+
+	withPrompt := fullPrompts(r.URL)
+	out := make([]promptAttemptResponse, 0, len(attempts))
+	for _, p := range attempts {
+		resp := promptAttemptResponse{
+			WorkflowID: p.WorkflowID,
+			Attempt:    p.Attempt,
+			Agent:      p.Agent,
+			Provider:   p.Provider,
+			Tier:       p.Tier,
+			CreatedAt:  p.CreatedAt.Format(time.RFC3339),
+			Changes:    p.Changes,
+			Diff:       p.Diff,
+		}
+		if withPrompt {
+			resp.Prompt = p.Prompt
+		}
+		out = append(out, resp)
+	}
+
+	writeJSON(w, map[string]any{
+		"bead_id":  beadID,
+		"attempts": out,
+	})
+}
+
+// fullPrompts reports whether a prompt attempts request asked for the prompt
+// bodies with ?full=1.
+func fullPrompts(u *url.URL) bool {
+	full := u.Query().Get("full")
+	return full == "1" || full == "true"
+}
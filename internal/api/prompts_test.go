@@ -0,0 +1,61 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestHandlePromptAttempts(t *testing.T) {
+	srv := setupTestServer(t)
+	for _, p := range []store.PromptAttempt{
+		{WorkflowID: "wf-1", BeadID: "cx-1", Attempt: 1, Agent: "claude", Tier: "temporal", Prompt: "TASK: x\nImplement."},
+		{WorkflowID: "wf-1", BeadID: "cx-1", Attempt: 2, Agent: "codex", Tier: "temporal", Prompt: "TASK: x\nPREVIOUS ERRORS TO FIX:\nboom\nImplement."},
+	} {
+		if _, err := srv.store.RecordPromptAttempt(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	get := func(path string) (int, map[string]any) {
+		w := httptest.NewRecorder()
+		srv.handleDispatchDetail(w, httptest.NewRequest(http.MethodGet, path, nil))
+		var body map[string]any
+		json.NewDecoder(w.Body).Decode(&body)
+		return w.Code, body
+	}
+
+	code, body := get("/dispatches/cx-1/prompts")
+	if code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	attempts := body["attempts"].([]any)
+	if len(attempts) != 2 {
+		t.Fatalf("expected 2 attempts, got %d", len(attempts))
+	}
+	second := attempts[1].(map[string]any)
+	if !strings.Contains(second["diff"].(string), "+boom") {
+		t.Fatalf("expected diff with new failure info, got %v", second["diff"])
+	}
+	if changes := second["changes"].([]any); len(changes) != 1 || changes[0] != "agent: claude -> codex" {
+		t.Fatalf("unexpected changes %v", changes)
+	}
+	if _, ok := second["prompt"]; ok {
+		t.Fatal("prompt body should be omitted without ?full=1")
+	}
+
+	_, body = get("/dispatches/cx-1/prompts?full=1")
+	first := body["attempts"].([]any)[0].(map[string]any)
+	if first["prompt"] != "TASK: x\nImplement." {
+		t.Fatalf("expected full prompt, got %v", first["prompt"])
+	}
+
+	_, body = get("/dispatches/cx-unknown/prompts")
+	if attempts := body["attempts"].([]any); len(attempts) != 0 {
+		t.Fatalf("expected no attempts, got %v", attempts)
+	}
+}
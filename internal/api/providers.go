@@ -0,0 +1,145 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/dispatch"
+	"github.com/antigravity-dev/cortex/internal/learner"
+)
+
+// providerHealthWindow is how far back the learner health score looks.
+const providerHealthWindow = 7 * 24 * time.Hour
+
+// ProviderUsage counts the dispatches a provider started recently.
+type ProviderUsage struct {
+	Last5h     int `json:"last_5h"`
+	LastWeek   int `json:"last_week"`
+	LastMinute int `json:"last_minute"`
+}
+
+// ProviderQuota is one provider's entry in GET /api/v1/providers.
+type ProviderQuota struct {
+	Name         string                   `json:"name"`
+	Tier         string                   `json:"tier"`
+	Model        string                   `json:"model"`
+	Authed       bool                     `json:"authed"`
+	Usage        ProviderUsage            `json:"usage"`
+	Reservations int                      `json:"reservations"`
+	Budget       *dispatch.ProviderBudget `json:"budget,omitempty"` // nil until the worker has started
+	Health       *learner.ProviderHealth  `json:"health,omitempty"` // nil without finished dispatches
+	Excluded     bool                     `json:"excluded"`
+}
+
+// AuthedQuota is the dispatch cap shared by every authed provider.
+type AuthedQuota struct {
+	Window5hCap       int `json:"window_5h_cap"`
+	Window5hUsed      int `json:"window_5h_used"`
+	Window5hHeadroom  int `json:"window_5h_headroom"`
+	WeeklyCap         int `json:"weekly_cap"`
+	WeeklyUsed        int `json:"weekly_used"`
+	WeeklyHeadroom    int `json:"weekly_headroom"`
+	WeeklyHeadroomPct int `json:"weekly_headroom_pct"`
+}
+
+// ProvidersResponse is the body of GET /api/v1/providers.
+type ProvidersResponse struct {
+	Authed    AuthedQuota     `json:"authed"`
+	Providers []ProviderQuota `json:"providers"`
+}
+
+// GET /api/v1/providers
+// Shows each provider's recent usage, remaining headroom, reservations held
+// by running dispatches, learner health score and circuit breaker state.
+func (s *Server) handleProviders(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	now := time.Now()
+	resp, err := s.providerQuotas(now)
+	if err != nil {
+		s.logger.Error("failed to build provider quotas", "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to build provider quotas")
+		return
+	}
+	writeJSON(w, resp)
+}
+
+func (s *Server) providerQuotas(now time.Time) (*ProvidersResponse, error) {
+	limits := s.cfg.RateLimits
+	used5h, err := s.store.CountAuthedUsage5h()
+	if err != nil {
+		return nil, err
+	}
+	usedWeekly, err := s.store.CountAuthedUsageWeekly()
+	if err != nil {
+		return nil, err
+	}
+	resp := &ProvidersResponse{
+		Authed: AuthedQuota{
+			Window5hCap:       limits.Window5hCap,
+			Window5hUsed:      used5h,
+			Window5hHeadroom:  max(0, limits.Window5hCap-used5h),
+			WeeklyCap:         limits.WeeklyCap,
+			WeeklyUsed:        usedWeekly,
+			WeeklyHeadroom:    max(0, limits.WeeklyCap-usedWeekly),
+			WeeklyHeadroomPct: limits.WeeklyHeadroomPct,
+		},
+		Providers: []ProviderQuota{},
+	}
+
+	lastMinute, err := s.store.CountProviderDispatchesSince(now.Add(-time.Minute))
+	if err != nil {
+		return nil, err
+	}
+	last5h, err := s.store.CountProviderDispatchesSince(now.Add(-5 * time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	lastWeek, err := s.store.CountProviderDispatchesSince(now.Add(-7 * 24 * time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	running, err := s.store.GetRunningDispatches()
+	if err != nil {
+		return nil, err
+	}
+	reservations := make(map[string]int)
+	for _, d := range running {
+		reservations[d.Provider]++
+	}
+	health, err := learner.ProviderHealthScores(s.store, providerHealthWindow)
+	if err != nil {
+		return nil, err
+	}
+	limiter := s.rateLimiter()
+
+	for name, p := range s.cfg.Providers {
+		q := ProviderQuota{
+			Name:   name,
+			Tier:   p.Tier,
+			Model:  p.Model,
+			Authed: p.Authed,
+			Usage: ProviderUsage{
+				Last5h:     last5h[name],
+				LastWeek:   lastWeek[name],
+				LastMinute: lastMinute[name],
+			},
+			Reservations: reservations[name],
+		}
+		if h, ok := health[name]; ok {
+			q.Health = &h
+		}
+		if limiter != nil {
+			budget := limiter.ProviderBudget(name, p)
+			q.Budget = &budget
+			q.Excluded = budget.CircuitOpen(now)
+		}
+		resp.Providers = append(resp.Providers, q)
+	}
+	sort.Slice(resp.Providers, func(i, j int) bool { return resp.Providers[i].Name < resp.Providers[j].Name })
+	return resp, nil
+}
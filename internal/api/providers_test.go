@@ -0,0 +1,87 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/dispatch"
+)
+
+func TestHandleProviders(t *testing.T) {
+	srv := setupTestServer(t)
+	srv.cfg.RateLimits = config.RateLimits{Window5hCap: 10, WeeklyCap: 100, WeeklyHeadroomPct: 80}
+	srv.cfg.Providers = map[string]config.Provider{
+		"claude": {Tier: "premium", Authed: true, Model: "opus", RequestsPerMinute: 30},
+		"codex":  {Tier: "fast", Model: "gpt"},
+	}
+
+	done, err := srv.store.RecordDispatch("bead-1", "test-proj", "agent", "claude", "premium", 0, "", "", "", "", "temporal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.store.UpdateDispatchStatus(done, "completed", 0, 10); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := srv.store.RecordDispatch("bead-2", "test-proj", "agent", "claude", "premium", 0, "", "", "", "", "temporal"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := srv.store.RecordProviderUsage("opus", "agent", "bead-2"); err != nil {
+		t.Fatal(err)
+	}
+
+	get := func() ProvidersResponse {
+		t.Helper()
+		w := httptest.NewRecorder()
+		srv.handleProviders(w, httptest.NewRequest(http.MethodGet, "/api/v1/providers", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var resp ProvidersResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	srv.rateLimiter = func() *dispatch.RateLimiter { return nil }
+	resp := get()
+	if resp.Authed.Window5hUsed != 1 || resp.Authed.Window5hHeadroom != 9 || resp.Authed.WeeklyHeadroom != 99 {
+		t.Fatalf("unexpected authed quota %+v", resp.Authed)
+	}
+	if len(resp.Providers) != 2 || resp.Providers[0].Name != "claude" || resp.Providers[1].Name != "codex" {
+		t.Fatalf("unexpected providers %+v", resp.Providers)
+	}
+	claude := resp.Providers[0]
+	if claude.Usage.Last5h != 2 || claude.Usage.LastWeek != 2 || claude.Reservations != 1 {
+		t.Fatalf("unexpected claude usage %+v, reservations %d", claude.Usage, claude.Reservations)
+	}
+	if claude.Health == nil || claude.Health.Samples != 1 {
+		t.Fatalf("expected claude health from one finished dispatch, got %+v", claude.Health)
+	}
+	if claude.Budget != nil || claude.Excluded {
+		t.Fatalf("expected no budget before the worker starts, got %+v", claude)
+	}
+	if resp.Providers[1].Health != nil {
+		t.Fatalf("expected no health score for codex, got %+v", resp.Providers[1].Health)
+	}
+
+	limiter := dispatch.NewRateLimiter(srv.store, srv.cfg.RateLimits)
+	limiter.SetCircuitBreakers(config.DispatchCircuitBreakers{
+		Provider: config.CircuitBreaker{Threshold: 2, Window: config.Duration{Duration: time.Hour}, Cooldown: config.Duration{Duration: time.Hour}},
+	})
+	for i := 0; i < 2; i++ {
+		limiter.RecordProviderOutcome("codex", srv.cfg.Providers["codex"], dispatch.FailureUnknown)
+	}
+	srv.rateLimiter = func() *dispatch.RateLimiter { return limiter }
+	resp = get()
+	if b := resp.Providers[0].Budget; b == nil || b.RequestsPerMinute != 30 || b.RequestsRemaining != 30 || resp.Providers[0].Excluded {
+		t.Fatalf("unexpected claude budget %+v", resp.Providers[0])
+	}
+	if !resp.Providers[1].Excluded || resp.Providers[1].Budget.RequestsRemaining != -1 {
+		t.Fatalf("expected codex excluded by the circuit breaker, got %+v", resp.Providers[1])
+	}
+}
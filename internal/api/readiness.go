@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/health"
+)
+
+const healthzTimeout = 2 * time.Second
+
+// Readiness is the daemon state /readyz checks beyond the server's own
+// config.
+type Readiness struct {
+	LockHeld   bool              // the single-instance lock in general.lock_file is held
+	Ticks      *health.Heartbeat // beaten every general.tick_interval
+	WorkerDone <-chan struct{}   // closed once the Temporal worker stops
+}
+
+// SetReadiness makes /readyz check r.
+func (s *Server) SetReadiness(r Readiness) {
+	s.readiness = r
+}
+
+// ReadinessCheck is one condition /readyz checked.
+type ReadinessCheck struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// GET /healthz - liveness: the process answers and the state DB is
+// reachable; 503 otherwise.
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	if err := s.pingStore(r.Context()); err != nil {
+		writeJSONStatus(w, http.StatusServiceUnavailable, map[string]any{"status": "unavailable", "error": err.Error()})
+		return
+	}
+	writeJSON(w, map[string]any{"status": "ok"})
+}
+
+// GET /readyz - readiness: config loaded, instance lock held, ticks
+// recent and, on the active instance, the Temporal worker running; 503
+// listing the failed checks otherwise.
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	checks := s.readinessChecks(r.Context(), time.Now())
+	ready := true
+	for _, c := range checks {
+		ready = ready && c.OK
+	}
+	code := http.StatusOK
+	if !ready {
+		code = http.StatusServiceUnavailable
+	}
+	writeJSONStatus(w, code, map[string]any{"ready": ready, "checks": checks})
+}
+
+func (s *Server) readinessChecks(ctx context.Context, now time.Time) []ReadinessCheck {
+	config := ReadinessCheck{Name: "config", OK: s.cfg != nil}
+	if !config.OK {
+		config.Detail = "no config loaded"
+		return []ReadinessCheck{config}
+	}
+
+	db := ReadinessCheck{Name: "state_db", OK: true}
+	if err := s.pingStore(ctx); err != nil {
+		db.OK, db.Detail = false, err.Error()
+	}
+
+	lock := ReadinessCheck{Name: "lock", OK: true}
+	switch {
+	case s.cfg.General.LockFile == "":
+		lock.Detail = "no lock file configured"
+	case !s.readiness.LockHeld:
+		lock.OK, lock.Detail = false, "lock not held: "+s.cfg.General.LockFile
+	}
+
+	ticks := ReadinessCheck{Name: "ticks", OK: true}
+	interval := s.cfg.General.TickInterval.Duration
+	if last := s.readiness.Ticks.Last(); last.IsZero() {
+		ticks.OK, ticks.Detail = false, "no tick yet"
+	} else if age := now.Sub(last); interval > 0 && age > 3*interval {
+		ticks.OK, ticks.Detail = false, fmt.Sprintf("last tick %s ago, tick_interval %s", age.Round(time.Second), interval)
+	}
+
+	worker := ReadinessCheck{Name: "worker", OK: true}
+	if !s.elector.Leading() {
+		worker.Detail = "standby instance"
+	} else if s.readiness.WorkerDone != nil {
+		select {
+		case <-s.readiness.WorkerDone:
+			worker.OK, worker.Detail = false, "temporal worker stopped"
+		default:
+		}
+	}
+	return []ReadinessCheck{config, db, lock, ticks, worker}
+}
+
+func (s *Server) pingStore(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, healthzTimeout)
+	defer cancel()
+	var one int
+	if err := s.store.DB().QueryRowContext(ctx, `SELECT 1`).Scan(&one); err != nil {
+		return fmt.Errorf("state db unreachable: %w", err)
+	}
+	return nil
+}
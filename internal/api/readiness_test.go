@@ -0,0 +1,80 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/health"
+)
+
+func TestReadyz(t *testing.T) {
+	srv := setupTestServer(t)
+	srv.cfg.General.LockFile = "/tmp/cortex.lock"
+	ticks := &health.Heartbeat{}
+	workerDone := make(chan struct{})
+
+	failed := func() map[string]string {
+		out := map[string]string{}
+		for _, c := range srv.readinessChecks(t.Context(), time.Now()) {
+			if !c.OK {
+				out[c.Name] = c.Detail
+			}
+		}
+		return out
+	}
+
+	srv.SetReadiness(Readiness{Ticks: ticks, WorkerDone: workerDone})
+	if got := failed(); len(got) != 2 || got["lock"] == "" || got["ticks"] != "no tick yet" {
+		t.Fatalf("before start: failed = %v", got)
+	}
+
+	srv.SetReadiness(Readiness{LockHeld: true, Ticks: ticks, WorkerDone: workerDone})
+	ticks.Beat()
+	w := httptest.NewRecorder()
+	srv.handleReadyz(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var resp struct {
+		Ready  bool             `json:"ready"`
+		Checks []ReadinessCheck `json:"checks"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusOK || !resp.Ready || len(resp.Checks) != 5 {
+		t.Fatalf("ready: code = %d, resp = %+v", w.Code, resp)
+	}
+
+	// Ticks older than three intervals mean the loop has stalled.
+	stale := srv.readinessChecks(t.Context(), time.Now().Add(4*srv.cfg.General.TickInterval.Duration))
+	if stale[3].Name != "ticks" || stale[3].OK {
+		t.Fatalf("stalled ticks passed: %+v", stale[3])
+	}
+
+	close(workerDone)
+	if got := failed(); got["worker"] != "temporal worker stopped" {
+		t.Fatalf("stopped worker: failed = %v", got)
+	}
+	w = httptest.NewRecorder()
+	srv.handleReadyz(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("stopped worker: code = %d, want 503", w.Code)
+	}
+}
+
+func TestHealthz(t *testing.T) {
+	srv := setupTestServer(t)
+	w := httptest.NewRecorder()
+	srv.handleHealthz(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("code = %d, want 200", w.Code)
+	}
+
+	srv.store.Close()
+	w = httptest.NewRecorder()
+	srv.handleHealthz(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("closed db: code = %d, want 503", w.Code)
+	}
+}
@@ -0,0 +1,188 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.temporal.io/sdk/client"
+
+	"github.com/antigravity-dev/cortex/internal/beads"
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/journal"
+	"github.com/antigravity-dev/cortex/internal/store"
+	"github.com/antigravity-dev/cortex/internal/temporal"
+)
+
+// RetryOverrides are the optional changes a manual retry applies to the
+// failed dispatch's settings.
+type RetryOverrides struct {
+	Tier     string `json:"tier,omitempty"`     // picks the tier's first agent
+	Provider string `json:"provider,omitempty"` // takes precedence over tier for the agent
+	Note     string `json:"note,omitempty"`     // appended to the prompt as an operator note
+}
+
+// POST /api/v1/dispatches/{id}/retry
+// Relaunches a dispatch now as a new agent workflow, with optional overrides,
+// instead of waiting for the retry policy.
+func (s *Server) handleDispatchRetry(w http.ResponseWriter, r *http.Request) {
+	idStr, ok := strings.CutSuffix(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/dispatches/"), "/"), "/retry")
+	if !ok {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		writeError(w, http.StatusBadRequest, "dispatch id must be a positive integer")
+		return
+	}
+
+	var overrides RetryOverrides
+	if err := json.NewDecoder(r.Body).Decode(&overrides); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid json request body")
+		return
+	}
+
+	d, err := s.store.GetDispatchByID(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "dispatch not found")
+		return
+	}
+	if d.Status == "running" {
+		writeError(w, http.StatusConflict, "dispatch is still running")
+		return
+	}
+	proj, ok := s.cfg.Projects[d.Project]
+	if !ok {
+		writeError(w, http.StatusConflict, "project "+d.Project+" is no longer configured")
+		return
+	}
+
+	prompt := d.Prompt
+	if prompt == "" {
+		// Temporal dispatches keep their prompt in workflow history; rebuild it from the bead.
+		bead, err := beads.ShowBeadCtx(r.Context(), proj.BeadsDir, d.BeadID)
+		if err != nil {
+			s.logger.Warn("failed to read bead for retry", "dispatch", id, "bead", d.BeadID, "error", err)
+			writeError(w, http.StatusConflict, "dispatch has no recorded prompt and its bead could not be read")
+			return
+		}
+		prompt = strings.TrimSpace(bead.Title + "\n\n" + bead.Description)
+	}
+
+	req, err := retryTaskRequest(s.cfg, d, proj, prompt, overrides)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.routeDispatch(r.Context(), &req) // the agent is already set; keeps the rule's reviewer and review requirement
+	decision := journal.Entry{Source: "retry", Project: req.Project, BeadID: req.BeadID, Agent: req.Agent, Provider: req.Provider}
+	if reason := s.dispatchHeld(r.Context(), req); reason != "" {
+		decision.Decision, decision.Reason = journal.Deny, reason
+		s.journalDecision(decision)
+		writeError(w, http.StatusConflict, "dispatch window closed for "+req.Project+": "+reason)
+		return
+	}
+
+	c, err := temporal.Dial(s.cfg.Temporal)
+	if err != nil {
+		s.logger.Error("failed to connect to temporal", "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to connect to temporal")
+		return
+	}
+	defer c.Close()
+
+	if reason := s.claimBead(r.Context(), &req); reason != "" {
+		decision.Decision, decision.Reason = journal.Deny, reason
+		s.journalDecision(decision)
+		writeError(w, http.StatusConflict, "bead "+req.BeadID+" not dispatched: "+reason)
+		return
+	}
+
+	wo := client.StartWorkflowOptions{
+		ID:        fmt.Sprintf("%s-retry-%d", d.BeadID, time.Now().Unix()),
+		TaskQueue: s.cfg.Temporal.TaskQueue,
+	}
+	we, err := c.ExecuteWorkflow(r.Context(), wo, temporal.CortexAgentWorkflow, req)
+	if err != nil {
+		s.releaseClaim(r.Context(), req)
+		s.logger.Error("failed to start retry workflow", "dispatch", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to start workflow")
+		return
+	}
+
+	decision.Decision, decision.Workflow = journal.Admit, we.GetID()
+	decision.Reason = fmt.Sprintf("manual retry of dispatch %d", id)
+	s.journalDecision(decision)
+
+	// The retry policy must not relaunch it a second time.
+	if d.Status == "pending_retry" {
+		if err := s.store.MarkDispatchRetried(id); err != nil {
+			s.logger.Warn("failed to mark dispatch retried", "dispatch", id, "error", err)
+		}
+	}
+	details := fmt.Sprintf("dispatch %d of %s relaunched as %s (agent %s, provider %s)", id, d.BeadID, we.GetID(), req.Agent, req.Provider)
+	if err := s.store.RecordHealthEvent("manual_retry", details); err != nil {
+		s.logger.Warn("failed to record manual retry", "dispatch", id, "error", err)
+	}
+	s.logger.Info("dispatch retried", "dispatch", id, "workflow_id", we.GetID(), "run_id", we.GetRunID())
+
+	writeJSON(w, map[string]any{
+		"dispatch_id": id,
+		"workflow_id": we.GetID(),
+		"run_id":      we.GetRunID(),
+		"agent":       req.Agent,
+		"provider":    req.Provider,
+		"status":      "started",
+	})
+}
+
+// retryTaskRequest rebuilds the task request of dispatch d with the
+// overrides applied.
+func retryTaskRequest(cfg *config.Config, d *store.Dispatch, proj config.Project, prompt string, o RetryOverrides) (temporal.TaskRequest, error) {
+	if strings.TrimSpace(prompt) == "" {
+		return temporal.TaskRequest{}, fmt.Errorf("dispatch has no prompt to retry")
+	}
+	if note := strings.TrimSpace(o.Note); note != "" {
+		prompt += "\n\nOPERATOR NOTE (manual retry of dispatch " + strconv.FormatInt(d.ID, 10) + "):\n" + note
+	}
+
+	req := temporal.TaskRequest{
+		BeadID:    d.BeadID,
+		Project:   d.Project,
+		Prompt:    prompt,
+		Agent:     d.AgentID,
+		Provider:  d.Provider,
+		WorkDir:   proj.Workspace,
+		DoDChecks: append([]string(nil), proj.DoD.Checks...),
+	}
+	if tier := strings.ToLower(strings.TrimSpace(o.Tier)); tier != "" {
+		switch tier {
+		case "fast", "balanced", "premium":
+		default:
+			return temporal.TaskRequest{}, fmt.Errorf("tier must be fast, balanced or premium")
+		}
+		req.Agent = temporal.ResolveTierAgent(cfg.Tiers, tier)
+		req.Provider = ""
+	}
+	if name := strings.TrimSpace(o.Provider); name != "" {
+		if _, ok := cfg.Providers[name]; !ok {
+			return temporal.TaskRequest{}, fmt.Errorf("unknown provider %q", name)
+		}
+		req.Provider = name
+		req.Agent = providerAgent(cfg, name)
+	}
+	if req.Agent == "" {
+		req.Agent = "claude"
+	}
+	return req, nil
+}
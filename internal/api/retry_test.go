@@ -0,0 +1,89 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestRetryTaskRequest(t *testing.T) {
+	cfg := &config.Config{
+		Tiers:     config.Tiers{Fast: []string{"codex"}, Premium: []string{"claude"}},
+		Providers: map[string]config.Provider{"claude-max": {CLI: "claude"}, "codex": {}},
+	}
+	proj := config.Project{Workspace: "/ws", DoD: config.DoDConfig{Checks: []string{"go test ./..."}}}
+	d := &store.Dispatch{ID: 7, BeadID: "cx-1", Project: "p", AgentID: "codex", Provider: "codex"}
+
+	req, err := retryTaskRequest(cfg, d, proj, "fix it", RetryOverrides{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Agent != "codex" || req.Provider != "codex" || req.Prompt != "fix it" || req.WorkDir != "/ws" || len(req.DoDChecks) != 1 {
+		t.Fatalf("unexpected request without overrides %+v", req)
+	}
+
+	req, err = retryTaskRequest(cfg, d, proj, "fix it", RetryOverrides{Tier: "premium", Note: "use the new API"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Agent != "claude" || req.Provider != "" {
+		t.Fatalf("expected the premium tier agent, got %+v", req)
+	}
+	if !strings.Contains(req.Prompt, "OPERATOR NOTE (manual retry of dispatch 7):\nuse the new API") {
+		t.Fatalf("expected the operator note in the prompt, got %q", req.Prompt)
+	}
+
+	req, err = retryTaskRequest(cfg, d, proj, "fix it", RetryOverrides{Tier: "fast", Provider: "claude-max"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Agent != "claude" || req.Provider != "claude-max" {
+		t.Fatalf("expected the provider override to win, got %+v", req)
+	}
+
+	for _, o := range []RetryOverrides{{Tier: "turbo"}, {Provider: "nope"}} {
+		if _, err := retryTaskRequest(cfg, d, proj, "fix it", o); err == nil {
+			t.Fatalf("expected %+v to be rejected", o)
+		}
+	}
+}
+
+func TestHandleDispatchRetryRejects(t *testing.T) {
+	srv := setupTestServer(t)
+	running, err := srv.store.RecordDispatch("cx-1", "test-proj", "codex", "codex", "fast", 0, "", "fix it", "", "", "temporal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	failed, err := srv.store.RecordDispatch("cx-2", "test-proj", "codex", "codex", "fast", 0, "", "fix it", "", "", "temporal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.store.UpdateDispatchStatus(failed, "failed", 1, 3); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		method, path, body string
+		want               int
+	}{
+		{http.MethodGet, "/api/v1/dispatches/1/retry", "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/api/v1/dispatches/1", "", http.StatusNotFound},
+		{http.MethodPost, "/api/v1/dispatches/abc/retry", "", http.StatusBadRequest},
+		{http.MethodPost, "/api/v1/dispatches/999/retry", "", http.StatusNotFound},
+		{http.MethodPost, "/api/v1/dispatches/" + strconv.FormatInt(running, 10) + "/retry", "", http.StatusConflict},
+		{http.MethodPost, "/api/v1/dispatches/" + strconv.FormatInt(failed, 10) + "/retry", `{"tier":"turbo"}`, http.StatusBadRequest},
+		{http.MethodPost, "/api/v1/dispatches/" + strconv.FormatInt(failed, 10) + "/retry", `{`, http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		srv.handleDispatchRetry(w, httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body)))
+		if w.Code != tc.want {
+			t.Errorf("%s %s %s: expected %d, got %d: %s", tc.method, tc.path, tc.body, tc.want, w.Code, w.Body.String())
+		}
+	}
+}
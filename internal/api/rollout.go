@@ -0,0 +1,32 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/rollout"
+)
+
+// GET /api/v1/rollout/completion
+// Evaluates the health.rollout criteria now, reading live bead status.
+func (s *Server) handleRolloutCompletion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	ro := s.cfg.Health.Rollout
+	if !ro.Enabled {
+		writeError(w, http.StatusNotFound, "rollout completion criteria not configured")
+		return
+	}
+
+	beadsDir := s.cfg.Projects[ro.Project].BeadsDir
+	result, err := rollout.Evaluate(r.Context(), s.store, ro, s.cfg.Reporter.BurnIn.ReportsDir, rollout.BeadsLookup(beadsDir), time.Now())
+	if err != nil {
+		s.logger.Error("failed to evaluate rollout completion", "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to evaluate rollout completion")
+		return
+	}
+	writeJSON(w, result)
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/rollout"
+)
+
+func TestHandleRolloutCompletion(t *testing.T) {
+	srv := setupTestServer(t)
+	get := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		srv.handleRolloutCompletion(w, httptest.NewRequest(http.MethodGet, "/api/v1/rollout/completion", nil))
+		return w
+	}
+
+	if w := get(); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 when not configured, got %d", w.Code)
+	}
+
+	fakeBin := t.TempDir()
+	script := "#!/bin/sh\necho \"{\\\"id\\\":\\\"$3\\\",\\\"status\\\":\\\"closed\\\"}\"\n"
+	if err := os.WriteFile(filepath.Join(fakeBin, "bd"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	srv.cfg.Health.Rollout = config.HealthRollout{
+		Enabled:       true,
+		Project:       "test-proj",
+		CriticalBeads: []string{"cx-1"},
+	}
+	w := get()
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var result rollout.Result
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	if !result.Complete || len(result.Beads) != 1 || result.Beads[0].Status != "closed" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	w = httptest.NewRecorder()
+	srv.handleRolloutCompletion(w, httptest.NewRequest(http.MethodPost, "/api/v1/rollout/completion", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", w.Code)
+	}
+}
@@ -0,0 +1,174 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/beads"
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/learner"
+	"github.com/antigravity-dev/cortex/internal/scheduler"
+	"github.com/antigravity-dev/cortex/internal/store"
+	"github.com/antigravity-dev/cortex/internal/temporal"
+)
+
+// routeDispatch applies the dispatch.routing rule matching the bead's labels
+// to req, then the custom project role working the bead's stage, then, with
+// dispatch.complexity on, picks the starting tier of a request that still
+// names no agent or provider. Each stands in for the defaults: an agent,
+// provider or reviewer the request already names is kept. A tier chosen here
+// is raised once after a stage SLA breach with escalate_on_breach. Beads are only
+// looked up when rules or tier detection are configured; a bead that cannot
+// be read gets the defaults.
+func (s *Server) routeDispatch(ctx context.Context, req *temporal.TaskRequest) {
+	proj, ok := s.cfg.Projects[req.Project]
+	if !ok {
+		return
+	}
+	detect := s.cfg.Dispatch.Complexity.Enabled
+	var bead *beads.BeadDetail
+	if (len(s.cfg.Dispatch.Routing.Rules) > 0 || detect) && strings.TrimSpace(proj.BeadsDir) != "" {
+		var err error
+		bead, err = beads.ShowBeadCtx(ctx, config.ExpandHome(strings.TrimSpace(proj.BeadsDir)), req.BeadID)
+		if err != nil {
+			s.logger.Warn("failed to read bead for routing", "project", req.Project, "bead", req.BeadID, "error", err)
+		}
+	}
+	if bead != nil {
+		if rule, ok := s.cfg.Dispatch.Routing.Rule(bead.Labels); ok {
+			s.applyRoutingRule(req, rule)
+			s.logger.Info("bead routed by labels", "project", req.Project, "bead", req.BeadID, "labels", rule.Labels,
+				"agent", req.Agent, "provider", req.Provider, "reviewer", req.Reviewer, "require_review", req.RequireReview)
+		}
+	}
+	s.applyStageRole(req, proj)
+	if detect && bead != nil {
+		s.detectTier(req, store.BeadFeatures{
+			Project:         req.Project,
+			BeadID:          bead.ID,
+			Type:            bead.Type,
+			EstimateMinutes: bead.EstimateMinutes,
+			DescriptionLen:  len(bead.Description),
+			Labels:          bead.Labels,
+		})
+	}
+}
+
+// applyStageRole hands req to the custom role of proj that works the bead's
+// current workflow stage, running it on the role's preferred provider or
+// tier unless the request names an agent or provider.
+func (s *Server) applyStageRole(req *temporal.TaskRequest, proj config.Project) {
+	if len(proj.Roles) == 0 {
+		return
+	}
+	stage, err := s.store.GetBeadStage(req.Project, req.BeadID)
+	if err != nil {
+		return // not in a workflow
+	}
+	wfRole := ""
+	for _, st := range s.cfg.Workflows[stage.Workflow].Stages {
+		if st.Name == stage.CurrentStage {
+			wfRole = st.Role
+		}
+	}
+	name := proj.StageRole(stage.CurrentStage, wfRole)
+	role, ok := proj.Roles[name]
+	if !ok {
+		return
+	}
+	req.Role = name
+	if req.Agent == "" && req.Provider == "" {
+		switch {
+		case role.Provider != "":
+			req.Provider, req.Agent = role.Provider, providerAgent(s.cfg, role.Provider)
+		case role.Tier != "":
+			req.Agent = s.tierAgent(req, role.Tier)
+		}
+	}
+	s.logger.Info("bead handed to project role", "project", req.Project, "bead", req.BeadID, "stage", stage.CurrentStage,
+		"role", name, "agent", req.Agent, "provider", req.Provider)
+}
+
+// detectTier records the bead's features for the complexity model and, when
+// req names no agent or provider, runs it at the tier the model or the
+// heuristics pick.
+func (s *Server) detectTier(req *temporal.TaskRequest, f store.BeadFeatures) {
+	if f.BeadID == "" {
+		f.BeadID = req.BeadID
+	}
+	if err := s.store.RecordBeadFeatures(f); err != nil {
+		s.logger.Warn("failed to record bead features", "project", req.Project, "bead", req.BeadID, "error", err)
+	}
+	if req.Agent != "" || req.Provider != "" {
+		return
+	}
+	cx := s.cfg.Dispatch.Complexity
+	tier, learned := learner.StartingTier(s.complexityModel(), cx.MinSamples, f, s.cfg.Dispatch.CostControl.ComplexityEscalationMinutes)
+	req.Agent = s.tierAgent(req, tier)
+	s.logger.Info("starting tier detected", "project", req.Project, "bead", req.BeadID, "tier", tier, "learned", learned, "agent", req.Agent)
+}
+
+// complexityCache holds the complexity model between retrains.
+type complexityCache struct {
+	mu    sync.Mutex
+	model *learner.ComplexityModel
+}
+
+// complexityModel returns the complexity model, retraining it on the
+// configured window once it is older than dispatch.complexity.retrain. A
+// failed retrain keeps the previous model, which may be nil.
+func (s *Server) complexityModel() *learner.ComplexityModel {
+	cx := s.cfg.Dispatch.Complexity
+	s.complexity.mu.Lock()
+	defer s.complexity.mu.Unlock()
+	if m := s.complexity.model; m != nil && time.Since(m.TrainedAt) < cx.Retrain.Duration {
+		return m
+	}
+	m, err := learner.QueryComplexityModel(s.store, time.Now().Add(-cx.Window.Duration))
+	if err != nil {
+		s.logger.Warn("failed to train complexity model", "error", err)
+		return s.complexity.model
+	}
+	s.complexity.model = m
+	return m
+}
+
+func (s *Server) applyRoutingRule(req *temporal.TaskRequest, rule config.RoutingRule) {
+	if req.Agent == "" && req.Provider == "" {
+		switch {
+		case rule.Provider != "":
+			req.Provider, req.Agent = rule.Provider, providerAgent(s.cfg, rule.Provider)
+		case rule.Tier != "":
+			req.Agent = s.tierAgent(req, rule.Tier)
+		}
+	}
+	if req.Reviewer == "" {
+		req.Reviewer = rule.Reviewer
+	}
+	req.RequireReview = req.RequireReview || rule.RequireReview
+}
+
+// tierAgent returns the agent to run req at tier, one tier higher when a
+// stage SLA breach escalated the bead. The escalation is used up here.
+func (s *Server) tierAgent(req *temporal.TaskRequest, tier string) string {
+	escalated, err := scheduler.DispatchTier(s.store, req.Project, req.BeadID, tier)
+	switch {
+	case err != nil:
+		s.logger.Warn("failed to check stage SLA escalation", "project", req.Project, "bead", req.BeadID, "error", err)
+	case escalated != tier:
+		s.logger.Info("dispatch tier escalated after stage SLA breach", "project", req.Project, "bead", req.BeadID, "from", tier, "to", escalated)
+		tier = escalated
+	}
+	return temporal.ResolveTierAgent(s.cfg.Tiers, tier)
+}
+
+// providerAgent returns the agent CLI a provider runs: its cli, or its name
+// when none is set.
+func providerAgent(cfg *config.Config, name string) string {
+	if cli := cfg.Providers[name].CLI; cli != "" {
+		return cli
+	}
+	return name
+}
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+	"github.com/antigravity-dev/cortex/internal/temporal"
+)
+
+func TestRouteByLabels(t *testing.T) {
+	srv := setupTestServer(t)
+	srv.cfg.Tiers = config.Tiers{Premium: []string{"claude"}}
+	srv.cfg.Providers = map[string]config.Provider{"claude-opus": {CLI: "claude", Tier: "premium"}}
+	srv.cfg.Dispatch.Routing.Rules = []config.RoutingRule{
+		{Labels: []string{"security"}, Provider: "claude-opus", Reviewer: "codex", RequireReview: true},
+		{Labels: []string{"docs", "security"}, Tier: "premium"},
+	}
+
+	fakeBin := t.TempDir()
+	script := "#!/bin/sh\necho '{\"id\":\"cx-1\",\"labels\":[\"Security\",\"backend\"]}'\n"
+	if err := os.WriteFile(fakeBin+"/bd", []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	req := temporal.TaskRequest{BeadID: "cx-1", Project: "test-proj"}
+	srv.routeDispatch(context.Background(), &req)
+	if req.Provider != "claude-opus" || req.Agent != "claude" || req.Reviewer != "codex" || !req.RequireReview {
+		t.Fatalf("expected the security rule to route the bead, got %+v", req)
+	}
+
+	// Fields the caller set are kept; the rule only replaces defaults.
+	req = temporal.TaskRequest{BeadID: "cx-1", Project: "test-proj", Agent: "codex", Reviewer: "claude"}
+	srv.routeDispatch(context.Background(), &req)
+	if req.Agent != "codex" || req.Provider != "" || req.Reviewer != "claude" || !req.RequireReview {
+		t.Fatalf("expected explicit agent and reviewer to be kept, got %+v", req)
+	}
+
+	// The first matching rule wins.
+	srv.cfg.Dispatch.Routing.Rules = srv.cfg.Dispatch.Routing.Rules[1:]
+	req = temporal.TaskRequest{BeadID: "cx-1", Project: "test-proj"}
+	srv.routeDispatch(context.Background(), &req)
+	if req.Agent != "claude" || req.Provider != "" || req.RequireReview {
+		t.Fatalf("expected the tier rule to pick the premium agent, got %+v", req)
+	}
+
+	req = temporal.TaskRequest{BeadID: "cx-1", Project: "unknown"}
+	srv.routeDispatch(context.Background(), &req)
+	if req.Agent != "" {
+		t.Fatalf("expected an unknown project to keep the defaults, got %+v", req)
+	}
+}
+
+func TestRouteDispatchDetectsTier(t *testing.T) {
+	srv := setupTestServer(t)
+	srv.cfg.Tiers = config.Tiers{Fast: []string{"codex-spark"}, Balanced: []string{"codex"}, Premium: []string{"claude"}}
+	srv.cfg.Dispatch.CostControl.ComplexityEscalationMinutes = 120
+	srv.cfg.Dispatch.Complexity = config.DispatchComplexity{Enabled: true, MinSamples: 1, Window: config.Duration{Duration: time.Hour}}
+
+	fakeBin := t.TempDir()
+	script := "#!/bin/sh\necho '{\"id\":\"cx-1\",\"issue_type\":\"task\",\"estimated_minutes\":240,\"labels\":[\"backend\"]}'\n"
+	if err := os.WriteFile(fakeBin+"/bd", []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	// No history yet: the heuristics start a large estimate at premium.
+	req := temporal.TaskRequest{BeadID: "cx-1", Project: "test-proj"}
+	srv.routeDispatch(context.Background(), &req)
+	if req.Agent != "claude" {
+		t.Fatalf("expected the heuristics to pick premium, got %+v", req)
+	}
+
+	// Once the bead has finished cleanly, the model learns it is easy.
+	id, err := srv.store.RecordDispatch("cx-1", "test-proj", "claude", "", "temporal", 0, "", "p", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.store.UpdateDispatchStatus(id, "completed", 0, 60); err != nil {
+		t.Fatal(err)
+	}
+	req = temporal.TaskRequest{BeadID: "cx-1", Project: "test-proj"}
+	srv.routeDispatch(context.Background(), &req)
+	if req.Agent != "codex-spark" {
+		t.Fatalf("expected the learned model to pick fast, got %+v", req)
+	}
+
+	// An explicit agent is kept.
+	req = temporal.TaskRequest{BeadID: "cx-1", Project: "test-proj", Agent: "codex"}
+	srv.routeDispatch(context.Background(), &req)
+	if req.Agent != "codex" {
+		t.Fatalf("expected the explicit agent to be kept, got %+v", req)
+	}
+}
+
+func TestRouteDispatchToStageRole(t *testing.T) {
+	srv := setupTestServer(t)
+	srv.cfg.Tiers = config.Tiers{Premium: []string{"claude"}}
+	srv.cfg.Workflows = map[string]config.WorkflowConfig{
+		"dev": {Stages: []config.StageConfig{
+			{Name: "implement", Role: "coder"},
+			{Name: "audit", Role: "security-auditor"},
+			{Name: "docs", Role: "coder"},
+		}},
+	}
+	proj := srv.cfg.Projects["test-proj"]
+	proj.Roles = map[string]config.RoleConfig{
+		"security-auditor": {Tier: "premium"},
+		"doc-writer":       {Provider: "cerebras", Stages: []string{"docs"}},
+	}
+	srv.cfg.Projects["test-proj"] = proj
+	srv.cfg.Providers = map[string]config.Provider{"cerebras": {CLI: "openclaw"}}
+
+	for stage, want := range map[string]temporal.TaskRequest{
+		"implement": {},
+		"audit":     {Role: "security-auditor", Agent: "claude"},
+		"docs":      {Role: "doc-writer", Agent: "openclaw", Provider: "cerebras"},
+	} {
+		if err := srv.store.UpsertBeadStage(&store.BeadStage{Project: "test-proj", BeadID: "cx-1", Workflow: "dev", CurrentStage: stage, TotalStages: 3}); err != nil {
+			t.Fatal(err)
+		}
+		req := temporal.TaskRequest{BeadID: "cx-1", Project: "test-proj"}
+		srv.routeDispatch(context.Background(), &req)
+		if req.Role != want.Role || req.Agent != want.Agent || req.Provider != want.Provider {
+			t.Fatalf("stage %s: got role=%q agent=%q provider=%q, want %+v", stage, req.Role, req.Agent, req.Provider, want)
+		}
+	}
+
+	// The role's backend preference does not replace an explicit agent.
+	if err := srv.store.UpsertBeadStage(&store.BeadStage{Project: "test-proj", BeadID: "cx-1", Workflow: "dev", CurrentStage: "docs", TotalStages: 3}); err != nil {
+		t.Fatal(err)
+	}
+	req := temporal.TaskRequest{BeadID: "cx-1", Project: "test-proj", Agent: "codex"}
+	srv.routeDispatch(context.Background(), &req)
+	if req.Role != "doc-writer" || req.Agent != "codex" || req.Provider != "" {
+		t.Fatalf("expected the explicit agent to be kept, got %+v", req)
+	}
+}
+
+func TestRouteDispatchEscalatesTierAfterSLABreach(t *testing.T) {
+	srv := setupTestServer(t)
+	srv.cfg.Tiers = config.Tiers{Fast: []string{"codex-spark"}, Balanced: []string{"codex"}, Premium: []string{"claude"}}
+	srv.cfg.Dispatch.Routing.Rules = []config.RoutingRule{{Labels: []string{"docs"}, Tier: "fast"}}
+
+	fakeBin := t.TempDir()
+	script := "#!/bin/sh\necho '{\"id\":\"cx-1\",\"issue_type\":\"task\",\"labels\":[\"docs\"]}'\n"
+	if err := os.WriteFile(fakeBin+"/bd", []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	if _, err := srv.store.RecordStageSLABreach(store.StageSLABreach{Project: "test-proj", BeadID: "cx-1", Stage: "implement", EnteredAt: time.Now(), Escalate: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	req := temporal.TaskRequest{BeadID: "cx-1", Project: "test-proj"}
+	srv.routeDispatch(context.Background(), &req)
+	if req.Agent != "codex" {
+		t.Fatalf("expected the breach to escalate fast to balanced, got %+v", req)
+	}
+
+	// The escalation is used up by that dispatch.
+	req = temporal.TaskRequest{BeadID: "cx-1", Project: "test-proj"}
+	srv.routeDispatch(context.Background(), &req)
+	if req.Agent != "codex-spark" {
+		t.Fatalf("expected the next dispatch back on fast, got %+v", req)
+	}
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+// maxSearchResults caps the limit a search request may ask for.
+const maxSearchResults = 100
+
+// GET /api/v1/search?q=...&project=...&limit=...
+// Finds the dispatches whose captured output matches q, best match first.
+// A q wrapped in double quotes matches as one phrase; otherwise every term
+// must appear.
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if strings.Trim(q, `"`) == "" {
+		writeError(w, http.StatusBadRequest, "q is required")
+		return
+	}
+	project := r.URL.Query().Get("project")
+	if project != "" {
+		if _, ok := s.cfg.Projects[project]; !ok {
+			writeError(w, http.StatusNotFound, "project not found")
+			return
+		}
+	}
+	limit := 20
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = min(n, maxSearchResults)
+	}
+
+	matches, err := s.store.SearchOutputs(q, project, limit)
+	if err != nil {
+		s.logger.Error("failed to search dispatch outputs", "q", q, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to search dispatch outputs")
+		return
+	}
+	if matches == nil {
+		matches = []store.OutputMatch{}
+	}
+	writeJSON(w, map[string]any{
+		"query":   q,
+		"count":   len(matches),
+		"matches": matches,
+	})
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestHandleSearch(t *testing.T) {
+	srv := setupTestServer(t)
+	id, err := srv.store.RecordDispatch("cx-1", "test-proj", "codex", "codex", "fast", 0, "", "fix it", "", "", "temporal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.store.CaptureOutput(id, "panic: nil map write in scheduler.go"); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	srv.handleSearch(w, httptest.NewRequest(http.MethodGet, "/api/v1/search?q=scheduler.go&project=test-proj", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Count   int                 `json:"count"`
+		Matches []store.OutputMatch `json:"matches"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Count != 1 || resp.Matches[0].DispatchID != id {
+		t.Fatalf("unexpected search response %+v", resp)
+	}
+
+	cases := []struct {
+		method, query string
+		want          int
+	}{
+		{http.MethodPost, "?q=panic", http.StatusMethodNotAllowed},
+		{http.MethodGet, "", http.StatusBadRequest},
+		{http.MethodGet, `?q=""`, http.StatusBadRequest},
+		{http.MethodGet, "?q=panic&limit=0", http.StatusBadRequest},
+		{http.MethodGet, "?q=panic&project=nope", http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		srv.handleSearch(w, httptest.NewRequest(tc.method, "/api/v1/search"+tc.query, nil))
+		if w.Code != tc.want {
+			t.Errorf("%s %s: expected %d, got %d", tc.method, tc.query, tc.want, w.Code)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const shardStatusTimeout = 3 * time.Second
+
+// ShardStatus is one shard's plain /status. A shard that could not be reached
+// reports why in Error.
+type ShardStatus struct {
+	Shard        string  `json:"shard"`
+	UptimeS      float64 `json:"uptime_s"`
+	RunningCount int     `json:"running_count"`
+	Error        string  `json:"error,omitempty"`
+}
+
+// shardStatuses returns this shard's status first, then each peer's in name
+// order. Peers are asked with ?local=1 so they do not aggregate in turn.
+func (s *Server) shardStatuses(ctx context.Context, local ShardStatus) []ShardStatus {
+	names := make([]string, 0, len(s.cfg.Sharding.Peers))
+	for name := range s.cfg.Sharding.Peers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := make([]ShardStatus, len(names)+1)
+	out[0] = local
+	ctx, cancel := context.WithTimeout(ctx, shardStatusTimeout)
+	defer cancel()
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			status, err := fetchShardStatus(ctx, s.cfg.Sharding.Peers[name])
+			if err != nil {
+				s.logger.Warn("status: shard unreachable", "shard", name, "error", err)
+				status.Error = err.Error()
+			}
+			status.Shard = name
+			out[i+1] = status
+		}(i, name)
+	}
+	wg.Wait()
+	return out
+}
+
+func fetchShardStatus(ctx context.Context, baseURL string) (ShardStatus, error) {
+	var status ShardStatus
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(baseURL, "/")+"/status?local=1", nil)
+	if err != nil {
+		return status, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return status, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return status, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return status, fmt.Errorf("decoding status: %w", err)
+	}
+	return status, nil
+}
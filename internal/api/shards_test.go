@@ -0,0 +1,62 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/temporal"
+)
+
+func TestStatusAggregatesShards(t *testing.T) {
+	peer := setupTestServer(t)
+	peer.cfg.Sharding = config.Sharding{Enabled: true, Shard: "west"}
+	if _, err := peer.store.RecordDispatch("cx-9", "test-proj", "agent", "provider", "fast", 1, "", "", "", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	peerHTTP := httptest.NewServer(http.HandlerFunc(peer.handleStatus))
+	defer peerHTTP.Close()
+
+	srv := setupTestServer(t)
+	srv.cfg.Sharding = config.Sharding{
+		Enabled: true,
+		Shard:   "east",
+		Peers:   map[string]string{"west": peerHTTP.URL, "north": "http://127.0.0.1:1"},
+	}
+
+	w := httptest.NewRecorder()
+	srv.handleStatus(w, httptest.NewRequest(http.MethodGet, "/status", nil))
+	var resp struct {
+		Shard             string        `json:"shard"`
+		Shards            []ShardStatus `json:"shards"`
+		TotalRunningCount int           `json:"total_running_count"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Shard != "east" || len(resp.Shards) != 3 {
+		t.Fatalf("status = %+v", resp)
+	}
+	if got := resp.Shards; got[0].Shard != "east" || got[1].Shard != "north" || got[1].Error == "" || got[2].Shard != "west" || got[2].RunningCount != 1 {
+		t.Fatalf("shards = %+v", got)
+	}
+	if resp.TotalRunningCount != 1 {
+		t.Fatalf("total_running_count = %d, want 1", resp.TotalRunningCount)
+	}
+}
+
+func TestDispatchHeldForOtherShard(t *testing.T) {
+	srv := setupTestServer(t)
+	srv.cfg.Sharding = config.Sharding{Enabled: true, Shard: "east", Peers: map[string]string{"west": "http://west"}}
+	proj := srv.cfg.Projects["test-proj"]
+	proj.Shard = "west"
+	srv.cfg.Projects["test-proj"] = proj
+	req := temporal.TaskRequest{BeadID: "cx-1", Project: "test-proj"}
+
+	if reason := srv.dispatchHeld(context.Background(), req); reason != "project runs on shard west; dispatch through it" {
+		t.Fatalf("reason = %q", reason)
+	}
+}
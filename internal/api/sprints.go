@@ -0,0 +1,59 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// GET /api/v1/sprints/timeline?sprint=N&project=name
+// Returns the per-bead timeline and daily burndown of a sprint, defaulting to
+// the current sprint.
+func (s *Server) handleSprintTimeline(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	sprint := 0
+	if v := r.URL.Query().Get("sprint"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, "sprint must be a positive integer")
+			return
+		}
+		sprint = n
+	}
+	project := r.URL.Query().Get("project")
+	if project != "" {
+		if _, ok := s.cfg.Projects[project]; !ok {
+			writeError(w, http.StatusNotFound, "project not found")
+			return
+		}
+	}
+
+	if sprint == 0 {
+		n, err := s.store.GetCurrentSprintNumber()
+		if err != nil {
+			s.logger.Error("failed to get current sprint", "error", err)
+			writeError(w, http.StatusInternalServerError, "failed to get current sprint")
+			return
+		}
+		if n == 0 {
+			writeError(w, http.StatusNotFound, "no current sprint")
+			return
+		}
+		sprint = n
+	}
+
+	timeline, err := s.store.GetSprintTimeline(sprint, project)
+	if err != nil {
+		s.logger.Error("failed to build sprint timeline", "sprint", sprint, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to build sprint timeline")
+		return
+	}
+	if timeline == nil {
+		writeError(w, http.StatusNotFound, "sprint not found")
+		return
+	}
+	writeJSON(w, timeline)
+}
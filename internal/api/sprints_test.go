@@ -0,0 +1,59 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestHandleSprintTimeline(t *testing.T) {
+	srv := setupTestServer(t)
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		srv.handleSprintTimeline(w, httptest.NewRequest(http.MethodGet, "/api/v1/sprints/timeline"+query, nil))
+		return w
+	}
+
+	if w := get(""); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without a current sprint, got %d", w.Code)
+	}
+	for _, q := range []string{"?sprint=abc", "?sprint=0"} {
+		if w := get(q); w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", q, w.Code)
+		}
+	}
+	if w := get("?project=nope"); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown project, got %d", w.Code)
+	}
+
+	start := time.Now().UTC().Add(-24 * time.Hour)
+	if err := srv.store.RecordSprintBoundary(3, start, start.Add(72*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := srv.store.RecordDispatch("cx-1", "test-proj", "agent", "claude", "balanced", 1, "", "p", "", "", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	w := get("?project=test-proj")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var tl store.SprintTimeline
+	if err := json.NewDecoder(w.Body).Decode(&tl); err != nil {
+		t.Fatal(err)
+	}
+	if tl.SprintNumber != 3 || len(tl.Beads) != 1 || tl.Beads[0].BeadID != "cx-1" || len(tl.Beads[0].Dispatches) != 1 {
+		t.Fatalf("unexpected timeline %+v", tl)
+	}
+	if len(tl.Burndown) == 0 || tl.Burndown[0].Remaining != 1 {
+		t.Fatalf("unexpected burndown %+v", tl.Burndown)
+	}
+
+	if w := get("?sprint=4"); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown sprint, got %d", w.Code)
+	}
+}
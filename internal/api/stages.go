@@ -0,0 +1,141 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/antigravity-dev/cortex/internal/beads"
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/scheduler"
+)
+
+// stageTransitionRequest is the body of a manual stage transition.
+type stageTransitionRequest struct {
+	Stage      string `json:"stage"`
+	ApprovedBy string `json:"approved_by"`
+	Reason     string `json:"reason"`
+}
+
+// POST /api/v1/beads/{project}/{id}/stage
+// Moves a bead to another stage of its workflow, subject to the stage's
+// transition guards. The move is recorded in the bead's stage history and as
+// a stage_transition health event, and the bead's stage:* label is updated.
+// With draft PRs enabled, entering the review stage marks the bead's PR ready.
+func (s *Server) handleBeadStage(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/api/v1/beads/")
+	parts := strings.Split(rest, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] != "stage" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	project, beadID := parts[0], parts[1]
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req stageTransitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	req.Stage = strings.TrimSpace(req.Stage)
+	if req.Stage == "" {
+		writeError(w, http.StatusBadRequest, "stage is required")
+		return
+	}
+
+	source := "manual"
+	if req.ApprovedBy != "" {
+		source += ", approved by " + req.ApprovedBy
+	}
+	if req.Reason != "" {
+		source += ": " + req.Reason
+	}
+	resp, err := s.moveBeadStage(r.Context(), project, beadID, req.Stage, req.ApprovedBy, source)
+	var moveErr *stageMoveError
+	if errors.As(err, &moveErr) {
+		writeError(w, moveErr.status, moveErr.msg)
+		return
+	}
+	writeJSON(w, resp)
+}
+
+// stageMoveError carries the HTTP status a failed stage move maps to.
+type stageMoveError struct {
+	status int
+	msg    string
+}
+
+func (e *stageMoveError) Error() string { return e.msg }
+
+// moveBeadStage moves a bead to stage to through scheduler.AdvanceStage,
+// records the move as a stage_transition health event noting source, syncs
+// the bead's stage:* label, and marks the bead's draft PR ready when it
+// enters review. Failures are returned as *stageMoveError.
+func (s *Server) moveBeadStage(ctx context.Context, project, beadID, to, approvedBy, source string) (map[string]any, error) {
+	proj, ok := s.cfg.Projects[project]
+	if !ok {
+		return nil, &stageMoveError{http.StatusNotFound, "project not found"}
+	}
+	current, err := s.store.GetBeadStage(project, beadID)
+	if err != nil {
+		return nil, &stageMoveError{http.StatusNotFound, "bead has no workflow stage"}
+	}
+	bead, err := beads.ShowBeadCtx(ctx, proj.BeadsDir, beadID)
+	if err != nil {
+		s.logger.Error("failed to read bead for stage transition", "project", project, "bead", beadID, "error", err)
+		return nil, &stageMoveError{http.StatusBadGateway, "failed to read bead"}
+	}
+
+	err = scheduler.AdvanceStage(s.store, s.cfg.Workflows, scheduler.StageTransition{
+		Project:    project,
+		BeadID:     beadID,
+		To:         to,
+		Labels:     bead.Labels,
+		ApprovedBy: approvedBy,
+	})
+	var denied *scheduler.TransitionDeniedError
+	if errors.As(err, &denied) {
+		return nil, &stageMoveError{http.StatusConflict, denied.Error()}
+	}
+	if err != nil {
+		s.logger.Error("stage transition failed", "project", project, "bead", beadID, "to", to, "error", err)
+		return nil, &stageMoveError{http.StatusInternalServerError, "stage transition failed"}
+	}
+
+	details := fmt.Sprintf("%s/%s: %s -> %s (%s)", project, beadID, current.CurrentStage, to, source)
+	if err := s.store.RecordHealthEventWithDispatch("stage_transition", details, 0, beadID); err != nil {
+		s.logger.Warn("failed to record stage transition event", "bead", beadID, "error", err)
+	}
+
+	labelErr := beads.SetLabelsCtx(ctx, proj.BeadsDir, beadID, beads.WithStageLabel(bead.Labels, to))
+	if labelErr != nil {
+		s.logger.Warn("stage moved but bead label not updated", "project", project, "bead", beadID, "error", labelErr)
+	}
+
+	resp := map[string]any{
+		"project":       project,
+		"bead_id":       beadID,
+		"from":          current.CurrentStage,
+		"to":            to,
+		"labels_synced": labelErr == nil,
+	}
+	if labelErr != nil {
+		resp["label_error"] = labelErr.Error()
+	}
+	if s.cfg.Dispatch.Git.DraftPRs && to == scheduler.ReviewStage {
+		pr, err := scheduler.MarkReadyForReview(s.store, config.ExpandHome(proj.Workspace), project, beadID)
+		if err != nil {
+			s.logger.Warn("bead entered review but its PR is still a draft", "project", project, "bead", beadID, "error", err)
+			resp["pr_error"] = err.Error()
+		} else if pr > 0 {
+			resp["pr_ready"] = pr
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestHandleBeadStage(t *testing.T) {
+	srv := setupTestServer(t)
+	srv.cfg.Workflows = map[string]config.WorkflowConfig{
+		"dev": {Stages: []config.StageConfig{
+			{Name: "implement", Role: "coder"},
+			{Name: "review", Role: "reviewer", RequireApproval: true},
+		}},
+	}
+	if err := srv.store.UpsertBeadStage(&store.BeadStage{Project: "test-proj", BeadID: "cx-1", Workflow: "dev", CurrentStage: "implement", TotalStages: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	fakeBin := t.TempDir()
+	logPath := filepath.Join(t.TempDir(), "args.log")
+	script := "#!/bin/sh\n" +
+		"echo \"$@\" >> \"$BD_ARGS_LOG\"\n" +
+		"if [ \"$1\" = show ]; then echo '{\"id\":\"cx-1\",\"status\":\"open\",\"labels\":[\"backend\",\"stage:implement\"]}'; fi\n"
+	if err := os.WriteFile(filepath.Join(fakeBin, "bd"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BD_ARGS_LOG", logPath)
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		srv.handleBeadStage(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return w
+	}
+
+	for _, tc := range []struct {
+		path, body string
+		code       int
+	}{
+		{"/api/v1/beads/test-proj/cx-1/stage", `{}`, http.StatusBadRequest},
+		{"/api/v1/beads/test-proj/cx-1/stage", `not json`, http.StatusBadRequest},
+		{"/api/v1/beads/nope/cx-1/stage", `{"stage":"review"}`, http.StatusNotFound},
+		{"/api/v1/beads/test-proj/cx-9/stage", `{"stage":"review"}`, http.StatusNotFound},
+		{"/api/v1/beads/test-proj/cx-1", `{"stage":"review"}`, http.StatusNotFound},
+		{"/api/v1/beads/test-proj/cx-1/stage", `{"stage":"done"}`, http.StatusConflict},
+	} {
+		if w := post(tc.path, tc.body); w.Code != tc.code {
+			t.Fatalf("POST %s %s: expected %d, got %d: %s", tc.path, tc.body, tc.code, w.Code, w.Body.String())
+		}
+	}
+
+	w := post("/api/v1/beads/test-proj/cx-1/stage", `{"stage":"review","reason":"hand-off"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		From         string `json:"from"`
+		To           string `json:"to"`
+		LabelsSynced bool   `json:"labels_synced"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.From != "implement" || resp.To != "review" || !resp.LabelsSynced {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+
+	stage, err := srv.store.GetBeadStage("test-proj", "cx-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stage.CurrentStage != "review" || len(stage.StageHistory) != 2 {
+		t.Fatalf("unexpected stage %+v", stage)
+	}
+	args, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(args), "update cx-1 --set-labels backend,stage:review --silent") {
+		t.Fatalf("expected stage label update, got %q", args)
+	}
+	events, err := srv.store.GetRecentHealthEvents(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) == 0 || events[0].EventType != "stage_transition" || events[0].Details != "test-proj/cx-1: implement -> review (manual: hand-off)" {
+		t.Fatalf("expected a stage_transition audit event, got %+v", events)
+	}
+
+	// Leaving review needs an approver.
+	if w := post("/api/v1/beads/test-proj/cx-1/stage", `{"stage":"done"}`); w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), "approval required") {
+		t.Fatalf("expected approval gate, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := post("/api/v1/beads/test-proj/cx-1/stage", `{"stage":"done","approved_by":"alice"}`); w.Code != http.StatusOK {
+		t.Fatalf("expected approved transition, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandleBeadStageMarksDraftPRReady(t *testing.T) {
+	srv := setupTestServer(t)
+	srv.cfg.Dispatch.Git.DraftPRs = true
+	proj := srv.cfg.Projects["test-proj"]
+	proj.Workspace = t.TempDir()
+	srv.cfg.Projects["test-proj"] = proj
+	srv.cfg.Workflows = map[string]config.WorkflowConfig{
+		"dev": {Stages: []config.StageConfig{{Name: "implement", Role: "coder"}, {Name: "review", Role: "reviewer"}}},
+	}
+	if err := srv.store.UpsertBeadStage(&store.BeadStage{Project: "test-proj", BeadID: "cx-1", Workflow: "dev", CurrentStage: "implement", TotalStages: 2}); err != nil {
+		t.Fatal(err)
+	}
+	id, err := srv.store.RecordDispatch("cx-1", "test-proj", "claude", "claude", "fast", 0, "", "", "", "feat/cx-1", "headless_cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.store.UpdateDispatchPR(id, "https://github.com/o/r/pull/12", 12); err != nil {
+		t.Fatal(err)
+	}
+
+	fakeBin := t.TempDir()
+	logPath := filepath.Join(t.TempDir(), "args.log")
+	scripts := map[string]string{
+		"bd": "#!/bin/sh\nif [ \"$1\" = show ]; then echo '{\"id\":\"cx-1\",\"status\":\"open\",\"labels\":[\"stage:implement\"]}'; fi\n",
+		"gh": "#!/bin/sh\necho \"gh $@\" >> \"$ARGS_LOG\"\n",
+	}
+	for name, script := range scripts {
+		if err := os.WriteFile(filepath.Join(fakeBin, name), []byte(script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("ARGS_LOG", logPath)
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	w := httptest.NewRecorder()
+	srv.handleBeadStage(w, httptest.NewRequest(http.MethodPost, "/api/v1/beads/test-proj/cx-1/stage", strings.NewReader(`{"stage":"review"}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		PRReady int `json:"pr_ready"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.PRReady != 12 {
+		t.Fatalf("expected PR 12 marked ready, got %+v", resp)
+	}
+	args, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(string(args)) != "gh pr ready 12" {
+		t.Fatalf("expected gh pr ready 12, got %q", args)
+	}
+}
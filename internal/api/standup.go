@@ -0,0 +1,47 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+// GET /api/v1/reports/standup?project=NAME&limit=N
+// Lists stored standup digests, newest first. Without project it lists every
+// project's digests; limit defaults to 7.
+func (s *Server) handleStandupDigests(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	project := r.URL.Query().Get("project")
+	if project != "" {
+		if _, ok := s.cfg.Projects[project]; !ok {
+			writeError(w, http.StatusNotFound, "project not found")
+			return
+		}
+	}
+
+	limit := 7
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = n
+	}
+
+	digests, err := s.store.ListStandupDigests(project, limit)
+	if err != nil {
+		s.logger.Error("failed to list standup digests", "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to list standup digests")
+		return
+	}
+	if digests == nil {
+		digests = []store.StandupDigest{}
+	}
+	writeJSON(w, map[string]any{"digests": digests})
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestHandleStandupDigests(t *testing.T) {
+	srv := setupTestServer(t)
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		srv.handleStandupDigests(w, httptest.NewRequest(http.MethodGet, "/api/v1/reports/standup"+query, nil))
+		return w
+	}
+
+	day := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		end := day.Add(time.Duration(i) * 24 * time.Hour)
+		d := store.StandupDigest{Project: "test-proj", Date: end.Format(time.DateOnly),
+			WindowStart: end.Add(-24 * time.Hour), WindowEnd: end, DispatchesCompleted: i}
+		if err := srv.store.UpsertStandupDigest(d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w := get("?project=test-proj&limit=2")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Digests []store.StandupDigest `json:"digests"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Digests) != 2 || resp.Digests[0].Date != "2026-03-04" || resp.Digests[0].DispatchesCompleted != 2 {
+		t.Fatalf("unexpected digests: %+v", resp.Digests)
+	}
+
+	if w := get("?project=nope"); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown project, got %d", w.Code)
+	}
+	if w := get("?limit=0"); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for bad limit, got %d", w.Code)
+	}
+}
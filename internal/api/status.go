@@ -0,0 +1,267 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"go.temporal.io/api/workflowservice/v1"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+	"github.com/antigravity-dev/cortex/internal/temporal"
+)
+
+const (
+	statusFailureLimit    = 10
+	statusWorkflowLimit   = 100
+	statusTemporalTimeout = 3 * time.Second
+)
+
+// StatusSchedule is a recurring job this daemon runs.
+type StatusSchedule struct {
+	Name     string `json:"name"`
+	Schedule string `json:"schedule"`
+	Project  string `json:"project,omitempty"`
+}
+
+// StatusWorkflow is an open Temporal workflow execution.
+type StatusWorkflow struct {
+	WorkflowID string `json:"workflow_id"`
+	Type       string `json:"type"`
+	StartTime  string `json:"start_time"`
+}
+
+// StatusDispatch is a running or failed dispatch.
+type StatusDispatch struct {
+	ID             int64  `json:"id"`
+	BeadID         string `json:"bead_id"`
+	Project        string `json:"project"`
+	Agent          string `json:"agent"`
+	Stage          string `json:"stage,omitempty"`
+	DispatchedAt   string `json:"dispatched_at"`
+	FailureSummary string `json:"failure_summary,omitempty"`
+}
+
+// StatusQueue summarizes dispatch work in flight and waiting.
+type StatusQueue struct {
+	Running       []StatusDispatch  `json:"running"`
+	PendingRetry  int               `json:"pending_retry"`
+	Overflow      int               `json:"overflow"`
+	OverflowWaits []StatusQueueWait `json:"overflow_waits,omitempty"`
+}
+
+// StatusQueueWait is how long a role's work waited in the overflow queue
+// over health.queue_wait.window, with items still queued at their wait so far.
+type StatusQueueWait struct {
+	Role    string  `json:"role"`
+	Waits   int     `json:"waits"`
+	Queued  int     `json:"queued"`
+	P50S    float64 `json:"p50_s"`
+	P95S    float64 `json:"p95_s"`
+	OldestS float64 `json:"oldest_s"`
+	SLOS    float64 `json:"slo_s,omitempty"`
+}
+
+// StatusConcurrency is the coder and reviewer limits in force. With
+// autoscaling on they are the controller's, with the reason for its last
+// change.
+type StatusConcurrency struct {
+	Coders     int    `json:"coders"`
+	Reviewers  int    `json:"reviewers"`
+	Autoscaled bool   `json:"autoscaled"`
+	Reason     string `json:"reason,omitempty"`
+	ChangedAt  string `json:"changed_at,omitempty"`
+}
+
+// StatusDetail is the body of GET /status?detail=1. uptime_s and
+// running_count keep the plain /status fields for existing clients.
+type StatusDetail struct {
+	UptimeS        float64           `json:"uptime_s"`
+	RunningCount   int               `json:"running_count"`
+	Schedules      []StatusSchedule  `json:"schedules"`
+	Workflows      []StatusWorkflow  `json:"workflows"`
+	WorkflowsError string            `json:"workflows_error,omitempty"`
+	Queue          StatusQueue       `json:"dispatch_queue"`
+	Concurrency    StatusConcurrency `json:"concurrency"`
+	RecentFailures []StatusDispatch  `json:"recent_failures"`
+}
+
+// statusDetail gathers the full operator view. Temporal being unreachable is
+// reported in WorkflowsError rather than failing the whole request.
+func (s *Server) statusDetail(ctx context.Context) StatusDetail {
+	detail := StatusDetail{
+		UptimeS:   time.Since(s.startTime).Seconds(),
+		Schedules: s.schedules(),
+	}
+
+	running, err := s.store.GetRunningDispatches()
+	if err != nil {
+		s.logger.Warn("status: running dispatches", "error", err)
+	}
+	detail.RunningCount = len(running)
+	detail.Queue.Running = statusDispatches(running)
+	if detail.Queue.PendingRetry, err = s.store.CountPendingRetryDispatches(); err != nil {
+		s.logger.Warn("status: pending retries", "error", err)
+	}
+	if detail.Queue.Overflow, err = s.store.CountOverflowQueue(); err != nil {
+		s.logger.Warn("status: overflow queue", "error", err)
+	}
+	if detail.Queue.OverflowWaits, err = s.queueWaits(time.Now()); err != nil {
+		s.logger.Warn("status: overflow queue waits", "error", err)
+	}
+
+	detail.Concurrency = s.concurrency()
+
+	failed, err := s.store.GetRecentFailedDispatches(statusFailureLimit)
+	if err != nil {
+		s.logger.Warn("status: recent failures", "error", err)
+	}
+	detail.RecentFailures = statusDispatches(failed)
+
+	if detail.Workflows, err = openWorkflows(ctx, s.cfg.Temporal); err != nil {
+		detail.WorkflowsError = err.Error()
+	}
+	return detail
+}
+
+// concurrency returns the limits in force: the autoscaler's while it is
+// enabled, the configured ones otherwise.
+func (s *Server) concurrency() StatusConcurrency {
+	autoscaled := s.cfg.General.Autoscale.Enabled && s.autoscaler != nil
+	ctrl := s.autoscaler
+	if !autoscaled {
+		ctrl = nil
+	}
+	limits, reason, changedAt := ctrl.Current(s.cfg.General)
+	out := StatusConcurrency{Coders: limits.Coders, Reviewers: limits.Reviewers, Autoscaled: autoscaled, Reason: reason}
+	if !changedAt.IsZero() {
+		out.ChangedAt = changedAt.UTC().Format(time.RFC3339)
+	}
+	return out
+}
+
+// queueWaits returns the overflow queue wait of each role, sorted by role.
+func (s *Server) queueWaits(now time.Time) ([]StatusQueueWait, error) {
+	qw := s.cfg.Health.QueueWait
+	stats, err := s.store.GetQueueWaitStats(now.Add(-qw.Window.Duration), now)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]StatusQueueWait, 0, len(stats))
+	for role, st := range stats {
+		out = append(out, StatusQueueWait{
+			Role:    role,
+			Waits:   st.Waits,
+			Queued:  st.Queued,
+			P50S:    st.P50.Seconds(),
+			P95S:    st.P95.Seconds(),
+			OldestS: st.Oldest.Seconds(),
+			SLOS:    qw.RoleSLO(role).Seconds(),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Role < out[j].Role })
+	return out, nil
+}
+
+// schedules lists the crons the daemon registers at startup, plus each
+// project's sprint planning slot. With sharding only this shard's projects
+// are listed.
+func (s *Server) schedules() []StatusSchedule {
+	var out []StatusSchedule
+	var names []string
+	for name, p := range s.cfg.Projects {
+		if p.Enabled && s.cfg.OwnsProject(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		out = append(out, StatusSchedule{Name: "strategic-groom-" + name, Schedule: s.cfg.Temporal.Schedules.StrategicGroom, Project: name})
+		if p := s.cfg.Projects[name]; p.SprintPlanningDay != "" {
+			out = append(out, StatusSchedule{Name: "sprint-planning", Schedule: p.SprintPlanningDay + " " + p.SprintPlanningTime, Project: name})
+		}
+	}
+	out = append(out, StatusSchedule{Name: "failure-cluster-report", Schedule: s.cfg.Temporal.Schedules.FailureClusterReport})
+	if sr := s.cfg.Dispatch.StalledReview; sr.Enabled {
+		out = append(out, StatusSchedule{Name: "stalled-review-check", Schedule: sr.Schedule})
+	}
+	if s.cfg.StageSLAEnabled() {
+		out = append(out, StatusSchedule{Name: "stage-sla-check", Schedule: s.cfg.Dispatch.StageSLA.Schedule})
+	}
+	if schedule, err := s.cfg.Reporter.DailyDigestSchedule(); err == nil && schedule != "" {
+		out = append(out, StatusSchedule{Name: "standup-digest", Schedule: schedule})
+	}
+	if bj := s.cfg.Dispatch.BranchJanitor; bj.Enabled {
+		out = append(out, StatusSchedule{Name: "branch-janitor", Schedule: bj.Schedule})
+	}
+	if bi := s.cfg.Reporter.BurnIn; bi.Enabled {
+		out = append(out, StatusSchedule{Name: "burnin-report", Schedule: bi.Schedule})
+	}
+	if cr := s.cfg.Reporter.Cost; cr.Enabled {
+		out = append(out, StatusSchedule{Name: "cost-report", Schedule: cr.Schedule})
+	}
+	if ro := s.cfg.Health.Rollout; ro.Enabled {
+		out = append(out, StatusSchedule{Name: "rollout-completion", Schedule: ro.Schedule})
+	}
+	if len(s.cfg.WarmupProviders()) > 0 {
+		out = append(out, StatusSchedule{Name: "provider-warmup", Schedule: s.cfg.Dispatch.Warmup.Schedule})
+	}
+	return out
+}
+
+func openWorkflows(ctx context.Context, tc config.Temporal) ([]StatusWorkflow, error) {
+	ctx, cancel := context.WithTimeout(ctx, statusTemporalTimeout)
+	defer cancel()
+
+	c, err := temporal.DialContext(ctx, tc)
+	if err != nil {
+		return nil, fmt.Errorf("connect to temporal: %w", err)
+	}
+	defer c.Close()
+
+	resp, err := c.ListOpenWorkflow(ctx, &workflowservice.ListOpenWorkflowExecutionsRequest{
+		Namespace:       tc.Namespace,
+		MaximumPageSize: statusWorkflowLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list open workflows: %w", err)
+	}
+
+	out := make([]StatusWorkflow, 0, len(resp.Executions))
+	for _, info := range resp.Executions {
+		out = append(out, StatusWorkflow{
+			WorkflowID: info.Execution.GetWorkflowId(),
+			Type:       info.Type.GetName(),
+			StartTime:  info.StartTime.AsTime().Format(time.RFC3339),
+		})
+	}
+	return out, nil
+}
+
+func statusDispatches(ds []store.Dispatch) []StatusDispatch {
+	out := make([]StatusDispatch, 0, len(ds))
+	for _, d := range ds {
+		out = append(out, StatusDispatch{
+			ID:             d.ID,
+			BeadID:         d.BeadID,
+			Project:        d.Project,
+			Agent:          d.AgentID,
+			Stage:          d.Stage,
+			DispatchedAt:   d.DispatchedAt.Format(time.RFC3339),
+			FailureSummary: d.FailureSummary,
+		})
+	}
+	return out
+}
+
+// statusDetailRequested reports whether GET /status asked for the full view.
+func statusDetailRequested(r *http.Request) bool {
+	switch r.URL.Query().Get("detail") {
+	case "1", "true":
+		return true
+	}
+	return false
+}
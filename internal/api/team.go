@@ -0,0 +1,166 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+// TeamAgentStatus is a roster entry with the statistics of the dispatches
+// the agent ran.
+type TeamAgentStatus struct {
+	Name      string         `json:"name"`
+	Project   string         `json:"project"`
+	Role      string         `json:"role"`
+	Model     string         `json:"model"`
+	Workspace string         `json:"workspace,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	Retired   bool           `json:"retired"`
+	RetiredAt *time.Time     `json:"retired_at,omitempty"`
+	RetiredBy string         `json:"retired_by,omitempty"`
+	Stats     TeamAgentStats `json:"stats"`
+}
+
+// TeamAgentStats summarizes an agent's dispatches.
+type TeamAgentStats struct {
+	Dispatches   int        `json:"dispatches"`
+	Completed    int        `json:"completed"`
+	Failed       int        `json:"failed"`
+	SuccessRate  float64    `json:"success_rate"` // completed share of dispatches, 0-1
+	AvgDurationS float64    `json:"avg_duration_s"`
+	CostUSD      float64    `json:"cost_usd"`
+	LastActiveAt *time.Time `json:"last_active_at,omitempty"`
+}
+
+// GET /api/v1/team
+// Lists the team roster. ?project narrows it to one project and ?retired=1
+// includes retired agents.
+func (s *Server) handleTeam(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	q := r.URL.Query()
+	agents, err := s.store.ListTeamAgents(q.Get("project"), q.Get("retired") == "1" || q.Get("retired") == "true")
+	if err != nil {
+		s.logger.Error("failed to list team agents", "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to list team agents")
+		return
+	}
+	out := make([]TeamAgentStatus, 0, len(agents))
+	for _, a := range agents {
+		status, err := s.teamAgentStatus(a)
+		if err != nil {
+			s.logger.Error("failed to read team agent stats", "agent", a.Name, "error", err)
+			writeError(w, http.StatusInternalServerError, "failed to read team agent stats")
+			return
+		}
+		out = append(out, status)
+	}
+	writeJSON(w, map[string]any{"agents": out})
+}
+
+// GET  /api/v1/team/{agent}         one agent with its statistics
+// POST /api/v1/team/{agent}/retire  retire the agent
+// The retire body is optional: {"retired_by": "ops"}. Retired agents stay in
+// the roster and are not recreated when the team is ensured.
+func (s *Server) routeTeam(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/team/"), "/"), "/")
+	name := parts[0]
+	switch {
+	case name == "" || len(parts) > 2 || (len(parts) == 2 && parts[1] != "retire"):
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	case len(parts) == 1 && r.Method != http.MethodGet, len(parts) == 2 && r.Method != http.MethodPost:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	agent, err := s.store.GetTeamAgent(name)
+	if err != nil {
+		s.logger.Error("failed to read team agent", "agent", name, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to read team agent")
+		return
+	}
+	if agent == nil {
+		writeError(w, http.StatusNotFound, "agent not in team roster")
+		return
+	}
+
+	if len(parts) == 2 {
+		var req struct {
+			RetiredBy string `json:"retired_by"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		retiredBy := strings.TrimSpace(req.RetiredBy)
+		if retiredBy == "" {
+			retiredBy = "api"
+		}
+		retired, err := s.store.RetireTeamAgent(name, retiredBy)
+		if err != nil {
+			s.logger.Error("failed to retire team agent", "agent", name, "error", err)
+			writeError(w, http.StatusInternalServerError, "failed to retire team agent")
+			return
+		}
+		if !retired {
+			writeError(w, http.StatusConflict, "agent is already retired")
+			return
+		}
+		s.logger.Info("team agent retired", "agent", name, "retired_by", retiredBy)
+		if agent, err = s.store.GetTeamAgent(name); err != nil || agent == nil {
+			s.logger.Error("failed to read team agent", "agent", name, "error", err)
+			writeError(w, http.StatusInternalServerError, "failed to read team agent")
+			return
+		}
+	}
+
+	status, err := s.teamAgentStatus(*agent)
+	if err != nil {
+		s.logger.Error("failed to read team agent stats", "agent", name, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to read team agent stats")
+		return
+	}
+	writeJSON(w, status)
+}
+
+func (s *Server) teamAgentStatus(a store.TeamAgent) (TeamAgentStatus, error) {
+	status := TeamAgentStatus{
+		Name:      a.Name,
+		Project:   a.Project,
+		Role:      a.Role,
+		Model:     a.Model,
+		Workspace: a.Workspace,
+		CreatedAt: a.CreatedAt,
+		Retired:   a.RetiredAt.Valid,
+		RetiredBy: a.RetiredBy,
+	}
+	if a.RetiredAt.Valid {
+		status.RetiredAt = &a.RetiredAt.Time
+	}
+	st, err := s.store.GetTeamAgentStats(a.Name)
+	if err != nil {
+		return status, err
+	}
+	status.Stats = TeamAgentStats{
+		Dispatches:   st.Dispatches,
+		Completed:    st.Completed,
+		Failed:       st.Failed,
+		AvgDurationS: st.AvgDurationS,
+		CostUSD:      st.CostUSD,
+	}
+	if st.Dispatches > 0 {
+		status.Stats.SuccessRate = float64(st.Completed) / float64(st.Dispatches)
+	}
+	if st.LastActiveAt.Valid {
+		status.Stats.LastActiveAt = &st.LastActiveAt.Time
+	}
+	return status, nil
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestTeamRosterEndpoints(t *testing.T) {
+	srv := setupTestServer(t)
+	for _, a := range []store.TeamAgent{
+		{Name: "test-proj-coder", Project: "test-proj", Role: "coder", Model: "sonnet"},
+		{Name: "test-proj-scrum", Project: "test-proj", Role: "scrum", Model: "sonnet"},
+		{Name: "other-coder", Project: "other", Role: "coder", Model: "opus"},
+	} {
+		if err := srv.store.RecordTeamAgent(a); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, status := range []string{"completed", "failed"} {
+		id, err := srv.store.RecordDispatch("cx-1", "test-proj", "test-proj-coder", "claude", "balanced", 0, "", "p", "", "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := srv.store.UpdateDispatchStatus(id, status, 0, 30); err != nil {
+			t.Fatal(err)
+		}
+	}
+	list := func(query string) []TeamAgentStatus {
+		t.Helper()
+		w := httptest.NewRecorder()
+		srv.handleTeam(w, httptest.NewRequest(http.MethodGet, "/api/v1/team"+query, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			Agents []TeamAgentStatus `json:"agents"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Agents
+	}
+	team := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		srv.routeTeam(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	agents := list("?project=test-proj")
+	if len(agents) != 2 || agents[0].Name != "test-proj-coder" {
+		t.Fatalf("unexpected roster: %+v", agents)
+	}
+	if st := agents[0].Stats; st.Dispatches != 2 || st.Completed != 1 || st.SuccessRate != 0.5 || st.LastActiveAt == nil {
+		t.Fatalf("unexpected coder stats: %+v", st)
+	}
+
+	w := team(http.MethodPost, "/api/v1/team/test-proj-scrum/retire", `{"retired_by": "ops"}`)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"retired":true`) || !strings.Contains(w.Body.String(), `"retired_by":"ops"`) {
+		t.Fatalf("unexpected retire response %d: %s", w.Code, w.Body.String())
+	}
+	if w := team(http.MethodPost, "/api/v1/team/test-proj-scrum/retire", ""); w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for a retired agent, got %d", w.Code)
+	}
+	if agents := list("?project=test-proj"); len(agents) != 1 {
+		t.Fatalf("expected retired agents to be hidden, got %+v", agents)
+	}
+	if agents := list("?retired=1"); len(agents) != 3 {
+		t.Fatalf("expected the full roster, got %+v", agents)
+	}
+
+	if w := team(http.MethodGet, "/api/v1/team/test-proj-coder", ""); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"role":"coder"`) {
+		t.Fatalf("unexpected agent response %d: %s", w.Code, w.Body.String())
+	}
+	if w := team(http.MethodGet, "/api/v1/team/nope", ""); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown agent, got %d", w.Code)
+	}
+	if w := team(http.MethodGet, "/api/v1/team/test-proj-coder/retire", ""); w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", w.Code)
+	}
+}
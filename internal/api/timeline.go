@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+)
+
+// routeBeads dispatches /api/v1/beads/{project}/{id}/{action}.
+func (s *Server) routeBeads(w http.ResponseWriter, r *http.Request) {
+	switch path := strings.TrimSuffix(r.URL.Path, "/"); {
+	case strings.HasSuffix(path, "/timeline"):
+		s.handleBeadTimeline(w, r)
+	case strings.HasSuffix(path, "/why"):
+		s.handleBeadWhy(w, r)
+	default:
+		s.handleBeadStage(w, r)
+	}
+}
+
+// GET /api/v1/beads/{project}/{id}/timeline
+// Returns the bead's dispatches, stage history, DoD runs, health events,
+// claim lease and PR events as one chronological record.
+func (s *Server) handleBeadTimeline(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/beads/"), "/"), "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] != "timeline" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	project, beadID := parts[0], parts[1]
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if _, ok := s.cfg.Projects[project]; !ok {
+		writeError(w, http.StatusNotFound, "project not found")
+		return
+	}
+
+	timeline, err := s.store.GetBeadTimeline(project, beadID)
+	if err != nil {
+		s.logger.Error("failed to build bead timeline", "project", project, "bead", beadID, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to build bead timeline")
+		return
+	}
+	writeJSON(w, timeline)
+}
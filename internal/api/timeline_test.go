@@ -0,0 +1,46 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestHandleBeadTimeline(t *testing.T) {
+	srv := setupTestServer(t)
+	if _, err := srv.store.RecordDispatch("cx-1", "test-proj", "codex", "codex", "fast", 0, "", "fix it", "", "", "temporal"); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	srv.routeBeads(w, httptest.NewRequest(http.MethodGet, "/api/v1/beads/test-proj/cx-1/timeline", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var tl store.BeadTimeline
+	if err := json.NewDecoder(w.Body).Decode(&tl); err != nil {
+		t.Fatal(err)
+	}
+	if tl.BeadID != "cx-1" || len(tl.Events) != 1 || tl.Events[0].Kind != store.TimelineDispatch {
+		t.Fatalf("unexpected timeline %+v", tl)
+	}
+
+	cases := []struct {
+		method, path string
+		want         int
+	}{
+		{http.MethodPost, "/api/v1/beads/test-proj/cx-1/timeline", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/api/v1/beads/nope/cx-1/timeline", http.StatusNotFound},
+		{http.MethodGet, "/api/v1/beads/test-proj/timeline", http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		srv.routeBeads(w, httptest.NewRequest(tc.method, tc.path, nil))
+		if w.Code != tc.want {
+			t.Errorf("%s %s: expected %d, got %d", tc.method, tc.path, tc.want, w.Code)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/beads"
+	"github.com/antigravity-dev/cortex/internal/journal"
+	"github.com/antigravity-dev/cortex/internal/temporal"
+)
+
+// WhyCheck is one dispatch gate evaluated for a bead.
+type WhyCheck struct {
+	Name     string `json:"name"`
+	Blocking bool   `json:"blocking"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// BeadWhy explains whether a bead can be dispatched now and, if not, which
+// gate stops it.
+type BeadWhy struct {
+	Project      string         `json:"project"`
+	BeadID       string         `json:"bead_id"`
+	Status       string         `json:"status"`
+	Dispatchable bool           `json:"dispatchable"`
+	Reason       string         `json:"reason,omitempty"` // the first blocking check's detail
+	Checks       []WhyCheck     `json:"checks"`
+	LastDecision *journal.Entry `json:"last_decision,omitempty"` // latest journaled decision about the bead
+}
+
+// GET /api/v1/beads/{project}/{id}/why
+// Evaluates every dispatch gate for the bead now and returns the most recent
+// journaled decision about it.
+func (s *Server) handleBeadWhy(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/beads/"), "/"), "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] != "why" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	project, beadID := parts[0], parts[1]
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	proj, ok := s.cfg.Projects[project]
+	if !ok {
+		writeError(w, http.StatusNotFound, "project not found")
+		return
+	}
+
+	list, err := beads.ListBeadsCtx(r.Context(), proj.BeadsDir)
+	if err != nil {
+		s.logger.Error("failed to list beads", "project", project, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to list beads")
+		return
+	}
+	graph := beads.BuildDepGraph(list)
+	bead := graph.Nodes()[beadID]
+	if bead == nil {
+		writeError(w, http.StatusNotFound, "bead not found")
+		return
+	}
+
+	why := &BeadWhy{Project: project, BeadID: beadID, Status: bead.Status}
+	check := func(name string, blocking bool, detail string) {
+		why.Checks = append(why.Checks, WhyCheck{Name: name, Blocking: blocking, Detail: detail})
+		if blocking && why.Reason == "" {
+			why.Reason = detail
+		}
+	}
+
+	check("status", bead.Status != "open" || bead.Type == "epic", fmt.Sprintf("%s %s", bead.Status, bead.Type))
+
+	var open []string
+	for _, dep := range graph.DependsOnIDs(beadID) {
+		switch d := graph.Nodes()[dep]; {
+		case d == nil:
+			open = append(open, dep+" (missing)")
+		case d.Status != "closed":
+			open = append(open, dep+" ("+d.Status+")")
+		}
+	}
+	if len(open) > 0 {
+		check("dependencies", true, "blocked by "+strings.Join(open, ", "))
+	} else {
+		check("dependencies", false, "")
+	}
+
+	if err := s.whyDispatchChecks(project, beadID, check); err != nil {
+		s.logger.Error("failed to evaluate dispatch gates", "project", project, "bead", beadID, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to evaluate dispatch gates")
+		return
+	}
+
+	held := s.dispatchHeld(r.Context(), temporal.TaskRequest{Project: project, BeadID: beadID})
+	check("dispatch_window", held != "", held)
+
+	why.Dispatchable = why.Reason == ""
+	if why.LastDecision, err = s.journal.Latest(project, beadID); err != nil {
+		s.logger.Warn("failed to read decision journal", "project", project, "bead", beadID, "error", err)
+	}
+	writeJSON(w, why)
+}
+
+// whyDispatchChecks evaluates the gates kept in the state DB: a running
+// dispatch, the re-dispatch cooldown, the churn guard and concurrency caps.
+func (s *Server) whyDispatchChecks(project, beadID string, check func(name string, blocking bool, detail string)) error {
+	running, err := s.store.IsBeadDispatched(beadID)
+	if err != nil {
+		return err
+	}
+	if running {
+		check("running", true, "a dispatch is already running")
+	} else {
+		check("running", false, "")
+	}
+
+	cooldown := s.cfg.General.DispatchCooldown.Duration
+	recent, err := s.store.WasBeadDispatchedRecently(beadID, cooldown)
+	if err != nil {
+		return err
+	}
+	if recent {
+		check("cooldown", true, "dispatched within the last "+cooldown.String())
+	} else {
+		check("cooldown", false, "")
+	}
+
+	if cc := s.cfg.Dispatch.CostControl; cc.PauseOnChurn {
+		since := time.Now().Add(-cc.ChurnPauseWindow.Duration)
+		failed, err := s.store.CountDispatchesSince(since, []string{"failed"})
+		if err != nil {
+			return err
+		}
+		total, err := s.store.CountDispatchesSince(since, nil)
+		if err != nil {
+			return err
+		}
+		churning := failed >= cc.ChurnPauseFailure || total >= cc.ChurnPauseTotal
+		detail := fmt.Sprintf("%d failed of %d dispatches in the last %s", failed, total, cc.ChurnPauseWindow.Duration)
+		check("churn_guard", churning, detail)
+	}
+
+	dispatches, err := s.store.GetRunningDispatches()
+	if err != nil {
+		return err
+	}
+	inProject := 0
+	for _, d := range dispatches {
+		if d.Project == project {
+			inProject++
+		}
+	}
+	switch limit, perProject := s.cfg.General.MaxConcurrentTotal, s.cfg.Dispatch.Git.MaxConcurrentPerProject; {
+	case limit > 0 && len(dispatches) >= limit:
+		check("capacity", true, fmt.Sprintf("%d of %d total dispatch slots in use", len(dispatches), limit))
+	case perProject > 0 && inProject >= perProject:
+		check("capacity", true, fmt.Sprintf("%d of %d %s dispatch slots in use", inProject, perProject, project))
+	default:
+		check("capacity", false, fmt.Sprintf("%d running, %d in %s", len(dispatches), inProject, project))
+	}
+	return nil
+}
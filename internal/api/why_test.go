@@ -0,0 +1,79 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/journal"
+)
+
+func TestHandleBeadWhy(t *testing.T) {
+	srv := setupTestServer(t)
+	fakeBin := t.TempDir()
+	list := `[{"id":"cx-1","status":"open","issue_type":"task","depends_on":["cx-0"]},` +
+		`{"id":"cx-0","status":"in_progress","issue_type":"task"},` +
+		`{"id":"cx-2","status":"open","issue_type":"task"}]`
+	if err := os.WriteFile(fakeBin+"/bd", []byte("#!/bin/sh\necho '"+list+"'\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+	srv.cfg.General.DispatchCooldown.Duration = time.Hour
+	jr, err := journal.Open(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer jr.Close()
+	srv.SetJournal(jr)
+
+	get := func(path string) (int, BeadWhy) {
+		w := httptest.NewRecorder()
+		srv.routeBeads(w, httptest.NewRequest(http.MethodGet, path, nil))
+		var why BeadWhy
+		if w.Code == http.StatusOK {
+			if err := json.NewDecoder(w.Body).Decode(&why); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return w.Code, why
+	}
+
+	code, why := get("/api/v1/beads/test-proj/cx-1/why")
+	if code != http.StatusOK || why.Dispatchable || why.Reason != "blocked by cx-0 (in_progress)" {
+		t.Fatalf("expected cx-1 blocked by its dependency, got %d %+v", code, why)
+	}
+
+	code, why = get("/api/v1/beads/test-proj/cx-2/why")
+	if code != http.StatusOK || !why.Dispatchable || why.LastDecision != nil {
+		t.Fatalf("expected cx-2 dispatchable, got %d %+v", code, why)
+	}
+
+	if _, err := srv.store.RecordDispatch("cx-2", "test-proj", "codex", "codex", "fast", 0, "", "fix it", "", "", "temporal"); err != nil {
+		t.Fatal(err)
+	}
+	if err := jr.Record(journal.Entry{Decision: journal.Admit, Source: "api", Project: "test-proj", BeadID: "cx-2", Workflow: "cx-2"}); err != nil {
+		t.Fatal(err)
+	}
+	_, why = get("/api/v1/beads/test-proj/cx-2/why")
+	if why.Dispatchable || why.Reason != "a dispatch is already running" {
+		t.Fatalf("expected cx-2 held by its running dispatch, got %+v", why)
+	}
+	if why.LastDecision == nil || why.LastDecision.Decision != journal.Admit {
+		t.Fatalf("expected last journaled decision, got %+v", why.LastDecision)
+	}
+	for _, c := range why.Checks {
+		if c.Name == "cooldown" && !c.Blocking {
+			t.Fatalf("expected cooldown to block, got %+v", c)
+		}
+	}
+
+	if code, _ := get("/api/v1/beads/test-proj/cx-9/why"); code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown bead, got %d", code)
+	}
+	if code, _ := get("/api/v1/beads/nope/cx-1/why"); code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown project, got %d", code)
+	}
+}
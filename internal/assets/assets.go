@@ -0,0 +1,55 @@
+// Package assets embeds static files shipped inside Cortex binaries:
+// the API dashboard and Definition of Done presets.
+package assets
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed dashboards presets
+var files embed.FS
+
+// Dashboards returns the embedded dashboard file tree rooted at dashboards/.
+func Dashboards() fs.FS {
+	sub, err := fs.Sub(files, "dashboards")
+	if err != nil {
+		// The directory is embedded at compile time; failure is a build bug.
+		panic(fmt.Sprintf("assets: dashboards missing from embed: %v", err))
+	}
+	return sub
+}
+
+// DoDPreset returns the raw TOML for the named Definition of Done preset.
+func DoDPreset(name string) ([]byte, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return nil, fmt.Errorf("invalid DoD preset name %q", name)
+	}
+	data, err := files.ReadFile(path.Join("presets", "dod", name+".toml"))
+	if err != nil {
+		return nil, fmt.Errorf("unknown DoD preset %q (available: %s)", name, strings.Join(DoDPresetNames(), ", "))
+	}
+	return data, nil
+}
+
+// DoDPresetNames lists the embedded Definition of Done presets in sorted order.
+func DoDPresetNames() []string {
+	entries, err := files.ReadDir("presets/dod")
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".toml") {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(entry.Name(), ".toml"))
+	}
+	sort.Strings(names)
+	return names
+}
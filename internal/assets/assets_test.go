@@ -0,0 +1,36 @@
+package assets
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+func TestDashboardsIncludesIndex(t *testing.T) {
+	data, err := fs.ReadFile(Dashboards(), "index.html")
+	if err != nil {
+		t.Fatalf("read index.html: %v", err)
+	}
+	if !strings.Contains(string(data), "Cortex") {
+		t.Fatal("expected dashboard index to mention Cortex")
+	}
+}
+
+func TestDoDPresetNames(t *testing.T) {
+	names := DoDPresetNames()
+	want := []string{"go", "node", "python", "rust"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("DoDPresetNames() = %v, want %v", names, want)
+	}
+}
+
+func TestDoDPresetRejectsUnknownAndTraversal(t *testing.T) {
+	if _, err := DoDPreset("Go"); err != nil {
+		t.Fatalf("expected case-insensitive lookup, got %v", err)
+	}
+	for _, name := range []string{"", "cobol", "../dashboards/index"} {
+		if _, err := DoDPreset(name); err == nil {
+			t.Fatalf("expected error for preset %q", name)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// Package autoscale moves the coder and reviewer concurrency limits within
+// configured bounds, growing a role that has work queued for a slot and
+// shrinking idle roles, or every role while dispatches fail too often or the
+// day's spend nears its cap.
+package autoscale
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+// minFailureSample is how many recent dispatches the failure rate needs
+// before it can shrink the limits, so one early failure does not.
+const minFailureSample = 5
+
+// Limits are the concurrency limits in force.
+type Limits struct {
+	Coders    int `json:"coders"`
+	Reviewers int `json:"reviewers"`
+}
+
+// Signals are what the controller decides on.
+type Signals struct {
+	Queued     map[string]int // overflow queue items by role
+	Dispatches int            // dispatches in the failure window
+	Failed     int            // of which failed
+	SpentToday float64        // USD
+	DailyCap   float64        // USD; 0 leaves spend out
+}
+
+// Decision is the outcome of one step.
+type Decision struct {
+	From, To Limits
+	Reason   string
+}
+
+// Changed reports whether the step moved a limit.
+func (d Decision) Changed() bool { return d.From != d.To }
+
+// Controller holds the current limits. A nil controller reports the
+// configured limits. The slots dispatches hold under them are leased in the
+// store, so they are shared by every worker and survive a restart.
+type Controller struct {
+	mu        sync.Mutex
+	limits    Limits
+	reason    string
+	changedAt time.Time
+}
+
+// New starts from the configured limits, clamped to the autoscale bounds.
+func New(cfg config.General) *Controller {
+	return &Controller{limits: clamp(Limits{Coders: cfg.MaxConcurrentCoders, Reviewers: cfg.MaxConcurrentReviewers}, cfg.Autoscale)}
+}
+
+// Current returns the limits in force, the reason for the last change and
+// when it was made. A nil controller returns the configured limits.
+func (c *Controller) Current(cfg config.General) (Limits, string, time.Time) {
+	if c == nil {
+		return Limits{Coders: cfg.MaxConcurrentCoders, Reviewers: cfg.MaxConcurrentReviewers}, "", time.Time{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.limits, c.reason, c.changedAt
+}
+
+// Limit returns role's limit in force: the controller's with autoscaling
+// enabled, the configured one otherwise. Roles other than coder and
+// reviewer, and a limit of 0, are unbounded and report 0.
+func (c *Controller) Limit(cfg config.General, role string) int {
+	limits, _, _ := c.Current(cfg)
+	if !cfg.Autoscale.Enabled {
+		limits = Limits{Coders: cfg.MaxConcurrentCoders, Reviewers: cfg.MaxConcurrentReviewers}
+	}
+	switch role {
+	case "coder":
+		return limits.Coders
+	case "reviewer":
+		return limits.Reviewers
+	}
+	return 0
+}
+
+// Step moves the limits one step according to s.
+func (c *Controller) Step(cfg config.GeneralAutoscale, s Signals, now time.Time) Decision {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	d := Decision{From: c.limits}
+	next, reason := decide(cfg, clamp(c.limits, cfg), s)
+	d.To, d.Reason = next, reason
+	if d.Changed() {
+		c.limits, c.reason, c.changedAt = next, reason, now
+	}
+	return d
+}
+
+// decide applies one step. Too many failures or too much spend shrink
+// every role; otherwise each role grows with queued work and shrinks when
+// nothing waits.
+func decide(cfg config.GeneralAutoscale, cur Limits, s Signals) (Limits, string) {
+	if s.Dispatches >= minFailureSample {
+		if rate := float64(s.Failed) / float64(s.Dispatches); rate > cfg.MaxFailureRate {
+			return clamp(Limits{Coders: cur.Coders - cfg.Step, Reviewers: cur.Reviewers - cfg.Step}, cfg),
+				fmt.Sprintf("failure rate %.0f%% above %.0f%%", rate*100, cfg.MaxFailureRate*100)
+		}
+	}
+	if s.DailyCap > 0 {
+		if burn := s.SpentToday / s.DailyCap; burn > cfg.MaxCostBurn {
+			return clamp(Limits{Coders: cur.Coders - cfg.Step, Reviewers: cur.Reviewers - cfg.Step}, cfg),
+				fmt.Sprintf("spent %.0f%% of the daily cost cap", burn*100)
+		}
+	}
+
+	var reasons []string
+	move := func(role string, limit int) int {
+		queued := s.Queued[role]
+		switch {
+		case queued >= cfg.QueueDepth:
+			reasons = append(reasons, fmt.Sprintf("%d %s item(s) queued", queued, role))
+			return limit + cfg.Step
+		case queued == 0:
+			reasons = append(reasons, fmt.Sprintf("no %s work queued", role))
+			return limit - cfg.Step
+		}
+		return limit
+	}
+	next := clamp(Limits{Coders: move("coder", cur.Coders), Reviewers: move("reviewer", cur.Reviewers)}, cfg)
+	return next, strings.Join(reasons, ", ")
+}
+
+func clamp(l Limits, cfg config.GeneralAutoscale) Limits {
+	return Limits{
+		Coders:    min(max(l.Coders, cfg.MinCoders), cfg.MaxCoders),
+		Reviewers: min(max(l.Reviewers, cfg.MinReviewers), cfg.MaxReviewers),
+	}
+}
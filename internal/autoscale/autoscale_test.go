@@ -0,0 +1,90 @@
+package autoscale
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+func testConfig() config.General {
+	return config.General{
+		MaxConcurrentCoders:    4,
+		MaxConcurrentReviewers: 2,
+		Autoscale: config.GeneralAutoscale{
+			Enabled: true, Step: 1, QueueDepth: 2,
+			MinCoders: 1, MaxCoders: 6, MinReviewers: 1, MaxReviewers: 3,
+			MaxFailureRate: 0.5, MaxCostBurn: 0.8,
+		},
+	}
+}
+
+func TestStep(t *testing.T) {
+	cfg := testConfig()
+	now := time.Now()
+	tests := []struct {
+		name   string
+		start  Limits
+		s      Signals
+		want   Limits
+		reason string
+	}{
+		{"queued work grows its role", Limits{4, 2}, Signals{Queued: map[string]int{"coder": 3, "reviewer": 1}}, Limits{5, 2}, "3 coder item(s) queued"},
+		{"growth stops at the max", Limits{6, 3}, Signals{Queued: map[string]int{"coder": 9, "reviewer": 9}}, Limits{6, 3}, ""},
+		{"idle roles shrink", Limits{4, 2}, Signals{}, Limits{3, 1}, "no coder work queued, no reviewer work queued"},
+		{"failures shrink everything", Limits{4, 2}, Signals{Queued: map[string]int{"coder": 5}, Dispatches: 10, Failed: 6}, Limits{3, 1}, "failure rate 60% above 50%"},
+		{"a small sample is ignored", Limits{4, 2}, Signals{Queued: map[string]int{"coder": 5, "reviewer": 1}, Dispatches: 2, Failed: 2}, Limits{5, 2}, "5 coder item(s) queued"},
+		{"spend shrinks everything", Limits{4, 2}, Signals{Queued: map[string]int{"coder": 5}, SpentToday: 90, DailyCap: 100}, Limits{3, 1}, "spent 90% of the daily cost cap"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Controller{limits: tt.start}
+			d := c.Step(cfg.Autoscale, tt.s, now)
+			if d.To != tt.want || d.From != tt.start {
+				t.Fatalf("step = %+v, want %+v", d, tt.want)
+			}
+			got, reason, changedAt := c.Current(cfg)
+			if got != tt.want {
+				t.Fatalf("current = %+v, want %+v", got, tt.want)
+			}
+			if d.Changed() && (reason != tt.reason || !changedAt.Equal(now)) {
+				t.Fatalf("reason = %q at %v, want %q", reason, changedAt, tt.reason)
+			}
+		})
+	}
+}
+
+func TestNewClampsToBounds(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxConcurrentCoders = 25
+	if got, _, _ := New(cfg).Current(cfg); got != (Limits{6, 2}) {
+		t.Fatalf("start limits = %+v, want coders clamped to 6", got)
+	}
+	var c *Controller
+	if got, _, _ := c.Current(cfg); got != (Limits{25, 2}) {
+		t.Fatalf("nil controller limits = %+v, want the configured ones", got)
+	}
+}
+
+func TestLimitFollowsTheLimitsInForce(t *testing.T) {
+	cfg := testConfig()
+	c := &Controller{limits: Limits{Coders: 1, Reviewers: 3}}
+	if got := c.Limit(cfg, "coder"); got != 1 {
+		t.Fatalf("coder limit = %d, want the autoscaled 1", got)
+	}
+	if got := c.Limit(cfg, "reviewer"); got != 3 {
+		t.Fatalf("reviewer limit = %d, want the autoscaled 3", got)
+	}
+	if got := c.Limit(cfg, "planner"); got != 0 {
+		t.Fatalf("planner limit = %d, want unbounded", got)
+	}
+
+	cfg.Autoscale.Enabled = false
+	if got := c.Limit(cfg, "coder"); got != 4 {
+		t.Fatalf("coder limit = %d, want the configured 4", got)
+	}
+	var none *Controller
+	if got := none.Limit(cfg, "reviewer"); got != 2 {
+		t.Fatalf("nil controller reviewer limit = %d, want the configured 2", got)
+	}
+}
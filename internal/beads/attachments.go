@@ -0,0 +1,313 @@
+package beads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// MaxAttachmentBytes caps the size of a single bead attachment.
+const MaxAttachmentBytes int64 = 20 << 20
+
+// ErrAttachmentNotFound is returned when a named attachment does not exist.
+var ErrAttachmentNotFound = errors.New("attachment not found")
+
+// Attachment is a file (screenshot, log, etc.) stored alongside a bead under
+// <beads_dir>/attachments/<bead_id>/.
+type Attachment struct {
+	BeadID      string    `json:"bead_id"`
+	Name        string    `json:"name"`
+	Path        string    `json:"path"`
+	Size        int64     `json:"size"`
+	ContentType string    `json:"content_type"`
+	ModTime     time.Time `json:"mod_time"`
+}
+
+// AttachmentsDir returns the directory holding attachments for a bead.
+func AttachmentsDir(beadsDir, beadID string) (string, error) {
+	id := strings.TrimSpace(beadID)
+	if id == "" || id == "." || id == ".." || strings.ContainsAny(id, `/\`) {
+		return "", fmt.Errorf("invalid bead id %q", beadID)
+	}
+	return filepath.Join(beadsDir, "attachments", id), nil
+}
+
+// SanitizeAttachmentName reduces a user-supplied file name to a safe base name.
+func SanitizeAttachmentName(name string) (string, error) {
+	base := path.Base(strings.ReplaceAll(strings.TrimSpace(name), `\`, "/"))
+	base = strings.TrimLeft(base, ".")
+	var b strings.Builder
+	for _, r := range base {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('_')
+		}
+	}
+	clean := b.String()
+	if clean == "" {
+		return "", fmt.Errorf("invalid attachment name %q", name)
+	}
+	return clean, nil
+}
+
+// SaveAttachment writes r to the bead's attachment directory. Name collisions
+// get a numeric suffix rather than overwriting an existing attachment.
+func SaveAttachment(beadsDir, beadID, name string, r io.Reader) (*Attachment, error) {
+	dir, err := AttachmentsDir(beadsDir, beadID)
+	if err != nil {
+		return nil, err
+	}
+	clean, err := SanitizeAttachmentName(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating attachment dir: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("creating attachment temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	n, err := io.Copy(tmp, io.LimitReader(r, MaxAttachmentBytes+1))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("writing attachment %s: %w", clean, err)
+	}
+	if n > MaxAttachmentBytes {
+		return nil, fmt.Errorf("attachment %s exceeds %d bytes", clean, MaxAttachmentBytes)
+	}
+
+	dest := uniqueAttachmentPath(dir, clean)
+	if err := os.Rename(tmpPath, dest); err != nil {
+		return nil, fmt.Errorf("storing attachment %s: %w", clean, err)
+	}
+	return statAttachment(beadID, dest)
+}
+
+func uniqueAttachmentPath(dir, name string) string {
+	dest := filepath.Join(dir, name)
+	if _, err := os.Stat(dest); errors.Is(err, os.ErrNotExist) {
+		return dest
+	}
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	for i := 1; ; i++ {
+		candidate := filepath.Join(dir, stem+"-"+strconv.Itoa(i)+ext)
+		if _, err := os.Stat(candidate); errors.Is(err, os.ErrNotExist) {
+			return candidate
+		}
+	}
+}
+
+// ErrAttachmentHostBlocked is returned when an attachment URL points at a
+// loopback, private or link-local address that was not explicitly allowed.
+var ErrAttachmentHostBlocked = errors.New("attachment host not allowed")
+
+// FetchAttachment downloads an attachment from an HTTP(S) URL — a Matrix
+// media download or an object storage link — into the bead's attachment dir.
+// URLs come from chat users, so connections to internal addresses are refused
+// unless the host is listed in allowedHosts (e.g. the configured homeserver).
+func FetchAttachment(ctx context.Context, client *http.Client, beadsDir, beadID, name, rawURL string, allowedHosts []string) (*Attachment, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return nil, fmt.Errorf("unsupported attachment url %q", rawURL)
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 2 * time.Minute}
+	}
+	client, err = guardedClient(client, allowedHosts)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("building attachment request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching attachment %s: %w", u.Redacted(), err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching attachment %s: status %d", u.Redacted(), resp.StatusCode)
+	}
+	if resp.ContentLength > MaxAttachmentBytes {
+		return nil, fmt.Errorf("attachment %s exceeds %d bytes", u.Redacted(), MaxAttachmentBytes)
+	}
+
+	if strings.TrimSpace(name) == "" {
+		name = path.Base(u.Path)
+	}
+	if filepath.Ext(name) == "" {
+		if exts, _ := mime.ExtensionsByType(resp.Header.Get("Content-Type")); len(exts) > 0 {
+			name += exts[0]
+		}
+	}
+	return SaveAttachment(beadsDir, beadID, name, resp.Body)
+}
+
+// guardedClient returns a copy of client that refuses to connect to internal
+// addresses unless the dialed host is allowed. The check runs on the resolved
+// IP at connect time, so it also covers redirects and DNS rebinding.
+func guardedClient(client *http.Client, allowedHosts []string) (*http.Client, error) {
+	var transport *http.Transport
+	switch rt := client.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = rt.Clone()
+	default:
+		return nil, fmt.Errorf("attachment client transport %T cannot be guarded", rt)
+	}
+
+	allowed := make(map[string]bool, len(allowedHosts))
+	for _, host := range allowedHosts {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if host != "" {
+			allowed[host] = true
+		}
+	}
+
+	// A proxy would make the dialed address the proxy's, hiding the target.
+	transport.Proxy = nil
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		if allowed[strings.ToLower(host)] {
+			return dialer.DialContext(ctx, network, addr)
+		}
+		guarded := *dialer
+		guarded.Control = func(_, address string, _ syscall.RawConn) error {
+			ipText, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(ipText); ip == nil || isInternalIP(ip) {
+				return fmt.Errorf("%w: %s resolves to %s", ErrAttachmentHostBlocked, host, ipText)
+			}
+			return nil
+		}
+		return guarded.DialContext(ctx, network, addr)
+	}
+
+	guardedCopy := *client
+	guardedCopy.Transport = transport
+	return &guardedCopy, nil
+}
+
+func isInternalIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast()
+}
+
+// ListAttachments returns a bead's attachments sorted by name. A bead without
+// attachments yields an empty list, not an error.
+func ListAttachments(beadsDir, beadID string) ([]Attachment, error) {
+	dir, err := AttachmentsDir(beadsDir, beadID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("listing attachments for %s: %w", beadID, err)
+	}
+
+	var out []Attachment
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		att, err := statAttachment(beadID, filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		out = append(out, *att)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// GetAttachment resolves a single attachment by name.
+func GetAttachment(beadsDir, beadID, name string) (*Attachment, error) {
+	dir, err := AttachmentsDir(beadsDir, beadID)
+	if err != nil {
+		return nil, err
+	}
+	clean, err := SanitizeAttachmentName(name)
+	if err != nil || clean != name {
+		return nil, ErrAttachmentNotFound
+	}
+	att, err := statAttachment(beadID, filepath.Join(dir, clean))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrAttachmentNotFound
+	}
+	return att, err
+}
+
+func statAttachment(beadID, p string) (*Attachment, error) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, ErrAttachmentNotFound
+	}
+	contentType := mime.TypeByExtension(filepath.Ext(p))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return &Attachment{
+		BeadID:      beadID,
+		Name:        filepath.Base(p),
+		Path:        p,
+		Size:        info.Size(),
+		ContentType: contentType,
+		ModTime:     info.ModTime().UTC(),
+	}, nil
+}
+
+// AttachmentsPromptSection renders attachments as a prompt block pointing the
+// agent at local files. Returns "" when there are none.
+func AttachmentsPromptSection(atts []Attachment) string {
+	if len(atts) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("ATTACHMENTS (local files provided with this task; open them as needed):\n")
+	for _, att := range atts {
+		fmt.Fprintf(&sb, "- %s (%s, %d bytes)\n", att.Path, att.ContentType, att.Size)
+	}
+	return sb.String()
+}
@@ -0,0 +1,116 @@
+package beads
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSaveAndListAttachments(t *testing.T) {
+	beadsDir := t.TempDir()
+
+	first, err := SaveAttachment(beadsDir, "cortex-1", "../../etc/crash log.png", strings.NewReader("one"))
+	if err != nil {
+		t.Fatalf("SaveAttachment: %v", err)
+	}
+	if first.Name != "crash_log.png" || first.ContentType != "image/png" || first.Size != 3 {
+		t.Fatalf("unexpected attachment: %+v", first)
+	}
+	second, err := SaveAttachment(beadsDir, "cortex-1", "crash log.png", strings.NewReader("two"))
+	if err != nil {
+		t.Fatalf("SaveAttachment duplicate: %v", err)
+	}
+	if second.Name != "crash_log-1.png" {
+		t.Fatalf("duplicate name should get suffix, got %q", second.Name)
+	}
+
+	atts, err := ListAttachments(beadsDir, "cortex-1")
+	if err != nil {
+		t.Fatalf("ListAttachments: %v", err)
+	}
+	if len(atts) != 2 || atts[0].Name != "crash_log-1.png" || atts[1].Name != "crash_log.png" {
+		t.Fatalf("unexpected list: %+v", atts)
+	}
+
+	none, err := ListAttachments(beadsDir, "cortex-2")
+	if err != nil || len(none) != 0 {
+		t.Fatalf("expected empty list for bead without attachments, got %v, %v", none, err)
+	}
+
+	if _, err := GetAttachment(beadsDir, "cortex-1", "../crash_log.png"); err != ErrAttachmentNotFound {
+		t.Fatalf("expected not found for traversal name, got %v", err)
+	}
+	if _, err := AttachmentsDir(beadsDir, "../x"); err == nil {
+		t.Fatal("expected invalid bead id error")
+	}
+
+	section := AttachmentsPromptSection(atts)
+	if !strings.Contains(section, atts[1].Path) || !strings.HasPrefix(section, "ATTACHMENTS") {
+		t.Fatalf("unexpected prompt section: %q", section)
+	}
+	if AttachmentsPromptSection(nil) != "" {
+		t.Fatal("expected empty prompt section for no attachments")
+	}
+}
+
+func TestSaveAttachmentRejectsOversized(t *testing.T) {
+	big := strings.NewReader(strings.Repeat("x", int(MaxAttachmentBytes)+1))
+	if _, err := SaveAttachment(t.TempDir(), "cortex-1", "big.log", big); err == nil {
+		t.Fatal("expected size limit error")
+	}
+}
+
+func TestFetchAttachmentInfersExtension(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte("panic: boom"))
+	}))
+	defer srv.Close()
+
+	beadsDir := t.TempDir()
+	att, err := FetchAttachment(context.Background(), srv.Client(), beadsDir, "cortex-1", "", srv.URL+"/media/trace", []string{"127.0.0.1"})
+	if err != nil {
+		t.Fatalf("FetchAttachment: %v", err)
+	}
+	if !strings.HasPrefix(att.Name, "trace.") {
+		t.Fatalf("expected inferred extension, got %q", att.Name)
+	}
+	data, err := os.ReadFile(att.Path)
+	if err != nil || string(data) != "panic: boom" {
+		t.Fatalf("unexpected content %q, %v", data, err)
+	}
+
+	if _, err := FetchAttachment(context.Background(), nil, beadsDir, "cortex-1", "x", "file:///etc/passwd", nil); err == nil {
+		t.Fatal("expected non-http url to be rejected")
+	}
+}
+
+func TestFetchAttachmentBlocksInternalHosts(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		_, _ = w.Write([]byte("metadata"))
+	}))
+	defer srv.Close()
+
+	beadsDir := t.TempDir()
+	_, err := FetchAttachment(context.Background(), srv.Client(), beadsDir, "cortex-1", "x.txt", srv.URL+"/latest/meta-data", nil)
+	if !errors.Is(err, ErrAttachmentHostBlocked) {
+		t.Fatalf("expected loopback url to be blocked, got %v", err)
+	}
+	if hits != 0 {
+		t.Fatalf("blocked fetch reached the server %d time(s)", hits)
+	}
+
+	redirect := httptest.NewServer(http.RedirectHandler(srv.URL+"/latest/meta-data", http.StatusFound))
+	defer redirect.Close()
+	viaName := strings.Replace(redirect.URL, "127.0.0.1", "localhost", 1)
+	_, err = FetchAttachment(context.Background(), nil, beadsDir, "cortex-1", "x.txt", viaName, []string{"localhost"})
+	if !errors.Is(err, ErrAttachmentHostBlocked) {
+		t.Fatalf("expected redirect to an internal host to be blocked, got %v", err)
+	}
+}
@@ -1,18 +1,18 @@
 package beads
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
 	"os/exec"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/antigravity-dev/cortex/internal/cmdexec"
 )
 
 // BeadDependency represents a dependency relationship from bd list --json.
@@ -38,8 +38,11 @@ type Bead struct {
 	Dependencies    []BeadDependency `json:"dependencies"`
 	Acceptance      string           `json:"acceptance_criteria"`
 	Design          string           `json:"design"`
+	ExternalRef     string           `json:"external_ref"`
 	CreatedAt       time.Time        `json:"created_at"`
 	UpdatedAt       time.Time        `json:"updated_at"`
+
+	detailed bool // read with its detail fields, so enrichment has nothing to add
 }
 
 // BeadDetail holds the full output of bd show --json.
@@ -70,24 +73,37 @@ func projectRoot(beadsDir string) string {
 	return filepath.Dir(beadsDir)
 }
 
+const (
+	// bdTimeout bounds a single bd invocation; a wedged bd must not hold up
+	// the scheduler tick.
+	bdTimeout = time.Minute
+	// bdMaxOutput leaves room for bd list --json on large projects.
+	bdMaxOutput = 64 << 20
+)
+
+// readOnlyBDCommands leave the bead list as it was.
+var readOnlyBDCommands = map[string]bool{"list": true, "show": true}
+
 func runBD(ctx context.Context, projectDir string, args ...string) ([]byte, error) {
-	path, err := exec.LookPath("bd")
-	if err != nil {
+	if _, err := exec.LookPath("bd"); err != nil {
 		return nil, fmt.Errorf("bd CLI not found in PATH: %w", err)
 	}
 
-	cmd := exec.CommandContext(ctx, path, args...)
-	cmd.Dir = projectDir
-	cmd.Env = append(os.Environ(), "BEADS_NO_DAEMON=1")
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("bd %v failed: %w\nstderr: %s", args, err, stderr.String())
+	res, err := cmdexec.Run(ctx, cmdexec.Cmd{
+		Name:      "bd",
+		Args:      args,
+		Dir:       projectDir,
+		Env:       []string{"BEADS_NO_DAEMON=1"},
+		Timeout:   bdTimeout,
+		MaxOutput: bdMaxOutput,
+	})
+	if len(args) > 0 && !readOnlyBDCommands[args[0]] {
+		invalidateList(projectDir)
 	}
-	return stdout.Bytes(), nil
+	if err != nil {
+		return nil, fmt.Errorf("bd %v failed: %w", args, err)
+	}
+	return res.Stdout, nil
 }
 
 // CreateIssue creates a new bead issue and returns its issue ID.
@@ -121,6 +137,58 @@ func CreateIssueCtx(ctx context.Context, beadsDir, title, issueType string, prio
 	return issueID, nil
 }
 
+// IssueOptions describes a bead to create with optional metadata beyond the
+// basic title/type/priority/description.
+type IssueOptions struct {
+	Title           string
+	Type            string
+	Priority        int
+	Description     string
+	Acceptance      string
+	Labels          []string
+	EstimateMinutes int
+	ExternalRef     string // e.g. "gh-123", links the bead to its source issue
+	Deps            []string
+}
+
+// CreateIssueWithOptionsCtx creates a bead with labels, estimate, acceptance
+// criteria and an external reference, returning its issue ID.
+func CreateIssueWithOptionsCtx(ctx context.Context, beadsDir string, opts IssueOptions) (string, error) {
+	args := []string{
+		"create",
+		"--type", opts.Type,
+		"--priority", strconv.Itoa(opts.Priority),
+		"--title", opts.Title,
+		"--description", opts.Description,
+		"--silent",
+	}
+	if opts.Acceptance != "" {
+		args = append(args, "--acceptance", opts.Acceptance)
+	}
+	if len(opts.Labels) > 0 {
+		args = append(args, "--labels", strings.Join(opts.Labels, ","))
+	}
+	if opts.EstimateMinutes > 0 {
+		args = append(args, "--estimate", strconv.Itoa(opts.EstimateMinutes))
+	}
+	if opts.ExternalRef != "" {
+		args = append(args, "--external-ref", opts.ExternalRef)
+	}
+	if len(opts.Deps) > 0 {
+		args = append(args, "--deps", strings.Join(opts.Deps, ","))
+	}
+
+	out, err := runBD(ctx, projectRoot(beadsDir), args...)
+	if err != nil {
+		return "", fmt.Errorf("creating bead issue %q: %w", opts.Title, err)
+	}
+	issueID := strings.TrimSpace(string(out))
+	if issueID == "" {
+		return "", fmt.Errorf("creating bead issue %q returned empty id", opts.Title)
+	}
+	return issueID, nil
+}
+
 // UpdatePriority updates a bead priority.
 func UpdatePriority(beadsDir, beadID string, priority int) error {
 	return UpdatePriorityCtx(context.Background(), beadsDir, beadID, priority)
@@ -148,13 +216,69 @@ func UpdatePriorityCtx(ctx context.Context, beadsDir, beadID string, priority in
 	return nil
 }
 
-// ListBeads runs bd list --json --quiet in the project root and returns parsed beads.
+// SetLabelsCtx replaces a bead's labels via bd update --set-labels.
+func SetLabelsCtx(ctx context.Context, beadsDir, beadID string, labels []string) error {
+	beadsDir = strings.TrimSpace(beadsDir)
+	beadID = strings.TrimSpace(beadID)
+	if beadsDir == "" {
+		return fmt.Errorf("project beads dir is required")
+	}
+	if beadID == "" {
+		return fmt.Errorf("bead id is required")
+	}
+
+	root := projectRoot(beadsDir)
+	_, err := runBD(ctx, root, "update", beadID, "--set-labels", strings.Join(labels, ","), "--silent")
+	if err != nil {
+		return fmt.Errorf("updating labels for %s: %w", beadID, err)
+	}
+	return nil
+}
+
+// WithStageLabel returns labels with any stage:* label replaced by
+// stage:<stage>.
+func WithStageLabel(labels []string, stage string) []string {
+	out := make([]string, 0, len(labels)+1)
+	for _, label := range labels {
+		if !strings.HasPrefix(label, "stage:") {
+			out = append(out, label)
+		}
+	}
+	return append(out, "stage:"+stage)
+}
+
+// ListBeads returns the project's beads. They are read from the JSONL export
+// when it is current, and otherwise from bd list --json --quiet in the
+// project root. An export newer than bd's database is imported first, so bd
+// commands act on the beads the caller saw. Repeated calls are served from
+// memory until the export or bd's database changes.
 func ListBeads(beadsDir string) ([]Bead, error) {
 	return ListBeadsCtx(context.Background(), beadsDir)
 }
 
 // ListBeadsCtx is the context-aware version of ListBeads.
 func ListBeadsCtx(ctx context.Context, beadsDir string) ([]Bead, error) {
+	// A failed import is retried on the next read; the export is still
+	// readable, and bd list recovers an out-of-sync database itself.
+	_, _ = importStale(ctx, beadsDir)
+	root := projectRoot(beadsDir)
+	fp := fingerprintOf(beadsDir)
+	if list, ok := cachedList(root, fp); ok {
+		return list, nil
+	}
+	list, err := listBeadsUncached(ctx, beadsDir, fp)
+	if err != nil {
+		return nil, err
+	}
+	storeList(root, fp, list)
+	return list, nil
+}
+
+func listBeadsUncached(ctx context.Context, beadsDir string, fp listFingerprint) ([]Bead, error) {
+	if list, err := readIssuesJSONL(beadsDir, fp); err == nil {
+		return list, nil
+	}
+
 	root := projectRoot(beadsDir)
 	out, err := runBDList(ctx, root)
 	if err != nil && isOutOfSyncListError(err) {
@@ -266,26 +390,73 @@ func ShowBeadCtx(ctx context.Context, beadsDir, beadID string) (*BeadDetail, err
 // Errors on individual beads are logged and skipped.
 func EnrichBeads(ctx context.Context, beadsDir string, beadList []Bead) {
 	for i := range beadList {
-		b := &beadList[i]
-		if b.Status != "open" || b.Type == "epic" {
-			continue
-		}
-		// Skip if already has the detail fields (e.g. from a richer API)
-		if b.Acceptance != "" || b.Design != "" || b.EstimateMinutes != 0 {
-			continue
-		}
-		detail, err := ShowBeadCtx(ctx, beadsDir, b.ID)
-		if err != nil {
-			continue // best-effort enrichment
+		enrichBead(ctx, beadsDir, &beadList[i])
+	}
+}
+
+// Detailed reports whether the bead's detail fields (acceptance criteria,
+// design, estimate) are known: it was read from the JSONL export or has been
+// enriched. Empty detail fields on other beads say nothing.
+func (b Bead) Detailed() bool { return b.detailed }
+
+// SortCandidates orders beads for dispatch: by priority, then stage-labeled
+// beads first, then by estimate. Sort again once enrichment has filled in
+// estimates.
+func SortCandidates(list []Bead) { sortByPriorityAndEstimate(list) }
+
+// EnrichCandidates enriches only the working set of candidates a dispatch
+// pass can use. candidates must already be sorted and filtered; the first
+// limit of them are enriched up front and offered to accept in order. If
+// fewer than want are accepted, further candidates are enriched lazily, only
+// as many as are still needed at a time. It returns up to want accepted beads.
+func EnrichCandidates(ctx context.Context, beadsDir string, candidates []Bead, want, limit int, accept func(Bead) bool) []Bead {
+	if want <= 0 {
+		return nil
+	}
+	if limit < want {
+		limit = want
+	}
+
+	var accepted []Bead
+	enriched := 0
+	for i := 0; i < len(candidates) && len(accepted) < want; i++ {
+		if i == enriched {
+			batch := limit
+			if enriched > 0 {
+				batch = want - len(accepted)
+			}
+			end := min(enriched+batch, len(candidates))
+			EnrichBeads(ctx, beadsDir, candidates[enriched:end])
+			enriched = end
 		}
-		b.Acceptance = detail.Acceptance
-		b.Design = detail.Design
-		b.EstimateMinutes = detail.EstimateMinutes
-		// Also backfill labels if bd list omitted them
-		if len(b.Labels) == 0 && len(detail.Labels) > 0 {
-			b.Labels = detail.Labels
+		if accept == nil || accept(candidates[i]) {
+			accepted = append(accepted, candidates[i])
 		}
 	}
+	return accepted
+}
+
+func enrichBead(ctx context.Context, beadsDir string, b *Bead) {
+	if b.detailed || b.Status != "open" || b.Type == "epic" {
+		return
+	}
+	// Skip if already has the detail fields (e.g. from a richer API)
+	if b.Acceptance != "" || b.Design != "" || b.EstimateMinutes != 0 {
+		b.detailed = true
+		return
+	}
+	detail, err := ShowBeadCtx(ctx, beadsDir, b.ID)
+	if err != nil {
+		return // best-effort enrichment
+	}
+	b.detailed = true
+	b.Acceptance = detail.Acceptance
+	b.Design = detail.Design
+	b.EstimateMinutes = detail.EstimateMinutes
+	// Also backfill labels if bd list omitted them
+	if len(b.Labels) == 0 && len(detail.Labels) > 0 {
+		b.Labels = detail.Labels
+	}
 }
 
 // CloseBead runs bd close {beadID} in the project root.
@@ -385,7 +556,8 @@ func BuildDepGraph(beads []Bead) *DepGraph {
 	return g
 }
 
-// FilterUnblockedOpen returns open, non-epic beads whose dependencies are all closed.
+// FilterUnblockedOpen returns open, non-epic beads whose dependencies are all closed,
+// leaving out beads the backlog triage labeled duplicate or stale.
 // Sorted by Priority ASC then EstimateMinutes ASC.
 func FilterUnblockedOpen(beads []Bead, graph *DepGraph) []Bead {
 	var result []Bead
@@ -394,7 +566,7 @@ func FilterUnblockedOpen(beads []Bead, graph *DepGraph) []Bead {
 		if b.Status != "open" {
 			continue
 		}
-		if b.Type == "epic" {
+		if b.Type == "epic" || Triaged(b) {
 			continue
 		}
 		if isBlocked(b, graph) {
@@ -419,11 +591,35 @@ func FilterUnblockedOpen(beads []Bead, graph *DepGraph) []Bead {
 	return result
 }
 
+// FilterBlocked returns non-epic beads that are open with an unfinished
+// dependency, or marked blocked, sorted by Priority ASC.
+func FilterBlocked(beads []Bead, graph *DepGraph) []Bead {
+	var result []Bead
+	for _, b := range beads {
+		if b.Type == "epic" {
+			continue
+		}
+		if b.Status == "blocked" || (b.Status == "open" && isBlocked(b, graph)) {
+			result = append(result, b)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Priority < result[j].Priority })
+	return result
+}
+
 // resolveDependencies populates DependsOn from the Dependencies array
 // returned by bd list --json. Only "blocks" type dependencies are treated
-// as blocking; "parent-child" is informational.
+// as blocking; "parent-child" only fills in a missing ParentID.
 func resolveDependencies(beads []Bead) {
 	for i := range beads {
+		if beads[i].ParentID == "" {
+			for _, dep := range beads[i].Dependencies {
+				if dep.Type == "parent-child" {
+					beads[i].ParentID = dep.DependsOnID
+					break
+				}
+			}
+		}
 		if len(beads[i].DependsOn) > 0 {
 			continue // already populated (e.g. from a flat depends_on field)
 		}
@@ -75,6 +75,21 @@ func TestFilterUnblockedOpen_SomeDepsOpen(t *testing.T) {
 	}
 }
 
+func TestFilterBlocked(t *testing.T) {
+	beads := []Bead{
+		{ID: "a", Title: "Task A", Status: "open"},
+		{ID: "b", Title: "Task B", Status: "open", DependsOn: []string{"a"}, Priority: 2},
+		{ID: "c", Title: "Task C", Status: "blocked", Priority: 1},
+		{ID: "d", Title: "Task D", Status: "closed", DependsOn: []string{"a"}},
+		{ID: "e", Title: "Epic", Status: "open", Type: "epic", DependsOn: []string{"a"}},
+	}
+
+	result := FilterBlocked(beads, BuildDepGraph(beads))
+	if len(result) != 2 || result[0].ID != "c" || result[1].ID != "b" {
+		t.Fatalf("expected blocked beads c, b; got %+v", result)
+	}
+}
+
 func TestFilterUnblockedOpen_ExcludesEpics(t *testing.T) {
 	beads := []Bead{
 		{ID: "e1", Title: "Epic", Status: "open", Type: "epic"},
@@ -235,6 +250,33 @@ func TestReleaseBeadOwnershipCtx(t *testing.T) {
 	}
 }
 
+func TestResolveDependenciesParentChild(t *testing.T) {
+	beads := []Bead{
+		{ID: "t1", Dependencies: []BeadDependency{
+			{IssueID: "t1", DependsOnID: "epic-1", Type: "parent-child"},
+			{IssueID: "t1", DependsOnID: "t0", Type: "blocks"},
+		}},
+		{ID: "t2", ParentID: "epic-2", Dependencies: []BeadDependency{
+			{IssueID: "t2", DependsOnID: "epic-1", Type: "parent-child"},
+		}},
+	}
+
+	resolveDependencies(beads)
+
+	if beads[0].ParentID != "epic-1" {
+		t.Errorf("t1 parent = %q, want epic-1", beads[0].ParentID)
+	}
+	if len(beads[0].DependsOn) != 1 || beads[0].DependsOn[0] != "t0" {
+		t.Errorf("t1 depends on %v, want [t0]", beads[0].DependsOn)
+	}
+	if beads[1].ParentID != "epic-2" {
+		t.Errorf("t2 parent = %q, want the explicit epic-2", beads[1].ParentID)
+	}
+	if len(beads[1].DependsOn) != 0 {
+		t.Errorf("t2 depends on %v, want none", beads[1].DependsOn)
+	}
+}
+
 func TestListBeadsCtxUsesAllFlag(t *testing.T) {
 	projectDir := t.TempDir()
 	beadsDir := filepath.Join(projectDir, ".beads")
@@ -371,6 +413,40 @@ func TestUpdatePriorityCtx(t *testing.T) {
 	}
 }
 
+func TestSetLabelsCtxWithStageLabel(t *testing.T) {
+	projectDir := t.TempDir()
+	beadsDir := filepath.Join(projectDir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0o755); err != nil {
+		t.Fatalf("mkdir beads dir: %v", err)
+	}
+	logPath := filepath.Join(projectDir, "args.log")
+
+	fakeBin := t.TempDir()
+	script := "#!/bin/sh\n" +
+		"echo \"$@\" >> \"$BD_ARGS_LOG\"\n"
+	if err := os.WriteFile(filepath.Join(fakeBin, "bd"), []byte(script), 0o755); err != nil {
+		t.Fatalf("write fake bd: %v", err)
+	}
+	t.Setenv("BD_ARGS_LOG", logPath)
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	labels := WithStageLabel([]string{"backend", "stage:coding", "urgent"}, "review")
+	if err := SetLabelsCtx(context.Background(), beadsDir, "cortex-123", labels); err != nil {
+		t.Fatalf("SetLabelsCtx failed: %v", err)
+	}
+	args, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read args log: %v", err)
+	}
+	if got := string(args); !strings.Contains(got, "update cortex-123 --set-labels backend,urgent,stage:review --silent") {
+		t.Fatalf("unexpected bd args: %q", got)
+	}
+
+	if err := SetLabelsCtx(context.Background(), beadsDir, "", labels); err == nil {
+		t.Fatalf("expected error for empty bead id")
+	}
+}
+
 func TestListBeadsCtxRecoversFromOutOfSyncDatabase(t *testing.T) {
 	projectDir := t.TempDir()
 	beadsDir := filepath.Join(projectDir, ".beads")
@@ -562,3 +638,101 @@ func TestSyncImportCtxFallsBackOnScannerTokenTooLong(t *testing.T) {
 		t.Fatalf("expected fallback sync call, got %q", got)
 	}
 }
+
+func TestCreateIssueWithOptionsCtxPassesMetadata(t *testing.T) {
+	projectDir := t.TempDir()
+	beadsDir := filepath.Join(projectDir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0o755); err != nil {
+		t.Fatalf("mkdir beads dir: %v", err)
+	}
+	logPath := filepath.Join(projectDir, "args.log")
+
+	fakeBin := t.TempDir()
+	script := "#!/bin/sh\n" +
+		"echo \"$@\" >> \"$BD_ARGS_LOG\"\n" +
+		"echo \"cortex-9\"\n"
+	if err := os.WriteFile(filepath.Join(fakeBin, "bd"), []byte(script), 0o755); err != nil {
+		t.Fatalf("write fake bd: %v", err)
+	}
+	t.Setenv("BD_ARGS_LOG", logPath)
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	id, err := CreateIssueWithOptionsCtx(context.Background(), beadsDir, IssueOptions{
+		Title:           "Login fails",
+		Type:            "bug",
+		Priority:        1,
+		Description:     "steps",
+		Labels:          []string{"area:auth", "imported"},
+		EstimateMinutes: 90,
+		ExternalRef:     "gh-12",
+	})
+	if err != nil {
+		t.Fatalf("CreateIssueWithOptionsCtx failed: %v", err)
+	}
+	if id != "cortex-9" {
+		t.Fatalf("id = %q, want cortex-9", id)
+	}
+
+	args, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read args log: %v", err)
+	}
+	got := string(args)
+	for _, want := range []string{"--type bug", "--priority 1", "--labels area:auth,imported", "--estimate 90", "--external-ref gh-12"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("bd args missing %q: %q", want, got)
+		}
+	}
+	if strings.Contains(got, "--acceptance") || strings.Contains(got, "--deps") {
+		t.Fatalf("unexpected optional flags: %q", got)
+	}
+}
+
+func TestEnrichCandidatesLimitsWorkingSet(t *testing.T) {
+	projectDir := t.TempDir()
+	beadsDir := filepath.Join(projectDir, ".beads")
+	logPath := filepath.Join(projectDir, "args.log")
+
+	fakeBin := t.TempDir()
+	script := "#!/bin/sh\n" +
+		"echo \"$@\" >> \"$BD_ARGS_LOG\"\n" +
+		"echo \"{\\\"id\\\":\\\"$3\\\",\\\"acceptance_criteria\\\":\\\"ac-$3\\\"}\"\n"
+	if err := os.WriteFile(filepath.Join(fakeBin, "bd"), []byte(script), 0o755); err != nil {
+		t.Fatalf("write fake bd: %v", err)
+	}
+	t.Setenv("BD_ARGS_LOG", logPath)
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	candidates := func() []Bead {
+		var out []Bead
+		for _, id := range []string{"b-1", "b-2", "b-3", "b-4", "b-5", "b-6", "b-7", "b-8"} {
+			out = append(out, Bead{ID: id, Status: "open", Type: "task"})
+		}
+		return out
+	}
+	shown := func() []string {
+		data, _ := os.ReadFile(logPath)
+		os.Remove(logPath)
+		return strings.Fields(strings.ReplaceAll(string(data), "show --json ", ""))
+	}
+
+	got := EnrichCandidates(context.Background(), beadsDir, candidates(), 2, 4, nil)
+	if len(got) != 2 || got[0].ID != "b-1" || got[1].Acceptance != "ac-b-2" {
+		t.Fatalf("unexpected accepted beads %+v", got)
+	}
+	if s := shown(); strings.Join(s, ",") != "b-1,b-2,b-3,b-4" {
+		t.Fatalf("expected only the working set to be enriched, got %v", s)
+	}
+
+	// Rejections beyond the working set enrich more candidates lazily.
+	rejected := map[string]bool{"b-1": true, "b-2": true, "b-3": true, "b-5": true}
+	got = EnrichCandidates(context.Background(), beadsDir, candidates(), 2, 3, func(b Bead) bool {
+		return b.Acceptance != "" && !rejected[b.ID]
+	})
+	if len(got) != 2 || got[0].ID != "b-4" || got[1].ID != "b-6" {
+		t.Fatalf("unexpected accepted beads %+v", got)
+	}
+	if s := shown(); strings.Join(s, ",") != "b-1,b-2,b-3,b-4,b-5,b-6" {
+		t.Fatalf("expected lazy enrichment up to b-6, got %v", s)
+	}
+}
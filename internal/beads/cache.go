@@ -0,0 +1,100 @@
+package beads
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// listFingerprint identifies one state of a project's beads on disk. A bead
+// list read at one fingerprint stays valid until the fingerprint changes.
+type listFingerprint struct {
+	jsonlModTime time.Time
+	jsonlSize    int64
+	dbModTime    time.Time
+}
+
+func fingerprintOf(beadsDir string) listFingerprint {
+	fp := listFingerprint{dbModTime: dbModTime(beadsDir)}
+	if info, err := os.Stat(filepath.Join(beadsDir, IssuesFile)); err == nil {
+		fp.jsonlModTime, fp.jsonlSize = info.ModTime(), info.Size()
+	}
+	return fp
+}
+
+// cacheable reports whether fp says anything about the beads' state; with
+// neither an export nor a database there is nothing to invalidate on.
+func (fp listFingerprint) cacheable() bool {
+	return !fp.jsonlModTime.IsZero() || !fp.dbModTime.IsZero()
+}
+
+// CacheStats counts bead list reads served from the cache and read from
+// disk or bd since the process started.
+type CacheStats struct {
+	Hits   int64
+	Misses int64
+}
+
+type listCacheEntry struct {
+	fp    listFingerprint
+	beads []Bead
+}
+
+var (
+	listCacheMu sync.Mutex
+	listCache   = map[string]listCacheEntry{} // by project root
+	cacheStats  CacheStats
+)
+
+// ListCacheStats returns the bead list cache counters.
+func ListCacheStats() CacheStats {
+	listCacheMu.Lock()
+	defer listCacheMu.Unlock()
+	return cacheStats
+}
+
+// cachedList returns a copy of the beads last listed for root, if they were
+// read at fp.
+func cachedList(root string, fp listFingerprint) ([]Bead, bool) {
+	listCacheMu.Lock()
+	defer listCacheMu.Unlock()
+	entry, ok := listCache[root]
+	if !ok || !fp.cacheable() || entry.fp != fp {
+		cacheStats.Misses++
+		return nil, false
+	}
+	cacheStats.Hits++
+	return copyBeads(entry.beads), true
+}
+
+// storeList caches list as root's beads at fp, which must have been taken
+// before list was read so that a write racing the read invalidates it.
+func storeList(root string, fp listFingerprint, list []Bead) {
+	if !fp.cacheable() {
+		return
+	}
+	listCacheMu.Lock()
+	defer listCacheMu.Unlock()
+	listCache[root] = listCacheEntry{fp: fp, beads: copyBeads(list)}
+}
+
+// invalidateList drops root's cached beads after cortex changed them, in
+// case the write landed within the file system's timestamp granularity.
+func invalidateList(root string) {
+	listCacheMu.Lock()
+	defer listCacheMu.Unlock()
+	delete(listCache, root)
+}
+
+// copyBeads deep-copies the slices callers are known to modify in place.
+func copyBeads(src []Bead) []Bead {
+	out := make([]Bead, len(src))
+	for i, b := range src {
+		b.Labels = append([]string(nil), b.Labels...)
+		b.DependsOn = append([]string(nil), b.DependsOn...)
+		b.Dependencies = append([]BeadDependency(nil), b.Dependencies...)
+		out[i] = b
+	}
+	return out
+}
@@ -0,0 +1,71 @@
+package beads
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestListBeadsCtxCachesUntilChanged(t *testing.T) {
+	projectDir := t.TempDir()
+	beadsDir := filepath.Join(projectDir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0o755); err != nil {
+		t.Fatalf("mkdir beads dir: %v", err)
+	}
+	// A database without an export: lists come from bd and are cached on
+	// the database's modification time.
+	db := filepath.Join(beadsDir, "beads.db")
+	if err := os.WriteFile(db, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	logPath := filepath.Join(projectDir, "args.log")
+
+	fakeBin := t.TempDir()
+	script := "#!/bin/sh\n" +
+		"echo \"$1\" >> \"$BD_ARGS_LOG\"\n" +
+		"echo '[{\"id\":\"p-1\",\"status\":\"open\",\"labels\":[\"a\"]}]'\n"
+	if err := os.WriteFile(filepath.Join(fakeBin, "bd"), []byte(script), 0o755); err != nil {
+		t.Fatalf("write fake bd: %v", err)
+	}
+	t.Setenv("BD_ARGS_LOG", logPath)
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	lists := func() int {
+		data, _ := os.ReadFile(logPath)
+		return strings.Count(string(data), "list")
+	}
+
+	before := ListCacheStats()
+	first, err := ListBeadsCtx(context.Background(), beadsDir)
+	if err != nil {
+		t.Fatalf("ListBeadsCtx: %v", err)
+	}
+	first[0].Labels[0] = "mutated"
+	second, err := ListBeadsCtx(context.Background(), beadsDir)
+	if err != nil {
+		t.Fatalf("ListBeadsCtx: %v", err)
+	}
+	if lists() != 1 {
+		t.Fatalf("expected one bd list, got %d", lists())
+	}
+	if second[0].Labels[0] != "a" {
+		t.Fatalf("cached list was modified through a returned copy: %+v", second)
+	}
+	if after := ListCacheStats(); after.Hits != before.Hits+1 || after.Misses != before.Misses+1 {
+		t.Fatalf("stats before %+v after %+v", before, after)
+	}
+
+	// A write through cortex invalidates the cache even if the database's
+	// timestamp did not move.
+	if err := UpdatePriorityCtx(context.Background(), beadsDir, "p-1", 1); err != nil {
+		t.Fatalf("UpdatePriorityCtx: %v", err)
+	}
+	if _, err := ListBeadsCtx(context.Background(), beadsDir); err != nil {
+		t.Fatalf("ListBeadsCtx: %v", err)
+	}
+	if lists() != 2 {
+		t.Fatalf("expected a fresh bd list after a write, got %d lists", lists())
+	}
+}
@@ -29,11 +29,21 @@ type CrossProjectGraph struct {
 }
 
 // BuildCrossProjectGraph scans all enabled projects and builds unified dep graph.
+// Projects whose bd database is behind their export are imported in
+// parallel first.
 func BuildCrossProjectGraph(ctx context.Context, projects map[string]config.Project) (*CrossProjectGraph, error) {
 	g := &CrossProjectGraph{
 		Projects: make(map[string]map[string]*Bead),
 	}
 
+	var dirs []string
+	for _, proj := range projects {
+		if proj.Enabled {
+			dirs = append(dirs, config.ExpandHome(proj.BeadsDir))
+		}
+	}
+	SyncImportsCtx(ctx, dirs, DefaultSyncWorkers)
+
 	for name, proj := range projects {
 		if !proj.Enabled {
 			continue
@@ -91,7 +101,7 @@ func FilterUnblockedCrossProject(beadList []Bead, localGraph *DepGraph, crossGra
 		if b.Status != "open" {
 			continue
 		}
-		if b.Type == "epic" {
+		if b.Type == "epic" || Triaged(b) {
 			continue
 		}
 
@@ -0,0 +1,82 @@
+package beads
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// IssuesFile is the JSONL export bd keeps beside its database in the beads
+// directory. bd flushes it after every write, and git syncs it between
+// checkouts.
+const IssuesFile = "issues.jsonl"
+
+// errJSONLStale means the bd database was written after the JSONL export,
+// so the export may miss changes that bd list would show.
+var errJSONLStale = errors.New("issues.jsonl is older than the bd database")
+
+// readIssuesJSONL parses beadsDir's JSONL export as of fp. It fails when
+// the export is missing, unparsable or stale, and the caller falls back to
+// bd list.
+func readIssuesJSONL(beadsDir string, fp listFingerprint) ([]Bead, error) {
+	if fp.jsonlModTime.IsZero() {
+		return nil, os.ErrNotExist
+	}
+	if fp.dbModTime.After(fp.jsonlModTime) {
+		return nil, errJSONLStale
+	}
+	return parseIssuesJSONL(filepath.Join(beadsDir, IssuesFile))
+}
+
+// dbModTime is the last write to bd's SQLite database in beadsDir, counting
+// its WAL, or the zero time when there is none.
+func dbModTime(beadsDir string) time.Time {
+	var latest time.Time
+	for _, pattern := range []string{"*.db", "*.db-wal"} {
+		matches, _ := filepath.Glob(filepath.Join(beadsDir, pattern))
+		for _, m := range matches {
+			if info, err := os.Stat(m); err == nil && info.ModTime().After(latest) {
+				latest = info.ModTime()
+			}
+		}
+	}
+	return latest
+}
+
+func parseIssuesJSONL(path string) ([]Bead, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var list []Bead
+	r := bufio.NewReader(f) // not a Scanner: rows can exceed its token limit
+	for n := 1; ; n++ {
+		line, readErr := r.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var b Bead
+			if err := json.Unmarshal(line, &b); err != nil {
+				return nil, fmt.Errorf("parsing %s line %d: %w", IssuesFile, n, err)
+			}
+			if b.Status != "tombstone" {
+				b.detailed = true
+				list = append(list, b)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, readErr
+		}
+	}
+	resolveDependencies(list)
+	return list, nil
+}
@@ -0,0 +1,98 @@
+package beads
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeIssuesJSONL(t *testing.T, beadsDir, content string, modTime time.Time) {
+	t.Helper()
+	path := filepath.Join(beadsDir, IssuesFile)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write issues.jsonl: %v", err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+}
+
+func TestListBeadsCtxReadsJSONL(t *testing.T) {
+	beadsDir := filepath.Join(t.TempDir(), ".beads")
+	if err := os.MkdirAll(beadsDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// bd only records that it ran: every read must come from the export.
+	called := filepath.Join(t.TempDir(), "bd-called")
+	fakeBin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(fakeBin, "bd"), []byte("#!/bin/sh\ntouch \"$BD_CALLED\"\nexit 1\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BD_CALLED", called)
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	now := time.Now()
+	writeIssuesJSONL(t, beadsDir, `{"id":"p-1","title":"One","status":"open","issue_type":"task","acceptance_criteria":"ac","labels":["stage:ready"]}
+
+{"id":"p-2","title":"Two","status":"open","issue_type":"task","dependencies":[{"issue_id":"p-2","depends_on_id":"p-1","type":"blocks"}]}
+{"id":"p-3","title":"Gone","status":"tombstone"}
+`, now.Add(-time.Minute))
+
+	list, err := ListBeadsCtx(context.Background(), beadsDir)
+	if err != nil {
+		t.Fatalf("ListBeadsCtx: %v", err)
+	}
+	if len(list) != 2 || list[0].Acceptance != "ac" || len(list[1].DependsOn) != 1 || list[1].DependsOn[0] != "p-1" {
+		t.Fatalf("unexpected beads %+v", list)
+	}
+
+	// Enrichment has nothing to fetch for beads read from the export.
+	EnrichBeads(context.Background(), beadsDir, list)
+	if _, err := os.Stat(called); err == nil {
+		t.Fatal("bd was run for beads read from issues.jsonl")
+	}
+
+	// Callers get their own copy of the cached snapshot.
+	list[0].Labels[0] = "changed"
+	again, err := ListBeadsCtx(context.Background(), beadsDir)
+	if err != nil || again[0].Labels[0] != "stage:ready" {
+		t.Fatalf("cached snapshot was modified: %+v, %v", again, err)
+	}
+
+	// A rewritten export is parsed again.
+	writeIssuesJSONL(t, beadsDir, `{"id":"p-4","title":"Four","status":"closed","issue_type":"bug"}`+"\n", now)
+	list, err = ListBeadsCtx(context.Background(), beadsDir)
+	if err != nil || len(list) != 1 || list[0].ID != "p-4" {
+		t.Fatalf("after rewrite: %+v, %v", list, err)
+	}
+}
+
+func TestListBeadsCtxSkipsStaleJSONL(t *testing.T) {
+	beadsDir := filepath.Join(t.TempDir(), ".beads")
+	if err := os.MkdirAll(beadsDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	writeIssuesJSONL(t, beadsDir, `{"id":"p-old","status":"open"}`+"\n", now.Add(-time.Hour))
+	db := filepath.Join(beadsDir, "beads.db")
+	if err := os.WriteFile(db, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(db, now, now); err != nil {
+		t.Fatal(err)
+	}
+
+	fakeBin := t.TempDir()
+	script := "#!/bin/sh\necho '[{\"id\":\"p-new\",\"status\":\"open\"}]'\n"
+	if err := os.WriteFile(filepath.Join(fakeBin, "bd"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	list, err := ListBeadsCtx(context.Background(), beadsDir)
+	if err != nil || len(list) != 1 || list[0].ID != "p-new" {
+		t.Fatalf("expected bd list when the database is newer: %+v, %v", list, err)
+	}
+}
@@ -0,0 +1,39 @@
+package beads
+
+import "sort"
+
+// FileOverlap is a pair of ready beads whose work touches the same files.
+// First comes earlier in dispatch order; Second is the one to hold back.
+type FileOverlap struct {
+	First  string   `json:"first"`
+	Second string   `json:"second"`
+	Files  []string `json:"files"`
+}
+
+// FindFileOverlaps returns every pair of beads in ready, which is in
+// dispatch order as from FilterUnblockedOpen, that share a file in files.
+func FindFileOverlaps(ready []Bead, files map[string][]string) []FileOverlap {
+	var overlaps []FileOverlap
+	for i := range ready {
+		mine := make(map[string]bool, len(files[ready[i].ID]))
+		for _, f := range files[ready[i].ID] {
+			mine[f] = true
+		}
+		if len(mine) == 0 {
+			continue
+		}
+		for j := i + 1; j < len(ready); j++ {
+			var shared []string
+			for _, f := range files[ready[j].ID] {
+				if mine[f] {
+					shared = append(shared, f)
+				}
+			}
+			if len(shared) > 0 {
+				sort.Strings(shared)
+				overlaps = append(overlaps, FileOverlap{First: ready[i].ID, Second: ready[j].ID, Files: shared})
+			}
+		}
+	}
+	return overlaps
+}
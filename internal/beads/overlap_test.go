@@ -0,0 +1,24 @@
+package beads
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindFileOverlaps(t *testing.T) {
+	ready := []Bead{{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "d"}}
+	files := map[string][]string{
+		"a": {"api.go", "store.go"},
+		"b": {"ui.go"},
+		"c": {"store.go", "api.go", "ui.go"},
+	}
+
+	got := FindFileOverlaps(ready, files)
+	want := []FileOverlap{
+		{First: "a", Second: "c", Files: []string{"api.go", "store.go"}},
+		{First: "b", Second: "c", Files: []string{"ui.go"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("overlaps = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,84 @@
+package beads
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultSyncWorkers bounds SyncImportsCtx when it is given no worker count.
+const DefaultSyncWorkers = 4
+
+// SyncResult is the outcome of importing one project's JSONL export.
+type SyncResult struct {
+	BeadsDir string
+	Skipped  bool // the database is not behind the export
+	Err      error
+}
+
+// exportStamp identifies one version of a JSONL export.
+type exportStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+var (
+	importMu sync.Mutex
+	imported = map[string]exportStamp{} // by beads dir, as of the last successful import
+)
+
+// importStale imports beadsDir's JSONL export into bd's database when the
+// export was written after the database, as after a git pull, and has not
+// been imported at this version yet. It reports whether it ran an import.
+// Without a database or an export there is nothing to import.
+func importStale(ctx context.Context, beadsDir string) (bool, error) {
+	fp := fingerprintOf(beadsDir)
+	if fp.jsonlModTime.IsZero() || fp.dbModTime.IsZero() || !fp.jsonlModTime.After(fp.dbModTime) {
+		return false, nil
+	}
+	stamp := exportStamp{modTime: fp.jsonlModTime, size: fp.jsonlSize}
+	importMu.Lock()
+	last, ok := imported[beadsDir]
+	importMu.Unlock()
+	if ok && last == stamp {
+		return false, nil
+	}
+	if err := SyncImportCtx(ctx, beadsDir); err != nil {
+		return true, err
+	}
+	importMu.Lock()
+	imported[beadsDir] = stamp
+	importMu.Unlock()
+	invalidateList(projectRoot(beadsDir))
+	return true, nil
+}
+
+// SyncImportsCtx brings each beads dir's database up to its JSONL export,
+// importing only the dirs whose database is behind and running at most
+// workers imports at once. Results are in the order of beadsDirs.
+func SyncImportsCtx(ctx context.Context, beadsDirs []string, workers int) []SyncResult {
+	if workers <= 0 {
+		workers = DefaultSyncWorkers
+	}
+	results := make([]SyncResult, len(beadsDirs))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, dir := range beadsDirs {
+		results[i].BeadsDir = dir
+		wg.Add(1)
+		go func(r *SyncResult) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				r.Err = ctx.Err()
+				return
+			}
+			ran, err := importStale(ctx, r.BeadsDir)
+			r.Skipped, r.Err = !ran, err
+		}(&results[i])
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,83 @@
+package beads
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyncImportsCtxImportsStaleDatabases(t *testing.T) {
+	root := t.TempDir()
+	logPath := filepath.Join(root, "args.log")
+	var dirs []string
+	for _, name := range []string{"a", "b", "c"} {
+		dir := filepath.Join(root, name, ".beads")
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		dirs = append(dirs, dir)
+	}
+	writeDB := func(dir string, modTime time.Time) {
+		path := filepath.Join(dir, "beads.db")
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hourAgo := time.Now().Add(-time.Hour)
+	// a's database is behind its export, b's is current, c has no export.
+	writeDB(dirs[0], hourAgo.Add(-time.Minute))
+	writeIssuesJSONL(t, dirs[0], `{"id":"x-1","status":"open"}`+"\n", hourAgo)
+	writeDB(dirs[1], hourAgo.Add(time.Minute))
+	writeIssuesJSONL(t, dirs[1], `{"id":"x-1","status":"open"}`+"\n", hourAgo)
+	writeDB(dirs[2], hourAgo)
+
+	fakeBin := t.TempDir()
+	script := "#!/bin/sh\necho \"$(pwd) $@\" >> \"$BD_ARGS_LOG\"\n"
+	if err := os.WriteFile(filepath.Join(fakeBin, "bd"), []byte(script), 0o755); err != nil {
+		t.Fatalf("write fake bd: %v", err)
+	}
+	t.Setenv("BD_ARGS_LOG", logPath)
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	imports := func() []string {
+		data, _ := os.ReadFile(logPath)
+		os.Remove(logPath)
+		if len(data) == 0 {
+			return nil
+		}
+		return strings.Split(strings.TrimSpace(string(data)), "\n")
+	}
+
+	results := SyncImportsCtx(context.Background(), dirs, 2)
+	if results[0].Skipped || !results[1].Skipped || !results[2].Skipped || results[0].Err != nil {
+		t.Fatalf("first round: %+v", results)
+	}
+	if got := imports(); len(got) != 1 || !strings.HasPrefix(got[0], filepath.Join(root, "a")+" sync --import-only") {
+		t.Fatalf("first round imports: %q", got)
+	}
+
+	// An export already imported is not imported again, even though the
+	// fake bd left the database untouched.
+	if results := SyncImportsCtx(context.Background(), dirs, 2); !results[0].Skipped {
+		t.Fatalf("second round: %+v", results)
+	}
+	if got := imports(); len(got) != 0 {
+		t.Fatalf("second round imports: %q", got)
+	}
+
+	// Reading beads brings a database that fell behind up to date first.
+	writeIssuesJSONL(t, dirs[1], `{"id":"x-1","status":"closed"}`+"\n", time.Now())
+	list, err := ListBeadsCtx(context.Background(), dirs[1])
+	if err != nil || len(list) != 1 || list[0].Status != "closed" {
+		t.Fatalf("list = %+v, %v", list, err)
+	}
+	if got := imports(); len(got) != 1 || !strings.HasPrefix(got[0], filepath.Join(root, "b")+" sync --import-only") {
+		t.Fatalf("read imports: %q", got)
+	}
+}
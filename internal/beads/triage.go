@@ -0,0 +1,150 @@
+package beads
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Labels the backlog triage puts on beads it takes out of the dispatch pool.
+// Removing the label returns a bead to the pool.
+const (
+	LabelDuplicate = "triage:duplicate"
+	LabelStale     = "triage:stale"
+)
+
+// LabelNeedsTriage marks a bead cortex created on its own, such as a
+// follow-up an agent reported, which waits out of the dispatch pool until a
+// human triages it and removes the label.
+const LabelNeedsTriage = "triage:needed"
+
+// DuplicatePair is a bead that looks like a restatement of an older one.
+type DuplicatePair struct {
+	Original  string  `json:"original"`
+	Duplicate string  `json:"duplicate"`
+	Score     float64 `json:"score"`
+}
+
+// Triaged reports whether b carries a triage label that holds it out of the
+// dispatch pool: duplicate, stale or awaiting triage.
+func Triaged(b Bead) bool {
+	for _, label := range b.Labels {
+		if label == LabelDuplicate || label == LabelStale || label == LabelNeedsTriage {
+			return true
+		}
+	}
+	return false
+}
+
+// Similarity scores how alike two beads read, from 0 to 1: the word overlap
+// (Jaccard) of their titles, blended 70/30 with that of their descriptions
+// when both have one.
+func Similarity(a, b Bead) float64 {
+	title := jaccard(words(a.Title), words(b.Title))
+	da, db := words(a.Description), words(b.Description)
+	if len(da) == 0 || len(db) == 0 {
+		return title
+	}
+	return 0.7*title + 0.3*jaccard(da, db)
+}
+
+// FindDuplicates pairs each open, untriaged, non-epic bead with the older
+// bead it most resembles when their similarity reaches threshold. A bead is
+// reported as a duplicate at most once; the older bead is the original.
+func FindDuplicates(list []Bead, threshold float64) []DuplicatePair {
+	var open []Bead
+	for _, b := range list {
+		if b.Status == "open" && b.Type != "epic" && !Triaged(b) {
+			open = append(open, b)
+		}
+	}
+	sort.Slice(open, func(i, j int) bool {
+		if !open[i].CreatedAt.Equal(open[j].CreatedAt) {
+			return open[i].CreatedAt.Before(open[j].CreatedAt)
+		}
+		return open[i].ID < open[j].ID
+	})
+
+	var pairs []DuplicatePair
+	for i := 1; i < len(open); i++ {
+		best := DuplicatePair{Duplicate: open[i].ID}
+		for j := 0; j < i; j++ {
+			if score := Similarity(open[i], open[j]); score >= threshold && score > best.Score {
+				best.Original, best.Score = open[j].ID, score
+			}
+		}
+		if best.Original != "" {
+			pairs = append(pairs, best)
+		}
+	}
+	return pairs
+}
+
+// FindStale returns the open, untriaged, non-epic beads last updated more
+// than age before now, oldest first.
+func FindStale(list []Bead, age time.Duration, now time.Time) []Bead {
+	var stale []Bead
+	for _, b := range list {
+		if b.Status != "open" || b.Type == "epic" || Triaged(b) || b.UpdatedAt.IsZero() {
+			continue
+		}
+		if now.Sub(b.UpdatedAt) > age {
+			stale = append(stale, b)
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool { return stale[i].UpdatedAt.Before(stale[j].UpdatedAt) })
+	return stale
+}
+
+// AddDiscoveredFromCtx links beadID to the bead it was discovered from.
+func AddDiscoveredFromCtx(ctx context.Context, beadsDir, beadID, fromID string) error {
+	beadsDir = strings.TrimSpace(beadsDir)
+	beadID = strings.TrimSpace(beadID)
+	fromID = strings.TrimSpace(fromID)
+	if beadsDir == "" || beadID == "" || fromID == "" {
+		return fmt.Errorf("beads dir, bead id, and discovered-from id are all required")
+	}
+	root := projectRoot(beadsDir)
+	_, err := runBD(ctx, root, "dep", "add", beadID, fromID, "--type", "discovered-from")
+	if err != nil {
+		return fmt.Errorf("linking %s discovered from %s: %w", beadID, fromID, err)
+	}
+	return nil
+}
+
+// stopwords are common words too short on meaning to count toward
+// similarity.
+var stopwords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "from": true,
+	"into": true, "when": true, "that": true, "this": true, "are": true,
+}
+
+// words returns the distinct lowercase words of s, ignoring stopwords and
+// words shorter than three letters.
+func words(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(w) >= 3 && !stopwords[w] {
+			set[w] = true
+		}
+	}
+	return set
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for w := range a {
+		if b[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
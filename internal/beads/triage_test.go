@@ -0,0 +1,105 @@
+package beads
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSimilarity(t *testing.T) {
+	a := Bead{Title: "Fix login redirect loop"}
+	if got := Similarity(a, Bead{Title: "fix: the login redirect loop!"}); got != 1 {
+		t.Errorf("same words apart from case, punctuation and stopwords = %v, want 1", got)
+	}
+	if got := Similarity(a, Bead{Title: "Add billing export"}); got != 0 {
+		t.Errorf("no shared words = %v, want 0", got)
+	}
+
+	withDesc := Bead{Title: "Fix login redirect loop", Description: "Users bounce between login and home"}
+	other := Bead{Title: "Fix login redirect loop", Description: "Unrelated text entirely"}
+	if got := Similarity(withDesc, other); got != 0.7 {
+		t.Errorf("same title, different description = %v, want 0.7", got)
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	list := []Bead{
+		{ID: "b", Title: "Fix login redirect loop on mobile", Status: "open", CreatedAt: day.Add(48 * time.Hour)},
+		{ID: "a", Title: "Fix login redirect loop", Status: "open", CreatedAt: day},
+		{ID: "c", Title: "Fix login redirect loop", Status: "open", CreatedAt: day.Add(24 * time.Hour)},
+		{ID: "d", Title: "Add billing export", Status: "open", CreatedAt: day},
+		{ID: "e", Title: "Fix login redirect loop", Status: "closed", CreatedAt: day},
+		{ID: "f", Title: "Fix login redirect loop", Status: "open", Labels: []string{LabelDuplicate}, CreatedAt: day},
+	}
+
+	pairs := FindDuplicates(list, 0.75)
+	if len(pairs) != 2 {
+		t.Fatalf("pairs = %+v, want 2", pairs)
+	}
+	if pairs[0] != (DuplicatePair{Original: "a", Duplicate: "c", Score: 1}) {
+		t.Errorf("first pair = %+v, want c duplicating a", pairs[0])
+	}
+	if pairs[1].Original != "a" || pairs[1].Duplicate != "b" || pairs[1].Score != 0.8 {
+		t.Errorf("second pair = %+v, want b duplicating a at 0.8", pairs[1])
+	}
+	if got := FindDuplicates(list, 0.9); len(got) != 1 {
+		t.Errorf("pairs at 0.9 = %+v, want only the exact match", got)
+	}
+}
+
+func TestFindStale(t *testing.T) {
+	now := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	list := []Bead{
+		{ID: "fresh", Status: "open", UpdatedAt: now.Add(-24 * time.Hour)},
+		{ID: "old", Status: "open", UpdatedAt: now.Add(-40 * 24 * time.Hour)},
+		{ID: "older", Status: "open", UpdatedAt: now.Add(-90 * 24 * time.Hour)},
+		{ID: "closed", Status: "closed", UpdatedAt: now.Add(-90 * 24 * time.Hour)},
+		{ID: "epic", Type: "epic", Status: "open", UpdatedAt: now.Add(-90 * 24 * time.Hour)},
+		{ID: "labeled", Status: "open", Labels: []string{LabelStale}, UpdatedAt: now.Add(-90 * 24 * time.Hour)},
+	}
+
+	stale := FindStale(list, 30*24*time.Hour, now)
+	if len(stale) != 2 || stale[0].ID != "older" || stale[1].ID != "old" {
+		t.Fatalf("stale = %+v, want older then old", stale)
+	}
+}
+
+func TestFilterUnblockedOpenSkipsTriaged(t *testing.T) {
+	list := []Bead{
+		{ID: "a", Status: "open"},
+		{ID: "b", Status: "open", Labels: []string{LabelDuplicate}},
+		{ID: "c", Status: "open", Labels: []string{"stage:ready", LabelStale}},
+		{ID: "d", Status: "open", Labels: []string{LabelNeedsTriage}},
+	}
+	got := FilterUnblockedOpen(list, BuildDepGraph(list))
+	if len(got) != 1 || got[0].ID != "a" {
+		t.Fatalf("unblocked = %+v, want only a", got)
+	}
+}
+
+func TestAddDiscoveredFromCtx(t *testing.T) {
+	projectDir := t.TempDir()
+	logPath := filepath.Join(projectDir, "args.log")
+	fakeBin := t.TempDir()
+	script := "#!/bin/sh\necho \"$@\" >> \"$BD_ARGS_LOG\"\n"
+	if err := os.WriteFile(filepath.Join(fakeBin, "bd"), []byte(script), 0o755); err != nil {
+		t.Fatalf("write fake bd: %v", err)
+	}
+	t.Setenv("BD_ARGS_LOG", logPath)
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	if err := AddDiscoveredFromCtx(context.Background(), filepath.Join(projectDir, ".beads"), "b", "a"); err != nil {
+		t.Fatalf("AddDiscoveredFromCtx: %v", err)
+	}
+	args, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read args log: %v", err)
+	}
+	if got := strings.TrimSpace(string(args)); got != "dep add b a --type discovered-from" {
+		t.Fatalf("bd args = %q", got)
+	}
+}
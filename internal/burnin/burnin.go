@@ -0,0 +1,326 @@
+// Package burnin scores dispatch reliability against per-project burn-in SLO
+// gates and stores the results as JSON and Markdown report artifacts.
+package burnin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+// Gate names, in report order.
+const (
+	GateUnknownRate      = "unknown_rate"
+	GateInterventionRate = "intervention_rate"
+	GateCriticalEvents   = "critical_events"
+)
+
+// Trend directions of a gate value against the previous report.
+const (
+	TrendUp   = "up"
+	TrendDown = "down"
+	TrendFlat = "flat"
+)
+
+const artifactPrefix = "burnin-"
+
+// ErrNoReport is returned when the reports directory has no matching report.
+var ErrNoReport = errors.New("burnin: no report")
+
+// Gate is one SLO metric scored against its limit. It passes while Value
+// stays below Limit.
+type Gate struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+	Limit float64 `json:"limit"`
+	Pass  bool    `json:"pass"`
+	Trend string  `json:"trend,omitempty"` // against the previous report; empty when there is none
+}
+
+// Counts are the raw numbers a project's gates are computed from.
+type Counts struct {
+	Dispatches     int `json:"dispatches"`
+	Completed      int `json:"completed"`
+	Failed         int `json:"failed"`
+	Unknown        int `json:"unknown"`
+	Cancelled      int `json:"cancelled"`
+	Retried        int `json:"retried"`
+	CriticalEvents int `json:"critical_events"`
+}
+
+// ProjectReport is one project's burn-in evidence.
+type ProjectReport struct {
+	Project string `json:"project"`
+	Pass    bool   `json:"pass"`
+	Counts  Counts `json:"counts"`
+	Gates   []Gate `json:"gates"`
+}
+
+// Report is a burn-in evidence report across projects.
+type Report struct {
+	GeneratedAt time.Time       `json:"generated_at"`
+	From        time.Time       `json:"from"`
+	To          time.Time       `json:"to"`
+	Pass        bool            `json:"pass"`
+	Projects    []ProjectReport `json:"projects"`
+}
+
+// Date is the report's artifact date, the UTC day its window ends on.
+func (r *Report) Date() string {
+	return r.To.UTC().Format(time.DateOnly)
+}
+
+// Failing lists the projects with at least one failing gate.
+func (r *Report) Failing() []string {
+	var out []string
+	for _, p := range r.Projects {
+		if !p.Pass {
+			out = append(out, p.Project)
+		}
+	}
+	return out
+}
+
+// Generate collects counts for every project in slos over [from, to) and
+// scores them against that project's gates.
+func Generate(st *store.Store, slos map[string]config.BurnInSLO, from, to time.Time) (*Report, error) {
+	names := make([]string, 0, len(slos))
+	for name := range slos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	r := &Report{GeneratedAt: time.Now().UTC(), From: from.UTC(), To: to.UTC(), Pass: true}
+	for _, name := range names {
+		c, err := st.GetBurnInCounts(name, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("burnin: %s: %w", name, err)
+		}
+		p := Score(name, c, slos[name])
+		r.Pass = r.Pass && p.Pass
+		r.Projects = append(r.Projects, p)
+	}
+	return r, nil
+}
+
+// Score computes a project's gates from its counts. Rates are 0 when the
+// project had no dispatches.
+func Score(project string, c store.BurnInCounts, slo config.BurnInSLO) ProjectReport {
+	p := ProjectReport{
+		Project: project,
+		Pass:    true,
+		Counts: Counts{
+			Dispatches:     c.Total,
+			Completed:      c.Completed,
+			Failed:         c.Failed,
+			Unknown:        c.Unknown,
+			Cancelled:      c.Cancelled,
+			Retried:        c.Retried,
+			CriticalEvents: c.CriticalEvents,
+		},
+	}
+	rate := func(n int) float64 {
+		if c.Total == 0 {
+			return 0
+		}
+		return float64(n) / float64(c.Total)
+	}
+	for _, g := range []Gate{
+		{Name: GateUnknownRate, Value: rate(c.Unknown), Limit: slo.UnknownRateLimit},
+		{Name: GateInterventionRate, Value: rate(c.Cancelled + c.Retried), Limit: slo.InterventionRateLimit},
+		{Name: GateCriticalEvents, Value: float64(c.CriticalEvents), Limit: float64(slo.CriticalEventLimit)},
+	} {
+		g.Pass = g.Value < g.Limit
+		p.Pass = p.Pass && g.Pass
+		p.Gates = append(p.Gates, g)
+	}
+	return p
+}
+
+// ApplyTrend sets each gate's trend against the same project and gate in
+// prev. Gates with no counterpart are left without a trend.
+func (r *Report) ApplyTrend(prev *Report) {
+	if prev == nil {
+		return
+	}
+	previous := make(map[string]float64)
+	for _, p := range prev.Projects {
+		for _, g := range p.Gates {
+			previous[p.Project+"/"+g.Name] = g.Value
+		}
+	}
+	for i := range r.Projects {
+		p := &r.Projects[i]
+		for j := range p.Gates {
+			g := &p.Gates[j]
+			old, ok := previous[p.Project+"/"+g.Name]
+			switch {
+			case !ok:
+			case g.Value > old:
+				g.Trend = TrendUp
+			case g.Value < old:
+				g.Trend = TrendDown
+			default:
+				g.Trend = TrendFlat
+			}
+		}
+	}
+}
+
+// Markdown renders the report for humans.
+func (r *Report) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Cortex Burn-in Report - %s\n\n", r.Date())
+	fmt.Fprintf(&b, "Window: %s to %s\n\n", r.From.Format(time.RFC3339), r.To.Format(time.RFC3339))
+	fmt.Fprintf(&b, "## Overall Status: %s\n", passLabel(r.Pass))
+	if len(r.Projects) == 0 {
+		b.WriteString("\nNo projects configured.\n")
+	}
+	for _, p := range r.Projects {
+		fmt.Fprintf(&b, "\n### %s: %s\n\n", p.Project, passLabel(p.Pass))
+		c := p.Counts
+		fmt.Fprintf(&b, "%d dispatches: %d completed, %d failed, %d unknown, %d cancelled, %d retried; %d critical events.\n\n",
+			c.Dispatches, c.Completed, c.Failed, c.Unknown, c.Cancelled, c.Retried, c.CriticalEvents)
+		b.WriteString("| Metric | Current | Threshold | Status | Trend |\n")
+		b.WriteString("|--------|---------|-----------|--------|-------|\n")
+		for _, g := range p.Gates {
+			fmt.Fprintf(&b, "| %s | %s | < %s | %s | %s |\n",
+				gateTitle(g.Name), formatGate(g.Name, g.Value), formatGate(g.Name, g.Limit), passLabel(g.Pass), trendArrow(g.Trend))
+		}
+	}
+	return b.String()
+}
+
+func gateTitle(name string) string {
+	switch name {
+	case GateUnknownRate:
+		return "Unknown/Disappeared Rate"
+	case GateInterventionRate:
+		return "Intervention Rate"
+	case GateCriticalEvents:
+		return "Critical Events"
+	}
+	return name
+}
+
+func formatGate(name string, v float64) string {
+	if name == GateCriticalEvents {
+		return fmt.Sprintf("%d", int(v))
+	}
+	return fmt.Sprintf("%.2f%%", v*100)
+}
+
+func passLabel(pass bool) string {
+	if pass {
+		return "PASS"
+	}
+	return "FAIL"
+}
+
+func trendArrow(trend string) string {
+	switch trend {
+	case TrendUp:
+		return "↗"
+	case TrendDown:
+		return "↘"
+	case TrendFlat:
+		return "→"
+	}
+	return "-"
+}
+
+// Write stores the report as burnin-<date>.json and burnin-<date>.md in dir,
+// replacing any report for the same date.
+func Write(dir string, r *Report) (jsonPath, mdPath string, err error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", "", fmt.Errorf("burnin: create reports dir: %w", err)
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", "", fmt.Errorf("burnin: encode report: %w", err)
+	}
+	base := filepath.Join(dir, artifactPrefix+r.Date())
+	if err := writeFileAtomic(base+".json", append(data, '\n')); err != nil {
+		return "", "", err
+	}
+	if err := writeFileAtomic(base+".md", []byte(r.Markdown())); err != nil {
+		return "", "", err
+	}
+	return base + ".json", base + ".md", nil
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("burnin: write %s: %w", filepath.Base(path), err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("burnin: write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// Load reads the report for date (YYYY-MM-DD) from dir.
+func Load(dir, date string) (*Report, error) {
+	if _, err := time.Parse(time.DateOnly, date); err != nil {
+		return nil, fmt.Errorf("burnin: date must be YYYY-MM-DD")
+	}
+	data, err := os.ReadFile(filepath.Join(dir, artifactPrefix+date+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoReport
+	}
+	if err != nil {
+		return nil, fmt.Errorf("burnin: read report: %w", err)
+	}
+	var r Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("burnin: decode report %s: %w", date, err)
+	}
+	return &r, nil
+}
+
+// Latest reads the most recent report in dir, or returns ErrNoReport.
+func Latest(dir string) (*Report, error) {
+	dates, err := Dates(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(dates) == 0 {
+		return nil, ErrNoReport
+	}
+	return Load(dir, dates[len(dates)-1])
+}
+
+// Dates lists the dates of the reports in dir, oldest first. A missing
+// directory has no reports.
+func Dates(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("burnin: list reports: %w", err)
+	}
+	var dates []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, artifactPrefix) || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		date := strings.TrimSuffix(strings.TrimPrefix(name, artifactPrefix), ".json")
+		if _, err := time.Parse(time.DateOnly, date); err == nil {
+			dates = append(dates, date)
+		}
+	}
+	sort.Strings(dates)
+	return dates, nil
+}
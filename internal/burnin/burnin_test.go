@@ -0,0 +1,153 @@
+package burnin
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+var defaultSLO = config.BurnInSLO{UnknownRateLimit: 0.02, InterventionRateLimit: 0.10, CriticalEventLimit: 2}
+
+func TestScore(t *testing.T) {
+	p := Score("alpha", store.BurnInCounts{Total: 50, Completed: 45, Unknown: 1, Cancelled: 2, Retried: 3, CriticalEvents: 2}, defaultSLO)
+	if p.Pass {
+		t.Fatal("expected project to fail")
+	}
+	got := map[string]Gate{}
+	for _, g := range p.Gates {
+		got[g.Name] = g
+	}
+	if g := got[GateUnknownRate]; g.Value != 0.02 || g.Pass {
+		t.Fatalf("unknown rate at the limit should fail: %+v", g)
+	}
+	if g := got[GateInterventionRate]; g.Value != 0.1 || g.Pass {
+		t.Fatalf("intervention rate at the limit should fail: %+v", g)
+	}
+	if g := got[GateCriticalEvents]; g.Value != 2 || g.Pass {
+		t.Fatalf("critical events at the limit should fail: %+v", g)
+	}
+
+	p = Score("idle", store.BurnInCounts{}, defaultSLO)
+	if !p.Pass || p.Gates[0].Value != 0 {
+		t.Fatalf("a project without dispatches should pass: %+v", p)
+	}
+}
+
+func TestApplyTrend(t *testing.T) {
+	prev := &Report{Projects: []ProjectReport{{Project: "alpha", Gates: []Gate{
+		{Name: GateUnknownRate, Value: 0.01}, {Name: GateInterventionRate, Value: 0.2}, {Name: GateCriticalEvents, Value: 1},
+	}}}}
+	r := &Report{Projects: []ProjectReport{
+		{Project: "alpha", Gates: []Gate{
+			{Name: GateUnknownRate, Value: 0.03}, {Name: GateInterventionRate, Value: 0.1}, {Name: GateCriticalEvents, Value: 1},
+		}},
+		{Project: "beta", Gates: []Gate{{Name: GateUnknownRate, Value: 0.5}}},
+	}}
+	r.ApplyTrend(prev)
+
+	var trends []string
+	for _, g := range r.Projects[0].Gates {
+		trends = append(trends, g.Trend)
+	}
+	if strings.Join(trends, ",") != "up,down,flat" {
+		t.Fatalf("unexpected trends %v", trends)
+	}
+	if r.Projects[1].Gates[0].Trend != "" {
+		t.Fatal("new project should have no trend")
+	}
+}
+
+func TestGenerateWriteAndLoad(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+
+	to := time.Date(2026, 3, 8, 7, 0, 0, 0, time.UTC)
+	from := to.Add(-7 * 24 * time.Hour)
+	for i, status := range []string{"completed", "completed", "interrupted"} {
+		id, err := st.RecordDispatch("b-"+status, "alpha", "agent", "claude", "balanced", i, "", "p", "", "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := st.SetDispatchTime(id, to.Add(-time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+		if err := st.UpdateDispatchStatus(id, status, 0, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	slos := map[string]config.BurnInSLO{"alpha": defaultSLO, "beta": {UnknownRateLimit: 0.5, InterventionRateLimit: 0.5, CriticalEventLimit: 1}}
+	r, err := Generate(st, slos, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Pass || len(r.Projects) != 2 || strings.Join(r.Failing(), ",") != "alpha" {
+		t.Fatalf("expected alpha to fail its unknown-rate gate: %+v", r)
+	}
+	if r.Projects[0].Counts.Dispatches != 3 || r.Projects[0].Counts.Unknown != 1 {
+		t.Fatalf("unexpected counts %+v", r.Projects[0].Counts)
+	}
+
+	dir := filepath.Join(t.TempDir(), "reports")
+	if _, err := Latest(dir); !errors.Is(err, ErrNoReport) {
+		t.Fatalf("expected ErrNoReport for a missing dir, got %v", err)
+	}
+
+	jsonPath, mdPath, err := Write(dir, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(jsonPath) != "burnin-2026-03-08.json" {
+		t.Fatalf("unexpected artifact %s", jsonPath)
+	}
+	md, err := os.ReadFile(mdPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# Cortex Burn-in Report - 2026-03-08",
+		"## Overall Status: FAIL",
+		"### alpha: FAIL",
+		"| Unknown/Disappeared Rate | 33.33% | < 2.00% | FAIL | - |",
+		"| Critical Events | 0 | < 1 | PASS | - |",
+	} {
+		if !strings.Contains(string(md), want) {
+			t.Fatalf("markdown missing %q:\n%s", want, md)
+		}
+	}
+
+	older := &Report{To: to.Add(-24 * time.Hour), Pass: true}
+	if _, _, err := Write(dir, older); err != nil {
+		t.Fatal(err)
+	}
+	dates, err := Dates(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(dates, ",") != "2026-03-07,2026-03-08" {
+		t.Fatalf("unexpected dates %v", dates)
+	}
+
+	latest, err := Latest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest.Date() != "2026-03-08" || latest.Pass || latest.Projects[0].Gates[0].Value != r.Projects[0].Gates[0].Value {
+		t.Fatalf("latest report did not round-trip: %+v", latest)
+	}
+	if _, err := Load(dir, "2026-01-01"); !errors.Is(err, ErrNoReport) {
+		t.Fatalf("expected ErrNoReport, got %v", err)
+	}
+	if _, err := Load(dir, "../etc"); err == nil || errors.Is(err, ErrNoReport) {
+		t.Fatalf("expected invalid date error, got %v", err)
+	}
+}
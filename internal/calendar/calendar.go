@@ -0,0 +1,203 @@
+// Package calendar tracks non-working days so cadence-driven work such as
+// sprint planning and ceremonies can move off holidays.
+package calendar
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+// maxShiftDays bounds how far NextWorkingDay searches, so a misconfigured
+// calendar cannot loop forever.
+const maxShiftDays = 366
+
+// Holiday is one non-working date.
+type Holiday struct {
+	Date string // YYYY-MM-DD
+	Name string
+}
+
+// Calendar answers whether a date is a holiday. A nil *Calendar has no
+// holidays.
+type Calendar struct {
+	holidays map[string]string
+}
+
+// New builds a calendar from holidays.
+func New(holidays []Holiday) *Calendar {
+	c := &Calendar{holidays: make(map[string]string, len(holidays))}
+	for _, h := range holidays {
+		c.holidays[h.Date] = h.Name
+	}
+	return c
+}
+
+// Static builds the calendar of cadence's static holidays list alone.
+func Static(cadence config.Cadence) *Calendar {
+	return New(staticHolidays(cadence))
+}
+
+func staticHolidays(cadence config.Cadence) []Holiday {
+	var holidays []Holiday
+	for _, day := range cadence.Holidays {
+		holidays = append(holidays, Holiday{Date: strings.TrimSpace(day)})
+	}
+	return holidays
+}
+
+// Load builds the calendar configured in cadence: the static holidays list
+// plus the events of holiday_calendar, fetched over HTTP.
+func Load(ctx context.Context, cadence config.Cadence) (*Calendar, error) {
+	holidays := staticHolidays(cadence)
+	if cadence.HolidayCalendar != "" {
+		fetched, err := FetchICal(ctx, cadence.HolidayCalendar)
+		if err != nil {
+			return nil, err
+		}
+		holidays = append(holidays, fetched...)
+	}
+	return New(holidays), nil
+}
+
+// FetchICal downloads an iCal feed and returns its events as holidays.
+func FetchICal(ctx context.Context, url string) ([]Holiday, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("calendar: fetch %s: %w", url, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("calendar: fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("calendar: fetch %s: status %s", url, resp.Status)
+	}
+	return ParseICal(resp.Body)
+}
+
+// ParseICal reads the VEVENTs of an iCal feed. Each day an event covers
+// becomes a holiday; all-day DTEND values are exclusive, as in RFC 5545.
+func ParseICal(r io.Reader) ([]Holiday, error) {
+	var (
+		out        []Holiday
+		inEvent    bool
+		name       string
+		start, end time.Time
+		allDay     bool
+	)
+	for _, line := range unfoldICal(r) {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		prop, _, _ := strings.Cut(key, ";")
+		switch strings.ToUpper(prop) {
+		case "BEGIN":
+			if strings.EqualFold(value, "VEVENT") {
+				inEvent, name, start, end, allDay = true, "", time.Time{}, time.Time{}, false
+			}
+		case "SUMMARY":
+			name = value
+		case "DTSTART":
+			t, dateOnly, err := parseICalTime(value)
+			if err != nil {
+				return nil, err
+			}
+			start, allDay = t, dateOnly
+		case "DTEND":
+			t, _, err := parseICalTime(value)
+			if err != nil {
+				return nil, err
+			}
+			end = t
+		case "END":
+			if !inEvent || !strings.EqualFold(value, "VEVENT") {
+				continue
+			}
+			inEvent = false
+			if start.IsZero() {
+				continue
+			}
+			last := start
+			if !end.IsZero() {
+				last = end
+				if allDay {
+					last = end.AddDate(0, 0, -1)
+				}
+			}
+			for d := start; !d.After(last); d = d.AddDate(0, 0, 1) {
+				out = append(out, Holiday{Date: d.Format(time.DateOnly), Name: name})
+			}
+		}
+	}
+	return out, nil
+}
+
+// unfoldICal joins folded continuation lines, which start with a space or tab.
+func unfoldICal(r io.Reader) []string {
+	var lines []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// parseICalTime parses a DATE or DATE-TIME value and truncates it to its
+// date. It reports whether the value was a plain DATE.
+func parseICalTime(value string) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+	if len(value) == 8 {
+		t, err := time.Parse("20060102", value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("calendar: invalid date %q", value)
+		}
+		return t, true, nil
+	}
+	if len(value) < 8 {
+		return time.Time{}, false, fmt.Errorf("calendar: invalid date-time %q", value)
+	}
+	t, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("calendar: invalid date-time %q", value)
+	}
+	return t, false, nil
+}
+
+// IsHoliday reports whether t's calendar date, in t's location, is a holiday.
+func (c *Calendar) IsHoliday(t time.Time) bool {
+	if c == nil {
+		return false
+	}
+	_, ok := c.holidays[t.Format(time.DateOnly)]
+	return ok
+}
+
+// NextWorkingDay returns t unchanged when its date is not a holiday.
+// Otherwise it moves t forward a day at a time, keeping the clock time, to
+// the first weekday that is not a holiday.
+func (c *Calendar) NextWorkingDay(t time.Time) time.Time {
+	if !c.IsHoliday(t) {
+		return t
+	}
+	for i := 0; i < maxShiftDays; i++ {
+		t = t.AddDate(0, 0, 1)
+		if wd := t.Weekday(); wd != time.Saturday && wd != time.Sunday && !c.IsHoliday(t) {
+			break
+		}
+	}
+	return t
+}
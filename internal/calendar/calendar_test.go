@@ -0,0 +1,97 @@
+package calendar
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+const testICal = "BEGIN:VCALENDAR\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20261224\r\n" +
+	"DTEND;VALUE=DATE:20261227\r\n" +
+	"SUMMARY:Christmas\r\n" +
+	"  break\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART:20261231T090000Z\r\n" +
+	"SUMMARY:New Year's Eve\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseICal(t *testing.T) {
+	got, err := ParseICal(strings.NewReader(testICal))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Holiday{
+		{Date: "2026-12-24", Name: "Christmas break"},
+		{Date: "2026-12-25", Name: "Christmas break"},
+		{Date: "2026-12-26", Name: "Christmas break"},
+		{Date: "2026-12-31", Name: "New Year's Eve"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("holiday %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if _, err := ParseICal(strings.NewReader("BEGIN:VEVENT\nDTSTART:2026\nEND:VEVENT\n")); err == nil {
+		t.Fatal("expected an invalid DTSTART to fail")
+	}
+}
+
+func TestNextWorkingDay(t *testing.T) {
+	cal := New([]Holiday{{Date: "2026-12-24"}, {Date: "2026-12-25"}, {Date: "2026-12-28"}})
+	at := func(day int) time.Time { return time.Date(2026, 12, day, 9, 30, 0, 0, time.UTC) }
+
+	if got := cal.NextWorkingDay(at(23)); !got.Equal(at(23)) {
+		t.Fatalf("working day moved to %v", got)
+	}
+	// Thursday and Friday are holidays, the weekend is skipped and Monday is
+	// a holiday too.
+	if got := cal.NextWorkingDay(at(24)); !got.Equal(at(29)) {
+		t.Fatalf("next working day = %v, want %v", got, at(29))
+	}
+	// A weekend day that is not a holiday is left alone.
+	if got := cal.NextWorkingDay(at(27)); !got.Equal(at(27)) {
+		t.Fatalf("non-holiday Sunday moved to %v", got)
+	}
+
+	var none *Calendar
+	if none.IsHoliday(at(25)) || !none.NextWorkingDay(at(25)).Equal(at(25)) {
+		t.Fatal("a nil calendar should have no holidays")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testICal))
+	}))
+	defer srv.Close()
+
+	cal, err := Load(context.Background(), config.Cadence{Holidays: []string{"2026-06-19"}, HolidayCalendar: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, day := range []string{"2026-06-19", "2026-12-25", "2026-12-31"} {
+		d, _ := time.Parse(time.DateOnly, day)
+		if !cal.IsHoliday(d) {
+			t.Fatalf("expected %s to be a holiday", day)
+		}
+	}
+
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+	if _, err := Load(context.Background(), config.Cadence{HolidayCalendar: missing.URL}); err == nil {
+		t.Fatal("expected a failed calendar fetch to return an error")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/antigravity-dev/cortex/internal/beads"
+	"github.com/antigravity-dev/cortex/internal/calendar"
 	"github.com/antigravity-dev/cortex/internal/config"
 	"github.com/antigravity-dev/cortex/internal/dispatch"
 	"github.com/antigravity-dev/cortex/internal/portfolio"
@@ -23,6 +24,7 @@ type Chief struct {
 	logger     *slog.Logger
 	allocator  *AllocationRecorder
 	retro      *RetrospectiveRecorder
+	holidays   *calendar.Calendar // nil runs ceremonies on their weekday regardless of holidays
 }
 
 type multiTeamPortfolioContextKey struct{}
@@ -76,7 +78,9 @@ type CeremonySchedule struct {
 	LastRan     time.Time     // When ceremony last ran successfully
 }
 
-// New creates a new Chief instance
+// New creates a new Chief instance. Ceremonies move off the cadence's static
+// holidays; SetHolidayCalendar replaces them with a fuller calendar, such as
+// one with the iCal feed loaded.
 func New(cfg *config.Config, store *store.Store, dispatcher dispatch.DispatcherInterface, logger *slog.Logger) *Chief {
 	allocator := NewAllocationRecorder(cfg, store, dispatcher, logger)
 	retroRecorder := NewRetrospectiveRecorder(cfg, store, dispatcher, logger)
@@ -87,6 +91,7 @@ func New(cfg *config.Config, store *store.Store, dispatcher dispatch.DispatcherI
 		logger:     logger,
 		allocator:  allocator,
 		retro:      retroRecorder,
+		holidays:   calendar.Static(cfg.Cadence),
 	}
 }
 
@@ -104,15 +109,9 @@ func (c *Chief) ShouldRunCeremony(ctx context.Context, schedule CeremonySchedule
 		return false
 	}
 
-	// Check if today is the right day of week
-	if now.Weekday() != schedule.DayOfWeek {
-		return false
-	}
-
-	// Check if we're past the target time today
-	targetTime := time.Date(now.Year(), now.Month(), now.Day(),
-		schedule.TimeOfDay.Hour(), schedule.TimeOfDay.Minute(), 0, 0, now.Location())
-	if now.Before(targetTime) {
+	// Check if today is the (holiday-shifted) ceremony day and we're past the target time
+	targetTime, due := ceremonyDue(schedule, now, c.holidays)
+	if !due {
 		return false
 	}
 
@@ -130,6 +129,34 @@ func (c *Chief) ShouldRunCeremony(ctx context.Context, schedule CeremonySchedule
 	return true
 }
 
+// SetHolidayCalendar makes ceremonies that fall on a holiday run on the next
+// working day instead.
+func (c *Chief) SetHolidayCalendar(cal *calendar.Calendar) {
+	c.holidays = cal
+}
+
+// ceremonyLookbackDays is how far back ceremonyDue looks for a scheduled day
+// that holidays pushed forward to today.
+const ceremonyLookbackDays = 28
+
+// ceremonyDue reports whether a weekly ceremony is due at now and returns its
+// target time today. A scheduled day that is a holiday moves to the next
+// working day.
+func ceremonyDue(schedule CeremonySchedule, now time.Time, holidays *calendar.Calendar) (time.Time, bool) {
+	today := time.Date(now.Year(), now.Month(), now.Day(),
+		schedule.TimeOfDay.Hour(), schedule.TimeOfDay.Minute(), 0, 0, now.Location())
+	for back := 0; back < ceremonyLookbackDays; back++ {
+		scheduled := today.AddDate(0, 0, -back)
+		if scheduled.Weekday() != schedule.DayOfWeek {
+			continue
+		}
+		if target := holidays.NextWorkingDay(scheduled); target.Equal(today) {
+			return target, !now.Before(target)
+		}
+	}
+	return today, false
+}
+
 // RunMultiTeamPlanning executes the multi-team sprint planning ceremony
 func (c *Chief) RunMultiTeamPlanning(ctx context.Context) error {
 	if !c.cfg.Chief.Enabled {
@@ -366,6 +393,8 @@ func (c *Chief) buildCeremonyPrompt(ctx context.Context, template string) string
 		return c.buildMultiTeamPlanningPrompt(ctx)
 	case "overall_retrospective":
 		return c.buildOverallRetrospectivePrompt(ctx)
+	case "sprint_review":
+		return c.buildSprintReviewPrompt(ctx)
 	default:
 		return fmt.Sprintf("Execute Chief SM ceremony: %s", template)
 	}
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/antigravity-dev/cortex/internal/calendar"
 	"github.com/antigravity-dev/cortex/internal/config"
 )
 
@@ -220,6 +221,44 @@ func TestGetMultiTeamPlanningScheduleUsesCadenceOverrides(t *testing.T) {
 	}
 }
 
+func TestCeremonyDueShiftsOffHolidays(t *testing.T) {
+	schedule := CeremonySchedule{
+		Type:      CeremonyMultiTeamPlanning,
+		DayOfWeek: time.Monday,
+		TimeOfDay: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+	}
+	// Monday 2026-12-28 is a holiday, so planning moves to Tuesday.
+	holidays := calendar.New([]calendar.Holiday{{Date: "2026-12-28"}})
+	at := func(day, hour int) time.Time { return time.Date(2026, 12, day, hour, 0, 0, 0, time.UTC) }
+
+	for _, tc := range []struct {
+		name     string
+		now      time.Time
+		holidays *calendar.Calendar
+		want     bool
+	}{
+		{"weekday without calendar", at(28, 10), nil, true},
+		{"holiday", at(28, 10), holidays, false},
+		{"shifted day", at(29, 10), holidays, true},
+		{"shifted day before target time", at(29, 8), holidays, false},
+		{"day after without shift", at(29, 10), nil, false},
+		{"regular monday", at(21, 10), holidays, true},
+	} {
+		if _, due := ceremonyDue(schedule, tc.now, tc.holidays); due != tc.want {
+			t.Errorf("%s: due = %v, want %v", tc.name, due, tc.want)
+		}
+	}
+}
+
+func TestNewUsesCadenceHolidays(t *testing.T) {
+	cfg := &config.Config{Cadence: config.Cadence{Holidays: []string{"2026-12-28"}}}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	chief := New(cfg, nil, nil, logger)
+	if !chief.holidays.IsHoliday(time.Date(2026, 12, 28, 9, 0, 0, 0, time.UTC)) {
+		t.Fatal("chief ignores the cadence's holidays")
+	}
+}
+
 func TestWithMultiTeamPortfolioContext(t *testing.T) {
 	ctx := context.Background()
 	ctx = WithMultiTeamPortfolioContext(ctx, `{"foo":"bar"}`)
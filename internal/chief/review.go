@@ -0,0 +1,44 @@
+package chief
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/antigravity-dev/cortex/internal/learner"
+)
+
+// sprintReviewVelocitySprints is how many recent sprints of velocity the
+// sprint review prompt shows, so the Chief SM can spot trends.
+const sprintReviewVelocitySprints = 4
+
+// buildSprintReviewPrompt briefs the Chief SM sprint review ceremony with
+// the recent velocity history the Temporal chief cron records.
+func (c *Chief) buildSprintReviewPrompt(ctx context.Context) string {
+	velocity := "Velocity analytics unavailable.\n"
+	if c.store != nil {
+		history, err := c.store.ListSprintVelocity("", sprintReviewVelocitySprints)
+		if err != nil {
+			c.logger.Warn("sprint review: failed to load velocity", "error", err)
+		} else {
+			velocity = learner.FormatVelocity(history)
+		}
+	}
+
+	return fmt.Sprintf(`# Sprint Review Ceremony
+
+You are the **Chief Scrum Master** reviewing what each project delivered this sprint.
+
+## Velocity (last %d sprints, newest first)
+
+Closed is beads completed within the sprint. Avg cycle is first activity to completion. Estimate vs actual compares estimated minutes with dispatch minutes for closed beads that carried an estimate.
+
+%s
+## Required Outcomes
+
+1. Summarize what each project delivered against its plan.
+2. Call out velocity trends: projects speeding up, slowing down or stalling.
+3. Flag stages whose cycle time dominates delivery and suggest why.
+4. Flag projects whose actual minutes consistently exceed estimates so planning can re-size their work.
+5. Send a concise review summary suitable for the Matrix room.
+`, sprintReviewVelocitySprints, velocity)
+}
@@ -0,0 +1,56 @@
+package chief
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestBuildSprintReviewPromptIncludesVelocity(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	if err := st.UpsertSprintVelocity(store.SprintVelocity{
+		Project:           "cortex",
+		SprintNumber:      7,
+		SprintStart:       start,
+		SprintEnd:         start.Add(7 * 24 * time.Hour),
+		BeadsClosed:       5,
+		AvgCycleMinutes:   42,
+		StageCycleMinutes: map[string]float64{"coding": 30, "review": 12},
+		EstimatedBeads:    2,
+		EstimateMinutes:   90,
+		ActualMinutes:     120,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	chief := New(&config.Config{Chief: config.Chief{Enabled: true}}, st, nil, logger)
+
+	prompt := chief.buildCeremonyPrompt(context.Background(), "sprint_review")
+	for _, want := range []string{
+		"Sprint Review Ceremony",
+		"| 7 | cortex | 5 | 42m | 90m vs 120m (2 beads) | coding (30m) |",
+		"actual minutes consistently exceed estimates",
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Fatalf("expected prompt to contain %q, got:\n%s", want, prompt)
+		}
+	}
+
+	if prompt := New(&config.Config{}, nil, nil, logger).buildSprintReviewPrompt(context.Background()); !strings.Contains(prompt, "Velocity analytics unavailable") {
+		t.Fatalf("expected fallback without a store, got:\n%s", prompt)
+	}
+}
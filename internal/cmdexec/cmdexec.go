@@ -0,0 +1,198 @@
+// Package cmdexec runs the external CLIs cortex shells out to (bd, gh, git)
+// with a timeout on every run, bounded output capture, optional retries and
+// per-command latency stats.
+package cmdexec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTimeout bounds a run whose Cmd sets no Timeout; no command runs
+	// unbounded.
+	DefaultTimeout = 2 * time.Minute
+	// DefaultMaxOutput is how many bytes of each output stream are kept when
+	// a Cmd sets no MaxOutput.
+	DefaultMaxOutput = 1 << 20
+	// DefaultRetryDelay is the pause between attempts when a Cmd with
+	// Retries sets no RetryDelay.
+	DefaultRetryDelay = time.Second
+)
+
+// Cmd is one external command to run.
+type Cmd struct {
+	Name string
+	Args []string
+	Dir  string
+	Env  []string // KEY=VALUE pairs added to the process environment
+
+	Timeout        time.Duration // per attempt; 0 means DefaultTimeout
+	MaxOutput      int           // bytes kept of each stream; 0 means DefaultMaxOutput
+	CombinedOutput bool          // capture stderr interleaved into Stdout
+	Retries        int           // extra attempts after a failed one; for idempotent commands only
+	RetryDelay     time.Duration // pause between attempts; 0 means DefaultRetryDelay
+}
+
+// Result is the outcome of the last attempt of a run.
+type Result struct {
+	Stdout    []byte
+	Stderr    []byte // empty with CombinedOutput
+	ExitCode  int    // -1 when the command did not exit on its own
+	Duration  time.Duration
+	Attempts  int
+	Truncated bool // output beyond MaxOutput was dropped
+}
+
+// Text returns Stdout with surrounding whitespace trimmed.
+func (r Result) Text() string {
+	return strings.TrimSpace(string(r.Stdout))
+}
+
+// Error is a failed run. It unwraps to the underlying error, such as an
+// *exec.ExitError or exec.ErrNotFound.
+type Error struct {
+	Command  string // name and subcommand, e.g. "gh pr"
+	ExitCode int
+	TimedOut bool
+	Timeout  time.Duration
+	Output   string // stderr, or the combined output, trimmed
+	Err      error
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%s: %v", e.Command, e.Err)
+	if e.TimedOut {
+		msg = fmt.Sprintf("%s: timed out after %s", e.Command, e.Timeout)
+	}
+	if e.Output != "" {
+		msg += " (" + e.Output + ")"
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// ExitCode returns the exit code of the failed run behind err, or -1 when
+// err is not a run that exited.
+func ExitCode(err error) int {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.ExitCode
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// Run runs c until it succeeds, its retries are spent or ctx ends, and
+// records each attempt in the command stats.
+func Run(ctx context.Context, c Cmd) (Result, error) {
+	delay := c.RetryDelay
+	if delay <= 0 {
+		delay = DefaultRetryDelay
+	}
+	var res Result
+	var err error
+	for attempt := 1; ; attempt++ {
+		res, err = runOnce(ctx, c)
+		res.Attempts = attempt
+		if err == nil || attempt > c.Retries || ctx.Err() != nil {
+			return res, err
+		}
+		select {
+		case <-ctx.Done():
+			return res, err
+		case <-time.After(delay):
+		}
+	}
+}
+
+func runOnce(ctx context.Context, c Cmd) (Result, error) {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	limit := c.MaxOutput
+	if limit <= 0 {
+		limit = DefaultMaxOutput
+	}
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(runCtx, c.Name, c.Args...)
+	cmd.Dir = c.Dir
+	if len(c.Env) > 0 {
+		cmd.Env = append(os.Environ(), c.Env...)
+	}
+	killGroupOnCancel(cmd)
+	cmd.WaitDelay = 5 * time.Second // don't wait on pipes held by stray children
+
+	stdout := &limitedBuffer{limit: limit}
+	stderr := stdout
+	if !c.CombinedOutput {
+		stderr = &limitedBuffer{limit: limit}
+	}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+
+	start := time.Now()
+	runErr := cmd.Run()
+	res := Result{
+		Stdout:    stdout.buf,
+		ExitCode:  -1,
+		Duration:  time.Since(start),
+		Truncated: stdout.dropped > 0 || stderr.dropped > 0,
+	}
+	if !c.CombinedOutput {
+		res.Stderr = stderr.buf
+	}
+	if cmd.ProcessState != nil && cmd.ProcessState.Exited() {
+		res.ExitCode = cmd.ProcessState.ExitCode()
+	}
+
+	name := commandName(c)
+	timedOut := runErr != nil && runCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil
+	record(name, res.Duration, runErr != nil, timedOut)
+	if runErr == nil {
+		return res, nil
+	}
+	return res, &Error{
+		Command:  name,
+		ExitCode: res.ExitCode,
+		TimedOut: timedOut,
+		Timeout:  timeout,
+		Output:   strings.TrimSpace(string(stderr.buf)),
+		Err:      runErr,
+	}
+}
+
+// commandName is the stats key of c: the executable and, when the first
+// argument is a subcommand rather than a flag, that too.
+func commandName(c Cmd) string {
+	if len(c.Args) > 0 && c.Args[0] != "" && !strings.HasPrefix(c.Args[0], "-") {
+		return c.Name + " " + c.Args[0]
+	}
+	return c.Name
+}
+
+// limitedBuffer keeps the first limit bytes written to it and counts the
+// rest.
+type limitedBuffer struct {
+	buf     []byte
+	limit   int
+	dropped int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	keep := min(len(p), b.limit-len(b.buf))
+	b.buf = append(b.buf, p[:keep]...)
+	b.dropped += len(p) - keep
+	return len(p), nil
+}
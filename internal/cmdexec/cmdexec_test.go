@@ -0,0 +1,128 @@
+package cmdexec
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func statFor(t *testing.T, command string) Stat {
+	t.Helper()
+	for _, st := range Stats() {
+		if st.Command == command {
+			return st
+		}
+	}
+	return Stat{}
+}
+
+func TestRunCapturesOutput(t *testing.T) {
+	res, err := Run(context.Background(), Cmd{Name: "sh", Args: []string{"-c", "echo out; echo err >&2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Text() != "out" || strings.TrimSpace(string(res.Stderr)) != "err" || res.ExitCode != 0 || res.Attempts != 1 {
+		t.Fatalf("result = %+v", res)
+	}
+
+	res, err = Run(context.Background(), Cmd{Name: "sh", Args: []string{"-c", "echo out; echo err >&2"}, CombinedOutput: true})
+	if err != nil || res.Text() != "out\nerr" || len(res.Stderr) != 0 {
+		t.Fatalf("combined = %+v, %v", res, err)
+	}
+}
+
+func TestRunFailure(t *testing.T) {
+	_, err := Run(context.Background(), Cmd{Name: "sh", Args: []string{"-c", "echo broken >&2; exit 3"}})
+	var cmdErr *Error
+	if !errors.As(err, &cmdErr) || cmdErr.ExitCode != 3 || cmdErr.Output != "broken" || ExitCode(err) != 3 {
+		t.Fatalf("err = %#v", err)
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatal("error does not unwrap to *exec.ExitError")
+	}
+	if !strings.Contains(err.Error(), "sh: exit status 3 (broken)") {
+		t.Fatalf("message = %q", err)
+	}
+
+	_, err = Run(context.Background(), Cmd{Name: "cortex-no-such-command"})
+	if !errors.Is(err, exec.ErrNotFound) || ExitCode(err) != -1 {
+		t.Fatalf("missing command err = %v", err)
+	}
+}
+
+func TestRunTimeoutKillsChildren(t *testing.T) {
+	before := statFor(t, "sh")
+	start := time.Now()
+	_, err := Run(context.Background(), Cmd{Name: "sh", Args: []string{"-c", "sleep 30 & wait"}, Timeout: 200 * time.Millisecond})
+	var cmdErr *Error
+	if !errors.As(err, &cmdErr) || !cmdErr.TimedOut {
+		t.Fatalf("err = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("timed out run took %s", elapsed)
+	}
+	if !strings.Contains(err.Error(), "timed out after 200ms") {
+		t.Fatalf("message = %q", err)
+	}
+	after := statFor(t, "sh")
+	if after.Timeouts != before.Timeouts+1 || after.Failures != before.Failures+1 || after.Runs != before.Runs+1 {
+		t.Fatalf("stats before %+v after %+v", before, after)
+	}
+}
+
+func TestRecordStreak(t *testing.T) {
+	const name = "cortex-test-streak"
+	Record(name, time.Second, true, true)
+	Record(name, time.Second, true, true)
+	if st := statFor(t, name); st.Streak != 2 || st.Timeouts != 2 {
+		t.Fatalf("after two timeouts %+v", st)
+	}
+	Record(name, time.Second, true, false)
+	if st := statFor(t, name); st.Streak != 0 || st.Timeouts != 2 || st.Failures != 3 {
+		t.Fatalf("after a failure that did not time out %+v", st)
+	}
+}
+
+func TestRunTruncatesOutput(t *testing.T) {
+	res, err := Run(context.Background(), Cmd{Name: "sh", Args: []string{"-c", "printf 0123456789"}, MaxOutput: 4})
+	if err != nil || string(res.Stdout) != "0123" || !res.Truncated {
+		t.Fatalf("result = %+v, %v", res, err)
+	}
+}
+
+func TestRunRetries(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "ran")
+	// Fails the first time, succeeds once the marker exists.
+	script := "if [ -e " + marker + " ]; then echo ok; else touch " + marker + "; exit 1; fi"
+	res, err := Run(context.Background(), Cmd{Name: "sh", Args: []string{"-c", script}, Retries: 2, RetryDelay: time.Millisecond})
+	if err != nil || res.Text() != "ok" || res.Attempts != 2 {
+		t.Fatalf("result = %+v, %v", res, err)
+	}
+
+	os.Remove(marker)
+	res, err = Run(context.Background(), Cmd{Name: "sh", Args: []string{"-c", "exit 1"}, Retries: 2, RetryDelay: time.Millisecond})
+	if err == nil || res.Attempts != 3 {
+		t.Fatalf("exhausted retries: %+v, %v", res, err)
+	}
+}
+
+func TestCommandName(t *testing.T) {
+	for _, tc := range []struct {
+		cmd  Cmd
+		want string
+	}{
+		{Cmd{Name: "gh", Args: []string{"pr", "view"}}, "gh pr"},
+		{Cmd{Name: "git", Args: []string{"-C", "/tmp", "status"}}, "git"},
+		{Cmd{Name: "bd"}, "bd"},
+	} {
+		if got := commandName(tc.cmd); got != tc.want {
+			t.Errorf("commandName(%v) = %q, want %q", tc.cmd, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+//go:build windows
+
+package cmdexec
+
+import "os/exec"
+
+// killGroupOnCancel leaves cmd to the default cancellation, which kills the
+// process itself.
+func killGroupOnCancel(cmd *exec.Cmd) {}
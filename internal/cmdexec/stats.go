@@ -0,0 +1,64 @@
+package cmdexec
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Stat is the run history of one command since the process started.
+type Stat struct {
+	Command  string
+	Runs     int64
+	Failures int64 // includes timeouts
+	Timeouts int64
+	Streak   int64 // timeouts in a row up to the latest run
+	Total    time.Duration
+	Max      time.Duration
+}
+
+var (
+	statsMu sync.Mutex
+	stats   = map[string]*Stat{}
+)
+
+func record(command string, d time.Duration, failed, timedOut bool) {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	st, ok := stats[command]
+	if !ok {
+		st = &Stat{Command: command}
+		stats[command] = st
+	}
+	st.Runs++
+	if failed {
+		st.Failures++
+	}
+	if timedOut {
+		st.Timeouts++
+		st.Streak++
+	} else {
+		st.Streak = 0
+	}
+	st.Total += d
+	st.Max = max(st.Max, d)
+}
+
+// Record adds a run of command made outside Run, such as a shell check
+// with its own process handling, to the command stats.
+func Record(command string, d time.Duration, failed, timedOut bool) {
+	record(command, d, failed, timedOut)
+}
+
+// Stats returns the run history of every command run so far, by command
+// name.
+func Stats() []Stat {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	out := make([]Stat, 0, len(stats))
+	for _, st := range stats {
+		out = append(out, *st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Command < out[j].Command })
+	return out
+}
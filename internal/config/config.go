@@ -3,14 +3,27 @@ package config
 
 import (
 	"fmt"
+	"io/fs"
+	"maps"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/BurntSushi/toml"
+	"github.com/robfig/cron"
+
+	"github.com/antigravity-dev/cortex/internal/assets"
 )
 
 // Duration is a time.Duration that unmarshals from TOML strings like "60s" or "2m".
@@ -32,242 +45,1016 @@ func (d Duration) MarshalText() ([]byte, error) {
 }
 
 type Config struct {
-	General    General                   `toml:"general"`
-	Projects   map[string]Project        `toml:"projects"`
-	RateLimits RateLimits                `toml:"rate_limits"`
-	Providers  map[string]Provider       `toml:"providers"`
-	Tiers      Tiers                     `toml:"tiers"`
-	Workflows  map[string]WorkflowConfig `toml:"workflows"`
-	Cadence    Cadence                   `toml:"cadence"`
-	Health     Health                    `toml:"health"`
-	Reporter   Reporter                  `toml:"reporter"`
-	Learner    Learner                   `toml:"learner"`
-	Matrix     Matrix                    `toml:"matrix"`
-	API        API                       `toml:"api"`
-	Dispatch   Dispatch                  `toml:"dispatch"`
-	Chief      Chief                     `toml:"chief"`
+	General    General                   `toml:"general" doc:"Core scheduler settings."`
+	Projects   map[string]Project        `toml:"projects" doc:"Projects managed by Cortex, keyed by project name."`
+	RateLimits RateLimits                `toml:"rate_limits" doc:"Dispatch rate limits and per-project budget shares."`
+	Providers  map[string]Provider       `toml:"providers" doc:"LLM provider definitions, keyed by provider name."`
+	Tiers      Tiers                     `toml:"tiers" doc:"Provider names per capability tier, in preference order."`
+	Workflows  map[string]WorkflowConfig `toml:"workflows" doc:"Label/type-matched stage pipelines, keyed by workflow name."`
+	Cadence    Cadence                   `toml:"cadence" doc:"Shared sprint cadence across all projects."`
+	Health     Health                    `toml:"health" doc:"Gateway and concurrency health checks."`
+	Reporter   Reporter                  `toml:"reporter" doc:"Status reporting destinations and schedule."`
+	Learner    Learner                   `toml:"learner" doc:"Dispatch history analysis."`
+	Matrix     Matrix                    `toml:"matrix" doc:"Inbound Matrix polling for scrum routing."`
+	API        API                       `toml:"api" doc:"HTTP API server."`
+	Dispatch   Dispatch                  `toml:"dispatch" doc:"Agent dispatch backends, timeouts, and cost controls."`
+	Chief      Chief                     `toml:"chief" doc:"Chief Scrum Master coordination agent."`
+	Secrets    Secrets                   `toml:"secrets" doc:"Secret provider used by ${secret:NAME} references."`
+	Temporal   Temporal                  `toml:"temporal" doc:"Temporal server connection, task queue and cron schedules."`
+	HA         HA                        `toml:"ha" doc:"Active/standby failover between cortex instances."`
+	Sharding   Sharding                  `toml:"sharding" doc:"Splitting the projects across cortex instances."`
+	OnCall     OnCall                    `toml:"oncall" doc:"On-call roster mentioned and paged on critical health events."`
+
+	Experiments map[string]Experiment `toml:"experiments" doc:"Prompt/agent A/B experiments, keyed by experiment name."`
+
+	// IncludedFiles lists the include_dir fragments merged into this config.
+	IncludedFiles []string `toml:"-"`
+}
+
+// HA controls active/standby failover. Instances elect a leader through the
+// dispatch.claims backend; only the leader runs the Temporal worker and crons
+// and accepts dispatches, and a standby takes over once the leader's lease
+// lapses.
+type HA struct {
+	Enabled  bool     `toml:"enabled" doc:"Elect one active instance among those sharing the dispatch.claims backend."`
+	LeaseTTL Duration `toml:"lease_ttl" doc:"How long leadership lasts without renewal, and so how soon a standby takes over from a dead leader (default 15s)."`
+}
+
+// OnCall names who is on call, so a critical health event routed to Matrix
+// mentions that one person and pages them, instead of alerting everyone.
+// The static roster wins when both it and PagerDuty are configured.
+type OnCall struct {
+	Shifts    []OnCallShift   `toml:"shifts" doc:"Static roster; the first shift open at the time is on call."`
+	PagerDuty OnCallPagerDuty `toml:"pagerduty" doc:"PagerDuty schedule read for the on-call user, and the integration paged."`
+}
+
+// OnCallShift is a recurring shift of the static roster.
+type OnCallShift struct {
+	User     string   `toml:"user" doc:"Matrix user mentioned during the shift, e.g. @alice:example.org."`
+	Days     []string `toml:"days" doc:"Days the shift starts on (e.g. Monday); empty means every day."`
+	Hours    string   `toml:"hours" doc:"Shift as HH:MM-HH:MM; wraps midnight when the end is earlier."`
+	Timezone string   `toml:"timezone" doc:"IANA timezone for days and hours (default UTC)."`
+}
+
+// OnCallPagerDuty reads the on-call user from a PagerDuty schedule and pages
+// through the Events API v2.
+type OnCallPagerDuty struct {
+	APIToken   string            `toml:"api_token" doc:"REST API token reading the schedule; use a ${secret:NAME} reference."`
+	ScheduleID string            `toml:"schedule_id" doc:"Schedule whose current on-call user is mentioned."`
+	RoutingKey string            `toml:"routing_key" doc:"Events API v2 integration key paged while someone is on call; use a ${secret:NAME} reference."`
+	Users      map[string]string `toml:"users" doc:"Matrix user of each PagerDuty user, keyed by email; unmapped users are mentioned by name."`
+}
+
+// Window is the recurring window the shift covers.
+func (s OnCallShift) Window() MaintenanceWindow {
+	return MaintenanceWindow{Name: s.User, Days: s.Days, Hours: s.Hours, Timezone: s.Timezone}
+}
+
+// OnCallAt returns the user of the first roster shift open at now.
+func (o OnCall) OnCallAt(now time.Time) (string, bool) {
+	for _, shift := range o.Shifts {
+		if _, ok := shift.Window().openAt(now); ok {
+			return shift.User, true
+		}
+	}
+	return "", false
+}
+
+// Sharding splits the projects of a large install across cortex instances.
+// Every shard loads the same project list but runs the crons and accepts
+// dispatches only for the projects assigned to it; the API aggregates status
+// from the other shards.
+type Sharding struct {
+	Enabled bool              `toml:"enabled" doc:"Run only the projects assigned to this instance's shard."`
+	Shard   string            `toml:"shard" doc:"This instance's shard name (required when enabled)."`
+	Peers   map[string]string `toml:"peers" doc:"API base URLs of the other shards, keyed by shard name, queried for aggregated status."`
 }
 
 type General struct {
-	TickInterval           Duration `toml:"tick_interval"`
-	MaxPerTick             int      `toml:"max_per_tick"`
-	StuckTimeout           Duration `toml:"stuck_timeout"`
-	MaxRetries             int      `toml:"max_retries"`
-	RetryBackoffBase       Duration `toml:"retry_backoff_base"`
-	RetryMaxDelay          Duration `toml:"retry_max_delay"`
-	RetryPolicy            RetryPolicy `toml:"retry_policy"`
-	RetryTiers             map[string]RetryPolicy `toml:"retry_tiers"`
-	DispatchCooldown       Duration `toml:"dispatch_cooldown"`
-	LogLevel               string   `toml:"log_level"`
-	StateDB                string   `toml:"state_db"`
-	LockFile               string   `toml:"lock_file"`
-	MaxConcurrentCoders    int      `toml:"max_concurrent_coders"`    // hard cap on concurrent coder agents
-	MaxConcurrentReviewers int      `toml:"max_concurrent_reviewers"` // hard cap on concurrent reviewer agents
-	MaxConcurrentTotal     int      `toml:"max_concurrent_total"`     // hard cap on total concurrent agents
+	TickInterval           Duration               `toml:"tick_interval" doc:"How often the scheduler evaluates work."`
+	MaxPerTick             int                    `toml:"max_per_tick" doc:"Maximum dispatches started per tick."`
+	EnrichHeadroom         float64                `toml:"enrich_headroom" doc:"Candidates enriched with bead details per tick, as a multiple of max_per_tick; more are enriched only when earlier ones are rejected."`
+	StuckTimeout           Duration               `toml:"stuck_timeout" doc:"Dispatches running longer than this are considered stuck."`
+	MaxRetries             int                    `toml:"max_retries" doc:"Maximum retries for a failed dispatch."`
+	RetryBackoffBase       Duration               `toml:"retry_backoff_base" doc:"Base delay for exponential retry backoff."`
+	RetryMaxDelay          Duration               `toml:"retry_max_delay" doc:"Upper bound on retry backoff delay."`
+	RetryPolicy            RetryPolicy            `toml:"retry_policy" doc:"Default retry policy; unset fields inherit the values above."`
+	RetryTiers             map[string]RetryPolicy `toml:"retry_tiers" doc:"Per-tier retry policy overrides, keyed by tier name."`
+	DispatchCooldown       Duration               `toml:"dispatch_cooldown" doc:"Minimum wait before re-dispatching the same bead."`
+	LogLevel               string                 `toml:"log_level" doc:"Log verbosity." valid:"debug, info, warn, error"`
+	StateDB                string                 `toml:"state_db" doc:"Path to the SQLite state database (required)."`
+	LockFile               string                 `toml:"lock_file" doc:"Path to the single-instance lock file."`
+	IncludeDir             string                 `toml:"include_dir" doc:"Directory of *.toml fragments holding extra [projects.*] tables, relative to this file."`
+	MaxConcurrentCoders    int                    `toml:"max_concurrent_coders" doc:"Hard cap on concurrent coder agents."`
+	MaxConcurrentReviewers int                    `toml:"max_concurrent_reviewers" doc:"Hard cap on concurrent reviewer agents."`
+	MaxConcurrentTotal     int                    `toml:"max_concurrent_total" doc:"Hard cap on total concurrent agents."`
+	Autoscale              GeneralAutoscale       `toml:"autoscale" doc:"Controller that moves the coder and reviewer limits within bounds."`
+	ShutdownDrain          Duration               `toml:"shutdown_drain" doc:"On SIGTERM, how long to wait for running dispatches to finish before interrupting them; 0 interrupts immediately."`
+	WALCheckpointInterval  Duration               `toml:"wal_checkpoint_interval" doc:"How often to checkpoint and truncate the state DB write-ahead log; 0 disables."`
+	BeadsGuardInterval     Duration               `toml:"beads_guard_interval" doc:"How often to check each project's .beads/issues.jsonl for oversized rows and trim them, keeping a backup; 0 disables."`
+	BeadsGuardMaxRowBytes  int                    `toml:"beads_guard_max_row_bytes" doc:"Largest issues.jsonl row, in bytes, the beads guard leaves alone."`
+	MaintenanceWindows     []MaintenanceWindow    `toml:"maintenance_windows" doc:"Recurring windows in which the scheduler pauses new dispatches, resuming when the window closes."`
+}
+
+// GeneralAutoscale lets the coder and reviewer limits follow the backlog:
+// a role with work queued for a slot grows, an idle role shrinks, and both
+// shrink while the failure rate or the day's spend is too high. Limits stay
+// within the min and max bounds; max_concurrent_coders and
+// max_concurrent_reviewers are where they start.
+type GeneralAutoscale struct {
+	Enabled        bool     `toml:"enabled" doc:"Adjust the coder and reviewer limits automatically."`
+	Interval       Duration `toml:"interval" doc:"How often the limits are reconsidered (default 5m)."`
+	Step           int      `toml:"step" doc:"How far a limit moves per interval (default 1)."`
+	MinCoders      int      `toml:"min_coders" doc:"Lowest coder limit (default 1)."`
+	MaxCoders      int      `toml:"max_coders" doc:"Highest coder limit (default max_concurrent_coders)."`
+	MinReviewers   int      `toml:"min_reviewers" doc:"Lowest reviewer limit (default 1)."`
+	MaxReviewers   int      `toml:"max_reviewers" doc:"Highest reviewer limit (default max_concurrent_reviewers)."`
+	QueueDepth     int      `toml:"queue_depth" doc:"Overflow queue items of a role that make its limit grow (default 1)."`
+	Window         Duration `toml:"window" doc:"Span of recent dispatches the failure rate covers (default 1h)."`
+	MaxFailureRate float64  `toml:"max_failure_rate" doc:"Failed share of recent dispatches (0-1] above which limits shrink (default 0.5)."`
+	MaxCostBurn    float64  `toml:"max_cost_burn" doc:"Share (0-1] of dispatch.cost_control.daily_cost_cap_usd spent today above which limits shrink (default 0.8)."`
+}
+
+// MaintenanceWindow is a recurring period, such as host maintenance, in which
+// no dispatch may start. It is given either as a cron start and a duration or
+// as an HH:MM-HH:MM range on some weekdays.
+type MaintenanceWindow struct {
+	Name     string   `toml:"name" doc:"Label reported while the window is open."`
+	Cron     string   `toml:"cron" doc:"Cron schedule the window opens on; needs duration."`
+	Duration Duration `toml:"duration" doc:"How long a cron window stays open."`
+	Days     []string `toml:"days" doc:"Days an hours window recurs on (e.g. Sunday); empty means every day."`
+	Hours    string   `toml:"hours" doc:"Window as HH:MM-HH:MM; wraps midnight when the end is earlier, staying on the day it opened."`
+	Timezone string   `toml:"timezone" doc:"IANA timezone for cron, days and hours (default UTC)."`
 }
 
 // Cadence defines shared sprint cadence across all projects.
 type Cadence struct {
-	SprintLength    string `toml:"sprint_length"`     // e.g. 1w, 2w
-	SprintStartDay  string `toml:"sprint_start_day"`  // e.g. Monday
-	SprintStartTime string `toml:"sprint_start_time"` // HH:MM 24h
-	Timezone        string `toml:"timezone"`          // IANA timezone (e.g. UTC)
+	SprintLength    string `toml:"sprint_length" doc:"Sprint length." valid:"1w, 2w"`
+	SprintStartDay  string `toml:"sprint_start_day" doc:"Day of week sprints start." valid:"Monday ... Sunday"`
+	SprintStartTime string `toml:"sprint_start_time" doc:"Sprint start time, HH:MM 24h."`
+	Timezone        string `toml:"timezone" doc:"IANA timezone for cadence times (e.g. UTC)."`
+
+	Holidays        []string `toml:"holidays" doc:"Non-working dates, YYYY-MM-DD; ceremonies and planning that fall on one move to the next working day."`
+	HolidayCalendar string   `toml:"holiday_calendar" doc:"iCal (.ics) URL whose events are treated as holidays, merged with holidays."`
 }
 
 type Project struct {
-	Enabled      bool   `toml:"enabled"`
-	BeadsDir     string `toml:"beads_dir"`
-	Workspace    string `toml:"workspace"`
-	Priority     int    `toml:"priority"`
-	MatrixRoom   string `toml:"matrix_room"`   // project-specific Matrix room (optional)
-	BaseBranch   string `toml:"base_branch"`   // branch to create features from (default "main")
-	BranchPrefix string `toml:"branch_prefix"` // prefix for feature branches (default "feat/")
-	UseBranches  bool   `toml:"use_branches"`  // enable branch workflow (default false)
-	MergeMethod  string `toml:"merge_method"`  // squash, merge, rebase (default squash)
-
-	PostMergeChecks     []string `toml:"post_merge_checks"`      // checks run after PR merge
-	AutoRevertOnFailure bool     `toml:"auto_revert_on_failure"` // auto-revert merge when post-merge checks fail (default true)
+	Enabled    bool   `toml:"enabled" doc:"Dispatch work for this project."`
+	Paused     bool   `toml:"paused" doc:"Hold new dispatches for this project, e.g. during a release freeze; running dispatches finish and other projects keep dispatching."`
+	BeadsDir   string `toml:"beads_dir" doc:"Path to the project .beads directory (required when enabled)."`
+	Workspace  string `toml:"workspace" doc:"Path to the project working tree (required when enabled)."`
+	Priority   int    `toml:"priority" doc:"Scheduling priority; lower runs first."`
+	MatrixRoom string `toml:"matrix_room" doc:"Project-specific Matrix room."`
+
+	EmailRecipients []string `toml:"email_recipients" doc:"Report recipients when reporter.channel is email; defaults to reporter.email.default_recipients."`
+	ForgeRepo       string   `toml:"forge_repo" doc:"Forge repository path (e.g. org/repo) whose inbound webhook events belong to this project."`
+	BaseBranch      string   `toml:"base_branch" doc:"Branch to create features from."`
+	BranchPrefix    string   `toml:"branch_prefix" doc:"Prefix for feature branches."`
+	UseBranches     bool     `toml:"use_branches" doc:"Enable the branch workflow."`
+	MergeMethod     string   `toml:"merge_method" doc:"How feature branches are merged." valid:"squash, merge, rebase"`
+
+	PostMergeChecks     []string `toml:"post_merge_checks" doc:"Commands run after a PR merges."`
+	AutoRevertOnFailure bool     `toml:"auto_revert_on_failure" doc:"Revert a merge when post-merge checks fail."`
 
 	// Sprint planning configuration (optional for backward compatibility)
-	SprintPlanningDay  string `toml:"sprint_planning_day"`  // day of week for sprint planning (e.g., "Monday")
-	SprintPlanningTime string `toml:"sprint_planning_time"` // time of day for sprint planning (e.g., "09:00")
-	SprintCapacity     int    `toml:"sprint_capacity"`      // maximum points/tasks per sprint
-	BacklogThreshold   int    `toml:"backlog_threshold"`    // minimum backlog size to maintain
+	SprintPlanningDay  string `toml:"sprint_planning_day" doc:"Day of week for sprint planning (e.g. Monday)."`
+	SprintPlanningTime string `toml:"sprint_planning_time" doc:"Time of day for sprint planning, HH:MM."`
+	SprintCapacity     int    `toml:"sprint_capacity" doc:"Maximum points/tasks per sprint."`
+	BacklogThreshold   int    `toml:"backlog_threshold" doc:"Minimum backlog size to maintain."`
+
+	Shard string `toml:"shard" doc:"Shard that runs this project when sharding is enabled."`
+
+	ChiefSchedule string `toml:"chief_schedule" doc:"Cron schedule on which the chief grooms the backlog, splits epics and proposes a sprint (needs chief.enabled; empty disables)."`
 
 	// Definition of Done configuration
-	DoD DoDConfig `toml:"dod"`
+	DoD DoDConfig `toml:"dod" doc:"Definition of Done checks."`
+
+	RetryPolicy RetryPolicy `toml:"retry_policy" doc:"Project retry policy override."`
+
+	Prompts PromptTemplates `toml:"prompts" doc:"Go-template files overriding the built-in agent prompts per role."`
+
+	Roles map[string]RoleConfig `toml:"roles" doc:"Custom agent roles keyed by name (e.g. security-auditor), usable in workflow stages."`
+
+	BurnIn BurnInSLO `toml:"burnin" doc:"Project burn-in SLO gates; unset fields inherit reporter.burnin.slo."`
+
+	Schedule ProjectSchedule `toml:"schedule" doc:"Working hours and blackout windows limiting when dispatches may start."`
+
+	Artifacts []string `toml:"artifacts" doc:"Glob patterns, relative to the workspace, of files kept after each dispatch (e.g. coverage.out, reports/*.xml)."`
+
+	Autofix ProjectAutofix `toml:"autofix" doc:"Formatter and linter fixes applied before each review."`
+
+	TestWriter ProjectTestWriter `toml:"test_writer" doc:"Test-writer stage run before review when the coder's changes come without tests."`
+
+	HealthChecks map[string]ProjectHealthCheck `toml:"health_checks" doc:"Custom health checks keyed by name, run by the health monitor (e.g. an API smoke test)."`
+}
+
+// ProjectHealthCheck is a command the health monitor runs for a project on an
+// interval. A failing check records a project health event and can hold the
+// project's dispatches until it passes again.
+type ProjectHealthCheck struct {
+	Command       string   `toml:"command" doc:"Shell command run in the project workspace; a non-zero exit is a failure."`
+	Interval      Duration `toml:"interval" doc:"How often the check runs (default health.check_interval)."`
+	Timeout       Duration `toml:"timeout" doc:"Time limit of one run (default 1m)."`
+	AfterDispatch bool     `toml:"after_dispatch" doc:"Also run as soon as a dispatch of the project completes."`
+	Failures      int      `toml:"failures" doc:"Consecutive failed runs before the check counts as failing (default 1)."`
+	Pause         bool     `toml:"pause" doc:"Pause the project's dispatches while the check is failing."`
+}
+
+// ProjectAutofix runs formatters and linters in fix mode after the coder and
+// before the reviewer, so review cycles are not spent on style. Empty
+// commands disable the stage.
+type ProjectAutofix struct {
+	Commands      []string `toml:"commands" doc:"Fix commands run in the work tree (e.g. 'gofmt -w .', 'golangci-lint run --fix')."`
+	Agent         bool     `toml:"agent" doc:"Ask the fast-tier agent to fix style issues the commands still report."`
+	CommitMessage string   `toml:"commit_message" doc:"Message of the commit holding the fixes (default 'style: apply lint and format fixes')."`
+}
+
+// ProjectTestWriter dispatches an agent to write tests when the coder
+// changed code files but no test files, before the change goes to review.
+type ProjectTestWriter struct {
+	Enabled       bool     `toml:"enabled" doc:"Run the test-writer stage for dispatches whose changes come without tests."`
+	Tier          string   `toml:"tier" doc:"Tier whose first agent writes the tests (default balanced)." valid:"fast, balanced, premium"`
+	CodePatterns  []string `toml:"code_patterns" doc:"File name globs of code that needs tests (default common source extensions such as *.go, *.py, *.ts)."`
+	TestPatterns  []string `toml:"test_patterns" doc:"File name globs of test files (default common conventions such as *_test.go, test_*.py, *.test.ts)."`
+	CommitMessage string   `toml:"commit_message" doc:"Message of the commit holding the tests (default 'test: add tests for untested changes')."`
+}
+
+// DefaultTestWriterCodePatterns are the code files the test writer looks
+// for when a project lists none.
+var DefaultTestWriterCodePatterns = []string{
+	"*.go", "*.py", "*.ts", "*.tsx", "*.js", "*.jsx", "*.java", "*.kt", "*.rb", "*.php", "*.cs",
+}
+
+// DefaultTestWriterTestPatterns are the test files the test writer looks
+// for when a project lists none. A file matching both lists is a test.
+var DefaultTestWriterTestPatterns = []string{
+	"*_test.go", "test_*.py", "*_test.py", "*.test.ts", "*.spec.ts", "*.test.tsx", "*.spec.tsx",
+	"*.test.js", "*.spec.js", "*.test.jsx", "*.spec.jsx", "*Test.java", "*Test.kt", "*_spec.rb", "*_test.rb", "*Test.php", "*Tests.cs",
+}
+
+// ProjectSchedule limits when new dispatches for a project may start. Running
+// dispatches are never interrupted. The zero value allows any time.
+type ProjectSchedule struct {
+	Timezone       string           `toml:"timezone" doc:"IANA timezone for working hours and blackouts (default UTC)."`
+	WorkingHours   string           `toml:"working_hours" doc:"Daily window dispatches may start in, HH:MM-HH:MM; wraps midnight when the end is earlier. Empty allows any hour."`
+	WorkingDays    []string         `toml:"working_days" doc:"Days dispatches may start (e.g. Monday); empty allows every day."`
+	Blackouts      []BlackoutWindow `toml:"blackouts" doc:"Periods in which no dispatch may start."`
+	UrgentOverride bool             `toml:"urgent_override" doc:"Let priority-0 bugs start outside working hours and during blackouts."`
+}
+
+// BlackoutWindow is a fixed period in which dispatches are held.
+type BlackoutWindow struct {
+	Start  string `toml:"start" doc:"Blackout start, YYYY-MM-DD HH:MM in the schedule timezone."`
+	End    string `toml:"end" doc:"Blackout end, YYYY-MM-DD HH:MM in the schedule timezone."`
+	Reason string `toml:"reason" doc:"Why dispatches are held; reported when one is refused."`
+}
 
-	RetryPolicy RetryPolicy `toml:"retry_policy"`
+// PromptTemplates points each agent role at a Go text/template file. Relative
+// paths resolve against the project workspace; empty keeps the built-in prompt.
+type PromptTemplates struct {
+	Planner  string `toml:"planner" doc:"Template for the planning prompt."`
+	Coder    string `toml:"coder" doc:"Template for the implementation prompt."`
+	Reviewer string `toml:"reviewer" doc:"Template for the code review prompt."`
+}
+
+// Experiment splits dispatches for one role between variants so the learner can
+// compare their outcomes.
+type Experiment struct {
+	Enabled  bool                `toml:"enabled" doc:"Assign new dispatches to this experiment's variants."`
+	Role     string              `toml:"role" doc:"Agent role whose prompt or agent is varied." valid:"planner, coder, reviewer"`
+	Projects []string            `toml:"projects" doc:"Limit the experiment to these projects; empty means all."`
+	Variants []ExperimentVariant `toml:"variants" doc:"Competing variants; at least two."`
+}
+
+// ExperimentVariant overrides the prompt template and/or agent for a role.
+type ExperimentVariant struct {
+	Name   string `toml:"name" doc:"Variant name, unique within the experiment."`
+	Prompt string `toml:"prompt" doc:"Prompt template file; empty keeps the project or built-in prompt."`
+	Agent  string `toml:"agent" doc:"Agent CLI for the role (e.g. claude, codex); empty keeps the default."`
+	Weight int    `toml:"weight" doc:"Relative share of dispatches; defaults to 1."`
+}
+
+// AppliesTo reports whether the experiment is active for a project.
+func (e Experiment) AppliesTo(project string) bool {
+	if !e.Enabled {
+		return false
+	}
+	if len(e.Projects) == 0 {
+		return true
+	}
+	for _, p := range e.Projects {
+		if p == project {
+			return true
+		}
+	}
+	return false
+}
+
+// ForRole returns the template path configured for a role, or "".
+func (p PromptTemplates) ForRole(role string) string {
+	switch role {
+	case "planner":
+		return p.Planner
+	case "coder":
+		return p.Coder
+	case "reviewer":
+		return p.Reviewer
+	}
+	return ""
+}
+
+// BuiltinRoles are the agent roles Cortex ships prompts and ROLE.md files for.
+var BuiltinRoles = []string{"scrum", "planner", "coder", "reviewer", "ops"}
+
+// RoleConfig registers a custom agent role for a project. A dispatch of a
+// bead in a stage the role works runs the role's prompt on its preferred
+// backend in place of the coder's.
+type RoleConfig struct {
+	Description string   `toml:"description" doc:"ROLE.md content for the role's agents."`
+	Prompt      string   `toml:"prompt" doc:"Template for the role's implementation prompt; relative paths resolve against the workspace. Empty keeps the coder prompt."`
+	Tier        string   `toml:"tier" doc:"Tier the role's dispatches run at." valid:"fast, balanced, premium"`
+	Provider    string   `toml:"provider" doc:"Provider the role's dispatches run on; takes precedence over tier."`
+	Stages      []string `toml:"stages" doc:"Workflow stages the role works in this project, in place of the role the stage names."`
+}
+
+// StageRole returns the role that works stage in this project: a registered
+// role claiming the stage, else wfRole, the role the workflow names for it.
+func (p Project) StageRole(stage, wfRole string) string {
+	for name, role := range p.Roles {
+		if slices.Contains(role.Stages, stage) {
+			return name
+		}
+	}
+	return wfRole
 }
 
 type RetryPolicy struct {
-	MaxRetries    int      `toml:"max_retries"`
-	InitialDelay  Duration `toml:"initial_delay"`
-	BackoffFactor float64  `toml:"backoff_factor"`
-	MaxDelay      Duration `toml:"max_delay"`
-	EscalateAfter int      `toml:"escalate_after"`
+	MaxRetries    int      `toml:"max_retries" doc:"Maximum retry attempts."`
+	InitialDelay  Duration `toml:"initial_delay" doc:"Delay before the first retry."`
+	BackoffFactor float64  `toml:"backoff_factor" doc:"Multiplier applied to the delay after each retry."`
+	MaxDelay      Duration `toml:"max_delay" doc:"Upper bound on retry delay."`
+	EscalateAfter int      `toml:"escalate_after" doc:"Escalate to a higher tier after this many retries."`
 }
 
 // DoDConfig defines the Definition of Done configuration for a project
 type DoDConfig struct {
-	Checks            []string `toml:"checks"`             // commands to run (e.g. "go test ./...", "go vet ./...")
-	CoverageMin       int      `toml:"coverage_min"`       // optional: fail if coverage < N%
-	RequireEstimate   bool     `toml:"require_estimate"`   // bead must have estimate before closing
-	RequireAcceptance bool     `toml:"require_acceptance"` // bead must have acceptance criteria
+	Preset            string   `toml:"preset" doc:"Embedded preset filling unset DoD fields." valid:"go, node, python, rust"`
+	Checks            []string `toml:"checks" doc:"Commands that must pass (e.g. 'go test ./...')."`
+	CoverageMin       int      `toml:"coverage_min" doc:"Fail when test coverage is below this percentage."`
+	RequireEstimate   bool     `toml:"require_estimate" doc:"Bead must have an estimate before closing."`
+	RequireAcceptance bool     `toml:"require_acceptance" doc:"Bead must have acceptance criteria."`
+
+	Security DoDSecurity `toml:"security" doc:"Security scans run as a DoD check."`
+	Coverage DoDCoverage `toml:"coverage" doc:"Coverage measured against the project's baseline as a DoD check."`
+}
+
+// DoDCoverage runs a coverage command after the DoD checks and fails DoD when
+// total coverage drops below the project's stored baseline by more than the
+// tolerance, or below coverage_min.
+type DoDCoverage struct {
+	Command   string  `toml:"command" doc:"Command printing total coverage (e.g. 'go test -coverprofile=c.out ./... && go tool cover -func=c.out')."`
+	Pattern   string  `toml:"pattern" doc:"Regexp whose first group captures the total percentage; default reads the last 'total' line."`
+	Tolerance float64 `toml:"tolerance" doc:"Percentage points coverage may fall below the baseline (default 0)."`
+}
+
+// SecurityScanners are the scanners a DoD security check can run; each
+// reports its findings as SARIF.
+var SecurityScanners = []string{"gosec", "trivy", "semgrep"}
+
+// SecuritySeverities are the finding severities, lowest first.
+var SecuritySeverities = []string{"low", "medium", "high", "critical"}
+
+// DoDSecurity runs security scanners after the DoD checks and fails DoD on
+// findings the dispatch introduced at or above a severity.
+type DoDSecurity struct {
+	Scanners []string `toml:"scanners" doc:"Scanners to run in the work tree." valid:"gosec, trivy, semgrep"`
+	Severity string   `toml:"severity" doc:"Lowest severity of a new finding that fails DoD (default high)." valid:"low, medium, high, critical"`
 }
 
 type RateLimits struct {
-	Window5hCap       int            `toml:"window_5h_cap"`
-	WeeklyCap         int            `toml:"weekly_cap"`
-	WeeklyHeadroomPct int            `toml:"weekly_headroom_pct"`
-	Budget            map[string]int `toml:"budget"` // project -> percentage allocation
+	Window5hCap       int            `toml:"window_5h_cap" doc:"Maximum dispatches per rolling 5 hour window."`
+	WeeklyCap         int            `toml:"weekly_cap" doc:"Maximum dispatches per week."`
+	WeeklyHeadroomPct int            `toml:"weekly_headroom_pct" doc:"Percentage of weekly_cap usable before throttling."`
+	Budget            map[string]int `toml:"budget" doc:"Project name to percentage share of capacity; must sum to 100."`
 }
 
 type Provider struct {
-	Tier              string  `toml:"tier"`
-	Authed            bool    `toml:"authed"`
-	Model             string  `toml:"model"`
-	CLI               string  `toml:"cli"`
-	CostInputPerMtok  float64 `toml:"cost_input_per_mtok"`
-	CostOutputPerMtok float64 `toml:"cost_output_per_mtok"`
+	Tier              string  `toml:"tier" doc:"Capability tier." valid:"fast, balanced, premium"`
+	Authed            bool    `toml:"authed" doc:"Provider CLI is authenticated and usable."`
+	Model             string  `toml:"model" doc:"Model identifier passed to the CLI."`
+	CLI               string  `toml:"cli" doc:"Key into [dispatch.cli] selecting the command to run."`
+	CostInputPerMtok  float64 `toml:"cost_input_per_mtok" doc:"USD per million input tokens."`
+	CostOutputPerMtok float64 `toml:"cost_output_per_mtok" doc:"USD per million output tokens."`
+	Warmup            bool    `toml:"warmup" doc:"Ping the CLI at startup and after idle periods to absorb cold-start latency."`
+	RequestsPerMinute int     `toml:"requests_per_minute" doc:"Token-bucket limit on dispatches started per minute; 0 is unlimited."`
+	TokensPerMinute   int     `toml:"tokens_per_minute" doc:"Token-bucket limit on tokens used per minute; 0 is unlimited."`
+	UsageFormat       string  `toml:"usage_format" doc:"Format of the usage report the CLI prints; empty picks the CLI's own format." valid:"claude, codex, openclaw"`
 }
 
 type Tiers struct {
-	Fast     []string `toml:"fast"`
-	Balanced []string `toml:"balanced"`
-	Premium  []string `toml:"premium"`
+	Fast     []string `toml:"fast" doc:"Providers for cheap, quick tasks."`
+	Balanced []string `toml:"balanced" doc:"Providers for typical tasks."`
+	Premium  []string `toml:"premium" doc:"Providers for complex tasks."`
 }
 
 type WorkflowConfig struct {
-	MatchLabels []string      `toml:"match_labels"`
-	MatchTypes  []string      `toml:"match_types"`
-	Stages      []StageConfig `toml:"stages"`
+	MatchLabels []string      `toml:"match_labels" doc:"Bead labels selecting this workflow."`
+	MatchTypes  []string      `toml:"match_types" doc:"Bead types selecting this workflow."`
+	Stages      []StageConfig `toml:"stages" doc:"Ordered stages; each names a role."`
 }
 
+// StageConfig is one workflow stage. The transition fields guard moving a
+// bead out of this stage.
 type StageConfig struct {
-	Name string `toml:"name"`
-	Role string `toml:"role"`
+	Name             string   `toml:"name" doc:"Stage name."`
+	Role             string   `toml:"role" doc:"Agent role for the stage."`
+	Next             []string `toml:"next" doc:"Stages a bead may move to from here; empty allows only the following stage, or done after the last."`
+	RequireLabels    []string `toml:"require_labels" doc:"Bead labels required to leave this stage."`
+	RequireChecks    []string `toml:"require_checks" doc:"DoD check commands that must have passed in the bead's latest DoD run to leave this stage."`
+	RequireApproval  bool     `toml:"require_approval" doc:"Leaving this stage needs a named approver."`
+	MaxDuration      Duration `toml:"max_duration" doc:"Longest a bead may stay in this stage before an SLA breach is raised; 0 disables the SLA."`
+	EscalateOnBreach bool     `toml:"escalate_on_breach" doc:"On an SLA breach, run the bead's next dispatch one tier higher."`
+}
+
+// TerminalStage is the implicit stage a bead enters when it leaves its
+// workflow's last stage.
+const TerminalStage = "done"
+
+// MatchWorkflow returns the name of the workflow a bead with labels and
+// beadType follows: the first by name whose match_labels has one of labels,
+// else the first whose match_types has beadType, else "".
+func MatchWorkflow(workflows map[string]WorkflowConfig, labels []string, beadType string) string {
+	names := make([]string, 0, len(workflows))
+	for name, wf := range workflows {
+		if len(wf.Stages) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, label := range workflows[name].MatchLabels {
+			if slices.Contains(labels, label) {
+				return name
+			}
+		}
+	}
+	for _, name := range names {
+		if beadType != "" && slices.Contains(workflows[name].MatchTypes, beadType) {
+			return name
+		}
+	}
+	return ""
 }
 
 type Health struct {
-	CheckInterval          Duration `toml:"check_interval"`
-	GatewayUnit            string   `toml:"gateway_unit"`
-	GatewayUserService     bool     `toml:"gateway_user_service"`     // use `systemctl --user` instead of system scope
-	ConcurrencyWarningPct  float64  `toml:"concurrency_warning_pct"`  // alert threshold (default 0.80)
-	ConcurrencyCriticalPct float64  `toml:"concurrency_critical_pct"` // critical threshold (default 0.95)
+	CheckInterval          Duration        `toml:"check_interval" doc:"How often health checks run."`
+	GatewayUnit            string          `toml:"gateway_unit" doc:"systemd unit of the agent gateway."`
+	GatewayUserService     bool            `toml:"gateway_user_service" doc:"Use systemctl --user instead of system scope."`
+	ConcurrencyWarningPct  float64         `toml:"concurrency_warning_pct" doc:"Concurrency utilization that raises a warning (0-1)."`
+	ConcurrencyCriticalPct float64         `toml:"concurrency_critical_pct" doc:"Concurrency utilization that raises a critical alert (0-1)."`
+	Rollout                HealthRollout   `toml:"rollout" doc:"Rollout completion criteria."`
+	Host                   HealthHost      `toml:"host" doc:"Host resource guardrails checked every tick."`
+	Events                 HealthEvents    `toml:"events" doc:"Severity of health event types and where events of each severity are sent."`
+	QueueWait              HealthQueueWait `toml:"queue_wait" doc:"Wait-time SLOs for the concurrency overflow queue."`
+	StuckCommands          HealthStuck     `toml:"stuck_commands" doc:"Remediation for external commands that keep timing out."`
+}
+
+// HealthStuck sets when an external command (bd, gh, git or a DoD check)
+// that keeps timing out counts as stuck. A stuck command records a
+// command_stuck health event and, with Restart, shuts cortex down so its
+// supervisor starts it afresh.
+type HealthStuck struct {
+	Breaches int  `toml:"breaches" doc:"Consecutive timed-out runs of one command that make it stuck (default 3, 0 off)."`
+	Restart  bool `toml:"restart" doc:"Shut down gracefully once a command is stuck, for the supervisor to restart cortex."`
+}
+
+// HealthQueueWait sets how long work may wait in the overflow queue for a
+// free concurrency slot. When the p95 wait of a role over the window, or the
+// wait of an item still queued, exceeds the role's SLO a health event is
+// raised.
+type HealthQueueWait struct {
+	SLO    Duration            `toml:"slo" doc:"Longest p95 queue wait for any role; 0 disables the check."`
+	Roles  map[string]Duration `toml:"roles" doc:"Per-role SLOs keyed by role, overriding slo."`
+	Window Duration            `toml:"window" doc:"Span of recent waits the percentiles cover (default 1h)."`
+}
+
+// RoleSLO returns the queue wait SLO for role, zero when it has none.
+func (q HealthQueueWait) RoleSLO(role string) time.Duration {
+	if slo, ok := q.Roles[role]; ok {
+		return slo.Duration
+	}
+	return q.SLO.Duration
+}
+
+// HealthSeverities are the health event severities, lowest first.
+var HealthSeverities = []string{"info", "warn", "critical"}
+
+// HealthEvents sets the severity health events are recorded with and routes
+// them by severity. Every event is stored in the state DB; routing only adds
+// delivery.
+type HealthEvents struct {
+	Severities map[string]string `toml:"severities" doc:"Severity of event types keyed by type, overriding the built-in ones." valid:"info, warn, critical"`
+	Matrix     []string          `toml:"matrix" doc:"Severities posted to the project's Matrix room, or reporter.default_room for instance-wide events." valid:"info, warn, critical"`
+	Webhooks   []string          `toml:"webhooks" doc:"Severities posted to the reporter webhooks." valid:"info, warn, critical"`
+}
+
+// HealthHost sets the resources a dispatch host must keep free. They are
+// checked every tick; while one is breached new dispatches are held. A zero
+// limit disables its check.
+type HealthHost struct {
+	MinFreeDiskMB   int      `toml:"min_free_disk_mb" doc:"Free space required on the volumes of every project workspace, state_db, dispatch.log_dir and paths (default 2048)."`
+	MinFreeMemoryMB int      `toml:"min_free_memory_mb" doc:"Available memory required (default 512). Linux only."`
+	MaxLoadPerCPU   float64  `toml:"max_load_per_cpu" doc:"Highest 1-minute load average per CPU (default 0, off). Linux only."`
+	Paths           []string `toml:"paths" doc:"Extra directories whose volumes need min_free_disk_mb, e.g. the log directory."`
+}
+
+// HealthRollout defines when a rollout counts as complete: its critical
+// beads are closed, critical health events stayed within the limit over the
+// window, and, if required, the latest burn-in report passes.
+type HealthRollout struct {
+	Enabled           bool     `toml:"enabled" doc:"Evaluate rollout completion on a schedule and record it as a health event."`
+	Schedule          string   `toml:"schedule" doc:"Cron schedule for the scheduled evaluation."`
+	Project           string   `toml:"project" doc:"Project whose beads hold the critical beads."`
+	CriticalBeads     []string `toml:"critical_beads" doc:"Bead IDs that must be closed for the rollout to be complete."`
+	Window            Duration `toml:"window" doc:"History checked for critical health events."`
+	MaxCriticalEvents int      `toml:"max_critical_events" doc:"Most critical health events allowed in the window."`
+	RequireBurnIn     bool     `toml:"require_burnin" doc:"Also require the latest burn-in report to pass."`
+}
+
+// Temporal configures the Temporal connection shared by the worker, the cron
+// registrations and the API.
+type Temporal struct {
+	Host      string            `toml:"host" doc:"Temporal frontend host."`
+	Port      int               `toml:"port" doc:"Temporal frontend port."`
+	Namespace string            `toml:"namespace" doc:"Temporal namespace."`
+	TaskQueue string            `toml:"task_queue" doc:"Task queue the worker polls and workflows are started on; with sharding enabled the shard name is appended."`
+	TLS       TemporalTLS       `toml:"tls" doc:"mTLS client certificates; unset connects without TLS."`
+	Schedules TemporalSchedules `toml:"schedules" doc:"Cron schedules of the built-in Temporal crons."`
+}
+
+// Address is the host:port to dial.
+func (t Temporal) Address() string {
+	return net.JoinHostPort(t.Host, strconv.Itoa(t.Port))
+}
+
+// TemporalTLS holds the client certificate for mTLS to the Temporal frontend.
+type TemporalTLS struct {
+	CertFile   string `toml:"cert_file" doc:"Client certificate (PEM)."`
+	KeyFile    string `toml:"key_file" doc:"Client private key (PEM)."`
+	CAFile     string `toml:"ca_file" doc:"CA bundle for the server certificate (PEM); system roots when unset."`
+	ServerName string `toml:"server_name" doc:"Server name to verify; defaults to host."`
+}
+
+// Enabled reports whether the connection should use TLS.
+func (t TemporalTLS) Enabled() bool {
+	return t.CertFile != "" || t.CAFile != ""
+}
+
+// TemporalSchedules are the cron schedules of the built-in Temporal crons.
+type TemporalSchedules struct {
+	StrategicGroom       string `toml:"strategic_groom" doc:"Per-project strategic groom."`
+	FailureClusterReport string `toml:"failure_cluster_report" doc:"Cross-project failure clustering report."`
 }
 
 type Reporter struct {
-	Channel          string `toml:"channel"`
-	AgentID          string `toml:"agent_id"`
-	MatrixBotAccount string `toml:"matrix_bot_account"` // optional OpenClaw matrix account id for direct reporting
-	DefaultRoom      string `toml:"default_room"`       // fallback Matrix room when project has no explicit room
-	DailyDigestTime  string `toml:"daily_digest_time"`
-	WeeklyRetroDay   string `toml:"weekly_retro_day"`
+	Channel          string `toml:"channel" doc:"Reporting channel." valid:"matrix, email"`
+	AgentID          string `toml:"agent_id" doc:"Agent that delivers reports."`
+	MatrixBotAccount string `toml:"matrix_bot_account" doc:"OpenClaw Matrix account id for direct reporting."`
+	DefaultRoom      string `toml:"default_room" doc:"Fallback Matrix room when a project has none."`
+	DailyDigestTime  string `toml:"daily_digest_time" doc:"Time of the daily standup digest, HH:MM UTC; empty disables it."`
+	WeeklyRetroDay   string `toml:"weekly_retro_day" doc:"Day of the weekly retrospective."`
+
+	BurnIn ReporterBurnIn `toml:"burnin" doc:"Scheduled burn-in evidence reports scored against SLO gates."`
+	Cost   ReporterCost   `toml:"cost" doc:"Scheduled monthly cost reports for chargeback."`
+	Email  ReporterEmail  `toml:"email" doc:"SMTP delivery used when channel is email."`
+
+	Webhooks []ReporterWebhook `toml:"webhooks" doc:"URLs that also receive every report as a signed JSON POST."`
+	Throttle ReporterThrottle  `toml:"throttle" doc:"Deduplication and per-room rate limits for reports."`
+}
+
+// ReporterThrottle keeps an incident from flooding a room: a report
+// identical to one the room got within dedup_window is dropped, and reports
+// past room_limit per rate_period are held and sent as one digest per
+// digest_interval.
+type ReporterThrottle struct {
+	Enabled        bool     `toml:"enabled" doc:"Throttle reports."`
+	DedupWindow    Duration `toml:"dedup_window" doc:"Drop a report identical to one sent to the same room within this window (default 10m)."`
+	RoomLimit      int      `toml:"room_limit" doc:"Reports sent to a room per rate_period before the rest are held (default 5)."`
+	RatePeriod     Duration `toml:"rate_period" doc:"Period room_limit applies to (default 1m)."`
+	DigestInterval Duration `toml:"digest_interval" doc:"How often a room's held reports are sent as one digest (default 5m)."`
+}
+
+// ReporterWebhook posts reports to an HTTP endpoint alongside the channel.
+type ReporterWebhook struct {
+	URL      string   `toml:"url" doc:"Endpoint receiving the POST."`
+	Secret   string   `toml:"secret" doc:"HMAC-SHA256 key signing the body in X-Cortex-Signature; use a ${secret:NAME} reference."`
+	Template string   `toml:"template" doc:"Go template rendering the request body from .Project, .Room, .Message, .Severity and .Timestamp; the json function quotes a value. Empty sends the default JSON."`
+	Projects []string `toml:"projects" doc:"Only forward reports of these projects; empty forwards all."`
+	Timeout  Duration `toml:"timeout" doc:"Request timeout."`
+}
+
+// ReporterEmail configures the SMTP reporter. Reports go to the project's
+// email_recipients, falling back to default_recipients.
+type ReporterEmail struct {
+	SMTPHost          string   `toml:"smtp_host" doc:"SMTP server host."`
+	SMTPPort          int      `toml:"smtp_port" doc:"SMTP server port."`
+	Username          string   `toml:"username" doc:"SMTP username; empty skips authentication."`
+	Password          string   `toml:"password" doc:"SMTP password; use a ${secret:NAME} reference."`
+	From              string   `toml:"from" doc:"Sender address."`
+	DefaultRecipients []string `toml:"default_recipients" doc:"Recipients for projects without email_recipients."`
+	SubjectPrefix     string   `toml:"subject_prefix" doc:"Prefix of every subject line."`
+	BatchInterval     Duration `toml:"batch_interval" doc:"Collect reports per recipient list and send one digest email per interval; 0 sends each report at once."`
+}
+
+// ReporterBurnIn controls the burn-in evidence report: dispatch reliability
+// and operator intervention metrics per project, scored against SLO gates and
+// written as JSON and Markdown artifacts.
+type ReporterBurnIn struct {
+	Enabled    bool      `toml:"enabled" doc:"Generate burn-in reports on a schedule."`
+	Schedule   string    `toml:"schedule" doc:"Cron schedule for report generation."`
+	Window     Duration  `toml:"window" doc:"History covered by each report, ending when it runs."`
+	ReportsDir string    `toml:"reports_dir" doc:"Directory for report artifacts; defaults to reports/ beside state_db."`
+	SLO        BurnInSLO `toml:"slo" doc:"Default SLO gates; projects override them under [projects.<name>.burnin]."`
+}
+
+// ReporterCost controls the monthly cost report: spend per project, broken
+// down by role, provider and tier, written as JSON and Markdown artifacts.
+type ReporterCost struct {
+	Enabled    bool   `toml:"enabled" doc:"Generate cost reports on a schedule."`
+	Schedule   string `toml:"schedule" doc:"Cron schedule for report generation; each run reports the previous calendar month."`
+	ReportsDir string `toml:"reports_dir" doc:"Directory for report artifacts; defaults to reports/ beside state_db."`
+}
+
+// BurnInSLO is a set of burn-in gates. Each metric must stay below its limit
+// to pass; zero inherits the default.
+type BurnInSLO struct {
+	UnknownRateLimit      float64 `toml:"unknown_rate_limit" doc:"Fail when the share of dispatches ending interrupted or with an unknown outcome reaches this (0-1)."`
+	InterventionRateLimit float64 `toml:"intervention_rate_limit" doc:"Fail when the share of dispatches cancelled or manually retried reaches this (0-1)."`
+	CriticalEventLimit    int     `toml:"critical_event_limit" doc:"Fail when this many critical health events occur in the window."`
 }
 
 type Learner struct {
-	Enabled         bool     `toml:"enabled"`
-	AnalysisWindow  Duration `toml:"analysis_window"`
-	CycleInterval   Duration `toml:"cycle_interval"`
-	IncludeInDigest bool     `toml:"include_in_digest"`
+	Enabled         bool     `toml:"enabled" doc:"Run periodic learner analysis."`
+	AnalysisWindow  Duration `toml:"analysis_window" doc:"History window analyzed each cycle."`
+	CycleInterval   Duration `toml:"cycle_interval" doc:"Time between learner cycles."`
+	IncludeInDigest bool     `toml:"include_in_digest" doc:"Include learner findings in the daily digest."`
 }
 
 // Matrix configures inbound Matrix polling for scrum master routing.
 type Matrix struct {
-	Enabled      bool     `toml:"enabled"`
-	PollInterval Duration `toml:"poll_interval"`
-	BotUser      string   `toml:"bot_user"`
-	ReadLimit    int      `toml:"read_limit"`
+	Enabled      bool     `toml:"enabled" doc:"Poll project Matrix rooms for inbound messages."`
+	PollInterval Duration `toml:"poll_interval" doc:"Time between polls."`
+	BotUser      string   `toml:"bot_user" doc:"Matrix user id of the Cortex bot; its own messages are ignored."`
+	ReadLimit    int      `toml:"read_limit" doc:"Messages read per request; the E2E reader pages back until it reaches the last message seen."`
+	MediaBaseURL string   `toml:"media_base_url" doc:"Homeserver base URL for downloading mxc:// attachments; the only private-network host attachment downloads may reach."`
+	E2EProxy     string   `toml:"e2e_proxy" doc:"Base URL of an E2E-capable Matrix proxy such as pantalaimon; when set, messages are read and sent through it so encrypted rooms work."`
+
+	CommandSenders []string            `toml:"command_senders" doc:"Matrix users allowed to run commands; empty allows anyone in a project room."`
+	CommandACL     map[string][]string `toml:"command_acl" doc:"Matrix users allowed to run each /cortex command, keyed by command name; \"*\" allows anyone. Overrides command_senders for that command."`
 }
 
 type API struct {
-	Bind     string      `toml:"bind"`
-	Security APISecurity `toml:"security"`
+	Bind          string            `toml:"bind" doc:"Listen address."`
+	Security      APISecurity       `toml:"security" doc:"Authentication for control endpoints."`
+	Endpoints     map[string]string `toml:"endpoints" doc:"Named API base URLs that cortexctl status --all queries alongside this daemon, e.g. chum = \"http://chum-host:8900\"."`
+	ForgeWebhooks APIForgeWebhooks  `toml:"forge_webhooks" doc:"Inbound GitHub and GitLab webhooks that advance beads on review, merge, and check events."`
+}
+
+// APIForgeWebhooks holds the shared secrets forges sign inbound webhooks
+// with. A forge without a secret has its webhook endpoint disabled.
+type APIForgeWebhooks struct {
+	GitHubSecret string `toml:"github_secret" doc:"Secret GitHub signs deliveries with (X-Hub-Signature-256)."`
+	GitLabToken  string `toml:"gitlab_token" doc:"Token GitLab sends in X-Gitlab-Token."`
 }
 
 type APISecurity struct {
-	Enabled          bool     `toml:"enabled"`            // Enable auth for control endpoints
-	AllowedTokens    []string `toml:"allowed_tokens"`     // Valid API tokens for auth
-	RequireLocalOnly bool     `toml:"require_local_only"` // Only allow local connections when auth disabled
-	AuditLog         string   `toml:"audit_log"`          // Path to audit log file
+	Enabled          bool     `toml:"enabled" doc:"Require a bearer token on control endpoints."`
+	AllowedTokens    []string `toml:"allowed_tokens" doc:"Accepted API tokens."`
+	RequireLocalOnly bool     `toml:"require_local_only" doc:"Only allow local connections when auth is disabled."`
+	AuditLog         string   `toml:"audit_log" doc:"Path to the audit log file."`
 }
 
 type Dispatch struct {
-	CLI              map[string]CLIConfig `toml:"cli"`
-	Routing          DispatchRouting      `toml:"routing"`
-	Timeouts         DispatchTimeouts     `toml:"timeouts"`
-	Git              DispatchGit          `toml:"git"`
-	Tmux             DispatchTmux         `toml:"tmux"`
-	CostControl      DispatchCostControl  `toml:"cost_control"`
-	LogDir           string               `toml:"log_dir"`
-	LogRetentionDays int                  `toml:"log_retention_days"`
+	CLI              map[string]CLIConfig    `toml:"cli" doc:"CLI command definitions, keyed by name referenced from providers."`
+	Routing          DispatchRouting         `toml:"routing" doc:"Backend per tier."`
+	Timeouts         DispatchTimeouts        `toml:"timeouts" doc:"Per-tier dispatch timeouts."`
+	Git              DispatchGit             `toml:"git" doc:"Branch and merge settings for dispatches."`
+	Tmux             DispatchTmux            `toml:"tmux" doc:"tmux backend settings."`
+	CostControl      DispatchCostControl     `toml:"cost_control" doc:"Policies that limit expensive usage and churn."`
+	Warmup           DispatchWarmup          `toml:"warmup" doc:"Cold-start warmup pings for providers with warmup = true."`
+	StalledReview    DispatchStalledReview   `toml:"stalled_review" doc:"Nudges for Cortex PRs waiting on review."`
+	BranchJanitor    DispatchBranchJanitor   `toml:"branch_janitor" doc:"Cleanup of feature branches left behind by closed or abandoned beads."`
+	StageSLA         DispatchStageSLA        `toml:"stage_sla" doc:"Checks of workflow stage max_duration limits."`
+	Confidence       DispatchConfidence      `toml:"confidence" doc:"Auto-close gated on the confidence agents report."`
+	Pair             DispatchPair            `toml:"pair" doc:"Pair mode: coder and reviewer alternate turns in one session."`
+	CircuitBreakers  DispatchCircuitBreakers `toml:"circuit_breakers" doc:"Breakers that stop dispatching after repeated failures."`
+	Artifacts        DispatchArtifacts       `toml:"artifacts" doc:"Storage of the files matched by each project's artifacts patterns."`
+	Worktrees        DispatchWorktrees       `toml:"worktrees" doc:"Pool of git worktrees giving each concurrent dispatch its own checkout."`
+	Journal          DispatchJournal         `toml:"journal" doc:"JSONL journal of dispatch admissions, denials and skips."`
+	EpicRollup       DispatchEpicRollup      `toml:"epic_rollup" doc:"Epic progress rollup and auto-close once enough children are closed."`
+	Triage           DispatchTriage          `toml:"triage" doc:"Backlog triage labeling duplicate and stale beads."`
+	Complexity       DispatchComplexity      `toml:"complexity" doc:"Starting tier detection from bead features."`
+	FileOverlap      DispatchFileOverlap     `toml:"file_overlap" doc:"Check for ready beads that touch the same files."`
+	Claims           DispatchClaims          `toml:"claims" doc:"Bead claims shared between cortex instances."`
+	ResponseCache    DispatchResponseCache   `toml:"response_cache" doc:"Reuse of agent answers to identical planning prompts."`
+	Redaction        DispatchRedaction       `toml:"redaction" doc:"Scrubbing of secrets from agent output before it is stored or logged."`
+	Approvals        DispatchApprovals       `toml:"approvals" doc:"Approval prompts posted to project rooms for actions that wait on a human."`
+	Preflight        DispatchPreflight       `toml:"preflight" doc:"Connectivity probe run before a provider is reserved."`
+	FollowUps        DispatchFollowUps       `toml:"follow_ups" doc:"Beads created from the follow-ups agents report."`
+	LogDir           string                  `toml:"log_dir" doc:"Directory for dispatch logs."`
+	LogRetentionDays int                     `toml:"log_retention_days" doc:"Days to keep dispatch logs."`
+}
+
+// DispatchEpicRollup controls the check that closes an epic once enough of
+// its executable (non-epic) children are closed.
+type DispatchEpicRollup struct {
+	Enabled   bool    `toml:"enabled" doc:"Close epics automatically and post their progress."`
+	Schedule  string  `toml:"schedule" doc:"Cron schedule for the epic rollup."`
+	Threshold float64 `toml:"threshold" doc:"Fraction (0-1] of an epic's executable children that must be closed to close the epic (default 1)."`
+}
+
+// DispatchTriage controls the backlog triage, which labels near-duplicate
+// and long-untouched open beads so they drop out of the dispatch pool.
+type DispatchTriage struct {
+	Enabled    bool     `toml:"enabled" doc:"Label duplicate and stale beads on a schedule."`
+	Schedule   string   `toml:"schedule" doc:"Cron schedule for the triage."`
+	Similarity float64  `toml:"similarity" doc:"Title/description similarity (0-1] at which a newer bead is a duplicate of an older one (default 0.8)."`
+	StaleAfter Duration `toml:"stale_after" doc:"Label open beads not updated for this long as stale (default 720h); 0 disables."`
+}
+
+// DispatchComplexity controls starting tier detection for API dispatches
+// that name no agent or provider. Once enough beads have finished, a model
+// learned from their features and outcomes picks the tier; until then the
+// heuristics on estimate and type do.
+type DispatchComplexity struct {
+	Enabled    bool     `toml:"enabled" doc:"Pick the starting tier of dispatches from the bead's features."`
+	MinSamples int      `toml:"min_samples" doc:"Finished beads the learned model needs before it replaces the heuristics (default 20)."`
+	Window     Duration `toml:"window" doc:"History the model learns from (default 2160h)."`
+	Retrain    Duration `toml:"retrain" doc:"How long a trained model is used before it is retrained (default 1h)."`
+}
+
+// DispatchClaims controls the bead claims that keep cortex instances sharing
+// projects from dispatching the same bead. A dispatch claims its bead before
+// it starts and holds the claim until the workflow ends.
+type DispatchClaims struct {
+	Enabled       bool     `toml:"enabled" doc:"Claim each bead before dispatching it."`
+	Backend       string   `toml:"backend" doc:"Where claims are held: sqlite shares them through state_db, etcd through an etcd cluster (default sqlite)." valid:"sqlite, etcd"`
+	InstanceID    string   `toml:"instance_id" doc:"Name this instance claims beads and leadership under; defaults to the hostname."`
+	TTL           Duration `toml:"ttl" doc:"How long a claim lasts without renewal (default 10m); running dispatches renew it every third of this."`
+	EtcdEndpoints []string `toml:"etcd_endpoints" doc:"etcd client URLs, e.g. http://etcd-1:2379 (required for the etcd backend)."`
+	EtcdPrefix    string   `toml:"etcd_prefix" doc:"Key prefix for claims in etcd (default /cortex/claims/)."`
+}
+
+// DispatchResponseCache lets planning roles reuse an agent's answer to an
+// identical prompt instead of paying for it again. Coders and reviewers are
+// never cached.
+type DispatchResponseCache struct {
+	Enabled bool     `toml:"enabled" doc:"Answer repeated planning prompts from the cache."`
+	TTL     Duration `toml:"ttl" doc:"How long a cached answer is reused (default 6h)."`
+	Roles   []string `toml:"roles" doc:"Roles whose prompts are cached (default all three)." valid:"planner, groom, chief"`
+}
+
+// DispatchRedaction controls the pass that replaces tokens, keys and
+// connection string passwords in agent output with RedactedPlaceholder
+// before the output is stored or logged.
+type DispatchRedaction struct {
+	Enabled          bool     `toml:"enabled" doc:"Redact known secret formats from captured output and logs."`
+	Patterns         []string `toml:"patterns" doc:"Extra regular expressions to redact. With a capture group only the first group is replaced."`
+	Entropy          bool     `toml:"entropy" doc:"Also redact long random-looking tokens."`
+	EntropyMinLength int      `toml:"entropy_min_length" doc:"Shortest token checked for entropy (default 24)."`
+	EntropyThreshold float64  `toml:"entropy_threshold" doc:"Shannon entropy in bits per character above which a token is redacted (default 4.0)."`
+}
+
+// DispatchApprovals controls the chat prompts for actions that wait on a
+// human, such as plan approval: the prompt is posted to the project room, an
+// authorized user answers with /cortex approve or /cortex deny, and
+// default_action is taken when nobody answers within the timeout.
+type DispatchApprovals struct {
+	Chat          bool     `toml:"chat" doc:"Post approval prompts to the project's Matrix room."`
+	Timeout       Duration `toml:"timeout" doc:"How long to wait for an answer before taking default_action; 0 waits forever."`
+	DefaultAction string   `toml:"default_action" doc:"Answer taken when the timeout passes (default deny)." valid:"approve, deny"`
+}
+
+// DispatchFollowUps controls the beads created from the follow_ups an agent
+// lists in its completion block. Each is linked discovered-from the bead the
+// agent worked on and labeled for triage.
+type DispatchFollowUps struct {
+	Enabled  bool `toml:"enabled" doc:"Create a bead for each follow-up an agent reports."`
+	Max      int  `toml:"max" doc:"Most beads created from one dispatch (default 5)."`
+	Priority int  `toml:"priority" doc:"Priority of the created beads, 0-4 (default 3)."`
+}
+
+// DispatchFileOverlap controls the file overlap check, which maps beads to
+// the files their plans, commits and branches touch and flags ready beads
+// that share files before their branches conflict at merge.
+type DispatchFileOverlap struct {
+	Enabled      bool   `toml:"enabled" doc:"Check ready beads for shared files on a schedule."`
+	Schedule     string `toml:"schedule" doc:"Cron schedule for the check."`
+	Mode         string `toml:"mode" doc:"warn reports overlapping beads; serialize also makes the later bead depend on the earlier one (default warn)." valid:"warn, serialize"`
+	LookbackDays int    `toml:"lookback_days" doc:"Days of commit history scanned for bead IDs (default 30)."`
+}
+
+// DispatchJournal controls the decision journal: one JSONL file per UTC day
+// recording why each dispatch was admitted, denied or skipped.
+type DispatchJournal struct {
+	Dir           string `toml:"dir" doc:"Directory for the daily journal files; defaults to journal/ beside state_db."`
+	RetentionDays int    `toml:"retention_days" doc:"Days of journal files to keep (default 30); 0 keeps them all."`
+}
+
+// DispatchWorktrees controls the worktree pool. When enabled, each
+// dispatch works in its own git worktree of the project's workspace instead
+// of the shared checkout.
+type DispatchWorktrees struct {
+	Enabled  bool   `toml:"enabled" doc:"Check each dispatch out into its own git worktree."`
+	Dir      string `toml:"dir" doc:"Directory holding the pooled worktrees; defaults to worktrees/ beside state_db."`
+	PoolSize int    `toml:"pool_size" doc:"Worktrees per project; defaults to dispatch.git.max_concurrent_per_project. Dispatches beyond it use the shared workspace."`
+}
+
+// DispatchArtifacts controls where collected dispatch artifacts are stored
+// and how long they are kept.
+type DispatchArtifacts struct {
+	Dir           string `toml:"dir" doc:"Directory for collected artifacts; defaults to artifacts/ beside state_db."`
+	RetentionDays int    `toml:"retention_days" doc:"Days to keep collected artifacts (default 14)."`
+	MaxFileMB     int    `toml:"max_file_mb" doc:"Files larger than this are skipped (default 50)."`
 }
 
 type CLIConfig struct {
-	Cmd           string   `toml:"cmd"`
-	PromptMode    string   `toml:"prompt_mode"` // "stdin", "file", "arg"
-	Args          []string `toml:"args"`
-	ModelFlag     string   `toml:"model_flag"`     // e.g. "--model"
-	ApprovalFlags []string `toml:"approval_flags"` // e.g. ["--dangerously-skip-permissions"]
+	Cmd           string   `toml:"cmd" doc:"Executable to run."`
+	PromptMode    string   `toml:"prompt_mode" doc:"How the prompt is passed." valid:"stdin, file, arg"`
+	Args          []string `toml:"args" doc:"Extra arguments."`
+	ModelFlag     string   `toml:"model_flag" doc:"Flag used to select the model (e.g. --model)."`
+	ApprovalFlags []string `toml:"approval_flags" doc:"Flags that skip interactive approval."`
 }
 
 type DispatchRouting struct {
-	FastBackend     string `toml:"fast_backend"` // "headless_cli", "tmux"
-	BalancedBackend string `toml:"balanced_backend"`
-	PremiumBackend  string `toml:"premium_backend"`
-	CommsBackend    string `toml:"comms_backend"`
-	RetryBackend    string `toml:"retry_backend"` // backend for retries
+	FastBackend     string `toml:"fast_backend" doc:"Backend for fast tier dispatches." valid:"headless_cli, tmux"`
+	BalancedBackend string `toml:"balanced_backend" doc:"Backend for balanced tier dispatches." valid:"headless_cli, tmux"`
+	PremiumBackend  string `toml:"premium_backend" doc:"Backend for premium tier dispatches." valid:"headless_cli, tmux"`
+	CommsBackend    string `toml:"comms_backend" doc:"Backend for communication dispatches." valid:"headless_cli, tmux"`
+	RetryBackend    string `toml:"retry_backend" doc:"Backend for retries." valid:"headless_cli, tmux"`
+
+	Rules []RoutingRule `toml:"rules" doc:"Routing rules keyed on bead labels; the first match is applied before tier detection and overrides its defaults."`
+}
+
+// RoutingRule routes beads carrying any of its labels: which tier or
+// provider codes them, who reviews them and whether a review must pass.
+type RoutingRule struct {
+	Labels        []string `toml:"labels" doc:"Bead labels the rule matches; any one of them matches."`
+	Tier          string   `toml:"tier" doc:"Tier whose first agent codes matching beads." valid:"fast, balanced, premium"`
+	Provider      string   `toml:"provider" doc:"Provider that codes matching beads; takes precedence over tier."`
+	Reviewer      string   `toml:"reviewer" doc:"Review agent for matching beads."`
+	RequireReview bool     `toml:"require_review" doc:"A review must approve the change; a review that fails to run blocks the attempt instead of passing it."`
+}
+
+// Rule returns the first routing rule matching one of labels.
+func (r DispatchRouting) Rule(labels []string) (RoutingRule, bool) {
+	for _, rule := range r.Rules {
+		for _, label := range rule.Labels {
+			if slices.ContainsFunc(labels, func(l string) bool { return strings.EqualFold(strings.TrimSpace(l), strings.TrimSpace(label)) }) {
+				return rule, true
+			}
+		}
+	}
+	return RoutingRule{}, false
 }
 
 type DispatchTimeouts struct {
-	Fast     Duration `toml:"fast"`     // default 15m
-	Balanced Duration `toml:"balanced"` // default 45m
-	Premium  Duration `toml:"premium"`  // default 120m
+	Fast     Duration `toml:"fast" doc:"Timeout for fast tier dispatches."`
+	Balanced Duration `toml:"balanced" doc:"Timeout for balanced tier dispatches."`
+	Premium  Duration `toml:"premium" doc:"Timeout for premium tier dispatches."`
 }
 
 type DispatchGit struct {
-	BranchPrefix            string `toml:"branch_prefix"`              // default "cortex/"
-	BranchCleanupDays       int    `toml:"branch_cleanup_days"`        // default 7
-	MergeStrategy           string `toml:"merge_strategy"`             // "merge", "squash", "rebase"
-	MaxConcurrentPerProject int    `toml:"max_concurrent_per_project"` // default 3
+	BranchPrefix            string `toml:"branch_prefix" doc:"Prefix for dispatch branches."`
+	BranchCleanupDays       int    `toml:"branch_cleanup_days" doc:"Delete merged dispatch branches after this many days."`
+	MergeStrategy           string `toml:"merge_strategy" doc:"How dispatch branches are merged." valid:"merge, squash, rebase"`
+	MaxConcurrentPerProject int    `toml:"max_concurrent_per_project" doc:"Maximum concurrent dispatches per project."`
+	DraftPRs                bool   `toml:"draft_prs" doc:"Open dispatch PRs as drafts and mark them ready when the bead enters the review stage."`
 }
 
 type DispatchTmux struct {
-	HistoryLimit  int    `toml:"history_limit"`  // default 50000
-	SessionPrefix string `toml:"session_prefix"` // default "cortex-"
+	HistoryLimit  int    `toml:"history_limit" doc:"tmux scrollback lines per session."`
+	SessionPrefix string `toml:"session_prefix" doc:"Prefix for tmux session names."`
+}
+
+// DispatchWarmup controls cold-start warmup pings for providers with warmup = true.
+type DispatchWarmup struct {
+	IdleAfter Duration `toml:"idle_after" doc:"Re-warm a provider idle longer than this."`
+	Schedule  string   `toml:"schedule" doc:"Cron schedule for the idle check."`
+	Timeout   Duration `toml:"timeout" doc:"Per-ping timeout."`
+}
+
+// DispatchPreflight controls the probe run before a provider is reserved, so
+// a dead gateway is skipped instead of failing full agent sessions until the
+// circuit breaker trips. Results are cached for TTL.
+type DispatchPreflight struct {
+	Enabled bool     `toml:"enabled" doc:"Probe a provider before reserving it and skip it while the probe fails."`
+	Command string   `toml:"command" doc:"Shell command pinging the gateway, run with CORTEX_PROVIDER set; empty sends the warmup prompt through the provider's CLI."`
+	TTL     Duration `toml:"ttl" doc:"How long a probe result is reused (default general.tick_interval)."`
+	Timeout Duration `toml:"timeout" doc:"Per-probe timeout (default 30s)."`
+}
+
+// DispatchStalledReview controls detection of Cortex-opened PRs that sit
+// without reviewer activity.
+type DispatchStalledReview struct {
+	Enabled   bool     `toml:"enabled" doc:"Check open Cortex PRs for stalled reviews."`
+	Threshold Duration `toml:"threshold" doc:"A PR with no review activity for this long is stalled; also the minimum gap between nudges."`
+	Schedule  string   `toml:"schedule" doc:"Cron schedule for the stalled review check."`
+	Reassign  bool     `toml:"reassign" doc:"Have a different reviewer agent review stalled PRs and comment on them."`
+}
+
+// DispatchStageSLA controls the check that flags beads sitting in a workflow
+// stage longer than the stage's max_duration.
+type DispatchStageSLA struct {
+	Schedule string `toml:"schedule" doc:"Cron schedule for the stage SLA check; it runs only when a stage sets max_duration."`
+}
+
+// DispatchBranchJanitor controls deletion of Cortex feature branches whose
+// bead is closed or gone and that have no open PR.
+type DispatchBranchJanitor struct {
+	Enabled      bool     `toml:"enabled" doc:"Delete stale Cortex feature branches."`
+	Schedule     string   `toml:"schedule" doc:"Cron schedule for the branch janitor."`
+	GracePeriod  Duration `toml:"grace_period" doc:"Keep a branch at least this long after its last commit."`
+	Protected    []string `toml:"protected" doc:"Branch name glob patterns never deleted; each project's base branch is always protected."`
+	DeleteRemote bool     `toml:"delete_remote" doc:"Also delete the branch on origin."`
+	DryRun       bool     `toml:"dry_run" doc:"Report the branches that would be deleted without deleting them."`
+}
+
+// DispatchConfidence gates automatic completion on the confidence score the
+// coding agent reports in its output footer.
+type DispatchConfidence struct {
+	AutoClose        bool    `toml:"auto_close" doc:"Close a bead automatically once its dispatch passes review and DoD."`
+	Threshold        float64 `toml:"threshold" doc:"Minimum reported confidence (0-1) to auto-close; lower or missing scores are routed to human review."`
+	VerifyCompletion bool    `toml:"verify_completion" doc:"Auto-close only when a recent commit or merged PR references the bead; otherwise route the completion to human review."`
+}
+
+// DispatchPair controls pair mode, where a coder and a reviewer agent take
+// alternating turns in one session instead of separate execute/review passes.
+type DispatchPair struct {
+	Enabled     bool `toml:"enabled" doc:"Run high-priority beads in pair mode."`
+	MaxPriority int  `toml:"max_priority" doc:"Beads at this priority or higher (0 = highest) are paired."`
+	MaxTurns    int  `toml:"max_turns" doc:"Maximum coder and reviewer turns per pair session."`
+}
+
+// CircuitBreaker opens after Threshold failures within Window and stays open
+// for Cooldown. A Threshold of 0 disables it.
+type CircuitBreaker struct {
+	Threshold int      `toml:"threshold" doc:"Failures within window that open the breaker; 0 disables it."`
+	Window    Duration `toml:"window" doc:"How far back failures count toward the threshold."`
+	Cooldown  Duration `toml:"cooldown" doc:"How long an open breaker stays open before a trial call."`
+}
+
+// DispatchCircuitBreakers configures the per-provider and per-failure-category
+// circuit breakers. An open provider breaker excludes that provider from
+// selection; an open category breaker stops all provider selection.
+type DispatchCircuitBreakers struct {
+	Provider   CircuitBreaker            `toml:"provider" doc:"Breaker applied to every provider."`
+	Providers  map[string]CircuitBreaker `toml:"providers" doc:"Per-provider overrides of the provider breaker, keyed by provider name."`
+	Categories map[string]CircuitBreaker `toml:"categories" doc:"Breakers keyed by failure category (e.g. gateway_closed, rate_limited, auth, timeout)."`
+}
+
+// ForProvider returns the breaker that applies to the named provider.
+func (c DispatchCircuitBreakers) ForProvider(name string) CircuitBreaker {
+	if b, ok := c.Providers[name]; ok {
+		return b
+	}
+	return c.Provider
 }
 
 // DispatchCostControl defines configurable dispatch policies to reduce expensive usage/churn.
 type DispatchCostControl struct {
-	Enabled                     bool     `toml:"enabled"`
-	SparkFirst                  bool     `toml:"spark_first"`
-	RetryEscalationAttempt      int      `toml:"retry_escalation_attempt"`
-	ComplexityEscalationMinutes int      `toml:"complexity_escalation_minutes"`
-	RiskyReviewLabels           []string `toml:"risky_review_labels"`
-	ForceSparkAtWeeklyUsagePct  float64  `toml:"force_spark_at_weekly_usage_pct"`
-	DailyCostCapUSD             float64  `toml:"daily_cost_cap_usd"`
-	PerBeadCostCapUSD           float64  `toml:"per_bead_cost_cap_usd"`
-	PerBeadStageAttemptLimit    int      `toml:"per_bead_stage_attempt_limit"`
-	StageAttemptWindow          Duration `toml:"stage_attempt_window"`
-	StageCooldown               Duration `toml:"stage_cooldown"`
+	Enabled                     bool     `toml:"enabled" doc:"Enable cost-control policies."`
+	SparkFirst                  bool     `toml:"spark_first" doc:"Try the cheapest provider first."`
+	RetryEscalationAttempt      int      `toml:"retry_escalation_attempt" doc:"Retry attempt at which to escalate tier."`
+	ComplexityEscalationMinutes int      `toml:"complexity_escalation_minutes" doc:"Estimates above this many minutes start at a higher tier."`
+	RiskyReviewLabels           []string `toml:"risky_review_labels" doc:"Labels that force a premium review."`
+	ForceSparkAtWeeklyUsagePct  float64  `toml:"force_spark_at_weekly_usage_pct" doc:"Weekly usage percentage at which only the cheapest provider is used."`
+	DailyCostCapUSD             float64  `toml:"daily_cost_cap_usd" doc:"Daily spend cap in USD; 0 disables."`
+	PerBeadCostCapUSD           float64  `toml:"per_bead_cost_cap_usd" doc:"Per-bead spend cap in USD; 0 disables."`
+	PerBeadStageAttemptLimit    int      `toml:"per_bead_stage_attempt_limit" doc:"Maximum attempts per bead stage within stage_attempt_window; 0 disables."`
+	StageAttemptWindow          Duration `toml:"stage_attempt_window" doc:"Window for per_bead_stage_attempt_limit."`
+	StageCooldown               Duration `toml:"stage_cooldown" doc:"Cooldown after a bead stage hits its attempt limit."`
+
+	// Caps enforced while agents run: a run that crosses one is stopped.
+	PerDispatchCostCapUSD float64            `toml:"per_dispatch_cost_cap_usd" doc:"Spend cap in USD for one dispatch, across its agent runs; 0 disables."`
+	RoleCostCapsUSD       map[string]float64 `toml:"role_cost_caps_usd" doc:"Spend caps in USD for a single agent run, keyed by role; 0 disables." valid:"coder, reviewer"`
 
 	// Escalation pause controls for system-level churn/token waste.
-	PauseOnChurn      bool     `toml:"pause_on_churn"`
-	ChurnPauseWindow  Duration `toml:"churn_pause_window"`
-	ChurnPauseFailure int      `toml:"churn_pause_failure_threshold"`
-	ChurnPauseTotal   int      `toml:"churn_pause_total_threshold"`
+	PauseOnChurn      bool     `toml:"pause_on_churn" doc:"Pause dispatching when failure churn exceeds thresholds."`
+	ChurnPauseWindow  Duration `toml:"churn_pause_window" doc:"Window used for churn thresholds."`
+	ChurnPauseFailure int      `toml:"churn_pause_failure_threshold" doc:"Failures within the window that trigger a pause."`
+	ChurnPauseTotal   int      `toml:"churn_pause_total_threshold" doc:"Dispatches within the window that trigger a pause."`
 
-	PauseOnTokenWastage bool     `toml:"pause_on_token_waste"`
-	TokenWasteWindow    Duration `toml:"token_waste_window"`
+	PauseOnTokenWastage bool     `toml:"pause_on_token_waste" doc:"Pause dispatching when token waste is detected."`
+	TokenWasteWindow    Duration `toml:"token_waste_window" doc:"Window used for token waste detection."`
 }
 
 type Chief struct {
-	Enabled             bool   `toml:"enabled"`               // Enable Chief Scrum Master
-	MatrixRoom          string `toml:"matrix_room"`           // Matrix room for coordination
-	Model               string `toml:"model"`                 // Model to use (defaults to premium)
-	AgentID             string `toml:"agent_id"`              // Agent identifier (defaults to "cortex-chief-scrum")
-	RequireApprovedPlan bool   `toml:"require_approved_plan"` // Block implementation dispatch without active approved plan
+	Enabled             bool   `toml:"enabled" doc:"Enable the Chief Scrum Master."`
+	MatrixRoom          string `toml:"matrix_room" doc:"Matrix room for coordination."`
+	Model               string `toml:"model" doc:"Model for the chief agent."`
+	AgentID             string `toml:"agent_id" doc:"Chief agent identifier."`
+	RequireApprovedPlan bool   `toml:"require_approved_plan" doc:"Block implementation dispatch without an active approved plan."`
 }
 
 // Clone returns a deep copy of cfg so callers can safely mutate the result.
@@ -279,6 +1066,7 @@ func (cfg *Config) Clone() *Config {
 	cloned := *cfg
 	cloned.General.RetryPolicy = cloneRetryPolicy(cfg.General.RetryPolicy)
 	cloned.General.RetryTiers = cloneRetryPolicyMap(cfg.General.RetryTiers)
+	cloned.General.MaintenanceWindows = cloneMaintenanceWindows(cfg.General.MaintenanceWindows)
 	cloned.Projects = cloneProjects(cfg.Projects)
 	cloned.RateLimits.Budget = cloneStringIntMap(cfg.RateLimits.Budget)
 	cloned.Providers = cloneProviders(cfg.Providers)
@@ -288,9 +1076,32 @@ func (cfg *Config) Clone() *Config {
 		Premium:  cloneStringSlice(cfg.Tiers.Premium),
 	}
 	cloned.Workflows = cloneWorkflows(cfg.Workflows)
+	cloned.Cadence.Holidays = cloneStringSlice(cfg.Cadence.Holidays)
 	cloned.API.Security.AllowedTokens = cloneStringSlice(cfg.API.Security.AllowedTokens)
+	cloned.API.Endpoints = cloneStringMap(cfg.API.Endpoints)
+	cloned.Sharding.Peers = cloneStringMap(cfg.Sharding.Peers)
+	cloned.Matrix.CommandSenders = cloneStringSlice(cfg.Matrix.CommandSenders)
+	cloned.Reporter.Email.DefaultRecipients = cloneStringSlice(cfg.Reporter.Email.DefaultRecipients)
+	cloned.Reporter.Webhooks = cloneWebhooks(cfg.Reporter.Webhooks)
+	cloned.Matrix.CommandACL = cloneStringSliceMap(cfg.Matrix.CommandACL)
 	cloned.Dispatch.CLI = cloneCLIConfigMap(cfg.Dispatch.CLI)
+	cloned.Dispatch.Routing.Rules = cloneRoutingRules(cfg.Dispatch.Routing.Rules)
 	cloned.Dispatch.CostControl.RiskyReviewLabels = cloneStringSlice(cfg.Dispatch.CostControl.RiskyReviewLabels)
+	cloned.Dispatch.CostControl.RoleCostCapsUSD = maps.Clone(cfg.Dispatch.CostControl.RoleCostCapsUSD)
+	cloned.Dispatch.BranchJanitor.Protected = cloneStringSlice(cfg.Dispatch.BranchJanitor.Protected)
+	cloned.Dispatch.ResponseCache.Roles = cloneStringSlice(cfg.Dispatch.ResponseCache.Roles)
+	cloned.Dispatch.Redaction.Patterns = cloneStringSlice(cfg.Dispatch.Redaction.Patterns)
+	cloned.Health.Host.Paths = cloneStringSlice(cfg.Health.Host.Paths)
+	cloned.Health.Events.Severities = cloneStringMap(cfg.Health.Events.Severities)
+	cloned.Health.Events.Matrix = cloneStringSlice(cfg.Health.Events.Matrix)
+	cloned.Health.Events.Webhooks = cloneStringSlice(cfg.Health.Events.Webhooks)
+	cloned.Health.QueueWait.Roles = maps.Clone(cfg.Health.QueueWait.Roles)
+	cloned.OnCall.Shifts = cloneOnCallShifts(cfg.OnCall.Shifts)
+	cloned.OnCall.PagerDuty.Users = cloneStringMap(cfg.OnCall.PagerDuty.Users)
+	cloned.Dispatch.CircuitBreakers.Providers = maps.Clone(cfg.Dispatch.CircuitBreakers.Providers)
+	cloned.Dispatch.CircuitBreakers.Categories = maps.Clone(cfg.Dispatch.CircuitBreakers.Categories)
+	cloned.IncludedFiles = cloneStringSlice(cfg.IncludedFiles)
+	cloned.Experiments = cloneExperiments(cfg.Experiments)
 	return &cloned
 }
 
@@ -301,13 +1112,98 @@ func cloneProjects(in map[string]Project) map[string]Project {
 	out := make(map[string]Project, len(in))
 	for key, project := range in {
 		project.DoD.Checks = cloneStringSlice(project.DoD.Checks)
+		project.DoD.Security.Scanners = cloneStringSlice(project.DoD.Security.Scanners)
 		project.PostMergeChecks = cloneStringSlice(project.PostMergeChecks)
+		project.EmailRecipients = cloneStringSlice(project.EmailRecipients)
 		project.RetryPolicy = cloneRetryPolicy(project.RetryPolicy)
+		project.Schedule.WorkingDays = cloneStringSlice(project.Schedule.WorkingDays)
+		project.Artifacts = cloneStringSlice(project.Artifacts)
+		project.Autofix.Commands = cloneStringSlice(project.Autofix.Commands)
+		project.TestWriter.CodePatterns = cloneStringSlice(project.TestWriter.CodePatterns)
+		project.TestWriter.TestPatterns = cloneStringSlice(project.TestWriter.TestPatterns)
+		if project.Schedule.Blackouts != nil {
+			project.Schedule.Blackouts = append([]BlackoutWindow(nil), project.Schedule.Blackouts...)
+		}
+		project.Roles = cloneRoles(project.Roles)
+		project.HealthChecks = maps.Clone(project.HealthChecks)
 		out[key] = project
 	}
 	return out
 }
 
+func cloneRoles(in map[string]RoleConfig) map[string]RoleConfig {
+	if in == nil {
+		return nil
+	}
+	out := make(map[string]RoleConfig, len(in))
+	for name, role := range in {
+		role.Stages = cloneStringSlice(role.Stages)
+		out[name] = role
+	}
+	return out
+}
+
+func cloneRoutingRules(in []RoutingRule) []RoutingRule {
+	if in == nil {
+		return nil
+	}
+	out := make([]RoutingRule, len(in))
+	for i, rule := range in {
+		rule.Labels = cloneStringSlice(rule.Labels)
+		out[i] = rule
+	}
+	return out
+}
+
+func cloneMaintenanceWindows(in []MaintenanceWindow) []MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := make([]MaintenanceWindow, len(in))
+	for i, w := range in {
+		w.Days = cloneStringSlice(w.Days)
+		out[i] = w
+	}
+	return out
+}
+
+func cloneOnCallShifts(in []OnCallShift) []OnCallShift {
+	if in == nil {
+		return nil
+	}
+	out := make([]OnCallShift, len(in))
+	for i, shift := range in {
+		shift.Days = cloneStringSlice(shift.Days)
+		out[i] = shift
+	}
+	return out
+}
+
+func cloneWebhooks(in []ReporterWebhook) []ReporterWebhook {
+	if in == nil {
+		return nil
+	}
+	out := make([]ReporterWebhook, len(in))
+	for i, hook := range in {
+		hook.Projects = cloneStringSlice(hook.Projects)
+		out[i] = hook
+	}
+	return out
+}
+
+func cloneExperiments(in map[string]Experiment) map[string]Experiment {
+	if in == nil {
+		return nil
+	}
+	out := make(map[string]Experiment, len(in))
+	for key, exp := range in {
+		exp.Projects = cloneStringSlice(exp.Projects)
+		exp.Variants = append([]ExperimentVariant(nil), exp.Variants...)
+		out[key] = exp
+	}
+	return out
+}
+
 func cloneRetryPolicyMap(in map[string]RetryPolicy) map[string]RetryPolicy {
 	if in == nil {
 		return nil
@@ -330,6 +1226,28 @@ func cloneRetryPolicy(in RetryPolicy) RetryPolicy {
 	}
 }
 
+func cloneStringMap(in map[string]string) map[string]string {
+	if in == nil {
+		return nil
+	}
+	out := make(map[string]string, len(in))
+	for key, value := range in {
+		out[key] = value
+	}
+	return out
+}
+
+func cloneStringSliceMap(in map[string][]string) map[string][]string {
+	if in == nil {
+		return nil
+	}
+	out := make(map[string][]string, len(in))
+	for key, value := range in {
+		out[key] = cloneStringSlice(value)
+	}
+	return out
+}
+
 func cloneStringIntMap(in map[string]int) map[string]int {
 	if in == nil {
 		return nil
@@ -359,7 +1277,12 @@ func cloneWorkflows(in map[string]WorkflowConfig) map[string]WorkflowConfig {
 	out := make(map[string]WorkflowConfig, len(in))
 	for key, workflow := range in {
 		stages := make([]StageConfig, len(workflow.Stages))
-		copy(stages, workflow.Stages)
+		for i, stage := range workflow.Stages {
+			stage.Next = cloneStringSlice(stage.Next)
+			stage.RequireLabels = cloneStringSlice(stage.RequireLabels)
+			stage.RequireChecks = cloneStringSlice(stage.RequireChecks)
+			stages[i] = stage
+		}
 		out[key] = WorkflowConfig{
 			MatchLabels: cloneStringSlice(workflow.MatchLabels),
 			MatchTypes:  cloneStringSlice(workflow.MatchTypes),
@@ -407,13 +1330,25 @@ func Load(path string) (*Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf("parsing config %s: %w", path, err)
 	}
+	if err := interpolate(string(data), &cfg, &cfg.Secrets); err != nil {
+		return nil, fmt.Errorf("interpolating config %s: %w", path, err)
+	}
+
+	fragments, err := loadIncludes(path, &cfg)
+	if err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
 
-	applyDefaults(&cfg, md)
+	applyDefaults(&cfg, append(mergedMetaData{&md}, fragments...))
+	if err := applyDoDPresets(&cfg); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
 	normalizePaths(&cfg)
 
 	if err := validate(&cfg); err != nil {
 		return nil, fmt.Errorf("validating config: %w", err)
 	}
+	registerConfigSecrets(&cfg)
 
 	return &cfg, nil
 }
@@ -438,7 +1373,13 @@ func LoadManager(path string) (ConfigManager, error) {
 	return NewRWMutexManager(cfg), nil
 }
 
-func applyDefaults(cfg *Config, md toml.MetaData) {
+// EnrichLimit is the number of dispatch candidates to enrich up front each
+// tick: max_per_tick scaled by enrich_headroom, rounded up.
+func (g General) EnrichLimit() int {
+	return int(math.Ceil(float64(g.MaxPerTick) * g.EnrichHeadroom))
+}
+
+func applyDefaults(cfg *Config, md keyDefiner) {
 	if cfg.General.TickInterval.Duration == 0 {
 		cfg.General.TickInterval.Duration = 60 * time.Second
 	}
@@ -448,6 +1389,15 @@ func applyDefaults(cfg *Config, md toml.MetaData) {
 	if cfg.General.MaxPerTick == 0 {
 		cfg.General.MaxPerTick = 3
 	}
+	if cfg.General.EnrichHeadroom == 0 {
+		cfg.General.EnrichHeadroom = 2
+	}
+	if !md.IsDefined("general", "wal_checkpoint_interval") {
+		cfg.General.WALCheckpointInterval.Duration = 5 * time.Minute
+	}
+	if cfg.General.BeadsGuardMaxRowBytes == 0 {
+		cfg.General.BeadsGuardMaxRowBytes = 60000
+	}
 	if cfg.General.MaxRetries == 0 {
 		cfg.General.MaxRetries = 3
 	}
@@ -493,6 +1443,37 @@ func applyDefaults(cfg *Config, md toml.MetaData) {
 	if cfg.General.MaxConcurrentTotal == 0 {
 		cfg.General.MaxConcurrentTotal = 40
 	}
+	autoscale := &cfg.General.Autoscale
+	if autoscale.Interval.Duration == 0 {
+		autoscale.Interval.Duration = 5 * time.Minute
+	}
+	if autoscale.Step == 0 {
+		autoscale.Step = 1
+	}
+	if autoscale.MinCoders == 0 {
+		autoscale.MinCoders = 1
+	}
+	if autoscale.MaxCoders == 0 {
+		autoscale.MaxCoders = cfg.General.MaxConcurrentCoders
+	}
+	if autoscale.MinReviewers == 0 {
+		autoscale.MinReviewers = 1
+	}
+	if autoscale.MaxReviewers == 0 {
+		autoscale.MaxReviewers = cfg.General.MaxConcurrentReviewers
+	}
+	if autoscale.QueueDepth == 0 {
+		autoscale.QueueDepth = 1
+	}
+	if autoscale.Window.Duration == 0 {
+		autoscale.Window.Duration = time.Hour
+	}
+	if autoscale.MaxFailureRate == 0 {
+		autoscale.MaxFailureRate = 0.5
+	}
+	if autoscale.MaxCostBurn == 0 {
+		autoscale.MaxCostBurn = 0.8
+	}
 
 	if cfg.RateLimits.Window5hCap == 0 {
 		cfg.RateLimits.Window5hCap = 20
@@ -586,98 +1567,605 @@ func applyDefaults(cfg *Config, md toml.MetaData) {
 		cfg.Dispatch.CostControl.TokenWasteWindow.Duration = 24 * time.Hour
 	}
 
-	// Dispatch log retention
-	if cfg.Dispatch.LogRetentionDays == 0 {
-		cfg.Dispatch.LogRetentionDays = 30
+	// Dispatch warmup defaults
+	if cfg.Dispatch.Warmup.IdleAfter.Duration == 0 {
+		cfg.Dispatch.Warmup.IdleAfter.Duration = 2 * time.Hour
 	}
-
-	// Health defaults
-	if cfg.Health.CheckInterval.Duration == 0 {
-		cfg.Health.CheckInterval.Duration = 5 * time.Minute
+	if strings.TrimSpace(cfg.Dispatch.Warmup.Schedule) == "" {
+		cfg.Dispatch.Warmup.Schedule = "*/15 * * * *"
 	}
-	if cfg.Health.GatewayUnit == "" {
-		cfg.Health.GatewayUnit = "openclaw-gateway.service"
+	if cfg.Dispatch.Warmup.Timeout.Duration == 0 {
+		cfg.Dispatch.Warmup.Timeout.Duration = 10 * time.Minute
 	}
-	if cfg.Health.ConcurrencyWarningPct == 0 {
-		cfg.Health.ConcurrencyWarningPct = 0.80
+	if cfg.Dispatch.Preflight.TTL.Duration == 0 {
+		cfg.Dispatch.Preflight.TTL = cfg.General.TickInterval
 	}
-	if cfg.Health.ConcurrencyCriticalPct == 0 {
-		cfg.Health.ConcurrencyCriticalPct = 0.95
+	if cfg.Dispatch.Preflight.Timeout.Duration == 0 {
+		cfg.Dispatch.Preflight.Timeout.Duration = 30 * time.Second
 	}
 
-	// Learner defaults
-	if cfg.Learner.AnalysisWindow.Duration == 0 {
-		cfg.Learner.AnalysisWindow.Duration = 48 * time.Hour
+	// Stalled review defaults
+	if cfg.Dispatch.StalledReview.Threshold.Duration == 0 {
+		cfg.Dispatch.StalledReview.Threshold.Duration = 24 * time.Hour
 	}
-	if cfg.Learner.CycleInterval.Duration == 0 {
-		cfg.Learner.CycleInterval.Duration = 6 * time.Hour
+	if strings.TrimSpace(cfg.Dispatch.StalledReview.Schedule) == "" {
+		cfg.Dispatch.StalledReview.Schedule = "*/30 * * * *"
 	}
-	// Enabled defaults to false - must be explicitly enabled
-	// IncludeInDigest defaults to false
 
-	// Matrix polling defaults
-	if cfg.Matrix.PollInterval.Duration == 0 {
-		cfg.Matrix.PollInterval.Duration = 30 * time.Second
+	// Stage SLA defaults
+	if strings.TrimSpace(cfg.Dispatch.StageSLA.Schedule) == "" {
+		cfg.Dispatch.StageSLA.Schedule = "*/10 * * * *"
 	}
-	if cfg.Matrix.ReadLimit == 0 {
-		cfg.Matrix.ReadLimit = 25
+
+	// Epic rollup defaults
+	if strings.TrimSpace(cfg.Dispatch.EpicRollup.Schedule) == "" {
+		cfg.Dispatch.EpicRollup.Schedule = "0 * * * *"
+	}
+	if !md.IsDefined("dispatch", "epic_rollup", "threshold") {
+		cfg.Dispatch.EpicRollup.Threshold = 1
 	}
 
-	// Project branch defaults
-	for name, project := range cfg.Projects {
-		if project.BaseBranch == "" {
-			project.BaseBranch = "main"
-		}
-		if project.BranchPrefix == "" {
-			project.BranchPrefix = "feat/"
-		}
-		if !md.IsDefined("projects", name, "merge_method") {
-			project.MergeMethod = "squash"
-		}
-		project.MergeMethod = strings.ToLower(strings.TrimSpace(project.MergeMethod))
+	// Backlog triage defaults
+	if strings.TrimSpace(cfg.Dispatch.Triage.Schedule) == "" {
+		cfg.Dispatch.Triage.Schedule = "0 4 * * *"
+	}
+	if !md.IsDefined("dispatch", "triage", "similarity") {
+		cfg.Dispatch.Triage.Similarity = 0.8
+	}
+	if !md.IsDefined("dispatch", "triage", "stale_after") {
+		cfg.Dispatch.Triage.StaleAfter.Duration = 30 * 24 * time.Hour
+	}
 
-		if !md.IsDefined("projects", name, "auto_revert_on_failure") {
-			project.AutoRevertOnFailure = true
-		}
+	// Complexity model defaults
+	if cfg.Dispatch.Complexity.MinSamples == 0 {
+		cfg.Dispatch.Complexity.MinSamples = 20
+	}
+	if cfg.Dispatch.Complexity.Window.Duration == 0 {
+		cfg.Dispatch.Complexity.Window.Duration = 90 * 24 * time.Hour
+	}
+	if cfg.Dispatch.Complexity.Retrain.Duration == 0 {
+		cfg.Dispatch.Complexity.Retrain.Duration = time.Hour
+	}
 
-		// Sprint planning defaults (optional - no defaults applied to maintain backward compatibility)
-		// Users must explicitly configure sprint planning to enable it
+	// Claim defaults
+	if strings.TrimSpace(cfg.Dispatch.Claims.Backend) == "" {
+		cfg.Dispatch.Claims.Backend = "sqlite"
+	}
+	if cfg.Dispatch.Claims.TTL.Duration == 0 {
+		cfg.Dispatch.Claims.TTL.Duration = 10 * time.Minute
+	}
+	if strings.TrimSpace(cfg.Dispatch.Claims.EtcdPrefix) == "" {
+		cfg.Dispatch.Claims.EtcdPrefix = "/cortex/claims/"
+	}
 
-		cfg.Projects[name] = project
+	// HA defaults
+	if cfg.HA.LeaseTTL.Duration == 0 {
+		cfg.HA.LeaseTTL.Duration = 15 * time.Second
 	}
 
-	// API security defaults
-	if !cfg.API.Security.Enabled && cfg.API.Bind != "" && !isLocalBind(cfg.API.Bind) {
-		// Default to requiring local-only when security is disabled and binding to non-local
-		cfg.API.Security.RequireLocalOnly = true
+	// File overlap defaults
+	if strings.TrimSpace(cfg.Dispatch.FileOverlap.Schedule) == "" {
+		cfg.Dispatch.FileOverlap.Schedule = "*/30 * * * *"
+	}
+	if strings.TrimSpace(cfg.Dispatch.FileOverlap.Mode) == "" {
+		cfg.Dispatch.FileOverlap.Mode = "warn"
+	}
+	if cfg.Dispatch.FileOverlap.LookbackDays == 0 {
+		cfg.Dispatch.FileOverlap.LookbackDays = 30
 	}
 
-	// Chief defaults
-	if cfg.Chief.Model == "" {
-		cfg.Chief.Model = "claude-opus-4-6" // Default to premium tier
+	// Branch janitor defaults
+	if strings.TrimSpace(cfg.Dispatch.BranchJanitor.Schedule) == "" {
+		cfg.Dispatch.BranchJanitor.Schedule = "0 3 * * *"
 	}
-	if cfg.Chief.AgentID == "" {
-		cfg.Chief.AgentID = "cortex-chief-scrum"
+	if !md.IsDefined("dispatch", "branch_janitor", "grace_period") {
+		cfg.Dispatch.BranchJanitor.GracePeriod.Duration = 7 * 24 * time.Hour
 	}
-}
 
-// RetryPolicyFor computes the effective retry policy for a project and tier.
-func (cfg *Config) RetryPolicyFor(projectName, tier string) RetryPolicy {
-	if cfg == nil {
-		return RetryPolicy{
-			MaxRetries:    3,
-			InitialDelay:  Duration{Duration: 5 * time.Minute},
-			BackoffFactor: 2.0,
-			MaxDelay:      Duration{Duration: 30 * time.Minute},
-			EscalateAfter: 2,
+	for i := range cfg.Reporter.Webhooks {
+		if cfg.Reporter.Webhooks[i].Timeout.Duration == 0 {
+			cfg.Reporter.Webhooks[i].Timeout.Duration = 10 * time.Second
 		}
 	}
 
-	policy := cfg.General.RetryPolicy
-	if tierPolicy, ok := cfg.General.RetryTiers[strings.ToLower(strings.TrimSpace(tier))]; ok {
-		policy = mergeRetryPolicy(policy, tierPolicy)
+	// Reporter throttle defaults
+	if cfg.Reporter.Throttle.DedupWindow.Duration == 0 {
+		cfg.Reporter.Throttle.DedupWindow.Duration = 10 * time.Minute
 	}
-
+	if cfg.Reporter.Throttle.RoomLimit == 0 {
+		cfg.Reporter.Throttle.RoomLimit = 5
+	}
+	if cfg.Reporter.Throttle.RatePeriod.Duration == 0 {
+		cfg.Reporter.Throttle.RatePeriod.Duration = time.Minute
+	}
+	if cfg.Reporter.Throttle.DigestInterval.Duration == 0 {
+		cfg.Reporter.Throttle.DigestInterval.Duration = 5 * time.Minute
+	}
+
+	// Email reporter defaults
+	if cfg.Reporter.Email.SMTPPort == 0 {
+		cfg.Reporter.Email.SMTPPort = 587
+	}
+	if cfg.Reporter.Email.SubjectPrefix == "" {
+		cfg.Reporter.Email.SubjectPrefix = "[cortex]"
+	}
+
+	// Burn-in report defaults
+	if strings.TrimSpace(cfg.Reporter.BurnIn.Schedule) == "" {
+		cfg.Reporter.BurnIn.Schedule = "0 7 * * *"
+	}
+	if cfg.Reporter.BurnIn.Window.Duration == 0 {
+		cfg.Reporter.BurnIn.Window.Duration = 7 * 24 * time.Hour
+	}
+	if strings.TrimSpace(cfg.Reporter.BurnIn.ReportsDir) == "" && strings.TrimSpace(cfg.General.StateDB) != "" {
+		cfg.Reporter.BurnIn.ReportsDir = filepath.Join(filepath.Dir(strings.TrimSpace(cfg.General.StateDB)), "reports")
+	}
+	if cfg.Reporter.BurnIn.SLO.UnknownRateLimit == 0 {
+		cfg.Reporter.BurnIn.SLO.UnknownRateLimit = 0.02
+	}
+	if cfg.Reporter.BurnIn.SLO.InterventionRateLimit == 0 {
+		cfg.Reporter.BurnIn.SLO.InterventionRateLimit = 0.10
+	}
+	if cfg.Reporter.BurnIn.SLO.CriticalEventLimit == 0 {
+		cfg.Reporter.BurnIn.SLO.CriticalEventLimit = 2
+	}
+	if strings.TrimSpace(cfg.Reporter.Cost.Schedule) == "" {
+		cfg.Reporter.Cost.Schedule = "0 6 1 * *"
+	}
+	if strings.TrimSpace(cfg.Reporter.Cost.ReportsDir) == "" && strings.TrimSpace(cfg.General.StateDB) != "" {
+		cfg.Reporter.Cost.ReportsDir = filepath.Join(filepath.Dir(strings.TrimSpace(cfg.General.StateDB)), "reports")
+	}
+
+	// Confidence gate defaults
+	if !md.IsDefined("dispatch", "confidence", "threshold") {
+		cfg.Dispatch.Confidence.Threshold = 0.7
+	}
+
+	// Response cache defaults
+	if cfg.Dispatch.ResponseCache.TTL.Duration == 0 {
+		cfg.Dispatch.ResponseCache.TTL.Duration = 6 * time.Hour
+	}
+	if len(cfg.Dispatch.ResponseCache.Roles) == 0 {
+		cfg.Dispatch.ResponseCache.Roles = []string{"planner", "groom", "chief"}
+	}
+
+	// Redaction defaults
+	if cfg.Dispatch.Redaction.EntropyMinLength == 0 {
+		cfg.Dispatch.Redaction.EntropyMinLength = 24
+	}
+	if cfg.Dispatch.Redaction.EntropyThreshold == 0 {
+		cfg.Dispatch.Redaction.EntropyThreshold = 4.0
+	}
+
+	if strings.TrimSpace(cfg.Dispatch.Approvals.DefaultAction) == "" {
+		cfg.Dispatch.Approvals.DefaultAction = "deny"
+	}
+
+	// Follow-up bead defaults
+	if cfg.Dispatch.FollowUps.Max == 0 {
+		cfg.Dispatch.FollowUps.Max = 5
+	}
+	if !md.IsDefined("dispatch", "follow_ups", "priority") {
+		cfg.Dispatch.FollowUps.Priority = 3
+	}
+
+	// Pair mode defaults
+	if !md.IsDefined("dispatch", "pair", "max_priority") {
+		cfg.Dispatch.Pair.MaxPriority = 1
+	}
+	if cfg.Dispatch.Pair.MaxTurns == 0 {
+		cfg.Dispatch.Pair.MaxTurns = 6
+	}
+
+	// Circuit breaker defaults; overrides inherit unset fields from the
+	// provider breaker.
+	breakers := &cfg.Dispatch.CircuitBreakers
+	if !md.IsDefined("dispatch", "circuit_breakers", "provider", "threshold") {
+		breakers.Provider.Threshold = 5
+	}
+	if breakers.Provider.Window.Duration == 0 {
+		breakers.Provider.Window.Duration = 30 * time.Minute
+	}
+	if breakers.Provider.Cooldown.Duration == 0 {
+		breakers.Provider.Cooldown.Duration = 10 * time.Minute
+	}
+	for name, b := range breakers.Providers {
+		if !md.IsDefined("dispatch", "circuit_breakers", "providers", name, "threshold") {
+			b.Threshold = breakers.Provider.Threshold
+		}
+		breakers.Providers[name] = inheritBreakerTiming(b, breakers.Provider)
+	}
+	for category, b := range breakers.Categories {
+		if !md.IsDefined("dispatch", "circuit_breakers", "categories", category, "threshold") {
+			b.Threshold = breakers.Provider.Threshold
+		}
+		breakers.Categories[category] = inheritBreakerTiming(b, breakers.Provider)
+	}
+	if !md.IsDefined("dispatch", "circuit_breakers", "categories") {
+		breakers.Categories = map[string]CircuitBreaker{
+			"gateway_closed": {Threshold: 3, Window: Duration{Duration: 5 * time.Minute}, Cooldown: Duration{Duration: 5 * time.Minute}},
+		}
+	}
+
+	// Dispatch log retention
+	if cfg.Dispatch.LogRetentionDays == 0 {
+		cfg.Dispatch.LogRetentionDays = 30
+	}
+
+	// Dispatch worktree pool
+	if strings.TrimSpace(cfg.Dispatch.Worktrees.Dir) == "" && strings.TrimSpace(cfg.General.StateDB) != "" {
+		cfg.Dispatch.Worktrees.Dir = filepath.Join(filepath.Dir(strings.TrimSpace(cfg.General.StateDB)), "worktrees")
+	}
+	if cfg.Dispatch.Worktrees.PoolSize == 0 {
+		cfg.Dispatch.Worktrees.PoolSize = cfg.Dispatch.Git.MaxConcurrentPerProject
+	}
+	if strings.TrimSpace(cfg.Dispatch.Journal.Dir) == "" && strings.TrimSpace(cfg.General.StateDB) != "" {
+		cfg.Dispatch.Journal.Dir = filepath.Join(filepath.Dir(strings.TrimSpace(cfg.General.StateDB)), "journal")
+	}
+	if !md.IsDefined("dispatch", "journal", "retention_days") {
+		cfg.Dispatch.Journal.RetentionDays = 30
+	}
+
+	// Dispatch artifacts
+	if strings.TrimSpace(cfg.Dispatch.Artifacts.Dir) == "" && strings.TrimSpace(cfg.General.StateDB) != "" {
+		cfg.Dispatch.Artifacts.Dir = filepath.Join(filepath.Dir(strings.TrimSpace(cfg.General.StateDB)), "artifacts")
+	}
+	if cfg.Dispatch.Artifacts.RetentionDays == 0 {
+		cfg.Dispatch.Artifacts.RetentionDays = 14
+	}
+	if cfg.Dispatch.Artifacts.MaxFileMB == 0 {
+		cfg.Dispatch.Artifacts.MaxFileMB = 50
+	}
+
+	// Health defaults
+	if cfg.Health.CheckInterval.Duration == 0 {
+		cfg.Health.CheckInterval.Duration = 5 * time.Minute
+	}
+	if cfg.Health.GatewayUnit == "" {
+		cfg.Health.GatewayUnit = "openclaw-gateway.service"
+	}
+	if cfg.Health.ConcurrencyWarningPct == 0 {
+		cfg.Health.ConcurrencyWarningPct = 0.80
+	}
+	if cfg.Health.ConcurrencyCriticalPct == 0 {
+		cfg.Health.ConcurrencyCriticalPct = 0.95
+	}
+	if cfg.Health.QueueWait.Window.Duration == 0 {
+		cfg.Health.QueueWait.Window.Duration = time.Hour
+	}
+	if cfg.Health.Rollout.Schedule == "" {
+		cfg.Health.Rollout.Schedule = "0 * * * *"
+	}
+	if cfg.Health.Rollout.Window.Duration == 0 {
+		cfg.Health.Rollout.Window.Duration = 24 * time.Hour
+	}
+	if !md.IsDefined("health", "host", "min_free_disk_mb") {
+		cfg.Health.Host.MinFreeDiskMB = 2048
+	}
+	if !md.IsDefined("health", "host", "min_free_memory_mb") {
+		cfg.Health.Host.MinFreeMemoryMB = 512
+	}
+	if !md.IsDefined("health", "stuck_commands", "breaches") {
+		cfg.Health.StuckCommands.Breaches = 3
+	}
+
+	// Temporal defaults
+	if cfg.Temporal.Host == "" {
+		cfg.Temporal.Host = "127.0.0.1"
+	}
+	if cfg.Temporal.Port == 0 {
+		cfg.Temporal.Port = 7233
+	}
+	if cfg.Temporal.Namespace == "" {
+		cfg.Temporal.Namespace = "default"
+	}
+	if cfg.Temporal.TaskQueue == "" {
+		cfg.Temporal.TaskQueue = "cortex-task-queue"
+	}
+	// Shards share a Temporal namespace, so each polls its own task queue
+	// and never runs another shard's workflows.
+	if shard := strings.TrimSpace(cfg.Sharding.Shard); cfg.Sharding.Enabled && shard != "" {
+		cfg.Temporal.TaskQueue += "-" + shard
+	}
+	if cfg.Temporal.Schedules.StrategicGroom == "" {
+		cfg.Temporal.Schedules.StrategicGroom = "0 5 * * *"
+	}
+	if cfg.Temporal.Schedules.FailureClusterReport == "" {
+		cfg.Temporal.Schedules.FailureClusterReport = "0 6 * * 1"
+	}
+
+	// Learner defaults
+	if cfg.Learner.AnalysisWindow.Duration == 0 {
+		cfg.Learner.AnalysisWindow.Duration = 48 * time.Hour
+	}
+	if cfg.Learner.CycleInterval.Duration == 0 {
+		cfg.Learner.CycleInterval.Duration = 6 * time.Hour
+	}
+	// Enabled defaults to false - must be explicitly enabled
+	// IncludeInDigest defaults to false
+
+	// Matrix polling defaults
+	if cfg.Matrix.PollInterval.Duration == 0 {
+		cfg.Matrix.PollInterval.Duration = 30 * time.Second
+	}
+	if cfg.Matrix.ReadLimit == 0 {
+		cfg.Matrix.ReadLimit = 25
+	}
+
+	// Project branch defaults
+	for name, project := range cfg.Projects {
+		if project.BaseBranch == "" {
+			project.BaseBranch = "main"
+		}
+		if project.BranchPrefix == "" {
+			project.BranchPrefix = "feat/"
+		}
+		if !md.IsDefined("projects", name, "merge_method") {
+			project.MergeMethod = "squash"
+		}
+		project.MergeMethod = strings.ToLower(strings.TrimSpace(project.MergeMethod))
+
+		if !md.IsDefined("projects", name, "auto_revert_on_failure") {
+			project.AutoRevertOnFailure = true
+		}
+		if project.DoD.Security.Severity == "" {
+			project.DoD.Security.Severity = "high"
+		}
+		if strings.TrimSpace(project.Autofix.CommitMessage) == "" {
+			project.Autofix.CommitMessage = "style: apply lint and format fixes"
+		}
+		if project.TestWriter.Tier == "" {
+			project.TestWriter.Tier = "balanced"
+		}
+		if len(project.TestWriter.CodePatterns) == 0 {
+			project.TestWriter.CodePatterns = slices.Clone(DefaultTestWriterCodePatterns)
+		}
+		if len(project.TestWriter.TestPatterns) == 0 {
+			project.TestWriter.TestPatterns = slices.Clone(DefaultTestWriterTestPatterns)
+		}
+		if strings.TrimSpace(project.TestWriter.CommitMessage) == "" {
+			project.TestWriter.CommitMessage = "test: add tests for untested changes"
+		}
+		for checkName, check := range project.HealthChecks {
+			if check.Interval.Duration == 0 {
+				check.Interval.Duration = cfg.Health.CheckInterval.Duration
+			}
+			if check.Timeout.Duration == 0 {
+				check.Timeout.Duration = time.Minute
+			}
+			if check.Failures == 0 {
+				check.Failures = 1
+			}
+			project.HealthChecks[checkName] = check
+		}
+		project.DoD.Security.Severity = strings.ToLower(strings.TrimSpace(project.DoD.Security.Severity))
+
+		// Sprint planning defaults (optional - no defaults applied to maintain backward compatibility)
+		// Users must explicitly configure sprint planning to enable it
+
+		cfg.Projects[name] = project
+	}
+
+	// API security defaults
+	if !cfg.API.Security.Enabled && cfg.API.Bind != "" && !isLocalBind(cfg.API.Bind) {
+		// Default to requiring local-only when security is disabled and binding to non-local
+		cfg.API.Security.RequireLocalOnly = true
+	}
+
+	// Chief defaults
+	if cfg.Chief.Model == "" {
+		cfg.Chief.Model = "claude-opus-4-6" // Default to premium tier
+	}
+	if cfg.Chief.AgentID == "" {
+		cfg.Chief.AgentID = "cortex-chief-scrum"
+	}
+
+	// Experiment variants share traffic equally unless weighted
+	for name, exp := range cfg.Experiments {
+		for i := range exp.Variants {
+			if exp.Variants[i].Weight == 0 {
+				exp.Variants[i].Weight = 1
+			}
+		}
+		cfg.Experiments[name] = exp
+	}
+}
+
+// applyDoDPresets fills project DoD checks and coverage from an embedded preset
+// when the project names one; explicitly configured values always win.
+func applyDoDPresets(cfg *Config) error {
+	for name, project := range cfg.Projects {
+		preset := strings.TrimSpace(project.DoD.Preset)
+		if preset == "" {
+			continue
+		}
+		data, err := assets.DoDPreset(preset)
+		if err != nil {
+			return fmt.Errorf("project %q dod.preset: %w", name, err)
+		}
+		var defaults DoDConfig
+		if _, err := toml.Decode(string(data), &defaults); err != nil {
+			return fmt.Errorf("project %q dod.preset %q: %w", name, preset, err)
+		}
+		if len(project.DoD.Checks) == 0 {
+			project.DoD.Checks = defaults.Checks
+		}
+		if project.DoD.CoverageMin == 0 {
+			project.DoD.CoverageMin = defaults.CoverageMin
+		}
+		cfg.Projects[name] = project
+	}
+	return nil
+}
+
+// BurnInSLOFor returns the burn-in gates for a project: its own overrides on
+// top of reporter.burnin.slo.
+func (cfg *Config) BurnInSLOFor(projectName string) BurnInSLO {
+	slo := cfg.Reporter.BurnIn.SLO
+	override := cfg.Projects[projectName].BurnIn
+	if override.UnknownRateLimit != 0 {
+		slo.UnknownRateLimit = override.UnknownRateLimit
+	}
+	if override.InterventionRateLimit != 0 {
+		slo.InterventionRateLimit = override.InterventionRateLimit
+	}
+	if override.CriticalEventLimit != 0 {
+		slo.CriticalEventLimit = override.CriticalEventLimit
+	}
+	return slo
+}
+
+func validateTemporal(t Temporal) error {
+	if t.Port < 1 || t.Port > 65535 {
+		return fmt.Errorf("temporal.port must be between 1 and 65535")
+	}
+	if strings.TrimSpace(t.TaskQueue) == "" {
+		return fmt.Errorf("temporal.task_queue must not be blank")
+	}
+	if _, err := cron.ParseStandard(t.Schedules.StrategicGroom); err != nil {
+		return fmt.Errorf("temporal.schedules.strategic_groom: %w", err)
+	}
+	if _, err := cron.ParseStandard(t.Schedules.FailureClusterReport); err != nil {
+		return fmt.Errorf("temporal.schedules.failure_cluster_report: %w", err)
+	}
+	if (t.TLS.CertFile == "") != (t.TLS.KeyFile == "") {
+		return fmt.Errorf("temporal.tls.cert_file and temporal.tls.key_file must be set together")
+	}
+	for _, f := range []struct{ key, path string }{
+		{"cert_file", t.TLS.CertFile},
+		{"key_file", t.TLS.KeyFile},
+		{"ca_file", t.TLS.CAFile},
+	} {
+		if f.path == "" {
+			continue
+		}
+		if _, err := os.Stat(f.path); err != nil {
+			return fmt.Errorf("temporal.tls.%s: %w", f.key, err)
+		}
+	}
+	return nil
+}
+
+func validateHealthEvents(events HealthEvents) error {
+	for eventType, severity := range events.Severities {
+		if !slices.Contains(HealthSeverities, severity) {
+			return fmt.Errorf("health.events.severities.%s: %q must be one of %s", eventType, severity, strings.Join(HealthSeverities, ", "))
+		}
+	}
+	for field, severities := range map[string][]string{"matrix": events.Matrix, "webhooks": events.Webhooks} {
+		for _, severity := range severities {
+			if !slices.Contains(HealthSeverities, severity) {
+				return fmt.Errorf("health.events.%s: %q must be one of %s", field, severity, strings.Join(HealthSeverities, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+func validateHealthQueueWait(q HealthQueueWait) error {
+	if q.SLO.Duration < 0 || q.Window.Duration < 0 {
+		return fmt.Errorf("health.queue_wait: slo and window must not be negative")
+	}
+	for role, slo := range q.Roles {
+		if slo.Duration < 0 {
+			return fmt.Errorf("health.queue_wait.roles.%s must not be negative", role)
+		}
+	}
+	return nil
+}
+
+func validateAutoscale(a GeneralAutoscale) error {
+	switch {
+	case a.Interval.Duration < 0 || a.Window.Duration < 0:
+		return fmt.Errorf("interval and window must not be negative")
+	case a.Step < 1 || a.QueueDepth < 1:
+		return fmt.Errorf("step and queue_depth must be at least 1")
+	case a.MinCoders < 1 || a.MinCoders > a.MaxCoders:
+		return fmt.Errorf("min_coders (%d) must be between 1 and max_coders (%d)", a.MinCoders, a.MaxCoders)
+	case a.MinReviewers < 1 || a.MinReviewers > a.MaxReviewers:
+		return fmt.Errorf("min_reviewers (%d) must be between 1 and max_reviewers (%d)", a.MinReviewers, a.MaxReviewers)
+	case a.MaxFailureRate <= 0 || a.MaxFailureRate > 1 || a.MaxCostBurn <= 0 || a.MaxCostBurn > 1:
+		return fmt.Errorf("max_failure_rate and max_cost_burn must be in (0, 1]")
+	}
+	return nil
+}
+
+func validateOnCall(o OnCall) error {
+	for i, shift := range o.Shifts {
+		if !strings.HasPrefix(shift.User, "@") || !strings.Contains(shift.User, ":") {
+			return fmt.Errorf("shifts[%d].user must be a Matrix user ID like @alice:example.org, got %q", i, shift.User)
+		}
+		if shift.Hours == "" {
+			return fmt.Errorf("shifts[%d].hours is required", i)
+		}
+		if err := validateMaintenanceWindow(shift.Window()); err != nil {
+			return fmt.Errorf("shifts[%d]: %w", i, err)
+		}
+	}
+	pd := o.PagerDuty
+	if (pd.APIToken == "") != (pd.ScheduleID == "") {
+		return fmt.Errorf("pagerduty.api_token and pagerduty.schedule_id must be set together")
+	}
+	if len(pd.Users) > 0 && pd.ScheduleID == "" {
+		return fmt.Errorf("pagerduty.users needs pagerduty.schedule_id")
+	}
+	return nil
+}
+
+func validateHealthRollout(cfg *Config) error {
+	ro := cfg.Health.Rollout
+	if ro.Window.Duration < 0 {
+		return fmt.Errorf("health.rollout.window must not be negative")
+	}
+	if ro.MaxCriticalEvents < 0 {
+		return fmt.Errorf("health.rollout.max_critical_events must not be negative")
+	}
+	if len(ro.CriticalBeads) > 0 && ro.Project == "" {
+		return fmt.Errorf("health.rollout.project is required with critical_beads")
+	}
+	if ro.Project != "" {
+		if _, ok := cfg.Projects[ro.Project]; !ok {
+			return fmt.Errorf("health.rollout.project references unknown project %q", ro.Project)
+		}
+	}
+	for _, id := range ro.CriticalBeads {
+		if strings.TrimSpace(id) == "" {
+			return fmt.Errorf("health.rollout.critical_beads must not contain empty IDs")
+		}
+	}
+	return nil
+}
+
+func validateBurnInSLO(field string, slo BurnInSLO) error {
+	if slo.UnknownRateLimit < 0 || slo.UnknownRateLimit > 1 {
+		return fmt.Errorf("%s.unknown_rate_limit must be between 0 and 1", field)
+	}
+	if slo.InterventionRateLimit < 0 || slo.InterventionRateLimit > 1 {
+		return fmt.Errorf("%s.intervention_rate_limit must be between 0 and 1", field)
+	}
+	if slo.CriticalEventLimit < 0 {
+		return fmt.Errorf("%s.critical_event_limit must not be negative", field)
+	}
+	return nil
+}
+
+// RetryPolicyFor computes the effective retry policy for a project and tier.
+func (cfg *Config) RetryPolicyFor(projectName, tier string) RetryPolicy {
+	if cfg == nil {
+		return RetryPolicy{
+			MaxRetries:    3,
+			InitialDelay:  Duration{Duration: 5 * time.Minute},
+			BackoffFactor: 2.0,
+			MaxDelay:      Duration{Duration: 30 * time.Minute},
+			EscalateAfter: 2,
+		}
+	}
+
+	policy := cfg.General.RetryPolicy
+	if tierPolicy, ok := cfg.General.RetryTiers[strings.ToLower(strings.TrimSpace(tier))]; ok {
+		policy = mergeRetryPolicy(policy, tierPolicy)
+	}
+
 	// If the project exists, merge its override.
 	if _, ok := cfg.Projects[projectName]; ok {
 		policy = mergeRetryPolicy(policy, cfg.Projects[projectName].RetryPolicy)
@@ -749,13 +2237,49 @@ func normalizePaths(cfg *Config) {
 
 	cfg.General.StateDB = ExpandHome(strings.TrimSpace(cfg.General.StateDB))
 	cfg.Dispatch.LogDir = ExpandHome(strings.TrimSpace(cfg.Dispatch.LogDir))
+	cfg.Reporter.BurnIn.ReportsDir = ExpandHome(strings.TrimSpace(cfg.Reporter.BurnIn.ReportsDir))
+	cfg.Reporter.Cost.ReportsDir = ExpandHome(strings.TrimSpace(cfg.Reporter.Cost.ReportsDir))
+	cfg.Dispatch.Artifacts.Dir = ExpandHome(strings.TrimSpace(cfg.Dispatch.Artifacts.Dir))
+	cfg.Dispatch.Worktrees.Dir = ExpandHome(strings.TrimSpace(cfg.Dispatch.Worktrees.Dir))
+	cfg.Dispatch.Journal.Dir = ExpandHome(strings.TrimSpace(cfg.Dispatch.Journal.Dir))
+	for i, p := range cfg.Health.Host.Paths {
+		cfg.Health.Host.Paths[i] = ExpandHome(strings.TrimSpace(p))
+	}
 	cfg.API.Security.AuditLog = ExpandHome(strings.TrimSpace(cfg.API.Security.AuditLog))
+	cfg.Temporal.TLS.CertFile = ExpandHome(strings.TrimSpace(cfg.Temporal.TLS.CertFile))
+	cfg.Temporal.TLS.KeyFile = ExpandHome(strings.TrimSpace(cfg.Temporal.TLS.KeyFile))
+	cfg.Temporal.TLS.CAFile = ExpandHome(strings.TrimSpace(cfg.Temporal.TLS.CAFile))
 
 	for name, project := range cfg.Projects {
 		project.BeadsDir = ExpandHome(strings.TrimSpace(project.BeadsDir))
 		project.Workspace = ExpandHome(strings.TrimSpace(project.Workspace))
+		project.Prompts.Planner = resolvePromptPath(project.Workspace, project.Prompts.Planner)
+		project.Prompts.Coder = resolvePromptPath(project.Workspace, project.Prompts.Coder)
+		project.Prompts.Reviewer = resolvePromptPath(project.Workspace, project.Prompts.Reviewer)
+		for roleName, role := range project.Roles {
+			role.Prompt = resolvePromptPath(project.Workspace, role.Prompt)
+			role.Tier = strings.ToLower(strings.TrimSpace(role.Tier))
+			project.Roles[roleName] = role
+		}
 		cfg.Projects[name] = project
 	}
+
+	for name, exp := range cfg.Experiments {
+		for i := range exp.Variants {
+			exp.Variants[i].Prompt = ExpandHome(strings.TrimSpace(exp.Variants[i].Prompt))
+		}
+		cfg.Experiments[name] = exp
+	}
+}
+
+// resolvePromptPath expands "~" and anchors relative template paths at the
+// project workspace so templates can live in the repo they describe.
+func resolvePromptPath(workspace, path string) string {
+	path = ExpandHome(strings.TrimSpace(path))
+	if path == "" || filepath.IsAbs(path) || workspace == "" {
+		return path
+	}
+	return filepath.Join(workspace, path)
 }
 
 // isLocalBind checks if a bind address is local (localhost, 127.0.0.1, or unix socket)
@@ -774,12 +2298,14 @@ func isLocalBind(bind string) bool {
 }
 
 func validate(cfg *Config) error {
-	knownRoles := map[string]struct{}{
-		"scrum":    {},
-		"planner":  {},
-		"coder":    {},
-		"reviewer": {},
-		"ops":      {},
+	knownRoles := make(map[string]struct{}, len(BuiltinRoles))
+	for _, role := range BuiltinRoles {
+		knownRoles[role] = struct{}{}
+	}
+	for _, p := range cfg.Projects {
+		for name := range p.Roles {
+			knownRoles[name] = struct{}{}
+		}
 	}
 
 	allTierNames := make([]string, 0, len(cfg.Tiers.Fast)+len(cfg.Tiers.Balanced)+len(cfg.Tiers.Premium))
@@ -814,15 +2340,95 @@ func validate(cfg *Config) error {
 		if err := validateProjectMergeConfig(projectName, p); err != nil {
 			return fmt.Errorf("project %q merge config: %w", projectName, err)
 		}
+		if err := validatePromptTemplates(p.Prompts); err != nil {
+			return fmt.Errorf("project %q prompts: %w", projectName, err)
+		}
+		if err := validateRoles(cfg, p.Roles); err != nil {
+			return fmt.Errorf("project %q roles: %w", projectName, err)
+		}
+		if err := validateProjectSchedule(p.Schedule); err != nil {
+			return fmt.Errorf("project %q schedule: %w", projectName, err)
+		}
+		if p.ChiefSchedule != "" {
+			if _, err := cron.ParseStandard(p.ChiefSchedule); err != nil {
+				return fmt.Errorf("project %q chief_schedule: %w", projectName, err)
+			}
+		}
 	}
 	if !hasEnabled {
 		return fmt.Errorf("at least one project must be enabled")
 	}
 
+	for name, exp := range cfg.Experiments {
+		if err := validateExperiment(name, exp); err != nil {
+			return err
+		}
+	}
+
+	if _, err := cfg.Reporter.DailyDigestSchedule(); err != nil {
+		return fmt.Errorf("reporter: %w", err)
+	}
+	if err := validateReporterChannel(cfg); err != nil {
+		return fmt.Errorf("reporter: %w", err)
+	}
+	if err := validateReporterWebhooks(cfg); err != nil {
+		return fmt.Errorf("reporter: %w", err)
+	}
+	if t := cfg.Reporter.Throttle; t.DedupWindow.Duration < 0 || t.RoomLimit < 0 || t.RatePeriod.Duration < 0 || t.DigestInterval.Duration < 0 {
+		return fmt.Errorf("reporter: throttle limits must not be negative")
+	}
+	if err := validateForgeRepos(cfg); err != nil {
+		return err
+	}
 	if err := validateCadenceConfig(cfg.Cadence); err != nil {
 		return fmt.Errorf("cadence config: %w", err)
 	}
 
+	if cfg.General.ShutdownDrain.Duration < 0 {
+		return fmt.Errorf("general.shutdown_drain must not be negative")
+	}
+	if cfg.General.WALCheckpointInterval.Duration < 0 {
+		return fmt.Errorf("general.wal_checkpoint_interval must not be negative")
+	}
+	if cfg.General.BeadsGuardInterval.Duration < 0 {
+		return fmt.Errorf("general.beads_guard_interval must not be negative")
+	}
+	if cfg.General.BeadsGuardMaxRowBytes < 0 {
+		return fmt.Errorf("general.beads_guard_max_row_bytes must not be negative")
+	}
+	if cfg.General.EnrichHeadroom < 1 {
+		return fmt.Errorf("general.enrich_headroom must be at least 1")
+	}
+	for i, w := range cfg.General.MaintenanceWindows {
+		if err := validateMaintenanceWindow(w); err != nil {
+			return fmt.Errorf("general.maintenance_windows[%d]: %w", i, err)
+		}
+	}
+	if err := validateHealthRollout(cfg); err != nil {
+		return err
+	}
+	if host := cfg.Health.Host; host.MinFreeDiskMB < 0 || host.MinFreeMemoryMB < 0 || host.MaxLoadPerCPU < 0 {
+		return fmt.Errorf("health.host limits must not be negative")
+	}
+	if cfg.Health.StuckCommands.Breaches < 0 {
+		return fmt.Errorf("health.stuck_commands.breaches must not be negative")
+	}
+	if err := validateHealthEvents(cfg.Health.Events); err != nil {
+		return err
+	}
+	if err := validateHealthQueueWait(cfg.Health.QueueWait); err != nil {
+		return err
+	}
+	if err := validateAutoscale(cfg.General.Autoscale); err != nil {
+		return fmt.Errorf("general.autoscale: %w", err)
+	}
+	if err := validateOnCall(cfg.OnCall); err != nil {
+		return fmt.Errorf("oncall: %w", err)
+	}
+	if err := validateTemporal(cfg.Temporal); err != nil {
+		return err
+	}
+
 	if err := validateRetryPolicy("general.retry_policy", cfg.General.RetryPolicy); err != nil {
 		return fmt.Errorf("general retry policy: %w", err)
 	}
@@ -858,6 +2464,19 @@ func validate(cfg *Config) error {
 				if _, ok := knownRoles[stage.Role]; !ok {
 					return fmt.Errorf("workflow %q stage %q references unknown role %q", workflowName, stage.Name, stage.Role)
 				}
+				if stage.MaxDuration.Duration < 0 {
+					return fmt.Errorf("workflow %q stage %q max_duration must be >= 0", workflowName, stage.Name)
+				}
+			}
+			for _, stage := range wf.Stages {
+				for _, next := range stage.Next {
+					if _, ok := seenStageNames[next]; !ok && next != TerminalStage {
+						return fmt.Errorf("workflow %q stage %q lists unknown next stage %q", workflowName, stage.Name, next)
+					}
+					if next == stage.Name {
+						return fmt.Errorf("workflow %q stage %q cannot transition to itself", workflowName, stage.Name)
+					}
+				}
 			}
 		}
 	}
@@ -908,6 +2527,160 @@ func validate(cfg *Config) error {
 		}
 	}
 
+	if cfg.Reporter.BurnIn.Window.Duration < 0 {
+		return fmt.Errorf("reporter.burnin.window must not be negative")
+	}
+	if err := validateBurnInSLO("reporter.burnin.slo", cfg.Reporter.BurnIn.SLO); err != nil {
+		return err
+	}
+	for name, project := range cfg.Projects {
+		if err := validateBurnInSLO("projects."+name+".burnin", project.BurnIn); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Dispatch.BranchJanitor.GracePeriod.Duration < 0 {
+		return fmt.Errorf("dispatch.branch_janitor.grace_period must not be negative")
+	}
+	for _, pattern := range cfg.Dispatch.BranchJanitor.Protected {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("dispatch.branch_janitor.protected: invalid pattern %q: %w", pattern, err)
+		}
+	}
+
+	if er := cfg.Dispatch.EpicRollup; er.Enabled {
+		if _, err := cron.ParseStandard(er.Schedule); err != nil {
+			return fmt.Errorf("dispatch.epic_rollup.schedule: %w", err)
+		}
+		if er.Threshold <= 0 || er.Threshold > 1 {
+			return fmt.Errorf("dispatch.epic_rollup.threshold must be greater than 0 and at most 1")
+		}
+	}
+
+	if tr := cfg.Dispatch.Triage; tr.Enabled {
+		if _, err := cron.ParseStandard(tr.Schedule); err != nil {
+			return fmt.Errorf("dispatch.triage.schedule: %w", err)
+		}
+		if tr.Similarity <= 0 || tr.Similarity > 1 {
+			return fmt.Errorf("dispatch.triage.similarity must be greater than 0 and at most 1")
+		}
+		if tr.StaleAfter.Duration < 0 {
+			return fmt.Errorf("dispatch.triage.stale_after must not be negative")
+		}
+	}
+
+	if cx := cfg.Dispatch.Complexity; cx.Enabled {
+		if cx.MinSamples < 0 {
+			return fmt.Errorf("dispatch.complexity.min_samples must not be negative")
+		}
+		if cx.Window.Duration < 0 || cx.Retrain.Duration < 0 {
+			return fmt.Errorf("dispatch.complexity.window and retrain must not be negative")
+		}
+	}
+
+	// HA elects its leader through the claims backend, so it needs one even
+	// when bead claims are off.
+	if cl := cfg.Dispatch.Claims; cl.Enabled || cfg.HA.Enabled {
+		if cl.Backend != "sqlite" && cl.Backend != "etcd" {
+			return fmt.Errorf("dispatch.claims.backend %q must be one of sqlite, etcd", cl.Backend)
+		}
+		if cl.Backend == "etcd" && len(cl.EtcdEndpoints) == 0 {
+			return fmt.Errorf("dispatch.claims.etcd_endpoints is required for the etcd backend")
+		}
+		if cl.Enabled && cl.TTL.Duration < 30*time.Second {
+			return fmt.Errorf("dispatch.claims.ttl must be at least 30s")
+		}
+	}
+	if cfg.HA.Enabled && cfg.HA.LeaseTTL.Duration < 3*time.Second {
+		return fmt.Errorf("ha.lease_ttl must be at least 3s")
+	}
+
+	if sh := cfg.Sharding; sh.Enabled {
+		if strings.TrimSpace(sh.Shard) == "" {
+			return fmt.Errorf("sharding.shard is required when sharding is enabled")
+		}
+		if _, ok := sh.Peers[sh.Shard]; ok {
+			return fmt.Errorf("sharding.peers must not list this instance's shard %q", sh.Shard)
+		}
+		for name, p := range cfg.Projects {
+			if !p.Enabled {
+				continue
+			}
+			if _, ok := sh.Peers[p.Shard]; p.Shard != sh.Shard && !ok {
+				return fmt.Errorf("projects.%s.shard %q must be sharding.shard or one of sharding.peers", name, p.Shard)
+			}
+		}
+	}
+
+	if fo := cfg.Dispatch.FileOverlap; fo.Enabled {
+		if _, err := cron.ParseStandard(fo.Schedule); err != nil {
+			return fmt.Errorf("dispatch.file_overlap.schedule: %w", err)
+		}
+		if fo.Mode != "warn" && fo.Mode != "serialize" {
+			return fmt.Errorf("dispatch.file_overlap.mode %q must be one of warn, serialize", fo.Mode)
+		}
+		if fo.LookbackDays < 0 {
+			return fmt.Errorf("dispatch.file_overlap.lookback_days must not be negative")
+		}
+	}
+
+	if cfg.Dispatch.Worktrees.Enabled && cfg.Dispatch.Worktrees.PoolSize < 1 {
+		return fmt.Errorf("dispatch.worktrees.pool_size must be at least 1")
+	}
+
+	if cfg.Dispatch.Artifacts.RetentionDays < 0 {
+		return fmt.Errorf("dispatch.artifacts.retention_days must not be negative")
+	}
+	if cfg.Dispatch.Artifacts.MaxFileMB < 0 {
+		return fmt.Errorf("dispatch.artifacts.max_file_mb must not be negative")
+	}
+	if cfg.Dispatch.Journal.RetentionDays < 0 {
+		return fmt.Errorf("dispatch.journal.retention_days must not be negative")
+	}
+	for name, project := range cfg.Projects {
+		for _, pattern := range project.Artifacts {
+			if _, err := path.Match(pattern, ""); err != nil || !fs.ValidPath(pattern) {
+				return fmt.Errorf("projects.%s.artifacts: %q must be a valid glob relative to the workspace", name, pattern)
+			}
+		}
+		for _, command := range project.Autofix.Commands {
+			if strings.TrimSpace(command) == "" {
+				return fmt.Errorf("projects.%s.autofix.commands must not contain empty commands", name)
+			}
+		}
+		if project.Autofix.Agent && len(project.Autofix.Commands) == 0 {
+			return fmt.Errorf("projects.%s.autofix.agent needs autofix.commands to report what to fix", name)
+		}
+		switch project.TestWriter.Tier {
+		case "", "fast", "balanced", "premium":
+		default:
+			return fmt.Errorf("projects.%s.test_writer.tier %q must be one of fast, balanced, premium", name, project.TestWriter.Tier)
+		}
+		for _, pattern := range slices.Concat(project.TestWriter.CodePatterns, project.TestWriter.TestPatterns) {
+			if _, err := path.Match(pattern, ""); err != nil || strings.Contains(pattern, "/") {
+				return fmt.Errorf("projects.%s.test_writer: %q must be a valid file name glob", name, pattern)
+			}
+		}
+		for checkName, check := range project.HealthChecks {
+			field := fmt.Sprintf("projects.%s.health_checks.%s", name, checkName)
+			switch {
+			case strings.TrimSpace(check.Command) == "":
+				return fmt.Errorf("%s.command is required", field)
+			case check.Interval.Duration < 0 || check.Timeout.Duration < 0:
+				return fmt.Errorf("%s: interval and timeout must not be negative", field)
+			case check.Failures < 0:
+				return fmt.Errorf("%s.failures must not be negative", field)
+			}
+		}
+	}
+
+	for name, endpoint := range cfg.API.Endpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("api.endpoints.%s: %q is not an http(s) URL", name, endpoint)
+		}
+	}
+
 	// Validate Chief configuration
 	if cfg.Chief.Enabled {
 		if cfg.Chief.MatrixRoom == "" {
@@ -924,6 +2697,21 @@ func validate(cfg *Config) error {
 			return fmt.Errorf("matrix.read_limit must be > 0")
 		}
 	}
+	if proxy := strings.TrimSpace(cfg.Matrix.E2EProxy); proxy != "" {
+		if u, err := url.Parse(proxy); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("matrix.e2e_proxy must be an http(s) URL, got %q", proxy)
+		}
+	}
+	for command, users := range cfg.Matrix.CommandACL {
+		if strings.TrimSpace(command) == "" {
+			return fmt.Errorf("matrix.command_acl has an empty command name")
+		}
+		for _, user := range users {
+			if user = strings.TrimSpace(user); user != "*" && !strings.HasPrefix(user, "@") {
+				return fmt.Errorf("matrix.command_acl.%s: %q is not a Matrix user id or \"*\"", command, user)
+			}
+		}
+	}
 
 	// Validate dispatch CLI configuration
 	if err := ValidateDispatchConfig(cfg); err != nil {
@@ -932,7 +2720,122 @@ func validate(cfg *Config) error {
 	if err := validateDispatchCostControlConfig(cfg.Dispatch.CostControl); err != nil {
 		return fmt.Errorf("dispatch cost control configuration: %w", err)
 	}
+	if cfg.Dispatch.Warmup.IdleAfter.Duration < 0 {
+		return fmt.Errorf("dispatch.warmup.idle_after cannot be negative")
+	}
+	if cfg.Dispatch.Warmup.Timeout.Duration < 0 {
+		return fmt.Errorf("dispatch.warmup.timeout cannot be negative")
+	}
+	if cfg.Dispatch.Preflight.TTL.Duration < 0 {
+		return fmt.Errorf("dispatch.preflight.ttl must not be negative")
+	}
+	if cfg.Dispatch.Preflight.Timeout.Duration < 0 {
+		return fmt.Errorf("dispatch.preflight.timeout must not be negative")
+	}
+	if t := cfg.Dispatch.Confidence.Threshold; t < 0 || t > 1 {
+		return fmt.Errorf("dispatch.confidence.threshold must be between 0 and 1")
+	}
+	if p := cfg.Dispatch.Pair.MaxPriority; p < 0 || p > 4 {
+		return fmt.Errorf("dispatch.pair.max_priority must be between 0 and 4")
+	}
+	if cfg.Dispatch.Pair.MaxTurns < 2 {
+		return fmt.Errorf("dispatch.pair.max_turns must be at least 2")
+	}
+	if cfg.Dispatch.ResponseCache.TTL.Duration < 0 {
+		return fmt.Errorf("dispatch.response_cache.ttl must not be negative")
+	}
+	for _, role := range cfg.Dispatch.ResponseCache.Roles {
+		if role != "planner" && role != "groom" && role != "chief" {
+			return fmt.Errorf("dispatch.response_cache.roles: %q must be one of planner, groom, chief", role)
+		}
+	}
+	for i, pattern := range cfg.Dispatch.Redaction.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("dispatch.redaction.patterns[%d]: %w", i, err)
+		}
+	}
+	if cfg.Dispatch.Redaction.EntropyMinLength < 8 {
+		return fmt.Errorf("dispatch.redaction.entropy_min_length must be at least 8")
+	}
+	if cfg.Dispatch.Redaction.EntropyThreshold < 0 {
+		return fmt.Errorf("dispatch.redaction.entropy_threshold must not be negative")
+	}
+	if cfg.Dispatch.Approvals.Timeout.Duration < 0 {
+		return fmt.Errorf("dispatch.approvals.timeout must not be negative")
+	}
+	if a := cfg.Dispatch.Approvals.DefaultAction; a != "approve" && a != "deny" {
+		return fmt.Errorf("dispatch.approvals.default_action %q must be one of approve, deny", a)
+	}
+	if cfg.Dispatch.Approvals.Chat {
+		for _, command := range []string{"approve", "deny"} {
+			if !commandRestricted(cfg.Matrix, command) {
+				return fmt.Errorf("dispatch.approvals.chat needs matrix.command_acl.%s or matrix.command_senders to name who may answer approvals", command)
+			}
+		}
+	}
+	if cfg.Dispatch.FollowUps.Max < 0 {
+		return fmt.Errorf("dispatch.follow_ups.max must not be negative")
+	}
+	if p := cfg.Dispatch.FollowUps.Priority; p < 0 || p > 4 {
+		return fmt.Errorf("dispatch.follow_ups.priority must be between 0 and 4")
+	}
+	breakers := cfg.Dispatch.CircuitBreakers
+	if err := validateCircuitBreaker("dispatch.circuit_breakers.provider", breakers.Provider); err != nil {
+		return err
+	}
+	for name, b := range breakers.Providers {
+		if _, ok := cfg.Providers[name]; !ok {
+			return fmt.Errorf("dispatch.circuit_breakers.providers.%s: unknown provider", name)
+		}
+		if err := validateCircuitBreaker("dispatch.circuit_breakers.providers."+name, b); err != nil {
+			return err
+		}
+	}
+	for category, b := range breakers.Categories {
+		if err := validateCircuitBreaker("dispatch.circuit_breakers.categories."+category, b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func inheritBreakerTiming(b, from CircuitBreaker) CircuitBreaker {
+	if b.Window.Duration == 0 {
+		b.Window = from.Window
+	}
+	if b.Cooldown.Duration == 0 {
+		b.Cooldown = from.Cooldown
+	}
+	return b
+}
+
+// commandRestricted reports whether the Matrix command may only be run by
+// named users: its command_acl entry, or command_senders without one, lists
+// users and does not allow anyone with "*".
+func commandRestricted(m Matrix, command string) bool {
+	users, ok := m.CommandACL[command]
+	if !ok {
+		users = m.CommandSenders
+	}
+	if len(users) == 0 {
+		return false
+	}
+	for _, user := range users {
+		if strings.TrimSpace(user) == "*" {
+			return false
+		}
+	}
+	return true
+}
 
+func validateCircuitBreaker(key string, b CircuitBreaker) error {
+	if b.Threshold < 0 {
+		return fmt.Errorf("%s.threshold cannot be negative", key)
+	}
+	if b.Window.Duration < 0 || b.Cooldown.Duration < 0 {
+		return fmt.Errorf("%s window and cooldown cannot be negative", key)
+	}
 	return nil
 }
 
@@ -960,13 +2863,25 @@ func (rl *RateLimits) GetProjectBudget(project string) int {
 	return rl.Budget[project]
 }
 
-// ResolveRoom returns the Matrix room for a project.
-// Priority: projects.<name>.matrix_room -> reporter.default_room -> empty string.
+// EmailRoutePrefix marks a reporting destination that is a project's email
+// recipient list rather than a Matrix room.
+const EmailRoutePrefix = "email:"
+
+// ResolveRoom returns the reporting destination for a project.
+// Matrix priority: projects.<name>.matrix_room -> reporter.default_room -> empty string.
+// With reporter.channel = "email" it is "email:<project>" when the project has
+// recipients, and empty otherwise.
 func (cfg *Config) ResolveRoom(project string) string {
 	if cfg == nil {
 		return ""
 	}
 	project = strings.TrimSpace(project)
+	if cfg.Reporter.Channel == "email" {
+		if len(cfg.EmailRecipients(EmailRoutePrefix+project)) == 0 {
+			return ""
+		}
+		return EmailRoutePrefix + project
+	}
 	if project != "" {
 		if p, ok := cfg.Projects[project]; ok {
 			if room := strings.TrimSpace(p.MatrixRoom); room != "" {
@@ -977,13 +2892,186 @@ func (cfg *Config) ResolveRoom(project string) string {
 	return strings.TrimSpace(cfg.Reporter.DefaultRoom)
 }
 
+// OwnsProject reports whether this instance runs the named project: always
+// without sharding, otherwise when the project is assigned to its shard.
+func (cfg *Config) OwnsProject(name string) bool {
+	if cfg == nil || !cfg.Sharding.Enabled {
+		return true
+	}
+	p, ok := cfg.Projects[name]
+	return ok && p.Shard == cfg.Sharding.Shard
+}
+
+// ShardProjects returns a copy of cfg whose Projects holds only the projects
+// this instance runs, for iterating over its own share of the work.
+func (cfg *Config) ShardProjects() *Config {
+	if cfg == nil || !cfg.Sharding.Enabled {
+		return cfg
+	}
+	sharded := *cfg
+	sharded.Projects = make(map[string]Project, len(cfg.Projects))
+	for name, p := range cfg.Projects {
+		if cfg.OwnsProject(name) {
+			sharded.Projects[name] = p
+		}
+	}
+	return &sharded
+}
+
+// ShardWorkflowID returns the ID of a cron that covers every project this
+// instance runs: id itself without sharding, otherwise id suffixed with the
+// shard, so the shards' crons do not collide in a shared namespace.
+func (cfg *Config) ShardWorkflowID(id string) string {
+	if cfg == nil || !cfg.Sharding.Enabled {
+		return id
+	}
+	return id + "-" + cfg.Sharding.Shard
+}
+
+// StageSLAEnabled reports whether any workflow stage sets a max_duration.
+func (cfg *Config) StageSLAEnabled() bool {
+	if cfg == nil {
+		return false
+	}
+	for _, wf := range cfg.Workflows {
+		for _, stage := range wf.Stages {
+			if stage.MaxDuration.Duration > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WarmupProviders returns tier-assigned providers that opted into warmup pings,
+// deduplicated and sorted.
+func (cfg *Config) WarmupProviders() []string {
+	if cfg == nil {
+		return nil
+	}
+	seen := make(map[string]struct{})
+	var out []string
+	for _, tier := range [][]string{cfg.Tiers.Fast, cfg.Tiers.Balanced, cfg.Tiers.Premium} {
+		for _, name := range tier {
+			if _, dup := seen[name]; dup {
+				continue
+			}
+			seen[name] = struct{}{}
+			if provider, ok := cfg.Providers[name]; ok && provider.Warmup {
+				out = append(out, name)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// EmailRecipients returns the recipients of an email route from ResolveRoom:
+// the project's email_recipients, or reporter.email.default_recipients.
+func (cfg *Config) EmailRecipients(route string) []string {
+	project, ok := strings.CutPrefix(route, EmailRoutePrefix)
+	if cfg == nil || !ok {
+		return nil
+	}
+	if p, ok := cfg.Projects[project]; ok && len(p.EmailRecipients) > 0 {
+		return p.EmailRecipients
+	}
+	return cfg.Reporter.Email.DefaultRecipients
+}
+
+// ProjectForRepo returns the project whose forge_repo matches repo,
+// ignoring case.
+func (cfg *Config) ProjectForRepo(repo string) (string, bool) {
+	repo = strings.TrimSpace(repo)
+	if cfg == nil || repo == "" {
+		return "", false
+	}
+	for name, project := range cfg.Projects {
+		if strings.EqualFold(strings.TrimSpace(project.ForgeRepo), repo) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func validateForgeRepos(cfg *Config) error {
+	owners := make(map[string]string)
+	for name, project := range cfg.Projects {
+		repo := strings.ToLower(strings.TrimSpace(project.ForgeRepo))
+		if repo == "" {
+			continue
+		}
+		if other, dup := owners[repo]; dup {
+			return fmt.Errorf("projects %q and %q share forge_repo %q", other, name, project.ForgeRepo)
+		}
+		owners[repo] = name
+	}
+	return nil
+}
+
+func validateReporterWebhooks(cfg *Config) error {
+	for i, hook := range cfg.Reporter.Webhooks {
+		if u, err := url.Parse(hook.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("webhooks[%d].url must be an http(s) URL, got %q", i, hook.URL)
+		}
+		if hook.Timeout.Duration < 0 {
+			return fmt.Errorf("webhooks[%d].timeout must not be negative", i)
+		}
+		if _, err := template.New("webhook").Funcs(template.FuncMap{"json": func(any) string { return "" }}).Parse(hook.Template); err != nil {
+			return fmt.Errorf("webhooks[%d].template: %w", i, err)
+		}
+		for _, project := range hook.Projects {
+			if _, ok := cfg.Projects[project]; !ok {
+				return fmt.Errorf("webhooks[%d].projects: unknown project %q", i, project)
+			}
+		}
+	}
+	return nil
+}
+
+func validateReporterChannel(cfg *Config) error {
+	switch cfg.Reporter.Channel {
+	case "", "matrix":
+		return nil
+	case "email":
+	default:
+		return fmt.Errorf("unknown channel %q (want matrix or email)", cfg.Reporter.Channel)
+	}
+	e := cfg.Reporter.Email
+	if strings.TrimSpace(e.SMTPHost) == "" {
+		return fmt.Errorf("email.smtp_host is required when channel is email")
+	}
+	if e.SMTPPort <= 0 || e.SMTPPort > 65535 {
+		return fmt.Errorf("email.smtp_port must be between 1 and 65535")
+	}
+	if _, err := mail.ParseAddress(e.From); err != nil {
+		return fmt.Errorf("email.from %q is not an address: %w", e.From, err)
+	}
+	if e.BatchInterval.Duration < 0 {
+		return fmt.Errorf("email.batch_interval must not be negative")
+	}
+	for _, addr := range e.DefaultRecipients {
+		if _, err := mail.ParseAddress(addr); err != nil {
+			return fmt.Errorf("email.default_recipients: %q is not an address", addr)
+		}
+	}
+	for name, p := range cfg.Projects {
+		for _, addr := range p.EmailRecipients {
+			if _, err := mail.ParseAddress(addr); err != nil {
+				return fmt.Errorf("projects.%s.email_recipients: %q is not an address", name, addr)
+			}
+		}
+	}
+	return nil
+}
+
 // MissingProjectRoomRouting returns enabled projects that have neither a project room
 // nor a reporter-level default room configured.
 func (cfg *Config) MissingProjectRoomRouting() []string {
 	if cfg == nil {
 		return nil
 	}
-	if strings.TrimSpace(cfg.Reporter.DefaultRoom) != "" {
+	if cfg.Reporter.Channel != "email" && strings.TrimSpace(cfg.Reporter.DefaultRoom) != "" {
 		return nil
 	}
 
@@ -992,7 +3080,7 @@ func (cfg *Config) MissingProjectRoomRouting() []string {
 		if !project.Enabled {
 			continue
 		}
-		if strings.TrimSpace(project.MatrixRoom) != "" {
+		if cfg.ResolveRoom(name) != "" {
 			continue
 		}
 		missing = append(missing, name)
@@ -1016,6 +3104,30 @@ func (c Cadence) StartClock() (int, int, error) {
 	return parseClock(c.SprintStartTime)
 }
 
+// SprintStart returns the latest sprint_start_day at sprint_start_time, in
+// the cadence timezone, at or before t.
+func (c Cadence) SprintStart(t time.Time) (time.Time, error) {
+	day, err := c.StartWeekday()
+	if err != nil {
+		return time.Time{}, err
+	}
+	hour, minute, err := c.StartClock()
+	if err != nil {
+		return time.Time{}, err
+	}
+	loc, err := c.LoadLocation()
+	if err != nil {
+		return time.Time{}, err
+	}
+	local := t.In(loc)
+	start := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, loc)
+	start = start.AddDate(0, 0, -((int(local.Weekday()) - int(day) + 7) % 7))
+	if start.After(t) {
+		start = start.AddDate(0, 0, -7)
+	}
+	return start, nil
+}
+
 // LoadLocation parses cadence timezone.
 func (c Cadence) LoadLocation() (*time.Location, error) {
 	tz := strings.TrimSpace(c.Timezone)
@@ -1029,6 +3141,251 @@ func (c Cadence) LoadLocation() (*time.Location, error) {
 	return loc, nil
 }
 
+// DailyDigestSchedule returns the cron schedule of the standup digest, or ""
+// when daily_digest_time is unset.
+func (r Reporter) DailyDigestSchedule() (string, error) {
+	if strings.TrimSpace(r.DailyDigestTime) == "" {
+		return "", nil
+	}
+	hour, minute, err := parseClock(r.DailyDigestTime)
+	if err != nil {
+		return "", fmt.Errorf("invalid daily_digest_time %q: %w", r.DailyDigestTime, err)
+	}
+	return fmt.Sprintf("%d %d * * *", minute, hour), nil
+}
+
+// blackoutLayout is the time format of blackout window bounds.
+const blackoutLayout = "2006-01-02 15:04"
+
+// LoadLocation parses the schedule timezone.
+func (s ProjectSchedule) LoadLocation() (*time.Location, error) {
+	return Cadence{Timezone: s.Timezone}.LoadLocation()
+}
+
+// DispatchAllowed reports whether a dispatch may start at now and, when it
+// may not, why. urgent marks a priority-0 bug, which bypasses the schedule
+// when urgent_override is set.
+func (s ProjectSchedule) DispatchAllowed(now time.Time, urgent bool) (bool, string) {
+	if urgent && s.UrgentOverride {
+		return true, ""
+	}
+	loc, err := s.LoadLocation()
+	if err != nil {
+		return false, err.Error()
+	}
+	local := now.In(loc)
+
+	for _, b := range s.Blackouts {
+		start, end, err := b.bounds(loc)
+		if err != nil {
+			return false, err.Error()
+		}
+		if !local.Before(start) && local.Before(end) {
+			reason := fmt.Sprintf("blackout until %s", b.End)
+			if b.Reason != "" {
+				reason += ": " + b.Reason
+			}
+			return false, reason
+		}
+	}
+
+	if len(s.WorkingDays) > 0 {
+		allowed := false
+		for _, day := range s.WorkingDays {
+			if wd, err := parseWeekday(day); err == nil && wd == local.Weekday() {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false, fmt.Sprintf("%s is not a working day", local.Weekday())
+		}
+	}
+
+	if s.WorkingHours != "" {
+		from, to, err := parseClockRange(s.WorkingHours)
+		if err != nil {
+			return false, err.Error()
+		}
+		minute := local.Hour()*60 + local.Minute()
+		inside := minute >= from && minute < to
+		if to < from {
+			inside = minute >= from || minute < to
+		}
+		if !inside {
+			return false, fmt.Sprintf("outside working hours %s %s", s.WorkingHours, loc)
+		}
+	}
+	return true, ""
+}
+
+func (b BlackoutWindow) bounds(loc *time.Location) (time.Time, time.Time, error) {
+	start, err := time.ParseInLocation(blackoutLayout, strings.TrimSpace(b.Start), loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid blackout start %q: must be YYYY-MM-DD HH:MM", b.Start)
+	}
+	end, err := time.ParseInLocation(blackoutLayout, strings.TrimSpace(b.End), loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid blackout end %q: must be YYYY-MM-DD HH:MM", b.End)
+	}
+	return start, end, nil
+}
+
+// parseClockRange parses HH:MM-HH:MM into minutes since midnight.
+func parseClockRange(raw string) (int, int, error) {
+	fromRaw, toRaw, ok := strings.Cut(raw, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid working_hours %q: must be HH:MM-HH:MM", raw)
+	}
+	fh, fm, err := parseClock(fromRaw)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid working_hours %q: %w", raw, err)
+	}
+	th, tm, err := parseClock(toRaw)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid working_hours %q: %w", raw, err)
+	}
+	from, to := fh*60+fm, th*60+tm
+	if from == to {
+		return 0, 0, fmt.Errorf("invalid working_hours %q: start and end must differ", raw)
+	}
+	return from, to, nil
+}
+
+func validateProjectSchedule(s ProjectSchedule) error {
+	loc, err := s.LoadLocation()
+	if err != nil {
+		return err
+	}
+	if s.WorkingHours != "" {
+		if _, _, err := parseClockRange(s.WorkingHours); err != nil {
+			return err
+		}
+	}
+	for _, day := range s.WorkingDays {
+		if _, err := parseWeekday(day); err != nil {
+			return fmt.Errorf("invalid working_days entry %q: %w", day, err)
+		}
+	}
+	for i, b := range s.Blackouts {
+		start, end, err := b.bounds(loc)
+		if err != nil {
+			return err
+		}
+		if !end.After(start) {
+			return fmt.Errorf("blackout %d must end after it starts", i)
+		}
+	}
+	return nil
+}
+
+// MaintenanceAt returns the maintenance window open at now and when it
+// closes. ok is false outside every window.
+func (g General) MaintenanceAt(now time.Time) (w MaintenanceWindow, until time.Time, ok bool) {
+	for _, w := range g.MaintenanceWindows {
+		if until, ok := w.openAt(now); ok {
+			return w, until, true
+		}
+	}
+	return MaintenanceWindow{}, time.Time{}, false
+}
+
+// Label names the window for logs and health events: its name, or its
+// schedule when it has none.
+func (w MaintenanceWindow) Label() string {
+	switch {
+	case w.Name != "":
+		return w.Name
+	case w.Cron != "":
+		return fmt.Sprintf("%q for %s", w.Cron, w.Duration.Duration)
+	case len(w.Days) > 0:
+		return strings.Join(w.Days, ",") + " " + w.Hours
+	}
+	return w.Hours
+}
+
+// openAt reports whether the window is open at now and, if so, when it
+// closes. A window whose settings do not parse is never open; validation
+// rejects them at load.
+func (w MaintenanceWindow) openAt(now time.Time) (time.Time, bool) {
+	loc, err := Cadence{Timezone: w.Timezone}.LoadLocation()
+	if err != nil {
+		return time.Time{}, false
+	}
+	local := now.In(loc)
+
+	if w.Cron != "" {
+		sched, err := cron.ParseStandard(w.Cron)
+		if err != nil || w.Duration.Duration <= 0 {
+			return time.Time{}, false
+		}
+		// A window that opened less than duration ago is still open.
+		start := sched.Next(local.Add(-w.Duration.Duration))
+		if start.After(local) {
+			return time.Time{}, false
+		}
+		return start.Add(w.Duration.Duration), true
+	}
+
+	from, to, err := parseClockRange(w.Hours)
+	if err != nil {
+		return time.Time{}, false
+	}
+	minute := local.Hour()*60 + local.Minute()
+	opened := local // the day the window opened
+	switch {
+	case to > from && minute >= from && minute < to:
+	case to < from && minute >= from:
+	case to < from && minute < to:
+		opened = local.AddDate(0, 0, -1)
+	default:
+		return time.Time{}, false
+	}
+	if len(w.Days) > 0 && !slices.ContainsFunc(w.Days, func(day string) bool {
+		wd, err := parseWeekday(day)
+		return err == nil && wd == opened.Weekday()
+	}) {
+		return time.Time{}, false
+	}
+	end := time.Date(opened.Year(), opened.Month(), opened.Day(), to/60, to%60, 0, 0, loc)
+	if to < from {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end, true
+}
+
+func validateMaintenanceWindow(w MaintenanceWindow) error {
+	if _, err := (Cadence{Timezone: w.Timezone}).LoadLocation(); err != nil {
+		return err
+	}
+	switch {
+	case w.Cron != "" && w.Hours != "":
+		return fmt.Errorf("set either cron or hours, not both")
+	case w.Cron != "":
+		if _, err := cron.ParseStandard(w.Cron); err != nil {
+			return fmt.Errorf("invalid cron %q: %w", w.Cron, err)
+		}
+		if w.Duration.Duration <= 0 {
+			return fmt.Errorf("cron windows need a positive duration")
+		}
+		if len(w.Days) > 0 {
+			return fmt.Errorf("days only apply to hours windows; put the weekdays in the cron schedule")
+		}
+	case w.Hours != "":
+		if _, _, err := parseClockRange(w.Hours); err != nil {
+			return fmt.Errorf("invalid hours %q: must be HH:MM-HH:MM with different ends", w.Hours)
+		}
+		for _, day := range w.Days {
+			if _, err := parseWeekday(day); err != nil {
+				return fmt.Errorf("invalid days entry %q: %w", day, err)
+			}
+		}
+	default:
+		return fmt.Errorf("set cron and duration, or hours")
+	}
+	return nil
+}
+
 func validateCadenceConfig(c Cadence) error {
 	length, err := c.SprintLengthDuration()
 	if err != nil {
@@ -1049,6 +3406,17 @@ func validateCadenceConfig(c Cadence) error {
 	if _, err := c.LoadLocation(); err != nil {
 		return err
 	}
+	for _, day := range c.Holidays {
+		if _, err := time.Parse(time.DateOnly, strings.TrimSpace(day)); err != nil {
+			return fmt.Errorf("invalid holiday %q: must be YYYY-MM-DD", day)
+		}
+	}
+	if c.HolidayCalendar != "" {
+		u, err := url.Parse(c.HolidayCalendar)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid holiday_calendar %q: must be an http(s) URL", c.HolidayCalendar)
+		}
+	}
 	return nil
 }
 
@@ -1200,6 +3568,36 @@ func validateDoDConfig(projectName string, dod DoDConfig) error {
 	// Note: Empty checks array is valid - DoD can be coverage-only or flags-only
 	// Note: All string commands in checks are valid - we can't validate arbitrary commands
 
+	seen := map[string]bool{}
+	for _, scanner := range dod.Security.Scanners {
+		if !slices.Contains(SecurityScanners, scanner) {
+			return fmt.Errorf("security.scanners: %q must be one of %s", scanner, strings.Join(SecurityScanners, ", "))
+		}
+		if seen[scanner] {
+			return fmt.Errorf("security.scanners: %q listed twice", scanner)
+		}
+		seen[scanner] = true
+	}
+	if !slices.Contains(SecuritySeverities, dod.Security.Severity) {
+		return fmt.Errorf("security.severity: %q must be one of %s", dod.Security.Severity, strings.Join(SecuritySeverities, ", "))
+	}
+
+	if dod.Coverage.Pattern != "" {
+		if strings.TrimSpace(dod.Coverage.Command) == "" {
+			return fmt.Errorf("coverage.pattern requires coverage.command")
+		}
+		re, err := regexp.Compile(dod.Coverage.Pattern)
+		if err != nil {
+			return fmt.Errorf("coverage.pattern: %w", err)
+		}
+		if re.NumSubexp() < 1 {
+			return fmt.Errorf("coverage.pattern must capture the percentage in a group")
+		}
+	}
+	if dod.Coverage.Tolerance < 0 || dod.Coverage.Tolerance > 100 {
+		return fmt.Errorf("coverage.tolerance must be between 0 and 100: %g", dod.Coverage.Tolerance)
+	}
+
 	return nil
 }
 
@@ -1232,6 +3630,114 @@ func validateProjectMergeConfig(projectName string, project Project) error {
 	}
 }
 
+func validateExperiment(name string, exp Experiment) error {
+	switch exp.Role {
+	case "planner", "coder", "reviewer":
+	default:
+		return fmt.Errorf("experiments.%s.role %q must be one of planner, coder, reviewer", name, exp.Role)
+	}
+	if len(exp.Variants) < 2 {
+		return fmt.Errorf("experiments.%s needs at least two variants", name)
+	}
+	seen := make(map[string]struct{}, len(exp.Variants))
+	for i, v := range exp.Variants {
+		if strings.TrimSpace(v.Name) == "" {
+			return fmt.Errorf("experiments.%s.variants[%d] missing name", name, i)
+		}
+		if _, ok := seen[v.Name]; ok {
+			return fmt.Errorf("experiments.%s has duplicate variant %q", name, v.Name)
+		}
+		seen[v.Name] = struct{}{}
+		if v.Weight < 0 {
+			return fmt.Errorf("experiments.%s variant %q weight cannot be negative: %d", name, v.Name, v.Weight)
+		}
+		if v.Prompt != "" {
+			if _, err := os.Stat(v.Prompt); err != nil {
+				return fmt.Errorf("experiments.%s variant %q prompt: %w", name, v.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// validateRoles checks a project's role registry. Every stage a role claims
+// must exist in some workflow, and only one role may claim it.
+func validateRoles(cfg *Config, roles map[string]RoleConfig) error {
+	stageOwners := map[string]string{}
+	for _, name := range slices.Sorted(maps.Keys(roles)) {
+		role := roles[name]
+		if !roleNamePattern.MatchString(name) {
+			return fmt.Errorf("role %q must be lowercase letters, digits and hyphens", name)
+		}
+		if slices.Contains(BuiltinRoles, name) {
+			return fmt.Errorf("role %q is built in; configure its prompt under prompts", name)
+		}
+		switch role.Tier {
+		case "", "fast", "balanced", "premium":
+		default:
+			return fmt.Errorf("role %q tier %q must be one of fast, balanced, premium", name, role.Tier)
+		}
+		if role.Provider != "" {
+			if _, ok := cfg.Providers[role.Provider]; !ok {
+				return fmt.Errorf("role %q references unknown provider %q", name, role.Provider)
+			}
+		}
+		if role.Prompt != "" {
+			info, err := os.Stat(role.Prompt)
+			if err != nil {
+				return fmt.Errorf("role %q prompt: %w", name, err)
+			}
+			if info.IsDir() {
+				return fmt.Errorf("role %q prompt %s is a directory", name, role.Prompt)
+			}
+		}
+		for _, stage := range role.Stages {
+			if !cfg.hasStage(stage) {
+				return fmt.Errorf("role %q claims unknown workflow stage %q", name, stage)
+			}
+			if owner, ok := stageOwners[stage]; ok {
+				return fmt.Errorf("stage %q is claimed by both %q and %q", stage, owner, name)
+			}
+			stageOwners[stage] = name
+		}
+	}
+	return nil
+}
+
+// hasStage reports whether any workflow defines a stage named stage.
+func (cfg *Config) hasStage(stage string) bool {
+	for _, wf := range cfg.Workflows {
+		for _, st := range wf.Stages {
+			if st.Name == stage {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func validatePromptTemplates(prompts PromptTemplates) error {
+	for _, entry := range []struct{ role, path string }{
+		{"planner", prompts.Planner},
+		{"coder", prompts.Coder},
+		{"reviewer", prompts.Reviewer},
+	} {
+		if entry.path == "" {
+			continue
+		}
+		info, err := os.Stat(entry.path)
+		if err != nil {
+			return fmt.Errorf("%s template: %w", entry.role, err)
+		}
+		if info.IsDir() {
+			return fmt.Errorf("%s template %s is a directory", entry.role, entry.path)
+		}
+	}
+	return nil
+}
+
 func validateDispatchCostControlConfig(cc DispatchCostControl) error {
 	if cc.PauseOnChurn {
 		if cc.ChurnPauseWindow.Duration <= 0 {
@@ -1268,6 +3774,17 @@ func validateDispatchCostControlConfig(cc DispatchCostControl) error {
 	if cc.PerBeadCostCapUSD < 0 {
 		return fmt.Errorf("per_bead_cost_cap_usd cannot be negative")
 	}
+	if cc.PerDispatchCostCapUSD < 0 {
+		return fmt.Errorf("per_dispatch_cost_cap_usd cannot be negative")
+	}
+	for role, limit := range cc.RoleCostCapsUSD {
+		if role != "coder" && role != "reviewer" {
+			return fmt.Errorf("role_cost_caps_usd role %q must be coder or reviewer", role)
+		}
+		if limit < 0 {
+			return fmt.Errorf("role_cost_caps_usd.%s cannot be negative", role)
+		}
+	}
 	if cc.PerBeadStageAttemptLimit < 0 {
 		return fmt.Errorf("per_bead_stage_attempt_limit cannot be negative")
 	}
@@ -1389,6 +3906,26 @@ func ValidateDispatchConfig(cfg *Config) error {
 		}
 	}
 
+	for i, rule := range routing.Rules {
+		field := fmt.Sprintf("dispatch.routing.rules[%d]", i)
+		if len(rule.Labels) == 0 {
+			validationErr.add(field+".labels", "a routing rule needs at least one label", "list the bead labels the rule applies to")
+		}
+		switch rule.Tier {
+		case "", "fast", "balanced", "premium":
+		default:
+			validationErr.add(field+".tier", fmt.Sprintf("invalid tier %q", rule.Tier), "choose one of: fast, balanced, premium")
+		}
+		if rule.Provider != "" {
+			if _, ok := cfg.Providers[rule.Provider]; !ok {
+				validationErr.add(field+".provider", fmt.Sprintf("unknown provider %q", rule.Provider), "name a provider defined under [providers]")
+			}
+		}
+		if rule.Tier == "" && rule.Provider == "" && rule.Reviewer == "" && !rule.RequireReview {
+			validationErr.add(field, "routing rule changes nothing", "set tier, provider, reviewer or require_review")
+		}
+	}
+
 	// Validate CLI config blocks.
 	for cliName, cliConfig := range cfg.Dispatch.CLI {
 		if err := validateCLIConfig(cliName, cliConfig); err != nil {
@@ -1407,6 +3944,22 @@ func ValidateDispatchConfig(cfg *Config) error {
 		"premium":  strings.TrimSpace(routing.PremiumBackend),
 	}
 	for providerName, provider := range cfg.Providers {
+		if provider.RequestsPerMinute < 0 || provider.TokensPerMinute < 0 {
+			validationErr.add(
+				fmt.Sprintf("providers.%s", providerName),
+				"requests_per_minute and tokens_per_minute must not be negative",
+				"use 0 for no per-minute limit",
+			)
+		}
+		switch provider.UsageFormat {
+		case "", "claude", "codex", "openclaw":
+		default:
+			validationErr.add(
+				fmt.Sprintf("providers.%s.usage_format", providerName),
+				fmt.Sprintf("invalid usage format %q", provider.UsageFormat),
+				"choose one of: claude, codex, openclaw, or leave empty",
+			)
+		}
 		tier := strings.TrimSpace(strings.ToLower(provider.Tier))
 		backend := tierBackends[tier]
 		if dispatchConfigured && tier != "" && backend == "" {
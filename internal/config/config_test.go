@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -221,6 +222,51 @@ merge_method = ""
 	}
 }
 
+func TestLoadProjectPromptTemplates(t *testing.T) {
+	workspace := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(workspace, "prompts"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(workspace, "prompts", "review.tmpl"), []byte("{{.Default}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := validConfig + fmt.Sprintf(`
+
+[projects.prompted]
+enabled = true
+beads_dir = %q
+workspace = %q
+priority = 1
+
+[projects.prompted.prompts]
+reviewer = "prompts/review.tmpl"
+`, filepath.Join(workspace, ".beads"), workspace)
+	loaded, err := Load(writeTestConfig(t, cfg))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	prompts := loaded.Projects["prompted"].Prompts
+	if want := filepath.Join(workspace, "prompts", "review.tmpl"); prompts.ForRole("reviewer") != want {
+		t.Fatalf("reviewer template = %q, want %q", prompts.ForRole("reviewer"), want)
+	}
+	if prompts.ForRole("coder") != "" || prompts.ForRole("ops") != "" {
+		t.Fatalf("unexpected templates for unset roles: %+v", prompts)
+	}
+}
+
+func TestLoadProjectPromptTemplateMissingFile(t *testing.T) {
+	cfg := validConfig + `
+
+[projects.test.prompts]
+coder = "/nonexistent/coder.tmpl"
+`
+	_, err := Load(writeTestConfig(t, cfg))
+	if err == nil || !strings.Contains(err.Error(), "coder template") {
+		t.Fatalf("expected missing coder template error, got %v", err)
+	}
+}
+
 func TestLoadProjectMergeConfigCustom(t *testing.T) {
 	cfg := validConfig + `
 
@@ -539,6 +585,428 @@ role = "reviewer"
 	}
 }
 
+func TestLoadWorkflowTransitions(t *testing.T) {
+	cfg := validConfig + `
+
+[workflows.dev]
+
+[[workflows.dev.stages]]
+name = "implement"
+role = "coder"
+next = ["review"]
+require_checks = ["go test ./..."]
+
+[[workflows.dev.stages]]
+name = "review"
+role = "reviewer"
+next = ["implement", "done"]
+require_labels = ["reviewed"]
+require_approval = true
+`
+	loaded, err := Load(writeTestConfig(t, cfg))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	review := loaded.Workflows["dev"].Stages[1]
+	if len(review.Next) != 2 || review.RequireLabels[0] != "reviewed" || !review.RequireApproval {
+		t.Fatalf("unexpected review stage: %+v", review)
+	}
+	if got := loaded.Workflows["dev"].Stages[0].RequireChecks; len(got) != 1 || got[0] != "go test ./..." {
+		t.Fatalf("unexpected implement checks: %v", got)
+	}
+
+	for name, next := range map[string]string{
+		"unknown next stage": `"deploy"`,
+		"itself":             `"implement"`,
+	} {
+		bad := validConfig + `
+
+[workflows.dev]
+
+[[workflows.dev.stages]]
+name = "implement"
+role = "coder"
+next = [` + next + `]
+`
+		if _, err := Load(writeTestConfig(t, bad)); err == nil {
+			t.Fatalf("expected %s to be rejected", name)
+		}
+	}
+}
+
+func TestLoadDoDSecurity(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig+"\n[projects.test.dod.security]\nscanners = [\"gosec\", \"semgrep\"]\n"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	sec := loaded.Projects["test"].DoD.Security
+	if len(sec.Scanners) != 2 || sec.Severity != "high" {
+		t.Fatalf("unexpected security config: %+v", sec)
+	}
+
+	for _, tc := range []struct{ body, want string }{
+		{"scanners = [\"bandit\"]", `"bandit" must be one of`},
+		{"scanners = [\"gosec\", \"gosec\"]", "listed twice"},
+		{"scanners = [\"trivy\"]\nseverity = \"severe\"", `security.severity: "severe"`},
+	} {
+		_, err := Load(writeTestConfig(t, validConfig+"\n[projects.test.dod.security]\n"+tc.body+"\n"))
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tc.body, tc.want, err)
+		}
+	}
+}
+
+func TestLoadDoDCoverage(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig+"\n[projects.test.dod.coverage]\ncommand = \"make cover\"\ntolerance = 0.5\n"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	cov := loaded.Projects["test"].DoD.Coverage
+	if cov.Command != "make cover" || cov.Tolerance != 0.5 {
+		t.Fatalf("unexpected coverage config: %+v", cov)
+	}
+
+	for _, tc := range []struct{ body, want string }{
+		{"pattern = \"(\\\\d+)%\"", "requires coverage.command"},
+		{"command = \"make cover\"\npattern = \"[\"", "coverage.pattern:"},
+		{"command = \"make cover\"\npattern = \"total\"", "capture the percentage"},
+		{"command = \"make cover\"\ntolerance = -1", "coverage.tolerance"},
+	} {
+		_, err := Load(writeTestConfig(t, validConfig+"\n[projects.test.dod.coverage]\n"+tc.body+"\n"))
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tc.body, tc.want, err)
+		}
+	}
+}
+
+func TestLoadProjectAutofix(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig+"\n[projects.test.autofix]\ncommands = [\"gofmt -w .\", \"golangci-lint run --fix\"]\nagent = true\n"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	fix := loaded.Projects["test"].Autofix
+	if len(fix.Commands) != 2 || !fix.Agent || fix.CommitMessage != "style: apply lint and format fixes" {
+		t.Fatalf("unexpected autofix config: %+v", fix)
+	}
+
+	for _, tc := range []struct{ body, want string }{
+		{"agent = true", "needs autofix.commands"},
+		{"commands = [\" \"]", "empty commands"},
+	} {
+		_, err := Load(writeTestConfig(t, validConfig+"\n[projects.test.autofix]\n"+tc.body+"\n"))
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tc.body, tc.want, err)
+		}
+	}
+}
+
+func TestLoadProjectTestWriter(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig+"\n[projects.test.test_writer]\nenabled = true\n"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	tw := loaded.Projects["test"].TestWriter
+	if !tw.Enabled || tw.Tier != "balanced" || tw.CommitMessage != "test: add tests for untested changes" ||
+		!slices.Contains(tw.CodePatterns, "*.go") || !slices.Contains(tw.TestPatterns, "*_test.go") {
+		t.Fatalf("unexpected test_writer config: %+v", tw)
+	}
+
+	for _, tc := range []struct{ body, want string }{
+		{"tier = \"huge\"", "must be one of fast, balanced, premium"},
+		{"test_patterns = [\"tests/*.py\"]", "must be a valid file name glob"},
+		{"code_patterns = [\"[\"]", "must be a valid file name glob"},
+	} {
+		_, err := Load(writeTestConfig(t, validConfig+"\n[projects.test.test_writer]\n"+tc.body+"\n"))
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tc.body, tc.want, err)
+		}
+	}
+}
+
+func TestLoadProjectHealthChecks(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig+`
+[projects.test.health_checks.api-smoke]
+command = "curl -fsS http://localhost:8080/healthz"
+after_dispatch = true
+pause = true
+`))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	check := loaded.Projects["test"].HealthChecks["api-smoke"]
+	if check.Interval.Duration != loaded.Health.CheckInterval.Duration || check.Timeout.Duration != time.Minute ||
+		check.Failures != 1 || !check.AfterDispatch || !check.Pause {
+		t.Fatalf("unexpected health check config: %+v", check)
+	}
+
+	for _, tc := range []struct{ body, want string }{
+		{"interval = \"1m\"", "health_checks.api-smoke.command is required"},
+		{"command = \"true\"\nfailures = -1", "failures must not be negative"},
+	} {
+		_, err := Load(writeTestConfig(t, validConfig+"\n[projects.test.health_checks.api-smoke]\n"+tc.body+"\n"))
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tc.body, tc.want, err)
+		}
+	}
+}
+
+func TestLoadReporterThrottle(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig+`
+[reporter.throttle]
+enabled = true
+room_limit = 3
+`))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	throttle := loaded.Reporter.Throttle
+	if !throttle.Enabled || throttle.RoomLimit != 3 || throttle.DedupWindow.Duration != 10*time.Minute ||
+		throttle.RatePeriod.Duration != time.Minute || throttle.DigestInterval.Duration != 5*time.Minute {
+		t.Fatalf("unexpected throttle config: %+v", throttle)
+	}
+
+	_, err = Load(writeTestConfig(t, validConfig+"\n[reporter.throttle]\nroom_limit = -1\n"))
+	if err == nil || !strings.Contains(err.Error(), "throttle limits must not be negative") {
+		t.Fatalf("expected negative limit error, got %v", err)
+	}
+}
+
+func TestLoadOnCall(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig+`
+[[oncall.shifts]]
+user = "@alice:example.org"
+days = ["Monday", "Tuesday"]
+hours = "09:00-17:00"
+timezone = "Europe/Helsinki"
+
+[oncall.pagerduty]
+routing_key = "rk"
+`))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	monday := time.Date(2026, 1, 5, 8, 0, 0, 0, time.UTC) // 10:00 in Helsinki
+	if user, ok := loaded.OnCall.OnCallAt(monday); !ok || user != "@alice:example.org" {
+		t.Fatalf("on call Monday morning = %q, %v", user, ok)
+	}
+	if _, ok := loaded.OnCall.OnCallAt(monday.Add(12 * time.Hour)); ok {
+		t.Fatal("expected nobody on call Monday night")
+	}
+
+	for _, tc := range []struct{ body, want string }{
+		{"[[oncall.shifts]]\nuser = \"alice\"\nhours = \"09:00-17:00\"", "must be a Matrix user ID"},
+		{"[[oncall.shifts]]\nuser = \"@alice:example.org\"", "hours is required"},
+		{"[[oncall.shifts]]\nuser = \"@alice:example.org\"\nhours = \"09:00-17:00\"\ndays = [\"Someday\"]", "invalid days entry"},
+		{"[oncall.pagerduty]\nschedule_id = \"P1\"", "must be set together"},
+	} {
+		_, err := Load(writeTestConfig(t, validConfig+"\n"+tc.body+"\n"))
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tc.body, tc.want, err)
+		}
+	}
+}
+
+func TestLoadAutoscale(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	a := loaded.General.Autoscale
+	if a.Enabled || a.Interval.Duration != 5*time.Minute || a.Step != 1 || a.MinCoders != 1 ||
+		a.MaxCoders != loaded.General.MaxConcurrentCoders || a.MaxReviewers != loaded.General.MaxConcurrentReviewers ||
+		a.MaxFailureRate != 0.5 || a.MaxCostBurn != 0.8 {
+		t.Fatalf("autoscale defaults = %+v", a)
+	}
+
+	loaded, err = Load(writeTestConfig(t, validConfig+"\n[general.autoscale]\nenabled = true\nmin_coders = 2\nmax_coders = 8\nmax_reviewers = 3\n"))
+	if err != nil {
+		t.Fatalf("Load explicit: %v", err)
+	}
+	if a := loaded.General.Autoscale; !a.Enabled || a.MinCoders != 2 || a.MaxCoders != 8 || a.MinReviewers != 1 || a.MaxReviewers != 3 {
+		t.Fatalf("explicit autoscale = %+v", a)
+	}
+
+	for _, bad := range []string{
+		"min_coders = 9\nmax_coders = 8",
+		"max_failure_rate = 1.5",
+		"step = -1",
+	} {
+		if _, err := Load(writeTestConfig(t, validConfig+"\n[general.autoscale]\n"+bad+"\n")); err == nil || !strings.Contains(err.Error(), "general.autoscale") {
+			t.Fatalf("%q: expected autoscale error, got %v", bad, err)
+		}
+	}
+}
+
+func TestLoadHealthQueueWait(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if q := loaded.Health.QueueWait; q.SLO.Duration != 0 || q.Window.Duration != time.Hour || q.RoleSLO("coder") != 0 {
+		t.Fatalf("queue wait defaults = %+v", q)
+	}
+
+	loaded, err = Load(writeTestConfig(t, validConfig+"\n[health.queue_wait]\nslo = \"10m\"\nwindow = \"30m\"\n\n[health.queue_wait.roles]\nreviewer = \"30m\"\n"))
+	if err != nil {
+		t.Fatalf("Load explicit: %v", err)
+	}
+	q := loaded.Health.QueueWait
+	if q.RoleSLO("coder") != 10*time.Minute || q.RoleSLO("reviewer") != 30*time.Minute || q.Window.Duration != 30*time.Minute {
+		t.Fatalf("explicit queue wait = %+v", q)
+	}
+	clone := loaded.Clone()
+	clone.Health.QueueWait.Roles["reviewer"] = Duration{Duration: time.Minute}
+	if loaded.Health.QueueWait.RoleSLO("reviewer") != 30*time.Minute {
+		t.Fatal("Clone shares health.queue_wait.roles")
+	}
+
+	if _, err := Load(writeTestConfig(t, validConfig+"\n[health.queue_wait.roles]\ncoder = \"-1m\"\n")); err == nil || !strings.Contains(err.Error(), "health.queue_wait.roles.coder") {
+		t.Fatalf("expected negative role SLO error, got %v", err)
+	}
+}
+
+func TestLoadHealthEvents(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig+`
+[health.events]
+severities = { dependency_down = "critical", backup_done = "info" }
+matrix = ["critical"]
+webhooks = ["warn", "critical"]
+`))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	events := loaded.Health.Events
+	if events.Severities["dependency_down"] != "critical" || len(events.Matrix) != 1 || len(events.Webhooks) != 2 {
+		t.Fatalf("unexpected health events config: %+v", events)
+	}
+
+	for _, body := range []string{
+		`severities = { dependency_down = "page" }`,
+		`matrix = ["error"]`,
+	} {
+		_, err := Load(writeTestConfig(t, validConfig+"\n[health.events]\n"+body+"\n"))
+		if err == nil || !strings.Contains(err.Error(), "must be one of info, warn, critical") {
+			t.Errorf("%s: expected severity error, got %v", body, err)
+		}
+	}
+}
+
+func TestLoadProjectRoles(t *testing.T) {
+	workflow := `
+[workflows.dev]
+
+[[workflows.dev.stages]]
+name = "implement"
+role = "coder"
+
+[[workflows.dev.stages]]
+name = "audit"
+role = "security-auditor"
+
+[[workflows.dev.stages]]
+name = "docs"
+role = "coder"
+`
+	roles := `
+[projects.test.roles.security-auditor]
+description = "# Security Auditor"
+tier = "Premium"
+
+[projects.test.roles.doc-writer]
+provider = "cerebras"
+stages = ["docs"]
+`
+	loaded, err := Load(writeTestConfig(t, validConfig+roles+workflow))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	proj := loaded.Projects["test"]
+	if got := proj.Roles["security-auditor"]; got.Tier != "premium" || got.Description != "# Security Auditor" {
+		t.Fatalf("unexpected security-auditor role: %+v", got)
+	}
+	if got := proj.StageRole("docs", "coder"); got != "doc-writer" {
+		t.Fatalf("expected doc-writer to work the docs stage, got %q", got)
+	}
+	if got := proj.StageRole("implement", "coder"); got != "coder" {
+		t.Fatalf("expected the workflow's role for unclaimed stages, got %q", got)
+	}
+	clone := loaded.Clone()
+	clone.Projects["test"].Roles["doc-writer"].Stages[0] = "changed"
+	if loaded.Projects["test"].Roles["doc-writer"].Stages[0] != "docs" {
+		t.Fatal("Clone shares role stages")
+	}
+
+	if _, err := Load(writeTestConfig(t, validConfig+workflow)); err == nil || !strings.Contains(err.Error(), `unknown role "security-auditor"`) {
+		t.Fatalf("expected an unregistered stage role to be rejected, got %v", err)
+	}
+	for name, role := range map[string]string{
+		"bad name":         "[projects.test.roles.Auditor]\ntier = \"fast\"",
+		"built-in name":    "[projects.test.roles.coder]\ntier = \"fast\"",
+		"bad tier":         "[projects.test.roles.auditor]\ntier = \"ultra\"",
+		"unknown provider": "[projects.test.roles.auditor]\nprovider = \"nope\"",
+		"unknown stage":    "[projects.test.roles.auditor]\nstages = [\"deploy\"]",
+		"claimed stage":    "[projects.test.roles.auditor]\nstages = [\"docs\"]\n\n[projects.test.roles.writer]\nstages = [\"docs\"]",
+		"missing prompt":   "[projects.test.roles.auditor]\nprompt = \"missing.tmpl\"",
+	} {
+		bad := validConfig + roles + "\n" + role + "\n" + workflow
+		if _, err := Load(writeTestConfig(t, bad)); err == nil || !strings.Contains(err.Error(), `project "test" roles`) {
+			t.Fatalf("expected %s to be rejected, got %v", name, err)
+		}
+	}
+}
+
+func TestLoadWorkflowStageSLA(t *testing.T) {
+	cfg := validConfig + `
+
+[workflows.dev]
+
+[[workflows.dev.stages]]
+name = "implement"
+role = "coder"
+
+[[workflows.dev.stages]]
+name = "review"
+role = "reviewer"
+max_duration = "4h"
+escalate_on_breach = true
+`
+	loaded, err := Load(writeTestConfig(t, cfg))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	review := loaded.Workflows["dev"].Stages[1]
+	if review.MaxDuration.Duration != 4*time.Hour || !review.EscalateOnBreach {
+		t.Fatalf("unexpected review SLA: %+v", review)
+	}
+	if !loaded.StageSLAEnabled() {
+		t.Fatal("expected stage SLA check to be enabled")
+	}
+	if loaded.Dispatch.StageSLA.Schedule != "*/10 * * * *" {
+		t.Fatalf("stage SLA schedule = %q, want default", loaded.Dispatch.StageSLA.Schedule)
+	}
+
+	noSLA, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if noSLA.StageSLAEnabled() {
+		t.Fatal("expected stage SLA check to be disabled without max_duration")
+	}
+
+	bad := validConfig + `
+
+[workflows.dev]
+
+[[workflows.dev.stages]]
+name = "implement"
+role = "coder"
+max_duration = "-1h"
+`
+	if _, err := Load(writeTestConfig(t, bad)); err == nil {
+		t.Fatal("expected negative max_duration to be rejected")
+	}
+}
+
 func TestLoadWorkflowValidationDuplicateStageName(t *testing.T) {
 	cfg := validConfig + `
 
@@ -1072,6 +1540,95 @@ func TestValidateDispatchConfigNilCLI(t *testing.T) {
 	}
 }
 
+func TestValidateDispatchConfigRejectsNegativeProviderRates(t *testing.T) {
+	cfg := &Config{Providers: map[string]Provider{"claude": {RequestsPerMinute: -1}}}
+	err := ValidateDispatchConfig(cfg)
+	if err == nil || !strings.Contains(err.Error(), "providers.claude") {
+		t.Fatalf("expected a negative requests_per_minute to be rejected, got %v", err)
+	}
+	cfg.Providers["claude"] = Provider{RequestsPerMinute: 50, TokensPerMinute: 40000}
+	if err := ValidateDispatchConfig(cfg); err != nil {
+		t.Fatalf("expected per-minute limits to be valid, got %v", err)
+	}
+}
+
+func TestLoadRoutingRules(t *testing.T) {
+	cfg := validConfig + `
+[[dispatch.routing.rules]]
+labels = ["security", "auth"]
+tier = "premium"
+provider = "claude-max20"
+require_review = true
+
+[[dispatch.routing.rules]]
+labels = ["docs"]
+tier = "fast"
+`
+	loaded, err := Load(writeTestConfig(t, cfg))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	routing := loaded.Dispatch.Routing
+	if rule, ok := routing.Rule([]string{"backend", "Auth"}); !ok || rule.Provider != "claude-max20" || !rule.RequireReview {
+		t.Fatalf("expected the security rule, got %+v (%v)", rule, ok)
+	}
+	if rule, ok := routing.Rule([]string{"docs", "security"}); !ok || rule.Tier != "premium" {
+		t.Fatalf("expected the first matching rule to win, got %+v", rule)
+	}
+	if _, ok := routing.Rule([]string{"backend"}); ok {
+		t.Fatal("expected no rule for unmatched labels")
+	}
+	clone := loaded.Clone()
+	clone.Dispatch.Routing.Rules[0].Labels[0] = "changed"
+	if loaded.Dispatch.Routing.Rules[0].Labels[0] != "security" {
+		t.Fatal("Clone shares routing rule labels")
+	}
+
+	for name, rule := range map[string]string{
+		"no labels":        `tier = "premium"`,
+		"bad tier":         "labels = [\"x\"]\ntier = \"ultra\"",
+		"unknown provider": "labels = [\"x\"]\nprovider = \"nope\"",
+		"no action":        `labels = ["x"]`,
+	} {
+		bad := validConfig + "\n[[dispatch.routing.rules]]\n" + rule + "\n"
+		if _, err := Load(writeTestConfig(t, bad)); err == nil || !strings.Contains(err.Error(), "dispatch.routing.rules[0]") {
+			t.Fatalf("expected %s to be rejected, got %v", name, err)
+		}
+	}
+}
+
+func TestLoadComplexity(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig+"\n[dispatch.complexity]\nenabled = true\n"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	cx := loaded.Dispatch.Complexity
+	if cx.MinSamples != 20 || cx.Window.Duration != 90*24*time.Hour || cx.Retrain.Duration != time.Hour {
+		t.Fatalf("unexpected complexity defaults: %+v", cx)
+	}
+
+	bad := validConfig + "\n[dispatch.complexity]\nenabled = true\nmin_samples = -1\n"
+	if _, err := Load(writeTestConfig(t, bad)); err == nil || !strings.Contains(err.Error(), "dispatch.complexity.min_samples") {
+		t.Fatalf("expected negative min_samples to be rejected, got %v", err)
+	}
+}
+
+func TestLoadRedaction(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig+"\n[dispatch.redaction]\nenabled = true\npatterns = ['internal-[0-9]+']\n"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	rd := loaded.Dispatch.Redaction
+	if rd.EntropyMinLength != 24 || rd.EntropyThreshold != 4.0 || len(rd.Patterns) != 1 {
+		t.Fatalf("unexpected redaction config: %+v", rd)
+	}
+
+	bad := validConfig + "\n[dispatch.redaction]\nenabled = true\npatterns = ['(unclosed']\n"
+	if _, err := Load(writeTestConfig(t, bad)); err == nil || !strings.Contains(err.Error(), "dispatch.redaction.patterns[0]") {
+		t.Fatalf("expected invalid pattern to be rejected, got %v", err)
+	}
+}
+
 // Cadence Configuration Tests
 
 func TestLoadCadenceConfigDefaults(t *testing.T) {
@@ -1140,6 +1697,33 @@ timezone = "America/New_York"
 	}
 }
 
+func TestCadenceSprintStart(t *testing.T) {
+	c := Cadence{SprintStartDay: "Wednesday", SprintStartTime: "10:30", Timezone: "America/New_York"}
+	loc, err := c.LoadLocation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		at   time.Time
+		want time.Time
+	}{
+		{time.Date(2026, 10, 16, 12, 0, 0, 0, loc), time.Date(2026, 10, 14, 10, 30, 0, 0, loc)},
+		{time.Date(2026, 10, 14, 10, 30, 0, 0, loc), time.Date(2026, 10, 14, 10, 30, 0, 0, loc)},
+		{time.Date(2026, 10, 14, 10, 29, 0, 0, loc), time.Date(2026, 10, 7, 10, 30, 0, 0, loc)},
+		// 01:00 UTC Thursday is still Wednesday evening in New York.
+		{time.Date(2026, 10, 15, 1, 0, 0, 0, time.UTC), time.Date(2026, 10, 14, 10, 30, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		got, err := c.SprintStart(tt.at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("SprintStart(%v) = %v, want %v", tt.at, got, tt.want)
+		}
+	}
+}
+
 func TestLoadCadenceConfigInvalidValues(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -1273,15 +1857,42 @@ sprint_planning_day = "InvalidDay"
 	}
 }
 
-func TestLoadSprintPlanningConfigInvalidTime(t *testing.T) {
-	tests := []struct {
-		name string
-		time string
-	}{
-		{"invalid format", "9:00"},
-		{"invalid format short", "9:0"},
-		{"non-numeric hour", "ab:00"},
-		{"non-numeric minute", "09:ab"},
+func TestLoadChiefSchedule(t *testing.T) {
+	cfg, err := Load(writeTestConfig(t, validConfig+`
+[projects.chief-project]
+enabled = true
+beads_dir = "/tmp/chief-test/.beads"
+workspace = "/tmp/chief-test"
+chief_schedule = "0 6 * * 1"
+`))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := cfg.Projects["chief-project"].ChiefSchedule; got != "0 6 * * 1" {
+		t.Errorf("chief_schedule = %q, want %q", got, "0 6 * * 1")
+	}
+
+	_, err = Load(writeTestConfig(t, validConfig+`
+[projects.chief-project]
+enabled = true
+beads_dir = "/tmp/chief-test/.beads"
+workspace = "/tmp/chief-test"
+chief_schedule = "every monday"
+`))
+	if err == nil || !strings.Contains(err.Error(), "chief_schedule") {
+		t.Fatalf("expected chief_schedule error, got %v", err)
+	}
+}
+
+func TestLoadSprintPlanningConfigInvalidTime(t *testing.T) {
+	tests := []struct {
+		name string
+		time string
+	}{
+		{"invalid format", "9:00"},
+		{"invalid format short", "9:0"},
+		{"non-numeric hour", "ab:00"},
+		{"non-numeric minute", "09:ab"},
 		{"invalid hour", "25:00"},
 		{"invalid minute", "09:60"},
 		{"missing colon", "0900"},
@@ -1861,3 +2472,1156 @@ func TestRetryPolicyForNilConfig(t *testing.T) {
 		t.Fatalf("expected default escalate_after 2, got %d", policy.EscalateAfter)
 	}
 }
+
+func TestLoadAppliesDoDPreset(t *testing.T) {
+	cfgText := strings.Replace(validConfig, "priority = 1\n", "priority = 1\n\n[projects.test.dod]\npreset = \"go\"\ncoverage_min = 70\n", 1)
+	cfg, err := Load(writeTestConfig(t, cfgText))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	dod := cfg.Projects["test"].DoD
+	if len(dod.Checks) != 3 || dod.Checks[0] != "go build ./..." {
+		t.Fatalf("expected go preset checks, got %v", dod.Checks)
+	}
+	if dod.CoverageMin != 70 {
+		t.Fatalf("explicit coverage_min should win over preset, got %d", dod.CoverageMin)
+	}
+}
+
+func TestLoadRejectsUnknownDoDPreset(t *testing.T) {
+	cfgText := strings.Replace(validConfig, "priority = 1\n", "priority = 1\n\n[projects.test.dod]\npreset = \"cobol\"\n", 1)
+	if _, err := Load(writeTestConfig(t, cfgText)); err == nil || !strings.Contains(err.Error(), "unknown DoD preset") {
+		t.Fatalf("expected unknown preset error, got %v", err)
+	}
+}
+
+func TestLoadDispatchConfidence(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.Dispatch.Confidence.AutoClose || loaded.Dispatch.Confidence.Threshold != 0.7 {
+		t.Fatalf("confidence defaults = %+v, want auto_close off and threshold 0.7", loaded.Dispatch.Confidence)
+	}
+
+	loaded, err = Load(writeTestConfig(t, validConfig+"\n[dispatch.confidence]\nauto_close = true\nthreshold = 0.0\n"))
+	if err != nil {
+		t.Fatalf("Load explicit: %v", err)
+	}
+	if !loaded.Dispatch.Confidence.AutoClose || loaded.Dispatch.Confidence.Threshold != 0 {
+		t.Fatalf("explicit confidence = %+v, want auto_close on and threshold 0", loaded.Dispatch.Confidence)
+	}
+
+	if _, err := Load(writeTestConfig(t, validConfig+"\n[dispatch.confidence]\nthreshold = 1.5\n")); err == nil || !strings.Contains(err.Error(), "dispatch.confidence.threshold") {
+		t.Fatalf("expected threshold range error, got %v", err)
+	}
+}
+
+func TestLoadDispatchPair(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.Dispatch.Pair.Enabled || loaded.Dispatch.Pair.MaxPriority != 1 || loaded.Dispatch.Pair.MaxTurns != 6 {
+		t.Fatalf("pair defaults = %+v", loaded.Dispatch.Pair)
+	}
+
+	loaded, err = Load(writeTestConfig(t, validConfig+"\n[dispatch.pair]\nenabled = true\nmax_priority = 0\nmax_turns = 4\n"))
+	if err != nil {
+		t.Fatalf("Load explicit: %v", err)
+	}
+	if !loaded.Dispatch.Pair.Enabled || loaded.Dispatch.Pair.MaxPriority != 0 || loaded.Dispatch.Pair.MaxTurns != 4 {
+		t.Fatalf("explicit pair = %+v", loaded.Dispatch.Pair)
+	}
+
+	if _, err := Load(writeTestConfig(t, validConfig+"\n[dispatch.pair]\nmax_turns = 1\n")); err == nil || !strings.Contains(err.Error(), "dispatch.pair.max_turns") {
+		t.Fatalf("expected max_turns error, got %v", err)
+	}
+}
+
+func TestLoadDispatchApprovals(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if a := loaded.Dispatch.Approvals; a.Chat || a.Timeout.Duration != 0 || a.DefaultAction != "deny" {
+		t.Fatalf("approval defaults = %+v, want chat off, no timeout and deny", a)
+	}
+
+	loaded, err = Load(writeTestConfig(t, validConfig+"\n[matrix]\ncommand_senders = [\"@ops:example.org\"]\n[dispatch.approvals]\nchat = true\ntimeout = \"4h\"\ndefault_action = \"approve\"\n"))
+	if err != nil {
+		t.Fatalf("Load explicit: %v", err)
+	}
+	if a := loaded.Dispatch.Approvals; !a.Chat || a.Timeout.Duration != 4*time.Hour || a.DefaultAction != "approve" {
+		t.Fatalf("explicit approvals = %+v", a)
+	}
+
+	// Chat approvals must name who may answer them
+	for _, matrix := range []string{
+		"",
+		"\n[matrix.command_acl]\napprove = [\"@ops:example.org\"]\n",
+		"\n[matrix]\ncommand_senders = [\"@ops:example.org\"]\n[matrix.command_acl]\ndeny = [\"*\"]\n",
+	} {
+		_, err := Load(writeTestConfig(t, validConfig+matrix+"\n[dispatch.approvals]\nchat = true\n"))
+		if err == nil || !strings.Contains(err.Error(), "dispatch.approvals.chat") {
+			t.Fatalf("matrix %q: expected chat approvals ACL error, got %v", matrix, err)
+		}
+	}
+	if _, err := Load(writeTestConfig(t, validConfig+"\n[matrix.command_acl]\napprove = [\"@ops:example.org\"]\ndeny = [\"@ops:example.org\"]\n[dispatch.approvals]\nchat = true\n")); err != nil {
+		t.Fatalf("chat approvals with an approve and deny ACL: %v", err)
+	}
+
+	if _, err := Load(writeTestConfig(t, validConfig+"\n[dispatch.approvals]\ndefault_action = \"ignore\"\n")); err == nil || !strings.Contains(err.Error(), "dispatch.approvals.default_action") {
+		t.Fatalf("expected default_action error, got %v", err)
+	}
+	if _, err := Load(writeTestConfig(t, validConfig+"\n[dispatch.approvals]\ntimeout = \"-1m\"\n")); err == nil || !strings.Contains(err.Error(), "dispatch.approvals.timeout") {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+}
+
+func TestLoadDispatchPreflight(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if p := loaded.Dispatch.Preflight; p.Enabled || p.TTL.Duration != loaded.General.TickInterval.Duration || p.Timeout.Duration != 30*time.Second {
+		t.Fatalf("preflight defaults = %+v, want disabled, tick_interval ttl and 30s timeout", p)
+	}
+
+	loaded, err = Load(writeTestConfig(t, validConfig+"\n[dispatch.preflight]\nenabled = true\ncommand = \"curl -fsS http://127.0.0.1:18789/health\"\nttl = \"2m\"\ntimeout = \"5s\"\n"))
+	if err != nil {
+		t.Fatalf("Load explicit: %v", err)
+	}
+	if p := loaded.Dispatch.Preflight; !p.Enabled || p.Command == "" || p.TTL.Duration != 2*time.Minute || p.Timeout.Duration != 5*time.Second {
+		t.Fatalf("explicit preflight = %+v", p)
+	}
+
+	if _, err := Load(writeTestConfig(t, validConfig+"\n[dispatch.preflight]\nttl = \"-1m\"\n")); err == nil || !strings.Contains(err.Error(), "dispatch.preflight.ttl") {
+		t.Fatalf("expected ttl error, got %v", err)
+	}
+}
+
+func TestLoadDispatchFollowUps(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if f := loaded.Dispatch.FollowUps; f.Enabled || f.Max != 5 || f.Priority != 3 {
+		t.Fatalf("follow-up defaults = %+v, want disabled, max 5 and priority 3", f)
+	}
+
+	loaded, err = Load(writeTestConfig(t, validConfig+"\n[dispatch.follow_ups]\nenabled = true\nmax = 2\npriority = 0\n"))
+	if err != nil {
+		t.Fatalf("Load explicit: %v", err)
+	}
+	if f := loaded.Dispatch.FollowUps; !f.Enabled || f.Max != 2 || f.Priority != 0 {
+		t.Fatalf("explicit follow-ups = %+v", f)
+	}
+
+	if _, err := Load(writeTestConfig(t, validConfig+"\n[dispatch.follow_ups]\npriority = 5\n")); err == nil || !strings.Contains(err.Error(), "dispatch.follow_ups.priority") {
+		t.Fatalf("expected priority error, got %v", err)
+	}
+}
+
+func TestLoadCircuitBreakers(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	breakers := loaded.Dispatch.CircuitBreakers
+	if breakers.Provider.Threshold != 5 || breakers.Provider.Window.Duration != 30*time.Minute || breakers.Provider.Cooldown.Duration != 10*time.Minute {
+		t.Fatalf("provider breaker defaults = %+v", breakers.Provider)
+	}
+	if gw, ok := breakers.Categories["gateway_closed"]; !ok || gw.Threshold != 3 || gw.Cooldown.Duration != 5*time.Minute {
+		t.Fatalf("category breaker defaults = %+v", breakers.Categories)
+	}
+
+	loaded, err = Load(writeTestConfig(t, validConfig+`
+[dispatch.circuit_breakers.provider]
+cooldown = "2m"
+
+[dispatch.circuit_breakers.providers.cerebras]
+threshold = 0
+
+[dispatch.circuit_breakers.providers.claude-max20]
+window = "1h"
+
+[dispatch.circuit_breakers.categories.rate_limited]
+threshold = 10
+`))
+	if err != nil {
+		t.Fatalf("Load explicit: %v", err)
+	}
+	breakers = loaded.Dispatch.CircuitBreakers
+	if b := breakers.ForProvider("cerebras"); b.Threshold != 0 || b.Cooldown.Duration != 2*time.Minute {
+		t.Fatalf("cerebras breaker = %+v", b)
+	}
+	if b := breakers.ForProvider("claude-max20"); b.Threshold != 5 || b.Window.Duration != time.Hour || b.Cooldown.Duration != 2*time.Minute {
+		t.Fatalf("claude-max20 breaker = %+v", b)
+	}
+	if len(breakers.Categories) != 1 || breakers.Categories["rate_limited"].Window.Duration != 30*time.Minute {
+		t.Fatalf("explicit categories = %+v", breakers.Categories)
+	}
+
+	if _, err := Load(writeTestConfig(t, validConfig+"\n[dispatch.circuit_breakers.providers.nope]\nthreshold = 1\n")); err == nil || !strings.Contains(err.Error(), "unknown provider") {
+		t.Fatalf("expected unknown provider error, got %v", err)
+	}
+	if _, err := Load(writeTestConfig(t, validConfig+"\n[dispatch.circuit_breakers.categories.auth]\nthreshold = -1\n")); err == nil || !strings.Contains(err.Error(), "categories.auth.threshold") {
+		t.Fatalf("expected negative threshold error, got %v", err)
+	}
+}
+
+func TestLoadShutdownDrain(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.General.ShutdownDrain.Duration != 0 {
+		t.Fatalf("shutdown_drain default = %v, want 0", loaded.General.ShutdownDrain)
+	}
+
+	cfg := strings.Replace(validConfig, "[general]\n", "[general]\nshutdown_drain = \"10m\"\n", 1)
+	if loaded, err = Load(writeTestConfig(t, cfg)); err != nil {
+		t.Fatalf("Load explicit: %v", err)
+	}
+	if loaded.General.ShutdownDrain.Duration != 10*time.Minute {
+		t.Fatalf("shutdown_drain = %v, want 10m", loaded.General.ShutdownDrain)
+	}
+
+	cfg = strings.Replace(validConfig, "[general]\n", "[general]\nshutdown_drain = \"-1m\"\n", 1)
+	if _, err := Load(writeTestConfig(t, cfg)); err == nil || !strings.Contains(err.Error(), "shutdown_drain") {
+		t.Fatalf("expected shutdown_drain error, got %v", err)
+	}
+}
+
+func TestLoadAPIEndpoints(t *testing.T) {
+	cfg := strings.Replace(validConfig, "[api]\n", "[api]\nendpoints = { chum = \"http://chum-host:8900\" }\n", 1)
+	loaded, err := Load(writeTestConfig(t, cfg))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := loaded.API.Endpoints["chum"]; got != "http://chum-host:8900" {
+		t.Fatalf("api.endpoints.chum = %q", got)
+	}
+
+	cfg = strings.Replace(validConfig, "[api]\n", "[api]\nendpoints = { chum = \"chum-host:8900\" }\n", 1)
+	if _, err := Load(writeTestConfig(t, cfg)); err == nil || !strings.Contains(err.Error(), "api.endpoints.chum") {
+		t.Fatalf("expected api.endpoints error, got %v", err)
+	}
+}
+
+func TestLoadBranchJanitor(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	bj := loaded.Dispatch.BranchJanitor
+	if bj.Enabled || bj.Schedule != "0 3 * * *" || bj.GracePeriod.Duration != 7*24*time.Hour {
+		t.Fatalf("unexpected branch_janitor defaults: %+v", bj)
+	}
+
+	cfg := validConfig + "\n[dispatch.branch_janitor]\nenabled = true\ngrace_period = \"48h\"\nprotected = [\"release/*\"]\n"
+	if loaded, err = Load(writeTestConfig(t, cfg)); err != nil {
+		t.Fatalf("Load explicit: %v", err)
+	}
+	bj = loaded.Dispatch.BranchJanitor
+	if !bj.Enabled || bj.GracePeriod.Duration != 48*time.Hour || len(bj.Protected) != 1 {
+		t.Fatalf("unexpected branch_janitor: %+v", bj)
+	}
+
+	cfg = validConfig + "\n[dispatch.branch_janitor]\nprotected = [\"release/[\"]\n"
+	if _, err := Load(writeTestConfig(t, cfg)); err == nil || !strings.Contains(err.Error(), "branch_janitor.protected") {
+		t.Fatalf("expected protected pattern error, got %v", err)
+	}
+}
+
+func TestLoadWALCheckpointInterval(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.General.WALCheckpointInterval.Duration != 5*time.Minute {
+		t.Fatalf("wal_checkpoint_interval default = %v, want 5m", loaded.General.WALCheckpointInterval)
+	}
+
+	cfg := strings.Replace(validConfig, "[general]\n", "[general]\nwal_checkpoint_interval = \"0s\"\n", 1)
+	if loaded, err = Load(writeTestConfig(t, cfg)); err != nil {
+		t.Fatalf("Load explicit: %v", err)
+	}
+	if loaded.General.WALCheckpointInterval.Duration != 0 {
+		t.Fatalf("explicit 0 should disable checkpoints, got %v", loaded.General.WALCheckpointInterval)
+	}
+
+	cfg = strings.Replace(validConfig, "[general]\n", "[general]\nwal_checkpoint_interval = \"-1m\"\n", 1)
+	if _, err := Load(writeTestConfig(t, cfg)); err == nil || !strings.Contains(err.Error(), "wal_checkpoint_interval") {
+		t.Fatalf("expected wal_checkpoint_interval error, got %v", err)
+	}
+}
+
+func TestLoadBurnIn(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	bi := loaded.Reporter.BurnIn
+	if bi.Enabled || bi.Schedule != "0 7 * * *" || bi.Window.Duration != 7*24*time.Hour || bi.ReportsDir != "/tmp/reports" {
+		t.Fatalf("unexpected burnin defaults: %+v", bi)
+	}
+	if got := loaded.BurnInSLOFor("test"); got != (BurnInSLO{UnknownRateLimit: 0.02, InterventionRateLimit: 0.10, CriticalEventLimit: 2}) {
+		t.Fatalf("unexpected default gates: %+v", got)
+	}
+
+	cfg := strings.Replace(validConfig, "priority = 1\n", "priority = 1\n\n[projects.test.burnin]\ncritical_event_limit = 1\n", 1) +
+		"\n[reporter.burnin]\nenabled = true\nreports_dir = \"/var/cortex/reports\"\n\n[reporter.burnin.slo]\nunknown_rate_limit = 0.05\n"
+	if loaded, err = Load(writeTestConfig(t, cfg)); err != nil {
+		t.Fatalf("Load explicit: %v", err)
+	}
+	if !loaded.Reporter.BurnIn.Enabled || loaded.Reporter.BurnIn.ReportsDir != "/var/cortex/reports" {
+		t.Fatalf("unexpected burnin: %+v", loaded.Reporter.BurnIn)
+	}
+	if got := loaded.BurnInSLOFor("test"); got != (BurnInSLO{UnknownRateLimit: 0.05, InterventionRateLimit: 0.10, CriticalEventLimit: 1}) {
+		t.Fatalf("project override not merged: %+v", got)
+	}
+
+	cfg = strings.Replace(validConfig, "priority = 1\n", "priority = 1\n\n[projects.test.burnin]\nintervention_rate_limit = 1.5\n", 1)
+	if _, err := Load(writeTestConfig(t, cfg)); err == nil || !strings.Contains(err.Error(), "projects.test.burnin.intervention_rate_limit") {
+		t.Fatalf("expected intervention_rate_limit error, got %v", err)
+	}
+}
+
+func TestLoadEnrichHeadroom(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.General.EnrichHeadroom != 2 || loaded.General.EnrichLimit() != 6 {
+		t.Fatalf("enrich_headroom default = %v (limit %d), want 2 (limit 6)", loaded.General.EnrichHeadroom, loaded.General.EnrichLimit())
+	}
+
+	cfg := strings.Replace(validConfig, "[general]\n", "[general]\nenrich_headroom = 1.5\n", 1)
+	if loaded, err = Load(writeTestConfig(t, cfg)); err != nil {
+		t.Fatalf("Load explicit: %v", err)
+	}
+	if loaded.General.EnrichLimit() != 5 {
+		t.Fatalf("EnrichLimit() = %d, want 5", loaded.General.EnrichLimit())
+	}
+
+	cfg = strings.Replace(validConfig, "[general]\n", "[general]\nenrich_headroom = 0.5\n", 1)
+	if _, err := Load(writeTestConfig(t, cfg)); err == nil || !strings.Contains(err.Error(), "enrich_headroom") {
+		t.Fatalf("expected enrich_headroom error, got %v", err)
+	}
+}
+
+func TestLoadHealthRollout(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	ro := loaded.Health.Rollout
+	if ro.Enabled || ro.Schedule != "0 * * * *" || ro.Window.Duration != 24*time.Hour || ro.MaxCriticalEvents != 0 {
+		t.Fatalf("unexpected rollout defaults: %+v", ro)
+	}
+
+	rollout := "\n[health.rollout]\nenabled = true\nproject = \"test\"\ncritical_beads = [\"cx-1\", \"cx-2\"]\nmax_critical_events = 2\nrequire_burnin = true\n"
+	loaded, err = Load(writeTestConfig(t, validConfig+rollout))
+	if err != nil {
+		t.Fatalf("Load explicit: %v", err)
+	}
+	ro = loaded.Health.Rollout
+	if !ro.Enabled || ro.Project != "test" || len(ro.CriticalBeads) != 2 || ro.MaxCriticalEvents != 2 || !ro.RequireBurnIn {
+		t.Fatalf("unexpected rollout config: %+v", ro)
+	}
+
+	for _, tc := range []struct{ snippet, want string }{
+		{"critical_beads = [\"cx-1\"]\n", "health.rollout.project is required"},
+		{"project = \"missing\"\n", "unknown project"},
+		{"project = \"test\"\ncritical_beads = [\" \"]\n", "empty IDs"},
+		{"max_critical_events = -1\n", "max_critical_events"},
+	} {
+		_, err := Load(writeTestConfig(t, validConfig+"\n[health.rollout]\n"+tc.snippet))
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%q: expected %q error, got %v", tc.snippet, tc.want, err)
+		}
+	}
+}
+
+func TestLoadTemporal(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	tc := loaded.Temporal
+	if tc.Address() != "127.0.0.1:7233" || tc.Namespace != "default" || tc.TaskQueue != "cortex-task-queue" || tc.TLS.Enabled() {
+		t.Fatalf("unexpected temporal defaults: %+v", tc)
+	}
+	if tc.Schedules.StrategicGroom != "0 5 * * *" || tc.Schedules.FailureClusterReport != "0 6 * * 1" {
+		t.Fatalf("unexpected schedule defaults: %+v", tc.Schedules)
+	}
+
+	dir := t.TempDir()
+	cert, key := filepath.Join(dir, "client.pem"), filepath.Join(dir, "client.key")
+	for _, f := range []string{cert, key} {
+		if err := os.WriteFile(f, []byte("pem"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	temporal := fmt.Sprintf("\n[temporal]\nhost = \"temporal.internal\"\nport = 7300\nnamespace = \"cortex\"\ntask_queue = \"cortex-prod\"\n\n[temporal.tls]\ncert_file = %q\nkey_file = %q\n\n[temporal.schedules]\nstrategic_groom = \"0 4 * * *\"\n", cert, key)
+	loaded, err = Load(writeTestConfig(t, validConfig+temporal))
+	if err != nil {
+		t.Fatalf("Load explicit: %v", err)
+	}
+	tc = loaded.Temporal
+	if tc.Address() != "temporal.internal:7300" || tc.Namespace != "cortex" || tc.TaskQueue != "cortex-prod" || !tc.TLS.Enabled() {
+		t.Fatalf("unexpected temporal config: %+v", tc)
+	}
+	if tc.Schedules.StrategicGroom != "0 4 * * *" || tc.Schedules.FailureClusterReport != "0 6 * * 1" {
+		t.Fatalf("unexpected schedules: %+v", tc.Schedules)
+	}
+
+	for _, tc := range []struct{ snippet, want string }{
+		{"port = 70000\n", "temporal.port"},
+		{"[temporal.schedules]\nstrategic_groom = \"daily\"\n", "temporal.schedules.strategic_groom"},
+		{"[temporal.tls]\ncert_file = \"" + cert + "\"\n", "set together"},
+		{"[temporal.tls]\nca_file = \"" + filepath.Join(dir, "missing.pem") + "\"\n", "temporal.tls.ca_file"},
+	} {
+		_, err := Load(writeTestConfig(t, validConfig+"\n[temporal]\n"+tc.snippet))
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%q: expected %q error, got %v", tc.snippet, tc.want, err)
+		}
+	}
+}
+
+func TestProjectScheduleDispatchAllowed(t *testing.T) {
+	sched := ProjectSchedule{
+		Timezone:     "Europe/Helsinki",
+		WorkingHours: "08:00-18:00",
+		WorkingDays:  []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday"},
+		Blackouts:    []BlackoutWindow{{Start: "2026-03-04 12:00", End: "2026-03-04 16:00", Reason: "release freeze"}},
+	}
+	helsinki, err := time.LoadLocation("Europe/Helsinki")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(day, hour int) time.Time { return time.Date(2026, 3, day, hour, 30, 0, 0, helsinki) }
+
+	cases := []struct {
+		name   string
+		now    time.Time
+		urgent bool
+		want   bool
+		reason string
+	}{
+		{"working hours", at(2, 9), false, true, ""},
+		{"before hours", at(2, 7), false, false, "outside working hours 08:00-18:00 Europe/Helsinki"},
+		{"weekend", at(7, 10), false, false, "Saturday is not a working day"},
+		{"blackout", at(4, 13), false, false, "blackout until 2026-03-04 16:00: release freeze"},
+		{"after blackout", at(4, 16), false, true, ""},
+		{"urgent without override", at(2, 23), true, false, "outside working hours 08:00-18:00 Europe/Helsinki"},
+	}
+	for _, tc := range cases {
+		ok, reason := sched.DispatchAllowed(tc.now, tc.urgent)
+		if ok != tc.want || reason != tc.reason {
+			t.Fatalf("%s: got (%v, %q), want (%v, %q)", tc.name, ok, reason, tc.want, tc.reason)
+		}
+	}
+
+	sched.UrgentOverride = true
+	if ok, _ := sched.DispatchAllowed(at(4, 13), true); !ok {
+		t.Fatal("expected urgent override to bypass the blackout")
+	}
+
+	night := ProjectSchedule{WorkingHours: "22:00-07:00"}
+	for hour, want := range map[int]bool{23: true, 3: true, 7: false, 12: false} {
+		if ok, _ := night.DispatchAllowed(time.Date(2026, 3, 2, hour, 0, 0, 0, time.UTC), false); ok != want {
+			t.Fatalf("overnight window at %02d:00: got %v, want %v", hour, ok, want)
+		}
+	}
+	if ok, _ := (ProjectSchedule{}).DispatchAllowed(at(7, 3), false); !ok {
+		t.Fatal("expected an empty schedule to allow any time")
+	}
+}
+
+func TestLoadProjectSchedule(t *testing.T) {
+	cfg := validConfig + `
+[projects.test.schedule]
+timezone = "America/New_York"
+working_hours = "09:00-17:00"
+working_days = ["Monday", "Friday"]
+urgent_override = true
+
+[[projects.test.schedule.blackouts]]
+start = "2026-12-24 00:00"
+end = "2026-12-27 00:00"
+reason = "holidays"
+`
+	loaded, err := Load(writeTestConfig(t, cfg))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	sched := loaded.Projects["test"].Schedule
+	if sched.WorkingHours != "09:00-17:00" || len(sched.WorkingDays) != 2 || !sched.UrgentOverride || len(sched.Blackouts) != 1 || sched.Blackouts[0].Reason != "holidays" {
+		t.Fatalf("unexpected schedule: %+v", sched)
+	}
+
+	for name, section := range map[string]string{
+		"bad timezone": `timezone = "Mars/Olympus"`,
+		"bad hours":    `working_hours = "9-5"`,
+		"empty window": `working_hours = "09:00-09:00"`,
+		"bad day":      `working_days = ["Funday"]`,
+		"bad blackout": "[[projects.test.schedule.blackouts]]\nstart = \"2026-12-27 00:00\"\nend = \"2026-12-24 00:00\"",
+	} {
+		bad := validConfig + "\n[projects.test.schedule]\n" + section + "\n"
+		if _, err := Load(writeTestConfig(t, bad)); err == nil {
+			t.Fatalf("expected %s to be rejected", name)
+		}
+	}
+}
+
+func TestMaintenanceAt(t *testing.T) {
+	g := General{MaintenanceWindows: []MaintenanceWindow{
+		{Name: "os patching", Cron: "0 2 * * 0", Duration: Duration{Duration: 2 * time.Hour}},
+		{Days: []string{"Wednesday"}, Hours: "23:00-01:00", Timezone: "Europe/Helsinki"},
+	}}
+	helsinki, err := time.LoadLocation("Europe/Helsinki")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name  string
+		now   time.Time
+		label string
+		until time.Time
+	}{
+		{"cron window open", time.Date(2026, 3, 8, 3, 59, 0, 0, time.UTC), "os patching", time.Date(2026, 3, 8, 4, 0, 0, 0, time.UTC)},
+		{"cron window closed", time.Date(2026, 3, 8, 4, 0, 0, 0, time.UTC), "", time.Time{}},
+		{"cron window not yet open", time.Date(2026, 3, 8, 1, 59, 0, 0, time.UTC), "", time.Time{}},
+		{"hours window before midnight", time.Date(2026, 3, 4, 23, 30, 0, 0, helsinki), "Wednesday 23:00-01:00", time.Date(2026, 3, 5, 1, 0, 0, 0, helsinki)},
+		{"hours window after midnight", time.Date(2026, 3, 5, 0, 30, 0, 0, helsinki), "Wednesday 23:00-01:00", time.Date(2026, 3, 5, 1, 0, 0, 0, helsinki)},
+		{"hours window on another day", time.Date(2026, 3, 5, 23, 30, 0, 0, helsinki), "", time.Time{}},
+	}
+	for _, tc := range cases {
+		w, until, ok := g.MaintenanceAt(tc.now)
+		if ok != (tc.label != "") || ok && w.Label() != tc.label || !until.Equal(tc.until) {
+			t.Fatalf("%s: got (%q, %s, %v), want (%q, %s)", tc.name, w.Label(), until, ok, tc.label, tc.until)
+		}
+	}
+}
+
+func TestLoadMaintenanceWindows(t *testing.T) {
+	cfg := strings.Replace(validConfig, "[general]\n", `[general]
+maintenance_windows = [
+  { name = "backups", cron = "30 3 * * *", duration = "45m" },
+  { days = ["Saturday"], hours = "22:00-02:00", timezone = "Australia/Brisbane" },
+]
+`, 1)
+	loaded, err := Load(writeTestConfig(t, cfg))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	windows := loaded.General.MaintenanceWindows
+	if len(windows) != 2 || windows[0].Duration.Duration != 45*time.Minute || windows[1].Days[0] != "Saturday" {
+		t.Fatalf("unexpected maintenance windows: %+v", windows)
+	}
+	clone := loaded.Clone()
+	clone.General.MaintenanceWindows[1].Days[0] = "Sunday"
+	if loaded.General.MaintenanceWindows[1].Days[0] != "Saturday" {
+		t.Fatal("Clone shares maintenance window days")
+	}
+
+	for name, window := range map[string]string{
+		"no schedule":    `{ name = "x" }`,
+		"cron and hours": `{ cron = "0 2 * * *", duration = "1h", hours = "02:00-03:00" }`,
+		"no duration":    `{ cron = "0 2 * * *" }`,
+		"bad cron":       `{ cron = "whenever", duration = "1h" }`,
+		"days on cron":   `{ cron = "0 2 * * *", duration = "1h", days = ["Monday"] }`,
+		"bad hours":      `{ hours = "2-3" }`,
+		"bad day":        `{ hours = "02:00-03:00", days = ["Funday"] }`,
+		"bad timezone":   `{ hours = "02:00-03:00", timezone = "Mars/Olympus" }`,
+	} {
+		bad := strings.Replace(validConfig, "[general]\n", "[general]\nmaintenance_windows = ["+window+"]\n", 1)
+		if _, err := Load(writeTestConfig(t, bad)); err == nil || !strings.Contains(err.Error(), "general.maintenance_windows[0]") {
+			t.Fatalf("expected %s to be rejected, got %v", name, err)
+		}
+	}
+}
+
+func TestLoadCadenceHolidays(t *testing.T) {
+	cfg := validConfig + `
+[cadence]
+holidays = ["2026-12-24", "2026-12-25"]
+holiday_calendar = "https://example.com/holidays.ics"
+`
+	loaded, err := Load(writeTestConfig(t, cfg))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(loaded.Cadence.Holidays) != 2 || loaded.Cadence.HolidayCalendar != "https://example.com/holidays.ics" {
+		t.Fatalf("unexpected cadence: %+v", loaded.Cadence)
+	}
+
+	for name, section := range map[string]string{
+		"bad holiday":  `holidays = ["24.12.2026"]`,
+		"bad calendar": `holiday_calendar = "file:///etc/holidays.ics"`,
+	} {
+		if _, err := Load(writeTestConfig(t, validConfig+"\n[cadence]\n"+section+"\n")); err == nil {
+			t.Fatalf("expected %s to be rejected", name)
+		}
+	}
+}
+
+func TestReporterDailyDigestSchedule(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if schedule, err := loaded.Reporter.DailyDigestSchedule(); err != nil || schedule != "0 9 * * *" {
+		t.Fatalf("schedule = %q, %v; want \"0 9 * * *\"", schedule, err)
+	}
+	if schedule, err := (Reporter{DailyDigestTime: "17:30"}).DailyDigestSchedule(); err != nil || schedule != "30 17 * * *" {
+		t.Fatalf("schedule = %q, %v; want \"30 17 * * *\"", schedule, err)
+	}
+	if schedule, err := (Reporter{}).DailyDigestSchedule(); err != nil || schedule != "" {
+		t.Fatalf("expected unset time to disable the digest, got %q, %v", schedule, err)
+	}
+
+	bad := strings.Replace(validConfig, `daily_digest_time = "09:00"`, `daily_digest_time = "9am"`, 1)
+	if _, err := Load(writeTestConfig(t, bad)); err == nil {
+		t.Fatal("expected invalid daily_digest_time to be rejected")
+	}
+}
+
+func TestLoadMatrixCommandACL(t *testing.T) {
+	cfg := validConfig + `
+[matrix]
+command_senders = ["@alice:example.org", "@bob:example.org"]
+
+[matrix.command_acl]
+pause = ["@alice:example.org"]
+status = ["*"]
+`
+	loaded, err := Load(writeTestConfig(t, cfg))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(loaded.Matrix.CommandSenders) != 2 || len(loaded.Matrix.CommandACL["pause"]) != 1 || loaded.Matrix.CommandACL["status"][0] != "*" {
+		t.Fatalf("unexpected matrix config: %+v", loaded.Matrix)
+	}
+	clone := loaded.Clone()
+	clone.Matrix.CommandACL["pause"][0] = "@eve:example.org"
+	if loaded.Matrix.CommandACL["pause"][0] != "@alice:example.org" {
+		t.Fatal("clone shares command_acl with the original")
+	}
+
+	bad := validConfig + "\n[matrix.command_acl]\npause = [\"alice\"]\n"
+	if _, err := Load(writeTestConfig(t, bad)); err == nil {
+		t.Fatal("expected non-Matrix user id in command_acl to be rejected")
+	}
+}
+
+func TestLoadMatrixE2EProxy(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig+"\n[matrix]\ne2e_proxy = \"http://localhost:8009\"\n"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.Matrix.E2EProxy != "http://localhost:8009" {
+		t.Fatalf("e2e_proxy = %q", loaded.Matrix.E2EProxy)
+	}
+	if _, err := Load(writeTestConfig(t, validConfig+"\n[matrix]\ne2e_proxy = \"localhost:8009\"\n")); err == nil {
+		t.Fatal("expected e2e_proxy without scheme to be rejected")
+	}
+}
+
+func TestLoadReporterEmail(t *testing.T) {
+	emailConfig := strings.Replace(validConfig, `channel = "matrix"`, `channel = "email"`, 1) + `
+[reporter.email]
+smtp_host = "smtp.example.org"
+from = "Cortex <cortex@example.org>"
+default_recipients = ["ops@example.org"]
+batch_interval = "15m"
+`
+	loaded, err := Load(writeTestConfig(t, emailConfig))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	e := loaded.Reporter.Email
+	if e.SMTPPort != 587 || e.SubjectPrefix != "[cortex]" || e.BatchInterval.Duration != 15*time.Minute {
+		t.Fatalf("unexpected email defaults: %+v", e)
+	}
+	if got := loaded.ResolveRoom("test"); got != "email:test" {
+		t.Fatalf("ResolveRoom(test) = %q, want email:test", got)
+	}
+	if got := loaded.EmailRecipients("email:test"); len(got) != 1 || got[0] != "ops@example.org" {
+		t.Fatalf("default recipients = %v", got)
+	}
+	if missing := loaded.MissingProjectRoomRouting(); len(missing) != 0 {
+		t.Fatalf("expected default recipients to route every project, missing %v", missing)
+	}
+
+	proj := loaded.Projects["test"]
+	proj.EmailRecipients = []string{"team@example.org"}
+	loaded.Projects["test"] = proj
+	if got := loaded.EmailRecipients("email:test"); len(got) != 1 || got[0] != "team@example.org" {
+		t.Fatalf("project recipients = %v", got)
+	}
+	loaded.Reporter.Email.DefaultRecipients = nil
+	if got := loaded.ResolveRoom("other"); got != "" {
+		t.Fatalf("expected project without recipients to have no route, got %q", got)
+	}
+
+	for name, cfg := range map[string]string{
+		"unknown channel": strings.Replace(validConfig, `channel = "matrix"`, `channel = "slack"`, 1),
+		"missing host":    strings.Replace(emailConfig, `smtp_host = "smtp.example.org"`, "", 1),
+		"bad from":        strings.Replace(emailConfig, `from = "Cortex <cortex@example.org>"`, `from = "cortex"`, 1),
+		"bad recipient":   strings.Replace(emailConfig, `["ops@example.org"]`, `["ops"]`, 1),
+	} {
+		if _, err := Load(writeTestConfig(t, cfg)); err == nil {
+			t.Fatalf("expected %s to be rejected", name)
+		}
+	}
+}
+
+func TestLoadReporterWebhooks(t *testing.T) {
+	hooks := validConfig + `
+[[reporter.webhooks]]
+url = "https://hooks.example.org/cortex"
+secret = "webhook-secret"
+template = '{"text": {{json .Message}}}'
+projects = ["test"]
+`
+	loaded, err := Load(writeTestConfig(t, hooks))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(loaded.Reporter.Webhooks) != 1 || loaded.Reporter.Webhooks[0].Timeout.Duration != 10*time.Second {
+		t.Fatalf("unexpected webhooks: %+v", loaded.Reporter.Webhooks)
+	}
+	if got := Redact("key webhook-secret"); got != "key "+RedactedPlaceholder {
+		t.Fatalf("expected webhook secret to be redacted, got %q", got)
+	}
+
+	for name, cfg := range map[string]string{
+		"bad url":         strings.Replace(hooks, "https://hooks.example.org/cortex", "hooks.example.org", 1),
+		"bad template":    strings.Replace(hooks, "{{json .Message}}", "{{json .Message", 1),
+		"unknown project": strings.Replace(hooks, `projects = ["test"]`, `projects = ["nope"]`, 1),
+	} {
+		if _, err := Load(writeTestConfig(t, cfg)); err == nil {
+			t.Fatalf("expected %s to be rejected", name)
+		}
+	}
+}
+
+func TestLoadForgeWebhooks(t *testing.T) {
+	forge := strings.Replace(validConfig, "priority = 1\n", "priority = 1\nforge_repo = \"org/repo\"\n", 1) + `
+[api.forge_webhooks]
+github_secret = "github-hook-secret"
+gitlab_token = "gitlab-hook-token"
+`
+	loaded, err := Load(writeTestConfig(t, forge))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if project, ok := loaded.ProjectForRepo("Org/Repo"); !ok || project != "test" {
+		t.Fatalf("ProjectForRepo = %q, %v", project, ok)
+	}
+	if _, ok := loaded.ProjectForRepo("org/other"); ok {
+		t.Fatal("expected an unmapped repo to resolve to no project")
+	}
+	if got := Redact("github-hook-secret gitlab-hook-token"); got != RedactedPlaceholder+" "+RedactedPlaceholder {
+		t.Fatalf("expected forge secrets to be redacted, got %q", got)
+	}
+
+	dup := forge + `
+[projects.other]
+enabled = true
+beads_dir = "/tmp/other/.beads"
+workspace = "/tmp/other"
+forge_repo = "ORG/repo"
+`
+	if _, err := Load(writeTestConfig(t, dup)); err == nil || !strings.Contains(err.Error(), "share forge_repo") {
+		t.Fatalf("expected a shared forge_repo to be rejected, got %v", err)
+	}
+}
+
+func TestLoadDispatchArtifacts(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	artifacts := loaded.Dispatch.Artifacts
+	if artifacts.Dir != "/tmp/artifacts" || artifacts.RetentionDays != 14 || artifacts.MaxFileMB != 50 {
+		t.Fatalf("artifact defaults = %+v", artifacts)
+	}
+
+	withPatterns := strings.Replace(validConfig, "[projects.test]\n", "[projects.test]\nartifacts = [\"coverage.out\", \"reports/*.xml\"]\n", 1)
+	loaded, err = Load(writeTestConfig(t, withPatterns))
+	if err != nil {
+		t.Fatalf("Load with patterns: %v", err)
+	}
+	if got := loaded.Projects["test"].Artifacts; len(got) != 2 || got[1] != "reports/*.xml" {
+		t.Fatalf("project artifacts = %v", got)
+	}
+
+	for _, pattern := range []string{"../secrets", "/etc/passwd", "reports/[x"} {
+		bad := strings.Replace(validConfig, "[projects.test]\n", "[projects.test]\nartifacts = [\""+pattern+"\"]\n", 1)
+		if _, err := Load(writeTestConfig(t, bad)); err == nil || !strings.Contains(err.Error(), "artifacts") {
+			t.Fatalf("expected %q to be rejected, got %v", pattern, err)
+		}
+	}
+}
+
+func TestLoadDispatchWorktrees(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	wt := loaded.Dispatch.Worktrees
+	if wt.Enabled || wt.Dir != "/tmp/worktrees" || wt.PoolSize != loaded.Dispatch.Git.MaxConcurrentPerProject {
+		t.Fatalf("worktree defaults = %+v", wt)
+	}
+
+	bad := validConfig + "\n[dispatch.worktrees]\nenabled = true\npool_size = -1\n"
+	if _, err := Load(writeTestConfig(t, bad)); err == nil || !strings.Contains(err.Error(), "pool_size") {
+		t.Fatalf("expected negative pool_size to be rejected, got %v", err)
+	}
+}
+
+func TestLoadHealthHost(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if host := loaded.Health.Host; host.MinFreeDiskMB != 2048 || host.MinFreeMemoryMB != 512 || host.MaxLoadPerCPU != 0 {
+		t.Fatalf("host defaults = %+v", host)
+	}
+
+	off := validConfig + "\n[health.host]\nmin_free_disk_mb = 0\nmin_free_memory_mb = 0\npaths = [\"~/logs\"]\n"
+	loaded, err = Load(writeTestConfig(t, off))
+	if err != nil {
+		t.Fatalf("Load with checks off: %v", err)
+	}
+	if host := loaded.Health.Host; host.MinFreeDiskMB != 0 || host.MinFreeMemoryMB != 0 || strings.HasPrefix(host.Paths[0], "~") {
+		t.Fatalf("host = %+v, want checks off and expanded paths", host)
+	}
+
+	bad := validConfig + "\n[health.host]\nmax_load_per_cpu = -1\n"
+	if _, err := Load(writeTestConfig(t, bad)); err == nil || !strings.Contains(err.Error(), "health.host") {
+		t.Fatalf("expected negative limit to be rejected, got %v", err)
+	}
+}
+
+func TestLoadHealthStuckCommands(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if stuck := loaded.Health.StuckCommands; stuck.Breaches != 3 || stuck.Restart {
+		t.Fatalf("stuck_commands defaults = %+v", stuck)
+	}
+
+	off := validConfig + "\n[health.stuck_commands]\nbreaches = 0\n"
+	loaded, err = Load(writeTestConfig(t, off))
+	if err != nil {
+		t.Fatalf("Load with breaches off: %v", err)
+	}
+	if loaded.Health.StuckCommands.Breaches != 0 {
+		t.Fatalf("breaches = %d, want 0", loaded.Health.StuckCommands.Breaches)
+	}
+
+	bad := validConfig + "\n[health.stuck_commands]\nbreaches = -1\n"
+	if _, err := Load(writeTestConfig(t, bad)); err == nil || !strings.Contains(err.Error(), "stuck_commands") {
+		t.Fatalf("expected negative breaches to be rejected, got %v", err)
+	}
+}
+
+func TestLoadDispatchJournal(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if jr := loaded.Dispatch.Journal; jr.Dir != "/tmp/journal" || jr.RetentionDays != 30 {
+		t.Fatalf("journal defaults = %+v", jr)
+	}
+
+	keepAll := validConfig + "\n[dispatch.journal]\nretention_days = 0\n"
+	if loaded, err = Load(writeTestConfig(t, keepAll)); err != nil || loaded.Dispatch.Journal.RetentionDays != 0 {
+		t.Fatalf("expected retention_days = 0 to keep all journals, got %v", err)
+	}
+}
+
+func TestLoadDispatchEpicRollup(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if er := loaded.Dispatch.EpicRollup; er.Enabled || er.Schedule != "0 * * * *" || er.Threshold != 1 {
+		t.Fatalf("epic rollup defaults = %+v", er)
+	}
+
+	partial := validConfig + "\n[dispatch.epic_rollup]\nenabled = true\nthreshold = 0.8\n"
+	if loaded, err = Load(writeTestConfig(t, partial)); err != nil || loaded.Dispatch.EpicRollup.Threshold != 0.8 {
+		t.Fatalf("expected threshold 0.8, got %v", err)
+	}
+
+	for _, bad := range []string{"threshold = 0", "threshold = 1.5", "schedule = \"hourly\""} {
+		cfg := validConfig + "\n[dispatch.epic_rollup]\nenabled = true\n" + bad + "\n"
+		if _, err := Load(writeTestConfig(t, cfg)); err == nil || !strings.Contains(err.Error(), "dispatch.epic_rollup") {
+			t.Errorf("%s: expected epic_rollup error, got %v", bad, err)
+		}
+	}
+}
+
+func TestLoadDispatchTriage(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if tr := loaded.Dispatch.Triage; tr.Enabled || tr.Schedule != "0 4 * * *" || tr.Similarity != 0.8 || tr.StaleAfter.Duration != 30*24*time.Hour {
+		t.Fatalf("triage defaults = %+v", tr)
+	}
+
+	noStale := validConfig + "\n[dispatch.triage]\nenabled = true\nstale_after = \"0s\"\n"
+	if loaded, err = Load(writeTestConfig(t, noStale)); err != nil || loaded.Dispatch.Triage.StaleAfter.Duration != 0 {
+		t.Fatalf("expected stale_after = 0 to disable the stale check, got %v", err)
+	}
+
+	for _, bad := range []string{"similarity = 0", "similarity = 1.2", "stale_after = \"-1h\""} {
+		cfg := validConfig + "\n[dispatch.triage]\nenabled = true\n" + bad + "\n"
+		if _, err := Load(writeTestConfig(t, cfg)); err == nil || !strings.Contains(err.Error(), "dispatch.triage") {
+			t.Errorf("%s: expected triage error, got %v", bad, err)
+		}
+	}
+}
+
+func TestLoadDispatchFileOverlap(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if fo := loaded.Dispatch.FileOverlap; fo.Enabled || fo.Schedule != "*/30 * * * *" || fo.Mode != "warn" || fo.LookbackDays != 30 {
+		t.Fatalf("file overlap defaults = %+v", fo)
+	}
+
+	serialize := validConfig + "\n[dispatch.file_overlap]\nenabled = true\nmode = \"serialize\"\n"
+	if loaded, err = Load(writeTestConfig(t, serialize)); err != nil || loaded.Dispatch.FileOverlap.Mode != "serialize" {
+		t.Fatalf("expected serialize mode, got %v", err)
+	}
+
+	for _, bad := range []string{"mode = \"block\"", "lookback_days = -1", "schedule = \"often\""} {
+		cfg := validConfig + "\n[dispatch.file_overlap]\nenabled = true\n" + bad + "\n"
+		if _, err := Load(writeTestConfig(t, cfg)); err == nil || !strings.Contains(err.Error(), "dispatch.file_overlap") {
+			t.Errorf("%s: expected file_overlap error, got %v", bad, err)
+		}
+	}
+}
+
+func TestLoadDispatchClaims(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cl := loaded.Dispatch.Claims; cl.Enabled || cl.Backend != "sqlite" || cl.TTL.Duration != 10*time.Minute || cl.EtcdPrefix != "/cortex/claims/" {
+		t.Fatalf("claims defaults = %+v", cl)
+	}
+
+	etcd := validConfig + "\n[dispatch.claims]\nenabled = true\nbackend = \"etcd\"\ninstance_id = \"host-a\"\netcd_endpoints = [\"http://etcd:2379\"]\n"
+	if loaded, err = Load(writeTestConfig(t, etcd)); err != nil || loaded.Dispatch.Claims.InstanceID != "host-a" {
+		t.Fatalf("expected etcd claims for host-a, got %v", err)
+	}
+
+	for _, bad := range []string{"backend = \"postgres\"", "backend = \"etcd\"", "ttl = \"5s\""} {
+		cfg := validConfig + "\n[dispatch.claims]\nenabled = true\n" + bad + "\n"
+		if _, err := Load(writeTestConfig(t, cfg)); err == nil || !strings.Contains(err.Error(), "dispatch.claims") {
+			t.Errorf("%s: expected claims error, got %v", bad, err)
+		}
+	}
+}
+
+func TestLoadHA(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if ha := loaded.HA; ha.Enabled || ha.LeaseTTL.Duration != 15*time.Second {
+		t.Fatalf("ha defaults = %+v", ha)
+	}
+
+	// HA validates the claims backend even with bead claims off.
+	noEndpoints := validConfig + "\n[ha]\nenabled = true\n\n[dispatch.claims]\nbackend = \"etcd\"\n"
+	if _, err := Load(writeTestConfig(t, noEndpoints)); err == nil || !strings.Contains(err.Error(), "dispatch.claims.etcd_endpoints") {
+		t.Fatalf("expected etcd_endpoints error, got %v", err)
+	}
+
+	short := validConfig + "\n[ha]\nenabled = true\nlease_ttl = \"1s\"\n"
+	if _, err := Load(writeTestConfig(t, short)); err == nil || !strings.Contains(err.Error(), "ha.lease_ttl") {
+		t.Fatalf("expected lease_ttl error, got %v", err)
+	}
+}
+
+func TestLoadSharding(t *testing.T) {
+	unassigned := validConfig + "\n[sharding]\nenabled = true\nshard = \"east\"\n"
+	if _, err := Load(writeTestConfig(t, unassigned)); err == nil || !strings.Contains(err.Error(), "projects.test.shard") {
+		t.Fatalf("expected projects.test.shard error, got %v", err)
+	}
+
+	noShard := validConfig + "\n[sharding]\nenabled = true\n"
+	if _, err := Load(writeTestConfig(t, noShard)); err == nil || !strings.Contains(err.Error(), "sharding.shard is required") {
+		t.Fatalf("expected sharding.shard error, got %v", err)
+	}
+
+	assigned := strings.Replace(validConfig, "[projects.test]\n", "[projects.test]\nshard = \"west\"\n", 1) +
+		"\n[sharding]\nenabled = true\nshard = \"east\"\n\n[sharding.peers]\nwest = \"http://cortex-west:8900\"\n"
+	cfg, err := Load(writeTestConfig(t, assigned))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.OwnsProject("test") {
+		t.Fatal("east owns a west project")
+	}
+	if sharded := cfg.ShardProjects(); len(sharded.Projects) != 0 || len(cfg.Projects) != 1 {
+		t.Fatalf("shard projects = %v, all = %v", sharded.Projects, cfg.Projects)
+	}
+	if cfg.Temporal.TaskQueue != "cortex-task-queue-east" {
+		t.Fatalf("task queue = %q, want the shard's own queue", cfg.Temporal.TaskQueue)
+	}
+	if id := cfg.ShardWorkflowID("epic-rollup"); id != "epic-rollup-east" {
+		t.Fatalf("cron workflow ID = %q", id)
+	}
+	if id := (&Config{}).ShardWorkflowID("epic-rollup"); id != "epic-rollup" {
+		t.Fatalf("unsharded cron workflow ID = %q", id)
+	}
+}
+
+func TestLoadBeadsGuard(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.General.BeadsGuardInterval.Duration != 0 || loaded.General.BeadsGuardMaxRowBytes != 60000 {
+		t.Fatalf("beads guard defaults = %v, %d", loaded.General.BeadsGuardInterval, loaded.General.BeadsGuardMaxRowBytes)
+	}
+
+	cfg := strings.Replace(validConfig, "[general]\n", "[general]\nbeads_guard_interval = \"10m\"\nbeads_guard_max_row_bytes = 4096\n", 1)
+	if loaded, err = Load(writeTestConfig(t, cfg)); err != nil {
+		t.Fatalf("Load explicit: %v", err)
+	}
+	if loaded.General.BeadsGuardInterval.Duration != 10*time.Minute || loaded.General.BeadsGuardMaxRowBytes != 4096 {
+		t.Fatalf("beads guard = %v, %d", loaded.General.BeadsGuardInterval, loaded.General.BeadsGuardMaxRowBytes)
+	}
+
+	cfg = strings.Replace(validConfig, "[general]\n", "[general]\nbeads_guard_interval = \"-1m\"\n", 1)
+	if _, err := Load(writeTestConfig(t, cfg)); err == nil || !strings.Contains(err.Error(), "beads_guard_interval") {
+		t.Fatalf("expected beads_guard_interval error, got %v", err)
+	}
+}
+
+func TestLoadResponseCache(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	rc := loaded.Dispatch.ResponseCache
+	if rc.Enabled || rc.TTL.Duration != 6*time.Hour || strings.Join(rc.Roles, ",") != "planner,groom,chief" {
+		t.Fatalf("response cache defaults = %+v", rc)
+	}
+
+	cfg := validConfig + "\n[dispatch.response_cache]\nenabled = true\nttl = \"1h\"\nroles = [\"groom\"]\n"
+	if loaded, err = Load(writeTestConfig(t, cfg)); err != nil {
+		t.Fatalf("Load explicit: %v", err)
+	}
+	rc = loaded.Dispatch.ResponseCache
+	if !rc.Enabled || rc.TTL.Duration != time.Hour || len(rc.Roles) != 1 || rc.Roles[0] != "groom" {
+		t.Fatalf("response cache = %+v", rc)
+	}
+
+	cfg = validConfig + "\n[dispatch.response_cache]\nroles = [\"coder\"]\n"
+	if _, err := Load(writeTestConfig(t, cfg)); err == nil || !strings.Contains(err.Error(), "dispatch.response_cache.roles") {
+		t.Fatalf("expected roles error, got %v", err)
+	}
+}
+
+func TestLoadCostCaps(t *testing.T) {
+	cfg := validConfig + "\n[dispatch.cost_control]\nper_dispatch_cost_cap_usd = 5.0\n[dispatch.cost_control.role_cost_caps_usd]\ncoder = 3.0\nreviewer = 0.5\n"
+	loaded, err := Load(writeTestConfig(t, cfg))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	cc := loaded.Dispatch.CostControl
+	if cc.PerDispatchCostCapUSD != 5 || cc.RoleCostCapsUSD["coder"] != 3 || cc.RoleCostCapsUSD["reviewer"] != 0.5 {
+		t.Fatalf("cost caps = %+v", cc)
+	}
+	clone := loaded.Clone()
+	clone.Dispatch.CostControl.RoleCostCapsUSD["coder"] = 9
+	if loaded.Dispatch.CostControl.RoleCostCapsUSD["coder"] != 3 {
+		t.Fatal("Clone shares role_cost_caps_usd")
+	}
+
+	for _, bad := range []string{
+		"[dispatch.cost_control]\nper_dispatch_cost_cap_usd = -1.0\n",
+		"[dispatch.cost_control.role_cost_caps_usd]\nplanner = 1.0\n",
+		"[dispatch.cost_control.role_cost_caps_usd]\ncoder = -1.0\n",
+	} {
+		if _, err := Load(writeTestConfig(t, validConfig+"\n"+bad)); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestLoadProviderUsageFormat(t *testing.T) {
+	cfg := strings.Replace(validConfig, "[providers.cerebras]\n", "[providers.cerebras]\nusage_format = \"openclaw\"\n", 1)
+	loaded, err := Load(writeTestConfig(t, cfg))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := loaded.Providers["cerebras"].UsageFormat; got != "openclaw" {
+		t.Fatalf("usage_format = %q", got)
+	}
+
+	cfg = strings.Replace(validConfig, "[providers.cerebras]\n", "[providers.cerebras]\nusage_format = \"gemini\"\n", 1)
+	if _, err := Load(writeTestConfig(t, cfg)); err == nil || !strings.Contains(err.Error(), "providers.cerebras.usage_format") {
+		t.Fatalf("expected usage_format error, got %v", err)
+	}
+}
+
+func TestLoadCostReport(t *testing.T) {
+	loaded, err := Load(writeTestConfig(t, validConfig))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	cr := loaded.Reporter.Cost
+	if cr.Enabled || cr.Schedule != "0 6 1 * *" || cr.ReportsDir != "/tmp/reports" {
+		t.Fatalf("unexpected cost report defaults: %+v", cr)
+	}
+
+	cfg := validConfig + "\n[reporter.cost]\nenabled = true\nschedule = \"0 3 1 * *\"\nreports_dir = \"/var/cortex/costs\"\n"
+	if loaded, err = Load(writeTestConfig(t, cfg)); err != nil {
+		t.Fatalf("Load explicit: %v", err)
+	}
+	if cr := loaded.Reporter.Cost; !cr.Enabled || cr.Schedule != "0 3 1 * *" || cr.ReportsDir != "/var/cortex/costs" {
+		t.Fatalf("unexpected cost report config: %+v", cr)
+	}
+}
+
+func TestMatchWorkflow(t *testing.T) {
+	stages := []StageConfig{{Name: "implement", Role: "coder"}}
+	workflows := map[string]WorkflowConfig{
+		"bugfix": {MatchTypes: []string{"bug"}, Stages: stages},
+		"dev":    {MatchLabels: []string{"backend"}, MatchTypes: []string{"task"}, Stages: stages},
+		"empty":  {MatchTypes: []string{"chore"}},
+	}
+	for _, tt := range []struct {
+		labels   []string
+		beadType string
+		want     string
+	}{
+		{[]string{"backend"}, "bug", "dev"}, // labels win over types
+		{nil, "bug", "bugfix"},
+		{nil, "task", "dev"},
+		{nil, "chore", ""}, // a workflow without stages never matches
+		{[]string{"frontend"}, "", ""},
+	} {
+		if got := MatchWorkflow(workflows, tt.labels, tt.beadType); got != tt.want {
+			t.Fatalf("MatchWorkflow(%v, %q) = %q, want %q", tt.labels, tt.beadType, got, tt.want)
+		}
+	}
+}
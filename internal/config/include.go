@@ -0,0 +1,115 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// keyDefiner reports whether a TOML key path was explicitly set by the user.
+type keyDefiner interface {
+	IsDefined(key ...string) bool
+}
+
+// mergedMetaData answers IsDefined across the main config and its fragments.
+type mergedMetaData []*toml.MetaData
+
+func (m mergedMetaData) IsDefined(key ...string) bool {
+	for _, md := range m {
+		if md != nil && md.IsDefined(key...) {
+			return true
+		}
+	}
+	return false
+}
+
+// projectFragment is the only shape accepted from include_dir fragments.
+type projectFragment struct {
+	Projects map[string]Project `toml:"projects"`
+}
+
+// IncludeDirPath resolves general.include_dir relative to the main config file.
+func IncludeDirPath(configPath, includeDir string) string {
+	dir := ExpandHome(strings.TrimSpace(includeDir))
+	if dir == "" {
+		return ""
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(filepath.Dir(configPath), dir)
+	}
+	return filepath.Clean(dir)
+}
+
+// loadIncludes merges [projects.*] tables from every *.toml file in
+// general.include_dir into cfg, in lexical filename order. A project defined
+// in more than one place is a conflict and fails the load, so a reload never
+// silently picks a winner.
+func loadIncludes(configPath string, cfg *Config) ([]*toml.MetaData, error) {
+	dir := IncludeDirPath(configPath, cfg.General.IncludeDir)
+	if dir == "" {
+		return nil, nil
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("include_dir %q: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("include_dir %q is not a directory", dir)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.toml"))
+	if err != nil {
+		return nil, fmt.Errorf("listing include_dir %q: %w", dir, err)
+	}
+	sort.Strings(files)
+
+	owners := make(map[string]string, len(cfg.Projects))
+	for name := range cfg.Projects {
+		owners[name] = configPath
+	}
+	if cfg.Projects == nil {
+		cfg.Projects = make(map[string]Project)
+	}
+
+	metas := make([]*toml.MetaData, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading include %s: %w", file, err)
+		}
+
+		var frag projectFragment
+		md, err := toml.Decode(string(data), &frag)
+		if err != nil {
+			return nil, fmt.Errorf("parsing include %s: %w", file, err)
+		}
+		for _, key := range md.Keys() {
+			if len(key) > 0 && key[0] == "secrets" {
+				return nil, fmt.Errorf("include %s: [secrets] must be set in the main config, not an include fragment", file)
+			}
+			if len(key) > 0 && key[0] != "projects" {
+				return nil, fmt.Errorf("include %s: only [projects.*] tables are allowed, found %q", file, key.String())
+			}
+		}
+		// Fragments resolve ${secret:NAME} through the main config's provider.
+		if err := interpolate(string(data), &frag, &cfg.Secrets); err != nil {
+			return nil, fmt.Errorf("interpolating include %s: %w", file, err)
+		}
+
+		for name, project := range frag.Projects {
+			if owner, ok := owners[name]; ok {
+				return nil, fmt.Errorf("project %q defined in both %s and %s", name, owner, file)
+			}
+			owners[name] = file
+			cfg.Projects[name] = project
+		}
+		cfg.IncludedFiles = append(cfg.IncludedFiles, file)
+		metas = append(metas, &md)
+	}
+	return metas, nil
+}
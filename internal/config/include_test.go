@@ -0,0 +1,156 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeInclude(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func configWithIncludeDir(includeDir string) string {
+	return strings.Replace(validConfig, "[general]\n", "[general]\ninclude_dir = \""+includeDir+"\"\n", 1)
+}
+
+func TestLoadMergesIncludeDirProjects(t *testing.T) {
+	path := writeTestConfig(t, configWithIncludeDir("conf.d"))
+	confDir := filepath.Join(filepath.Dir(path), "conf.d")
+	writeInclude(t, confDir, "10-alpha.toml", `
+[projects.alpha]
+enabled = true
+beads_dir = "/tmp/alpha/.beads"
+workspace = "/tmp/alpha"
+priority = 2
+`)
+	writeInclude(t, confDir, "20-beta.toml", `
+[projects.beta]
+enabled = false
+beads_dir = "/tmp/beta/.beads"
+workspace = "/tmp/beta"
+merge_method = "rebase"
+auto_revert_on_failure = false
+`)
+	writeInclude(t, confDir, "notes.txt", "ignored")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cfg.Projects) != 3 {
+		t.Fatalf("expected 3 projects, got %d", len(cfg.Projects))
+	}
+	if len(cfg.IncludedFiles) != 2 {
+		t.Fatalf("expected 2 included files, got %v", cfg.IncludedFiles)
+	}
+
+	alpha := cfg.Projects["alpha"]
+	if alpha.MergeMethod != "squash" || !alpha.AutoRevertOnFailure || alpha.BaseBranch != "main" {
+		t.Fatalf("expected defaults applied to included project, got %+v", alpha)
+	}
+	beta := cfg.Projects["beta"]
+	if beta.MergeMethod != "rebase" || beta.AutoRevertOnFailure {
+		t.Fatalf("expected explicit fragment values preserved, got merge=%q auto_revert=%v", beta.MergeMethod, beta.AutoRevertOnFailure)
+	}
+}
+
+func TestLoadRejectsIncludeConflicts(t *testing.T) {
+	path := writeTestConfig(t, configWithIncludeDir("conf.d"))
+	confDir := filepath.Join(filepath.Dir(path), "conf.d")
+	writeInclude(t, confDir, "dup.toml", `
+[projects.test]
+enabled = true
+beads_dir = "/tmp/other/.beads"
+workspace = "/tmp/other"
+`)
+
+	_, err := Load(path)
+	if err == nil || !strings.Contains(err.Error(), `project "test" defined in both`) {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+}
+
+func TestLoadRejectsNonProjectIncludeTables(t *testing.T) {
+	path := writeTestConfig(t, configWithIncludeDir("conf.d"))
+	writeInclude(t, filepath.Join(filepath.Dir(path), "conf.d"), "bad.toml", "[general]\nmax_per_tick = 9\n")
+
+	_, err := Load(path)
+	if err == nil || !strings.Contains(err.Error(), "only [projects.*] tables are allowed") {
+		t.Fatalf("expected non-project table error, got %v", err)
+	}
+}
+
+func TestLoadRejectsSecretsInIncludeFragment(t *testing.T) {
+	path := writeTestConfig(t, configWithIncludeDir("conf.d"))
+	writeInclude(t, filepath.Join(filepath.Dir(path), "conf.d"), "secrets.toml", "[secrets]\nprovider = \"file\"\ndir = \"/tmp\"\n")
+
+	_, err := Load(path)
+	if err == nil || !strings.Contains(err.Error(), "[secrets] must be set in the main config") {
+		t.Fatalf("expected fragment [secrets] error, got %v", err)
+	}
+}
+
+func TestLoadResolvesIncludeSecretsWithMainProvider(t *testing.T) {
+	secretDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(secretDir, "alpha_ws"), []byte("/tmp/alpha-secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	path := writeTestConfig(t, configWithIncludeDir("conf.d")+`
+[secrets]
+provider = "file"
+dir = "`+secretDir+`"
+`)
+	writeInclude(t, filepath.Join(filepath.Dir(path), "conf.d"), "alpha.toml", `
+[projects.alpha]
+enabled = true
+beads_dir = "/tmp/alpha/.beads"
+workspace = "${secret:alpha_ws}"
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := cfg.Projects["alpha"].Workspace; got != "/tmp/alpha-secret" {
+		t.Fatalf("alpha workspace = %q, want value from main [secrets] provider", got)
+	}
+}
+
+func TestLoadRejectsMissingIncludeDir(t *testing.T) {
+	path := writeTestConfig(t, configWithIncludeDir("missing.d"))
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "include_dir") {
+		t.Fatalf("expected missing include_dir error, got %v", err)
+	}
+}
+
+func TestReloadPicksUpNewIncludeFragments(t *testing.T) {
+	path := writeTestConfig(t, configWithIncludeDir("conf.d"))
+	confDir := filepath.Join(filepath.Dir(path), "conf.d")
+	if err := os.MkdirAll(confDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	mgr, err := LoadManager(path)
+	if err != nil {
+		t.Fatalf("LoadManager failed: %v", err)
+	}
+	if _, ok := mgr.Get().Projects["gamma"]; ok {
+		t.Fatal("gamma should not exist before fragment is added")
+	}
+
+	writeInclude(t, confDir, "gamma.toml", "[projects.gamma]\nenabled = true\nbeads_dir = \"/tmp/g/.beads\"\nworkspace = \"/tmp/g\"\n")
+	if err := mgr.Reload(path); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if _, ok := mgr.Get().Projects["gamma"]; !ok {
+		t.Fatal("expected gamma after reload")
+	}
+}
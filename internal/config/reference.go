@@ -0,0 +1,171 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// referenceExampleKey names the placeholder entry emitted for keyed tables
+// such as [projects.<name>] so their fields are documented too.
+const referenceExampleKey = "example"
+
+// DefaultConfigTOML renders a fully commented reference config: every option
+// with its doc and valid-values struct tags and the value applyDefaults fills
+// in. Keyed tables get a single "example" entry.
+func DefaultConfigTOML() string {
+	cfg := Config{
+		General:   General{StateDB: "~/.local/share/cortex/cortex.db"},
+		Projects:  map[string]Project{referenceExampleKey: {Enabled: true, BeadsDir: "~/projects/example/.beads", Workspace: "~/projects/example", Priority: 1}},
+		Providers: map[string]Provider{referenceExampleKey: {Tier: "balanced", Authed: true, Model: "example-model", CLI: referenceExampleKey}},
+		Tiers:     Tiers{Balanced: []string{referenceExampleKey}},
+		Workflows: map[string]WorkflowConfig{referenceExampleKey: {MatchTypes: []string{"task"}, Stages: []StageConfig{{Name: "implement", Role: "coder"}}}},
+		RateLimits: RateLimits{
+			Budget: map[string]int{referenceExampleKey: 100},
+		},
+		API: API{Bind: "127.0.0.1:8900"},
+		Experiments: map[string]Experiment{referenceExampleKey: {Role: "coder", Variants: []ExperimentVariant{
+			{Name: "control"},
+			{Name: "terse", Prompt: "~/cortex/prompts/execute-terse.tmpl", Agent: "codex"},
+		}}},
+		Dispatch: Dispatch{
+			CLI: map[string]CLIConfig{referenceExampleKey: {Cmd: "claude", PromptMode: "arg", ModelFlag: "--model"}},
+		},
+	}
+	applyDefaults(&cfg, mergedMetaData{})
+	cfg.General.RetryTiers = map[string]RetryPolicy{"premium": cfg.General.RetryPolicy}
+
+	var b strings.Builder
+	b.WriteString("# Cortex reference configuration.\n")
+	b.WriteString("# Generated by `cortex -print-default-config`; values shown are the defaults.\n")
+	b.WriteString("# Tables keyed by name (projects, providers, ...) show one \"example\" entry.\n")
+	writeReferenceTable(&b, nil, reflect.ValueOf(cfg))
+	return b.String()
+}
+
+type referenceField struct {
+	key   string
+	doc   string
+	valid string
+	value reflect.Value
+}
+
+func referenceFields(v reflect.Value) []referenceField {
+	t := v.Type()
+	var out []referenceField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key := strings.Split(f.Tag.Get("toml"), ",")[0]
+		if key == "" || key == "-" || !f.IsExported() {
+			continue
+		}
+		out = append(out, referenceField{key: key, doc: f.Tag.Get("doc"), valid: f.Tag.Get("valid"), value: v.Field(i)})
+	}
+	return out
+}
+
+// isReferenceTable reports whether a value renders as its own [table].
+func isReferenceTable(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Struct:
+		return v.Type() != reflect.TypeOf(Duration{})
+	case reflect.Map:
+		return v.Type().Elem().Kind() == reflect.Struct
+	case reflect.Slice:
+		return v.Type().Elem().Kind() == reflect.Struct
+	}
+	return false
+}
+
+func writeReferenceTable(b *strings.Builder, path []string, v reflect.Value) {
+	fields := referenceFields(v)
+
+	// Scalars first so they belong to the current table header.
+	for _, f := range fields {
+		if isReferenceTable(f.value) {
+			continue
+		}
+		writeReferenceComment(b, f.doc, f.valid)
+		fmt.Fprintf(b, "%s = %s\n", f.key, formatReferenceValue(f.value))
+	}
+
+	for _, f := range fields {
+		if !isReferenceTable(f.value) {
+			continue
+		}
+		child := append(append([]string(nil), path...), f.key)
+		switch f.value.Kind() {
+		case reflect.Struct:
+			b.WriteString("\n")
+			writeReferenceComment(b, f.doc, f.valid)
+			fmt.Fprintf(b, "[%s]\n", strings.Join(child, "."))
+			writeReferenceTable(b, child, f.value)
+		case reflect.Map:
+			keys := f.value.MapKeys()
+			sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+			for _, k := range keys {
+				entry := append(append([]string(nil), child...), k.String())
+				b.WriteString("\n")
+				writeReferenceComment(b, f.doc, f.valid)
+				fmt.Fprintf(b, "[%s]\n", strings.Join(entry, "."))
+				writeReferenceTable(b, entry, f.value.MapIndex(k))
+			}
+		case reflect.Slice:
+			for i := 0; i < f.value.Len(); i++ {
+				b.WriteString("\n")
+				writeReferenceComment(b, f.doc, f.valid)
+				fmt.Fprintf(b, "[[%s]]\n", strings.Join(child, "."))
+				writeReferenceTable(b, child, f.value.Index(i))
+			}
+		}
+	}
+}
+
+func writeReferenceComment(b *strings.Builder, doc, valid string) {
+	if doc != "" {
+		fmt.Fprintf(b, "# %s\n", doc)
+	}
+	if valid != "" {
+		fmt.Fprintf(b, "# Valid values: %s\n", valid)
+	}
+}
+
+func formatReferenceValue(v reflect.Value) string {
+	if d, ok := v.Interface().(Duration); ok {
+		return strconv.Quote(d.Duration.String())
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return strconv.Quote(v.String())
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Float32, reflect.Float64:
+		s := strconv.FormatFloat(v.Float(), 'f', -1, 64)
+		if !strings.ContainsAny(s, ".eE") {
+			s += ".0"
+		}
+		return s
+	case reflect.Slice:
+		parts := make([]string, v.Len())
+		for i := range parts {
+			parts[i] = formatReferenceValue(v.Index(i))
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case reflect.Map:
+		if v.Len() == 0 {
+			return "{}"
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		parts := make([]string, len(keys))
+		for i, k := range keys {
+			parts[i] = fmt.Sprintf("%s = %s", strconv.Quote(k.String()), formatReferenceValue(v.MapIndex(k)))
+		}
+		return "{ " + strings.Join(parts, ", ") + " }"
+	}
+	return strconv.Quote(fmt.Sprint(v.Interface()))
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+)
+
+func TestDefaultConfigTOMLRoundTrips(t *testing.T) {
+	out := DefaultConfigTOML()
+
+	var cfg Config
+	md, err := toml.Decode(out, &cfg)
+	if err != nil {
+		t.Fatalf("reference config does not parse: %v\n%s", err, out)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		t.Fatalf("reference config has unknown keys: %v", undecoded)
+	}
+	if cfg.General.TickInterval.Duration.String() != "1m0s" || cfg.Dispatch.Warmup.Schedule != "*/15 * * * *" {
+		t.Fatalf("defaults not rendered: tick=%v warmup=%q", cfg.General.TickInterval, cfg.Dispatch.Warmup.Schedule)
+	}
+	for _, want := range []string{
+		"# Valid values: debug, info, warn, error",
+		"[projects.example.dod]",
+		"[[workflows.example.stages]]",
+		"[dispatch.cost_control]",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("reference config missing %q", want)
+		}
+	}
+}
+
+func TestConfigFieldsHaveDocTags(t *testing.T) {
+	seen := map[reflect.Type]bool{}
+	var walk func(reflect.Type, string)
+	walk = func(typ reflect.Type, path string) {
+		for typ.Kind() == reflect.Map || typ.Kind() == reflect.Slice {
+			typ = typ.Elem()
+		}
+		if typ.Kind() != reflect.Struct || typ == reflect.TypeOf(Duration{}) || seen[typ] {
+			return
+		}
+		seen[typ] = true
+		for i := 0; i < typ.NumField(); i++ {
+			f := typ.Field(i)
+			key := f.Tag.Get("toml")
+			if key == "" || key == "-" {
+				continue
+			}
+			if f.Tag.Get("doc") == "" {
+				t.Errorf("%s.%s has no doc tag", path, key)
+			}
+			walk(f.Type, path+"."+key)
+		}
+	}
+	walk(reflect.TypeOf(Config{}), "config")
+}
@@ -0,0 +1,458 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RedactedPlaceholder replaces secret values in log output.
+const RedactedPlaceholder = "[REDACTED]"
+
+// Secrets configures where ${secret:NAME} references in cortex.toml are resolved from.
+type Secrets struct {
+	Provider      string   `toml:"provider" doc:"Secret backend." valid:"env, file, vault"`
+	Dir           string   `toml:"dir" doc:"file provider: directory holding one file per secret."`
+	EnvPrefix     string   `toml:"env_prefix" doc:"env provider: prefix prepended to secret names (e.g. CORTEX_SECRET_)."`
+	VaultAddr     string   `toml:"vault_addr" doc:"vault provider: server address (defaults to $VAULT_ADDR)."`
+	VaultPath     string   `toml:"vault_path" doc:"vault provider: KV path under /v1/ (e.g. secret/data/cortex)."`
+	VaultTokenEnv string   `toml:"vault_token_env" doc:"vault provider: env var holding the token."`
+	VaultTimeout  Duration `toml:"vault_timeout" doc:"vault provider: request timeout."`
+}
+
+// SecretProvider resolves a named secret to its plaintext value.
+type SecretProvider interface {
+	Resolve(name string) (string, error)
+}
+
+// interpolationPattern matches ${VAR}, ${VAR:-default}, and ${secret:NAME}.
+var interpolationPattern = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+var (
+	secretRegistryMu sync.RWMutex
+	secretRegistry   = map[string]struct{}{}
+)
+
+// RegisterSecret marks value as sensitive so Redact scrubs it from log output.
+// Very short values are ignored to avoid redacting common substrings.
+func RegisterSecret(value string) {
+	value = strings.TrimSpace(value)
+	if len(value) < 6 {
+		return
+	}
+	secretRegistryMu.Lock()
+	secretRegistry[value] = struct{}{}
+	secretRegistryMu.Unlock()
+}
+
+// Redact replaces every registered secret value in s with RedactedPlaceholder.
+func Redact(s string) string {
+	if s == "" {
+		return s
+	}
+	secretRegistryMu.RLock()
+	defer secretRegistryMu.RUnlock()
+	if len(secretRegistry) == 0 {
+		return s
+	}
+
+	// Replace longest values first so overlapping secrets are fully scrubbed.
+	values := make([]string, 0, len(secretRegistry))
+	for value := range secretRegistry {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	for _, value := range values {
+		s = strings.ReplaceAll(s, value, RedactedPlaceholder)
+	}
+	return s
+}
+
+// Redacted returns a deep copy of cfg with known sensitive fields masked,
+// suitable for dumping or logging the effective configuration.
+func (cfg *Config) Redacted() *Config {
+	cloned := cfg.Clone()
+	if cloned == nil {
+		return nil
+	}
+	for i := range cloned.API.Security.AllowedTokens {
+		cloned.API.Security.AllowedTokens[i] = RedactedPlaceholder
+	}
+	return cloned
+}
+
+// NewSecretProvider constructs the provider selected by [secrets].provider.
+func NewSecretProvider(s Secrets) (SecretProvider, error) {
+	switch strings.ToLower(strings.TrimSpace(s.Provider)) {
+	case "", "env":
+		return envSecretProvider{prefix: s.EnvPrefix}, nil
+	case "file":
+		dir := ExpandHome(strings.TrimSpace(s.Dir))
+		if dir == "" {
+			return nil, fmt.Errorf("secrets.dir is required for the file provider")
+		}
+		return fileSecretProvider{dir: dir}, nil
+	case "vault":
+		addr := strings.TrimSpace(s.VaultAddr)
+		if addr == "" {
+			addr = strings.TrimSpace(os.Getenv("VAULT_ADDR"))
+		}
+		if addr == "" {
+			return nil, fmt.Errorf("secrets.vault_addr (or VAULT_ADDR) is required for the vault provider")
+		}
+		if strings.TrimSpace(s.VaultPath) == "" {
+			return nil, fmt.Errorf("secrets.vault_path is required for the vault provider")
+		}
+		tokenEnv := strings.TrimSpace(s.VaultTokenEnv)
+		if tokenEnv == "" {
+			tokenEnv = "VAULT_TOKEN"
+		}
+		token := strings.TrimSpace(os.Getenv(tokenEnv))
+		if token == "" {
+			return nil, fmt.Errorf("vault token env %s is empty", tokenEnv)
+		}
+		timeout := s.VaultTimeout.Duration
+		if timeout <= 0 {
+			timeout = 10 * time.Second
+		}
+		return &vaultSecretProvider{
+			addr:   strings.TrimRight(addr, "/"),
+			path:   strings.Trim(strings.TrimSpace(s.VaultPath), "/"),
+			token:  token,
+			client: &http.Client{Timeout: timeout},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown secrets provider %q (valid: env, file, vault)", s.Provider)
+	}
+}
+
+type envSecretProvider struct {
+	prefix string
+}
+
+func (p envSecretProvider) Resolve(name string) (string, error) {
+	key := p.prefix + name
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return "", fmt.Errorf("secret %q: environment variable %s is not set", name, key)
+	}
+	return value, nil
+}
+
+type fileSecretProvider struct {
+	dir string
+}
+
+func (p fileSecretProvider) Resolve(name string) (string, error) {
+	if name != filepath.Base(name) || name == "." || name == ".." {
+		return "", fmt.Errorf("secret %q: invalid secret name", name)
+	}
+	data, err := os.ReadFile(filepath.Join(p.dir, name))
+	if err != nil {
+		return "", fmt.Errorf("secret %q: %w", name, err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+type vaultSecretProvider struct {
+	addr   string
+	path   string
+	token  string
+	client *http.Client
+
+	once   sync.Once
+	values map[string]string
+	err    error
+}
+
+func (p *vaultSecretProvider) Resolve(name string) (string, error) {
+	p.once.Do(p.fetch)
+	if p.err != nil {
+		return "", fmt.Errorf("secret %q: %w", name, p.err)
+	}
+	value, ok := p.values[name]
+	if !ok {
+		return "", fmt.Errorf("secret %q: key not found at vault path %s", name, p.path)
+	}
+	return value, nil
+}
+
+// fetch reads the whole secret once; both KV v1 ({"data":{...}}) and
+// KV v2 ({"data":{"data":{...}}}) response shapes are accepted.
+func (p *vaultSecretProvider) fetch() {
+	req, err := http.NewRequest(http.MethodGet, p.addr+"/v1/"+p.path, nil)
+	if err != nil {
+		p.err = fmt.Errorf("building vault request: %w", err)
+		return
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		p.err = fmt.Errorf("vault request: %w", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		p.err = fmt.Errorf("reading vault response: %w", err)
+		return
+	}
+	if resp.StatusCode != http.StatusOK {
+		p.err = fmt.Errorf("vault returned status %d for %s", resp.StatusCode, p.path)
+		return
+	}
+
+	var payload struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		p.err = fmt.Errorf("decoding vault response: %w", err)
+		return
+	}
+
+	fields := payload.Data
+	if nested, ok := payload.Data["data"]; ok {
+		var inner map[string]json.RawMessage
+		if err := json.Unmarshal(nested, &inner); err == nil {
+			fields = inner
+		}
+	}
+
+	p.values = make(map[string]string, len(fields))
+	for key, raw := range fields {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			s = string(raw)
+		}
+		p.values[key] = s
+	}
+}
+
+// interpolate expands ${VAR}, ${VAR:-default}, and ${secret:NAME} references
+// in the decoded string values of v, which must be a pointer to the struct
+// decoded from source. Expansion runs after parsing so comments are never
+// rewritten, and single-quoted TOML literal strings are kept verbatim. The
+// secrets section is expanded first (with env references only) so it can
+// select the provider used for secret references.
+func interpolate(source string, v any, secrets *Secrets) error {
+	if !strings.Contains(source, "${") {
+		return nil
+	}
+	w := interpolationWalker{literals: literalStrings(source)}
+	if err := w.walk(reflect.ValueOf(secrets).Elem()); err != nil {
+		return err
+	}
+	w.provider = &lazySecretProvider{secrets: secrets}
+	return w.walk(reflect.ValueOf(v).Elem())
+}
+
+// interpolationWalker expands references in every string reachable through
+// exported, TOML-mapped fields, including slice and map elements.
+type interpolationWalker struct {
+	provider SecretProvider
+	literals map[string]struct{}
+}
+
+func (w interpolationWalker) walk(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.String:
+		value := v.String()
+		if !strings.Contains(value, "${") {
+			return nil
+		}
+		if _, literal := w.literals[value]; literal {
+			return nil
+		}
+		out, err := expandReferences(value, w.provider)
+		if err != nil {
+			return err
+		}
+		v.SetString(out)
+	case reflect.Pointer:
+		if !v.IsNil() {
+			return w.walk(v.Elem())
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() || field.Tag.Get("toml") == "-" {
+				continue
+			}
+			if err := w.walk(v.Field(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := w.walk(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(iter.Value())
+			if err := w.walk(elem); err != nil {
+				return err
+			}
+			v.SetMapIndex(iter.Key(), elem)
+		}
+	}
+	return nil
+}
+
+// literalStrings returns the contents of single-quoted TOML literal strings in
+// source that contain a reference, so interpolation can leave them alone.
+// Comments and double-quoted strings are skipped while scanning.
+func literalStrings(source string) map[string]struct{} {
+	literals := make(map[string]struct{})
+	for i := 0; i < len(source); {
+		switch {
+		case source[i] == '#':
+			end := strings.IndexByte(source[i:], '\n')
+			if end < 0 {
+				return literals
+			}
+			i += end
+		case strings.HasPrefix(source[i:], `"""`):
+			j := i + 3
+			for j < len(source) && (!strings.HasPrefix(source[j:], `"""`) || isEscaped(source, j)) {
+				j++
+			}
+			i = j + 3
+		case source[i] == '"':
+			j := i + 1
+			for j < len(source) && source[j] != '\n' && (source[j] != '"' || isEscaped(source, j)) {
+				j++
+			}
+			i = j + 1
+		case strings.HasPrefix(source[i:], "'''"):
+			end := strings.Index(source[i+3:], "'''")
+			if end < 0 {
+				return literals
+			}
+			content := source[i+3 : i+3+end]
+			content = strings.TrimPrefix(strings.TrimPrefix(content, "\r"), "\n")
+			if strings.Contains(content, "${") {
+				literals[content] = struct{}{}
+			}
+			i += 3 + end + 3
+		case source[i] == '\'':
+			end := strings.IndexAny(source[i+1:], "'\n")
+			if end < 0 {
+				return literals
+			}
+			if content := source[i+1 : i+1+end]; strings.Contains(content, "${") {
+				literals[content] = struct{}{}
+			}
+			i += 1 + end + 1
+		default:
+			i++
+		}
+	}
+	return literals
+}
+
+// isEscaped reports whether the byte at pos is preceded by an odd number of
+// backslashes.
+func isEscaped(s string, pos int) bool {
+	n := 0
+	for pos-n-1 >= 0 && s[pos-n-1] == '\\' {
+		n++
+	}
+	return n%2 == 1
+}
+
+// lazySecretProvider builds the configured provider on first use, so configs
+// without ${secret:NAME} references never need a reachable secret backend.
+type lazySecretProvider struct {
+	secrets *Secrets
+
+	once     sync.Once
+	provider SecretProvider
+	err      error
+}
+
+func (p *lazySecretProvider) Resolve(name string) (string, error) {
+	p.once.Do(func() {
+		p.provider, p.err = NewSecretProvider(*p.secrets)
+	})
+	if p.err != nil {
+		return "", fmt.Errorf("secrets provider: %w", p.err)
+	}
+	return p.provider.Resolve(name)
+}
+
+// expandReferences substitutes references in data. When provider is nil,
+// ${secret:NAME} references are left untouched.
+func expandReferences(data string, provider SecretProvider) (string, error) {
+	var firstErr error
+	out := interpolationPattern.ReplaceAllStringFunc(data, func(ref string) string {
+		if firstErr != nil {
+			return ref
+		}
+		expr := strings.TrimSpace(ref[2 : len(ref)-1])
+
+		if name, ok := strings.CutPrefix(expr, "secret:"); ok {
+			if provider == nil {
+				return ref
+			}
+			value, err := provider.Resolve(strings.TrimSpace(name))
+			if err != nil {
+				firstErr = err
+				return ref
+			}
+			RegisterSecret(value)
+			return value
+		}
+
+		name, fallback, hasDefault := strings.Cut(expr, ":-")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			firstErr = fmt.Errorf("empty interpolation reference %q", ref)
+			return ref
+		}
+		value, ok := os.LookupEnv(name)
+		if !ok || (hasDefault && value == "") {
+			if !hasDefault {
+				firstErr = fmt.Errorf("environment variable %s referenced in config is not set", name)
+				return ref
+			}
+			value = fallback
+		}
+		return value
+	})
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return out, nil
+}
+
+// registerConfigSecrets marks sensitive fields of a loaded config for redaction.
+func registerConfigSecrets(cfg *Config) {
+	if cfg == nil {
+		return
+	}
+	for _, token := range cfg.API.Security.AllowedTokens {
+		RegisterSecret(token)
+	}
+	RegisterSecret(cfg.Reporter.Email.Password)
+	RegisterSecret(cfg.API.ForgeWebhooks.GitHubSecret)
+	RegisterSecret(cfg.API.ForgeWebhooks.GitLabToken)
+	for _, hook := range cfg.Reporter.Webhooks {
+		RegisterSecret(hook.Secret)
+	}
+}
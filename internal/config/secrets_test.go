@@ -0,0 +1,153 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadInterpolatesEnvVars(t *testing.T) {
+	t.Setenv("CORTEX_TEST_BIND", "127.0.0.1:8911")
+	cfgText := strings.Replace(validConfig, `bind = "127.0.0.1:8900"`, `bind = "${CORTEX_TEST_BIND}"`, 1)
+	cfgText = strings.Replace(cfgText, `agent_id = "main"`, `agent_id = "${CORTEX_TEST_UNSET_AGENT:-fallback}"`, 1)
+
+	cfg, err := Load(writeTestConfig(t, cfgText))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.API.Bind != "127.0.0.1:8911" {
+		t.Fatalf("api.bind = %q, want interpolated value", cfg.API.Bind)
+	}
+	if cfg.Reporter.AgentID != "fallback" {
+		t.Fatalf("reporter.agent_id = %q, want default value", cfg.Reporter.AgentID)
+	}
+}
+
+func TestLoadFailsOnUnsetEnvVar(t *testing.T) {
+	cfgText := strings.Replace(validConfig, `bind = "127.0.0.1:8900"`, `bind = "${CORTEX_TEST_DEFINITELY_UNSET}"`, 1)
+	_, err := Load(writeTestConfig(t, cfgText))
+	if err == nil || !strings.Contains(err.Error(), "CORTEX_TEST_DEFINITELY_UNSET") {
+		t.Fatalf("expected unset env var error, got %v", err)
+	}
+}
+
+func TestLoadLeavesCommentsAndLiteralStringsAlone(t *testing.T) {
+	t.Setenv("CORTEX_TEST_AGENT", `env "agent"`)
+	cfgText := strings.Replace(validConfig, `agent_id = "main"`, `agent_id = "${CORTEX_TEST_AGENT}"
+# agent_id used to be "${CORTEX_TEST_COMMENTED_OUT}"`, 1)
+	cfgText = strings.Replace(cfgText, `bind = "127.0.0.1:8900"`, `bind = '${CORTEX_TEST_LITERAL}'`, 1)
+
+	cfg, err := Load(writeTestConfig(t, cfgText))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Reporter.AgentID != `env "agent"` {
+		t.Fatalf("reporter.agent_id = %q, want value inserted verbatim", cfg.Reporter.AgentID)
+	}
+	if cfg.API.Bind != "${CORTEX_TEST_LITERAL}" {
+		t.Fatalf("api.bind = %q, want literal string left untouched", cfg.API.Bind)
+	}
+}
+
+func TestLoadResolvesFileSecretsAndRedacts(t *testing.T) {
+	secretDir := t.TempDir()
+	token := "file-secret-token-0123456789"
+	if err := os.WriteFile(filepath.Join(secretDir, "api_token"), []byte(token+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfgText := validConfig + `
+[api.security]
+enabled = true
+allowed_tokens = ["${secret:api_token}"]
+
+[secrets]
+provider = "file"
+dir = "` + secretDir + `"
+`
+	cfg, err := Load(writeTestConfig(t, cfgText))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cfg.API.Security.AllowedTokens) != 1 || cfg.API.Security.AllowedTokens[0] != token {
+		t.Fatalf("allowed_tokens = %v, want resolved secret", cfg.API.Security.AllowedTokens)
+	}
+
+	if got := Redact("auth failed for " + token); strings.Contains(got, token) {
+		t.Fatalf("Redact leaked secret: %q", got)
+	}
+	redacted := cfg.Redacted()
+	if redacted.API.Security.AllowedTokens[0] != RedactedPlaceholder {
+		t.Fatalf("Redacted() token = %q", redacted.API.Security.AllowedTokens[0])
+	}
+	if cfg.API.Security.AllowedTokens[0] != token {
+		t.Fatal("Redacted() must not mutate the source config")
+	}
+}
+
+func TestEnvSecretProviderUsesPrefix(t *testing.T) {
+	t.Setenv("CORTEX_SECRET_MATRIX", "matrix-value")
+	provider, err := NewSecretProvider(Secrets{Provider: "env", EnvPrefix: "CORTEX_SECRET_"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := provider.Resolve("MATRIX")
+	if err != nil || got != "matrix-value" {
+		t.Fatalf("Resolve = %q, %v", got, err)
+	}
+	if _, err := provider.Resolve("MISSING"); err == nil {
+		t.Fatal("expected error for missing env secret")
+	}
+}
+
+func TestFileSecretProviderRejectsTraversal(t *testing.T) {
+	provider, err := NewSecretProvider(Secrets{Provider: "file", Dir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := provider.Resolve("../etc/passwd"); err == nil {
+		t.Fatal("expected traversal to be rejected")
+	}
+}
+
+func TestVaultSecretProviderReadsKVv2(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "vault-test-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/v1/secret/data/cortex" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"data":{"api_token":"vault-secret-value"}}}`))
+	}))
+	defer srv.Close()
+
+	t.Setenv("CORTEX_TEST_VAULT_TOKEN", "vault-test-token")
+	provider, err := NewSecretProvider(Secrets{
+		Provider:      "vault",
+		VaultAddr:     srv.URL,
+		VaultPath:     "secret/data/cortex",
+		VaultTokenEnv: "CORTEX_TEST_VAULT_TOKEN",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := provider.Resolve("api_token")
+	if err != nil || got != "vault-secret-value" {
+		t.Fatalf("Resolve = %q, %v", got, err)
+	}
+	if _, err := provider.Resolve("missing"); err == nil {
+		t.Fatal("expected missing key error")
+	}
+}
+
+func TestNewSecretProviderRejectsUnknown(t *testing.T) {
+	if _, err := NewSecretProvider(Secrets{Provider: "keychain"}); err == nil {
+		t.Fatal("expected unknown provider error")
+	}
+}
@@ -9,6 +9,11 @@ import (
 type TokenUsage struct {
 	Input  int
 	Output int
+
+	// Only known from a CLI's own usage report.
+	CacheRead     int
+	CacheCreation int
+	CostUSD       float64 // cost the CLI reported; 0 if it reported none
 }
 
 var (
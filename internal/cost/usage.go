@@ -0,0 +1,242 @@
+package cost
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Usage report formats understood by ParserFor.
+const (
+	FormatClaude   = "claude"   // claude --output-format json, or stream-json events
+	FormatCodex    = "codex"    // codex exec --json turn events, or its "tokens used" footer
+	FormatOpenClaw = "openclaw" // OpenClaw gateway accounting lines
+)
+
+// Formats lists the usage report formats, for config validation.
+var Formats = []string{FormatClaude, FormatCodex, FormatOpenClaw}
+
+// Parser extracts the token usage a CLI reported in its own output. ok is
+// false when the output holds no usage report.
+type Parser func(output string) (usage TokenUsage, ok bool)
+
+// ParserFor returns the parser for a usage report format. An empty format
+// selects the format of the named CLI; a CLI with no known format gets a
+// parser that tries each format in turn.
+func ParserFor(format, cli string) Parser {
+	if format == "" {
+		format = strings.ToLower(cli)
+	}
+	switch format {
+	case FormatClaude:
+		return ParseClaudeUsage
+	case FormatCodex:
+		return ParseCodexUsage
+	case FormatOpenClaw:
+		return ParseOpenClawUsage
+	}
+	return parseAnyUsage
+}
+
+// ExtractUsage returns the usage parse finds in output, or the heuristic
+// estimate of ExtractTokenUsage when there is no report. estimated tells
+// the two apart.
+func ExtractUsage(parse Parser, output, prompt string) (usage TokenUsage, estimated bool) {
+	if parse != nil {
+		if usage, ok := parse(output); ok {
+			return usage, false
+		}
+	}
+	return ExtractTokenUsage(output, prompt), true
+}
+
+func parseAnyUsage(output string) (TokenUsage, bool) {
+	for _, parse := range []Parser{ParseClaudeUsage, ParseCodexUsage, ParseOpenClawUsage} {
+		if usage, ok := parse(output); ok {
+			return usage, true
+		}
+	}
+	return TokenUsage{}, false
+}
+
+// claudeUsage is the usage object of claude's result and message events.
+type claudeUsage struct {
+	InputTokens         int `json:"input_tokens"`
+	OutputTokens        int `json:"output_tokens"`
+	CacheReadTokens     int `json:"cache_read_input_tokens"`
+	CacheCreationTokens int `json:"cache_creation_input_tokens"`
+}
+
+// claudeResult is the result object of claude's json and stream-json output.
+type claudeResult struct {
+	Usage        *claudeUsage `json:"usage"`
+	TotalCostUSD float64      `json:"total_cost_usd"`
+	CostUSD      float64      `json:"cost_usd"` // older CLIs
+}
+
+// claudeEvent is one assistant line of claude's stream-json output. A
+// message is printed once per content block, each time with its usage so far.
+type claudeEvent struct {
+	Type    string `json:"type"`
+	Message struct {
+		ID    string       `json:"id"`
+		Usage *claudeUsage `json:"usage"`
+	} `json:"message"`
+}
+
+// ParseClaudeUsage reads the usage of claude's final result object. With
+// stream-json output that is the last JSON line carrying usage; a stream
+// still running has no result yet, so the usage of the assistant messages
+// printed so far is summed instead.
+func ParseClaudeUsage(output string) (TokenUsage, bool) {
+	if r, ok := decodeClaudeResult(strings.TrimSpace(output)); ok {
+		return r, true
+	}
+	lines := strings.Split(output, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if r, ok := decodeClaudeResult(strings.TrimSpace(lines[i])); ok {
+			return r, true
+		}
+	}
+	return sumClaudeMessages(lines)
+}
+
+// sumClaudeMessages adds up the latest usage of each assistant message in
+// stream-json lines.
+func sumClaudeMessages(lines []string) (TokenUsage, bool) {
+	var ids []string
+	latest := make(map[string]claudeUsage)
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "{") || !strings.Contains(line, `"assistant"`) {
+			continue
+		}
+		var ev claudeEvent
+		if err := json.Unmarshal([]byte(line), &ev); err != nil || ev.Type != "assistant" || ev.Message.Usage == nil {
+			continue
+		}
+		id := ev.Message.ID
+		if id == "" {
+			id = strconv.Itoa(i)
+		}
+		if _, ok := latest[id]; !ok {
+			ids = append(ids, id)
+		}
+		latest[id] = *ev.Message.Usage
+	}
+	if len(ids) == 0 {
+		return TokenUsage{}, false
+	}
+	var usage TokenUsage
+	for _, id := range ids {
+		u := latest[id]
+		usage.Input += u.InputTokens
+		usage.Output += u.OutputTokens
+		usage.CacheRead += u.CacheReadTokens
+		usage.CacheCreation += u.CacheCreationTokens
+	}
+	return usage, true
+}
+
+func decodeClaudeResult(s string) (TokenUsage, bool) {
+	if !strings.HasPrefix(s, "{") {
+		return TokenUsage{}, false
+	}
+	var r claudeResult
+	if err := json.Unmarshal([]byte(s), &r); err != nil || r.Usage == nil {
+		return TokenUsage{}, false
+	}
+	cost := r.TotalCostUSD
+	if cost == 0 {
+		cost = r.CostUSD
+	}
+	return TokenUsage{
+		Input:         r.Usage.InputTokens,
+		Output:        r.Usage.OutputTokens,
+		CacheRead:     r.Usage.CacheReadTokens,
+		CacheCreation: r.Usage.CacheCreationTokens,
+		CostUSD:       cost,
+	}, true
+}
+
+// codexEvent is one line of codex exec --json output.
+type codexEvent struct {
+	Type  string `json:"type"`
+	Usage *struct {
+		InputTokens       int `json:"input_tokens"`
+		CachedInputTokens int `json:"cached_input_tokens"`
+		OutputTokens      int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// codexFooterRe matches the footer of plain codex exec output, which puts
+// the total on the same line or the next one.
+var codexFooterRe = regexp.MustCompile(`(?im)^\s*(?:\[[^\]]*\]\s*)?tokens used:?\s*([\d,]+)\s*$`)
+
+// ParseCodexUsage sums the usage of the turn.completed events of codex exec
+// --json output. Plain output only reports a total in its "tokens used"
+// footer; the total is counted as input, which dominates agent sessions.
+func ParseCodexUsage(output string) (TokenUsage, bool) {
+	var usage TokenUsage
+	found := false
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "{") || !strings.Contains(line, "turn.completed") {
+			continue
+		}
+		var ev codexEvent
+		if err := json.Unmarshal([]byte(line), &ev); err != nil || ev.Type != "turn.completed" || ev.Usage == nil {
+			continue
+		}
+		usage.Input += ev.Usage.InputTokens
+		usage.Output += ev.Usage.OutputTokens
+		usage.CacheRead += ev.Usage.CachedInputTokens
+		found = true
+	}
+	if found {
+		return usage, true
+	}
+	matches := codexFooterRe.FindAllStringSubmatch(output, -1)
+	if len(matches) == 0 {
+		return TokenUsage{}, false
+	}
+	total, err := strconv.Atoi(strings.ReplaceAll(matches[len(matches)-1][1], ",", ""))
+	if err != nil {
+		return TokenUsage{}, false
+	}
+	return TokenUsage{Input: total}, true
+}
+
+var (
+	// openClawUsageRe matches a gateway accounting line, e.g.
+	// "Tokens: 1500 input, 2500 output, 300 cache read, 20 cache write".
+	openClawUsageRe = regexp.MustCompile(`Tokens: (\d+) input, (\d+) output(?:, (\d+) cache read)?(?:, (\d+) cache write)?`)
+	openClawCostRe  = regexp.MustCompile(`Cost: \$(\d+(?:\.\d+)?)`)
+)
+
+// ParseOpenClawUsage sums the accounting lines the OpenClaw gateway prints,
+// one per model request, and the costs it reports with them.
+func ParseOpenClawUsage(output string) (TokenUsage, bool) {
+	matches := openClawUsageRe.FindAllStringSubmatch(output, -1)
+	if len(matches) == 0 {
+		return TokenUsage{}, false
+	}
+	var usage TokenUsage
+	for _, m := range matches {
+		usage.Input += atoi(m[1])
+		usage.Output += atoi(m[2])
+		usage.CacheRead += atoi(m[3])
+		usage.CacheCreation += atoi(m[4])
+	}
+	for _, m := range openClawCostRe.FindAllStringSubmatch(output, -1) {
+		cost, _ := strconv.ParseFloat(m[1], 64)
+		usage.CostUSD += cost
+	}
+	return usage, true
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
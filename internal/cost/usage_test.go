@@ -0,0 +1,92 @@
+package cost
+
+import (
+	"testing"
+)
+
+func TestParseClaudeUsage(t *testing.T) {
+	json := `{"type":"result","result":"done","total_cost_usd":0.12,"usage":{"input_tokens":1200,"output_tokens":300,"cache_read_input_tokens":5000,"cache_creation_input_tokens":40}}`
+	stream := `{"type":"system","subtype":"init"}
+{"type":"assistant","message":{"content":[]}}
+` + json + "\n"
+	for name, output := range map[string]string{"json": json, "stream-json": stream} {
+		usage, ok := ParseClaudeUsage(output)
+		if !ok {
+			t.Fatalf("%s: no usage found", name)
+		}
+		want := TokenUsage{Input: 1200, Output: 300, CacheRead: 5000, CacheCreation: 40, CostUSD: 0.12}
+		if usage != want {
+			t.Fatalf("%s: usage = %+v, want %+v", name, usage, want)
+		}
+	}
+
+	// A stream still running has no result: its messages' usage is summed,
+	// counting each message once at its latest usage.
+	running := `{"type":"system","subtype":"init"}
+{"type":"assistant","message":{"id":"msg_1","content":[{"type":"text"}],"usage":{"input_tokens":10,"output_tokens":5,"cache_read_input_tokens":4000}}}
+{"type":"assistant","message":{"id":"msg_1","content":[{"type":"tool_use"}],"usage":{"input_tokens":10,"output_tokens":90,"cache_read_input_tokens":4000}}}
+{"type":"user","message":{"content":[{"type":"tool_result"}]}}
+{"type":"assistant","message":{"id":"msg_2","content":[{"type":"text"}],"usage":{"input_tokens":20,"output_tokens":30,"cache_creation_input_tokens":100}}}
+`
+	usage, ok := ParseClaudeUsage(running)
+	if want := (TokenUsage{Input: 30, Output: 120, CacheRead: 4000, CacheCreation: 100}); !ok || usage != want {
+		t.Fatalf("running stream usage = %+v, %v; want %+v", usage, ok, want)
+	}
+	if _, ok := ParseClaudeUsage("plain text"); ok {
+		t.Fatal("plain text has no usage")
+	}
+}
+
+func TestParseCodexUsage(t *testing.T) {
+	events := `{"type":"thread.started","thread_id":"t"}
+{"type":"turn.completed","usage":{"input_tokens":1000,"cached_input_tokens":600,"output_tokens":200}}
+{"type":"turn.completed","usage":{"input_tokens":500,"cached_input_tokens":0,"output_tokens":50}}
+`
+	usage, ok := ParseCodexUsage(events)
+	if !ok || usage != (TokenUsage{Input: 1500, Output: 250, CacheRead: 600}) {
+		t.Fatalf("events usage = %+v, %v", usage, ok)
+	}
+
+	for _, footer := range []string{
+		"edited main.go\ntokens used: 12,345\n",
+		"edited main.go\n[2026-01-02T03:04:05] tokens used: 12345\n",
+		"edited main.go\ntokens used\n12,345\n",
+	} {
+		usage, ok := ParseCodexUsage(footer)
+		if !ok || usage != (TokenUsage{Input: 12345}) {
+			t.Fatalf("footer %q usage = %+v, %v", footer, usage, ok)
+		}
+	}
+	if _, ok := ParseCodexUsage("no usage here"); ok {
+		t.Fatal("expected no usage")
+	}
+}
+
+func TestParseOpenClawUsage(t *testing.T) {
+	output := "step 1\nTokens: 1000 input, 200 output, 300 cache read, 20 cache write\nCost: $0.0125\n" +
+		"step 2\nTokens: 500 input, 100 output\nCost: $0.005\n"
+	usage, ok := ParseOpenClawUsage(output)
+	want := TokenUsage{Input: 1500, Output: 300, CacheRead: 300, CacheCreation: 20, CostUSD: 0.0175}
+	if !ok || usage.Input != want.Input || usage.Output != want.Output || usage.CacheRead != want.CacheRead ||
+		usage.CacheCreation != want.CacheCreation || usage.CostUSD < 0.01749 || usage.CostUSD > 0.01751 {
+		t.Fatalf("usage = %+v, %v", usage, ok)
+	}
+}
+
+func TestParserForAndExtractUsage(t *testing.T) {
+	footer := "done\ntokens used: 900\n"
+	if usage, estimated := ExtractUsage(ParserFor("", "codex"), footer, "prompt"); estimated || usage.Input != 900 {
+		t.Fatalf("codex usage = %+v, estimated %v", usage, estimated)
+	}
+	// An explicit format wins over the CLI name.
+	if _, estimated := ExtractUsage(ParserFor(FormatOpenClaw, "codex"), footer, "prompt"); !estimated {
+		t.Fatal("openclaw parser should not read a codex footer")
+	}
+	// Unknown CLIs try every format.
+	if usage, estimated := ExtractUsage(ParserFor("", "aider"), footer, "prompt"); estimated || usage.Input != 900 {
+		t.Fatalf("fallback usage = %+v, estimated %v", usage, estimated)
+	}
+	if usage, estimated := ExtractUsage(ParserFor("", "codex"), "no report", "a prompt"); !estimated || usage.Input == 0 {
+		t.Fatalf("estimate = %+v, estimated %v", usage, estimated)
+	}
+}
@@ -0,0 +1,288 @@
+// Package costreport totals dispatch spend per calendar month, by project
+// and by role, provider and tier, for internal chargeback. Reports are stored
+// as JSON and Markdown artifacts.
+package costreport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+// MonthLayout is the format of a report month.
+const MonthLayout = "2006-01"
+
+const artifactPrefix = "cost-"
+
+// ErrNoReport is returned when the reports directory has no matching report.
+var ErrNoReport = errors.New("costreport: no report")
+
+// roleNames maps recorded activity names to the roles that ran them.
+var roleNames = map[string]string{
+	"plan":    "planner",
+	"execute": "coder",
+	"review":  "reviewer",
+}
+
+// Line is the spend under one role, provider or tier.
+type Line struct {
+	Name         string  `json:"name"`
+	Dispatches   int     `json:"dispatches"`
+	InputTokens  int     `json:"input_tokens"`
+	OutputTokens int     `json:"output_tokens"`
+	CostUSD      float64 `json:"cost_usd"`
+}
+
+// ProjectCost is one project's spend for the month: its chargeback invoice.
+type ProjectCost struct {
+	Project      string  `json:"project"`
+	Dispatches   int     `json:"dispatches"`
+	InputTokens  int     `json:"input_tokens"`
+	OutputTokens int     `json:"output_tokens"`
+	CostUSD      float64 `json:"cost_usd"`
+	ByRole       []Line  `json:"by_role"`
+	ByProvider   []Line  `json:"by_provider"`
+	ByTier       []Line  `json:"by_tier"`
+}
+
+// Report is the spend of every project in one calendar month (UTC).
+type Report struct {
+	Month       string        `json:"month"`
+	GeneratedAt time.Time     `json:"generated_at"`
+	From        time.Time     `json:"from"`
+	To          time.Time     `json:"to"`
+	Dispatches  int           `json:"dispatches"`
+	CostUSD     float64       `json:"cost_usd"`
+	Projects    []ProjectCost `json:"projects"`
+}
+
+// MonthRange returns the UTC bounds [from, to) of month (YYYY-MM).
+func MonthRange(month string) (from, to time.Time, err error) {
+	from, err = time.Parse(MonthLayout, month)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("costreport: month must be YYYY-MM")
+	}
+	return from, from.AddDate(0, 1, 0), nil
+}
+
+// PreviousMonth returns the month before the one now falls in.
+func PreviousMonth(now time.Time) string {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0).Format(MonthLayout)
+}
+
+// Generate totals the spend of month. Dispatch counts, tokens and cost per
+// project, provider and tier come from the dispatches started in the month;
+// the role split comes from the per-activity usage recorded in it.
+func Generate(st *store.Store, month string) (*Report, error) {
+	from, to, err := MonthRange(month)
+	if err != nil {
+		return nil, err
+	}
+	providers, err := st.GetDispatchCosts("provider", from, to)
+	if err != nil {
+		return nil, err
+	}
+	tiers, err := st.GetDispatchCosts("tier", from, to)
+	if err != nil {
+		return nil, err
+	}
+	activities, err := st.GetActivityCosts(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := map[string]*ProjectCost{}
+	project := func(name string) *ProjectCost {
+		p, ok := projects[name]
+		if !ok {
+			p = &ProjectCost{Project: name}
+			projects[name] = p
+		}
+		return p
+	}
+	for _, l := range providers {
+		p := project(l.Project)
+		p.Dispatches += l.Dispatches
+		p.InputTokens += l.InputTokens
+		p.OutputTokens += l.OutputTokens
+		p.CostUSD += l.CostUSD
+		p.ByProvider = append(p.ByProvider, line(l, l.Key))
+	}
+	for _, l := range tiers {
+		p := project(l.Project)
+		p.ByTier = append(p.ByTier, line(l, l.Key))
+	}
+	for _, l := range activities {
+		role := roleNames[l.Key]
+		if role == "" {
+			role = l.Key
+		}
+		p := project(l.Project)
+		p.ByRole = append(p.ByRole, line(l, role))
+	}
+
+	r := &Report{Month: month, GeneratedAt: time.Now().UTC(), From: from, To: to}
+	for _, p := range projects {
+		sortLines(p.ByRole)
+		r.Dispatches += p.Dispatches
+		r.CostUSD += p.CostUSD
+		r.Projects = append(r.Projects, *p)
+	}
+	sort.Slice(r.Projects, func(i, j int) bool { return r.Projects[i].Project < r.Projects[j].Project })
+	return r, nil
+}
+
+func line(l store.CostLine, name string) Line {
+	if name == "" {
+		name = "unknown"
+	}
+	return Line{Name: name, Dispatches: l.Dispatches, InputTokens: l.InputTokens, OutputTokens: l.OutputTokens, CostUSD: l.CostUSD}
+}
+
+func sortLines(lines []Line) {
+	sort.Slice(lines, func(i, j int) bool { return lines[i].Name < lines[j].Name })
+}
+
+// ForProject narrows the report to one project's invoice, or returns false
+// if the project had no spend that month.
+func (r *Report) ForProject(name string) (*Report, bool) {
+	for _, p := range r.Projects {
+		if p.Project == name {
+			out := *r
+			out.Dispatches, out.CostUSD = p.Dispatches, p.CostUSD
+			out.Projects = []ProjectCost{p}
+			return &out, true
+		}
+	}
+	return nil, false
+}
+
+// Markdown renders the report for humans.
+func (r *Report) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Cortex Cost Report - %s\n\n", r.Month)
+	fmt.Fprintf(&b, "Period: %s to %s\n\n", r.From.Format(time.DateOnly), r.To.AddDate(0, 0, -1).Format(time.DateOnly))
+	fmt.Fprintf(&b, "Total: %s over %d dispatches\n", usd(r.CostUSD), r.Dispatches)
+	if len(r.Projects) == 0 {
+		b.WriteString("\nNo spend recorded.\n")
+	}
+	for _, p := range r.Projects {
+		fmt.Fprintf(&b, "\n## %s: %s\n\n", p.Project, usd(p.CostUSD))
+		fmt.Fprintf(&b, "%d dispatches, %d input and %d output tokens.\n", p.Dispatches, p.InputTokens, p.OutputTokens)
+		writeLines(&b, "Role", p.ByRole)
+		writeLines(&b, "Provider", p.ByProvider)
+		writeLines(&b, "Tier", p.ByTier)
+	}
+	return b.String()
+}
+
+func writeLines(b *strings.Builder, title string, lines []Line) {
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n| %s | Dispatches | Input Tokens | Output Tokens | Cost |\n", title)
+	b.WriteString("|---|---|---|---|---|\n")
+	for _, l := range lines {
+		fmt.Fprintf(b, "| %s | %d | %d | %d | %s |\n", l.Name, l.Dispatches, l.InputTokens, l.OutputTokens, usd(l.CostUSD))
+	}
+}
+
+func usd(v float64) string {
+	return fmt.Sprintf("$%.2f", v)
+}
+
+// Write stores the report as cost-<month>.json and cost-<month>.md in dir,
+// replacing any report for the same month.
+func Write(dir string, r *Report) (jsonPath, mdPath string, err error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", "", fmt.Errorf("costreport: create reports dir: %w", err)
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", "", fmt.Errorf("costreport: encode report: %w", err)
+	}
+	base := filepath.Join(dir, artifactPrefix+r.Month)
+	if err := writeFileAtomic(base+".json", append(data, '\n')); err != nil {
+		return "", "", err
+	}
+	if err := writeFileAtomic(base+".md", []byte(r.Markdown())); err != nil {
+		return "", "", err
+	}
+	return base + ".json", base + ".md", nil
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("costreport: write %s: %w", filepath.Base(path), err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("costreport: write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// Load reads the report for month (YYYY-MM) from dir.
+func Load(dir, month string) (*Report, error) {
+	if _, _, err := MonthRange(month); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, artifactPrefix+month+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoReport
+	}
+	if err != nil {
+		return nil, fmt.Errorf("costreport: read report: %w", err)
+	}
+	var r Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("costreport: decode report %s: %w", month, err)
+	}
+	return &r, nil
+}
+
+// Latest reads the most recent report in dir, or returns ErrNoReport.
+func Latest(dir string) (*Report, error) {
+	months, err := Months(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(months) == 0 {
+		return nil, ErrNoReport
+	}
+	return Load(dir, months[len(months)-1])
+}
+
+// Months lists the months of the reports in dir, oldest first. A missing
+// directory has no reports.
+func Months(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("costreport: list reports: %w", err)
+	}
+	var months []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, artifactPrefix) || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		month := strings.TrimSuffix(strings.TrimPrefix(name, artifactPrefix), ".json")
+		if _, err := time.Parse(MonthLayout, month); err == nil {
+			months = append(months, month)
+		}
+	}
+	sort.Strings(months)
+	return months, nil
+}
@@ -0,0 +1,140 @@
+package costreport
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestMonthRange(t *testing.T) {
+	from, to, err := MonthRange("2026-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !from.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) || !to.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected range %s - %s", from, to)
+	}
+	if _, _, err := MonthRange("2026-02-01"); err == nil {
+		t.Fatal("expected a date to be rejected")
+	}
+	if got := PreviousMonth(time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)); got != "2025-12" {
+		t.Fatalf("expected 2025-12, got %s", got)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { st.Close() })
+
+	record := func(project, provider, tier string, plan, execute float64) {
+		t.Helper()
+		id, err := st.RecordDispatch("b", project, "agent", provider, tier, 1, "", "p", "", "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := st.RecordDispatchCost(id, 1000, 100, plan+execute); err != nil {
+			t.Fatal(err)
+		}
+		for activity, cost := range map[string]float64{"plan": plan, "execute": execute} {
+			if err := st.StoreTokenUsage(id, "b", project, activity, "agent", store.TokenUsage{InputTokens: 500, OutputTokens: 50, CostUSD: cost}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	record("alpha", "claude", "premium", 1, 3)
+	record("alpha", "codex", "balanced", 0.25, 0.75)
+	record("beta", "codex", "balanced", 0.5, 1.5)
+
+	month := time.Now().UTC().Format(MonthLayout)
+	r, err := Generate(st, month)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Dispatches != 3 || r.CostUSD != 7 || len(r.Projects) != 2 {
+		t.Fatalf("unexpected totals: %+v", r)
+	}
+	alpha := r.Projects[0]
+	if alpha.Project != "alpha" || alpha.CostUSD != 5 || alpha.Dispatches != 2 || alpha.InputTokens != 2000 {
+		t.Fatalf("unexpected alpha invoice: %+v", alpha)
+	}
+	if len(alpha.ByRole) != 2 || alpha.ByRole[0].Name != "coder" || alpha.ByRole[0].CostUSD != 3.75 || alpha.ByRole[1].Name != "planner" {
+		t.Fatalf("unexpected role split: %+v", alpha.ByRole)
+	}
+	if len(alpha.ByProvider) != 2 || alpha.ByProvider[0].Name != "claude" || alpha.ByProvider[0].CostUSD != 4 {
+		t.Fatalf("unexpected provider split: %+v", alpha.ByProvider)
+	}
+	if len(alpha.ByTier) != 2 || alpha.ByTier[0].Name != "balanced" || alpha.ByTier[0].CostUSD != 1 {
+		t.Fatalf("unexpected tier split: %+v", alpha.ByTier)
+	}
+
+	invoice, ok := r.ForProject("beta")
+	if !ok || invoice.CostUSD != 2 || invoice.Dispatches != 1 || len(invoice.Projects) != 1 {
+		t.Fatalf("unexpected beta invoice: %+v", invoice)
+	}
+	if _, ok := r.ForProject("gamma"); ok {
+		t.Fatal("expected no invoice for a project without spend")
+	}
+
+	md := r.Markdown()
+	for _, want := range []string{
+		"# Cortex Cost Report - " + month,
+		"Total: $7.00 over 3 dispatches",
+		"## alpha: $5.00",
+		"| coder | 2 | 1000 | 100 | $3.75 |",
+		"| claude | 1 | 1000 | 100 | $4.00 |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+
+	empty, err := Generate(st, "2020-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if empty.CostUSD != 0 || len(empty.Projects) != 0 || !strings.Contains(empty.Markdown(), "No spend recorded.") {
+		t.Fatalf("expected an empty report, got %+v", empty)
+	}
+}
+
+func TestWriteLoadLatest(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "reports")
+	if _, err := Latest(dir); !errors.Is(err, ErrNoReport) {
+		t.Fatalf("expected ErrNoReport for a missing dir, got %v", err)
+	}
+
+	for _, month := range []string{"2026-02", "2026-01"} {
+		jsonPath, mdPath, err := Write(dir, &Report{Month: month, CostUSD: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if filepath.Base(jsonPath) != "cost-"+month+".json" || filepath.Base(mdPath) != "cost-"+month+".md" {
+			t.Fatalf("unexpected artifact paths %s, %s", jsonPath, mdPath)
+		}
+	}
+
+	months, err := Months(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(months, ",") != "2026-01,2026-02" {
+		t.Fatalf("unexpected months %v", months)
+	}
+	latest, err := Latest(dir)
+	if err != nil || latest.Month != "2026-02" {
+		t.Fatalf("expected the 2026-02 report, got %+v, %v", latest, err)
+	}
+	if _, err := Load(dir, "2025-12"); !errors.Is(err, ErrNoReport) {
+		t.Fatalf("expected ErrNoReport, got %v", err)
+	}
+	if _, err := Load(dir, "../x"); err == nil || errors.Is(err, ErrNoReport) {
+		t.Fatalf("expected a bad month to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,239 @@
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+// Failure categories ClassifyFailure assigns to failed provider calls.
+const (
+	FailureGatewayClosed = "gateway_closed"
+	FailureRateLimited   = "rate_limited"
+	FailureAuth          = "auth"
+	FailureTimeout       = "timeout"
+	FailureUnknown       = "unknown"
+)
+
+// Circuit breaker scopes.
+const (
+	BreakerScopeProvider = "provider"
+	BreakerScopeCategory = "category"
+)
+
+var failurePatterns = []struct {
+	category string
+	pattern  *regexp.Regexp
+}{
+	{FailureGatewayClosed, regexp.MustCompile(`(?i)gateway closed|gateway connect failed|ECONNREFUSED|connection refused`)},
+	{FailureRateLimited, regexp.MustCompile(`(?i)\b429\b|rate.?limit(ed)? exceeded|too many requests|overloaded`)},
+	{FailureAuth, regexp.MustCompile(`(?i)\b401\b|\b403\b|unauthori[sz]ed|invalid api key|not logged in|authentication failed`)},
+	{FailureTimeout, regexp.MustCompile(`(?i)timed out|timeout exceeded|deadline exceeded`)},
+}
+
+// ClassifyFailure assigns a failure category to a provider call that exited
+// with err, from the error and the call's output.
+func ClassifyFailure(output string, err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return FailureTimeout
+	}
+	text := output
+	if err != nil {
+		text = err.Error() + "\n" + output
+	}
+	for _, fp := range failurePatterns {
+		if fp.pattern.MatchString(text) {
+			return fp.category
+		}
+	}
+	return FailureUnknown
+}
+
+// circuitBreaker opens once cfg.Threshold failures fall within cfg.Window and
+// stays open for cfg.Cooldown. Once it has tripped, the first failure after
+// the cooldown reopens it; a success closes it for good.
+type circuitBreaker struct {
+	cfg       config.CircuitBreaker
+	failures  []time.Time
+	openUntil time.Time
+	tripped   bool
+}
+
+func (c *circuitBreaker) prune(now time.Time) {
+	cutoff := now.Add(-c.cfg.Window.Duration)
+	kept := c.failures[:0]
+	for _, t := range c.failures {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	c.failures = kept
+}
+
+func (c *circuitBreaker) recordFailure(now time.Time) {
+	if c.cfg.Threshold <= 0 {
+		return
+	}
+	c.prune(now)
+	c.failures = append(c.failures, now)
+	if len(c.failures) >= c.cfg.Threshold || (c.tripped && !now.Before(c.openUntil)) {
+		c.openUntil = now.Add(c.cfg.Cooldown.Duration)
+		c.tripped = true
+	}
+}
+
+func (c *circuitBreaker) reset() {
+	c.failures = nil
+	c.openUntil = time.Time{}
+	c.tripped = false
+}
+
+func (c *circuitBreaker) open(now time.Time) bool {
+	return c.cfg.Threshold > 0 && now.Before(c.openUntil)
+}
+
+func (c *circuitBreaker) status(scope, name string, now time.Time) BreakerStatus {
+	c.prune(now)
+	st := BreakerStatus{
+		Scope:          scope,
+		Name:           name,
+		Threshold:      c.cfg.Threshold,
+		WindowS:        c.cfg.Window.Seconds(),
+		CooldownS:      c.cfg.Cooldown.Seconds(),
+		RecentFailures: len(c.failures),
+		Open:           c.open(now),
+	}
+	if st.Open {
+		st.OpenUntil = c.openUntil
+	}
+	return st
+}
+
+// BreakerStatus describes one circuit breaker.
+type BreakerStatus struct {
+	Scope          string    `json:"scope"` // provider or category
+	Name           string    `json:"name"`
+	Threshold      int       `json:"threshold"` // 0 when disabled
+	WindowS        float64   `json:"window_s"`
+	CooldownS      float64   `json:"cooldown_s"`
+	RecentFailures int       `json:"recent_failures"`
+	Open           bool      `json:"open"`
+	OpenUntil      time.Time `json:"open_until,omitzero"`
+}
+
+// SetCircuitBreakers swaps the circuit breaker configuration. Failures
+// already recorded are kept.
+func (r *RateLimiter) SetCircuitBreakers(cfg config.DispatchCircuitBreakers) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.breakerCfg = cfg
+}
+
+// categoryLocked returns the breaker for a failure category, or nil when the
+// category has none configured. Caller holds r.mu.
+func (r *RateLimiter) categoryLocked(category string) *circuitBreaker {
+	cfg, ok := r.breakerCfg.Categories[category]
+	if !ok {
+		return nil
+	}
+	if r.categories == nil {
+		r.categories = make(map[string]*circuitBreaker)
+	}
+	c, ok := r.categories[category]
+	if !ok {
+		c = &circuitBreaker{}
+		r.categories[category] = c
+	}
+	c.cfg = cfg
+	return c
+}
+
+// openCategoryLocked returns an open category breaker's category, or "".
+// Caller holds r.mu.
+func (r *RateLimiter) openCategoryLocked(now time.Time) string {
+	categories := make([]string, 0, len(r.breakerCfg.Categories))
+	for category := range r.breakerCfg.Categories {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		if r.categoryLocked(category).open(now) {
+			return category
+		}
+	}
+	return ""
+}
+
+// RecordProviderOutcome feeds a finished call into the circuit breakers. An
+// empty category is a success, which closes the provider's breaker; a failure
+// counts toward both the provider's breaker and its category's breaker.
+func (r *RateLimiter) RecordProviderOutcome(name string, p config.Provider, category string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	b := r.bucketsLocked(name, p)
+	if category == "" {
+		b.breaker.reset()
+		// A call getting through shows a tripped category has recovered.
+		for c := range r.breakerCfg.Categories {
+			if cb := r.categoryLocked(c); !cb.open(now) {
+				cb.tripped = false
+			}
+		}
+		return
+	}
+	b.breaker.recordFailure(now)
+	if cb := r.categoryLocked(category); cb != nil {
+		cb.recordFailure(now)
+	}
+}
+
+// CircuitBreakers returns the state of every category breaker and of the
+// given providers' breakers, categories first.
+func (r *RateLimiter) CircuitBreakers(providers map[string]config.Provider) []BreakerStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	var out []BreakerStatus
+	for category := range r.breakerCfg.Categories {
+		out = append(out, r.categoryLocked(category).status(BreakerScopeCategory, category, now))
+	}
+	for name, p := range providers {
+		out = append(out, r.bucketsLocked(name, p).breaker.status(BreakerScopeProvider, name, now))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Scope != out[j].Scope {
+			return out[i].Scope == BreakerScopeCategory
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// ResetCircuitBreaker closes a breaker and forgets its failures. It reports
+// false for an unknown scope or unconfigured category; provider names are
+// not checked.
+func (r *RateLimiter) ResetCircuitBreaker(scope, name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch scope {
+	case BreakerScopeCategory:
+		if c := r.categoryLocked(name); c != nil {
+			c.reset()
+			return true
+		}
+	case BreakerScopeProvider:
+		if b, ok := r.buckets[name]; ok {
+			b.breaker.reset()
+		}
+		return true
+	}
+	return false
+}
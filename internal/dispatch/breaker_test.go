@@ -0,0 +1,143 @@
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+func testBreakers() config.DispatchCircuitBreakers {
+	return config.DispatchCircuitBreakers{
+		Provider: config.CircuitBreaker{Threshold: 3, Window: config.Duration{Duration: 10 * time.Minute}, Cooldown: config.Duration{Duration: 5 * time.Minute}},
+		Providers: map[string]config.CircuitBreaker{
+			"codex": {Threshold: 0},
+		},
+		Categories: map[string]config.CircuitBreaker{
+			FailureGatewayClosed: {Threshold: 2, Window: config.Duration{Duration: time.Minute}, Cooldown: config.Duration{Duration: time.Minute}},
+		},
+	}
+}
+
+func TestProviderCircuitBreaker(t *testing.T) {
+	rl, clock := bucketLimiter(t)
+	rl.SetCircuitBreakers(testBreakers())
+	providers := map[string]config.Provider{
+		"claude": {Authed: true, Model: "claude", RequestsPerMinute: 60},
+		"codex":  {Authed: false, Model: "gpt"},
+	}
+
+	rl.RecordProviderOutcome("claude", providers["claude"], FailureUnknown)
+	rl.RecordProviderOutcome("claude", providers["claude"], FailureUnknown)
+	// Failures outside the window no longer count.
+	clock.advance(11 * time.Minute)
+	rl.RecordProviderOutcome("claude", providers["claude"], FailureUnknown)
+	if got := pick(rl, providers, "claude", "codex"); got != "claude" {
+		t.Fatalf("expected claude below the threshold, got %q", got)
+	}
+	rl.RecordProviderOutcome("claude", providers["claude"], FailureUnknown)
+	rl.RecordProviderOutcome("claude", providers["claude"], FailureUnknown)
+	if got := pick(rl, providers, "claude", "codex"); got != "codex" {
+		t.Fatalf("expected the open circuit to exclude claude, got %q", got)
+	}
+	budget := rl.ProviderBudget("claude", providers["claude"])
+	if !budget.CircuitOpen(clock.now()) || budget.RecentFailures != 3 {
+		t.Fatalf("unexpected budget %+v", budget)
+	}
+	if budget.RequestsPerMinute != 60 || budget.RequestsRemaining != 59 || budget.TokensRemaining != -1 {
+		t.Fatalf("unexpected remaining budgets %+v", budget)
+	}
+
+	// After the cooldown one trial failure reopens the circuit.
+	clock.advance(5 * time.Minute)
+	if got := pick(rl, providers, "claude", "codex"); got != "claude" {
+		t.Fatalf("expected a trial call after the cooldown, got %q", got)
+	}
+	rl.RecordProviderOutcome("claude", providers["claude"], FailureUnknown)
+	if got := pick(rl, providers, "claude", "codex"); got != "codex" {
+		t.Fatalf("expected the trial failure to reopen the circuit, got %q", got)
+	}
+
+	if !rl.ResetCircuitBreaker(BreakerScopeProvider, "claude") {
+		t.Fatal("expected the provider breaker reset to succeed")
+	}
+	if got := pick(rl, providers, "claude", "codex"); got != "claude" {
+		t.Fatalf("expected claude after a manual reset, got %q", got)
+	}
+
+	// The codex override disables its breaker.
+	for i := 0; i < 10; i++ {
+		rl.RecordProviderOutcome("codex", providers["codex"], FailureUnknown)
+	}
+	if got := pick(rl, providers, "codex"); got != "codex" {
+		t.Fatalf("expected codex with its breaker disabled, got %q", got)
+	}
+}
+
+func TestCategoryCircuitBreaker(t *testing.T) {
+	rl, clock := bucketLimiter(t)
+	rl.SetCircuitBreakers(testBreakers())
+	providers := map[string]config.Provider{
+		"claude": {Authed: false, Model: "claude"},
+		"codex":  {Authed: false, Model: "gpt"},
+	}
+
+	rl.RecordProviderOutcome("claude", providers["claude"], FailureGatewayClosed)
+	rl.RecordProviderOutcome("codex", providers["codex"], FailureTimeout)
+	if got := pick(rl, providers, "claude", "codex"); got != "claude" {
+		t.Fatalf("expected dispatching below the category threshold, got %q", got)
+	}
+	rl.RecordProviderOutcome("codex", providers["codex"], FailureGatewayClosed)
+	if got := pick(rl, providers, "claude", "codex"); got != "" {
+		t.Fatalf("expected the open gateway_closed breaker to stop every provider, got %q", got)
+	}
+
+	statuses := rl.CircuitBreakers(providers)
+	if len(statuses) != 3 {
+		t.Fatalf("expected 1 category and 2 provider breakers, got %+v", statuses)
+	}
+	gateway := statuses[0]
+	if gateway.Scope != BreakerScopeCategory || gateway.Name != FailureGatewayClosed || !gateway.Open || gateway.RecentFailures != 2 {
+		t.Fatalf("unexpected gateway breaker %+v", gateway)
+	}
+	if statuses[1].Name != "claude" || statuses[1].RecentFailures != 1 || statuses[2].Name != "codex" || statuses[2].Threshold != 0 {
+		t.Fatalf("unexpected provider breakers %+v", statuses[1:])
+	}
+
+	// A success after the cooldown settles the category breaker.
+	clock.advance(time.Minute)
+	rl.RecordProviderOutcome("claude", providers["claude"], "")
+	rl.RecordProviderOutcome("claude", providers["claude"], FailureGatewayClosed)
+	if got := pick(rl, providers, "claude", "codex"); got == "" {
+		t.Fatal("expected one failure after recovery to leave the category closed")
+	}
+
+	if rl.ResetCircuitBreaker(BreakerScopeCategory, "auth") {
+		t.Fatal("expected resetting an unconfigured category to fail")
+	}
+	if !rl.ResetCircuitBreaker(BreakerScopeCategory, FailureGatewayClosed) {
+		t.Fatal("expected resetting gateway_closed to succeed")
+	}
+}
+
+func TestClassifyFailure(t *testing.T) {
+	cases := []struct {
+		output string
+		err    error
+		want   string
+	}{
+		{"gateway connect failed: Error: gateway closed (1000):", errors.New("exit status 1"), FailureGatewayClosed},
+		{"API Error: 429 Too Many Requests", errors.New("exit status 1"), FailureRateLimited},
+		{"Invalid API key · Please run /login", errors.New("exit status 1"), FailureAuth},
+		{"", fmt.Errorf("agent: %w", context.DeadlineExceeded), FailureTimeout},
+		{"panic: nil map", errors.New("exit status 2"), FailureUnknown},
+	}
+	for _, tc := range cases {
+		if got := ClassifyFailure(tc.output, tc.err); got != tc.want {
+			t.Errorf("ClassifyFailure(%q, %v) = %q, want %q", tc.output, tc.err, got, tc.want)
+		}
+	}
+}
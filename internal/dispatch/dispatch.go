@@ -8,7 +8,6 @@ import (
 	"strconv"
 	"strings"
 	"sync"
-	"syscall"
 	"time"
 )
 
@@ -382,12 +381,6 @@ func (d *Dispatcher) monitorProcess(pid int) {
 	info.tempFiles = nil
 }
 
-// IsProcessAlive checks whether a process with the given PID is still running.
-func IsProcessAlive(pid int) bool {
-	err := syscall.Kill(pid, 0)
-	return err == nil
-}
-
 // IsAlive implements DispatcherInterface for PID-based dispatching.
 func (d *Dispatcher) IsAlive(handle int) bool {
 	d.mu.RLock()
@@ -480,36 +473,3 @@ func (d *Dispatcher) CleanupProcess(handle int) {
 		delete(d.processes, handle)
 	}
 }
-
-// KillProcess sends SIGTERM, waits 5s, then SIGKILL if still alive.
-func KillProcess(pid int) error {
-	if !IsProcessAlive(pid) {
-		return nil
-	}
-
-	if err := syscall.Kill(pid, syscall.SIGTERM); err != nil {
-		if err == syscall.ESRCH {
-			return nil
-		}
-		return fmt.Errorf("dispatch: send SIGTERM to pid %d: %w", pid, err)
-	}
-
-	deadline := time.Now().Add(5 * time.Second)
-	for time.Now().Before(deadline) {
-		if !IsProcessAlive(pid) {
-			return nil
-		}
-		time.Sleep(100 * time.Millisecond)
-	}
-
-	if IsProcessAlive(pid) {
-		if err := syscall.Kill(pid, syscall.SIGKILL); err != nil {
-			if err == syscall.ESRCH {
-				return nil
-			}
-			return fmt.Errorf("dispatch: send SIGKILL to pid %d: %w", pid, err)
-		}
-	}
-
-	return nil
-}
@@ -8,10 +8,10 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
-	"syscall"
 	"time"
 
 	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/redact"
 )
 
 type headlessProcess struct {
@@ -20,7 +20,10 @@ type headlessProcess struct {
 	exitCode       int
 	completedAt    time.Time
 	logPath        string
+	log            *os.File
+	out            *redact.Writer // redacts output into log
 	tempPromptPath string
+	job            *processJob // nil where the platform signals by PID
 }
 
 // HeadlessBackend runs configured CLIs as background processes with file logs.
@@ -28,6 +31,7 @@ type HeadlessBackend struct {
 	cliConfigs    map[string]config.CLIConfig
 	logDir        string
 	retentionDays int
+	redactor      *redact.Redactor
 
 	mu        sync.RWMutex
 	processes map[int]*headlessProcess
@@ -46,6 +50,15 @@ func NewHeadlessBackend(cliConfigs map[string]config.CLIConfig, logDir string, r
 	}
 }
 
+// SetRedactor sets the redaction pass applied to dispatch output before it
+// reaches the log file. Without one the logs are still scrubbed of
+// registered config secrets.
+func (b *HeadlessBackend) SetRedactor(r *redact.Redactor) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.redactor = r
+}
+
 func (b *HeadlessBackend) Name() string {
 	return "headless_cli"
 }
@@ -86,8 +99,11 @@ func (b *HeadlessBackend) Dispatch(ctx context.Context, opts DispatchOpts) (Hand
 	if strings.TrimSpace(opts.WorkDir) != "" {
 		cmd.Dir = opts.WorkDir
 	}
-	cmd.Stdout = logFile
-	cmd.Stderr = logFile
+	b.mu.RLock()
+	out := redact.NewWriter(logFile, b.redactor)
+	b.mu.RUnlock()
+	cmd.Stdout = out
+	cmd.Stderr = out
 
 	mode := strings.TrimSpace(cliCfg.PromptMode)
 	if mode == "" || mode == "stdin" {
@@ -101,16 +117,21 @@ func (b *HeadlessBackend) Dispatch(ctx context.Context, opts DispatchOpts) (Hand
 		}
 		return Handle{}, fmt.Errorf("headless backend: start command: %w", err)
 	}
-	_ = logFile.Close()
 
 	pid := cmd.Process.Pid
+	// Without a job the dispatch is still killable by PID, only its
+	// children are not, so a job failure does not fail the dispatch.
+	job, _ := newProcessJob(cmd.Process)
 	b.mu.Lock()
 	b.processes[pid] = &headlessProcess{
 		cmd:            cmd,
 		state:          "running",
 		exitCode:       -1,
 		logPath:        logPath,
+		log:            logFile,
+		out:            out,
 		tempPromptPath: tempPromptPath,
+		job:            job,
 	}
 	b.mu.Unlock()
 
@@ -133,6 +154,9 @@ func (b *HeadlessBackend) waitForProcess(pid int) {
 	b.mu.RUnlock()
 
 	err := cmd.Wait()
+	// Wait has copied all output, so what the writer holds back can go.
+	_ = p.out.Close()
+	_ = p.log.Close()
 
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -169,7 +193,7 @@ func (b *HeadlessBackend) Status(handle Handle) (DispatchStatus, error) {
 	p, ok := b.processes[pid]
 	if !ok {
 		b.mu.RUnlock()
-		if syscall.Kill(pid, 0) == nil {
+		if IsProcessAlive(pid) {
 			return DispatchStatus{State: "running", ExitCode: -1}, nil
 		}
 		return DispatchStatus{State: "unknown", ExitCode: -1}, nil
@@ -181,7 +205,7 @@ func (b *HeadlessBackend) Status(handle Handle) (DispatchStatus, error) {
 
 	switch state {
 		case "running":
-			if syscall.Kill(pid, 0) == nil {
+			if IsProcessAlive(pid) {
 				return DispatchStatus{State: "running", ExitCode: -1}, nil
 			}
 			return DispatchStatus{State: "unknown", ExitCode: -1}, nil
@@ -224,6 +248,15 @@ func (b *HeadlessBackend) Kill(handle Handle) error {
 	if handle.PID <= 0 {
 		return nil
 	}
+	b.mu.RLock()
+	var job *processJob
+	if p, ok := b.processes[handle.PID]; ok {
+		job = p.job
+	}
+	b.mu.RUnlock()
+	if job != nil {
+		return job.kill()
+	}
 	return KillProcess(handle.PID)
 }
 
@@ -235,11 +268,13 @@ func (b *HeadlessBackend) Cleanup(handle Handle) error {
 
 	var tempPromptPath string
 	var logPath string
+	var job *processJob
 	b.mu.Lock()
 	p, ok := b.processes[pid]
 	if ok {
 		tempPromptPath = p.tempPromptPath
 		logPath = p.logPath
+		job = p.job
 		delete(b.processes, pid)
 	}
 	b.mu.Unlock()
@@ -248,6 +283,8 @@ func (b *HeadlessBackend) Cleanup(handle Handle) error {
 		return nil
 	}
 
+	job.close()
+
 	if tempPromptPath != "" {
 		_ = os.Remove(tempPromptPath)
 	}
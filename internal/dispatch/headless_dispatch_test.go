@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/redact"
 )
 
 func TestHeadlessBackend_DispatchEchoHelloWorld(t *testing.T) {
@@ -77,3 +78,49 @@ func TestHeadlessBackend_DispatchEchoHelloWorld(t *testing.T) {
 		t.Fatalf("Cleanup failed: %v", err)
 	}
 }
+
+func TestHeadlessBackend_RedactsLog(t *testing.T) {
+	t.Parallel()
+
+	backend := NewHeadlessBackend(
+		map[string]config.CLIConfig{
+			"test": {Cmd: "sh", Args: []string{"-c", "echo token ghp_" + strings.Repeat("a1B2", 9) + "; echo token again >&2"}},
+		},
+		"",
+		0,
+	)
+	r, err := redact.New(config.DispatchRedaction{Enabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.SetRedactor(r)
+
+	handle, err := backend.Dispatch(context.Background(), DispatchOpts{Agent: "test-agent", CLIConfig: "test"})
+	if err != nil {
+		t.Fatalf("Dispatch failed: %v", err)
+	}
+	defer backend.Cleanup(handle)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		status, err := backend.Status(handle)
+		if err != nil {
+			t.Fatalf("Status failed: %v", err)
+		}
+		if status.State == "completed" {
+			break
+		}
+		if status.State != "running" || time.Now().After(deadline) {
+			t.Fatalf("dispatch did not complete: %+v", status)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	output, err := backend.CaptureOutput(handle)
+	if err != nil {
+		t.Fatalf("CaptureOutput failed: %v", err)
+	}
+	if strings.Contains(output, "ghp_") || !strings.Contains(output, "token [REDACTED]") || !strings.Contains(output, "token again") {
+		t.Fatalf("log not redacted: %q", output)
+	}
+}
@@ -0,0 +1,151 @@
+package dispatch
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogCompressAfter is how long a dispatch log goes unmodified before the
+// janitor compresses it.
+const LogCompressAfter = 24 * time.Hour
+
+// LogSweep is the outcome of one pass over the dispatch log directory.
+type LogSweep struct {
+	Compressed int
+	Deleted    int
+	Files      int   // files left in the directory
+	Bytes      int64 // their size on disk
+}
+
+// LogStats is the log directory's usage as of the last sweep, with the
+// totals of every sweep since startup.
+type LogStats struct {
+	Files      int
+	Bytes      int64
+	Compressed int64
+	Deleted    int64
+	LastSweep  time.Time
+}
+
+var (
+	logStatsMu sync.Mutex
+	logStats   LogStats
+)
+
+// SweepLogs enforces retention on the dispatch logs under dir, whichever
+// backend wrote them: logs unmodified for LogCompressAfter are gzipped and
+// logs older than retentionDays are deleted. A retentionDays of zero or less
+// keeps logs forever. Symlinks are deleted when expired but never
+// compressed, since they point at files other processes own.
+func SweepLogs(dir string, retentionDays int, now time.Time) (LogSweep, error) {
+	var sweep LogSweep
+	if strings.TrimSpace(dir) == "" {
+		return sweep, nil
+	}
+	var cutoff time.Time
+	if retentionDays > 0 {
+		cutoff = now.AddDate(0, 0, -retentionDays)
+	}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		switch {
+		case !cutoff.IsZero() && info.ModTime().Before(cutoff):
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("remove log %s: %w", path, err)
+			}
+			sweep.Deleted++
+			return nil
+		case info.Mode().IsRegular() && !strings.HasSuffix(path, ".gz") && now.Sub(info.ModTime()) >= LogCompressAfter:
+			if info, err = compressLog(path, info); err != nil {
+				return err
+			}
+			sweep.Compressed++
+		}
+		sweep.Files++
+		sweep.Bytes += info.Size()
+		return nil
+	})
+	if err != nil {
+		return sweep, fmt.Errorf("sweep dispatch logs: %w", err)
+	}
+
+	logStatsMu.Lock()
+	logStats.Files = sweep.Files
+	logStats.Bytes = sweep.Bytes
+	logStats.Compressed += int64(sweep.Compressed)
+	logStats.Deleted += int64(sweep.Deleted)
+	logStats.LastSweep = now
+	logStatsMu.Unlock()
+	return sweep, nil
+}
+
+// CurrentLogStats returns the dispatch log usage recorded by the last sweep.
+func CurrentLogStats() LogStats {
+	logStatsMu.Lock()
+	defer logStatsMu.Unlock()
+	return logStats
+}
+
+// compressLog replaces path with path.gz, keeping its modification time so
+// retention still counts from when the log was last written.
+func compressLog(path string, info fs.FileInfo) (fs.FileInfo, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open log %s: %w", path, err)
+	}
+	defer src.Close()
+
+	gzPath := path + ".gz"
+	dst, err := os.OpenFile(gzPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return nil, fmt.Errorf("create %s: %w", gzPath, err)
+	}
+	zw := gzip.NewWriter(dst)
+	zw.Name = filepath.Base(path)
+	zw.ModTime = info.ModTime()
+	_, err = io.Copy(zw, src)
+	if cerr := zw.Close(); err == nil {
+		err = cerr
+	}
+	if serr := dst.Sync(); err == nil {
+		err = serr
+	}
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(gzPath)
+		return nil, fmt.Errorf("compress log %s: %w", path, err)
+	}
+
+	if err := os.Chtimes(gzPath, info.ModTime(), info.ModTime()); err != nil {
+		return nil, fmt.Errorf("compress log %s: %w", path, err)
+	}
+	if err := os.Remove(path); err != nil {
+		return nil, fmt.Errorf("remove compressed log %s: %w", path, err)
+	}
+	return os.Stat(gzPath)
+}
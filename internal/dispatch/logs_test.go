@@ -0,0 +1,83 @@
+package dispatch
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSweepLogs(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	write := func(name, body string, age time.Duration) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		mt := now.Add(-age)
+		if err := os.Chtimes(path, mt, mt); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	active := write("dispatch-1-codex.log", "still running\n", time.Minute)
+	old := write("tmux/dispatch-2-claude.log", "finished yesterday\n", 36*time.Hour)
+	expired := write("dispatch-3-codex.log.gz", "gz", 40*24*time.Hour)
+	expiredPlain := write("dispatch-4-codex.log", "old", 31*24*time.Hour)
+
+	sweep, err := SweepLogs(dir, 30, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sweep.Compressed != 1 || sweep.Deleted != 2 || sweep.Files != 2 {
+		t.Fatalf("sweep = %+v", sweep)
+	}
+	for _, path := range []string{old, expired, expiredPlain} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s still exists", path)
+		}
+	}
+	if _, err := os.Stat(active); err != nil {
+		t.Errorf("active log: %v", err)
+	}
+
+	info, err := os.Stat(old + ".gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ModTime().Sub(now.Add(-36*time.Hour)).Abs() > time.Second {
+		t.Errorf("compressed log mtime = %v, want the original", info.ModTime())
+	}
+	f, err := os.Open(old + ".gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(zr)
+	if string(body) != "finished yesterday\n" {
+		t.Errorf("decompressed log = %q", body)
+	}
+
+	stats := CurrentLogStats()
+	if stats.Files != 2 || stats.Bytes != sweep.Bytes || stats.Compressed < 1 || stats.Deleted < 2 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestSweepLogsMissingDir(t *testing.T) {
+	sweep, err := SweepLogs(filepath.Join(t.TempDir(), "missing"), 30, time.Now())
+	if err != nil || sweep.Files != 0 {
+		t.Fatalf("SweepLogs = %+v, %v", sweep, err)
+	}
+}
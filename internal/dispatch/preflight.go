@@ -0,0 +1,80 @@
+package dispatch
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+// ProbeFunc checks that a provider answers at all, with a gateway ping or a
+// one-token completion, before a full agent session is dispatched to it.
+type ProbeFunc func(ctx context.Context, name string, p config.Provider) error
+
+// providerProbe is one provider's cached probe result. Its mu is held while
+// the provider is probed, so concurrent picks share one probe per provider
+// without waiting on the probes of others.
+type providerProbe struct {
+	mu     sync.Mutex
+	probed bool
+	at     time.Time
+	err    error
+}
+
+// SetPreflight sets the pre-flight probe configuration and the probe run
+// before a provider is reserved. Cached results are dropped.
+func (r *RateLimiter) SetPreflight(cfg config.DispatchPreflight, probe ProbeFunc) {
+	r.probeMu.Lock()
+	defer r.probeMu.Unlock()
+	r.preflightCfg = cfg
+	r.probe = probe
+	r.probes = nil
+}
+
+// Preflight reports whether the provider passed its pre-flight probe, for
+// dispatches that run a fixed agent rather than picking a provider.
+func (r *RateLimiter) Preflight(name string, p config.Provider) bool {
+	return r.preflight(name, p)
+}
+
+// preflight reports whether the provider passed its pre-flight probe. A
+// result is reused for the configured TTL, so each provider is probed at most
+// once per TTL however many dispatches consider it. A provider failing its
+// probe is skipped without counting toward its circuit breaker; the first
+// failure after a pass records a provider_probe_failed health event.
+func (r *RateLimiter) preflight(name string, p config.Provider) bool {
+	r.probeMu.Lock()
+	cfg, probe := r.preflightCfg, r.probe
+	if !cfg.Enabled || probe == nil {
+		r.probeMu.Unlock()
+		return true
+	}
+	if r.probes == nil {
+		r.probes = make(map[string]*providerProbe)
+	}
+	pp := r.probes[name]
+	if pp == nil {
+		pp = &providerProbe{}
+		r.probes[name] = pp
+	}
+	r.probeMu.Unlock()
+
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+	if pp.probed && r.now().Sub(pp.at) < cfg.TTL.Duration {
+		return pp.err == nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout.Duration)
+	err := probe(ctx, name, p)
+	cancel()
+	passedBefore := !pp.probed || pp.err == nil
+	pp.probed, pp.at, pp.err = true, r.now(), err
+
+	if err != nil && passedBefore && r.store != nil {
+		_ = r.store.RecordHealthEvent("provider_probe_failed", fmt.Sprintf("provider %s failed its pre-flight probe and is skipped: %v", name, err))
+	}
+	return err == nil
+}
@@ -0,0 +1,110 @@
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+func TestPreflightSkipsDeadProvider(t *testing.T) {
+	rl, clock := bucketLimiter(t)
+	rl.SetCircuitBreakers(testBreakers())
+	providers := map[string]config.Provider{
+		"claude": {Authed: true, Model: "claude"},
+		"codex":  {Authed: false, Model: "gpt"},
+	}
+
+	probes := map[string]int{}
+	dead := map[string]bool{"claude": true}
+	rl.SetPreflight(config.DispatchPreflight{
+		Enabled: true,
+		TTL:     config.Duration{Duration: time.Minute},
+		Timeout: config.Duration{Duration: time.Second},
+	}, func(ctx context.Context, name string, p config.Provider) error {
+		probes[name]++
+		if dead[name] {
+			return errors.New("gateway connect failed")
+		}
+		return nil
+	})
+
+	for i := 0; i < 3; i++ {
+		if got := pick(rl, providers, "claude", "codex"); got != "codex" {
+			t.Fatalf("pick %d = %q, want the dead provider skipped", i, got)
+		}
+	}
+	if probes["claude"] != 1 || probes["codex"] != 1 {
+		t.Fatalf("probes = %v, want one cached probe per provider", probes)
+	}
+	if budget := rl.ProviderBudget("claude", providers["claude"]); budget.RecentFailures != 0 {
+		t.Fatalf("failed probes counted toward the breaker: %+v", budget)
+	}
+	events, err := rl.store.GetRecentHealthEvents(1)
+	if err != nil {
+		t.Fatalf("GetRecentHealthEvents: %v", err)
+	}
+	if len(events) != 1 || events[0].EventType != "provider_probe_failed" {
+		t.Fatalf("events = %+v, want one provider_probe_failed", events)
+	}
+
+	// Once the TTL passes the provider is probed again and used on recovery.
+	dead["claude"] = false
+	clock.advance(time.Minute)
+	if got := pick(rl, providers, "claude", "codex"); got != "claude" {
+		t.Fatalf("expected the recovered provider, got %q", got)
+	}
+	if probes["claude"] != 2 {
+		t.Fatalf("claude probes = %d, want 2", probes["claude"])
+	}
+}
+
+func TestPreflightDisabled(t *testing.T) {
+	rl, _ := bucketLimiter(t)
+	providers := map[string]config.Provider{"claude": {Authed: false, Model: "claude"}}
+	rl.SetPreflight(config.DispatchPreflight{}, func(context.Context, string, config.Provider) error {
+		t.Fatal("probe ran while pre-flight is disabled")
+		return nil
+	})
+	if got := pick(rl, providers, "claude"); got != "claude" {
+		t.Fatalf("pick = %q, want claude", got)
+	}
+}
+
+func TestPreflightProbesProvidersIndependently(t *testing.T) {
+	rl, _ := bucketLimiter(t)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	rl.SetPreflight(config.DispatchPreflight{
+		Enabled: true,
+		TTL:     config.Duration{Duration: time.Minute},
+		Timeout: config.Duration{Duration: 5 * time.Second},
+	}, func(ctx context.Context, name string, p config.Provider) error {
+		if name == "slow" {
+			close(started)
+			<-release
+		}
+		return nil
+	})
+
+	done := make(chan bool)
+	go func() { done <- rl.Preflight("slow", config.Provider{}) }()
+	<-started
+
+	fast := make(chan bool)
+	go func() { fast <- rl.Preflight("fast", config.Provider{}) }()
+	select {
+	case ok := <-fast:
+		if !ok {
+			t.Fatal("fast provider failed its probe")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("probe of one provider waited on another's")
+	}
+	close(release)
+	if !<-done {
+		t.Fatal("slow provider failed its probe")
+	}
+}
@@ -0,0 +1,60 @@
+//go:build unix
+
+package dispatch
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+)
+
+// IsProcessAlive checks whether a process with the given PID is still running.
+func IsProcessAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil
+}
+
+// KillProcess sends SIGTERM, waits 5s, then SIGKILL if still alive.
+func KillProcess(pid int) error {
+	if !IsProcessAlive(pid) {
+		return nil
+	}
+
+	if err := syscall.Kill(pid, syscall.SIGTERM); err != nil {
+		if err == syscall.ESRCH {
+			return nil
+		}
+		return fmt.Errorf("dispatch: send SIGTERM to pid %d: %w", pid, err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if !IsProcessAlive(pid) {
+			return nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	if IsProcessAlive(pid) {
+		if err := syscall.Kill(pid, syscall.SIGKILL); err != nil {
+			if err == syscall.ESRCH {
+				return nil
+			}
+			return fmt.Errorf("dispatch: send SIGKILL to pid %d: %w", pid, err)
+		}
+	}
+
+	return nil
+}
+
+// processJob groups a dispatched process with its children. POSIX dispatches
+// are signalled by PID, so no job is created and callers fall back to
+// KillProcess.
+type processJob struct{}
+
+func newProcessJob(*os.Process) (*processJob, error) { return nil, nil }
+
+func (j *processJob) kill() error { return nil }
+
+func (j *processJob) close() {}
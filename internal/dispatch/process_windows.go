@@ -0,0 +1,110 @@
+//go:build windows
+
+package dispatch
+
+import (
+	"fmt"
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// stillActive is the exit code GetExitCodeProcess reports for a running process.
+const stillActive = 259
+
+// IsProcessAlive checks whether a process with the given PID is still running.
+func IsProcessAlive(pid int) bool {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer windows.CloseHandle(h)
+
+	var code uint32
+	if err := windows.GetExitCodeProcess(h, &code); err != nil {
+		return false
+	}
+	return code == stillActive
+}
+
+// KillProcess terminates the process. Windows has no SIGTERM equivalent for
+// console-less children, so there is no graceful phase.
+func KillProcess(pid int) error {
+	if !IsProcessAlive(pid) {
+		return nil
+	}
+
+	h, err := windows.OpenProcess(windows.PROCESS_TERMINATE, false, uint32(pid))
+	if err != nil {
+		if !IsProcessAlive(pid) {
+			return nil
+		}
+		return fmt.Errorf("dispatch: open pid %d: %w", pid, err)
+	}
+	defer windows.CloseHandle(h)
+
+	if err := windows.TerminateProcess(h, 1); err != nil {
+		return fmt.Errorf("dispatch: terminate pid %d: %w", pid, err)
+	}
+	return nil
+}
+
+// processJob is a Windows job object holding a dispatched process and every
+// child it spawns, playing the role process groups play on POSIX. Closing the
+// job kills anything still running in it.
+type processJob struct {
+	handle windows.Handle
+}
+
+// newProcessJob places p in a new kill-on-close job object. Children the
+// process starts before it is assigned are not captured.
+func newProcessJob(p *os.Process) (*processJob, error) {
+	job, err := windows.CreateJobObject(nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("dispatch: create job object: %w", err)
+	}
+
+	info := windows.JOBOBJECT_EXTENDED_LIMIT_INFORMATION{
+		BasicLimitInformation: windows.JOBOBJECT_BASIC_LIMIT_INFORMATION{
+			LimitFlags: windows.JOB_OBJECT_LIMIT_KILL_ON_JOB_CLOSE,
+		},
+	}
+	if _, err := windows.SetInformationJobObject(job, windows.JobObjectExtendedLimitInformation,
+		uintptr(unsafe.Pointer(&info)), uint32(unsafe.Sizeof(info))); err != nil {
+		windows.CloseHandle(job)
+		return nil, fmt.Errorf("dispatch: configure job object: %w", err)
+	}
+
+	ph, err := windows.OpenProcess(windows.PROCESS_SET_QUOTA|windows.PROCESS_TERMINATE, false, uint32(p.Pid))
+	if err != nil {
+		windows.CloseHandle(job)
+		return nil, fmt.Errorf("dispatch: open pid %d: %w", p.Pid, err)
+	}
+	defer windows.CloseHandle(ph)
+
+	if err := windows.AssignProcessToJobObject(job, ph); err != nil {
+		windows.CloseHandle(job)
+		return nil, fmt.Errorf("dispatch: assign pid %d to job object: %w", p.Pid, err)
+	}
+	return &processJob{handle: job}, nil
+}
+
+// kill terminates every process in the job.
+func (j *processJob) kill() error {
+	if j == nil {
+		return nil
+	}
+	if err := windows.TerminateJobObject(j.handle, 1); err != nil {
+		return fmt.Errorf("dispatch: terminate job object: %w", err)
+	}
+	return nil
+}
+
+func (j *processJob) close() {
+	if j == nil || j.handle == 0 {
+		return
+	}
+	windows.CloseHandle(j.handle)
+	j.handle = 0
+}
@@ -3,16 +3,30 @@ package dispatch
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/antigravity-dev/cortex/internal/config"
 	"github.com/antigravity-dev/cortex/internal/store"
 )
 
-// RateLimiter enforces unified rate limits across all authed providers.
+// RateLimiter enforces unified rate limits across all authed providers,
+// per-provider requests and tokens per minute as token buckets, the provider
+// and failure-category circuit breakers, and the pre-flight probe.
 type RateLimiter struct {
-	store *store.Store
-	cfg   config.RateLimits
-	mu    sync.Mutex
+	store      *store.Store
+	cfg        config.RateLimits
+	breakerCfg config.DispatchCircuitBreakers
+	mu         sync.Mutex
+	buckets    map[string]*providerBuckets
+	categories map[string]*circuitBreaker
+	now        func() time.Time
+
+	// probeMu guards the pre-flight configuration and the per-provider
+	// probe entries; it is never held while a probe runs.
+	probeMu      sync.Mutex
+	preflightCfg config.DispatchPreflight
+	probe        ProbeFunc
+	probes       map[string]*providerProbe
 }
 
 // SetConfig swaps the in-memory rate limit configuration.
@@ -32,7 +46,7 @@ const (
 
 // NewRateLimiter creates a new rate limiter backed by the given store.
 func NewRateLimiter(s *store.Store, cfg config.RateLimits) *RateLimiter {
-	return &RateLimiter{store: s, cfg: cfg}
+	return &RateLimiter{store: s, cfg: cfg, now: time.Now}
 }
 
 // CanDispatchAuthed checks both the 5h rolling window and weekly cap.
@@ -149,15 +163,13 @@ func (r *RateLimiter) PickAndReserveProviderFromCandidates(
 }
 
 // pickAndReserveFromCandidates is the core implementation used by both public methods.
+// Candidates are probed outside r.mu, since a pre-flight probe can take seconds.
 func (r *RateLimiter) pickAndReserveFromCandidates(
 	candidates []string,
 	providers map[string]config.Provider,
 	excludeModels map[string]bool,
 	agentID, beadID string,
 ) (*config.Provider, string, int64, func(), error) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
 	for _, name := range candidates {
 		p, ok := providers[name]
 		if !ok {
@@ -169,31 +181,119 @@ func (r *RateLimiter) pickAndReserveFromCandidates(
 			continue
 		}
 
-		// Free-tier providers bypass rate limits
-		if !p.Authed {
-			return &p, name, 0, nil, nil
+		available, held := r.candidateAvailable(name, p)
+		if held {
+			return nil, "", 0, nil, nil
 		}
-
-		// Check authed gates (optimistic check)
-		usageID, reserveResult, err := r.recordAuthedDispatchLocked(p.Model, agentID, beadID)
-		if err != nil {
-			if reserveResult == dispatchReservePostLimit {
-				return nil, "", 0, nil, err
-			}
-			// Continue to next provider on pre-limit or transient store errors.
+		if !available || !r.preflight(name, p) {
 			continue
 		}
 
-		// Success with reservation
-		cleanup := func() {
-			_ = r.ReleaseAuthedDispatch(usageID)
+		usageID, cleanup, reserved, err := r.reserve(name, p, agentID, beadID)
+		if err != nil {
+			return nil, "", 0, nil, err
+		}
+		if reserved {
+			return &p, name, usageID, cleanup, nil
 		}
-		return &p, name, usageID, cleanup, nil
 	}
 
 	return nil, "", 0, nil, nil
 }
 
+// candidateAvailable reports whether the provider's per-minute budgets and
+// breaker allow a dispatch, and whether an open failure-category breaker
+// holds back every provider.
+func (r *RateLimiter) candidateAvailable(name string, p config.Provider) (available, held bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.openCategoryLocked(r.now()) != "" {
+		return false, true
+	}
+	return r.providerAvailableLocked(name, p), false
+}
+
+// reserve takes a request from the provider's budget and, for an authed
+// provider, reserves a dispatch. reserved is false when the provider is no
+// longer available or its reservation hit a pre-limit; err is set only when
+// the dispatch caps are exhausted. Call cleanup if the dispatch fails.
+func (r *RateLimiter) reserve(name string, p config.Provider, agentID, beadID string) (usageID int64, cleanup func(), reserved bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Budgets may have been spent while the provider was being probed
+	if r.openCategoryLocked(r.now()) != "" || !r.providerAvailableLocked(name, p) {
+		return 0, nil, false, nil
+	}
+
+	// Free-tier providers bypass the dispatch caps
+	if !p.Authed {
+		r.takeProviderRequestLocked(name, p)
+		return 0, nil, true, nil
+	}
+
+	// Check authed gates (optimistic check)
+	usageID, reserveResult, err := r.recordAuthedDispatchLocked(p.Model, agentID, beadID)
+	if err != nil {
+		if reserveResult == dispatchReservePostLimit {
+			return 0, nil, false, err
+		}
+		// Continue to next provider on pre-limit or transient store errors.
+		return 0, nil, false, nil
+	}
+
+	// Success with reservation
+	r.takeProviderRequestLocked(name, p)
+	return usageID, func() {
+		_ = r.ReleaseAuthedDispatch(usageID)
+	}, true, nil
+}
+
+// PairReservation holds both provider reservations of a pair dispatch: one
+// for the coder and one for the reviewer, even when they share a provider.
+type PairReservation struct {
+	Coder           string
+	Reviewer        string
+	CoderUsageID    int64
+	ReviewerUsageID int64
+}
+
+// PickAndReservePair reserves a provider for each side of a pair dispatch.
+// Either both reservations are kept or neither is: a nil reservation means no
+// provider was available for one side. Call cleanup if the dispatch fails.
+func (r *RateLimiter) PickAndReservePair(
+	coderCandidates, reviewerCandidates []string,
+	providers map[string]config.Provider,
+	agentID, beadID string,
+) (*PairReservation, func(), error) {
+	coder, coderName, coderUsage, coderCleanup, err := r.pickAndReserveFromCandidates(coderCandidates, providers, nil, agentID, beadID)
+	if err != nil || coder == nil {
+		return nil, nil, err
+	}
+	reviewer, reviewerName, reviewerUsage, reviewerCleanup, err := r.pickAndReserveFromCandidates(reviewerCandidates, providers, nil, agentID, beadID)
+	if err != nil || reviewer == nil {
+		if coderCleanup != nil {
+			coderCleanup()
+		}
+		return nil, nil, err
+	}
+
+	cleanup := func() {
+		if coderCleanup != nil {
+			coderCleanup()
+		}
+		if reviewerCleanup != nil {
+			reviewerCleanup()
+		}
+	}
+	return &PairReservation{
+		Coder:           coderName,
+		Reviewer:        reviewerName,
+		CoderUsageID:    coderUsage,
+		ReviewerUsageID: reviewerUsage,
+	}, cleanup, nil
+}
+
 // PickProvider selects a provider from the given tier, respecting rate limits.
 // Returns nil if no provider is available (caller should handle tier downgrade).
 // DEPRECATED: Use PickAndReserveProvider instead.
@@ -371,3 +371,44 @@ func TestPickAndReserveProviderFromCandidates_EmptyCandidates(t *testing.T) {
 		t.Error("should not return cleanup for empty candidates")
 	}
 }
+
+func TestPickAndReservePair_ReservesBothSides(t *testing.T) {
+	s := tempStore(t)
+	rl := NewRateLimiter(s, config.RateLimits{Window5hCap: 10, WeeklyCap: 100})
+
+	res, cleanup, err := rl.PickAndReservePair([]string{"claude-max20"}, []string{"claude-max20"}, testProviders(), "pair", "bead-1")
+	if err != nil {
+		t.Fatalf("PickAndReservePair: %v", err)
+	}
+	if res == nil || res.Coder != "claude-max20" || res.Reviewer != "claude-max20" {
+		t.Fatalf("unexpected reservation: %+v", res)
+	}
+	if res.CoderUsageID == 0 || res.ReviewerUsageID == 0 || res.CoderUsageID == res.ReviewerUsageID {
+		t.Fatalf("expected two distinct usage rows, got %+v", res)
+	}
+	if count, _ := s.CountAuthedUsage5h(); count != 2 {
+		t.Fatalf("expected 2 reservations, got %d", count)
+	}
+
+	cleanup()
+	if count, _ := s.CountAuthedUsage5h(); count != 0 {
+		t.Fatalf("expected cleanup to release both reservations, got %d", count)
+	}
+}
+
+func TestPickAndReservePair_RollsBackWhenReviewerUnavailable(t *testing.T) {
+	s := tempStore(t)
+	// The reviewer's reservation overshoots the cap after the coder's succeeds.
+	rl := NewRateLimiter(s, config.RateLimits{Window5hCap: 2, WeeklyCap: 100})
+
+	res, cleanup, err := rl.PickAndReservePair([]string{"claude-max20"}, []string{"google-pro"}, testProviders(), "pair", "bead-1")
+	if err == nil {
+		t.Fatal("expected rate limit error for reviewer reservation")
+	}
+	if res != nil || cleanup != nil {
+		t.Fatalf("expected no reservation, got %+v", res)
+	}
+	if count, _ := s.CountAuthedUsage5h(); count != 0 {
+		t.Fatalf("coder reservation should be rolled back, got %d rows", count)
+	}
+}
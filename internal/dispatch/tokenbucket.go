@@ -0,0 +1,316 @@
+package dispatch
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+// tokenBucket refills at perMinute/60 per second up to perMinute. Its level
+// may go negative when usage is only known after the fact (tokens), which
+// holds the provider back until the debt is repaid.
+type tokenBucket struct {
+	perMinute float64
+	level     float64
+	updated   time.Time
+}
+
+func newTokenBucket(perMinute int, now time.Time) *tokenBucket {
+	return &tokenBucket{perMinute: float64(perMinute), level: float64(perMinute), updated: now}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+		b.level = min(b.perMinute, b.level+elapsed*b.perMinute/60)
+	}
+	b.updated = now
+}
+
+// available reports whether at least one unit can be taken now.
+func (b *tokenBucket) available(now time.Time) bool {
+	b.refill(now)
+	return b.level >= 1
+}
+
+// wait returns how long until one unit can be taken.
+func (b *tokenBucket) wait(now time.Time) time.Duration {
+	if b.available(now) {
+		return 0
+	}
+	return time.Duration((1 - b.level) * 60 / b.perMinute * float64(time.Second))
+}
+
+func (b *tokenBucket) take(now time.Time, n float64) {
+	b.refill(now)
+	b.level -= n
+}
+
+// clamp lowers the level to what the provider reports as remaining.
+func (b *tokenBucket) clamp(now time.Time, remaining int) {
+	b.refill(now)
+	b.level = min(b.level, float64(remaining))
+}
+
+// providerBuckets holds one provider's per-minute request and token budgets;
+// a nil bucket means that dimension is unlimited. blockedUntil comes from
+// provider feedback and applies whether or not limits are configured, as does
+// its circuit breaker.
+type providerBuckets struct {
+	requests     *tokenBucket
+	tokens       *tokenBucket
+	blockedUntil time.Time
+	breaker      circuitBreaker
+}
+
+func (b *providerBuckets) blockFor(now time.Time, d time.Duration) {
+	if until := now.Add(d); d > 0 && until.After(b.blockedUntil) {
+		b.blockedUntil = until
+	}
+}
+
+// RateLimitFeedback is what a provider reported about its own limits in
+// rate-limit response headers. Counts are -1 when not reported.
+type RateLimitFeedback struct {
+	RemainingRequests int
+	RemainingTokens   int
+	RequestsReset     time.Duration
+	TokensReset       time.Duration
+	RetryAfter        time.Duration
+}
+
+// Empty reports whether no rate-limit headers were found.
+func (f RateLimitFeedback) Empty() bool {
+	return f == RateLimitFeedback{RemainingRequests: -1, RemainingTokens: -1}
+}
+
+var rateLimitHeaderPattern = regexp.MustCompile(`(?im)\b(x-ratelimit-remaining-requests|x-ratelimit-remaining-tokens|x-ratelimit-reset-requests|x-ratelimit-reset-tokens|anthropic-ratelimit-requests-remaining|anthropic-ratelimit-tokens-remaining|anthropic-ratelimit-requests-reset|anthropic-ratelimit-tokens-reset|retry-after)"?\s*[:=]\s*"?([^\s",;)}]+)`)
+
+// ParseRateLimitHeaders extracts OpenAI-style (x-ratelimit-*), Anthropic-style
+// (anthropic-ratelimit-*) and retry-after headers from dispatch output, as
+// printed by CLIs in verbose or error output. The last value of each header
+// wins.
+func ParseRateLimitHeaders(output string, now time.Time) RateLimitFeedback {
+	f := RateLimitFeedback{RemainingRequests: -1, RemainingTokens: -1}
+	for _, m := range rateLimitHeaderPattern.FindAllStringSubmatch(output, -1) {
+		name, value := strings.ToLower(m[1]), m[2]
+		switch name {
+		case "x-ratelimit-remaining-requests", "anthropic-ratelimit-requests-remaining":
+			if n, err := strconv.Atoi(value); err == nil {
+				f.RemainingRequests = n
+			}
+		case "x-ratelimit-remaining-tokens", "anthropic-ratelimit-tokens-remaining":
+			if n, err := strconv.Atoi(value); err == nil {
+				f.RemainingTokens = n
+			}
+		case "x-ratelimit-reset-requests", "anthropic-ratelimit-requests-reset":
+			f.RequestsReset = parseResetValue(value, now)
+		case "x-ratelimit-reset-tokens", "anthropic-ratelimit-tokens-reset":
+			f.TokensReset = parseResetValue(value, now)
+		case "retry-after":
+			f.RetryAfter = parseResetValue(value, now)
+		}
+	}
+	return f
+}
+
+// parseResetValue accepts seconds ("30"), Go durations ("1m30s", "6ms") and
+// RFC 3339 timestamps, returning the wait from now.
+func parseResetValue(value string, now time.Time) time.Duration {
+	if secs, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Duration(secs * float64(time.Second))
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return d
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// bucketsLocked returns the provider's buckets, creating them on first use
+// and resizing them when the configured limits change. Caller holds r.mu.
+func (r *RateLimiter) bucketsLocked(name string, p config.Provider) *providerBuckets {
+	if r.buckets == nil {
+		r.buckets = make(map[string]*providerBuckets)
+	}
+	now := r.now()
+	b, ok := r.buckets[name]
+	if !ok {
+		b = &providerBuckets{}
+		r.buckets[name] = b
+	}
+	b.requests = resizeBucket(b.requests, p.RequestsPerMinute, now)
+	b.tokens = resizeBucket(b.tokens, p.TokensPerMinute, now)
+	b.breaker.cfg = r.breakerCfg.ForProvider(name)
+	return b
+}
+
+func resizeBucket(b *tokenBucket, perMinute int, now time.Time) *tokenBucket {
+	switch {
+	case perMinute <= 0:
+		return nil
+	case b == nil:
+		return newTokenBucket(perMinute, now)
+	default:
+		b.refill(now)
+		b.perMinute = float64(perMinute)
+		b.level = min(b.level, b.perMinute)
+		return b
+	}
+}
+
+// providerAvailableLocked reports whether the provider's per-minute budgets
+// allow another request. Caller holds r.mu.
+func (r *RateLimiter) providerAvailableLocked(name string, p config.Provider) bool {
+	b := r.bucketsLocked(name, p)
+	now := r.now()
+	if now.Before(b.blockedUntil) || b.breaker.open(now) {
+		return false
+	}
+	if b.requests != nil && !b.requests.available(now) {
+		return false
+	}
+	if b.tokens != nil && !b.tokens.available(now) {
+		return false
+	}
+	return true
+}
+
+// ProviderWait returns how long until the first of names has budget again
+// once a retry-after or a spent per-minute budget passes. ok is false when
+// none of them can be waited for: an open circuit breaker holds every one.
+func (r *RateLimiter) ProviderWait(names []string, providers map[string]config.Provider) (wait time.Duration, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	if r.openCategoryLocked(now) != "" {
+		return 0, false
+	}
+	for _, name := range names {
+		p, known := providers[name]
+		if !known {
+			continue
+		}
+		b := r.bucketsLocked(name, p)
+		if b.breaker.open(now) {
+			continue
+		}
+		d := max(b.blockedUntil.Sub(now), 0)
+		if b.requests != nil {
+			d = max(d, b.requests.wait(now))
+		}
+		if b.tokens != nil {
+			d = max(d, b.tokens.wait(now))
+		}
+		if !ok || d < wait {
+			wait, ok = d, true
+		}
+	}
+	return wait, ok
+}
+
+// takeProviderRequestLocked spends one request from the provider's budget.
+// Caller holds r.mu.
+func (r *RateLimiter) takeProviderRequestLocked(name string, p config.Provider) {
+	if b := r.bucketsLocked(name, p); b.requests != nil {
+		b.requests.take(r.now(), 1)
+	}
+}
+
+// RecordProviderTokens charges tokens used by a finished call to the
+// provider's per-minute token budget.
+func (r *RateLimiter) RecordProviderTokens(name string, p config.Provider, tokens int) {
+	if tokens <= 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if b := r.bucketsLocked(name, p); b.tokens != nil {
+		b.tokens.take(r.now(), float64(tokens))
+	}
+}
+
+// ObserveProviderOutput adapts the provider's budgets to the rate-limit
+// headers found in a call's output, so Cortex slows down before the provider
+// starts answering 429. A retry-after blocks the provider entirely until it
+// passes. It returns the parsed feedback.
+func (r *RateLimiter) ObserveProviderOutput(name string, p config.Provider, output string) RateLimitFeedback {
+	now := r.now()
+	f := ParseRateLimitHeaders(output, now)
+	if f.Empty() {
+		return f
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b := r.bucketsLocked(name, p)
+	if f.RemainingRequests >= 0 {
+		if b.requests != nil {
+			b.requests.clamp(now, f.RemainingRequests)
+		}
+		if f.RemainingRequests == 0 {
+			b.blockFor(now, f.RequestsReset)
+		}
+	}
+	if f.RemainingTokens >= 0 {
+		if b.tokens != nil {
+			b.tokens.clamp(now, f.RemainingTokens)
+		}
+		if f.RemainingTokens == 0 {
+			b.blockFor(now, f.TokensReset)
+		}
+	}
+	b.blockFor(now, f.RetryAfter)
+	return f
+}
+
+// ProviderBudget is a snapshot of one provider's per-minute budgets and
+// circuit breaker. Remaining counts are -1 when that dimension is unlimited.
+type ProviderBudget struct {
+	RequestsPerMinute int       `json:"requests_per_minute"`
+	RequestsRemaining int       `json:"requests_remaining"`
+	TokensPerMinute   int       `json:"tokens_per_minute"`
+	TokensRemaining   int       `json:"tokens_remaining"`
+	BlockedUntil      time.Time `json:"blocked_until,omitzero"`
+	RecentFailures    int       `json:"recent_failures"`
+	CircuitOpenUntil  time.Time `json:"circuit_open_until,omitzero"`
+}
+
+// CircuitOpen reports whether the circuit breaker excludes the provider at now.
+func (b ProviderBudget) CircuitOpen(now time.Time) bool {
+	return now.Before(b.CircuitOpenUntil)
+}
+
+// ProviderBudget returns the provider's current budgets after refilling them.
+func (r *RateLimiter) ProviderBudget(name string, p config.Provider) ProviderBudget {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b := r.bucketsLocked(name, p)
+	now := r.now()
+	budget := ProviderBudget{
+		RequestsPerMinute: p.RequestsPerMinute,
+		RequestsRemaining: -1,
+		TokensPerMinute:   p.TokensPerMinute,
+		TokensRemaining:   -1,
+	}
+	if now.Before(b.blockedUntil) {
+		budget.BlockedUntil = b.blockedUntil
+	}
+	breaker := b.breaker.status(BreakerScopeProvider, name, now)
+	budget.RecentFailures = breaker.RecentFailures
+	budget.CircuitOpenUntil = breaker.OpenUntil
+	if b.requests != nil {
+		b.requests.refill(now)
+		budget.RequestsRemaining = max(0, int(b.requests.level))
+	}
+	if b.tokens != nil {
+		b.tokens.refill(now)
+		budget.TokensRemaining = max(0, int(b.tokens.level))
+	}
+	return budget
+}
@@ -0,0 +1,115 @@
+package dispatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+// fakeClock lets a test move the rate limiter's time forward.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func bucketLimiter(t *testing.T) (*RateLimiter, *fakeClock) {
+	t.Helper()
+	clock := &fakeClock{t: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)}
+	rl := NewRateLimiter(tempStore(t), config.RateLimits{Window5hCap: 100, WeeklyCap: 1000})
+	rl.now = clock.now
+	return rl, clock
+}
+
+func pick(rl *RateLimiter, providers map[string]config.Provider, candidates ...string) string {
+	_, name, _, _, _ := rl.PickAndReserveProviderFromCandidates(candidates, providers, nil, "agent", "bead")
+	return name
+}
+
+func TestProviderRequestsPerMinute(t *testing.T) {
+	rl, clock := bucketLimiter(t)
+	providers := map[string]config.Provider{
+		"claude": {Authed: true, Model: "claude", RequestsPerMinute: 2},
+		"codex":  {Authed: false, Model: "gpt"},
+	}
+
+	for i := 0; i < 2; i++ {
+		if got := pick(rl, providers, "claude", "codex"); got != "claude" {
+			t.Fatalf("pick %d = %q, want claude", i, got)
+		}
+	}
+	// The burst is spent: the next pick falls through to the next candidate.
+	if got := pick(rl, providers, "claude", "codex"); got != "codex" {
+		t.Fatalf("expected claude to be throttled, got %q", got)
+	}
+	// One request refills every 30s at 2/min.
+	clock.advance(30 * time.Second)
+	if got := pick(rl, providers, "claude", "codex"); got != "claude" {
+		t.Fatalf("expected claude after refill, got %q", got)
+	}
+}
+
+func TestProviderTokensPerMinute(t *testing.T) {
+	rl, clock := bucketLimiter(t)
+	p := config.Provider{Authed: false, Model: "llama", TokensPerMinute: 60000}
+	providers := map[string]config.Provider{"cerebras": p}
+
+	rl.RecordProviderTokens("cerebras", p, 90000)
+	if got := pick(rl, providers, "cerebras"); got != "" {
+		t.Fatalf("expected a token debt to block, got %q", got)
+	}
+	// 30s repays 30000 of the 30000 debt but leaves no budget; one more second does.
+	clock.advance(31 * time.Second)
+	if got := pick(rl, providers, "cerebras"); got != "cerebras" {
+		t.Fatalf("expected cerebras once the debt is repaid, got %q", got)
+	}
+}
+
+func TestObserveProviderOutput(t *testing.T) {
+	rl, clock := bucketLimiter(t)
+	p := config.Provider{Authed: false, Model: "gpt", RequestsPerMinute: 100}
+	providers := map[string]config.Provider{"codex": p}
+
+	f := rl.ObserveProviderOutput("codex", p, "HTTP 200\nx-ratelimit-remaining-requests: 1\nx-ratelimit-reset-requests: 6ms\n")
+	if f.RemainingRequests != 1 || f.RequestsReset != 6*time.Millisecond {
+		t.Fatalf("unexpected feedback %+v", f)
+	}
+	if got := pick(rl, providers, "codex"); got != "codex" {
+		t.Fatalf("expected the one remaining request, got %q", got)
+	}
+	if got := pick(rl, providers, "codex"); got != "" {
+		t.Fatalf("expected the bucket to be clamped to the provider's remaining count, got %q", got)
+	}
+
+	// A 429 with retry-after blocks even providers without configured limits.
+	free := config.Provider{Authed: false, Model: "llama"}
+	providers["groq"] = free
+	rl.ObserveProviderOutput("groq", free, "error: 429 Too Many Requests (retry-after: 20)")
+	if got := pick(rl, providers, "groq"); got != "" {
+		t.Fatalf("expected groq to be blocked, got %q", got)
+	}
+	if wait, ok := rl.ProviderWait([]string{"groq"}, providers); !ok || wait != 20*time.Second {
+		t.Fatalf("expected a 20s wait for groq, got %v (%v)", wait, ok)
+	}
+	clock.advance(21 * time.Second)
+	if wait, ok := rl.ProviderWait([]string{"groq"}, providers); !ok || wait != 0 {
+		t.Fatalf("expected groq to be free after retry-after, got %v (%v)", wait, ok)
+	}
+	if got := pick(rl, providers, "groq"); got != "groq" {
+		t.Fatalf("expected groq after retry-after, got %q", got)
+	}
+}
+
+func TestParseRateLimitHeaders(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	out := `{"headers":{"anthropic-ratelimit-requests-remaining":"40","anthropic-ratelimit-tokens-remaining":"0","anthropic-ratelimit-tokens-reset":"2026-03-02T09:00:45Z"}}
+Retry-After: 1.5`
+	f := ParseRateLimitHeaders(out, now)
+	want := RateLimitFeedback{RemainingRequests: 40, RemainingTokens: 0, TokensReset: 45 * time.Second, RetryAfter: 1500 * time.Millisecond}
+	if f != want {
+		t.Fatalf("ParseRateLimitHeaders = %+v, want %+v", f, want)
+	}
+	if f := ParseRateLimitHeaders("all good", now); !f.Empty() {
+		t.Fatalf("expected no feedback, got %+v", f)
+	}
+}
@@ -0,0 +1,147 @@
+package dispatch
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/git"
+)
+
+// ErrWorktreePoolFull is returned by Acquire when every worktree of the
+// project is in use.
+var ErrWorktreePoolFull = errors.New("worktree pool full")
+
+// WorktreeLease is a pooled worktree held by one dispatch.
+type WorktreeLease struct {
+	Project string `json:"project"`
+	Slot    int    `json:"slot"`
+	Path    string `json:"path"`
+	Branch  string `json:"branch,omitempty"`
+	BeadID  string `json:"bead_id"`
+}
+
+// WorktreePool hands out per-project git worktrees so concurrent dispatches
+// of one project never share a checkout. Worktrees live at
+// <dir>/<project>/<slot> and are kept between dispatches; every checkout
+// starts from a reset tree. A leased slot is marked by a lock file,
+// <dir>/<project>/<slot>.lock holding the bead's ID, so leases survive a
+// restart and are shared by every worker using the same dir.
+type WorktreePool struct {
+	dir  string
+	size int
+	mu   sync.Mutex
+}
+
+// NewWorktreePool creates a pool of size worktrees per project under dir.
+func NewWorktreePool(cfg config.DispatchWorktrees) *WorktreePool {
+	return &WorktreePool{dir: cfg.Dir, size: cfg.PoolSize}
+}
+
+// Acquire reserves a free worktree of project and checks branch out in it
+// (detached at base when branch is empty). A bead that already holds a
+// worktree gets the same one back. It returns ErrWorktreePoolFull when no
+// worktree is free.
+func (p *WorktreePool) Acquire(project, workspace, beadID, branch, base string) (*WorktreeLease, error) {
+	slot, err := p.reserve(project, beadID)
+	if err != nil {
+		return nil, err
+	}
+	lease := &WorktreeLease{
+		Project: project,
+		Slot:    slot,
+		Path:    filepath.Join(p.dir, project, strconv.Itoa(slot)),
+		Branch:  branch,
+		BeadID:  beadID,
+	}
+	if err := git.CheckoutWorktree(workspace, lease.Path, branch, base); err != nil {
+		p.free(project, slot, beadID)
+		return nil, err
+	}
+	return lease, nil
+}
+
+func (p *WorktreePool) lockPath(project string, slot int) string {
+	return filepath.Join(p.dir, project, strconv.Itoa(slot)+".lock")
+}
+
+// holder returns the bead holding slot, or "" when it is free.
+func (p *WorktreePool) holder(project string, slot int) string {
+	data, err := os.ReadFile(p.lockPath(project, slot))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func (p *WorktreePool) reserve(project, beadID string) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Join(p.dir, project), 0o755); err != nil {
+		return 0, fmt.Errorf("create worktree pool dir: %w", err)
+	}
+	for slot := 0; slot < p.size; slot++ {
+		if p.holder(project, slot) == beadID {
+			return slot, nil
+		}
+	}
+	for slot := 0; slot < p.size; slot++ {
+		// O_EXCL makes taking the lock atomic across workers.
+		f, err := os.OpenFile(p.lockPath(project, slot), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("lock worktree %s/%d: %w", project, slot, err)
+		}
+		_, err = f.WriteString(beadID + "\n")
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			_ = os.Remove(p.lockPath(project, slot))
+			return 0, fmt.Errorf("lock worktree %s/%d: %w", project, slot, err)
+		}
+		return slot, nil
+	}
+	return 0, fmt.Errorf("%w: %s has %d worktrees in use", ErrWorktreePoolFull, project, p.size)
+}
+
+// free releases slot if beadID still holds it, so a repeated release cannot
+// free a slot another bead has since taken.
+func (p *WorktreePool) free(project string, slot int, beadID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.holder(project, slot) == beadID {
+		_ = os.Remove(p.lockPath(project, slot))
+	}
+}
+
+// Release reclaims a leased worktree: uncommitted changes are discarded, the
+// branch is detached so other worktrees may check it out, and the slot is
+// freed. The slot is freed even when the reset fails, since the next
+// Acquire resets the tree again.
+func (p *WorktreePool) Release(lease WorktreeLease) error {
+	defer p.free(lease.Project, lease.Slot, lease.BeadID)
+	return git.ResetWorktree(lease.Path)
+}
+
+// InUse returns how many worktrees of project are leased.
+func (p *WorktreePool) InUse(project string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := 0
+	for slot := 0; slot < p.size; slot++ {
+		if p.holder(project, slot) != "" {
+			n++
+		}
+	}
+	return n
+}
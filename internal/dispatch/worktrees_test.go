@@ -0,0 +1,70 @@
+package dispatch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+func TestWorktreePoolReserve(t *testing.T) {
+	p := NewWorktreePool(config.DispatchWorktrees{Dir: t.TempDir(), PoolSize: 2})
+
+	a, err := p.reserve("proj", "bead-a")
+	if err != nil {
+		t.Fatalf("reserve a: %v", err)
+	}
+	b, err := p.reserve("proj", "bead-b")
+	if err != nil {
+		t.Fatalf("reserve b: %v", err)
+	}
+	if a == b {
+		t.Fatalf("two beads share slot %d", a)
+	}
+	if again, err := p.reserve("proj", "bead-a"); err != nil || again != a {
+		t.Fatalf("re-reserve a = %d, %v; want %d", again, err, a)
+	}
+	if _, err := p.reserve("proj", "bead-c"); !errors.Is(err, ErrWorktreePoolFull) {
+		t.Fatalf("reserve c err = %v, want ErrWorktreePoolFull", err)
+	}
+	if _, err := p.reserve("other", "bead-c"); err != nil {
+		t.Fatalf("pools are per project, got %v", err)
+	}
+
+	// Only the holder frees a slot.
+	p.free("proj", a, "bead-c")
+	if got := p.InUse("proj"); got != 2 {
+		t.Fatalf("InUse = %d after foreign free, want 2", got)
+	}
+	p.free("proj", a, "bead-a")
+	if got := p.InUse("proj"); got != 1 {
+		t.Fatalf("InUse = %d after free, want 1", got)
+	}
+	if c, err := p.reserve("proj", "bead-c"); err != nil || c != a {
+		t.Fatalf("reserve c = %d, %v; want freed slot %d", c, err, a)
+	}
+}
+
+func TestWorktreePoolLeasesSurviveRestart(t *testing.T) {
+	cfg := config.DispatchWorktrees{Dir: t.TempDir(), PoolSize: 1}
+	slot, err := NewWorktreePool(cfg).reserve("proj", "bead-a")
+	if err != nil {
+		t.Fatalf("reserve a: %v", err)
+	}
+
+	// A new pool over the same dir, as after a restart or on another worker.
+	restarted := NewWorktreePool(cfg)
+	if got := restarted.InUse("proj"); got != 1 {
+		t.Fatalf("InUse = %d after restart, want 1", got)
+	}
+	if _, err := restarted.reserve("proj", "bead-b"); !errors.Is(err, ErrWorktreePoolFull) {
+		t.Fatalf("reserve b err = %v, want ErrWorktreePoolFull", err)
+	}
+	if again, err := restarted.reserve("proj", "bead-a"); err != nil || again != slot {
+		t.Fatalf("re-reserve a = %d, %v; want %d", again, err, slot)
+	}
+	restarted.free("proj", slot, "bead-a")
+	if _, err := NewWorktreePool(cfg).reserve("proj", "bead-b"); err != nil {
+		t.Fatalf("reserve b after release: %v", err)
+	}
+}
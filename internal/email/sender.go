@@ -0,0 +1,211 @@
+// Package email delivers reporter messages over SMTP for teams that do not
+// run chat ops.
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net"
+	"net/smtp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+const maxSubjectLen = 78
+
+// Sender sends reports by email. It implements the same SendMessage method as
+// the Matrix senders; the room is an email route from config.ResolveRoom,
+// resolved to recipients by the config. With a batch interval, messages are
+// queued per route and sent as one digest email per interval by Run.
+type Sender struct {
+	cfg        config.ReporterEmail
+	recipients func(route string) []string
+	send       func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+	now        func() time.Time
+
+	mu      sync.Mutex
+	pending map[string][]string // route -> queued messages, oldest first
+}
+
+// NewSender constructs an SMTP sender. recipients maps a route to addresses,
+// normally cfg.EmailRecipients.
+func NewSender(cfg config.ReporterEmail, recipients func(route string) []string) *Sender {
+	s := &Sender{
+		cfg:        cfg,
+		recipients: recipients,
+		now:        time.Now,
+		pending:    make(map[string][]string),
+	}
+	s.send = s.sendSMTP
+	return s
+}
+
+// SendMessage emails a report to the route's recipients, or queues it for the
+// next digest when batching is enabled.
+func (s *Sender) SendMessage(_ context.Context, route, message string) error {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return fmt.Errorf("message is required")
+	}
+	if len(s.recipients(route)) == 0 {
+		return fmt.Errorf("no email recipients for %q", route)
+	}
+	if s.cfg.BatchInterval.Duration <= 0 {
+		return s.deliver(route, s.subject(route, firstLine(message)), message)
+	}
+
+	s.mu.Lock()
+	s.pending[route] = append(s.pending[route], message)
+	s.mu.Unlock()
+	return nil
+}
+
+// Run flushes queued messages every batch interval until ctx is cancelled,
+// then flushes what is left. It returns at once when batching is disabled.
+func (s *Sender) Run(ctx context.Context, logger *slog.Logger) {
+	if s.cfg.BatchInterval.Duration <= 0 {
+		return
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	ticker := time.NewTicker(s.cfg.BatchInterval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if err := s.Flush(); err != nil {
+				logger.Warn("email digest flush failed at shutdown", "error", err)
+			}
+			return
+		case <-ticker.C:
+			if err := s.Flush(); err != nil {
+				logger.Warn("email digest flush failed", "error", err)
+			}
+		}
+	}
+}
+
+// Flush sends one digest email per route with everything queued. A route
+// whose delivery fails keeps its messages for the next flush.
+func (s *Sender) Flush() error {
+	s.mu.Lock()
+	batches := s.pending
+	s.pending = make(map[string][]string)
+	s.mu.Unlock()
+
+	routes := make([]string, 0, len(batches))
+	for route := range batches {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+
+	var errs []error
+	for _, route := range routes {
+		messages := batches[route]
+		subject := s.subject(route, fmt.Sprintf("%d report(s)", len(messages)))
+		if len(messages) == 1 {
+			subject = s.subject(route, firstLine(messages[0]))
+		}
+		if err := s.deliver(route, subject, strings.Join(messages, "\n\n---\n\n")); err != nil {
+			errs = append(errs, err)
+			s.mu.Lock()
+			s.pending[route] = append(messages, s.pending[route]...)
+			s.mu.Unlock()
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Sender) subject(route, summary string) string {
+	project := strings.TrimPrefix(route, config.EmailRoutePrefix)
+	subject := []rune(strings.TrimSpace(s.cfg.SubjectPrefix + " " + project + ": " + summary))
+	if len(subject) > maxSubjectLen {
+		subject = append(subject[:maxSubjectLen-3], []rune("...")...)
+	}
+	return string(subject)
+}
+
+func (s *Sender) deliver(route, subject, body string) error {
+	to := s.recipients(route)
+	if len(to) == 0 {
+		return fmt.Errorf("no email recipients for %q", route)
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", s.cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", s.now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	msg.WriteString("\r\n")
+
+	addr := net.JoinHostPort(s.cfg.SMTPHost, strconv.Itoa(s.cfg.SMTPPort))
+	var auth smtp.Auth
+	if s.cfg.Username != "" {
+		auth = smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.SMTPHost)
+	}
+	if err := s.send(addr, auth, s.cfg.From, to, []byte(msg.String())); err != nil {
+		return fmt.Errorf("email to %s: %w", strings.Join(to, ", "), err)
+	}
+	return nil
+}
+
+// sendSMTP delivers with STARTTLS when the server offers it, or over implicit
+// TLS on port 465.
+func (s *Sender) sendSMTP(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+	if s.cfg.SMTPPort != 465 {
+		return smtp.SendMail(addr, auth, from, to, msg)
+	}
+
+	conn, err := tls.Dial("tcp", addr, &tls.Config{ServerName: s.cfg.SMTPHost})
+	if err != nil {
+		return err
+	}
+	c, err := smtp.NewClient(conn, s.cfg.SMTPHost)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if auth != nil {
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+func firstLine(message string) string {
+	line, _, _ := strings.Cut(message, "\n")
+	return strings.TrimSpace(line)
+}
@@ -0,0 +1,131 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+type sentMail struct {
+	addr string
+	from string
+	to   []string
+	msg  string
+}
+
+func testSender(batch time.Duration) (*Sender, *[]sentMail) {
+	cfg := config.ReporterEmail{
+		SMTPHost:      "smtp.example.org",
+		SMTPPort:      587,
+		From:          "cortex@example.org",
+		SubjectPrefix: "[cortex]",
+		BatchInterval: config.Duration{Duration: batch},
+	}
+	recipients := map[string][]string{
+		"email:alpha": {"dev@example.org", "lead@example.org"},
+	}
+	s := NewSender(cfg, func(route string) []string { return recipients[route] })
+	s.now = func() time.Time { return time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC) }
+	var sent []sentMail
+	s.send = func(addr string, _ smtp.Auth, from string, to []string, msg []byte) error {
+		sent = append(sent, sentMail{addr: addr, from: from, to: to, msg: string(msg)})
+		return nil
+	}
+	return s, &sent
+}
+
+func TestSendMessageImmediate(t *testing.T) {
+	s, sent := testSender(0)
+	if err := s.SendMessage(context.Background(), "email:alpha", "Stage SLA breached\nalpha-1 sat in review for 5h"); err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	if len(*sent) != 1 {
+		t.Fatalf("expected 1 email, got %d", len(*sent))
+	}
+	m := (*sent)[0]
+	if m.addr != "smtp.example.org:587" || m.from != "cortex@example.org" || len(m.to) != 2 {
+		t.Fatalf("unexpected envelope: %+v", m)
+	}
+	for _, want := range []string{
+		"To: dev@example.org, lead@example.org\r\n",
+		"Subject: [cortex] alpha: Stage SLA breached\r\n",
+		"Date: Mon, 02 Mar 2026 09:00:00 +0000\r\n",
+		"\r\n\r\nStage SLA breached\r\nalpha-1 sat in review for 5h\r\n",
+	} {
+		if !strings.Contains(m.msg, want) {
+			t.Fatalf("message missing %q:\n%s", want, m.msg)
+		}
+	}
+
+	if err := s.SendMessage(context.Background(), "email:unknown", "hello"); err == nil {
+		t.Fatal("expected error for a route without recipients")
+	}
+}
+
+func TestSendMessageBatchesDigest(t *testing.T) {
+	s, sent := testSender(time.Hour)
+	for _, msg := range []string{"first report", "second report"} {
+		if err := s.SendMessage(context.Background(), "email:alpha", msg); err != nil {
+			t.Fatalf("SendMessage: %v", err)
+		}
+	}
+	if len(*sent) != 0 {
+		t.Fatalf("expected reports to be queued, got %d emails", len(*sent))
+	}
+
+	if err := s.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if len(*sent) != 1 {
+		t.Fatalf("expected 1 digest email, got %d", len(*sent))
+	}
+	msg := (*sent)[0].msg
+	if !strings.Contains(msg, "Subject: [cortex] alpha: 2 report(s)\r\n") ||
+		!strings.Contains(msg, "first report\r\n\r\n---\r\n\r\nsecond report") {
+		t.Fatalf("unexpected digest:\n%s", msg)
+	}
+
+	// Nothing queued, nothing sent.
+	if err := s.Flush(); err != nil || len(*sent) != 1 {
+		t.Fatalf("empty flush: %v, %d emails", err, len(*sent))
+	}
+}
+
+func TestFlushKeepsFailedBatch(t *testing.T) {
+	s, sent := testSender(time.Hour)
+	ok := s.send
+	s.send = func(string, smtp.Auth, string, []string, []byte) error { return errors.New("connection refused") }
+
+	if err := s.SendMessage(context.Background(), "email:alpha", "report"); err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	if err := s.Flush(); err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Fatalf("expected delivery error, got %v", err)
+	}
+
+	s.send = ok
+	if err := s.Flush(); err != nil {
+		t.Fatalf("retry flush: %v", err)
+	}
+	if len(*sent) != 1 || !strings.Contains((*sent)[0].msg, "Subject: [cortex] alpha: report\r\n") {
+		t.Fatalf("expected the failed batch to be resent, got %+v", *sent)
+	}
+}
+
+func TestRunFlushesOnShutdown(t *testing.T) {
+	s, sent := testSender(time.Hour)
+	if err := s.SendMessage(context.Background(), "email:alpha", "late report"); err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.Run(ctx, nil)
+	if len(*sent) != 1 {
+		t.Fatalf("expected queued report to be flushed on shutdown, got %d emails", len(*sent))
+	}
+}
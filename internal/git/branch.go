@@ -1,24 +1,27 @@
 package git
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"os/exec"
 	"strings"
+
+	"github.com/antigravity-dev/cortex/internal/cmdexec"
 )
 
 var ErrMergeConflict = errors.New("git merge conflict")
 
+// maxCommandOutput leaves room for large diffs and gh/git log listings.
+const maxCommandOutput = 16 << 20
+
 // CreateFeatureBranch creates and checks out a branch for a bead
 // Branch name: feat/{bead-id} (e.g. feat/cortex-abc)
 func CreateFeatureBranch(workspace, beadID, baseBranch string) error {
 	branchName := fmt.Sprintf("feat/%s", beadID)
 
 	// Create and checkout the new branch from the base branch
-	cmd := exec.Command("git", "checkout", "-b", branchName, baseBranch)
-	cmd.Dir = workspace
-	if out, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("failed to create branch %s from %s: %w (%s)", branchName, baseBranch, err, strings.TrimSpace(string(out)))
+	if _, err := run(workspace, "git", "checkout", "-b", branchName, baseBranch); err != nil {
+		return fmt.Errorf("failed to create branch %s from %s: %w", branchName, baseBranch, err)
 	}
 
 	return nil
@@ -26,11 +29,9 @@ func CreateFeatureBranch(workspace, beadID, baseBranch string) error {
 
 // GetCurrentBranch returns the current branch name
 func GetCurrentBranch(workspace string) (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
-	cmd.Dir = workspace
-	out, err := cmd.CombinedOutput()
+	out, err := run(workspace, "git", "rev-parse", "--abbrev-ref", "HEAD")
 	if err != nil {
-		return "", fmt.Errorf("failed to get current branch: %w (%s)", err, strings.TrimSpace(string(out)))
+		return "", fmt.Errorf("failed to get current branch: %w", err)
 	}
 
 	return strings.TrimSpace(string(out)), nil
@@ -38,12 +39,10 @@ func GetCurrentBranch(workspace string) (string, error) {
 
 // BranchExists checks if a branch already exists
 func BranchExists(workspace, branch string) (bool, error) {
-	cmd := exec.Command("git", "show-ref", "--verify", "--quiet", fmt.Sprintf("refs/heads/%s", branch))
-	cmd.Dir = workspace
-	err := cmd.Run()
+	_, err := run(workspace, "git", "show-ref", "--verify", "--quiet", fmt.Sprintf("refs/heads/%s", branch))
 	if err != nil {
 		// Exit code 1 means branch doesn't exist, other errors are real failures
-		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		if cmdexec.ExitCode(err) == 1 {
 			return false, nil
 		}
 		return false, fmt.Errorf("failed to check if branch %s exists: %w", branch, err)
@@ -52,6 +51,14 @@ func BranchExists(workspace, branch string) (bool, error) {
 	return true, nil
 }
 
+// PushBranch pushes a branch to origin, setting it as the upstream.
+func PushBranch(workspace, branch string) error {
+	if _, err := run(workspace, "git", "push", "-u", "origin", branch); err != nil {
+		return fmt.Errorf("failed to push branch %s: %w", branch, err)
+	}
+	return nil
+}
+
 // EnsureFeatureBranch creates branch if not exists, checks out if exists
 func EnsureFeatureBranch(workspace, beadID string) error {
 	branchName := fmt.Sprintf("feat/%s", beadID)
@@ -64,18 +71,14 @@ func EnsureFeatureBranch(workspace, beadID string) error {
 
 	if exists {
 		// Branch exists, just check it out
-		cmd := exec.Command("git", "checkout", branchName)
-		cmd.Dir = workspace
-		if out, err := cmd.CombinedOutput(); err != nil {
-			return fmt.Errorf("failed to checkout existing branch %s: %w (%s)", branchName, err, strings.TrimSpace(string(out)))
+		if _, err := run(workspace, "git", "checkout", branchName); err != nil {
+			return fmt.Errorf("failed to checkout existing branch %s: %w", branchName, err)
 		}
 	} else {
 		// Branch doesn't exist, create it from main
 		// First, make sure we're up to date with the base branch
-		cmd := exec.Command("git", "fetch", "origin")
-		cmd.Dir = workspace
-		if out, err := cmd.CombinedOutput(); err != nil {
-			return fmt.Errorf("failed to fetch from origin: %w (%s)", err, strings.TrimSpace(string(out)))
+		if _, err := run(workspace, "git", "fetch", "origin"); err != nil {
+			return fmt.Errorf("failed to fetch from origin: %w", err)
 		}
 
 		// Create the new branch from origin/main (assuming main is the base)
@@ -99,28 +102,20 @@ func EnsureFeatureBranchWithBase(workspace, beadID, baseBranch, branchPrefix str
 
 	if exists {
 		// Branch exists, just check it out
-		cmd := exec.Command("git", "checkout", branchName)
-		cmd.Dir = workspace
-		if out, err := cmd.CombinedOutput(); err != nil {
-			return fmt.Errorf("failed to checkout existing branch %s: %w (%s)", branchName, err, strings.TrimSpace(string(out)))
+		if _, err := run(workspace, "git", "checkout", branchName); err != nil {
+			return fmt.Errorf("failed to checkout existing branch %s: %w", branchName, err)
 		}
 	} else {
 		// Branch doesn't exist, create it from the specified base branch
 		// Try to fetch from origin (optional - ignore if no remote)
-		cmd := exec.Command("git", "fetch", "origin")
-		cmd.Dir = workspace
-		cmd.CombinedOutput() // Ignore errors - remote may not exist
+		_, _ = run(workspace, "git", "fetch", "origin") // Ignore errors - remote may not exist
 
 		// Try to create from remote branch first, fall back to local
 		remoteBranch := fmt.Sprintf("origin/%s", baseBranch)
-		cmd = exec.Command("git", "checkout", "-b", branchName, remoteBranch)
-		cmd.Dir = workspace
-		if err := cmd.Run(); err != nil {
+		if _, err := run(workspace, "git", "checkout", "-b", branchName, remoteBranch); err != nil {
 			// If remote branch doesn't exist, try local branch
-			cmd = exec.Command("git", "checkout", "-b", branchName, baseBranch)
-			cmd.Dir = workspace
-			if out, err := cmd.CombinedOutput(); err != nil {
-				return fmt.Errorf("failed to create branch %s from %s: %w (%s)", branchName, baseBranch, err, strings.TrimSpace(string(out)))
+			if _, err := run(workspace, "git", "checkout", "-b", branchName, baseBranch); err != nil {
+				return fmt.Errorf("failed to create branch %s from %s: %w", branchName, baseBranch, err)
 			}
 		}
 	}
@@ -236,17 +231,21 @@ func isMergeConflictText(text string) bool {
 }
 
 func runGitCommand(workspace string, args ...string) (string, error) {
-	cmd := exec.Command("git", args...)
-	cmd.Dir = workspace
-	out, err := cmd.CombinedOutput()
-	text := strings.TrimSpace(string(out))
-	if err != nil {
-		if text == "" {
-			return "", err
-		}
-		return text, fmt.Errorf("%w (%s)", err, text)
-	}
-	return text, nil
+	out, err := run(workspace, "git", args...)
+	return strings.TrimSpace(string(out)), err
+}
+
+// run runs name in workspace through cmdexec and returns its combined
+// output. A failure's error already carries that output.
+func run(workspace, name string, args ...string) ([]byte, error) {
+	res, err := cmdexec.Run(context.Background(), cmdexec.Cmd{
+		Name:           name,
+		Args:           args,
+		Dir:            workspace,
+		CombinedOutput: true,
+		MaxOutput:      maxCommandOutput,
+	})
+	return res.Stdout, err
 }
 
 func abortMergeInProgress(workspace string) {
@@ -321,3 +321,22 @@ func TestDeleteBranch(t *testing.T) {
 		t.Fatalf("expected branch %s to be deleted", branchName)
 	}
 }
+
+func TestGetBranchDiff(t *testing.T) {
+	repo := setupTestRepo(t)
+	baseBranch, _ := GetCurrentBranch(repo)
+	runGit(t, repo, "checkout", "-b", "feat/diff")
+	if err := os.WriteFile(filepath.Join(repo, "new.txt"), []byte("hello\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	runGit(t, repo, "add", "new.txt")
+	runGit(t, repo, "commit", "-m", "add new file")
+
+	diff, err := GetBranchDiff(repo, baseBranch, "feat/diff")
+	if err != nil {
+		t.Fatalf("GetBranchDiff failed: %v", err)
+	}
+	if !strings.Contains(diff, "+hello") || strings.Contains(diff, "README") {
+		t.Fatalf("unexpected branch diff:\n%s", diff)
+	}
+}
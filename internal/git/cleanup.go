@@ -2,12 +2,40 @@ package git
 
 import (
 	"fmt"
-	"os/exec"
 	"strconv"
 	"strings"
 	"time"
 )
 
+// Branch is a local branch and the committer date of its tip.
+type Branch struct {
+	Name       string
+	LastCommit time.Time
+}
+
+// ListBranches returns the local branches in workspace.
+func ListBranches(workspace string) ([]Branch, error) {
+	out, err := run(workspace, "git", "for-each-ref", "--format=%(refname:short)|%(committerdate:unix)", "refs/heads")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	var branches []Branch
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		parts := strings.SplitN(strings.TrimSpace(line), "|", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		name := strings.TrimSpace(parts[0])
+		unix, err := strconv.ParseInt(strings.TrimSpace(parts[1]), 10, 64)
+		if name == "" || err != nil {
+			continue
+		}
+		branches = append(branches, Branch{Name: name, LastCommit: time.Unix(unix, 0)})
+	}
+	return branches, nil
+}
+
 // CleanupBranchesOlderThan prunes local branches with the given prefix older than cutoff.
 // It never deletes the currently checked-out branch.
 func CleanupBranchesOlderThan(workspace, prefix string, cutoff time.Time) ([]string, error) {
@@ -21,43 +49,42 @@ func CleanupBranchesOlderThan(workspace, prefix string, cutoff time.Time) ([]str
 		return nil, err
 	}
 
-	cmd := exec.Command("git", "for-each-ref", "--format=%(refname:short)|%(committerdate:unix)", "refs/heads")
-	cmd.Dir = workspace
-	out, err := cmd.CombinedOutput()
+	branches, err := ListBranches(workspace)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list branches for cleanup: %w (%s)", err, strings.TrimSpace(string(out)))
+		return nil, fmt.Errorf("failed to list branches for cleanup: %w", err)
 	}
 
-	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
 	deleted := make([]string, 0)
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
+	for _, b := range branches {
+		if b.Name == currentBranch || !strings.HasPrefix(b.Name, prefix) || !b.LastCommit.Before(cutoff) {
 			continue
 		}
-		parts := strings.SplitN(line, "|", 2)
-		if len(parts) != 2 {
-			continue
+		if err := ForceDeleteBranch(workspace, b.Name); err != nil {
+			return deleted, fmt.Errorf("failed to delete stale branch %s: %w", b.Name, err)
 		}
-		branch := strings.TrimSpace(parts[0])
-		if branch == "" || branch == currentBranch || !strings.HasPrefix(branch, prefix) {
-			continue
-		}
-		unix, err := strconv.ParseInt(strings.TrimSpace(parts[1]), 10, 64)
-		if err != nil {
-			continue
-		}
-		if !time.Unix(unix, 0).Before(cutoff) {
-			continue
-		}
-
-		delCmd := exec.Command("git", "branch", "-D", branch)
-		delCmd.Dir = workspace
-		if delOut, delErr := delCmd.CombinedOutput(); delErr != nil {
-			return deleted, fmt.Errorf("failed to delete stale branch %s: %w (%s)", branch, delErr, strings.TrimSpace(string(delOut)))
-		}
-		deleted = append(deleted, branch)
+		deleted = append(deleted, b.Name)
 	}
 
 	return deleted, nil
 }
+
+// ForceDeleteBranch deletes a local branch whether or not it has been merged.
+func ForceDeleteBranch(workspace, branch string) error {
+	if _, err := runGitCommand(workspace, "branch", "-D", branch); err != nil {
+		return fmt.Errorf("failed to delete branch %s: %w", branch, err)
+	}
+	return nil
+}
+
+// DeleteRemoteBranch deletes branch on origin. A branch that was never
+// pushed, or is already gone, is not an error.
+func DeleteRemoteBranch(workspace, branch string) error {
+	out, err := runGitCommand(workspace, "push", "origin", "--delete", branch)
+	if err != nil {
+		if strings.Contains(out, "remote ref does not exist") {
+			return nil
+		}
+		return fmt.Errorf("failed to delete remote branch %s: %w", branch, err)
+	}
+	return nil
+}
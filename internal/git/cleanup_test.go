@@ -61,3 +61,53 @@ func TestCleanupBranchesOlderThanNoPrefixNoop(t *testing.T) {
 		t.Fatalf("expected no deletions, got %v", deleted)
 	}
 }
+
+func TestListBranches(t *testing.T) {
+	repo := setupTestRepo(t)
+	baseBranch, _ := GetCurrentBranch(repo)
+	runGit(t, repo, "branch", "feat/cx-1")
+
+	branches, err := ListBranches(repo)
+	if err != nil {
+		t.Fatalf("ListBranches failed: %v", err)
+	}
+	names := map[string]bool{}
+	for _, b := range branches {
+		names[b.Name] = true
+		if b.LastCommit.IsZero() || time.Since(b.LastCommit) > time.Hour {
+			t.Errorf("branch %s has unexpected last commit %v", b.Name, b.LastCommit)
+		}
+	}
+	if len(branches) != 2 || !names[baseBranch] || !names["feat/cx-1"] {
+		t.Fatalf("unexpected branches: %+v", branches)
+	}
+}
+
+func TestForceAndRemoteDeleteBranch(t *testing.T) {
+	repo := setupTestRepo(t)
+	baseBranch, _ := GetCurrentBranch(repo)
+	remote := t.TempDir()
+	runGit(t, remote, "init", "--bare")
+	runGit(t, repo, "remote", "add", "origin", remote)
+
+	runGit(t, repo, "checkout", "-b", "feat/cx-2")
+	runGit(t, repo, "commit", "--allow-empty", "-m", "unmerged work")
+	runGit(t, repo, "push", "origin", "feat/cx-2")
+	runGit(t, repo, "checkout", baseBranch)
+
+	if err := DeleteBranch(repo, "feat/cx-2"); err == nil {
+		t.Fatal("expected safe delete of an unmerged branch to fail")
+	}
+	if err := ForceDeleteBranch(repo, "feat/cx-2"); err != nil {
+		t.Fatalf("ForceDeleteBranch failed: %v", err)
+	}
+	if err := DeleteRemoteBranch(repo, "feat/cx-2"); err != nil {
+		t.Fatalf("DeleteRemoteBranch failed: %v", err)
+	}
+	if out := runGit(t, remote, "branch", "--list", "feat/cx-2"); out != "" {
+		t.Fatalf("remote branch still present: %q", out)
+	}
+	if err := DeleteRemoteBranch(repo, "feat/cx-2"); err != nil {
+		t.Fatalf("deleting an already-deleted remote branch should succeed: %v", err)
+	}
+}
@@ -2,7 +2,6 @@ package git
 
 import (
 	"fmt"
-	"os/exec"
 	"regexp"
 	"strings"
 	"time"
@@ -20,12 +19,9 @@ type Commit struct {
 // GetRecentCommits returns commits from the last N days
 func GetRecentCommits(workspace string, days int) ([]Commit, error) {
 	since := fmt.Sprintf("--since=%d.days.ago", days)
-	cmd := exec.Command("git", "log", since, "--pretty=format:%H|%s|%an|%ai", "--no-merges")
-	cmd.Dir = workspace
-	
-	out, err := cmd.CombinedOutput()
+	out, err := run(workspace, "git", "log", since, "--pretty=format:%H|%s|%an|%ai", "--no-merges")
 	if err != nil {
-		return nil, fmt.Errorf("failed to get recent commits: %w (%s)", err, strings.TrimSpace(string(out)))
+		return nil, fmt.Errorf("failed to get recent commits: %w", err)
 	}
 	
 	if strings.TrimSpace(string(out)) == "" {
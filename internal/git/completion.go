@@ -0,0 +1,157 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// closingKeyword matches "Fixes <bead-id>" style references: close, fix or
+// resolve in any tense, optionally followed by a colon.
+var closingKeyword = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?):?\s+([a-zA-Z][a-zA-Z0-9]*(?:-[a-zA-Z0-9]+)+(?:\.[0-9]+)?)\b`)
+
+// MergedPR is a merged pull request that references a bead.
+type MergedPR struct {
+	Number      int       `json:"number"`
+	Title       string    `json:"title"`
+	HeadRefName string    `json:"headRefName"`
+	Body        string    `json:"body"`
+	URL         string    `json:"url"`
+	MergedAt    time.Time `json:"mergedAt"`
+}
+
+// Completion is the evidence that a bead's work has landed: commits naming
+// it in their subject or closing it in their body, and merged PRs whose
+// branch, title or closing keywords reference it.
+type Completion struct {
+	BeadID  string
+	Commits []Commit
+	PRs     []MergedPR
+}
+
+// Completed reports whether any evidence was found.
+func (c *Completion) Completed() bool {
+	return len(c.Commits) > 0 || len(c.PRs) > 0
+}
+
+// ClosingBeadIDs returns the bead IDs that message closes with a keyword,
+// e.g. "Fixes cortex-abc" or "closes: cortex-abc.1".
+func ClosingBeadIDs(message string) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, match := range closingKeyword.FindAllStringSubmatch(message, -1) {
+		if id := match[1]; isLikelyBeadID(id) && !seen[id] {
+			ids = append(ids, id)
+			seen[id] = true
+		}
+	}
+	return ids
+}
+
+// VerifyCompletion looks for evidence from the last days that beadID's work
+// landed. Commits are checked first; merged PRs are only looked up with the
+// gh CLI when no commit references the bead, which catches squash merges
+// whose commit subject lost the bead ID.
+func VerifyCompletion(workspace, beadID string, days int) (*Completion, error) {
+	completion := &Completion{BeadID: beadID}
+
+	commits, err := commitsReferencing(workspace, beadID, days)
+	if err != nil {
+		return nil, err
+	}
+	completion.Commits = commits
+	if completion.Completed() {
+		return completion, nil
+	}
+
+	prs, err := ListMergedPRs(workspace, days)
+	if err != nil {
+		return nil, err
+	}
+	for _, pr := range prs {
+		if PRReferencesBead(pr, beadID) {
+			completion.PRs = append(completion.PRs, pr)
+		}
+	}
+	return completion, nil
+}
+
+// ListMergedPRs returns the PRs merged in the last days, using gh CLI.
+func ListMergedPRs(workspace string, days int) ([]MergedPR, error) {
+	since := time.Now().AddDate(0, 0, -days).Format("2006-01-02")
+	out, err := run(workspace, "gh", "pr", "list", "--state", "merged", "--search", "merged:>="+since,
+		"--limit", "200", "--json", "number,title,headRefName,body,url,mergedAt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merged PRs: %w", err)
+	}
+	var prs []MergedPR
+	if err := json.Unmarshal(out, &prs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal merged PRs: %w", err)
+	}
+	return prs, nil
+}
+
+// PRReferencesBead reports whether pr was made for beadID: its head branch
+// ends in the bead ID (feat/cortex-abc), its title names it, or its body
+// closes it with a keyword.
+func PRReferencesBead(pr MergedPR, beadID string) bool {
+	if branch := pr.HeadRefName; strings.HasSuffix(branch, beadID) {
+		rest := strings.TrimSuffix(branch, beadID)
+		if rest == "" || strings.HasSuffix(rest, "/") {
+			return true
+		}
+	}
+	for _, id := range ExtractBeadIDs(pr.Title) {
+		if id == beadID {
+			return true
+		}
+	}
+	for _, id := range ClosingBeadIDs(pr.Body) {
+		if id == beadID {
+			return true
+		}
+	}
+	return false
+}
+
+// commitsReferencing returns the commits from the last days whose subject
+// names beadID or whose body closes it with a keyword.
+func commitsReferencing(workspace, beadID string, days int) ([]Commit, error) {
+	since := fmt.Sprintf("--since=%d.days.ago", days)
+	out, err := run(workspace, "git", "log", since, "--no-merges", "--pretty=format:%x1e%H%x1f%an%x1f%ai%x1f%s%x1f%b")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent commits: %w", err)
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(string(out), "\x1e") {
+		parts := strings.SplitN(record, "\x1f", 5)
+		if len(parts) != 5 {
+			continue
+		}
+		subjectIDs := ExtractBeadIDs(parts[3])
+		if !containsID(subjectIDs, beadID) && !containsID(ClosingBeadIDs(parts[4]), beadID) {
+			continue
+		}
+		date, _ := time.Parse("2006-01-02 15:04:05 -0700", parts[2])
+		commits = append(commits, Commit{
+			Hash:    parts[0],
+			Message: parts[3],
+			Author:  parts[1],
+			Date:    date,
+			BeadIDs: subjectIDs,
+		})
+	}
+	return commits, nil
+}
+
+func containsID(ids []string, id string) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
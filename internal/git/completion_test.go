@@ -0,0 +1,86 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestClosingBeadIDs(t *testing.T) {
+	got := ClosingBeadIDs("Refactor auth.\n\nFixes cortex-abc, closes: cortex-def.1\nResolved cortex-abc. Mentions cortex-xyz.")
+	want := []string{"cortex-abc", "cortex-def.1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ClosingBeadIDs = %v, want %v", got, want)
+	}
+}
+
+func TestPRReferencesBead(t *testing.T) {
+	tests := []struct {
+		name string
+		pr   MergedPR
+		want bool
+	}{
+		{"branch", MergedPR{HeadRefName: "feat/cortex-abc"}, true},
+		{"bare branch", MergedPR{HeadRefName: "cortex-abc"}, true},
+		{"longer id branch", MergedPR{HeadRefName: "feat/xcortex-abc"}, false},
+		{"title", MergedPR{Title: "cortex-abc: add handler"}, true},
+		{"closing keyword", MergedPR{Title: "Add handler", Body: "Fixes cortex-abc"}, true},
+		{"mention only", MergedPR{Title: "Add handler", Body: "Related to cortex-abc"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PRReferencesBead(tt.pr, "cortex-abc"); got != tt.want {
+				t.Errorf("PRReferencesBead = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyCompletionCommitKeyword(t *testing.T) {
+	repo := setupTestRepo(t)
+	commitFiles(t, repo, "Add handler\n\nFixes cortex-abc", "handler.go")
+	commitFiles(t, repo, "chore: tidy", "tidy.go")
+
+	// A commit match must not need gh.
+	fakeBin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(fakeBin, "gh"), []byte("#!/bin/sh\nexit 1\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+	completion, err := VerifyCompletion(repo, "cortex-abc", 7)
+	if err != nil {
+		t.Fatalf("VerifyCompletion: %v", err)
+	}
+	if !completion.Completed() || len(completion.Commits) != 1 || completion.Commits[0].Message != "Add handler" {
+		t.Fatalf("completion = %+v, want the keyword commit", completion)
+	}
+}
+
+func TestVerifyCompletionMergedPR(t *testing.T) {
+	repo := setupTestRepo(t)
+	commitFiles(t, repo, "Squashed work", "handler.go")
+
+	fakeBin := t.TempDir()
+	gh := "#!/bin/sh\ncat <<'JSON'\n" + `[
+		{"number":7,"title":"Add handler","headRefName":"feat/cortex-abc","body":"","url":"https://example.com/pr/7","mergedAt":"2026-01-02T03:04:05Z"},
+		{"number":8,"title":"Other work","headRefName":"feat/cortex-def","body":"","url":"https://example.com/pr/8","mergedAt":"2026-01-02T03:04:05Z"}
+	]` + "\nJSON\n"
+	if err := os.WriteFile(filepath.Join(fakeBin, "gh"), []byte(gh), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	completion, err := VerifyCompletion(repo, "cortex-abc", 7)
+	if err != nil {
+		t.Fatalf("VerifyCompletion: %v", err)
+	}
+	if len(completion.Commits) != 0 || len(completion.PRs) != 1 || completion.PRs[0].Number != 7 {
+		t.Fatalf("completion = %+v, want PR 7 only", completion)
+	}
+
+	completion, err = VerifyCompletion(repo, "cortex-zzz", 7)
+	if err != nil || completion.Completed() {
+		t.Fatalf("expected no evidence for cortex-zzz, got %+v, %v", completion, err)
+	}
+}
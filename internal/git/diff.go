@@ -2,17 +2,13 @@ package git
 
 import (
 	"fmt"
-	"os/exec"
-	"strings"
 )
 
 // GetPRDiff returns the diff for a PR using gh CLI
 func GetPRDiff(workspace string, prNumber int) (string, error) {
-	cmd := exec.Command("gh", "pr", "diff", fmt.Sprintf("%d", prNumber))
-	cmd.Dir = workspace
-	out, err := cmd.CombinedOutput()
+	out, err := run(workspace, "gh", "pr", "diff", fmt.Sprintf("%d", prNumber))
 	if err != nil {
-		return "", fmt.Errorf("failed to get PR diff: %w (%s)", err, strings.TrimSpace(string(out)))
+		return "", fmt.Errorf("failed to get PR diff: %w", err)
 	}
 	return string(out), nil
 }
@@ -24,3 +20,23 @@ func TruncateDiff(diff string, maxBytes int) string {
 	}
 	return diff[:maxBytes] + "\n\n[Diff truncated...]"
 }
+
+// GetWorkingTreeDiff returns uncommitted changes (staged and unstaged) relative
+// to HEAD in workspace.
+func GetWorkingTreeDiff(workspace string) (string, error) {
+	out, err := run(workspace, "git", "diff", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to get working tree diff: %w", err)
+	}
+	return string(out), nil
+}
+
+// GetBranchDiff returns the changes on branch since it diverged from
+// baseBranch.
+func GetBranchDiff(workspace, baseBranch, branch string) (string, error) {
+	out, err := run(workspace, "git", "diff", baseBranch+"..."+branch)
+	if err != nil {
+		return "", fmt.Errorf("failed to get branch diff: %w", err)
+	}
+	return string(out), nil
+}
@@ -0,0 +1,87 @@
+package git
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// BeadFilesFromCommits maps each bead ID named in the subject of a
+// non-merge commit from the last days to the files those commits changed.
+func BeadFilesFromCommits(workspace string, days int) (map[string][]string, error) {
+	out, err := runGitCommand(workspace, "log", fmt.Sprintf("--since=%d.days.ago", days), "--no-merges",
+		"--name-only", "--pretty=format:%x1e%s")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commit files: %w", err)
+	}
+
+	sets := make(map[string]map[string]bool)
+	for _, record := range strings.Split(out, "\x1e") {
+		lines := strings.Split(strings.TrimSpace(record), "\n")
+		if len(lines) < 2 {
+			continue
+		}
+		for _, beadID := range ExtractBeadIDs(lines[0]) {
+			if sets[beadID] == nil {
+				sets[beadID] = make(map[string]bool)
+			}
+			for _, file := range lines[1:] {
+				if file = strings.TrimSpace(file); file != "" {
+					sets[beadID][file] = true
+				}
+			}
+		}
+	}
+
+	files := make(map[string][]string, len(sets))
+	for beadID, set := range sets {
+		for file := range set {
+			files[beadID] = append(files[beadID], file)
+		}
+		sort.Strings(files[beadID])
+	}
+	return files, nil
+}
+
+// BranchFiles returns the files changed on branch since it diverged from
+// baseBranch.
+func BranchFiles(workspace, baseBranch, branch string) ([]string, error) {
+	out, err := runGitCommand(workspace, "diff", "--name-only", baseBranch+"..."+branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branch files: %w", err)
+	}
+	var files []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// ChangedFiles returns the files the work tree at workspace changed since it
+// diverged from baseBranch: committed, uncommitted and untracked ones.
+func ChangedFiles(workspace, baseBranch string) ([]string, error) {
+	base, err := runGitCommand(workspace, "merge-base", baseBranch, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find merge base with %s: %w", baseBranch, err)
+	}
+	changed, err := runGitCommand(workspace, "diff", "--name-only", strings.TrimSpace(base))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changed files: %w", err)
+	}
+	untracked, err := runGitCommand(workspace, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+	seen := map[string]bool{}
+	var files []string
+	for _, line := range strings.Split(changed+"\n"+untracked, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !seen[line] {
+			seen[line] = true
+			files = append(files, line)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
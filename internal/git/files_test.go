@@ -0,0 +1,87 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func commitFiles(t *testing.T, repo, message string, files ...string) {
+	t.Helper()
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(repo, f), []byte(message+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, repo, "add", f)
+	}
+	runGit(t, repo, "commit", "-m", message)
+}
+
+func TestBeadFilesFromCommits(t *testing.T) {
+	repo := setupTestRepo(t)
+	commitFiles(t, repo, "feat(cortex-abc): add handler", "handler.go", "routes.go")
+	commitFiles(t, repo, "fix(cortex-abc): handle errors", "handler.go")
+	commitFiles(t, repo, "fix cortex-def and cortex-abc.1", "routes.go")
+	commitFiles(t, repo, "chore: tidy", "tidy.go")
+
+	files, err := BeadFilesFromCommits(repo, 7)
+	if err != nil {
+		t.Fatalf("BeadFilesFromCommits: %v", err)
+	}
+	want := map[string][]string{
+		"cortex-abc":   {"handler.go", "routes.go"},
+		"cortex-def":   {"routes.go"},
+		"cortex-abc.1": {"routes.go"},
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Fatalf("files = %v, want %v", files, want)
+	}
+}
+
+func TestBranchFiles(t *testing.T) {
+	repo := setupTestRepo(t)
+	base := currentBranch(t, repo)
+	runGit(t, repo, "checkout", "-b", "feat/cortex-abc")
+	commitFiles(t, repo, "work", "a.go", "b.go")
+	runGit(t, repo, "checkout", base)
+	commitFiles(t, repo, "base moved on", "c.go")
+
+	files, err := BranchFiles(repo, base, "feat/cortex-abc")
+	if err != nil {
+		t.Fatalf("BranchFiles: %v", err)
+	}
+	if !reflect.DeepEqual(files, []string{"a.go", "b.go"}) {
+		t.Fatalf("files = %v, want only the branch's own changes", files)
+	}
+}
+
+func currentBranch(t *testing.T, repo string) string {
+	t.Helper()
+	branch, err := GetCurrentBranch(repo)
+	if err != nil {
+		t.Fatalf("GetCurrentBranch: %v", err)
+	}
+	return branch
+}
+
+func TestChangedFiles(t *testing.T) {
+	repo := setupTestRepo(t)
+	base := currentBranch(t, repo)
+	runGit(t, repo, "checkout", "-b", "feat/cortex-abc")
+	commitFiles(t, repo, "work", "a.go")
+	if err := os.WriteFile(filepath.Join(repo, "new.go"), []byte("package x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "a.go"), []byte("package x // edited\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := ChangedFiles(repo, base)
+	if err != nil {
+		t.Fatalf("ChangedFiles: %v", err)
+	}
+	if !reflect.DeepEqual(files, []string{"a.go", "new.go"}) {
+		t.Fatalf("files = %v, want committed, uncommitted and untracked changes", files)
+	}
+}
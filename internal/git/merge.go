@@ -1,10 +1,14 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os/exec"
 	"strings"
 	"time"
+
+	"github.com/antigravity-dev/cortex/internal/cmdexec"
 )
 
 var supportedMergeMethods = map[string]string{
@@ -27,6 +31,7 @@ type CheckResult struct {
 	Output   string        // truncated stdout/stderr output
 	Passed   bool          // true if the check passed
 	Duration time.Duration // how long the command took
+	Killed   bool          // the command was still running when its context ended
 }
 
 // MergePR merges an approved PR using gh CLI.
@@ -51,14 +56,7 @@ func MergePR(workspace string, prNumber int, method string) error {
 		return fmt.Errorf("invalid PR number: %d", prNumber)
 	}
 
-	cmd := exec.Command("gh", "pr", "merge", fmt.Sprintf("%d", prNumber), mergeFlag)
-	cmd.Dir = workspace
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		output := strings.TrimSpace(string(out))
-		if output != "" {
-			return fmt.Errorf("failed to merge PR #%d using %q in %s: %w (%s)", prNumber, method, workspace, err, output)
-		}
+	if _, err := run(workspace, "gh", "pr", "merge", fmt.Sprintf("%d", prNumber), mergeFlag); err != nil {
 		return fmt.Errorf("failed to merge PR #%d using %q in %s: %w", prNumber, method, workspace, err)
 	}
 	return nil
@@ -76,32 +74,60 @@ func RevertMerge(workspace string, commitSHA string) error {
 		return fmt.Errorf("commit SHA is required")
 	}
 
-	revertCmd := exec.Command("git", "revert", commitSHA, "--no-edit")
-	revertCmd.Dir = workspace
-	out, err := revertCmd.CombinedOutput()
-	if err != nil {
-		output := strings.TrimSpace(string(out))
-		if output != "" {
-			return fmt.Errorf("failed to revert commit %s in %s: %w (%s)", commitSHA, workspace, err, output)
-		}
+	if _, err := run(workspace, "git", "revert", commitSHA, "--no-edit"); err != nil {
 		return fmt.Errorf("failed to revert commit %s in %s: %w", commitSHA, workspace, err)
 	}
 
-	pushCmd := exec.Command("git", "push")
-	pushCmd.Dir = workspace
-	out, err = pushCmd.CombinedOutput()
-	if err != nil {
-		output := strings.TrimSpace(string(out))
-		if output != "" {
-			return fmt.Errorf("failed to push revert of commit %s from %s: %w (%s)", commitSHA, workspace, err, output)
-		}
+	if _, err := run(workspace, "git", "push"); err != nil {
 		return fmt.Errorf("failed to push revert of commit %s from %s: %w", commitSHA, workspace, err)
 	}
 	return nil
 }
 
+// CheckoutRemoteBase fetches baseBranch from origin and checks the worktree
+// at path out detached at its tip, creating the worktree when needed, so
+// merged work can be checked without touching the workspace's checkout.
+func CheckoutRemoteBase(workspace, path, baseBranch string) error {
+	if _, err := runGitCommand(workspace, "fetch", "origin", baseBranch); err != nil {
+		return fmt.Errorf("failed to fetch %s in %s: %w", baseBranch, workspace, err)
+	}
+	return CheckoutWorktree(workspace, path, "", "origin/"+baseBranch)
+}
+
+// RevertOntoBase reverts commitSHA in the worktree at path, checked out at
+// the tip of baseBranch by CheckoutRemoteBase, and pushes the revert to
+// baseBranch. A merge commit is reverted against its first parent.
+func RevertOntoBase(path, commitSHA, baseBranch string) error {
+	commitSHA = strings.TrimSpace(commitSHA)
+	if commitSHA == "" {
+		return fmt.Errorf("commit SHA is required")
+	}
+	parents, err := runGitCommand(path, "rev-list", "--parents", "-n", "1", commitSHA)
+	if err != nil {
+		return fmt.Errorf("failed to read commit %s: %w", commitSHA, err)
+	}
+	args := []string{"revert", "--no-edit"}
+	if len(strings.Fields(parents)) > 2 {
+		args = append(args, "-m", "1")
+	}
+	if _, err := runGitCommand(path, append(args, commitSHA)...); err != nil {
+		return fmt.Errorf("failed to revert commit %s: %w", commitSHA, err)
+	}
+	if _, err := runGitCommand(path, "push", "origin", "HEAD:refs/heads/"+baseBranch); err != nil {
+		return fmt.Errorf("failed to push revert of commit %s to %s: %w", commitSHA, baseBranch, err)
+	}
+	return nil
+}
+
 // RunPostMergeChecks runs PR merge validation checks after merge.
 func RunPostMergeChecks(workspace string, checks []string) (*DoDResult, error) {
+	return RunPostMergeChecksCtx(context.Background(), workspace, checks)
+}
+
+// RunPostMergeChecksCtx is RunPostMergeChecks bounded by ctx. A check still
+// running when ctx ends is killed with its child processes and fails with
+// Killed set; the checks after it are not started.
+func RunPostMergeChecksCtx(ctx context.Context, workspace string, checks []string) (*DoDResult, error) {
 	result := &DoDResult{
 		Passed:   true,
 		Checks:   make([]CheckResult, 0, len(checks)),
@@ -123,8 +149,14 @@ func RunPostMergeChecks(workspace string, checks []string) (*DoDResult, error) {
 			continue
 		}
 
-		checkResult := runSinglePostMergeCheck(workspace, check)
+		checkResult := runSinglePostMergeCheck(ctx, workspace, check)
 		result.Checks = append(result.Checks, *checkResult)
+		if checkResult.Killed {
+			result.Passed = false
+			result.Failures = append(result.Failures,
+				fmt.Sprintf("Command killed after %s: %s (%v)", checkResult.Duration.Round(time.Second), check, ctx.Err()))
+			break
+		}
 		if !checkResult.Passed {
 			result.Passed = false
 			result.Failures = append(result.Failures,
@@ -135,7 +167,7 @@ func RunPostMergeChecks(workspace string, checks []string) (*DoDResult, error) {
 	return result, nil
 }
 
-func runSinglePostMergeCheck(workspace, command string) *CheckResult {
+func runSinglePostMergeCheck(ctx context.Context, workspace, command string) *CheckResult {
 	start := time.Now()
 	parts := strings.Fields(command)
 	if len(parts) == 0 {
@@ -147,11 +179,17 @@ func runSinglePostMergeCheck(workspace, command string) *CheckResult {
 			Duration: 0,
 		}
 	}
-	cmd := exec.Command("sh", "-c", command)
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
 	cmd.Dir = workspace
+	killGroupOnCancel(cmd)
+	cmd.WaitDelay = 5 * time.Second // don't wait on pipes held by stray children
 
 	output, err := cmd.CombinedOutput()
 	duration := time.Since(start)
+	killed := err != nil && ctx.Err() != nil
+	// Counted with the bd, gh and git runs, so a check that keeps running
+	// past its activity's timeout shows up as stuck like they do.
+	cmdexec.Record("dod check", duration, err != nil, killed && errors.Is(ctx.Err(), context.DeadlineExceeded))
 
 	exitCode := 0
 	passed := true
@@ -174,16 +212,15 @@ func runSinglePostMergeCheck(workspace, command string) *CheckResult {
 		Output:   out,
 		Passed:   passed,
 		Duration: duration,
+		Killed:   killed,
 	}
 }
 
 // LatestCommitSHA returns HEAD commit SHA for workspace.
 func LatestCommitSHA(workspace string) (string, error) {
-	cmd := exec.Command("git", "rev-parse", "HEAD")
-	cmd.Dir = workspace
-	out, err := cmd.CombinedOutput()
+	out, err := run(workspace, "git", "rev-parse", "HEAD")
 	if err != nil {
-		return "", fmt.Errorf("failed to read HEAD commit: %w (%s)", err, strings.TrimSpace(string(out)))
+		return "", fmt.Errorf("failed to read HEAD commit: %w", err)
 	}
 	return strings.TrimSpace(string(out)), nil
 }
@@ -1,11 +1,15 @@
 package git
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/cmdexec"
 )
 
 func writeFakeBinaryForGitMergeTests(t *testing.T, command string, content string) (string, string) {
@@ -264,3 +268,79 @@ func readFileOrT(t *testing.T, path string) string {
 	}
 	return string(data)
 }
+
+func TestRunPostMergeChecksCtx_KillsHungCheck(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	before := dodCheckStat()
+	start := time.Now()
+	// The sleep is a child of sh that outlives it unless the whole group dies.
+	result, err := RunPostMergeChecksCtx(ctx, t.TempDir(), []string{
+		"echo started; sleep 30 & wait",
+		"echo never",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("hung check ran for %s", elapsed)
+	}
+	if result.Passed || len(result.Checks) != 1 {
+		t.Fatalf("result = %+v", result)
+	}
+	if c := result.Checks[0]; !c.Killed || c.Passed || c.Output != "started" {
+		t.Fatalf("check = %+v", c)
+	}
+	if len(result.Failures) != 1 || !strings.Contains(result.Failures[0], "Command killed") {
+		t.Fatalf("failures = %v", result.Failures)
+	}
+	if after := dodCheckStat(); after.Timeouts != before.Timeouts+1 || after.Streak < 1 {
+		t.Fatalf("dod check stats before %+v after %+v", before, after)
+	}
+}
+
+func dodCheckStat() cmdexec.Stat {
+	for _, st := range cmdexec.Stats() {
+		if st.Command == "dod check" {
+			return st
+		}
+	}
+	return cmdexec.Stat{}
+}
+
+func TestRevertOntoBase(t *testing.T) {
+	repo := setupTestRepo(t)
+	baseBranch, _ := GetCurrentBranch(repo)
+	remote := t.TempDir()
+	runGit(t, remote, "init", "--bare")
+	runGit(t, repo, "remote", "add", "origin", remote)
+	if err := os.WriteFile(filepath.Join(repo, "broken.go"), []byte("package broken\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "add", "broken.go")
+	runGit(t, repo, "commit", "-m", "merged work")
+	runGit(t, repo, "push", "origin", baseBranch)
+	merged := strings.TrimSpace(runGit(t, repo, "rev-parse", "HEAD"))
+
+	path := filepath.Join(t.TempDir(), "checkout")
+	if err := CheckoutRemoteBase(repo, path, baseBranch); err != nil {
+		t.Fatalf("CheckoutRemoteBase: %v", err)
+	}
+	if head := strings.TrimSpace(runGit(t, path, "rev-parse", "HEAD")); head != merged {
+		t.Fatalf("worktree HEAD = %s, want the merged commit %s", head, merged)
+	}
+	if err := RevertOntoBase(path, merged, baseBranch); err != nil {
+		t.Fatalf("RevertOntoBase: %v", err)
+	}
+
+	subject := strings.TrimSpace(runGit(t, remote, "log", "-1", "--format=%s", baseBranch))
+	if subject != `Revert "merged work"` {
+		t.Fatalf("remote %s tip = %q, want the revert", baseBranch, subject)
+	}
+	if files := runGit(t, remote, "ls-tree", "--name-only", baseBranch); strings.Contains(files, "broken.go") {
+		t.Fatalf("reverted file still on %s: %s", baseBranch, files)
+	}
+	if b, _ := GetCurrentBranch(repo); b != baseBranch {
+		t.Fatalf("workspace branch = %q, want it left on %s", b, baseBranch)
+	}
+}
@@ -3,9 +3,9 @@ package git
 import (
 	"encoding/json"
 	"fmt"
-	"os/exec"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type PRStatus struct {
@@ -17,16 +17,22 @@ type PRStatus struct {
 
 // CreatePR creates a pull request for a feature branch using gh CLI
 func CreatePR(workspace, branch, baseBranch, title, body string) (string, int, error) {
-	cmd := exec.Command("gh", "pr", "create",
+	return createPR(workspace, branch, baseBranch, title, body, false)
+}
+
+func createPR(workspace, branch, baseBranch, title, body string, draft bool) (string, int, error) {
+	args := []string{"pr", "create",
 		"--head", branch,
 		"--base", baseBranch,
 		"--title", title,
 		"--body", body,
-	)
-	cmd.Dir = workspace
-	out, err := cmd.CombinedOutput()
+	}
+	if draft {
+		args = append(args, "--draft")
+	}
+	out, err := run(workspace, "gh", args...)
 	if err != nil {
-		return "", 0, fmt.Errorf("failed to create PR: %w (%s)", err, strings.TrimSpace(string(out)))
+		return "", 0, fmt.Errorf("failed to create PR: %w", err)
 	}
 
 	prURL := strings.TrimSpace(string(out))
@@ -41,16 +47,128 @@ func CreatePR(workspace, branch, baseBranch, title, body string) (string, int, e
 	return prURL, 0, nil
 }
 
+// maxSummaryDiffBytes caps the diff sent to the PR summarizer.
+const maxSummaryDiffBytes = 30000
+
+// PRDescription is what a pull request body is written from.
+type PRDescription struct {
+	BeadID      string
+	Title       string
+	Description string
+	Acceptance  string
+	Diff        string
+	Checks      []CheckResult
+	Draft       bool // open the PR as a draft, taken out of draft by MarkPRReady
+}
+
+// Summarizer turns a prompt into text, typically with a cheap fast-tier agent.
+type Summarizer func(prompt string) (string, error)
+
+// CreateDescribedPR creates a pull request whose body is a changelog-style
+// summary of the diff, check results and acceptance criteria written by
+// summarize. A nil summarizer, a failed call or an empty answer falls back to
+// the templated bead fields, so PR creation never depends on the summarizer.
+func CreateDescribedPR(workspace, branch, baseBranch string, pr PRDescription, summarize Summarizer) (string, int, error) {
+	title := pr.Title
+	if pr.BeadID != "" {
+		title = fmt.Sprintf("%s: %s", pr.BeadID, pr.Title)
+	}
+	return createPR(workspace, branch, baseBranch, title, DescribePR(pr, summarize), pr.Draft)
+}
+
+// MarkPRReady turns a draft pull request into one ready for review using gh CLI.
+func MarkPRReady(workspace string, prNumber int) error {
+	if _, err := run(workspace, "gh", "pr", "ready", strconv.Itoa(prNumber)); err != nil {
+		return fmt.Errorf("failed to mark PR ready: %w", err)
+	}
+	return nil
+}
+
+// DescribePR returns the PR body for pr: the summarizer's answer followed by
+// the check results, or the templated body when no summary is available.
+func DescribePR(pr PRDescription, summarize Summarizer) string {
+	if summarize == nil || strings.TrimSpace(pr.Diff) == "" {
+		return TemplatePRBody(pr)
+	}
+	summary, err := summarize(PRSummaryPrompt(pr))
+	summary = strings.TrimSpace(summary)
+	if err != nil || summary == "" {
+		return TemplatePRBody(pr)
+	}
+	return summary + "\n\n" + prFooter(pr)
+}
+
+// TemplatePRBody renders a PR body from the bead fields and check results.
+func TemplatePRBody(pr PRDescription) string {
+	var b strings.Builder
+	if pr.Description != "" {
+		fmt.Fprintf(&b, "## Summary\n\n%s\n\n", strings.TrimSpace(pr.Description))
+	}
+	if pr.Acceptance != "" {
+		fmt.Fprintf(&b, "## Acceptance Criteria\n\n%s\n\n", strings.TrimSpace(pr.Acceptance))
+	}
+	b.WriteString(prFooter(pr))
+	return b.String()
+}
+
+// PRSummaryPrompt asks for a changelog-style PR body covering the diff,
+// check results and acceptance criteria.
+func PRSummaryPrompt(pr PRDescription) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `Write the description of a pull request for task %s: %s.
+Reply with markdown only, no preamble. Use these sections:
+## Summary - one or two sentences on what changed and why.
+## Changes - a changelog-style bullet list of user-visible and notable code changes.
+## Acceptance Criteria - for each criterion, whether the diff meets it.
+## Testing - what the check results below show.
+`, pr.BeadID, pr.Title)
+	if pr.Description != "" {
+		fmt.Fprintf(&b, "\nTASK DESCRIPTION:\n%s\n", strings.TrimSpace(pr.Description))
+	}
+	if pr.Acceptance != "" {
+		fmt.Fprintf(&b, "\nACCEPTANCE CRITERIA:\n%s\n", strings.TrimSpace(pr.Acceptance))
+	}
+	if len(pr.Checks) > 0 {
+		b.WriteString("\nCHECK RESULTS:\n")
+		for _, c := range pr.Checks {
+			fmt.Fprintf(&b, "- %s: %s\n", c.Command, checkStatus(c))
+		}
+	}
+	fmt.Fprintf(&b, "\nDIFF:\n%s\n", TruncateDiff(pr.Diff, maxSummaryDiffBytes))
+	return b.String()
+}
+
+// prFooter lists check results and the bead reference under every PR body.
+func prFooter(pr PRDescription) string {
+	var b strings.Builder
+	if len(pr.Checks) > 0 {
+		b.WriteString("## Checks\n\n")
+		for _, c := range pr.Checks {
+			fmt.Fprintf(&b, "- `%s`: %s\n", c.Command, checkStatus(c))
+		}
+		b.WriteString("\n")
+	}
+	if pr.BeadID != "" {
+		fmt.Fprintf(&b, "Bead: %s\n", pr.BeadID)
+	}
+	return b.String()
+}
+
+func checkStatus(c CheckResult) string {
+	if c.Passed {
+		return "passed"
+	}
+	return fmt.Sprintf("failed (exit %d)", c.ExitCode)
+}
+
 // GetPRStatus checks if a PR exists and its status using gh CLI
 func GetPRStatus(workspace, branch string) (*PRStatus, error) {
-	cmd := exec.Command("gh", "pr", "view", branch, "--json", "number,url,state,reviewDecision")
-	cmd.Dir = workspace
-	out, err := cmd.CombinedOutput()
+	out, err := run(workspace, "gh", "pr", "view", branch, "--json", "number,url,state,reviewDecision")
 	if err != nil {
 		if strings.Contains(string(out), "no pull requests found") {
 			return nil, nil
 		}
-		return nil, fmt.Errorf("failed to get PR status: %w (%s)", err, strings.TrimSpace(string(out)))
+		return nil, fmt.Errorf("failed to get PR status: %w", err)
 	}
 
 	var status PRStatus
@@ -60,3 +178,162 @@ func GetPRStatus(workspace, branch string) (*PRStatus, error) {
 
 	return &status, nil
 }
+
+// PRReviewActivity summarizes reviewer engagement on a pull request.
+// FirstReviewAt is zero when nobody other than the PR author has reviewed or commented.
+type PRReviewActivity struct {
+	State         string
+	CreatedAt     time.Time
+	FirstReviewAt time.Time
+}
+
+// GetPRReviewActivity returns the PR state and the time of the first review
+// or comment by someone other than the author, using gh CLI.
+func GetPRReviewActivity(workspace string, prNumber int) (*PRReviewActivity, error) {
+	out, err := run(workspace, "gh", "pr", "view", strconv.Itoa(prNumber), "--json", "state,createdAt,author,reviews,comments")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get PR review activity: %w", err)
+	}
+	return parsePRReviewActivity(out)
+}
+
+func parsePRReviewActivity(raw []byte) (*PRReviewActivity, error) {
+	type author struct {
+		Login string `json:"login"`
+	}
+	var view struct {
+		State     string    `json:"state"`
+		CreatedAt time.Time `json:"createdAt"`
+		Author    author    `json:"author"`
+		Reviews   []struct {
+			Author      author    `json:"author"`
+			SubmittedAt time.Time `json:"submittedAt"`
+		} `json:"reviews"`
+		Comments []struct {
+			Author    author    `json:"author"`
+			CreatedAt time.Time `json:"createdAt"`
+		} `json:"comments"`
+	}
+	if err := json.Unmarshal(raw, &view); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal PR review activity: %w", err)
+	}
+
+	activity := &PRReviewActivity{State: view.State, CreatedAt: view.CreatedAt}
+	consider := func(login string, at time.Time) {
+		if at.IsZero() || login == view.Author.Login {
+			return
+		}
+		if activity.FirstReviewAt.IsZero() || at.Before(activity.FirstReviewAt) {
+			activity.FirstReviewAt = at
+		}
+	}
+	for _, r := range view.Reviews {
+		consider(r.Author.Login, r.SubmittedAt)
+	}
+	for _, c := range view.Comments {
+		consider(c.Author.Login, c.CreatedAt)
+	}
+	return activity, nil
+}
+
+// CommentOnPR posts a comment on a pull request using gh CLI.
+func CommentOnPR(workspace string, prNumber int, body string) error {
+	if _, err := run(workspace, "gh", "pr", "comment", strconv.Itoa(prNumber), "--body", body); err != nil {
+		return fmt.Errorf("failed to comment on PR: %w", err)
+	}
+	return nil
+}
+
+// PRReviewThread is an inline review conversation on a pull request.
+type PRReviewThread struct {
+	ID       string
+	Path     string
+	Line     int
+	Author   string // author of the first comment
+	Body     string // comments in order, one per line, prefixed by their author
+	Resolved bool
+	Outdated bool
+}
+
+const prReviewThreadsQuery = `query($owner: String!, $repo: String!, $number: Int!) {
+  repository(owner: $owner, name: $repo) {
+    pullRequest(number: $number) {
+      state
+      reviewThreads(first: 100) {
+        nodes {
+          id isResolved isOutdated path line
+          comments(first: 20) { nodes { author { login } body } }
+        }
+      }
+    }
+  }
+}`
+
+// ListPRReviewThreads returns the PR state and its review threads, using the
+// gh CLI's GraphQL API for the repository of workspace.
+func ListPRReviewThreads(workspace string, prNumber int) (string, []PRReviewThread, error) {
+	out, err := run(workspace, "gh", "api", "graphql",
+		"-F", "owner={owner}", "-F", "repo={repo}", "-F", "number="+strconv.Itoa(prNumber),
+		"-f", "query="+prReviewThreadsQuery)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to list PR review threads: %w", err)
+	}
+	return parsePRReviewThreads(out)
+}
+
+func parsePRReviewThreads(raw []byte) (string, []PRReviewThread, error) {
+	var resp struct {
+		Data struct {
+			Repository struct {
+				PullRequest struct {
+					State         string `json:"state"`
+					ReviewThreads struct {
+						Nodes []struct {
+							ID         string `json:"id"`
+							IsResolved bool   `json:"isResolved"`
+							IsOutdated bool   `json:"isOutdated"`
+							Path       string `json:"path"`
+							Line       int    `json:"line"`
+							Comments   struct {
+								Nodes []struct {
+									Author struct {
+										Login string `json:"login"`
+									} `json:"author"`
+									Body string `json:"body"`
+								} `json:"nodes"`
+							} `json:"comments"`
+						} `json:"nodes"`
+					} `json:"reviewThreads"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return "", nil, fmt.Errorf("failed to unmarshal PR review threads: %w", err)
+	}
+
+	pr := resp.Data.Repository.PullRequest
+	var threads []PRReviewThread
+	for _, n := range pr.ReviewThreads.Nodes {
+		t := PRReviewThread{ID: n.ID, Path: n.Path, Line: n.Line, Resolved: n.IsResolved, Outdated: n.IsOutdated}
+		var body []string
+		for i, c := range n.Comments.Nodes {
+			if i == 0 {
+				t.Author = c.Author.Login
+			}
+			body = append(body, fmt.Sprintf("%s: %s", c.Author.Login, strings.TrimSpace(c.Body)))
+		}
+		t.Body = strings.Join(body, "\n")
+		threads = append(threads, t)
+	}
+	return pr.State, threads, nil
+}
+
+// ResolvePRReviewThread marks a review thread resolved using gh CLI.
+func ResolvePRReviewThread(workspace, threadID string) error {
+	if _, err := run(workspace, "gh", "api", "graphql", "-F", "threadId="+threadID,
+		"-f", "query=mutation($threadId: ID!) { resolveReviewThread(input: {threadId: $threadId}) { thread { isResolved } } }"); err != nil {
+		return fmt.Errorf("failed to resolve PR review thread %s: %w", threadID, err)
+	}
+	return nil
+}
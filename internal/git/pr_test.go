@@ -0,0 +1,117 @@
+package git
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParsePRReviewActivityIgnoresAuthor(t *testing.T) {
+	raw := []byte(`{
+		"state": "OPEN",
+		"createdAt": "2026-01-10T08:00:00Z",
+		"author": {"login": "cortex-bot"},
+		"reviews": [
+			{"author": {"login": "cortex-bot"}, "submittedAt": "2026-01-10T08:05:00Z"},
+			{"author": {"login": "alice"}, "submittedAt": "2026-01-11T09:00:00Z"}
+		],
+		"comments": [
+			{"author": {"login": "cortex-bot"}, "createdAt": "2026-01-10T08:01:00Z"},
+			{"author": {"login": "bob"}, "createdAt": "2026-01-10T20:00:00Z"}
+		]
+	}`)
+
+	activity, err := parsePRReviewActivity(raw)
+	if err != nil {
+		t.Fatalf("parsePRReviewActivity: %v", err)
+	}
+	if activity.State != "OPEN" || !activity.CreatedAt.Equal(time.Date(2026, 1, 10, 8, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected PR metadata: %+v", activity)
+	}
+	if want := time.Date(2026, 1, 10, 20, 0, 0, 0, time.UTC); !activity.FirstReviewAt.Equal(want) {
+		t.Fatalf("first review = %v, want %v", activity.FirstReviewAt, want)
+	}
+}
+
+func TestParsePRReviewActivityNoReviewers(t *testing.T) {
+	activity, err := parsePRReviewActivity([]byte(`{"state":"OPEN","author":{"login":"me"},"reviews":[],"comments":[{"author":{"login":"me"},"createdAt":"2026-01-10T08:01:00Z"}]}`))
+	if err != nil {
+		t.Fatalf("parsePRReviewActivity: %v", err)
+	}
+	if !activity.FirstReviewAt.IsZero() {
+		t.Fatalf("expected no reviewer activity, got %v", activity.FirstReviewAt)
+	}
+}
+
+func TestParsePRReviewThreads(t *testing.T) {
+	raw := []byte(`{"data":{"repository":{"pullRequest":{"state":"OPEN","reviewThreads":{"nodes":[
+		{"id":"T1","isResolved":false,"isOutdated":false,"path":"main.go","line":12,
+		 "comments":{"nodes":[{"author":{"login":"alice"},"body":"Handle the error here. "},{"author":{"login":"cortex-bot"},"body":"Will do"}]}},
+		{"id":"T2","isResolved":true,"isOutdated":false,"path":"util.go","line":3,
+		 "comments":{"nodes":[{"author":{"login":"bob"},"body":"nit"}]}}
+	]}}}}}`)
+
+	state, threads, err := parsePRReviewThreads(raw)
+	if err != nil {
+		t.Fatalf("parsePRReviewThreads: %v", err)
+	}
+	if state != "OPEN" || len(threads) != 2 {
+		t.Fatalf("unexpected result: state=%q threads=%+v", state, threads)
+	}
+	first := threads[0]
+	if first.ID != "T1" || first.Path != "main.go" || first.Line != 12 || first.Author != "alice" || first.Resolved {
+		t.Fatalf("unexpected first thread: %+v", first)
+	}
+	if first.Body != "alice: Handle the error here.\ncortex-bot: Will do" {
+		t.Fatalf("unexpected first thread body: %q", first.Body)
+	}
+	if !threads[1].Resolved {
+		t.Fatalf("expected second thread resolved: %+v", threads[1])
+	}
+}
+
+func TestDescribePR(t *testing.T) {
+	pr := PRDescription{
+		BeadID:      "cx-7",
+		Title:       "Add retries",
+		Description: "Retry flaky fetches.",
+		Acceptance:  "Fetch retries three times",
+		Diff:        "+retry := 3\n",
+		Checks:      []CheckResult{{Command: "go test ./...", Passed: true}, {Command: "go vet ./...", ExitCode: 1}},
+	}
+
+	var prompt string
+	body := DescribePR(pr, func(p string) (string, error) {
+		prompt = p
+		return "## Summary\n\nAdds fetch retries.\n", nil
+	})
+	for _, want := range []string{"cx-7: Add retries", "Fetch retries three times", "- go vet ./...: failed (exit 1)", "+retry := 3"} {
+		if !strings.Contains(prompt, want) {
+			t.Fatalf("prompt missing %q:\n%s", want, prompt)
+		}
+	}
+	if !strings.HasPrefix(body, "## Summary\n\nAdds fetch retries.") || !strings.Contains(body, "- `go test ./...`: passed") || !strings.HasSuffix(body, "Bead: cx-7\n") {
+		t.Fatalf("unexpected summarized body:\n%s", body)
+	}
+
+	template := TemplatePRBody(pr)
+	for name, summarize := range map[string]Summarizer{
+		"nil summarizer": nil,
+		"failed call":    func(string) (string, error) { return "", errors.New("rate limited") },
+		"empty answer":   func(string) (string, error) { return "  \n", nil },
+	} {
+		if got := DescribePR(pr, summarize); got != template {
+			t.Fatalf("%s: expected templated body, got:\n%s", name, got)
+		}
+	}
+	if !strings.Contains(template, "## Acceptance Criteria\n\nFetch retries three times") {
+		t.Fatalf("unexpected templated body:\n%s", template)
+	}
+
+	noDiff := pr
+	noDiff.Diff = ""
+	if got := DescribePR(noDiff, func(string) (string, error) { t.Fatal("summarizer called without a diff"); return "", nil }); got != TemplatePRBody(noDiff) {
+		t.Fatalf("expected templated body without a diff, got:\n%s", got)
+	}
+}
@@ -0,0 +1,70 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/antigravity-dev/cortex/internal/cmdexec"
+)
+
+// HasUncommittedChanges reports whether workspace has staged, unstaged or
+// untracked changes.
+func HasUncommittedChanges(workspace string) (bool, error) {
+	out, err := run(workspace, "git", "status", "--porcelain")
+	if err != nil {
+		return false, fmt.Errorf("failed to read working tree status: %w", err)
+	}
+	return strings.TrimSpace(string(out)) != "", nil
+}
+
+// WorkTreeID returns the tree hash of workspace's files as they are on
+// disk, untracked ones included, so two calls can tell whether anything in
+// the work tree changed in between. A temporary index keeps the real one
+// untouched.
+func WorkTreeID(workspace string) (string, error) {
+	index, err := os.CreateTemp("", "cortex-index-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary index: %w", err)
+	}
+	index.Close()
+	// git refuses an empty index file but creates a missing one.
+	os.Remove(index.Name())
+	defer os.Remove(index.Name())
+
+	env := []string{"GIT_INDEX_FILE=" + index.Name()}
+	for _, args := range [][]string{{"add", "-A"}, {"write-tree"}} {
+		res, err := cmdexec.Run(context.Background(), cmdexec.Cmd{
+			Name:           "git",
+			Args:           args,
+			Dir:            workspace,
+			Env:            env,
+			CombinedOutput: true,
+			MaxOutput:      maxCommandOutput,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to hash work tree: %w", err)
+		}
+		if args[0] == "write-tree" {
+			return res.Text(), nil
+		}
+	}
+	return "", nil
+}
+
+// CommitAll stages every change in workspace and commits it, reporting
+// whether there was anything to commit.
+func CommitAll(workspace, message string) (bool, error) {
+	dirty, err := HasUncommittedChanges(workspace)
+	if err != nil || !dirty {
+		return false, err
+	}
+	if _, err := runGitCommand(workspace, "add", "-A"); err != nil {
+		return false, fmt.Errorf("failed to stage changes: %w", err)
+	}
+	if _, err := runGitCommand(workspace, "commit", "-m", message); err != nil {
+		return false, fmt.Errorf("failed to commit changes: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,56 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWorkTreeID(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	before, err := WorkTreeID(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := WorkTreeID(repo); again != before {
+		t.Fatalf("unchanged tree hashed to %s and %s", before, again)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "new.go"), []byte("package x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	after, err := WorkTreeID(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after == before {
+		t.Fatal("untracked file did not change the work tree ID")
+	}
+	if status := runGit(t, repo, "status", "--porcelain"); !strings.HasPrefix(status, "??") {
+		t.Fatalf("WorkTreeID touched the index: %q", status)
+	}
+}
+
+func TestCommitAll(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	if committed, err := CommitAll(repo, "nothing"); err != nil || committed {
+		t.Fatalf("clean tree: committed=%v err=%v", committed, err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "fmt.go"), []byte("package x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if dirty, err := HasUncommittedChanges(repo); err != nil || !dirty {
+		t.Fatalf("untracked file: dirty=%v err=%v", dirty, err)
+	}
+	if committed, err := CommitAll(repo, "style: format"); err != nil || !committed {
+		t.Fatalf("dirty tree: committed=%v err=%v", committed, err)
+	}
+	if dirty, _ := HasUncommittedChanges(repo); dirty {
+		t.Fatal("tree still dirty after CommitAll")
+	}
+	if msg := strings.TrimSpace(runGit(t, repo, "log", "-1", "--format=%s")); msg != "style: format" {
+		t.Fatalf("commit message = %q", msg)
+	}
+}
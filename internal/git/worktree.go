@@ -0,0 +1,121 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Worktree is a working tree attached to a repository.
+type Worktree struct {
+	Path     string
+	Branch   string // empty when detached
+	Prunable bool   // its directory is gone
+}
+
+// ListWorktrees returns the worktrees of the repository at workspace,
+// starting with the main one.
+func ListWorktrees(workspace string) ([]Worktree, error) {
+	out, err := runGitCommand(workspace, "worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	var worktrees []Worktree
+	for _, block := range strings.Split(out, "\n\n") {
+		var wt Worktree
+		for _, line := range strings.Split(strings.TrimSpace(block), "\n") {
+			key, value, _ := strings.Cut(line, " ")
+			switch key {
+			case "worktree":
+				wt.Path = value
+			case "branch":
+				wt.Branch = strings.TrimPrefix(value, "refs/heads/")
+			case "prunable":
+				wt.Prunable = true
+			}
+		}
+		if wt.Path != "" {
+			worktrees = append(worktrees, wt)
+		}
+	}
+	return worktrees, nil
+}
+
+// CheckoutWorktree points the worktree at path to a clean checkout of
+// branch, creating the worktree when path is not one yet. An existing branch
+// is checked out as is and fails while another worktree has it; a new one
+// starts from base. With no branch the worktree is detached at base. Leftover
+// changes and untracked files from a previous checkout are discarded.
+func CheckoutWorktree(workspace, path, branch, base string) error {
+	if base == "" {
+		base = "HEAD"
+	}
+	start, exists := base, false
+	if branch != "" {
+		var err error
+		exists, err = BranchExists(workspace, branch)
+		if err != nil {
+			return err
+		}
+		if exists {
+			start = branch
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(path, ".git")); err != nil {
+		// Forget a registration whose directory was removed by hand.
+		_, _ = runGitCommand(workspace, "worktree", "prune")
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("failed to create worktree parent for %s: %w", path, err)
+		}
+		if _, err := runGitCommand(workspace, "worktree", "add", "--force", "--detach", path, start); err != nil {
+			return fmt.Errorf("failed to add worktree %s: %w", path, err)
+		}
+	}
+
+	if _, err := runGitCommand(path, "reset", "--hard", "--quiet"); err != nil {
+		return fmt.Errorf("failed to reset worktree %s: %w", path, err)
+	}
+	if _, err := runGitCommand(path, "clean", "-fdx", "--quiet"); err != nil {
+		return fmt.Errorf("failed to clean worktree %s: %w", path, err)
+	}
+	// A plain checkout of an existing branch is refused while another
+	// worktree has it, which -B would override.
+	args := []string{"checkout", "--quiet", "--detach", start}
+	switch {
+	case exists:
+		args = []string{"checkout", "--quiet", branch}
+	case branch != "":
+		args = []string{"checkout", "--quiet", "-b", branch, start}
+	}
+	if _, err := runGitCommand(path, args...); err != nil {
+		return fmt.Errorf("failed to check out %s in worktree %s: %w", start, path, err)
+	}
+	return nil
+}
+
+// ResetWorktree discards every change in the worktree at path and detaches
+// it, so the branch it had is free for other worktrees. The worktree itself
+// is kept for reuse.
+func ResetWorktree(path string) error {
+	if _, err := runGitCommand(path, "reset", "--hard", "--quiet"); err != nil {
+		return fmt.Errorf("failed to reset worktree %s: %w", path, err)
+	}
+	if _, err := runGitCommand(path, "clean", "-fdx", "--quiet"); err != nil {
+		return fmt.Errorf("failed to clean worktree %s: %w", path, err)
+	}
+	if _, err := runGitCommand(path, "checkout", "--quiet", "--detach"); err != nil {
+		return fmt.Errorf("failed to detach worktree %s: %w", path, err)
+	}
+	return nil
+}
+
+// RemoveWorktree deletes the worktree at path and its registration.
+func RemoveWorktree(workspace, path string) error {
+	if _, err := runGitCommand(workspace, "worktree", "remove", "--force", path); err != nil {
+		return fmt.Errorf("failed to remove worktree %s: %w", path, err)
+	}
+	return nil
+}
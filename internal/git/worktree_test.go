@@ -0,0 +1,73 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckoutWorktree(t *testing.T) {
+	repo := setupTestRepo(t)
+	path := filepath.Join(t.TempDir(), "pool", "0")
+
+	if err := CheckoutWorktree(repo, path, "feat/a", ""); err != nil {
+		t.Fatalf("CheckoutWorktree: %v", err)
+	}
+	if b, err := GetCurrentBranch(path); err != nil || b != "feat/a" {
+		t.Fatalf("branch = %q, %v; want feat/a", b, err)
+	}
+
+	// Leftovers from the previous dispatch are discarded on reuse.
+	if err := os.WriteFile(filepath.Join(path, "README.md"), []byte("edited\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(path, "stray.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckoutWorktree(repo, path, "feat/b", ""); err != nil {
+		t.Fatalf("CheckoutWorktree reuse: %v", err)
+	}
+	if b, _ := GetCurrentBranch(path); b != "feat/b" {
+		t.Fatalf("branch = %q, want feat/b", b)
+	}
+	if _, err := os.Stat(filepath.Join(path, "stray.txt")); !os.IsNotExist(err) {
+		t.Fatalf("untracked file survived checkout: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(path, "README.md")); string(data) != "# Test Repo\n" {
+		t.Fatalf("README.md = %q, want reset content", data)
+	}
+
+	worktrees, err := ListWorktrees(repo)
+	if err != nil {
+		t.Fatalf("ListWorktrees: %v", err)
+	}
+	if len(worktrees) != 2 || worktrees[1].Branch != "feat/b" {
+		t.Fatalf("worktrees = %+v, want main plus feat/b", worktrees)
+	}
+}
+
+func TestResetWorktreeFreesBranch(t *testing.T) {
+	repo := setupTestRepo(t)
+	first := filepath.Join(t.TempDir(), "0")
+	second := filepath.Join(t.TempDir(), "1")
+
+	if err := CheckoutWorktree(repo, first, "feat/shared", ""); err != nil {
+		t.Fatalf("CheckoutWorktree: %v", err)
+	}
+	if err := CheckoutWorktree(repo, second, "feat/shared", ""); err == nil {
+		t.Fatal("checked out a branch another worktree has")
+	}
+	if err := ResetWorktree(first); err != nil {
+		t.Fatalf("ResetWorktree: %v", err)
+	}
+	if err := CheckoutWorktree(repo, second, "feat/shared", ""); err != nil {
+		t.Fatalf("CheckoutWorktree after reset: %v", err)
+	}
+
+	if err := RemoveWorktree(repo, first); err != nil {
+		t.Fatalf("RemoveWorktree: %v", err)
+	}
+	if _, err := os.Stat(first); !os.IsNotExist(err) {
+		t.Fatalf("worktree directory still present: %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package health
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/antigravity-dev/cortex/internal/cmdexec"
+)
+
+// CommandWatchdog tracks which external commands are stuck: their last
+// breaches runs all timed out. The zero value has no stuck commands.
+type CommandWatchdog struct {
+	mu    sync.Mutex
+	stuck map[string]bool
+}
+
+// Update checks the latest command stats and returns the commands that
+// became stuck and the names of those that recovered since the previous
+// update. A breaches of 0 or less turns the check off, so every stuck
+// command recovers.
+func (w *CommandWatchdog) Update(stats []cmdexec.Stat, breaches int) (stuck []cmdexec.Stat, recovered []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stuck == nil {
+		w.stuck = map[string]bool{}
+	}
+	now := map[string]bool{}
+	for _, st := range stats {
+		if breaches <= 0 || st.Streak < int64(breaches) {
+			continue
+		}
+		now[st.Command] = true
+		if !w.stuck[st.Command] {
+			stuck = append(stuck, st)
+		}
+	}
+	for name := range w.stuck {
+		if !now[name] {
+			recovered = append(recovered, name)
+		}
+	}
+	sort.Strings(recovered)
+	w.stuck = now
+	return stuck, recovered
+}
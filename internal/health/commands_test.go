@@ -0,0 +1,32 @@
+package health
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/antigravity-dev/cortex/internal/cmdexec"
+)
+
+func TestCommandWatchdogTransitions(t *testing.T) {
+	var w CommandWatchdog
+	stats := []cmdexec.Stat{{Command: "bd list", Streak: 2}, {Command: "gh pr", Streak: 3}}
+	stuck, recovered := w.Update(stats, 3)
+	if len(stuck) != 1 || stuck[0].Command != "gh pr" || recovered != nil {
+		t.Fatalf("first update stuck %+v recovered %v", stuck, recovered)
+	}
+
+	stats = []cmdexec.Stat{{Command: "bd list", Streak: 3}, {Command: "gh pr", Streak: 4}}
+	stuck, recovered = w.Update(stats, 3)
+	if len(stuck) != 1 || stuck[0].Command != "bd list" || recovered != nil {
+		t.Fatalf("a command already stuck must not be reported again, got %+v %v", stuck, recovered)
+	}
+
+	stats = []cmdexec.Stat{{Command: "bd list", Streak: 0}, {Command: "gh pr", Streak: 5}}
+	if stuck, recovered = w.Update(stats, 3); stuck != nil || !reflect.DeepEqual(recovered, []string{"bd list"}) {
+		t.Fatalf("expected bd list recovered, got %+v %v", stuck, recovered)
+	}
+
+	if stuck, recovered = w.Update(stats, 0); stuck != nil || !reflect.DeepEqual(recovered, []string{"gh pr"}) {
+		t.Fatalf("expected the check off to clear gh pr, got %+v %v", stuck, recovered)
+	}
+}
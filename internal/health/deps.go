@@ -0,0 +1,183 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os/exec"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Dependency is an external tool or service cortex relies on. Probe returns
+// nil while it is usable.
+type Dependency struct {
+	Name     string
+	Target   string // the executable, address, URL or unit probed
+	Required bool   // dispatches fail without it
+	Probe    func(ctx context.Context) error
+}
+
+// DependencyStatus is the outcome of probing one dependency.
+type DependencyStatus struct {
+	Name      string `json:"name"`
+	Target    string `json:"target"`
+	Required  bool   `json:"required"`
+	OK        bool   `json:"ok"`
+	Error     string `json:"error,omitempty"`
+	LatencyMS int64  `json:"latency_ms"`
+}
+
+// DependencyReport is the outcome of one round of probes.
+type DependencyReport struct {
+	CheckedAt    time.Time          `json:"checked_at"`
+	Dependencies []DependencyStatus `json:"dependencies"`
+}
+
+// OK reports whether every required dependency is usable.
+func (r DependencyReport) OK() bool {
+	for _, d := range r.Dependencies {
+		if d.Required && !d.OK {
+			return false
+		}
+	}
+	return true
+}
+
+// Failing returns the names of the dependencies whose probe failed.
+func (r DependencyReport) Failing() []string {
+	var names []string
+	for _, d := range r.Dependencies {
+		if !d.OK {
+			names = append(names, d.Name)
+		}
+	}
+	return names
+}
+
+// CommandDependency probes that an executable is on PATH.
+func CommandDependency(name, cmd string, required bool) Dependency {
+	return Dependency{Name: name, Target: cmd, Required: required, Probe: func(context.Context) error {
+		_, err := exec.LookPath(cmd)
+		return err
+	}}
+}
+
+// TCPDependency probes that addr accepts connections.
+func TCPDependency(name, addr string, required bool) Dependency {
+	return Dependency{Name: name, Target: addr, Required: required, Probe: func(ctx context.Context) error {
+		conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}}
+}
+
+// HTTPDependency probes that url answers a GET without a server error.
+func HTTPDependency(name, url string, required bool) Dependency {
+	return Dependency{Name: name, Target: url, Required: required, Probe: func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 500 {
+			return fmt.Errorf("status %d", resp.StatusCode)
+		}
+		return nil
+	}}
+}
+
+// SystemdDependency probes that a systemd unit is active, in the user's
+// service manager when user is set.
+func SystemdDependency(name, unit string, user, required bool) Dependency {
+	return Dependency{Name: name, Target: unit, Required: required, Probe: func(ctx context.Context) error {
+		args := []string{"is-active", "--quiet", unit}
+		if user {
+			args = append([]string{"--user"}, args...)
+		}
+		if err := exec.CommandContext(ctx, "systemctl", args...).Run(); err != nil {
+			return fmt.Errorf("unit not active: %w", err)
+		}
+		return nil
+	}}
+}
+
+// CheckDependencies probes deps concurrently, each within timeout, and
+// reports them sorted by name.
+func CheckDependencies(ctx context.Context, deps []Dependency, timeout time.Duration) DependencyReport {
+	report := DependencyReport{CheckedAt: time.Now(), Dependencies: make([]DependencyStatus, len(deps))}
+	var wg sync.WaitGroup
+	for i, dep := range deps {
+		wg.Add(1)
+		go func(i int, dep Dependency) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			start := time.Now()
+			err := dep.Probe(ctx)
+			st := DependencyStatus{
+				Name:      dep.Name,
+				Target:    dep.Target,
+				Required:  dep.Required,
+				OK:        err == nil,
+				LatencyMS: time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				st.Error = err.Error()
+			}
+			report.Dependencies[i] = st
+		}(i, dep)
+	}
+	wg.Wait()
+	sort.Slice(report.Dependencies, func(i, j int) bool { return report.Dependencies[i].Name < report.Dependencies[j].Name })
+	return report
+}
+
+// DependencyMonitor holds the latest dependency probes. A nil monitor has
+// no report.
+type DependencyMonitor struct {
+	mu     sync.Mutex
+	report DependencyReport
+	seen   bool
+}
+
+// Update stores r and returns the dependencies that went down and the ones
+// that came back since the previous report. On the first report every
+// failing dependency counts as down.
+func (m *DependencyMonitor) Update(r DependencyReport) (down, up []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	was := make(map[string]bool)
+	for _, d := range m.report.Dependencies {
+		was[d.Name] = d.OK
+	}
+	for _, d := range r.Dependencies {
+		ok, known := was[d.Name]
+		switch {
+		case !d.OK && (!m.seen || !known || ok):
+			down = append(down, d.Name)
+		case d.OK && known && !ok:
+			up = append(up, d.Name)
+		}
+	}
+	m.report, m.seen = r, true
+	return down, up
+}
+
+// Report returns the latest probes, and false before the first round.
+func (m *DependencyMonitor) Report() (DependencyReport, bool) {
+	if m == nil {
+		return DependencyReport{}, false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.report, m.seen
+}
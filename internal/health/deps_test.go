@@ -0,0 +1,84 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCheckDependencies(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close() // nothing listens there now
+
+	report := CheckDependencies(context.Background(), []Dependency{
+		CommandDependency("sh", "sh", true),
+		CommandDependency("missing", "cortex-no-such-tool", false),
+		HTTPDependency("http-up", srv.URL+"/", true),
+		HTTPDependency("http-down", srv.URL+"/down", false),
+		TCPDependency("tcp", addr, false),
+		{Name: "slow", Probe: func(ctx context.Context) error { <-ctx.Done(); return ctx.Err() }},
+	}, 200*time.Millisecond)
+
+	ok := map[string]bool{}
+	for _, d := range report.Dependencies {
+		ok[d.Name] = d.OK
+	}
+	want := map[string]bool{"sh": true, "missing": false, "http-up": true, "http-down": false, "tcp": false, "slow": false}
+	for name, w := range want {
+		if ok[name] != w {
+			t.Errorf("%s ok = %v, want %v", name, ok[name], w)
+		}
+	}
+	if !report.OK() {
+		t.Fatalf("report with only optional failures is not OK: %+v", report)
+	}
+	if report.Dependencies[0].Name != "http-down" {
+		t.Fatalf("not sorted by name: %+v", report.Dependencies)
+	}
+}
+
+func TestDependencyMonitorTransitions(t *testing.T) {
+	var m *DependencyMonitor
+	if _, seen := m.Report(); seen {
+		t.Fatal("nil monitor has a report")
+	}
+
+	m = &DependencyMonitor{}
+	probe := func(errs map[string]error) DependencyReport {
+		var deps []Dependency
+		for _, name := range []string{"bd", "temporal"} {
+			err := errs[name]
+			deps = append(deps, Dependency{Name: name, Required: true, Probe: func(context.Context) error { return err }})
+		}
+		return CheckDependencies(context.Background(), deps, time.Second)
+	}
+
+	down, up := m.Update(probe(map[string]error{"temporal": errors.New("refused")}))
+	if len(down) != 1 || down[0] != "temporal" || len(up) != 0 {
+		t.Fatalf("first round: down %v up %v", down, up)
+	}
+	if down, up := m.Update(probe(map[string]error{"temporal": errors.New("refused")})); len(down)+len(up) != 0 {
+		t.Fatalf("unchanged round: down %v up %v", down, up)
+	}
+	down, up = m.Update(probe(nil))
+	if len(down) != 0 || len(up) != 1 || up[0] != "temporal" {
+		t.Fatalf("recovery: down %v up %v", down, up)
+	}
+	if r, seen := m.Report(); !seen || !r.OK() {
+		t.Fatalf("report = %+v, %v", r, seen)
+	}
+}
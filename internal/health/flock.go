@@ -0,0 +1,63 @@
+// Package health holds process-level safety checks for the cortex daemon.
+package health
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// ErrLocked is returned by AcquireFlock when another process holds the lock.
+var ErrLocked = errors.New("lock held by another process")
+
+// Flock is an exclusive advisory lock on a file, held until Release or exit.
+type Flock struct {
+	path string
+	f    *os.File
+}
+
+// AcquireFlock takes an exclusive lock on path without blocking, creating the
+// file if needed, and records the holder's PID in it. It uses flock(2) on
+// POSIX systems and LockFileEx on Windows; either way the OS drops the lock
+// when the holder exits, so a crashed daemon never leaves a stale lock.
+func AcquireFlock(path string) (*Flock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("health: create lock directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("health: open lock file: %w", err)
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("health: lock %s: %w", path, err)
+	}
+
+	if err := f.Truncate(0); err == nil {
+		f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+	return &Flock{path: path, f: f}, nil
+}
+
+// Path returns the lock file path.
+func (l *Flock) Path() string {
+	return l.path
+}
+
+// Release unlocks and closes the lock file. It is safe to call more than once.
+func (l *Flock) Release() error {
+	if l == nil || l.f == nil {
+		return nil
+	}
+	err := unlockFile(l.f)
+	if cerr := l.f.Close(); err == nil {
+		err = cerr
+	}
+	l.f = nil
+	if err != nil {
+		return fmt.Errorf("health: release %s: %w", l.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package health
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestAcquireFlockIsExclusive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run", "cortex.lock")
+
+	first, err := AcquireFlock(path)
+	if err != nil {
+		t.Fatalf("first acquire: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(data)); got != strconv.Itoa(os.Getpid()) {
+		t.Fatalf("lock file holds %q, want pid %d", got, os.Getpid())
+	}
+
+	if _, err := AcquireFlock(path); !errors.Is(err, ErrLocked) {
+		t.Fatalf("second acquire error = %v, want ErrLocked", err)
+	}
+
+	if err := first.Release(); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if err := first.Release(); err != nil {
+		t.Fatalf("second release: %v", err)
+	}
+
+	again, err := AcquireFlock(path)
+	if err != nil {
+		t.Fatalf("acquire after release: %v", err)
+	}
+	again.Release()
+}
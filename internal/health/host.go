@@ -0,0 +1,145 @@
+package health
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HostLimits are the resources a dispatch host must keep free. A zero limit
+// disables its check.
+type HostLimits struct {
+	MinFreeDiskMB   int
+	MinFreeMemoryMB int
+	MaxLoadPerCPU   float64
+	Paths           []string // directories whose volumes need MinFreeDiskMB
+}
+
+// HostStatus is the outcome of one host check.
+type HostStatus struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Breaches  []string  `json:"breaches,omitempty"`
+}
+
+// OK reports whether every limit held.
+func (s HostStatus) OK() bool {
+	return len(s.Breaches) == 0
+}
+
+// Where load and memory are read from; the files only exist on Linux, so
+// elsewhere those checks are skipped.
+var (
+	loadavgPath = "/proc/loadavg"
+	meminfoPath = "/proc/meminfo"
+)
+
+// CheckHost compares free disk on each path's volume, available memory and
+// the load average against limits. Paths that cannot be read, such as a
+// workspace not cloned yet, are skipped rather than reported.
+func CheckHost(limits HostLimits) HostStatus {
+	st := HostStatus{CheckedAt: time.Now()}
+
+	if limits.MinFreeDiskMB > 0 {
+		seen := make(map[string]bool, len(limits.Paths))
+		for _, p := range limits.Paths {
+			if p == "" || seen[p] {
+				continue
+			}
+			seen[p] = true
+			free, err := diskFreeBytes(p)
+			if err != nil {
+				continue
+			}
+			if mb := free >> 20; mb < uint64(limits.MinFreeDiskMB) {
+				st.Breaches = append(st.Breaches, fmt.Sprintf("disk %s has %d MB free, below %d MB", p, mb, limits.MinFreeDiskMB))
+			}
+		}
+	}
+
+	if limits.MinFreeMemoryMB > 0 {
+		if data, err := os.ReadFile(meminfoPath); err == nil {
+			if kb, ok := parseMemAvailable(string(data)); ok && kb>>10 < uint64(limits.MinFreeMemoryMB) {
+				st.Breaches = append(st.Breaches, fmt.Sprintf("memory has %d MB available, below %d MB", kb>>10, limits.MinFreeMemoryMB))
+			}
+		}
+	}
+
+	if limits.MaxLoadPerCPU > 0 {
+		if data, err := os.ReadFile(loadavgPath); err == nil {
+			if load, ok := parseLoadavg(string(data)); ok {
+				if perCPU := load / float64(runtime.NumCPU()); perCPU > limits.MaxLoadPerCPU {
+					st.Breaches = append(st.Breaches, fmt.Sprintf("load average %.2f is %.2f per CPU, above %.2f", load, perCPU, limits.MaxLoadPerCPU))
+				}
+			}
+		}
+	}
+	return st
+}
+
+// parseLoadavg returns the 1-minute load average from /proc/loadavg.
+func parseLoadavg(data string) (float64, bool) {
+	fields := strings.Fields(data)
+	if len(fields) == 0 {
+		return 0, false
+	}
+	load, err := strconv.ParseFloat(fields[0], 64)
+	return load, err == nil
+}
+
+// parseMemAvailable returns MemAvailable in kB from /proc/meminfo.
+func parseMemAvailable(data string) (uint64, bool) {
+	for _, line := range strings.Split(data, "\n") {
+		value, ok := strings.CutPrefix(line, "MemAvailable:")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			return 0, false
+		}
+		kb, err := strconv.ParseUint(fields[0], 10, 64)
+		return kb, err == nil
+	}
+	return 0, false
+}
+
+// HostGuard holds the latest host check so dispatching can be paused while
+// the host is short of resources. The zero value, and a nil guard, hold
+// nothing.
+type HostGuard struct {
+	mu     sync.Mutex
+	status HostStatus
+}
+
+// Update stores st and reports whether the host went from healthy to
+// breached or back.
+func (g *HostGuard) Update(st HostStatus) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	changed := g.status.OK() != st.OK()
+	g.status = st
+	return changed
+}
+
+// Status returns the latest host check.
+func (g *HostGuard) Status() HostStatus {
+	if g == nil {
+		return HostStatus{}
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.status
+}
+
+// Held returns why new dispatches are paused, or "" if they may start.
+func (g *HostGuard) Held() string {
+	st := g.Status()
+	if st.OK() {
+		return ""
+	}
+	return "host resources low: " + strings.Join(st.Breaches, "; ")
+}
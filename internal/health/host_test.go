@@ -0,0 +1,65 @@
+package health
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckHost(t *testing.T) {
+	dir := t.TempDir()
+	loadavgPath = filepath.Join(dir, "loadavg")
+	meminfoPath = filepath.Join(dir, "meminfo")
+	t.Cleanup(func() { loadavgPath, meminfoPath = "/proc/loadavg", "/proc/meminfo" })
+	if err := os.WriteFile(loadavgPath, []byte("9999.00 1.00 1.00 1/100 1234\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(meminfoPath, []byte("MemTotal:       16384000 kB\nMemAvailable:     102400 kB\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Limits no host can meet, plus a path that does not exist.
+	st := CheckHost(HostLimits{
+		MinFreeDiskMB:   1 << 30,
+		MinFreeMemoryMB: 512,
+		MaxLoadPerCPU:   1,
+		Paths:           []string{dir, dir, filepath.Join(dir, "missing")},
+	})
+	if st.OK() || len(st.Breaches) != 3 {
+		t.Fatalf("breaches = %q, want disk, memory and load", st.Breaches)
+	}
+	for i, want := range []string{"disk " + dir, "memory has 100 MB", "load average 9999.00"} {
+		if !strings.HasPrefix(st.Breaches[i], want) {
+			t.Errorf("breach %d = %q, want prefix %q", i, st.Breaches[i], want)
+		}
+	}
+
+	if st := CheckHost(HostLimits{MinFreeDiskMB: 1, Paths: []string{dir}}); !st.OK() {
+		t.Fatalf("expected host within limits, got %q", st.Breaches)
+	}
+}
+
+func TestHostGuard(t *testing.T) {
+	var nilGuard *HostGuard
+	if reason := nilGuard.Held(); reason != "" {
+		t.Fatalf("nil guard held dispatch: %q", reason)
+	}
+
+	g := &HostGuard{}
+	if g.Update(HostStatus{}) {
+		t.Fatal("healthy to healthy reported as a change")
+	}
+	if !g.Update(HostStatus{Breaches: []string{"disk / has 1 MB free, below 2048 MB"}}) {
+		t.Fatal("healthy to breached not reported")
+	}
+	if g.Update(HostStatus{Breaches: []string{"disk / has 0 MB free, below 2048 MB"}}) {
+		t.Fatal("breached to breached reported as a change")
+	}
+	if reason := g.Held(); reason != "host resources low: disk / has 0 MB free, below 2048 MB" {
+		t.Fatalf("Held = %q", reason)
+	}
+	if !g.Update(HostStatus{}) || g.Held() != "" {
+		t.Fatal("recovery not reported or dispatch still held")
+	}
+}
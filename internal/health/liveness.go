@@ -0,0 +1,52 @@
+package health
+
+import (
+	"net"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// Heartbeat records when a loop last ran, so a stalled loop can be told
+// apart from a live one. The zero value, and a nil heartbeat, have never
+// beaten.
+type Heartbeat struct {
+	last atomic.Int64
+}
+
+// Beat records that the loop ran now.
+func (h *Heartbeat) Beat() {
+	h.last.Store(time.Now().UnixNano())
+}
+
+// Last returns when the loop last ran, or the zero time if it never has.
+func (h *Heartbeat) Last() time.Time {
+	if h == nil {
+		return time.Time{}
+	}
+	n := h.last.Load()
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
+
+// SdNotify sends state, such as "READY=1" or "WATCHDOG=1", to the systemd
+// service manager. It does nothing when the daemon was not started by
+// systemd with Type=notify or WatchdogSec=, i.e. NOTIFY_SOCKET is unset.
+func SdNotify(state string) error {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return nil
+	}
+	if socket[0] == '@' {
+		socket = "\x00" + socket[1:] // abstract socket
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Write([]byte(state))
+	return err
+}
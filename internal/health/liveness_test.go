@@ -0,0 +1,47 @@
+package health
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHeartbeat(t *testing.T) {
+	var nilBeat *Heartbeat
+	if !nilBeat.Last().IsZero() {
+		t.Fatal("nil heartbeat has beaten")
+	}
+	h := &Heartbeat{}
+	if !h.Last().IsZero() {
+		t.Fatal("new heartbeat has beaten")
+	}
+	h.Beat()
+	if since := time.Since(h.Last()); since < 0 || since > time.Second {
+		t.Fatalf("last beat %s ago", since)
+	}
+}
+
+func TestSdNotify(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	if err := SdNotify("READY=1"); err != nil {
+		t.Fatalf("without systemd: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram unavailable: %v", err)
+	}
+	defer conn.Close()
+	t.Setenv("NOTIFY_SOCKET", path)
+	if err := SdNotify("WATCHDOG=1"); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 64)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := conn.Read(buf)
+	if err != nil || string(buf[:n]) != "WATCHDOG=1" {
+		t.Fatalf("read %q, %v", buf[:n], err)
+	}
+}
@@ -0,0 +1,126 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/cmdexec"
+)
+
+// maxProjectCheckOutput caps the output kept from one project check run.
+const maxProjectCheckOutput = 2048
+
+// ProjectCheck is a custom health check a project defines.
+type ProjectCheck struct {
+	Project  string
+	Name     string
+	Command  string
+	Dir      string // the project workspace
+	Timeout  time.Duration
+	Failures int // consecutive failed runs before the check is failing
+}
+
+// ProjectCheckStatus is the latest outcome of a project check.
+type ProjectCheckStatus struct {
+	Project             string    `json:"project"`
+	Name                string    `json:"name"`
+	Command             string    `json:"command"`
+	OK                  bool      `json:"ok"`      // the last run passed
+	Failing             bool      `json:"failing"` // the check has failed Failures runs in a row
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	ExitCode            int       `json:"exit_code"`
+	Output              string    `json:"output,omitempty"`
+	LatencyMS           int64     `json:"latency_ms"`
+	CheckedAt           time.Time `json:"checked_at"`
+}
+
+// RunProjectCheck runs c's command through sh in c.Dir within c.Timeout.
+// A run that times out fails with exit code -1.
+func RunProjectCheck(ctx context.Context, c ProjectCheck) ProjectCheckStatus {
+	res, err := cmdexec.Run(ctx, cmdexec.Cmd{
+		Name:           "sh",
+		Args:           []string{"-c", c.Command},
+		Dir:            c.Dir,
+		Timeout:        c.Timeout,
+		CombinedOutput: true,
+	})
+	st := ProjectCheckStatus{
+		Project:   c.Project,
+		Name:      c.Name,
+		Command:   c.Command,
+		OK:        err == nil,
+		ExitCode:  res.ExitCode,
+		Output:    res.Text(),
+		LatencyMS: res.Duration.Milliseconds(),
+		CheckedAt: time.Now().Add(-res.Duration),
+	}
+	// A nonzero exit speaks for itself; say why a run that never exited failed.
+	if e := (*cmdexec.Error)(nil); err != nil && (!errors.As(err, &e) || e.TimedOut || e.ExitCode < 0) {
+		st.Output = strings.TrimSpace(st.Output + "\n" + err.Error())
+	}
+	if len(st.Output) > maxProjectCheckOutput {
+		st.Output = st.Output[len(st.Output)-maxProjectCheckOutput:]
+	}
+	return st
+}
+
+// ProjectCheckMonitor holds the latest run of every project check. A nil
+// monitor has no statuses.
+type ProjectCheckMonitor struct {
+	mu       sync.Mutex
+	statuses map[string]ProjectCheckStatus
+}
+
+// Update stores the outcome of a run of c and reports whether the check has
+// just started failing or just recovered.
+func (m *ProjectCheckMonitor) Update(c ProjectCheck, st ProjectCheckStatus) (ProjectCheckStatus, bool, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.statuses == nil {
+		m.statuses = make(map[string]ProjectCheckStatus)
+	}
+	key := c.Project + "/" + c.Name
+	prev := m.statuses[key]
+	if !st.OK {
+		st.ConsecutiveFailures = prev.ConsecutiveFailures + 1
+	}
+	st.Failing = st.ConsecutiveFailures >= max(c.Failures, 1)
+	m.statuses[key] = st
+	return st, st.Failing && !prev.Failing, !st.Failing && prev.Failing
+}
+
+// Forget drops the statuses of the checks keep rejects, so checks removed
+// by a config reload do not linger.
+func (m *ProjectCheckMonitor) Forget(keep func(project, name string) bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, st := range m.statuses {
+		if !keep(st.Project, st.Name) {
+			delete(m.statuses, key)
+		}
+	}
+}
+
+// Statuses returns the latest runs sorted by project and check name.
+func (m *ProjectCheckMonitor) Statuses() []ProjectCheckStatus {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]ProjectCheckStatus, 0, len(m.statuses))
+	for _, st := range m.statuses {
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Project != out[j].Project {
+			return out[i].Project < out[j].Project
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
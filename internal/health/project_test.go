@@ -0,0 +1,63 @@
+package health
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunProjectCheck(t *testing.T) {
+	dir := t.TempDir()
+	pass := RunProjectCheck(context.Background(), ProjectCheck{Project: "p", Name: "smoke", Command: "pwd", Dir: dir, Timeout: 5 * time.Second})
+	if !pass.OK || pass.ExitCode != 0 || !strings.HasSuffix(pass.Output, dir) {
+		t.Fatalf("passing check = %+v", pass)
+	}
+
+	fail := RunProjectCheck(context.Background(), ProjectCheck{Command: "echo broken; exit 3", Timeout: 5 * time.Second})
+	if fail.OK || fail.ExitCode != 3 || fail.Output != "broken" {
+		t.Fatalf("failing check = %+v", fail)
+	}
+
+	slow := RunProjectCheck(context.Background(), ProjectCheck{Command: "sleep 5", Timeout: 100 * time.Millisecond})
+	if slow.OK || slow.ExitCode != -1 || !strings.Contains(slow.Output, "timed out") {
+		t.Fatalf("timed out check = %+v", slow)
+	}
+}
+
+func TestProjectCheckMonitor(t *testing.T) {
+	var m ProjectCheckMonitor
+	c := ProjectCheck{Project: "p", Name: "smoke", Failures: 2}
+	ok, bad := ProjectCheckStatus{Project: "p", Name: "smoke", OK: true}, ProjectCheckStatus{Project: "p", Name: "smoke"}
+
+	steps := []struct {
+		run                 ProjectCheckStatus
+		failing             bool
+		failed, recovered   bool
+		consecutiveFailures int
+	}{
+		{run: ok},
+		{run: bad, consecutiveFailures: 1},
+		{run: bad, failing: true, failed: true, consecutiveFailures: 2},
+		{run: bad, failing: true, consecutiveFailures: 3},
+		{run: ok, recovered: true},
+	}
+	for i, step := range steps {
+		st, failed, recovered := m.Update(c, step.run)
+		if st.Failing != step.failing || failed != step.failed || recovered != step.recovered || st.ConsecutiveFailures != step.consecutiveFailures {
+			t.Fatalf("step %d: status %+v failed=%v recovered=%v", i, st, failed, recovered)
+		}
+	}
+
+	m.Update(ProjectCheck{Project: "a", Name: "api"}, ProjectCheckStatus{Project: "a", Name: "api", OK: true})
+	if got := m.Statuses(); len(got) != 2 || got[0].Project != "a" || got[1].Project != "p" {
+		t.Fatalf("statuses = %+v", got)
+	}
+	m.Forget(func(project, _ string) bool { return project == "a" })
+	if got := m.Statuses(); len(got) != 1 || got[0].Project != "a" {
+		t.Fatalf("statuses after forget = %+v", got)
+	}
+	if (*ProjectCheckMonitor)(nil).Statuses() != nil {
+		t.Fatal("nil monitor has statuses")
+	}
+}
@@ -0,0 +1,91 @@
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/antigravity-dev/cortex/internal/cmdexec"
+)
+
+// Issue is a tracker issue normalized for import.
+type Issue struct {
+	Number int
+	Title  string
+	Body   string
+	Labels []string
+	URL    string
+}
+
+// ExternalRef is the bead external reference recorded for an imported issue,
+// used to skip issues that were already imported.
+func (i Issue) ExternalRef() string {
+	return "gh-" + strconv.Itoa(i.Number)
+}
+
+// GitHubQuery selects the issues fetched by FetchGitHubIssues.
+type GitHubQuery struct {
+	Repo  string // owner/name; empty uses the repo of the workspace checkout
+	State string // open, closed or all
+	Limit int
+}
+
+// FetchGitHubIssues lists issues with the gh CLI, run from workspace so the
+// repository can be inferred from the checkout when Repo is empty.
+func FetchGitHubIssues(ctx context.Context, workspace string, q GitHubQuery) ([]Issue, error) {
+	state := q.State
+	if state == "" {
+		state = "open"
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = 500
+	}
+	args := []string{"issue", "list",
+		"--state", state,
+		"--limit", strconv.Itoa(limit),
+		"--json", "number,title,body,labels,url",
+	}
+	if q.Repo != "" {
+		args = append(args, "--repo", q.Repo)
+	}
+
+	res, err := cmdexec.Run(ctx, cmdexec.Cmd{
+		Name:      "gh",
+		Args:      args,
+		Dir:       workspace,
+		MaxOutput: 16 << 20,
+		Retries:   2, // a read-only listing; ride out GitHub API blips
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseGitHubIssues(res.Stdout)
+}
+
+func parseGitHubIssues(data []byte) ([]Issue, error) {
+	var raw []struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+		Body   string `json:"body"`
+		URL    string `json:"url"`
+		Labels []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing gh issue list output: %w", err)
+	}
+
+	issues := make([]Issue, 0, len(raw))
+	for _, r := range raw {
+		issue := Issue{Number: r.Number, Title: strings.TrimSpace(r.Title), Body: r.Body, URL: r.URL}
+		for _, l := range r.Labels {
+			issue.Labels = append(issue.Labels, l.Name)
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
@@ -0,0 +1,27 @@
+package importer
+
+import "testing"
+
+func TestParseGitHubIssues(t *testing.T) {
+	data := []byte(`[
+		{"number": 42, "title": " Login fails ", "body": "steps", "url": "https://github.com/o/r/issues/42",
+		 "labels": [{"name": "bug"}, {"name": "size/S"}]},
+		{"number": 7, "title": "Docs", "body": "", "url": "https://github.com/o/r/issues/7", "labels": []}
+	]`)
+	issues, err := parseGitHubIssues(data)
+	if err != nil {
+		t.Fatalf("parseGitHubIssues: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("got %d issues, want 2", len(issues))
+	}
+	if issues[0].Title != "Login fails" || len(issues[0].Labels) != 2 || issues[0].Labels[1] != "size/S" {
+		t.Fatalf("issue[0] = %+v", issues[0])
+	}
+	if issues[0].ExternalRef() != "gh-42" {
+		t.Fatalf("external ref = %q", issues[0].ExternalRef())
+	}
+	if _, err := parseGitHubIssues([]byte("not json")); err == nil {
+		t.Fatal("expected parse error")
+	}
+}
@@ -0,0 +1,77 @@
+// Package importer bootstraps a project's beads from an existing issue
+// tracker backlog.
+package importer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/antigravity-dev/cortex/internal/beads"
+)
+
+// Result records what happened to one issue during an import.
+type Result struct {
+	Issue   Issue
+	Mapping Mapping
+	BeadID  string // empty for skipped issues and dry runs
+	Skipped bool   // already imported (external ref exists)
+	Err     error
+}
+
+// Import creates a bead for every issue not already imported into beadsDir.
+// Issues are matched on external ref, so re-running an import is safe. With
+// dryRun set nothing is created and the planned mappings are returned.
+func Import(ctx context.Context, beadsDir string, issues []Issue, rules *Rules, dryRun bool) ([]Result, error) {
+	if rules == nil {
+		rules = DefaultRules()
+	}
+	existing, err := beads.ListBeadsCtx(ctx, beadsDir)
+	if err != nil {
+		return nil, err
+	}
+	imported := make(map[string]string, len(existing))
+	for _, b := range existing {
+		if b.ExternalRef != "" {
+			imported[b.ExternalRef] = b.ID
+		}
+	}
+
+	results := make([]Result, 0, len(issues))
+	for _, issue := range issues {
+		res := Result{Issue: issue, Mapping: rules.Map(issue)}
+		if id, ok := imported[issue.ExternalRef()]; ok {
+			res.BeadID = id
+			res.Skipped = true
+			results = append(results, res)
+			continue
+		}
+		if dryRun {
+			results = append(results, res)
+			continue
+		}
+		res.BeadID, res.Err = beads.CreateIssueWithOptionsCtx(ctx, beadsDir, beads.IssueOptions{
+			Title:           issue.Title,
+			Type:            res.Mapping.Type,
+			Priority:        res.Mapping.Priority,
+			Description:     issueDescription(issue),
+			Labels:          res.Mapping.Labels,
+			EstimateMinutes: res.Mapping.EstimateMinutes,
+			ExternalRef:     issue.ExternalRef(),
+		})
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+func issueDescription(issue Issue) string {
+	body := strings.TrimSpace(issue.Body)
+	if issue.URL == "" {
+		return body
+	}
+	source := fmt.Sprintf("Imported from %s", issue.URL)
+	if body == "" {
+		return source
+	}
+	return body + "\n\n" + source
+}
@@ -0,0 +1,86 @@
+package importer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setupFakeBD(t *testing.T, listJSON string) (beadsDir, logPath string) {
+	t.Helper()
+	projectDir := t.TempDir()
+	beadsDir = filepath.Join(projectDir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	logPath = filepath.Join(projectDir, "args.log")
+	listPath := filepath.Join(projectDir, "list.json")
+	if err := os.WriteFile(listPath, []byte(listJSON), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fakeBin := t.TempDir()
+	script := "#!/bin/sh\n" +
+		"if [ \"$1\" = \"list\" ]; then cat \"$BD_LIST_JSON\"; exit 0; fi\n" +
+		"echo \"$@\" >> \"$BD_ARGS_LOG\"\n" +
+		"echo \"proj-new\"\n"
+	if err := os.WriteFile(filepath.Join(fakeBin, "bd"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BD_ARGS_LOG", logPath)
+	t.Setenv("BD_LIST_JSON", listPath)
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+	return beadsDir, logPath
+}
+
+func TestImportSkipsAlreadyImported(t *testing.T) {
+	beadsDir, logPath := setupFakeBD(t, `[{"id":"proj-1","title":"Old","status":"open","external_ref":"gh-1"}]`)
+	issues := []Issue{
+		{Number: 1, Title: "Old"},
+		{Number: 2, Title: "Crash", Body: "trace", URL: "https://github.com/o/r/issues/2", Labels: []string{"bug"}},
+	}
+
+	results, err := Import(context.Background(), beadsDir, issues, DefaultRules(), false)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if !results[0].Skipped || results[0].BeadID != "proj-1" {
+		t.Fatalf("result[0] = %+v, want skipped proj-1", results[0])
+	}
+	if results[1].Skipped || results[1].Err != nil || results[1].BeadID != "proj-new" {
+		t.Fatalf("result[1] = %+v, want created proj-new", results[1])
+	}
+
+	args, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read args log: %v", err)
+	}
+	got := string(args)
+	if strings.Count(got, "create") != 1 {
+		t.Fatalf("expected one create, got %q", got)
+	}
+	for _, want := range []string{"--type bug", "--priority 1", "--external-ref gh-2", "Imported from https://github.com/o/r/issues/2"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("bd args missing %q: %q", want, got)
+		}
+	}
+}
+
+func TestImportDryRunCreatesNothing(t *testing.T) {
+	beadsDir, logPath := setupFakeBD(t, `[]`)
+	results, err := Import(context.Background(), beadsDir, []Issue{{Number: 3, Title: "Idea", Labels: []string{"enhancement"}}}, nil, true)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if len(results) != 1 || results[0].BeadID != "" || results[0].Mapping.Type != "feature" {
+		t.Fatalf("results = %+v", results)
+	}
+	if _, err := os.Stat(logPath); !os.IsNotExist(err) {
+		t.Fatalf("dry run invoked bd create")
+	}
+}
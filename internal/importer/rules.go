@@ -0,0 +1,180 @@
+package importer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Rules map tracker labels and titles onto bead type, priority, labels and
+// estimates. Rules are evaluated in order; later matches override fields set
+// by earlier ones, and labels accumulate.
+type Rules struct {
+	DefaultType     string `toml:"default_type"`
+	DefaultPriority int    `toml:"default_priority"`
+	// KeepLabels copies the issue's own labels onto the bead.
+	KeepLabels bool `toml:"keep_labels"`
+	// Labels are added to every imported bead.
+	Labels []string `toml:"labels"`
+	Rules  []Rule   `toml:"rule"`
+}
+
+// Rule applies when the issue carries Label (case-insensitive) and/or its
+// title matches TitlePattern. A rule with neither condition never matches.
+type Rule struct {
+	Label           string   `toml:"label"`
+	TitlePattern    string   `toml:"title_pattern"`
+	Type            string   `toml:"type"`
+	Priority        *int     `toml:"priority"`
+	Labels          []string `toml:"labels"`
+	EstimateMinutes int      `toml:"estimate_minutes"`
+
+	title *regexp.Regexp
+}
+
+// Mapping is the bead metadata derived from one issue.
+type Mapping struct {
+	Type            string
+	Priority        int
+	Labels          []string
+	EstimateMinutes int
+}
+
+// DefaultRules maps the common GitHub labels: bugs become p1 bugs,
+// enhancements become features, and size labels become estimates.
+func DefaultRules() *Rules {
+	p1 := 1
+	p3 := 3
+	r := &Rules{
+		DefaultType:     "task",
+		DefaultPriority: 2,
+		Labels:          []string{"imported"},
+		Rules: []Rule{
+			{Label: "bug", Type: "bug", Priority: &p1},
+			{Label: "enhancement", Type: "feature"},
+			{Label: "feature", Type: "feature"},
+			{Label: "documentation", Type: "chore", Priority: &p3},
+			{Label: "chore", Type: "chore", Priority: &p3},
+			{Label: "size/xs", EstimateMinutes: 30},
+			{Label: "size/s", EstimateMinutes: 60},
+			{Label: "size/m", EstimateMinutes: 180},
+			{Label: "size/l", EstimateMinutes: 480},
+			{Label: "size/xl", EstimateMinutes: 960},
+		},
+	}
+	_ = r.compile()
+	return r
+}
+
+// LoadRules reads rules from a TOML file. Omitted defaults fall back to
+// DefaultRules' defaults; the rule list replaces the built-in one.
+func LoadRules(path string) (*Rules, error) {
+	r := &Rules{DefaultPriority: -1}
+	md, err := toml.DecodeFile(path, r)
+	if err != nil {
+		return nil, fmt.Errorf("loading import rules: %w", err)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		return nil, fmt.Errorf("loading import rules: unknown keys %v", undecoded)
+	}
+	defaults := DefaultRules()
+	if r.DefaultType == "" {
+		r.DefaultType = defaults.DefaultType
+	}
+	if r.DefaultPriority < 0 {
+		r.DefaultPriority = defaults.DefaultPriority
+	}
+	if err := r.compile(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Rules) compile() error {
+	if r.DefaultPriority < 0 || r.DefaultPriority > 4 {
+		return fmt.Errorf("import rules: default_priority must be 0-4")
+	}
+	for i := range r.Rules {
+		rule := &r.Rules[i]
+		if rule.Label == "" && rule.TitlePattern == "" {
+			return fmt.Errorf("import rule %d: label or title_pattern is required", i+1)
+		}
+		if rule.Priority != nil && (*rule.Priority < 0 || *rule.Priority > 4) {
+			return fmt.Errorf("import rule %d: priority must be 0-4", i+1)
+		}
+		if rule.TitlePattern != "" {
+			re, err := regexp.Compile("(?i)" + rule.TitlePattern)
+			if err != nil {
+				return fmt.Errorf("import rule %d: title_pattern: %w", i+1, err)
+			}
+			rule.title = re
+		}
+	}
+	return nil
+}
+
+func (rule Rule) matches(issue Issue) bool {
+	if rule.Label != "" && !hasLabel(issue.Labels, rule.Label) {
+		return false
+	}
+	if rule.title != nil && !rule.title.MatchString(issue.Title) {
+		return false
+	}
+	return rule.Label != "" || rule.title != nil
+}
+
+// Map derives bead metadata for an issue.
+func (r *Rules) Map(issue Issue) Mapping {
+	m := Mapping{Type: r.DefaultType, Priority: r.DefaultPriority}
+	labels := append([]string(nil), r.Labels...)
+	if r.KeepLabels {
+		labels = append(labels, issue.Labels...)
+	}
+	for _, rule := range r.Rules {
+		if !rule.matches(issue) {
+			continue
+		}
+		if rule.Type != "" {
+			m.Type = rule.Type
+		}
+		if rule.Priority != nil {
+			m.Priority = *rule.Priority
+		}
+		if rule.EstimateMinutes > 0 {
+			m.EstimateMinutes = rule.EstimateMinutes
+		}
+		labels = append(labels, rule.Labels...)
+	}
+	m.Labels = uniqueLabels(labels)
+	return m
+}
+
+func hasLabel(labels []string, want string) bool {
+	for _, l := range labels {
+		if strings.EqualFold(strings.TrimSpace(l), want) {
+			return true
+		}
+	}
+	return false
+}
+
+func uniqueLabels(labels []string) []string {
+	seen := make(map[string]struct{}, len(labels))
+	var out []string
+	for _, l := range labels {
+		l = strings.TrimSpace(l)
+		// bd separates labels with commas.
+		l = strings.ReplaceAll(l, ",", "-")
+		if l == "" {
+			continue
+		}
+		if _, ok := seen[l]; ok {
+			continue
+		}
+		seen[l] = struct{}{}
+		out = append(out, l)
+	}
+	return out
+}
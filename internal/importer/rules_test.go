@@ -0,0 +1,82 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDefaultRulesMapLabels(t *testing.T) {
+	rules := DefaultRules()
+
+	bug := rules.Map(Issue{Title: "Crash on start", Labels: []string{"Bug", "size/M"}})
+	if bug.Type != "bug" || bug.Priority != 1 || bug.EstimateMinutes != 180 {
+		t.Fatalf("bug mapping = %+v", bug)
+	}
+	if !reflect.DeepEqual(bug.Labels, []string{"imported"}) {
+		t.Fatalf("bug labels = %v", bug.Labels)
+	}
+
+	plain := rules.Map(Issue{Title: "Tidy README"})
+	if plain.Type != "task" || plain.Priority != 2 || plain.EstimateMinutes != 0 {
+		t.Fatalf("plain mapping = %+v", plain)
+	}
+}
+
+func TestLoadRulesAppliesInOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.toml")
+	content := `
+default_type = "feature"
+keep_labels = true
+labels = ["from-gh"]
+
+[[rule]]
+label = "bug"
+type = "bug"
+priority = 1
+
+[[rule]]
+title_pattern = "^security:"
+priority = 0
+labels = ["security"]
+estimate_minutes = 120
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rules, err := LoadRules(path)
+	if err != nil {
+		t.Fatalf("LoadRules: %v", err)
+	}
+	if rules.DefaultPriority != 2 {
+		t.Fatalf("default priority = %d, want fallback 2", rules.DefaultPriority)
+	}
+
+	m := rules.Map(Issue{Title: "Security: token leak", Labels: []string{"bug", "area, auth"}})
+	want := Mapping{Type: "bug", Priority: 0, EstimateMinutes: 120, Labels: []string{"from-gh", "bug", "area- auth", "security"}}
+	if !reflect.DeepEqual(m, want) {
+		t.Fatalf("mapping = %+v, want %+v", m, want)
+	}
+}
+
+func TestLoadRulesRejectsInvalid(t *testing.T) {
+	cases := map[string]string{
+		"no condition":  "[[rule]]\ntype = \"bug\"\n",
+		"bad priority":  "[[rule]]\nlabel = \"x\"\npriority = 9\n",
+		"bad pattern":   "[[rule]]\ntitle_pattern = \"(\"\n",
+		"unknown field": "[[rule]]\nlabel = \"x\"\nsize = 3\n",
+	}
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "rules.toml")
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadRules(path); err == nil || !strings.Contains(err.Error(), "rule") {
+				t.Fatalf("LoadRules error = %v, want rule error", err)
+			}
+		})
+	}
+}
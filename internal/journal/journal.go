@@ -0,0 +1,193 @@
+// Package journal keeps an append-only record of dispatch decisions, apart
+// from the human-oriented logs, so an operator can later answer why a bead
+// was or was not dispatched at a given time.
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Decisions recorded in the journal.
+const (
+	Admit = "admit" // the dispatch was started
+	Deny  = "deny"  // the dispatch was refused
+	Skip  = "skip"  // an optional step was passed over; the dispatch went on without it
+)
+
+// Entry is one journal line.
+type Entry struct {
+	At       time.Time `json:"at"`
+	Decision string    `json:"decision"`
+	Source   string    `json:"source"` // what decided, e.g. api, retry, pair, worktree
+	Project  string    `json:"project,omitempty"`
+	BeadID   string    `json:"bead_id,omitempty"`
+	Agent    string    `json:"agent,omitempty"`
+	Provider string    `json:"provider,omitempty"`
+	Workflow string    `json:"workflow_id,omitempty"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+const filePrefix, fileSuffix = "decisions-", ".jsonl"
+
+// Journal appends entries to decisions-YYYY-MM-DD.jsonl in its directory,
+// starting a new file each UTC day. Methods on a nil Journal do nothing.
+type Journal struct {
+	dir       string
+	retention int // days of files kept; 0 keeps all
+
+	mu  sync.Mutex
+	day string
+	f   *os.File
+	now func() time.Time
+}
+
+// Open creates dir if needed and returns a journal writing to it. Files
+// older than retentionDays are removed whenever a new day's file starts.
+func Open(dir string, retentionDays int) (*Journal, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("journal: create %s: %w", dir, err)
+	}
+	return &Journal{dir: dir, retention: retentionDays, now: time.Now}, nil
+}
+
+// Record appends e, stamping it with the current time when At is zero.
+func (j *Journal) Record(e Entry) error {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if e.At.IsZero() {
+		e.At = j.now()
+	}
+	e.At = e.At.UTC()
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("journal: encode entry: %w", err)
+	}
+	if err := j.rotateLocked(e.At.Format(time.DateOnly)); err != nil {
+		return err
+	}
+	if _, err := j.f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("journal: write %s: %w", j.f.Name(), err)
+	}
+	return nil
+}
+
+// rotateLocked makes day's file the current one. Caller holds j.mu.
+func (j *Journal) rotateLocked(day string) error {
+	if j.f != nil && j.day == day {
+		return nil
+	}
+	f, err := os.OpenFile(filepath.Join(j.dir, filePrefix+day+fileSuffix), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("journal: open day %s: %w", day, err)
+	}
+	if j.f != nil {
+		j.f.Close()
+	}
+	j.f, j.day = f, day
+	j.pruneLocked()
+	return nil
+}
+
+// pruneLocked removes day files past the retention. Failures only leave
+// old files behind, so they are ignored. Caller holds j.mu.
+func (j *Journal) pruneLocked() {
+	if j.retention <= 0 {
+		return
+	}
+	current, err := time.Parse(time.DateOnly, j.day)
+	if err != nil {
+		return
+	}
+	cutoff := current.AddDate(0, 0, -j.retention)
+	for _, day := range j.daysLocked() {
+		if t, err := time.Parse(time.DateOnly, day); err == nil && t.Before(cutoff) {
+			os.Remove(filepath.Join(j.dir, filePrefix+day+fileSuffix))
+		}
+	}
+}
+
+func (j *Journal) daysLocked() []string {
+	entries, err := os.ReadDir(j.dir)
+	if err != nil {
+		return nil
+	}
+	var days []string
+	for _, e := range entries {
+		name := e.Name()
+		if strings.HasPrefix(name, filePrefix) && strings.HasSuffix(name, fileSuffix) {
+			days = append(days, strings.TrimSuffix(strings.TrimPrefix(name, filePrefix), fileSuffix))
+		}
+	}
+	sort.Strings(days)
+	return days
+}
+
+// Latest returns the most recent entry for a bead of project, searching
+// the kept days newest first, or nil if it has none.
+func (j *Journal) Latest(project, beadID string) (*Entry, error) {
+	if j == nil {
+		return nil, nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	days := j.daysLocked()
+	for i := len(days) - 1; i >= 0; i-- {
+		f, err := os.Open(filepath.Join(j.dir, filePrefix+days[i]+fileSuffix))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("journal: open day %s: %w", days[i], err)
+		}
+		var latest *Entry
+		sc := bufio.NewScanner(f)
+		sc.Buffer(nil, 1<<20)
+		for sc.Scan() {
+			var e Entry
+			// A torn last line from a crash is skipped.
+			if json.Unmarshal(sc.Bytes(), &e) != nil || e.BeadID != beadID || e.Project != project {
+				continue
+			}
+			latest = &e
+		}
+		err = sc.Err()
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("journal: read day %s: %w", days[i], err)
+		}
+		if latest != nil {
+			return latest, nil
+		}
+	}
+	return nil, nil
+}
+
+// Close closes the current file. Later Records reopen it.
+func (j *Journal) Close() error {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.f == nil {
+		return nil
+	}
+	err := j.f.Close()
+	j.f, j.day = nil, ""
+	return err
+}
@@ -0,0 +1,123 @@
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func readDay(t *testing.T, dir, day string) []Entry {
+	t.Helper()
+	f, err := os.Open(filepath.Join(dir, filePrefix+day+fileSuffix))
+	if err != nil {
+		t.Fatalf("open %s: %v", day, err)
+	}
+	defer f.Close()
+	var entries []Entry
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			t.Fatalf("decode %q: %v", sc.Text(), err)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestJournalRotatesDaily(t *testing.T) {
+	dir := t.TempDir()
+	j, err := Open(dir, 0)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer j.Close()
+	now := time.Date(2026, 3, 1, 23, 59, 0, 0, time.UTC)
+	j.now = func() time.Time { return now }
+
+	if err := j.Record(Entry{Decision: Deny, Source: "api", BeadID: "cx-1", Reason: "project paused"}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	now = now.Add(2 * time.Minute)
+	if err := j.Record(Entry{Decision: Admit, Source: "api", BeadID: "cx-1", Workflow: "cx-1"}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	first := readDay(t, dir, "2026-03-01")
+	if len(first) != 1 || first[0].Decision != Deny || first[0].Reason != "project paused" || !first[0].At.Equal(time.Date(2026, 3, 1, 23, 59, 0, 0, time.UTC)) {
+		t.Fatalf("first day = %+v", first)
+	}
+	second := readDay(t, dir, "2026-03-02")
+	if len(second) != 1 || second[0].Decision != Admit || second[0].Workflow != "cx-1" {
+		t.Fatalf("second day = %+v", second)
+	}
+
+	var nilJournal *Journal
+	if err := nilJournal.Record(Entry{Decision: Admit}); err != nil {
+		t.Fatalf("nil journal Record: %v", err)
+	}
+}
+
+func TestJournalPrunesExpiredDays(t *testing.T) {
+	dir := t.TempDir()
+	for _, day := range []string{"2026-02-01", "2026-02-25", "2026-02-26"} {
+		if err := os.WriteFile(filepath.Join(dir, filePrefix+day+fileSuffix), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	j, err := Open(dir, 5)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer j.Close()
+	if err := j.Record(Entry{At: time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC), Decision: Skip, Source: "pair"}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	for name, kept := range map[string]bool{
+		filePrefix + "2026-02-01" + fileSuffix: false,
+		filePrefix + "2026-02-25" + fileSuffix: true,
+		filePrefix + "2026-02-26" + fileSuffix: true,
+		filePrefix + "2026-03-02" + fileSuffix: true,
+		"notes.txt":                            true,
+	} {
+		if _, err := os.Stat(filepath.Join(dir, name)); (err == nil) != kept {
+			t.Errorf("%s: kept = %v, want %v", name, err == nil, kept)
+		}
+	}
+}
+
+func TestJournalLatest(t *testing.T) {
+	dir := t.TempDir()
+	j, err := Open(dir, 0)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer j.Close()
+
+	day1 := time.Date(2026, 3, 1, 3, 12, 0, 0, time.UTC)
+	for _, e := range []Entry{
+		{At: day1, Decision: Deny, Project: "p", BeadID: "cx-1", Reason: "outside working hours"},
+		{At: day1.Add(time.Minute), Decision: Admit, Project: "other", BeadID: "cx-1"},
+		{At: day1.Add(24 * time.Hour), Decision: Admit, Project: "p", BeadID: "cx-2"},
+	} {
+		if err := j.Record(e); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	got, err := j.Latest("p", "cx-1")
+	if err != nil || got == nil || got.Decision != Deny || got.Reason != "outside working hours" {
+		t.Fatalf("Latest(p, cx-1) = %+v, %v", got, err)
+	}
+	if got, err := j.Latest("p", "cx-3"); err != nil || got != nil {
+		t.Fatalf("Latest for unknown bead = %+v, %v", got, err)
+	}
+}
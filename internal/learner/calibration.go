@@ -0,0 +1,131 @@
+package learner
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const (
+	// calibrationMinSamples is the minimum scored dispatches before the
+	// calibration gap produces a recommendation.
+	calibrationMinSamples = 10
+	// calibrationMaxGap is how far mean confidence may drift from the actual
+	// DoD pass rate before agents are flagged as over- or underconfident.
+	calibrationMaxGap  = 0.15
+	calibrationBuckets = 5
+)
+
+// ConfidenceBucket compares reported confidence with the DoD pass rate for
+// dispatches whose score fell in [Low, High).
+type ConfidenceBucket struct {
+	Low           float64 `json:"low"`
+	High          float64 `json:"high"`
+	Count         int     `json:"count"`
+	AvgConfidence float64 `json:"avg_confidence"`
+	DoDPassRate   float64 `json:"dod_pass_rate"`
+}
+
+// ConfidenceCalibration measures how well agents' self-reported confidence
+// predicts DoD outcomes. A well-calibrated agent's 0.8 passes ~80% of the time.
+type ConfidenceCalibration struct {
+	Samples        int                `json:"samples"`
+	HumanReviews   int                `json:"human_reviews"` // completions held back from auto-close
+	MeanConfidence float64            `json:"mean_confidence"`
+	DoDPassRate    float64            `json:"dod_pass_rate"`
+	BrierScore     float64            `json:"brier_score"` // mean squared error, lower is better
+	Buckets        []ConfidenceBucket `json:"buckets"`
+}
+
+// Gap is mean confidence minus the DoD pass rate: positive means
+// overconfident, negative underconfident.
+func (c ConfidenceCalibration) Gap() float64 {
+	return c.MeanConfidence - c.DoDPassRate
+}
+
+type confidenceSample struct {
+	confidence  float64
+	dodPassed   bool
+	humanReview bool
+}
+
+// QueryConfidenceCalibration computes calibration over dispatches that
+// reported a confidence score since the given time.
+func QueryConfidenceCalibration(db *sql.DB, since time.Time) (*ConfidenceCalibration, error) {
+	rows, err := db.Query(`
+		SELECT confidence, dod_passed, human_review
+		FROM dispatch_confidence
+		WHERE recorded_at >= ?
+	`, since.UTC().Format(time.DateTime))
+	if err != nil {
+		return nil, fmt.Errorf("query confidence calibration: %w", err)
+	}
+	defer rows.Close()
+
+	var samples []confidenceSample
+	for rows.Next() {
+		var s confidenceSample
+		if err := rows.Scan(&s.confidence, &s.dodPassed, &s.humanReview); err != nil {
+			return nil, fmt.Errorf("scan confidence sample: %w", err)
+		}
+		samples = append(samples, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	c := calibrate(samples)
+	return &c, nil
+}
+
+func calibrate(samples []confidenceSample) ConfidenceCalibration {
+	c := ConfidenceCalibration{Buckets: make([]ConfidenceBucket, calibrationBuckets)}
+	width := 1.0 / calibrationBuckets
+	for i := range c.Buckets {
+		c.Buckets[i].Low = float64(i) * width
+		c.Buckets[i].High = float64(i+1) * width
+	}
+	if len(samples) == 0 {
+		return c
+	}
+
+	var sumConf, sumSq float64
+	passes := 0
+	bucketPasses := make([]int, calibrationBuckets)
+	for _, s := range samples {
+		outcome := 0.0
+		if s.dodPassed {
+			outcome = 1
+			passes++
+		}
+		if s.humanReview {
+			c.HumanReviews++
+		}
+		sumConf += s.confidence
+		sumSq += (s.confidence - outcome) * (s.confidence - outcome)
+
+		idx := int(s.confidence / width)
+		if idx >= calibrationBuckets {
+			idx = calibrationBuckets - 1
+		}
+		b := &c.Buckets[idx]
+		b.Count++
+		b.AvgConfidence += s.confidence
+		if s.dodPassed {
+			bucketPasses[idx]++
+		}
+	}
+
+	n := float64(len(samples))
+	c.Samples = len(samples)
+	c.MeanConfidence = sumConf / n
+	c.DoDPassRate = float64(passes) / n
+	c.BrierScore = sumSq / n
+	for i := range c.Buckets {
+		b := &c.Buckets[i]
+		if b.Count > 0 {
+			b.AvgConfidence /= float64(b.Count)
+			b.DoDPassRate = float64(bucketPasses[i]) / float64(b.Count)
+		}
+	}
+	return c
+}
@@ -0,0 +1,71 @@
+package learner
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestCalibrateBucketsAndBrier(t *testing.T) {
+	c := calibrate([]confidenceSample{
+		{confidence: 0.9, dodPassed: true},
+		{confidence: 0.9, dodPassed: false},
+		{confidence: 1.0, dodPassed: true},
+		{confidence: 0.1, dodPassed: false, humanReview: true},
+	})
+	if c.Samples != 4 || c.HumanReviews != 1 {
+		t.Fatalf("samples=%d human_reviews=%d", c.Samples, c.HumanReviews)
+	}
+	if math.Abs(c.MeanConfidence-0.725) > 1e-9 || c.DoDPassRate != 0.5 {
+		t.Fatalf("mean=%f pass=%f", c.MeanConfidence, c.DoDPassRate)
+	}
+	// (0.01 + 0.81 + 0 + 0.01) / 4
+	if math.Abs(c.BrierScore-0.2075) > 1e-9 {
+		t.Fatalf("brier = %f, want 0.2075", c.BrierScore)
+	}
+	top := c.Buckets[4]
+	if top.Count != 3 || math.Abs(top.DoDPassRate-2.0/3) > 1e-9 {
+		t.Fatalf("top bucket = %+v", top)
+	}
+	if c.Buckets[0].Count != 1 || c.Buckets[2].Count != 0 {
+		t.Fatalf("buckets = %+v", c.Buckets)
+	}
+}
+
+func TestQueryConfidenceCalibrationFlagsOverconfidence(t *testing.T) {
+	st, err := store.Open(t.TempDir() + "/test.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+
+	for i := 0; i < 12; i++ {
+		if err := st.RecordDispatchConfidence(store.DispatchConfidence{
+			DispatchID: int64(i + 1), Agent: "claude", Confidence: 0.95, DoDPassed: i < 6,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c, err := QueryConfidenceCalibration(st.DB(), time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("QueryConfidenceCalibration: %v", err)
+	}
+	if c.Samples != 12 || c.DoDPassRate != 0.5 {
+		t.Fatalf("calibration = %+v", c)
+	}
+
+	recs := generateRecommendations(&LearnerReport{Calibration: c})
+	found := false
+	for _, r := range recs {
+		if strings.Contains(r, "overconfident") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected overconfidence recommendation, got %v", recs)
+	}
+}
@@ -0,0 +1,326 @@
+package learner
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// clusterSimilarity is the minimum Jaccard similarity between two error
+// signatures for their failures to be merged into one cluster.
+const clusterSimilarity = 0.6
+
+// FailureSample is the text of one failed dispatch, gathered for clustering.
+type FailureSample struct {
+	DispatchID int64     `json:"dispatch_id"`
+	BeadID     string    `json:"bead_id"`
+	Project    string    `json:"project"`
+	Agent      string    `json:"agent"`
+	Text       string    `json:"-"`
+	FailedAt   time.Time `json:"failed_at"`
+}
+
+// FailureCluster groups failed dispatches that share an error signature,
+// typically across several projects and agents.
+type FailureCluster struct {
+	Signature   string    `json:"signature"`
+	Example     string    `json:"example"`
+	Count       int       `json:"count"`
+	Projects    []string  `json:"projects"`
+	Agents      []string  `json:"agents"`
+	DispatchIDs []int64   `json:"dispatch_ids"` // most recent first, capped
+	FirstSeen   time.Time `json:"first_seen"`
+	LastSeen    time.Time `json:"last_seen"`
+}
+
+const maxClusterDispatchIDs = 20
+
+var (
+	errorLinePattern = regexp.MustCompile(`(?i)(panic:|fatal|error|exception|traceback|undefined|cannot|failed|fail:|--- fail|segmentation|timeout|timed out|denied|not found|refused)`)
+	pathPattern      = regexp.MustCompile(`(?:[A-Za-z]:)?(?:[\w.\-~]*/)+[\w.\-]+`)
+	hexPattern       = regexp.MustCompile(`0x[0-9a-fA-F]+|\b[0-9a-f]{7,40}\b`)
+	quotedPattern    = regexp.MustCompile("\"[^\"]*\"|'[^']*'|`[^`]*`")
+	numberPattern    = regexp.MustCompile(`\b\d+(\.\d+)?(ms|s|m|h)?\b`)
+	spacePattern     = regexp.MustCompile(`\s+`)
+)
+
+// Signature reduces failure output to a normalized error signature: the
+// salient error lines with paths, addresses, quoted values and numbers
+// replaced by placeholders so the same bug in different runs matches.
+func Signature(text string) string {
+	var picked []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || !errorLinePattern.MatchString(line) {
+			continue
+		}
+		picked = append(picked, normalizeErrorLine(line))
+		if len(picked) == 3 {
+			break
+		}
+	}
+	if len(picked) == 0 {
+		// No recognizable error line: fall back to the last non-empty line.
+		lines := strings.Split(strings.TrimSpace(text), "\n")
+		if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+			picked = append(picked, normalizeErrorLine(last))
+		}
+	}
+	return strings.Join(dedupeStrings(picked), " | ")
+}
+
+func normalizeErrorLine(line string) string {
+	line = quotedPattern.ReplaceAllString(line, "<str>")
+	line = pathPattern.ReplaceAllString(line, "<path>")
+	line = hexPattern.ReplaceAllString(line, "<hex>")
+	line = numberPattern.ReplaceAllString(line, "<n>")
+	line = spacePattern.ReplaceAllString(line, " ")
+	line = strings.ToLower(strings.TrimSpace(line))
+	if len(line) > 200 {
+		line = line[:200]
+	}
+	return line
+}
+
+// ClusterFailures groups samples by signature, then merges signatures whose
+// token sets are similar enough. Clusters are returned largest first.
+func ClusterFailures(samples []FailureSample) []FailureCluster {
+	type group struct {
+		cluster  FailureCluster
+		tokens   map[string]struct{}
+		projects map[string]struct{}
+		agents   map[string]struct{}
+	}
+	var groups []*group
+
+	for _, sample := range samples {
+		sig := Signature(sample.Text)
+		if sig == "" {
+			continue
+		}
+		tokens := tokenSet(sig)
+
+		var target *group
+		for _, g := range groups {
+			if g.cluster.Signature == sig || jaccard(g.tokens, tokens) >= clusterSimilarity {
+				target = g
+				break
+			}
+		}
+		if target == nil {
+			target = &group{
+				cluster:  FailureCluster{Signature: sig, Example: excerpt(sample.Text), FirstSeen: sample.FailedAt, LastSeen: sample.FailedAt},
+				tokens:   tokens,
+				projects: make(map[string]struct{}),
+				agents:   make(map[string]struct{}),
+			}
+			groups = append(groups, target)
+		}
+
+		c := &target.cluster
+		c.Count++
+		if len(c.DispatchIDs) < maxClusterDispatchIDs {
+			c.DispatchIDs = append(c.DispatchIDs, sample.DispatchID)
+		}
+		if sample.FailedAt.Before(c.FirstSeen) {
+			c.FirstSeen = sample.FailedAt
+		}
+		if sample.FailedAt.After(c.LastSeen) {
+			c.LastSeen = sample.FailedAt
+		}
+		if sample.Project != "" {
+			target.projects[sample.Project] = struct{}{}
+		}
+		if sample.Agent != "" {
+			target.agents[sample.Agent] = struct{}{}
+		}
+	}
+
+	clusters := make([]FailureCluster, 0, len(groups))
+	for _, g := range groups {
+		g.cluster.Projects = sortedKeys(g.projects)
+		g.cluster.Agents = sortedKeys(g.agents)
+		clusters = append(clusters, g.cluster)
+	}
+	sort.SliceStable(clusters, func(i, j int) bool {
+		if clusters[i].Count != clusters[j].Count {
+			return clusters[i].Count > clusters[j].Count
+		}
+		return clusters[i].LastSeen.After(clusters[j].LastSeen)
+	})
+	return clusters
+}
+
+// QueryFailureSamples loads failed dispatches since the given time with their
+// failure summary, DoD failures and output tail, most recent first.
+func QueryFailureSamples(db *sql.DB, since time.Time) ([]FailureSample, error) {
+	rows, err := db.Query(`
+		SELECT d.id, d.bead_id, d.project, d.agent_id,
+			COALESCE(d.completed_at, d.dispatched_at),
+			d.failure_summary,
+			COALESCE((SELECT dr.failures FROM dod_results dr WHERE dr.dispatch_id = d.id ORDER BY dr.id DESC LIMIT 1), ''),
+			COALESCE((SELECT o.output_tail FROM dispatch_output o WHERE o.dispatch_id = d.id ORDER BY o.id DESC LIMIT 1), '')
+		FROM dispatches d
+		WHERE d.status NOT IN ('completed', 'running', 'pending_retry')
+			AND COALESCE(d.completed_at, d.dispatched_at) >= ?
+		ORDER BY d.id DESC
+	`, since.UTC().Format(time.DateTime))
+	if err != nil {
+		return nil, fmt.Errorf("query failure samples: %w", err)
+	}
+	defer rows.Close()
+
+	var samples []FailureSample
+	for rows.Next() {
+		var s FailureSample
+		var failedAt any
+		var summary, dodFailures, tail string
+		if err := rows.Scan(&s.DispatchID, &s.BeadID, &s.Project, &s.Agent, &failedAt, &summary, &dodFailures, &tail); err != nil {
+			return nil, fmt.Errorf("scan failure sample: %w", err)
+		}
+		s.FailedAt = parseDBTime(failedAt)
+		s.Text = strings.TrimSpace(strings.Join(nonEmpty(summary, dodFailures, tail), "\n"))
+		if s.Text == "" {
+			continue
+		}
+		samples = append(samples, s)
+	}
+	return samples, rows.Err()
+}
+
+// TopFailureClusters clusters failures since the given time and returns the
+// largest clusters (limit <= 0 returns all).
+func TopFailureClusters(db *sql.DB, since time.Time, limit int) ([]FailureCluster, error) {
+	samples, err := QueryFailureSamples(db, since)
+	if err != nil {
+		return nil, err
+	}
+	clusters := ClusterFailures(samples)
+	if limit > 0 && len(clusters) > limit {
+		clusters = clusters[:limit]
+	}
+	return clusters, nil
+}
+
+// FormatFailureClusterReport renders clusters as a markdown report.
+func FormatFailureClusterReport(clusters []FailureCluster, since, until time.Time) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Recurring Failure Clusters\n\n")
+	fmt.Fprintf(&sb, "Window: %s to %s\n\n", since.UTC().Format("2006-01-02"), until.UTC().Format("2006-01-02"))
+	if len(clusters) == 0 {
+		sb.WriteString("No failed dispatches in this window.\n")
+		return sb.String()
+	}
+	for i, c := range clusters {
+		fmt.Fprintf(&sb, "## %d. %s\n\n", i+1, c.Signature)
+		fmt.Fprintf(&sb, "- Occurrences: %d (last seen %s)\n", c.Count, c.LastSeen.UTC().Format(time.RFC3339))
+		fmt.Fprintf(&sb, "- Projects: %s\n", strings.Join(c.Projects, ", "))
+		fmt.Fprintf(&sb, "- Agents: %s\n", strings.Join(c.Agents, ", "))
+		fmt.Fprintf(&sb, "- Dispatches: %s\n\n", joinIDs(c.DispatchIDs))
+		fmt.Fprintf(&sb, "```\n%s\n```\n\n", c.Example)
+	}
+	return sb.String()
+}
+
+func tokenSet(sig string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, tok := range strings.FieldsFunc(sig, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '<' || r == '>' || r == '_')
+	}) {
+		set[tok] = struct{}{}
+	}
+	return set
+}
+
+func jaccard(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	inter := 0
+	for tok := range a {
+		if _, ok := b[tok]; ok {
+			inter++
+		}
+	}
+	return float64(inter) / float64(len(a)+len(b)-inter)
+}
+
+func excerpt(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, line := range lines {
+		if errorLinePattern.MatchString(line) {
+			end := i + 5
+			if end > len(lines) {
+				end = len(lines)
+			}
+			return strings.Join(lines[i:end], "\n")
+		}
+	}
+	if len(lines) > 5 {
+		lines = lines[len(lines)-5:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func parseDBTime(value any) time.Time {
+	var raw string
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC()
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return time.Time{}
+	}
+	for _, layout := range []string{time.RFC3339Nano, time.DateTime, "2006-01-02 15:04:05 -0700 MST", "2006-01-02T15:04:05Z"} {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func nonEmpty(values ...string) []string {
+	var out []string
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func dedupeStrings(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	out := values[:0]
+	for _, v := range values {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		out = append(out, v)
+	}
+	return out
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	out := make([]string, 0, len(set))
+	for k := range set {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func joinIDs(ids []int64) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprintf("%d", id)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,89 @@
+package learner
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestSignatureNormalizesVolatileParts(t *testing.T) {
+	a := Signature("building...\n/home/a/proj/internal/x.go:42:7: undefined: \"FooBar\"\nexit status 2")
+	b := Signature("/tmp/ws-9/internal/y.go:17:3: undefined: \"Baz\"")
+	if a == "" || !strings.Contains(a, "undefined") {
+		t.Fatalf("unexpected signature %q", a)
+	}
+	if !strings.HasPrefix(a, b) {
+		t.Fatalf("expected same leading signature, got %q vs %q", a, b)
+	}
+	if got := Signature("panic: runtime error at 0xc000123456"); !strings.Contains(got, "<hex>") {
+		t.Fatalf("expected hex placeholder, got %q", got)
+	}
+}
+
+func TestClusterFailuresGroupsAcrossProjects(t *testing.T) {
+	now := time.Now()
+	samples := []FailureSample{
+		{DispatchID: 1, Project: "alpha", Agent: "claude", Text: "--- FAIL: TestLogin (0.12s)\n    login_test.go:33: connection refused", FailedAt: now},
+		{DispatchID: 2, Project: "beta", Agent: "codex", Text: "--- FAIL: TestLogin (3.40s)\n    login_test.go:35: connection refused", FailedAt: now.Add(-time.Hour)},
+		{DispatchID: 3, Project: "alpha", Agent: "codex", Text: "--- FAIL: TestLogin (0.50s)\n    login_test.go:33: connection refused", FailedAt: now.Add(-2 * time.Hour)},
+		{DispatchID: 4, Project: "gamma", Agent: "claude", Text: "npm ERR! missing script: lint", FailedAt: now},
+	}
+
+	clusters := ClusterFailures(samples)
+	if len(clusters) != 2 {
+		t.Fatalf("expected 2 clusters, got %d: %+v", len(clusters), clusters)
+	}
+	top := clusters[0]
+	if top.Count != 3 || strings.Join(top.Projects, ",") != "alpha,beta" || strings.Join(top.Agents, ",") != "claude,codex" {
+		t.Fatalf("unexpected top cluster: %+v", top)
+	}
+	if !top.FirstSeen.Equal(now.Add(-2*time.Hour)) || !top.LastSeen.Equal(now) {
+		t.Fatalf("unexpected seen range: %v - %v", top.FirstSeen, top.LastSeen)
+	}
+
+	report := FormatFailureClusterReport(clusters, now.Add(-7*24*time.Hour), now)
+	if !strings.Contains(report, "Occurrences: 3") || !strings.Contains(report, "alpha, beta") {
+		t.Fatalf("unexpected report:\n%s", report)
+	}
+}
+
+func TestTopFailureClustersReadsStore(t *testing.T) {
+	st, err := store.Open(t.TempDir() + "/test.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+
+	for i, project := range []string{"alpha", "beta"} {
+		id, err := st.RecordDispatch("bead-"+project, project, "claude", "claude", "temporal", 0, "", "", "", "", "temporal")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := st.UpdateDispatchStatus(id, "failed", 1, float64(i)); err != nil {
+			t.Fatal(err)
+		}
+		if err := st.RecordDoDResult(id, "bead-"+project, project, false, "go test ./... failed: panic: nil map write", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ok, err := st.RecordDispatch("bead-ok", "alpha", "claude", "claude", "temporal", 0, "", "", "", "", "temporal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := st.UpdateDispatchStatus(ok, "completed", 0, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	clusters, err := TopFailureClusters(st.DB(), time.Now().Add(-time.Hour), 5)
+	if err != nil {
+		t.Fatalf("TopFailureClusters: %v", err)
+	}
+	if len(clusters) != 1 || clusters[0].Count != 2 || len(clusters[0].Projects) != 2 {
+		t.Fatalf("unexpected clusters: %+v", clusters)
+	}
+	if clusters[0].LastSeen.IsZero() {
+		t.Fatal("expected parsed failure time")
+	}
+}
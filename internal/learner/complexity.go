@@ -0,0 +1,172 @@
+package learner
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+const (
+	// complexityPrior is how many beads' worth of weight the overall mean
+	// carries against a feature's own mean, so rarely seen features stay
+	// close to the average.
+	complexityPrior = 2.0
+	// complexityLongRunS marks a bead's dispatches as long-running.
+	complexityLongRunS = 1800.0
+
+	// Predicted difficulty below fastMaxScore starts at the fast tier,
+	// below balancedMaxScore at balanced, and otherwise at premium.
+	fastMaxScore     = 0.5
+	balancedMaxScore = 1.5
+)
+
+// ComplexityFeature is the mean difficulty of the beads sharing a feature.
+type ComplexityFeature struct {
+	Count     int     `json:"count"`
+	MeanScore float64 `json:"mean_score"`
+}
+
+// ComplexityModel predicts how hard a bead will be from the outcomes of
+// beads with the same features. A bead's difficulty counts its retries,
+// failed dispatches, tier escalations and long runs; the prediction adds
+// the deviations of its features' means from the overall mean.
+type ComplexityModel struct {
+	TrainedAt time.Time                    `json:"trained_at"`
+	Samples   int                          `json:"samples"`
+	MeanScore float64                      `json:"mean_score"`
+	Features  map[string]ComplexityFeature `json:"features"`
+}
+
+// TrainComplexityModel fits a model to the beads' outcomes.
+func TrainComplexityModel(samples []store.ComplexitySample) *ComplexityModel {
+	m := &ComplexityModel{TrainedAt: time.Now(), Features: map[string]ComplexityFeature{}}
+	if len(samples) == 0 {
+		return m
+	}
+	sums := map[string]float64{}
+	counts := map[string]int{}
+	var total float64
+	for _, s := range samples {
+		score := difficulty(s)
+		total += score
+		for _, key := range featureKeys(s.BeadFeatures) {
+			sums[key] += score
+			counts[key]++
+		}
+	}
+	m.Samples = len(samples)
+	m.MeanScore = total / float64(m.Samples)
+	for key, n := range counts {
+		m.Features[key] = ComplexityFeature{Count: n, MeanScore: sums[key] / float64(n)}
+	}
+	return m
+}
+
+// QueryComplexityModel trains a model on the beads dispatched since the
+// given time.
+func QueryComplexityModel(st *store.Store, since time.Time) (*ComplexityModel, error) {
+	samples, err := st.GetComplexitySamples(since)
+	if err != nil {
+		return nil, fmt.Errorf("query complexity samples: %w", err)
+	}
+	return TrainComplexityModel(samples), nil
+}
+
+// Score predicts the difficulty of a bead with features f. Features the
+// model has not seen leave the prediction at the overall mean.
+func (m *ComplexityModel) Score(f store.BeadFeatures) float64 {
+	score := m.MeanScore
+	for _, key := range featureKeys(f) {
+		if feat, ok := m.Features[key]; ok {
+			shrunk := (feat.MeanScore*float64(feat.Count) + m.MeanScore*complexityPrior) / (float64(feat.Count) + complexityPrior)
+			score += shrunk - m.MeanScore
+		}
+	}
+	return max(score, 0)
+}
+
+// Tier returns the tier a bead with features f should start at.
+func (m *ComplexityModel) Tier(f store.BeadFeatures) string {
+	switch score := m.Score(f); {
+	case score < fastMaxScore:
+		return "fast"
+	case score < balancedMaxScore:
+		return "balanced"
+	default:
+		return "premium"
+	}
+}
+
+// DetectComplexity picks a starting tier from the bead alone: estimates
+// above escalationMinutes start at premium, small chores and docs at fast,
+// and everything else at balanced.
+func DetectComplexity(f store.BeadFeatures, escalationMinutes int) string {
+	if escalationMinutes > 0 && f.EstimateMinutes > escalationMinutes {
+		return "premium"
+	}
+	switch strings.ToLower(f.Type) {
+	case "epic":
+		return "premium"
+	case "chore", "docs":
+		return "fast"
+	}
+	if f.EstimateMinutes > 0 && f.EstimateMinutes <= 30 {
+		return "fast"
+	}
+	return "balanced"
+}
+
+// StartingTier picks the tier a bead's first dispatch runs at: the model's
+// prediction once it has learned from minSamples beads, else the
+// DetectComplexity heuristics. learned reports which one chose.
+func StartingTier(m *ComplexityModel, minSamples int, f store.BeadFeatures, escalationMinutes int) (tier string, learned bool) {
+	if m != nil && m.Samples > 0 && m.Samples >= minSamples {
+		return m.Tier(f), true
+	}
+	return DetectComplexity(f, escalationMinutes), false
+}
+
+func difficulty(s store.ComplexitySample) float64 {
+	score := float64(s.Retries + s.Failures + s.Escalations)
+	if s.Dispatches > 1 {
+		score += float64(s.Dispatches - 1)
+	}
+	if s.DurationS > complexityLongRunS {
+		score++
+	}
+	return score
+}
+
+// featureKeys turns a bead's features into the categorical keys the model
+// keeps means for. Estimates and description lengths are bucketed.
+func featureKeys(f store.BeadFeatures) []string {
+	keys := []string{
+		"estimate:" + bucket(f.EstimateMinutes, []int{30, 120, 480}, "none"),
+		"description:" + bucket(f.DescriptionLen, []int{200, 1000, 4000}, "empty"),
+	}
+	if t := strings.ToLower(strings.TrimSpace(f.Type)); t != "" {
+		keys = append(keys, "type:"+t)
+	}
+	for _, l := range f.Labels {
+		if l = strings.ToLower(strings.TrimSpace(l)); l != "" {
+			keys = append(keys, "label:"+l)
+		}
+	}
+	return keys
+}
+
+// bucket names the bucket of v among ascending upper bounds: "<=30",
+// ">480", or zero when v is not positive.
+func bucket(v int, bounds []int, zero string) string {
+	if v <= 0 {
+		return zero
+	}
+	for _, b := range bounds {
+		if v <= b {
+			return fmt.Sprintf("<=%d", b)
+		}
+	}
+	return fmt.Sprintf(">%d", bounds[len(bounds)-1])
+}
@@ -0,0 +1,71 @@
+package learner
+
+import (
+	"testing"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestComplexityModelTier(t *testing.T) {
+	var samples []store.ComplexitySample
+	for i := 0; i < 10; i++ {
+		samples = append(samples,
+			store.ComplexitySample{BeadFeatures: store.BeadFeatures{Labels: []string{"auth"}}, Dispatches: 3, Failures: 2, Escalations: 1},
+			store.ComplexitySample{BeadFeatures: store.BeadFeatures{Labels: []string{"docs"}}, Dispatches: 1},
+		)
+	}
+	m := TrainComplexityModel(samples)
+	if m.Samples != 20 || m.MeanScore != 2.5 {
+		t.Fatalf("model = %+v", m)
+	}
+
+	cases := []struct {
+		labels []string
+		want   string
+	}{
+		{[]string{"auth"}, "premium"},
+		{[]string{"Docs"}, "fast"},
+		{[]string{"auth", "docs"}, "premium"}, // the deviations cancel out
+		{nil, "premium"},
+	}
+	for _, tc := range cases {
+		if got := m.Tier(store.BeadFeatures{Labels: tc.labels}); got != tc.want {
+			t.Errorf("Tier(%v) = %s (score %.2f), want %s", tc.labels, got, m.Score(store.BeadFeatures{Labels: tc.labels}), tc.want)
+		}
+	}
+}
+
+func TestStartingTier(t *testing.T) {
+	big := store.BeadFeatures{EstimateMinutes: 240}
+	if tier, learned := StartingTier(nil, 20, big, 120); tier != "premium" || learned {
+		t.Errorf("no model: got %s learned=%v", tier, learned)
+	}
+
+	easy := []store.ComplexitySample{{BeadFeatures: big, Dispatches: 1}}
+	m := TrainComplexityModel(easy)
+	if tier, learned := StartingTier(m, 20, big, 120); tier != "premium" || learned {
+		t.Errorf("too few samples: got %s learned=%v", tier, learned)
+	}
+	if tier, learned := StartingTier(m, 1, big, 120); tier != "fast" || !learned {
+		t.Errorf("trained: got %s learned=%v", tier, learned)
+	}
+}
+
+func TestDetectComplexity(t *testing.T) {
+	cases := []struct {
+		f    store.BeadFeatures
+		want string
+	}{
+		{store.BeadFeatures{EstimateMinutes: 180}, "premium"},
+		{store.BeadFeatures{Type: "epic"}, "premium"},
+		{store.BeadFeatures{Type: "chore", EstimateMinutes: 60}, "fast"},
+		{store.BeadFeatures{EstimateMinutes: 15}, "fast"},
+		{store.BeadFeatures{Type: "task", EstimateMinutes: 60}, "balanced"},
+		{store.BeadFeatures{}, "balanced"},
+	}
+	for _, tc := range cases {
+		if got := DetectComplexity(tc.f, 120); got != tc.want {
+			t.Errorf("DetectComplexity(%+v) = %s, want %s", tc.f, got, tc.want)
+		}
+	}
+}
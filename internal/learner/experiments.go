@@ -0,0 +1,132 @@
+package learner
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+)
+
+const (
+	// experimentMinSamples is the minimum completed runs per variant before a
+	// winner can be declared.
+	experimentMinSamples = 10
+	// experimentAlpha is the significance level for declaring a winner.
+	experimentAlpha = 0.05
+)
+
+// VariantStat summarizes completed runs for one experiment variant.
+type VariantStat struct {
+	Variant     string  `json:"variant"`
+	Runs        int     `json:"runs"`
+	Successes   int     `json:"successes"`
+	SuccessRate float64 `json:"success_rate"` // 0.0 - 1.0
+	DoDPassRate float64 `json:"dod_pass_rate"`
+	AvgDuration float64 `json:"avg_duration"` // seconds
+	AvgCost     float64 `json:"avg_cost"`     // USD
+}
+
+// ExperimentResult compares the variants of one experiment. Winner is set
+// only when the best variant beats the runner-up at experimentAlpha.
+type ExperimentResult struct {
+	Experiment  string        `json:"experiment"`
+	Role        string        `json:"role"`
+	Variants    []VariantStat `json:"variants"` // best success rate first
+	Pending     int           `json:"pending"`  // assigned runs without an outcome yet
+	Winner      string        `json:"winner,omitempty"`
+	PValue      float64       `json:"p_value"`
+	Significant bool          `json:"significant"`
+}
+
+// QueryExperimentResults aggregates experiment_runs into per-variant stats
+// and tests whether the leading variant is a statistically significant winner.
+func QueryExperimentResults(db *sql.DB) ([]ExperimentResult, error) {
+	rows, err := db.Query(`
+		SELECT experiment, MAX(role), variant,
+			SUM(completed),
+			SUM(CASE WHEN completed = 1 THEN success ELSE 0 END),
+			SUM(CASE WHEN completed = 1 THEN dod_passed ELSE 0 END),
+			COALESCE(AVG(CASE WHEN completed = 1 THEN duration_s END), 0),
+			COALESCE(AVG(CASE WHEN completed = 1 THEN cost_usd END), 0),
+			SUM(1 - completed)
+		FROM experiment_runs
+		GROUP BY experiment, variant
+		ORDER BY experiment, variant
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query experiment results: %w", err)
+	}
+	defer rows.Close()
+
+	var results []ExperimentResult
+	byName := make(map[string]int)
+	for rows.Next() {
+		var name, role string
+		var v VariantStat
+		var dodPassed, pending int
+		if err := rows.Scan(&name, &role, &v.Variant, &v.Runs, &v.Successes, &dodPassed, &v.AvgDuration, &v.AvgCost, &pending); err != nil {
+			return nil, fmt.Errorf("scan experiment result: %w", err)
+		}
+		if v.Runs > 0 {
+			v.SuccessRate = float64(v.Successes) / float64(v.Runs)
+			v.DoDPassRate = float64(dodPassed) / float64(v.Runs)
+		}
+		idx, ok := byName[name]
+		if !ok {
+			idx = len(results)
+			byName[name] = idx
+			results = append(results, ExperimentResult{Experiment: name, Role: role})
+		}
+		results[idx].Variants = append(results[idx].Variants, v)
+		results[idx].Pending += pending
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range results {
+		evaluateExperiment(&results[i])
+	}
+	return results, nil
+}
+
+// evaluateExperiment ranks variants and runs a two-proportion z-test between
+// the best and second-best success rates.
+func evaluateExperiment(r *ExperimentResult) {
+	sort.SliceStable(r.Variants, func(i, j int) bool {
+		if r.Variants[i].SuccessRate != r.Variants[j].SuccessRate {
+			return r.Variants[i].SuccessRate > r.Variants[j].SuccessRate
+		}
+		return r.Variants[i].AvgCost < r.Variants[j].AvgCost
+	})
+	r.PValue = 1
+	if len(r.Variants) < 2 {
+		return
+	}
+	best, next := r.Variants[0], r.Variants[1]
+	if best.Runs < experimentMinSamples || next.Runs < experimentMinSamples {
+		return
+	}
+	r.PValue = twoProportionPValue(best.Successes, best.Runs, next.Successes, next.Runs)
+	if r.PValue < experimentAlpha && best.SuccessRate > next.SuccessRate {
+		r.Significant = true
+		r.Winner = best.Variant
+	}
+}
+
+// twoProportionPValue returns the two-sided p-value for the difference between
+// two success proportions using the pooled z-test.
+func twoProportionPValue(s1, n1, s2, n2 int) float64 {
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+	p1 := float64(s1) / float64(n1)
+	p2 := float64(s2) / float64(n2)
+	pooled := float64(s1+s2) / float64(n1+n2)
+	se := math.Sqrt(pooled * (1 - pooled) * (1/float64(n1) + 1/float64(n2)))
+	if se == 0 {
+		return 1
+	}
+	z := math.Abs(p1-p2) / se
+	return math.Erfc(z / math.Sqrt2)
+}
@@ -0,0 +1,71 @@
+package learner
+
+import (
+	"testing"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestTwoProportionPValue(t *testing.T) {
+	if p := twoProportionPValue(18, 20, 8, 20); p >= 0.01 {
+		t.Fatalf("expected strong significance for 90%% vs 40%%, got p=%f", p)
+	}
+	if p := twoProportionPValue(11, 20, 10, 20); p < 0.5 {
+		t.Fatalf("expected no significance for 55%% vs 50%%, got p=%f", p)
+	}
+	if p := twoProportionPValue(0, 0, 5, 10); p != 1 {
+		t.Fatalf("expected p=1 with no samples, got %f", p)
+	}
+}
+
+func TestQueryExperimentResultsDeclaresWinner(t *testing.T) {
+	st, err := store.Open(t.TempDir() + "/test.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+
+	record := func(experiment, variant string, n, successes int) {
+		for i := 0; i < n; i++ {
+			id, err := st.RecordExperimentAssignment(experiment, variant, "coder", "bead", "cortex")
+			if err != nil {
+				t.Fatal(err)
+			}
+			ok := i < successes
+			if err := st.CompleteExperimentRun(id, int64(i+1), ok, ok, 60, 0.1); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	record("exec-prompt", "control", 20, 8)
+	record("exec-prompt", "terse", 20, 18)
+	record("review-agent", "claude", 5, 5)
+	record("review-agent", "codex", 5, 1)
+	if _, err := st.RecordExperimentAssignment("review-agent", "codex", "reviewer", "bead-x", "cortex"); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := QueryExperimentResults(st.DB())
+	if err != nil {
+		t.Fatalf("QueryExperimentResults: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 experiments, got %+v", results)
+	}
+
+	exec := results[0]
+	if exec.Experiment != "exec-prompt" || !exec.Significant || exec.Winner != "terse" {
+		t.Fatalf("expected terse to win exec-prompt, got %+v", exec)
+	}
+	if exec.Variants[0].Variant != "terse" || exec.Variants[0].SuccessRate != 0.9 {
+		t.Fatalf("expected variants ranked by success rate, got %+v", exec.Variants)
+	}
+
+	review := results[1]
+	if review.Significant || review.Winner != "" {
+		t.Fatalf("expected no winner below the sample minimum, got %+v", review)
+	}
+	if review.Pending != 1 {
+		t.Fatalf("expected 1 pending run, got %d", review.Pending)
+	}
+}
@@ -17,6 +17,9 @@ type LearnerReport struct {
 	ModelStats  []ModelStat    `json:"model_stats"`
 	Sizing      SizingAnalysis `json:"sizing"`
 	Patterns    []Pattern      `json:"patterns"`
+	FailureClusters []FailureCluster `json:"failure_clusters"` // top recurring failures, last 7 days
+	Experiments     []ExperimentResult `json:"experiments"`
+	Calibration     *ConfidenceCalibration `json:"confidence_calibration,omitempty"`
 	Recommendations []string   `json:"recommendations"`
 }
 
@@ -112,6 +115,50 @@ func Analyze(db *sql.DB) (*LearnerReport, []LogEntry, error) {
 		}
 	}
 
+	// --- Failure Clustering ---
+	clusters, err := TopFailureClusters(db, time.Now().Add(-7*24*time.Hour), 5)
+	if err != nil {
+		logf("error", "Failed to cluster failures: %v", err)
+	} else {
+		report.FailureClusters = clusters
+		for _, c := range clusters {
+			if c.Count < 3 && len(c.Projects) < 2 {
+				continue
+			}
+			p := Pattern{
+				Type:        "recurring_failure",
+				Description: fmt.Sprintf("%q recurred %d times across %d project(s)", c.Signature, c.Count, len(c.Projects)),
+				Frequency:   c.Count,
+				Severity:    severityFromCount(c.Count),
+			}
+			report.Patterns = append(report.Patterns, p)
+			logf("pattern", "[%s] %s (seen %dx, severity: %s)", p.Type, p.Description, p.Frequency, p.Severity)
+		}
+	}
+
+	// --- Experiments ---
+	experiments, err := QueryExperimentResults(db)
+	if err != nil {
+		logf("error", "Failed to query experiment results: %v", err)
+	} else {
+		report.Experiments = experiments
+		for _, e := range experiments {
+			if e.Significant {
+				logf("analysis", "Experiment %s: variant %s wins (p=%.3f)", e.Experiment, e.Winner, e.PValue)
+			}
+		}
+	}
+
+	// --- Confidence Calibration ---
+	calibration, err := QueryConfidenceCalibration(db, time.Now().Add(-7*24*time.Hour))
+	if err != nil {
+		logf("error", "Failed to query confidence calibration: %v", err)
+	} else if calibration.Samples > 0 {
+		report.Calibration = calibration
+		logf("analysis", "Confidence: mean %.0f%% vs %.0f%% DoD pass (brier %.3f, %d samples)",
+			calibration.MeanConfidence*100, calibration.DoDPassRate*100, calibration.BrierScore, calibration.Samples)
+	}
+
 	// --- Recommendations ---
 	recs := generateRecommendations(report)
 	report.Recommendations = recs
@@ -259,6 +306,27 @@ func detectPatterns(db *sql.DB) ([]Pattern, error) {
 func generateRecommendations(report *LearnerReport) []string {
 	var recs []string
 
+	// Experiment winners are significance-tested on their own, so they are
+	// reported regardless of overall task volume.
+	for _, e := range report.Experiments {
+		if !e.Significant || len(e.Variants) < 2 {
+			continue
+		}
+		recs = append(recs, fmt.Sprintf("Experiment %s (%s): adopt variant %s — %.0f%% success vs %.0f%% for %s (p=%.3f)",
+			e.Experiment, e.Role, e.Winner, e.Variants[0].SuccessRate*100, e.Variants[1].SuccessRate*100, e.Variants[1].Variant, e.PValue))
+	}
+
+	if c := report.Calibration; c != nil && c.Samples >= calibrationMinSamples {
+		switch gap := c.Gap(); {
+		case gap > calibrationMaxGap:
+			recs = append(recs, fmt.Sprintf("Agents are overconfident: mean reported confidence %.0f%% vs %.0f%% DoD pass rate — raise dispatch.confidence.threshold",
+				c.MeanConfidence*100, c.DoDPassRate*100))
+		case gap < -calibrationMaxGap:
+			recs = append(recs, fmt.Sprintf("Agents are underconfident: mean reported confidence %.0f%% vs %.0f%% DoD pass rate — dispatch.confidence.threshold can be lowered",
+				c.MeanConfidence*100, c.DoDPassRate*100))
+		}
+	}
+
 	if report.TotalTasks < 5 {
 		recs = append(recs, "Insufficient data (< 5 tasks) — models are treated equally. Run more tasks to build performance data.")
 		return recs
@@ -0,0 +1,41 @@
+package learner
+
+import (
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+// ProviderHealth is the learner's view of how well a provider has been doing.
+type ProviderHealth struct {
+	Provider  string  `json:"provider"`
+	Samples   int     `json:"samples"`
+	Successes int     `json:"successes"`
+	Score     float64 `json:"score"` // 0.0 - 1.0
+}
+
+// ScoreProviderHealth turns a provider's finished dispatches into a health
+// score: the success rate with one success and one failure added, so a
+// provider with a handful of dispatches is not judged perfect or hopeless.
+func ScoreProviderHealth(stat store.ProviderStat) ProviderHealth {
+	return ProviderHealth{
+		Provider:  stat.Provider,
+		Samples:   stat.Total,
+		Successes: stat.Successes,
+		Score:     float64(stat.Successes+1) / float64(stat.Total+2),
+	}
+}
+
+// ProviderHealthScores scores every provider with finished dispatches in the
+// window. Providers without any are absent; all models start equal.
+func ProviderHealthScores(st *store.Store, window time.Duration) (map[string]ProviderHealth, error) {
+	stats, err := st.GetProviderStats(window)
+	if err != nil {
+		return nil, err
+	}
+	scores := make(map[string]ProviderHealth, len(stats))
+	for name, stat := range stats {
+		scores[name] = ScoreProviderHealth(stat)
+	}
+	return scores, nil
+}
@@ -0,0 +1,28 @@
+package learner
+
+import (
+	"testing"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestScoreProviderHealth(t *testing.T) {
+	cases := []struct {
+		stat store.ProviderStat
+		want float64
+	}{
+		{store.ProviderStat{Provider: "new", Total: 0}, 0.5},
+		{store.ProviderStat{Provider: "lucky", Total: 1, Successes: 1}, 2.0 / 3},
+		{store.ProviderStat{Provider: "solid", Total: 98, Successes: 98}, 0.99},
+		{store.ProviderStat{Provider: "broken", Total: 8, Successes: 0}, 0.1},
+	}
+	for _, tc := range cases {
+		got := ScoreProviderHealth(tc.stat)
+		if diff := got.Score - tc.want; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("%s: score = %v, want %v", tc.stat.Provider, got.Score, tc.want)
+		}
+		if got.Samples != tc.stat.Total || got.Provider != tc.stat.Provider {
+			t.Errorf("%s: unexpected health %+v", tc.stat.Provider, got)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package learner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+// EstimateLookup returns a bead's estimate in minutes, or 0 when it has none.
+type EstimateLookup func(project, beadID string) int
+
+// ComputeVelocity derives per-project velocity from a sprint timeline. A bead
+// counts as closed when it completed within the sprint; estimate vs. actual
+// compares only closed beads that carried an estimate. Stage cycle times
+// average every stage that finished within the sprint, closed bead or not.
+func ComputeVelocity(tl *store.SprintTimeline, estimate EstimateLookup) []store.SprintVelocity {
+	byProject := map[string]*store.SprintVelocity{}
+	var projects []string
+	stageTotals := map[string]map[string]float64{}
+	stageCounts := map[string]map[string]int{}
+
+	for _, b := range tl.Beads {
+		v, ok := byProject[b.Project]
+		if !ok {
+			v = &store.SprintVelocity{
+				Project:           b.Project,
+				SprintNumber:      tl.SprintNumber,
+				SprintStart:       tl.SprintStart,
+				SprintEnd:         tl.SprintEnd,
+				StageCycleMinutes: map[string]float64{},
+			}
+			byProject[b.Project] = v
+			projects = append(projects, b.Project)
+			stageTotals[b.Project] = map[string]float64{}
+			stageCounts[b.Project] = map[string]int{}
+		}
+
+		for _, st := range b.Stages {
+			if st.CompletedAt == nil || !st.CompletedAt.Before(tl.SprintEnd) || st.CompletedAt.Equal(st.StartedAt) {
+				continue
+			}
+			stageTotals[b.Project][st.Stage] += st.CompletedAt.Sub(st.StartedAt).Minutes()
+			stageCounts[b.Project][st.Stage]++
+		}
+
+		if b.CompletedAt == nil || b.CompletedAt.Before(tl.SprintStart) || !b.CompletedAt.Before(tl.SprintEnd) {
+			continue
+		}
+		v.BeadsClosed++
+		v.AvgCycleMinutes += b.CompletedAt.Sub(b.StartedAt).Minutes()
+		if estimate == nil {
+			continue
+		}
+		if est := estimate(b.Project, b.BeadID); est > 0 {
+			v.EstimatedBeads++
+			v.EstimateMinutes += est
+			v.ActualMinutes += dispatchMinutes(b.Dispatches)
+		}
+	}
+
+	sort.Strings(projects)
+	out := make([]store.SprintVelocity, 0, len(projects))
+	for _, p := range projects {
+		v := byProject[p]
+		if v.BeadsClosed > 0 {
+			v.AvgCycleMinutes /= float64(v.BeadsClosed)
+		}
+		for stage, total := range stageTotals[p] {
+			v.StageCycleMinutes[stage] = total / float64(stageCounts[p][stage])
+		}
+		out = append(out, *v)
+	}
+	return out
+}
+
+// dispatchMinutes sums the running time of finished dispatch windows.
+func dispatchMinutes(windows []store.DispatchWindow) float64 {
+	var total time.Duration
+	for _, w := range windows {
+		if w.EndedAt != nil {
+			total += w.EndedAt.Sub(w.StartedAt)
+		}
+	}
+	return total.Minutes()
+}
+
+// RecordSprintVelocity computes velocity for a sprint and stores it in the
+// analytics table. It returns nil when the sprint has no recorded boundary.
+func RecordSprintVelocity(st *store.Store, sprintNumber int, estimate EstimateLookup) ([]store.SprintVelocity, error) {
+	tl, err := st.GetSprintTimeline(sprintNumber, "")
+	if err != nil || tl == nil {
+		return nil, err
+	}
+	velocity := ComputeVelocity(tl, estimate)
+	now := time.Now()
+	for i := range velocity {
+		velocity[i].ComputedAt = now
+		if err := st.UpsertSprintVelocity(velocity[i]); err != nil {
+			return nil, err
+		}
+	}
+	return velocity, nil
+}
+
+// FormatVelocity renders velocity history as a markdown table, newest sprint
+// first, for inclusion in ceremony prompts.
+func FormatVelocity(history []store.SprintVelocity) string {
+	if len(history) == 0 {
+		return "No velocity recorded yet.\n"
+	}
+	var b strings.Builder
+	b.WriteString("| Sprint | Project | Closed | Avg cycle | Estimate vs actual | Slowest stage |\n")
+	b.WriteString("|---|---|---|---|---|---|\n")
+	for _, v := range history {
+		estimate := "-"
+		if v.EstimatedBeads > 0 {
+			estimate = fmt.Sprintf("%dm vs %.0fm (%d beads)", v.EstimateMinutes, v.ActualMinutes, v.EstimatedBeads)
+		}
+		slowest, slowestMinutes := "-", 0.0
+		for stage, m := range v.StageCycleMinutes {
+			if m > slowestMinutes || (m == slowestMinutes && stage < slowest) {
+				slowest, slowestMinutes = stage, m
+			}
+		}
+		if slowest != "-" {
+			slowest = fmt.Sprintf("%s (%.0fm)", slowest, slowestMinutes)
+		}
+		fmt.Fprintf(&b, "| %d | %s | %d | %.0fm | %s | %s |\n",
+			v.SprintNumber, v.Project, v.BeadsClosed, v.AvgCycleMinutes, estimate, slowest)
+	}
+	return b.String()
+}
@@ -0,0 +1,116 @@
+package learner
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestComputeVelocity(t *testing.T) {
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	at := func(min int) *time.Time {
+		t := start.Add(time.Duration(min) * time.Minute)
+		return &t
+	}
+	tl := &store.SprintTimeline{
+		SprintNumber: 4,
+		SprintStart:  start,
+		SprintEnd:    start.Add(7 * 24 * time.Hour),
+		Beads: []store.TimelineBead{
+			{
+				Project: "alpha", BeadID: "cx-1", StartedAt: *at(0), CompletedAt: at(90),
+				Stages: []store.StageHistoryEntry{
+					{Stage: "coding", StartedAt: *at(0), CompletedAt: at(60)},
+					{Stage: "review", StartedAt: *at(60), CompletedAt: at(90)},
+					{Stage: "done", StartedAt: *at(90), CompletedAt: at(90)},
+				},
+				Dispatches: []store.DispatchWindow{
+					{StartedAt: *at(0), EndedAt: at(50)},
+					{StartedAt: *at(60), EndedAt: at(80)},
+				},
+			},
+			{
+				Project: "alpha", BeadID: "cx-2", StartedAt: *at(0), CompletedAt: at(30),
+				Dispatches: []store.DispatchWindow{{StartedAt: *at(0), EndedAt: at(30)}},
+			},
+			{
+				// Still in progress: counts toward stage times only.
+				Project: "alpha", BeadID: "cx-3", StartedAt: *at(0),
+				Stages: []store.StageHistoryEntry{
+					{Stage: "coding", StartedAt: *at(0), CompletedAt: at(120)},
+					{Stage: "review", StartedAt: *at(120)},
+				},
+			},
+			{Project: "beta", BeadID: "cx-4", StartedAt: *at(10)},
+		},
+	}
+	estimates := map[string]int{"cx-1": 60}
+
+	got := ComputeVelocity(tl, func(_, id string) int { return estimates[id] })
+	if len(got) != 2 || got[0].Project != "alpha" || got[1].Project != "beta" {
+		t.Fatalf("expected alpha and beta, got %+v", got)
+	}
+	a := got[0]
+	if a.SprintNumber != 4 || a.BeadsClosed != 2 || a.AvgCycleMinutes != 60 {
+		t.Fatalf("unexpected alpha velocity %+v", a)
+	}
+	if a.EstimatedBeads != 1 || a.EstimateMinutes != 60 || math.Abs(a.ActualMinutes-70) > 1e-9 {
+		t.Fatalf("unexpected estimate vs actual %+v", a)
+	}
+	if a.StageCycleMinutes["coding"] != 90 || a.StageCycleMinutes["review"] != 30 || len(a.StageCycleMinutes) != 2 {
+		t.Fatalf("unexpected stage cycle times %+v", a.StageCycleMinutes)
+	}
+	if got[1].BeadsClosed != 0 || got[1].AvgCycleMinutes != 0 {
+		t.Fatalf("unexpected beta velocity %+v", got[1])
+	}
+
+	table := FormatVelocity(got)
+	if !strings.Contains(table, "| 4 | alpha | 2 | 60m | 60m vs 70m (1 beads) | coding (90m) |") {
+		t.Fatalf("unexpected table:\n%s", table)
+	}
+	if !strings.Contains(table, "| 4 | beta | 0 | 0m | - | - |") {
+		t.Fatalf("unexpected table:\n%s", table)
+	}
+}
+
+func TestRecordSprintVelocity(t *testing.T) {
+	st, err := store.Open(t.TempDir() + "/test.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+
+	if v, err := RecordSprintVelocity(st, 1, nil); err != nil || v != nil {
+		t.Fatalf("expected nothing for an unknown sprint, got %+v, %v", v, err)
+	}
+
+	start := time.Now().UTC().Add(-24 * time.Hour)
+	if err := st.RecordSprintBoundary(1, start, start.Add(72*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	id, err := st.RecordDispatch("cx-1", "alpha", "agent", "claude", "balanced", 1, "", "p", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := st.UpdateDispatchStatus(id, "completed", 0, 60); err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := RecordSprintVelocity(st, 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(v) != 1 || v[0].BeadsClosed != 1 {
+		t.Fatalf("unexpected velocity %+v", v)
+	}
+	stored, err := st.ListSprintVelocity("alpha", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 1 || stored[0].SprintNumber != 1 || stored[0].BeadsClosed != 1 {
+		t.Fatalf("unexpected stored velocity %+v", stored)
+	}
+}
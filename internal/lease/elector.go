@@ -0,0 +1,73 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// LeaderKey is the key instances campaign for. Bead IDs always contain a
+// dash, so it cannot collide with a bead claim.
+const LeaderKey = "_leader"
+
+// ErrDeposed is returned by Run when this instance loses leadership.
+var ErrDeposed = errors.New("lease: leadership lost")
+
+// Elector campaigns for leadership among the instances sharing a Backend.
+// The instance holding LeaderKey is active; the others stand by and take
+// over once its lease lapses. Leading on a nil Elector reports true, so a
+// deployment without HA is always active.
+type Elector struct {
+	Backend Backend
+	Holder  string
+	TTL     time.Duration
+
+	leading atomic.Bool
+}
+
+// Leading reports whether this instance holds leadership.
+func (e *Elector) Leading() bool {
+	return e == nil || e.leading.Load()
+}
+
+// Run campaigns every third of TTL until ctx is done. When this instance is
+// elected it calls onElected once and keeps renewing. It returns ErrDeposed
+// when another instance has taken leadership, or when no renewal has
+// succeeded for a whole TTL, after which another instance may have; the
+// caller must then stop acting as leader.
+func (e *Elector) Run(ctx context.Context, onElected func()) error {
+	var renewed time.Time
+	for {
+		err := e.Backend.Acquire(ctx, LeaderKey, e.Holder, e.TTL)
+		if ctx.Err() != nil {
+			return nil
+		}
+		switch {
+		case err == nil:
+			renewed = time.Now()
+			if !e.leading.Swap(true) {
+				onElected()
+			}
+		case e.leading.Load() && (errors.Is(err, ErrHeld) || time.Since(renewed) >= e.TTL):
+			e.leading.Store(false)
+			return fmt.Errorf("%w: %v", ErrDeposed, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(e.TTL / 3):
+		}
+	}
+}
+
+// Resign gives up leadership so a standby can take over without waiting
+// for the lease to lapse.
+func (e *Elector) Resign(ctx context.Context) error {
+	if e == nil || !e.leading.Swap(false) {
+		return nil
+	}
+	return e.Backend.Release(ctx, LeaderKey, e.Holder)
+}
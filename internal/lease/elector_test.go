@@ -0,0 +1,120 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memBackend is an in-memory Backend with real expiry.
+type memBackend struct {
+	mu     sync.Mutex
+	leases map[string]memLease
+}
+
+type memLease struct {
+	holder  string
+	expires time.Time
+}
+
+func (m *memBackend) Acquire(_ context.Context, key, holder string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if l, ok := m.leases[key]; ok && l.holder != holder && time.Now().Before(l.expires) {
+		return ErrHeld
+	}
+	m.leases[key] = memLease{holder: holder, expires: time.Now().Add(ttl)}
+	return nil
+}
+
+func (m *memBackend) Release(_ context.Context, key, holder string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.leases[key].holder == holder {
+		delete(m.leases, key)
+	}
+	return nil
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestElectorFailover(t *testing.T) {
+	backend := &memBackend{leases: map[string]memLease{}}
+	ttl := 90 * time.Millisecond
+	active := &Elector{Backend: backend, Holder: "host-a", TTL: ttl}
+	standby := &Elector{Backend: backend, Holder: "host-b", TTL: ttl}
+
+	activeCtx, killActive := context.WithCancel(context.Background())
+	elected := make(chan string, 2)
+	go active.Run(activeCtx, func() { elected <- "host-a" })
+	if got := <-elected; got != "host-a" {
+		t.Fatalf("first elected %s", got)
+	}
+
+	standbyCtx, stopStandby := context.WithCancel(context.Background())
+	defer stopStandby()
+	standbyDone := make(chan error, 1)
+	go func() { standbyDone <- standby.Run(standbyCtx, func() { elected <- "host-b" }) }()
+	time.Sleep(2 * ttl)
+	if standby.Leading() {
+		t.Fatal("standby elected while the leader renews")
+	}
+
+	// The leader dies without resigning; the standby takes over once its
+	// lease lapses.
+	killActive()
+	if got := <-elected; got != "host-b" {
+		t.Fatalf("takeover by %s", got)
+	}
+	waitFor(t, "standby to lead", standby.Leading)
+
+	// Another instance takes the key: the standby is deposed.
+	backend.mu.Lock()
+	backend.leases[LeaderKey] = memLease{holder: "host-c", expires: time.Now().Add(time.Hour)}
+	backend.mu.Unlock()
+	select {
+	case err := <-standbyDone:
+		if !errors.Is(err, ErrDeposed) {
+			t.Fatalf("Run = %v, want ErrDeposed", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("standby not deposed")
+	}
+	if standby.Leading() {
+		t.Fatal("deposed instance still leading")
+	}
+}
+
+func TestElectorResign(t *testing.T) {
+	backend := &memBackend{leases: map[string]memLease{}}
+	e := &Elector{Backend: backend, Holder: "host-a", TTL: time.Hour}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() { e.Run(ctx, func() {}); close(done) }()
+	waitFor(t, "election", e.Leading)
+	cancel()
+	<-done
+
+	if err := e.Resign(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.Acquire(context.Background(), LeaderKey, "host-b", time.Hour); err != nil {
+		t.Fatalf("leadership not released: %v", err)
+	}
+
+	var none *Elector
+	if !none.Leading() {
+		t.Fatal("nil elector must always lead")
+	}
+}
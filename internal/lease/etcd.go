@@ -0,0 +1,149 @@
+package lease
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// etcdBackend holds leases as etcd keys bound to etcd leases, through the
+// v3 JSON gateway so no etcd client library is needed. A key's value is its
+// holder; etcd deletes the key once its lease expires.
+type etcdBackend struct {
+	endpoints []string
+	prefix    string
+	client    *http.Client
+}
+
+// NewEtcdBackend returns a Backend holding leases under prefix in the etcd
+// cluster at endpoints. Requests go to the first endpoint that answers.
+func NewEtcdBackend(endpoints []string, prefix string, client *http.Client) Backend {
+	trimmed := make([]string, len(endpoints))
+	for i, ep := range endpoints {
+		trimmed[i] = strings.TrimRight(ep, "/")
+	}
+	return &etcdBackend{endpoints: trimmed, prefix: prefix, client: client}
+}
+
+type etcdCompare struct {
+	Key            string `json:"key"`
+	Result         string `json:"result"`
+	Target         string `json:"target"`
+	CreateRevision string `json:"create_revision,omitempty"`
+	Value          string `json:"value,omitempty"`
+}
+
+type etcdRequestOp struct {
+	RequestPut         *etcdPut   `json:"request_put,omitempty"`
+	RequestDeleteRange *etcdRange `json:"request_delete_range,omitempty"`
+}
+
+type etcdPut struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Lease string `json:"lease,omitempty"`
+}
+
+type etcdRange struct {
+	Key string `json:"key"`
+}
+
+type etcdTxn struct {
+	Compare []etcdCompare   `json:"compare"`
+	Success []etcdRequestOp `json:"success"`
+}
+
+func (b *etcdBackend) Acquire(ctx context.Context, key, holder string, ttl time.Duration) error {
+	var grant struct {
+		ID string `json:"ID"`
+	}
+	if err := b.call(ctx, "/v3/lease/grant", map[string]any{"TTL": int64(ttl.Seconds())}, &grant); err != nil {
+		return fmt.Errorf("lease: grant etcd lease: %w", err)
+	}
+
+	k, v := b.encode(key), base64.StdEncoding.EncodeToString([]byte(holder))
+	put := []etcdRequestOp{{RequestPut: &etcdPut{Key: k, Value: v, Lease: grant.ID}}}
+	// Take the key if nobody has it, else renew it if holder has it.
+	for _, cmp := range []etcdCompare{
+		{Key: k, Result: "EQUAL", Target: "CREATE", CreateRevision: "0"},
+		{Key: k, Result: "EQUAL", Target: "VALUE", Value: v},
+	} {
+		ok, err := b.txn(ctx, etcdTxn{Compare: []etcdCompare{cmp}, Success: put})
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+	}
+	// The unused lease would expire anyway; revoking it is a courtesy.
+	_ = b.call(ctx, "/v3/lease/revoke", map[string]any{"ID": grant.ID}, nil)
+	return ErrHeld
+}
+
+func (b *etcdBackend) Release(ctx context.Context, key, holder string) error {
+	k, v := b.encode(key), base64.StdEncoding.EncodeToString([]byte(holder))
+	_, err := b.txn(ctx, etcdTxn{
+		Compare: []etcdCompare{{Key: k, Result: "EQUAL", Target: "VALUE", Value: v}},
+		Success: []etcdRequestOp{{RequestDeleteRange: &etcdRange{Key: k}}},
+	})
+	return err
+}
+
+func (b *etcdBackend) encode(key string) string {
+	return base64.StdEncoding.EncodeToString([]byte(b.prefix + key))
+}
+
+func (b *etcdBackend) txn(ctx context.Context, txn etcdTxn) (bool, error) {
+	var resp struct {
+		Succeeded bool `json:"succeeded"`
+	}
+	if err := b.call(ctx, "/v3/kv/txn", txn, &resp); err != nil {
+		return false, fmt.Errorf("lease: etcd txn: %w", err)
+	}
+	return resp.Succeeded, nil
+}
+
+// call posts body to path on the first endpoint that answers and decodes
+// the reply into out, which may be nil.
+func (b *etcdBackend) call(ctx context.Context, path string, body, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	var lastErr error
+	for _, ep := range b.endpoints {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep+path, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := b.client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("%s: %s: %s", ep+path, resp.Status, strings.TrimSpace(string(data)))
+		}
+		if out == nil {
+			return nil
+		}
+		return json.Unmarshal(data, out)
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no etcd endpoints configured")
+	}
+	return lastErr
+}
@@ -0,0 +1,90 @@
+package lease
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeEtcd serves the parts of the etcd v3 JSON gateway the backend uses,
+// keeping keys in memory without expiry.
+type fakeEtcd struct {
+	mu   sync.Mutex
+	kv   map[string]string
+	next int
+}
+
+func (f *fakeEtcd) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.URL.Path {
+	case "/v3/lease/grant":
+		f.next++
+		// etcd encodes 64-bit IDs as JSON strings.
+		json.NewEncoder(w).Encode(map[string]any{"ID": strconv.Itoa(f.next)})
+	case "/v3/lease/revoke":
+		w.Write([]byte("{}"))
+	case "/v3/kv/txn":
+		var txn etcdTxn
+		if err := json.NewDecoder(r.Body).Decode(&txn); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, cmp := range txn.Compare {
+			value, exists := f.kv[cmp.Key]
+			if (cmp.Target == "CREATE" && exists) || (cmp.Target == "VALUE" && (!exists || value != cmp.Value)) {
+				w.Write([]byte("{}"))
+				return
+			}
+		}
+		for _, op := range txn.Success {
+			if op.RequestPut != nil {
+				f.kv[op.RequestPut.Key] = op.RequestPut.Value
+			}
+			if op.RequestDeleteRange != nil {
+				delete(f.kv, op.RequestDeleteRange.Key)
+			}
+		}
+		w.Write([]byte(`{"succeeded":true}`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestEtcdBackend(t *testing.T) {
+	fake := &fakeEtcd{kv: map[string]string{}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	ctx := context.Background()
+
+	// The first endpoint is down; requests fall through to the second.
+	b := NewEtcdBackend([]string{"http://127.0.0.1:1", srv.URL + "/"}, "/cortex/claims/", srv.Client())
+
+	if err := b.Acquire(ctx, "cx-1", "host-a", time.Minute); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	if err := b.Acquire(ctx, "cx-1", "host-a", time.Minute); err != nil {
+		t.Fatalf("renew: %v", err)
+	}
+	if err := b.Acquire(ctx, "cx-1", "host-b", time.Minute); !errors.Is(err, ErrHeld) {
+		t.Fatalf("acquire by another holder = %v, want ErrHeld", err)
+	}
+	if err := b.Release(ctx, "cx-1", "host-b"); err != nil {
+		t.Fatal(err)
+	}
+	if len(fake.kv) != 1 {
+		t.Fatal("release by a non-holder deleted the key")
+	}
+	if err := b.Release(ctx, "cx-1", "host-a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Acquire(ctx, "cx-1", "host-b", time.Minute); err != nil {
+		t.Fatalf("acquire after release: %v", err)
+	}
+}
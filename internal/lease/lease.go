@@ -0,0 +1,124 @@
+// Package lease keeps cortex instances that share projects from dispatching
+// the same bead. A Backend holds expiring keys that one holder owns at a
+// time; Claims uses one to claim beads on behalf of this instance.
+package lease
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+// ErrHeld is returned by Acquire when another holder has the key.
+var ErrHeld = errors.New("lease: held by another instance")
+
+// Backend holds expiring, exclusively owned keys.
+type Backend interface {
+	// Acquire gives key to holder for ttl. Acquiring a key the holder
+	// already has renews it; a key another holder has returns ErrHeld.
+	Acquire(ctx context.Context, key, holder string, ttl time.Duration) error
+	// Release frees key if holder has it.
+	Release(ctx context.Context, key, holder string) error
+}
+
+// Claims claims beads for one cortex instance. Methods on a nil Claims do
+// nothing, so callers need not check whether claims are enabled.
+type Claims struct {
+	Backend Backend
+	Holder  string        // this instance's name
+	TTL     time.Duration // claim lifetime without renewal
+}
+
+// New returns the claims cfg describes, or nil when they are disabled.
+func New(cfg config.DispatchClaims, st *store.Store) (*Claims, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	backend, err := NewBackend(cfg, st)
+	if err != nil {
+		return nil, err
+	}
+	holder, err := InstanceID(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Claims{Backend: backend, Holder: holder, TTL: cfg.TTL.Duration}, nil
+}
+
+// NewBackend returns the Backend cfg selects, whether or not bead claims
+// are enabled.
+func NewBackend(cfg config.DispatchClaims, st *store.Store) (Backend, error) {
+	switch cfg.Backend {
+	case "sqlite":
+		if st == nil {
+			return nil, fmt.Errorf("lease: the sqlite backend needs the state store")
+		}
+		return NewStoreBackend(st), nil
+	case "etcd":
+		return NewEtcdBackend(cfg.EtcdEndpoints, cfg.EtcdPrefix, &http.Client{Timeout: 10 * time.Second}), nil
+	default:
+		return nil, fmt.Errorf("lease: unknown backend %q", cfg.Backend)
+	}
+}
+
+// InstanceID returns the name this instance holds leases under: the
+// configured instance_id, else the hostname.
+func InstanceID(cfg config.DispatchClaims) (string, error) {
+	if id := strings.TrimSpace(cfg.InstanceID); id != "" {
+		return id, nil
+	}
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		return "", fmt.Errorf("lease: dispatch.claims.instance_id is unset and the hostname is unavailable: %v", err)
+	}
+	return host, nil
+}
+
+// Claim claims beadID for this instance, or renews its claim.
+func (c *Claims) Claim(ctx context.Context, beadID string) error {
+	if c == nil {
+		return nil
+	}
+	return c.Backend.Acquire(ctx, beadID, c.Holder, c.TTL)
+}
+
+// Release gives up this instance's claim on beadID.
+func (c *Claims) Release(ctx context.Context, beadID string) error {
+	if c == nil {
+		return nil
+	}
+	return c.Backend.Release(ctx, beadID, c.Holder)
+}
+
+// storeBackend holds leases in the state store, which instances share when
+// they use the same state_db.
+type storeBackend struct {
+	st *store.Store
+}
+
+// NewStoreBackend returns a Backend holding leases in st.
+func NewStoreBackend(st *store.Store) Backend {
+	return storeBackend{st: st}
+}
+
+func (b storeBackend) Acquire(_ context.Context, key, holder string, ttl time.Duration) error {
+	ok, err := b.st.AcquireLease(key, holder, ttl)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrHeld
+	}
+	return nil
+}
+
+func (b storeBackend) Release(_ context.Context, key, holder string) error {
+	return b.st.ReleaseLease(key, holder)
+}
@@ -0,0 +1,58 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestClaimsStoreBackend(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { st.Close() })
+	ctx := context.Background()
+
+	cfg := config.DispatchClaims{Enabled: true, Backend: "sqlite", InstanceID: "host-a", TTL: config.Duration{Duration: time.Minute}}
+	a, err := New(cfg, st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.InstanceID = "host-b"
+	b, err := New(cfg, st)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.Claim(ctx, "cx-1"); err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	if err := b.Claim(ctx, "cx-1"); !errors.Is(err, ErrHeld) {
+		t.Fatalf("second instance claim = %v, want ErrHeld", err)
+	}
+	if err := a.Release(ctx, "cx-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Claim(ctx, "cx-1"); err != nil {
+		t.Fatalf("claim after release: %v", err)
+	}
+}
+
+func TestClaimsDisabled(t *testing.T) {
+	c, err := New(config.DispatchClaims{}, nil)
+	if err != nil || c != nil {
+		t.Fatalf("New = %v, %v; want nil claims", c, err)
+	}
+	if err := c.Claim(context.Background(), "cx-1"); err != nil {
+		t.Fatalf("nil claims claim: %v", err)
+	}
+	if err := c.Release(context.Background(), "cx-1"); err != nil {
+		t.Fatalf("nil claims release: %v", err)
+	}
+}
@@ -0,0 +1,312 @@
+package matrix
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CommandPrefix starts a registry command in a project room, e.g. "/cortex status".
+const CommandPrefix = "/cortex"
+
+// CommandRequest is one invocation of a /cortex command.
+type CommandRequest struct {
+	Message InboundMessage
+	Args    []string // words after the command name
+}
+
+// CommandHandler runs a command and returns the reply posted to the room.
+type CommandHandler func(ctx context.Context, req CommandRequest) (string, error)
+
+// Command is a /cortex subcommand.
+type Command struct {
+	Name    string
+	Args    string // argument synopsis shown in help, e.g. "<dispatch-id>"
+	Help    string
+	MinArgs int
+	MaxArgs int // -1 for no limit
+	Run     CommandHandler
+}
+
+func (c Command) usage() string {
+	if c.Args == "" {
+		return CommandPrefix + " " + c.Name
+	}
+	return CommandPrefix + " " + c.Name + " " + c.Args
+}
+
+// CommandRegistry holds the /cortex commands a poller answers.
+type CommandRegistry struct {
+	mu       sync.RWMutex
+	commands map[string]Command
+}
+
+// NewCommandRegistry returns an empty registry.
+func NewCommandRegistry() *CommandRegistry {
+	return &CommandRegistry{commands: make(map[string]Command)}
+}
+
+// Register adds a command. Names are case-insensitive and must be unique.
+func (r *CommandRegistry) Register(cmd Command) error {
+	cmd.Name = strings.ToLower(strings.TrimSpace(cmd.Name))
+	if cmd.Name == "" || strings.ContainsAny(cmd.Name, " \t\n") {
+		return fmt.Errorf("matrix: invalid command name %q", cmd.Name)
+	}
+	if cmd.Run == nil {
+		return fmt.Errorf("matrix: command %q has no handler", cmd.Name)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.commands[cmd.Name]; exists {
+		return fmt.Errorf("matrix: command %q already registered", cmd.Name)
+	}
+	r.commands[cmd.Name] = cmd
+	return nil
+}
+
+// Lookup returns the named command.
+func (r *CommandRegistry) Lookup(name string) (Command, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	cmd, ok := r.commands[strings.ToLower(strings.TrimSpace(name))]
+	return cmd, ok
+}
+
+// Commands returns the registered commands sorted by name.
+func (r *CommandRegistry) Commands() []Command {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]Command, 0, len(r.commands))
+	for _, cmd := range r.commands {
+		out = append(out, cmd)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// BeadDispatcher starts work on a bead for /cortex dispatch and returns an
+// identifier for the started run.
+type BeadDispatcher interface {
+	DispatchBead(ctx context.Context, project, beadID string) (string, error)
+}
+
+// ProjectPauser holds and releases a project's new dispatches for /cortex
+// pause and resume. The store implements it.
+type ProjectPauser interface {
+	PauseProject(project, pausedBy string) error
+	ResumeProject(project string) (bool, error)
+}
+
+// Approver answers approval requests for /cortex approve and deny. room is
+// where the answer was posted; a request only takes answers from the room
+// its prompt went to. It returns a short description of what was answered.
+type Approver interface {
+	DecideApproval(ctx context.Context, id int64, approve bool, decidedBy, room string) (string, error)
+}
+
+// RegisterCommand adds a /cortex command to the poller.
+func (p *Poller) RegisterCommand(cmd Command) error {
+	return p.commands.Register(cmd)
+}
+
+func (p *Poller) registerBuiltinCommands() {
+	builtins := []Command{
+		{
+			Name: "help", Help: "Lists the commands you may run.", MaxArgs: 0,
+			Run: func(_ context.Context, req CommandRequest) (string, error) {
+				return p.commandHelp(req.Message.Sender), nil
+			},
+		},
+		{
+			Name: "status", Help: "Shows the room's project: running beads and recent completions.", MaxArgs: 0,
+			Run: func(_ context.Context, req CommandRequest) (string, error) {
+				return p.handleStatusCommand(req.Message.Project)
+			},
+		},
+		{
+			Name: "cancel", Args: "<dispatch-id>", Help: "Cancels a running dispatch.", MinArgs: 1, MaxArgs: 1,
+			Run: func(ctx context.Context, req CommandRequest) (string, error) {
+				id, err := strconv.ParseInt(req.Args[0], 10, 64)
+				if err != nil || id <= 0 {
+					return "", fmt.Errorf("dispatch id must be a positive integer")
+				}
+				return p.handleCancelCommand(ctx, req.Message.Project, id)
+			},
+		},
+		{
+			Name: "dispatch", Args: "<bead-id>", Help: "Dispatches a bead of the room's project now.", MinArgs: 1, MaxArgs: 1,
+			Run: func(ctx context.Context, req CommandRequest) (string, error) {
+				if p.beadDispatcher == nil {
+					return "", fmt.Errorf("dispatch unavailable: bead dispatcher is not configured")
+				}
+				id, err := p.beadDispatcher.DispatchBead(ctx, req.Message.Project, req.Args[0])
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("Dispatched %s (%s)", req.Args[0], id), nil
+			},
+		},
+		{
+			Name: "pause", Args: "<project>", Help: "Holds new dispatches for a project.", MinArgs: 1, MaxArgs: 1,
+			Run: func(_ context.Context, req CommandRequest) (string, error) {
+				project, err := p.pauseTarget(req.Message.Project, req.Args[0])
+				if err != nil {
+					return "", err
+				}
+				if err := p.pauser.PauseProject(project, strings.TrimSpace(req.Message.Sender)); err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("Paused dispatches for %s", project), nil
+			},
+		},
+		{
+			Name: "resume", Args: "<project>", Help: "Resumes dispatches for a paused project.", MinArgs: 1, MaxArgs: 1,
+			Run: func(_ context.Context, req CommandRequest) (string, error) {
+				project, err := p.pauseTarget(req.Message.Project, req.Args[0])
+				if err != nil {
+					return "", err
+				}
+				resumed, err := p.pauser.ResumeProject(project)
+				if err != nil {
+					return "", err
+				}
+				if cfg, _ := p.projectConfig(project); cfg.Paused {
+					return fmt.Sprintf("%s is paused in config; set paused = false and reload", project), nil
+				}
+				if !resumed {
+					return fmt.Sprintf("%s was not paused", project), nil
+				}
+				return fmt.Sprintf("Resumed dispatches for %s", project), nil
+			},
+		},
+		{
+			Name: "approve", Args: "<request-id>", Help: "Approves a pending approval request.", MinArgs: 1, MaxArgs: 1,
+			Run: func(ctx context.Context, req CommandRequest) (string, error) {
+				return p.handleApprovalCommand(ctx, req, true)
+			},
+		},
+		{
+			Name: "deny", Args: "<request-id>", Help: "Denies a pending approval request.", MinArgs: 1, MaxArgs: 1,
+			Run: func(ctx context.Context, req CommandRequest) (string, error) {
+				return p.handleApprovalCommand(ctx, req, false)
+			},
+		},
+	}
+	for _, cmd := range builtins {
+		p.commands.commands[cmd.Name] = cmd
+	}
+}
+
+// pauseTarget checks that project, named in a pause or resume posted in
+// room's project, is that project: a room only holds its own dispatches.
+func (p *Poller) pauseTarget(roomProject, project string) (string, error) {
+	if p.pauser == nil {
+		return "", fmt.Errorf("pause unavailable: project pauser is not configured")
+	}
+	if _, ok := p.projectConfig(project); !ok {
+		return "", fmt.Errorf("unknown project %q", project)
+	}
+	if project != roomProject {
+		return "", fmt.Errorf("%s is not this room's project", project)
+	}
+	return project, nil
+}
+
+func (p *Poller) handleApprovalCommand(ctx context.Context, req CommandRequest, approve bool) (string, error) {
+	if p.approver == nil {
+		return "", fmt.Errorf("approvals unavailable: approver is not configured")
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(req.Args[0], "#"), 10, 64)
+	if err != nil || id <= 0 {
+		return "", fmt.Errorf("request id must be a positive integer")
+	}
+	what, err := p.approver.DecideApproval(ctx, id, approve, strings.TrimSpace(req.Message.Sender), req.Message.Room)
+	if err != nil {
+		return "", err
+	}
+	if approve {
+		return fmt.Sprintf("Approved #%d: %s", id, what), nil
+	}
+	return fmt.Sprintf("Denied #%d: %s", id, what), nil
+}
+
+func isCortexCommand(body string) bool {
+	fields := strings.Fields(body)
+	return len(fields) > 0 && strings.EqualFold(fields[0], CommandPrefix)
+}
+
+// handleCortexCommand runs a /cortex command from the registry and replies in
+// the room. A bare /cortex shows help.
+func (p *Poller) handleCortexCommand(ctx context.Context, msg InboundMessage) error {
+	args := strings.Fields(msg.Body)[1:]
+	name := "help"
+	if len(args) > 0 {
+		name, args = strings.ToLower(args[0]), args[1:]
+	}
+
+	cmd, ok := p.commands.Lookup(name)
+	if !ok {
+		return p.sendScrumResponse(ctx, msg, fmt.Sprintf("Unknown command %q.\n\n%s", name, p.commandHelp(msg.Sender)))
+	}
+	if !p.mayRunCommand(msg.Sender, cmd.Name) {
+		return p.sendScrumResponse(ctx, msg, fmt.Sprintf("You do not have permission to run %s %s.", CommandPrefix, cmd.Name))
+	}
+	if len(args) < cmd.MinArgs || (cmd.MaxArgs >= 0 && len(args) > cmd.MaxArgs) {
+		return p.sendScrumResponse(ctx, msg, "Usage: "+cmd.usage())
+	}
+
+	response, err := cmd.Run(ctx, CommandRequest{Message: msg, Args: args})
+	if err != nil {
+		return p.sendScrumResponse(ctx, msg, fmt.Sprintf("Command failed: %s", err.Error()))
+	}
+	return p.sendScrumResponse(ctx, msg, response)
+}
+
+// mayRunCommand applies the command's ACL when it has one and the global
+// command sender list otherwise.
+func (p *Poller) mayRunCommand(sender, command string) bool {
+	users, ok := p.commandACL[command]
+	if !ok {
+		return p.isAllowedCommandSender(sender)
+	}
+	if _, anyone := users["*"]; anyone {
+		return true
+	}
+	_, allowed := users[strings.ToLower(strings.TrimSpace(sender))]
+	return allowed
+}
+
+// commandHelp lists the commands the sender may run.
+func (p *Poller) commandHelp(sender string) string {
+	lines := []string{"Commands:"}
+	for _, cmd := range p.commands.Commands() {
+		if !p.mayRunCommand(sender, cmd.Name) {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("- %s\n  - %s", cmd.usage(), cmd.Help))
+	}
+	if len(lines) == 1 {
+		return "You do not have permission to run any commands."
+	}
+	return strings.Join(lines, "\n")
+}
+
+func normalizeCommandACL(raw map[string][]string) map[string]map[string]struct{} {
+	if len(raw) == 0 {
+		return nil
+	}
+	acl := make(map[string]map[string]struct{}, len(raw))
+	for command, users := range raw {
+		allowed := make(map[string]struct{}, len(users))
+		for _, user := range users {
+			if user = strings.ToLower(strings.TrimSpace(user)); user != "" {
+				allowed[user] = struct{}{}
+			}
+		}
+		acl[strings.ToLower(strings.TrimSpace(command))] = allowed
+	}
+	return acl
+}
@@ -0,0 +1,297 @@
+package matrix
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+type fakeBeadDispatcher struct {
+	calls []string
+}
+
+func (d *fakeBeadDispatcher) DispatchBead(_ context.Context, project, beadID string) (string, error) {
+	d.calls = append(d.calls, project+"/"+beadID)
+	return "wf-" + beadID, nil
+}
+
+type fakePauser struct {
+	paused map[string]string
+}
+
+func (f *fakePauser) PauseProject(project, pausedBy string) error {
+	f.paused[project] = pausedBy
+	return nil
+}
+
+func (f *fakePauser) ResumeProject(project string) (bool, error) {
+	_, ok := f.paused[project]
+	delete(f.paused, project)
+	return ok, nil
+}
+
+type fakeApprover struct {
+	calls []string
+}
+
+func (f *fakeApprover) DecideApproval(_ context.Context, id int64, approve bool, decidedBy, room string) (string, error) {
+	if id != 3 {
+		return "", fmt.Errorf("approval request #%d not found", id)
+	}
+	f.calls = append(f.calls, fmt.Sprintf("%d/%t/%s/%s", id, approve, decidedBy, room))
+	return "plan for cortex-7", nil
+}
+
+// runCommands polls one room holding the given messages and returns the replies.
+func runCommands(t *testing.T, cfg PollerConfig, messages ...InboundMessage) []string {
+	t.Helper()
+	sender := &fakeSender{}
+	cfg.Enabled = true
+	cfg.Sender = sender
+	cfg.RoomToProject = map[string]string{"!room-a:matrix.org": "project-a"}
+	client := &fakeClient{responses: map[string]fakePollResponse{"!room-a:matrix.org": {messages: messages}}}
+	if err := NewPoller(cfg, client, &fakeDispatcher{}, nil).PollOnce(context.Background()); err != nil {
+		t.Fatalf("PollOnce returned error: %v", err)
+	}
+	return sender.messages
+}
+
+func cortexMessage(sender, body string) InboundMessage {
+	return InboundMessage{ID: body, Room: "!room-a:matrix.org", Sender: sender, Body: body}
+}
+
+func TestCommandRegistryRegister(t *testing.T) {
+	r := NewCommandRegistry()
+	run := func(context.Context, CommandRequest) (string, error) { return "ok", nil }
+
+	if err := r.Register(Command{Name: " Deploy ", Run: run}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if _, ok := r.Lookup("DEPLOY"); !ok {
+		t.Fatal("expected case-insensitive lookup")
+	}
+	if err := r.Register(Command{Name: "deploy", Run: run}); err == nil {
+		t.Fatal("expected duplicate name to be rejected")
+	}
+	if err := r.Register(Command{Name: "two words", Run: run}); err == nil {
+		t.Fatal("expected name with spaces to be rejected")
+	}
+	if err := r.Register(Command{Name: "nohandler"}); err == nil {
+		t.Fatal("expected command without handler to be rejected")
+	}
+}
+
+func TestCortexCommandsDispatchPauseResume(t *testing.T) {
+	beads := &fakeBeadDispatcher{}
+	pauser := &fakePauser{paused: map[string]string{}}
+	replies := runCommands(t, PollerConfig{
+		Projects:       map[string]config.Project{"project-a": {}},
+		BeadDispatcher: beads,
+		Pauser:         pauser,
+	},
+		cortexMessage("@alice:matrix.org", "/cortex dispatch cortex-7"),
+		cortexMessage("@alice:matrix.org", "/cortex pause project-a"),
+		cortexMessage("@alice:matrix.org", "/cortex resume project-a"),
+		cortexMessage("@alice:matrix.org", "/cortex resume project-a"),
+		cortexMessage("@alice:matrix.org", "/cortex pause nope"),
+	)
+
+	want := []string{
+		"Dispatched cortex-7 (wf-cortex-7)",
+		"Paused dispatches for project-a",
+		"Resumed dispatches for project-a",
+		"project-a was not paused",
+		`Command failed: unknown project "nope"`,
+	}
+	if len(replies) != len(want) {
+		t.Fatalf("replies = %q", replies)
+	}
+	for i := range want {
+		if replies[i] != want[i] {
+			t.Fatalf("reply %d = %q, want %q", i, replies[i], want[i])
+		}
+	}
+	if len(beads.calls) != 1 || beads.calls[0] != "project-a/cortex-7" {
+		t.Fatalf("dispatch calls = %v", beads.calls)
+	}
+}
+
+func TestCortexCommandsStayInTheRoomsProject(t *testing.T) {
+	canceler := &fakeCanceler{}
+	pauser := &fakePauser{paused: map[string]string{}}
+	replies := runCommands(t, PollerConfig{
+		Projects: map[string]config.Project{"project-a": {}, "project-b": {}},
+		Pauser:   pauser,
+		Canceler: canceler,
+		Store:    &fakeStore{running: []store.Dispatch{{ID: 7, Project: "project-b", Status: "running"}}},
+	},
+		cortexMessage("@alice:matrix.org", "/cortex pause project-b"),
+		cortexMessage("@alice:matrix.org", "/cortex resume project-b"),
+		cortexMessage("@alice:matrix.org", "/cortex cancel 7"),
+		cortexMessage("@alice:matrix.org", "cancel 7"),
+	)
+
+	want := []string{
+		"Command failed: project-b is not this room's project",
+		"Command failed: project-b is not this room's project",
+		"Command failed: dispatch 7 belongs to project-b, not this room's project",
+		"Command failed: dispatch 7 belongs to project-b, not this room's project",
+	}
+	if len(replies) != len(want) {
+		t.Fatalf("replies = %q", replies)
+	}
+	for i := range want {
+		if replies[i] != want[i] {
+			t.Fatalf("reply %d = %q, want %q", i, replies[i], want[i])
+		}
+	}
+	if len(pauser.paused) != 0 || len(canceler.cancelledIDs) != 0 {
+		t.Fatalf("another project was touched: paused %v, cancelled %v", pauser.paused, canceler.cancelledIDs)
+	}
+}
+
+func TestCortexCommandsApproveDeny(t *testing.T) {
+	approver := &fakeApprover{}
+	replies := runCommands(t, PollerConfig{
+		Approver:   approver,
+		CommandACL: map[string][]string{"approve": {"@alice:matrix.org"}, "deny": {"@alice:matrix.org"}},
+	},
+		cortexMessage("@alice:matrix.org", "/cortex approve #3"),
+		cortexMessage("@alice:matrix.org", "/cortex deny 3"),
+		cortexMessage("@alice:matrix.org", "/cortex approve 9"),
+		cortexMessage("@alice:matrix.org", "/cortex approve x"),
+		cortexMessage("@mallory:matrix.org", "/cortex approve 3"),
+	)
+
+	want := []string{
+		"Approved #3: plan for cortex-7",
+		"Denied #3: plan for cortex-7",
+		"Command failed: approval request #9 not found",
+		"Command failed: request id must be a positive integer",
+		"You do not have permission to run /cortex approve.",
+	}
+	if len(replies) != len(want) {
+		t.Fatalf("replies = %q", replies)
+	}
+	for i := range want {
+		if replies[i] != want[i] {
+			t.Fatalf("reply %d = %q, want %q", i, replies[i], want[i])
+		}
+	}
+	wantCalls := []string{"3/true/@alice:matrix.org/!room-a:matrix.org", "3/false/@alice:matrix.org/!room-a:matrix.org"}
+	if strings.Join(approver.calls, ",") != strings.Join(wantCalls, ",") {
+		t.Fatalf("approver calls = %v", approver.calls)
+	}
+}
+
+func TestCortexCommandResumeConfigPausedProject(t *testing.T) {
+	replies := runCommands(t, PollerConfig{
+		Projects: map[string]config.Project{"project-a": {Paused: true}},
+		Pauser:   &fakePauser{paused: map[string]string{"project-a": "@alice:matrix.org"}},
+	},
+		cortexMessage("@alice:matrix.org", "/cortex resume project-a"),
+	)
+	if len(replies) != 1 || replies[0] != "project-a is paused in config; set paused = false and reload" {
+		t.Fatalf("replies = %q", replies)
+	}
+}
+
+func TestCortexCommandUsageAndUnknown(t *testing.T) {
+	replies := runCommands(t, PollerConfig{},
+		cortexMessage("@alice:matrix.org", "/cortex cancel"),
+		cortexMessage("@alice:matrix.org", "/cortex cancel abc"),
+		cortexMessage("@alice:matrix.org", "/cortex frobnicate"),
+		cortexMessage("@alice:matrix.org", "/cortex dispatch cortex-1"),
+	)
+	if len(replies) != 4 {
+		t.Fatalf("replies = %q", replies)
+	}
+	if replies[0] != "Usage: /cortex cancel <dispatch-id>" {
+		t.Fatalf("unexpected usage reply: %q", replies[0])
+	}
+	if !strings.Contains(replies[1], "dispatch id must be a positive integer") {
+		t.Fatalf("unexpected bad id reply: %q", replies[1])
+	}
+	if !strings.HasPrefix(replies[2], `Unknown command "frobnicate"`) || !strings.Contains(replies[2], "/cortex pause <project>") {
+		t.Fatalf("unexpected unknown command reply: %q", replies[2])
+	}
+	if !strings.Contains(replies[3], "bead dispatcher is not configured") {
+		t.Fatalf("unexpected unconfigured dispatch reply: %q", replies[3])
+	}
+}
+
+func TestCortexCommandACL(t *testing.T) {
+	cfg := PollerConfig{
+		Projects:       map[string]config.Project{"project-a": {}},
+		Pauser:         &fakePauser{paused: map[string]string{}},
+		Store:          &fakeStore{},
+		CommandSenders: []string{"@alice:matrix.org", "@bob:matrix.org"},
+		CommandACL: map[string][]string{
+			"pause":  {"@Alice:matrix.org"},
+			"status": {"*"},
+		},
+	}
+	replies := runCommands(t, cfg,
+		cortexMessage("@bob:matrix.org", "/cortex pause project-a"),
+		cortexMessage("@alice:matrix.org", "/cortex pause project-a"),
+		cortexMessage("@bob:matrix.org", "/cortex"),
+		cortexMessage("@eve:matrix.org", "/cortex help"),
+		cortexMessage("@eve:matrix.org", "status"),
+	)
+	if len(replies) != 5 {
+		t.Fatalf("replies = %q", replies)
+	}
+	if replies[0] != "You do not have permission to run /cortex pause." {
+		t.Fatalf("expected bob to be denied pause, got %q", replies[0])
+	}
+	if replies[1] != "Paused dispatches for project-a" {
+		t.Fatalf("expected alice to pause, got %q", replies[1])
+	}
+	// Help only lists what the sender may run.
+	if strings.Contains(replies[2], "/cortex pause") || !strings.Contains(replies[2], "/cortex resume <project>") {
+		t.Fatalf("unexpected help for bob: %q", replies[2])
+	}
+	if !strings.Contains(replies[3], "You do not have permission to run /cortex help.") {
+		t.Fatalf("expected eve to be denied help, got %q", replies[3])
+	}
+	// The ACL also covers the bare command of the same name.
+	if !strings.Contains(replies[4], "Project: project-a") {
+		t.Fatalf("expected eve to run status, got %q", replies[4])
+	}
+}
+
+func TestPollerRegisterCommand(t *testing.T) {
+	sender := &fakeSender{}
+	client := &fakeClient{responses: map[string]fakePollResponse{
+		"!room-a:matrix.org": {messages: []InboundMessage{cortexMessage("@alice:matrix.org", "/cortex echo hello world")}},
+	}}
+	poller := NewPoller(PollerConfig{
+		Enabled:       true,
+		RoomToProject: map[string]string{"!room-a:matrix.org": "project-a"},
+		Sender:        sender,
+	}, client, &fakeDispatcher{}, nil)
+
+	err := poller.RegisterCommand(Command{
+		Name: "echo", Args: "<text>", Help: "Repeats text.", MinArgs: 1, MaxArgs: -1,
+		Run: func(_ context.Context, req CommandRequest) (string, error) {
+			return req.Message.Project + ": " + strings.Join(req.Args, " "), nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if err := poller.RegisterCommand(Command{Name: "status", Run: func(context.Context, CommandRequest) (string, error) { return "", nil }}); err == nil {
+		t.Fatal("expected builtin name clash to be rejected")
+	}
+
+	if err := poller.PollOnce(context.Background()); err != nil {
+		t.Fatalf("PollOnce returned error: %v", err)
+	}
+	if len(sender.messages) != 1 || sender.messages[0] != "project-a: hello world" {
+		t.Fatalf("unexpected replies: %q", sender.messages)
+	}
+}
@@ -0,0 +1,133 @@
+package matrix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// HTTPClient reads room messages through the Matrix client API. Pointed at an
+// E2E-capable proxy such as pantalaimon it also reads encrypted rooms, which
+// the OpenClaw reader cannot: the proxy decrypts events before returning them.
+type HTTPClient struct {
+	client     *http.Client
+	account    string
+	configPath string
+	homeserver string
+	readLimit  int
+}
+
+// NewE2EClient constructs a reader that polls through an E2E-capable Matrix
+// proxy using the account's OpenClaw credentials.
+func NewE2EClient(client *http.Client, account, proxyURL string, readLimit int) *HTTPClient {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if readLimit <= 0 {
+		readLimit = defaultReadLimit
+	}
+	return &HTTPClient{
+		client:     client,
+		account:    strings.TrimSpace(account),
+		homeserver: strings.TrimSpace(proxyURL),
+		readLimit:  readLimit,
+	}
+}
+
+// maxReadPages bounds how far back ReadMessages pages for a cursor it does
+// not find, such as one whose event was purged.
+const maxReadPages = 50
+
+// ReadMessages returns the room's messages newer than the after event id,
+// oldest first, and the newest event id as the next cursor. When more than
+// a page arrived since after, it follows the pagination token back until it
+// reaches after. Events the proxy could not decrypt stay m.room.encrypted
+// and are skipped.
+func (c *HTTPClient) ReadMessages(ctx context.Context, roomID string, after string) ([]InboundMessage, string, error) {
+	roomID = strings.TrimSpace(roomID)
+	if roomID == "" {
+		return nil, "", fmt.Errorf("room id is required")
+	}
+
+	creds, err := loadMatrixCredentials(c.configPath, c.account, c.homeserver)
+	if err != nil {
+		return nil, "", err
+	}
+
+	after = strings.TrimSpace(after)
+	var messages []InboundMessage
+	next, from := "", ""
+	for pages := 0; pages < maxReadPages; pages++ {
+		chunk, end, err := c.readPage(ctx, creds, roomID, from)
+		if err != nil {
+			return nil, "", err
+		}
+		if next == "" && len(chunk) > 0 {
+			next = firstString(chunk[0], "event_id")
+		}
+		caughtUp := false
+		for _, event := range chunk { // newest first
+			if after != "" && firstString(event, "event_id") == after {
+				caughtUp = true
+				break
+			}
+			if firstString(event, "type") != "m.room.message" {
+				continue
+			}
+			msg := decodeMessageItem(event, roomID)
+			if strings.TrimSpace(msg.Body) == "" && len(msg.Attachments) == 0 {
+				continue
+			}
+			messages = append(messages, msg)
+		}
+		// Without a cursor only the latest page is read, as on a first poll.
+		if caughtUp || after == "" || len(chunk) == 0 || end == "" || end == from {
+			break
+		}
+		from = end
+	}
+	slices.Reverse(messages)
+	return messages, next, nil
+}
+
+// readPage reads one page of room events backwards from the from token, or
+// from the latest event when from is empty, and returns them newest first
+// with the token of the page before.
+func (c *HTTPClient) readPage(ctx context.Context, creds matrixCredentials, roomID, from string) ([]map[string]any, string, error) {
+	endpoint := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/messages?dir=b&limit=%d",
+		creds.homeserver, neturl.PathEscape(roomID), c.readLimit)
+	if from != "" {
+		endpoint += "&from=" + neturl.QueryEscape(from)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("build matrix request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+creds.accessToken)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("matrix read request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		out, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, "", fmt.Errorf("matrix read failed: status %d (%s)", resp.StatusCode, compactOutput(out))
+	}
+
+	var page struct {
+		Chunk []map[string]any `json:"chunk"`
+		End   string           `json:"end"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, "", fmt.Errorf("parse matrix messages: %w", err)
+	}
+	return page.Chunk, page.End, nil
+}
@@ -0,0 +1,138 @@
+package matrix
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestE2EClientReadMessagesThroughProxy(t *testing.T) {
+	var gotURL, gotAuth string
+	chunk := `{"chunk":[
+		{"type":"m.room.message","event_id":"$4","sender":"@alice:example.org","origin_server_ts":1767225600000,"content":{"msgtype":"m.text","body":"/cortex status"}},
+		{"type":"m.room.encrypted","event_id":"$3","sender":"@bob:example.org","content":{"algorithm":"m.megolm.v1.aes-sha2"}},
+		{"type":"m.room.message","event_id":"$2","sender":"@bob:example.org","content":{"msgtype":"m.text","body":"hello"}},
+		{"type":"m.room.message","event_id":"$1","sender":"@bob:example.org","content":{"msgtype":"m.text","body":"already seen"}}
+	]}`
+	client := &http.Client{
+		Transport: fakeRoundTripper(func(req *http.Request) (*http.Response, error) {
+			gotURL = req.URL.String()
+			gotAuth = req.Header.Get("Authorization")
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(chunk)),
+				Header:     make(http.Header),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	cfgPath := writeOpenClawMatrixConfig(t, "https://matrix.example.org", "@cortex:example.org", []openClawMatrixEntry{
+		{UserID: "@cortex:example.org", AccessToken: "proxy-token"},
+	})
+	reader := NewE2EClient(client, "", "http://localhost:8009", 10)
+	reader.configPath = cfgPath
+
+	messages, next, err := reader.ReadMessages(context.Background(), "!ops:example.org", "$1")
+	if err != nil {
+		t.Fatalf("ReadMessages returned error: %v", err)
+	}
+	if !strings.HasPrefix(gotURL, "http://localhost:8009/_matrix/client/v3/rooms/%21ops:example.org/messages?dir=b&limit=10") {
+		t.Fatalf("request went to %q, want the proxy", gotURL)
+	}
+	if gotAuth != "Bearer proxy-token" {
+		t.Fatalf("authorization header = %q", gotAuth)
+	}
+	if next != "$4" {
+		t.Fatalf("next cursor = %q, want $4", next)
+	}
+	// Oldest first, stopping at the cursor and skipping undecrypted events.
+	if len(messages) != 2 || messages[0].Body != "hello" || messages[1].Body != "/cortex status" {
+		t.Fatalf("unexpected messages: %+v", messages)
+	}
+	if messages[1].Sender != "@alice:example.org" || messages[1].Room != "!ops:example.org" || messages[1].Timestamp.Unix() != 1767225600 {
+		t.Fatalf("unexpected decoded message: %+v", messages[1])
+	}
+}
+
+func TestE2EClientReadMessagesFollowsPagination(t *testing.T) {
+	pages := map[string]string{
+		"": `{"end":"t2","chunk":[
+			{"type":"m.room.message","event_id":"$6","sender":"@a:example.org","content":{"msgtype":"m.text","body":"six"}},
+			{"type":"m.room.message","event_id":"$5","sender":"@a:example.org","content":{"msgtype":"m.text","body":"five"}}
+		]}`,
+		"t2": `{"end":"t1","chunk":[
+			{"type":"m.room.message","event_id":"$4","sender":"@a:example.org","content":{"msgtype":"m.text","body":"four"}},
+			{"type":"m.room.message","event_id":"$3","sender":"@a:example.org","content":{"msgtype":"m.text","body":"three"}}
+		]}`,
+		"t1": `{"end":"t0","chunk":[
+			{"type":"m.room.message","event_id":"$2","sender":"@a:example.org","content":{"msgtype":"m.text","body":"two"}},
+			{"type":"m.room.message","event_id":"$1","sender":"@a:example.org","content":{"msgtype":"m.text","body":"one"}}
+		]}`,
+	}
+	var froms []string
+	client := &http.Client{
+		Transport: fakeRoundTripper(func(req *http.Request) (*http.Response, error) {
+			from := req.URL.Query().Get("from")
+			froms = append(froms, from)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(pages[from])),
+				Header:     make(http.Header),
+				Request:    req,
+			}, nil
+		}),
+	}
+	cfgPath := writeOpenClawMatrixConfig(t, "https://matrix.example.org", "", []openClawMatrixEntry{
+		{UserID: "@cortex:example.org", AccessToken: "token"},
+	})
+	reader := NewE2EClient(client, "", "http://localhost:8009", 2)
+	reader.configPath = cfgPath
+
+	messages, next, err := reader.ReadMessages(context.Background(), "!ops:example.org", "$2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(froms, ",") != ",t2,t1" {
+		t.Fatalf("pages read from %q, want the latest then t2 and t1", froms)
+	}
+	if next != "$6" {
+		t.Fatalf("next cursor = %q, want $6", next)
+	}
+	var bodies []string
+	for _, m := range messages {
+		bodies = append(bodies, m.Body)
+	}
+	if strings.Join(bodies, ",") != "three,four,five,six" {
+		t.Fatalf("messages = %v, want everything after $2 oldest first", bodies)
+	}
+
+	froms = nil
+	if _, _, err := reader.ReadMessages(context.Background(), "!ops:example.org", ""); err != nil || len(froms) != 1 {
+		t.Fatalf("a first poll should read one page, read %d: %v", len(froms), err)
+	}
+}
+
+func TestE2EClientReadMessagesError(t *testing.T) {
+	client := &http.Client{
+		Transport: fakeRoundTripper(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusForbidden,
+				Body:       io.NopCloser(strings.NewReader(`{"errcode":"M_FORBIDDEN"}`)),
+				Header:     make(http.Header),
+				Request:    req,
+			}, nil
+		}),
+	}
+	cfgPath := writeOpenClawMatrixConfig(t, "https://matrix.example.org", "", []openClawMatrixEntry{
+		{UserID: "@cortex:example.org", AccessToken: "token"},
+	})
+	reader := NewE2EClient(client, "", "http://localhost:8009", 0)
+	reader.configPath = cfgPath
+
+	if _, _, err := reader.ReadMessages(context.Background(), "!ops:example.org", ""); err == nil || !strings.Contains(err.Error(), "status 403") {
+		t.Fatalf("expected status error, got %v", err)
+	}
+}
@@ -22,6 +22,7 @@ type HTTPSender struct {
 	client     *http.Client
 	account    string
 	configPath string
+	homeserver string // overrides the configured homeserver, e.g. an E2E proxy
 }
 
 // NewHTTPSender constructs a direct Matrix sender.
@@ -35,6 +36,15 @@ func NewHTTPSender(client *http.Client, account string) *HTTPSender {
 	}
 }
 
+// NewE2ESender constructs a sender that posts through an E2E-capable Matrix
+// proxy such as pantalaimon, which encrypts messages for encrypted rooms. The
+// account's access token must come from a login through the proxy.
+func NewE2ESender(client *http.Client, account, proxyURL string) *HTTPSender {
+	s := NewHTTPSender(client, account)
+	s.homeserver = strings.TrimSpace(proxyURL)
+	return s
+}
+
 // SendMessage sends a message directly to a Matrix room.
 func (s *HTTPSender) SendMessage(ctx context.Context, roomID, message string) error {
 	roomID = strings.TrimSpace(roomID)
@@ -93,7 +103,13 @@ type matrixCredentials struct {
 }
 
 func (s *HTTPSender) loadCredentials() (matrixCredentials, error) {
-	configPath, err := resolveOpenClawConfigPath(s.configPath)
+	return loadMatrixCredentials(s.configPath, s.account, s.homeserver)
+}
+
+// loadMatrixCredentials reads the account's access token and homeserver from
+// the OpenClaw config. A non-empty homeserver replaces the configured one.
+func loadMatrixCredentials(explicitPath, requestedAccount, homeserverOverride string) (matrixCredentials, error) {
+	configPath, err := resolveOpenClawConfigPath(explicitPath)
 	if err != nil {
 		return matrixCredentials{}, err
 	}
@@ -113,7 +129,7 @@ func (s *HTTPSender) loadCredentials() (matrixCredentials, error) {
 		return matrixCredentials{}, fmt.Errorf("openclaw matrix accounts are not configured in %s", configPath)
 	}
 
-	account, err := selectMatrixAccount(channel.Accounts, channel.UserID, s.account)
+	account, err := selectMatrixAccount(channel.Accounts, channel.UserID, requestedAccount)
 	if err != nil {
 		return matrixCredentials{}, err
 	}
@@ -123,7 +139,7 @@ func (s *HTTPSender) loadCredentials() (matrixCredentials, error) {
 		return matrixCredentials{}, fmt.Errorf("matrix account %q has no access token", strings.TrimSpace(account.UserID))
 	}
 
-	homeserver := firstNonEmpty(account.Homeserver, account.BaseURL, channel.Homeserver)
+	homeserver := firstNonEmpty(homeserverOverride, account.Homeserver, account.BaseURL, channel.Homeserver)
 	homeserver = strings.TrimSpace(homeserver)
 	if homeserver == "" {
 		return matrixCredentials{}, fmt.Errorf("matrix homeserver is not configured in %s", configPath)
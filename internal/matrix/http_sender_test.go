@@ -227,3 +227,30 @@ type fakeRoundTripper func(req *http.Request) (*http.Response, error)
 func (f fakeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 	return f(req)
 }
+
+func TestE2ESenderPostsThroughProxy(t *testing.T) {
+	var gotHost string
+	client := &http.Client{
+		Transport: fakeRoundTripper(func(req *http.Request) (*http.Response, error) {
+			gotHost = req.URL.Host
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"event_id":"$evt"}`)),
+				Header:     make(http.Header),
+				Request:    req,
+			}, nil
+		}),
+	}
+	cfgPath := writeOpenClawMatrixConfig(t, "https://matrix.example.org", "@cortex:example.org", []openClawMatrixEntry{
+		{UserID: "@cortex:example.org", AccessToken: "token", Homeserver: "https://matrix.example.org"},
+	})
+	sender := NewE2ESender(client, "", "http://localhost:8009")
+	sender.configPath = cfgPath
+
+	if err := sender.SendMessage(context.Background(), "!ops:example.org", "digest"); err != nil {
+		t.Fatalf("SendMessage returned error: %v", err)
+	}
+	if gotHost != "localhost:8009" {
+		t.Fatalf("message sent to %q, want the proxy", gotHost)
+	}
+}
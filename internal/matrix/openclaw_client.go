@@ -99,7 +99,7 @@ func decodeMessages(decoded any, defaultRoom string) []InboundMessage {
 	out := make([]InboundMessage, 0, len(items))
 	for _, item := range items {
 		msg := decodeMessageItem(item, defaultRoom)
-		if strings.TrimSpace(msg.Body) == "" {
+		if strings.TrimSpace(msg.Body) == "" && len(msg.Attachments) == 0 {
 			continue
 		}
 		out = append(out, msg)
@@ -146,10 +146,15 @@ func decodeMessageItem(item any, defaultRoom string) InboundMessage {
 	}
 
 	msg := InboundMessage{
-		ID:     firstString(obj, "id", "event_id", "message_id"),
-		Room:   firstString(obj, "room", "room_id", "target"),
-		Sender: decodeSender(obj),
-		Body:   body,
+		ID:          firstString(obj, "id", "event_id", "message_id"),
+		Room:        firstString(obj, "room", "room_id", "target"),
+		Sender:      decodeSender(obj),
+		Body:        body,
+		Attachments: decodeAttachments(obj),
+	}
+	if isMediaMessage(obj) {
+		// Media events carry the file name in body; it is not message text.
+		msg.Body = ""
 	}
 	if msg.Room == "" {
 		msg.Room = defaultRoom
@@ -162,6 +167,57 @@ func decodeMessageItem(item any, defaultRoom string) InboundMessage {
 	return msg
 }
 
+var mediaMsgTypes = map[string]bool{"m.image": true, "m.file": true, "m.video": true, "m.audio": true}
+
+func isMediaMessage(obj map[string]any) bool {
+	if mediaMsgTypes[firstString(obj, "msgtype")] {
+		return true
+	}
+	content, ok := obj["content"].(map[string]any)
+	return ok && mediaMsgTypes[firstString(content, "msgtype")]
+}
+
+// decodeAttachments extracts media from a Matrix media event (content.url) and
+// from an explicit attachments array when the reader provides one.
+func decodeAttachments(obj map[string]any) []InboundAttachment {
+	var out []InboundAttachment
+	for _, node := range []map[string]any{obj, contentMap(obj)} {
+		if node == nil || !mediaMsgTypes[firstString(node, "msgtype")] {
+			continue
+		}
+		if att := decodeAttachment(node); att.URL != "" {
+			out = append(out, att)
+		}
+	}
+	if items, ok := obj["attachments"].([]any); ok {
+		for _, item := range items {
+			if m, ok := item.(map[string]any); ok {
+				if att := decodeAttachment(m); att.URL != "" {
+					out = append(out, att)
+				}
+			}
+		}
+	}
+	return out
+}
+
+func contentMap(obj map[string]any) map[string]any {
+	content, _ := obj["content"].(map[string]any)
+	return content
+}
+
+func decodeAttachment(obj map[string]any) InboundAttachment {
+	att := InboundAttachment{
+		Name:     firstString(obj, "filename", "name", "body"),
+		URL:      firstString(obj, "url", "media_url", "download_url"),
+		MimeType: firstString(obj, "mimetype", "content_type", "mime_type"),
+	}
+	if info, ok := obj["info"].(map[string]any); ok && att.MimeType == "" {
+		att.MimeType = firstString(info, "mimetype")
+	}
+	return att
+}
+
 func decodeSender(obj map[string]any) string {
 	sender := firstString(obj, "sender", "from", "user")
 	if sender != "" {
@@ -174,7 +230,7 @@ func decodeSender(obj map[string]any) string {
 }
 
 func decodeTimestamp(obj map[string]any) time.Time {
-	for _, key := range []string{"timestamp", "ts", "created_at", "time"} {
+	for _, key := range []string{"timestamp", "ts", "origin_server_ts", "created_at", "time"} {
 		raw, ok := obj[key]
 		if !ok {
 			continue
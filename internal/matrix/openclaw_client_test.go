@@ -120,3 +120,27 @@ func TestParseReadOutputNestedMessagesAndCursorFallback(t *testing.T) {
 		t.Fatalf("unexpected parsed timestamp: %s", msgs[0].Timestamp.Format(time.RFC3339))
 	}
 }
+
+func TestParseReadOutputDecodesMediaAttachments(t *testing.T) {
+	out := []byte(`{"messages":[
+  {"id":"m1","sender":"@u:matrix.org","content":{"msgtype":"m.image","body":"crash.png","url":"mxc://matrix.org/abc","info":{"mimetype":"image/png"}}},
+  {"id":"m2","sender":"@u:matrix.org","body":"see log","attachments":[{"name":"app.log","url":"https://files.example/app.log"}]}
+]}`)
+
+	msgs, _, err := parseReadOutput(out, "!room:matrix.org")
+	if err != nil {
+		t.Fatalf("parseReadOutput returned error: %v", err)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(msgs))
+	}
+	if msgs[0].Body != "" {
+		t.Fatalf("media message body should be empty, got %q", msgs[0].Body)
+	}
+	if len(msgs[0].Attachments) != 1 || msgs[0].Attachments[0] != (InboundAttachment{Name: "crash.png", URL: "mxc://matrix.org/abc", MimeType: "image/png"}) {
+		t.Fatalf("unexpected media attachment: %+v", msgs[0].Attachments)
+	}
+	if msgs[1].Body != "see log" || len(msgs[1].Attachments) != 1 || msgs[1].Attachments[0].URL != "https://files.example/app.log" {
+		t.Fatalf("unexpected attachments array decode: %+v", msgs[1])
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"net/url"
 	"regexp"
 	"sort"
 	"strconv"
@@ -23,6 +25,10 @@ const (
 	defaultWorkDir      = "/tmp"
 	defaultThinking     = "none"
 	statusRecentWindow  = 24 * time.Hour
+
+	// Media sent shortly before a create command is attached to the new bead.
+	pendingAttachmentTTL  = 15 * time.Minute
+	maxPendingAttachments = 10
 )
 
 var createTaskPattern = regexp.MustCompile(`(?i)^create\s+task\s+"([^"]+)"\s+"([^"]+)"\s*$`)
@@ -30,6 +36,7 @@ var createTaskPattern = regexp.MustCompile(`(?i)^create\s+task\s+"([^"]+)"\s+"([
 type commandStore interface {
 	GetRunningDispatches() ([]store.Dispatch, error)
 	GetCompletedDispatchesSince(projectName, since string) ([]store.Dispatch, error)
+	GetDispatchByID(id int64) (*store.Dispatch, error)
 }
 
 type commandCanceler interface {
@@ -45,6 +52,22 @@ const (
 	scrumCommandCreate
 )
 
+// name is the command's ACL key, shared with the /cortex command of the same name.
+func (k scrumCommandKind) name() string {
+	switch k {
+	case scrumCommandStatus:
+		return "status"
+	case scrumCommandPriority:
+		return "priority"
+	case scrumCommandCancel:
+		return "cancel"
+	case scrumCommandCreate:
+		return "create"
+	default:
+		return ""
+	}
+}
+
 type scrumCommand struct {
 	kind        scrumCommandKind
 	beadID      string
@@ -62,6 +85,15 @@ type InboundMessage struct {
 	Sender    string
 	Body      string
 	Timestamp time.Time
+
+	Attachments []InboundAttachment
+}
+
+// InboundAttachment is a media file (screenshot, log) sent with a Matrix message.
+type InboundAttachment struct {
+	Name     string
+	URL      string // mxc:// or http(s)://
+	MimeType string
 }
 
 // Client reads inbound messages for a Matrix room.
@@ -81,6 +113,16 @@ type PollerConfig struct {
 	Store          commandStore
 	Canceler       commandCanceler
 	CommandSenders []string
+	// CommandACL limits each /cortex command to the listed users ("*" for
+	// anyone); commands without an entry fall back to CommandSenders.
+	CommandACL     map[string][]string
+	BeadDispatcher BeadDispatcher
+	Pauser         ProjectPauser
+	Approver       Approver
+
+	// MediaBaseURL is the homeserver base used to download mxc:// attachments.
+	MediaBaseURL string
+	HTTPClient   *http.Client
 }
 
 // Poller polls Matrix rooms and routes inbound messages to project scrum agents.
@@ -95,9 +137,20 @@ type Poller struct {
 	store          commandStore
 	canceler       commandCanceler
 	commandSenders map[string]struct{}
+	commandACL     map[string]map[string]struct{}
+	commands       *CommandRegistry
+	beadDispatcher BeadDispatcher
+	pauser         ProjectPauser
+	approver       Approver
 
 	mu      sync.Mutex
-	cursors map[string]string // room -> last cursor/message id
+	cursors map[string]string              // room -> last cursor/message id
+	pending map[string][]pendingAttachment // room|sender -> recent media awaiting a create command
+}
+
+type pendingAttachment struct {
+	InboundAttachment
+	receivedAt time.Time
 }
 
 // NewPoller constructs a Matrix poller.
@@ -114,7 +167,7 @@ func NewPoller(cfg PollerConfig, client Client, dispatcher dispatch.DispatcherIn
 	if cfg.Projects == nil {
 		cfg.Projects = make(map[string]config.Project)
 	}
-	return &Poller{
+	p := &Poller{
 		cfg:            cfg,
 		client:         client,
 		dispatcher:     dispatcher,
@@ -124,8 +177,16 @@ func NewPoller(cfg PollerConfig, client Client, dispatcher dispatch.DispatcherIn
 		store:          cfg.Store,
 		canceler:       cfg.Canceler,
 		commandSenders: normalizeCommandSenders(cfg.CommandSenders),
+		commandACL:     normalizeCommandACL(cfg.CommandACL),
+		commands:       NewCommandRegistry(),
+		beadDispatcher: cfg.BeadDispatcher,
+		pauser:         cfg.Pauser,
+		approver:       cfg.Approver,
 		cursors:        make(map[string]string),
+		pending:        make(map[string][]pendingAttachment),
 	}
+	p.registerBuiltinCommands()
+	return p
 }
 
 func cloneProjects(src map[string]config.Project) map[string]config.Project {
@@ -177,7 +238,7 @@ func BuildRoomProjectMap(cfg *config.Config) map[string]string {
 
 	for _, name := range names {
 		room := strings.TrimSpace(cfg.ResolveRoom(name))
-		if room == "" {
+		if room == "" || strings.HasPrefix(room, config.EmailRoutePrefix) {
 			continue
 		}
 		if _, exists := out[room]; exists {
@@ -275,7 +336,16 @@ func (p *Poller) PollOnce(ctx context.Context) error {
 }
 
 func (p *Poller) routeMessage(ctx context.Context, msg InboundMessage) error {
+	if isCortexCommand(msg.Body) {
+		return p.handleCortexCommand(ctx, msg)
+	}
 	command, isCommand, parseErr := parseScrumCommand(msg.Body)
+	if !isCommand && len(msg.Attachments) > 0 {
+		p.stashAttachments(msg)
+		if strings.TrimSpace(msg.Body) == "" {
+			return nil
+		}
+	}
 	if isCommand {
 		if err := p.handleScrumCommand(ctx, msg, command, parseErr); err != nil {
 			return err
@@ -358,7 +428,7 @@ func (p *Poller) isAllowedCommandSender(sender string) bool {
 }
 
 func (p *Poller) handleScrumCommand(ctx context.Context, msg InboundMessage, cmd scrumCommand, parseErr error) error {
-	if !p.isAllowedCommandSender(msg.Sender) {
+	if !p.mayRunCommand(msg.Sender, cmd.kind.name()) {
 		return p.sendScrumResponse(ctx, msg, commandPermissionDeniedMessage())
 	}
 
@@ -381,9 +451,9 @@ func (p *Poller) runScrumCommand(ctx context.Context, msg InboundMessage, cmd sc
 	case scrumCommandPriority:
 		return p.handlePriorityCommand(ctx, msg.Project, cmd.beadID, cmd.priority)
 	case scrumCommandCancel:
-		return p.handleCancelCommand(ctx, cmd.dispatchID)
+		return p.handleCancelCommand(ctx, msg.Project, cmd.dispatchID)
 	case scrumCommandCreate:
-		return p.handleCreateCommand(msg.Project, cmd.title, cmd.description)
+		return p.handleCreateCommand(ctx, msg, cmd.title, cmd.description)
 	default:
 		return "", fmt.Errorf("unsupported command type")
 	}
@@ -477,17 +547,30 @@ func (p *Poller) handlePriorityCommand(ctx context.Context, project, beadID stri
 	return fmt.Sprintf("Updated %s priority to p%d", beadID, priority), nil
 }
 
-func (p *Poller) handleCancelCommand(_ context.Context, dispatchID int64) (string, error) {
+// handleCancelCommand cancels a running dispatch of the room's project; a
+// dispatch of another project is refused.
+func (p *Poller) handleCancelCommand(_ context.Context, project string, dispatchID int64) (string, error) {
 	if p.canceler == nil {
 		return "", fmt.Errorf("cancel unavailable: command dispatcher is not configured")
 	}
+	if p.store == nil {
+		return "", fmt.Errorf("cancel unavailable: dispatch store is not configured")
+	}
+	d, err := p.store.GetDispatchByID(dispatchID)
+	if err != nil || d == nil {
+		return "", fmt.Errorf("dispatch %d not found", dispatchID)
+	}
+	if d.Project != project {
+		return "", fmt.Errorf("dispatch %d belongs to %s, not this room's project", dispatchID, d.Project)
+	}
 	if err := p.canceler.CancelDispatch(dispatchID); err != nil {
 		return "", err
 	}
 	return fmt.Sprintf("Cancelled dispatch %d", dispatchID), nil
 }
 
-func (p *Poller) handleCreateCommand(project, title, description string) (string, error) {
+func (p *Poller) handleCreateCommand(ctx context.Context, msg InboundMessage, title, description string) (string, error) {
+	project := msg.Project
 	cfg, ok := p.projectConfig(project)
 	if !ok {
 		return "", fmt.Errorf("unknown project %q", project)
@@ -502,7 +585,92 @@ func (p *Poller) handleCreateCommand(project, title, description string) (string
 		return "", err
 	}
 
-	return fmt.Sprintf("Created new task %s", id), nil
+	attachments := append(p.takePendingAttachments(msg), msg.Attachments...)
+	if len(attachments) == 0 {
+		return fmt.Sprintf("Created new task %s", id), nil
+	}
+	saved := p.saveAttachments(ctx, config.ExpandHome(beadsDir), id, attachments)
+	return fmt.Sprintf("Created new task %s with %d/%d attachment(s)", id, saved, len(attachments)), nil
+}
+
+func pendingKey(msg InboundMessage) string {
+	return msg.Room + "|" + strings.ToLower(strings.TrimSpace(msg.Sender))
+}
+
+// stashAttachments remembers media from a non-command message so a following
+// create command from the same sender in the same room can attach it.
+func (p *Poller) stashAttachments(msg InboundMessage) {
+	key := pendingKey(msg)
+	now := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	list := p.pending[key]
+	for _, att := range msg.Attachments {
+		list = append(list, pendingAttachment{InboundAttachment: att, receivedAt: now})
+	}
+	if len(list) > maxPendingAttachments {
+		list = list[len(list)-maxPendingAttachments:]
+	}
+	p.pending[key] = list
+}
+
+func (p *Poller) takePendingAttachments(msg InboundMessage) []InboundAttachment {
+	key := pendingKey(msg)
+	cutoff := time.Now().Add(-pendingAttachmentTTL)
+	p.mu.Lock()
+	list := p.pending[key]
+	delete(p.pending, key)
+	p.mu.Unlock()
+
+	var out []InboundAttachment
+	for _, att := range list {
+		if att.receivedAt.After(cutoff) {
+			out = append(out, att.InboundAttachment)
+		}
+	}
+	return out
+}
+
+// saveAttachments downloads Matrix media into the bead's attachment dir.
+// Individual failures are logged so the bead itself is never lost.
+func (p *Poller) saveAttachments(ctx context.Context, beadsDir, beadID string, attachments []InboundAttachment) int {
+	saved := 0
+	for _, att := range attachments {
+		mediaURL, err := p.resolveMediaURL(att.URL)
+		if err == nil {
+			_, err = beads.FetchAttachment(ctx, p.cfg.HTTPClient, beadsDir, beadID, att.Name, mediaURL, p.mediaHosts())
+		}
+		if err != nil {
+			p.logger.Warn("failed to store matrix attachment", "bead", beadID, "name", att.Name, "error", err)
+			continue
+		}
+		saved++
+	}
+	return saved
+}
+
+// mediaHosts lists the hosts attachment downloads may reach even when they
+// resolve to internal addresses: only the configured homeserver.
+func (p *Poller) mediaHosts() []string {
+	u, err := url.Parse(strings.TrimSpace(p.cfg.MediaBaseURL))
+	if err != nil || u.Hostname() == "" {
+		return nil
+	}
+	return []string{u.Hostname()}
+}
+
+// resolveMediaURL converts mxc://server/media-id into a homeserver download URL.
+func (p *Poller) resolveMediaURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	rest, isMXC := strings.CutPrefix(raw, "mxc://")
+	if !isMXC {
+		return raw, nil
+	}
+	base := strings.TrimRight(strings.TrimSpace(p.cfg.MediaBaseURL), "/")
+	if base == "" {
+		return "", fmt.Errorf("matrix.media_base_url is required to download %s", raw)
+	}
+	return base + "/_matrix/media/v3/download/" + rest, nil
 }
 
 func parseScrumCommand(raw string) (scrumCommand, bool, error) {
@@ -586,7 +754,9 @@ Usage:
 - cancel <dispatch-id>
   - Cancels a running dispatch by id.
 - create task "<title>" "<description>"
-  - Creates a new task bead with the provided title and description.`
+  - Creates a new task bead with the provided title and description.
+
+Send /cortex help for the full command list.`
 }
 
 func (p *Poller) cursor(room string) string {
@@ -3,6 +3,9 @@ package matrix
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
@@ -99,6 +102,20 @@ func (s *fakeStore) GetCompletedDispatchesSince(_ string, _ string) ([]store.Dis
 	return s.completed, s.getCompletedErr
 }
 
+func (s *fakeStore) GetDispatchByID(id int64) (*store.Dispatch, error) {
+	if s == nil {
+		return nil, nil
+	}
+	for _, list := range [][]store.Dispatch{s.running, s.completed} {
+		for i := range list {
+			if list[i].ID == id {
+				return &list[i], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("dispatch %d not found", id)
+}
+
 type fakeCanceler struct {
 	cancelledIDs []int64
 	err          error
@@ -463,6 +480,114 @@ func TestPollOnceRoutesScrumCreateCommandToMatrixSender(t *testing.T) {
 	}
 }
 
+func TestPollOnceCreateCommandAttachesPendingMedia(t *testing.T) {
+	projectDir := t.TempDir()
+	beadsDir := filepath.Join(projectDir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0o755); err != nil {
+		t.Fatalf("mkdir beads dir: %v", err)
+	}
+
+	fakeBin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(fakeBin, "bd"), []byte("#!/bin/sh\necho cortex-bug\n"), 0o755); err != nil {
+		t.Fatalf("write fake bd: %v", err)
+	}
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	media := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_matrix/media/v3/download/matrix.org/abc" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte("png-bytes"))
+	}))
+	defer media.Close()
+
+	sender := &fakeSender{}
+	dispatcher := &fakeDispatcher{}
+	client := &fakeClient{
+		responses: map[string]fakePollResponse{
+			"!room-a:matrix.org": {
+				messages: []InboundMessage{
+					{ID: "1", Room: "!room-a:matrix.org", Sender: "@alice:matrix.org", Attachments: []InboundAttachment{{Name: "crash.png", URL: "mxc://matrix.org/abc"}}},
+					{ID: "2", Room: "!room-a:matrix.org", Sender: "@alice:matrix.org", Body: "create task \"Login crash\" \"See screenshot\""},
+				},
+			},
+		},
+	}
+
+	poller := NewPoller(PollerConfig{
+		Enabled:       true,
+		BotUser:       "@cortex-bot:matrix.org",
+		RoomToProject: map[string]string{"!room-a:matrix.org": "project-a"},
+		Projects:      map[string]config.Project{"project-a": {BeadsDir: beadsDir}},
+		Sender:        sender,
+		MediaBaseURL:  media.URL,
+	}, client, dispatcher, nil)
+
+	if err := poller.PollOnce(context.Background()); err != nil {
+		t.Fatalf("PollOnce returned error: %v", err)
+	}
+	if len(dispatcher.calls) != 0 {
+		t.Fatalf("media-only message should not be routed to scrum agent, got %d dispatches", len(dispatcher.calls))
+	}
+	if len(sender.messages) != 1 || !strings.Contains(sender.messages[0], "Created new task cortex-bug with 1/1 attachment(s)") {
+		t.Fatalf("unexpected responses: %q", sender.messages)
+	}
+	data, err := os.ReadFile(filepath.Join(beadsDir, "attachments", "cortex-bug", "crash.png"))
+	if err != nil || string(data) != "png-bytes" {
+		t.Fatalf("attachment not stored: %q, %v", data, err)
+	}
+}
+
+func TestPollOnceRefusesAttachmentsFromInternalHosts(t *testing.T) {
+	beadsDir := filepath.Join(t.TempDir(), ".beads")
+	if err := os.MkdirAll(beadsDir, 0o755); err != nil {
+		t.Fatalf("mkdir beads dir: %v", err)
+	}
+	fakeBin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(fakeBin, "bd"), []byte("#!/bin/sh\necho cortex-bug\n"), 0o755); err != nil {
+		t.Fatalf("write fake bd: %v", err)
+	}
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("instance-credentials"))
+	}))
+	defer internal.Close()
+
+	sender := &fakeSender{}
+	client := &fakeClient{
+		responses: map[string]fakePollResponse{
+			"!room-a:matrix.org": {
+				messages: []InboundMessage{
+					{ID: "1", Room: "!room-a:matrix.org", Sender: "@alice:matrix.org", Attachments: []InboundAttachment{{Name: "creds.txt", URL: internal.URL + "/latest/meta-data"}}},
+					{ID: "2", Room: "!room-a:matrix.org", Sender: "@alice:matrix.org", Body: "create task \"Leak\" \"See file\""},
+				},
+			},
+		},
+	}
+
+	poller := NewPoller(PollerConfig{
+		Enabled:       true,
+		BotUser:       "@cortex-bot:matrix.org",
+		RoomToProject: map[string]string{"!room-a:matrix.org": "project-a"},
+		Projects:      map[string]config.Project{"project-a": {BeadsDir: beadsDir}},
+		Sender:        sender,
+		MediaBaseURL:  "https://matrix.example.org",
+	}, client, &fakeDispatcher{}, nil)
+
+	if err := poller.PollOnce(context.Background()); err != nil {
+		t.Fatalf("PollOnce returned error: %v", err)
+	}
+	if len(sender.messages) != 1 || !strings.Contains(sender.messages[0], "with 0/1 attachment(s)") {
+		t.Fatalf("unexpected responses: %q", sender.messages)
+	}
+	if _, err := os.Stat(filepath.Join(beadsDir, "attachments", "cortex-bug", "creds.txt")); !os.IsNotExist(err) {
+		t.Fatalf("internal attachment should not be stored, stat err = %v", err)
+	}
+}
+
 func TestPollOnceRoutesScrumCancelCommandToMatrixSender(t *testing.T) {
 	canceler := &fakeCanceler{}
 	sender := &fakeSender{}
@@ -483,6 +608,7 @@ func TestPollOnceRoutesScrumCancelCommandToMatrixSender(t *testing.T) {
 			"!room-a:matrix.org": "project-a",
 		},
 		Canceler: canceler,
+		Store:    &fakeStore{running: []store.Dispatch{{ID: 99, Project: "project-a", Status: "running"}}},
 		Sender:   sender,
 	}, client, &fakeDispatcher{}, nil)
 
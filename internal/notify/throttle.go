@@ -0,0 +1,187 @@
+// Package notify throttles reporter messages so an incident does not flood a
+// room: identical messages within a window are dropped, and a room that has
+// reached its rate limit gets the rest as one digest per interval.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+// maxDigestMessages caps how many held messages a digest lists in full.
+const maxDigestMessages = 20
+
+// Sender is the reporter interface shared with the Matrix, email and webhook
+// senders.
+type Sender interface {
+	SendMessage(ctx context.Context, room, message string) error
+}
+
+// Throttle is a Sender that dedupes and rate-limits messages per room before
+// passing them on. Held messages are sent by Run.
+type Throttle struct {
+	cfg  config.ReporterThrottle
+	next Sender
+	now  func() time.Time
+
+	mu         sync.Mutex
+	recent     map[string]time.Time   // room and message -> when last accepted
+	sent       map[string][]time.Time // room -> sends within the rate period
+	held       map[string][]string    // room -> messages for the next digest, oldest first
+	suppressed map[string]int         // room -> duplicates dropped since the last digest
+}
+
+// NewThrottle wraps next. With throttling disabled every message goes
+// straight through.
+func NewThrottle(cfg config.ReporterThrottle, next Sender) *Throttle {
+	return &Throttle{
+		cfg:        cfg,
+		next:       next,
+		now:        time.Now,
+		recent:     make(map[string]time.Time),
+		sent:       make(map[string][]time.Time),
+		held:       make(map[string][]string),
+		suppressed: make(map[string]int),
+	}
+}
+
+// SendMessage sends message to room unless the room got the same message
+// within the dedup window, in which case it is dropped, or the room reached
+// its rate limit, in which case it is held for the next digest.
+func (t *Throttle) SendMessage(ctx context.Context, room, message string) error {
+	if !t.cfg.Enabled {
+		return t.next.SendMessage(ctx, room, message)
+	}
+	message = strings.TrimSpace(message)
+	now := t.now()
+
+	t.mu.Lock()
+	key := room + "\x00" + message
+	if last, ok := t.recent[key]; ok && now.Sub(last) < t.cfg.DedupWindow.Duration {
+		t.suppressed[room]++
+		t.mu.Unlock()
+		return nil
+	}
+	t.recent[key] = now
+	if !t.reserve(room, now) {
+		t.held[room] = append(t.held[room], message)
+		t.mu.Unlock()
+		return nil
+	}
+	t.mu.Unlock()
+	return t.next.SendMessage(ctx, room, message)
+}
+
+// reserve records a send to room and reports whether the room's rate limit
+// allowed it. A zero limit never holds messages. Callers hold t.mu.
+func (t *Throttle) reserve(room string, now time.Time) bool {
+	sent := t.sent[room]
+	for len(sent) > 0 && now.Sub(sent[0]) >= t.cfg.RatePeriod.Duration {
+		sent = sent[1:]
+	}
+	if t.cfg.RoomLimit > 0 && len(sent) >= t.cfg.RoomLimit {
+		t.sent[room] = sent
+		return false
+	}
+	t.sent[room] = append(sent, now)
+	return true
+}
+
+// Run sends held messages as digests every digest interval until ctx is
+// cancelled, then sends what is left. It returns at once when throttling is
+// disabled.
+func (t *Throttle) Run(ctx context.Context, logger *slog.Logger) {
+	if !t.cfg.Enabled {
+		return
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	ticker := time.NewTicker(t.cfg.DigestInterval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+			if err := t.Flush(flushCtx); err != nil {
+				logger.Warn("notification digest flush failed at shutdown", "error", err)
+			}
+			cancel()
+			return
+		case <-ticker.C:
+			if err := t.Flush(ctx); err != nil {
+				logger.Warn("notification digest flush failed", "error", err)
+			}
+		}
+	}
+}
+
+// Flush sends one digest per room with the messages held so far. A room
+// whose digest fails keeps its messages for the next flush.
+func (t *Throttle) Flush(ctx context.Context) error {
+	now := t.now()
+	t.mu.Lock()
+	held, suppressed := t.held, t.suppressed
+	t.held, t.suppressed = make(map[string][]string), make(map[string]int)
+	for key, last := range t.recent {
+		if now.Sub(last) >= t.cfg.DedupWindow.Duration {
+			delete(t.recent, key)
+		}
+	}
+	for room, sent := range t.sent {
+		if len(sent) == 0 || now.Sub(sent[len(sent)-1]) >= t.cfg.RatePeriod.Duration {
+			delete(t.sent, room)
+		}
+	}
+	t.mu.Unlock()
+
+	rooms := make([]string, 0, len(held))
+	for room := range held {
+		rooms = append(rooms, room)
+	}
+	sort.Strings(rooms)
+
+	var errs []error
+	for _, room := range rooms {
+		messages := held[room]
+		if err := t.next.SendMessage(ctx, room, digest(messages, suppressed[room])); err != nil {
+			errs = append(errs, fmt.Errorf("digest for %s: %w", room, err))
+			t.mu.Lock()
+			t.held[room] = append(messages, t.held[room]...)
+			t.suppressed[room] += suppressed[room]
+			t.mu.Unlock()
+			continue
+		}
+		t.mu.Lock()
+		t.sent[room] = append(t.sent[room], now)
+		t.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// digest renders held messages, and the duplicates dropped alongside them,
+// as one message.
+func digest(messages []string, suppressed int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d notification(s) held back by the room rate limit", len(messages))
+	if suppressed > 0 {
+		fmt.Fprintf(&b, "; %d duplicate(s) dropped", suppressed)
+	}
+	b.WriteString(":")
+	for i, msg := range messages {
+		if i == maxDigestMessages {
+			fmt.Fprintf(&b, "\n\n…and %d more", len(messages)-i)
+			break
+		}
+		b.WriteString("\n\n" + msg)
+	}
+	return b.String()
+}
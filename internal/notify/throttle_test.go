@@ -0,0 +1,107 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+type recorder struct {
+	sent []string // "room: message"
+	err  error
+}
+
+func (r *recorder) SendMessage(_ context.Context, room, message string) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.sent = append(r.sent, room+": "+message)
+	return nil
+}
+
+func testThrottle(next Sender) (*Throttle, *time.Time) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	t := NewThrottle(config.ReporterThrottle{
+		Enabled:        true,
+		DedupWindow:    config.Duration{Duration: 10 * time.Minute},
+		RoomLimit:      2,
+		RatePeriod:     config.Duration{Duration: time.Minute},
+		DigestInterval: config.Duration{Duration: 5 * time.Minute},
+	}, next)
+	t.now = func() time.Time { return now }
+	return t, &now
+}
+
+func TestThrottleDedupesAndRateLimits(t *testing.T) {
+	rec := &recorder{}
+	th, now := testThrottle(rec)
+	ctx := context.Background()
+
+	for _, msg := range []string{"merge failed: a", "merge failed: a", "merge failed: b", "merge failed: c", "merge failed: d"} {
+		if err := th.SendMessage(ctx, "!ops", msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	th.SendMessage(ctx, "!dev", "merge failed: a")
+
+	want := []string{"!ops: merge failed: a", "!ops: merge failed: b", "!dev: merge failed: a"}
+	if strings.Join(rec.sent, "|") != strings.Join(want, "|") {
+		t.Fatalf("sent %q, want %q", rec.sent, want)
+	}
+
+	*now = now.Add(5 * time.Minute)
+	if err := th.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.sent) != 4 {
+		t.Fatalf("expected one digest, sent %q", rec.sent)
+	}
+	digest := rec.sent[3]
+	if !strings.HasPrefix(digest, "!ops: 2 notification(s) held back by the room rate limit; 1 duplicate(s) dropped:") ||
+		!strings.Contains(digest, "merge failed: c") || !strings.Contains(digest, "merge failed: d") {
+		t.Fatalf("digest = %q", digest)
+	}
+
+	// Past the dedup window the same message goes out again.
+	*now = now.Add(10 * time.Minute)
+	th.SendMessage(ctx, "!ops", "merge failed: a")
+	if rec.sent[len(rec.sent)-1] != "!ops: merge failed: a" {
+		t.Fatalf("repeat after window not sent: %q", rec.sent)
+	}
+}
+
+func TestThrottleKeepsDigestWhenSendFails(t *testing.T) {
+	rec := &recorder{}
+	th, _ := testThrottle(rec)
+	ctx := context.Background()
+	for _, msg := range []string{"a", "b", "c"} {
+		th.SendMessage(ctx, "!ops", msg)
+	}
+
+	rec.err = errors.New("matrix down")
+	if err := th.Flush(ctx); err == nil {
+		t.Fatal("expected flush error")
+	}
+	rec.err = nil
+	if err := th.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.sent) != 3 || !strings.HasSuffix(rec.sent[2], "limit:\n\nc") {
+		t.Fatalf("sent %q", rec.sent)
+	}
+}
+
+func TestThrottleDisabledPassesThrough(t *testing.T) {
+	rec := &recorder{}
+	th := NewThrottle(config.ReporterThrottle{}, rec)
+	for range 3 {
+		th.SendMessage(context.Background(), "!ops", "same")
+	}
+	if len(rec.sent) != 3 {
+		t.Fatalf("sent %q", rec.sent)
+	}
+}
@@ -0,0 +1,161 @@
+// Package oncall finds who is on call, from the static roster or a
+// PagerDuty schedule, and pages them through the PagerDuty Events API.
+package oncall
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+const (
+	defaultAPIURL    = "https://api.pagerduty.com"
+	defaultEventsURL = "https://events.pagerduty.com/v2/enqueue"
+)
+
+// User is whoever is on call.
+type User struct {
+	Mention string // Matrix user ID, or the PagerDuty name when unmapped
+	Source  string // "roster" or "pagerduty"
+}
+
+// Roster answers who is on call and pages them.
+type Roster struct {
+	cfg       config.OnCall
+	client    *http.Client
+	apiURL    string
+	eventsURL string
+	now       func() time.Time
+}
+
+// New returns the roster cfg describes, or nil when it configures neither
+// shifts nor a PagerDuty schedule or integration. A nil roster has nobody
+// on call and never pages.
+func New(cfg config.OnCall) *Roster {
+	if len(cfg.Shifts) == 0 && cfg.PagerDuty.ScheduleID == "" && cfg.PagerDuty.RoutingKey == "" {
+		return nil
+	}
+	return &Roster{
+		cfg:       cfg,
+		client:    &http.Client{Timeout: 10 * time.Second},
+		apiURL:    defaultAPIURL,
+		eventsURL: defaultEventsURL,
+		now:       time.Now,
+	}
+}
+
+// Current returns who is on call now: the open roster shift's user, else
+// the PagerDuty schedule's. ok is false when nobody is.
+func (r *Roster) Current(ctx context.Context) (User, bool, error) {
+	if r == nil {
+		return User{}, false, nil
+	}
+	if user, ok := r.cfg.OnCallAt(r.now()); ok {
+		return User{Mention: user, Source: "roster"}, true, nil
+	}
+	if r.cfg.PagerDuty.ScheduleID == "" {
+		return User{}, false, nil
+	}
+	return r.pagerDutyOnCall(ctx)
+}
+
+// pagerDutyOnCall reads the first on-call user of the schedule.
+func (r *Roster) pagerDutyOnCall(ctx context.Context) (User, bool, error) {
+	pd := r.cfg.PagerDuty
+	q := url.Values{}
+	q.Set("schedule_ids[]", pd.ScheduleID)
+	q.Set("include[]", "users")
+	q.Set("earliest", "true")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.apiURL+"/oncalls?"+q.Encode(), nil)
+	if err != nil {
+		return User{}, false, err
+	}
+	req.Header.Set("Authorization", "Token token="+pd.APIToken)
+	req.Header.Set("Accept", "application/vnd.pagerduty+json;version=2")
+
+	var body struct {
+		OnCalls []struct {
+			User struct {
+				Name    string `json:"name"`
+				Summary string `json:"summary"`
+				Email   string `json:"email"`
+			} `json:"user"`
+		} `json:"oncalls"`
+	}
+	if err := r.do(req, &body); err != nil {
+		return User{}, false, fmt.Errorf("pagerduty oncalls: %w", err)
+	}
+	if len(body.OnCalls) == 0 {
+		return User{}, false, nil
+	}
+	u := body.OnCalls[0].User
+	for email, mention := range pd.Users {
+		if u.Email != "" && strings.EqualFold(email, u.Email) {
+			return User{Mention: mention, Source: "pagerduty"}, true, nil
+		}
+	}
+	name := u.Name
+	if name == "" {
+		name = u.Summary
+	}
+	return User{Mention: name, Source: "pagerduty"}, name != "", nil
+}
+
+// Page triggers a PagerDuty incident, which PagerDuty routes to whoever is
+// on call. Events with the same dedupKey collapse into one incident. It
+// does nothing without a routing key.
+func (r *Roster) Page(ctx context.Context, summary, dedupKey string, details map[string]string) error {
+	if r == nil || r.cfg.PagerDuty.RoutingKey == "" {
+		return nil
+	}
+	if len(summary) > 1024 {
+		summary = summary[:1024]
+	}
+	payload, err := json.Marshal(map[string]any{
+		"routing_key":  r.cfg.PagerDuty.RoutingKey,
+		"event_action": "trigger",
+		"dedup_key":    dedupKey,
+		"payload": map[string]any{
+			"summary":        summary,
+			"source":         "cortex",
+			"severity":       "critical",
+			"custom_details": details,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.eventsURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if err := r.do(req, nil); err != nil {
+		return fmt.Errorf("pagerduty page: %w", err)
+	}
+	return nil
+}
+
+func (r *Roster) do(req *http.Request, out any) error {
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,81 @@
+package oncall
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+func TestCurrentFromRoster(t *testing.T) {
+	r := New(config.OnCall{Shifts: []config.OnCallShift{
+		{User: "@alice:example.org", Days: []string{"Monday"}, Hours: "09:00-17:00"},
+		{User: "@bob:example.org", Hours: "17:00-09:00"},
+	}})
+	for _, tc := range []struct {
+		at   time.Time
+		want string
+	}{
+		{time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC), "@alice:example.org"}, // Monday
+		{time.Date(2026, 1, 5, 3, 0, 0, 0, time.UTC), "@bob:example.org"},
+		{time.Date(2026, 1, 6, 10, 0, 0, 0, time.UTC), ""}, // Tuesday, nobody
+	} {
+		r.now = func() time.Time { return tc.at }
+		user, ok, err := r.Current(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user.Mention != tc.want || ok != (tc.want != "") {
+			t.Errorf("%s: on call %+v (%v), want %q", tc.at, user, ok, tc.want)
+		}
+	}
+
+	if _, ok, _ := (*Roster)(nil).Current(context.Background()); ok {
+		t.Fatal("nil roster has someone on call")
+	}
+	if New(config.OnCall{}) != nil {
+		t.Fatal("empty config built a roster")
+	}
+}
+
+func TestPagerDuty(t *testing.T) {
+	var page map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oncalls":
+			if r.Header.Get("Authorization") != "Token token=pd-token" || r.URL.Query().Get("schedule_ids[]") != "PSCHED" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			io.WriteString(w, `{"oncalls":[{"user":{"name":"Carol","email":"Carol@Example.org"}}]}`)
+		case "/enqueue":
+			json.NewDecoder(r.Body).Decode(&page)
+			w.WriteHeader(http.StatusAccepted)
+		}
+	}))
+	defer srv.Close()
+
+	r := New(config.OnCall{PagerDuty: config.OnCallPagerDuty{
+		APIToken:   "pd-token",
+		ScheduleID: "PSCHED",
+		RoutingKey: "rk",
+		Users:      map[string]string{"carol@example.org": "@carol:example.org"},
+	}})
+	r.apiURL, r.eventsURL = srv.URL, srv.URL+"/enqueue"
+
+	user, ok, err := r.Current(context.Background())
+	if err != nil || !ok || user.Mention != "@carol:example.org" || user.Source != "pagerduty" {
+		t.Fatalf("on call = %+v, %v, %v", user, ok, err)
+	}
+	if err := r.Page(context.Background(), "escalation_required", "cortex/escalation_required/api", nil); err != nil {
+		t.Fatal(err)
+	}
+	if page["routing_key"] != "rk" || page["event_action"] != "trigger" || page["dedup_key"] != "cortex/escalation_required/api" {
+		t.Fatalf("page = %v", page)
+	}
+}
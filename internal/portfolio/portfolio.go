@@ -96,7 +96,7 @@ func GatherPortfolioBacklogs(ctx context.Context, cfg *config.Config, logger *sl
 
 		logger.Debug("gathering backlog", "project", name, "beads_dir", project.BeadsDir)
 
-		backlog, err := gatherProjectBacklog(ctx, name, project, crossGraph, logger)
+		backlog, err := gatherProjectBacklog(ctx, name, project, cfg.General, crossGraph, logger)
 		if err != nil {
 			logger.Error("failed to gather project backlog", "project", name, "error", err)
 			// Continue with other projects rather than failing completely
@@ -123,7 +123,7 @@ func GatherPortfolioBacklogs(ctx context.Context, cfg *config.Config, logger *sl
 }
 
 // gatherProjectBacklog collects backlog data for a single project
-func gatherProjectBacklog(ctx context.Context, projectName string, project config.Project, crossGraph *beads.CrossProjectGraph, logger *slog.Logger) (*ProjectBacklog, error) {
+func gatherProjectBacklog(ctx context.Context, projectName string, project config.Project, general config.General, crossGraph *beads.CrossProjectGraph, logger *slog.Logger) (*ProjectBacklog, error) {
 	beadsDir := config.ExpandHome(project.BeadsDir)
 
 	// List all beads for the project
@@ -132,9 +132,6 @@ func gatherProjectBacklog(ctx context.Context, projectName string, project confi
 		return nil, fmt.Errorf("listing beads for project %s: %w", projectName, err)
 	}
 
-	// Enrich beads with detailed information (acceptance criteria, design, estimates)
-	beads.EnrichBeads(ctx, beadsDir, allBeads)
-
 	// Build local dependency graph for this project
 	localGraph := buildLocalDepGraph(allBeads)
 
@@ -147,15 +144,28 @@ func gatherProjectBacklog(ctx context.Context, projectName string, project confi
 		CapacityPercent: 0, // Will be set from rate limits budget if available
 	}
 
-	// Categorize beads
-	backlog.RefinedBeads = filterRefinedBeads(backlog.AllBeads)
-	backlog.UnrefinedBeads = filterUnrefinedBeads(backlog.AllBeads)
-
-	// Find beads ready to work (unblocked by dependencies)
+	// Find beads ready to work (unblocked by dependencies), best first
 	backlog.ReadyToWork = beads.FilterUnblockedCrossProject(backlog.AllBeads, localGraph, crossGraph)
 
-	// Calculate total estimate
+	// Only the ready candidates that could be dispatched next need details
+	// (acceptance criteria, design, estimates) from bd show
+	beads.EnrichCandidates(ctx, beadsDir, backlog.ReadyToWork, general.MaxPerTick, general.EnrichLimit(), nil)
+	copyBeadDetails(backlog.AllBeads, backlog.ReadyToWork)
+	// Enrichment fills in estimates, which break priority ties
+	beads.SortCandidates(backlog.ReadyToWork)
+
+	// Categorize beads and total their estimates. Only beads whose details
+	// are known count: a bead that was not enriched is neither refined nor
+	// unrefined as far as we know
+	var detailed []beads.Bead
 	for _, bead := range backlog.AllBeads {
+		if bead.Detailed() {
+			detailed = append(detailed, bead)
+		}
+	}
+	backlog.RefinedBeads = filterRefinedBeads(detailed)
+	backlog.UnrefinedBeads = filterUnrefinedBeads(detailed)
+	for _, bead := range detailed {
 		backlog.TotalEstimate += bead.EstimateMinutes
 	}
 
@@ -176,6 +186,22 @@ func buildLocalDepGraph(allBeads []beads.Bead) *beads.DepGraph {
 	return beads.BuildDepGraph(allBeads)
 }
 
+// copyBeadDetails replaces the beads in dst with the matching enriched beads,
+// so they carry the detail fields and are marked detailed
+func copyBeadDetails(dst, enriched []beads.Bead) {
+	byID := make(map[string]beads.Bead, len(enriched))
+	for _, bead := range enriched {
+		if bead.Detailed() {
+			byID[bead.ID] = bead
+		}
+	}
+	for i := range dst {
+		if src, ok := byID[dst[i].ID]; ok {
+			dst[i] = src
+		}
+	}
+}
+
 // filterOpenBeads returns only open beads (excludes closed, cancelled, etc.)
 func filterOpenBeads(allBeads []beads.Bead) []beads.Bead {
 	var open []beads.Bead
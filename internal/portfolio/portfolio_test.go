@@ -2,8 +2,11 @@ package portfolio
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/antigravity-dev/cortex/internal/beads"
@@ -56,6 +59,65 @@ func TestGatherPortfolioBacklogs(t *testing.T) {
 	}
 }
 
+func TestGatherPortfolioBacklogsEnrichesOnlyWorkingSet(t *testing.T) {
+	projectDir := t.TempDir()
+	beadsDir := filepath.Join(projectDir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	var list []string
+	for i := 1; i <= 6; i++ {
+		list = append(list, fmt.Sprintf(`{"id":"p-%d","title":"bead %d","status":"open","issue_type":"task","priority":%d}`, i, i, (i+1)/2))
+	}
+	listPath := filepath.Join(projectDir, "list.json")
+	if err := os.WriteFile(listPath, []byte("["+strings.Join(list, ",")+"]"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	logPath := filepath.Join(projectDir, "show.log")
+	fakeBin := t.TempDir()
+	script := "#!/bin/sh\n" +
+		"if [ \"$1\" = list ]; then cat \"$BD_LIST\"; exit 0; fi\n" +
+		"echo \"$3\" >> \"$BD_SHOW_LOG\"\n" +
+		"est=30; if [ \"$3\" = p-1 ]; then est=90; fi\n" +
+		"echo \"{\\\"id\\\":\\\"$3\\\",\\\"acceptance_criteria\\\":\\\"ac\\\",\\\"estimated_minutes\\\":$est}\"\n"
+	if err := os.WriteFile(filepath.Join(fakeBin, "bd"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BD_LIST", listPath)
+	t.Setenv("BD_SHOW_LOG", logPath)
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	cfg := &config.Config{
+		General: config.General{MaxPerTick: 1, EnrichHeadroom: 2},
+		Projects: map[string]config.Project{
+			"p": {Enabled: true, Priority: 1, BeadsDir: beadsDir, Workspace: projectDir},
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	portfolio, err := GatherPortfolioBacklogs(context.Background(), cfg, logger)
+	if err != nil {
+		t.Fatalf("GatherPortfolioBacklogs failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(logPath)
+	if shown := strings.Fields(string(data)); strings.Join(shown, ",") != "p-1,p-2" {
+		t.Fatalf("expected only the top 2 candidates to be shown, got %v", shown)
+	}
+	backlog := portfolio.ProjectBacklogs["p"]
+	if len(backlog.ReadyToWork) != 6 || len(backlog.RefinedBeads) != 2 {
+		t.Fatalf("ready = %d, refined = %d; want 6 ready, 2 refined", len(backlog.ReadyToWork), len(backlog.RefinedBeads))
+	}
+	// Beads that were never shown are not known to be unrefined
+	if len(backlog.UnrefinedBeads) != 0 || backlog.TotalEstimate != 120 {
+		t.Fatalf("unrefined = %d, estimate = %d; want 0 unrefined, 120 minutes", len(backlog.UnrefinedBeads), backlog.TotalEstimate)
+	}
+	// The enriched estimates reorder the equal-priority p-1 and p-2
+	if backlog.ReadyToWork[0].ID != "p-2" || backlog.ReadyToWork[1].ID != "p-1" {
+		t.Fatalf("ready order = %s, %s; want p-2, p-1", backlog.ReadyToWork[0].ID, backlog.ReadyToWork[1].ID)
+	}
+}
+
 func TestPortfolioBacklogStructure(t *testing.T) {
 	// Test that all required fields exist and have correct types
 	portfolio := &PortfolioBacklog{
@@ -0,0 +1,84 @@
+package redact
+
+import (
+	"io"
+	"strings"
+	"sync"
+)
+
+// Window is how much of the most recent output a stream holds back before
+// redacting it, so a secret still being printed is redacted whole once the
+// rest of it arrives. It fits an RSA 4096 private key block with room to
+// spare.
+const Window = 8192
+
+// Cut returns how much of pending, output not yet redacted, can be redacted
+// and emitted now. The cut falls on a line boundary at least Window bytes
+// before the end of pending, moved back while a secret straddles it, which
+// shows as the redacted prefix differing from the start of the redacted
+// whole. It returns 0 when nothing can be emitted yet.
+func Cut(redact func(string) (string, int), pending string) int {
+	if len(pending) <= Window {
+		return 0
+	}
+	whole, _ := redact(pending)
+	cut := strings.LastIndexByte(pending[:len(pending)-Window], '\n') + 1
+	for cut > 0 {
+		prefix, _ := redact(pending[:cut])
+		if strings.HasPrefix(whole, prefix) {
+			return cut
+		}
+		cut = strings.LastIndexByte(pending[:cut-1], '\n') + 1
+	}
+	return 0
+}
+
+// Writer redacts what is written through it before passing it on, holding
+// back the last Window bytes until Close. It is safe for concurrent use, so
+// a command's stdout and stderr can share one.
+type Writer struct {
+	mu      sync.Mutex
+	w       io.Writer
+	r       *Redactor
+	pending string
+	err     error
+}
+
+// NewWriter returns a Writer that redacts with r into w. A nil r still
+// scrubs registered config secrets.
+func NewWriter(w io.Writer, r *Redactor) *Writer {
+	return &Writer{w: w, r: r}
+}
+
+// Write buffers p and passes on the settled part of what is buffered. An
+// error from the underlying writer is returned by every later call.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return 0, w.err
+	}
+	w.pending += string(p)
+	if cut := Cut(w.r.Redact, w.pending); cut > 0 {
+		w.emit(w.pending[:cut])
+		w.pending = w.pending[cut:]
+	}
+	return len(p), w.err
+}
+
+// Close redacts and passes on whatever is still held back. It does not
+// close the underlying writer.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err == nil && w.pending != "" {
+		w.emit(w.pending)
+		w.pending = ""
+	}
+	return w.err
+}
+
+func (w *Writer) emit(s string) {
+	s, _ = w.r.Redact(s)
+	_, w.err = io.WriteString(w.w, s)
+}
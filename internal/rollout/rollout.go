@@ -0,0 +1,145 @@
+// Package rollout evaluates rollout completion criteria against live bead
+// status, recorded health events and the latest burn-in report.
+package rollout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/beads"
+	"github.com/antigravity-dev/cortex/internal/burnin"
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+// Criterion names, in result order.
+const (
+	CriterionCriticalBeads  = "critical_beads"
+	CriterionCriticalEvents = "critical_events"
+	CriterionBurnIn         = "burnin"
+)
+
+// StatusUnknown is reported for a critical bead whose status could not be read.
+const StatusUnknown = "unknown"
+
+// BeadStatus is the current status of one critical bead.
+type BeadStatus struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Criterion is one completion criterion and whether it is met.
+type Criterion struct {
+	Name   string `json:"name"`
+	Pass   bool   `json:"pass"`
+	Detail string `json:"detail"`
+}
+
+// Result is a rollout completion evaluation. The rollout is complete when
+// every criterion passes.
+type Result struct {
+	EvaluatedAt time.Time    `json:"evaluated_at"`
+	Complete    bool         `json:"complete"`
+	Criteria    []Criterion  `json:"criteria"`
+	Beads       []BeadStatus `json:"beads,omitempty"`
+}
+
+// BeadLookup returns the status of a bead.
+type BeadLookup func(ctx context.Context, id string) (string, error)
+
+// BeadsLookup reads bead status from beadsDir with bd show.
+func BeadsLookup(beadsDir string) BeadLookup {
+	return func(ctx context.Context, id string) (string, error) {
+		detail, err := beads.ShowBeadCtx(ctx, beadsDir, id)
+		if err != nil {
+			return "", err
+		}
+		return detail.Status, nil
+	}
+}
+
+// Evaluate checks the criteria in ro as of now. Critical beads are only
+// checked when configured, and the burn-in criterion only when required. A
+// bead whose status cannot be read counts as not closed.
+func Evaluate(ctx context.Context, st *store.Store, ro config.HealthRollout, burnInDir string, lookup BeadLookup, now time.Time) (*Result, error) {
+	r := &Result{EvaluatedAt: now.UTC(), Complete: true}
+	add := func(c Criterion) {
+		r.Complete = r.Complete && c.Pass
+		r.Criteria = append(r.Criteria, c)
+	}
+
+	if len(ro.CriticalBeads) > 0 {
+		var pending []string
+		for _, id := range ro.CriticalBeads {
+			b := BeadStatus{ID: id}
+			status, err := lookup(ctx, id)
+			switch {
+			case err != nil:
+				b.Status, b.Error = StatusUnknown, err.Error()
+			case status == "":
+				b.Status = StatusUnknown
+			default:
+				b.Status = status
+			}
+			r.Beads = append(r.Beads, b)
+			if b.Status != "closed" {
+				pending = append(pending, fmt.Sprintf("%s (%s)", id, b.Status))
+			}
+		}
+		c := Criterion{
+			Name:   CriterionCriticalBeads,
+			Pass:   len(pending) == 0,
+			Detail: fmt.Sprintf("%d/%d closed", len(ro.CriticalBeads)-len(pending), len(ro.CriticalBeads)),
+		}
+		if len(pending) > 0 {
+			c.Detail += "; pending: " + strings.Join(pending, ", ")
+		}
+		add(c)
+	}
+
+	n, err := st.CountHealthEvents(store.BurnInCriticalEvents, now.Add(-ro.Window.Duration), now)
+	if err != nil {
+		return nil, err
+	}
+	add(Criterion{
+		Name:   CriterionCriticalEvents,
+		Pass:   n <= ro.MaxCriticalEvents,
+		Detail: fmt.Sprintf("%d critical health events in the last %s (limit %d)", n, ro.Window.Duration, ro.MaxCriticalEvents),
+	})
+
+	if ro.RequireBurnIn {
+		c := Criterion{Name: CriterionBurnIn}
+		report, err := burnin.Latest(burnInDir)
+		switch {
+		case errors.Is(err, burnin.ErrNoReport):
+			c.Detail = "no burn-in report"
+		case err != nil:
+			return nil, err
+		case report.Pass:
+			c.Pass = true
+			c.Detail = "burn-in report " + report.Date() + " passes"
+		default:
+			c.Detail = "burn-in report " + report.Date() + " failing for " + strings.Join(report.Failing(), ", ")
+		}
+		add(c)
+	}
+	return r, nil
+}
+
+// Summary is a one-line description of the result.
+func (r *Result) Summary() string {
+	var failing []string
+	for _, c := range r.Criteria {
+		if !c.Pass {
+			failing = append(failing, c.Name+": "+c.Detail)
+		}
+	}
+	if len(failing) == 0 {
+		return fmt.Sprintf("Rollout complete: all %d criteria met", len(r.Criteria))
+	}
+	return fmt.Sprintf("Rollout incomplete: %s", strings.Join(failing, "; "))
+}
@@ -0,0 +1,90 @@
+package rollout
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/burnin"
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestEvaluate(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+
+	now := time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC)
+	if _, err := st.RecordHealthEvents([]store.HealthEvent{
+		{EventType: "gateway_critical", CreatedAt: now.Add(-time.Hour)},
+		{EventType: "gateway_critical", CreatedAt: now.Add(-48 * time.Hour)}, // outside the window
+		{EventType: "gateway_restart", CreatedAt: now.Add(-time.Hour)},       // not critical
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	status := map[string]string{"cx-1": "closed", "cx-2": "in_progress"}
+	lookup := func(_ context.Context, id string) (string, error) {
+		if s, ok := status[id]; ok {
+			return s, nil
+		}
+		return "", errors.New("bd show failed")
+	}
+	ro := config.HealthRollout{
+		CriticalBeads: []string{"cx-1", "cx-2", "cx-3"},
+		Window:        config.Duration{Duration: 24 * time.Hour},
+		RequireBurnIn: true,
+	}
+	reports := filepath.Join(t.TempDir(), "reports")
+
+	r, err := Evaluate(context.Background(), st, ro, reports, lookup, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Complete || len(r.Criteria) != 3 {
+		t.Fatalf("expected an incomplete rollout with 3 criteria: %+v", r)
+	}
+	if r.Beads[2].Status != StatusUnknown || r.Beads[2].Error == "" {
+		t.Fatalf("unreadable bead should be unknown: %+v", r.Beads[2])
+	}
+	for i, want := range []string{
+		"1/3 closed; pending: cx-2 (in_progress), cx-3 (unknown)",
+		"1 critical health events in the last 24h0m0s (limit 0)",
+		"no burn-in report",
+	} {
+		if r.Criteria[i].Pass || r.Criteria[i].Detail != want {
+			t.Fatalf("criterion %d = %+v, want failing %q", i, r.Criteria[i], want)
+		}
+	}
+	if !strings.HasPrefix(r.Summary(), "Rollout incomplete: critical_beads: 1/3 closed") {
+		t.Fatalf("unexpected summary %q", r.Summary())
+	}
+
+	status["cx-2"], status["cx-3"] = "closed", "closed"
+	ro.MaxCriticalEvents = 1
+	if _, _, err := burnin.Write(reports, &burnin.Report{To: now, Pass: true}); err != nil {
+		t.Fatal(err)
+	}
+	r, err = Evaluate(context.Background(), st, ro, reports, lookup, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Complete || r.Summary() != "Rollout complete: all 3 criteria met" {
+		t.Fatalf("expected a complete rollout: %+v", r)
+	}
+
+	// Without critical beads or a burn-in requirement only events are checked.
+	r, err = Evaluate(context.Background(), st, config.HealthRollout{Window: ro.Window}, reports, lookup, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Complete || len(r.Criteria) != 1 || r.Criteria[0].Name != CriterionCriticalEvents {
+		t.Fatalf("unexpected result %+v", r)
+	}
+}
@@ -0,0 +1,21 @@
+package scheduler
+
+import (
+	"github.com/antigravity-dev/cortex/internal/dispatch"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+// DispatchTier returns the tier a bead's next dispatch should use. A pending
+// stage SLA escalation raises tier by one and is consumed, so the following
+// dispatch falls back to the requested tier. At the top tier the escalation
+// is still consumed and tier is returned unchanged.
+func DispatchTier(st *store.Store, project, beadID, tier string) (string, error) {
+	escalate, err := st.ConsumeStageEscalation(project, beadID)
+	if err != nil || !escalate {
+		return tier, err
+	}
+	if up := dispatch.UpgradeTier(tier); up != "" {
+		return up, nil
+	}
+	return tier, nil
+}
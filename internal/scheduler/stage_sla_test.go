@@ -0,0 +1,37 @@
+package scheduler
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestDispatchTier(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+
+	if got, err := DispatchTier(st, "p", "cx-1", "fast"); err != nil || got != "fast" {
+		t.Fatalf("without breach: tier=%q err=%v", got, err)
+	}
+	if _, err := st.RecordStageSLABreach(store.StageSLABreach{Project: "p", BeadID: "cx-1", Stage: "implement", EnteredAt: time.Now(), Escalate: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := DispatchTier(st, "p", "cx-1", "fast"); err != nil || got != "balanced" {
+		t.Fatalf("after breach: tier=%q err=%v, want balanced", got, err)
+	}
+	if got, err := DispatchTier(st, "p", "cx-1", "fast"); err != nil || got != "fast" {
+		t.Fatalf("escalation should apply once: tier=%q err=%v", got, err)
+	}
+
+	if _, err := st.RecordStageSLABreach(store.StageSLABreach{Project: "p", BeadID: "cx-1", Stage: "review", EnteredAt: time.Now(), Escalate: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := DispatchTier(st, "p", "cx-1", "premium"); err != nil || got != "premium" {
+		t.Fatalf("top tier: tier=%q err=%v", got, err)
+	}
+}
@@ -0,0 +1,149 @@
+package scheduler
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/git"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+// StageTransition asks to move a bead to another stage of its workflow.
+type StageTransition struct {
+	Project    string
+	BeadID     string
+	To         string
+	Labels     []string // the bead's current labels
+	DispatchID int64    // dispatch that runs the new stage, if already known
+	ApprovedBy string   // approver satisfying the current stage's approval gate
+}
+
+// TransitionDeniedError reports the guards a stage transition failed.
+type TransitionDeniedError struct {
+	Project string
+	BeadID  string
+	From    string
+	To      string
+	Reasons []string
+}
+
+func (e *TransitionDeniedError) Error() string {
+	return fmt.Sprintf("stage transition %s -> %s denied for %s/%s: %s",
+		e.From, e.To, e.Project, e.BeadID, strings.Join(e.Reasons, "; "))
+}
+
+// AdvanceStage moves a bead to t.To after checking the transition rules of
+// the stage it is leaving, and records the move in the bead's stage history.
+// A failed guard returns a *TransitionDeniedError and leaves the bead where
+// it is.
+func AdvanceStage(st *store.Store, workflows map[string]config.WorkflowConfig, t StageTransition) error {
+	stage, err := st.GetBeadStage(t.Project, t.BeadID)
+	if err != nil {
+		return err
+	}
+	wf, ok := workflows[stage.Workflow]
+	if !ok {
+		return fmt.Errorf("scheduler: bead %s/%s uses unknown workflow %q", t.Project, t.BeadID, stage.Workflow)
+	}
+	from := slices.IndexFunc(wf.Stages, func(s config.StageConfig) bool { return s.Name == stage.CurrentStage })
+	if from < 0 {
+		return fmt.Errorf("scheduler: bead %s/%s is in stage %q, which workflow %q does not define", t.Project, t.BeadID, stage.CurrentStage, stage.Workflow)
+	}
+	to := slices.IndexFunc(wf.Stages, func(s config.StageConfig) bool { return s.Name == t.To })
+	if to < 0 && t.To == config.TerminalStage {
+		to = len(wf.Stages)
+	}
+
+	reasons, err := transitionGuards(st, wf, from, to, t)
+	if err != nil {
+		return err
+	}
+	if len(reasons) > 0 {
+		return &TransitionDeniedError{Project: t.Project, BeadID: t.BeadID, From: stage.CurrentStage, To: t.To, Reasons: reasons}
+	}
+
+	return st.TransitionBeadStage(t.Project, t.BeadID, to, len(wf.Stages), store.StageHistoryEntry{
+		Stage:      t.To,
+		DispatchID: t.DispatchID,
+		ApprovedBy: t.ApprovedBy,
+	})
+}
+
+// NextStage returns the stage a bead moves to once its current stage is done:
+// the first of the stage's next stages, else the following stage, else
+// config.TerminalStage after the last stage.
+func NextStage(workflows map[string]config.WorkflowConfig, stage *store.BeadStage) (string, error) {
+	wf, ok := workflows[stage.Workflow]
+	if !ok {
+		return "", fmt.Errorf("scheduler: bead %s/%s uses unknown workflow %q", stage.Project, stage.BeadID, stage.Workflow)
+	}
+	i := slices.IndexFunc(wf.Stages, func(s config.StageConfig) bool { return s.Name == stage.CurrentStage })
+	switch {
+	case i < 0:
+		return "", fmt.Errorf("scheduler: bead %s/%s is in stage %q, which workflow %q does not define", stage.Project, stage.BeadID, stage.CurrentStage, stage.Workflow)
+	case len(wf.Stages[i].Next) > 0:
+		return wf.Stages[i].Next[0], nil
+	case i+1 < len(wf.Stages):
+		return wf.Stages[i+1].Name, nil
+	default:
+		return config.TerminalStage, nil
+	}
+}
+
+// transitionGuards returns why leaving stage from for stage to is not
+// allowed; to is len(wf.Stages) for the terminal stage and -1 when unknown.
+func transitionGuards(st *store.Store, wf config.WorkflowConfig, from, to int, t StageTransition) ([]string, error) {
+	rules := wf.Stages[from]
+	var reasons []string
+
+	switch {
+	case to < 0:
+		reasons = append(reasons, fmt.Sprintf("unknown stage %q", t.To))
+	case len(rules.Next) > 0 && !slices.Contains(rules.Next, t.To):
+		reasons = append(reasons, fmt.Sprintf("%s may only move to %s", rules.Name, strings.Join(rules.Next, ", ")))
+	case len(rules.Next) == 0 && to != from+1:
+		reasons = append(reasons, fmt.Sprintf("%s may only move to the following stage", rules.Name))
+	}
+
+	for _, label := range rules.RequireLabels {
+		if !slices.Contains(t.Labels, label) {
+			reasons = append(reasons, fmt.Sprintf("missing label %q", label))
+		}
+	}
+
+	if len(rules.RequireChecks) > 0 {
+		dod, err := st.GetLatestDoDResult(t.Project, t.BeadID)
+		if err != nil {
+			return nil, err
+		}
+		for _, check := range rules.RequireChecks {
+			if dod == nil || !slices.Contains(dod.Checks, store.DoDCheck{Command: check, Passed: true}) {
+				reasons = append(reasons, fmt.Sprintf("DoD check %q has not passed", check))
+			}
+		}
+	}
+
+	if rules.RequireApproval && t.ApprovedBy == "" {
+		reasons = append(reasons, "approval required")
+	}
+	return reasons, nil
+}
+
+// ReviewStage is the stage whose entry takes a bead's draft PR out of draft.
+const ReviewStage = "review"
+
+// MarkReadyForReview marks the PR opened by the bead's latest dispatch ready
+// for review, so reviewers are only pinged once the coder is done iterating.
+// It returns the PR number, or 0 when the bead has no PR.
+func MarkReadyForReview(st *store.Store, workspace, project, beadID string) (int, error) {
+	number, _, err := st.GetLatestBeadPR(project, beadID)
+	if err != nil || number == 0 {
+		return 0, err
+	}
+	if err := git.MarkPRReady(workspace, number); err != nil {
+		return 0, err
+	}
+	return number, nil
+}
@@ -0,0 +1,99 @@
+package scheduler
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestAdvanceStage(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+
+	workflows := map[string]config.WorkflowConfig{
+		"dev": {Stages: []config.StageConfig{
+			{Name: "implement", Role: "coder", RequireChecks: []string{"go test ./..."}},
+			{Name: "review", Role: "reviewer", Next: []string{"implement", "done"}, RequireLabels: []string{"reviewed"}, RequireApproval: true},
+		}},
+	}
+	if err := st.UpsertBeadStage(&store.BeadStage{Project: "p", BeadID: "cx-1", Workflow: "dev", CurrentStage: "implement", TotalStages: 2}); err != nil {
+		t.Fatal(err)
+	}
+	advance := func(tr StageTransition) error {
+		tr.Project, tr.BeadID = "p", "cx-1"
+		return AdvanceStage(st, workflows, tr)
+	}
+	denied := func(err error, want ...string) {
+		t.Helper()
+		var d *TransitionDeniedError
+		if !errors.As(err, &d) {
+			t.Fatalf("expected a denied transition, got %v", err)
+		}
+		if got := strings.Join(d.Reasons, "; "); got != strings.Join(want, "; ") {
+			t.Fatalf("reasons = %q, want %q", got, strings.Join(want, "; "))
+		}
+	}
+
+	// Without next stages only the following stage is allowed, and the DoD
+	// check must have passed in the latest run.
+	denied(advance(StageTransition{To: "done"}),
+		"implement may only move to the following stage", `DoD check "go test ./..." has not passed`)
+	if err := st.RecordDoDResult(1, "cx-1", "p", true, "", `[{"command":"go test ./...","passed":true}]`); err != nil {
+		t.Fatal(err)
+	}
+	if err := advance(StageTransition{To: "review", DispatchID: 7}); err != nil {
+		t.Fatalf("expected implement -> review, got %v", err)
+	}
+
+	denied(advance(StageTransition{To: "bogus"}),
+		`unknown stage "bogus"`, `missing label "reviewed"`, "approval required")
+	denied(advance(StageTransition{To: "done", Labels: []string{"reviewed"}}), "approval required")
+	if err := advance(StageTransition{To: "done", Labels: []string{"reviewed"}, ApprovedBy: "alice"}); err != nil {
+		t.Fatalf("expected review -> done, got %v", err)
+	}
+
+	stage, err := st.GetBeadStage("p", "cx-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stage.CurrentStage != "done" || stage.StageIndex != 2 {
+		t.Fatalf("unexpected stage %+v", stage)
+	}
+	h := stage.StageHistory
+	if len(h) != 3 || h[1].Stage != "review" || h[1].DispatchID != 7 || h[2].ApprovedBy != "alice" {
+		t.Fatalf("unexpected history %+v", h)
+	}
+
+	if err := AdvanceStage(st, map[string]config.WorkflowConfig{}, StageTransition{Project: "p", BeadID: "cx-1", To: "review"}); err == nil {
+		t.Fatal("expected an error for an unknown workflow")
+	}
+}
+
+func TestNextStage(t *testing.T) {
+	workflows := map[string]config.WorkflowConfig{
+		"dev": {Stages: []config.StageConfig{
+			{Name: "implement"},
+			{Name: "review", Next: []string{"qa", "implement"}},
+			{Name: "qa"},
+		}},
+	}
+	for current, want := range map[string]string{"implement": "review", "review": "qa", "qa": config.TerminalStage} {
+		got, err := NextStage(workflows, &store.BeadStage{Project: "p", BeadID: "cx-1", Workflow: "dev", CurrentStage: current})
+		if err != nil || got != want {
+			t.Fatalf("NextStage(%s) = %q, %v; want %q", current, got, err, want)
+		}
+	}
+	if _, err := NextStage(workflows, &store.BeadStage{Workflow: "dev", CurrentStage: "nope"}); err == nil {
+		t.Fatal("expected an error for an unknown stage")
+	}
+	if _, err := NextStage(workflows, &store.BeadStage{Workflow: "ops", CurrentStage: "implement"}); err == nil {
+		t.Fatal("expected an error for an unknown workflow")
+	}
+}
@@ -0,0 +1,135 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// AgentRun checkpoints an agent CLI process started by a Temporal activity so
+// a restarted worker can re-adopt it instead of launching a duplicate. A run
+// is identified by its workflow, bead and execution attempt, which stay the
+// same when the activity is retried on another worker.
+type AgentRun struct {
+	ID         int64
+	WorkflowID string
+	BeadID     string
+	Attempt    int
+	CallKey    string // hash of the command line, distinguishing calls within one attempt
+	Agent      string
+	Command    string // argv[0], used to guard against PID reuse
+	PID        int
+	StdoutPath string
+	StderrPath string
+	StartedAt  time.Time
+}
+
+// migrateAgentRunsTable creates the agent_runs table. Called from migrate().
+// Checkpoints only live for the duration of a run, so the earlier table keyed
+// on activity IDs is dropped rather than converted.
+func migrateAgentRunsTable(db *sql.DB) error {
+	var legacy int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('agent_runs') WHERE name = 'activity_id'`).Scan(&legacy); err != nil {
+		return fmt.Errorf("inspect agent_runs table: %w", err)
+	}
+	if legacy > 0 {
+		if _, err := db.Exec(`DROP TABLE agent_runs`); err != nil {
+			return fmt.Errorf("drop activity-keyed agent_runs table: %w", err)
+		}
+	}
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS agent_runs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			workflow_id TEXT NOT NULL,
+			bead_id TEXT NOT NULL DEFAULT '',
+			attempt INTEGER NOT NULL DEFAULT 0,
+			call_key TEXT NOT NULL,
+			agent TEXT NOT NULL DEFAULT '',
+			command TEXT NOT NULL DEFAULT '',
+			pid INTEGER NOT NULL,
+			stdout_path TEXT NOT NULL DEFAULT '',
+			stderr_path TEXT NOT NULL DEFAULT '',
+			started_at DATETIME NOT NULL DEFAULT (datetime('now')),
+			UNIQUE (workflow_id, bead_id, attempt, call_key)
+		)
+	`); err != nil {
+		return fmt.Errorf("create agent_runs table: %w", err)
+	}
+	return nil
+}
+
+// RecordAgentRun checkpoints a started agent process, replacing any earlier
+// checkpoint for the same call.
+func (s *Store) RecordAgentRun(r AgentRun) (int64, error) {
+	var id int64
+	err := s.db.QueryRow(
+		`INSERT INTO agent_runs (workflow_id, bead_id, attempt, call_key, agent, command, pid, stdout_path, stderr_path)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(workflow_id, bead_id, attempt, call_key) DO UPDATE SET
+			agent = excluded.agent, command = excluded.command, pid = excluded.pid,
+			stdout_path = excluded.stdout_path, stderr_path = excluded.stderr_path,
+			started_at = datetime('now')
+		 RETURNING id`,
+		r.WorkflowID, r.BeadID, r.Attempt, r.CallKey, r.Agent, r.Command, r.PID, r.StdoutPath, r.StderrPath,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("store: record agent run: %w", err)
+	}
+	return id, nil
+}
+
+// GetAgentRun returns the checkpoint for one call, or nil if there is none.
+func (s *Store) GetAgentRun(workflowID, beadID string, attempt int, callKey string) (*AgentRun, error) {
+	row := s.db.QueryRow(
+		`SELECT id, workflow_id, bead_id, attempt, call_key, agent, command, pid, stdout_path, stderr_path, started_at
+		 FROM agent_runs WHERE workflow_id = ? AND bead_id = ? AND attempt = ? AND call_key = ?`,
+		workflowID, beadID, attempt, callKey,
+	)
+	r, err := scanAgentRun(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return r, err
+}
+
+// ListAgentRuns returns every checkpointed agent run, oldest first.
+func (s *Store) ListAgentRuns() ([]AgentRun, error) {
+	rows, err := s.db.Query(
+		`SELECT id, workflow_id, bead_id, attempt, call_key, agent, command, pid, stdout_path, stderr_path, started_at
+		 FROM agent_runs ORDER BY id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("store: list agent runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []AgentRun
+	for rows.Next() {
+		r, err := scanAgentRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, *r)
+	}
+	return runs, rows.Err()
+}
+
+// DeleteAgentRun removes a checkpoint once its process has been accounted for.
+func (s *Store) DeleteAgentRun(id int64) error {
+	if _, err := s.db.Exec(`DELETE FROM agent_runs WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("store: delete agent run %d: %w", id, err)
+	}
+	return nil
+}
+
+func scanAgentRun(r rowScanner) (*AgentRun, error) {
+	var run AgentRun
+	if err := r.Scan(&run.ID, &run.WorkflowID, &run.BeadID, &run.Attempt, &run.CallKey, &run.Agent, &run.Command,
+		&run.PID, &run.StdoutPath, &run.StderrPath, &run.StartedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("store: scan agent run: %w", err)
+	}
+	return &run, nil
+}
@@ -0,0 +1,51 @@
+package store
+
+import "testing"
+
+func TestAgentRunCheckpoints(t *testing.T) {
+	s := tempStore(t)
+
+	run := AgentRun{WorkflowID: "wf-1", BeadID: "cortex-1", Attempt: 2, CallKey: "abc", Agent: "claude", Command: "claude", PID: 100, StdoutPath: "/tmp/o"}
+	firstID, err := s.RecordAgentRun(run)
+	if err != nil {
+		t.Fatal(err)
+	}
+	run.PID = 200
+	secondID, err := s.RecordAgentRun(run)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secondID != firstID {
+		t.Fatalf("re-record returned id %d, want %d", secondID, firstID)
+	}
+
+	got, err := s.GetAgentRun("wf-1", "cortex-1", 2, "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.PID != 200 || got.StdoutPath != "/tmp/o" {
+		t.Fatalf("GetAgentRun = %+v, want pid 200 after re-record", got)
+	}
+
+	if missing, err := s.GetAgentRun("wf-1", "cortex-1", 2, "other"); err != nil || missing != nil {
+		t.Fatalf("GetAgentRun(other) = %+v, %v; want nil, nil", missing, err)
+	}
+	if missing, err := s.GetAgentRun("wf-1", "cortex-1", 3, "abc"); err != nil || missing != nil {
+		t.Fatalf("GetAgentRun(next attempt) = %+v, %v; want nil, nil", missing, err)
+	}
+
+	runs, err := s.ListAgentRuns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 {
+		t.Fatalf("ListAgentRuns = %d rows, want 1", len(runs))
+	}
+
+	if err := s.DeleteAgentRun(got.ID); err != nil {
+		t.Fatal(err)
+	}
+	if runs, _ := s.ListAgentRuns(); len(runs) != 0 {
+		t.Fatalf("rows after delete = %d", len(runs))
+	}
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Approval request statuses.
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalDenied   = "denied"
+)
+
+// ApprovalRequest is an action waiting on a human answer in chat, such as a
+// plan awaiting approval. The waiting workflow is signalled when it is
+// answered.
+type ApprovalRequest struct {
+	ID         int64
+	Kind       string // what needs approving, e.g. "plan"
+	Project    string
+	BeadID     string
+	WorkflowID string
+	Room       string // room the prompt was posted to; answers come from there
+	Prompt     string
+	Status     string
+	DecidedBy  string // Matrix user, "api" or "timeout"
+	CreatedAt  time.Time
+	DecidedAt  time.Time // zero while pending
+}
+
+// migrateApprovalRequestsTable creates the approval_requests table. Called from migrate().
+func migrateApprovalRequestsTable(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS approval_requests (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			kind TEXT NOT NULL,
+			project TEXT NOT NULL DEFAULT '',
+			bead_id TEXT NOT NULL DEFAULT '',
+			workflow_id TEXT NOT NULL DEFAULT '',
+			room TEXT NOT NULL DEFAULT '',
+			prompt TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL DEFAULT 'pending',
+			decided_by TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL DEFAULT (datetime('now')),
+			decided_at DATETIME
+		)
+	`); err != nil {
+		return fmt.Errorf("create approval_requests table: %w", err)
+	}
+	return nil
+}
+
+// CreateApprovalRequest records a pending request and returns its ID.
+func (s *Store) CreateApprovalRequest(r ApprovalRequest) (int64, error) {
+	res, err := s.db.Exec(`
+		INSERT INTO approval_requests (kind, project, bead_id, workflow_id, room, prompt, status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		r.Kind, r.Project, r.BeadID, r.WorkflowID, r.Room, r.Prompt, ApprovalPending,
+		time.Now().UTC().Format(time.DateTime),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("store: create approval request: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+// GetApprovalRequest returns the request, or nil when there is none.
+func (s *Store) GetApprovalRequest(id int64) (*ApprovalRequest, error) {
+	var r ApprovalRequest
+	var decidedAt sql.NullTime
+	err := s.db.QueryRow(`
+		SELECT id, kind, project, bead_id, workflow_id, room, prompt, status, decided_by, created_at, decided_at
+		FROM approval_requests WHERE id = ?`,
+		id,
+	).Scan(&r.ID, &r.Kind, &r.Project, &r.BeadID, &r.WorkflowID, &r.Room, &r.Prompt, &r.Status, &r.DecidedBy,
+		&r.CreatedAt, &decidedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("store: get approval request: %w", err)
+	}
+	r.DecidedAt = decidedAt.Time
+	return &r, nil
+}
+
+// DecideApprovalRequest answers a pending request and reports whether it
+// was still pending; an answered request keeps its first answer.
+func (s *Store) DecideApprovalRequest(id int64, approved bool, decidedBy string) (bool, error) {
+	status := ApprovalDenied
+	if approved {
+		status = ApprovalApproved
+	}
+	res, err := s.db.Exec(`
+		UPDATE approval_requests SET status = ?, decided_by = ?, decided_at = ?
+		WHERE id = ? AND status = ?`,
+		status, decidedBy, time.Now().UTC().Format(time.DateTime), id, ApprovalPending,
+	)
+	if err != nil {
+		return false, fmt.Errorf("store: decide approval request: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("store: decide approval request: %w", err)
+	}
+	return n > 0, nil
+}
+
+// ReopenApprovalRequest puts an answered request back to pending, for an
+// answer that could not be delivered to the waiting workflow.
+func (s *Store) ReopenApprovalRequest(id int64) error {
+	if _, err := s.db.Exec(`
+		UPDATE approval_requests SET status = ?, decided_by = '', decided_at = NULL WHERE id = ?`,
+		ApprovalPending, id,
+	); err != nil {
+		return fmt.Errorf("store: reopen approval request: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package store
+
+import "testing"
+
+func TestApprovalRequests(t *testing.T) {
+	s := tempStore(t)
+
+	if r, err := s.GetApprovalRequest(1); err != nil || r != nil {
+		t.Fatalf("expected no request, got %+v, %v", r, err)
+	}
+	id, err := s.CreateApprovalRequest(ApprovalRequest{
+		Kind: "plan", Project: "alpha", BeadID: "alpha-1", WorkflowID: "wf-1", Room: "!room:x", Prompt: "Approve?",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := s.GetApprovalRequest(id)
+	if err != nil || r == nil || r.Status != ApprovalPending || r.WorkflowID != "wf-1" || r.Room != "!room:x" || r.CreatedAt.IsZero() || !r.DecidedAt.IsZero() {
+		t.Fatalf("unexpected request: %+v, %v", r, err)
+	}
+
+	if ok, err := s.DecideApprovalRequest(id, true, "@alice:x"); err != nil || !ok {
+		t.Fatalf("decide: %v, %v", ok, err)
+	}
+	// The first answer sticks.
+	if ok, err := s.DecideApprovalRequest(id, false, "timeout"); err != nil || ok {
+		t.Fatalf("second decide: %v, %v", ok, err)
+	}
+	r, _ = s.GetApprovalRequest(id)
+	if r.Status != ApprovalApproved || r.DecidedBy != "@alice:x" || r.DecidedAt.IsZero() {
+		t.Fatalf("unexpected decided request: %+v", r)
+	}
+
+	if err := s.ReopenApprovalRequest(id); err != nil {
+		t.Fatal(err)
+	}
+	r, _ = s.GetApprovalRequest(id)
+	if r.Status != ApprovalPending || r.DecidedBy != "" || !r.DecidedAt.IsZero() {
+		t.Fatalf("unexpected reopened request: %+v", r)
+	}
+	if ok, err := s.DecideApprovalRequest(id, false, "timeout"); err != nil || !ok {
+		t.Fatalf("decide after reopen: %v, %v", ok, err)
+	}
+	if r, _ = s.GetApprovalRequest(id); r.Status != ApprovalDenied {
+		t.Fatalf("status = %q, want denied", r.Status)
+	}
+}
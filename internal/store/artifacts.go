@@ -0,0 +1,105 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Artifact is a file collected from an agent workspace after a dispatch.
+type Artifact struct {
+	ID          int64     `json:"id"`
+	DispatchID  int64     `json:"dispatch_id"`
+	Project     string    `json:"project"`
+	BeadID      string    `json:"bead_id"`
+	Path        string    `json:"path"` // relative to the workspace
+	StoredPath  string    `json:"-"`    // where the copy lives under the artifacts dir
+	SizeBytes   int64     `json:"size_bytes"`
+	CollectedAt time.Time `json:"collected_at"`
+}
+
+// migrateArtifactsTable creates the dispatch_artifacts table. Called from migrate().
+func migrateArtifactsTable(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS dispatch_artifacts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			dispatch_id INTEGER NOT NULL,
+			project TEXT NOT NULL,
+			bead_id TEXT NOT NULL,
+			path TEXT NOT NULL,
+			stored_path TEXT NOT NULL,
+			size_bytes INTEGER NOT NULL DEFAULT 0,
+			collected_at DATETIME NOT NULL DEFAULT (datetime('now'))
+		)
+	`); err != nil {
+		return fmt.Errorf("create dispatch_artifacts table: %w", err)
+	}
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_dispatch_artifacts_dispatch ON dispatch_artifacts(dispatch_id)`); err != nil {
+		return fmt.Errorf("create dispatch_artifacts dispatch index: %w", err)
+	}
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_dispatch_artifacts_collected ON dispatch_artifacts(collected_at)`); err != nil {
+		return fmt.Errorf("create dispatch_artifacts collected_at index: %w", err)
+	}
+	return nil
+}
+
+const artifactCols = `id, dispatch_id, project, bead_id, path, stored_path, size_bytes, collected_at`
+
+// RecordArtifact stores a collected artifact and returns its ID.
+func (s *Store) RecordArtifact(a Artifact) (int64, error) {
+	res, err := s.db.Exec(
+		`INSERT INTO dispatch_artifacts (dispatch_id, project, bead_id, path, stored_path, size_bytes) VALUES (?, ?, ?, ?, ?, ?)`,
+		a.DispatchID, a.Project, a.BeadID, a.Path, a.StoredPath, a.SizeBytes,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("store: record artifact: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+// ListArtifacts returns a dispatch's artifacts ordered by path.
+func (s *Store) ListArtifacts(dispatchID int64) ([]Artifact, error) {
+	return s.queryArtifacts(`SELECT `+artifactCols+` FROM dispatch_artifacts WHERE dispatch_id = ? ORDER BY path, id`, dispatchID)
+}
+
+// GetArtifact returns an artifact by ID, or nil if it does not exist.
+func (s *Store) GetArtifact(id int64) (*Artifact, error) {
+	artifacts, err := s.queryArtifacts(`SELECT `+artifactCols+` FROM dispatch_artifacts WHERE id = ?`, id)
+	if err != nil || len(artifacts) == 0 {
+		return nil, err
+	}
+	return &artifacts[0], nil
+}
+
+// ListArtifactsBefore returns the artifacts collected before cutoff, oldest first.
+func (s *Store) ListArtifactsBefore(cutoff time.Time) ([]Artifact, error) {
+	return s.queryArtifacts(`SELECT `+artifactCols+` FROM dispatch_artifacts WHERE collected_at < ? ORDER BY collected_at, id`,
+		cutoff.UTC().Format(time.DateTime))
+}
+
+// DeleteArtifact removes an artifact record; the stored file is the caller's to remove.
+func (s *Store) DeleteArtifact(id int64) error {
+	if _, err := s.db.Exec(`DELETE FROM dispatch_artifacts WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("store: delete artifact: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) queryArtifacts(query string, args ...any) ([]Artifact, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("store: query artifacts: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Artifact
+	for rows.Next() {
+		var a Artifact
+		if err := rows.Scan(&a.ID, &a.DispatchID, &a.Project, &a.BeadID, &a.Path, &a.StoredPath, &a.SizeBytes, &a.CollectedAt); err != nil {
+			return nil, fmt.Errorf("store: scan artifact: %w", err)
+		}
+		a.CollectedAt = a.CollectedAt.UTC()
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
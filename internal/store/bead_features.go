@@ -0,0 +1,99 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// BeadFeatures are the bead properties the complexity model learns from,
+// captured when the bead is dispatched.
+type BeadFeatures struct {
+	Project         string
+	BeadID          string
+	Type            string
+	EstimateMinutes int
+	DescriptionLen  int
+	Labels          []string
+}
+
+// ComplexitySample pairs a bead's features with the outcome of its finished
+// dispatches.
+type ComplexitySample struct {
+	BeadFeatures
+	Dispatches  int
+	Retries     int
+	Failures    int
+	Escalations int
+	DurationS   float64
+}
+
+// migrateBeadFeaturesTable creates the bead_features table. Called from migrate().
+func migrateBeadFeaturesTable(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS bead_features (
+			project TEXT NOT NULL,
+			bead_id TEXT NOT NULL,
+			issue_type TEXT NOT NULL DEFAULT '',
+			estimate_minutes INTEGER NOT NULL DEFAULT 0,
+			description_len INTEGER NOT NULL DEFAULT 0,
+			labels TEXT NOT NULL DEFAULT '',
+			recorded_at DATETIME NOT NULL DEFAULT (datetime('now')),
+			PRIMARY KEY (project, bead_id)
+		)
+	`); err != nil {
+		return fmt.Errorf("create bead_features table: %w", err)
+	}
+	return nil
+}
+
+// RecordBeadFeatures stores the features of a bead being dispatched,
+// replacing those of an earlier dispatch.
+func (s *Store) RecordBeadFeatures(f BeadFeatures) error {
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO bead_features (project, bead_id, issue_type, estimate_minutes, description_len, labels, recorded_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		f.Project, f.BeadID, f.Type, f.EstimateMinutes, f.DescriptionLen, encodeDispatchLabels(f.Labels),
+		time.Now().UTC().Format(time.DateTime),
+	)
+	if err != nil {
+		return fmt.Errorf("store: record bead features: %w", err)
+	}
+	return nil
+}
+
+// GetComplexitySamples returns the beads with recorded features and their
+// outcome over the dispatches that started since and have finished.
+func (s *Store) GetComplexitySamples(since time.Time) ([]ComplexitySample, error) {
+	rows, err := s.db.Query(`
+		SELECT f.project, f.bead_id, f.issue_type, f.estimate_minutes, f.description_len, f.labels,
+			COUNT(d.id),
+			COALESCE(SUM(d.retries), 0),
+			COALESCE(SUM(CASE WHEN d.status = 'failed' THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN d.escalated_from_tier != '' THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(d.duration_s), 0)
+		FROM bead_features f
+		JOIN dispatches d ON d.project = f.project AND d.bead_id = f.bead_id
+		WHERE d.dispatched_at >= ? AND d.status != 'running'
+		GROUP BY f.project, f.bead_id
+		ORDER BY f.project, f.bead_id`,
+		since.UTC().Format(time.DateTime),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("store: query complexity samples: %w", err)
+	}
+	defer rows.Close()
+
+	var out []ComplexitySample
+	for rows.Next() {
+		var c ComplexitySample
+		var labels string
+		if err := rows.Scan(&c.Project, &c.BeadID, &c.Type, &c.EstimateMinutes, &c.DescriptionLen, &labels,
+			&c.Dispatches, &c.Retries, &c.Failures, &c.Escalations, &c.DurationS); err != nil {
+			return nil, fmt.Errorf("store: scan complexity sample: %w", err)
+		}
+		c.Labels = decodeDispatchLabels(labels)
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
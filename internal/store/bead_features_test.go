@@ -0,0 +1,64 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetComplexitySamples(t *testing.T) {
+	s := tempStore(t)
+
+	if err := s.RecordBeadFeatures(BeadFeatures{Project: "p", BeadID: "b-1", Type: "task", EstimateMinutes: 60, Labels: []string{"api"}}); err != nil {
+		t.Fatal(err)
+	}
+	// A later dispatch of the same bead replaces its features.
+	if err := s.RecordBeadFeatures(BeadFeatures{Project: "p", BeadID: "b-1", Type: "bug", EstimateMinutes: 90, DescriptionLen: 400, Labels: []string{"api", "auth"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RecordBeadFeatures(BeadFeatures{Project: "p", BeadID: "b-2", Type: "chore"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, d := range []struct {
+		bead, status string
+		duration     float64
+	}{
+		{"b-1", "failed", 600},
+		{"b-1", "completed", 300},
+		{"b-2", "running", 0},    // unfinished dispatches do not count
+		{"b-3", "completed", 60}, // no recorded features
+	} {
+		id, err := s.RecordDispatch(d.bead, "p", "agent", "prov", "temporal", 0, "", "prompt", "", "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.status != "running" {
+			if err := s.UpdateDispatchStatus(id, d.status, 0, d.duration); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	samples, err := s.GetComplexitySamples(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 1 {
+		t.Fatalf("samples = %+v, want only b-1", samples)
+	}
+	got := samples[0]
+	if got.BeadID != "b-1" || got.Type != "bug" || got.EstimateMinutes != 90 || got.DescriptionLen != 400 || len(got.Labels) != 2 {
+		t.Errorf("features = %+v", got.BeadFeatures)
+	}
+	if got.Dispatches != 2 || got.Failures != 1 || got.DurationS != 900 {
+		t.Errorf("outcome = %+v", got)
+	}
+
+	samples, err = s.GetComplexitySamples(time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 0 {
+		t.Errorf("samples before since = %+v", samples)
+	}
+}
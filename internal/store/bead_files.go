@@ -0,0 +1,98 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Sources of a bead's file mapping.
+const (
+	BeadFilesPlan   = "plan"   // the files a dispatch plan said it would modify
+	BeadFilesCommit = "commit" // files changed by commits naming the bead
+	BeadFilesBranch = "branch" // files changed on the bead's feature branch
+)
+
+// migrateBeadFilesTable creates bead_files, which maps beads to the files
+// their work touched or plans to touch. Called from migrate().
+func migrateBeadFilesTable(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS bead_files (
+			project TEXT NOT NULL,
+			bead_id TEXT NOT NULL,
+			path TEXT NOT NULL,
+			source TEXT NOT NULL,
+			recorded_at DATETIME NOT NULL DEFAULT (datetime('now')),
+			PRIMARY KEY (project, bead_id, path)
+		)
+	`); err != nil {
+		return fmt.Errorf("create bead_files table: %w", err)
+	}
+	return nil
+}
+
+// RecordBeadFiles maps paths to a bead. A path already mapped takes the new
+// source.
+func (s *Store) RecordBeadFiles(project, beadID, source string, paths []string) error {
+	project, beadID = strings.TrimSpace(project), strings.TrimSpace(beadID)
+	if project == "" || beadID == "" {
+		return fmt.Errorf("store: record bead files: project and bead id are required")
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("store: begin bead files transaction: %w", err)
+	}
+	defer tx.Rollback()
+	for _, p := range paths {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO bead_files (project, bead_id, path, source, recorded_at)
+			VALUES (?, ?, ?, ?, datetime('now'))
+			ON CONFLICT(project, bead_id, path) DO UPDATE SET
+				source = excluded.source,
+				recorded_at = excluded.recorded_at
+		`, project, beadID, p, source); err != nil {
+			return fmt.Errorf("store: record bead file %s: %w", p, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("store: commit bead files: %w", err)
+	}
+	return nil
+}
+
+// GetBeadFiles returns the sorted files mapped to each of beadIDs in
+// project. Beads without files are absent from the map.
+func (s *Store) GetBeadFiles(project string, beadIDs []string) (map[string][]string, error) {
+	files := make(map[string][]string)
+	if len(beadIDs) == 0 {
+		return files, nil
+	}
+	args := []any{project}
+	for _, id := range beadIDs {
+		args = append(args, id)
+	}
+	rows, err := s.db.Query(`SELECT bead_id, path FROM bead_files WHERE project = ? AND bead_id IN (?`+
+		strings.Repeat(", ?", len(beadIDs)-1)+`)`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("store: get bead files: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var beadID, path string
+		if err := rows.Scan(&beadID, &path); err != nil {
+			return nil, fmt.Errorf("store: scan bead file: %w", err)
+		}
+		files[beadID] = append(files[beadID], path)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("store: get bead files: %w", err)
+	}
+	for _, paths := range files {
+		sort.Strings(paths)
+	}
+	return files, nil
+}
@@ -0,0 +1,37 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBeadFiles(t *testing.T) {
+	s := tempStore(t)
+
+	if err := s.RecordBeadFiles("alpha", "a-1", BeadFilesPlan, []string{"store.go", "api.go", " "}); err != nil {
+		t.Fatal(err)
+	}
+	// Re-recording a path updates it rather than duplicating it.
+	if err := s.RecordBeadFiles("alpha", "a-1", BeadFilesCommit, []string{"api.go"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RecordBeadFiles("beta", "a-2", BeadFilesBranch, []string{"api.go"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RecordBeadFiles("", "a-1", BeadFilesPlan, []string{"x.go"}); err == nil {
+		t.Fatal("expected error for missing project")
+	}
+
+	files, err := s.GetBeadFiles("alpha", []string{"a-1", "a-2", "a-3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{"a-1": {"api.go", "store.go"}}
+	if !reflect.DeepEqual(files, want) {
+		t.Fatalf("files = %v, want %v", files, want)
+	}
+
+	if files, err := s.GetBeadFiles("alpha", nil); err != nil || len(files) != 0 {
+		t.Fatalf("empty lookup = %v, %v", files, err)
+	}
+}
@@ -279,6 +279,33 @@ func TestBeadStageUpdateProgress(t *testing.T) {
 	if updated.StageIndex != 1 {
 		t.Errorf("Expected stage index 1, got %d", updated.StageIndex)
 	}
+
+	h := updated.StageHistory
+	if len(h) != 2 {
+		t.Fatalf("Expected 2 stage history entries, got %+v", h)
+	}
+	if h[0].Stage != "initial" || h[0].Status != "completed" || h[0].CompletedAt == nil {
+		t.Errorf("Expected closed initial entry, got %+v", h[0])
+	}
+	if h[1].Stage != "middle" || h[1].Status != "running" || h[1].DispatchID != dispatchID || h[1].CompletedAt != nil {
+		t.Errorf("Expected open middle entry for dispatch %d, got %+v", dispatchID, h[1])
+	}
+
+	// Re-upserting the same stage must not add a transition.
+	stage.CurrentStage = "middle"
+	if err := s.UpsertBeadStage(stage); err != nil {
+		t.Fatalf("Failed to re-upsert stage: %v", err)
+	}
+	if err := s.UpdateBeadStageProgress("progress-project", "progress-bead", "done", 2, 3, 0); err != nil {
+		t.Fatalf("Failed to complete: %v", err)
+	}
+	done, err := s.GetBeadStage("progress-project", "progress-bead")
+	if err != nil {
+		t.Fatalf("Failed to retrieve completed stage: %v", err)
+	}
+	if len(done.StageHistory) != 3 || done.StageHistory[2].Status != "completed" {
+		t.Errorf("Expected terminal done entry, got %+v", done.StageHistory)
+	}
 }
 
 func TestBeadStageDelete(t *testing.T) {
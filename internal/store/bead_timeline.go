@@ -0,0 +1,196 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Bead timeline event kinds.
+const (
+	TimelineDispatch = "dispatch"
+	TimelineStage    = "stage"
+	TimelineDoD      = "dod"
+	TimelineHealth   = "health"
+	TimelineLease    = "lease"
+	TimelinePR       = "pr"
+)
+
+// BeadTimelineEvent is one entry in a bead's timeline.
+type BeadTimelineEvent struct {
+	At         time.Time `json:"at"`
+	Kind       string    `json:"kind"`  // dispatch, stage, dod, health, lease or pr
+	Event      string    `json:"event"` // what happened, e.g. started, failed, entered, opened
+	DispatchID int64     `json:"dispatch_id,omitempty"`
+	Details    string    `json:"details,omitempty"`
+}
+
+// BeadTimeline is everything recorded about one bead, oldest first.
+type BeadTimeline struct {
+	Project      string              `json:"project"`
+	BeadID       string              `json:"bead_id"`
+	CurrentStage string              `json:"current_stage,omitempty"`
+	Events       []BeadTimelineEvent `json:"events"`
+}
+
+// GetBeadTimeline assembles a bead's dispatches, stage history, DoD runs,
+// health events, claim lease and PR review events into one chronological
+// record. The claim lease only keeps its latest state, so it contributes its
+// claim and last heartbeat. Health events are matched on bead ID alone since
+// they carry no project.
+func (s *Store) GetBeadTimeline(project, beadID string) (*BeadTimeline, error) {
+	tl := &BeadTimeline{Project: project, BeadID: beadID, Events: []BeadTimelineEvent{}}
+	add := func(at time.Time, kind, event string, dispatchID int64, details string) {
+		tl.Events = append(tl.Events, BeadTimelineEvent{At: at.UTC(), Kind: kind, Event: event, DispatchID: dispatchID, Details: details})
+	}
+
+	dispatches, err := s.GetDispatchesByBead(beadID)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range dispatches {
+		if d.Project != project {
+			continue
+		}
+		add(d.DispatchedAt, TimelineDispatch, "started", d.ID, fmt.Sprintf("agent %s, provider %s, tier %s", d.AgentID, d.Provider, d.Tier))
+		if d.CompletedAt.Valid {
+			details := fmt.Sprintf("exit %d after %.0fs", d.ExitCode, d.DurationS)
+			if d.FailureCategory != "" {
+				details += ", " + d.FailureCategory
+			}
+			if d.FailureSummary != "" {
+				details += ": " + d.FailureSummary
+			}
+			add(d.CompletedAt.Time, TimelineDispatch, d.Status, d.ID, details)
+		}
+	}
+
+	// A bead without stage tracking has no stage events.
+	stage, err := s.GetBeadStage(project, beadID)
+	if err == nil {
+		tl.CurrentStage = stage.CurrentStage
+		for _, h := range stage.StageHistory {
+			details := ""
+			if h.ApprovedBy != "" {
+				details = "approved by " + h.ApprovedBy
+			}
+			add(h.StartedAt, TimelineStage, "entered "+h.Stage, h.DispatchID, details)
+			if h.CompletedAt != nil {
+				add(*h.CompletedAt, TimelineStage, "left "+h.Stage, h.DispatchID, h.Status)
+			}
+		}
+	}
+
+	if err := s.addDoDTimeline(project, beadID, add); err != nil {
+		return nil, err
+	}
+	if err := s.addHealthTimeline(beadID, add); err != nil {
+		return nil, err
+	}
+
+	lease, err := s.GetClaimLease(beadID)
+	if err != nil {
+		return nil, err
+	}
+	if lease != nil && lease.Project == project {
+		add(lease.ClaimedAt, TimelineLease, "claimed", lease.DispatchID, "agent "+lease.AgentID)
+		if lease.HeartbeatAt.After(lease.ClaimedAt) {
+			add(lease.HeartbeatAt, TimelineLease, "heartbeat", lease.DispatchID, "")
+		}
+	}
+
+	if err := s.addPRTimeline(project, beadID, add); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(tl.Events, func(i, j int) bool { return tl.Events[i].At.Before(tl.Events[j].At) })
+	return tl, nil
+}
+
+type timelineAdder func(at time.Time, kind, event string, dispatchID int64, details string)
+
+func (s *Store) addDoDTimeline(project, beadID string, add timelineAdder) error {
+	rows, err := s.db.Query(
+		`SELECT dispatch_id, passed, failures, checked_at FROM dod_results
+		 WHERE project = ? AND bead_id = ? ORDER BY id`,
+		project, beadID,
+	)
+	if err != nil {
+		return fmt.Errorf("store: bead timeline dod results: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var dispatchID int64
+		var passed bool
+		var failures string
+		var checkedAt time.Time
+		if err := rows.Scan(&dispatchID, &passed, &failures, &checkedAt); err != nil {
+			return fmt.Errorf("store: scan bead timeline dod result: %w", err)
+		}
+		event := "failed"
+		if passed {
+			event = "passed"
+		}
+		add(checkedAt, TimelineDoD, event, dispatchID, failures)
+	}
+	return rows.Err()
+}
+
+func (s *Store) addHealthTimeline(beadID string, add timelineAdder) error {
+	rows, err := s.db.Query(
+		`SELECT event_type, details, dispatch_id, source, created_at FROM health_events
+		 WHERE bead_id = ? ORDER BY id`,
+		strings.TrimSpace(beadID),
+	)
+	if err != nil {
+		return fmt.Errorf("store: bead timeline health events: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var e HealthEvent
+		if err := rows.Scan(&e.EventType, &e.Details, &e.DispatchID, &e.Source, &e.CreatedAt); err != nil {
+			return fmt.Errorf("store: scan bead timeline health event: %w", err)
+		}
+		details := e.Details
+		if e.Source != "" {
+			details = e.Source + ": " + details
+		}
+		add(e.CreatedAt, TimelineHealth, e.EventType, e.DispatchID, details)
+	}
+	return rows.Err()
+}
+
+func (s *Store) addPRTimeline(project, beadID string, add timelineAdder) error {
+	if err := s.trackPRReviews(); err != nil {
+		return err
+	}
+	rows, err := s.db.Query(`SELECT `+prReviewCols+` FROM pr_reviews WHERE project = ? AND bead_id = ? ORDER BY id`, project, beadID)
+	if err != nil {
+		return fmt.Errorf("store: bead timeline pr reviews: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var r PRReview
+		if err := rows.Scan(&r.ID, &r.DispatchID, &r.Project, &r.BeadID, &r.Agent, &r.PRNumber, &r.PRURL,
+			&r.OpenedAt, &r.FirstReviewAt, &r.Reviewer, &r.Nudges, &r.LastNudgeAt, &r.ClosedAt); err != nil {
+			return fmt.Errorf("store: scan bead timeline pr review: %w", err)
+		}
+		pr := fmt.Sprintf("#%d %s", r.PRNumber, r.PRURL)
+		add(r.OpenedAt, TimelinePR, "opened", r.DispatchID, strings.TrimSpace(pr))
+		if r.FirstReviewAt.Valid {
+			details := fmt.Sprintf("#%d", r.PRNumber)
+			if r.Reviewer != "" {
+				details += " by " + r.Reviewer
+			}
+			add(r.FirstReviewAt.Time, TimelinePR, "reviewed", r.DispatchID, details)
+		}
+		if r.LastNudgeAt.Valid {
+			add(r.LastNudgeAt.Time, TimelinePR, "nudged", r.DispatchID, fmt.Sprintf("#%d, %d nudges", r.PRNumber, r.Nudges))
+		}
+		if r.ClosedAt.Valid {
+			add(r.ClosedAt.Time, TimelinePR, "closed", r.DispatchID, fmt.Sprintf("#%d", r.PRNumber))
+		}
+	}
+	return rows.Err()
+}
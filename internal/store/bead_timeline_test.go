@@ -0,0 +1,88 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetBeadTimeline(t *testing.T) {
+	s := tempStore(t)
+	start := time.Now().UTC().Add(-2 * time.Hour).Truncate(time.Second)
+
+	id, err := s.RecordDispatch("cx-1", "alpha", "agent", "claude", "balanced", 1, "", "p", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetDispatchTime(id, start); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.db.Exec(`UPDATE dispatches SET status = 'completed', completed_at = ? WHERE id = ?`,
+		start.Add(30*time.Minute).Format(time.DateTime), id); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpdateDispatchPR(id, "https://example.com/pr/9", 9); err != nil {
+		t.Fatal(err)
+	}
+	// Another project's dispatch of the same bead ID stays out.
+	if _, err := s.RecordDispatch("cx-1", "beta", "agent", "claude", "balanced", 1, "", "p", "", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpsertBeadStage(&BeadStage{Project: "alpha", BeadID: "cx-1", Workflow: "dev", CurrentStage: "coding", TotalStages: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpdateBeadStageProgress("alpha", "cx-1", "review", 1, 2, id); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RecordDoDResult(id, "cx-1", "alpha", false, "go test failed", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RecordHealthEventWithDispatch("stage_transition", "coding -> review", id, "cx-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpsertClaimLease("cx-1", "alpha", "/beads", "agent"); err != nil {
+		t.Fatal(err)
+	}
+
+	tl, err := s.GetBeadTimeline("alpha", "cx-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tl.CurrentStage != "review" {
+		t.Fatalf("expected current stage review, got %q", tl.CurrentStage)
+	}
+	seen := map[string]bool{}
+	for i, e := range tl.Events {
+		if i > 0 && e.At.Before(tl.Events[i-1].At) {
+			t.Fatalf("events out of order: %+v", tl.Events)
+		}
+		seen[e.Kind+" "+e.Event] = true
+	}
+	for _, want := range []string{
+		"dispatch started", "dispatch completed", "stage entered coding", "stage left coding", "stage entered review",
+		"dod failed", "health stage_transition", "lease claimed", "pr opened",
+	} {
+		if !seen[want] {
+			t.Errorf("missing %q in %+v", want, tl.Events)
+		}
+	}
+	if tl.Events[0].Kind != TimelineDispatch || tl.Events[0].Event != "started" {
+		t.Fatalf("expected the dispatch start first, got %+v", tl.Events[0])
+	}
+	starts := 0
+	for _, e := range tl.Events {
+		if e.Kind == TimelineDispatch && e.Event == "started" {
+			starts++
+		}
+	}
+	if starts != 1 {
+		t.Fatalf("expected only alpha's dispatch, got %d starts", starts)
+	}
+
+	empty, err := s.GetBeadTimeline("alpha", "cx-none")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(empty.Events) != 0 || empty.CurrentStage != "" {
+		t.Fatalf("expected an empty timeline, got %+v", empty)
+	}
+}
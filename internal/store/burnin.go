@@ -0,0 +1,71 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BurnInCriticalEvents are the health event types that count against the
+// burn-in critical event gate.
+var BurnInCriticalEvents = []string{"gateway_critical", "dispatch_session_gone", "escalation_required"}
+
+// BurnInCounts are the raw dispatch and health event counts behind one
+// project's burn-in SLO scores.
+type BurnInCounts struct {
+	Total          int // dispatches started in the window
+	Completed      int
+	Failed         int
+	Unknown        int // interrupted, or failed with an unknown or vanished session
+	Cancelled      int
+	Retried        int // manually retried
+	CriticalEvents int // critical health events tied to the project, plus unattributed ones
+}
+
+// GetBurnInCounts counts a project's dispatches started in [from, to) and the
+// critical health events recorded in the same window. Events that name no
+// dispatch or bead are system-wide and count for every project.
+func (s *Store) GetBurnInCounts(project string, from, to time.Time) (BurnInCounts, error) {
+	var c BurnInCounts
+	fromS, toS := from.UTC().Format(time.DateTime), to.UTC().Format(time.DateTime)
+
+	err := s.db.QueryRow(`
+		SELECT COUNT(*),
+			COALESCE(SUM(status = 'completed'), 0),
+			COALESCE(SUM(status = 'failed'), 0),
+			COALESCE(SUM(status = 'interrupted' OR failure_category IN ('unknown', 'session_disappeared')), 0),
+			COALESCE(SUM(status = 'cancelled'), 0),
+			COALESCE(SUM(status = 'retried'), 0)
+		FROM dispatches
+		WHERE project = ? AND dispatched_at >= ? AND dispatched_at < ?`,
+		project, fromS, toS,
+	).Scan(&c.Total, &c.Completed, &c.Failed, &c.Unknown, &c.Cancelled, &c.Retried)
+	if err != nil {
+		return c, fmt.Errorf("store: burn-in dispatch counts: %w", err)
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(BurnInCriticalEvents)), ",")
+	args := make([]any, 0, len(BurnInCriticalEvents)+3)
+	for _, t := range BurnInCriticalEvents {
+		args = append(args, t)
+	}
+	args = append(args, fromS, toS, project)
+	err = s.db.QueryRow(`
+		SELECT COUNT(*) FROM health_events h
+		WHERE h.event_type IN (`+placeholders+`)
+		  AND h.created_at >= ? AND h.created_at < ?
+		  AND (
+			(h.dispatch_id = 0 AND h.bead_id = '')
+			OR EXISTS (
+				SELECT 1 FROM dispatches d
+				WHERE d.project = ?
+				  AND ((h.dispatch_id != 0 AND d.id = h.dispatch_id) OR (h.dispatch_id = 0 AND d.bead_id = h.bead_id))
+			)
+		  )`,
+		args...,
+	).Scan(&c.CriticalEvents)
+	if err != nil {
+		return c, fmt.Errorf("store: burn-in critical events: %w", err)
+	}
+	return c, nil
+}
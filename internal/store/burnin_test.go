@@ -0,0 +1,80 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetBurnInCounts(t *testing.T) {
+	s := tempStore(t)
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(7 * 24 * time.Hour)
+
+	record := func(bead, project, status string, at time.Time) int64 {
+		t.Helper()
+		id, err := s.RecordDispatch(bead, project, "agent", "claude", "balanced", 1, "", "p", "", "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.SetDispatchTime(id, at); err != nil {
+			t.Fatal(err)
+		}
+		if status != "running" {
+			if err := s.UpdateDispatchStatus(id, status, 0, 1); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return id
+	}
+
+	in := from.Add(time.Hour)
+	record("b-1", "alpha", "completed", in)
+	record("b-2", "alpha", "completed", in)
+	failed := record("b-3", "alpha", "failed", in)
+	record("b-4", "alpha", "interrupted", in)
+	record("b-5", "alpha", "cancelled", in)
+	record("b-6", "alpha", "retried", in)
+	record("b-7", "alpha", "running", in)
+	record("b-8", "alpha", "failed", from.Add(-time.Hour)) // before the window
+	record("b-9", "alpha", "failed", to)                   // window end is exclusive
+	beta := record("b-10", "beta", "failed", in)
+	if err := s.UpdateFailureDiagnosis(failed, "session_disappeared", "tmux session vanished"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.RecordHealthEvents([]HealthEvent{
+		{EventType: "gateway_critical", CreatedAt: in},                          // system-wide
+		{EventType: "dispatch_session_gone", DispatchID: failed, CreatedAt: in}, // alpha
+		{EventType: "escalation_required", BeadID: "b-1", CreatedAt: in},        // alpha via bead
+		{EventType: "dispatch_session_gone", DispatchID: beta, CreatedAt: in},   // beta only
+		{EventType: "gateway_restart", CreatedAt: in},                           // not critical
+		{EventType: "gateway_critical", CreatedAt: to.Add(time.Hour)},           // outside the window
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := s.GetBurnInCounts("alpha", from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := BurnInCounts{Total: 7, Completed: 2, Failed: 1, Unknown: 2, Cancelled: 1, Retried: 1, CriticalEvents: 3}
+	if c != want {
+		t.Fatalf("alpha counts = %+v, want %+v", c, want)
+	}
+
+	c, err = s.GetBurnInCounts("beta", from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Total != 1 || c.Failed != 1 || c.CriticalEvents != 2 {
+		t.Fatalf("unexpected beta counts %+v", c)
+	}
+
+	c, err = s.GetBurnInCounts("idle", from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Total != 0 || c.CriticalEvents != 1 {
+		t.Fatalf("idle project should see only system-wide events, got %+v", c)
+	}
+}
@@ -0,0 +1,210 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// CandidateScan is one backlog grooming pass: every candidate the chief
+// considered, in ranked order, and which one the planner finally picked.
+type CandidateScan struct {
+	ID         int64              `json:"id"`
+	Project    string             `json:"project"`
+	SessionID  string             `json:"session_id"` // planning workflow ID
+	Agent      string             `json:"agent"`
+	Rationale  string             `json:"rationale"`
+	SelectedID string             `json:"selected_id,omitempty"`
+	ScannedAt  time.Time          `json:"scanned_at"`
+	SelectedAt *time.Time         `json:"selected_at,omitempty"`
+	Candidates []ScannedCandidate `json:"candidates,omitempty"`
+}
+
+// ScannedCandidate is one backlog item as the chief ranked it. For items the
+// chief did not recommend, Rationale is the reason it was passed over.
+type ScannedCandidate struct {
+	Rank        int    `json:"rank"` // 1 is the chief's top pick
+	ItemID      string `json:"item_id"`
+	Title       string `json:"title"`
+	Impact      string `json:"impact"`
+	Effort      string `json:"effort"`
+	Recommended bool   `json:"recommended"`
+	Selected    bool   `json:"selected"`
+	Rationale   string `json:"rationale"`
+}
+
+// migrateCandidateScansTables creates the candidate_scans and
+// scan_candidates tables. Called from migrate().
+func migrateCandidateScansTables(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS candidate_scans (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			project TEXT NOT NULL DEFAULT '',
+			session_id TEXT NOT NULL DEFAULT '',
+			agent TEXT NOT NULL DEFAULT '',
+			rationale TEXT NOT NULL DEFAULT '',
+			selected_id TEXT NOT NULL DEFAULT '',
+			scanned_at DATETIME NOT NULL DEFAULT (datetime('now')),
+			selected_at DATETIME
+		)
+	`); err != nil {
+		return fmt.Errorf("create candidate_scans table: %w", err)
+	}
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_candidate_scans_project ON candidate_scans(project, scanned_at)`); err != nil {
+		return fmt.Errorf("create candidate_scans project index: %w", err)
+	}
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS scan_candidates (
+			scan_id INTEGER NOT NULL REFERENCES candidate_scans(id) ON DELETE CASCADE,
+			rank INTEGER NOT NULL,
+			item_id TEXT NOT NULL DEFAULT '',
+			title TEXT NOT NULL DEFAULT '',
+			impact TEXT NOT NULL DEFAULT '',
+			effort TEXT NOT NULL DEFAULT '',
+			recommended INTEGER NOT NULL DEFAULT 0,
+			rationale TEXT NOT NULL DEFAULT '',
+			PRIMARY KEY (scan_id, rank)
+		)
+	`); err != nil {
+		return fmt.Errorf("create scan_candidates table: %w", err)
+	}
+	return nil
+}
+
+// RecordCandidateScan stores a scan and its candidates in one transaction and
+// returns the scan ID. Candidates are ranked in the order given.
+func (s *Store) RecordCandidateScan(scan CandidateScan) (int64, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("store: begin candidate scan transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(
+		`INSERT INTO candidate_scans (project, session_id, agent, rationale) VALUES (?, ?, ?, ?)`,
+		scan.Project, scan.SessionID, scan.Agent, scan.Rationale,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("store: record candidate scan: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("store: read candidate scan id: %w", err)
+	}
+
+	for i, c := range scan.Candidates {
+		if _, err := tx.Exec(
+			`INSERT INTO scan_candidates (scan_id, rank, item_id, title, impact, effort, recommended, rationale) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, i+1, c.ItemID, c.Title, c.Impact, c.Effort, boolToInt(c.Recommended), c.Rationale,
+		); err != nil {
+			return 0, fmt.Errorf("store: record scan candidate %d: %w", i+1, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("store: commit candidate scan transaction: %w", err)
+	}
+	return id, nil
+}
+
+// RecordScanSelection marks which item the planner picked from a scan. The
+// item need not be one of the candidates: planners may write in their own.
+func (s *Store) RecordScanSelection(scanID int64, itemID string) error {
+	res, err := s.db.Exec(
+		`UPDATE candidate_scans SET selected_id = ?, selected_at = datetime('now') WHERE id = ?`,
+		itemID, scanID,
+	)
+	if err != nil {
+		return fmt.Errorf("store: record scan selection %d: %w", scanID, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("store: record scan selection: scan %d not found", scanID)
+	}
+	return nil
+}
+
+// ListCandidateScans returns the most recent scans, newest first, without
+// their candidates. An empty project matches all projects.
+func (s *Store) ListCandidateScans(project string, limit int) ([]CandidateScan, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	rows, err := s.db.Query(
+		`SELECT id, project, session_id, agent, rationale, selected_id, scanned_at, selected_at
+		 FROM candidate_scans
+		 WHERE ? = '' OR project = ?
+		 ORDER BY scanned_at DESC, id DESC
+		 LIMIT ?`,
+		project, project, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("store: list candidate scans: %w", err)
+	}
+	defer rows.Close()
+
+	var scans []CandidateScan
+	for rows.Next() {
+		scan, err := scanCandidateScan(rows)
+		if err != nil {
+			return nil, err
+		}
+		scans = append(scans, *scan)
+	}
+	return scans, rows.Err()
+}
+
+// GetCandidateScan returns one scan with its ranked candidates, or nil if it
+// does not exist.
+func (s *Store) GetCandidateScan(id int64) (*CandidateScan, error) {
+	row := s.db.QueryRow(
+		`SELECT id, project, session_id, agent, rationale, selected_id, scanned_at, selected_at
+		 FROM candidate_scans WHERE id = ?`, id,
+	)
+	scan, err := scanCandidateScan(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(
+		`SELECT rank, item_id, title, impact, effort, recommended, rationale
+		 FROM scan_candidates WHERE scan_id = ? ORDER BY rank`, id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("store: query scan candidates: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c ScannedCandidate
+		if err := rows.Scan(&c.Rank, &c.ItemID, &c.Title, &c.Impact, &c.Effort, &c.Recommended, &c.Rationale); err != nil {
+			return nil, fmt.Errorf("store: scan scan candidate: %w", err)
+		}
+		c.Selected = scan.SelectedID != "" && c.ItemID == scan.SelectedID
+		scan.Candidates = append(scan.Candidates, c)
+	}
+	return scan, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanCandidateScan(r rowScanner) (*CandidateScan, error) {
+	var scan CandidateScan
+	var selectedAt sql.NullTime
+	if err := r.Scan(&scan.ID, &scan.Project, &scan.SessionID, &scan.Agent, &scan.Rationale,
+		&scan.SelectedID, &scan.ScannedAt, &selectedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("store: scan candidate scan: %w", err)
+	}
+	if selectedAt.Valid {
+		t := selectedAt.Time
+		scan.SelectedAt = &t
+	}
+	return &scan, nil
+}
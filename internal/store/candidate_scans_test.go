@@ -0,0 +1,83 @@
+package store
+
+import "testing"
+
+func TestCandidateScanRoundTrip(t *testing.T) {
+	s := tempStore(t)
+
+	id, err := s.RecordCandidateScan(CandidateScan{
+		Project:   "proj",
+		SessionID: "planning-proj-1",
+		Agent:     "claude",
+		Rationale: "auth first",
+		Candidates: []ScannedCandidate{
+			{ItemID: "auth", Title: "Fix auth", Effort: "low", Recommended: true, Rationale: "blocks users"},
+			{ItemID: "docs", Title: "Docs refresh", Effort: "medium", Rationale: "low impact this sprint"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.RecordScanSelection(id, "docs"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RecordScanSelection(id+100, "x"); err == nil {
+		t.Fatal("expected error selecting from unknown scan")
+	}
+
+	scan, err := s.GetCandidateScan(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scan == nil {
+		t.Fatal("scan not found")
+	}
+	if scan.SelectedID != "docs" || scan.SelectedAt == nil {
+		t.Fatalf("selection = %q at %v, want docs", scan.SelectedID, scan.SelectedAt)
+	}
+	if len(scan.Candidates) != 2 {
+		t.Fatalf("candidates = %d, want 2", len(scan.Candidates))
+	}
+	top, picked := scan.Candidates[0], scan.Candidates[1]
+	if top.Rank != 1 || top.ItemID != "auth" || !top.Recommended || top.Selected {
+		t.Errorf("unexpected top candidate: %+v", top)
+	}
+	if picked.Rank != 2 || !picked.Selected || picked.Rationale != "low impact this sprint" {
+		t.Errorf("unexpected picked candidate: %+v", picked)
+	}
+
+	missing, err := s.GetCandidateScan(id + 100)
+	if err != nil || missing != nil {
+		t.Fatalf("GetCandidateScan(missing) = %v, %v; want nil, nil", missing, err)
+	}
+}
+
+func TestListCandidateScansFiltersByProject(t *testing.T) {
+	s := tempStore(t)
+
+	for _, p := range []string{"a", "b", "a"} {
+		if _, err := s.RecordCandidateScan(CandidateScan{Project: p}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	all, err := s.ListCandidateScans("", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("all scans = %d, want 3", len(all))
+	}
+	if all[0].ID < all[1].ID {
+		t.Errorf("scans not newest first: %d before %d", all[0].ID, all[1].ID)
+	}
+
+	onlyA, err := s.ListCandidateScans("a", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(onlyA) != 1 || onlyA[0].Project != "a" {
+		t.Fatalf("project filter/limit returned %+v", onlyA)
+	}
+}
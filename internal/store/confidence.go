@@ -0,0 +1,81 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// DispatchConfidence is the confidence an agent reported for a dispatch,
+// paired with whether the work actually passed DoD.
+type DispatchConfidence struct {
+	DispatchID  int64
+	BeadID      string
+	Project     string
+	Agent       string
+	Confidence  float64
+	DoDPassed   bool
+	HumanReview bool
+	RecordedAt  time.Time
+}
+
+// migrateDispatchConfidenceTable creates the dispatch_confidence table. Called from migrate().
+func migrateDispatchConfidenceTable(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS dispatch_confidence (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			dispatch_id INTEGER NOT NULL,
+			bead_id TEXT NOT NULL DEFAULT '',
+			project TEXT NOT NULL DEFAULT '',
+			agent TEXT NOT NULL DEFAULT '',
+			confidence REAL NOT NULL,
+			dod_passed INTEGER NOT NULL DEFAULT 0,
+			human_review INTEGER NOT NULL DEFAULT 0,
+			recorded_at DATETIME NOT NULL DEFAULT (datetime('now'))
+		)
+	`); err != nil {
+		return fmt.Errorf("create dispatch_confidence table: %w", err)
+	}
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_dispatch_confidence_recorded ON dispatch_confidence(recorded_at)`); err != nil {
+		return fmt.Errorf("create dispatch_confidence recorded index: %w", err)
+	}
+	return nil
+}
+
+// RecordDispatchConfidence stores a dispatch's reported confidence and DoD outcome.
+func (s *Store) RecordDispatchConfidence(c DispatchConfidence) error {
+	if _, err := s.db.Exec(
+		`INSERT INTO dispatch_confidence (dispatch_id, bead_id, project, agent, confidence, dod_passed, human_review)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		c.DispatchID, c.BeadID, c.Project, c.Agent, c.Confidence, boolToInt(c.DoDPassed), boolToInt(c.HumanReview),
+	); err != nil {
+		return fmt.Errorf("store: record dispatch confidence: %w", err)
+	}
+	return nil
+}
+
+// ListDispatchConfidence returns confidence records since the given time, oldest first.
+func (s *Store) ListDispatchConfidence(since time.Time) ([]DispatchConfidence, error) {
+	rows, err := s.db.Query(
+		`SELECT dispatch_id, bead_id, project, agent, confidence, dod_passed, human_review, recorded_at
+		 FROM dispatch_confidence WHERE recorded_at >= ? ORDER BY id`,
+		since.UTC().Format(time.DateTime),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("store: list dispatch confidence: %w", err)
+	}
+	defer rows.Close()
+
+	var out []DispatchConfidence
+	for rows.Next() {
+		var c DispatchConfidence
+		var dodPassed, humanReview int
+		if err := rows.Scan(&c.DispatchID, &c.BeadID, &c.Project, &c.Agent, &c.Confidence, &dodPassed, &humanReview, &c.RecordedAt); err != nil {
+			return nil, fmt.Errorf("store: scan dispatch confidence: %w", err)
+		}
+		c.DoDPassed = dodPassed != 0
+		c.HumanReview = humanReview != 0
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,39 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDispatchConfidenceRecordAndList(t *testing.T) {
+	s := tempStore(t)
+
+	if err := s.RecordDispatchConfidence(DispatchConfidence{DispatchID: 1, BeadID: "bead-1", Project: "cortex", Agent: "claude", Confidence: 0.9, DoDPassed: true}); err != nil {
+		t.Fatalf("RecordDispatchConfidence: %v", err)
+	}
+	if err := s.RecordDispatchConfidence(DispatchConfidence{DispatchID: 2, BeadID: "bead-2", Project: "cortex", Agent: "codex", Confidence: 0.4, HumanReview: true}); err != nil {
+		t.Fatalf("RecordDispatchConfidence: %v", err)
+	}
+
+	got, err := s.ListDispatchConfidence(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("ListDispatchConfidence: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(got))
+	}
+	if got[0].DispatchID != 1 || got[0].Confidence != 0.9 || !got[0].DoDPassed || got[0].HumanReview {
+		t.Fatalf("unexpected first record: %+v", got[0])
+	}
+	if got[1].Agent != "codex" || got[1].DoDPassed || !got[1].HumanReview {
+		t.Fatalf("unexpected second record: %+v", got[1])
+	}
+
+	future, err := s.ListDispatchConfidence(time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("ListDispatchConfidence future: %v", err)
+	}
+	if len(future) != 0 {
+		t.Fatalf("expected no records after now, got %d", len(future))
+	}
+}
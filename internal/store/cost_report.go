@@ -0,0 +1,63 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// CostLine is the spend of one project under one value of a cost dimension.
+type CostLine struct {
+	Project      string
+	Key          string // provider, tier or activity, by the query
+	Dispatches   int
+	InputTokens  int
+	OutputTokens int
+	CostUSD      float64
+}
+
+// GetDispatchCosts totals the cost of dispatches started in [from, to) by
+// project and by dimension, which is "provider" or "tier".
+func (s *Store) GetDispatchCosts(dimension string, from, to time.Time) ([]CostLine, error) {
+	switch dimension {
+	case "provider", "tier":
+	default:
+		return nil, fmt.Errorf("store: unknown cost dimension %q", dimension)
+	}
+	return s.queryCostLines(`
+		SELECT project, `+dimension+`, COUNT(*), COALESCE(SUM(input_tokens), 0), COALESCE(SUM(output_tokens), 0), COALESCE(SUM(cost_usd), 0)
+		FROM dispatches
+		WHERE dispatched_at >= ? AND dispatched_at < ?
+		GROUP BY project, `+dimension+`
+		ORDER BY project, `+dimension,
+		from, to)
+}
+
+// GetActivityCosts totals the per-activity token usage recorded in
+// [from, to) by project and activity name (plan, execute, review).
+func (s *Store) GetActivityCosts(from, to time.Time) ([]CostLine, error) {
+	return s.queryCostLines(`
+		SELECT project, activity_name, COUNT(DISTINCT dispatch_id), COALESCE(SUM(input_tokens), 0), COALESCE(SUM(output_tokens), 0), COALESCE(SUM(cost_usd), 0)
+		FROM token_usage
+		WHERE recorded_at >= ? AND recorded_at < ?
+		GROUP BY project, activity_name
+		ORDER BY project, activity_name`,
+		from, to)
+}
+
+func (s *Store) queryCostLines(query string, from, to time.Time) ([]CostLine, error) {
+	rows, err := s.db.Query(query, from.UTC().Format(time.DateTime), to.UTC().Format(time.DateTime))
+	if err != nil {
+		return nil, fmt.Errorf("store: query costs: %w", err)
+	}
+	defer rows.Close()
+
+	var out []CostLine
+	for rows.Next() {
+		var l CostLine
+		if err := rows.Scan(&l.Project, &l.Key, &l.Dispatches, &l.InputTokens, &l.OutputTokens, &l.CostUSD); err != nil {
+			return nil, fmt.Errorf("store: scan cost line: %w", err)
+		}
+		out = append(out, l)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,77 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetDispatchAndActivityCosts(t *testing.T) {
+	s := tempStore(t)
+
+	now := time.Now().UTC()
+	for i, d := range []struct {
+		project, provider, tier string
+		cost                    float64
+		at                      time.Time
+	}{
+		{"alpha", "claude", "premium", 2.5, now},
+		{"alpha", "claude", "premium", 1.5, now},
+		{"alpha", "codex", "balanced", 0.5, now},
+		{"beta", "codex", "balanced", 1, now},
+		{"beta", "codex", "balanced", 9, now.AddDate(0, -2, 0)}, // outside the range
+	} {
+		id, err := s.RecordDispatch("b", d.project, "agent", d.provider, d.tier, i, "", "p", "", "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.RecordDispatchCost(id, 100, 10, d.cost); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.SetDispatchTime(id, d.at); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.StoreTokenUsage(id, "b", d.project, "execute", "agent", TokenUsage{InputTokens: 100, OutputTokens: 10, CostUSD: d.cost}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	from, to := now.Add(-time.Hour), now.Add(time.Hour)
+	lines, err := s.GetDispatchCosts("provider", from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []CostLine{
+		{Project: "alpha", Key: "claude", Dispatches: 2, InputTokens: 200, OutputTokens: 20, CostUSD: 4},
+		{Project: "alpha", Key: "codex", Dispatches: 1, InputTokens: 100, OutputTokens: 10, CostUSD: 0.5},
+		{Project: "beta", Key: "codex", Dispatches: 1, InputTokens: 100, OutputTokens: 10, CostUSD: 1},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("expected %d provider lines, got %+v", len(want), lines)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("line %d: expected %+v, got %+v", i, want[i], lines[i])
+		}
+	}
+
+	tiers, err := s.GetDispatchCosts("tier", from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tiers) != 3 || tiers[0].Key != "balanced" || tiers[1].Key != "premium" || tiers[1].CostUSD != 4 {
+		t.Fatalf("unexpected tier lines %+v", tiers)
+	}
+
+	if _, err := s.GetDispatchCosts("agent_id; DROP TABLE dispatches", from, to); err == nil {
+		t.Fatal("expected an unknown dimension to be rejected")
+	}
+
+	// token_usage rows are dated when recorded, so all five land in range.
+	activities, err := s.GetActivityCosts(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(activities) != 2 || activities[0].Key != "execute" || activities[0].Dispatches != 3 || activities[1].CostUSD != 10 {
+		t.Fatalf("unexpected activity lines %+v", activities)
+	}
+}
@@ -0,0 +1,64 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CoverageBaseline is the total coverage a project's DoD last accepted.
+type CoverageBaseline struct {
+	Project   string
+	Coverage  float64 // percent
+	BeadID    string  // bead whose DoD run set the baseline
+	UpdatedAt time.Time
+}
+
+// migrateCoverageBaselinesTable creates the coverage_baselines table. Called from migrate().
+func migrateCoverageBaselinesTable(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS coverage_baselines (
+			project TEXT PRIMARY KEY,
+			coverage REAL NOT NULL,
+			bead_id TEXT NOT NULL DEFAULT '',
+			updated_at DATETIME NOT NULL DEFAULT (datetime('now'))
+		)
+	`); err != nil {
+		return fmt.Errorf("create coverage_baselines table: %w", err)
+	}
+	return nil
+}
+
+// SetCoverageBaseline replaces the project's coverage baseline.
+func (s *Store) SetCoverageBaseline(project, beadID string, coverage float64) error {
+	_, err := s.db.Exec(`
+		INSERT INTO coverage_baselines (project, coverage, bead_id, updated_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(project) DO UPDATE SET
+			coverage = excluded.coverage,
+			bead_id = excluded.bead_id,
+			updated_at = excluded.updated_at`,
+		project, coverage, beadID, time.Now().UTC().Format(time.DateTime),
+	)
+	if err != nil {
+		return fmt.Errorf("store: set coverage baseline: %w", err)
+	}
+	return nil
+}
+
+// GetCoverageBaseline returns the project's coverage baseline, or nil when
+// none has been recorded.
+func (s *Store) GetCoverageBaseline(project string) (*CoverageBaseline, error) {
+	var b CoverageBaseline
+	err := s.db.QueryRow(`
+		SELECT project, coverage, bead_id, updated_at FROM coverage_baselines WHERE project = ?`,
+		project,
+	).Scan(&b.Project, &b.Coverage, &b.BeadID, &b.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("store: get coverage baseline: %w", err)
+	}
+	return &b, nil
+}
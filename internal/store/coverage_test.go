@@ -0,0 +1,28 @@
+package store
+
+import "testing"
+
+func TestCoverageBaseline(t *testing.T) {
+	s := tempStore(t)
+
+	if b, err := s.GetCoverageBaseline("proj"); err != nil || b != nil {
+		t.Fatalf("expected no baseline, got %+v, %v", b, err)
+	}
+	if err := s.SetCoverageBaseline("proj", "cx-1", 81.5); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetCoverageBaseline("proj", "cx-2", 79.25); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetCoverageBaseline("other", "cx-3", 50); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := s.GetCoverageBaseline("proj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Coverage != 79.25 || b.BeadID != "cx-2" || b.UpdatedAt.IsZero() {
+		t.Fatalf("unexpected baseline %+v", b)
+	}
+}
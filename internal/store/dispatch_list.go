@@ -0,0 +1,133 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sort orders ListDispatches accepts.
+const (
+	DispatchSortDispatchedAt = "dispatched_at"
+	DispatchSortDuration     = "duration_s"
+	DispatchSortCost         = "cost_usd"
+)
+
+// MaxDispatchListLimit caps the page size of ListDispatches.
+const MaxDispatchListLimit = 500
+
+// DispatchListOptions filters, orders and pages ListDispatches. Empty filter
+// fields and a zero From or To do not filter.
+type DispatchListOptions struct {
+	Project         string
+	Status          string
+	Agent           string
+	Tier            string
+	FailureCategory string
+	From            time.Time // dispatched at or after
+	To              time.Time // dispatched before
+
+	Sort   string // one of the DispatchSort constants; defaults to dispatched_at
+	Desc   bool
+	Limit  int    // defaults to 50, at most MaxDispatchListLimit
+	Cursor string // NextCursor of the previous page; empty for the first
+}
+
+// DispatchPage is one page of ListDispatches. NextCursor is empty on the last
+// page.
+type DispatchPage struct {
+	Dispatches []Dispatch
+	NextCursor string
+}
+
+func migrateDispatchListIndexes(db *sql.DB) error {
+	for _, stmt := range []string{
+		`CREATE INDEX IF NOT EXISTS idx_dispatches_dispatched_at ON dispatches(dispatched_at, id)`,
+		`CREATE INDEX IF NOT EXISTS idx_dispatches_project_dispatched_at ON dispatches(project, dispatched_at, id)`,
+		`CREATE INDEX IF NOT EXISTS idx_dispatches_failure_category ON dispatches(failure_category, dispatched_at)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("create dispatch list index: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListDispatches returns one page of dispatches matching opts. Pages are
+// keyed on the sort column and then the dispatch ID, so a cursor stays valid
+// while new dispatches are recorded; the cursor is the ID of the page's last
+// dispatch.
+func (s *Store) ListDispatches(opts DispatchListOptions) (DispatchPage, error) {
+	col := opts.Sort
+	switch col {
+	case "":
+		col = DispatchSortDispatchedAt
+	case DispatchSortDispatchedAt, DispatchSortDuration, DispatchSortCost:
+	default:
+		return DispatchPage{}, fmt.Errorf("store: unknown dispatch sort %q", opts.Sort)
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > MaxDispatchListLimit {
+		limit = MaxDispatchListLimit
+	}
+
+	var where []string
+	var args []any
+	for _, f := range []struct{ col, value string }{
+		{"project", opts.Project},
+		{"status", opts.Status},
+		{"agent_id", opts.Agent},
+		{"tier", opts.Tier},
+		{"failure_category", opts.FailureCategory},
+	} {
+		if f.value != "" {
+			where = append(where, f.col+` = ?`)
+			args = append(args, f.value)
+		}
+	}
+	if !opts.From.IsZero() {
+		where = append(where, `dispatched_at >= ?`)
+		args = append(args, opts.From.UTC().Format(time.DateTime))
+	}
+	if !opts.To.IsZero() {
+		where = append(where, `dispatched_at < ?`)
+		args = append(args, opts.To.UTC().Format(time.DateTime))
+	}
+
+	cmp, dir := ">", "ASC"
+	if opts.Desc {
+		cmp, dir = "<", "DESC"
+	}
+	if opts.Cursor != "" {
+		after, err := strconv.ParseInt(opts.Cursor, 10, 64)
+		if err != nil {
+			return DispatchPage{}, fmt.Errorf("store: invalid dispatch cursor %q", opts.Cursor)
+		}
+		where = append(where, fmt.Sprintf(`(%[1]s, id) %[2]s ((SELECT %[1]s FROM dispatches WHERE id = ?), ?)`, col, cmp))
+		args = append(args, after, after)
+	}
+
+	query := `SELECT ` + dispatchCols + ` FROM dispatches`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, ` AND `)
+	}
+	// One extra row tells whether another page follows.
+	query += fmt.Sprintf(` ORDER BY %s %s, id %s LIMIT ?`, col, dir, dir)
+	args = append(args, limit+1)
+
+	dispatches, err := s.queryDispatches(query, args...)
+	if err != nil {
+		return DispatchPage{}, err
+	}
+	page := DispatchPage{Dispatches: dispatches}
+	if len(dispatches) > limit {
+		page.Dispatches = dispatches[:limit]
+		page.NextCursor = strconv.FormatInt(page.Dispatches[limit-1].ID, 10)
+	}
+	return page, nil
+}
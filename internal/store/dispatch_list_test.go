@@ -0,0 +1,70 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestListDispatches(t *testing.T) {
+	s := tempStore(t)
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var ids []int64
+	for i := 0; i < 5; i++ {
+		project := "alpha"
+		if i == 4 {
+			project = "beta"
+		}
+		id, err := s.RecordDispatch("cx-"+string(rune('a'+i)), project, "coder", "p", "fast", 1, "", "", "", "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.SetDispatchTime(id, base.Add(time.Duration(i)*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.UpdateDispatchStatus(id, "completed", 0, float64(10-i)); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+
+	// Newest first, two per page, walked to the end.
+	var got []int64
+	opts := DispatchListOptions{Project: "alpha", Desc: true, Limit: 2}
+	for pages := 0; ; pages++ {
+		page, err := s.ListDispatches(opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, d := range page.Dispatches {
+			got = append(got, d.ID)
+		}
+		if page.NextCursor == "" {
+			if pages != 1 {
+				t.Fatalf("walked %d pages, want 2", pages+1)
+			}
+			break
+		}
+		opts.Cursor = page.NextCursor
+	}
+	want := []int64{ids[3], ids[2], ids[1], ids[0]}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+
+	page, err := s.ListDispatches(DispatchListOptions{Sort: DispatchSortDuration, From: base.Add(2 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Dispatches) != 3 || page.Dispatches[0].ID != ids[4] || page.NextCursor != "" {
+		t.Fatalf("by duration from 14:00 = %+v", page)
+	}
+
+	if _, err := s.ListDispatches(DispatchListOptions{Sort: "prompt"}); err == nil {
+		t.Fatal("expected an error for an unknown sort")
+	}
+}
@@ -0,0 +1,90 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ReportedTest is a test command an agent says it ran.
+type ReportedTest struct {
+	Command string `json:"command"`
+	Passed  bool   `json:"passed"`
+}
+
+// DispatchReport is the completion block an agent emitted at the end of a
+// dispatch: what it changed, what it tested, how confident it is and what
+// it left for later.
+type DispatchReport struct {
+	DispatchID   int64          `json:"dispatch_id"`
+	BeadID       string         `json:"bead_id"`
+	Project      string         `json:"project"`
+	Agent        string         `json:"agent"`
+	FilesChanged []string       `json:"files_changed"`
+	TestsRun     []ReportedTest `json:"tests_run"`
+	Confidence   *float64       `json:"confidence,omitempty"` // nil when the agent gave none
+	FollowUps    []string       `json:"follow_ups"`
+	RecordedAt   time.Time      `json:"recorded_at"`
+}
+
+// migrateDispatchReportsTable creates the dispatch_reports table. Called from migrate().
+func migrateDispatchReportsTable(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS dispatch_reports (
+			dispatch_id INTEGER PRIMARY KEY,
+			bead_id TEXT NOT NULL DEFAULT '',
+			project TEXT NOT NULL DEFAULT '',
+			report TEXT NOT NULL,
+			recorded_at DATETIME NOT NULL DEFAULT (datetime('now'))
+		)
+	`); err != nil {
+		return fmt.Errorf("create dispatch_reports table: %w", err)
+	}
+	return nil
+}
+
+// RecordDispatchReport stores a dispatch's completion report, replacing any
+// report recorded for it before.
+func (s *Store) RecordDispatchReport(r DispatchReport) error {
+	if r.RecordedAt.IsZero() {
+		r.RecordedAt = time.Now()
+	}
+	r.RecordedAt = r.RecordedAt.UTC().Truncate(time.Second)
+	body, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("store: record dispatch report: encode: %w", err)
+	}
+	if _, err := s.db.Exec(`
+		INSERT INTO dispatch_reports (dispatch_id, bead_id, project, report, recorded_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (dispatch_id) DO UPDATE SET
+			bead_id = excluded.bead_id,
+			project = excluded.project,
+			report = excluded.report,
+			recorded_at = excluded.recorded_at`,
+		r.DispatchID, r.BeadID, r.Project, string(body), r.RecordedAt.Format(time.DateTime),
+	); err != nil {
+		return fmt.Errorf("store: record dispatch report: %w", err)
+	}
+	return nil
+}
+
+// GetDispatchReport returns a dispatch's completion report, or nil when the
+// agent emitted none.
+func (s *Store) GetDispatchReport(dispatchID int64) (*DispatchReport, error) {
+	var body string
+	err := s.db.QueryRow(`SELECT report FROM dispatch_reports WHERE dispatch_id = ?`, dispatchID).Scan(&body)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("store: get dispatch report: %w", err)
+	}
+	var r DispatchReport
+	if err := json.Unmarshal([]byte(body), &r); err != nil {
+		return nil, fmt.Errorf("store: decode dispatch report: %w", err)
+	}
+	return &r, nil
+}
@@ -0,0 +1,44 @@
+package store
+
+import "testing"
+
+func TestDispatchReports(t *testing.T) {
+	s := tempStore(t)
+
+	if r, err := s.GetDispatchReport(1); err != nil || r != nil {
+		t.Fatalf("GetDispatchReport before recording = %+v, %v; want nil", r, err)
+	}
+
+	confidence := 0.8
+	report := DispatchReport{
+		DispatchID:   1,
+		BeadID:       "cortex-1",
+		Project:      "cortex",
+		Agent:        "claude",
+		FilesChanged: []string{"internal/store/store.go"},
+		TestsRun:     []ReportedTest{{Command: "go test ./internal/store", Passed: true}},
+		Confidence:   &confidence,
+		FollowUps:    []string{"document the new table"},
+	}
+	if err := s.RecordDispatchReport(report); err != nil {
+		t.Fatalf("RecordDispatchReport: %v", err)
+	}
+	got, err := s.GetDispatchReport(1)
+	if err != nil || got == nil {
+		t.Fatalf("GetDispatchReport = %+v, %v", got, err)
+	}
+	if got.BeadID != "cortex-1" || len(got.FilesChanged) != 1 || len(got.TestsRun) != 1 || !got.TestsRun[0].Passed ||
+		got.Confidence == nil || *got.Confidence != 0.8 || len(got.FollowUps) != 1 || got.RecordedAt.IsZero() {
+		t.Fatalf("GetDispatchReport = %+v", got)
+	}
+
+	// A later report for the same dispatch replaces the first.
+	report.Confidence = nil
+	report.FollowUps = nil
+	if err := s.RecordDispatchReport(report); err != nil {
+		t.Fatalf("RecordDispatchReport again: %v", err)
+	}
+	if got, _ = s.GetDispatchReport(1); got == nil || got.Confidence != nil || len(got.FollowUps) != 0 {
+		t.Fatalf("replaced report = %+v", got)
+	}
+}
@@ -0,0 +1,118 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ExperimentRun is one dispatch's assignment to an experiment variant and,
+// once the workflow finishes, its outcome.
+type ExperimentRun struct {
+	ID          int64
+	Experiment  string
+	Variant     string
+	Role        string
+	BeadID      string
+	Project     string
+	DispatchID  int64
+	Completed   bool
+	Success     bool
+	DoDPassed   bool
+	DurationS   float64
+	CostUSD     float64
+	AssignedAt  time.Time
+	CompletedAt sql.NullTime
+}
+
+// migrateExperimentRunsTable creates the experiment_runs table. Called from migrate().
+func migrateExperimentRunsTable(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS experiment_runs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			experiment TEXT NOT NULL,
+			variant TEXT NOT NULL,
+			role TEXT NOT NULL DEFAULT '',
+			bead_id TEXT NOT NULL DEFAULT '',
+			project TEXT NOT NULL DEFAULT '',
+			dispatch_id INTEGER NOT NULL DEFAULT 0,
+			completed INTEGER NOT NULL DEFAULT 0,
+			success INTEGER NOT NULL DEFAULT 0,
+			dod_passed INTEGER NOT NULL DEFAULT 0,
+			duration_s REAL NOT NULL DEFAULT 0,
+			cost_usd REAL NOT NULL DEFAULT 0,
+			assigned_at DATETIME NOT NULL DEFAULT (datetime('now')),
+			completed_at DATETIME
+		)
+	`); err != nil {
+		return fmt.Errorf("create experiment_runs table: %w", err)
+	}
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_experiment_runs_experiment ON experiment_runs(experiment, variant, completed)`); err != nil {
+		return fmt.Errorf("create experiment_runs experiment index: %w", err)
+	}
+	return nil
+}
+
+// RecordExperimentAssignment records that a bead was assigned to a variant and
+// returns the run ID used to attach the outcome later.
+func (s *Store) RecordExperimentAssignment(experiment, variant, role, beadID, project string) (int64, error) {
+	res, err := s.db.Exec(
+		`INSERT INTO experiment_runs (experiment, variant, role, bead_id, project) VALUES (?, ?, ?, ?, ?)`,
+		experiment, variant, role, beadID, project,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("store: record experiment assignment: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+// CompleteExperimentRun stores the outcome of an assigned run.
+func (s *Store) CompleteExperimentRun(id, dispatchID int64, success, dodPassed bool, durationS, costUSD float64) error {
+	res, err := s.db.Exec(
+		`UPDATE experiment_runs
+		 SET dispatch_id = ?, completed = 1, success = ?, dod_passed = ?, duration_s = ?, cost_usd = ?, completed_at = datetime('now')
+		 WHERE id = ?`,
+		dispatchID, boolToInt(success), boolToInt(dodPassed), durationS, costUSD, id,
+	)
+	if err != nil {
+		return fmt.Errorf("store: complete experiment run %d: %w", id, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("store: complete experiment run %d: not found", id)
+	}
+	return nil
+}
+
+// ListExperimentRuns returns runs for an experiment (all experiments when
+// empty), oldest first.
+func (s *Store) ListExperimentRuns(experiment string) ([]ExperimentRun, error) {
+	query := `SELECT id, experiment, variant, role, bead_id, project, dispatch_id, completed, success, dod_passed,
+		duration_s, cost_usd, assigned_at, completed_at FROM experiment_runs`
+	args := []any{}
+	if experiment != "" {
+		query += ` WHERE experiment = ?`
+		args = append(args, experiment)
+	}
+	query += ` ORDER BY id`
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("store: list experiment runs: %w", err)
+	}
+	defer rows.Close()
+
+	var out []ExperimentRun
+	for rows.Next() {
+		var r ExperimentRun
+		var completed, success, dodPassed int
+		if err := rows.Scan(&r.ID, &r.Experiment, &r.Variant, &r.Role, &r.BeadID, &r.Project, &r.DispatchID,
+			&completed, &success, &dodPassed, &r.DurationS, &r.CostUSD, &r.AssignedAt, &r.CompletedAt); err != nil {
+			return nil, fmt.Errorf("store: scan experiment run: %w", err)
+		}
+		r.Completed = completed != 0
+		r.Success = success != 0
+		r.DoDPassed = dodPassed != 0
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
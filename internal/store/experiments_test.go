@@ -0,0 +1,51 @@
+package store
+
+import "testing"
+
+func TestExperimentRunsAssignAndComplete(t *testing.T) {
+	s := tempStore(t)
+
+	control, err := s.RecordExperimentAssignment("exec-prompt", "control", "coder", "bead-1", "cortex")
+	if err != nil {
+		t.Fatalf("RecordExperimentAssignment: %v", err)
+	}
+	if _, err := s.RecordExperimentAssignment("exec-prompt", "terse", "coder", "bead-2", "cortex"); err != nil {
+		t.Fatalf("RecordExperimentAssignment: %v", err)
+	}
+	if _, err := s.RecordExperimentAssignment("review-agent", "codex", "reviewer", "bead-1", "cortex"); err != nil {
+		t.Fatalf("RecordExperimentAssignment: %v", err)
+	}
+
+	if err := s.CompleteExperimentRun(control, 42, true, true, 120.5, 0.25); err != nil {
+		t.Fatalf("CompleteExperimentRun: %v", err)
+	}
+	if err := s.CompleteExperimentRun(9999, 1, true, true, 1, 1); err == nil {
+		t.Fatal("expected error completing unknown run")
+	}
+
+	runs, err := s.ListExperimentRuns("exec-prompt")
+	if err != nil {
+		t.Fatalf("ListExperimentRuns: %v", err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("expected 2 runs, got %d", len(runs))
+	}
+	got := runs[0]
+	if !got.Completed || !got.Success || !got.DoDPassed || got.DispatchID != 42 || got.DurationS != 120.5 || got.CostUSD != 0.25 {
+		t.Fatalf("unexpected completed run: %+v", got)
+	}
+	if !got.CompletedAt.Valid {
+		t.Fatal("expected completed_at to be set")
+	}
+	if runs[1].Completed || runs[1].Variant != "terse" {
+		t.Fatalf("unexpected pending run: %+v", runs[1])
+	}
+
+	all, err := s.ListExperimentRuns("")
+	if err != nil {
+		t.Fatalf("ListExperimentRuns all: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("expected 3 runs overall, got %d", len(all))
+	}
+}
@@ -0,0 +1,116 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"maps"
+)
+
+// Health event severities, lowest first.
+const (
+	HealthSeverityInfo     = "info"
+	HealthSeverityWarn     = "warn"
+	HealthSeverityCritical = "critical"
+)
+
+// HealthSeverities lists the health event severities, lowest first.
+var HealthSeverities = []string{HealthSeverityInfo, HealthSeverityWarn, HealthSeverityCritical}
+
+// healthEventSeverities is the built-in severity of each health event type
+// above info. Types not listed, including those reported by external
+// monitors, are info.
+var healthEventSeverities = map[string]string{
+	"gateway_critical":      HealthSeverityCritical,
+	"dispatch_session_gone": HealthSeverityCritical,
+	"escalation_required":   HealthSeverityCritical,
+	"ha_deposed":            HealthSeverityCritical,
+
+	"dependency_down":       HealthSeverityWarn,
+	"host_resources_low":    HealthSeverityWarn,
+	"health_check_failed":   HealthSeverityWarn,
+	"stage_sla_breach":      HealthSeverityWarn,
+	"queue_wait_slo_breach": HealthSeverityWarn,
+	"provider_probe_failed": HealthSeverityWarn,
+	"dod_check_killed":      HealthSeverityWarn,
+	"command_stuck":         HealthSeverityWarn,
+	"file_overlap":          HealthSeverityWarn,
+	"restart_reconcile":     HealthSeverityWarn,
+}
+
+// SetHealthSeverities overrides the built-in severity of the given event
+// types for events recorded from now on; nil restores the built-in ones.
+func (s *Store) SetHealthSeverities(overrides map[string]string) {
+	s.severityMu.Lock()
+	defer s.severityMu.Unlock()
+	s.severityOverrides = maps.Clone(overrides)
+}
+
+// HealthEventSeverity returns the severity events of eventType are recorded
+// with.
+func (s *Store) HealthEventSeverity(eventType string) string {
+	s.severityMu.RLock()
+	severity, ok := s.severityOverrides[eventType]
+	s.severityMu.RUnlock()
+	if ok {
+		return severity
+	}
+	if severity, ok := healthEventSeverities[eventType]; ok {
+		return severity
+	}
+	return HealthSeverityInfo
+}
+
+// migrateHealthEventSeverity adds the severity column to health_events and
+// gives existing events their type's built-in severity. Called from migrate().
+func migrateHealthEventSeverity(db *sql.DB) error {
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('health_events') WHERE name = 'severity'`).Scan(&count); err != nil {
+		return fmt.Errorf("check health_events severity column: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+	if _, err := db.Exec(`ALTER TABLE health_events ADD COLUMN severity TEXT NOT NULL DEFAULT 'info'`); err != nil {
+		return fmt.Errorf("add health_events severity column: %w", err)
+	}
+	for eventType, severity := range healthEventSeverities {
+		if _, err := db.Exec(`UPDATE health_events SET severity = ? WHERE event_type = ?`, severity, eventType); err != nil {
+			return fmt.Errorf("backfill health_events severity: %w", err)
+		}
+	}
+	return nil
+}
+
+// LatestHealthEventID returns the ID of the newest health event, or 0 when
+// there are none.
+func (s *Store) LatestHealthEventID() (int64, error) {
+	var id int64
+	if err := s.db.QueryRow(`SELECT COALESCE(MAX(id), 0) FROM health_events`).Scan(&id); err != nil {
+		return 0, fmt.Errorf("store: latest health event id: %w", err)
+	}
+	return id, nil
+}
+
+// GetHealthEventsAfter returns up to limit health events recorded after the
+// event with ID afterID, oldest first, so a reader can follow the stream.
+func (s *Store) GetHealthEventsAfter(afterID int64, limit int) ([]HealthEvent, error) {
+	rows, err := s.db.Query(`
+		SELECT id, event_type, details, dispatch_id, bead_id, source, project, severity, created_at
+		FROM health_events WHERE id > ? ORDER BY id LIMIT ?`,
+		afterID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("store: query health events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []HealthEvent
+	for rows.Next() {
+		var e HealthEvent
+		if err := rows.Scan(&e.ID, &e.EventType, &e.Details, &e.DispatchID, &e.BeadID, &e.Source, &e.Project, &e.Severity, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("store: scan health event: %w", err)
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,56 @@
+package store
+
+import "testing"
+
+func TestHealthEventSeverity(t *testing.T) {
+	s := tempStore(t)
+
+	if got := s.HealthEventSeverity("dependency_down"); got != HealthSeverityWarn {
+		t.Fatalf("dependency_down severity = %q", got)
+	}
+	if got := s.HealthEventSeverity("backup_done"); got != HealthSeverityInfo {
+		t.Fatalf("unknown type severity = %q", got)
+	}
+	s.SetHealthSeverities(map[string]string{"backup_done": HealthSeverityCritical})
+
+	if err := s.RecordHealthEvent("escalation_required", "bead needs a human"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RecordProjectHealthEvent("api", "health_check_ok", "api-smoke passing again"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.RecordHealthEvents([]HealthEvent{
+		{EventType: "backup_done", Source: "backup"},
+		{EventType: "disk_full", Source: "node", Severity: HealthSeverityWarn},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.RecordHealthEvents([]HealthEvent{{EventType: "disk_full", Source: "node", Severity: "page"}}); err == nil {
+		t.Fatal("expected unknown severity to be rejected")
+	}
+
+	events, err := s.GetHealthEventsAfter(0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{HealthSeverityCritical, HealthSeverityInfo, HealthSeverityCritical, HealthSeverityWarn}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d", len(events), len(want))
+	}
+	for i, e := range events {
+		if e.Severity != want[i] {
+			t.Errorf("event %d (%s) severity = %q, want %q", i, e.EventType, e.Severity, want[i])
+		}
+	}
+
+	latest, err := s.LatestHealthEventID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest != events[3].ID {
+		t.Fatalf("latest id = %d, want %d", latest, events[3].ID)
+	}
+	if rest, err := s.GetHealthEventsAfter(events[1].ID, 1); err != nil || len(rest) != 1 || rest[0].EventType != "backup_done" {
+		t.Fatalf("events after %d = %+v, %v", events[1].ID, rest, err)
+	}
+}
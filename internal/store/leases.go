@@ -0,0 +1,114 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// migrateLeasesTable creates leases, which holds expiring keys owned by one
+// cortex instance at a time, and slot_leases, which holds the coder and
+// reviewer slots dispatches hold. Called from migrate().
+func migrateLeasesTable(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS leases (
+			key TEXT PRIMARY KEY,
+			holder TEXT NOT NULL,
+			expires_at DATETIME NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("create leases table: %w", err)
+	}
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS slot_leases (
+			role TEXT NOT NULL,
+			bead_id TEXT NOT NULL,
+			expires_at DATETIME NOT NULL,
+			PRIMARY KEY (role, bead_id)
+		)
+	`); err != nil {
+		return fmt.Errorf("create slot_leases table: %w", err)
+	}
+	return nil
+}
+
+// AcquireLease gives key to holder for ttl when it is free, expired, or
+// already holder's, in which case the lease is renewed. It reports whether
+// holder now has the lease.
+func (s *Store) AcquireLease(key, holder string, ttl time.Duration) (bool, error) {
+	key, holder = strings.TrimSpace(key), strings.TrimSpace(holder)
+	if key == "" || holder == "" {
+		return false, fmt.Errorf("store: acquire lease: key and holder are required")
+	}
+	res, err := s.db.Exec(`
+		INSERT INTO leases (key, holder, expires_at) VALUES (?, ?, datetime('now', ?))
+		ON CONFLICT(key) DO UPDATE SET
+			holder = excluded.holder,
+			expires_at = excluded.expires_at
+		WHERE leases.holder = excluded.holder OR leases.expires_at <= datetime('now')
+	`, key, holder, fmt.Sprintf("%+d seconds", int(ttl.Seconds())))
+	if err != nil {
+		return false, fmt.Errorf("store: acquire lease: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("store: acquire lease: %w", err)
+	}
+	return n > 0, nil
+}
+
+// ReleaseLease frees key if holder has it.
+func (s *Store) ReleaseLease(key, holder string) error {
+	if _, err := s.db.Exec(`DELETE FROM leases WHERE key = ? AND holder = ?`, key, holder); err != nil {
+		return fmt.Errorf("store: release lease: %w", err)
+	}
+	return nil
+}
+
+// AcquireSlotLease takes a slot in role for beadID for ttl while fewer than
+// limit unexpired slots of role are held; a limit of 0 is unbounded. A bead
+// that already holds a slot keeps it and has it renewed. It reports whether
+// beadID holds the slot. Expired slots are dropped first, so a dispatch that
+// died without releasing its slot frees it once the lease lapses.
+func (s *Store) AcquireSlotLease(role, beadID string, limit int, ttl time.Duration) (bool, error) {
+	role, beadID = strings.TrimSpace(role), strings.TrimSpace(beadID)
+	if role == "" || beadID == "" {
+		return false, fmt.Errorf("store: acquire slot lease: role and bead id are required")
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("store: begin slot lease transaction: %w", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`DELETE FROM slot_leases WHERE expires_at <= datetime('now')`); err != nil {
+		return false, fmt.Errorf("store: expire slot leases: %w", err)
+	}
+	var held, total int
+	if err := tx.QueryRow(`
+		SELECT COALESCE(SUM(bead_id = ?), 0), COUNT(*) FROM slot_leases WHERE role = ?
+	`, beadID, role).Scan(&held, &total); err != nil {
+		return false, fmt.Errorf("store: count slot leases: %w", err)
+	}
+	if held == 0 && limit > 0 && total >= limit {
+		return false, nil
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO slot_leases (role, bead_id, expires_at) VALUES (?, ?, datetime('now', ?))
+		ON CONFLICT(role, bead_id) DO UPDATE SET expires_at = excluded.expires_at
+	`, role, beadID, fmt.Sprintf("%+d seconds", int(ttl.Seconds()))); err != nil {
+		return false, fmt.Errorf("store: acquire slot lease: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("store: commit slot lease: %w", err)
+	}
+	return true, nil
+}
+
+// ReleaseSlotLease frees beadID's slot in role.
+func (s *Store) ReleaseSlotLease(role, beadID string) error {
+	if _, err := s.db.Exec(`DELETE FROM slot_leases WHERE role = ? AND bead_id = ?`, role, beadID); err != nil {
+		return fmt.Errorf("store: release slot lease: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLeases(t *testing.T) {
+	s := tempStore(t)
+
+	if ok, err := s.AcquireLease("cx-1", "host-a", time.Minute); err != nil || !ok {
+		t.Fatalf("first acquire = %v, %v", ok, err)
+	}
+	if ok, err := s.AcquireLease("cx-1", "host-b", time.Minute); err != nil || ok {
+		t.Fatalf("acquire of a held lease = %v, %v", ok, err)
+	}
+	if ok, err := s.AcquireLease("cx-1", "host-a", time.Minute); err != nil || !ok {
+		t.Fatalf("renew = %v, %v", ok, err)
+	}
+
+	// Another holder's release leaves the lease alone.
+	if err := s.ReleaseLease("cx-1", "host-b"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := s.AcquireLease("cx-1", "host-b", time.Minute); ok {
+		t.Fatal("lease released by a non-holder")
+	}
+	if err := s.ReleaseLease("cx-1", "host-a"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := s.AcquireLease("cx-1", "host-b", time.Minute); err != nil || !ok {
+		t.Fatalf("acquire after release = %v, %v", ok, err)
+	}
+
+	// An expired lease can be taken over.
+	if ok, err := s.AcquireLease("cx-2", "host-a", -time.Minute); err != nil || !ok {
+		t.Fatalf("acquire = %v, %v", ok, err)
+	}
+	if ok, err := s.AcquireLease("cx-2", "host-b", time.Minute); err != nil || !ok {
+		t.Fatalf("takeover of expired lease = %v, %v", ok, err)
+	}
+}
+
+func TestSlotLeases(t *testing.T) {
+	s := tempStore(t)
+
+	for _, bead := range []string{"cx-1", "cx-1", "cx-2"} {
+		if ok, err := s.AcquireSlotLease("coder", bead, 2, time.Minute); err != nil || !ok {
+			t.Fatalf("acquire %s = %v, %v", bead, ok, err)
+		}
+	}
+	if ok, err := s.AcquireSlotLease("coder", "cx-3", 2, time.Minute); err != nil || ok {
+		t.Fatalf("acquire over the limit = %v, %v", ok, err)
+	}
+	if ok, err := s.AcquireSlotLease("reviewer", "cx-3", 2, time.Minute); err != nil || !ok {
+		t.Fatalf("reviewer slot = %v, %v", ok, err)
+	}
+	if err := s.ReleaseSlotLease("coder", "cx-1"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := s.AcquireSlotLease("coder", "cx-3", 2, time.Minute); !ok {
+		t.Fatal("released slot not handed out")
+	}
+
+	// A lapsed lease, as left by a dispatch that died holding it, frees its slot.
+	if _, err := s.db.Exec(`UPDATE slot_leases SET expires_at = datetime('now', '-1 seconds') WHERE bead_id = 'cx-2'`); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := s.AcquireSlotLease("coder", "cx-4", 2, time.Minute); !ok {
+		t.Fatal("expired slot not handed out")
+	}
+}
@@ -0,0 +1,129 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// OutputChunk is a piece of agent output appended while the agent runs.
+// Chunks are keyed by workflow because a dispatch row is only written once
+// the workflow records its outcome.
+type OutputChunk struct {
+	ID         int64
+	WorkflowID string
+	ActivityID string
+	Agent      string
+	Stream     string // "stdout" or "stderr"
+	Chunk      string
+	Redactions int // secrets redacted from Chunk
+	CapturedAt time.Time
+}
+
+// migrateOutputChunksTable creates the output_chunks table. Called from migrate().
+func migrateOutputChunksTable(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS output_chunks (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			workflow_id TEXT NOT NULL,
+			activity_id TEXT NOT NULL DEFAULT '',
+			agent TEXT NOT NULL DEFAULT '',
+			stream TEXT NOT NULL DEFAULT 'stdout',
+			chunk TEXT NOT NULL,
+			captured_at DATETIME NOT NULL DEFAULT (datetime('now'))
+		)
+	`); err != nil {
+		return fmt.Errorf("create output_chunks table: %w", err)
+	}
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_output_chunks_workflow ON output_chunks(workflow_id, id)`); err != nil {
+		return fmt.Errorf("create output_chunks workflow index: %w", err)
+	}
+	return nil
+}
+
+// AppendOutputChunk stores output a running agent printed since its last
+// chunk, after the redaction pass.
+func (s *Store) AppendOutputChunk(c OutputChunk) error {
+	if c.Stream == "" {
+		c.Stream = "stdout"
+	}
+	chunk, n := s.redact(c.Chunk)
+	_, err := s.db.Exec(`
+		INSERT INTO output_chunks (workflow_id, activity_id, agent, stream, chunk, redactions, captured_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		c.WorkflowID, c.ActivityID, c.Agent, c.Stream, chunk, c.Redactions+n, time.Now().UTC().Format(time.DateTime),
+	)
+	if err != nil {
+		return fmt.Errorf("store: append output chunk: %w", err)
+	}
+	return nil
+}
+
+// GetOutputChunks returns the chunks streamed for a workflow in the order
+// they were appended.
+func (s *Store) GetOutputChunks(workflowID string) ([]OutputChunk, error) {
+	rows, err := s.db.Query(`
+		SELECT id, workflow_id, activity_id, agent, stream, chunk, redactions, captured_at
+		FROM output_chunks WHERE workflow_id = ? ORDER BY id`,
+		workflowID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("store: get output chunks: %w", err)
+	}
+	defer rows.Close()
+
+	var out []OutputChunk
+	for rows.Next() {
+		var c OutputChunk
+		if err := rows.Scan(&c.ID, &c.WorkflowID, &c.ActivityID, &c.Agent, &c.Stream, &c.Chunk, &c.Redactions, &c.CapturedAt); err != nil {
+			return nil, fmt.Errorf("store: scan output chunk: %w", err)
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// CaptureStreamedOutput moves the chunks one activity of a workflow
+// streamed into the dispatch's captured output, then drops every chunk of
+// the workflow, since it has recorded its outcome. An empty activityID
+// captures the activity that streamed last. It reports whether there was any
+// output to move.
+func (s *Store) CaptureStreamedOutput(workflowID, activityID string, dispatchID int64) (bool, error) {
+	chunks, err := s.GetOutputChunks(workflowID)
+	if err != nil || len(chunks) == 0 {
+		return false, err
+	}
+	if activityID == "" {
+		activityID = chunks[len(chunks)-1].ActivityID
+	}
+	var b strings.Builder
+	redactions := 0
+	for _, c := range chunks {
+		if c.ActivityID != activityID {
+			continue
+		}
+		b.WriteString(c.Chunk)
+		redactions += c.Redactions
+	}
+	captured := b.Len() > 0
+	if captured {
+		if err := s.captureOutput(dispatchID, b.String(), redactions); err != nil {
+			return false, err
+		}
+	}
+	if _, err := s.db.Exec(`DELETE FROM output_chunks WHERE workflow_id = ? AND id <= ?`, workflowID, chunks[len(chunks)-1].ID); err != nil {
+		return captured, fmt.Errorf("store: delete output chunks: %w", err)
+	}
+	return captured, nil
+}
+
+// PruneOutputChunks deletes chunks streamed before cutoff, which workflows
+// that ended without recording an outcome leave behind, and returns how many
+// it deleted.
+func (s *Store) PruneOutputChunks(cutoff time.Time) (int64, error) {
+	res, err := s.db.Exec(`DELETE FROM output_chunks WHERE captured_at < ?`, cutoff.UTC().Format(time.DateTime))
+	if err != nil {
+		return 0, fmt.Errorf("store: prune output chunks: %w", err)
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,139 @@
+package store
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCaptureStreamedOutput(t *testing.T) {
+	s := tempStore(t)
+
+	for _, c := range []OutputChunk{
+		{WorkflowID: "wf-1", ActivityID: "5", Agent: "codex", Chunk: "step 1\n"},
+		{WorkflowID: "wf-1", ActivityID: "5", Agent: "codex", Stream: "stderr", Chunk: "warning\n"},
+		{WorkflowID: "wf-2", ActivityID: "5", Agent: "codex", Chunk: "other workflow\n"},
+		{WorkflowID: "wf-1", ActivityID: "5", Agent: "codex", Chunk: "step 2\n"},
+		{WorkflowID: "wf-1", ActivityID: "11", Agent: "claude", Chunk: "review notes\n"},
+	} {
+		if err := s.AppendOutputChunk(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	chunks, err := s.GetOutputChunks("wf-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) != 4 || chunks[0].Stream != "stdout" || chunks[1].Stream != "stderr" || chunks[2].Chunk != "step 2\n" {
+		t.Fatalf("chunks = %+v", chunks)
+	}
+
+	id, err := s.RecordDispatch("b-1", "p", "agent", "prov", "fast", 0, "", "prompt", "", "", "temporal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	moved, err := s.CaptureStreamedOutput("wf-1", "5", id)
+	if err != nil || !moved {
+		t.Fatalf("CaptureStreamedOutput = %v, %v", moved, err)
+	}
+	out, err := s.GetOutput(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "step 1\nwarning\nstep 2\n" {
+		t.Errorf("output = %q", out)
+	}
+
+	if chunks, _ := s.GetOutputChunks("wf-1"); len(chunks) != 0 {
+		t.Errorf("chunks left after capture = %+v", chunks)
+	}
+	if chunks, _ := s.GetOutputChunks("wf-2"); len(chunks) != 1 {
+		t.Errorf("other workflow's chunks = %+v", chunks)
+	}
+	if moved, err := s.CaptureStreamedOutput("wf-1", "", id); err != nil || moved {
+		t.Errorf("second capture = %v, %v", moved, err)
+	}
+}
+
+func TestCaptureStreamedOutputDefaultsToLastActivity(t *testing.T) {
+	s := tempStore(t)
+	for _, c := range []OutputChunk{
+		{WorkflowID: "wf-1", ActivityID: "5", Chunk: "plan\n"},
+		{WorkflowID: "wf-1", ActivityID: "11", Chunk: "execute\n"},
+	} {
+		if err := s.AppendOutputChunk(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	id, err := s.RecordDispatch("b-1", "p", "agent", "prov", "fast", 0, "", "prompt", "", "", "temporal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved, err := s.CaptureStreamedOutput("wf-1", "", id); err != nil || !moved {
+		t.Fatalf("CaptureStreamedOutput = %v, %v", moved, err)
+	}
+	if out, _ := s.GetOutput(id); out != "execute\n" {
+		t.Errorf("output = %q, want only the last activity's", out)
+	}
+}
+
+func TestPruneOutputChunks(t *testing.T) {
+	s := tempStore(t)
+	if err := s.AppendOutputChunk(OutputChunk{WorkflowID: "abandoned", Chunk: "partial\n"}); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := s.PruneOutputChunks(time.Now().Add(-time.Hour)); err != nil || n != 0 {
+		t.Fatalf("prune before the chunk = %d, %v", n, err)
+	}
+	if n, err := s.PruneOutputChunks(time.Now().Add(time.Hour)); err != nil || n != 1 {
+		t.Fatalf("prune after the chunk = %d, %v", n, err)
+	}
+	if chunks, _ := s.GetOutputChunks("abandoned"); len(chunks) != 0 {
+		t.Errorf("chunks left after prune = %+v", chunks)
+	}
+}
+
+func TestOutputRedaction(t *testing.T) {
+	s := tempStore(t)
+	s.SetOutputRedactor(func(out string) (string, int) {
+		n := strings.Count(out, "hunter2")
+		return strings.ReplaceAll(out, "hunter2", "[REDACTED]"), n
+	})
+
+	if err := s.AppendOutputChunk(OutputChunk{WorkflowID: "wf-1", Chunk: "password hunter2\n"}); err != nil {
+		t.Fatal(err)
+	}
+	chunks, err := s.GetOutputChunks("wf-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) != 1 || chunks[0].Chunk != "password [REDACTED]\n" || chunks[0].Redactions != 1 {
+		t.Fatalf("chunks = %+v", chunks)
+	}
+
+	id, err := s.RecordDispatch("b-1", "p", "agent", "prov", "fast", 0, "", "prompt", "", "", "temporal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CaptureStreamedOutput("wf-1", "", id); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CaptureOutput(id, "again hunter2 and hunter2\n"); err != nil {
+		t.Fatal(err)
+	}
+	out, err := s.GetOutput(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out, "hunter2") {
+		t.Errorf("output kept the secret: %q", out)
+	}
+	n, err := s.GetOutputRedactions(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("redactions = %d, want 3", n)
+	}
+}
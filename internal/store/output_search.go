@@ -0,0 +1,107 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// OutputMatch is a dispatch whose captured output matched a search.
+type OutputMatch struct {
+	DispatchID   int64     `json:"dispatch_id"`
+	BeadID       string    `json:"bead_id"`
+	Project      string    `json:"project"`
+	AgentID      string    `json:"agent_id"`
+	Provider     string    `json:"provider"`
+	Status       string    `json:"status"`
+	DispatchedAt time.Time `json:"dispatched_at"`
+	Snippet      string    `json:"snippet"` // matched terms wrapped in [ ]
+}
+
+// migrateDispatchOutputFTS creates the FTS5 index over dispatch_output and
+// the triggers keeping it in sync, and indexes existing output the first
+// time. Called from migrate().
+func migrateDispatchOutputFTS(db *sql.DB) error {
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = 'dispatch_output_fts'`).Scan(&count); err != nil {
+		return fmt.Errorf("check dispatch_output_fts table: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+	for _, stmt := range []string{
+		`CREATE VIRTUAL TABLE dispatch_output_fts USING fts5(output, content='dispatch_output', content_rowid='id')`,
+		`CREATE TRIGGER IF NOT EXISTS dispatch_output_fts_insert AFTER INSERT ON dispatch_output BEGIN
+			INSERT INTO dispatch_output_fts(rowid, output) VALUES (new.id, new.output);
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS dispatch_output_fts_delete AFTER DELETE ON dispatch_output BEGIN
+			INSERT INTO dispatch_output_fts(dispatch_output_fts, rowid, output) VALUES ('delete', old.id, old.output);
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS dispatch_output_fts_update AFTER UPDATE ON dispatch_output BEGIN
+			INSERT INTO dispatch_output_fts(dispatch_output_fts, rowid, output) VALUES ('delete', old.id, old.output);
+			INSERT INTO dispatch_output_fts(rowid, output) VALUES (new.id, new.output);
+		END`,
+		`INSERT INTO dispatch_output_fts(dispatch_output_fts) VALUES ('rebuild')`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("create dispatch_output_fts: %w", err)
+		}
+	}
+	return nil
+}
+
+// ftsQuery turns a search string into an FTS5 query. A string wrapped in
+// double quotes is matched as one phrase; otherwise every whitespace
+// separated term must appear. Terms are quoted so punctuation in stack traces
+// and file paths is never read as FTS5 syntax.
+func ftsQuery(q string) string {
+	q = strings.TrimSpace(q)
+	quote := func(s string) string { return `"` + strings.ReplaceAll(s, `"`, `""`) + `"` }
+	if len(q) >= 2 && strings.HasPrefix(q, `"`) && strings.HasSuffix(q, `"`) {
+		return quote(q[1 : len(q)-1])
+	}
+	terms := strings.Fields(q)
+	for i, t := range terms {
+		terms[i] = quote(t)
+	}
+	return strings.Join(terms, " AND ")
+}
+
+// SearchOutputs finds dispatches whose captured output matches q, best match
+// first. An empty project searches every project.
+func (s *Store) SearchOutputs(q, project string, limit int) ([]OutputMatch, error) {
+	match := ftsQuery(q)
+	if match == "" || match == `""` {
+		return nil, fmt.Errorf("store: search outputs: query is empty")
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	rows, err := s.db.Query(
+		`SELECT d.id, d.bead_id, d.project, d.agent_id, d.provider, d.status, d.dispatched_at,
+		        snippet(dispatch_output_fts, 0, '[', ']', '...', 16)
+		 FROM dispatch_output_fts
+		 JOIN dispatch_output o ON o.id = dispatch_output_fts.rowid
+		 JOIN dispatches d ON d.id = o.dispatch_id
+		 WHERE dispatch_output_fts MATCH ? AND (? = '' OR d.project = ?)
+		 ORDER BY rank
+		 LIMIT ?`,
+		match, project, project, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("store: search outputs: %w", err)
+	}
+	defer rows.Close()
+
+	var out []OutputMatch
+	for rows.Next() {
+		var m OutputMatch
+		if err := rows.Scan(&m.DispatchID, &m.BeadID, &m.Project, &m.AgentID, &m.Provider, &m.Status, &m.DispatchedAt, &m.Snippet); err != nil {
+			return nil, fmt.Errorf("store: scan output match: %w", err)
+		}
+		m.DispatchedAt = m.DispatchedAt.UTC()
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
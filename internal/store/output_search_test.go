@@ -0,0 +1,100 @@
+package store
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSearchOutputs(t *testing.T) {
+	s := tempStore(t)
+
+	record := func(bead, project, output string) int64 {
+		t.Helper()
+		id, err := s.RecordDispatch(bead, project, "agent", "claude", "balanced", 1, "", "p", "", "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.CaptureOutput(id, output); err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+	panicked := record("cx-1", "alpha", "ok\npanic: runtime error: invalid memory address\ngoroutine 1 [running]:\ninternal/api/api.go:42")
+	record("cx-2", "alpha", "edited internal/store/store.go\nall tests pass")
+	other := record("cx-3", "beta", "panic: runtime error: index out of range")
+
+	matches, err := s.SearchOutputs("internal/api/api.go", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 || matches[0].DispatchID != panicked || matches[0].BeadID != "cx-1" {
+		t.Fatalf("expected the dispatch that touched api.go, got %+v", matches)
+	}
+	if !strings.Contains(matches[0].Snippet, "[internal/api/api.go]") {
+		t.Fatalf("expected the match highlighted, got %q", matches[0].Snippet)
+	}
+
+	matches, err = s.SearchOutputs("panic runtime", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("expected both panics, got %+v", matches)
+	}
+	matches, err = s.SearchOutputs("panic runtime", "beta", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 || matches[0].DispatchID != other {
+		t.Fatalf("expected only beta's panic, got %+v", matches)
+	}
+	matches, err = s.SearchOutputs(`"memory address goroutine"`, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 || matches[0].DispatchID != panicked {
+		t.Fatalf("expected the phrase match, got %+v", matches)
+	}
+	matches, err = s.SearchOutputs(`"address memory"`, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 0 {
+		t.Fatalf("expected no match for the reversed phrase, got %+v", matches)
+	}
+	if _, err := s.SearchOutputs("  ", "", 0); err == nil {
+		t.Fatal("expected an empty query to be rejected")
+	}
+}
+
+func TestMigrateDispatchOutputFTSIndexesExistingOutput(t *testing.T) {
+	s := tempStore(t)
+	id, err := s.RecordDispatch("cx-1", "alpha", "agent", "claude", "balanced", 1, "", "p", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range []string{
+		`DROP TRIGGER dispatch_output_fts_insert`,
+		`DROP TRIGGER dispatch_output_fts_delete`,
+		`DROP TRIGGER dispatch_output_fts_update`,
+		`DROP TABLE dispatch_output_fts`,
+	} {
+		if _, err := s.db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.CaptureOutput(id, "segmentation fault in parser.go"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := migrateDispatchOutputFTS(s.db.DB); err != nil {
+		t.Fatal(err)
+	}
+	matches, err := s.SearchOutputs("segmentation", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 || matches[0].DispatchID != id {
+		t.Fatalf("expected output captured before the index existed to be found, got %+v", matches)
+	}
+}
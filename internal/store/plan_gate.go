@@ -7,17 +7,50 @@ import (
 	"time"
 )
 
-// ExecutionPlanGate represents the currently active approved plan used to gate dispatches.
+// ExecutionPlanGate is an approved plan opening implementation dispatch
+// for one project, or for one epic of it.
 type ExecutionPlanGate struct {
-	PlanID      string
-	ApprovedBy  string
-	ApprovedAt  time.Time
-	ActivatedAt time.Time
-	UpdatedAt   time.Time
+	Project     string    `json:"project"`
+	EpicID      string    `json:"epic_id,omitempty"` // empty for a project-wide gate
+	PlanID      string    `json:"plan_id"`
+	ApprovedBy  string    `json:"approved_by"`
+	ApprovedAt  time.Time `json:"approved_at"`
+	ActivatedAt time.Time `json:"activated_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
 }
 
-// SetActiveApprovedPlan activates the given approved plan for execution.
-func (s *Store) SetActiveApprovedPlan(planID, approvedBy string) error {
+// migrateExecutionPlanGates creates the per-project execution_plan_gates
+// table. The old single global gate cannot be mapped to a project, so it is
+// dropped and plans must be activated again per project. Called from
+// migrate().
+func migrateExecutionPlanGates(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS execution_plan_gates (
+			project TEXT NOT NULL,
+			epic_id TEXT NOT NULL DEFAULT '',
+			plan_id TEXT NOT NULL,
+			approved_by TEXT NOT NULL DEFAULT '',
+			approved_at DATETIME NOT NULL DEFAULT (datetime('now')),
+			activated_at DATETIME NOT NULL DEFAULT (datetime('now')),
+			updated_at DATETIME NOT NULL DEFAULT (datetime('now')),
+			PRIMARY KEY (project, epic_id)
+		)
+	`); err != nil {
+		return fmt.Errorf("create execution_plan_gates table: %w", err)
+	}
+	if _, err := db.Exec(`DROP TABLE IF EXISTS execution_plan_gate`); err != nil {
+		return fmt.Errorf("drop global execution_plan_gate table: %w", err)
+	}
+	return nil
+}
+
+// SetActiveApprovedPlan activates an approved plan for project, or only for
+// the epic's beads when epicID is set. It replaces the plan of that scope.
+func (s *Store) SetActiveApprovedPlan(project, epicID, planID, approvedBy string) error {
+	project = strings.TrimSpace(project)
+	if project == "" {
+		return fmt.Errorf("project is required")
+	}
 	planID = strings.TrimSpace(planID)
 	if planID == "" {
 		return fmt.Errorf("plan id is required")
@@ -28,89 +61,91 @@ func (s *Store) SetActiveApprovedPlan(planID, approvedBy string) error {
 	}
 
 	_, err := s.db.Exec(`
-		INSERT INTO execution_plan_gate (
-			id, active_plan_id, approved_by, approved_at, activated_at, updated_at
+		INSERT INTO execution_plan_gates (
+			project, epic_id, plan_id, approved_by, approved_at, activated_at, updated_at
 		) VALUES (
-			1, ?, ?, datetime('now'), datetime('now'), datetime('now')
+			?, ?, ?, ?, datetime('now'), datetime('now'), datetime('now')
 		)
-		ON CONFLICT(id) DO UPDATE SET
-			active_plan_id = excluded.active_plan_id,
+		ON CONFLICT(project, epic_id) DO UPDATE SET
+			plan_id = excluded.plan_id,
 			approved_by = excluded.approved_by,
 			approved_at = excluded.approved_at,
 			activated_at = excluded.activated_at,
 			updated_at = excluded.updated_at
-	`, planID, approvedBy)
+	`, project, strings.TrimSpace(epicID), planID, approvedBy)
 	if err != nil {
 		return fmt.Errorf("store: set active approved plan: %w", err)
 	}
 	return nil
 }
 
-// ClearActiveApprovedPlan clears the currently active plan gate.
-func (s *Store) ClearActiveApprovedPlan() error {
-	_, err := s.db.Exec(`
-		INSERT INTO execution_plan_gate (
-			id, active_plan_id, approved_by, approved_at, activated_at, updated_at
-		) VALUES (
-			1, '', '', NULL, NULL, datetime('now')
-		)
-		ON CONFLICT(id) DO UPDATE SET
-			active_plan_id = excluded.active_plan_id,
-			approved_by = excluded.approved_by,
-			approved_at = NULL,
-			activated_at = NULL,
-			updated_at = excluded.updated_at
-	`)
+// ClearActiveApprovedPlan clears the plan gate of one scope. It reports
+// whether a gate was active.
+func (s *Store) ClearActiveApprovedPlan(project, epicID string) (bool, error) {
+	res, err := s.db.Exec(`DELETE FROM execution_plan_gates WHERE project = ? AND epic_id = ?`,
+		strings.TrimSpace(project), strings.TrimSpace(epicID))
 	if err != nil {
-		return fmt.Errorf("store: clear active approved plan: %w", err)
+		return false, fmt.Errorf("store: clear active approved plan: %w", err)
 	}
-	return nil
+	n, _ := res.RowsAffected()
+	return n > 0, nil
 }
 
-// GetActiveApprovedPlan returns the current active approved plan, or nil when no gate is active.
-func (s *Store) GetActiveApprovedPlan() (*ExecutionPlanGate, error) {
-	var (
-		planID      string
-		approvedBy  string
-		approvedAt  sql.NullTime
-		activatedAt sql.NullTime
-		updatedAt   time.Time
-	)
+const planGateCols = `project, epic_id, plan_id, approved_by, approved_at, activated_at, updated_at`
+
+func scanPlanGate(sc interface{ Scan(...any) error }) (*ExecutionPlanGate, error) {
+	var g ExecutionPlanGate
+	if err := sc.Scan(&g.Project, &g.EpicID, &g.PlanID, &g.ApprovedBy, &g.ApprovedAt, &g.ActivatedAt, &g.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
 
-	err := s.db.QueryRow(`
-		SELECT active_plan_id, approved_by, approved_at, activated_at, updated_at
-		FROM execution_plan_gate
-		WHERE id = 1
-	`).Scan(&planID, &approvedBy, &approvedAt, &activatedAt, &updatedAt)
+// GetActiveApprovedPlan returns the plan gate of exactly one scope, or nil
+// when none is active. An empty epicID selects the project-wide gate.
+func (s *Store) GetActiveApprovedPlan(project, epicID string) (*ExecutionPlanGate, error) {
+	g, err := scanPlanGate(s.db.QueryRow(`SELECT `+planGateCols+` FROM execution_plan_gates WHERE project = ? AND epic_id = ?`,
+		strings.TrimSpace(project), strings.TrimSpace(epicID)))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("store: get active approved plan: %w", err)
 	}
+	return g, nil
+}
 
-	planID = strings.TrimSpace(planID)
-	if planID == "" || !approvedAt.Valid {
-		return nil, nil
+// ListActiveApprovedPlans returns every active plan gate, by project and
+// then epic, with each project-wide gate first.
+func (s *Store) ListActiveApprovedPlans() ([]ExecutionPlanGate, error) {
+	rows, err := s.db.Query(`SELECT ` + planGateCols + ` FROM execution_plan_gates ORDER BY project, epic_id`)
+	if err != nil {
+		return nil, fmt.Errorf("store: list active approved plans: %w", err)
 	}
-
-	activated := approvedAt.Time
-	if activatedAt.Valid {
-		activated = activatedAt.Time
+	defer rows.Close()
+	var out []ExecutionPlanGate
+	for rows.Next() {
+		g, err := scanPlanGate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("store: scan active approved plan: %w", err)
+		}
+		out = append(out, *g)
 	}
-
-	return &ExecutionPlanGate{
-		PlanID:      planID,
-		ApprovedBy:  strings.TrimSpace(approvedBy),
-		ApprovedAt:  approvedAt.Time,
-		ActivatedAt: activated,
-		UpdatedAt:   updatedAt,
-	}, nil
+	return out, rows.Err()
 }
 
-// HasActiveApprovedPlan returns whether execution currently has an active approved plan.
-func (s *Store) HasActiveApprovedPlan() (bool, *ExecutionPlanGate, error) {
-	plan, err := s.GetActiveApprovedPlan()
+// HasActiveApprovedPlan returns whether an approved plan opens dispatch for
+// a bead of project under epicID: the project-wide gate, or else the epic's
+// own gate. A plan for another project never counts.
+func (s *Store) HasActiveApprovedPlan(project, epicID string) (bool, *ExecutionPlanGate, error) {
+	plan, err := s.GetActiveApprovedPlan(project, "")
+	if err != nil || plan != nil {
+		return plan != nil, plan, err
+	}
+	if strings.TrimSpace(epicID) == "" {
+		return false, nil, nil
+	}
+	plan, err = s.GetActiveApprovedPlan(project, epicID)
 	if err != nil {
 		return false, nil, err
 	}
@@ -5,7 +5,7 @@ import "testing"
 func TestExecutionPlanGateLifecycle(t *testing.T) {
 	s := tempStore(t)
 
-	active, plan, err := s.HasActiveApprovedPlan()
+	active, plan, err := s.HasActiveApprovedPlan("cortex", "")
 	if err != nil {
 		t.Fatalf("HasActiveApprovedPlan failed: %v", err)
 	}
@@ -16,11 +16,11 @@ func TestExecutionPlanGateLifecycle(t *testing.T) {
 		t.Fatalf("expected nil plan initially")
 	}
 
-	if err := s.SetActiveApprovedPlan("plan-2026-02-18-a", "simon"); err != nil {
+	if err := s.SetActiveApprovedPlan("cortex", "", "plan-2026-02-18-a", "simon"); err != nil {
 		t.Fatalf("SetActiveApprovedPlan failed: %v", err)
 	}
 
-	active, plan, err = s.HasActiveApprovedPlan()
+	active, plan, err = s.HasActiveApprovedPlan("cortex", "")
 	if err != nil {
 		t.Fatalf("HasActiveApprovedPlan after set failed: %v", err)
 	}
@@ -43,11 +43,15 @@ func TestExecutionPlanGateLifecycle(t *testing.T) {
 		t.Fatalf("expected activated_at to be populated")
 	}
 
-	if err := s.ClearActiveApprovedPlan(); err != nil {
+	cleared, err := s.ClearActiveApprovedPlan("cortex", "")
+	if err != nil {
 		t.Fatalf("ClearActiveApprovedPlan failed: %v", err)
 	}
+	if !cleared {
+		t.Fatalf("expected clear to report the active gate")
+	}
 
-	active, plan, err = s.HasActiveApprovedPlan()
+	active, plan, err = s.HasActiveApprovedPlan("cortex", "")
 	if err != nil {
 		t.Fatalf("HasActiveApprovedPlan after clear failed: %v", err)
 	}
@@ -62,7 +66,54 @@ func TestExecutionPlanGateLifecycle(t *testing.T) {
 func TestExecutionPlanGateRejectsEmptyPlanID(t *testing.T) {
 	s := tempStore(t)
 
-	if err := s.SetActiveApprovedPlan("   ", "simon"); err == nil {
+	if err := s.SetActiveApprovedPlan("cortex", "", "   ", "simon"); err == nil {
 		t.Fatalf("expected error for empty plan id")
 	}
+	if err := s.SetActiveApprovedPlan(" ", "", "plan-a", "simon"); err == nil {
+		t.Fatalf("expected error for empty project")
+	}
+}
+
+func TestExecutionPlanGateScopes(t *testing.T) {
+	s := tempStore(t)
+
+	if err := s.SetActiveApprovedPlan("alpha", "", "plan-alpha", "simon"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetActiveApprovedPlan("beta", "beta-epic-1", "plan-beta-1", "simon"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A plan for one project never opens another.
+	if active, _, err := s.HasActiveApprovedPlan("beta", ""); err != nil || active {
+		t.Fatalf("beta without epic: active = %v, err = %v", active, err)
+	}
+	if active, _, err := s.HasActiveApprovedPlan("gamma", "beta-epic-1"); err != nil || active {
+		t.Fatalf("gamma: active = %v, err = %v", active, err)
+	}
+
+	// An epic gate only opens that epic's beads.
+	if active, plan, err := s.HasActiveApprovedPlan("beta", "beta-epic-1"); err != nil || !active || plan.PlanID != "plan-beta-1" {
+		t.Fatalf("beta-epic-1: active = %v, plan = %+v, err = %v", active, plan, err)
+	}
+	if active, _, err := s.HasActiveApprovedPlan("beta", "beta-epic-2"); err != nil || active {
+		t.Fatalf("beta-epic-2: active = %v, err = %v", active, err)
+	}
+
+	// A project-wide gate covers every epic.
+	if active, plan, err := s.HasActiveApprovedPlan("alpha", "alpha-epic-9"); err != nil || !active || plan.PlanID != "plan-alpha" {
+		t.Fatalf("alpha-epic-9: active = %v, plan = %+v, err = %v", active, plan, err)
+	}
+
+	plans, err := s.ListActiveApprovedPlans()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plans) != 2 || plans[0].Project != "alpha" || plans[1].EpicID != "beta-epic-1" {
+		t.Fatalf("unexpected plans %+v", plans)
+	}
+
+	if cleared, err := s.ClearActiveApprovedPlan("beta", "beta-epic-2"); err != nil || cleared {
+		t.Fatalf("clearing an inactive scope: cleared = %v, err = %v", cleared, err)
+	}
 }
@@ -0,0 +1,71 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ProjectPause records that new dispatches for a project are on hold.
+type ProjectPause struct {
+	Project  string
+	PausedBy string
+	PausedAt time.Time
+}
+
+// migrateProjectPausesTable creates the project_pauses table. Called from migrate().
+func migrateProjectPausesTable(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS project_pauses (
+			project TEXT PRIMARY KEY,
+			paused_by TEXT NOT NULL DEFAULT '',
+			paused_at DATETIME NOT NULL DEFAULT (datetime('now'))
+		)
+	`); err != nil {
+		return fmt.Errorf("create project_pauses table: %w", err)
+	}
+	return nil
+}
+
+// PauseProject holds new dispatches for a project until ResumeProject.
+// Pausing an already paused project keeps the original pause.
+func (s *Store) PauseProject(project, pausedBy string) error {
+	_, err := s.db.Exec(`
+		INSERT OR IGNORE INTO project_pauses (project, paused_by, paused_at) VALUES (?, ?, ?)`,
+		project, pausedBy, time.Now().UTC().Format(time.DateTime),
+	)
+	if err != nil {
+		return fmt.Errorf("store: pause project: %w", err)
+	}
+	return nil
+}
+
+// ResumeProject lifts a project's pause and reports whether it was paused.
+func (s *Store) ResumeProject(project string) (bool, error) {
+	res, err := s.db.Exec(`DELETE FROM project_pauses WHERE project = ?`, project)
+	if err != nil {
+		return false, fmt.Errorf("store: resume project: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("store: resume project: %w", err)
+	}
+	return n > 0, nil
+}
+
+// GetProjectPause returns the project's pause, or nil when it is not paused.
+func (s *Store) GetProjectPause(project string) (*ProjectPause, error) {
+	var p ProjectPause
+	err := s.db.QueryRow(`
+		SELECT project, paused_by, paused_at FROM project_pauses WHERE project = ?`,
+		project,
+	).Scan(&p.Project, &p.PausedBy, &p.PausedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("store: get project pause: %w", err)
+	}
+	return &p, nil
+}
@@ -0,0 +1,35 @@
+package store
+
+import "testing"
+
+func TestProjectPause(t *testing.T) {
+	s := tempStore(t)
+
+	if p, err := s.GetProjectPause("alpha"); err != nil || p != nil {
+		t.Fatalf("expected no pause, got %+v, %v", p, err)
+	}
+	if err := s.PauseProject("alpha", "@alice:example.org"); err != nil {
+		t.Fatal(err)
+	}
+	// A second pause keeps the first one's author.
+	if err := s.PauseProject("alpha", "@bob:example.org"); err != nil {
+		t.Fatal(err)
+	}
+	p, err := s.GetProjectPause("alpha")
+	if err != nil || p == nil || p.PausedBy != "@alice:example.org" || p.PausedAt.IsZero() {
+		t.Fatalf("unexpected pause: %+v, %v", p, err)
+	}
+	if p, _ := s.GetProjectPause("beta"); p != nil {
+		t.Fatalf("pause leaked to another project: %+v", p)
+	}
+
+	if resumed, err := s.ResumeProject("alpha"); err != nil || !resumed {
+		t.Fatalf("resume: %v, %v", resumed, err)
+	}
+	if resumed, err := s.ResumeProject("alpha"); err != nil || resumed {
+		t.Fatalf("second resume: %v, %v", resumed, err)
+	}
+	if p, _ := s.GetProjectPause("alpha"); p != nil {
+		t.Fatalf("expected pause lifted, got %+v", p)
+	}
+}
@@ -0,0 +1,305 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// promptDiffContext is how many unchanged lines surround each change in a
+// stored prompt diff.
+const promptDiffContext = 3
+
+// promptDiffMaxCells bounds the line-diff table (old lines x new lines).
+// Prompts past it are diffed as a whole-body replacement.
+const promptDiffMaxCells = 4_000_000
+
+// PromptAttempt is the coder prompt sent on one execution attempt of a
+// workflow, with the diff from the attempt before it.
+type PromptAttempt struct {
+	ID         int64
+	WorkflowID string
+	BeadID     string
+	Project    string
+	Attempt    int // 1-based execution attempt within the workflow
+	Agent      string
+	Provider   string
+	Tier       string
+	Prompt     string
+	Changes    []string // dispatch settings that differ from the previous attempt, e.g. "agent: claude -> codex"
+	Diff       string   // line diff against the previous attempt's prompt; empty for the first attempt
+	CreatedAt  time.Time
+}
+
+// migratePromptAttemptsTable creates the prompt_attempts table. Called from migrate().
+func migratePromptAttemptsTable(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS prompt_attempts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			workflow_id TEXT NOT NULL,
+			bead_id TEXT NOT NULL,
+			project TEXT NOT NULL DEFAULT '',
+			attempt INTEGER NOT NULL,
+			agent TEXT NOT NULL DEFAULT '',
+			provider TEXT NOT NULL DEFAULT '',
+			tier TEXT NOT NULL DEFAULT '',
+			prompt TEXT NOT NULL DEFAULT '',
+			changes TEXT NOT NULL DEFAULT '',
+			diff TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL DEFAULT (datetime('now')),
+			UNIQUE (workflow_id, attempt)
+		)
+	`); err != nil {
+		return fmt.Errorf("create prompt_attempts table: %w", err)
+	}
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_prompt_attempts_bead ON prompt_attempts(bead_id)`); err != nil {
+		return fmt.Errorf("create prompt_attempts index: %w", err)
+	}
+	return nil
+}
+
+// RecordPromptAttempt stores the prompt for one attempt, diffing it against
+// the workflow's previous attempt. Recording the same attempt again (an
+// activity retry) replaces it. Changes and Diff are computed here and
+// returned on the stored attempt.
+func (s *Store) RecordPromptAttempt(p PromptAttempt) (PromptAttempt, error) {
+	if p.Attempt > 1 {
+		prev, err := s.getPromptAttempt(p.WorkflowID, p.Attempt-1)
+		if err != nil {
+			return p, err
+		}
+		if prev != nil {
+			p.Changes = promptAttemptChanges(*prev, p)
+			p.Diff = LineDiff(prev.Prompt, p.Prompt)
+		}
+	}
+
+	err := s.db.QueryRow(
+		`INSERT INTO prompt_attempts (workflow_id, bead_id, project, attempt, agent, provider, tier, prompt, changes, diff)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(workflow_id, attempt) DO UPDATE SET
+			agent = excluded.agent, provider = excluded.provider, tier = excluded.tier,
+			prompt = excluded.prompt, changes = excluded.changes, diff = excluded.diff,
+			created_at = datetime('now')
+		 RETURNING id, created_at`,
+		p.WorkflowID, p.BeadID, p.Project, p.Attempt, p.Agent, p.Provider, p.Tier, p.Prompt,
+		strings.Join(p.Changes, "\n"), p.Diff,
+	).Scan(&p.ID, &p.CreatedAt)
+	if err != nil {
+		return p, fmt.Errorf("store: record prompt attempt: %w", err)
+	}
+	return p, nil
+}
+
+// GetPromptAttempts returns every recorded prompt attempt for a bead, grouped
+// by workflow in the order the workflows started, then by attempt.
+func (s *Store) GetPromptAttempts(beadID string) ([]PromptAttempt, error) {
+	rows, err := s.db.Query(
+		`SELECT `+promptAttemptCols+` FROM prompt_attempts p
+		 WHERE bead_id = ?
+		 ORDER BY (SELECT MIN(id) FROM prompt_attempts f WHERE f.workflow_id = p.workflow_id), attempt`,
+		beadID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("store: get prompt attempts: %w", err)
+	}
+	defer rows.Close()
+
+	var out []PromptAttempt
+	for rows.Next() {
+		p, err := scanPromptAttempt(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+const promptAttemptCols = `id, workflow_id, bead_id, project, attempt, agent, provider, tier, prompt, changes, diff, created_at`
+
+func (s *Store) getPromptAttempt(workflowID string, attempt int) (*PromptAttempt, error) {
+	p, err := scanPromptAttempt(s.db.QueryRow(
+		`SELECT `+promptAttemptCols+` FROM prompt_attempts WHERE workflow_id = ? AND attempt = ?`,
+		workflowID, attempt,
+	))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func scanPromptAttempt(r rowScanner) (PromptAttempt, error) {
+	var p PromptAttempt
+	var changes string
+	if err := r.Scan(&p.ID, &p.WorkflowID, &p.BeadID, &p.Project, &p.Attempt, &p.Agent, &p.Provider, &p.Tier,
+		&p.Prompt, &changes, &p.Diff, &p.CreatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return p, err
+		}
+		return p, fmt.Errorf("store: scan prompt attempt: %w", err)
+	}
+	if changes != "" {
+		p.Changes = strings.Split(changes, "\n")
+	}
+	return p, nil
+}
+
+func promptAttemptChanges(prev, cur PromptAttempt) []string {
+	var changes []string
+	for _, f := range []struct{ name, old, new string }{
+		{"agent", prev.Agent, cur.Agent},
+		{"provider", prev.Provider, cur.Provider},
+		{"tier", prev.Tier, cur.Tier},
+	} {
+		if f.old != f.new {
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", f.name, orNone(f.old), orNone(f.new)))
+		}
+	}
+	return changes
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+// LineDiff returns a unified-style line diff from a to b: removed lines are
+// prefixed "-", added lines "+", and unchanged context lines " ". Runs of
+// unchanged lines far from any change collapse into "@@ -i,n +j,m @@" hunk
+// headers. Identical inputs produce an empty diff.
+func LineDiff(a, b string) string {
+	if a == b {
+		return ""
+	}
+	oldLines, newLines := splitLines(a), splitLines(b)
+	ops := diffLines(oldLines, newLines)
+
+	// Group ops into hunks with promptDiffContext lines of context.
+	var sb strings.Builder
+	for start := 0; start < len(ops); {
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+		from := max(start-promptDiffContext, 0)
+		end := start
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == ' ' {
+				run++
+			}
+			if run == len(ops) || run-end > 2*promptDiffContext {
+				end = min(end+promptDiffContext, run)
+				break
+			}
+			end = run
+		}
+
+		oldStart, newStart := ops[from].oldLine, ops[from].newLine
+		var oldCount, newCount int
+		for _, op := range ops[from:end] {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", oldStart+1, oldCount, newStart+1, newCount)
+		for _, op := range ops[from:end] {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.text)
+			sb.WriteByte('\n')
+		}
+		start = end
+	}
+	return sb.String()
+}
+
+type diffOp struct {
+	kind    byte // ' ', '-' or '+'
+	text    string
+	oldLine int // index in the old text of this line, or of the next old line for '+'
+	newLine int // index in the new text of this line, or of the next new line for '-'
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines computes a minimal line edit script via longest common
+// subsequence, trimming the shared prefix and suffix first.
+func diffLines(a, b []string) []diffOp {
+	var ops []diffOp
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		ops = append(ops, diffOp{' ', a[prefix], prefix, prefix})
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	if len(ma)*len(mb) > promptDiffMaxCells {
+		for i, line := range ma {
+			ops = append(ops, diffOp{'-', line, prefix + i, prefix})
+		}
+		for j, line := range mb {
+			ops = append(ops, diffOp{'+', line, prefix + len(ma), prefix + j})
+		}
+	} else {
+		// lcs[i][j] is the LCS length of ma[i:] and mb[j:].
+		lcs := make([][]int, len(ma)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(mb)+1)
+		}
+		for i := len(ma) - 1; i >= 0; i-- {
+			for j := len(mb) - 1; j >= 0; j-- {
+				if ma[i] == mb[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < len(ma) || j < len(mb) {
+			switch {
+			case i < len(ma) && j < len(mb) && ma[i] == mb[j]:
+				ops = append(ops, diffOp{' ', ma[i], prefix + i, prefix + j})
+				i++
+				j++
+			case i < len(ma) && (j == len(mb) || lcs[i+1][j] >= lcs[i][j+1]):
+				ops = append(ops, diffOp{'-', ma[i], prefix + i, prefix + j})
+				i++
+			default:
+				ops = append(ops, diffOp{'+', mb[j], prefix + i, prefix + j})
+				j++
+			}
+		}
+	}
+
+	for k := 0; k < suffix; k++ {
+		i, j := len(a)-suffix+k, len(b)-suffix+k
+		ops = append(ops, diffOp{' ', a[i], i, j})
+	}
+	return ops
+}
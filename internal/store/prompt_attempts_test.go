@@ -0,0 +1,109 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLineDiff(t *testing.T) {
+	if d := LineDiff("same\n", "same\n"); d != "" {
+		t.Fatalf("identical inputs should not diff, got %q", d)
+	}
+
+	old := "TASK: fix it\n\nPLAN:\n1. edit\n\nImplement this plan now."
+	cur := "TASK: fix it\n\nPLAN:\n1. edit\n\nPREVIOUS ERRORS TO FIX:\nDoD check failures: go test\n\nImplement this plan now."
+	want := "@@ -3,4 +3,7 @@\n" +
+		" PLAN:\n 1. edit\n \n" +
+		"+PREVIOUS ERRORS TO FIX:\n+DoD check failures: go test\n+\n" +
+		" Implement this plan now.\n"
+	if got := LineDiff(old, cur); got != want {
+		t.Fatalf("unexpected diff:\n%s\nwant:\n%s", got, want)
+	}
+
+	if got := LineDiff("a\nb\nc", "a\nx\nc"); got != "@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n" {
+		t.Fatalf("unexpected replacement diff:\n%s", got)
+	}
+}
+
+func TestLineDiffSplitsDistantHunks(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 30; i++ {
+		a = append(a, fmt.Sprintf("line %d", i))
+		b = append(b, fmt.Sprintf("line %d", i))
+	}
+	b[2] = "changed 2"
+	b[25] = "changed 25"
+
+	got := LineDiff(strings.Join(a, "\n"), strings.Join(b, "\n"))
+	if n := strings.Count(got, "@@ -"); n != 2 {
+		t.Fatalf("expected 2 hunks, got %d:\n%s", n, got)
+	}
+	if !strings.Contains(got, "@@ -1,6 +1,6 @@\n line 0\n line 1\n-line 2\n+changed 2\n") ||
+		!strings.Contains(got, "@@ -23,7 +23,7 @@\n") {
+		t.Fatalf("unexpected hunks:\n%s", got)
+	}
+	if strings.Contains(got, "line 12") {
+		t.Fatalf("unchanged middle lines should be collapsed:\n%s", got)
+	}
+}
+
+func TestRecordPromptAttempts(t *testing.T) {
+	s := tempStore(t)
+
+	first, err := s.RecordPromptAttempt(PromptAttempt{
+		WorkflowID: "task-cx-1", BeadID: "cx-1", Project: "proj", Attempt: 1,
+		Agent: "claude", Tier: "temporal", Prompt: "TASK: x\nImplement.",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.ID == 0 || first.Diff != "" || first.Changes != nil {
+		t.Fatalf("first attempt should have no diff, got %+v", first)
+	}
+
+	second, err := s.RecordPromptAttempt(PromptAttempt{
+		WorkflowID: "task-cx-1", BeadID: "cx-1", Project: "proj", Attempt: 2,
+		Agent: "codex", Tier: "temporal", Prompt: "TASK: x\nPREVIOUS ERRORS TO FIX:\nboom\nImplement.",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(second.Diff, "+PREVIOUS ERRORS TO FIX:\n+boom\n") {
+		t.Fatalf("expected new failure info in diff, got %q", second.Diff)
+	}
+	if len(second.Changes) != 1 || second.Changes[0] != "agent: claude -> codex" {
+		t.Fatalf("unexpected changes %v", second.Changes)
+	}
+
+	// An activity retry of the same attempt replaces it.
+	if _, err := s.RecordPromptAttempt(PromptAttempt{
+		WorkflowID: "task-cx-1", BeadID: "cx-1", Project: "proj", Attempt: 2,
+		Agent: "codex", Tier: "temporal", Prompt: "TASK: x\nPREVIOUS ERRORS TO FIX:\nboom again\nImplement.",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// A later workflow for the same bead starts its own sequence.
+	if _, err := s.RecordPromptAttempt(PromptAttempt{
+		WorkflowID: "task-cx-1-b", BeadID: "cx-1", Project: "proj", Attempt: 1, Agent: "claude", Prompt: "TASK: y",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	attempts, err := s.GetPromptAttempts("cx-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attempts) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(attempts))
+	}
+	if attempts[0].Attempt != 1 || attempts[1].Attempt != 2 || attempts[2].WorkflowID != "task-cx-1-b" {
+		t.Fatalf("unexpected order: %+v", attempts)
+	}
+	if !strings.Contains(attempts[1].Diff, "+boom again") || attempts[1].Changes[0] != "agent: claude -> codex" {
+		t.Fatalf("retried attempt not replaced: %+v", attempts[1])
+	}
+	if attempts[2].Diff != "" {
+		t.Fatalf("new workflow should not diff against the old one: %+v", attempts[2])
+	}
+}
@@ -0,0 +1,101 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// QueueWaitStats is how long a role's work waited in the overflow queue.
+// Items still queued count with their wait so far, so a stuck queue shows
+// up before anything leaves it.
+type QueueWaitStats struct {
+	Role   string
+	Waits  int           // items dequeued in the window plus items still queued
+	Queued int           // items still queued
+	P50    time.Duration // median wait
+	P95    time.Duration
+	Oldest time.Duration // longest wait of an item still queued
+}
+
+// migrateOverflowWaitsTable creates the overflow_waits table. Called from migrate().
+func migrateOverflowWaitsTable(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS overflow_waits (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			bead_id TEXT NOT NULL,
+			project TEXT NOT NULL,
+			role TEXT NOT NULL,
+			enqueued_at DATETIME NOT NULL,
+			dequeued_at DATETIME NOT NULL DEFAULT (datetime('now')),
+			wait_seconds INTEGER NOT NULL DEFAULT 0
+		)
+	`); err != nil {
+		return fmt.Errorf("create overflow_waits table: %w", err)
+	}
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_overflow_waits_dequeued_at ON overflow_waits(dequeued_at)`); err != nil {
+		return fmt.Errorf("create overflow_waits dequeued_at index: %w", err)
+	}
+	return nil
+}
+
+// GetQueueWaitStats returns the overflow queue wait of each role over the
+// items dequeued since since and the items queued now, keyed by role.
+func (s *Store) GetQueueWaitStats(since, now time.Time) (map[string]QueueWaitStats, error) {
+	waits := make(map[string][]time.Duration)
+	stats := make(map[string]QueueWaitStats)
+
+	rows, err := s.db.Query(`SELECT role, wait_seconds FROM overflow_waits WHERE dequeued_at >= ?`,
+		since.UTC().Format(time.DateTime))
+	if err != nil {
+		return nil, fmt.Errorf("store: query overflow waits: %w", err)
+	}
+	for rows.Next() {
+		var role string
+		var seconds int64
+		if err := rows.Scan(&role, &seconds); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("store: scan overflow wait: %w", err)
+		}
+		waits[role] = append(waits[role], time.Duration(seconds)*time.Second)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("store: iterate overflow waits: %w", err)
+	}
+
+	queued, err := s.ListOverflowQueue()
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range queued {
+		wait := max(now.Sub(item.EnqueuedAt), 0).Truncate(time.Second)
+		waits[item.Role] = append(waits[item.Role], wait)
+		st := stats[item.Role]
+		st.Queued++
+		st.Oldest = max(st.Oldest, wait)
+		stats[item.Role] = st
+	}
+
+	for role, w := range waits {
+		slices.Sort(w)
+		st := stats[role]
+		st.Role = role
+		st.Waits = len(w)
+		st.P50 = nearestRank(w, 0.50)
+		st.P95 = nearestRank(w, 0.95)
+		stats[role] = st
+	}
+	return stats, nil
+}
+
+// nearestRank returns the p-th percentile of sorted values.
+func nearestRank(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(i, 0)]
+}
@@ -0,0 +1,59 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQueueWaitStats(t *testing.T) {
+	s := tempStore(t)
+	now := time.Now().UTC()
+
+	enqueue := func(bead, role string, ago time.Duration) {
+		t.Helper()
+		if _, err := s.EnqueueOverflowItem(bead, "proj", role, "agent", 1, "role_limit"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.db.Exec(`UPDATE overflow_queue SET enqueued_at = ? WHERE bead_id = ?`,
+			now.Add(-ago).Format(time.DateTime), bead); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Four coder waits of 1, 2, 3 and 20 minutes, and one reviewer item
+	// still queued for 8 minutes.
+	for i, ago := range []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute, 20 * time.Minute} {
+		bead := string(rune('a' + i))
+		enqueue(bead, "coder", ago)
+		if n, err := s.RemoveOverflowItem(bead); err != nil || n != 1 {
+			t.Fatalf("remove %s: %d, %v", bead, n, err)
+		}
+	}
+	enqueue("r", "reviewer", 8*time.Minute)
+
+	stats, err := s.GetQueueWaitStats(now.Add(-time.Hour), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coder := stats["coder"]
+	if coder.Waits != 4 || coder.Queued != 0 || coder.Oldest != 0 {
+		t.Fatalf("coder stats = %+v", coder)
+	}
+	near := func(got, want time.Duration) bool { return got >= want-2*time.Second && got <= want+2*time.Second }
+	if !near(coder.P50, 2*time.Minute) || !near(coder.P95, 20*time.Minute) {
+		t.Fatalf("coder percentiles = %s/%s, want 2m/20m", coder.P50, coder.P95)
+	}
+	reviewer := stats["reviewer"]
+	if reviewer.Waits != 1 || reviewer.Queued != 1 || !near(reviewer.Oldest, 8*time.Minute) || !near(reviewer.P95, 8*time.Minute) {
+		t.Fatalf("reviewer stats = %+v", reviewer)
+	}
+
+	// Waits dequeued before the window are left out.
+	stats, err = s.GetQueueWaitStats(now.Add(time.Minute), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := stats["coder"]; ok {
+		t.Fatalf("expected no coder waits in the window, got %+v", stats["coder"])
+	}
+}
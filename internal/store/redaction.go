@@ -0,0 +1,61 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// OutputRedactor replaces secrets in agent output and reports how many it
+// replaced.
+type OutputRedactor func(output string) (string, int)
+
+// SetOutputRedactor installs the redaction pass applied to agent output
+// before it is stored; nil stores output as given.
+func (s *Store) SetOutputRedactor(r OutputRedactor) {
+	s.redactMu.Lock()
+	defer s.redactMu.Unlock()
+	s.redactOutput = r
+}
+
+// RedactOutput applies the installed redaction pass to output, as
+// AppendOutputChunk does before storing it.
+func (s *Store) RedactOutput(output string) (string, int) {
+	return s.redact(output)
+}
+
+func (s *Store) redact(output string) (string, int) {
+	s.redactMu.RLock()
+	r := s.redactOutput
+	s.redactMu.RUnlock()
+	if r == nil {
+		return output, 0
+	}
+	return r(output)
+}
+
+// GetOutputRedactions returns how many secrets were redacted from the
+// dispatch's captured output.
+func (s *Store) GetOutputRedactions(dispatchID int64) (int, error) {
+	var n int
+	if err := s.db.QueryRow(`SELECT COALESCE(SUM(redactions), 0) FROM dispatch_output WHERE dispatch_id = ?`, dispatchID).Scan(&n); err != nil {
+		return 0, fmt.Errorf("store: get output redactions: %w", err)
+	}
+	return n, nil
+}
+
+// migrateOutputRedactions adds the redaction counts to dispatch_output and
+// output_chunks. Called from migrate().
+func migrateOutputRedactions(db *sql.DB) error {
+	for _, table := range []string{"dispatch_output", "output_chunks"} {
+		var count int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('` + table + `') WHERE name = 'redactions'`).Scan(&count); err != nil {
+			return fmt.Errorf("check %s redactions column: %w", table, err)
+		}
+		if count == 0 {
+			if _, err := db.Exec(`ALTER TABLE ` + table + ` ADD COLUMN redactions INTEGER NOT NULL DEFAULT 0`); err != nil {
+				return fmt.Errorf("add %s redactions column: %w", table, err)
+			}
+		}
+	}
+	return nil
+}
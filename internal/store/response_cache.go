@@ -0,0 +1,76 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CachedResponse is an agent answer kept for reuse by an identical prompt.
+type CachedResponse struct {
+	Key       string
+	Agent     string
+	Role      string
+	Output    string
+	CreatedAt time.Time
+	Hits      int
+}
+
+// migrateResponseCacheTable creates the response_cache table. Called from migrate().
+func migrateResponseCacheTable(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS response_cache (
+			key TEXT PRIMARY KEY,
+			agent TEXT NOT NULL DEFAULT '',
+			role TEXT NOT NULL DEFAULT '',
+			output TEXT NOT NULL,
+			created_at DATETIME NOT NULL DEFAULT (datetime('now')),
+			hits INTEGER NOT NULL DEFAULT 0
+		)
+	`); err != nil {
+		return fmt.Errorf("create response_cache table: %w", err)
+	}
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_response_cache_created ON response_cache(created_at)`); err != nil {
+		return fmt.Errorf("create response_cache created index: %w", err)
+	}
+	return nil
+}
+
+// GetCachedResponse returns the response cached under key if it is younger
+// than maxAge, counting the hit.
+func (s *Store) GetCachedResponse(key string, maxAge time.Duration) (*CachedResponse, bool, error) {
+	cutoff := time.Now().Add(-maxAge).UTC().Format(time.DateTime)
+	var c CachedResponse
+	err := s.db.QueryRow(
+		`SELECT key, agent, role, output, created_at, hits FROM response_cache WHERE key = ? AND created_at > ?`,
+		key, cutoff,
+	).Scan(&c.Key, &c.Agent, &c.Role, &c.Output, &c.CreatedAt, &c.Hits)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("store: get cached response: %w", err)
+	}
+	if _, err := s.db.Exec(`UPDATE response_cache SET hits = hits + 1 WHERE key = ?`, key); err != nil {
+		return nil, false, fmt.Errorf("store: count cached response hit: %w", err)
+	}
+	c.Hits++
+	return &c, true, nil
+}
+
+// PutCachedResponse caches output under key, replacing any older entry, and
+// drops entries older than maxAge.
+func (s *Store) PutCachedResponse(key, agent, role, output string, maxAge time.Duration) error {
+	now := time.Now().UTC()
+	if _, err := s.db.Exec(
+		`INSERT OR REPLACE INTO response_cache (key, agent, role, output, created_at, hits) VALUES (?, ?, ?, ?, ?, 0)`,
+		key, agent, role, output, now.Format(time.DateTime),
+	); err != nil {
+		return fmt.Errorf("store: put cached response: %w", err)
+	}
+	if _, err := s.db.Exec(`DELETE FROM response_cache WHERE created_at <= ?`, now.Add(-maxAge).Format(time.DateTime)); err != nil {
+		return fmt.Errorf("store: prune response cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResponseCacheGetPut(t *testing.T) {
+	s := tempStore(t)
+
+	if _, ok, err := s.GetCachedResponse("k1", time.Hour); err != nil || ok {
+		t.Fatalf("empty cache: ok=%v err=%v", ok, err)
+	}
+	if err := s.PutCachedResponse("k1", "claude", "planner", "plan output", time.Hour); err != nil {
+		t.Fatalf("PutCachedResponse: %v", err)
+	}
+	c, ok, err := s.GetCachedResponse("k1", time.Hour)
+	if err != nil || !ok || c.Output != "plan output" || c.Agent != "claude" || c.Role != "planner" || c.Hits != 1 {
+		t.Fatalf("GetCachedResponse = %+v, %v, %v", c, ok, err)
+	}
+
+	// An entry older than the TTL is a miss and is pruned by the next put.
+	old := time.Now().Add(-2 * time.Hour).UTC().Format(time.DateTime)
+	if _, err := s.DB().Exec(`UPDATE response_cache SET created_at = ? WHERE key = 'k1'`, old); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := s.GetCachedResponse("k1", time.Hour); ok {
+		t.Fatal("expired entry was returned")
+	}
+	if err := s.PutCachedResponse("k2", "codex", "groom", "[]", time.Hour); err != nil {
+		t.Fatalf("PutCachedResponse: %v", err)
+	}
+	var n int
+	if err := s.DB().QueryRow(`SELECT COUNT(*) FROM response_cache`).Scan(&n); err != nil || n != 1 {
+		t.Fatalf("expected the expired entry pruned, %d rows (%v)", n, err)
+	}
+}
@@ -0,0 +1,158 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"modernc.org/sqlite"
+)
+
+// SQLite result codes; extended codes keep the primary code in the low byte.
+const (
+	sqliteBusy   = 5
+	sqliteLocked = 6
+)
+
+// Busy retry tuning. Each attempt already waits up to busy_timeout inside
+// SQLite, so these only cover contention that outlasts it.
+const (
+	busyRetryAttempts = 5
+	busyRetryBase     = 25 * time.Millisecond
+	busyRetryMax      = time.Second
+)
+
+// retryDB wraps the connection pool so autocommit statements that fail with
+// SQLITE_BUSY or SQLITE_LOCKED are retried with jittered backoff. Statements
+// inside a transaction go through *sql.Tx and are not retried, since only
+// the caller can safely replay a whole transaction.
+type retryDB struct {
+	*sql.DB
+	stats lockStats
+}
+
+type lockStats struct {
+	waitNanos atomic.Int64 // time spent in statements that hit a busy error
+	retries   atomic.Int64
+	exhausted atomic.Int64
+}
+
+// LockStats reports SQLite lock contention since the store was opened.
+type LockStats struct {
+	Wait      time.Duration // total time spent in statements that hit a busy error, including retries
+	Retries   int64         // busy statements retried
+	Exhausted int64         // statements that were still busy after every retry
+}
+
+// LockStats returns lock contention counters for metrics.
+func (s *Store) LockStats() LockStats {
+	return LockStats{
+		Wait:      time.Duration(s.db.stats.waitNanos.Load()),
+		Retries:   s.db.stats.retries.Load(),
+		Exhausted: s.db.stats.exhausted.Load(),
+	}
+}
+
+// isBusy reports whether err means the database was locked by another
+// connection and the statement can be retried.
+func isBusy(err error) bool {
+	if err == nil {
+		return false
+	}
+	var se *sqlite.Error
+	if errors.As(err, &se) {
+		code := se.Code() & 0xff
+		return code == sqliteBusy || code == sqliteLocked
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "SQLITE_BUSY") || strings.Contains(msg, "database is locked")
+}
+
+// busyBackoff returns the jittered delay before retry attempt n (0-based):
+// uniformly between half and all of base*2^n, capped at busyRetryMax.
+func busyBackoff(n int) time.Duration {
+	d := busyRetryBase << n
+	if d > busyRetryMax || d <= 0 {
+		d = busyRetryMax
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// retry runs op until it succeeds, fails with a non-busy error, or runs out
+// of attempts.
+func (db *retryDB) retry(op func() error) error {
+	start := time.Now()
+	busy := false
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = op()
+		if !isBusy(err) {
+			break
+		}
+		busy = true
+		if attempt == busyRetryAttempts-1 {
+			db.stats.exhausted.Add(1)
+			break
+		}
+		db.stats.retries.Add(1)
+		time.Sleep(busyBackoff(attempt))
+	}
+	if busy {
+		db.stats.waitNanos.Add(int64(time.Since(start)))
+	}
+	return err
+}
+
+func (db *retryDB) Exec(query string, args ...any) (sql.Result, error) {
+	var res sql.Result
+	err := db.retry(func() error {
+		var err error
+		res, err = db.DB.Exec(query, args...)
+		return err
+	})
+	return res, err
+}
+
+func (db *retryDB) Query(query string, args ...any) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := db.retry(func() error {
+		var err error
+		rows, err = db.DB.Query(query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// QueryRow retries while the query itself reports busy. Errors that only
+// surface during Scan are returned to the caller as usual.
+func (db *retryDB) QueryRow(query string, args ...any) *sql.Row {
+	var row *sql.Row
+	db.retry(func() error {
+		row = db.DB.QueryRow(query, args...)
+		return row.Err()
+	})
+	return row
+}
+
+// WALCheckpoint is the outcome of PRAGMA wal_checkpoint.
+type WALCheckpoint struct {
+	Busy         bool // a reader or writer blocked the checkpoint from completing
+	LogFrames    int  // frames in the WAL before the checkpoint
+	Checkpointed int  // frames copied back into the database
+}
+
+// CheckpointWAL copies the write-ahead log into the database and truncates
+// it, so the WAL does not grow without bound under steady write load.
+func (s *Store) CheckpointWAL() (WALCheckpoint, error) {
+	var busy int
+	var cp WALCheckpoint
+	if err := s.db.QueryRow(`PRAGMA wal_checkpoint(TRUNCATE)`).Scan(&busy, &cp.LogFrames, &cp.Checkpointed); err != nil {
+		return cp, fmt.Errorf("store: wal checkpoint: %w", err)
+	}
+	cp.Busy = busy != 0
+	return cp, nil
+}
@@ -0,0 +1,90 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRetryDBRetriesBusyErrors(t *testing.T) {
+	db := &retryDB{}
+	busy := errors.New("database is locked (5) (SQLITE_BUSY)")
+
+	calls := 0
+	err := db.retry(func() error {
+		calls++
+		if calls < 3 {
+			return busy
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("expected success on 3rd attempt, got err=%v calls=%d", err, calls)
+	}
+
+	calls = 0
+	err = db.retry(func() error {
+		calls++
+		return fmt.Errorf("insert: %w", busy)
+	})
+	if !errors.Is(err, busy) || calls != busyRetryAttempts {
+		t.Fatalf("expected busy error after %d attempts, got err=%v calls=%d", busyRetryAttempts, err, calls)
+	}
+
+	calls = 0
+	other := errors.New("UNIQUE constraint failed")
+	if err := db.retry(func() error { calls++; return other }); err != other || calls != 1 {
+		t.Fatalf("non-busy errors must not be retried, got err=%v calls=%d", err, calls)
+	}
+
+	stats := (&Store{db: db}).LockStats()
+	if stats.Retries != int64(2+busyRetryAttempts-1) || stats.Exhausted != 1 || stats.Wait <= 0 {
+		t.Fatalf("unexpected lock stats: %+v", stats)
+	}
+}
+
+func TestBusyBackoffJitteredAndCapped(t *testing.T) {
+	for n := 0; n < 10; n++ {
+		d := busyBackoff(n)
+		ceiling := busyRetryBase << n
+		if ceiling > busyRetryMax {
+			ceiling = busyRetryMax
+		}
+		if d < ceiling/2 || d > ceiling {
+			t.Fatalf("busyBackoff(%d) = %v, want within [%v, %v]", n, d, ceiling/2, ceiling)
+		}
+	}
+	if d := busyBackoff(62); d > busyRetryMax {
+		t.Fatalf("busyBackoff overflowed: %v", d)
+	}
+}
+
+func TestCheckpointWAL(t *testing.T) {
+	s := tempStore(t)
+	for i := 0; i < 20; i++ {
+		if err := s.RecordHealthEvent("test", fmt.Sprintf("event %d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cp, err := s.CheckpointWAL()
+	if err != nil {
+		t.Fatalf("CheckpointWAL: %v", err)
+	}
+	if cp.Busy || cp.Checkpointed != cp.LogFrames {
+		t.Fatalf("expected a complete checkpoint, got %+v", cp)
+	}
+
+	// TRUNCATE leaves an empty WAL behind.
+	cp, err = s.CheckpointWAL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp.LogFrames != 0 {
+		t.Fatalf("expected empty WAL after truncate, got %+v", cp)
+	}
+	if s.LockStats().Wait > time.Second {
+		t.Fatalf("unexpected lock wait without contention: %+v", s.LockStats())
+	}
+}
@@ -0,0 +1,180 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// PRReview tracks a Cortex-opened pull request from the moment it awaits
+// review until the first reviewer activity or the PR closes.
+type PRReview struct {
+	ID            int64
+	DispatchID    int64
+	Project       string
+	BeadID        string
+	Agent         string
+	PRNumber      int
+	PRURL         string
+	OpenedAt      time.Time
+	FirstReviewAt sql.NullTime
+	Reviewer      string
+	Nudges        int
+	LastNudgeAt   sql.NullTime
+	ClosedAt      sql.NullTime
+}
+
+// ReviewLatencyStat aggregates review latency for one project.
+type ReviewLatencyStat struct {
+	Project     string
+	Reviewed    int
+	Pending     int
+	Nudges      int
+	AvgLatencyS float64
+	MaxLatencyS float64
+}
+
+// migratePRReviewsTable creates the pr_reviews table. Called from migrate().
+func migratePRReviewsTable(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS pr_reviews (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			dispatch_id INTEGER NOT NULL UNIQUE,
+			project TEXT NOT NULL,
+			bead_id TEXT NOT NULL,
+			agent TEXT NOT NULL DEFAULT '',
+			pr_number INTEGER NOT NULL,
+			pr_url TEXT NOT NULL DEFAULT '',
+			opened_at DATETIME NOT NULL,
+			first_review_at DATETIME,
+			reviewer TEXT NOT NULL DEFAULT '',
+			nudges INTEGER NOT NULL DEFAULT 0,
+			last_nudge_at DATETIME,
+			closed_at DATETIME
+		)
+	`); err != nil {
+		return fmt.Errorf("create pr_reviews table: %w", err)
+	}
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_pr_reviews_project ON pr_reviews(project, first_review_at)`); err != nil {
+		return fmt.Errorf("create pr_reviews project index: %w", err)
+	}
+	return nil
+}
+
+const prReviewCols = `id, dispatch_id, project, bead_id, agent, pr_number, pr_url, opened_at, first_review_at, reviewer, nudges, last_nudge_at, closed_at`
+
+// ListPendingPRReviews starts tracking any newly opened Cortex PRs and returns
+// those still waiting for their first review, oldest first.
+func (s *Store) ListPendingPRReviews() ([]PRReview, error) {
+	if err := s.trackPRReviews(); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(`SELECT ` + prReviewCols + ` FROM pr_reviews
+		WHERE first_review_at IS NULL AND closed_at IS NULL ORDER BY opened_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("store: list pending pr reviews: %w", err)
+	}
+	defer rows.Close()
+
+	var out []PRReview
+	for rows.Next() {
+		var r PRReview
+		if err := rows.Scan(&r.ID, &r.DispatchID, &r.Project, &r.BeadID, &r.Agent, &r.PRNumber, &r.PRURL,
+			&r.OpenedAt, &r.FirstReviewAt, &r.Reviewer, &r.Nudges, &r.LastNudgeAt, &r.ClosedAt); err != nil {
+			return nil, fmt.Errorf("store: scan pr review: %w", err)
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// trackPRReviews starts tracking PRs opened by dispatches since the last call.
+func (s *Store) trackPRReviews() error {
+	if _, err := s.db.Exec(`
+		INSERT OR IGNORE INTO pr_reviews (dispatch_id, project, bead_id, agent, pr_number, pr_url, opened_at)
+		SELECT id, project, bead_id, agent_id, pr_number, pr_url, COALESCE(completed_at, dispatched_at)
+		FROM dispatches WHERE pr_number > 0
+	`); err != nil {
+		return fmt.Errorf("store: track pr reviews: %w", err)
+	}
+	return nil
+}
+
+// GetPRReviewByNumber returns the tracked review of a project's PR, or nil
+// when no dispatch opened that PR. When several dispatches reported the same
+// PR the latest wins.
+func (s *Store) GetPRReviewByNumber(project string, number int) (*PRReview, error) {
+	if err := s.trackPRReviews(); err != nil {
+		return nil, err
+	}
+	var r PRReview
+	err := s.db.QueryRow(`SELECT `+prReviewCols+` FROM pr_reviews
+		WHERE project = ? AND pr_number = ? ORDER BY id DESC LIMIT 1`, project, number).Scan(
+		&r.ID, &r.DispatchID, &r.Project, &r.BeadID, &r.Agent, &r.PRNumber, &r.PRURL,
+		&r.OpenedAt, &r.FirstReviewAt, &r.Reviewer, &r.Nudges, &r.LastNudgeAt, &r.ClosedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("store: get pr review %s#%d: %w", project, number, err)
+	}
+	return &r, nil
+}
+
+// RecordPRFirstReview marks when a reviewer first acted on the PR.
+func (s *Store) RecordPRFirstReview(id int64, at time.Time) error {
+	if _, err := s.db.Exec(`UPDATE pr_reviews SET first_review_at = ? WHERE id = ? AND first_review_at IS NULL`,
+		at.UTC().Format(time.DateTime), id); err != nil {
+		return fmt.Errorf("store: record pr first review %d: %w", id, err)
+	}
+	return nil
+}
+
+// ClosePRReview stops tracking a PR that was merged or closed unreviewed.
+func (s *Store) ClosePRReview(id int64, at time.Time) error {
+	if _, err := s.db.Exec(`UPDATE pr_reviews SET closed_at = ? WHERE id = ?`, at.UTC().Format(time.DateTime), id); err != nil {
+		return fmt.Errorf("store: close pr review %d: %w", id, err)
+	}
+	return nil
+}
+
+// RecordPRReviewNudge counts a nudge and the reviewer the PR was handed to.
+func (s *Store) RecordPRReviewNudge(id int64, reviewer string, at time.Time) error {
+	if _, err := s.db.Exec(`UPDATE pr_reviews SET nudges = nudges + 1, last_nudge_at = ?, reviewer = ? WHERE id = ?`,
+		at.UTC().Format(time.DateTime), reviewer, id); err != nil {
+		return fmt.Errorf("store: record pr review nudge %d: %w", id, err)
+	}
+	return nil
+}
+
+// GetReviewLatencyStats returns per-project review latency for PRs opened
+// since the given time.
+func (s *Store) GetReviewLatencyStats(since time.Time) ([]ReviewLatencyStat, error) {
+	rows, err := s.db.Query(`
+		SELECT project,
+			SUM(CASE WHEN first_review_at IS NOT NULL THEN 1 ELSE 0 END),
+			SUM(CASE WHEN first_review_at IS NULL AND closed_at IS NULL THEN 1 ELSE 0 END),
+			COALESCE(SUM(nudges), 0),
+			COALESCE(AVG(CASE WHEN first_review_at IS NOT NULL THEN (julianday(first_review_at) - julianday(opened_at)) * 86400 END), 0),
+			COALESCE(MAX(CASE WHEN first_review_at IS NOT NULL THEN (julianday(first_review_at) - julianday(opened_at)) * 86400 END), 0)
+		FROM pr_reviews
+		WHERE opened_at >= ?
+		GROUP BY project
+		ORDER BY project
+	`, since.UTC().Format(time.DateTime))
+	if err != nil {
+		return nil, fmt.Errorf("store: review latency stats: %w", err)
+	}
+	defer rows.Close()
+
+	var out []ReviewLatencyStat
+	for rows.Next() {
+		var st ReviewLatencyStat
+		if err := rows.Scan(&st.Project, &st.Reviewed, &st.Pending, &st.Nudges, &st.AvgLatencyS, &st.MaxLatencyS); err != nil {
+			return nil, fmt.Errorf("store: scan review latency: %w", err)
+		}
+		out = append(out, st)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,129 @@
+package store
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestPRReviewsTrackingAndLatency(t *testing.T) {
+	s := tempStore(t)
+
+	withPR, err := s.RecordDispatch("bead-1", "alpha", "claude", "claude", "balanced", 0, "", "", "", "feat/bead-1", "headless_cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpdateDispatchPR(withPR, "https://github.com/o/r/pull/7", 7); err != nil {
+		t.Fatal(err)
+	}
+	other, err := s.RecordDispatch("bead-2", "alpha", "codex", "codex", "balanced", 0, "", "", "", "feat/bead-2", "headless_cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpdateDispatchPR(other, "https://github.com/o/r/pull/8", 8); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.RecordDispatch("bead-3", "alpha", "claude", "claude", "balanced", 0, "", "", "", "", "headless_cli"); err != nil {
+		t.Fatal(err)
+	}
+
+	pending, err := s.ListPendingPRReviews()
+	if err != nil {
+		t.Fatalf("ListPendingPRReviews: %v", err)
+	}
+	if len(pending) != 2 || pending[0].PRNumber != 7 || pending[0].Agent != "claude" {
+		t.Fatalf("expected two tracked PRs, got %+v", pending)
+	}
+
+	first := pending[0]
+	if err := s.RecordPRReviewNudge(first.ID, "codex", time.Now()); err != nil {
+		t.Fatalf("RecordPRReviewNudge: %v", err)
+	}
+	if err := s.RecordPRFirstReview(first.ID, first.OpenedAt.Add(2*time.Hour)); err != nil {
+		t.Fatalf("RecordPRFirstReview: %v", err)
+	}
+
+	// Re-listing must not re-track or resurrect the reviewed PR.
+	pending, err = s.ListPendingPRReviews()
+	if err != nil {
+		t.Fatalf("ListPendingPRReviews: %v", err)
+	}
+	if len(pending) != 1 || pending[0].PRNumber != 8 {
+		t.Fatalf("expected only PR 8 pending, got %+v", pending)
+	}
+
+	stats, err := s.GetReviewLatencyStats(time.Now().Add(-24 * time.Hour))
+	if err != nil {
+		t.Fatalf("GetReviewLatencyStats: %v", err)
+	}
+	if len(stats) != 1 {
+		t.Fatalf("expected one project, got %+v", stats)
+	}
+	st := stats[0]
+	if st.Project != "alpha" || st.Reviewed != 1 || st.Pending != 1 || st.Nudges != 1 {
+		t.Fatalf("unexpected stats: %+v", st)
+	}
+	if st.AvgLatencyS < 7199 || st.AvgLatencyS > 7201 {
+		t.Fatalf("expected ~2h latency, got %f", st.AvgLatencyS)
+	}
+
+	if err := s.ClosePRReview(pending[0].ID, time.Now()); err != nil {
+		t.Fatalf("ClosePRReview: %v", err)
+	}
+	pending, err = s.ListPendingPRReviews()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("expected no pending PRs after close, got %+v", pending)
+	}
+}
+
+func TestGetLatestBeadPR(t *testing.T) {
+	s := tempStore(t)
+	if n, _, err := s.GetLatestBeadPR("alpha", "bead-1"); err != nil || n != 0 {
+		t.Fatalf("expected no PR, got %d err=%v", n, err)
+	}
+	for _, pr := range []int{7, 9, 0} {
+		id, err := s.RecordDispatch("bead-1", "alpha", "claude", "claude", "fast", 0, "", "", "", "feat/bead-1", "headless_cli")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pr > 0 {
+			if err := s.UpdateDispatchPR(id, "https://github.com/o/r/pull/"+strconv.Itoa(pr), pr); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	n, url, err := s.GetLatestBeadPR("alpha", "bead-1")
+	if err != nil || n != 9 || url != "https://github.com/o/r/pull/9" {
+		t.Fatalf("expected PR 9, got %d %q err=%v", n, url, err)
+	}
+}
+
+func TestGetPRReviewByNumber(t *testing.T) {
+	s := tempStore(t)
+	id, err := s.RecordDispatch("bead-1", "alpha", "claude", "claude", "fast", 0, "", "", "", "feat/bead-1", "headless_cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpdateDispatchPR(id, "https://github.com/o/r/pull/7", 7); err != nil {
+		t.Fatal(err)
+	}
+
+	review, err := s.GetPRReviewByNumber("alpha", 7)
+	if err != nil {
+		t.Fatalf("GetPRReviewByNumber: %v", err)
+	}
+	if review == nil || review.BeadID != "bead-1" || review.DispatchID != id {
+		t.Fatalf("unexpected review %+v", review)
+	}
+	for _, tc := range []struct {
+		project string
+		number  int
+	}{{"alpha", 8}, {"beta", 7}} {
+		if review, err := s.GetPRReviewByNumber(tc.project, tc.number); err != nil || review != nil {
+			t.Fatalf("%s#%d: expected no review, got %+v err=%v", tc.project, tc.number, review, err)
+		}
+	}
+}
@@ -29,7 +29,7 @@ func inMemoryStore(t *testing.T) *Store {
 		t.Fatalf("migrate schema: %v", err)
 	}
 
-	s := &Store{db: db}
+	s := &Store{db: &retryDB{DB: db}}
 	t.Cleanup(func() {
 		_ = s.Close()
 	})
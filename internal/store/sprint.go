@@ -459,6 +459,7 @@ func parseSQLiteTime(value string) (time.Time, error) {
 		time.DateTime,
 		time.RFC3339Nano,
 		time.RFC3339,
+		"2006-01-02 15:04:05.999999999 -0700 MST", // time.Time values written by the driver
 	}
 	var lastErr error
 	for _, layout := range layouts {
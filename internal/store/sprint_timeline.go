@@ -0,0 +1,228 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DispatchWindow is the span one dispatch of a bead was running.
+type DispatchWindow struct {
+	DispatchID int64      `json:"dispatch_id"`
+	AgentID    string     `json:"agent_id"`
+	Status     string     `json:"status"`
+	StartedAt  time.Time  `json:"started_at"`
+	EndedAt    *time.Time `json:"ended_at,omitempty"`
+}
+
+// TimelineBead is one bead's activity within a sprint.
+type TimelineBead struct {
+	Project      string              `json:"project"`
+	BeadID       string              `json:"bead_id"`
+	Workflow     string              `json:"workflow,omitempty"`
+	CurrentStage string              `json:"current_stage,omitempty"`
+	Stages       []StageHistoryEntry `json:"stages"`
+	Dispatches   []DispatchWindow    `json:"dispatches"`
+	StartedAt    time.Time           `json:"started_at"`
+	CompletedAt  *time.Time          `json:"completed_at,omitempty"`
+}
+
+// BurndownPoint is the number of timeline beads remaining and completed at
+// the end of one sprint day.
+type BurndownPoint struct {
+	Date      string `json:"date"`
+	Remaining int    `json:"remaining"`
+	Completed int    `json:"completed"`
+}
+
+// SprintTimeline is the per-bead activity of one sprint, suitable for
+// rendering a Gantt chart or burndown.
+type SprintTimeline struct {
+	SprintNumber int             `json:"sprint_number"`
+	Project      string          `json:"project,omitempty"`
+	SprintStart  time.Time       `json:"sprint_start"`
+	SprintEnd    time.Time       `json:"sprint_end"`
+	Beads        []TimelineBead  `json:"beads"`
+	Burndown     []BurndownPoint `json:"burndown"`
+}
+
+// GetSprintBoundary returns the boundary of a sprint by number, or nil if
+// the sprint is not recorded.
+func (s *Store) GetSprintBoundary(sprintNumber int) (*SprintBoundary, error) {
+	var sb SprintBoundary
+	err := s.db.QueryRow(
+		`SELECT id, sprint_number, sprint_start, sprint_end, created_at
+		 FROM sprint_boundaries WHERE sprint_number = ?`,
+		sprintNumber,
+	).Scan(&sb.ID, &sb.SprintNumber, &sb.SprintStart, &sb.SprintEnd, &sb.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("store: get sprint boundary: %w", err)
+	}
+	return &sb, nil
+}
+
+// GetLatestSprintBoundary returns the boundary of the highest-numbered
+// recorded sprint, or nil if none is recorded.
+func (s *Store) GetLatestSprintBoundary() (*SprintBoundary, error) {
+	var sb SprintBoundary
+	err := s.db.QueryRow(
+		`SELECT id, sprint_number, sprint_start, sprint_end, created_at
+		 FROM sprint_boundaries ORDER BY sprint_number DESC LIMIT 1`,
+	).Scan(&sb.ID, &sb.SprintNumber, &sb.SprintStart, &sb.SprintEnd, &sb.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("store: get latest sprint boundary: %w", err)
+	}
+	return &sb, nil
+}
+
+// GetSprintTimeline builds the timeline of a sprint from its boundary, bead
+// stage history and dispatch history. A bead is included when one of its
+// stages or dispatches overlaps the sprint. An empty project includes every
+// project. It returns nil if the sprint is not recorded.
+//
+// A bead is complete when it reached a terminal stage; beads without stage
+// tracking are complete once a dispatch completed.
+func (s *Store) GetSprintTimeline(sprintNumber int, project string) (*SprintTimeline, error) {
+	sb, err := s.GetSprintBoundary(sprintNumber)
+	if err != nil || sb == nil {
+		return nil, err
+	}
+	start, end := sb.SprintStart.UTC(), sb.SprintEnd.UTC()
+	tl := &SprintTimeline{SprintNumber: sb.SprintNumber, Project: project, SprintStart: start, SprintEnd: end}
+
+	type key struct{ project, beadID string }
+	byKey := map[key]*TimelineBead{}
+	bead := func(k key) *TimelineBead {
+		b, ok := byKey[k]
+		if !ok {
+			b = &TimelineBead{Project: k.project, BeadID: k.beadID, Stages: []StageHistoryEntry{}, Dispatches: []DispatchWindow{}}
+			byKey[k] = b
+		}
+		return b
+	}
+	overlaps := func(from time.Time, to *time.Time) bool {
+		return from.Before(end) && (to == nil || !to.Before(start))
+	}
+
+	rows, err := s.db.Query(
+		`SELECT id, bead_id, project, agent_id, status, dispatched_at, completed_at
+		 FROM dispatches
+		 WHERE dispatched_at < ? AND (completed_at IS NULL OR completed_at >= ?)
+		   AND (? = '' OR project = ?)
+		 ORDER BY dispatched_at, id`,
+		end.Format(time.DateTime), start.Format(time.DateTime), project, project,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("store: sprint timeline dispatches: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var w DispatchWindow
+		var beadID, proj string
+		var completedAt sql.NullTime
+		if err := rows.Scan(&w.DispatchID, &beadID, &proj, &w.AgentID, &w.Status, &w.StartedAt, &completedAt); err != nil {
+			return nil, fmt.Errorf("store: scan sprint timeline dispatch: %w", err)
+		}
+		w.StartedAt = w.StartedAt.UTC()
+		if completedAt.Valid {
+			t := completedAt.Time.UTC()
+			w.EndedAt = &t
+		}
+		b := bead(key{proj, beadID})
+		b.Dispatches = append(b.Dispatches, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("store: sprint timeline dispatches: %w", err)
+	}
+
+	stageRows, err := s.db.Query(
+		`SELECT project, bead_id, workflow, current_stage, stage_history
+		 FROM bead_stages WHERE ? = '' OR project = ?`,
+		project, project,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("store: sprint timeline stages: %w", err)
+	}
+	defer stageRows.Close()
+	for stageRows.Next() {
+		var k key
+		var workflow, currentStage, historyJSON string
+		if err := stageRows.Scan(&k.project, &k.beadID, &workflow, &currentStage, &historyJSON); err != nil {
+			return nil, fmt.Errorf("store: scan sprint timeline stage: %w", err)
+		}
+		var stages []StageHistoryEntry
+		for _, e := range parseStageHistory(historyJSON) {
+			if overlaps(e.StartedAt, e.CompletedAt) {
+				stages = append(stages, e)
+			}
+		}
+		if len(stages) == 0 && byKey[k] == nil {
+			continue
+		}
+		b := bead(k)
+		b.Workflow, b.CurrentStage = workflow, currentStage
+		if stages != nil {
+			b.Stages = stages
+		}
+		if n := len(stages); n > 0 && isTerminalStage(stages[n-1].Stage) {
+			done := stages[n-1].StartedAt
+			b.CompletedAt = &done
+		}
+	}
+	if err := stageRows.Err(); err != nil {
+		return nil, fmt.Errorf("store: sprint timeline stages: %w", err)
+	}
+
+	tl.Beads = make([]TimelineBead, 0, len(byKey))
+	for _, b := range byKey {
+		if b.Workflow == "" && b.CompletedAt == nil {
+			for _, w := range b.Dispatches {
+				if w.Status == "completed" && w.EndedAt != nil {
+					b.CompletedAt = w.EndedAt
+				}
+			}
+		}
+		b.StartedAt = end
+		if len(b.Stages) > 0 {
+			b.StartedAt = b.Stages[0].StartedAt
+		}
+		if len(b.Dispatches) > 0 && b.Dispatches[0].StartedAt.Before(b.StartedAt) {
+			b.StartedAt = b.Dispatches[0].StartedAt
+		}
+		tl.Beads = append(tl.Beads, *b)
+	}
+	sort.Slice(tl.Beads, func(i, j int) bool {
+		a, b := tl.Beads[i], tl.Beads[j]
+		if !a.StartedAt.Equal(b.StartedAt) {
+			return a.StartedAt.Before(b.StartedAt)
+		}
+		if a.Project != b.Project {
+			return a.Project < b.Project
+		}
+		return a.BeadID < b.BeadID
+	})
+
+	tl.Burndown = []BurndownPoint{}
+	for day := start.Truncate(24 * time.Hour); day.Before(end); day = day.Add(24 * time.Hour) {
+		dayEnd := day.Add(24 * time.Hour)
+		if dayEnd.After(end) {
+			dayEnd = end
+		}
+		p := BurndownPoint{Date: day.Format(time.DateOnly)}
+		for _, b := range tl.Beads {
+			if b.CompletedAt != nil && b.CompletedAt.Before(dayEnd) {
+				p.Completed++
+			}
+		}
+		p.Remaining = len(tl.Beads) - p.Completed
+		tl.Burndown = append(tl.Burndown, p)
+	}
+	return tl, nil
+}
@@ -0,0 +1,110 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetSprintTimeline(t *testing.T) {
+	s := tempStore(t)
+	start := time.Now().UTC().Truncate(24 * time.Hour).Add(-48 * time.Hour)
+	if err := s.RecordSprintBoundary(1, start, start.Add(7*24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	record := func(bead, project, status string, at time.Time, done *time.Time) int64 {
+		t.Helper()
+		id, err := s.RecordDispatch(bead, project, "agent", "claude", "balanced", 1, "", "p", "", "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.SetDispatchTime(id, at); err != nil {
+			t.Fatal(err)
+		}
+		if done != nil {
+			if _, err := s.db.Exec(`UPDATE dispatches SET status = ?, completed_at = ? WHERE id = ?`,
+				status, done.Format(time.DateTime), id); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return id
+	}
+
+	// cx-a moves through stages and finishes today.
+	if err := s.UpsertBeadStage(&BeadStage{Project: "alpha", BeadID: "cx-a", Workflow: "dev", CurrentStage: "coding", TotalStages: 2}); err != nil {
+		t.Fatal(err)
+	}
+	dispatchA := record("cx-a", "alpha", "running", start.Add(time.Hour), nil)
+	if err := s.UpdateBeadStageProgress("alpha", "cx-a", "done", 1, 2, dispatchA); err != nil {
+		t.Fatal(err)
+	}
+	// cx-b has no stage tracking and completes on the first day.
+	doneB := start.Add(3 * time.Hour)
+	record("cx-b", "alpha", "completed", start.Add(2*time.Hour), &doneB)
+	// cx-c finished before the sprint started.
+	doneC := start.Add(-47 * time.Hour)
+	record("cx-c", "alpha", "failed", start.Add(-48*time.Hour), &doneC)
+	record("cx-d", "beta", "running", start.Add(time.Hour), nil)
+
+	tl, err := s.GetSprintTimeline(1, "alpha")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tl.Beads) != 2 || tl.Beads[0].BeadID != "cx-a" || tl.Beads[1].BeadID != "cx-b" {
+		t.Fatalf("unexpected beads %+v", tl.Beads)
+	}
+	a, b := tl.Beads[0], tl.Beads[1]
+	if len(a.Stages) != 2 || a.Stages[1].Stage != "done" || len(a.Dispatches) != 1 || a.CompletedAt == nil {
+		t.Fatalf("unexpected cx-a %+v", a)
+	}
+	if !a.StartedAt.Equal(start.Add(time.Hour)) {
+		t.Fatalf("cx-a should start with its first dispatch, got %s", a.StartedAt)
+	}
+	if b.CompletedAt == nil || !b.CompletedAt.Equal(doneB) || b.Dispatches[0].EndedAt == nil {
+		t.Fatalf("cx-b should complete with its dispatch: %+v", b)
+	}
+
+	if len(tl.Burndown) != 7 {
+		t.Fatalf("expected 7 burndown days, got %+v", tl.Burndown)
+	}
+	if p := tl.Burndown[0]; p.Date != start.Format(time.DateOnly) || p.Remaining != 1 || p.Completed != 1 {
+		t.Fatalf("unexpected first day %+v", p)
+	}
+	if p := tl.Burndown[6]; p.Remaining != 0 || p.Completed != 2 {
+		t.Fatalf("unexpected last day %+v", p)
+	}
+
+	all, err := s.GetSprintTimeline(1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all.Beads) != 3 {
+		t.Fatalf("expected beads from every project, got %+v", all.Beads)
+	}
+
+	missing, err := s.GetSprintTimeline(2, "")
+	if err != nil || missing != nil {
+		t.Fatalf("expected no timeline for an unknown sprint, got %+v, %v", missing, err)
+	}
+}
+
+func TestGetLatestSprintBoundary(t *testing.T) {
+	s := tempStore(t)
+	if sb, err := s.GetLatestSprintBoundary(); err != nil || sb != nil {
+		t.Fatalf("expected no boundary, got %+v, %v", sb, err)
+	}
+	start := time.Date(2026, 10, 5, 9, 0, 0, 0, time.UTC)
+	for n := 2; n >= 1; n-- {
+		from := start.Add(time.Duration(n-1) * 7 * 24 * time.Hour)
+		if err := s.RecordSprintBoundary(n, from, from.Add(7*24*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sb, err := s.GetLatestSprintBoundary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sb.SprintNumber != 2 || !sb.SprintEnd.Equal(start.Add(14*24*time.Hour)) {
+		t.Fatalf("unexpected latest boundary %+v", sb)
+	}
+}
@@ -0,0 +1,121 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// StageSLABreach records a bead that stayed in a workflow stage past the
+// stage's max_duration. A breach is recorded once per stage entry.
+type StageSLABreach struct {
+	ID          int64
+	Project     string
+	BeadID      string
+	Workflow    string
+	Stage       string
+	EnteredAt   time.Time
+	BreachedAt  time.Time
+	Escalate    bool         // the next dispatch of the bead should run one tier higher
+	EscalatedAt sql.NullTime // when a dispatch consumed the escalation
+}
+
+// migrateStageSLABreachesTable creates the stage_sla_breaches table. Called from migrate().
+func migrateStageSLABreachesTable(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS stage_sla_breaches (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			project TEXT NOT NULL,
+			bead_id TEXT NOT NULL,
+			workflow TEXT NOT NULL DEFAULT '',
+			stage TEXT NOT NULL,
+			entered_at DATETIME NOT NULL,
+			breached_at DATETIME NOT NULL DEFAULT (datetime('now')),
+			escalate INTEGER NOT NULL DEFAULT 0,
+			escalated_at DATETIME,
+			UNIQUE (project, bead_id, stage, entered_at)
+		)
+	`); err != nil {
+		return fmt.Errorf("create stage_sla_breaches table: %w", err)
+	}
+	return nil
+}
+
+// StageEnteredAt returns when the bead entered its current stage: the start
+// of the latest history entry for that stage, or the last update when the
+// history does not record it.
+func (bs *BeadStage) StageEnteredAt() time.Time {
+	if n := len(bs.StageHistory); n > 0 && bs.StageHistory[n-1].Stage == bs.CurrentStage {
+		return bs.StageHistory[n-1].StartedAt
+	}
+	return bs.UpdatedAt
+}
+
+// RecordStageSLABreach stores a breach unless one is already recorded for the
+// same stage entry. It reports whether the breach is new.
+func (s *Store) RecordStageSLABreach(b StageSLABreach) (bool, error) {
+	breachedAt := b.BreachedAt
+	if breachedAt.IsZero() {
+		breachedAt = time.Now()
+	}
+	res, err := s.db.Exec(`
+		INSERT OR IGNORE INTO stage_sla_breaches (project, bead_id, workflow, stage, entered_at, breached_at, escalate)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		b.Project, b.BeadID, b.Workflow, b.Stage,
+		b.EnteredAt.UTC().Format(time.DateTime), breachedAt.UTC().Format(time.DateTime), b.Escalate,
+	)
+	if err != nil {
+		return false, fmt.Errorf("store: record stage sla breach: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("store: record stage sla breach: %w", err)
+	}
+	return n > 0, nil
+}
+
+// ListStageSLABreaches returns a bead's breaches, oldest first.
+func (s *Store) ListStageSLABreaches(project, beadID string) ([]StageSLABreach, error) {
+	rows, err := s.db.Query(`
+		SELECT id, project, bead_id, workflow, stage, entered_at, breached_at, escalate, escalated_at
+		FROM stage_sla_breaches WHERE project = ? AND bead_id = ?
+		ORDER BY breached_at, id`,
+		project, beadID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("store: list stage sla breaches: %w", err)
+	}
+	defer rows.Close()
+
+	var out []StageSLABreach
+	for rows.Next() {
+		var b StageSLABreach
+		if err := rows.Scan(&b.ID, &b.Project, &b.BeadID, &b.Workflow, &b.Stage,
+			&b.EnteredAt, &b.BreachedAt, &b.Escalate, &b.EscalatedAt); err != nil {
+			return nil, fmt.Errorf("store: scan stage sla breach: %w", err)
+		}
+		out = append(out, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("store: list stage sla breaches: %w", err)
+	}
+	return out, nil
+}
+
+// ConsumeStageEscalation marks a bead's pending breach escalations as used and
+// reports whether there were any, so only one dispatch is escalated per breach.
+func (s *Store) ConsumeStageEscalation(project, beadID string) (bool, error) {
+	res, err := s.db.Exec(`
+		UPDATE stage_sla_breaches SET escalated_at = ?
+		WHERE project = ? AND bead_id = ? AND escalate = 1 AND escalated_at IS NULL`,
+		time.Now().UTC().Format(time.DateTime), project, beadID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("store: consume stage escalation: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("store: consume stage escalation: %w", err)
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,64 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStageSLABreaches(t *testing.T) {
+	s := tempStore(t)
+	entered := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	breach := StageSLABreach{Project: "alpha", BeadID: "a-1", Workflow: "dev", Stage: "review", EnteredAt: entered, Escalate: true}
+
+	isNew, err := s.RecordStageSLABreach(breach)
+	if err != nil || !isNew {
+		t.Fatalf("first breach: new=%v err=%v", isNew, err)
+	}
+	// The same stage entry only breaches once.
+	if isNew, err = s.RecordStageSLABreach(breach); err != nil || isNew {
+		t.Fatalf("repeat breach: new=%v err=%v", isNew, err)
+	}
+	// Re-entering the stage starts a new SLA.
+	reentry := breach
+	reentry.EnteredAt = entered.Add(24 * time.Hour)
+	reentry.Escalate = false
+	if isNew, err = s.RecordStageSLABreach(reentry); err != nil || !isNew {
+		t.Fatalf("re-entry breach: new=%v err=%v", isNew, err)
+	}
+
+	got, err := s.ListStageSLABreaches("alpha", "a-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Stage != "review" || !got[0].EnteredAt.Equal(entered) || !got[0].Escalate || got[1].Escalate {
+		t.Fatalf("unexpected breaches: %+v", got)
+	}
+
+	escalate, err := s.ConsumeStageEscalation("alpha", "a-1")
+	if err != nil || !escalate {
+		t.Fatalf("first consume: escalate=%v err=%v", escalate, err)
+	}
+	if escalate, err = s.ConsumeStageEscalation("alpha", "a-1"); err != nil || escalate {
+		t.Fatalf("second consume: escalate=%v err=%v", escalate, err)
+	}
+	if got, _ = s.ListStageSLABreaches("alpha", "a-1"); !got[0].EscalatedAt.Valid {
+		t.Fatalf("expected escalation to be marked consumed: %+v", got[0])
+	}
+}
+
+func TestBeadStageEnteredAt(t *testing.T) {
+	updated := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	started := updated.Add(-3 * time.Hour)
+	bs := &BeadStage{
+		CurrentStage: "review",
+		UpdatedAt:    updated,
+		StageHistory: []StageHistoryEntry{{Stage: "implement", StartedAt: started.Add(-time.Hour)}, {Stage: "review", StartedAt: started}},
+	}
+	if got := bs.StageEnteredAt(); !got.Equal(started) {
+		t.Fatalf("entered at = %v, want %v", got, started)
+	}
+	bs.StageHistory = nil
+	if got := bs.StageEnteredAt(); !got.Equal(updated) {
+		t.Fatalf("entered at without history = %v, want %v", got, updated)
+	}
+}
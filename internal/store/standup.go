@@ -0,0 +1,143 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// StageMove is one bead moving between workflow stages.
+type StageMove struct {
+	BeadID string    `json:"bead_id"`
+	From   string    `json:"from"`
+	To     string    `json:"to"`
+	At     time.Time `json:"at"`
+}
+
+// Blocker is an open bead that cannot progress.
+type Blocker struct {
+	BeadID    string   `json:"bead_id"`
+	Title     string   `json:"title"`
+	BlockedBy []string `json:"blocked_by,omitempty"`
+}
+
+// StandupDigest summarizes one project's last day of activity.
+type StandupDigest struct {
+	Project             string      `json:"project"`
+	Date                string      `json:"date"` // YYYY-MM-DD the digest was generated (UTC)
+	WindowStart         time.Time   `json:"window_start"`
+	WindowEnd           time.Time   `json:"window_end"`
+	DispatchesCompleted int         `json:"dispatches_completed"`
+	DispatchesFailed    int         `json:"dispatches_failed"`
+	Moves               []StageMove `json:"moves"`
+	CostUSD             float64     `json:"cost_usd"`
+	Blockers            []Blocker   `json:"blockers"`
+	Text                string      `json:"text"` // the message posted to the project room
+	CreatedAt           time.Time   `json:"created_at"`
+}
+
+// migrateStandupDigestsTable creates the standup_digests table. Called from migrate().
+func migrateStandupDigestsTable(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS standup_digests (
+			project TEXT NOT NULL,
+			digest_date TEXT NOT NULL,
+			window_start DATETIME NOT NULL,
+			window_end DATETIME NOT NULL,
+			digest TEXT NOT NULL,
+			created_at DATETIME NOT NULL DEFAULT (datetime('now')),
+			PRIMARY KEY (project, digest_date)
+		)
+	`); err != nil {
+		return fmt.Errorf("create standup_digests table: %w", err)
+	}
+	return nil
+}
+
+// ListStageMoves returns the stage moves of a project's beads within
+// [from, to), oldest first, from the bead stage histories.
+func (s *Store) ListStageMoves(project string, from, to time.Time) ([]StageMove, error) {
+	stages, err := s.ListBeadStagesForProject(project)
+	if err != nil {
+		return nil, err
+	}
+	var moves []StageMove
+	for _, bs := range stages {
+		for i := 1; i < len(bs.StageHistory); i++ {
+			e := bs.StageHistory[i]
+			if e.StartedAt.Before(from) || !e.StartedAt.Before(to) {
+				continue
+			}
+			moves = append(moves, StageMove{BeadID: bs.BeadID, From: bs.StageHistory[i-1].Stage, To: e.Stage, At: e.StartedAt})
+		}
+	}
+	sort.Slice(moves, func(i, j int) bool {
+		if !moves[i].At.Equal(moves[j].At) {
+			return moves[i].At.Before(moves[j].At)
+		}
+		return moves[i].BeadID < moves[j].BeadID
+	})
+	return moves, nil
+}
+
+// UpsertStandupDigest stores a project's digest for its date, replacing a
+// digest generated earlier the same day.
+func (s *Store) UpsertStandupDigest(d StandupDigest) error {
+	createdAt := d.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	d.CreatedAt = createdAt.UTC()
+	body, err := json.Marshal(d)
+	if err != nil {
+		return fmt.Errorf("store: upsert standup digest: encode: %w", err)
+	}
+	_, err = s.db.Exec(`
+		INSERT INTO standup_digests (project, digest_date, window_start, window_end, digest, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (project, digest_date) DO UPDATE SET
+			window_start = excluded.window_start,
+			window_end = excluded.window_end,
+			digest = excluded.digest,
+			created_at = excluded.created_at`,
+		d.Project, d.Date,
+		d.WindowStart.UTC().Format(time.DateTime), d.WindowEnd.UTC().Format(time.DateTime),
+		string(body), d.CreatedAt.Format(time.DateTime),
+	)
+	if err != nil {
+		return fmt.Errorf("store: upsert standup digest: %w", err)
+	}
+	return nil
+}
+
+// ListStandupDigests returns up to limit digests, newest first. An empty
+// project lists every project.
+func (s *Store) ListStandupDigests(project string, limit int) ([]StandupDigest, error) {
+	rows, err := s.db.Query(`
+		SELECT digest FROM standup_digests
+		WHERE (? = '' OR project = ?)
+		ORDER BY digest_date DESC, project
+		LIMIT ?`,
+		project, project, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("store: list standup digests: %w", err)
+	}
+	defer rows.Close()
+
+	var out []StandupDigest
+	for rows.Next() {
+		var body string
+		if err := rows.Scan(&body); err != nil {
+			return nil, fmt.Errorf("store: scan standup digest: %w", err)
+		}
+		var d StandupDigest
+		if err := json.Unmarshal([]byte(body), &d); err != nil {
+			return nil, fmt.Errorf("store: decode standup digest: %w", err)
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,70 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestListStageMoves(t *testing.T) {
+	s := tempStore(t)
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	if err := s.UpsertBeadStage(&BeadStage{
+		Project: "alpha", BeadID: "a-1", Workflow: "dev", CurrentStage: "review", TotalStages: 2,
+		StageHistory: []StageHistoryEntry{
+			{Stage: "implement", StartedAt: day.Add(-30 * time.Hour)},
+			{Stage: "review", StartedAt: day.Add(-2 * time.Hour)},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpsertBeadStage(&BeadStage{
+		Project: "alpha", BeadID: "a-2", Workflow: "dev", CurrentStage: "implement", TotalStages: 2,
+		StageHistory: []StageHistoryEntry{{Stage: "implement", StartedAt: day.Add(-time.Hour)}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	moves, err := s.ListStageMoves("alpha", day.Add(-24*time.Hour), day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(moves) != 1 || moves[0].BeadID != "a-1" || moves[0].From != "implement" || moves[0].To != "review" {
+		t.Fatalf("unexpected moves: %+v", moves)
+	}
+}
+
+func TestStandupDigests(t *testing.T) {
+	s := tempStore(t)
+	end := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	for _, d := range []StandupDigest{
+		{Project: "alpha", Date: "2026-03-01", DispatchesCompleted: 1},
+		{Project: "alpha", Date: "2026-03-02", DispatchesCompleted: 2},
+		{Project: "beta", Date: "2026-03-02", DispatchesFailed: 1},
+		// Regenerating a day replaces it.
+		{Project: "alpha", Date: "2026-03-02", DispatchesCompleted: 3, Blockers: []Blocker{{BeadID: "a-9", BlockedBy: []string{"a-1"}}}},
+	} {
+		d.WindowStart, d.WindowEnd = end.Add(-24*time.Hour), end
+		if err := s.UpsertStandupDigest(d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := s.ListStandupDigests("alpha", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Date != "2026-03-02" || got[0].DispatchesCompleted != 3 || got[0].Blockers[0].BlockedBy[0] != "a-1" {
+		t.Fatalf("unexpected alpha digests: %+v", got)
+	}
+	if !got[0].WindowEnd.Equal(end) || got[0].CreatedAt.IsZero() {
+		t.Fatalf("unexpected digest window: %+v", got[0])
+	}
+
+	all, err := s.ListStandupDigests("", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all[0].Project != "alpha" || all[1].Project != "beta" {
+		t.Fatalf("unexpected latest digests: %+v", all)
+	}
+}
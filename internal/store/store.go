@@ -4,7 +4,9 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	_ "modernc.org/sqlite"
@@ -12,8 +14,14 @@ import (
 
 // Store provides SQLite-backed persistence for Cortex state.
 type Store struct {
-	db                  *sql.DB
+	db                  *retryDB
 	dispatchPersistHook func(point string) error
+
+	redactMu     sync.RWMutex
+	redactOutput OutputRedactor
+
+	severityMu        sync.RWMutex
+	severityOverrides map[string]string
 }
 
 // Dispatch represents a dispatched agent task.
@@ -69,6 +77,9 @@ type HealthEvent struct {
 	Details    string
 	DispatchID int64
 	BeadID     string
+	Source     string // reporting system; empty for scheduler-internal events
+	Project    string // project the event concerns; empty for instance-wide events
+	Severity   string // HealthSeverityInfo, HealthSeverityWarn or HealthSeverityCritical
 	CreatedAt  time.Time
 }
 
@@ -125,6 +136,7 @@ type StageHistoryEntry struct {
 	StartedAt   time.Time  `json:"started_at"`
 	CompletedAt *time.Time `json:"completed_at,omitempty"`
 	DispatchID  int64      `json:"dispatch_id,omitempty"`
+	ApprovedBy  string     `json:"approved_by,omitempty"` // approver who let the bead into this stage
 }
 
 // BeadStage is the persisted workflow stage state for a bead in a project.
@@ -219,6 +231,9 @@ CREATE TABLE IF NOT EXISTS health_events (
 	details TEXT NOT NULL DEFAULT '',
 	dispatch_id INTEGER NOT NULL DEFAULT 0,
 	bead_id TEXT NOT NULL DEFAULT '',
+	source TEXT NOT NULL DEFAULT '',
+	project TEXT NOT NULL DEFAULT '',
+	severity TEXT NOT NULL DEFAULT 'info',
 	created_at DATETIME NOT NULL DEFAULT (datetime('now'))
 );
 
@@ -310,15 +325,6 @@ CREATE TABLE IF NOT EXISTS sprint_boundaries (
 	created_at DATETIME NOT NULL DEFAULT (datetime('now'))
 );
 
-CREATE TABLE IF NOT EXISTS execution_plan_gate (
-	id INTEGER PRIMARY KEY CHECK (id = 1),
-	active_plan_id TEXT NOT NULL DEFAULT '',
-	approved_by TEXT NOT NULL DEFAULT '',
-	approved_at DATETIME,
-	activated_at DATETIME,
-	updated_at DATETIME NOT NULL DEFAULT (datetime('now'))
-);
-
 CREATE UNIQUE INDEX IF NOT EXISTS idx_bead_stages_project_bead ON bead_stages(project, bead_id);
 CREATE INDEX IF NOT EXISTS idx_bead_stages_project_stage ON bead_stages(project, current_stage);
 CREATE INDEX IF NOT EXISTS idx_dispatches_status ON dispatches(status);
@@ -331,7 +337,6 @@ CREATE INDEX IF NOT EXISTS idx_safety_blocks_scope_type ON safety_blocks(scope,
 CREATE INDEX IF NOT EXISTS idx_safety_blocks_blocked_until ON safety_blocks(blocked_until);
 CREATE INDEX IF NOT EXISTS idx_sprint_boundaries_start ON sprint_boundaries(sprint_start);
 CREATE INDEX IF NOT EXISTS idx_sprint_boundaries_end ON sprint_boundaries(sprint_end);
-CREATE INDEX IF NOT EXISTS idx_execution_plan_gate_active ON execution_plan_gate(active_plan_id);
 CREATE INDEX IF NOT EXISTS idx_usage_provider ON provider_usage(provider, dispatched_at);
 CREATE INDEX IF NOT EXISTS idx_dispatch_output_dispatch ON dispatch_output(dispatch_id);
 CREATE INDEX IF NOT EXISTS idx_quality_scores_provider_role ON quality_scores(provider, role, recorded_at);
@@ -375,7 +380,7 @@ func Open(dbPath string) (*Store, error) {
 		return nil, fmt.Errorf("store: migrate: %w", err)
 	}
 
-	return &Store{db: db}, nil
+	return &Store{db: &retryDB{DB: db}}, nil
 }
 
 // migrate applies incremental schema migrations for existing databases.
@@ -573,6 +578,26 @@ func migrate(db *sql.DB) error {
 		}
 	}
 
+	err = db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('health_events') WHERE name = 'source'`).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("check health_events source column: %w", err)
+	}
+	if count == 0 {
+		if _, err := db.Exec(`ALTER TABLE health_events ADD COLUMN source TEXT NOT NULL DEFAULT ''`); err != nil {
+			return fmt.Errorf("add health_events source column: %w", err)
+		}
+	}
+
+	err = db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('health_events') WHERE name = 'project'`).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("check health_events project column: %w", err)
+	}
+	if count == 0 {
+		if _, err := db.Exec(`ALTER TABLE health_events ADD COLUMN project TEXT NOT NULL DEFAULT ''`); err != nil {
+			return fmt.Errorf("add health_events project column: %w", err)
+		}
+	}
+
 	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_health_events_dispatch ON health_events(dispatch_id)`); err != nil {
 		return fmt.Errorf("create health_events dispatch index: %w", err)
 	}
@@ -618,20 +643,6 @@ func migrate(db *sql.DB) error {
 	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_sprint_boundaries_end ON sprint_boundaries(sprint_end)`); err != nil {
 		return fmt.Errorf("create sprint_boundaries end index: %w", err)
 	}
-	if _, err := db.Exec(`
-		CREATE TABLE IF NOT EXISTS execution_plan_gate (
-			id INTEGER PRIMARY KEY CHECK (id = 1),
-			active_plan_id TEXT NOT NULL DEFAULT '',
-			approved_by TEXT NOT NULL DEFAULT '',
-			approved_at DATETIME,
-			activated_at DATETIME,
-			updated_at DATETIME NOT NULL DEFAULT (datetime('now'))
-		)`); err != nil {
-		return fmt.Errorf("create execution_plan_gate table: %w", err)
-	}
-	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_execution_plan_gate_active ON execution_plan_gate(active_plan_id)`); err != nil {
-		return fmt.Errorf("create execution_plan_gate active index: %w", err)
-	}
 	if _, err := db.Exec(`
 		CREATE TABLE IF NOT EXISTS overflow_queue (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -709,6 +720,103 @@ func migrate(db *sql.DB) error {
 		return err
 	}
 
+	if err := migrateProviderWarmupsTable(db); err != nil {
+		return err
+	}
+
+	if err := migrateExperimentRunsTable(db); err != nil {
+		return err
+	}
+
+	if err := migratePRReviewsTable(db); err != nil {
+		return err
+	}
+
+	if err := migrateDispatchConfidenceTable(db); err != nil {
+		return err
+	}
+
+	if err := migrateCandidateScansTables(db); err != nil {
+		return err
+	}
+
+	if err := migrateAgentRunsTable(db); err != nil {
+		return err
+	}
+	if err := migratePromptAttemptsTable(db); err != nil {
+		return err
+	}
+	if err := migrateSprintVelocityTable(db); err != nil {
+		return err
+	}
+	if err := migrateStageSLABreachesTable(db); err != nil {
+		return err
+	}
+	if err := migrateStandupDigestsTable(db); err != nil {
+		return err
+	}
+	if err := migrateProjectPausesTable(db); err != nil {
+		return err
+	}
+	if err := migrateDispatchOutputFTS(db); err != nil {
+		return err
+	}
+	if err := migrateArtifactsTable(db); err != nil {
+		return err
+	}
+	if err := migrateExecutionPlanGates(db); err != nil {
+		return err
+	}
+	if err := migrateBeadFilesTable(db); err != nil {
+		return err
+	}
+	if err := migrateLeasesTable(db); err != nil {
+		return err
+	}
+	if err := migrateDispatchListIndexes(db); err != nil {
+		return err
+	}
+	if err := migrateResponseCacheTable(db); err != nil {
+		return err
+	}
+	if err := migrateBeadFeaturesTable(db); err != nil {
+		return err
+	}
+	if err := migrateTeamAgentsTable(db); err != nil {
+		return err
+	}
+	if err := migrateOutputChunksTable(db); err != nil {
+		return err
+	}
+	if err := migrateOutputRedactions(db); err != nil {
+		return err
+	}
+	if err := migrateCoverageBaselinesTable(db); err != nil {
+		return err
+	}
+	if err := migrateHealthEventSeverity(db); err != nil {
+		return err
+	}
+	if err := migrateApprovalRequestsTable(db); err != nil {
+		return err
+	}
+	if err := migrateOverflowWaitsTable(db); err != nil {
+		return err
+	}
+	if err := migrateDispatchReportsTable(db); err != nil {
+		return err
+	}
+
+	err = db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('dod_results') WHERE name = 'findings'`).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("check dod_results findings column: %w", err)
+	}
+	if count == 0 {
+		if _, err := db.Exec(`ALTER TABLE dod_results ADD COLUMN findings TEXT NOT NULL DEFAULT ''`); err != nil {
+			return fmt.Errorf("add dod_results findings column: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -752,7 +860,7 @@ func (s *Store) Close() error {
 
 // DB returns the underlying sql.DB for advanced queries.
 func (s *Store) DB() *sql.DB {
-	return s.db
+	return s.db.DB
 }
 
 // RecordDispatch inserts a new dispatch record and returns its ID.
@@ -894,6 +1002,39 @@ func (s *Store) MarkDispatchPendingRetry(id int64, nextTier string, nextRetryAt
 	return nil
 }
 
+// MarkDispatchRetried records that a pending retry was relaunched by other
+// means, so the retry policy leaves it alone.
+func (s *Store) MarkDispatchRetried(id int64) error {
+	_, err := s.db.Exec(
+		`UPDATE dispatches SET status = 'retried', stage = 'retried', next_retry_at = NULL WHERE id = ? AND status = 'pending_retry'`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("store: mark dispatch retried: %w", err)
+	}
+	return nil
+}
+
+// GetLatestBeadPR returns the PR opened by a bead's most recent dispatch
+// that opened one, or a zero number if none did.
+func (s *Store) GetLatestBeadPR(project, beadID string) (int, string, error) {
+	var number int
+	var url string
+	err := s.db.QueryRow(
+		`SELECT pr_number, pr_url FROM dispatches
+		 WHERE project = ? AND bead_id = ? AND pr_number > 0
+		 ORDER BY id DESC LIMIT 1`,
+		project, beadID,
+	).Scan(&number, &url)
+	if err == sql.ErrNoRows {
+		return 0, "", nil
+	}
+	if err != nil {
+		return 0, "", fmt.Errorf("store: get latest bead PR: %w", err)
+	}
+	return number, url, nil
+}
+
 // UpdateDispatchPR updates a dispatch's PR information.
 func (s *Store) UpdateDispatchPR(id int64, prURL string, prNumber int) error {
 	_, err := s.db.Exec(
@@ -961,6 +1102,21 @@ func (s *Store) GetRunningDispatches() ([]Dispatch, error) {
 	return s.queryDispatches(`SELECT ` + dispatchCols + ` FROM dispatches WHERE status = 'running'`)
 }
 
+// GetRecentFailedDispatches returns the most recent failed dispatches, newest first.
+func (s *Store) GetRecentFailedDispatches(limit int) ([]Dispatch, error) {
+	return s.queryDispatches(`SELECT `+dispatchCols+` FROM dispatches WHERE status = 'failed' ORDER BY dispatched_at DESC, id DESC LIMIT ?`, limit)
+}
+
+// CountPendingRetryDispatches returns how many dispatches are waiting to be retried,
+// whether or not their retry time has come.
+func (s *Store) CountPendingRetryDispatches() (int, error) {
+	var count int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM dispatches WHERE status = 'pending_retry'`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("store: count pending retry dispatches: %w", err)
+	}
+	return count, nil
+}
+
 // ProjectDispatchStatusCounts summarizes dispatch counts per project within a time window.
 type ProjectDispatchStatusCounts struct {
 	Project   string
@@ -1028,6 +1184,24 @@ func (s *Store) GetCompletedDispatchesSince(projectName, since string) ([]Dispat
 	return s.queryDispatches(`SELECT `+dispatchCols+` FROM dispatches WHERE project = ? AND status = 'completed' AND dispatched_at >= ? ORDER BY dispatched_at DESC`, projectName, since)
 }
 
+// GetLastCompletionAt returns when the project's most recent completed
+// dispatch finished, or the zero time when none has.
+func (s *Store) GetLastCompletionAt(projectName string) (time.Time, error) {
+	var raw sql.NullString
+	err := s.db.QueryRow(`SELECT MAX(completed_at) FROM dispatches WHERE project = ? AND status = 'completed'`, projectName).Scan(&raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("store: last completion: %w", err)
+	}
+	if !raw.Valid || raw.String == "" {
+		return time.Time{}, nil
+	}
+	ts, err := parseSQLiteTime(raw.String)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("store: parse last completion %q: %w", raw.String, err)
+	}
+	return ts, nil
+}
+
 // WasBeadDispatchedRecently checks if a bead has been dispatched within the cooldown period.
 // Returns true if the bead should be skipped due to recent dispatch activity.
 func (s *Store) WasBeadDispatchedRecently(beadID string, cooldownPeriod time.Duration) (bool, error) {
@@ -1201,10 +1375,24 @@ func (s *Store) EnqueueOverflowItem(beadID, project, role, agentID string, prior
 	return id, nil
 }
 
-// RemoveOverflowItem deletes all persisted queue items for a bead.
+// RemoveOverflowItem deletes all persisted queue items for a bead, keeping
+// how long each waited for the queue wait statistics.
 func (s *Store) RemoveOverflowItem(beadID string) (int64, error) {
 	beadID = strings.TrimSpace(beadID)
-	result, err := s.db.Exec(`DELETE FROM overflow_queue WHERE bead_id = ?`, beadID)
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("store: remove overflow item: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		INSERT INTO overflow_waits (bead_id, project, role, enqueued_at, dequeued_at, wait_seconds)
+		SELECT bead_id, project, role, enqueued_at, datetime('now'),
+			MAX(0, CAST(ROUND((julianday('now') - julianday(enqueued_at)) * 86400) AS INTEGER))
+		FROM overflow_queue WHERE bead_id = ?`, beadID); err != nil {
+		return 0, fmt.Errorf("store: record overflow wait: %w", err)
+	}
+	result, err := tx.Exec(`DELETE FROM overflow_queue WHERE bead_id = ?`, beadID)
 	if err != nil {
 		return 0, fmt.Errorf("store: remove overflow item: %w", err)
 	}
@@ -1212,6 +1400,9 @@ func (s *Store) RemoveOverflowItem(beadID string) (int64, error) {
 	if err != nil {
 		return 0, fmt.Errorf("store: overflow rows affected: %w", err)
 	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("store: remove overflow item: %w", err)
+	}
 	return affected, nil
 }
 
@@ -1528,20 +1719,66 @@ func (s *Store) queryDispatches(query string, args ...any) ([]Dispatch, error) {
 
 	var dispatches []Dispatch
 	for rows.Next() {
-		var d Dispatch
-		if err := rows.Scan(
-			&d.ID, &d.BeadID, &d.Project, &d.AgentID, &d.Provider, &d.Tier, &d.PID, &d.SessionName,
-			&d.Prompt, &d.DispatchedAt, &d.CompletedAt, &d.NextRetryAt, &d.Status, &d.Stage, &d.Labels, &d.PRURL, &d.PRNumber, &d.ExitCode, &d.DurationS,
-			&d.Retries, &d.EscalatedFromTier, &d.FailureCategory, &d.FailureSummary, &d.LogPath, &d.Branch, &d.Backend,
-			&d.InputTokens, &d.OutputTokens, &d.CostUSD,
-		); err != nil {
-			return nil, fmt.Errorf("store: scan dispatch: %w", err)
+		d, err := scanDispatch(rows)
+		if err != nil {
+			return nil, err
 		}
 		dispatches = append(dispatches, d)
 	}
 	return dispatches, rows.Err()
 }
 
+func scanDispatch(r rowScanner) (Dispatch, error) {
+	var d Dispatch
+	if err := r.Scan(
+		&d.ID, &d.BeadID, &d.Project, &d.AgentID, &d.Provider, &d.Tier, &d.PID, &d.SessionName,
+		&d.Prompt, &d.DispatchedAt, &d.CompletedAt, &d.NextRetryAt, &d.Status, &d.Stage, &d.Labels, &d.PRURL, &d.PRNumber, &d.ExitCode, &d.DurationS,
+		&d.Retries, &d.EscalatedFromTier, &d.FailureCategory, &d.FailureSummary, &d.LogPath, &d.Branch, &d.Backend,
+		&d.InputTokens, &d.OutputTokens, &d.CostUSD,
+	); err != nil {
+		return d, fmt.Errorf("store: scan dispatch: %w", err)
+	}
+	return d, nil
+}
+
+// EachDispatch calls fn for every dispatch started in [from, to), oldest
+// first, one row at a time so large exports never hold the table in memory.
+// A zero from or to leaves that end of the range open. An error from fn
+// stops the iteration and is returned as is.
+func (s *Store) EachDispatch(from, to time.Time, fn func(Dispatch) error) error {
+	query := `SELECT ` + dispatchCols + ` FROM dispatches WHERE 1=1`
+	var args []any
+	if !from.IsZero() {
+		query += ` AND dispatched_at >= ?`
+		args = append(args, from.UTC().Format(time.DateTime))
+	}
+	if !to.IsZero() {
+		query += ` AND dispatched_at < ?`
+		args = append(args, to.UTC().Format(time.DateTime))
+	}
+	query += ` ORDER BY dispatched_at ASC, id ASC`
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("store: query dispatches: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		d, err := scanDispatch(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(d); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("store: iterate dispatches: %w", err)
+	}
+	return nil
+}
+
 func scanClaimLeases(rows *sql.Rows) ([]ClaimLease, error) {
 	var leases []ClaimLease
 	for rows.Next() {
@@ -1642,14 +1879,26 @@ func (s *Store) RecordHealthEvent(eventType, details string) error {
 	return s.RecordHealthEventWithDispatch(eventType, details, 0, "")
 }
 
+// RecordProjectHealthEvent records a health event concerning one project.
+func (s *Store) RecordProjectHealthEvent(project, eventType, details string) error {
+	_, err := s.db.Exec(
+		`INSERT INTO health_events (event_type, details, project, severity) VALUES (?, ?, ?, ?)`,
+		eventType, details, strings.TrimSpace(project), s.HealthEventSeverity(eventType),
+	)
+	if err != nil {
+		return fmt.Errorf("store: record health event: %w", err)
+	}
+	return nil
+}
+
 // RecordHealthEventWithDispatch records a health event with optional dispatch/bead correlation.
 func (s *Store) RecordHealthEventWithDispatch(eventType, details string, dispatchID int64, beadID string) error {
 	if dispatchID < 0 {
 		dispatchID = 0
 	}
 	_, err := s.db.Exec(
-		`INSERT INTO health_events (event_type, details, dispatch_id, bead_id) VALUES (?, ?, ?, ?)`,
-		eventType, details, dispatchID, strings.TrimSpace(beadID),
+		`INSERT INTO health_events (event_type, details, dispatch_id, bead_id, severity) VALUES (?, ?, ?, ?, ?)`,
+		eventType, details, dispatchID, strings.TrimSpace(beadID), s.HealthEventSeverity(eventType),
 	)
 	if err != nil {
 		return fmt.Errorf("store: record health event: %w", err)
@@ -1657,6 +1906,54 @@ func (s *Store) RecordHealthEventWithDispatch(eventType, details string, dispatc
 	return nil
 }
 
+// RecordHealthEvents inserts a batch of externally reported health events in
+// one transaction, so a batch is either stored whole or not at all. Events
+// without a CreatedAt are stamped with the current time, and events without
+// a Severity get their type's.
+func (s *Store) RecordHealthEvents(events []HealthEvent) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("store: begin health events transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`INSERT INTO health_events (event_type, details, dispatch_id, bead_id, source, project, severity, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("store: prepare health event insert: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now().UTC()
+	for i, e := range events {
+		if strings.TrimSpace(e.EventType) == "" {
+			return 0, fmt.Errorf("store: record health events: event %d has no event_type", i)
+		}
+		if e.DispatchID < 0 {
+			e.DispatchID = 0
+		}
+		createdAt := now
+		if !e.CreatedAt.IsZero() {
+			createdAt = e.CreatedAt.UTC()
+		}
+		severity := strings.TrimSpace(e.Severity)
+		if severity == "" {
+			severity = s.HealthEventSeverity(strings.TrimSpace(e.EventType))
+		}
+		if !slices.Contains(HealthSeverities, severity) {
+			return 0, fmt.Errorf("store: record health events: event %d has unknown severity %q", i, severity)
+		}
+		if _, err := stmt.Exec(strings.TrimSpace(e.EventType), e.Details, e.DispatchID, strings.TrimSpace(e.BeadID),
+			strings.TrimSpace(e.Source), strings.TrimSpace(e.Project), severity, createdAt.Format(time.DateTime)); err != nil {
+			return 0, fmt.Errorf("store: record health event %d: %w", i, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("store: commit health events transaction: %w", err)
+	}
+	return len(events), nil
+}
+
 // RecordTickMetrics records metrics for a scheduler tick.
 func (s *Store) RecordTickMetrics(project string, open, ready, dispatched, completed, failed, stuck int) error {
 	_, err := s.db.Exec(
@@ -1713,7 +2010,7 @@ func (s *Store) GetCurrentSprintNumber() (int, error) {
 // GetRecentHealthEvents returns health events from the last N hours.
 func (s *Store) GetRecentHealthEvents(hours int) ([]HealthEvent, error) {
 	rows, err := s.db.Query(
-		`SELECT id, event_type, details, dispatch_id, bead_id, created_at FROM health_events WHERE created_at >= datetime('now', ? || ' hours') ORDER BY created_at DESC`,
+		`SELECT id, event_type, details, dispatch_id, bead_id, source, project, severity, created_at FROM health_events WHERE created_at >= datetime('now', ? || ' hours') ORDER BY created_at DESC`,
 		fmt.Sprintf("-%d", hours),
 	)
 	if err != nil {
@@ -1724,7 +2021,7 @@ func (s *Store) GetRecentHealthEvents(hours int) ([]HealthEvent, error) {
 	var events []HealthEvent
 	for rows.Next() {
 		var e HealthEvent
-		if err := rows.Scan(&e.ID, &e.EventType, &e.Details, &e.DispatchID, &e.BeadID, &e.CreatedAt); err != nil {
+		if err := rows.Scan(&e.ID, &e.EventType, &e.Details, &e.DispatchID, &e.BeadID, &e.Source, &e.Project, &e.Severity, &e.CreatedAt); err != nil {
 			return nil, fmt.Errorf("store: scan health event: %w", err)
 		}
 		events = append(events, e)
@@ -1732,6 +2029,30 @@ func (s *Store) GetRecentHealthEvents(hours int) ([]HealthEvent, error) {
 	return events, rows.Err()
 }
 
+// CountHealthEvents counts health events of the given types recorded in
+// [from, to).
+func (s *Store) CountHealthEvents(eventTypes []string, from, to time.Time) (int, error) {
+	if len(eventTypes) == 0 {
+		return 0, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(eventTypes)), ",")
+	args := make([]any, 0, len(eventTypes)+2)
+	for _, t := range eventTypes {
+		args = append(args, t)
+	}
+	args = append(args, from.UTC().Format(time.DateTime), to.UTC().Format(time.DateTime))
+
+	var n int
+	err := s.db.QueryRow(
+		`SELECT COUNT(*) FROM health_events WHERE event_type IN (`+placeholders+`) AND created_at >= ? AND created_at < ?`,
+		args...,
+	).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("store: count health events: %w", err)
+	}
+	return n, nil
+}
+
 // IsBeadDispatched checks if a bead currently has a running dispatch.
 func (s *Store) IsBeadDispatched(beadID string) (bool, error) {
 	var count int
@@ -1757,9 +2078,19 @@ func (s *Store) IsAgentBusy(project, agent string) (bool, error) {
 
 // CaptureOutput captures and stores agent output from a completed dispatch.
 // Output is truncated to 500KB max. The tail contains the last 100 lines.
+// Secrets are redacted first when an output redactor is set.
 func (s *Store) CaptureOutput(dispatchID int64, output string) error {
+	return s.captureOutput(dispatchID, output, 0)
+}
+
+// captureOutput stores output after the redaction pass, adding the secrets
+// it replaces to those already redacted upstream.
+func (s *Store) captureOutput(dispatchID int64, output string, redactions int) error {
 	const maxOutputBytes = 500 * 1024 // 500KB
 
+	output, n := s.redact(output)
+	redactions += n
+
 	outputBytes := int64(len(output))
 
 	// Truncate output if too large
@@ -1778,8 +2109,8 @@ func (s *Store) CaptureOutput(dispatchID int64, output string) error {
 	outputTail := extractTail(output, 100)
 
 	_, err := s.db.Exec(
-		`INSERT INTO dispatch_output (dispatch_id, output, output_tail, output_bytes) VALUES (?, ?, ?, ?)`,
-		dispatchID, output, outputTail, outputBytes,
+		`INSERT INTO dispatch_output (dispatch_id, output, output_tail, output_bytes, redactions) VALUES (?, ?, ?, ?, ?)`,
+		dispatchID, output, outputTail, outputBytes, redactions,
 	)
 	if err != nil {
 		return fmt.Errorf("store: capture output: %w", err)
@@ -1947,9 +2278,15 @@ func (s *Store) RecordDispatchCost(dispatchID int64, inputTokens, outputTokens i
 
 // RecordDoDResult records the results of a Definition of Done check.
 func (s *Store) RecordDoDResult(dispatchID int64, beadID, project string, passed bool, failures string, checkResults string) error {
+	return s.RecordDoDResultWithFindings(dispatchID, beadID, project, passed, failures, checkResults, "")
+}
+
+// RecordDoDResultWithFindings records a Definition of Done check together
+// with the security findings (a JSON array of DoDFinding) it reported.
+func (s *Store) RecordDoDResultWithFindings(dispatchID int64, beadID, project string, passed bool, failures, checkResults, findings string) error {
 	_, err := s.db.Exec(
-		`INSERT INTO dod_results (dispatch_id, bead_id, project, passed, failures, check_results) VALUES (?, ?, ?, ?, ?, ?)`,
-		dispatchID, beadID, project, passed, failures, checkResults,
+		`INSERT INTO dod_results (dispatch_id, bead_id, project, passed, failures, check_results, findings) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		dispatchID, beadID, project, passed, failures, checkResults, findings,
 	)
 	if err != nil {
 		return fmt.Errorf("store: record DoD result: %w", err)
@@ -1957,6 +2294,61 @@ func (s *Store) RecordDoDResult(dispatchID int64, beadID, project string, passed
 	return nil
 }
 
+// DoDCheck is one recorded DoD check command and whether it passed.
+type DoDCheck struct {
+	Command string `json:"command"`
+	Passed  bool   `json:"passed"`
+}
+
+// DoDFinding is a security finding recorded with a DoD run.
+type DoDFinding struct {
+	Scanner  string `json:"scanner"`
+	RuleID   string `json:"rule_id"`
+	Severity string `json:"severity"`
+	Path     string `json:"path,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Message  string `json:"message"`
+}
+
+// DoDRecord is a recorded Definition of Done run.
+type DoDRecord struct {
+	DispatchID int64
+	Passed     bool
+	Failures   string
+	Checks     []DoDCheck
+	Findings   []DoDFinding
+	CheckedAt  time.Time
+}
+
+// GetLatestDoDResult returns the most recent DoD run for a bead, or nil if
+// it has none. Runs recorded without per-check results have no Checks.
+func (s *Store) GetLatestDoDResult(project, beadID string) (*DoDRecord, error) {
+	var r DoDRecord
+	var checks, findings string
+	err := s.db.QueryRow(
+		`SELECT dispatch_id, passed, failures, check_results, findings, checked_at FROM dod_results
+		 WHERE project = ? AND bead_id = ? ORDER BY id DESC LIMIT 1`,
+		project, beadID,
+	).Scan(&r.DispatchID, &r.Passed, &r.Failures, &checks, &findings, &r.CheckedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("store: get latest DoD result: %w", err)
+	}
+	if checks != "" {
+		if err := json.Unmarshal([]byte(checks), &r.Checks); err != nil {
+			return nil, fmt.Errorf("store: decode DoD check results: %w", err)
+		}
+	}
+	if findings != "" {
+		if err := json.Unmarshal([]byte(findings), &r.Findings); err != nil {
+			return nil, fmt.Errorf("store: decode DoD findings: %w", err)
+		}
+	}
+	return &r, nil
+}
+
 // GetDispatchCost returns token usage and cost for a dispatch.
 func (s *Store) GetDispatchCost(dispatchID int64) (inputTokens, outputTokens int, costUSD float64, err error) {
 	err = s.db.QueryRow(
@@ -2088,20 +2480,35 @@ func (s *Store) GetBeadStage(project, beadID string) (*BeadStage, error) {
 		return nil, fmt.Errorf("store: get bead stage: %w", err)
 	}
 
-	// Parse stage history JSON
-	if historyJSON != "" && historyJSON != "[]" {
-		// For simplicity, we'll store history as JSON string - proper JSON unmarshaling would be added in production
-		// This is a placeholder for the stage history parsing
-	}
+	stage.StageHistory = parseStageHistory(historyJSON)
 
 	return &stage, nil
 }
 
 // UpsertBeadStage creates or updates a bead stage using composite project+bead_id key.
+// A caller-supplied StageHistory replaces the stored one; otherwise a change of
+// CurrentStage is appended to the stored history as a transition.
 func (s *Store) UpsertBeadStage(stage *BeadStage) error {
-	historyJSON := "[]" // Placeholder for stage history JSON serialization
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("store: upsert bead stage: %w", err)
+	}
+	defer tx.Rollback()
+
+	history := stage.StageHistory
+	if len(history) == 0 {
+		existing, err := stageHistoryTx(tx, stage.Project, stage.BeadID)
+		if err != nil {
+			return fmt.Errorf("store: upsert bead stage: %w", err)
+		}
+		history = advanceStageHistory(existing, StageHistoryEntry{Stage: stage.CurrentStage}, time.Now())
+	}
+	historyJSON, err := json.Marshal(history)
+	if err != nil {
+		return fmt.Errorf("store: upsert bead stage: encode history: %w", err)
+	}
 
-	_, err := s.db.Exec(`
+	_, err = tx.Exec(`
 		INSERT INTO bead_stages (project, bead_id, workflow, current_stage, stage_index, 
 		                        total_stages, stage_history, updated_at)
 		VALUES (?, ?, ?, ?, ?, ?, ?, datetime('now'))
@@ -2113,32 +2520,113 @@ func (s *Store) UpsertBeadStage(stage *BeadStage) error {
 			stage_history = excluded.stage_history,
 			updated_at = datetime('now')`,
 		stage.Project, stage.BeadID, stage.Workflow, stage.CurrentStage,
-		stage.StageIndex, stage.TotalStages, historyJSON,
+		stage.StageIndex, stage.TotalStages, string(historyJSON),
 	)
-
 	if err != nil {
 		return fmt.Errorf("store: upsert bead stage: %w", err)
 	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("store: upsert bead stage: %w", err)
+	}
 
 	return nil
 }
 
-// UpdateBeadStageProgress advances a bead to the next stage in its workflow.
+// UpdateBeadStageProgress advances a bead to the next stage in its workflow,
+// recording the transition and the dispatch that runs the new stage.
 func (s *Store) UpdateBeadStageProgress(project, beadID, newStage string, stageIndex, totalStages int, dispatchID int64) error {
-	_, err := s.db.Exec(`
+	return s.TransitionBeadStage(project, beadID, stageIndex, totalStages, StageHistoryEntry{Stage: newStage, DispatchID: dispatchID})
+}
+
+// TransitionBeadStage moves a bead to next.Stage and appends next to its
+// stage history. Only the Stage, DispatchID and ApprovedBy fields of next are
+// used; status and timing are filled in here.
+func (s *Store) TransitionBeadStage(project, beadID string, stageIndex, totalStages int, next StageHistoryEntry) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("store: transition bead stage: %w", err)
+	}
+	defer tx.Rollback()
+
+	existing, err := stageHistoryTx(tx, project, beadID)
+	if err != nil {
+		return fmt.Errorf("store: transition bead stage: %w", err)
+	}
+	historyJSON, err := json.Marshal(advanceStageHistory(existing, next, time.Now()))
+	if err != nil {
+		return fmt.Errorf("store: transition bead stage: encode history: %w", err)
+	}
+
+	_, err = tx.Exec(`
 		UPDATE bead_stages 
-		SET current_stage = ?, stage_index = ?, total_stages = ?, updated_at = datetime('now')
+		SET current_stage = ?, stage_index = ?, total_stages = ?, stage_history = ?, updated_at = datetime('now')
 		WHERE project = ? AND bead_id = ?`,
-		newStage, stageIndex, totalStages, project, beadID,
+		next.Stage, stageIndex, totalStages, string(historyJSON), project, beadID,
 	)
-
 	if err != nil {
-		return fmt.Errorf("store: update bead stage progress: %w", err)
+		return fmt.Errorf("store: transition bead stage: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("store: transition bead stage: %w", err)
 	}
 
 	return nil
 }
 
+// isTerminalStage reports whether a stage ends a bead's workflow.
+func isTerminalStage(stage string) bool {
+	return stage == "done" || stage == "completed"
+}
+
+// advanceStageHistory records a move to next.Stage at now. The open entry is
+// closed and a new one started, unless the bead is already in that stage, in
+// which case only a missing dispatch ID is filled in. Terminal stages are
+// recorded as already completed.
+func advanceStageHistory(history []StageHistoryEntry, next StageHistoryEntry, now time.Time) []StageHistoryEntry {
+	now = now.UTC()
+	if n := len(history); n > 0 {
+		last := &history[n-1]
+		if last.Stage == next.Stage {
+			if last.DispatchID == 0 {
+				last.DispatchID = next.DispatchID
+			}
+			return history
+		}
+		if last.CompletedAt == nil {
+			last.Status = "completed"
+			last.CompletedAt = &now
+		}
+	}
+	entry := StageHistoryEntry{Stage: next.Stage, Status: "running", StartedAt: now, DispatchID: next.DispatchID, ApprovedBy: next.ApprovedBy}
+	if isTerminalStage(next.Stage) {
+		entry.Status = "completed"
+		entry.CompletedAt = &now
+	}
+	return append(history, entry)
+}
+
+func stageHistoryTx(tx *sql.Tx, project, beadID string) ([]StageHistoryEntry, error) {
+	var historyJSON string
+	err := tx.QueryRow(`SELECT stage_history FROM bead_stages WHERE project = ? AND bead_id = ?`, project, beadID).Scan(&historyJSON)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read stage history: %w", err)
+	}
+	return parseStageHistory(historyJSON), nil
+}
+
+// parseStageHistory decodes a stored stage history. Unreadable history is
+// treated as empty rather than failing the whole read.
+func parseStageHistory(historyJSON string) []StageHistoryEntry {
+	var history []StageHistoryEntry
+	if historyJSON == "" || json.Unmarshal([]byte(historyJSON), &history) != nil {
+		return nil
+	}
+	return history
+}
+
 // ListBeadStagesForProject retrieves all bead stages for a specific project.
 func (s *Store) ListBeadStagesForProject(project string) ([]*BeadStage, error) {
 	rows, err := s.db.Query(`
@@ -2167,6 +2655,7 @@ func (s *Store) ListBeadStagesForProject(project string) ([]*BeadStage, error) {
 		if err != nil {
 			return nil, fmt.Errorf("store: scan bead stage: %w", err)
 		}
+		stage.StageHistory = parseStageHistory(historyJSON)
 
 		stages = append(stages, &stage)
 	}
@@ -2316,6 +2805,36 @@ func (s *Store) GetProviderStats(window time.Duration) (map[string]ProviderStat,
 	return stats, nil
 }
 
+// CountProviderDispatchesSince returns how many dispatches each provider
+// started since the given time, running ones included.
+func (s *Store) CountProviderDispatchesSince(since time.Time) (map[string]int, error) {
+	rows, err := s.db.Query(`
+		SELECT provider, COUNT(*)
+		FROM dispatches
+		WHERE dispatched_at > ?
+		GROUP BY provider`,
+		since.UTC().Format(time.DateTime),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("store: count provider dispatches: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var provider string
+		var n int
+		if err := rows.Scan(&provider, &n); err != nil {
+			return nil, fmt.Errorf("store: scan provider dispatch count: %w", err)
+		}
+		counts[provider] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("store: provider dispatch count rows: %w", err)
+	}
+	return counts, nil
+}
+
 // GetProviderLabelStats aggregates provider performance by label within the time window.
 func (s *Store) GetProviderLabelStats(window time.Duration) (map[string]map[string]ProviderLabelStat, error) {
 	cutoff := time.Now().Add(-window).UTC().Format(time.DateTime)
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -140,6 +141,112 @@ func TestHealthEventsWithDispatchCorrelation(t *testing.T) {
 	}
 }
 
+func TestProjectHealthEvents(t *testing.T) {
+	s := tempStore(t)
+
+	if err := s.RecordProjectHealthEvent("api", "health_check_failed", "api-smoke failed (exit 1)"); err != nil {
+		t.Fatal(err)
+	}
+	events, err := s.GetRecentHealthEvents(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Project != "api" || events[0].EventType != "health_check_failed" {
+		t.Fatalf("unexpected events %+v", events)
+	}
+}
+
+func TestGetLastCompletionAt(t *testing.T) {
+	s := tempStore(t)
+
+	if ts, err := s.GetLastCompletionAt("proj"); err != nil || !ts.IsZero() {
+		t.Fatalf("expected no completion, got %v, %v", ts, err)
+	}
+	id, err := s.RecordDispatch("bead-1", "proj", "agent-1", "cerebras", "fast", 100, "session", "prompt", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpdateDispatchStatus(id, "completed", 0, 1); err != nil {
+		t.Fatal(err)
+	}
+	ts, err := s.GetLastCompletionAt("proj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(ts) > time.Minute {
+		t.Fatalf("last completion = %v", ts)
+	}
+	if ts, _ := s.GetLastCompletionAt("other"); !ts.IsZero() {
+		t.Fatalf("other project completion = %v", ts)
+	}
+}
+
+func TestRecordHealthEventsBatch(t *testing.T) {
+	s := tempStore(t)
+
+	n, err := s.RecordHealthEvents([]HealthEvent{
+		{EventType: "disk_pressure", Details: "/var at 91%", Source: "node-exporter"},
+		{EventType: "backup_completed", Details: "nightly", Source: "db-backup", CreatedAt: time.Now().Add(-10 * time.Minute)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("recorded = %d, want 2", n)
+	}
+
+	events, err := s.GetRecentHealthEvents(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	sources := map[string]string{}
+	for _, e := range events {
+		sources[e.EventType] = e.Source
+	}
+	if sources["disk_pressure"] != "node-exporter" || sources["backup_completed"] != "db-backup" {
+		t.Fatalf("unexpected sources: %v", sources)
+	}
+
+	// A bad event rejects the whole batch.
+	if _, err := s.RecordHealthEvents([]HealthEvent{{EventType: "ok", Source: "x"}, {EventType: " "}}); err == nil {
+		t.Fatal("expected error for event without type")
+	}
+	events, _ = s.GetRecentHealthEvents(1)
+	if len(events) != 2 {
+		t.Fatalf("partial batch stored: got %d events", len(events))
+	}
+}
+
+func TestCountHealthEvents(t *testing.T) {
+	s := tempStore(t)
+	to := time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC)
+	from := to.Add(-24 * time.Hour)
+
+	if _, err := s.RecordHealthEvents([]HealthEvent{
+		{EventType: "gateway_critical", CreatedAt: to.Add(-time.Hour)},
+		{EventType: "dispatch_session_gone", CreatedAt: from},
+		{EventType: "gateway_restart", CreatedAt: to.Add(-time.Hour)},
+		{EventType: "gateway_critical", CreatedAt: from.Add(-time.Second)},
+		{EventType: "gateway_critical", CreatedAt: to},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := s.CountHealthEvents([]string{"gateway_critical", "dispatch_session_gone"}, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("count = %d, want 2", n)
+	}
+	if n, err := s.CountHealthEvents(nil, from, to); err != nil || n != 0 {
+		t.Fatalf("no types should count nothing, got %d, %v", n, err)
+	}
+}
+
 func TestGetLatestDispatchBySession(t *testing.T) {
 	s := tempStore(t)
 
@@ -540,6 +647,46 @@ func TestGetPendingRetryDispatches(t *testing.T) {
 	}
 }
 
+func TestGetRecentFailedDispatches(t *testing.T) {
+	s := tempStore(t)
+
+	var ids []int64
+	for _, bead := range []string{"bead-1", "bead-2", "bead-3"} {
+		id, err := s.RecordDispatch(bead, "proj", "agent-1", "cerebras", "fast", 100, "", "prompt", "", "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	for i, id := range ids[:2] {
+		if err := s.UpdateDispatchStatus(id, "failed", 1, 10); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.SetDispatchTime(id, time.Now().Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.DB().Exec("UPDATE dispatches SET status = 'pending_retry' WHERE id = ?", ids[2]); err != nil {
+		t.Fatal(err)
+	}
+
+	failed, err := s.GetRecentFailedDispatches(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(failed) != 1 || failed[0].BeadID != "bead-2" {
+		t.Fatalf("expected newest failure bead-2, got %+v", failed)
+	}
+
+	n, err := s.CountPendingRetryDispatches()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("expected 1 pending retry, got %d", n)
+	}
+}
+
 func TestGetPendingRetryDispatchesRespectsNextRetryAt(t *testing.T) {
 	s := tempStore(t)
 
@@ -753,6 +900,35 @@ func TestMarkDispatchPendingRetryUpdatesStageAndCompletion(t *testing.T) {
 	}
 }
 
+func TestMarkDispatchRetried(t *testing.T) {
+	s := tempStore(t)
+
+	id, err := s.RecordDispatch("bead-retry", "proj", "agent-1", "cerebras", "fast", 100, "", "prompt", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.MarkDispatchRetried(id); err != nil {
+		t.Fatal(err)
+	}
+	if d, _ := s.GetDispatchByID(id); d.Status != "running" {
+		t.Fatalf("expected a running dispatch to be left alone, got %s", d.Status)
+	}
+
+	if err := s.MarkDispatchPendingRetry(id, "balanced", time.Now().Add(5*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.MarkDispatchRetried(id); err != nil {
+		t.Fatal(err)
+	}
+	d, err := s.GetDispatchByID(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Status != "retried" || d.Stage != "retried" || d.NextRetryAt.Valid {
+		t.Fatalf("expected retried with no next retry, got status=%s stage=%s next=%v", d.Status, d.Stage, d.NextRetryAt)
+	}
+}
+
 func TestClaimLeaseLifecycle(t *testing.T) {
 	s := tempStore(t)
 
@@ -1617,6 +1793,14 @@ func TestProviderStatsExcludeNonTerminalDispatches(t *testing.T) {
 		t.Fatalf("expected 1 success in provider stats, got %d", openAI.Successes)
 	}
 
+	counts, err := s.CountProviderDispatchesSince(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counts["openai"] != 4 {
+		t.Fatalf("expected all 4 openai dispatches counted, got %d", counts["openai"])
+	}
+
 	labelStats, err := s.GetProviderLabelStats(time.Hour)
 	if err != nil {
 		t.Fatal(err)
@@ -2042,3 +2226,89 @@ func TestGetTokenUsageByDispatch_Empty(t *testing.T) {
 		t.Errorf("expected 0 records for nonexistent dispatch, got %d", len(records))
 	}
 }
+
+func TestEachDispatchRange(t *testing.T) {
+	s := tempStore(t)
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, bead := range []string{"b-0", "b-1", "b-2", "b-3"} {
+		id, err := s.RecordDispatch(bead, "proj", "agent", "claude", "balanced", 100+i, "", "prompt", "", "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.SetDispatchTime(id, base.Add(time.Duration(i)*24*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []string
+	err := s.EachDispatch(base.Add(24*time.Hour), base.Add(3*24*time.Hour), func(d Dispatch) error {
+		got = append(got, d.BeadID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, ",") != "b-1,b-2" {
+		t.Fatalf("expected b-1,b-2 in range, got %v", got)
+	}
+
+	got = nil
+	if err := s.EachDispatch(time.Time{}, time.Time{}, func(d Dispatch) error {
+		got = append(got, d.BeadID)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 4 || got[0] != "b-0" {
+		t.Fatalf("open range should return all dispatches oldest first, got %v", got)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	if err := s.EachDispatch(time.Time{}, time.Time{}, func(Dispatch) error { calls++; return stop }); err != stop || calls != 1 {
+		t.Fatalf("expected callback error to stop iteration, got err=%v calls=%d", err, calls)
+	}
+}
+
+func TestGetLatestDoDResult(t *testing.T) {
+	s := tempStore(t)
+
+	if r, err := s.GetLatestDoDResult("proj", "cx-1"); err != nil || r != nil {
+		t.Fatalf("expected no DoD result, got %+v, %v", r, err)
+	}
+	if err := s.RecordDoDResult(1, "cx-1", "proj", false, "go test failed", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RecordDoDResult(2, "cx-1", "proj", true, "", `[{"command":"go test ./...","exit_code":0,"passed":true}]`); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RecordDoDResult(3, "cx-1", "other", false, "", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := s.GetLatestDoDResult("proj", "cx-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.DispatchID != 2 || !r.Passed || len(r.Checks) != 1 || r.Checks[0] != (DoDCheck{Command: "go test ./...", Passed: true}) {
+		t.Fatalf("unexpected DoD result %+v", r)
+	}
+}
+
+func TestRecordDoDResultWithFindings(t *testing.T) {
+	s := tempStore(t)
+
+	findings := `[{"scanner":"gosec","rule_id":"G101","severity":"high","path":"main.go","line":12,"message":"Potential hardcoded credentials"}]`
+	if err := s.RecordDoDResultWithFindings(1, "cx-1", "proj", false, "Security scan gosec: 1 new finding(s) at or above high severity", "", findings); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := s.GetLatestDoDResult("proj", "cx-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := DoDFinding{Scanner: "gosec", RuleID: "G101", Severity: "high", Path: "main.go", Line: 12, Message: "Potential hardcoded credentials"}
+	if r.Passed || len(r.Findings) != 1 || r.Findings[0] != want {
+		t.Fatalf("unexpected DoD findings %+v", r.Findings)
+	}
+}
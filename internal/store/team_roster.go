@@ -0,0 +1,162 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TeamAgent is an agent of a project team in the roster.
+type TeamAgent struct {
+	Name      string
+	Project   string
+	Role      string
+	Model     string
+	Workspace string
+	CreatedAt time.Time
+	RetiredAt sql.NullTime
+	RetiredBy string
+}
+
+// TeamAgentStats summarizes the dispatches an agent ran.
+type TeamAgentStats struct {
+	Dispatches   int
+	Completed    int
+	Failed       int
+	AvgDurationS float64
+	CostUSD      float64
+	LastActiveAt sql.NullTime
+}
+
+// migrateTeamAgentsTable creates the team_agents table. Called from migrate().
+func migrateTeamAgentsTable(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS team_agents (
+			name TEXT PRIMARY KEY,
+			project TEXT NOT NULL DEFAULT '',
+			role TEXT NOT NULL DEFAULT '',
+			model TEXT NOT NULL DEFAULT '',
+			workspace TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL DEFAULT (datetime('now')),
+			retired_at DATETIME,
+			retired_by TEXT NOT NULL DEFAULT ''
+		)
+	`); err != nil {
+		return fmt.Errorf("create team_agents table: %w", err)
+	}
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_team_agents_project ON team_agents(project)`); err != nil {
+		return fmt.Errorf("create team_agents project index: %w", err)
+	}
+	return nil
+}
+
+// RecordTeamAgent adds an agent to the roster. Recording an agent already in
+// it replaces its identity and creation time and reinstates it if retired.
+func (s *Store) RecordTeamAgent(a TeamAgent) error {
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now()
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO team_agents (name, project, role, model, workspace, created_at) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET
+			project = excluded.project, role = excluded.role, model = excluded.model,
+			workspace = excluded.workspace, created_at = excluded.created_at,
+			retired_at = NULL, retired_by = ''`,
+		a.Name, a.Project, a.Role, a.Model, a.Workspace, a.CreatedAt.UTC().Format(time.DateTime),
+	)
+	if err != nil {
+		return fmt.Errorf("store: record team agent: %w", err)
+	}
+	return nil
+}
+
+// GetTeamAgent returns the named agent, or nil when it is not in the roster.
+func (s *Store) GetTeamAgent(name string) (*TeamAgent, error) {
+	a, err := scanTeamAgent(s.db.QueryRow(`SELECT `+teamAgentCols+` FROM team_agents WHERE name = ?`, name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("store: get team agent: %w", err)
+	}
+	return a, nil
+}
+
+// ListTeamAgents returns the roster ordered by project and name, limited to
+// one project unless project is empty. Retired agents are left out unless
+// includeRetired is set.
+func (s *Store) ListTeamAgents(project string, includeRetired bool) ([]TeamAgent, error) {
+	query := `SELECT ` + teamAgentCols + ` FROM team_agents WHERE (? = '' OR project = ?)`
+	if !includeRetired {
+		query += ` AND retired_at IS NULL`
+	}
+	rows, err := s.db.Query(query+` ORDER BY project, name`, project, project)
+	if err != nil {
+		return nil, fmt.Errorf("store: list team agents: %w", err)
+	}
+	defer rows.Close()
+
+	var out []TeamAgent
+	for rows.Next() {
+		a, err := scanTeamAgent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("store: scan team agent: %w", err)
+		}
+		out = append(out, *a)
+	}
+	return out, rows.Err()
+}
+
+// RetireTeamAgent marks an agent retired and reports whether it was active.
+func (s *Store) RetireTeamAgent(name, retiredBy string) (bool, error) {
+	res, err := s.db.Exec(`
+		UPDATE team_agents SET retired_at = ?, retired_by = ? WHERE name = ? AND retired_at IS NULL`,
+		time.Now().UTC().Format(time.DateTime), retiredBy, name,
+	)
+	if err != nil {
+		return false, fmt.Errorf("store: retire team agent: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("store: retire team agent: %w", err)
+	}
+	return n > 0, nil
+}
+
+// GetTeamAgentStats summarizes the dispatches recorded under the agent's name.
+func (s *Store) GetTeamAgentStats(name string) (TeamAgentStats, error) {
+	var st TeamAgentStats
+	var last sql.NullString
+	err := s.db.QueryRow(`
+		SELECT COUNT(*),
+			COALESCE(SUM(CASE WHEN status = 'completed' THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN status = 'failed' THEN 1 ELSE 0 END), 0),
+			COALESCE(AVG(duration_s), 0),
+			COALESCE(SUM(cost_usd), 0),
+			MAX(dispatched_at)
+		FROM dispatches WHERE agent_id = ?`,
+		name,
+	).Scan(&st.Dispatches, &st.Completed, &st.Failed, &st.AvgDurationS, &st.CostUSD, &last)
+	if err != nil {
+		return TeamAgentStats{}, fmt.Errorf("store: get team agent stats: %w", err)
+	}
+	if last.Valid && last.String != "" {
+		ts, err := parseSQLiteTime(last.String)
+		if err != nil {
+			return TeamAgentStats{}, fmt.Errorf("store: parse team agent last active %q: %w", last.String, err)
+		}
+		st.LastActiveAt = sql.NullTime{Time: ts, Valid: true}
+	}
+	return st, nil
+}
+
+const teamAgentCols = `name, project, role, model, workspace, created_at, retired_at, retired_by`
+
+func scanTeamAgent(row interface{ Scan(...any) error }) (*TeamAgent, error) {
+	var a TeamAgent
+	if err := row.Scan(&a.Name, &a.Project, &a.Role, &a.Model, &a.Workspace, &a.CreatedAt, &a.RetiredAt, &a.RetiredBy); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
@@ -0,0 +1,92 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTeamRoster(t *testing.T) {
+	s := tempStore(t)
+
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, a := range []TeamAgent{
+		{Name: "alpha-coder", Project: "alpha", Role: "coder", Model: "sonnet", CreatedAt: created},
+		{Name: "alpha-scrum", Project: "alpha", Role: "scrum", Model: "sonnet"},
+		{Name: "beta-coder", Project: "beta", Role: "coder", Model: "opus"},
+	} {
+		if err := s.RecordTeamAgent(a); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := s.GetTeamAgent("alpha-coder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.Role != "coder" || got.Model != "sonnet" || !got.CreatedAt.Equal(created) || got.RetiredAt.Valid {
+		t.Fatalf("unexpected agent %+v", got)
+	}
+	if missing, err := s.GetTeamAgent("nope"); err != nil || missing != nil {
+		t.Fatalf("expected no agent, got %+v (%v)", missing, err)
+	}
+
+	if ok, err := s.RetireTeamAgent("alpha-scrum", "ops"); err != nil || !ok {
+		t.Fatalf("retire = %v, %v", ok, err)
+	}
+	if ok, err := s.RetireTeamAgent("alpha-scrum", "ops"); err != nil || ok {
+		t.Fatalf("retiring twice = %v, %v", ok, err)
+	}
+
+	active, err := s.ListTeamAgents("alpha", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(active) != 1 || active[0].Name != "alpha-coder" {
+		t.Fatalf("active alpha agents = %+v", active)
+	}
+	all, err := s.ListTeamAgents("", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 || all[1].Name != "alpha-scrum" || !all[1].RetiredAt.Valid || all[1].RetiredBy != "ops" {
+		t.Fatalf("all agents = %+v", all)
+	}
+
+	// Recording a retired agent again reinstates it.
+	if err := s.RecordTeamAgent(TeamAgent{Name: "alpha-scrum", Project: "alpha", Role: "scrum", Model: "opus"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := s.GetTeamAgent("alpha-scrum"); got.RetiredAt.Valid || got.Model != "opus" {
+		t.Fatalf("expected a reinstated agent, got %+v", got)
+	}
+}
+
+func TestGetTeamAgentStats(t *testing.T) {
+	s := tempStore(t)
+
+	for _, status := range []string{"completed", "completed", "failed"} {
+		id, err := s.RecordDispatch("b", "alpha", "alpha-coder", "claude", "balanced", 0, "", "p", "", "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.UpdateDispatchStatus(id, status, 0, 60); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.RecordDispatchCost(id, 10, 10, 0.5); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	st, err := s.GetTeamAgentStats("alpha-coder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Dispatches != 3 || st.Completed != 2 || st.Failed != 1 || st.AvgDurationS != 60 || st.CostUSD != 1.5 || !st.LastActiveAt.Valid {
+		t.Fatalf("unexpected stats %+v", st)
+	}
+
+	st, err = s.GetTeamAgentStats("idle")
+	if err != nil || st.Dispatches != 0 || st.LastActiveAt.Valid {
+		t.Fatalf("expected empty stats, got %+v (%v)", st, err)
+	}
+}
@@ -0,0 +1,117 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SprintVelocity is one project's delivery analytics for one sprint, as
+// computed by the learner.
+type SprintVelocity struct {
+	Project           string             `json:"project"`
+	SprintNumber      int                `json:"sprint_number"`
+	SprintStart       time.Time          `json:"sprint_start"`
+	SprintEnd         time.Time          `json:"sprint_end"`
+	BeadsClosed       int                `json:"beads_closed"`
+	AvgCycleMinutes   float64            `json:"avg_cycle_minutes"`   // first activity to completion
+	StageCycleMinutes map[string]float64 `json:"stage_cycle_minutes"` // average time spent per stage
+	EstimatedBeads    int                `json:"estimated_beads"`     // closed beads that carried an estimate
+	EstimateMinutes   int                `json:"estimate_minutes"`    // summed estimates of those beads
+	ActualMinutes     float64            `json:"actual_minutes"`      // dispatch time spent on those beads
+	ComputedAt        time.Time          `json:"computed_at"`
+}
+
+// migrateSprintVelocityTable creates the sprint_velocity table. Called from migrate().
+func migrateSprintVelocityTable(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS sprint_velocity (
+			project TEXT NOT NULL,
+			sprint_number INTEGER NOT NULL,
+			sprint_start DATETIME NOT NULL,
+			sprint_end DATETIME NOT NULL,
+			beads_closed INTEGER NOT NULL DEFAULT 0,
+			avg_cycle_minutes REAL NOT NULL DEFAULT 0,
+			stage_cycle_minutes TEXT NOT NULL DEFAULT '{}',
+			estimated_beads INTEGER NOT NULL DEFAULT 0,
+			estimate_minutes INTEGER NOT NULL DEFAULT 0,
+			actual_minutes REAL NOT NULL DEFAULT 0,
+			computed_at DATETIME NOT NULL DEFAULT (datetime('now')),
+			PRIMARY KEY (project, sprint_number)
+		)
+	`); err != nil {
+		return fmt.Errorf("create sprint_velocity table: %w", err)
+	}
+	return nil
+}
+
+// UpsertSprintVelocity stores a project's velocity for a sprint, replacing
+// any earlier computation for the same sprint.
+func (s *Store) UpsertSprintVelocity(v SprintVelocity) error {
+	stages, err := json.Marshal(v.StageCycleMinutes)
+	if err != nil {
+		return fmt.Errorf("store: upsert sprint velocity: encode stages: %w", err)
+	}
+	computedAt := v.ComputedAt
+	if computedAt.IsZero() {
+		computedAt = time.Now()
+	}
+	_, err = s.db.Exec(`
+		INSERT INTO sprint_velocity (project, sprint_number, sprint_start, sprint_end, beads_closed,
+			avg_cycle_minutes, stage_cycle_minutes, estimated_beads, estimate_minutes, actual_minutes, computed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (project, sprint_number) DO UPDATE SET
+			sprint_start = excluded.sprint_start,
+			sprint_end = excluded.sprint_end,
+			beads_closed = excluded.beads_closed,
+			avg_cycle_minutes = excluded.avg_cycle_minutes,
+			stage_cycle_minutes = excluded.stage_cycle_minutes,
+			estimated_beads = excluded.estimated_beads,
+			estimate_minutes = excluded.estimate_minutes,
+			actual_minutes = excluded.actual_minutes,
+			computed_at = excluded.computed_at`,
+		v.Project, v.SprintNumber,
+		v.SprintStart.UTC().Format(time.DateTime), v.SprintEnd.UTC().Format(time.DateTime),
+		v.BeadsClosed, v.AvgCycleMinutes, string(stages),
+		v.EstimatedBeads, v.EstimateMinutes, v.ActualMinutes,
+		computedAt.UTC().Format(time.DateTime),
+	)
+	if err != nil {
+		return fmt.Errorf("store: upsert sprint velocity: %w", err)
+	}
+	return nil
+}
+
+// ListSprintVelocity returns stored velocity for a project (all projects when
+// empty), newest sprint first, covering at most the last limit sprints.
+func (s *Store) ListSprintVelocity(project string, limit int) ([]SprintVelocity, error) {
+	rows, err := s.db.Query(`
+		SELECT project, sprint_number, sprint_start, sprint_end, beads_closed, avg_cycle_minutes,
+			stage_cycle_minutes, estimated_beads, estimate_minutes, actual_minutes, computed_at
+		FROM sprint_velocity
+		WHERE (? = '' OR project = ?)
+		  AND sprint_number IN (SELECT DISTINCT sprint_number FROM sprint_velocity ORDER BY sprint_number DESC LIMIT ?)
+		ORDER BY sprint_number DESC, project`,
+		project, project, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("store: list sprint velocity: %w", err)
+	}
+	defer rows.Close()
+
+	var out []SprintVelocity
+	for rows.Next() {
+		var v SprintVelocity
+		var stages string
+		if err := rows.Scan(&v.Project, &v.SprintNumber, &v.SprintStart, &v.SprintEnd, &v.BeadsClosed,
+			&v.AvgCycleMinutes, &stages, &v.EstimatedBeads, &v.EstimateMinutes, &v.ActualMinutes, &v.ComputedAt); err != nil {
+			return nil, fmt.Errorf("store: scan sprint velocity: %w", err)
+		}
+		if err := json.Unmarshal([]byte(stages), &v.StageCycleMinutes); err != nil {
+			return nil, fmt.Errorf("store: decode sprint velocity stages: %w", err)
+		}
+		out = append(out, v)
+	}
+	return out, rows.Err()
+}
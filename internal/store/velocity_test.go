@@ -0,0 +1,52 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSprintVelocity(t *testing.T) {
+	s := tempStore(t)
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	for sprint := 1; sprint <= 3; sprint++ {
+		for _, project := range []string{"alpha", "beta"} {
+			v := SprintVelocity{
+				Project:           project,
+				SprintNumber:      sprint,
+				SprintStart:       start,
+				SprintEnd:         start.Add(7 * 24 * time.Hour),
+				BeadsClosed:       sprint,
+				StageCycleMinutes: map[string]float64{"coding": 30},
+			}
+			if err := s.UpsertSprintVelocity(v); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	// Recomputing a sprint replaces it.
+	if err := s.UpsertSprintVelocity(SprintVelocity{Project: "alpha", SprintNumber: 3, SprintStart: start, SprintEnd: start, BeadsClosed: 9, EstimateMinutes: 120, ActualMinutes: 90.5}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.ListSprintVelocity("alpha", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].SprintNumber != 3 || got[1].SprintNumber != 2 {
+		t.Fatalf("expected alpha sprints 3 and 2, got %+v", got)
+	}
+	if got[0].BeadsClosed != 9 || got[0].EstimateMinutes != 120 || got[0].ActualMinutes != 90.5 {
+		t.Fatalf("expected recomputed sprint 3, got %+v", got[0])
+	}
+	if got[1].StageCycleMinutes["coding"] != 30 || got[1].ComputedAt.IsZero() {
+		t.Fatalf("unexpected sprint 2 %+v", got[1])
+	}
+
+	all, err := s.ListSprintVelocity("", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all[0].Project != "alpha" || all[1].Project != "beta" {
+		t.Fatalf("expected both projects for the latest sprint, got %+v", all)
+	}
+}
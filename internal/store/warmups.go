@@ -0,0 +1,124 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ProviderWarmup is a cold-start warmup ping recorded outside the dispatches
+// table so it never skews dispatch duration, failure, or cost metrics.
+type ProviderWarmup struct {
+	ID        int64
+	Provider  string
+	Reason    string // startup, idle
+	StartedAt time.Time
+	DurationS float64
+	Success   bool
+	Error     string
+}
+
+// migrateProviderWarmupsTable creates the provider_warmups table. Called from migrate().
+func migrateProviderWarmupsTable(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS provider_warmups (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			provider TEXT NOT NULL,
+			reason TEXT NOT NULL DEFAULT '',
+			started_at DATETIME NOT NULL DEFAULT (datetime('now')),
+			duration_s REAL NOT NULL DEFAULT 0,
+			success INTEGER NOT NULL DEFAULT 0,
+			error TEXT NOT NULL DEFAULT ''
+		)
+	`); err != nil {
+		return fmt.Errorf("create provider_warmups table: %w", err)
+	}
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_provider_warmups_provider ON provider_warmups(provider, started_at)`); err != nil {
+		return fmt.Errorf("create provider_warmups provider index: %w", err)
+	}
+	return nil
+}
+
+// RecordProviderWarmup persists the result of a single warmup ping.
+func (s *Store) RecordProviderWarmup(provider, reason string, startedAt time.Time, durationS float64, success bool, errMsg string) (int64, error) {
+	res, err := s.db.Exec(
+		`INSERT INTO provider_warmups (provider, reason, started_at, duration_s, success, error) VALUES (?, ?, ?, ?, ?, ?)`,
+		provider, reason, startedAt.UTC().Format(time.DateTime), durationS, boolToInt(success), errMsg,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("store: record provider warmup: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+// ListProviderWarmups returns the most recent warmups, optionally filtered by provider.
+func (s *Store) ListProviderWarmups(provider string, limit int) ([]ProviderWarmup, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	query := `SELECT id, provider, reason, started_at, duration_s, success, error FROM provider_warmups`
+	args := []any{}
+	if provider != "" {
+		query += ` WHERE provider = ?`
+		args = append(args, provider)
+	}
+	query += ` ORDER BY started_at DESC, id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("store: list provider warmups: %w", err)
+	}
+	defer rows.Close()
+
+	var out []ProviderWarmup
+	for rows.Next() {
+		var w ProviderWarmup
+		var success int
+		if err := rows.Scan(&w.ID, &w.Provider, &w.Reason, &w.StartedAt, &w.DurationS, &success, &w.Error); err != nil {
+			return nil, fmt.Errorf("store: scan provider warmup: %w", err)
+		}
+		w.Success = success != 0
+		out = append(out, w)
+	}
+	return out, rows.Err()
+}
+
+// CountProviderWarmups returns the number of warmups since the given time, split by outcome.
+func (s *Store) CountProviderWarmups(since time.Time) (succeeded, failed int, err error) {
+	err = s.db.QueryRow(
+		`SELECT COALESCE(SUM(CASE WHEN success = 1 THEN 1 ELSE 0 END), 0),
+		        COALESCE(SUM(CASE WHEN success = 0 THEN 1 ELSE 0 END), 0)
+		 FROM provider_warmups WHERE started_at >= ?`,
+		since.UTC().Format(time.DateTime),
+	).Scan(&succeeded, &failed)
+	if err != nil {
+		return 0, 0, fmt.Errorf("store: count provider warmups: %w", err)
+	}
+	return succeeded, failed, nil
+}
+
+// GetProviderLastActive returns the most recent time a provider did any work:
+// a dispatch (matched on agent or provider) or a successful warmup.
+// The zero time is returned when the provider has never been used.
+func (s *Store) GetProviderLastActive(provider string) (time.Time, error) {
+	var raw sql.NullString
+	err := s.db.QueryRow(`
+		SELECT MAX(ts) FROM (
+			SELECT MAX(dispatched_at) AS ts FROM dispatches WHERE agent_id = ? OR provider = ?
+			UNION ALL
+			SELECT MAX(started_at) AS ts FROM provider_warmups WHERE provider = ? AND success = 1
+		)`, provider, provider, provider,
+	).Scan(&raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("store: provider last active: %w", err)
+	}
+	if !raw.Valid || raw.String == "" {
+		return time.Time{}, nil
+	}
+	ts, err := parseSQLiteTime(raw.String)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("store: parse provider last active %q: %w", raw.String, err)
+	}
+	return ts, nil
+}
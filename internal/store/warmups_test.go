@@ -0,0 +1,101 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProviderWarmupsRecordAndList(t *testing.T) {
+	s := tempStore(t)
+	now := time.Now().UTC()
+
+	if _, err := s.RecordProviderWarmup("claude-max20", "startup", now.Add(-time.Minute), 42.5, true, ""); err != nil {
+		t.Fatalf("RecordProviderWarmup: %v", err)
+	}
+	if _, err := s.RecordProviderWarmup("codex", "idle", now, 3, false, "auth expired"); err != nil {
+		t.Fatalf("RecordProviderWarmup: %v", err)
+	}
+
+	all, err := s.ListProviderWarmups("", 10)
+	if err != nil {
+		t.Fatalf("ListProviderWarmups: %v", err)
+	}
+	if len(all) != 2 || all[0].Provider != "codex" {
+		t.Fatalf("expected newest-first warmups, got %+v", all)
+	}
+	if all[0].Success || all[0].Error != "auth expired" {
+		t.Fatalf("unexpected failed warmup row: %+v", all[0])
+	}
+
+	claude, err := s.ListProviderWarmups("claude-max20", 10)
+	if err != nil {
+		t.Fatalf("ListProviderWarmups filtered: %v", err)
+	}
+	if len(claude) != 1 || !claude[0].Success || claude[0].DurationS != 42.5 {
+		t.Fatalf("unexpected filtered warmups: %+v", claude)
+	}
+
+	ok, failed, err := s.CountProviderWarmups(now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("CountProviderWarmups: %v", err)
+	}
+	if ok != 1 || failed != 1 {
+		t.Fatalf("CountProviderWarmups = %d/%d, want 1/1", ok, failed)
+	}
+
+	// Warmups must not leak into dispatch metrics.
+	running, err := s.GetRunningDispatches()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(running) != 0 {
+		t.Fatalf("warmups should not create dispatches, got %d", len(running))
+	}
+}
+
+func TestGetProviderLastActive(t *testing.T) {
+	s := tempStore(t)
+
+	last, err := s.GetProviderLastActive("codex")
+	if err != nil {
+		t.Fatalf("GetProviderLastActive: %v", err)
+	}
+	if !last.IsZero() {
+		t.Fatalf("expected zero time for unused provider, got %v", last)
+	}
+
+	id, err := s.RecordDispatch("bead-1", "proj", "codex", "codex", "fast", 0, "", "prompt", "", "", "headless_cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dispatchedAt := time.Now().UTC().Add(-3 * time.Hour).Truncate(time.Second)
+	if err := s.SetDispatchTime(id, dispatchedAt); err != nil {
+		t.Fatal(err)
+	}
+
+	last, err = s.GetProviderLastActive("codex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !last.Equal(dispatchedAt) {
+		t.Fatalf("last active = %v, want dispatch time %v", last, dispatchedAt)
+	}
+
+	// Failed warmups do not count as activity; successful ones do.
+	if _, err := s.RecordProviderWarmup("codex", "idle", time.Now().UTC().Add(-time.Hour), 1, false, "boom"); err != nil {
+		t.Fatal(err)
+	}
+	last, _ = s.GetProviderLastActive("codex")
+	if !last.Equal(dispatchedAt) {
+		t.Fatalf("failed warmup should not refresh activity, got %v", last)
+	}
+
+	warmedAt := time.Now().UTC().Add(-10 * time.Minute).Truncate(time.Second)
+	if _, err := s.RecordProviderWarmup("codex", "idle", warmedAt, 1, true, ""); err != nil {
+		t.Fatal(err)
+	}
+	last, _ = s.GetProviderLastActive("codex")
+	if !last.Equal(warmedAt) {
+		t.Fatalf("last active = %v, want warmup time %v", last, warmedAt)
+	}
+}
@@ -8,6 +8,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/store"
 )
 
 // roleDescriptions provides the ROLE.md content for each agent role.
@@ -135,8 +138,10 @@ You are the QA and operations engineer for this project. Your job is to validate
 }
 
 // EnsureTeam checks that all role agents exist for a project and creates missing ones.
-// It returns the list of agents that were created.
-func EnsureTeam(project, workspace, model string, roles []string, logger *slog.Logger) ([]string, error) {
+// custom holds the ROLE.md content of the project's custom roles, by role name.
+// With a roster, every agent is recorded in it, and agents retired there are
+// neither recreated nor updated. It returns the list of agents that were created.
+func EnsureTeam(project, workspace, model string, roles []string, custom map[string]string, roster *store.Store, logger *slog.Logger) ([]string, error) {
 	agentsDir, err := agentsBasePath()
 	if err != nil {
 		return nil, fmt.Errorf("team: get agents dir: %w", err)
@@ -147,9 +152,21 @@ func EnsureTeam(project, workspace, model string, roles []string, logger *slog.L
 		agentName := project + "-" + role
 		agentPath := filepath.Join(agentsDir, agentName)
 
+		var listed *store.TeamAgent
+		if roster != nil {
+			if listed, err = roster.GetTeamAgent(agentName); err != nil {
+				logger.Warn("failed to read team roster", "agent", agentName, "error", err)
+			} else if listed != nil && listed.RetiredAt.Valid {
+				logger.Info("skipping retired agent", "agent", agentName, "retired_by", listed.RetiredBy)
+				continue
+			}
+		}
+
 		existing := false
-		if _, err := os.Stat(agentPath); err == nil {
+		var createdAt time.Time
+		if info, err := os.Stat(agentPath); err == nil {
 			existing = true
+			createdAt = info.ModTime()
 		} else if !os.IsNotExist(err) {
 			logger.Error("failed to stat existing agent", "agent", agentName, "error", err)
 			continue
@@ -164,10 +181,20 @@ func EnsureTeam(project, workspace, model string, roles []string, logger *slog.L
 			}
 
 			created = append(created, agentName)
+			createdAt = time.Now()
 			logger.Info("agent created", "agent", agentName)
 		}
 
-		if err := writeRoleMD(agentPath, role); err != nil {
+		if roster != nil && (!existing || listed == nil) {
+			err := roster.RecordTeamAgent(store.TeamAgent{
+				Name: agentName, Project: project, Role: role, Model: model, Workspace: workspace, CreatedAt: createdAt,
+			})
+			if err != nil {
+				logger.Warn("failed to record agent in team roster", "agent", agentName, "error", err)
+			}
+		}
+
+		if err := writeRoleMD(agentPath, role, custom); err != nil {
 			logger.Warn("agent created but failed to write ROLE.md", "agent", agentName, "error", err)
 		}
 	}
@@ -234,9 +261,12 @@ func createAgent(name, workspace, model string) error {
 	return nil
 }
 
-func writeRoleMD(agentDir, role string) error {
+func writeRoleMD(agentDir, role string, custom map[string]string) error {
 	content, ok := roleDescriptions[role]
 	if !ok {
+		content = custom[role]
+	}
+	if content == "" {
 		return nil // no ROLE.md for unknown roles
 	}
 
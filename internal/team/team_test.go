@@ -1,9 +1,13 @@
 package team
 
 import (
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/antigravity-dev/cortex/internal/store"
 )
 
 func TestRoleDescriptionsComplete(t *testing.T) {
@@ -56,7 +60,7 @@ func TestListTeamNoAgents(t *testing.T) {
 func TestWriteRoleMDCreatesScrumRole(t *testing.T) {
 	agentDir := t.TempDir()
 
-	if err := writeRoleMD(agentDir, "scrum"); err != nil {
+	if err := writeRoleMD(agentDir, "scrum", nil); err != nil {
 		t.Fatalf("writeRoleMD: unexpected error: %v", err)
 	}
 
@@ -101,7 +105,7 @@ You are the scrum master for this project. Your job is to refine incoming tasks.
 		t.Fatalf("seed legacy role: %v", err)
 	}
 
-	if err := writeRoleMD(agentDir, "scrum"); err != nil {
+	if err := writeRoleMD(agentDir, "scrum", nil); err != nil {
 		t.Fatalf("writeRoleMD: unexpected error: %v", err)
 	}
 
@@ -113,3 +117,59 @@ You are the scrum master for this project. Your job is to refine incoming tasks.
 		t.Fatalf("expected legacy role to be refreshed\nexpected:\n%q\ngot:\n%q", roleDescriptions["scrum"], string(got))
 	}
 }
+
+func TestWriteRoleMDCustomRole(t *testing.T) {
+	agentDir := t.TempDir()
+	custom := map[string]string{"doc-writer": "# Doc Writer\n", "scrum": "ignored"}
+
+	if err := writeRoleMD(agentDir, "doc-writer", custom); err != nil {
+		t.Fatalf("writeRoleMD: unexpected error: %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(agentDir, "ROLE.md"))
+	if err != nil || string(got) != "# Doc Writer\n" {
+		t.Fatalf("unexpected custom role content %q (%v)", got, err)
+	}
+
+	scrumDir := t.TempDir()
+	if err := writeRoleMD(scrumDir, "scrum", custom); err != nil {
+		t.Fatalf("writeRoleMD: unexpected error: %v", err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(scrumDir, "ROLE.md")); string(got) != roleDescriptions["scrum"] {
+		t.Fatal("custom content replaced a built-in role")
+	}
+}
+
+func TestEnsureTeamRecordsRoster(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.MkdirAll(filepath.Join(home, ".openclaw", "agents", "proj-coder"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	roster, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { roster.Close() })
+	if err := roster.RecordTeamAgent(store.TeamAgent{Name: "proj-scrum", Project: "proj", Role: "scrum"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := roster.RetireTeamAgent("proj-scrum", "ops"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The retired scrum agent is skipped, so openclaw is never called.
+	created, err := EnsureTeam("proj", "/ws", "sonnet", []string{"coder", "scrum"}, nil, roster, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 0 {
+		t.Fatalf("expected no agents to be created, got %v", created)
+	}
+	coder, err := roster.GetTeamAgent("proj-coder")
+	if err != nil || coder == nil || coder.Role != "coder" || coder.Workspace != "/ws" {
+		t.Fatalf("expected the existing coder in the roster, got %+v (%v)", coder, err)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".openclaw", "agents", "proj-coder", "ROLE.md")); err != nil {
+		t.Fatalf("expected ROLE.md for the coder: %v", err)
+	}
+}
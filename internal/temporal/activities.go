@@ -1,7 +1,6 @@
 package temporal
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -11,15 +10,64 @@ import (
 
 	"go.temporal.io/sdk/activity"
 
+	"github.com/antigravity-dev/cortex/internal/autoscale"
+	"github.com/antigravity-dev/cortex/internal/beads"
+	"github.com/antigravity-dev/cortex/internal/calendar"
 	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/cost"
+	"github.com/antigravity-dev/cortex/internal/dispatch"
 	"github.com/antigravity-dev/cortex/internal/git"
+	"github.com/antigravity-dev/cortex/internal/journal"
+	"github.com/antigravity-dev/cortex/internal/lease"
+	"github.com/antigravity-dev/cortex/internal/matrix"
 	"github.com/antigravity-dev/cortex/internal/store"
 )
 
 // Activities holds dependencies for Temporal activity methods.
 type Activities struct {
-	Store *store.Store
-	Tiers config.Tiers
+	Store       *store.Store
+	Tiers       config.Tiers
+	Projects    map[string]config.Project
+	Experiments map[string]config.Experiment
+	Workflows   map[string]config.WorkflowConfig
+	Confidence  config.DispatchConfidence
+	Pair        config.DispatchPair
+	Providers   map[string]config.Provider
+	RateLimiter *dispatch.RateLimiter // reserves providers for pair sessions and tracks per-minute budgets; nil skips both
+	Sender      matrix.Sender         // room notifications; nil disables them
+	Artifacts   config.DispatchArtifacts
+	Worktrees   *dispatch.WorktreePool // per-dispatch git worktrees; nil keeps every task in the shared workspace
+	Journal     *journal.Journal       // dispatch decision journal; nil disables it
+	Claims      *lease.Claims          // bead claims shared with other instances; nil disables them
+	Holidays    *calendar.Calendar     // cadence holidays the chief and groom crons move off; nil has none
+	Slots       *autoscale.Controller  // coder and reviewer concurrency limits; nil leaves both unbounded
+
+	General       config.General
+	Cadence       config.Cadence
+	ResponseCache config.DispatchResponseCache
+	CostControl   config.DispatchCostControl
+	Approvals     config.DispatchApprovals
+	FollowUps     config.DispatchFollowUps
+	Git           config.DispatchGit
+}
+
+// journalSkip records that the task went on without an optional step, such
+// as a pair session or its own worktree. A journal write failure is only
+// logged.
+func (a *Activities) journalSkip(ctx context.Context, source string, req TaskRequest, reason string) {
+	err := a.Journal.Record(journal.Entry{
+		Decision: journal.Skip,
+		Source:   source,
+		Project:  req.Project,
+		BeadID:   req.BeadID,
+		Agent:    req.Agent,
+		Provider: req.Provider,
+		Workflow: activity.GetInfo(ctx).WorkflowExecution.ID,
+		Reason:   reason,
+	})
+	if err != nil {
+		activity.GetLogger(ctx).Warn("Failed to journal dispatch decision", "BeadID", req.BeadID, "error", err)
+	}
 }
 
 // ResolveTierAgent returns the first agent in the given tier's agent list.
@@ -43,15 +91,16 @@ func ResolveTierAgent(tiers config.Tiers, tier string) string {
 }
 
 // cliCommand returns an exec.Cmd for a given agent in non-interactive coding mode.
-// V0: claude and codex only. Claude uses --output-format json for token tracking.
+// V0: claude and codex only. Claude streams --output-format stream-json, so
+// its usage can be tracked and its output streamed while it runs.
 func cliCommand(agent string, prompt string, workDir string) *exec.Cmd {
 	var cmd *exec.Cmd
 	switch strings.ToLower(agent) {
 	case "codex":
 		// codex exec --full-auto for non-interactive coding
 		cmd = exec.Command("codex", "exec", "--full-auto", prompt)
-	default: // claude is the default — JSON output gives us token usage
-		cmd = exec.Command("claude", "--print", "--output-format", "json", "--dangerously-skip-permissions", prompt)
+	default: // claude is the default — JSON events give us token usage
+		cmd = exec.Command("claude", "--print", "--output-format", "stream-json", "--verbose", "--dangerously-skip-permissions", prompt)
 	}
 	cmd.Dir = workDir
 	return cmd
@@ -66,22 +115,23 @@ func cliReviewCommand(agent string, prompt string, workDir string) *exec.Cmd {
 	case "codex":
 		// codex exec for review — same as coding, but the prompt asks for review output
 		cmd = exec.Command("codex", "exec", "--full-auto", prompt)
-	default: // claude reviews via --print with JSON output for token tracking
-		cmd = exec.Command("claude", "--print", "--output-format", "json", "--dangerously-skip-permissions", prompt)
+	default: // claude reviews via --print with JSON events for token tracking
+		cmd = exec.Command("claude", "--print", "--output-format", "stream-json", "--verbose", "--dangerously-skip-permissions", prompt)
 	}
 	cmd.Dir = workDir
 	return cmd
 }
 
 // CLIResult wraps the text output of a CLI command together with token usage
-// extracted from claude's --output-format json. For non-JSON agents (codex),
+// extracted from claude's JSON output. For non-JSON agents (codex),
 // Tokens is zero-valued.
 type CLIResult struct {
 	Output string
 	Tokens TokenUsage
 }
 
-// claudeJSONOutput matches the JSON structure from `claude --print --output-format json`.
+// claudeJSONOutput matches the JSON structure from `claude --print --output-format json`,
+// which is also the result event that ends stream-json output.
 type claudeJSONOutput struct {
 	Result string `json:"result"`
 	Usage  struct {
@@ -90,22 +140,35 @@ type claudeJSONOutput struct {
 		CacheReadTokens     int `json:"cache_read_input_tokens"`
 		CacheCreationTokens int `json:"cache_creation_input_tokens"`
 	} `json:"usage"`
-	CostUSD float64 `json:"cost_usd"`
+	CostUSD      float64 `json:"cost_usd"`
+	TotalCostUSD float64 `json:"total_cost_usd"` // replaces cost_usd in newer CLIs
 }
 
-// parseJSONOutput extracts text result and token usage from claude's JSON output.
-// If the output is not valid JSON or doesn't have a result field, it falls back
-// to returning the raw output with zero tokens (graceful degradation for codex).
+// parseJSONOutput extracts text result and token usage from claude's JSON output,
+// or from the result event of its stream-json output. A stream cut off before
+// its result keeps the raw output with the usage of the messages it finished.
+// Output that is neither falls back to the raw output with zero tokens
+// (graceful degradation for codex).
 func parseJSONOutput(raw string) CLIResult {
 	var parsed claudeJSONOutput
 	if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
-		return CLIResult{Output: raw}
+		line, ok := streamResultEvent(raw)
+		if !ok || json.Unmarshal([]byte(line), &parsed) != nil {
+			result := CLIResult{Output: raw}
+			if usage, ok := cost.ParseClaudeUsage(raw); ok {
+				result.Tokens = tokenUsageFrom(usage)
+			}
+			return result
+		}
 	}
 	// If the JSON parsed but has no result field, it's probably not claude output
 	if parsed.Result == "" && parsed.Usage.InputTokens == 0 {
 		return CLIResult{Output: raw}
 	}
 	output := parsed.Result
+	if parsed.CostUSD == 0 {
+		parsed.CostUSD = parsed.TotalCostUSD
+	}
 	if output == "" {
 		output = raw // fallback: keep original if result is empty but we got tokens
 	}
@@ -121,57 +184,239 @@ func parseJSONOutput(raw string) CLIResult {
 	}
 }
 
+// streamResultEvent returns the result event line of claude's stream-json
+// output.
+func streamResultEvent(raw string) (string, bool) {
+	lines := strings.Split(raw, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+		var ev struct {
+			Type string `json:"type"`
+		}
+		if json.Unmarshal([]byte(line), &ev) == nil && ev.Type == "result" {
+			return line, true
+		}
+	}
+	return "", false
+}
+
+// agentPollInterval is how often a running agent is heartbeated and checked
+// against its cost cap.
+var agentPollInterval = 5 * time.Second
+
 // runCLI executes a CLI command and returns a CLIResult with stdout and token usage.
-// For claude agents, parses --output-format json to extract tokens.
+// For claude agents, parses its stream-json output to extract tokens.
 // For codex/other agents, returns raw output with zero tokens.
-func runCLI(ctx context.Context, agent string, cmd *exec.Cmd) (CLIResult, error) {
-	var stdout, stderr bytes.Buffer
+// Runs are checkpointed so a retry after a worker restart re-adopts the
+// process instead of starting a second agent. Output is streamed to the
+// store at every poll. A run whose estimated spend crosses the cost cap on
+// ctx is killed. st receives the output while the agent runs; nil disables
+// streaming.
+func runCLI(ctx context.Context, st *store.Store, agent string, cmd *exec.Cmd) (CLIResult, error) {
+	call := checkpoints.call(ctx, agent, cmd)
+	if result, adopted, err := call.adopt(ctx); adopted {
+		return result, err
+	}
+
+	var stdout, stderr syncBuffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
+	if call != nil {
+		if err := call.redirect(cmd); err != nil {
+			activity.GetLogger(ctx).Warn("Agent checkpoint unavailable, running without", "error", err)
+			call = nil
+		}
+	}
 
 	if err := cmd.Start(); err != nil {
+		call.release()
 		return CLIResult{}, fmt.Errorf("failed to start %s: %w", agent, err)
 	}
+	runningAgents.add(cmd.Process, agent)
+	defer runningAgents.remove(cmd.Process)
+	call.started(cmd.Process.Pid)
+	defer call.release()
 
 	done := make(chan error, 1)
 	go func() { done <- cmd.Wait() }()
 
+	outputs := func() (string, string) {
+		if call != nil {
+			return call.outputs()
+		}
+		return stdout.String(), stderr.String()
+	}
+	limit := costCapFrom(ctx)
+	stream := streamOutput(ctx, st, agent)
 	for {
 		select {
 		case err := <-done:
-			raw := strings.TrimSpace(stdout.String())
-			if err != nil {
-				errOut := strings.TrimSpace(stderr.String())
-				if errOut != "" {
-					raw += "\n" + errOut
-				}
-				result := parseAgentOutput(agent, raw)
-				return result, fmt.Errorf("%s exited with error: %w", agent, err)
-			}
-			return parseAgentOutput(agent, raw), nil
-		case <-time.After(5 * time.Second):
+			out, errOut := outputs()
+			stream.flush(out, errOut, true)
+			return finishCLI(agent, out, errOut, err)
+		case <-time.After(agentPollInterval):
 			activity.RecordHeartbeat(ctx)
+			if limit == nil && stream == nil {
+				continue
+			}
+			out, errOut := outputs()
+			stream.flush(out, errOut, false)
+			if limit == nil {
+				continue
+			}
+			if spent := limit.spent(out); spent > limit.limitUSD {
+				_ = cmd.Process.Kill()
+				err := <-done
+				out, errOut := outputs()
+				stream.flush(out, errOut, true)
+				result, _ := finishCLI(agent, out, errOut, err)
+				return result, limit.exceeded(agent, spent)
+			}
 		}
 	}
 }
 
+// finishCLI turns a finished run's output and exit error into a CLIResult.
+func finishCLI(agent, stdout, stderr string, err error) (CLIResult, error) {
+	raw := strings.TrimSpace(stdout)
+	if err != nil {
+		errOut := strings.TrimSpace(stderr)
+		if errOut != "" {
+			raw += "\n" + errOut
+		}
+		result := parseAgentOutput(agent, raw)
+		return result, fmt.Errorf("%s exited with error: %w", agent, err)
+	}
+	return parseAgentOutput(agent, raw), nil
+}
+
 // parseAgentOutput routes output parsing based on agent type.
-// Claude output is JSON (--output-format json); others are plain text.
+// Claude output is JSON (--output-format stream-json); others are plain text with
+// whatever usage report their CLI prints.
 func parseAgentOutput(agent string, raw string) CLIResult {
 	if strings.ToLower(agent) == "claude" {
 		return parseJSONOutput(raw)
 	}
-	return CLIResult{Output: raw}
+	result := CLIResult{Output: raw}
+	if usage, ok := cost.ParserFor("", agent)(raw); ok {
+		result.Tokens = tokenUsageFrom(usage)
+	}
+	return result
 }
 
 // runAgent executes a CLI agent in coding mode and returns a CLIResult.
-func runAgent(ctx context.Context, agent, prompt, workDir string) (CLIResult, error) {
-	return runCLI(ctx, agent, cliCommand(agent, prompt, workDir))
+func (a *Activities) runAgent(ctx context.Context, agent, prompt, workDir string) (CLIResult, error) {
+	return runCLI(ctx, a.Store, agent, cliCommand(agent, prompt, workDir))
+}
+
+// observeProvider feeds a finished agent call back into its provider's
+// per-minute budgets and circuit breakers: the tokens it used, any rate-limit
+// headers it printed, and how the CLI failed, if it did.
+func (a *Activities) observeProvider(ctx context.Context, agent, preferred string, result CLIResult, runErr error) {
+	if a.RateLimiter == nil {
+		return
+	}
+	names := agentProviders(a.Providers, agent, preferred)
+	if len(names) == 0 {
+		return
+	}
+	name, p := names[0], a.Providers[names[0]]
+	a.RateLimiter.RecordProviderTokens(name, p, result.Tokens.InputTokens+result.Tokens.OutputTokens)
+	category := ""
+	if runErr != nil {
+		category = dispatch.ClassifyFailure(result.Output, runErr)
+	}
+	a.RateLimiter.RecordProviderOutcome(name, p, category)
+	if f := a.RateLimiter.ObserveProviderOutput(name, p, result.Output); !f.Empty() {
+		activity.GetLogger(ctx).Info("Provider rate-limit feedback", "Provider", name,
+			"RemainingRequests", f.RemainingRequests, "RemainingTokens", f.RemainingTokens, "RetryAfter", f.RetryAfter)
+	}
+}
+
+// maxProviderWait bounds how long a solo dispatch waits for its providers'
+// retry-after or per-minute budgets before the execution fails and is
+// retried.
+const maxProviderWait = 5 * time.Minute
+
+// reserveProvider reserves the first of agent's providers, preferred first,
+// that the circuit breakers, per-minute budgets, dispatch caps and pre-flight
+// probe allow, as a pair dispatch does. While the providers are only held
+// back by a retry-after or a spent budget it waits, up to maxProviderWait,
+// for them to free up. With nothing to reserve from it returns preferred; it
+// fails when no provider of the agent can take the dispatch. Call release if
+// the run fails.
+func (a *Activities) reserveProvider(ctx context.Context, agent string, req TaskRequest) (provider string, release func(), err error) {
+	release = func() {}
+	if a.RateLimiter == nil {
+		return req.Provider, release, nil
+	}
+	names := agentProviders(a.Providers, agent, req.Provider)
+	if len(names) == 0 {
+		return req.Provider, release, nil
+	}
+	deadline := time.Now().Add(maxProviderWait)
+	for {
+		p, name, _, cleanup, err := a.RateLimiter.PickAndReserveProviderFromCandidates(names, a.Providers, nil, agent, req.BeadID)
+		if err != nil {
+			return "", release, err
+		}
+		if p != nil {
+			if cleanup != nil {
+				release = cleanup
+			}
+			return name, release, nil
+		}
+		wait, ok := a.RateLimiter.ProviderWait(names, a.Providers)
+		if !ok || wait <= 0 || time.Now().Add(wait).After(deadline) {
+			return "", release, fmt.Errorf("no %s provider available: circuit open, budget spent or pre-flight probe failed", agent)
+		}
+		if activity.IsActivity(ctx) {
+			activity.GetLogger(ctx).Info("Waiting for provider budget", "Agent", agent, "BeadID", req.BeadID, "Wait", wait)
+		}
+		if err := waitHeartbeating(ctx, wait); err != nil {
+			return "", release, err
+		}
+	}
+}
+
+// waitHeartbeating sleeps for d, heartbeating inside an activity so the wait
+// does not time it out. It returns early with ctx's error when ctx is done.
+func waitHeartbeating(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		case <-time.After(agentPollInterval):
+			if activity.IsActivity(ctx) {
+				activity.RecordHeartbeat(ctx)
+			}
+		}
+	}
 }
 
 // runReviewAgent executes a CLI agent in code review mode and returns a CLIResult.
-func runReviewAgent(ctx context.Context, agent, prompt, workDir string) (CLIResult, error) {
-	return runCLI(ctx, agent, cliReviewCommand(agent, prompt, workDir))
+func (a *Activities) runReviewAgent(ctx context.Context, agent, prompt, workDir string) (CLIResult, error) {
+	return runCLI(ctx, a.Store, agent, cliReviewCommand(agent, prompt, workDir))
+}
+
+// taskAttachmentsSection lists the bead's attachments so agents can open them
+// from disk. Attachment lookup failures never block a dispatch.
+func taskAttachmentsSection(req TaskRequest) string {
+	if req.BeadID == "" || req.WorkDir == "" {
+		return ""
+	}
+	atts, err := beads.ListAttachments(resolveBeadsDir(req.sharedWorkspace()), req.BeadID)
+	if err != nil {
+		return ""
+	}
+	return beads.AttachmentsPromptSection(atts)
 }
 
 // StructuredPlanActivity generates a structured plan from a task prompt.
@@ -183,7 +428,7 @@ func (a *Activities) StructuredPlanActivity(ctx context.Context, req TaskRequest
 	prompt := fmt.Sprintf(`You are a senior engineering planner. Analyze this task and produce a structured execution plan.
 
 TASK: %s
-
+%s
 OUTPUT FORMAT: You MUST respond with ONLY a JSON object (no markdown, no commentary) with this exact structure:
 {
   "summary": "one-line summary of the task",
@@ -194,9 +439,17 @@ OUTPUT FORMAT: You MUST respond with ONLY a JSON object (no markdown, no comment
   "risk_assessment": "what could go wrong"
 }
 
-Be thorough. Planning space is cheap — implementation is expensive.`, req.Prompt)
-
-	cliResult, err := runAgent(ctx, req.Agent, prompt, req.WorkDir)
+Be thorough. Planning space is cheap — implementation is expensive.`, req.Prompt, taskAttachmentsSection(req))
+	prompt = a.buildPrompt(ctx, "planner", req, PromptData{
+		Stage:       "plan",
+		Agent:       req.Agent,
+		Attachments: taskAttachmentsSection(req),
+		Default:     prompt,
+	})
+
+	cliResult, keep, err := a.runCachedAgent(ctx, "planner", req.Agent, req.Provider, prompt, req.WorkDir)
+	cliResult = a.accountUsage(req.Agent, req.Provider, cliResult)
+	a.observeProvider(ctx, req.Agent, req.Provider, cliResult, err)
 	if err != nil {
 		return nil, fmt.Errorf("plan generation failed: %w", err)
 	}
@@ -225,6 +478,7 @@ Be thorough. Planning space is cheap — implementation is expensive.`, req.Prom
 	if issues := plan.Validate(); len(issues) > 0 {
 		return nil, fmt.Errorf("plan failed quality gate:\n- %s", strings.Join(issues, "\n- "))
 	}
+	keep()
 
 	logger.Info("Plan generated and validated",
 		"Summary", plan.Summary,
@@ -233,6 +487,13 @@ Be thorough. Planning space is cheap — implementation is expensive.`, req.Prom
 		"Criteria", len(plan.AcceptanceCriteria),
 	)
 
+	// Map the bead to its planned files so the file overlap check can see it.
+	if a.Store != nil && len(plan.FilesToModify) > 0 {
+		if err := a.Store.RecordBeadFiles(req.Project, req.BeadID, store.BeadFilesPlan, plan.FilesToModify); err != nil {
+			logger.Warn("Failed to record planned files", "BeadID", req.BeadID, "error", err)
+		}
+	}
+
 	return &plan, nil
 }
 
@@ -242,26 +503,26 @@ func (a *Activities) ExecuteActivity(ctx context.Context, plan StructuredPlan, r
 	agent := req.Agent
 	logger.Info("Executing plan", "Agent", agent, "BeadID", req.BeadID)
 
-	// Build a detailed execution prompt from the structured plan
-	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("TASK: %s\n\n", plan.Summary))
-	sb.WriteString("PLAN:\n")
-	for i, step := range plan.Steps {
-		sb.WriteString(fmt.Sprintf("%d. [%s] %s\n   Rationale: %s\n", i+1, step.File, step.Description, step.Rationale))
-	}
-	sb.WriteString(fmt.Sprintf("\nFILES TO MODIFY: %s\n", strings.Join(plan.FilesToModify, ", ")))
-	sb.WriteString(fmt.Sprintf("\nACCEPTANCE CRITERIA:\n"))
-	for _, c := range plan.AcceptanceCriteria {
-		sb.WriteString(fmt.Sprintf("- %s\n", c))
-	}
-
-	if len(plan.PreviousErrors) > 0 {
-		sb.WriteString(fmt.Sprintf("\nPREVIOUS ERRORS TO FIX:\n%s\n", strings.Join(plan.PreviousErrors, "\n")))
+	provider, release, err := a.reserveProvider(ctx, agent, req)
+	if err != nil {
+		return nil, err
 	}
+	req.Provider = provider
 
-	sb.WriteString("\nImplement this plan now. Make all necessary code changes.")
+	prompt := a.executionPrompt(ctx, plan, req, agent) + resultContract
+	a.recordPromptAttempt(ctx, req, agent, prompt)
 
-	cliResult, err := runAgent(ctx, agent, sb.String(), req.WorkDir)
+	runCtx := withAgentRun(withCostCap(ctx, a.costCapFor("coder", agent, req, prompt)), req.BeadID, req.Attempt)
+	cliResult, err := a.runAgent(runCtx, agent, prompt, req.WorkDir)
+	cliResult = a.accountUsage(agent, req.Provider, cliResult)
+	a.observeProvider(ctx, agent, req.Provider, cliResult, err)
+	if err != nil {
+		release()
+	}
+	if isCostCapExceeded(err) {
+		logger.Warn("Agent stopped by cost cap", "Agent", agent, "BeadID", req.BeadID, "error", err)
+		return nil, err
+	}
 	exitCode := 0
 	if err != nil {
 		exitCode = 1
@@ -278,14 +539,86 @@ func (a *Activities) ExecuteActivity(ctx context.Context, plan StructuredPlan, r
 		"CostUSD", cliResult.Tokens.CostUSD,
 	)
 
+	report := parseAgentReport(cliResult.Output)
+	if report == nil {
+		logger.Warn("Agent output has no completion block", "Agent", agent, "BeadID", req.BeadID)
+	}
+
 	return &ExecutionResult{
-		ExitCode: exitCode,
-		Output:   cliResult.Output,
-		Agent:    agent,
-		Tokens:   cliResult.Tokens,
+		ExitCode:   exitCode,
+		Output:     cliResult.Output,
+		Agent:      agent,
+		Tokens:     cliResult.Tokens,
+		Confidence: agentConfidence(cliResult.Output, report),
+		Report:     report,
+		ActivityID: currentActivityID(ctx),
 	}, nil
 }
 
+// currentActivityID returns the ID of the activity ctx belongs to, or ""
+// outside an activity.
+func currentActivityID(ctx context.Context) string {
+	if !activity.IsActivity(ctx) {
+		return ""
+	}
+	return activity.GetInfo(ctx).ActivityID
+}
+
+// recordPromptAttempt stores the coder prompt for this attempt so the change
+// from the previous attempt can be reviewed. Failures are logged only.
+func (a *Activities) recordPromptAttempt(ctx context.Context, req TaskRequest, agent, prompt string) {
+	if a.Store == nil || req.Attempt == 0 {
+		return
+	}
+	_, err := a.Store.RecordPromptAttempt(store.PromptAttempt{
+		WorkflowID: activity.GetInfo(ctx).WorkflowExecution.ID,
+		BeadID:     req.BeadID,
+		Project:    req.Project,
+		Attempt:    req.Attempt,
+		Agent:      agent,
+		Provider:   req.Provider,
+		Tier:       dispatchTier(req.Mode),
+		Prompt:     prompt,
+	})
+	if err != nil {
+		activity.GetLogger(ctx).Warn("Failed to record prompt attempt", "BeadID", req.BeadID, "Attempt", req.Attempt, "error", err)
+	}
+}
+
+// executionPrompt builds the coder prompt for a structured plan, applying any
+// project or experiment template.
+func (a *Activities) executionPrompt(ctx context.Context, plan StructuredPlan, req TaskRequest, agent string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("TASK: %s\n\n", plan.Summary))
+	sb.WriteString("PLAN:\n")
+	for i, step := range plan.Steps {
+		sb.WriteString(fmt.Sprintf("%d. [%s] %s\n   Rationale: %s\n", i+1, step.File, step.Description, step.Rationale))
+	}
+	sb.WriteString(fmt.Sprintf("\nFILES TO MODIFY: %s\n", strings.Join(plan.FilesToModify, ", ")))
+	sb.WriteString(fmt.Sprintf("\nACCEPTANCE CRITERIA:\n"))
+	for _, c := range plan.AcceptanceCriteria {
+		sb.WriteString(fmt.Sprintf("- %s\n", c))
+	}
+
+	if len(plan.PreviousErrors) > 0 {
+		sb.WriteString(fmt.Sprintf("\nPREVIOUS ERRORS TO FIX:\n%s\n", strings.Join(plan.PreviousErrors, "\n")))
+	}
+
+	if section := taskAttachmentsSection(req); section != "" {
+		sb.WriteString("\n" + section)
+	}
+
+	sb.WriteString("\nImplement this plan now. Make all necessary code changes.")
+
+	return a.buildPrompt(ctx, "coder", req, PromptData{
+		Stage:       "execute",
+		Agent:       agent,
+		Plan:        plan,
+		Attachments: taskAttachmentsSection(req),
+		Default:     sb.String(),
+	})
+}
+
 // CodeReviewActivity runs a DIFFERENT agent to review the implementation.
 // Claude reviews codex's work, codex reviews claude's. Cross-pollination catches blind spots.
 func (a *Activities) CodeReviewActivity(ctx context.Context, plan StructuredPlan, execResult ExecutionResult, req TaskRequest) (*ReviewResult, error) {
@@ -322,8 +655,22 @@ Be rigorous. Quality enterprise-grade code only. Flag any: missing error handlin
 		formatCriteria(plan.AcceptanceCriteria),
 		truncate(execResult.Output, 3000),
 	)
-
-	cliResult, err := runReviewAgent(ctx, reviewer, prompt, req.WorkDir)
+	prompt = a.buildPrompt(ctx, "reviewer", req, PromptData{
+		Stage:       "review",
+		Agent:       execResult.Agent,
+		Reviewer:    reviewer,
+		Plan:        plan,
+		AgentOutput: execResult.Output,
+		Default:     prompt,
+	})
+
+	cliResult, err := a.runReviewAgent(withCostCap(ctx, a.costCapFor("reviewer", reviewer, req, prompt)), reviewer, prompt, req.WorkDir)
+	cliResult = a.accountUsage(reviewer, "", cliResult)
+	a.observeProvider(ctx, reviewer, "", cliResult, err)
+	if isCostCapExceeded(err) {
+		logger.Warn("Review agent stopped by cost cap", "Reviewer", reviewer, "BeadID", req.BeadID, "error", err)
+		return nil, err
+	}
 	if err != nil {
 		// Review failure is not fatal — log and approve with warning
 		logger.Warn("Review agent error, defaulting to approved with warning", "error", err)
@@ -383,7 +730,9 @@ func (a *Activities) DoDVerifyActivity(ctx context.Context, req TaskRequest) (*D
 		checks = []string{"go build ./..."}
 	}
 
-	gitResult, err := git.RunPostMergeChecks(req.WorkDir, checks)
+	// Checks run on the activity context, so one still running when the
+	// activity times out or the worker stops is killed instead of lingering.
+	gitResult, err := git.RunPostMergeChecksCtx(ctx, req.WorkDir, checks)
 	if err != nil {
 		return nil, fmt.Errorf("DoD check execution failed: %w", err)
 	}
@@ -393,6 +742,37 @@ func (a *Activities) DoDVerifyActivity(ctx context.Context, req TaskRequest) (*D
 		Failures: gitResult.Failures,
 	}
 
+	// Security scanners run once the checks have finished in time; findings
+	// at or above the project's severity in changed files fail DoD.
+	project := a.Projects[req.Project]
+	if len(project.DoD.Security.Scanners) > 0 && ctx.Err() == nil {
+		scan := runSecurityScans(ctx, req.WorkDir, project.BaseBranch, project.DoD.Security)
+		gitResult.Checks = append(gitResult.Checks, scan.Checks...)
+		result.Findings = scan.Findings
+		if len(scan.Failures) > 0 {
+			result.Passed = false
+			result.Failures = append(result.Failures, scan.Failures...)
+		}
+	}
+
+	// Coverage is compared against the project's baseline, which a passing
+	// run then moves to its own measurement.
+	var baseline *store.CoverageBaseline
+	if project.DoD.Coverage.Command != "" && ctx.Err() == nil {
+		if a.Store != nil {
+			if baseline, err = a.Store.GetCoverageBaseline(req.Project); err != nil {
+				logger.Warn("Failed to read coverage baseline", "Project", req.Project, "error", err)
+			}
+		}
+		cov := checkCoverage(ctx, req.WorkDir, project.DoD, baseline)
+		gitResult.Checks = append(gitResult.Checks, cov.Check)
+		result.Coverage = cov.Coverage
+		if len(cov.Failures) > 0 {
+			result.Passed = false
+			result.Failures = append(result.Failures, cov.Failures...)
+		}
+	}
+
 	for _, c := range gitResult.Checks {
 		result.Checks = append(result.Checks, CheckResult{
 			Command:    c.Command,
@@ -400,13 +780,41 @@ func (a *Activities) DoDVerifyActivity(ctx context.Context, req TaskRequest) (*D
 			Output:     c.Output,
 			Passed:     c.Passed,
 			DurationMs: c.Duration.Milliseconds(),
+			Killed:     c.Killed,
 		})
+		if c.Killed {
+			a.recordKilledCheck(ctx, req, c)
+		}
+	}
+
+	if result.Passed && result.Coverage != nil && a.Store != nil {
+		if err := a.Store.SetCoverageBaseline(req.Project, req.BeadID, *result.Coverage); err != nil {
+			logger.Warn("Failed to update coverage baseline", "Project", req.Project, "error", err)
+		}
 	}
 
 	logger.Info("DoD result", "Passed", result.Passed, "Checks", len(result.Checks), "Failures", len(result.Failures))
 	return result, nil
 }
 
+// recordKilledCheck logs a DoD check killed mid-run and records it as a
+// dod_check_killed health event with the tail of its output, which usually
+// shows where it hung.
+func (a *Activities) recordKilledCheck(ctx context.Context, req TaskRequest, c git.CheckResult) {
+	output := c.Output
+	if len(output) > 500 {
+		output = "..." + output[len(output)-500:]
+	}
+	activity.GetLogger(ctx).Warn("DoD check killed", "BeadID", req.BeadID, "Command", c.Command, "Duration", c.Duration, "error", ctx.Err())
+	if a.Store == nil {
+		return
+	}
+	details := fmt.Sprintf("%s: %q killed after %s (%v); output tail: %s", req.Project, c.Command, c.Duration.Round(time.Second), ctx.Err(), output)
+	if err := a.Store.RecordHealthEventWithDispatch("dod_check_killed", details, 0, req.BeadID); err != nil {
+		activity.GetLogger(ctx).Warn("Failed to record killed DoD check", "error", err)
+	}
+}
+
 // RecordOutcomeActivity persists the workflow outcome to the store.
 // This feeds the learner loop — learner runs on top to surface problems and inefficiencies.
 func (a *Activities) RecordOutcomeActivity(ctx context.Context, outcome OutcomeRecord) error {
@@ -424,7 +832,7 @@ func (a *Activities) RecordOutcomeActivity(ctx context.Context, outcome OutcomeR
 		outcome.Project,
 		outcome.Agent,
 		outcome.Provider,
-		"temporal", // tier
+		dispatchTier(outcome.Mode),
 		0,          // handle (not PID-based)
 		"",         // session name
 		"",         // prompt (stored in Temporal history)
@@ -437,13 +845,48 @@ func (a *Activities) RecordOutcomeActivity(ctx context.Context, outcome OutcomeR
 		return err
 	}
 
+	// Keep the output the latest execution streamed with the dispatch
+	if activity.IsActivity(ctx) {
+		if _, err := a.Store.CaptureStreamedOutput(activity.GetInfo(ctx).WorkflowExecution.ID, outcome.OutputActivity, dispatchID); err != nil {
+			logger.Error("Failed to capture streamed output", "error", err)
+		}
+	}
+
 	// Update status
 	if err := a.Store.UpdateDispatchStatus(dispatchID, outcome.Status, outcome.ExitCode, outcome.DurationS); err != nil {
 		logger.Error("Failed to update dispatch status", "error", err)
 	}
+	if outcome.Status == "pending_retry" {
+		if err := a.Store.MarkDispatchPendingRetry(dispatchID, dispatchTier(outcome.Mode), time.Time{}); err != nil {
+			logger.Error("Failed to mark dispatch pending retry", "error", err)
+		}
+	}
+	if outcome.PR != nil && outcome.PR.Number > 0 {
+		if err := a.Store.UpdateDispatchPR(dispatchID, outcome.PR.URL, outcome.PR.Number); err != nil {
+			logger.Error("Failed to record dispatch PR", "error", err)
+		}
+	}
+	if outcome.FailureCategory != "" {
+		if err := a.Store.UpdateFailureDiagnosis(dispatchID, outcome.FailureCategory, outcome.FailureSummary); err != nil {
+			logger.Error("Failed to record failure diagnosis", "error", err)
+		}
+	}
 
-	// Record DoD result
-	if err := a.Store.RecordDoDResult(dispatchID, outcome.BeadID, outcome.Project, outcome.DoDPassed, outcome.DoDFailures, ""); err != nil {
+	// Record DoD result, keeping per-check results for stage transition guards
+	// and the security findings the scanners reported
+	checkResults := ""
+	if len(outcome.DoDChecks) > 0 {
+		if data, err := json.Marshal(outcome.DoDChecks); err == nil {
+			checkResults = string(data)
+		}
+	}
+	findings := ""
+	if len(outcome.DoDFindings) > 0 {
+		if data, err := json.Marshal(outcome.DoDFindings); err == nil {
+			findings = string(data)
+		}
+	}
+	if err := a.Store.RecordDoDResultWithFindings(dispatchID, outcome.BeadID, outcome.Project, outcome.DoDPassed, outcome.DoDFailures, checkResults, findings); err != nil {
 		logger.Error("Failed to record DoD result", "error", err)
 	}
 
@@ -454,6 +897,52 @@ func (a *Activities) RecordOutcomeActivity(ctx context.Context, outcome OutcomeR
 		logger.Error("Failed to record dispatch cost", "error", err)
 	}
 
+	// Attach the outcome to any experiment variants this task ran under.
+	for _, exp := range outcome.Experiments {
+		if exp.RunID == 0 {
+			continue
+		}
+		if err := a.Store.CompleteExperimentRun(exp.RunID, dispatchID, outcome.Status == "completed", outcome.DoDPassed,
+			outcome.DurationS, outcome.TotalTokens.CostUSD); err != nil {
+			logger.Error("Failed to record experiment outcome", "Experiment", exp.Experiment, "error", err)
+		}
+	}
+
+	// Record the reported confidence against the DoD outcome for calibration.
+	if outcome.Confidence.Reported {
+		if err := a.Store.RecordDispatchConfidence(store.DispatchConfidence{
+			DispatchID:  dispatchID,
+			BeadID:      outcome.BeadID,
+			Project:     outcome.Project,
+			Agent:       outcome.Agent,
+			Confidence:  outcome.Confidence.Score,
+			DoDPassed:   outcome.DoDPassed,
+			HumanReview: outcome.HumanReview,
+		}); err != nil {
+			logger.Error("Failed to record dispatch confidence", "error", err)
+		}
+	}
+
+	// Keep the agent's completion block with the dispatch.
+	if r := outcome.Report; r != nil {
+		tests := make([]store.ReportedTest, 0, len(r.TestsRun))
+		for _, t := range r.TestsRun {
+			tests = append(tests, store.ReportedTest{Command: t.Command, Passed: t.Passed})
+		}
+		if err := a.Store.RecordDispatchReport(store.DispatchReport{
+			DispatchID:   dispatchID,
+			BeadID:       outcome.BeadID,
+			Project:      outcome.Project,
+			Agent:        outcome.Agent,
+			FilesChanged: r.FilesChanged,
+			TestsRun:     tests,
+			Confidence:   r.Confidence,
+			FollowUps:    r.FollowUps,
+		}); err != nil {
+			logger.Error("Failed to record dispatch report", "error", err)
+		}
+	}
+
 	// Record per-activity token breakdown for learner optimization.
 	for _, at := range outcome.ActivityTokens {
 		if err := a.Store.StoreTokenUsage(
@@ -482,6 +971,25 @@ func (a *Activities) RecordOutcomeActivity(ctx context.Context, outcome OutcomeR
 			"CostUSD", at.Tokens.CostUSD)
 	}
 
+	// Keep the files the project asked for before the next dispatch overwrites them.
+	workDir := outcome.WorkDir
+	if workDir == "" {
+		workDir = a.Projects[outcome.Project].Workspace
+	}
+	artifacts, err := collectArtifacts(a.Store, a.Artifacts, config.ExpandHome(workDir), a.Projects[outcome.Project].Artifacts,
+		dispatchID, outcome.Project, outcome.BeadID)
+	if err != nil {
+		logger.Warn("Failed to collect artifacts", "error", err)
+	}
+	if len(artifacts) > 0 {
+		logger.Info("Artifacts collected", "DispatchID", dispatchID, "Count", len(artifacts))
+	}
+	if pruned, err := pruneArtifacts(a.Store, a.Artifacts, time.Now()); err != nil {
+		logger.Warn("Failed to prune expired artifacts", "error", err)
+	} else if pruned > 0 {
+		logger.Info("Expired artifacts pruned", "Count", pruned)
+	}
+
 	logger.Info("Outcome recorded", "DispatchID", dispatchID,
 		"InputTokens", totalInput,
 		"OutputTokens", totalOutput,
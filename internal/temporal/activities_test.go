@@ -1,12 +1,19 @@
 package temporal
 
 import (
+	"context"
 	"encoding/json"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/testsuite"
 
+	"github.com/antigravity-dev/cortex/internal/beads"
 	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
 )
 
 func TestResolveTierAgent(t *testing.T) {
@@ -118,6 +125,25 @@ func TestParseJSONOutput_ResultOnlyNoUsage(t *testing.T) {
 	require.Equal(t, 0, result.Tokens.InputTokens)
 }
 
+func TestParseJSONOutput_ClaudeStream(t *testing.T) {
+	stream := `{"type":"system","subtype":"init"}
+{"type":"assistant","message":{"id":"msg_1","content":[{"type":"text","text":"working"}],"usage":{"input_tokens":10,"output_tokens":5}}}
+{"type":"result","subtype":"success","result":"done","total_cost_usd":0.03,"usage":{"input_tokens":40,"output_tokens":20}}
+`
+	result := parseJSONOutput(stream)
+	require.Equal(t, "done", result.Output)
+	require.Equal(t, 40, result.Tokens.InputTokens)
+	require.Equal(t, 20, result.Tokens.OutputTokens)
+	require.InDelta(t, 0.03, result.Tokens.CostUSD, 0.0001)
+
+	// A stream cut off before its result keeps the usage it reported.
+	cut := strings.Join(strings.Split(stream, "\n")[:2], "\n")
+	result = parseJSONOutput(cut)
+	require.Equal(t, cut, result.Output)
+	require.Equal(t, 10, result.Tokens.InputTokens)
+	require.Equal(t, 5, result.Tokens.OutputTokens)
+}
+
 func TestParseAgentOutput_RoutesClaude(t *testing.T) {
 	input := claudeJSONOutput{
 		Result: "claude output",
@@ -147,3 +173,53 @@ func TestTokenUsageAdd(t *testing.T) {
 	require.Equal(t, 15, a.CacheCreationTokens)
 	require.InDelta(t, 0.03, a.CostUSD, 0.0001)
 }
+
+func TestTaskAttachmentsSection(t *testing.T) {
+	workDir := t.TempDir()
+	_, err := beads.SaveAttachment(resolveBeadsDir(workDir), "cortex-1", "crash.png", strings.NewReader("png"))
+	require.NoError(t, err)
+
+	section := taskAttachmentsSection(TaskRequest{BeadID: "cortex-1", WorkDir: workDir})
+	require.Contains(t, section, "crash.png")
+	require.Empty(t, taskAttachmentsSection(TaskRequest{BeadID: "cortex-2", WorkDir: workDir}))
+	require.Empty(t, taskAttachmentsSection(TaskRequest{WorkDir: workDir}))
+}
+
+func TestRecordPromptAttemptDiffsRetries(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+	acts := &Activities{Store: st}
+
+	plan := StructuredPlan{Summary: "add widget", AcceptanceCriteria: []string{"GET /widget returns 200"}}
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivityWithOptions(func(ctx context.Context, plan StructuredPlan, req TaskRequest) error {
+		acts.recordPromptAttempt(ctx, req, req.Agent, acts.executionPrompt(ctx, plan, req, req.Agent))
+		return nil
+	}, activity.RegisterOptions{Name: "record"})
+
+	req := TaskRequest{BeadID: "cx-7", Project: "cortex", Agent: "claude", Attempt: 1}
+	_, err = env.ExecuteActivity("record", plan, req)
+	require.NoError(t, err)
+
+	plan.PreviousErrors = []string{"DoD check failures: go test ./... failed"}
+	req.Agent, req.Attempt = "codex", 2
+	_, err = env.ExecuteActivity("record", plan, req)
+	require.NoError(t, err)
+
+	attempts, err := st.GetPromptAttempts("cx-7")
+	require.NoError(t, err)
+	require.Len(t, attempts, 2)
+	require.Empty(t, attempts[0].Diff)
+	require.Equal(t, []string{"agent: claude -> codex"}, attempts[1].Changes)
+	require.Contains(t, attempts[1].Diff, "+PREVIOUS ERRORS TO FIX:\n+DoD check failures: go test ./... failed\n")
+
+	// Requests without an attempt number (e.g. direct activity calls) are not recorded.
+	req.BeadID, req.Attempt = "cx-8", 0
+	_, err = env.ExecuteActivity("record", plan, req)
+	require.NoError(t, err)
+	attempts, err = st.GetPromptAttempts("cx-8")
+	require.NoError(t, err)
+	require.Empty(t, attempts)
+}
@@ -0,0 +1,202 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+// approvalSignal is the signal a waiting workflow receives its answer on,
+// "APPROVED" or "REJECTED", from chat or the approve and reject endpoints.
+const approvalSignal = "human-approval"
+
+// ApprovalPrompt describes an action waiting on a human answer.
+type ApprovalPrompt struct {
+	Kind    string `json:"kind"` // "plan", "budget" or "revert"
+	Project string `json:"project"`
+	BeadID  string `json:"bead_id"`
+	Summary string `json:"summary"`
+}
+
+// ApprovalTicket is a prompt posted to chat. ID is zero when no prompt was
+// posted, and a zero Timeout waits for an answer forever.
+type ApprovalTicket struct {
+	ID            int64         `json:"id"`
+	Timeout       time.Duration `json:"timeout"`
+	DefaultAction string        `json:"default_action"` // "approve" or "deny"
+}
+
+// RequestApprovalActivity records an approval request and posts its prompt
+// to the project room. Without chat approvals, a sender or a room it posts
+// nothing and the workflow waits for the API as before.
+func (a *Activities) RequestApprovalActivity(ctx context.Context, prompt ApprovalPrompt) (*ApprovalTicket, error) {
+	ticket := &ApprovalTicket{Timeout: a.Approvals.Timeout.Duration, DefaultAction: a.Approvals.DefaultAction}
+	room := a.Projects[prompt.Project].MatrixRoom
+	if !a.Approvals.Chat || a.Sender == nil || a.Store == nil || room == "" {
+		return ticket, nil
+	}
+	id, err := a.Store.CreateApprovalRequest(store.ApprovalRequest{
+		Kind:       prompt.Kind,
+		Project:    prompt.Project,
+		BeadID:     prompt.BeadID,
+		WorkflowID: activity.GetInfo(ctx).WorkflowExecution.ID,
+		Room:       room,
+		Prompt:     prompt.Summary,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := a.Sender.SendMessage(ctx, room, approvalPromptMessage(id, prompt, a.Approvals)); err != nil {
+		return nil, fmt.Errorf("post approval prompt: %w", err)
+	}
+	ticket.ID = id
+	return ticket, nil
+}
+
+// CloseApprovalActivity records how a prompt was answered. An answer given
+// in chat was already recorded by ChatApprover and is kept; a default taken
+// on timeout is announced in the room.
+func (a *Activities) CloseApprovalActivity(ctx context.Context, id int64, approved bool, decidedBy string) error {
+	if a.Store == nil || id == 0 {
+		return nil
+	}
+	closed, err := a.Store.DecideApprovalRequest(id, approved, decidedBy)
+	if err != nil || !closed || decidedBy != "timeout" {
+		return err
+	}
+	r, err := a.Store.GetApprovalRequest(id)
+	if err != nil || r == nil || a.Sender == nil {
+		return err
+	}
+	verdict := "denied"
+	if approved {
+		verdict = "approved"
+	}
+	msg := fmt.Sprintf("Approval #%d (%s %s) got no answer in time and was %s by default.", id, r.Kind, r.BeadID, verdict)
+	if err := a.Sender.SendMessage(ctx, r.Room, msg); err != nil {
+		activity.GetLogger(ctx).Warn("Approval timeout notice failed", "ID", id, "error", err)
+	}
+	return nil
+}
+
+func approvalPromptMessage(id int64, prompt ApprovalPrompt, cfg config.DispatchApprovals) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Approval needed #%d: %s for %s (%s)", id, prompt.Kind, prompt.BeadID, prompt.Project)
+	if s := strings.TrimSpace(prompt.Summary); s != "" {
+		b.WriteString("\n" + s)
+	}
+	fmt.Fprintf(&b, "\nReply /cortex approve %d or /cortex deny %d.", id, id)
+	if cfg.Timeout.Duration > 0 {
+		verdict := "denied"
+		if cfg.DefaultAction == "approve" {
+			verdict = "approved"
+		}
+		fmt.Fprintf(&b, " Without an answer in %s it is %s.", cfg.Timeout.Duration, verdict)
+	}
+	return b.String()
+}
+
+// awaitApproval waits for the answer to ticket and returns it with who gave
+// it: "signal" for an answer from chat or the API, "timeout" when the
+// ticket's default action was taken.
+func awaitApproval(ctx workflow.Context, ticket ApprovalTicket) (approved bool, decidedBy string) {
+	signalChan := workflow.GetSignalChannel(ctx, approvalSignal)
+	var signalVal string
+	if ticket.Timeout <= 0 {
+		signalChan.Receive(ctx, &signalVal)
+		return signalVal != "REJECTED", "signal"
+	}
+
+	timerCtx, cancelTimer := workflow.WithCancel(ctx)
+	defer cancelTimer()
+	timedOut := false
+	sel := workflow.NewSelector(ctx)
+	sel.AddReceive(signalChan, func(c workflow.ReceiveChannel, _ bool) {
+		c.Receive(ctx, &signalVal)
+	})
+	sel.AddFuture(workflow.NewTimer(timerCtx, ticket.Timeout), func(workflow.Future) {
+		timedOut = true
+	})
+	sel.Select(ctx)
+	if timedOut {
+		return ticket.DefaultAction == "approve", "timeout"
+	}
+	return signalVal != "REJECTED", "signal"
+}
+
+// requestApproval posts prompt, waits for its answer and records how it
+// was answered. A prompt that cannot be posted waits for the API instead.
+func requestApproval(ctx workflow.Context, opts workflow.ActivityOptions, a *Activities, prompt ApprovalPrompt) (approved bool, decidedBy string) {
+	logger := workflow.GetLogger(ctx)
+	approvalCtx := workflow.WithActivityOptions(ctx, opts)
+	var ticket ApprovalTicket
+	if err := workflow.ExecuteActivity(approvalCtx, a.RequestApprovalActivity, prompt).Get(ctx, &ticket); err != nil {
+		logger.Warn("Approval prompt failed, waiting for the API", "Kind", prompt.Kind, "error", err)
+		ticket = ApprovalTicket{}
+	}
+	approved, decidedBy = awaitApproval(ctx, ticket)
+	if ticket.ID != 0 {
+		if err := workflow.ExecuteActivity(approvalCtx, a.CloseApprovalActivity, ticket.ID, approved, decidedBy).Get(ctx, nil); err != nil {
+			logger.Warn("Approval close failed", "ID", ticket.ID, "error", err)
+		}
+	}
+	return approved, decidedBy
+}
+
+// ChatApprover answers approval requests from /cortex approve and deny by
+// recording the answer and signalling the waiting workflow.
+type ChatApprover struct {
+	Store    *store.Store
+	Temporal config.Temporal
+}
+
+// DecideApproval implements matrix.Approver.
+func (c *ChatApprover) DecideApproval(ctx context.Context, id int64, approve bool, decidedBy, room string) (string, error) {
+	r, err := c.Store.GetApprovalRequest(id)
+	if err != nil {
+		return "", err
+	}
+	if r == nil || r.Room != room {
+		return "", fmt.Errorf("approval request #%d not found in this room", id)
+	}
+	what := fmt.Sprintf("%s for %s", r.Kind, r.BeadID)
+	decided, err := c.Store.DecideApprovalRequest(id, approve, decidedBy)
+	if err != nil {
+		return "", err
+	}
+	if !decided {
+		if r, _ = c.Store.GetApprovalRequest(id); r != nil {
+			return "", fmt.Errorf("approval request #%d was already %s by %s", id, r.Status, r.DecidedBy)
+		}
+		return "", fmt.Errorf("approval request #%d was already answered", id)
+	}
+
+	signal := "APPROVED"
+	if !approve {
+		signal = "REJECTED"
+	}
+	err = c.signal(ctx, r.WorkflowID, signal)
+	if err != nil {
+		if rerr := c.Store.ReopenApprovalRequest(id); rerr != nil {
+			return "", fmt.Errorf("signal workflow %s: %w (reopen: %v)", r.WorkflowID, err, rerr)
+		}
+		return "", fmt.Errorf("signal workflow %s: %w", r.WorkflowID, err)
+	}
+	return what, nil
+}
+
+func (c *ChatApprover) signal(ctx context.Context, workflowID, value string) error {
+	tc, err := DialContext(ctx, c.Temporal)
+	if err != nil {
+		return err
+	}
+	defer tc.Close()
+	return tc.SignalWorkflow(ctx, workflowID, "", approvalSignal, value)
+}
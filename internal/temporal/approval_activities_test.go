@@ -0,0 +1,104 @@
+package temporal
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestRequestApprovalActivityPostsPrompt(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+
+	sender := &recordingSender{}
+	acts := &Activities{
+		Store:     st,
+		Sender:    sender,
+		Projects:  map[string]config.Project{"cortex": {MatrixRoom: "!cortex"}},
+		Approvals: config.DispatchApprovals{Chat: true, Timeout: config.Duration{Duration: time.Hour}, DefaultAction: "deny"},
+	}
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(acts.RequestApprovalActivity)
+	env.RegisterActivity(acts.CloseApprovalActivity)
+
+	val, err := env.ExecuteActivity(acts.RequestApprovalActivity, ApprovalPrompt{Kind: "plan", Project: "cortex", BeadID: "cortex-7", Summary: "Add widget"})
+	require.NoError(t, err)
+	var ticket ApprovalTicket
+	require.NoError(t, val.Get(&ticket))
+	require.NotZero(t, ticket.ID)
+	require.Equal(t, time.Hour, ticket.Timeout)
+	require.Equal(t, []string{"!cortex"}, sender.rooms)
+	require.Contains(t, sender.messages[0], "Approval needed #1: plan for cortex-7 (cortex)\nAdd widget")
+	require.Contains(t, sender.messages[0], "Reply /cortex approve 1 or /cortex deny 1. Without an answer in 1h0m0s it is denied.")
+
+	r, err := st.GetApprovalRequest(ticket.ID)
+	require.NoError(t, err)
+	require.Equal(t, store.ApprovalPending, r.Status)
+	require.Equal(t, "!cortex", r.Room)
+
+	_, err = env.ExecuteActivity(acts.CloseApprovalActivity, ticket.ID, false, "timeout")
+	require.NoError(t, err)
+	r, err = st.GetApprovalRequest(ticket.ID)
+	require.NoError(t, err)
+	require.Equal(t, store.ApprovalDenied, r.Status)
+	require.Equal(t, "timeout", r.DecidedBy)
+	require.Len(t, sender.messages, 2)
+	require.Contains(t, sender.messages[1], "got no answer in time and was denied by default")
+
+	// Without chat approvals nothing is posted.
+	acts.Approvals.Chat = false
+	val, err = env.ExecuteActivity(acts.RequestApprovalActivity, ApprovalPrompt{Kind: "plan", Project: "cortex", BeadID: "cortex-8"})
+	require.NoError(t, err)
+	require.NoError(t, val.Get(&ticket))
+	require.Zero(t, ticket.ID)
+	require.Len(t, sender.messages, 2)
+}
+
+func TestPlanApprovalTimeoutTakesDefault(t *testing.T) {
+	for _, tc := range []struct {
+		defaultAction string
+		wantApproved  bool
+	}{
+		{"deny", false},
+		{"approve", true},
+	} {
+		t.Run(tc.defaultAction, func(t *testing.T) {
+			s := testsuite.WorkflowTestSuite{}
+			env := s.NewTestWorkflowEnvironment()
+			var a *Activities
+			env.OnActivity(a.RequestApprovalActivity, mock.Anything, mock.Anything).Return(&ApprovalTicket{
+				ID: 4, Timeout: time.Hour, DefaultAction: tc.defaultAction,
+			}, nil)
+			var closed []any
+			env.OnActivity(a.CloseApprovalActivity, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				closed = []any{args.Get(1), args.Get(2), args.Get(3)}
+			}).Return(nil)
+			env.OnActivity(a.RecordOutcomeActivity, mock.Anything, mock.Anything).Return(nil).Maybe()
+			stubActivities(env) // after the ticket mock, which must match first
+			env.OnWorkflow(ContinuousLearnerWorkflow, mock.Anything, mock.Anything).Return(nil).Maybe()
+			env.OnWorkflow(TacticalGroomWorkflow, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			env.ExecuteWorkflow(CortexAgentWorkflow, TaskRequest{
+				BeadID: "test-bead-timeout", Project: "test-project", Prompt: "widget", Agent: "claude", WorkDir: "/tmp/test",
+			})
+
+			require.True(t, env.IsWorkflowCompleted())
+			require.Equal(t, []any{int64(4), tc.wantApproved, "timeout"}, closed)
+			if tc.wantApproved {
+				env.AssertActivityCalled(t, "ExecuteActivity", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.ErrorContains(t, env.GetWorkflowError(), "plan approval timed out")
+			env.AssertActivityNotCalled(t, "ExecuteActivity", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package temporal
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+// collectArtifacts copies the files in workDir matching patterns to
+// <dir>/<project>/<dispatch id>/ and records them against the dispatch.
+// Directories, symlinks, files reached through a symlinked directory outside
+// workDir and files over the size cap are skipped. It returns
+// the artifacts it stored; a file that fails to copy is reported in the
+// error without stopping the others.
+func collectArtifacts(st *store.Store, cfg config.DispatchArtifacts, workDir string, patterns []string,
+	dispatchID int64, project, beadID string) ([]store.Artifact, error) {
+	if st == nil || cfg.Dir == "" || workDir == "" || len(patterns) == 0 {
+		return nil, nil
+	}
+	maxBytes := int64(cfg.MaxFileMB) << 20
+	dest := filepath.Join(cfg.Dir, project, strconv.FormatInt(dispatchID, 10))
+	root, err := filepath.EvalSymlinks(workDir)
+	if err != nil {
+		return nil, fmt.Errorf("artifacts: resolve workspace: %w", err)
+	}
+	fsys := os.DirFS(workDir)
+
+	seen := map[string]bool{}
+	var stored []store.Artifact
+	var firstErr error
+	for _, pattern := range patterns {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return stored, fmt.Errorf("artifacts: pattern %q: %w", pattern, err)
+		}
+		for _, rel := range matches {
+			if seen[rel] {
+				continue
+			}
+			seen[rel] = true
+			src := filepath.Join(workDir, filepath.FromSlash(rel))
+			info, err := os.Lstat(src)
+			if err != nil || !info.Mode().IsRegular() || (maxBytes > 0 && info.Size() > maxBytes) {
+				continue
+			}
+			// Lstat only sees the last element; a symlinked directory on
+			// the way could still lead out of the workspace.
+			resolved, err := filepath.EvalSymlinks(src)
+			if err != nil || !withinDir(root, resolved) {
+				continue
+			}
+			target := filepath.Join(dest, filepath.FromSlash(rel))
+			if err := copyArtifact(resolved, target); err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("artifacts: copy %s: %w", rel, err)
+				}
+				continue
+			}
+			a := store.Artifact{DispatchID: dispatchID, Project: project, BeadID: beadID, Path: rel, StoredPath: target, SizeBytes: info.Size()}
+			id, err := st.RecordArtifact(a)
+			if err != nil {
+				return stored, err
+			}
+			a.ID = id
+			stored = append(stored, a)
+		}
+	}
+	return stored, firstErr
+}
+
+// withinDir reports whether path is dir or lies beneath it.
+func withinDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func copyArtifact(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// pruneArtifacts deletes the artifacts collected more than retentionDays
+// ago, files and records both, and returns how many it removed.
+func pruneArtifacts(st *store.Store, cfg config.DispatchArtifacts, now time.Time) (int, error) {
+	if st == nil || cfg.RetentionDays <= 0 {
+		return 0, nil
+	}
+	expired, err := st.ListArtifactsBefore(now.AddDate(0, 0, -cfg.RetentionDays))
+	if err != nil {
+		return 0, err
+	}
+	dirs := map[string]bool{}
+	for _, a := range expired {
+		if err := os.Remove(a.StoredPath); err != nil && !os.IsNotExist(err) {
+			return 0, fmt.Errorf("artifacts: remove %s: %w", a.StoredPath, err)
+		}
+		if err := st.DeleteArtifact(a.ID); err != nil {
+			return 0, err
+		}
+		if cfg.Dir != "" {
+			dirs[filepath.Join(cfg.Dir, a.Project, strconv.FormatInt(a.DispatchID, 10))] = true
+		}
+	}
+	// Every artifact of a dispatch is collected at once, so its directory
+	// only holds expired files and their now empty subdirectories.
+	for dir := range dirs {
+		os.RemoveAll(dir)
+	}
+	return len(expired), nil
+}
@@ -0,0 +1,117 @@
+package temporal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestCollectAndPruneArtifacts(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { st.Close() })
+
+	work := t.TempDir()
+	write := func(rel, content string) {
+		t.Helper()
+		p := filepath.Join(work, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("coverage.out", "mode: set")
+	write("reports/unit.xml", "<testsuite/>")
+	write("reports/big.xml", string(make([]byte, 2<<20)))
+	write("main.go", "package main")
+	if err := os.Symlink(filepath.Join(work, "main.go"), filepath.Join(work, "reports", "link.xml")); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DispatchArtifacts{Dir: t.TempDir(), RetentionDays: 14, MaxFileMB: 1}
+	got, err := collectArtifacts(st, cfg, work, []string{"coverage.out", "reports/*.xml", "*.out", "missing/*"}, 7, "alpha", "cx-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Path != "coverage.out" || got[1].Path != "reports/unit.xml" {
+		t.Fatalf("expected coverage.out and reports/unit.xml, got %+v", got)
+	}
+	data, err := os.ReadFile(filepath.Join(cfg.Dir, "alpha", "7", "reports", "unit.xml"))
+	if err != nil || string(data) != "<testsuite/>" {
+		t.Fatalf("expected the stored copy, got %q, %v", data, err)
+	}
+	listed, err := st.ListArtifacts(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 2 || listed[1].SizeBytes != int64(len("<testsuite/>")) {
+		t.Fatalf("unexpected recorded artifacts %+v", listed)
+	}
+
+	if n, err := pruneArtifacts(st, cfg, time.Now()); err != nil || n != 0 {
+		t.Fatalf("expected fresh artifacts kept, pruned %d: %v", n, err)
+	}
+	n, err := pruneArtifacts(st, cfg, time.Now().AddDate(0, 0, 15))
+	if err != nil || n != 2 {
+		t.Fatalf("expected both artifacts pruned, got %d: %v", n, err)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.Dir, "alpha", "7")); !os.IsNotExist(err) {
+		t.Fatalf("expected the dispatch directory removed, got %v", err)
+	}
+	if listed, _ := st.ListArtifacts(7); len(listed) != 0 {
+		t.Fatalf("expected records removed, got %+v", listed)
+	}
+
+	if got, err := collectArtifacts(st, config.DispatchArtifacts{}, work, []string{"*.out"}, 8, "alpha", "cx-1"); err != nil || got != nil {
+		t.Fatalf("expected no collection without a dir, got %+v, %v", got, err)
+	}
+}
+
+func TestCollectArtifactsStaysInWorkspace(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { st.Close() })
+
+	outside := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "secret.out"), []byte("token"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	work := t.TempDir()
+	if err := os.WriteFile(filepath.Join(work, "local.out"), []byte("ok"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(work, "escape")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(work, "inner"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(work, "inner", "kept.out"), []byte("ok"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(work, "inner"), filepath.Join(work, "alias")); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DispatchArtifacts{Dir: t.TempDir(), RetentionDays: 14}
+	got, err := collectArtifacts(st, cfg, work, []string{"*.out", "escape/*.out", "alias/*.out"}, 9, "alpha", "cx-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Path != "local.out" || got[1].Path != "alias/kept.out" {
+		t.Fatalf("expected only files resolving inside the workspace, got %+v", got)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.Dir, "alpha", "9", "escape", "secret.out")); !os.IsNotExist(err) {
+		t.Fatalf("expected the file outside the workspace not copied, got %v", err)
+	}
+}
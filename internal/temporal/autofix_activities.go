@@ -0,0 +1,93 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.temporal.io/sdk/activity"
+
+	"github.com/antigravity-dev/cortex/internal/git"
+)
+
+// AutofixActivity applies the project's formatter and linter fixes to the
+// coder's work before it goes to review. Fix commands that still fail after
+// fixing what they can are handed to the fast-tier agent when the project
+// enables it. Projects without autofix commands skip the stage.
+func (a *Activities) AutofixActivity(ctx context.Context, req TaskRequest) (*AutofixResult, error) {
+	fix := a.Projects[req.Project].Autofix
+	result := &AutofixResult{}
+	if len(fix.Commands) == 0 || req.WorkDir == "" {
+		return result, nil
+	}
+	logger := activity.GetLogger(ctx)
+
+	// The fixes get a commit of their own only when the coder committed its
+	// work; otherwise they stay in the work tree next to it, so the review
+	// diff still shows the whole change.
+	dirty, err := git.HasUncommittedChanges(req.WorkDir)
+	if err != nil {
+		return nil, err
+	}
+	before, err := git.WorkTreeID(req.WorkDir)
+	if err != nil {
+		return nil, err
+	}
+
+	runs, err := git.RunPostMergeChecksCtx(ctx, req.WorkDir, fix.Commands)
+	if err != nil {
+		return nil, fmt.Errorf("autofix: %w", err)
+	}
+	var remaining []git.CheckResult
+	for _, c := range runs.Checks {
+		result.Checks = append(result.Checks, CheckResult{
+			Command:    c.Command,
+			ExitCode:   c.ExitCode,
+			Output:     c.Output,
+			Passed:     c.Passed,
+			DurationMs: c.Duration.Milliseconds(),
+			Killed:     c.Killed,
+		})
+		if !c.Passed && !c.Killed {
+			remaining = append(remaining, c)
+		}
+	}
+
+	if fix.Agent && len(remaining) > 0 && ctx.Err() == nil {
+		agent := ResolveTierAgent(a.Tiers, "fast")
+		cliResult, err := a.runAgent(ctx, agent, autofixPrompt(remaining), req.WorkDir)
+		cliResult = a.accountUsage(agent, "", cliResult)
+		a.observeProvider(ctx, agent, "", cliResult, err)
+		result.Agent, result.Tokens = agent, cliResult.Tokens
+		if err != nil {
+			logger.Warn("Autofix agent failed", "Agent", agent, "BeadID", req.BeadID, "error", err)
+		}
+	}
+
+	after, err := git.WorkTreeID(req.WorkDir)
+	if err != nil {
+		return nil, err
+	}
+	result.Changed = after != before
+	if result.Changed && !dirty {
+		if result.Committed, err = git.CommitAll(req.WorkDir, fix.CommitMessage); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Info("Autofix applied", "BeadID", req.BeadID, "Changed", result.Changed, "Committed", result.Committed,
+		"Remaining", len(remaining), "Agent", result.Agent)
+	return result, nil
+}
+
+// autofixPrompt asks the fast-tier agent to clear the issues the fix
+// commands could not fix themselves, and nothing else.
+func autofixPrompt(remaining []git.CheckResult) string {
+	var sb strings.Builder
+	sb.WriteString("You are fixing formatting and lint issues only. These commands still report problems after applying their automatic fixes:\n")
+	for _, c := range remaining {
+		sb.WriteString(fmt.Sprintf("\n$ %s (exit %d)\n%s\n", c.Command, c.ExitCode, truncate(c.Output, 2000)))
+	}
+	sb.WriteString("\nFix the reported issues in place. Do not change behaviour, rename exported identifiers or touch unrelated code, and do not commit.")
+	return sb.String()
+}
@@ -0,0 +1,92 @@
+package temporal
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+func TestAutofixActivity(t *testing.T) {
+	workDir := t.TempDir()
+	gitRun := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = workDir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		return strings.TrimSpace(string(out))
+	}
+	gitRun("init", "-b", "master")
+	gitRun("config", "user.email", "test@example.com")
+	gitRun("config", "user.name", "Test User")
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "main.go"), []byte("package main\n"), 0o644))
+	gitRun("add", "main.go")
+	gitRun("commit", "-m", "coder work")
+
+	// The fake agent fixes what the linter reports.
+	fakeBin := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(fakeBin, "codex"),
+		[]byte("#!/bin/sh\necho '// linted' >> main.go\n"), 0o755))
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	acts := &Activities{Projects: map[string]config.Project{
+		"proj": {Autofix: config.ProjectAutofix{
+			Commands:      []string{"echo 'formatted' > fmt.txt", "grep -q linted main.go"},
+			Agent:         true,
+			CommitMessage: "style: apply lint and format fixes",
+		}},
+	}}
+	run := func() AutofixResult {
+		s := testsuite.WorkflowTestSuite{}
+		env := s.NewTestActivityEnvironment()
+		env.RegisterActivity(acts.AutofixActivity)
+		val, err := env.ExecuteActivity(acts.AutofixActivity, TaskRequest{BeadID: "cx-1", Project: "proj", WorkDir: workDir})
+		require.NoError(t, err)
+		var res AutofixResult
+		require.NoError(t, val.Get(&res))
+		return res
+	}
+
+	// Committed coder work: the fixes get their own commit.
+	res := run()
+	require.True(t, res.Changed)
+	require.True(t, res.Committed)
+	require.Equal(t, "codex", res.Agent)
+	require.Len(t, res.Checks, 2)
+	require.False(t, res.Checks[1].Passed)
+	require.Equal(t, "style: apply lint and format fixes", gitRun("log", "-1", "--format=%s"))
+	require.Empty(t, gitRun("status", "--porcelain"))
+
+	// Nothing left to fix: no agent and no commit.
+	res = run()
+	require.False(t, res.Changed)
+	require.False(t, res.Committed)
+	require.Empty(t, res.Agent)
+
+	// Uncommitted coder work: the fixes stay in the work tree with it.
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "work.go"), []byte("package main\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "fmt.txt"), []byte("unformatted\n"), 0o644))
+	res = run()
+	require.True(t, res.Changed)
+	require.False(t, res.Committed)
+	require.Equal(t, "style: apply lint and format fixes", gitRun("log", "-1", "--format=%s"))
+	require.Equal(t, "?? work.go", gitRun("status", "--porcelain"))
+}
+
+func TestAutofixActivitySkipsUnconfiguredProject(t *testing.T) {
+	acts := &Activities{}
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(acts.AutofixActivity)
+	val, err := env.ExecuteActivity(acts.AutofixActivity, TaskRequest{BeadID: "cx-1", Project: "proj", WorkDir: t.TempDir()})
+	require.NoError(t, err)
+	var res AutofixResult
+	require.NoError(t, val.Get(&res))
+	require.Equal(t, AutofixResult{}, res)
+}
@@ -0,0 +1,82 @@
+package temporal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.temporal.io/sdk/activity"
+
+	"github.com/antigravity-dev/cortex/internal/burnin"
+)
+
+// BurnInReportActivity scores every project's dispatches over the request
+// window against its burn-in gates and writes the JSON and Markdown artifacts
+// to the reports dir. Trends compare against the latest earlier report. The
+// outcome is recorded as a burnin_report health event, and failing gates are
+// posted to the room.
+func (a *Activities) BurnInReportActivity(ctx context.Context, req BurnInReportRequest) (*BurnInReportResult, error) {
+	logger := activity.GetLogger(ctx)
+	if a.Store == nil {
+		return nil, fmt.Errorf("burn-in report: no store configured")
+	}
+
+	to := time.Now().UTC()
+	report, err := burnin.Generate(a.Store, req.SLOs, to.Add(-req.Window), to)
+	if err != nil {
+		return nil, err
+	}
+	prev, err := previousBurnInReport(req.ReportsDir, report.Date())
+	if err != nil {
+		logger.Warn("Burn-in report: previous report unreadable, skipping trends", "error", err)
+	}
+	report.ApplyTrend(prev)
+
+	jsonPath, mdPath, err := burnin.Write(req.ReportsDir, report)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BurnInReportResult{
+		Date:         report.Date(),
+		Pass:         report.Pass,
+		Failing:      report.Failing(),
+		JSONPath:     jsonPath,
+		MarkdownPath: mdPath,
+	}
+	msg := burnInMessage(result)
+	a.Store.RecordHealthEvent("burnin_report", msg)
+	if !result.Pass && a.Sender != nil && req.Room != "" {
+		if err := a.Sender.SendMessage(ctx, req.Room, msg); err != nil {
+			logger.Warn("Burn-in report: notify failed", "Room", req.Room, "error", err)
+		}
+	}
+	return result, nil
+}
+
+// previousBurnInReport returns the newest report dated before date, or nil.
+func previousBurnInReport(dir, date string) (*burnin.Report, error) {
+	dates, err := burnin.Dates(dir)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(dates) - 1; i >= 0; i-- {
+		if dates[i] < date {
+			r, err := burnin.Load(dir, dates[i])
+			if errors.Is(err, burnin.ErrNoReport) {
+				return nil, nil
+			}
+			return r, err
+		}
+	}
+	return nil, nil
+}
+
+func burnInMessage(r *BurnInReportResult) string {
+	if r.Pass {
+		return fmt.Sprintf("Burn-in report %s: all SLO gates pass (%s)", r.Date, r.MarkdownPath)
+	}
+	return fmt.Sprintf("Burn-in report %s: SLO gates failing for %s (%s)", r.Date, strings.Join(r.Failing, ", "), r.MarkdownPath)
+}
@@ -0,0 +1,74 @@
+package temporal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/burnin"
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestBurnInReportActivityWritesArtifacts(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+
+	for _, status := range []string{"completed", "interrupted"} {
+		id, err := st.RecordDispatch("b-"+status, "cortex", "agent", "claude", "balanced", 1, "", "p", "", "", "")
+		require.NoError(t, err)
+		require.NoError(t, st.UpdateDispatchStatus(id, status, 0, 1))
+		require.NoError(t, st.SetDispatchTime(id, time.Now().Add(-time.Hour)))
+	}
+
+	dir := filepath.Join(t.TempDir(), "reports")
+	// An earlier report provides the trend baseline.
+	_, _, err = burnin.Write(dir, &burnin.Report{
+		To: time.Now().Add(-24 * time.Hour),
+		Projects: []burnin.ProjectReport{{Project: "cortex", Gates: []burnin.Gate{
+			{Name: burnin.GateUnknownRate, Value: 0.1},
+		}}},
+	})
+	require.NoError(t, err)
+
+	sender := &recordingSender{}
+	acts := &Activities{Store: st, Sender: sender}
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(acts.BurnInReportActivity)
+
+	val, err := env.ExecuteActivity(acts.BurnInReportActivity, BurnInReportRequest{
+		Window:     24 * time.Hour,
+		ReportsDir: dir,
+		SLOs: map[string]config.BurnInSLO{
+			"cortex": {UnknownRateLimit: 0.02, InterventionRateLimit: 0.1, CriticalEventLimit: 2},
+		},
+		Room: "!ops",
+	})
+	require.NoError(t, err)
+	var result BurnInReportResult
+	require.NoError(t, val.Get(&result))
+
+	require.False(t, result.Pass)
+	require.Equal(t, []string{"cortex"}, result.Failing)
+	require.FileExists(t, result.JSONPath)
+	md, err := os.ReadFile(result.MarkdownPath)
+	require.NoError(t, err)
+	require.Contains(t, string(md), "| Unknown/Disappeared Rate | 50.00% | < 2.00% | FAIL | ↗ |")
+
+	latest, err := burnin.Latest(dir)
+	require.NoError(t, err)
+	require.Equal(t, result.Date, latest.Date())
+
+	require.Equal(t, []string{"!ops"}, sender.rooms)
+	require.Contains(t, sender.messages[0], "SLO gates failing for cortex")
+	events, err := st.GetRecentHealthEvents(1)
+	require.NoError(t, err)
+	require.NotEmpty(t, events)
+	require.Equal(t, "burnin_report", events[0].EventType)
+}
@@ -0,0 +1,252 @@
+package temporal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.temporal.io/sdk/activity"
+
+	"github.com/antigravity-dev/cortex/internal/dispatch"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+// adoptPollInterval is how often a re-adopted agent process is checked for exit.
+const adoptPollInterval = 2 * time.Second
+
+// agentCheckpoints persists the agent processes this worker starts so that,
+// after a restart, the retried activity re-adopts a still-running process
+// instead of launching a duplicate agent on the same working tree.
+type agentCheckpoints struct {
+	store *store.Store
+	dir   string
+}
+
+// checkpoints is configured by StartWorker; nil disables checkpointing.
+var checkpoints *agentCheckpoints
+
+func newAgentCheckpoints(st *store.Store, dir string) (*agentCheckpoints, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create agent run directory: %w", err)
+	}
+	return &agentCheckpoints{store: st, dir: dir}, nil
+}
+
+// agentCall is one checkpointed agent invocation.
+type agentCall struct {
+	cp     *agentCheckpoints
+	run    store.AgentRun
+	stdout *os.File
+	stderr *os.File
+}
+
+// agentRunKey identifies the run an activity is executing: its bead and
+// execution attempt. Unlike the activity ID, it is fixed by the workflow's own
+// state, so a retried or rescheduled activity finds the same checkpoint.
+type agentRunKey struct {
+	beadID  string
+	attempt int
+}
+
+// withAgentRun attaches the bead and attempt being run to ctx.
+func withAgentRun(ctx context.Context, beadID string, attempt int) context.Context {
+	return context.WithValue(ctx, agentRunKey{}, agentRunKey{beadID: beadID, attempt: attempt})
+}
+
+// call returns the checkpoint for cmd run from the current activity, or nil
+// when checkpointing is off or ctx is not an activity context. Runs without a
+// bead and attempt on ctx fall back to the activity ID.
+func (c *agentCheckpoints) call(ctx context.Context, agent string, cmd *exec.Cmd) *agentCall {
+	if c == nil || !activity.IsActivity(ctx) {
+		return nil
+	}
+	info := activity.GetInfo(ctx)
+	key, ok := ctx.Value(agentRunKey{}).(agentRunKey)
+	if !ok {
+		key = agentRunKey{beadID: "activity:" + info.ActivityID}
+	}
+	return c.newAgentCall(info.WorkflowExecution.ID, key.beadID, key.attempt, agent, cmd)
+}
+
+func (c *agentCheckpoints) newAgentCall(workflowID, beadID string, attempt int, agent string, cmd *exec.Cmd) *agentCall {
+	key := hashParts(append([]string{cmd.Dir}, cmd.Args...)...)
+	base := filepath.Join(c.dir, hashParts(workflowID, beadID, strconv.Itoa(attempt), key)[:16])
+	return &agentCall{cp: c, run: store.AgentRun{
+		WorkflowID: workflowID,
+		BeadID:     beadID,
+		Attempt:    attempt,
+		CallKey:    key,
+		Agent:      agent,
+		Command:    filepath.Base(cmd.Args[0]),
+		StdoutPath: base + ".out",
+		StderrPath: base + ".err",
+	}}
+}
+
+func hashParts(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// adopt waits for a process a previous worker started for this same call and
+// returns its output. It reports false when there is nothing alive to adopt,
+// clearing any stale checkpoint so the caller starts a fresh run.
+func (c *agentCall) adopt(ctx context.Context) (CLIResult, bool, error) {
+	if c == nil {
+		return CLIResult{}, false, nil
+	}
+	prev, err := c.cp.store.GetAgentRun(c.run.WorkflowID, c.run.BeadID, c.run.Attempt, c.run.CallKey)
+	if err != nil || prev == nil {
+		return CLIResult{}, false, nil
+	}
+	if !processMatches(prev.PID, prev.Command) {
+		c.cp.discard(*prev)
+		return CLIResult{}, false, nil
+	}
+
+	logger := activity.GetLogger(ctx)
+	logger.Info("Re-adopting agent process from previous worker", "Agent", prev.Agent, "PID", prev.PID, "StartedAt", prev.StartedAt)
+	if p, err := os.FindProcess(prev.PID); err == nil {
+		runningAgents.add(p, prev.Agent)
+		defer runningAgents.remove(p)
+	}
+
+	lastBeat := time.Now()
+	for processMatches(prev.PID, prev.Command) {
+		time.Sleep(adoptPollInterval)
+		if time.Since(lastBeat) >= 5*time.Second {
+			activity.RecordHeartbeat(ctx)
+			lastBeat = time.Now()
+		}
+	}
+
+	stdout, _ := os.ReadFile(prev.StdoutPath)
+	stderr, _ := os.ReadFile(prev.StderrPath)
+	c.cp.discard(*prev)
+	// The exit status of a process we did not start is unknowable; its
+	// output goes through the same review and DoD gates as any other run.
+	logger.Info("Re-adopted agent process finished", "Agent", prev.Agent, "PID", prev.PID)
+	result, err := finishCLI(c.run.Agent, string(stdout), string(stderr), nil)
+	return result, true, err
+}
+
+// redirect points cmd's output at checkpoint files owned by the child
+// process, so output keeps flowing if this worker dies.
+func (c *agentCall) redirect(cmd *exec.Cmd) error {
+	var err error
+	if c.stdout, err = os.Create(c.run.StdoutPath); err != nil {
+		return fmt.Errorf("create agent stdout: %w", err)
+	}
+	if c.stderr, err = os.Create(c.run.StderrPath); err != nil {
+		c.stdout.Close()
+		os.Remove(c.run.StdoutPath)
+		return fmt.Errorf("create agent stderr: %w", err)
+	}
+	cmd.Stdout = c.stdout
+	cmd.Stderr = c.stderr
+	return nil
+}
+
+// started records the running process. The child holds its own copies of
+// the output files, so ours are closed right away.
+func (c *agentCall) started(pid int) {
+	if c == nil {
+		return
+	}
+	c.closeFiles()
+	c.run.PID = pid
+	id, err := c.cp.store.RecordAgentRun(c.run)
+	if err == nil {
+		c.run.ID = id
+	}
+}
+
+func (c *agentCall) outputs() (string, string) {
+	stdout, _ := os.ReadFile(c.run.StdoutPath)
+	stderr, _ := os.ReadFile(c.run.StderrPath)
+	return string(stdout), string(stderr)
+}
+
+// release drops the checkpoint once the run's output has been collected.
+func (c *agentCall) release() {
+	if c == nil {
+		return
+	}
+	c.closeFiles()
+	c.cp.discard(c.run)
+}
+
+func (c *agentCall) closeFiles() {
+	if c.stdout != nil {
+		c.stdout.Close()
+		c.stdout = nil
+	}
+	if c.stderr != nil {
+		c.stderr.Close()
+		c.stderr = nil
+	}
+}
+
+func (c *agentCheckpoints) discard(run store.AgentRun) {
+	if run.ID != 0 {
+		c.store.DeleteAgentRun(run.ID)
+	}
+	os.Remove(run.StdoutPath)
+	os.Remove(run.StderrPath)
+}
+
+// ReconcileResult summarizes agent checkpoints found at worker startup.
+type ReconcileResult struct {
+	Adoptable int // processes still running, waiting for their activity to retry
+	Stale     int // processes that died with the previous worker
+}
+
+// reconcile clears checkpoints whose process is gone. Live ones are left for
+// their activity's retry to re-adopt.
+func (c *agentCheckpoints) reconcile() (ReconcileResult, error) {
+	var res ReconcileResult
+	runs, err := c.store.ListAgentRuns()
+	if err != nil {
+		return res, err
+	}
+	for _, run := range runs {
+		if processMatches(run.PID, run.Command) {
+			res.Adoptable++
+			continue
+		}
+		c.discard(run)
+		res.Stale++
+	}
+	return res, nil
+}
+
+// processMatches reports whether pid is alive and, where /proc is available,
+// still running command, so a recycled PID is never adopted.
+func processMatches(pid int, command string) bool {
+	if pid <= 0 || !dispatch.IsProcessAlive(pid) {
+		return false
+	}
+	cmdline, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	if err != nil {
+		return true
+	}
+	args := strings.Split(strings.TrimRight(string(cmdline), "\x00"), "\x00")
+	// Script CLIs show up as "node /path/to/claude ...", so look past argv[0].
+	for i := 0; i < len(args) && i < 3; i++ {
+		if filepath.Base(args[i]) == command {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+package temporal
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func testCheckpoints(t *testing.T) *agentCheckpoints {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+	cp, err := newAgentCheckpoints(st, filepath.Join(t.TempDir(), "agent-runs"))
+	require.NoError(t, err)
+	return cp
+}
+
+func TestRunCLICheckpointsOutputAndCleansUp(t *testing.T) {
+	cp := testCheckpoints(t)
+	checkpoints = cp
+	t.Cleanup(func() { checkpoints = nil })
+
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivityWithOptions(func(ctx context.Context) (string, error) {
+		res, err := runCLI(ctx, nil, "sh", exec.Command("sh", "-c", "echo out; echo oops >&2; exit 3"))
+		return res.Output, err
+	}, activity.RegisterOptions{Name: "run"})
+
+	val, err := env.ExecuteActivity("run")
+	require.Error(t, err, "non-zero exit still surfaces as an error")
+	require.Contains(t, err.Error(), "exited with error")
+	require.Nil(t, val)
+
+	runs, err := cp.store.ListAgentRuns()
+	require.NoError(t, err)
+	require.Empty(t, runs, "checkpoint released after the run")
+	files, err := os.ReadDir(cp.dir)
+	require.NoError(t, err)
+	require.Empty(t, files, "output files removed after the run")
+}
+
+func TestAdoptWaitsForLiveProcessAndCollectsOutput(t *testing.T) {
+	cp := testCheckpoints(t)
+	script := "sleep 1; echo adopted"
+
+	// Simulate the previous worker: a checkpointed run still in flight.
+	prev := cp.newAgentCall("wf-1", "cortex-1", 1, "sh", exec.Command("sh", "-c", script))
+	orphan := exec.Command("sh", "-c", script)
+	require.NoError(t, prev.redirect(orphan))
+	require.NoError(t, orphan.Start())
+	go orphan.Wait()
+	prev.started(orphan.Process.Pid)
+
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivityWithOptions(func(ctx context.Context) (string, error) {
+		call := cp.newAgentCall("wf-1", "cortex-1", 1, "sh", exec.Command("sh", "-c", script))
+		res, adopted, err := call.adopt(ctx)
+		require.True(t, adopted)
+		return res.Output, err
+	}, activity.RegisterOptions{Name: "adopt"})
+
+	val, err := env.ExecuteActivity("adopt")
+	require.NoError(t, err)
+	var out string
+	require.NoError(t, val.Get(&out))
+	require.Equal(t, "adopted", out)
+
+	runs, err := cp.store.ListAgentRuns()
+	require.NoError(t, err)
+	require.Empty(t, runs)
+}
+
+func TestRunCLIReadoptsAfterWorkerRestart(t *testing.T) {
+	cp := testCheckpoints(t)
+	checkpoints = cp
+	t.Cleanup(func() { checkpoints = nil })
+
+	starts := filepath.Join(t.TempDir(), "starts")
+	script := "echo x >> " + starts + "; sleep 1; echo adopted"
+	s := testsuite.WorkflowTestSuite{}
+
+	// First worker: starts the agent for attempt 1, then dies before
+	// collecting its output. The process keeps running.
+	first := s.NewTestActivityEnvironment()
+	first.RegisterActivityWithOptions(func(ctx context.Context) (int, error) {
+		cmd := exec.Command("sh", "-c", script)
+		call := checkpoints.call(withAgentRun(ctx, "cortex-7", 1), "sh", cmd)
+		require.NoError(t, call.redirect(cmd))
+		require.NoError(t, cmd.Start())
+		go cmd.Wait()
+		call.started(cmd.Process.Pid)
+		return cmd.Process.Pid, nil
+	}, activity.RegisterOptions{Name: "crash"})
+	_, err := first.ExecuteActivity("crash")
+	require.NoError(t, err)
+
+	// Restarted worker: reconcile keeps the live run, and the retried
+	// activity re-adopts it instead of starting a second agent.
+	res, err := cp.reconcile()
+	require.NoError(t, err)
+	require.Equal(t, ReconcileResult{Adoptable: 1}, res)
+
+	second := s.NewTestActivityEnvironment()
+	second.RegisterActivityWithOptions(func(ctx context.Context) (string, error) {
+		res, err := runCLI(withAgentRun(ctx, "cortex-7", 1), nil, "sh", exec.Command("sh", "-c", script))
+		return res.Output, err
+	}, activity.RegisterOptions{Name: "retry"})
+	val, err := second.ExecuteActivity("retry")
+	require.NoError(t, err)
+	var out string
+	require.NoError(t, val.Get(&out))
+	require.Equal(t, "adopted", out)
+
+	data, err := os.ReadFile(starts)
+	require.NoError(t, err)
+	require.Equal(t, "x\n", string(data), "agent started exactly once")
+
+	runs, err := cp.store.ListAgentRuns()
+	require.NoError(t, err)
+	require.Empty(t, runs)
+}
+
+func TestReconcileClearsDeadCheckpoints(t *testing.T) {
+	cp := testCheckpoints(t)
+
+	dead := exec.Command("sh", "-c", "exit 0")
+	require.NoError(t, dead.Run())
+	call := cp.newAgentCall("wf-2", "cortex-2", 1, "sh", dead)
+	require.NoError(t, os.WriteFile(call.run.StdoutPath, []byte("partial"), 0o644))
+	call.started(dead.Process.Pid)
+
+	res, err := cp.reconcile()
+	require.NoError(t, err)
+	require.Equal(t, ReconcileResult{Stale: 1}, res)
+
+	runs, err := cp.store.ListAgentRuns()
+	require.NoError(t, err)
+	require.Empty(t, runs)
+	_, err = os.Stat(call.run.StdoutPath)
+	require.True(t, os.IsNotExist(err), "stale output removed")
+
+	// With nothing alive to adopt, the retry starts a fresh run.
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivityWithOptions(func(ctx context.Context) (bool, error) {
+		_, adopted, err := cp.newAgentCall("wf-2", "cortex-2", 1, "sh", dead).adopt(ctx)
+		return adopted, err
+	}, activity.RegisterOptions{Name: "adopt"})
+	val, err := env.ExecuteActivity("adopt")
+	require.NoError(t, err)
+	var adopted bool
+	require.NoError(t, val.Get(&adopted))
+	require.False(t, adopted)
+}
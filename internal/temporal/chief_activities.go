@@ -0,0 +1,162 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/beads"
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/learner"
+)
+
+// ChiefPromptActivity briefs the chief on a project's backlog: the open
+// epics with how many open children each has, and the open tasks in the
+// strategic groom's summary form.
+func (a *Activities) ChiefPromptActivity(ctx context.Context, req ChiefRequest) (string, error) {
+	all, err := beads.ListBeadsCtx(ctx, req.BeadsDir)
+	if err != nil {
+		return "", fmt.Errorf("listing beads: %w", err)
+	}
+	summary, err := a.GetBeadStateSummaryActivity(ctx, StrategicGroomRequest{Project: req.Project, WorkDir: req.WorkDir, BeadsDir: req.BeadsDir})
+	if err != nil {
+		return "", err
+	}
+
+	children := make(map[string]int)
+	for _, b := range all {
+		if b.ParentID != "" && b.Status != "closed" {
+			children[b.ParentID]++
+		}
+	}
+	var epics []beads.Bead
+	for _, b := range all {
+		if b.Type == "epic" && b.Status != "closed" {
+			epics = append(epics, b)
+		}
+	}
+	sort.Slice(epics, func(i, j int) bool {
+		if epics[i].Priority != epics[j].Priority {
+			return epics[i].Priority < epics[j].Priority
+		}
+		return epics[i].ID < epics[j].ID
+	})
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "You are the chief for project %s. Run the scheduled backlog review with the bd CLI; do not change code.\n\n", req.Project)
+	sb.WriteString("1. Groom the backlog: close duplicate and obsolete beads, and correct priorities and dependencies.\n")
+	sb.WriteString("2. Split each epic with no open children into task beads small enough for one dispatch, parented to the epic.\n")
+	if req.SprintCapacity > 0 {
+		fmt.Fprintf(&sb, "3. Propose the next sprint: at most %d unblocked beads, in the order they should run, with one line of rationale each.\n", req.SprintCapacity)
+	} else {
+		sb.WriteString("3. Propose the next sprint: the unblocked beads that should run next, in order, with one line of rationale each.\n")
+	}
+	sb.WriteString("\nReport the sprint proposal as your plan summary so it can be approved.\n\n## Epics\n")
+	if len(epics) == 0 {
+		sb.WriteString("(none open)\n")
+	}
+	for _, e := range epics {
+		fmt.Fprintf(&sb, "[P%d] %s: %s (%d open children)\n", e.Priority, e.ID, e.Title, children[e.ID])
+	}
+	sb.WriteString("\n## Backlog\n")
+	sb.WriteString(summary)
+	return sb.String(), nil
+}
+
+// NextWorkingDayActivity moves a run scheduled at t that falls on a cadence
+// holiday to the next working day, at the same clock time in the cadence's
+// timezone. A t on a working day comes back unchanged.
+func (a *Activities) NextWorkingDayActivity(ctx context.Context, t time.Time) (time.Time, error) {
+	loc, err := a.Cadence.LoadLocation()
+	if err != nil {
+		return t, err
+	}
+	return a.Holidays.NextWorkingDay(t.In(loc)), nil
+}
+
+// sprintReviewVelocitySprints is how many recent sprints of velocity the
+// chief's briefing shows.
+const sprintReviewVelocitySprints = 4
+
+// SprintReviewActivity reviews the sprint for the chief before it proposes
+// the next one. It records velocity for the running sprint and the one
+// before it, first recording the running sprint's boundary from the cadence
+// when none covers now, and returns the project's recent velocity as a
+// briefing section.
+func (a *Activities) SprintReviewActivity(ctx context.Context, req ChiefRequest) (string, error) {
+	if a.Store == nil {
+		return "", nil
+	}
+	sprint, err := a.currentSprint(time.Now())
+	if err != nil {
+		return "", err
+	}
+	estimate := a.beadEstimate(ctx)
+	for n := sprint; n > 0 && n >= sprint-1; n-- {
+		if _, err := learner.RecordSprintVelocity(a.Store, n, estimate); err != nil {
+			return "", fmt.Errorf("recording sprint %d velocity: %w", n, err)
+		}
+	}
+	history, err := a.Store.ListSprintVelocity(req.Project, sprintReviewVelocitySprints)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "\n## Velocity (last %d sprints, newest first)\n", sprintReviewVelocitySprints)
+	sb.WriteString("Review what the last sprint delivered against these numbers before proposing the next one, and size the proposal to the velocity.\n\n")
+	sb.WriteString(learner.FormatVelocity(history))
+	return sb.String(), nil
+}
+
+// currentSprint returns the number of the sprint running at now. When no
+// recorded boundary covers now it records one: the sprint after the latest
+// recorded one, or sprint 1 starting on the cadence's last start day.
+func (a *Activities) currentSprint(now time.Time) (int, error) {
+	if n, err := a.Store.GetCurrentSprintNumber(); err != nil || n > 0 {
+		return n, err
+	}
+	length, err := a.Cadence.SprintLengthDuration()
+	if err != nil {
+		return 0, err
+	}
+	last, err := a.Store.GetLatestSprintBoundary()
+	if err != nil {
+		return 0, err
+	}
+	number, start := 1, time.Time{}
+	switch {
+	case last == nil:
+		if start, err = a.Cadence.SprintStart(now); err != nil {
+			return 0, err
+		}
+	case last.SprintEnd.After(now):
+		return 0, nil // the latest sprint has not started yet
+	default:
+		number, start = last.SprintNumber+1, last.SprintEnd
+		for !start.Add(length).After(now) {
+			number, start = number+1, start.Add(length)
+		}
+	}
+	if err := a.Store.RecordSprintBoundary(number, start, start.Add(length)); err != nil {
+		return 0, err
+	}
+	return number, nil
+}
+
+// beadEstimate reads bead estimates from each project's beads directory.
+// Beads that cannot be read count as unestimated.
+func (a *Activities) beadEstimate(ctx context.Context) learner.EstimateLookup {
+	return func(project, beadID string) int {
+		p, ok := a.Projects[project]
+		if !ok || p.BeadsDir == "" {
+			return 0
+		}
+		detail, err := beads.ShowBeadCtx(ctx, config.ExpandHome(p.BeadsDir), beadID)
+		if err != nil {
+			return 0
+		}
+		return detail.EstimateMinutes
+	}
+}
@@ -0,0 +1,100 @@
+package temporal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestChiefPromptActivity(t *testing.T) {
+	workDir := t.TempDir()
+	fakeBin := t.TempDir()
+	list := `[
+		{"id":"e1","title":"Billing","issue_type":"epic","status":"open","priority":1},
+		{"id":"e2","title":"Search","issue_type":"epic","status":"open","priority":2},
+		{"id":"t1","title":"Invoice model","issue_type":"task","status":"open","priority":1,"parent_id":"e2"},
+		{"id":"t2","title":"Old task","issue_type":"task","status":"closed","priority":3,"parent_id":"e1"}
+	]`
+	script := "#!/bin/sh\ncat <<'JSON'\n" + list + "\nJSON\n"
+	require.NoError(t, os.WriteFile(filepath.Join(fakeBin, "bd"), []byte(script), 0o755))
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	acts := &Activities{}
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(acts.ChiefPromptActivity)
+	val, err := env.ExecuteActivity(acts.ChiefPromptActivity, ChiefRequest{
+		Project:        "cortex",
+		WorkDir:        workDir,
+		BeadsDir:       filepath.Join(workDir, ".beads"),
+		SprintCapacity: 5,
+	})
+	require.NoError(t, err)
+	var prompt string
+	require.NoError(t, val.Get(&prompt))
+
+	require.Contains(t, prompt, "chief for project cortex")
+	require.Contains(t, prompt, "at most 5 unblocked beads")
+	require.Contains(t, prompt, "[P1] e1: Billing (0 open children)")
+	require.Contains(t, prompt, "[P2] e2: Search (1 open children)")
+	require.Contains(t, prompt, "[P1] t1: Invoice model")
+	require.NotContains(t, prompt, "Old task")
+}
+
+func TestSprintReviewActivityRecordsVelocity(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+	cadence := config.Cadence{SprintLength: "1w", SprintStartDay: "Monday", SprintStartTime: "00:00", Timezone: "UTC"}
+	acts := &Activities{Store: st, Cadence: cadence}
+
+	id, err := st.RecordDispatch("cx-1", "cortex", "agent", "claude", "balanced", 1, "", "p", "", "", "")
+	require.NoError(t, err)
+	require.NoError(t, st.UpdateDispatchStatus(id, "completed", 0, 60))
+
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(acts.SprintReviewActivity)
+	val, err := env.ExecuteActivity(acts.SprintReviewActivity, ChiefRequest{Project: "cortex"})
+	require.NoError(t, err)
+	var review string
+	require.NoError(t, val.Get(&review))
+
+	sprint, err := st.GetCurrentSprintNumber()
+	require.NoError(t, err)
+	require.Equal(t, 1, sprint, "running sprint not recorded from the cadence")
+	require.Contains(t, review, "## Velocity")
+	require.Contains(t, review, "| 1 | cortex | 1 |")
+}
+
+func TestSprintReviewActivityContinuesSprintNumbering(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+	cadence := config.Cadence{SprintLength: "1w", SprintStartDay: "Monday", SprintStartTime: "00:00", Timezone: "UTC"}
+	acts := &Activities{Store: st, Cadence: cadence}
+
+	week := 7 * 24 * time.Hour
+	start, err := cadence.SprintStart(time.Now())
+	require.NoError(t, err)
+	require.NoError(t, st.RecordSprintBoundary(1, start.Add(-4*week), start.Add(-3*week)))
+
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(acts.SprintReviewActivity)
+	_, err = env.ExecuteActivity(acts.SprintReviewActivity, ChiefRequest{Project: "cortex"})
+	require.NoError(t, err)
+
+	sb, err := st.GetCurrentSprintBoundary()
+	require.NoError(t, err)
+	require.NotNil(t, sb)
+	require.Equal(t, 5, sb.SprintNumber, "sprints missed while idle not counted")
+	require.True(t, sb.SprintStart.Equal(start), "sprint starts at %v, want %v", sb.SprintStart, start)
+}
@@ -0,0 +1,93 @@
+package temporal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/antigravity-dev/cortex/internal/lease"
+)
+
+// beadClaimedErrorType is the application error type ClaimBeadActivity
+// returns for a bead another instance holds.
+const beadClaimedErrorType = "BeadClaimed"
+
+// ClaimBeadActivity claims req's bead for this instance before a workflow
+// starts a CortexAgentWorkflow on it as a child, as the API does for the
+// workflows it starts, and returns req carrying the claim for the child to
+// renew and release. A bead another instance holds fails without retrying.
+func (a *Activities) ClaimBeadActivity(ctx context.Context, req TaskRequest) (TaskRequest, error) {
+	if a.Claims == nil {
+		return req, nil
+	}
+	if err := a.Claims.Claim(ctx, req.BeadID); err != nil {
+		if errors.Is(err, lease.ErrHeld) {
+			return req, temporal.NewNonRetryableApplicationError(
+				fmt.Sprintf("bead %s is claimed by another cortex instance", req.BeadID), beadClaimedErrorType, err)
+		}
+		return req, fmt.Errorf("claiming %s: %w", req.BeadID, err)
+	}
+	req.ClaimHolder, req.ClaimTTL = a.Claims.Holder, a.Claims.TTL
+	return req, nil
+}
+
+// claimChild claims task's bead through ClaimBeadActivity before a workflow
+// starts it as a child. It returns the task carrying the claim, or an error
+// when the bead may not be dispatched.
+func claimChild(ctx workflow.Context, task TaskRequest) (TaskRequest, error) {
+	var a *Activities
+	claimCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Second,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 3},
+	})
+	var claimed TaskRequest
+	if err := workflow.ExecuteActivity(claimCtx, a.ClaimBeadActivity, task).Get(ctx, &claimed); err != nil {
+		return task, err
+	}
+	return claimed, nil
+}
+
+// releaseChildClaim gives up the claim claimChild took when the child
+// workflow could not be started; a started child releases it when it ends.
+func releaseChildClaim(ctx workflow.Context, task TaskRequest) {
+	var a *Activities
+	releaseCtx, _ := workflow.NewDisconnectedContext(ctx)
+	releaseCtx = workflow.WithActivityOptions(releaseCtx, workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Second,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 3},
+	})
+	if err := workflow.ExecuteActivity(releaseCtx, a.ReleaseClaimActivity, task).Get(releaseCtx, nil); err != nil {
+		workflow.GetLogger(ctx).Warn("Claim release failed; it expires on its own", "BeadID", task.BeadID, "error", err)
+	}
+}
+
+// RenewClaimActivity renews the claim the API took on the task's bead, so
+// that it outlives the claim TTL while the workflow runs. A claim another
+// instance has taken over fails the renewal; the workflow only logs it, as
+// the dispatch is already under way.
+func (a *Activities) RenewClaimActivity(ctx context.Context, req TaskRequest) error {
+	if a.Claims == nil || req.ClaimHolder == "" {
+		return nil
+	}
+	if err := a.Claims.Backend.Acquire(ctx, req.BeadID, req.ClaimHolder, req.ClaimTTL); err != nil {
+		return fmt.Errorf("renewing claim on %s: %w", req.BeadID, err)
+	}
+	return nil
+}
+
+// ReleaseClaimActivity gives up the claim on the task's bead once the
+// workflow ends, however it ends.
+func (a *Activities) ReleaseClaimActivity(ctx context.Context, req TaskRequest) error {
+	if a.Claims == nil || req.ClaimHolder == "" {
+		return nil
+	}
+	if err := a.Claims.Backend.Release(ctx, req.BeadID, req.ClaimHolder); err != nil {
+		activity.GetLogger(ctx).Warn("Claim release failed; it expires on its own", "BeadID", req.BeadID, "error", err)
+	}
+	return nil
+}
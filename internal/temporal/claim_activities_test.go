@@ -0,0 +1,36 @@
+package temporal
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/lease"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestClaimBeadActivity(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer st.Close()
+
+	backend := lease.NewStoreBackend(st)
+	acts := &Activities{Claims: &lease.Claims{Backend: backend, Holder: "host-a", TTL: time.Minute}}
+	env := (&testsuite.WorkflowTestSuite{}).NewTestActivityEnvironment()
+	env.RegisterActivity(acts.ClaimBeadActivity)
+
+	require.NoError(t, backend.Acquire(context.Background(), "cx-1", "host-b", time.Minute))
+	_, err = env.ExecuteActivity(acts.ClaimBeadActivity, TaskRequest{BeadID: "cx-1"})
+	require.ErrorContains(t, err, "claimed by another cortex instance")
+
+	val, err := env.ExecuteActivity(acts.ClaimBeadActivity, TaskRequest{BeadID: "cx-2"})
+	require.NoError(t, err)
+	var claimed TaskRequest
+	require.NoError(t, val.Get(&claimed))
+	require.Equal(t, "host-a", claimed.ClaimHolder)
+	require.Equal(t, time.Minute, claimed.ClaimTTL)
+}
@@ -0,0 +1,67 @@
+package temporal
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"go.temporal.io/sdk/client"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+// ClientOptions builds Temporal client options from the [temporal] config,
+// loading the mTLS certificates when configured.
+func ClientOptions(tc config.Temporal) (client.Options, error) {
+	opts := client.Options{
+		HostPort:  tc.Address(),
+		Namespace: tc.Namespace,
+	}
+	if !tc.TLS.Enabled() {
+		return opts, nil
+	}
+
+	tlsCfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: tc.TLS.ServerName,
+	}
+	if tlsCfg.ServerName == "" {
+		tlsCfg.ServerName = tc.Host
+	}
+	if tc.TLS.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(tc.TLS.CertFile, tc.TLS.KeyFile)
+		if err != nil {
+			return opts, fmt.Errorf("temporal: load client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	if tc.TLS.CAFile != "" {
+		pem, err := os.ReadFile(tc.TLS.CAFile)
+		if err != nil {
+			return opts, fmt.Errorf("temporal: read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return opts, fmt.Errorf("temporal: no certificates in CA file %s", tc.TLS.CAFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+	opts.ConnectionOptions.TLS = tlsCfg
+	return opts, nil
+}
+
+// Dial connects to the Temporal server described by tc.
+func Dial(tc config.Temporal) (client.Client, error) {
+	return DialContext(context.Background(), tc)
+}
+
+// DialContext is the context-aware version of Dial.
+func DialContext(ctx context.Context, tc config.Temporal) (client.Client, error) {
+	opts, err := ClientOptions(tc)
+	if err != nil {
+		return nil, err
+	}
+	return client.DialContext(ctx, opts)
+}
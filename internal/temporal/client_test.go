@@ -0,0 +1,64 @@
+package temporal
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+func writeTestCert(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "cortex"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile, keyFile = filepath.Join(dir, "client.pem"), filepath.Join(dir, "client.key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+func TestClientOptions(t *testing.T) {
+	tc := config.Temporal{Host: "temporal.internal", Port: 7300, Namespace: "cortex"}
+	opts, err := ClientOptions(tc)
+	require.NoError(t, err)
+	require.Equal(t, "temporal.internal:7300", opts.HostPort)
+	require.Equal(t, "cortex", opts.Namespace)
+	require.Nil(t, opts.ConnectionOptions.TLS)
+
+	certFile, keyFile := writeTestCert(t, t.TempDir())
+	tc.TLS = config.TemporalTLS{CertFile: certFile, KeyFile: keyFile, CAFile: certFile}
+	opts, err = ClientOptions(tc)
+	require.NoError(t, err)
+	require.NotNil(t, opts.ConnectionOptions.TLS)
+	require.Len(t, opts.ConnectionOptions.TLS.Certificates, 1)
+	require.NotNil(t, opts.ConnectionOptions.TLS.RootCAs)
+	require.Equal(t, "temporal.internal", opts.ConnectionOptions.TLS.ServerName)
+
+	tc.TLS.CAFile = keyFile
+	_, err = ClientOptions(tc)
+	require.ErrorContains(t, err, "no certificates in CA file")
+}
@@ -0,0 +1,101 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.temporal.io/sdk/activity"
+
+	"github.com/antigravity-dev/cortex/internal/beads"
+	"github.com/antigravity-dev/cortex/internal/git"
+)
+
+var confidencePattern = regexp.MustCompile(`(?im)^[\s*_#>-]*confidence[\s*_]*[:=][\s*_]*([0-9]+(?:\.[0-9]+)?)\s*(%|/\s*100|/\s*10)?`)
+
+// parseConfidence extracts the last CONFIDENCE footer from agent output. It
+// is the fallback for output without a completion block.
+// Scores may be written as 0.8, 80%, 8/10 or 80/100; a bare number above 1
+// is read as a percentage.
+func parseConfidence(output string) Confidence {
+	matches := confidencePattern.FindAllStringSubmatch(output, -1)
+	if len(matches) == 0 {
+		return Confidence{}
+	}
+	m := matches[len(matches)-1]
+	score, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return Confidence{}
+	}
+	switch unit := strings.ReplaceAll(m[2], " ", ""); {
+	case unit == "/10":
+		score /= 10
+	case unit == "%" || unit == "/100" || score > 1:
+		score /= 100
+	}
+	if score < 0 || score > 1 {
+		return Confidence{}
+	}
+	return Confidence{Score: score, Reported: true}
+}
+
+// completionLookbackDays is how far back verify_completion looks for commits
+// and merged PRs that reference the bead.
+const completionLookbackDays = 14
+
+// CompleteTaskActivity closes out a task that passed review and DoD. With
+// auto-close enabled, the bead is closed only when the agent reported a
+// confidence at or above the threshold and, with verify_completion, a recent
+// commit or merged PR references the bead; otherwise it stays open and the
+// project room is asked for a human review.
+func (a *Activities) CompleteTaskActivity(ctx context.Context, req CompletionRequest) (*CompletionResult, error) {
+	logger := activity.GetLogger(ctx)
+	gate := a.Confidence
+	if !gate.AutoClose {
+		return &CompletionResult{}, nil
+	}
+
+	if !req.Confidence.Reported || req.Confidence.Score < gate.Threshold {
+		return a.routeToHumanReview(ctx, req, lowConfidenceReason(req.Confidence, gate.Threshold)), nil
+	}
+	if gate.VerifyCompletion {
+		completion, err := git.VerifyCompletion(req.WorkDir, req.BeadID, completionLookbackDays)
+		if err != nil {
+			logger.Warn("Completion verification failed", "BeadID", req.BeadID, "error", err)
+			return a.routeToHumanReview(ctx, req, "its completion could not be verified"), nil
+		}
+		if !completion.Completed() {
+			return a.routeToHumanReview(ctx, req, "no recent commit or merged PR references it"), nil
+		}
+	}
+
+	reason := fmt.Sprintf("Completed by %s (confidence %.2f)", req.Agent, req.Confidence.Score)
+	if err := beads.CloseBeadWithReasonCtx(ctx, resolveBeadsDir(req.WorkDir), req.BeadID, reason); err != nil {
+		return nil, fmt.Errorf("auto-close %s: %w", req.BeadID, err)
+	}
+	return &CompletionResult{AutoClosed: true, Reason: reason}, nil
+}
+
+// routeToHumanReview leaves the bead open and asks the project room to
+// review it.
+func (a *Activities) routeToHumanReview(ctx context.Context, req CompletionRequest, reason string) *CompletionResult {
+	logger := activity.GetLogger(ctx)
+	logger.Info("Completion routed to human review", "BeadID", req.BeadID, "Reason", reason)
+	if room := a.Projects[req.Project].MatrixRoom; a.Sender != nil && room != "" {
+		msg := fmt.Sprintf("Human review needed: %s (%s) passed review and DoD but was not auto-closed — %s.",
+			req.BeadID, req.Project, reason)
+		if err := a.Sender.SendMessage(ctx, room, msg); err != nil {
+			logger.Warn("Human review notification failed", "Room", room, "error", err)
+		}
+	}
+	return &CompletionResult{HumanReview: true, Reason: reason}
+}
+
+func lowConfidenceReason(c Confidence, threshold float64) string {
+	if !c.Reported {
+		return "agent reported no confidence score"
+	}
+	return fmt.Sprintf("confidence %.2f is below %.2f", c.Score, threshold)
+}
@@ -0,0 +1,144 @@
+package temporal
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+func TestParseConfidence(t *testing.T) {
+	cases := map[string]Confidence{
+		"did the thing\nCONFIDENCE: 0.85":           {Score: 0.85, Reported: true},
+		"**Confidence:** 80%":                       {Score: 0.8, Reported: true},
+		"confidence = 7/10":                         {Score: 0.7, Reported: true},
+		"Confidence: 90/100\n":                      {Score: 0.9, Reported: true},
+		"CONFIDENCE: 0.2\nfixed it\nCONFIDENCE: 1":  {Score: 1, Reported: true},
+		"no footer here":                            {},
+		"I have low confidence in the test harness": {},
+		"CONFIDENCE: 250":                           {},
+	}
+	for output, want := range cases {
+		require.Equal(t, want, parseConfidence(output), "output %q", output)
+	}
+}
+
+type recordingSender struct {
+	rooms    []string
+	messages []string
+}
+
+func (s *recordingSender) SendMessage(_ context.Context, room, message string) error {
+	s.rooms = append(s.rooms, room)
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+func TestCompleteTaskActivity(t *testing.T) {
+	workDir := t.TempDir()
+	logPath := filepath.Join(workDir, "bd.log")
+	fakeBin := t.TempDir()
+	script := "#!/bin/sh\necho \"$@\" >> \"$BD_ARGS_LOG\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(fakeBin, "bd"), []byte(script), 0o755))
+	t.Setenv("BD_ARGS_LOG", logPath)
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	sender := &recordingSender{}
+	acts := &Activities{
+		Projects:   map[string]config.Project{"cortex": {MatrixRoom: "!room"}},
+		Confidence: config.DispatchConfidence{AutoClose: true, Threshold: 0.7},
+		Sender:     sender,
+	}
+	run := func(acts *Activities, c Confidence) CompletionResult {
+		s := testsuite.WorkflowTestSuite{}
+		env := s.NewTestActivityEnvironment()
+		env.RegisterActivity(acts.CompleteTaskActivity)
+		val, err := env.ExecuteActivity(acts.CompleteTaskActivity, CompletionRequest{
+			BeadID: "cortex-1", Project: "cortex", WorkDir: workDir, Agent: "claude", Confidence: c,
+		})
+		require.NoError(t, err)
+		var res CompletionResult
+		require.NoError(t, val.Get(&res))
+		return res
+	}
+
+	low := run(acts, Confidence{Score: 0.5, Reported: true})
+	require.True(t, low.HumanReview)
+	require.False(t, low.AutoClosed)
+	require.Equal(t, []string{"!room"}, sender.rooms)
+	require.Contains(t, sender.messages[0], "confidence 0.50 is below 0.70")
+
+	missing := run(acts, Confidence{})
+	require.True(t, missing.HumanReview)
+	require.Contains(t, missing.Reason, "no confidence")
+
+	_, err := os.Stat(logPath)
+	require.True(t, os.IsNotExist(err), "gated completions must not close the bead")
+
+	high := run(acts, Confidence{Score: 0.9, Reported: true})
+	require.True(t, high.AutoClosed)
+	args, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(args), "close cortex-1"), "bd args: %s", args)
+
+	off := run(&Activities{Sender: sender}, Confidence{})
+	require.Equal(t, CompletionResult{}, off)
+}
+
+func TestCompleteTaskActivityVerifiesCompletion(t *testing.T) {
+	workDir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-b", "master"},
+		{"-c", "user.email=t@example.com", "-c", "user.name=T", "commit", "--allow-empty", "-m", "Unrelated change"},
+	} {
+		out, err := exec.Command("git", append([]string{"-C", workDir}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	logPath := filepath.Join(workDir, "bd.log")
+	fakeBin := t.TempDir()
+	scripts := map[string]string{
+		"bd": "#!/bin/sh\necho \"$@\" >> \"$BD_ARGS_LOG\"\n",
+		"gh": "#!/bin/sh\necho '[]'\n",
+	}
+	for name, script := range scripts {
+		require.NoError(t, os.WriteFile(filepath.Join(fakeBin, name), []byte(script), 0o755))
+	}
+	t.Setenv("BD_ARGS_LOG", logPath)
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	acts := &Activities{
+		Projects:   map[string]config.Project{"cortex": {}},
+		Confidence: config.DispatchConfidence{AutoClose: true, Threshold: 0.7, VerifyCompletion: true},
+	}
+	run := func() CompletionResult {
+		s := testsuite.WorkflowTestSuite{}
+		env := s.NewTestActivityEnvironment()
+		env.RegisterActivity(acts.CompleteTaskActivity)
+		val, err := env.ExecuteActivity(acts.CompleteTaskActivity, CompletionRequest{
+			BeadID: "cortex-a1", Project: "cortex", WorkDir: workDir, Agent: "claude", Confidence: Confidence{Score: 0.9, Reported: true},
+		})
+		require.NoError(t, err)
+		var res CompletionResult
+		require.NoError(t, val.Get(&res))
+		return res
+	}
+
+	unverified := run()
+	require.True(t, unverified.HumanReview)
+	require.Contains(t, unverified.Reason, "no recent commit or merged PR")
+	_, err := os.Stat(logPath)
+	require.True(t, os.IsNotExist(err), "unverified completions must not close the bead")
+
+	out, err := exec.Command("git", "-C", workDir, "-c", "user.email=t@example.com", "-c", "user.name=T",
+		"commit", "--allow-empty", "-m", "Add widget endpoint", "-m", "Fixes cortex-a1").CombinedOutput()
+	require.NoError(t, err, string(out))
+	verified := run()
+	require.True(t, verified.AutoClosed)
+}
@@ -0,0 +1,145 @@
+package temporal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+
+	"github.com/antigravity-dev/cortex/internal/cost"
+)
+
+const (
+	// CostCapExceeded is the failure category of a dispatch whose agent was
+	// stopped for crossing a cost cap.
+	CostCapExceeded = "cost_cap_exceeded"
+
+	// costCapErrorType is the application error type the activities return
+	// for a stopped run, so the workflow can tell it from other failures.
+	costCapErrorType = "CostCapExceeded"
+)
+
+// costCap is the spend limit of one agent run, priced at its provider's
+// rates. Usage is estimated from the output the agent has printed so far.
+type costCap struct {
+	limitUSD      float64
+	inputPerMtok  float64
+	outputPerMtok float64
+	prompt        string
+	parse         cost.Parser
+}
+
+type costCapKey struct{}
+
+// withCostCap attaches c to ctx for runCLI to enforce; nil leaves ctx alone.
+func withCostCap(ctx context.Context, c *costCap) context.Context {
+	if c == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, costCapKey{}, c)
+}
+
+func costCapFrom(ctx context.Context) *costCap {
+	c, _ := ctx.Value(costCapKey{}).(*costCap)
+	return c
+}
+
+// spent estimates what the run has cost given its output so far, preferring
+// the usage its CLI reported.
+func (c *costCap) spent(output string) float64 {
+	usage, _ := cost.ExtractUsage(c.parse, output, c.prompt)
+	if usage.CostUSD > 0 {
+		return usage.CostUSD
+	}
+	return cost.CalculateCost(usage, c.inputPerMtok, c.outputPerMtok)
+}
+
+func (c *costCap) exceeded(agent string, spent float64) error {
+	return temporal.NewNonRetryableApplicationError(
+		fmt.Sprintf("%s stopped: estimated spend $%.2f crossed the $%.2f cost cap", agent, spent, c.limitUSD),
+		costCapErrorType, nil)
+}
+
+// isCostCapExceeded reports whether err, as returned by an activity, means
+// an agent run was stopped by a cost cap.
+func isCostCapExceeded(err error) bool {
+	var appErr *temporal.ApplicationError
+	return errors.As(err, &appErr) && appErr.Type() == costCapErrorType
+}
+
+// costCapFor returns the cap for a run of agent in role, or nil when no cap
+// applies. The dispatch cap is reduced by what the dispatch already spent;
+// the role cap bounds the run on its own. Runs on providers without prices
+// cannot be estimated and are not capped.
+func (a *Activities) costCapFor(role, agent string, req TaskRequest, prompt string) *costCap {
+	cc := a.CostControl
+	var limit float64
+	capped := false
+	if cc.PerDispatchCostCapUSD > 0 {
+		// A dispatch already over its cap is stopped at the first check.
+		limit, capped = cc.PerDispatchCostCapUSD-req.SpentUSD, true
+	}
+	if roleCap := cc.RoleCostCapsUSD[role]; roleCap > 0 && (!capped || roleCap < limit) {
+		limit, capped = roleCap, true
+	}
+	if !capped {
+		return nil
+	}
+	p, ok := a.usageProvider(agent, req.Provider)
+	if !ok || p.CostInputPerMtok == 0 && p.CostOutputPerMtok == 0 {
+		return nil
+	}
+	return &costCap{limitUSD: limit, inputPerMtok: p.CostInputPerMtok, outputPerMtok: p.CostOutputPerMtok, prompt: prompt,
+		parse: cost.ParserFor(p.UsageFormat, agent)}
+}
+
+// syncBuffer is a bytes.Buffer that can be read while a process writes it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// PremiumBudget reports whether a premium-tier dispatch would run while
+// today's spend is at or over the daily cost cap, and so needs a human to
+// override the budget.
+type PremiumBudget struct {
+	OverBudget bool    `json:"over_budget"`
+	SpentUSD   float64 `json:"spent_usd"`
+	CapUSD     float64 `json:"cap_usd"`
+}
+
+// PremiumBudgetActivity checks req against
+// dispatch.cost_control.daily_cost_cap_usd. Dispatches on providers outside
+// the premium tier, and all dispatches without a daily cap, are never over
+// budget.
+func (a *Activities) PremiumBudgetActivity(ctx context.Context, req TaskRequest) (*PremiumBudget, error) {
+	budget := &PremiumBudget{CapUSD: a.CostControl.DailyCostCapUSD}
+	if budget.CapUSD <= 0 || a.Store == nil || !slices.Contains(a.Tiers.Premium, req.Provider) {
+		return budget, nil
+	}
+	y, m, d := time.Now().UTC().Date()
+	spent, err := a.Store.GetTotalCostSince("", time.Date(y, m, d, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		return nil, err
+	}
+	budget.SpentUSD = spent
+	budget.OverBudget = spent >= budget.CapUSD
+	return budget, nil
+}
@@ -0,0 +1,216 @@
+package temporal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/dispatch"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestCostCapFor(t *testing.T) {
+	acts := &Activities{
+		Providers: map[string]config.Provider{
+			"codex-main": {CLI: "codex", CostInputPerMtok: 3, CostOutputPerMtok: 15},
+			"free":       {CLI: "free"},
+		},
+		CostControl: config.DispatchCostControl{
+			PerDispatchCostCapUSD: 5,
+			RoleCostCapsUSD:       map[string]float64{"reviewer": 1},
+		},
+	}
+
+	c := acts.costCapFor("coder", "codex", TaskRequest{SpentUSD: 2}, "p")
+	require.NotNil(t, c)
+	require.InDelta(t, 3, c.limitUSD, 1e-9, "dispatch cap less what was spent")
+	require.Equal(t, 15.0, c.outputPerMtok)
+
+	c = acts.costCapFor("reviewer", "codex", TaskRequest{SpentUSD: 2}, "p")
+	require.InDelta(t, 1, c.limitUSD, 1e-9, "the lower role cap wins")
+
+	c = acts.costCapFor("coder", "codex", TaskRequest{SpentUSD: 6}, "p")
+	require.Negative(t, c.limitUSD, "an exhausted dispatch is stopped at the first check")
+
+	require.Nil(t, acts.costCapFor("coder", "free", TaskRequest{}, "p"), "unpriced providers are not capped")
+	require.Nil(t, (&Activities{Providers: acts.Providers}).costCapFor("coder", "codex", TaskRequest{}, "p"))
+}
+
+func TestExecuteActivityStoppedByCostCap(t *testing.T) {
+	prev := agentPollInterval
+	agentPollInterval = 20 * time.Millisecond
+	t.Cleanup(func() { agentPollInterval = prev })
+
+	fakeBin := t.TempDir()
+	script := "#!/bin/sh\necho 'Tokens: 400000 input, 200000 output'\nexec sleep 30\n"
+	require.NoError(t, os.WriteFile(filepath.Join(fakeBin, "codex"), []byte(script), 0o755))
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	acts := &Activities{
+		Providers: map[string]config.Provider{"codex": {CostInputPerMtok: 3, CostOutputPerMtok: 15}},
+		CostControl: config.DispatchCostControl{
+			RoleCostCapsUSD: map[string]float64{"coder": 1},
+		},
+	}
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(acts.ExecuteActivity)
+
+	start := time.Now()
+	_, err := env.ExecuteActivity(acts.ExecuteActivity, StructuredPlan{Summary: "s"}, TaskRequest{
+		BeadID: "b-1", Agent: "codex", WorkDir: t.TempDir(),
+	})
+	require.Error(t, err)
+	require.True(t, isCostCapExceeded(err), "unexpected error: %v", err)
+	require.Contains(t, err.Error(), "$4.20")
+	require.Less(t, time.Since(start), 10*time.Second, "agent was not killed")
+}
+
+func TestClaudeStreamStoppedByCostCap(t *testing.T) {
+	prev := agentPollInterval
+	agentPollInterval = 20 * time.Millisecond
+	t.Cleanup(func() { agentPollInterval = prev })
+
+	// claude prints its result only when it exits; the assistant events
+	// before it carry the usage the cap is checked against.
+	fakeBin := t.TempDir()
+	script := `#!/bin/sh
+case "$*" in *"--output-format stream-json"*) ;; *) echo "no stream-json" >&2; exit 2 ;; esac
+echo '{"type":"system","subtype":"init"}'
+echo '{"type":"assistant","message":{"id":"msg_1","content":[{"type":"text","text":"working"}],"usage":{"input_tokens":100000,"output_tokens":200000}}}'
+exec sleep 30
+`
+	require.NoError(t, os.WriteFile(filepath.Join(fakeBin, "claude"), []byte(script), 0o755))
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	acts := &Activities{
+		Providers: map[string]config.Provider{"claude": {CostInputPerMtok: 3, CostOutputPerMtok: 15}},
+		CostControl: config.DispatchCostControl{
+			RoleCostCapsUSD: map[string]float64{"coder": 1},
+		},
+	}
+	env := (&testsuite.WorkflowTestSuite{}).NewTestActivityEnvironment()
+	env.RegisterActivity(acts.ExecuteActivity)
+
+	start := time.Now()
+	_, err := env.ExecuteActivity(acts.ExecuteActivity, StructuredPlan{Summary: "s"}, TaskRequest{
+		BeadID: "b-1", Agent: "claude", WorkDir: t.TempDir(),
+	})
+	require.Error(t, err)
+	require.True(t, isCostCapExceeded(err), "unexpected error: %v", err)
+	require.Contains(t, err.Error(), "$3.30")
+	require.Less(t, time.Since(start), 10*time.Second, "agent was not killed")
+}
+
+func TestCostCapMarksDispatchPendingRetry(t *testing.T) {
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+	var a *Activities
+
+	env.OnActivity(a.AssignExperimentsActivity, mock.Anything, mock.Anything).Return([]ExperimentAssignment(nil), nil)
+	env.OnActivity(a.StructuredPlanActivity, mock.Anything, mock.Anything).Return(&StructuredPlan{
+		Summary:            "Add widget endpoint",
+		Steps:              []PlanStep{{Description: "Create handler", File: "handler.go", Rationale: "API needs it"}},
+		FilesToModify:      []string{"handler.go"},
+		AcceptanceCriteria: []string{"GET /widget returns 200"},
+	}, nil)
+	env.OnActivity(a.RequestApprovalActivity, mock.Anything, mock.Anything).Return(&ApprovalTicket{}, nil)
+	env.OnActivity(a.PremiumBudgetActivity, mock.Anything, mock.Anything).Return(&PremiumBudget{}, nil)
+	env.OnActivity(a.AcquireWorktreeActivity, mock.Anything, mock.Anything).Return((*dispatch.WorktreeLease)(nil), nil)
+	env.OnActivity(a.ReservePairActivity, mock.Anything, mock.Anything).Return(&PairPlan{}, nil)
+	env.OnActivity(a.ExecuteActivity, mock.Anything, mock.Anything, mock.Anything).Return(nil,
+		temporal.NewNonRetryableApplicationError("codex stopped: estimated spend $4.20 crossed the $1.00 cost cap", costCapErrorType, nil))
+
+	var outcome OutcomeRecord
+	env.OnActivity(a.RecordOutcomeActivity, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		outcome = args.Get(1).(OutcomeRecord)
+	}).Return(nil)
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow("human-approval", "APPROVED")
+	}, 0)
+
+	env.ExecuteWorkflow(CortexAgentWorkflow, TaskRequest{BeadID: "b-1", Project: "p", Agent: "codex", WorkDir: "/tmp/test"})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.Error(t, env.GetWorkflowError())
+	require.Equal(t, "pending_retry", outcome.Status)
+	require.Equal(t, CostCapExceeded, outcome.FailureCategory)
+	require.Contains(t, outcome.FailureSummary, "crossed the $1.00 cost cap")
+	env.AssertNumberOfCalls(t, "ExecuteActivity", 1)
+	env.AssertActivityNotCalled(t, "DoDVerifyActivity", mock.Anything, mock.Anything)
+}
+
+func TestPremiumBudgetActivity(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+	id, err := st.RecordDispatch("b-0", "p", "claude", "opus", "premium", 0, "", "prompt", "", "", "temporal")
+	require.NoError(t, err)
+	require.NoError(t, st.UpdateDispatchStatus(id, "completed", 0, 1))
+	require.NoError(t, st.RecordDispatchCost(id, 100, 100, 12.5))
+
+	acts := &Activities{
+		Store:       st,
+		Tiers:       config.Tiers{Balanced: []string{"sonnet"}, Premium: []string{"opus"}},
+		CostControl: config.DispatchCostControl{DailyCostCapUSD: 10},
+	}
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(acts.PremiumBudgetActivity)
+
+	for _, tc := range []struct {
+		provider string
+		capUSD   float64
+		want     bool
+	}{
+		{"opus", 10, true},
+		{"opus", 20, false},
+		{"opus", 0, false},
+		{"sonnet", 10, false},
+	} {
+		acts.CostControl.DailyCostCapUSD = tc.capUSD
+		val, err := env.ExecuteActivity(acts.PremiumBudgetActivity, TaskRequest{BeadID: "b-1", Provider: tc.provider})
+		require.NoError(t, err)
+		var budget PremiumBudget
+		require.NoError(t, val.Get(&budget))
+		require.Equal(t, tc.want, budget.OverBudget, "%s with a $%.0f cap", tc.provider, tc.capUSD)
+	}
+}
+
+func TestPremiumBudgetOverrideDenied(t *testing.T) {
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+	var a *Activities
+
+	env.OnActivity(a.PremiumBudgetActivity, mock.Anything, mock.Anything).Return(&PremiumBudget{
+		OverBudget: true, SpentUSD: 12.5, CapUSD: 10,
+	}, nil)
+	var prompts []ApprovalPrompt
+	env.OnActivity(a.RequestApprovalActivity, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		prompts = append(prompts, args.Get(1).(ApprovalPrompt))
+	}).Return(&ApprovalTicket{}, nil)
+	var outcome OutcomeRecord
+	env.OnActivity(a.RecordOutcomeActivity, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		outcome = args.Get(1).(OutcomeRecord)
+	}).Return(nil)
+	stubActivities(env)
+
+	env.RegisterDelayedCallback(func() { env.SignalWorkflow("human-approval", "APPROVED") }, time.Second)
+	env.RegisterDelayedCallback(func() { env.SignalWorkflow("human-approval", "REJECTED") }, 2*time.Second)
+	env.ExecuteWorkflow(CortexAgentWorkflow, TaskRequest{BeadID: "b-1", Project: "p", Agent: "claude", Provider: "opus", WorkDir: "/tmp/test"})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.ErrorContains(t, env.GetWorkflowError(), "premium budget override denied")
+	require.Len(t, prompts, 2)
+	require.Equal(t, "budget", prompts[1].Kind)
+	require.Contains(t, prompts[1].Summary, "$12.50 has reached the $10.00 daily cap")
+	require.Equal(t, "rejected", outcome.Status)
+	env.AssertActivityNotCalled(t, "ExecuteActivity", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,42 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/costreport"
+)
+
+// CostReportActivity totals the spend of the request month per project, role,
+// provider and tier and writes the JSON and Markdown artifacts to the reports
+// dir. The outcome is recorded as a cost_report health event.
+func (a *Activities) CostReportActivity(ctx context.Context, req CostReportRequest) (*CostReportResult, error) {
+	if a.Store == nil {
+		return nil, fmt.Errorf("cost report: no store configured")
+	}
+
+	month := req.Month
+	if month == "" {
+		month = costreport.PreviousMonth(time.Now())
+	}
+	report, err := costreport.Generate(a.Store, month)
+	if err != nil {
+		return nil, err
+	}
+	jsonPath, mdPath, err := costreport.Write(req.ReportsDir, report)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CostReportResult{
+		Month:        report.Month,
+		CostUSD:      report.CostUSD,
+		Projects:     len(report.Projects),
+		JSONPath:     jsonPath,
+		MarkdownPath: mdPath,
+	}
+	a.Store.RecordHealthEvent("cost_report", fmt.Sprintf("Cost report %s: $%.2f across %d projects (%s)",
+		result.Month, result.CostUSD, result.Projects, result.MarkdownPath))
+	return result, nil
+}
@@ -0,0 +1,61 @@
+package temporal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/costreport"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestCostReportActivityWritesArtifacts(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+
+	id, err := st.RecordDispatch("b-1", "cortex", "agent", "claude", "premium", 1, "", "p", "", "", "")
+	require.NoError(t, err)
+	require.NoError(t, st.RecordDispatchCost(id, 1000, 200, 2.5))
+	require.NoError(t, st.StoreTokenUsage(id, "b-1", "cortex", "execute", "claude", store.TokenUsage{InputTokens: 1000, OutputTokens: 200, CostUSD: 2.5}))
+
+	dir := filepath.Join(t.TempDir(), "reports")
+	acts := &Activities{Store: st}
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(acts.CostReportActivity)
+
+	month := time.Now().UTC().Format(costreport.MonthLayout)
+	val, err := env.ExecuteActivity(acts.CostReportActivity, CostReportRequest{ReportsDir: dir, Month: month})
+	require.NoError(t, err)
+	var result CostReportResult
+	require.NoError(t, val.Get(&result))
+
+	require.Equal(t, month, result.Month)
+	require.Equal(t, 2.5, result.CostUSD)
+	require.Equal(t, 1, result.Projects)
+	require.FileExists(t, result.JSONPath)
+	md, err := os.ReadFile(result.MarkdownPath)
+	require.NoError(t, err)
+	require.Contains(t, string(md), "| coder | 1 | 1000 | 200 | $2.50 |")
+
+	latest, err := costreport.Latest(dir)
+	require.NoError(t, err)
+	require.Equal(t, month, latest.Month)
+
+	events, err := st.GetRecentHealthEvents(1)
+	require.NoError(t, err)
+	require.NotEmpty(t, events)
+	require.Equal(t, "cost_report", events[0].EventType)
+
+	// Without a month the previous one is reported.
+	val, err = env.ExecuteActivity(acts.CostReportActivity, CostReportRequest{ReportsDir: dir})
+	require.NoError(t, err)
+	require.NoError(t, val.Get(&result))
+	require.Equal(t, costreport.PreviousMonth(time.Now()), result.Month)
+	require.Zero(t, result.CostUSD)
+}
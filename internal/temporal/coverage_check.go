@@ -0,0 +1,92 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/git"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+// totalCoveragePattern finds a percentage on a coverage tool's total line, as
+// printed by `go tool cover -func`, pytest-cov and most text reporters.
+var totalCoveragePattern = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*%`)
+
+// coverageCheck is the outcome of a project's DoD coverage check.
+type coverageCheck struct {
+	Check    git.CheckResult
+	Coverage *float64 // total coverage in percent; nil when it was not measured
+	Failures []string
+}
+
+// checkCoverage runs the project's coverage command in workDir and compares
+// the total against coverage_min and the project's baseline. Without a
+// baseline only coverage_min applies.
+func checkCoverage(ctx context.Context, workDir string, dod config.DoDConfig, baseline *store.CoverageBaseline) coverageCheck {
+	res, _ := git.RunPostMergeChecksCtx(ctx, workDir, []string{dod.Coverage.Command})
+	check := res.Checks[0]
+	if !check.Passed {
+		return coverageCheck{Check: check, Failures: []string{fmt.Sprintf("Coverage command failed: %s (exit %d)", check.Command, check.ExitCode)}}
+	}
+	total, err := parseCoverage(check.Output, dod.Coverage.Pattern)
+	if err != nil {
+		check.Passed = false
+		check.Output = err.Error()
+		return coverageCheck{Check: check, Failures: []string{"Coverage check: " + err.Error()}}
+	}
+
+	cov := coverageCheck{Check: check, Coverage: &total}
+	summary := fmt.Sprintf("coverage %.2f%%", total)
+	if baseline != nil {
+		summary += fmt.Sprintf(" (baseline %.2f%%, tolerance %g)", baseline.Coverage, dod.Coverage.Tolerance)
+		// The epsilon keeps float noise from failing a run that matches
+		// the baseline exactly.
+		if baseline.Coverage-total > dod.Coverage.Tolerance+1e-9 {
+			cov.Failures = append(cov.Failures, fmt.Sprintf("Coverage %.2f%% regressed from baseline %.2f%% by more than %g points",
+				total, baseline.Coverage, dod.Coverage.Tolerance))
+		}
+	}
+	if dod.CoverageMin > 0 && total < float64(dod.CoverageMin) {
+		cov.Failures = append(cov.Failures, fmt.Sprintf("Coverage %.2f%% is below coverage_min %d%%", total, dod.CoverageMin))
+	}
+	cov.Check.Output = summary
+	cov.Check.Passed = len(cov.Failures) == 0
+	return cov
+}
+
+// parseCoverage reads total coverage from a coverage command's output. With
+// a pattern, the first group of its last match is the total; otherwise the
+// total is the last percentage on the last line mentioning "total".
+func parseCoverage(output, pattern string) (float64, error) {
+	var value string
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return 0, fmt.Errorf("coverage pattern: %w", err)
+		}
+		if m := re.FindAllStringSubmatch(output, -1); len(m) > 0 && len(m[len(m)-1]) > 1 {
+			value = m[len(m)-1][1]
+		}
+	} else {
+		for _, line := range strings.Split(output, "\n") {
+			if !strings.Contains(strings.ToLower(line), "total") {
+				continue
+			}
+			if m := totalCoveragePattern.FindAllStringSubmatch(line, -1); len(m) > 0 {
+				value = m[len(m)-1][1]
+			}
+		}
+	}
+	if value == "" {
+		return 0, fmt.Errorf("no total coverage in output")
+	}
+	total, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse total coverage %q: %w", value, err)
+	}
+	return total, nil
+}
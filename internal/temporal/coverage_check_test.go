@@ -0,0 +1,81 @@
+package temporal
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestParseCoverage(t *testing.T) {
+	for _, tc := range []struct {
+		name, output, pattern string
+		want                  float64
+	}{
+		{"go tool cover", "cortex/a.go:10:\tRun\t\t100.0%\ntotal:\t\t\t(statements)\t83.4%\n", "", 83.4},
+		{"pytest-cov", "Name    Stmts   Miss  Cover\napp.py     40      4    90%\nTOTAL      120     10    92%\n", "", 92},
+		{"pattern", "85.30% coverage, 102/120 lines covered\n", `(\d+\.\d+)% coverage`, 85.3},
+	} {
+		got, err := parseCoverage(tc.output, tc.pattern)
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.want, got, tc.name)
+	}
+
+	_, err := parseCoverage("ok  \tcortex/a\tcoverage: 80.0% of statements\n", "")
+	require.ErrorContains(t, err, "no total coverage")
+}
+
+func TestDoDVerifyTracksCoverageBaseline(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+
+	verify := func(total string) DoDResult {
+		acts := &Activities{Store: st, Projects: map[string]config.Project{
+			"proj": {DoD: config.DoDConfig{CoverageMin: 50, Coverage: config.DoDCoverage{
+				Command:   "echo 'total: (statements) " + total + "%'",
+				Tolerance: 1,
+			}}},
+		}}
+		s := testsuite.WorkflowTestSuite{}
+		env := s.NewTestActivityEnvironment()
+		env.RegisterActivity(acts.DoDVerifyActivity)
+		val, err := env.ExecuteActivity(acts.DoDVerifyActivity, TaskRequest{
+			BeadID: "cx-1", Project: "proj", WorkDir: t.TempDir(), DoDChecks: []string{"true"},
+		})
+		require.NoError(t, err)
+		var res DoDResult
+		require.NoError(t, val.Get(&res))
+		return res
+	}
+
+	// The first measurement seeds the baseline.
+	res := verify("80.0")
+	require.True(t, res.Passed, res.Failures)
+	require.Equal(t, 80.0, *res.Coverage)
+	require.Equal(t, "coverage 80.00%", res.Checks[1].Output)
+
+	// A drop within the tolerance passes and moves the baseline.
+	res = verify("79.5")
+	require.True(t, res.Passed, res.Failures)
+	require.Equal(t, "coverage 79.50% (baseline 80.00%, tolerance 1)", res.Checks[1].Output)
+
+	// A drop beyond it fails and leaves the baseline alone.
+	res = verify("78.0")
+	require.False(t, res.Passed)
+	require.Equal(t, []string{"Coverage 78.00% regressed from baseline 79.50% by more than 1 points"}, res.Failures)
+	baseline, err := st.GetCoverageBaseline("proj")
+	require.NoError(t, err)
+	require.Equal(t, 79.5, baseline.Coverage)
+
+	res = verify("40")
+	require.False(t, res.Passed)
+	require.Equal(t, []string{
+		"Coverage 40.00% regressed from baseline 79.50% by more than 1 points",
+		"Coverage 40.00% is below coverage_min 50%",
+	}, res.Failures)
+}
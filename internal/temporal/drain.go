@@ -0,0 +1,111 @@
+package temporal
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// drainPollInterval is how often a drain checks for finished agents.
+	drainPollInterval = 250 * time.Millisecond
+	// drainInterruptGrace is how long interrupted agents get to exit before
+	// they are killed.
+	drainInterruptGrace = 10 * time.Second
+)
+
+// agentTracker records the agent CLI processes running in this worker so a
+// shutdown can wait for them and interrupt only the ones that overrun.
+type agentTracker struct {
+	mu      sync.Mutex
+	running map[*os.Process]string
+}
+
+var runningAgents = &agentTracker{running: make(map[*os.Process]string)}
+
+func (t *agentTracker) add(p *os.Process, agent string) {
+	t.mu.Lock()
+	t.running[p] = agent
+	t.mu.Unlock()
+}
+
+func (t *agentTracker) remove(p *os.Process) {
+	t.mu.Lock()
+	delete(t.running, p)
+	t.mu.Unlock()
+}
+
+func (t *agentTracker) snapshot() map[*os.Process]string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make(map[*os.Process]string, len(t.running))
+	for p, agent := range t.running {
+		out[p] = agent
+	}
+	return out
+}
+
+// DrainResult reports how a graceful shutdown went.
+type DrainResult struct {
+	Window      time.Duration `json:"window"`
+	InFlight    int           `json:"in_flight"`   // agents running when the drain began
+	Completed   int           `json:"completed"`   // finished within the window
+	Interrupted []string      `json:"interrupted"` // agents still running at the deadline
+	Elapsed     time.Duration `json:"elapsed"`
+}
+
+func (r DrainResult) String() string {
+	s := fmt.Sprintf("drained %d/%d dispatches in %s (window %s)",
+		r.Completed, r.InFlight, r.Elapsed.Round(time.Millisecond), r.Window)
+	if len(r.Interrupted) > 0 {
+		s += fmt.Sprintf("; interrupted %d: %s", len(r.Interrupted), strings.Join(r.Interrupted, ", "))
+	}
+	return s
+}
+
+// DrainAgents waits up to window for running agent processes to exit, then
+// interrupts the stragglers. Call it once the worker has stopped polling, so
+// no new dispatches start while it waits. Interrupted activities fail and
+// are retried by Temporal after restart.
+func DrainAgents(window time.Duration) DrainResult {
+	return runningAgents.drain(window, drainPollInterval, drainInterruptGrace)
+}
+
+func (t *agentTracker) drain(window, poll, grace time.Duration) DrainResult {
+	start := time.Now()
+	initial := t.snapshot()
+	result := DrainResult{Window: window, InFlight: len(initial)}
+
+	deadline := start.Add(window)
+	for len(t.snapshot()) > 0 && time.Now().Before(deadline) {
+		time.Sleep(poll)
+	}
+
+	stragglers := t.snapshot()
+	for p, agent := range stragglers {
+		result.Interrupted = append(result.Interrupted, fmt.Sprintf("%s (pid %d)", agent, p.Pid))
+		// os.Interrupt is unsupported on Windows; fall straight through to Kill.
+		if err := p.Signal(os.Interrupt); err != nil {
+			p.Kill()
+		}
+	}
+	sort.Strings(result.Interrupted)
+
+	killAt := time.Now().Add(grace)
+	for len(t.snapshot()) > 0 && time.Now().Before(killAt) {
+		time.Sleep(poll)
+	}
+	for p := range t.snapshot() {
+		p.Kill()
+	}
+
+	result.Completed = result.InFlight - len(stragglers)
+	if result.Completed < 0 {
+		result.Completed = 0 // agents that started after the snapshot
+	}
+	result.Elapsed = time.Since(start)
+	return result
+}
@@ -0,0 +1,58 @@
+package temporal
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func trackSleep(t *testing.T, tr *agentTracker, agent, seconds string) <-chan struct{} {
+	t.Helper()
+	cmd := exec.Command("sleep", seconds)
+	require.NoError(t, cmd.Start())
+	tr.add(cmd.Process, agent)
+	exited := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		tr.remove(cmd.Process)
+		close(exited)
+	}()
+	return exited
+}
+
+func TestDrainWaitsThenInterruptsStragglers(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep not available")
+	}
+	tr := &agentTracker{running: make(map[*os.Process]string)}
+	quick := trackSleep(t, tr, "codex", "0.2")
+	slow := trackSleep(t, tr, "claude", "30")
+
+	res := tr.drain(2*time.Second, 20*time.Millisecond, 2*time.Second)
+
+	require.Equal(t, 2, res.InFlight)
+	require.Equal(t, 1, res.Completed)
+	require.Len(t, res.Interrupted, 1)
+	require.Contains(t, res.Interrupted[0], "claude")
+	require.Less(t, res.Elapsed, 10*time.Second)
+	require.Contains(t, res.String(), "drained 1/2")
+
+	for _, ch := range []<-chan struct{}{quick, slow} {
+		select {
+		case <-ch:
+		case <-time.After(5 * time.Second):
+			t.Fatal("agent process still running after drain")
+		}
+	}
+}
+
+func TestDrainWithNothingRunningReturnsImmediately(t *testing.T) {
+	tr := &agentTracker{running: make(map[*os.Process]string)}
+	res := tr.drain(time.Minute, 10*time.Millisecond, time.Minute)
+	require.Zero(t, res.InFlight)
+	require.Empty(t, res.Interrupted)
+	require.Less(t, res.Elapsed, time.Second)
+}
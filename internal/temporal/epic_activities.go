@@ -0,0 +1,111 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.temporal.io/sdk/activity"
+
+	"github.com/antigravity-dev/cortex/internal/beads"
+)
+
+// EpicRollupActivity tallies each open epic's executable (non-epic)
+// children and closes the epic once the closed share reaches the threshold.
+// An epic without executable children is still waiting to be broken down and
+// is left open. Each close is recorded as an epic_auto_closed health event,
+// and a project that closed an epic gets the progress of all its epics
+// posted to its room.
+func (a *Activities) EpicRollupActivity(ctx context.Context, req EpicRollupRequest) (*EpicRollupResult, error) {
+	logger := activity.GetLogger(ctx)
+	names := make([]string, 0, len(req.Projects))
+	for name := range req.Projects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := &EpicRollupResult{}
+	for _, name := range names {
+		project := req.Projects[name]
+		list, err := beads.ListBeadsCtx(ctx, project.BeadsDir)
+		if err != nil {
+			logger.Warn("Epic rollup: listing beads failed", "Project", name, "error", err)
+			continue
+		}
+
+		progress := epicProgress(name, list)
+		closed := 0
+		for i := range progress {
+			p := &progress[i]
+			if p.Total == 0 || float64(p.Closed)/float64(p.Total) < req.Threshold {
+				continue
+			}
+			reason := fmt.Sprintf("Epic rollup: %d of %d children closed", p.Closed, p.Total)
+			if err := beads.CloseBeadWithReasonCtx(ctx, project.BeadsDir, p.EpicID, reason); err != nil {
+				logger.Warn("Epic rollup: close failed", "Project", name, "Epic", p.EpicID, "error", err)
+				continue
+			}
+			p.AutoClosed = true
+			closed++
+			logger.Info("Epic rollup: epic closed", "Project", name, "Epic", p.EpicID, "Closed", p.Closed, "Total", p.Total)
+			if a.Store != nil {
+				a.Store.RecordHealthEventWithDispatch("epic_auto_closed", reason, 0, p.EpicID)
+			}
+		}
+		if closed > 0 && a.Sender != nil && project.Room != "" {
+			if err := a.Sender.SendMessage(ctx, project.Room, epicRollupMessage(name, progress)); err != nil {
+				logger.Warn("Epic rollup: report failed", "Room", project.Room, "error", err)
+			}
+		}
+		result.Epics = append(result.Epics, progress...)
+	}
+	return result, nil
+}
+
+// epicProgress returns the child completion of each open epic in list,
+// ordered by priority then ID.
+func epicProgress(project string, list []beads.Bead) []EpicProgress {
+	var epics []beads.Bead
+	for _, b := range list {
+		if b.Type == "epic" && b.Status != "closed" {
+			epics = append(epics, b)
+		}
+	}
+	sort.Slice(epics, func(i, j int) bool {
+		if epics[i].Priority != epics[j].Priority {
+			return epics[i].Priority < epics[j].Priority
+		}
+		return epics[i].ID < epics[j].ID
+	})
+
+	progress := make([]EpicProgress, len(epics))
+	index := make(map[string]int, len(epics))
+	for i, e := range epics {
+		progress[i] = EpicProgress{Project: project, EpicID: e.ID, Title: e.Title}
+		index[e.ID] = i
+	}
+	for _, b := range list {
+		i, ok := index[b.ParentID]
+		if !ok || b.Type == "epic" {
+			continue
+		}
+		progress[i].Total++
+		if b.Status == "closed" {
+			progress[i].Closed++
+		}
+	}
+	return progress
+}
+
+func epicRollupMessage(project string, progress []EpicProgress) string {
+	lines := []string{fmt.Sprintf("Epic progress (%s):", project)}
+	for _, p := range progress {
+		line := fmt.Sprintf("- %s %s: %d/%d children closed", p.EpicID, p.Title, p.Closed, p.Total)
+		if p.AutoClosed {
+			line += " — epic closed"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,84 @@
+package temporal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestEpicRollupActivity(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+
+	workDir := t.TempDir()
+	logPath := filepath.Join(workDir, "bd.log")
+	fakeBin := t.TempDir()
+	list := `[
+		{"id":"e1","title":"Billing","issue_type":"epic","status":"open","priority":1},
+		{"id":"e2","title":"Search","issue_type":"epic","status":"open","priority":2},
+		{"id":"e3","title":"Reports","issue_type":"epic","status":"open","priority":2},
+		{"id":"t1","title":"Invoices","issue_type":"task","status":"closed","parent_id":"e1"},
+		{"id":"t2","title":"Refunds","issue_type":"task","status":"closed","dependencies":[{"issue_id":"t2","depends_on_id":"e1","type":"parent-child"}]},
+		{"id":"t3","title":"Index","issue_type":"task","status":"closed","parent_id":"e2"},
+		{"id":"t4","title":"Query","issue_type":"task","status":"open","parent_id":"e2"},
+		{"id":"e4","title":"Sub-epic","issue_type":"epic","status":"open","priority":3,"parent_id":"e1"}
+	]`
+	script := "#!/bin/sh\necho \"$@\" >> \"$BD_ARGS_LOG\"\nif [ \"$1\" = list ]; then cat <<'JSON'\n" + list + "\nJSON\nfi\n"
+	require.NoError(t, os.WriteFile(filepath.Join(fakeBin, "bd"), []byte(script), 0o755))
+	t.Setenv("BD_ARGS_LOG", logPath)
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	sender := &recordingSender{}
+	acts := &Activities{Store: st, Sender: sender}
+	run := func(threshold float64) EpicRollupResult {
+		t.Helper()
+		s := testsuite.WorkflowTestSuite{}
+		env := s.NewTestActivityEnvironment()
+		env.RegisterActivity(acts.EpicRollupActivity)
+		val, err := env.ExecuteActivity(acts.EpicRollupActivity, EpicRollupRequest{
+			Threshold: threshold,
+			Projects:  map[string]BacklogProject{"cortex": {BeadsDir: filepath.Join(workDir, ".beads"), Room: "!room"}},
+		})
+		require.NoError(t, err)
+		var result EpicRollupResult
+		require.NoError(t, val.Get(&result))
+		return result
+	}
+
+	result := run(1)
+	require.Len(t, result.Epics, 4)
+	require.Equal(t, EpicProgress{Project: "cortex", EpicID: "e1", Title: "Billing", Closed: 2, Total: 2, AutoClosed: true}, result.Epics[0])
+	require.Equal(t, "e2", result.Epics[1].EpicID)
+	require.False(t, result.Epics[1].AutoClosed, "half done is below the default threshold")
+	require.Equal(t, "e3", result.Epics[2].EpicID)
+	require.False(t, result.Epics[2].AutoClosed, "an epic without children awaits breakdown")
+	require.Zero(t, result.Epics[3].Total)
+
+	args, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	require.Contains(t, string(args), "close e1 --reason Epic rollup: 2 of 2 children closed")
+	require.NotContains(t, string(args), "close e2")
+	require.Len(t, sender.messages, 1)
+	require.Contains(t, sender.messages[0], "e1 Billing: 2/2 children closed — epic closed")
+	require.Contains(t, sender.messages[0], "e2 Search: 1/2 children closed")
+
+	events, err := st.GetRecentHealthEvents(1)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "epic_auto_closed", events[0].EventType)
+	require.Equal(t, "e1", events[0].BeadID)
+
+	require.NoError(t, os.Remove(logPath))
+	result = run(0.5)
+	require.True(t, result.Epics[1].AutoClosed, "half done meets a 0.5 threshold")
+	args, err = os.ReadFile(logPath)
+	require.NoError(t, err)
+	require.Equal(t, 2, strings.Count(string(args), "close "))
+}
@@ -0,0 +1,83 @@
+package temporal
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+
+	"go.temporal.io/sdk/activity"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+// AssignExperimentsActivity picks a variant for every enabled experiment that
+// applies to the task's project and records the assignment. Assignment runs as
+// an activity because the weighted draw is non-deterministic. At most one
+// experiment per role is applied so outcomes stay attributable.
+func (a *Activities) AssignExperimentsActivity(ctx context.Context, req TaskRequest) ([]ExperimentAssignment, error) {
+	logger := activity.GetLogger(ctx)
+
+	names := make([]string, 0, len(a.Experiments))
+	for name := range a.Experiments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var out []ExperimentAssignment
+	roles := make(map[string]bool)
+	for _, name := range names {
+		exp := a.Experiments[name]
+		if !exp.AppliesTo(req.Project) || roles[exp.Role] {
+			continue
+		}
+		variant, ok := pickVariant(exp.Variants, rand.Float64())
+		if !ok {
+			continue
+		}
+		roles[exp.Role] = true
+
+		assignment := ExperimentAssignment{
+			Experiment: name,
+			Variant:    variant.Name,
+			Role:       exp.Role,
+			Prompt:     variant.Prompt,
+			Agent:      variant.Agent,
+		}
+		if a.Store != nil {
+			runID, err := a.Store.RecordExperimentAssignment(name, variant.Name, exp.Role, req.BeadID, req.Project)
+			if err != nil {
+				return nil, err
+			}
+			assignment.RunID = runID
+		}
+		logger.Info("Experiment variant assigned", "Experiment", name, "Variant", variant.Name, "Role", exp.Role, "BeadID", req.BeadID)
+		out = append(out, assignment)
+	}
+	return out, nil
+}
+
+// pickVariant selects a variant by weight given a uniform draw r in [0, 1).
+func pickVariant(variants []config.ExperimentVariant, r float64) (config.ExperimentVariant, bool) {
+	total := 0
+	for _, v := range variants {
+		if v.Weight > 0 {
+			total += v.Weight
+		}
+	}
+	if total == 0 {
+		return config.ExperimentVariant{}, false
+	}
+	target := r * float64(total)
+	var last config.ExperimentVariant
+	for _, v := range variants {
+		if v.Weight <= 0 {
+			continue
+		}
+		last = v
+		target -= float64(v.Weight)
+		if target < 0 {
+			return v, true
+		}
+	}
+	return last, true
+}
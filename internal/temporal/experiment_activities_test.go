@@ -0,0 +1,45 @@
+package temporal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+func TestPickVariantHonorsWeights(t *testing.T) {
+	variants := []config.ExperimentVariant{
+		{Name: "control", Weight: 3},
+		{Name: "terse", Weight: 1},
+		{Name: "off", Weight: 0},
+	}
+
+	for r, want := range map[float64]string{0: "control", 0.74: "control", 0.75: "terse", 0.999: "terse"} {
+		got, ok := pickVariant(variants, r)
+		require.True(t, ok)
+		require.Equal(t, want, got.Name, "draw %v", r)
+	}
+
+	_, ok := pickVariant([]config.ExperimentVariant{{Name: "off"}}, 0.5)
+	require.False(t, ok, "all-zero weights assign nothing")
+}
+
+func TestAssignExperimentsActivity(t *testing.T) {
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	acts := &Activities{Experiments: map[string]config.Experiment{
+		"a-exec":   {Enabled: true, Role: "coder", Variants: []config.ExperimentVariant{{Name: "only", Agent: "codex", Weight: 1}}},
+		"b-exec":   {Enabled: true, Role: "coder", Variants: []config.ExperimentVariant{{Name: "shadowed", Weight: 1}}},
+		"review":   {Enabled: true, Role: "reviewer", Projects: []string{"other"}, Variants: []config.ExperimentVariant{{Name: "x", Weight: 1}}},
+		"disabled": {Role: "planner", Variants: []config.ExperimentVariant{{Name: "y", Weight: 1}}},
+	}}
+	env.RegisterActivity(acts.AssignExperimentsActivity)
+
+	val, err := env.ExecuteActivity(acts.AssignExperimentsActivity, TaskRequest{BeadID: "b1", Project: "cortex"})
+	require.NoError(t, err)
+	var got []ExperimentAssignment
+	require.NoError(t, val.Get(&got))
+	require.Equal(t, []ExperimentAssignment{{Experiment: "a-exec", Variant: "only", Role: "coder", Agent: "codex"}}, got)
+}
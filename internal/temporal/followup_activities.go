@@ -0,0 +1,95 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.temporal.io/sdk/activity"
+
+	"github.com/antigravity-dev/cortex/internal/beads"
+)
+
+// maxFollowUpTitle bounds the title of a follow-up bead; the full text goes
+// in its description.
+const maxFollowUpTitle = 100
+
+// CreateFollowUpBeadsActivity files the follow-ups an agent reported as
+// beads linked discovered-from the bead it worked on. They are labeled
+// triage:needed, which keeps them out of the dispatch pool until a human
+// triages them. A follow-up matching the title of a bead that is still open
+// is skipped, so a retried dispatch does not file it twice.
+func (a *Activities) CreateFollowUpBeadsActivity(ctx context.Context, req FollowUpRequest) (*FollowUpResult, error) {
+	logger := activity.GetLogger(ctx)
+	cfg := a.FollowUps
+	result := &FollowUpResult{}
+	if !cfg.Enabled || len(req.FollowUps) == 0 {
+		return result, nil
+	}
+
+	beadsDir := resolveBeadsDir(req.WorkDir)
+	list, err := beads.ListBeadsCtx(ctx, beadsDir)
+	if err != nil {
+		return nil, fmt.Errorf("list beads: %w", err)
+	}
+	open := make(map[string]string, len(list))
+	for _, b := range list {
+		if b.Status != "closed" {
+			open[strings.ToLower(b.Title)] = b.ID
+		}
+	}
+
+	var filed []string
+	for _, text := range req.FollowUps {
+		if len(result.Created) >= cfg.Max {
+			break
+		}
+		title := followUpTitle(text)
+		if title == "" {
+			continue
+		}
+		if id, ok := open[strings.ToLower(title)]; ok {
+			result.Skipped = append(result.Skipped, id)
+			continue
+		}
+		id, err := beads.CreateIssueWithOptionsCtx(ctx, beadsDir, beads.IssueOptions{
+			Title:       title,
+			Type:        "task",
+			Priority:    cfg.Priority,
+			Description: fmt.Sprintf("Reported by %s while working on %s:\n\n%s", req.Agent, req.BeadID, strings.TrimSpace(text)),
+			Labels:      []string{beads.LabelNeedsTriage},
+		})
+		if err != nil {
+			logger.Warn("Failed to create follow-up bead", "BeadID", req.BeadID, "Title", title, "error", err)
+			continue
+		}
+		if err := beads.AddDiscoveredFromCtx(ctx, beadsDir, id, req.BeadID); err != nil {
+			logger.Warn("Failed to link follow-up bead", "BeadID", req.BeadID, "FollowUp", id, "error", err)
+		}
+		open[strings.ToLower(title)] = id
+		result.Created = append(result.Created, id)
+		filed = append(filed, fmt.Sprintf("%s %s", id, title))
+	}
+
+	if len(filed) > 0 {
+		logger.Info("Follow-up beads created", "BeadID", req.BeadID, "Created", result.Created)
+		if room := a.Projects[req.Project].MatrixRoom; a.Sender != nil && room != "" {
+			msg := fmt.Sprintf("%s (%s) reported follow-ups, filed for triage:\n- %s", req.BeadID, req.Project, strings.Join(filed, "\n- "))
+			if err := a.Sender.SendMessage(ctx, room, msg); err != nil {
+				logger.Warn("Follow-up notification failed", "Room", room, "error", err)
+			}
+		}
+	}
+	return result, nil
+}
+
+// followUpTitle is the first line of a follow-up, without list markers,
+// shortened to maxFollowUpTitle.
+func followUpTitle(text string) string {
+	title, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	title = strings.TrimSpace(strings.TrimLeft(title, "-*• "))
+	if r := []rune(title); len(r) > maxFollowUpTitle {
+		title = strings.TrimSpace(string(r[:maxFollowUpTitle-3])) + "..."
+	}
+	return title
+}
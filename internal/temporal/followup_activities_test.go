@@ -0,0 +1,81 @@
+package temporal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+func TestCreateFollowUpBeadsActivity(t *testing.T) {
+	workDir := t.TempDir()
+	logPath := filepath.Join(workDir, "bd.log")
+	fakeBin := t.TempDir()
+	list := `[{"id":"cx-3","title":"Add rate limiting","status":"open"},{"id":"cx-4","title":"Document the API","status":"closed"}]`
+	script := "#!/bin/sh\necho \"$@\" >> \"$BD_ARGS_LOG\"\n" +
+		"case \"$1\" in\nlist) cat <<'JSON'\n" + list + "\nJSON\n;;\ncreate) echo cx-$(wc -l < \"$BD_ARGS_LOG\" | tr -d ' ');;\nesac\n"
+	require.NoError(t, os.WriteFile(filepath.Join(fakeBin, "bd"), []byte(script), 0o755))
+	t.Setenv("BD_ARGS_LOG", logPath)
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	sender := &recordingSender{}
+	acts := &Activities{
+		Sender:    sender,
+		Projects:  map[string]config.Project{"cortex": {MatrixRoom: "!cortex"}},
+		FollowUps: config.DispatchFollowUps{Enabled: true, Max: 2, Priority: 3},
+	}
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(acts.CreateFollowUpBeadsActivity)
+
+	val, err := env.ExecuteActivity(acts.CreateFollowUpBeadsActivity, FollowUpRequest{
+		BeadID:  "cx-1",
+		Project: "cortex",
+		WorkDir: workDir,
+		Agent:   "claude",
+		FollowUps: []string{
+			"add rate limiting",                        // still open as cx-3
+			"- Document the API\nCover every endpoint", // closed, so filed again
+			"Remove the legacy flag",
+			"Over the limit",
+		},
+	})
+	require.NoError(t, err)
+	var result FollowUpResult
+	require.NoError(t, val.Get(&result))
+	require.Len(t, result.Created, 2)
+	require.Equal(t, []string{"cx-3"}, result.Skipped)
+
+	args, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	log := string(args)
+	require.Contains(t, log, "--title Document the API --description Reported by claude while working on cx-1:")
+	require.Contains(t, log, "--labels triage:needed")
+	require.Contains(t, log, "dep add "+result.Created[0]+" cx-1 --type discovered-from")
+	require.Contains(t, log, "--title Remove the legacy flag")
+	require.NotContains(t, log, "Over the limit")
+	require.Equal(t, 2, strings.Count(log, "create "))
+
+	require.Len(t, sender.messages, 1)
+	require.Contains(t, sender.messages[0], "cx-1 (cortex) reported follow-ups, filed for triage:")
+
+	// Disabled, nothing is filed.
+	acts.FollowUps.Enabled = false
+	val, err = env.ExecuteActivity(acts.CreateFollowUpBeadsActivity, FollowUpRequest{BeadID: "cx-1", WorkDir: workDir, FollowUps: []string{"More work"}})
+	require.NoError(t, err)
+	var disabled FollowUpResult
+	require.NoError(t, val.Get(&disabled))
+	require.Empty(t, disabled.Created)
+}
+
+func TestFollowUpTitle(t *testing.T) {
+	require.Equal(t, "Add retries", followUpTitle("  * Add retries\nwith backoff"))
+	long := followUpTitle(strings.Repeat("word ", 40))
+	require.Len(t, []rune(long), maxFollowUpTitle)
+	require.True(t, strings.HasSuffix(long, "..."))
+}
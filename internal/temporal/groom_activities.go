@@ -83,13 +83,13 @@ Respond with ONLY a JSON array of mutations:
 Return empty array [] if no mutations are needed.`, completedContext, openCount, beadSummary.String(), req.BeadID)
 
 	agent := ResolveTierAgent(a.Tiers, req.Tier)
-	cliResult, err := runAgent(ctx, agent, prompt, req.WorkDir)
+	cliResult, keep, err := a.runCachedAgent(ctx, "groom", agent, "", prompt, req.WorkDir)
 	if err != nil {
 		return &GroomResult{}, nil // non-fatal
 	}
 
 	jsonStr := extractJSONArray(cliResult.Output)
-	if jsonStr == "" || jsonStr == "[]" {
+	if jsonStr == "" {
 		return &GroomResult{}, nil
 	}
 
@@ -98,6 +98,7 @@ Return empty array [] if no mutations are needed.`, completedContext, openCount,
 		logger.Warn("Failed to parse mutations JSON", "error", err)
 		return &GroomResult{}, nil
 	}
+	keep()
 
 	// Cap at 5 mutations per cycle
 	if len(mutations) > 5 {
@@ -339,7 +340,7 @@ Be opinionated. Say what matters most and why.`,
 	)
 
 	agent := ResolveTierAgent(a.Tiers, req.Tier)
-	cliResult, err := runAgent(ctx, agent, prompt, req.WorkDir)
+	cliResult, keep, err := a.runCachedAgent(ctx, "groom", agent, "", prompt, req.WorkDir)
 	if err != nil {
 		return nil, fmt.Errorf("strategic analysis failed: %w", err)
 	}
@@ -353,6 +354,7 @@ Be opinionated. Say what matters most and why.`,
 	if err := json.Unmarshal([]byte(jsonStr), &analysis); err != nil {
 		return nil, fmt.Errorf("failed to parse strategic analysis: %w", err)
 	}
+	keep()
 
 	logger.Info("Strategic analysis complete", "Priorities", len(analysis.Priorities), "Risks", len(analysis.Risks))
 	return &analysis, nil
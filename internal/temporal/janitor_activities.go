@@ -0,0 +1,177 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"go.temporal.io/sdk/activity"
+
+	"github.com/antigravity-dev/cortex/internal/beads"
+	"github.com/antigravity-dev/cortex/internal/git"
+)
+
+// BranchJanitorActivity deletes Cortex feature branches left behind by closed
+// or abandoned beads. A branch is only deleted once it is past the grace
+// period, not protected, and has no open PR; anything that cannot be
+// verified is kept. Each project's outcome is posted to its room and
+// recorded as a branch_janitor health event.
+func (a *Activities) BranchJanitorActivity(ctx context.Context, req BranchJanitorRequest) (*BranchJanitorResult, error) {
+	names := make([]string, 0, len(req.Projects))
+	for name := range req.Projects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := &BranchJanitorResult{}
+	now := time.Now()
+	for _, name := range names {
+		project := req.Projects[name]
+		report := janitorProject(ctx, name, project, req, now)
+		a.reportJanitor(ctx, project, report)
+		result.Projects = append(result.Projects, report)
+	}
+	return result, nil
+}
+
+func janitorProject(ctx context.Context, name string, project JanitorProject, req BranchJanitorRequest, now time.Time) BranchJanitorReport {
+	logger := activity.GetLogger(ctx)
+	report := BranchJanitorReport{Project: name, DryRun: req.DryRun}
+
+	branches, err := git.ListBranches(project.Workspace)
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+		return report
+	}
+	// Without the bead list a closed bead is indistinguishable from a
+	// missing one, so skip the project rather than guess.
+	beadList, err := beads.ListBeadsCtx(ctx, project.BeadsDir)
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+		return report
+	}
+	statuses := make(map[string]string, len(beadList))
+	for _, b := range beadList {
+		statuses[b.ID] = b.Status
+	}
+	current, _ := git.GetCurrentBranch(project.Workspace)
+
+	for _, b := range branches {
+		beadID, ok := cortexBranchBead(b.Name, project.Prefixes)
+		if !ok {
+			continue
+		}
+		report.Scanned++
+
+		if b.Name == current || b.Name == project.BaseBranch || branchProtected(b.Name, req.Protected) ||
+			now.Sub(b.LastCommit) < req.GracePeriod {
+			report.Kept++
+			continue
+		}
+		reason := staleBeadReason(statuses, beadID)
+		if reason == "" {
+			report.Kept++
+			continue
+		}
+		pr, err := git.GetPRStatus(project.Workspace, b.Name)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", b.Name, err))
+			report.Kept++
+			continue
+		}
+		if pr != nil && pr.State == "OPEN" {
+			report.Kept++
+			continue
+		}
+
+		if !req.DryRun {
+			if err := git.ForceDeleteBranch(project.Workspace, b.Name); err != nil {
+				report.Errors = append(report.Errors, err.Error())
+				report.Kept++
+				continue
+			}
+			if req.DeleteRemote {
+				if err := git.DeleteRemoteBranch(project.Workspace, b.Name); err != nil {
+					report.Errors = append(report.Errors, err.Error())
+				}
+			}
+		}
+		logger.Info("Branch janitor: stale branch", "Project", name, "Branch", b.Name, "Reason", reason, "DryRun", req.DryRun)
+		report.Deleted = append(report.Deleted, JanitorBranch{Branch: b.Name, BeadID: beadID, Reason: reason})
+		activity.RecordHeartbeat(ctx)
+	}
+	return report
+}
+
+// cortexBranchBead returns the bead ID a Cortex branch was created for, or
+// false when the branch does not carry one of the Cortex prefixes.
+func cortexBranchBead(branch string, prefixes []string) (string, bool) {
+	for _, prefix := range prefixes {
+		if prefix == "" || !strings.HasPrefix(branch, prefix) {
+			continue
+		}
+		if beadID := strings.TrimPrefix(branch, prefix); beadID != "" && !strings.Contains(beadID, "/") {
+			return beadID, true
+		}
+	}
+	return "", false
+}
+
+func branchProtected(branch string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, branch); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// staleBeadReason explains why a bead's branch may go, or returns "" while
+// the bead is still live.
+func staleBeadReason(statuses map[string]string, beadID string) string {
+	status, ok := statuses[beadID]
+	switch {
+	case !ok:
+		return "bead missing"
+	case status == "closed":
+		return "bead closed"
+	}
+	return ""
+}
+
+func (a *Activities) reportJanitor(ctx context.Context, project JanitorProject, report BranchJanitorReport) {
+	if len(report.Deleted) == 0 && len(report.Errors) == 0 {
+		return
+	}
+	msg := branchJanitorMessage(report)
+	if a.Store != nil {
+		a.Store.RecordHealthEvent("branch_janitor", msg)
+	}
+	if a.Sender != nil && project.Room != "" {
+		if err := a.Sender.SendMessage(ctx, project.Room, msg); err != nil {
+			activity.GetLogger(ctx).Warn("Branch janitor: report failed", "Room", project.Room, "error", err)
+		}
+	}
+}
+
+func branchJanitorMessage(r BranchJanitorReport) string {
+	verb := "deleted"
+	if r.DryRun {
+		verb = "would delete"
+	}
+	msg := fmt.Sprintf("Branch janitor (%s): %s %d of %d Cortex branches", r.Project, verb, len(r.Deleted), r.Scanned)
+	if len(r.Deleted) > 0 {
+		parts := make([]string, 0, len(r.Deleted))
+		for _, d := range r.Deleted {
+			parts = append(parts, fmt.Sprintf("%s (%s)", d.Branch, d.Reason))
+		}
+		msg += ": " + strings.Join(parts, ", ")
+	}
+	if len(r.Errors) > 0 {
+		msg += fmt.Sprintf("\n%d error(s): %s", len(r.Errors), strings.Join(r.Errors, "; "))
+	}
+	return msg
+}
@@ -0,0 +1,148 @@
+package temporal
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/git"
+)
+
+func TestCortexBranchBead(t *testing.T) {
+	prefixes := []string{"feat/", "cortex/"}
+
+	id, ok := cortexBranchBead("feat/cx-12", prefixes)
+	require.True(t, ok)
+	require.Equal(t, "cx-12", id)
+
+	id, ok = cortexBranchBead("cortex/cx-3", prefixes)
+	require.True(t, ok)
+	require.Equal(t, "cx-3", id)
+
+	for _, branch := range []string{"main", "feat/", "feat/ui/cx-1", "fix/cx-1"} {
+		_, ok := cortexBranchBead(branch, prefixes)
+		require.False(t, ok, branch)
+	}
+}
+
+func TestStaleBeadReasonAndProtection(t *testing.T) {
+	statuses := map[string]string{"cx-1": "closed", "cx-2": "open", "cx-3": "in_progress"}
+	require.Equal(t, "bead closed", staleBeadReason(statuses, "cx-1"))
+	require.Equal(t, "", staleBeadReason(statuses, "cx-2"))
+	require.Equal(t, "", staleBeadReason(statuses, "cx-3"))
+	require.Equal(t, "bead missing", staleBeadReason(statuses, "cx-9"))
+
+	require.True(t, branchProtected("feat/keep-me", []string{"release/*", "feat/keep-*"}))
+	require.False(t, branchProtected("feat/cx-1", []string{"release/*"}))
+}
+
+func TestBranchJanitorMessage(t *testing.T) {
+	msg := branchJanitorMessage(BranchJanitorReport{
+		Project: "cortex", Scanned: 4, DryRun: true,
+		Deleted: []JanitorBranch{{Branch: "feat/cx-1", BeadID: "cx-1", Reason: "bead closed"}},
+		Errors:  []string{"feat/cx-5: gh failed"},
+	})
+	require.Contains(t, msg, "Branch janitor (cortex): would delete 1 of 4 Cortex branches: feat/cx-1 (bead closed)")
+	require.Contains(t, msg, "1 error(s): feat/cx-5: gh failed")
+}
+
+func janitorGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=t", "-c", "user.email=t@example.com"}, args...)...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func TestBranchJanitorActivityDeletesOnlyStaleBranches(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	janitorGit(t, repo, "init", "-b", "main")
+	janitorGit(t, repo, "commit", "--allow-empty", "-m", "init")
+	for _, b := range []string{"feat/cx-closed", "feat/cx-open", "feat/cx-gone", "feat/cx-pr", "feat/cx-keep", "topic/cx-closed"} {
+		janitorGit(t, repo, "branch", b)
+	}
+
+	fakeBin := t.TempDir()
+	bd := `#!/bin/sh
+echo '[{"id":"cx-closed","status":"closed"},{"id":"cx-open","status":"open"},{"id":"cx-pr","status":"closed"},{"id":"cx-keep","status":"closed"}]'
+`
+	gh := `#!/bin/sh
+if [ "$3" = "feat/cx-pr" ]; then
+  echo '{"number":7,"url":"https://github.com/o/r/pull/7","state":"OPEN"}'
+  exit 0
+fi
+echo "no pull requests found for branch \"$3\"" >&2
+exit 1
+`
+	require.NoError(t, os.WriteFile(filepath.Join(fakeBin, "bd"), []byte(bd), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(fakeBin, "gh"), []byte(gh), 0o755))
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	sender := &recordingSender{}
+	acts := &Activities{Sender: sender}
+	run := func(req BranchJanitorRequest) BranchJanitorReport {
+		s := testsuite.WorkflowTestSuite{}
+		env := s.NewTestActivityEnvironment()
+		env.RegisterActivity(acts.BranchJanitorActivity)
+		val, err := env.ExecuteActivity(acts.BranchJanitorActivity, req)
+		require.NoError(t, err)
+		var res BranchJanitorResult
+		require.NoError(t, val.Get(&res))
+		require.Len(t, res.Projects, 1)
+		return res.Projects[0]
+	}
+	req := BranchJanitorRequest{
+		Protected: []string{"feat/cx-keep"},
+		Projects: map[string]JanitorProject{"cortex": {
+			Workspace: repo, BeadsDir: filepath.Join(repo, ".beads"), BaseBranch: "main",
+			Prefixes: []string{"feat/"}, Room: "!room",
+		}},
+	}
+
+	// Inside the grace period nothing goes.
+	req.GracePeriod = time.Hour
+	report := run(req)
+	require.Equal(t, 5, report.Scanned)
+	require.Empty(t, report.Deleted)
+	require.Empty(t, sender.messages)
+
+	req.GracePeriod = 0
+	req.DryRun = true
+	report = run(req)
+	require.Len(t, report.Deleted, 2)
+	exists, err := git.BranchExists(repo, "feat/cx-closed")
+	require.NoError(t, err)
+	require.True(t, exists, "dry run keeps branches")
+
+	req.DryRun = false
+	report = run(req)
+	deleted := map[string]string{}
+	for _, d := range report.Deleted {
+		deleted[d.Branch] = d.Reason
+	}
+	require.Equal(t, map[string]string{"feat/cx-closed": "bead closed", "feat/cx-gone": "bead missing"}, deleted)
+	require.Equal(t, 3, report.Kept)
+	require.Empty(t, report.Errors)
+
+	for branch, want := range map[string]bool{
+		"feat/cx-closed": false, "feat/cx-gone": false,
+		"feat/cx-open": true, "feat/cx-pr": true, "feat/cx-keep": true, "topic/cx-closed": true, "main": true,
+	} {
+		exists, err := git.BranchExists(repo, branch)
+		require.NoError(t, err)
+		require.Equal(t, want, exists, branch)
+	}
+
+	require.Len(t, sender.messages, 2)
+	require.Equal(t, "!room", sender.rooms[1])
+	require.True(t, strings.HasPrefix(sender.messages[1], "Branch janitor (cortex): deleted 2 of 5"))
+}
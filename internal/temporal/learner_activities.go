@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"go.temporal.io/sdk/activity"
+
+	"github.com/antigravity-dev/cortex/internal/learner"
 )
 
 // sanitizeForFilename converts a summary to a safe filename component.
@@ -95,7 +97,7 @@ If there are no meaningful lessons, return an empty array [].`,
 	)
 
 	agent := ResolveTierAgent(a.Tiers, req.Tier)
-	cliResult, err := runAgent(ctx, agent, prompt, req.WorkDir)
+	cliResult, err := a.runAgent(ctx, agent, prompt, req.WorkDir)
 	if err != nil {
 		logger.Warn("Lesson extraction LLM failed", "error", err)
 		return nil, nil // non-fatal
@@ -208,7 +210,7 @@ rules:
 			strings.Join(lesson.FilePaths, ", "),
 		)
 
-		cliResult, err := runAgent(ctx, ResolveTierAgent(a.Tiers, req.Tier), prompt, req.WorkDir)
+		cliResult, err := a.runAgent(ctx, ResolveTierAgent(a.Tiers, req.Tier), prompt, req.WorkDir)
 		if err != nil {
 			logger.Warn("Semgrep rule generation failed", "lesson", lesson.Summary, "error", err)
 			continue
@@ -312,3 +314,48 @@ func (a *Activities) RunSemgrepScanActivity(ctx context.Context, workDir string)
 		Output:   truncate(outStr, 2000),
 	}, nil
 }
+
+// FailureClusterReportActivity clusters failed dispatches across all projects
+// and writes the top recurring clusters to a dated markdown report.
+func (a *Activities) FailureClusterReportActivity(ctx context.Context, req FailureClusterReportRequest) (*FailureClusterReportResult, error) {
+	logger := activity.GetLogger(ctx)
+
+	if a.Store == nil {
+		return nil, fmt.Errorf("no store configured")
+	}
+	if req.WindowDays <= 0 {
+		req.WindowDays = 7
+	}
+	if req.Limit <= 0 {
+		req.Limit = 10
+	}
+
+	until := time.Now().UTC()
+	since := until.Add(-time.Duration(req.WindowDays) * 24 * time.Hour)
+	clusters, err := learner.TopFailureClusters(a.Store.DB(), since, req.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("cluster failures: %w", err)
+	}
+
+	result := &FailureClusterReportResult{Clusters: len(clusters)}
+	for _, c := range clusters {
+		result.Failures += c.Count
+	}
+
+	if req.ReportDir != "" {
+		if err := os.MkdirAll(req.ReportDir, 0o755); err != nil {
+			return nil, fmt.Errorf("create report dir: %w", err)
+		}
+		result.Path = filepath.Join(req.ReportDir, fmt.Sprintf("failure-clusters-%s.md", until.Format("2006-01-02")))
+		report := learner.FormatFailureClusterReport(clusters, since, until)
+		if err := os.WriteFile(result.Path, []byte(report), 0o644); err != nil {
+			return nil, fmt.Errorf("write failure cluster report: %w", err)
+		}
+	}
+
+	for i, c := range clusters {
+		logger.Info("Failure cluster", "Rank", i+1, "Count", c.Count, "Projects", strings.Join(c.Projects, ","), "Signature", c.Signature)
+	}
+	logger.Info("Failure cluster report generated", "Clusters", result.Clusters, "Failures", result.Failures, "Path", result.Path)
+	return result, nil
+}
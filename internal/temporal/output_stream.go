@@ -0,0 +1,60 @@
+package temporal
+
+import (
+	"context"
+
+	"go.temporal.io/sdk/activity"
+
+	"github.com/antigravity-dev/cortex/internal/redact"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+// outputStream appends what a running agent has printed since the last
+// flush to the store, so a run whose worker or session dies mid-flight still
+// leaves its output behind. RecordOutcomeActivity folds the chunks into the
+// dispatch's captured output.
+type outputStream struct {
+	st    *store.Store
+	chunk store.OutputChunk
+	sent  [2]int // bytes of stdout and stderr already appended
+}
+
+// streamOutput returns the stream into st for agent run from the current
+// activity, or nil when st is nil or ctx is not an activity context.
+func streamOutput(ctx context.Context, st *store.Store, agent string) *outputStream {
+	if st == nil || !activity.IsActivity(ctx) {
+		return nil
+	}
+	info := activity.GetInfo(ctx)
+	return &outputStream{st: st, chunk: store.OutputChunk{
+		WorkflowID: info.WorkflowExecution.ID,
+		ActivityID: info.ActivityID,
+		Agent:      agent,
+	}}
+}
+
+// flush appends the output added since the previous flush. Until the final
+// flush the last redact.Window bytes are held back, and the cut is moved
+// back off any secret it would split, so the redaction pass sees every
+// secret whole. A failed append is retried with the next flush.
+func (o *outputStream) flush(stdout, stderr string, final bool) {
+	if o == nil {
+		return
+	}
+	for i, out := range []string{stdout, stderr} {
+		end := len(out)
+		if !final {
+			end = o.sent[i] + redact.Cut(o.st.RedactOutput, out[o.sent[i]:])
+		}
+		if end <= o.sent[i] {
+			continue
+		}
+		c := o.chunk
+		c.Stream = [2]string{"stdout", "stderr"}[i]
+		c.Chunk = out[o.sent[i]:end]
+		if err := o.st.AppendOutputChunk(c); err != nil {
+			continue
+		}
+		o.sent[i] = end
+	}
+}
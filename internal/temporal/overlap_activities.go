@@ -0,0 +1,154 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.temporal.io/sdk/activity"
+
+	"github.com/antigravity-dev/cortex/internal/beads"
+	"github.com/antigravity-dev/cortex/internal/git"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+// FileOverlapActivity flags ready beads that will touch the same files. It
+// maps beads to files from commits naming them and from their feature
+// branches, adding to the planned files StructuredPlanActivity records, then
+// pairs up the ready beads that share a file. In serialize mode the later
+// bead of a pair, in dispatch order, is made to depend on the earlier one so
+// they run one after the other. Each project with overlaps gets them posted
+// to its room and recorded as a file_overlap health event.
+func (a *Activities) FileOverlapActivity(ctx context.Context, req FileOverlapRequest) (*FileOverlapResult, error) {
+	result := &FileOverlapResult{}
+	if a.Store == nil {
+		return result, nil
+	}
+
+	names := make([]string, 0, len(req.Projects))
+	for name := range req.Projects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		project := req.Projects[name]
+		report := a.overlapProject(ctx, name, project, req)
+		a.reportOverlap(ctx, project.Room, report)
+		result.Projects = append(result.Projects, report)
+	}
+	return result, nil
+}
+
+func (a *Activities) overlapProject(ctx context.Context, name string, project JanitorProject, req FileOverlapRequest) FileOverlapReport {
+	logger := activity.GetLogger(ctx)
+	report := FileOverlapReport{Project: name}
+
+	list, err := beads.ListBeadsCtx(ctx, project.BeadsDir)
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+		return report
+	}
+	known := make(map[string]bool, len(list))
+	for _, b := range list {
+		known[b.ID] = true
+	}
+	ready := beads.FilterUnblockedOpen(list, beads.BuildDepGraph(list))
+	report.Ready = len(ready)
+
+	record := func(beadID, source string, paths []string) {
+		if err := a.Store.RecordBeadFiles(name, beadID, source, paths); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", beadID, err))
+		}
+	}
+	if project.Workspace != "" {
+		commits, err := git.BeadFilesFromCommits(project.Workspace, req.LookbackDays)
+		if err != nil {
+			report.Errors = append(report.Errors, err.Error())
+		}
+		for beadID, paths := range commits {
+			if known[beadID] {
+				record(beadID, store.BeadFilesCommit, paths)
+			}
+		}
+		for _, b := range ready {
+			for _, prefix := range project.Prefixes {
+				branch := prefix + b.ID
+				if exists, err := git.BranchExists(project.Workspace, branch); err != nil || !exists {
+					continue
+				}
+				paths, err := git.BranchFiles(project.Workspace, project.BaseBranch, branch)
+				if err != nil {
+					report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", branch, err))
+					continue
+				}
+				record(b.ID, store.BeadFilesBranch, paths)
+			}
+		}
+	}
+
+	ids := make([]string, len(ready))
+	for i, b := range ready {
+		ids[i] = b.ID
+	}
+	files, err := a.Store.GetBeadFiles(name, ids)
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+		return report
+	}
+	report.Mapped = len(files)
+	report.Overlaps = beads.FindFileOverlaps(ready, files)
+
+	if req.Mode != "serialize" {
+		return report
+	}
+	// A bead waits on the first earlier bead it overlaps; once it waits it is
+	// no longer ready, so its later overlaps are left to the next run.
+	held := make(map[string]bool)
+	for _, o := range report.Overlaps {
+		if held[o.First] || held[o.Second] {
+			continue
+		}
+		if err := beads.AddDependencyCtx(ctx, project.BeadsDir, o.Second, o.First); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", o.Second, err))
+			continue
+		}
+		held[o.Second] = true
+		report.Serialized = append(report.Serialized, o.Second)
+		logger.Info("File overlap: bead serialized", "Project", name, "Bead", o.Second, "DependsOn", o.First, "Files", len(o.Files))
+	}
+	return report
+}
+
+func (a *Activities) reportOverlap(ctx context.Context, room string, report FileOverlapReport) {
+	if len(report.Overlaps) == 0 && len(report.Errors) == 0 {
+		return
+	}
+	msg := overlapMessage(report)
+	a.Store.RecordHealthEvent("file_overlap", msg)
+	if a.Sender != nil && room != "" {
+		if err := a.Sender.SendMessage(ctx, room, msg); err != nil {
+			activity.GetLogger(ctx).Warn("File overlap: report failed", "Room", room, "error", err)
+		}
+	}
+}
+
+func overlapMessage(r FileOverlapReport) string {
+	msg := fmt.Sprintf("File overlap (%s): %d pair(s) of ready beads touch the same files", r.Project, len(r.Overlaps))
+	for _, o := range r.Overlaps {
+		files := o.Files
+		more := ""
+		if len(files) > 3 {
+			files, more = files[:3], fmt.Sprintf(" and %d more", len(o.Files)-3)
+		}
+		msg += fmt.Sprintf("\n- %s and %s: %s%s", o.First, o.Second, strings.Join(files, ", "), more)
+	}
+	if len(r.Serialized) > 0 {
+		msg += "\nNow waiting on their overlap: " + strings.Join(r.Serialized, ", ")
+	}
+	if len(r.Errors) > 0 {
+		msg += fmt.Sprintf("\n%d error(s): %s", len(r.Errors), strings.Join(r.Errors, "; "))
+	}
+	return msg
+}
@@ -0,0 +1,111 @@
+package temporal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/beads"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestFileOverlapActivitySerializes(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+	require.NoError(t, st.RecordBeadFiles("cortex", "a", store.BeadFilesPlan, []string{"api.go", "store.go"}))
+	require.NoError(t, st.RecordBeadFiles("cortex", "b", store.BeadFilesPlan, []string{"store.go"}))
+	require.NoError(t, st.RecordBeadFiles("cortex", "c", store.BeadFilesPlan, []string{"store.go", "ui.go"}))
+	require.NoError(t, st.RecordBeadFiles("cortex", "d", store.BeadFilesPlan, []string{"store.go"}))
+
+	workDir := t.TempDir()
+	logPath := filepath.Join(workDir, "bd.log")
+	fakeBin := t.TempDir()
+	list := `[
+		{"id":"a","title":"API","status":"open","priority":1},
+		{"id":"b","title":"Store","status":"open","priority":2},
+		{"id":"c","title":"UI","status":"open","priority":3},
+		{"id":"d","title":"Done","status":"closed","priority":1}
+	]`
+	script := "#!/bin/sh\necho \"$@\" >> \"$BD_ARGS_LOG\"\nif [ \"$1\" = list ]; then cat <<'JSON'\n" + list + "\nJSON\nfi\n"
+	require.NoError(t, os.WriteFile(filepath.Join(fakeBin, "bd"), []byte(script), 0o755))
+	t.Setenv("BD_ARGS_LOG", logPath)
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	sender := &recordingSender{}
+	acts := &Activities{Store: st, Sender: sender}
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(acts.FileOverlapActivity)
+	val, err := env.ExecuteActivity(acts.FileOverlapActivity, FileOverlapRequest{
+		Mode:     "serialize",
+		Projects: map[string]JanitorProject{"cortex": {BeadsDir: filepath.Join(workDir, ".beads"), Room: "!room"}},
+	})
+	require.NoError(t, err)
+	var result FileOverlapResult
+	require.NoError(t, val.Get(&result))
+
+	require.Len(t, result.Projects, 1)
+	report := result.Projects[0]
+	require.Empty(t, report.Errors)
+	require.Equal(t, 3, report.Ready)
+	require.Equal(t, 3, report.Mapped)
+	require.Equal(t, []beads.FileOverlap{
+		{First: "a", Second: "b", Files: []string{"store.go"}},
+		{First: "a", Second: "c", Files: []string{"store.go"}},
+		{First: "b", Second: "c", Files: []string{"store.go"}},
+	}, report.Overlaps)
+	require.Equal(t, []string{"b", "c"}, report.Serialized)
+
+	args, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	require.Contains(t, string(args), "dep add b a\n")
+	require.Contains(t, string(args), "dep add c a\n")
+	require.NotContains(t, string(args), "dep add c b")
+
+	require.Len(t, sender.messages, 1)
+	require.Contains(t, sender.messages[0], "- a and b: store.go")
+	require.Contains(t, sender.messages[0], "Now waiting on their overlap: b, c")
+	events, err := st.GetRecentHealthEvents(1)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "file_overlap", events[0].EventType)
+}
+
+func TestFileOverlapActivityWarnOnly(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+	require.NoError(t, st.RecordBeadFiles("cortex", "a", store.BeadFilesPlan, []string{"store.go"}))
+	require.NoError(t, st.RecordBeadFiles("cortex", "b", store.BeadFilesPlan, []string{"store.go"}))
+
+	workDir := t.TempDir()
+	logPath := filepath.Join(workDir, "bd.log")
+	fakeBin := t.TempDir()
+	list := `[{"id":"a","title":"A","status":"open","priority":1},{"id":"b","title":"B","status":"open","priority":2}]`
+	script := "#!/bin/sh\necho \"$@\" >> \"$BD_ARGS_LOG\"\nif [ \"$1\" = list ]; then cat <<'JSON'\n" + list + "\nJSON\nfi\n"
+	require.NoError(t, os.WriteFile(filepath.Join(fakeBin, "bd"), []byte(script), 0o755))
+	t.Setenv("BD_ARGS_LOG", logPath)
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	acts := &Activities{Store: st}
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(acts.FileOverlapActivity)
+	val, err := env.ExecuteActivity(acts.FileOverlapActivity, FileOverlapRequest{
+		Mode:     "warn",
+		Projects: map[string]JanitorProject{"cortex": {BeadsDir: filepath.Join(workDir, ".beads")}},
+	})
+	require.NoError(t, err)
+	var result FileOverlapResult
+	require.NoError(t, val.Get(&result))
+	require.Len(t, result.Projects[0].Overlaps, 1)
+	require.Empty(t, result.Projects[0].Serialized)
+
+	args, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	require.NotContains(t, string(args), "dep add")
+}
@@ -0,0 +1,239 @@
+package temporal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.temporal.io/sdk/activity"
+
+	"github.com/antigravity-dev/cortex/internal/beads"
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/git"
+)
+
+const (
+	// defaultPairTurns applies when pair mode is requested explicitly but
+	// [dispatch.pair] was never configured.
+	defaultPairTurns = 6
+	// maxPairTurnChars caps each turn's output carried into later prompts.
+	maxPairTurnChars = 1500
+)
+
+// ReservePairActivity decides whether a task runs as a pair session. Pair
+// mode applies to beads at or above the configured priority, or when the
+// request asks for it. Both agents' providers are reserved up front so the
+// session counts as one dispatch holding two reservations; if either side
+// cannot be reserved the task falls back to the normal execute/review flow.
+func (a *Activities) ReservePairActivity(ctx context.Context, req TaskRequest) (*PairPlan, error) {
+	logger := activity.GetLogger(ctx)
+	forced := req.Mode == "pair"
+	if !a.Pair.Enabled && !forced {
+		return &PairPlan{}, nil
+	}
+
+	if !forced {
+		if req.BeadID == "" || req.WorkDir == "" {
+			return &PairPlan{Reason: "no bead to prioritize"}, nil
+		}
+		bead, err := beads.ShowBeadCtx(ctx, resolveBeadsDir(req.sharedWorkspace()), req.BeadID)
+		if err != nil || bead == nil {
+			return &PairPlan{Reason: "bead lookup failed"}, nil
+		}
+		if bead.Priority > a.Pair.MaxPriority {
+			return &PairPlan{Reason: fmt.Sprintf("priority %d below pair threshold %d", bead.Priority, a.Pair.MaxPriority)}, nil
+		}
+	}
+
+	plan := &PairPlan{Active: true, MaxTurns: a.Pair.MaxTurns}
+	if plan.MaxTurns < 2 {
+		plan.MaxTurns = defaultPairTurns
+	}
+
+	coder := agentProviders(a.Providers, req.Agent, req.Provider)
+	reviewer := agentProviders(a.Providers, req.Reviewer, "")
+	if a.RateLimiter == nil || len(coder) == 0 || len(reviewer) == 0 {
+		return plan, nil
+	}
+	res, release, err := a.RateLimiter.PickAndReservePair(coder, reviewer, a.Providers, "pair:"+req.Agent+"+"+req.Reviewer, req.BeadID)
+	if err != nil || res == nil {
+		reason := "no provider available for both agents"
+		if err != nil {
+			reason = err.Error()
+		}
+		logger.Info("Pair mode unavailable, using solo dispatch", "BeadID", req.BeadID, "Reason", reason)
+		a.journalSkip(ctx, "pair", req, reason)
+		return &PairPlan{Reason: reason}, nil
+	}
+	if err := ctx.Err(); err != nil {
+		// The workflow never sees this plan and falls back to solo, so
+		// the reservation must not keep counting against the caps.
+		release()
+		return nil, err
+	}
+	plan.CoderProvider = res.Coder
+	plan.ReviewerProvider = res.Reviewer
+	logger.Info("Pair session reserved", "BeadID", req.BeadID, "Coder", res.Coder, "Reviewer", res.Reviewer)
+	return plan, nil
+}
+
+// agentProviders lists the providers that run agent: those named after it
+// (claude, claude-max20) or using it as their CLI. preferred comes first.
+func agentProviders(providers map[string]config.Provider, agent, preferred string) []string {
+	var out []string
+	if _, ok := providers[preferred]; ok {
+		out = append(out, preferred)
+	}
+	var matched []string
+	for name, p := range providers {
+		if name == preferred {
+			continue
+		}
+		if name == agent || p.CLI == agent || strings.HasPrefix(name, agent+"-") {
+			matched = append(matched, name)
+		}
+	}
+	sort.Strings(matched)
+	return append(out, matched...)
+}
+
+// PairSessionActivity runs a pair session: the coder and reviewer alternate
+// turns on the same working tree, each seeing the session transcript, until
+// the reviewer approves or maxTurns is reached.
+func (a *Activities) PairSessionActivity(ctx context.Context, plan StructuredPlan, req TaskRequest, maxTurns int) (*PairSessionResult, error) {
+	logger := activity.GetLogger(ctx)
+	coder := req.Agent
+	reviewer := req.Reviewer
+	if reviewer == "" {
+		reviewer = DefaultReviewer(coder)
+	}
+	if maxTurns < 2 {
+		maxTurns = defaultPairTurns
+	}
+	logger.Info("Pair session", "Coder", coder, "Reviewer", reviewer, "MaxTurns", maxTurns, "BeadID", req.BeadID)
+
+	result := &PairSessionResult{
+		Execution: ExecutionResult{Agent: coder, ActivityID: currentActivityID(ctx)},
+		Review:    ReviewResult{ReviewerAgent: reviewer},
+	}
+	task := a.executionPrompt(ctx, plan, req, coder)
+
+	for turn := 1; turn <= maxTurns; turn++ {
+		if turn%2 == 1 {
+			prompt := pairCoderPrompt(task, reviewer, turn, maxTurns, result.Turns) + resultContract
+			cliResult, err := a.runAgent(ctx, coder, prompt, req.WorkDir)
+			cliResult = a.accountUsage(coder, req.Provider, cliResult)
+			a.observeProvider(ctx, coder, req.Provider, cliResult, err)
+			if err != nil {
+				logger.Warn("Pair coder turn exited with error", "Turn", turn, "error", err)
+				result.Execution.ExitCode = 1
+			} else {
+				result.Execution.ExitCode = 0
+			}
+			result.Execution.Output = cliResult.Output
+			result.Execution.Tokens.Add(cliResult.Tokens)
+			result.Execution.Report = parseAgentReport(cliResult.Output)
+			result.Execution.Confidence = agentConfidence(cliResult.Output, result.Execution.Report)
+			result.Turns = append(result.Turns, PairTurn{Turn: turn, Role: "coder", Agent: coder, Output: cliResult.Output})
+			continue
+		}
+
+		diff, _ := git.GetWorkingTreeDiff(req.WorkDir)
+		prompt := pairReviewerPrompt(plan, coder, git.TruncateDiff(diff, maxPromptDiffBytes), turn, maxTurns, result.Turns)
+		cliResult, err := a.runReviewAgent(ctx, reviewer, prompt, req.WorkDir)
+		cliResult = a.accountUsage(reviewer, "", cliResult)
+		a.observeProvider(ctx, reviewer, "", cliResult, err)
+		result.Review.Tokens.Add(cliResult.Tokens)
+		result.Review.ReviewOutput = cliResult.Output
+		result.Turns = append(result.Turns, PairTurn{Turn: turn, Role: "reviewer", Agent: reviewer, Output: cliResult.Output})
+
+		verdict, ok := parsePairVerdict(cliResult.Output)
+		if err != nil || !ok {
+			// Same policy as CodeReviewActivity: review infrastructure
+			// problems never block the task.
+			logger.Warn("Pair reviewer turn unusable, approving with warning", "Turn", turn, "error", err)
+			result.Review.Approved = true
+			result.Review.Issues = []string{"Pair reviewer output could not be used"}
+			result.Approved = true
+			break
+		}
+		result.Review.Approved = verdict.Approved
+		result.Review.Issues = verdict.Issues
+		result.Review.Suggestions = verdict.Suggestions
+		if verdict.Approved {
+			result.Approved = true
+			break
+		}
+	}
+
+	logger.Info("Pair session finished", "Turns", len(result.Turns), "Approved", result.Approved,
+		"CoderCostUSD", result.Execution.Tokens.CostUSD, "ReviewerCostUSD", result.Review.Tokens.CostUSD)
+	return result, nil
+}
+
+func parsePairVerdict(output string) (ReviewResult, bool) {
+	var verdict ReviewResult
+	jsonStr := extractJSON(output)
+	if jsonStr == "" {
+		return verdict, false
+	}
+	if err := json.Unmarshal([]byte(jsonStr), &verdict); err != nil {
+		return verdict, false
+	}
+	return verdict, true
+}
+
+func pairCoderPrompt(task, reviewer string, turn, maxTurns int, turns []PairTurn) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "PAIR SESSION — turn %d of %d. You are the coder; %s reviews the working tree after each of your turns.\n\n", turn, maxTurns, reviewer)
+	sb.WriteString(task)
+	if len(turns) > 0 {
+		sb.WriteString("\n\nSESSION SO FAR:\n")
+		sb.WriteString(formatPairTranscript(turns))
+		sb.WriteString("\nAddress the reviewer's latest feedback in the working tree.")
+	}
+	return sb.String()
+}
+
+func pairReviewerPrompt(plan StructuredPlan, coder, diff string, turn, maxTurns int, turns []PairTurn) string {
+	return fmt.Sprintf(`PAIR SESSION — turn %d of %d. You are reviewing %s's work as it happens; they will address your feedback on their next turn.
+
+PLAN SUMMARY: %s
+
+ACCEPTANCE CRITERIA:
+%s
+
+CURRENT DIFF:
+%s
+
+SESSION SO FAR:
+%s
+Respond with ONLY a JSON object:
+{
+  "approved": true/false,
+  "issues": ["issue 1", "issue 2"],
+  "suggestions": ["suggestion 1"]
+}
+
+Approve only when the acceptance criteria are met. Keep issues concrete so they can be fixed in one turn.`,
+		turn, maxTurns, coder, plan.Summary, formatCriteria(plan.AcceptanceCriteria), diff, formatPairTranscript(turns))
+}
+
+func formatPairTranscript(turns []PairTurn) string {
+	var sb strings.Builder
+	for _, t := range turns {
+		fmt.Fprintf(&sb, "[turn %d] %s (%s):\n%s\n\n", t.Turn, t.Role, t.Agent, truncate(strings.TrimSpace(t.Output), maxPairTurnChars))
+	}
+	return sb.String()
+}
+
+// dispatchTier labels temporal dispatches in the tier column; pair sessions
+// are tracked as a single "pair" dispatch.
+func dispatchTier(mode string) string {
+	if mode == "pair" {
+		return "pair"
+	}
+	return "temporal"
+}
@@ -0,0 +1,214 @@
+package temporal
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/dispatch"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestAgentProviders(t *testing.T) {
+	providers := map[string]config.Provider{
+		"claude-max20": {Authed: true},
+		"claude-pro":   {Authed: true},
+		"gpt":          {CLI: "codex"},
+		"claudette":    {},
+	}
+	require.Equal(t, []string{"claude-pro", "claude-max20"}, agentProviders(providers, "claude", "claude-pro"))
+	require.Equal(t, []string{"gpt"}, agentProviders(providers, "codex", ""))
+	require.Empty(t, agentProviders(providers, "gemini", "missing"))
+}
+
+func TestReserveProviderSkipsDeadProviders(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer st.Close()
+
+	dead := map[string]bool{"claude-max20": true}
+	rl := dispatch.NewRateLimiter(st, config.RateLimits{Window5hCap: 10, WeeklyCap: 100})
+	preflight := config.DispatchPreflight{
+		Enabled: true,
+		TTL:     config.Duration{Duration: time.Minute},
+		Timeout: config.Duration{Duration: time.Second},
+	}
+	probe := func(ctx context.Context, name string, p config.Provider) error {
+		if dead[name] {
+			return errors.New("gateway connect failed")
+		}
+		return nil
+	}
+	rl.SetPreflight(preflight, probe)
+	acts := &Activities{
+		Providers:   map[string]config.Provider{"claude-max20": {Authed: true}, "claude-pro": {Authed: true}},
+		RateLimiter: rl,
+	}
+
+	provider, _, err := acts.reserveProvider(context.Background(), "claude", TaskRequest{Provider: "claude-max20"})
+	require.NoError(t, err)
+	require.Equal(t, "claude-pro", provider, "solo dispatch kept the provider that failed its probe")
+
+	dead["claude-pro"] = true
+	rl.SetPreflight(preflight, probe) // drops the cached passes
+	_, _, err = acts.reserveProvider(context.Background(), "claude", TaskRequest{Provider: "claude-max20"})
+	require.Error(t, err)
+
+	provider, _, err = acts.reserveProvider(context.Background(), "gemini", TaskRequest{})
+	require.NoError(t, err)
+	require.Empty(t, provider, "an agent without providers has nothing to reserve")
+}
+
+func TestReserveProviderSpendsBudgetAndWaitsOutRetryAfter(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer st.Close()
+
+	p := config.Provider{Model: "sonnet", RequestsPerMinute: 60}
+	providers := map[string]config.Provider{"claude-free": p}
+	rl := dispatch.NewRateLimiter(st, config.RateLimits{Window5hCap: 10, WeeklyCap: 100})
+	acts := &Activities{Providers: providers, RateLimiter: rl}
+
+	provider, _, err := acts.reserveProvider(context.Background(), "claude", TaskRequest{BeadID: "b-1"})
+	require.NoError(t, err)
+	require.Equal(t, "claude-free", provider)
+	require.Equal(t, 59, rl.ProviderBudget("claude-free", p).RequestsRemaining, "solo dispatch took no request from the bucket")
+
+	rl.ObserveProviderOutput("claude-free", p, "error: 429 Too Many Requests (retry-after: 1)")
+	start := time.Now()
+	provider, _, err = acts.reserveProvider(context.Background(), "claude", TaskRequest{BeadID: "b-2"})
+	require.NoError(t, err)
+	require.Equal(t, "claude-free", provider)
+	require.GreaterOrEqual(t, time.Since(start), 900*time.Millisecond, "solo dispatch ran before retry-after passed")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rl.ObserveProviderOutput("claude-free", p, "error: 429 Too Many Requests (retry-after: 60)")
+	_, _, err = acts.reserveProvider(ctx, "claude", TaskRequest{BeadID: "b-3"})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestSoloDispatchBlockedByOpenBreaker(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer st.Close()
+
+	providers := map[string]config.Provider{"claude-max20": {Authed: true, Model: "sonnet"}}
+	rl := dispatch.NewRateLimiter(st, config.RateLimits{Window5hCap: 10, WeeklyCap: 100})
+	rl.SetCircuitBreakers(config.DispatchCircuitBreakers{
+		Provider: config.CircuitBreaker{Threshold: 1, Window: config.Duration{Duration: time.Minute}, Cooldown: config.Duration{Duration: time.Hour}},
+		Categories: map[string]config.CircuitBreaker{
+			dispatch.FailureGatewayClosed: {Threshold: 1, Window: config.Duration{Duration: time.Minute}, Cooldown: config.Duration{Duration: time.Hour}},
+		},
+	})
+	acts := &Activities{Providers: providers, RateLimiter: rl}
+	env := (&testsuite.WorkflowTestSuite{}).NewTestActivityEnvironment()
+	env.RegisterActivity(acts.ExecuteActivity)
+	req := TaskRequest{BeadID: "b-1", Agent: "claude", Provider: "claude-max20", WorkDir: t.TempDir()}
+
+	rl.RecordProviderOutcome("claude-max20", providers["claude-max20"], dispatch.FailureGatewayClosed)
+	_, err = env.ExecuteActivity(acts.ExecuteActivity, StructuredPlan{Summary: "widget"}, req)
+	require.ErrorContains(t, err, "no claude provider available", "solo dispatch ran past an open breaker")
+
+	require.True(t, rl.ResetCircuitBreaker(dispatch.BreakerScopeCategory, dispatch.FailureGatewayClosed))
+	_, err = env.ExecuteActivity(acts.ExecuteActivity, StructuredPlan{Summary: "widget"}, req)
+	require.ErrorContains(t, err, "no claude provider available", "solo dispatch ran past an open provider breaker")
+
+	usage, err := st.CountAuthedUsage5h()
+	require.NoError(t, err)
+	require.Zero(t, usage, "a blocked dispatch reserved provider usage")
+}
+
+func TestParsePairVerdict(t *testing.T) {
+	v, ok := parsePairVerdict("looks close\n```json\n{\"approved\": false, \"issues\": [\"missing test\"]}\n```")
+	require.True(t, ok)
+	require.False(t, v.Approved)
+	require.Equal(t, []string{"missing test"}, v.Issues)
+
+	_, ok = parsePairVerdict("LGTM")
+	require.False(t, ok)
+}
+
+func TestPairPromptsCarryTranscript(t *testing.T) {
+	turns := []PairTurn{
+		{Turn: 1, Role: "coder", Agent: "claude", Output: "added handler"},
+		{Turn: 2, Role: "reviewer", Agent: "codex", Output: `{"approved": false, "issues": ["no test"]}`},
+	}
+	coder := pairCoderPrompt("TASK: widget", "codex", 3, 4, turns)
+	require.Contains(t, coder, "turn 3 of 4")
+	require.Contains(t, coder, "TASK: widget")
+	require.Contains(t, coder, "[turn 2] reviewer (codex)")
+
+	first := pairCoderPrompt("TASK: widget", "codex", 1, 4, nil)
+	require.NotContains(t, first, "SESSION SO FAR")
+
+	reviewer := pairReviewerPrompt(StructuredPlan{Summary: "widget", AcceptanceCriteria: []string{"returns 200"}}, "claude", "+func Widget()", 2, 4, turns[:1])
+	require.Contains(t, reviewer, "+func Widget()")
+	require.Contains(t, reviewer, "returns 200")
+	require.Contains(t, reviewer, "[turn 1] coder (claude)")
+}
+
+func TestReservePairActivity(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer st.Close()
+
+	providers := map[string]config.Provider{
+		"claude-max20": {Authed: true, Model: "sonnet"},
+		"codex-pro":    {Authed: true, Model: "gpt"},
+	}
+	acts := &Activities{
+		Providers:   providers,
+		RateLimiter: dispatch.NewRateLimiter(st, config.RateLimits{Window5hCap: 10, WeeklyCap: 100}),
+	}
+	run := func(req TaskRequest) PairPlan {
+		s := testsuite.WorkflowTestSuite{}
+		env := s.NewTestActivityEnvironment()
+		env.RegisterActivity(acts.ReservePairActivity)
+		val, err := env.ExecuteActivity(acts.ReservePairActivity, req)
+		require.NoError(t, err)
+		var plan PairPlan
+		require.NoError(t, val.Get(&plan))
+		return plan
+	}
+
+	require.Equal(t, PairPlan{}, run(TaskRequest{BeadID: "b1", Agent: "claude", Reviewer: "codex"}), "pair mode is off by default")
+
+	plan := run(TaskRequest{BeadID: "b1", Agent: "claude", Reviewer: "codex", Mode: "pair"})
+	require.True(t, plan.Active)
+	require.Equal(t, defaultPairTurns, plan.MaxTurns)
+	require.Equal(t, "claude-max20", plan.CoderProvider)
+	require.Equal(t, "codex-pro", plan.ReviewerProvider)
+	count, err := st.CountAuthedUsage5h()
+	require.NoError(t, err)
+	require.Equal(t, 2, count, "one reservation per agent")
+
+	acts.RateLimiter = dispatch.NewRateLimiter(st, config.RateLimits{Window5hCap: 3, WeeklyCap: 100})
+	plan = run(TaskRequest{BeadID: "b2", Agent: "claude", Reviewer: "codex", Mode: "pair"})
+	require.False(t, plan.Active)
+	require.True(t, strings.Contains(plan.Reason, "rate limit"), plan.Reason)
+	count, err = st.CountAuthedUsage5h()
+	require.NoError(t, err)
+	require.Equal(t, 2, count, "failed pair reservation must not leak usage")
+
+	acts.RateLimiter = dispatch.NewRateLimiter(st, config.RateLimits{Window5hCap: 10, WeeklyCap: 100})
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.SetWorkerOptions(worker.Options{BackgroundActivityContext: cancelled})
+	env.RegisterActivity(acts.ReservePairActivity)
+	_, err = env.ExecuteActivity(acts.ReservePairActivity, TaskRequest{BeadID: "b3", Agent: "claude", Reviewer: "codex", Mode: "pair"})
+	require.Error(t, err)
+	count, err = st.CountAuthedUsage5h()
+	require.NoError(t, err)
+	require.Equal(t, 2, count, "an abandoned pair reservation must be released")
+}
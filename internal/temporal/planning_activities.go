@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	"go.temporal.io/sdk/activity"
+
+	"github.com/antigravity-dev/cortex/internal/store"
 )
 
 // PlanningAgents is the team that contributes perspectives during planning.
@@ -46,7 +48,7 @@ Respond with ONLY a JSON object:
 Start wide — consider all possible areas of improvement. Then rank by impact.`, req.Project)
 
 	agent := ResolveTierAgent(a.Tiers, req.Tier)
-	cliResult, err := runAgent(ctx, agent, prompt, req.WorkDir)
+	cliResult, keep, err := a.runCachedAgent(ctx, "chief", agent, "", prompt, req.WorkDir)
 	if err != nil {
 		return nil, fmt.Errorf("backlog grooming failed: %w", err)
 	}
@@ -64,15 +66,54 @@ Start wide — consider all possible areas of improvement. Then rank by impact.`
 	if len(backlog.Items) == 0 {
 		return nil, fmt.Errorf("chief produced empty backlog")
 	}
+	keep()
 
 	logger.Info("Backlog groomed",
 		"Items", len(backlog.Items),
 		"TopPick", backlog.Items[0].Title,
 	)
 
+	if a.Store != nil {
+		scanID, err := a.Store.RecordCandidateScan(candidateScan(req, agent, activity.GetInfo(ctx).WorkflowExecution.ID, backlog))
+		if err != nil {
+			logger.Warn("Failed to persist candidate scan", "error", err)
+		} else {
+			backlog.ScanID = scanID
+		}
+	}
+
 	return &backlog, nil
 }
 
+// RecordScanSelectionActivity records which backlog item the planner picked
+// from a persisted candidate scan, so the scan shows why it won.
+func (a *Activities) RecordScanSelectionActivity(ctx context.Context, scanID int64, itemID string) error {
+	if a.Store == nil || scanID == 0 {
+		return nil
+	}
+	return a.Store.RecordScanSelection(scanID, itemID)
+}
+
+func candidateScan(req PlanningRequest, agent, sessionID string, backlog BacklogPresentation) store.CandidateScan {
+	scan := store.CandidateScan{
+		Project:   req.Project,
+		SessionID: sessionID,
+		Agent:     agent,
+		Rationale: backlog.Rationale,
+	}
+	for _, item := range backlog.Items {
+		scan.Candidates = append(scan.Candidates, store.ScannedCandidate{
+			ItemID:      item.ID,
+			Title:       item.Title,
+			Impact:      item.Impact,
+			Effort:      item.Effort,
+			Recommended: item.Recommended,
+			Rationale:   item.Rationale,
+		})
+	}
+	return scan
+}
+
 // GenerateQuestionsActivity generates clarifying questions for the selected item.
 // Questions are sequential — each one builds on knowledge from the previous.
 // Consults the planning agent team for diverse perspectives.
@@ -111,7 +152,7 @@ Think carefully. These questions prevent wasted tokens and wrong assumptions.`,
 	)
 
 	agent := ResolveTierAgent(a.Tiers, req.Tier)
-	cliResult, err := runAgent(ctx, agent, prompt, req.WorkDir)
+	cliResult, keep, err := a.runCachedAgent(ctx, "chief", agent, "", prompt, req.WorkDir)
 	if err != nil {
 		return nil, fmt.Errorf("question generation failed: %w", err)
 	}
@@ -129,6 +170,7 @@ Think carefully. These questions prevent wasted tokens and wrong assumptions.`,
 	if len(questions) == 0 {
 		return nil, fmt.Errorf("no questions generated")
 	}
+	keep()
 
 	// Cap at 5 questions — keep planning focused
 	if len(questions) > 5 {
@@ -177,7 +219,7 @@ Be specific. The implementation team needs to know EXACTLY what to build.`,
 	)
 
 	agent := ResolveTierAgent(a.Tiers, req.Tier)
-	cliResult, err := runAgent(ctx, agent, prompt, req.WorkDir)
+	cliResult, keep, err := a.runCachedAgent(ctx, "chief", agent, "", prompt, req.WorkDir)
 	if err != nil {
 		return nil, fmt.Errorf("plan summary failed: %w", err)
 	}
@@ -191,6 +233,7 @@ Be specific. The implementation team needs to know EXACTLY what to build.`,
 	if err := json.Unmarshal([]byte(jsonStr), &summary); err != nil {
 		return nil, fmt.Errorf("failed to parse summary JSON: %w", err)
 	}
+	keep()
 
 	logger.Info("Plan summarized",
 		"What", summary.What,
@@ -0,0 +1,59 @@
+package temporal
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestCandidateScanKeepsRankAndReasons(t *testing.T) {
+	backlog := BacklogPresentation{
+		Rationale: "unblock users first",
+		Items: []BacklogItem{
+			{ID: "auth", Title: "Fix auth", Effort: "low", Recommended: true, Rationale: "blocks logins"},
+			{ID: "docs", Title: "Docs", Effort: "high", Rationale: "no users waiting on it"},
+		},
+	}
+
+	scan := candidateScan(PlanningRequest{Project: "cortex"}, "claude", "planning-cortex-1", backlog)
+	require.Equal(t, "cortex", scan.Project)
+	require.Equal(t, "planning-cortex-1", scan.SessionID)
+	require.Equal(t, "unblock users first", scan.Rationale)
+	require.Len(t, scan.Candidates, 2)
+	require.Equal(t, "auth", scan.Candidates[0].ItemID)
+	require.True(t, scan.Candidates[0].Recommended)
+	require.Equal(t, "no users waiting on it", scan.Candidates[1].Rationale)
+}
+
+func TestRecordScanSelectionActivity(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+
+	scanID, err := st.RecordCandidateScan(store.CandidateScan{
+		Project:    "cortex",
+		Candidates: []store.ScannedCandidate{{ItemID: "auth"}, {ItemID: "docs"}},
+	})
+	require.NoError(t, err)
+
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	acts := &Activities{Store: st}
+	env.RegisterActivity(acts.RecordScanSelectionActivity)
+
+	_, err = env.ExecuteActivity(acts.RecordScanSelectionActivity, scanID, "docs")
+	require.NoError(t, err)
+
+	scan, err := st.GetCandidateScan(scanID)
+	require.NoError(t, err)
+	require.Equal(t, "docs", scan.SelectedID)
+	require.True(t, scan.Candidates[1].Selected)
+
+	// Unpersisted scans are a no-op rather than an error.
+	_, err = env.ExecuteActivity(acts.RecordScanSelectionActivity, int64(0), "docs")
+	require.NoError(t, err)
+}
@@ -72,6 +72,12 @@ func PlanningCeremonyWorkflow(ctx workflow.Context, req PlanningRequest) (*TaskR
 
 		logger.Info("Planning: item selected", "Title", selectedItem.Title)
 
+		if backlog.ScanID != 0 {
+			if err := workflow.ExecuteActivity(ctx, a.RecordScanSelectionActivity, backlog.ScanID, selectedID).Get(ctx, nil); err != nil {
+				logger.Warn("Planning: failed to record scan selection", "ScanID", backlog.ScanID, "error", err)
+			}
+		}
+
 		// ===== PHASE 3: SEQUENTIAL QUESTIONS =====
 		var questions []PlanningQuestion
 		if err := workflow.ExecuteActivity(ctx, a.GenerateQuestionsActivity, req, *selectedItem).Get(ctx, &questions); err != nil {
@@ -134,15 +140,25 @@ func PlanningCeremonyWorkflow(ctx workflow.Context, req PlanningRequest) (*TaskR
 				"Effort", summary.Effort,
 			)
 
-			// Launch execution as a child workflow.
+			// Launch execution as a child workflow on the parent's task queue.
 			// "How do you build a coding elephant? One piece of chum at a time."
 			childOpts := workflow.ChildWorkflowOptions{
 				WorkflowID: fmt.Sprintf("exec-%s-%d", taskReq.BeadID, workflow.Now(ctx).Unix()),
-				TaskQueue:  "cortex-task-queue",
 			}
 			childCtx := workflow.WithChildOptions(ctx, childOpts)
 
-			future := workflow.ExecuteChildWorkflow(childCtx, CortexAgentWorkflow, *taskReq)
+			// Claim the bead first, so no other instance dispatches it too.
+			claimed, err := claimChild(ctx, *taskReq)
+			if err != nil {
+				logger.Warn("Planning: bead not claimed, execution skipped", "BeadID", taskReq.BeadID, "Error", err)
+				return taskReq, fmt.Errorf("planned and greenlit but not dispatched: %w", err)
+			}
+
+			future := workflow.ExecuteChildWorkflow(childCtx, CortexAgentWorkflow, claimed)
+			if err := future.GetChildWorkflowExecution().Get(ctx, nil); err != nil {
+				releaseChildClaim(ctx, claimed)
+				return taskReq, fmt.Errorf("planned and greenlit but execution failed to start: %w", err)
+			}
 
 			logger.Info("Planning: execution workflow launched",
 				"ExecutionWorkflowID", childOpts.WorkflowID,
@@ -0,0 +1,96 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.temporal.io/sdk/activity"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/git"
+)
+
+// PostMergeChecksActivity runs the project's post_merge_checks against the
+// tip of its base branch. A failure is recorded as a post_merge_failed health
+// event; without auto_revert_on_failure it is also posted to the project
+// room, since no revert prompt will follow.
+func (a *Activities) PostMergeChecksActivity(ctx context.Context, req PostMergeRequest) (*PostMergeResult, error) {
+	project, ok := a.Projects[req.Project]
+	if !ok {
+		return nil, fmt.Errorf("unknown project %q", req.Project)
+	}
+	result := &PostMergeResult{Passed: true}
+	if len(project.PostMergeChecks) == 0 {
+		return result, nil
+	}
+	err := withBaseCheckout(project, func(path string) error {
+		res, err := git.RunPostMergeChecksCtx(ctx, path, project.PostMergeChecks)
+		if err != nil {
+			return err
+		}
+		result.Passed, result.Failures = res.Passed, res.Failures
+		return nil
+	})
+	if err != nil || result.Passed {
+		return result, err
+	}
+
+	result.Revert = project.AutoRevertOnFailure && req.Commit != ""
+	msg := fmt.Sprintf("Post-merge checks failed for %s PR #%d (%s): %s",
+		req.Project, req.PR, req.BeadID, strings.Join(result.Failures, "; "))
+	if a.Store != nil {
+		a.Store.RecordHealthEvent("post_merge_failed", msg)
+	}
+	if !result.Revert && a.Sender != nil && project.MatrixRoom != "" {
+		if err := a.Sender.SendMessage(ctx, project.MatrixRoom, msg); err != nil {
+			activity.GetLogger(ctx).Warn("Post-merge failure notice failed", "Project", req.Project, "error", err)
+		}
+	}
+	return result, nil
+}
+
+// RevertMergeActivity reverts the merge commit of req on the project's base
+// branch and pushes the revert. It runs once the revert is approved.
+func (a *Activities) RevertMergeActivity(ctx context.Context, req PostMergeRequest) error {
+	project, ok := a.Projects[req.Project]
+	if !ok {
+		return fmt.Errorf("unknown project %q", req.Project)
+	}
+	if err := withBaseCheckout(project, func(path string) error {
+		return git.RevertOntoBase(path, req.Commit, project.BaseBranch)
+	}); err != nil {
+		return err
+	}
+
+	msg := fmt.Sprintf("Reverted %s PR #%d (%s) after failed post-merge checks: %s",
+		req.Project, req.PR, req.BeadID, req.Commit)
+	if a.Store != nil {
+		a.Store.RecordHealthEvent("post_merge_reverted", msg)
+	}
+	if a.Sender != nil && project.MatrixRoom != "" {
+		if err := a.Sender.SendMessage(ctx, project.MatrixRoom, msg); err != nil {
+			activity.GetLogger(ctx).Warn("Revert notice failed", "Project", req.Project, "error", err)
+		}
+	}
+	return nil
+}
+
+// withBaseCheckout runs fn in a throwaway worktree checked out at the tip of
+// the project's remote base branch, leaving the shared workspace alone.
+func withBaseCheckout(project config.Project, fn func(path string) error) error {
+	workspace := config.ExpandHome(project.Workspace)
+	dir, err := os.MkdirTemp("", "cortex-postmerge-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "checkout")
+	if err := git.CheckoutRemoteBase(workspace, path, project.BaseBranch); err != nil {
+		return err
+	}
+	defer git.RemoveWorktree(workspace, path)
+	return fn(path)
+}
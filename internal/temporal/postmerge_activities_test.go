@@ -0,0 +1,127 @@
+package temporal
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+// mergedRepo returns a workspace whose origin holds a base branch with a
+// merged commit adding broken.go, and that commit's SHA.
+func mergedRepo(t *testing.T) (workspace, remote, commit string) {
+	t.Helper()
+	workspace, remote = t.TempDir(), t.TempDir()
+	gitRun := func(dir string, args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		return strings.TrimSpace(string(out))
+	}
+	gitRun(remote, "init", "--bare")
+	gitRun(workspace, "init", "-b", "master")
+	gitRun(workspace, "config", "user.email", "test@example.com")
+	gitRun(workspace, "config", "user.name", "Test User")
+	gitRun(workspace, "remote", "add", "origin", remote)
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "README.md"), []byte("# repo\n"), 0o644))
+	gitRun(workspace, "add", "README.md")
+	gitRun(workspace, "commit", "-m", "init")
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "broken.go"), []byte("package broken\n"), 0o644))
+	gitRun(workspace, "add", "broken.go")
+	gitRun(workspace, "commit", "-m", "merged work")
+	gitRun(workspace, "push", "origin", "master")
+	return workspace, remote, gitRun(workspace, "rev-parse", "HEAD")
+}
+
+func TestPostMergeChecksAndRevert(t *testing.T) {
+	workspace, remote, commit := mergedRepo(t)
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+	sender := &recordingSender{}
+
+	acts := &Activities{Store: st, Sender: sender, Projects: map[string]config.Project{
+		"cortex": {
+			Workspace: workspace, BaseBranch: "master", MatrixRoom: "!room",
+			PostMergeChecks: []string{"test ! -e broken.go"}, AutoRevertOnFailure: true,
+		},
+	}}
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(acts.PostMergeChecksActivity)
+	env.RegisterActivity(acts.RevertMergeActivity)
+
+	req := PostMergeRequest{Project: "cortex", BeadID: "cx-9", PR: 17, Commit: commit}
+	val, err := env.ExecuteActivity(acts.PostMergeChecksActivity, req)
+	require.NoError(t, err)
+	var result PostMergeResult
+	require.NoError(t, val.Get(&result))
+	require.False(t, result.Passed)
+	require.True(t, result.Revert)
+	require.Empty(t, sender.messages, "the revert prompt reports the failure")
+
+	_, err = env.ExecuteActivity(acts.RevertMergeActivity, req)
+	require.NoError(t, err)
+	out, err := exec.Command("git", "-C", remote, "log", "-1", "--format=%s", "master").CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.Equal(t, `Revert "merged work"`, strings.TrimSpace(string(out)))
+	require.Len(t, sender.messages, 1)
+	require.Contains(t, sender.messages[0], "Reverted cortex PR #17")
+
+	events, err := st.GetRecentHealthEvents(1)
+	require.NoError(t, err)
+	var types []string
+	for _, e := range events {
+		types = append(types, e.EventType)
+	}
+	require.ElementsMatch(t, []string{"post_merge_failed", "post_merge_reverted"}, types)
+
+	val, err = env.ExecuteActivity(acts.PostMergeChecksActivity, req)
+	require.NoError(t, err)
+	require.NoError(t, val.Get(&result))
+	require.True(t, result.Passed, "the checks pass once the merge is reverted")
+}
+
+func TestPostMergeWorkflowRevertsOnlyWhenApproved(t *testing.T) {
+	for _, signal := range []string{"APPROVED", "REJECTED"} {
+		t.Run(signal, func(t *testing.T) {
+			s := testsuite.WorkflowTestSuite{}
+			env := s.NewTestWorkflowEnvironment()
+			var a *Activities
+			env.OnActivity(a.PostMergeChecksActivity, mock.Anything, mock.Anything).Return(&PostMergeResult{
+				Failures: []string{"Command failed: go test ./... (exit 1)"}, Revert: true,
+			}, nil)
+			var prompt ApprovalPrompt
+			env.OnActivity(a.RequestApprovalActivity, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				prompt = args.Get(1).(ApprovalPrompt)
+			}).Return(&ApprovalTicket{}, nil)
+			env.OnActivity(a.RevertMergeActivity, mock.Anything, mock.Anything).Return(nil).Maybe()
+			env.RegisterDelayedCallback(func() { env.SignalWorkflow("human-approval", signal) }, time.Second)
+
+			env.ExecuteWorkflow(PostMergeWorkflow, PostMergeRequest{Project: "cortex", BeadID: "cx-9", PR: 17, Commit: "abc123"})
+
+			require.True(t, env.IsWorkflowCompleted())
+			require.NoError(t, env.GetWorkflowError())
+			require.Equal(t, "revert", prompt.Kind)
+			require.Contains(t, prompt.Summary, "revert merge commit abc123")
+			var result PostMergeResult
+			require.NoError(t, env.GetWorkflowResult(&result))
+			require.Equal(t, signal == "APPROVED", result.Reverted)
+			if signal == "APPROVED" {
+				env.AssertActivityCalled(t, "RevertMergeActivity", mock.Anything, mock.Anything)
+			} else {
+				env.AssertActivityNotCalled(t, "RevertMergeActivity", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package temporal
+
+import (
+	"context"
+	"time"
+
+	"go.temporal.io/sdk/activity"
+
+	"github.com/antigravity-dev/cortex/internal/beads"
+	"github.com/antigravity-dev/cortex/internal/git"
+)
+
+// CreatePRActivity opens a pull request for a bead's branch. The body is a
+// changelog-style summary of the branch diff, DoD results and acceptance
+// criteria written by a fast-tier agent; if the bead, diff or summary are
+// unavailable it falls back to the templated bead fields. The branch is
+// pushed first; if it already has an open PR, the push updates that PR and it
+// is returned instead of opening another. With draft_prs the PR opens as a
+// draft.
+func (a *Activities) CreatePRActivity(ctx context.Context, req CreatePRRequest) (*CreatePRResult, error) {
+	logger := activity.GetLogger(ctx)
+	if req.BaseBranch == "" {
+		req.BaseBranch = a.Projects[req.Project].BaseBranch
+	}
+	req.Draft = req.Draft || a.Git.DraftPRs
+
+	if err := git.PushBranch(req.WorkDir, req.Branch); err != nil {
+		return nil, err
+	}
+	existing, err := git.GetPRStatus(req.WorkDir, req.Branch)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.State == "OPEN" {
+		logger.Info("Branch already has an open PR", "BeadID", req.BeadID, "PR", existing.Number)
+		return &CreatePRResult{URL: existing.URL, Number: existing.Number}, nil
+	}
+
+	pr := git.PRDescription{BeadID: req.BeadID, Title: req.BeadID, Draft: req.Draft}
+	if bead, err := beads.ShowBeadCtx(ctx, resolveBeadsDir(req.WorkDir), req.BeadID); err != nil {
+		logger.Warn("PR description: bead lookup failed", "BeadID", req.BeadID, "error", err)
+	} else {
+		pr.Title, pr.Description, pr.Acceptance = bead.Title, bead.Description, bead.Acceptance
+	}
+	if diff, err := git.GetBranchDiff(req.WorkDir, req.BaseBranch, req.Branch); err != nil {
+		logger.Warn("PR description: diff failed", "Branch", req.Branch, "error", err)
+	} else {
+		pr.Diff = diff
+	}
+	for _, c := range req.Checks {
+		pr.Checks = append(pr.Checks, git.CheckResult{
+			Command:  c.Command,
+			ExitCode: c.ExitCode,
+			Passed:   c.Passed,
+			Duration: time.Duration(c.DurationMs) * time.Millisecond,
+		})
+	}
+
+	url, number, err := git.CreateDescribedPR(req.WorkDir, req.Branch, req.BaseBranch, pr, a.prSummarizer(ctx, req.WorkDir))
+	if err != nil {
+		return nil, err
+	}
+	return &CreatePRResult{URL: url, Number: number}, nil
+}
+
+// prSummarizer writes PR descriptions with the fast tier's agent.
+func (a *Activities) prSummarizer(ctx context.Context, workDir string) git.Summarizer {
+	agent := ResolveTierAgent(a.Tiers, "fast")
+	return func(prompt string) (string, error) {
+		result, err := a.runAgent(ctx, agent, prompt, workDir)
+		if err != nil {
+			activity.GetLogger(ctx).Warn("PR description: summary failed, using template", "Agent", agent, "error", err)
+			return "", err
+		}
+		return result.Output, nil
+	}
+}
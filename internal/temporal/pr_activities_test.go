@@ -0,0 +1,111 @@
+package temporal
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+func TestCreatePRActivity(t *testing.T) {
+	workDir := t.TempDir()
+	gitRun := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = workDir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	remote := t.TempDir()
+	require.NoError(t, exec.Command("git", "init", "--bare", remote).Run())
+	gitRun("init", "-b", "master")
+	gitRun("remote", "add", "origin", remote)
+	gitRun("config", "user.email", "test@example.com")
+	gitRun("config", "user.name", "Test User")
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "README.md"), []byte("# repo\n"), 0o644))
+	gitRun("add", "README.md")
+	gitRun("commit", "-m", "init")
+	gitRun("checkout", "-b", "feat/cx-9")
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "hello.go"), []byte("package hello\n"), 0o644))
+	gitRun("add", "hello.go")
+	gitRun("commit", "-m", "add hello")
+
+	fakeBin := t.TempDir()
+	bodyPath := filepath.Join(workDir, "body.md")
+	promptPath := filepath.Join(workDir, "prompt.txt")
+	scripts := map[string]string{
+		"bd":    "#!/bin/sh\necho '{\"id\":\"cx-9\",\"title\":\"Say hello\",\"description\":\"Add a hello package.\",\"acceptance_criteria\":\"hello.go exists\"}'\n",
+		"gh":    "#!/bin/sh\nif [ \"$2\" = view ]; then echo 'no pull requests found for branch' >&2; exit 1; fi\nprintf '%s' \"${10}\" > \"$GH_BODY\"\necho \"$@\" > \"$GH_BODY.args\"\necho https://github.com/o/r/pull/17\n",
+		"codex": "#!/bin/sh\nprintf '%s' \"$3\" > \"$AGENT_PROMPT\"\nprintf '## Summary\\n\\nAdds the hello package.\\n'\n",
+	}
+	for name, script := range scripts {
+		require.NoError(t, os.WriteFile(filepath.Join(fakeBin, name), []byte(script), 0o755))
+	}
+	t.Setenv("GH_BODY", bodyPath)
+	t.Setenv("AGENT_PROMPT", promptPath)
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	acts := &Activities{Git: config.DispatchGit{DraftPRs: true}}
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(acts.CreatePRActivity)
+	val, err := env.ExecuteActivity(acts.CreatePRActivity, CreatePRRequest{
+		BeadID: "cx-9", Project: "cortex", WorkDir: workDir, Branch: "feat/cx-9", BaseBranch: "master",
+		Checks: []CheckResult{{Command: "go test ./...", Passed: true}},
+	})
+	require.NoError(t, err)
+	var res CreatePRResult
+	require.NoError(t, val.Get(&res))
+	require.Equal(t, CreatePRResult{URL: "https://github.com/o/r/pull/17", Number: 17}, res)
+	args, err := os.ReadFile(bodyPath + ".args")
+	require.NoError(t, err)
+	require.Contains(t, string(args), "--draft")
+	pushed, err := exec.Command("git", "--git-dir", remote, "rev-parse", "--verify", "refs/heads/feat/cx-9").CombinedOutput()
+	require.NoError(t, err, string(pushed))
+
+	prompt, err := os.ReadFile(promptPath)
+	require.NoError(t, err)
+	require.Contains(t, string(prompt), "hello.go exists")
+	require.Contains(t, string(prompt), "+package hello")
+	require.Contains(t, string(prompt), "- go test ./...: passed")
+
+	body, err := os.ReadFile(bodyPath)
+	require.NoError(t, err)
+	require.Equal(t, "## Summary\n\nAdds the hello package.\n\n## Checks\n\n- `go test ./...`: passed\n\nBead: cx-9\n", string(body))
+}
+
+func TestCreatePRActivityReusesOpenPR(t *testing.T) {
+	workDir := t.TempDir()
+	remote := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--bare", remote},
+		{"-C", workDir, "init", "-b", "master"},
+		{"-C", workDir, "remote", "add", "origin", remote},
+		{"-C", workDir, "-c", "user.email=t@example.com", "-c", "user.name=T", "commit", "--allow-empty", "-m", "init"},
+		{"-C", workDir, "checkout", "-b", "feat/cx-9"},
+	} {
+		out, err := exec.Command("git", args...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	fakeBin := t.TempDir()
+	gh := "#!/bin/sh\nif [ \"$2\" = view ]; then echo '{\"number\":17,\"url\":\"https://github.com/o/r/pull/17\",\"state\":\"OPEN\"}'; exit 0; fi\necho unexpected gh \"$@\" >&2\nexit 1\n"
+	require.NoError(t, os.WriteFile(filepath.Join(fakeBin, "gh"), []byte(gh), 0o755))
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	acts := &Activities{}
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(acts.CreatePRActivity)
+	val, err := env.ExecuteActivity(acts.CreatePRActivity, CreatePRRequest{
+		BeadID: "cx-9", Project: "cortex", WorkDir: workDir, Branch: "feat/cx-9", BaseBranch: "master",
+	})
+	require.NoError(t, err)
+	var res CreatePRResult
+	require.NoError(t, val.Get(&res))
+	require.Equal(t, CreatePRResult{URL: "https://github.com/o/r/pull/17", Number: 17}, res)
+}
@@ -0,0 +1,111 @@
+package temporal
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"go.temporal.io/sdk/activity"
+
+	"github.com/antigravity-dev/cortex/internal/beads"
+	"github.com/antigravity-dev/cortex/internal/git"
+)
+
+// maxPromptDiffBytes caps the diff exposed to review templates.
+const maxPromptDiffBytes = 30000
+
+// PromptData is the context available to project prompt templates.
+// Default holds the built-in prompt so a template can wrap rather than
+// replace it: {{.Default}}.
+type PromptData struct {
+	Stage       string
+	Role        string
+	Project     string
+	Agent       string
+	Reviewer    string
+	Task        string
+	Bead        beads.Bead
+	Plan        StructuredPlan
+	AgentOutput string
+	Diff        string
+	Attachments string
+	Default     string
+}
+
+var promptTemplateFuncs = template.FuncMap{
+	"join":     strings.Join,
+	"truncate": truncate,
+	"trim":     strings.TrimSpace,
+}
+
+// renderPromptTemplate executes the template file at path with data.
+func renderPromptTemplate(path string, data PromptData) (string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read prompt template: %w", err)
+	}
+	tmpl, err := template.New(filepath.Base(path)).Funcs(promptTemplateFuncs).Option("missingkey=error").Parse(string(raw))
+	if err != nil {
+		return "", fmt.Errorf("parse prompt template %s: %w", path, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render prompt template %s: %w", path, err)
+	}
+	return buf.String(), nil
+}
+
+// buildPrompt returns the templated prompt for role when an experiment variant
+// or the project configures one, otherwise data.Default. A custom role working
+// the bead replaces the coder prompt with its own template. A broken template
+// never blocks a dispatch: it is logged and the built-in prompt is used instead.
+func (a *Activities) buildPrompt(ctx context.Context, role string, req TaskRequest, data PromptData) string {
+	proj := a.Projects[req.Project]
+	path := proj.Prompts.ForRole(role)
+	if custom, ok := proj.Roles[req.Role]; ok && role == "coder" && custom.Prompt != "" {
+		role, path = req.Role, custom.Prompt
+	}
+	for _, exp := range req.Experiments {
+		if exp.Role == role && exp.Prompt != "" {
+			path = exp.Prompt
+		}
+	}
+	if path == "" {
+		return data.Default
+	}
+
+	data.Role = role
+	data.Project = req.Project
+	data.Task = req.Prompt
+	data.Bead = loadPromptBead(ctx, req)
+	if role == "reviewer" && data.Diff == "" && req.WorkDir != "" {
+		if diff, err := git.GetWorkingTreeDiff(req.WorkDir); err == nil {
+			data.Diff = git.TruncateDiff(diff, maxPromptDiffBytes)
+		}
+	}
+
+	prompt, err := renderPromptTemplate(path, data)
+	if err != nil {
+		activity.GetLogger(ctx).Warn("Prompt template failed, using built-in prompt", "Role", role, "Project", req.Project, "error", err)
+		return data.Default
+	}
+	return prompt
+}
+
+// loadPromptBead fetches the bead for template rendering, falling back to
+// just the ID when bd is unavailable.
+func loadPromptBead(ctx context.Context, req TaskRequest) beads.Bead {
+	bead := beads.Bead{ID: req.BeadID}
+	if req.BeadID == "" || req.WorkDir == "" {
+		return bead
+	}
+	detail, err := beads.ShowBeadCtx(ctx, resolveBeadsDir(req.sharedWorkspace()), req.BeadID)
+	if err != nil || detail == nil {
+		return bead
+	}
+	return detail.Bead
+}
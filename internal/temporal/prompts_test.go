@@ -0,0 +1,95 @@
+package temporal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+func writePromptTemplate(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "prompt.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte(body), 0o644))
+	return path
+}
+
+func TestRenderPromptTemplate(t *testing.T) {
+	path := writePromptTemplate(t, `{{.Stage}}/{{.Role}} for {{.Bead.ID}}: {{.Plan.Summary}}
+{{join .Plan.AcceptanceCriteria "; "}}
+{{.Default}}`)
+
+	out, err := renderPromptTemplate(path, PromptData{
+		Stage:   "execute",
+		Role:    "coder",
+		Plan:    StructuredPlan{Summary: "add flag", AcceptanceCriteria: []string{"builds", "tested"}},
+		Default: "BUILT-IN",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "execute/coder for : add flag\nbuilds; tested\nBUILT-IN", out)
+}
+
+func TestRenderPromptTemplateErrors(t *testing.T) {
+	_, err := renderPromptTemplate(filepath.Join(t.TempDir(), "missing.tmpl"), PromptData{})
+	require.Error(t, err)
+
+	_, err = renderPromptTemplate(writePromptTemplate(t, "{{.Nope}}"), PromptData{})
+	require.ErrorContains(t, err, "render prompt template")
+}
+
+func TestBuildPromptUsesProjectTemplate(t *testing.T) {
+	path := writePromptTemplate(t, "[{{.Project}}] {{.Task}} by {{.Reviewer}}")
+	acts := &Activities{Projects: map[string]config.Project{
+		"cortex": {Prompts: config.PromptTemplates{Reviewer: path}},
+	}}
+	req := TaskRequest{Project: "cortex", Prompt: "fix the bug"}
+
+	got := acts.buildPrompt(context.Background(), "reviewer", req, PromptData{Reviewer: "codex", Diff: "-", Default: "built-in"})
+	require.Equal(t, "[cortex] fix the bug by codex", got)
+
+	got = acts.buildPrompt(context.Background(), "coder", req, PromptData{Default: "built-in"})
+	require.Equal(t, "built-in", got, "roles without a template keep the built-in prompt")
+
+	got = acts.buildPrompt(context.Background(), "reviewer", TaskRequest{Project: "other"}, PromptData{Default: "built-in"})
+	require.Equal(t, "built-in", got, "projects without templates keep the built-in prompt")
+}
+
+func TestBuildPromptPrefersExperimentVariant(t *testing.T) {
+	projectTmpl := writePromptTemplate(t, "project")
+	variantTmpl := writePromptTemplate(t, "variant {{.Stage}}")
+	acts := &Activities{Projects: map[string]config.Project{
+		"cortex": {Prompts: config.PromptTemplates{Coder: projectTmpl}},
+	}}
+	req := TaskRequest{Project: "cortex", Experiments: []ExperimentAssignment{
+		{Experiment: "exec-prompt", Variant: "terse", Role: "coder", Prompt: variantTmpl},
+	}}
+
+	got := acts.buildPrompt(context.Background(), "coder", req, PromptData{Stage: "execute", Default: "built-in"})
+	require.Equal(t, "variant execute", got)
+}
+
+func TestBuildPromptUsesCustomRoleTemplate(t *testing.T) {
+	coderTmpl := writePromptTemplate(t, "coder")
+	roleTmpl := writePromptTemplate(t, "{{.Role}}: {{.Task}}")
+	acts := &Activities{Projects: map[string]config.Project{
+		"cortex": {
+			Prompts: config.PromptTemplates{Coder: coderTmpl},
+			Roles:   map[string]config.RoleConfig{"security-auditor": {Prompt: roleTmpl}},
+		},
+	}}
+	req := TaskRequest{Project: "cortex", Prompt: "audit the login flow", Role: "security-auditor"}
+
+	got := acts.buildPrompt(context.Background(), "coder", req, PromptData{Default: "built-in"})
+	require.Equal(t, "security-auditor: audit the login flow", got)
+
+	got = acts.buildPrompt(context.Background(), "planner", req, PromptData{Default: "built-in"})
+	require.Equal(t, "built-in", got, "the role only replaces the coder prompt")
+
+	req.Role = "doc-writer"
+	got = acts.buildPrompt(context.Background(), "coder", req, PromptData{Default: "built-in"})
+	require.Equal(t, "coder", got, "an unregistered role keeps the project's coder prompt")
+}
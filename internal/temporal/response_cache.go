@@ -0,0 +1,52 @@
+package temporal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strings"
+
+	"go.temporal.io/sdk/activity"
+)
+
+// runCachedAgent is runAgent for the planning roles in
+// dispatch.response_cache: an identical prompt to the same agent and model in
+// the same workspace within the TTL is answered from the cache instead of
+// running the agent again. A cached answer carries no token usage, so it is
+// billed at $0. A fresh answer is only cached when the caller calls keep,
+// once it has parsed the answer, so an answer it could not use is asked again.
+func (a *Activities) runCachedAgent(ctx context.Context, role, agent, provider, prompt, workDir string) (result CLIResult, keep func(), err error) {
+	keep = func() {}
+	rc := a.ResponseCache
+	if !rc.Enabled || a.Store == nil || !slices.Contains(rc.Roles, role) {
+		result, err = a.runAgent(ctx, agent, prompt, workDir)
+		return result, keep, err
+	}
+
+	logger := activity.GetLogger(ctx)
+	p, _ := a.usageProvider(agent, provider)
+	key := responseCacheKey(agent, p.Model, workDir, prompt)
+	cached, hit, err := a.Store.GetCachedResponse(key, rc.TTL.Duration)
+	if err != nil {
+		logger.Warn("Response cache lookup failed", "Role", role, "error", err)
+	} else if hit {
+		logger.Info("Response cache hit", "Role", role, "Agent", agent, "Hits", cached.Hits)
+		return CLIResult{Output: cached.Output}, keep, nil
+	}
+
+	result, err = a.runAgent(ctx, agent, prompt, workDir)
+	if err == nil && strings.TrimSpace(result.Output) != "" {
+		keep = func() {
+			if putErr := a.Store.PutCachedResponse(key, agent, role, result.Output, rc.TTL.Duration); putErr != nil {
+				logger.Warn("Response cache store failed", "Role", role, "error", putErr)
+			}
+		}
+	}
+	return result, keep, err
+}
+
+func responseCacheKey(agent, model, workDir, prompt string) string {
+	sum := sha256.Sum256([]byte(agent + "\x00" + model + "\x00" + workDir + "\x00" + prompt))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,107 @@
+package temporal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestGroomBacklogActivityUsesResponseCache(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer st.Close()
+
+	workDir := t.TempDir()
+	calls := filepath.Join(workDir, "calls.log")
+	fakeBin := t.TempDir()
+	script := "#!/bin/sh\necho run >> \"$AGENT_CALLS\"\n" +
+		"echo '{\"items\":[{\"id\":\"a\",\"title\":\"Do A\",\"recommended\":true}],\"rationale\":\"because\"}'\n"
+	require.NoError(t, os.WriteFile(filepath.Join(fakeBin, "codex"), []byte(script), 0o755))
+	t.Setenv("AGENT_CALLS", calls)
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	groom := func(acts *Activities, project string) {
+		s := testsuite.WorkflowTestSuite{}
+		env := s.NewTestActivityEnvironment()
+		env.RegisterActivity(acts.GroomBacklogActivity)
+		val, err := env.ExecuteActivity(acts.GroomBacklogActivity, PlanningRequest{Project: project, WorkDir: workDir})
+		require.NoError(t, err)
+		var backlog BacklogPresentation
+		require.NoError(t, val.Get(&backlog))
+		require.Equal(t, "Do A", backlog.Items[0].Title)
+	}
+	runs := func() int {
+		data, _ := os.ReadFile(calls)
+		return strings.Count(string(data), "run")
+	}
+
+	cached := &Activities{Store: st, ResponseCache: config.DispatchResponseCache{
+		Enabled: true, TTL: config.Duration{Duration: time.Hour}, Roles: []string{"chief"},
+	}}
+	groom(cached, "cortex")
+	groom(cached, "cortex")
+	require.Equal(t, 1, runs(), "identical prompt should be answered from the cache")
+	groom(cached, "other")
+	require.Equal(t, 2, runs(), "a different prompt is not a hit")
+
+	// Roles outside the configured list always run the agent.
+	uncached := &Activities{Store: st, ResponseCache: config.DispatchResponseCache{
+		Enabled: true, TTL: config.Duration{Duration: time.Hour}, Roles: []string{"planner"},
+	}}
+	groom(uncached, "cortex")
+	require.Equal(t, 3, runs())
+}
+
+func TestResponseCacheKeepsOnlyParsedAnswers(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer st.Close()
+
+	workDir := t.TempDir()
+	calls := filepath.Join(workDir, "calls.log")
+	answer := filepath.Join(workDir, "answer.json")
+	fakeBin := t.TempDir()
+	script := "#!/bin/sh\necho run >> \"$AGENT_CALLS\"\ncat \"$AGENT_ANSWER\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(fakeBin, "codex"), []byte(script), 0o755))
+	t.Setenv("AGENT_CALLS", calls)
+	t.Setenv("AGENT_ANSWER", answer)
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+	runs := func() int {
+		data, _ := os.ReadFile(calls)
+		return strings.Count(string(data), "run")
+	}
+
+	acts := &Activities{
+		Store:     st,
+		Providers: map[string]config.Provider{"codex": {Model: "gpt-5"}},
+		ResponseCache: config.DispatchResponseCache{
+			Enabled: true, TTL: config.Duration{Duration: time.Hour}, Roles: []string{"chief"},
+		},
+	}
+	groom := func() error {
+		env := (&testsuite.WorkflowTestSuite{}).NewTestActivityEnvironment()
+		env.RegisterActivity(acts.GroomBacklogActivity)
+		_, err := env.ExecuteActivity(acts.GroomBacklogActivity, PlanningRequest{Project: "cortex", WorkDir: workDir})
+		return err
+	}
+
+	require.NoError(t, os.WriteFile(answer, []byte("I could not decide.\n"), 0o644))
+	require.Error(t, groom())
+	require.NoError(t, os.WriteFile(answer, []byte(`{"items":[{"id":"a","title":"Do A"}]}`+"\n"), 0o644))
+	require.NoError(t, groom())
+	require.Equal(t, 2, runs(), "an answer that failed to parse was served from the cache")
+	require.NoError(t, groom())
+	require.Equal(t, 2, runs(), "a parsed answer is reused")
+
+	acts.Providers["codex"] = config.Provider{Model: "gpt-5-mini"}
+	require.NoError(t, groom())
+	require.Equal(t, 3, runs(), "another model's answer was reused")
+}
@@ -0,0 +1,51 @@
+package temporal
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// resultFence opens the completion block agents end their output with.
+const resultFence = "```cortex-result"
+
+// resultContract is the prompt contract appended to every coding prompt,
+// including project templates, so the outcome can be read from the output
+// without guessing at free-form text.
+const resultContract = `
+
+When you are finished, end your response with this completion block, filled in, and nothing after it:
+` + resultFence + `
+{"files_changed": ["path/to/file.go"], "tests_run": [{"command": "go test ./...", "passed": true}], "confidence": 0.8, "follow_ups": ["work you found but did not do"]}
+` + "```" + `
+files_changed lists every file you changed; tests_run every test command you ran and whether it passed; confidence (0.0 to 1.0) how confident you are that the change is correct, complete and meets the acceptance criteria; follow_ups any work you leave for later, or [].`
+
+// parseAgentReport extracts the last completion block from agent output. It
+// returns nil when there is none or it is not valid JSON; a confidence
+// outside 0-1 is dropped.
+func parseAgentReport(output string) *AgentReport {
+	idx := strings.LastIndex(output, resultFence)
+	if idx < 0 {
+		return nil
+	}
+	body := output[idx+len(resultFence):]
+	if end := strings.Index(body, "```"); end >= 0 {
+		body = body[:end]
+	}
+	var report AgentReport
+	if err := json.Unmarshal([]byte(strings.TrimSpace(body)), &report); err != nil {
+		return nil
+	}
+	if c := report.Confidence; c != nil && (*c < 0 || *c > 1) {
+		report.Confidence = nil
+	}
+	return &report
+}
+
+// agentConfidence is the confidence from the completion block, or from a
+// CONFIDENCE line for agents that ignore the contract.
+func agentConfidence(output string, report *AgentReport) Confidence {
+	if report != nil && report.Confidence != nil {
+		return Confidence{Score: *report.Confidence, Reported: true}
+	}
+	return parseConfidence(output)
+}
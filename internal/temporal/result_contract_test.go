@@ -0,0 +1,100 @@
+package temporal
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestParseAgentReport(t *testing.T) {
+	output := "Implemented the endpoint.\n\n" + resultFence + `
+{"files_changed": ["api.go", "api_test.go"], "tests_run": [{"command": "go test ./api", "passed": true}], "confidence": 0.85, "follow_ups": ["add rate limiting"]}
+` + "```\n"
+	report := parseAgentReport(output)
+	require.NotNil(t, report)
+	require.Equal(t, []string{"api.go", "api_test.go"}, report.FilesChanged)
+	require.Equal(t, []TestRun{{Command: "go test ./api", Passed: true}}, report.TestsRun)
+	require.Equal(t, []string{"add rate limiting"}, report.FollowUps)
+	require.Equal(t, Confidence{Score: 0.85, Reported: true}, agentConfidence(output, report))
+
+	// The last block wins, and the prompt's own example is not mistaken for it.
+	twice := resultContract + "\n" + resultFence + "\n{\"files_changed\": [], \"confidence\": 0.3}\n```"
+	require.Equal(t, Confidence{Score: 0.3, Reported: true}, agentConfidence(twice, parseAgentReport(twice)))
+
+	// Without a usable block the CONFIDENCE footer still counts.
+	for _, output := range []string{
+		"done\nCONFIDENCE: 0.6",
+		"done\n" + resultFence + "\nnot json\n```\nCONFIDENCE: 0.6",
+	} {
+		report := parseAgentReport(output)
+		require.Nil(t, report, "output %q", output)
+		require.Equal(t, Confidence{Score: 0.6, Reported: true}, agentConfidence(output, report))
+	}
+
+	// An out-of-range confidence is dropped but the rest of the block kept.
+	report = parseAgentReport(resultFence + "\n{\"files_changed\": [\"a.go\"], \"confidence\": 80}\n```")
+	require.NotNil(t, report)
+	require.Nil(t, report.Confidence)
+	require.Equal(t, []string{"a.go"}, report.FilesChanged)
+}
+
+func TestRecordOutcomeStoresAgentReport(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+
+	acts := &Activities{Store: st}
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(acts.RecordOutcomeActivity)
+
+	confidence := 0.9
+	_, err = env.ExecuteActivity(acts.RecordOutcomeActivity, OutcomeRecord{
+		BeadID: "cortex-9", Project: "cortex", Agent: "claude", Status: "completed", DoDPassed: true,
+		Report: &AgentReport{
+			FilesChanged: []string{"main.go"},
+			TestsRun:     []TestRun{{Command: "go test ./...", Passed: true}},
+			Confidence:   &confidence,
+			FollowUps:    []string{"clean up flags"},
+		},
+	})
+	require.NoError(t, err)
+
+	dispatches, err := st.GetDispatchesByBead("cortex-9")
+	require.NoError(t, err)
+	require.Len(t, dispatches, 1)
+	report, err := st.GetDispatchReport(dispatches[0].ID)
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Equal(t, "claude", report.Agent)
+	require.Equal(t, []string{"main.go"}, report.FilesChanged)
+	require.Equal(t, []store.ReportedTest{{Command: "go test ./...", Passed: true}}, report.TestsRun)
+	require.Equal(t, 0.9, *report.Confidence)
+	require.Equal(t, []string{"clean up flags"}, report.FollowUps)
+}
+
+func TestRecordOutcomeStoresPR(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+
+	acts := &Activities{Store: st}
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(acts.RecordOutcomeActivity)
+
+	_, err = env.ExecuteActivity(acts.RecordOutcomeActivity, OutcomeRecord{
+		BeadID: "cortex-9", Project: "cortex", Agent: "claude", Status: "completed", DoDPassed: true,
+		PR: &CreatePRResult{URL: "https://github.com/o/r/pull/17", Number: 17},
+	})
+	require.NoError(t, err)
+
+	number, url, err := st.GetLatestBeadPR("cortex", "cortex-9")
+	require.NoError(t, err)
+	require.Equal(t, 17, number)
+	require.Equal(t, "https://github.com/o/r/pull/17", url)
+}
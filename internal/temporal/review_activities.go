@@ -0,0 +1,135 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/activity"
+
+	"github.com/antigravity-dev/cortex/internal/git"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+// CheckStalledReviewsActivity walks Cortex-opened PRs still awaiting their
+// first review. Reviewed or closed PRs are recorded (feeding review latency
+// metrics); PRs past the threshold get a room nudge and, when enabled, a
+// review from a different agent posted as a PR comment.
+func (a *Activities) CheckStalledReviewsActivity(ctx context.Context, req StalledReviewRequest) (*StalledReviewResult, error) {
+	logger := activity.GetLogger(ctx)
+	result := &StalledReviewResult{}
+	if a.Store == nil {
+		return result, nil
+	}
+
+	pending, err := a.Store.ListPendingPRReviews()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	for _, review := range pending {
+		project, ok := req.Projects[review.Project]
+		if !ok || project.Workspace == "" {
+			continue
+		}
+		result.Checked++
+
+		pr, err := git.GetPRReviewActivity(project.Workspace, review.PRNumber)
+		if err != nil {
+			logger.Warn("Stalled review: PR lookup failed", "Project", review.Project, "PR", review.PRNumber, "error", err)
+			continue
+		}
+		if !pr.FirstReviewAt.IsZero() {
+			if err := a.Store.RecordPRFirstReview(review.ID, pr.FirstReviewAt); err != nil {
+				logger.Warn("Stalled review: record first review failed", "PR", review.PRNumber, "error", err)
+			}
+			result.Reviewed++
+			continue
+		}
+		if pr.State != "" && pr.State != "OPEN" {
+			if err := a.Store.ClosePRReview(review.ID, now); err != nil {
+				logger.Warn("Stalled review: close failed", "PR", review.PRNumber, "error", err)
+			}
+			result.Closed++
+			continue
+		}
+		if !reviewNeedsNudge(review, now, req.Threshold) {
+			continue
+		}
+
+		stalled := StalledReview{
+			Project:  review.Project,
+			BeadID:   review.BeadID,
+			PRNumber: review.PRNumber,
+			PRURL:    review.PRURL,
+			WaitingH: now.Sub(review.OpenedAt).Hours(),
+			Reviewer: nextReviewer(review),
+		}
+		// Only the first nudge hands the PR to another agent; later nudges
+		// are reminders so a stuck PR doesn't burn a review every interval.
+		if req.Reassign && review.Nudges == 0 {
+			if err := a.reassignReview(ctx, project.Workspace, review, stalled.Reviewer); err != nil {
+				logger.Warn("Stalled review: reassignment failed", "PR", review.PRNumber, "Reviewer", stalled.Reviewer, "error", err)
+			} else {
+				stalled.Reassigned = true
+			}
+		}
+		if a.Sender != nil && project.Room != "" {
+			if err := a.Sender.SendMessage(ctx, project.Room, stalledReviewMessage(stalled)); err != nil {
+				logger.Warn("Stalled review: nudge failed", "Room", project.Room, "error", err)
+			}
+		}
+		if err := a.Store.RecordPRReviewNudge(review.ID, stalled.Reviewer, now); err != nil {
+			logger.Warn("Stalled review: record nudge failed", "PR", review.PRNumber, "error", err)
+		}
+		result.Stalled = append(result.Stalled, stalled)
+	}
+	return result, nil
+}
+
+// reviewNeedsNudge reports whether a pending PR has waited past threshold
+// since it opened and since the last nudge.
+func reviewNeedsNudge(review store.PRReview, now time.Time, threshold time.Duration) bool {
+	if threshold <= 0 || now.Sub(review.OpenedAt) < threshold {
+		return false
+	}
+	return !review.LastNudgeAt.Valid || now.Sub(review.LastNudgeAt.Time) >= threshold
+}
+
+// nextReviewer picks a reviewer agent different from the last one assigned
+// (or from the implementing agent when none was).
+func nextReviewer(review store.PRReview) string {
+	if review.Reviewer != "" {
+		return DefaultReviewer(review.Reviewer)
+	}
+	return DefaultReviewer(review.Agent)
+}
+
+func (a *Activities) reassignReview(ctx context.Context, workspace string, review store.PRReview, reviewer string) error {
+	diff, err := git.GetPRDiff(workspace, review.PRNumber)
+	if err != nil {
+		return err
+	}
+	prompt := fmt.Sprintf(`You are a senior code reviewer. Pull request #%d for task %s has been waiting for review.
+Review the diff below. List concrete issues (bugs, missing error handling, untested paths, security problems) and say whether it is ready to merge.
+
+DIFF:
+%s`, review.PRNumber, review.BeadID, git.TruncateDiff(diff, maxPromptDiffBytes))
+
+	cliResult, err := a.runReviewAgent(ctx, reviewer, prompt, workspace)
+	if err != nil {
+		return err
+	}
+	body := fmt.Sprintf("Automated review by %s (PR waited without review):\n\n%s", reviewer, cliResult.Output)
+	return git.CommentOnPR(workspace, review.PRNumber, body)
+}
+
+func stalledReviewMessage(s StalledReview) string {
+	msg := fmt.Sprintf("Review nudge: PR #%d for %s (%s) has waited %.0fh without review. %s",
+		s.PRNumber, s.BeadID, s.Project, s.WaitingH, s.PRURL)
+	if s.Reassigned {
+		msg += fmt.Sprintf("\n%s posted an automated review; a human approval is still needed.", s.Reviewer)
+	}
+	return msg
+}
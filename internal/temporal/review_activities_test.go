@@ -0,0 +1,48 @@
+package temporal
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestReviewNeedsNudge(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	threshold := 24 * time.Hour
+
+	fresh := store.PRReview{OpenedAt: now.Add(-2 * time.Hour)}
+	require.False(t, reviewNeedsNudge(fresh, now, threshold))
+
+	stale := store.PRReview{OpenedAt: now.Add(-30 * time.Hour)}
+	require.True(t, reviewNeedsNudge(stale, now, threshold))
+
+	recentlyNudged := stale
+	recentlyNudged.LastNudgeAt = sql.NullTime{Time: now.Add(-time.Hour), Valid: true}
+	require.False(t, reviewNeedsNudge(recentlyNudged, now, threshold))
+
+	nudgedLongAgo := stale
+	nudgedLongAgo.LastNudgeAt = sql.NullTime{Time: now.Add(-25 * time.Hour), Valid: true}
+	require.True(t, reviewNeedsNudge(nudgedLongAgo, now, threshold))
+
+	require.False(t, reviewNeedsNudge(stale, now, 0), "zero threshold disables nudges")
+}
+
+func TestNextReviewerRotates(t *testing.T) {
+	require.Equal(t, "codex", nextReviewer(store.PRReview{Agent: "claude"}))
+	require.Equal(t, "claude", nextReviewer(store.PRReview{Agent: "claude", Reviewer: "codex"}))
+}
+
+func TestStalledReviewMessage(t *testing.T) {
+	msg := stalledReviewMessage(StalledReview{
+		Project: "cortex", BeadID: "cx-12", PRNumber: 42, PRURL: "https://github.com/o/r/pull/42",
+		WaitingH: 30.4, Reviewer: "codex", Reassigned: true,
+	})
+	require.Contains(t, msg, "PR #42 for cx-12 (cortex) has waited 30h")
+	require.Contains(t, msg, "https://github.com/o/r/pull/42")
+	require.True(t, strings.Contains(msg, "codex posted an automated review"))
+}
@@ -0,0 +1,79 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+
+	"go.temporal.io/sdk/activity"
+
+	"github.com/antigravity-dev/cortex/internal/git"
+)
+
+// FetchReviewFeedbackActivity returns the unresolved review threads of the
+// bead's open PR, so a coder dispatched after reviewers requested changes
+// sees what they asked for. Beads without an open PR get no feedback.
+func (a *Activities) FetchReviewFeedbackActivity(ctx context.Context, req TaskRequest) (*ReviewFeedback, error) {
+	feedback := &ReviewFeedback{Project: req.Project}
+	if a.Store == nil {
+		return feedback, nil
+	}
+	number, _, err := a.Store.GetLatestBeadPR(req.Project, req.BeadID)
+	if err != nil || number == 0 {
+		return feedback, err
+	}
+	workspace := a.Projects[req.Project].Workspace
+	if workspace == "" {
+		workspace = req.WorkDir
+	}
+
+	state, threads, err := git.ListPRReviewThreads(workspace, number)
+	if err != nil {
+		return nil, err
+	}
+	if state != "OPEN" {
+		return feedback, nil
+	}
+	feedback.PRNumber = number
+	for _, t := range threads {
+		if t.Resolved {
+			continue
+		}
+		feedback.Threads = append(feedback.Threads, ReviewThread{ID: t.ID, Path: t.Path, Line: t.Line, Author: t.Author, Body: t.Body})
+	}
+	activity.GetLogger(ctx).Info("PR review feedback", "BeadID", req.BeadID, "PR", number, "Unresolved", len(feedback.Threads))
+	return feedback, nil
+}
+
+// ResolveReviewThreadsActivity marks the threads fed to the coder resolved
+// once a review has passed the change that addressed them, and returns how
+// many it resolved. A thread that fails to resolve is left open.
+func (a *Activities) ResolveReviewThreadsActivity(ctx context.Context, feedback ReviewFeedback) (int, error) {
+	logger := activity.GetLogger(ctx)
+	workspace := a.Projects[feedback.Project].Workspace
+	resolved := 0
+	for _, t := range feedback.Threads {
+		if err := git.ResolvePRReviewThread(workspace, t.ID); err != nil {
+			logger.Warn("Resolve review thread failed", "PR", feedback.PRNumber, "Thread", t.ID, "error", err)
+			continue
+		}
+		resolved++
+	}
+	return resolved, nil
+}
+
+// reviewFeedbackErrors renders unresolved threads as previous errors for
+// the coder's prompt.
+func reviewFeedbackErrors(feedback ReviewFeedback) []string {
+	var out []string
+	for _, t := range feedback.Threads {
+		where := t.Path
+		if t.Line > 0 {
+			where = fmt.Sprintf("%s:%d", t.Path, t.Line)
+		}
+		if where == "" {
+			where = "the PR"
+		}
+		out = append(out, fmt.Sprintf("Unresolved review thread on PR #%d at %s:\n%s", feedback.PRNumber, where, truncate(t.Body, 1000)))
+	}
+	return out
+}
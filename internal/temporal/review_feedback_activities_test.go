@@ -0,0 +1,68 @@
+package temporal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestReviewFeedbackActivities(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+	id, err := st.RecordDispatch("cx-1", "proj", "claude", "claude", "balanced", 0, "", "", "", "feat/cx-1", "headless_cli")
+	require.NoError(t, err)
+	require.NoError(t, st.UpdateDispatchPR(id, "https://github.com/o/r/pull/7", 7))
+
+	// The fake gh answers the thread query and logs resolved threads.
+	fakeBin := t.TempDir()
+	resolvedLog := filepath.Join(t.TempDir(), "resolved")
+	gh := `#!/bin/sh
+case "$*" in
+  *threadId=*) echo "$*" | sed 's/.*threadId=\([^ ]*\).*/\1/' >> "` + resolvedLog + `"; echo '{}' ;;
+  *) echo '{"data":{"repository":{"pullRequest":{"state":"OPEN","reviewThreads":{"nodes":[
+    {"id":"T1","isResolved":false,"path":"main.go","line":12,"comments":{"nodes":[{"author":{"login":"alice"},"body":"Handle the error"}]}},
+    {"id":"T2","isResolved":true,"path":"util.go","line":3,"comments":{"nodes":[{"author":{"login":"bob"},"body":"nit"}]}}
+  ]}}}}}' ;;
+esac
+`
+	require.NoError(t, os.WriteFile(filepath.Join(fakeBin, "gh"), []byte(gh), 0o755))
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	acts := &Activities{Store: st, Projects: map[string]config.Project{"proj": {Workspace: t.TempDir()}}}
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(acts.FetchReviewFeedbackActivity)
+	env.RegisterActivity(acts.ResolveReviewThreadsActivity)
+
+	val, err := env.ExecuteActivity(acts.FetchReviewFeedbackActivity, TaskRequest{BeadID: "cx-1", Project: "proj"})
+	require.NoError(t, err)
+	var feedback ReviewFeedback
+	require.NoError(t, val.Get(&feedback))
+	require.Equal(t, 7, feedback.PRNumber)
+	require.Equal(t, []ReviewThread{{ID: "T1", Path: "main.go", Line: 12, Author: "alice", Body: "alice: Handle the error"}}, feedback.Threads)
+	require.Equal(t, []string{"Unresolved review thread on PR #7 at main.go:12:\nalice: Handle the error"}, reviewFeedbackErrors(feedback))
+
+	val, err = env.ExecuteActivity(acts.ResolveReviewThreadsActivity, feedback)
+	require.NoError(t, err)
+	var resolved int
+	require.NoError(t, val.Get(&resolved))
+	require.Equal(t, 1, resolved)
+	logged, err := os.ReadFile(resolvedLog)
+	require.NoError(t, err)
+	require.Equal(t, "T1\n", string(logged))
+
+	// A bead that never opened a PR gets no feedback and no gh call.
+	val, err = env.ExecuteActivity(acts.FetchReviewFeedbackActivity, TaskRequest{BeadID: "cx-2", Project: "proj"})
+	require.NoError(t, err)
+	var none ReviewFeedback
+	require.NoError(t, val.Get(&none))
+	require.Zero(t, none.PRNumber)
+	require.Empty(t, none.Threads)
+}
@@ -0,0 +1,25 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/rollout"
+)
+
+// RolloutCompletionActivity evaluates the rollout completion criteria against
+// live bead status and records the outcome as a rollout_completion health
+// event.
+func (a *Activities) RolloutCompletionActivity(ctx context.Context, req RolloutCompletionRequest) (*rollout.Result, error) {
+	if a.Store == nil {
+		return nil, fmt.Errorf("rollout completion: no store configured")
+	}
+
+	result, err := rollout.Evaluate(ctx, a.Store, req.Criteria, req.BurnInDir, rollout.BeadsLookup(req.BeadsDir), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	a.Store.RecordHealthEvent("rollout_completion", result.Summary())
+	return result, nil
+}
@@ -0,0 +1,55 @@
+package temporal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/rollout"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestRolloutCompletionActivityUsesBeadStatus(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+
+	// Fake bd: cx-1 is closed, cx-2 is still open.
+	fakeBin := t.TempDir()
+	script := "#!/bin/sh\n" +
+		"status=open\n" +
+		"[ \"$3\" = cx-1 ] && status=closed\n" +
+		"echo \"{\\\"id\\\":\\\"$3\\\",\\\"status\\\":\\\"$status\\\"}\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(fakeBin, "bd"), []byte(script), 0o755))
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	acts := &Activities{Store: st}
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(acts.RolloutCompletionActivity)
+
+	val, err := env.ExecuteActivity(acts.RolloutCompletionActivity, RolloutCompletionRequest{
+		Criteria: config.HealthRollout{
+			CriticalBeads: []string{"cx-1", "cx-2"},
+			Window:        config.Duration{Duration: 24 * time.Hour},
+		},
+		BeadsDir: filepath.Join(t.TempDir(), ".beads"),
+	})
+	require.NoError(t, err)
+	var result rollout.Result
+	require.NoError(t, val.Get(&result))
+
+	require.False(t, result.Complete)
+	require.Equal(t, []rollout.BeadStatus{{ID: "cx-1", Status: "closed"}, {ID: "cx-2", Status: "open"}}, result.Beads)
+
+	events, err := st.GetRecentHealthEvents(1)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "rollout_completion", events[0].EventType)
+	require.Contains(t, events[0].Details, "pending: cx-2 (open)")
+}
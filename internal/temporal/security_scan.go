@@ -0,0 +1,230 @@
+package temporal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/git"
+)
+
+// securityScanCommands are the commands of the DoD security scanners. Each
+// prints a SARIF report to stdout and exits zero whatever it finds.
+var securityScanCommands = map[string]string{
+	"gosec":   "gosec -fmt sarif -quiet -no-fail ./...",
+	"trivy":   "trivy fs --format sarif --quiet .",
+	"semgrep": "semgrep scan --config auto --sarif --quiet",
+}
+
+// securityScan is the outcome of a project's DoD security scanners.
+type securityScan struct {
+	Checks   []git.CheckResult
+	Findings []SecurityFinding // findings in files the dispatch changed
+	Failures []string          // scanners that failed to run or found blocking issues
+}
+
+// runSecurityScans runs the scanners in workDir and keeps the findings in
+// files changed since baseBranch. When the changed files cannot be listed,
+// every finding counts as new.
+func runSecurityScans(ctx context.Context, workDir, baseBranch string, sec config.DoDSecurity) securityScan {
+	var scan securityScan
+	changed, err := git.ChangedFiles(workDir, baseBranch)
+	scoped := err == nil
+	threshold := slices.Index(config.SecuritySeverities, sec.Severity)
+
+	for _, scanner := range sec.Scanners {
+		check, findings := runScanner(ctx, scanner, workDir)
+		if !check.Passed {
+			scan.Checks = append(scan.Checks, check)
+			scan.Failures = append(scan.Failures, fmt.Sprintf("Security scanner %s failed: %s", scanner, check.Output))
+			if check.Killed {
+				break
+			}
+			continue
+		}
+		reported, blocking := len(findings), 0
+		findings = slices.DeleteFunc(findings, func(f SecurityFinding) bool {
+			return scoped && !slices.Contains(changed, f.Path)
+		})
+		for _, f := range findings {
+			if slices.Index(config.SecuritySeverities, f.Severity) >= threshold {
+				blocking++
+			}
+		}
+		check.Output = fmt.Sprintf("%d finding(s), %d in changed files, %d at or above %s", reported, len(findings), blocking, sec.Severity)
+		check.Passed = blocking == 0
+		scan.Checks = append(scan.Checks, check)
+		scan.Findings = append(scan.Findings, findings...)
+		if blocking > 0 {
+			scan.Failures = append(scan.Failures,
+				fmt.Sprintf("Security scan %s: %d new finding(s) at or above %s severity", scanner, blocking, sec.Severity))
+		}
+	}
+	return scan
+}
+
+// runScanner runs one scanner with its report redirected to a temporary
+// file and parses the report. A scanner that fails to run or to report
+// returns a failed check.
+func runScanner(ctx context.Context, scanner, workDir string) (git.CheckResult, []SecurityFinding) {
+	command := securityScanCommands[scanner]
+	report, err := os.CreateTemp("", "cortex-"+scanner+"-*.sarif")
+	if err != nil {
+		return git.CheckResult{Command: command, ExitCode: -1, Output: fmt.Sprintf("create report file: %v", err)}, nil
+	}
+	report.Close()
+	defer os.Remove(report.Name())
+
+	res, _ := git.RunPostMergeChecksCtx(ctx, workDir, []string{command + " > " + shellQuote(report.Name())})
+	check := res.Checks[0]
+	check.Command = command
+	if !check.Passed {
+		return check, nil
+	}
+	data, err := os.ReadFile(report.Name())
+	if err == nil {
+		var findings []SecurityFinding
+		if findings, err = parseSARIF(scanner, data, workDir); err == nil {
+			return check, findings
+		}
+	}
+	check.Passed = false
+	check.Output = fmt.Sprintf("read SARIF report: %v", err)
+	return check, nil
+}
+
+// sarifReport is the part of a SARIF 2.1.0 log the DoD scan reads.
+type sarifReport struct {
+	Runs []struct {
+		Tool struct {
+			Driver struct {
+				Rules []struct {
+					ID                   string         `json:"id"`
+					Properties           map[string]any `json:"properties"`
+					DefaultConfiguration struct {
+						Level string `json:"level"`
+					} `json:"defaultConfiguration"`
+				} `json:"rules"`
+			} `json:"driver"`
+		} `json:"tool"`
+		Results []struct {
+			RuleID    string `json:"ruleId"`
+			RuleIndex *int   `json:"ruleIndex"`
+			Level     string `json:"level"`
+			Message   struct {
+				Text string `json:"text"`
+			} `json:"message"`
+			Properties map[string]any `json:"properties"`
+			Locations  []struct {
+				PhysicalLocation struct {
+					ArtifactLocation struct {
+						URI string `json:"uri"`
+					} `json:"artifactLocation"`
+					Region struct {
+						StartLine int `json:"startLine"`
+					} `json:"region"`
+				} `json:"physicalLocation"`
+			} `json:"locations"`
+		} `json:"results"`
+	} `json:"runs"`
+}
+
+// parseSARIF turns a scanner's SARIF report into findings with paths
+// relative to workDir. Severity comes from the numeric security-severity
+// property where the scanner sets one, and from the result level otherwise.
+func parseSARIF(scanner string, data []byte, workDir string) ([]SecurityFinding, error) {
+	var report sarifReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("parse SARIF: %w", err)
+	}
+	var findings []SecurityFinding
+	for _, run := range report.Runs {
+		rules := run.Tool.Driver.Rules
+		for _, r := range run.Results {
+			f := SecurityFinding{Scanner: scanner, RuleID: r.RuleID, Message: strings.TrimSpace(r.Message.Text)}
+			level, score := r.Level, securitySeverityScore(r.Properties)
+			for i, rule := range rules {
+				if (r.RuleIndex != nil && *r.RuleIndex == i) || (r.RuleIndex == nil && rule.ID == r.RuleID) {
+					if f.RuleID == "" {
+						f.RuleID = rule.ID
+					}
+					if level == "" {
+						level = rule.DefaultConfiguration.Level
+					}
+					if score < 0 {
+						score = securitySeverityScore(rule.Properties)
+					}
+					break
+				}
+			}
+			f.Severity = severityFrom(score, level)
+			if len(r.Locations) > 0 {
+				loc := r.Locations[0].PhysicalLocation
+				f.Path = sarifPath(loc.ArtifactLocation.URI, workDir)
+				f.Line = loc.Region.StartLine
+			}
+			findings = append(findings, f)
+		}
+	}
+	return findings, nil
+}
+
+// securitySeverityScore reads the CVSS-style security-severity property,
+// or returns -1 when it is absent.
+func securitySeverityScore(props map[string]any) float64 {
+	switch v := props["security-severity"].(type) {
+	case string:
+		if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+			return f
+		}
+	case float64:
+		return v
+	}
+	return -1
+}
+
+func severityFrom(score float64, level string) string {
+	switch {
+	case score >= 9:
+		return "critical"
+	case score >= 7:
+		return "high"
+	case score >= 4:
+		return "medium"
+	case score >= 0:
+		return "low"
+	}
+	switch level {
+	case "error":
+		return "high"
+	case "note", "none":
+		return "low"
+	default: // SARIF's default level is warning
+		return "medium"
+	}
+}
+
+// sarifPath makes a SARIF artifact URI relative to the work tree, matching
+// the paths git reports.
+func sarifPath(uri, workDir string) string {
+	if u, err := url.Parse(uri); err == nil && u.Scheme == "file" {
+		uri = u.Path
+	}
+	if filepath.IsAbs(uri) {
+		if rel, err := filepath.Rel(workDir, uri); err == nil && !strings.HasPrefix(rel, "..") {
+			uri = rel
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(uri))
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,91 @@
+package temporal
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+const testSARIF = `{
+  "version": "2.1.0",
+  "runs": [{
+    "tool": {"driver": {"name": "gosec", "rules": [
+      {"id": "G101", "properties": {"security-severity": "7.5"}},
+      {"id": "G104", "defaultConfiguration": {"level": "note"}}
+    ]}},
+    "results": [
+      {"ruleId": "G101", "ruleIndex": 0, "message": {"text": "Potential hardcoded credentials"},
+       "locations": [{"physicalLocation": {"artifactLocation": {"uri": "file://WORKDIR/new.go"}, "region": {"startLine": 3}}}]},
+      {"ruleId": "G104", "message": {"text": "Errors unhandled"},
+       "locations": [{"physicalLocation": {"artifactLocation": {"uri": "old.go"}, "region": {"startLine": 5}}}]},
+      {"ruleId": "G402", "level": "error", "message": {"text": "TLS InsecureSkipVerify set true"},
+       "locations": [{"physicalLocation": {"artifactLocation": {"uri": "old.go"}, "region": {"startLine": 9}}}]}
+    ]
+  }]
+}`
+
+func TestParseSARIF(t *testing.T) {
+	report := strings.ReplaceAll(testSARIF, "WORKDIR", "/work")
+	findings, err := parseSARIF("gosec", []byte(report), "/work")
+	require.NoError(t, err)
+	require.Equal(t, []SecurityFinding{
+		{Scanner: "gosec", RuleID: "G101", Severity: "high", Path: "new.go", Line: 3, Message: "Potential hardcoded credentials"},
+		{Scanner: "gosec", RuleID: "G104", Severity: "low", Path: "old.go", Line: 5, Message: "Errors unhandled"},
+		{Scanner: "gosec", RuleID: "G402", Severity: "high", Path: "old.go", Line: 9, Message: "TLS InsecureSkipVerify set true"},
+	}, findings)
+
+	_, err = parseSARIF("gosec", []byte("not json"), "/work")
+	require.Error(t, err)
+}
+
+func TestRunSecurityScans(t *testing.T) {
+	workDir := t.TempDir()
+	gitRun := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = workDir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	gitRun("init", "-b", "master")
+	gitRun("config", "user.email", "test@example.com")
+	gitRun("config", "user.name", "Test User")
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "old.go"), []byte("package x\n"), 0o644))
+	gitRun("add", "old.go")
+	gitRun("commit", "-m", "init")
+	gitRun("checkout", "-b", "feat/cx-1")
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "new.go"), []byte("package x\n"), 0o644))
+
+	fakeBin := t.TempDir()
+	sarif := filepath.Join(fakeBin, "report.sarif")
+	require.NoError(t, os.WriteFile(sarif, []byte(testSARIF), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(fakeBin, "gosec"),
+		[]byte("#!/bin/sh\nsed \"s|WORKDIR|$PWD|\" \""+sarif+"\"\n"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(fakeBin, "trivy"), []byte("#!/bin/sh\necho boom >&2\nexit 2\n"), 0o755))
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	// Only the finding in the changed file counts, and it is high severity.
+	scan := runSecurityScans(context.Background(), workDir, "master", config.DoDSecurity{Scanners: []string{"gosec"}, Severity: "high"})
+	require.Len(t, scan.Findings, 1)
+	require.Equal(t, "G101", scan.Findings[0].RuleID)
+	require.Len(t, scan.Checks, 1)
+	require.False(t, scan.Checks[0].Passed)
+	require.Equal(t, "3 finding(s), 1 in changed files, 1 at or above high", scan.Checks[0].Output)
+	require.Equal(t, []string{"Security scan gosec: 1 new finding(s) at or above high severity"}, scan.Failures)
+
+	scan = runSecurityScans(context.Background(), workDir, "master", config.DoDSecurity{Scanners: []string{"gosec"}, Severity: "critical"})
+	require.Len(t, scan.Findings, 1)
+	require.True(t, scan.Checks[0].Passed)
+	require.Empty(t, scan.Failures)
+
+	// A scanner that fails to run fails the DoD.
+	scan = runSecurityScans(context.Background(), workDir, "master", config.DoDSecurity{Scanners: []string{"trivy"}, Severity: "high"})
+	require.Len(t, scan.Failures, 1)
+	require.Contains(t, scan.Failures[0], "Security scanner trivy failed")
+}
@@ -0,0 +1,115 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// slotPollInterval is how long a dispatch waiting for a coder or reviewer
+// slot sleeps before asking again.
+const slotPollInterval = 30 * time.Second
+
+// slotLeaseTTL is how long a slot stays held without renewal. A dispatch
+// renews its slot every third of it, so a slot held by a workflow that was
+// terminated or lost with its worker frees itself.
+const slotLeaseTTL = 10 * time.Minute
+
+// AcquireSlotActivity takes a slot in role ("coder" or "reviewer") for req's
+// bead under the concurrency limits in force, or renews the one it holds.
+// Slots are leased in the store for slotLeaseTTL. A bead refused a slot
+// waits in the overflow queue, which the autoscaler grows the role's limit
+// from; once granted it leaves the queue and its wait counts toward the
+// queue-wait SLOs.
+func (a *Activities) AcquireSlotActivity(ctx context.Context, req TaskRequest, role string) (bool, error) {
+	if a.Store == nil || a.Slots == nil {
+		return true, nil
+	}
+	granted, err := a.Store.AcquireSlotLease(role, req.BeadID, a.Slots.Limit(a.General, role), slotLeaseTTL)
+	if err != nil {
+		return false, err
+	}
+	if !granted {
+		agent := req.Agent
+		if role == "reviewer" {
+			agent = req.Reviewer
+		}
+		reason := fmt.Sprintf("%s concurrency limit reached", role)
+		if _, err := a.Store.EnqueueOverflowItem(req.BeadID, req.Project, role, agent, 0, reason); err != nil {
+			activity.GetLogger(ctx).Warn("Failed to queue bead for a slot", "BeadID", req.BeadID, "Role", role, "error", err)
+		}
+		return false, nil
+	}
+	if _, err := a.Store.RemoveOverflowItem(req.BeadID); err != nil {
+		activity.GetLogger(ctx).Warn("Failed to dequeue bead", "BeadID", req.BeadID, "Role", role, "error", err)
+	}
+	return true, nil
+}
+
+// ReleaseSlotActivity frees req's bead's slot in role, and takes the bead
+// out of the overflow queue if it gave up waiting there, so an abandoned
+// item does not hold the role's queue wait over its SLO.
+func (a *Activities) ReleaseSlotActivity(ctx context.Context, req TaskRequest, role string) error {
+	if a.Store == nil {
+		return nil
+	}
+	if err := a.Store.ReleaseSlotLease(role, req.BeadID); err != nil {
+		return err
+	}
+	_, err := a.Store.RemoveOverflowItem(req.BeadID)
+	return err
+}
+
+// acquireSlot waits until req's bead holds a slot in role and returns the
+// function that frees it. The slot is renewed until then. A slot that
+// cannot be checked goes on without one; a workflow cancelled while it
+// waits leaves the overflow queue.
+func acquireSlot(ctx workflow.Context, a *Activities, req TaskRequest, role string) func() {
+	logger := workflow.GetLogger(ctx)
+	opts := workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Second,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 3},
+	}
+	slotCtx := workflow.WithActivityOptions(ctx, opts)
+	finished := false
+	release := func() {
+		finished = true
+		releaseCtx, _ := workflow.NewDisconnectedContext(ctx)
+		releaseCtx = workflow.WithActivityOptions(releaseCtx, opts)
+		if err := workflow.ExecuteActivity(releaseCtx, a.ReleaseSlotActivity, req, role).Get(releaseCtx, nil); err != nil {
+			logger.Warn("Slot release failed", "Role", role, "error", err)
+		}
+	}
+	for waited := false; ; waited = true {
+		var granted bool
+		if err := workflow.ExecuteActivity(slotCtx, a.AcquireSlotActivity, req, role).Get(ctx, &granted); err != nil {
+			logger.Warn("Slot check failed, going on without one", "Role", role, "error", err)
+			return func() {}
+		}
+		if granted {
+			workflow.Go(ctx, func(gctx workflow.Context) {
+				for {
+					if err := workflow.Sleep(gctx, slotLeaseTTL/3); err != nil || finished {
+						return
+					}
+					var renewed bool
+					if err := workflow.ExecuteActivity(slotCtx, a.AcquireSlotActivity, req, role).Get(gctx, &renewed); err != nil || !renewed {
+						logger.Warn("Slot renewal failed", "Role", role, "error", err)
+					}
+				}
+			})
+			return release
+		}
+		if !waited {
+			logger.Info("Waiting for a slot", "Role", role)
+		}
+		if err := workflow.Sleep(ctx, slotPollInterval); err != nil {
+			release()
+			return func() {}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package temporal
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/autoscale"
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestAcquireSlotQueuesBeadsOverTheLimit(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+
+	general := config.General{MaxConcurrentCoders: 1, MaxConcurrentReviewers: 1}
+	acts := &Activities{Store: st, General: general, Slots: autoscale.New(general)}
+	env := (&testsuite.WorkflowTestSuite{}).NewTestActivityEnvironment()
+	env.RegisterActivity(acts.AcquireSlotActivity)
+	env.RegisterActivity(acts.ReleaseSlotActivity)
+
+	acquire := func(bead string) bool {
+		val, err := env.ExecuteActivity(acts.AcquireSlotActivity, TaskRequest{BeadID: bead, Project: "p", Agent: "claude"}, "coder")
+		require.NoError(t, err)
+		var granted bool
+		require.NoError(t, val.Get(&granted))
+		return granted
+	}
+
+	require.True(t, acquire("b-1"))
+	require.False(t, acquire("b-2"), "second coder admitted over the limit")
+
+	// Slots live in the store, so another worker, or this one restarted, sees them.
+	other := &Activities{Store: st, General: general, Slots: autoscale.New(general)}
+	env.RegisterActivity(other.AcquireSlotActivity)
+	val, err := env.ExecuteActivity(other.AcquireSlotActivity, TaskRequest{BeadID: "b-2", Project: "p", Agent: "claude"}, "coder")
+	require.NoError(t, err)
+	var granted bool
+	require.NoError(t, val.Get(&granted))
+	require.False(t, granted, "slot held through another worker handed out again")
+	queued, err := st.ListOverflowQueue()
+	require.NoError(t, err)
+	require.Len(t, queued, 1)
+	require.Equal(t, "b-2", queued[0].BeadID)
+	require.Equal(t, "coder", queued[0].Role)
+	require.Equal(t, "claude", queued[0].AgentID)
+
+	_, err = env.ExecuteActivity(acts.ReleaseSlotActivity, TaskRequest{BeadID: "b-1"}, "coder")
+	require.NoError(t, err)
+	require.True(t, acquire("b-2"))
+	count, err := st.CountOverflowQueue()
+	require.NoError(t, err)
+	require.Zero(t, count, "admitted bead left in the overflow queue")
+}
+
+func TestSlotWaitsFeedQueueWaitStats(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+
+	general := config.General{MaxConcurrentCoders: 1, MaxConcurrentReviewers: 1}
+	acts := &Activities{Store: st, General: general, Slots: autoscale.New(general)}
+	env := (&testsuite.WorkflowTestSuite{}).NewTestActivityEnvironment()
+	env.RegisterActivity(acts.AcquireSlotActivity)
+	env.RegisterActivity(acts.ReleaseSlotActivity)
+
+	run := func(activity any, bead, role string) {
+		_, err := env.ExecuteActivity(activity, TaskRequest{BeadID: bead, Project: "p"}, role)
+		require.NoError(t, err)
+	}
+	run(acts.AcquireSlotActivity, "b-1", "reviewer")
+	run(acts.AcquireSlotActivity, "b-2", "reviewer") // queued
+	run(acts.AcquireSlotActivity, "b-3", "reviewer") // queued, then abandoned
+	run(acts.ReleaseSlotActivity, "b-3", "reviewer")
+	run(acts.ReleaseSlotActivity, "b-1", "reviewer")
+	run(acts.AcquireSlotActivity, "b-2", "reviewer")
+
+	now := time.Now()
+	stats, err := st.GetQueueWaitStats(now.Add(-time.Hour), now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, 2, stats["reviewer"].Waits, "both dispatch waits recorded")
+	require.Zero(t, stats["reviewer"].Queued, "abandoned wait left in the queue")
+}
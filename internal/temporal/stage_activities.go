@@ -0,0 +1,115 @@
+package temporal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.temporal.io/sdk/activity"
+
+	"github.com/antigravity-dev/cortex/internal/beads"
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/scheduler"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+// SeedBeadStageActivity puts the task's bead in the first stage of the
+// workflow its labels or type match, unless it is already in one. It
+// returns the bead's current stage, or "" when no workflow matches.
+func (a *Activities) SeedBeadStageActivity(ctx context.Context, req TaskRequest) (string, error) {
+	if a.Store == nil || len(a.Workflows) == 0 {
+		return "", nil
+	}
+	if stage, err := a.Store.GetBeadStage(req.Project, req.BeadID); err == nil {
+		return stage.CurrentStage, nil
+	}
+	bead, err := a.findBead(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	name := config.MatchWorkflow(a.Workflows, bead.Labels, bead.Type)
+	if name == "" {
+		return "", nil
+	}
+	wf := a.Workflows[name]
+	if err := a.Store.UpsertBeadStage(&store.BeadStage{
+		Project:      req.Project,
+		BeadID:       req.BeadID,
+		Workflow:     name,
+		CurrentStage: wf.Stages[0].Name,
+		TotalStages:  len(wf.Stages),
+	}); err != nil {
+		return "", err
+	}
+	activity.GetLogger(ctx).Info("Bead entered workflow", "BeadID", req.BeadID, "Workflow", name, "Stage", wf.Stages[0].Name)
+	return wf.Stages[0].Name, nil
+}
+
+// AdvanceBeadStageActivity moves the task's bead to the stage after its
+// current one once a dispatch of it passed DoD, through the same transition
+// guards as a manual move. The review stage is left to the forge webhook,
+// which advances it when the PR is approved. A move the guards deny leaves
+// the bead where it is. It returns the stage the bead moved to, or "".
+func (a *Activities) AdvanceBeadStageActivity(ctx context.Context, req TaskRequest) (string, error) {
+	logger := activity.GetLogger(ctx)
+	if a.Store == nil || len(a.Workflows) == 0 {
+		return "", nil
+	}
+	current, err := a.Store.GetBeadStage(req.Project, req.BeadID)
+	if err != nil || current.CurrentStage == scheduler.ReviewStage || current.CurrentStage == config.TerminalStage {
+		return "", nil // not in a workflow, or not the dispatch's to move on
+	}
+	to, err := scheduler.NextStage(a.Workflows, current)
+	if err != nil {
+		return "", err
+	}
+	bead, err := a.findBead(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	err = scheduler.AdvanceStage(a.Store, a.Workflows, scheduler.StageTransition{
+		Project: req.Project,
+		BeadID:  req.BeadID,
+		To:      to,
+		Labels:  bead.Labels,
+	})
+	var denied *scheduler.TransitionDeniedError
+	if errors.As(err, &denied) {
+		logger.Info("Bead stays in its stage", "BeadID", req.BeadID, "Stage", current.CurrentStage, "Reason", denied.Error())
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	details := fmt.Sprintf("%s/%s: %s -> %s (dispatch)", req.Project, req.BeadID, current.CurrentStage, to)
+	if err := a.Store.RecordHealthEventWithDispatch("stage_transition", details, 0, req.BeadID); err != nil {
+		logger.Warn("Failed to record stage transition event", "BeadID", req.BeadID, "error", err)
+	}
+	beadsDir := config.ExpandHome(a.Projects[req.Project].BeadsDir)
+	if err := beads.SetLabelsCtx(ctx, beadsDir, req.BeadID, beads.WithStageLabel(bead.Labels, to)); err != nil {
+		logger.Warn("Stage moved but bead label not updated", "BeadID", req.BeadID, "error", err)
+	}
+	if a.Git.DraftPRs && to == scheduler.ReviewStage {
+		workspace := config.ExpandHome(a.Projects[req.Project].Workspace)
+		if _, err := scheduler.MarkReadyForReview(a.Store, workspace, req.Project, req.BeadID); err != nil {
+			logger.Warn("Bead entered review but its PR is still a draft", "BeadID", req.BeadID, "error", err)
+		}
+	}
+	return to, nil
+}
+
+// findBead returns the task's bead from its project's bead list.
+func (a *Activities) findBead(ctx context.Context, req TaskRequest) (beads.Bead, error) {
+	list, err := beads.ListBeadsCtx(ctx, config.ExpandHome(a.Projects[req.Project].BeadsDir))
+	if err != nil {
+		return beads.Bead{}, err
+	}
+	for _, b := range list {
+		if b.ID == req.BeadID {
+			return b, nil
+		}
+	}
+	return beads.Bead{}, fmt.Errorf("bead %s not found in project %s", req.BeadID, req.Project)
+}
@@ -0,0 +1,72 @@
+package temporal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestDispatchSeedsAndAdvancesBeadStages(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer st.Close()
+
+	beadsDir := filepath.Join(t.TempDir(), ".beads")
+	require.NoError(t, os.MkdirAll(beadsDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(beadsDir, "issues.jsonl"), []byte(
+		`{"id":"cx-1","status":"open","issue_type":"task","labels":["backend"]}`+"\n"+
+			`{"id":"cx-2","status":"open","issue_type":"task","labels":["frontend"]}`+"\n"+
+			`{"id":"cx-3","status":"open","issue_type":"chore"}`+"\n"), 0o644))
+	fakeBin := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(fakeBin, "bd"), []byte("#!/bin/sh\nexit 0\n"), 0o755))
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	acts := &Activities{
+		Store:    st,
+		Projects: map[string]config.Project{"cortex": {BeadsDir: beadsDir}},
+		Workflows: map[string]config.WorkflowConfig{
+			"dev": {MatchLabels: []string{"backend"}, Stages: []config.StageConfig{
+				{Name: "implement", Role: "coder"},
+				{Name: "review", Role: "reviewer"},
+			}},
+			"ui": {MatchLabels: []string{"frontend"}, Stages: []config.StageConfig{
+				{Name: "implement", Role: "coder", RequireLabels: []string{"design-ok"}},
+				{Name: "review", Role: "reviewer"},
+			}},
+		},
+	}
+	env := (&testsuite.WorkflowTestSuite{}).NewTestActivityEnvironment()
+	env.RegisterActivity(acts.SeedBeadStageActivity)
+	env.RegisterActivity(acts.AdvanceBeadStageActivity)
+	run := func(activity any, bead string) string {
+		val, err := env.ExecuteActivity(activity, TaskRequest{Project: "cortex", BeadID: bead})
+		require.NoError(t, err)
+		var stage string
+		require.NoError(t, val.Get(&stage))
+		return stage
+	}
+
+	require.Equal(t, "implement", run(acts.SeedBeadStageActivity, "cx-1"))
+	require.Equal(t, "review", run(acts.AdvanceBeadStageActivity, "cx-1"))
+	require.Equal(t, "review", run(acts.SeedBeadStageActivity, "cx-1"), "a bead in a workflow was seeded again")
+	require.Empty(t, run(acts.AdvanceBeadStageActivity, "cx-1"), "review left before the PR was approved")
+	stage, err := st.GetBeadStage("cortex", "cx-1")
+	require.NoError(t, err)
+	require.Equal(t, "review", stage.CurrentStage)
+	require.Equal(t, 1, stage.StageIndex)
+
+	// A guard the bead fails keeps it in its stage.
+	require.Equal(t, "implement", run(acts.SeedBeadStageActivity, "cx-2"))
+	require.Empty(t, run(acts.AdvanceBeadStageActivity, "cx-2"))
+
+	// A bead no workflow matches is not tracked.
+	require.Empty(t, run(acts.SeedBeadStageActivity, "cx-3"))
+	_, err = st.GetBeadStage("cortex", "cx-3")
+	require.Error(t, err)
+}
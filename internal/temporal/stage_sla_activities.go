@@ -0,0 +1,97 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.temporal.io/sdk/activity"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+// CheckStageSLAActivity compares each tracked bead's time in its current
+// stage against the stage's max_duration. A breach is reported once per stage
+// entry: it is recorded as a stage_sla_breach health event, announced in the
+// project room and, when the stage escalates, marks the bead's next dispatch
+// for a higher tier.
+func (a *Activities) CheckStageSLAActivity(ctx context.Context, req StageSLARequest) (*StageSLAResult, error) {
+	logger := activity.GetLogger(ctx)
+	result := &StageSLAResult{}
+	if a.Store == nil {
+		return result, nil
+	}
+
+	projects := make([]string, 0, len(req.Projects))
+	for name := range req.Projects {
+		projects = append(projects, name)
+	}
+	sort.Strings(projects)
+
+	now := time.Now().UTC()
+	for _, project := range projects {
+		stages, err := a.Store.ListBeadStagesForProject(project)
+		if err != nil {
+			return nil, err
+		}
+		for _, bs := range stages {
+			limit, ok := req.Limits[bs.Workflow][bs.CurrentStage]
+			if !ok || limit.MaxDuration <= 0 {
+				continue
+			}
+			result.Checked++
+
+			entered := bs.StageEnteredAt()
+			inStage := now.Sub(entered)
+			if inStage < limit.MaxDuration {
+				continue
+			}
+			isNew, err := a.Store.RecordStageSLABreach(store.StageSLABreach{
+				Project:    project,
+				BeadID:     bs.BeadID,
+				Workflow:   bs.Workflow,
+				Stage:      bs.CurrentStage,
+				EnteredAt:  entered,
+				BreachedAt: now,
+				Escalate:   limit.Escalate,
+			})
+			if err != nil {
+				return nil, err
+			}
+			if !isNew {
+				continue
+			}
+
+			breach := StageBreach{
+				Project:   project,
+				BeadID:    bs.BeadID,
+				Workflow:  bs.Workflow,
+				Stage:     bs.CurrentStage,
+				InStageH:  inStage.Hours(),
+				LimitH:    limit.MaxDuration.Hours(),
+				Escalated: limit.Escalate,
+			}
+			msg := stageBreachMessage(breach)
+			if err := a.Store.RecordHealthEventWithDispatch("stage_sla_breach", msg, 0, bs.BeadID); err != nil {
+				logger.Warn("Stage SLA: record health event failed", "Bead", bs.BeadID, "error", err)
+			}
+			if room := req.Projects[project]; a.Sender != nil && room != "" {
+				if err := a.Sender.SendMessage(ctx, room, msg); err != nil {
+					logger.Warn("Stage SLA: notification failed", "Room", room, "error", err)
+				}
+			}
+			result.Breaches = append(result.Breaches, breach)
+		}
+	}
+	return result, nil
+}
+
+func stageBreachMessage(b StageBreach) string {
+	msg := fmt.Sprintf("Stage SLA breach: %s (%s) has been in %s stage %q for %.1fh, over its %.1fh limit.",
+		b.BeadID, b.Project, b.Workflow, b.Stage, b.InStageH, b.LimitH)
+	if b.Escalated {
+		msg += " Its next dispatch will run one tier higher."
+	}
+	return msg
+}
@@ -0,0 +1,70 @@
+package temporal
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestCheckStageSLAActivity(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer st.Close()
+
+	now := time.Now().UTC()
+	for id, entered := range map[string]time.Time{"cx-slow": now.Add(-5 * time.Hour), "cx-fresh": now.Add(-time.Hour)} {
+		require.NoError(t, st.UpsertBeadStage(&store.BeadStage{
+			Project: "cortex", BeadID: id, Workflow: "dev", CurrentStage: "review", StageIndex: 1, TotalStages: 2,
+			StageHistory: []store.StageHistoryEntry{{Stage: "review", Status: "running", StartedAt: entered}},
+		}))
+	}
+	require.NoError(t, st.UpsertBeadStage(&store.BeadStage{
+		Project: "cortex", BeadID: "cx-coding", Workflow: "dev", CurrentStage: "implement", TotalStages: 2,
+		StageHistory: []store.StageHistoryEntry{{Stage: "implement", Status: "running", StartedAt: now.Add(-48 * time.Hour)}},
+	}))
+
+	sender := &recordingSender{}
+	acts := &Activities{Store: st, Sender: sender}
+	req := StageSLARequest{
+		Limits:   map[string]map[string]StageLimit{"dev": {"review": {MaxDuration: 4 * time.Hour, Escalate: true}}},
+		Projects: map[string]string{"cortex": "!room"},
+	}
+	run := func() StageSLAResult {
+		s := testsuite.WorkflowTestSuite{}
+		env := s.NewTestActivityEnvironment()
+		env.RegisterActivity(acts.CheckStageSLAActivity)
+		val, err := env.ExecuteActivity(acts.CheckStageSLAActivity, req)
+		require.NoError(t, err)
+		var res StageSLAResult
+		require.NoError(t, val.Get(&res))
+		return res
+	}
+
+	first := run()
+	require.Equal(t, 2, first.Checked)
+	require.Len(t, first.Breaches, 1)
+	require.Equal(t, "cx-slow", first.Breaches[0].BeadID)
+	require.True(t, first.Breaches[0].Escalated)
+	require.Equal(t, []string{"!room"}, sender.rooms)
+	require.Contains(t, sender.messages[0], `cx-slow (cortex) has been in dev stage "review" for 5.0h, over its 4.0h limit`)
+
+	events, err := st.GetRecentHealthEvents(1)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "stage_sla_breach", events[0].EventType)
+	require.Equal(t, "cx-slow", events[0].BeadID)
+
+	escalate, err := st.ConsumeStageEscalation("cortex", "cx-slow")
+	require.NoError(t, err)
+	require.True(t, escalate)
+
+	// The same stage entry is not reported again.
+	second := run()
+	require.Empty(t, second.Breaches)
+	require.Len(t, sender.messages, 1)
+}
@@ -0,0 +1,133 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.temporal.io/sdk/activity"
+
+	"github.com/antigravity-dev/cortex/internal/beads"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+// StandupDigestActivity summarizes each project's activity over the request
+// window: dispatches completed and failed, stage moves, cost and open
+// blockers. Each digest is stored for the API and posted to the project room.
+// A project whose beads cannot be listed still gets a digest, without
+// blockers.
+func (a *Activities) StandupDigestActivity(ctx context.Context, req StandupDigestRequest) (*StandupDigestResult, error) {
+	logger := activity.GetLogger(ctx)
+	if a.Store == nil {
+		return nil, fmt.Errorf("standup digest: no store configured")
+	}
+
+	to := time.Now().UTC()
+	from := to.Add(-req.Window)
+	result := &StandupDigestResult{Date: to.Format(time.DateOnly)}
+
+	counts, err := a.Store.GetProjectDispatchStatusCounts(from)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make([]string, 0, len(req.Projects))
+	for name := range req.Projects {
+		projects = append(projects, name)
+	}
+	sort.Strings(projects)
+
+	for _, name := range projects {
+		project := req.Projects[name]
+		d := store.StandupDigest{
+			Project:             name,
+			Date:                result.Date,
+			WindowStart:         from,
+			WindowEnd:           to,
+			DispatchesCompleted: counts[name].Completed,
+			DispatchesFailed:    counts[name].Failed,
+			Moves:               []store.StageMove{},
+			Blockers:            []store.Blocker{},
+			CreatedAt:           to,
+		}
+		if d.Moves, err = a.Store.ListStageMoves(name, from, to); err != nil {
+			return nil, err
+		}
+		if d.CostUSD, err = a.Store.GetTotalCostSince(name, from); err != nil {
+			return nil, err
+		}
+		if blockers, err := listBlockers(ctx, project.BeadsDir); err != nil {
+			logger.Warn("Standup digest: listing blockers failed", "Project", name, "error", err)
+		} else {
+			d.Blockers = blockers
+		}
+		d.Text = standupDigestMessage(d)
+
+		if err := a.Store.UpsertStandupDigest(d); err != nil {
+			return nil, err
+		}
+		result.Projects = append(result.Projects, name)
+
+		if a.Sender != nil && project.Room != "" {
+			if err := a.Sender.SendMessage(ctx, project.Room, d.Text); err != nil {
+				logger.Warn("Standup digest: post failed", "Project", name, "Room", project.Room, "error", err)
+				continue
+			}
+			result.Posted++
+		}
+	}
+	return result, nil
+}
+
+// listBlockers returns the project's blocked beads with the unfinished
+// dependencies blocking each.
+func listBlockers(ctx context.Context, beadsDir string) ([]store.Blocker, error) {
+	if beadsDir == "" {
+		return []store.Blocker{}, nil
+	}
+	list, err := beads.ListBeadsCtx(ctx, beadsDir)
+	if err != nil {
+		return nil, err
+	}
+	graph := beads.BuildDepGraph(list)
+	out := []store.Blocker{}
+	for _, b := range beads.FilterBlocked(list, graph) {
+		blocker := store.Blocker{BeadID: b.ID, Title: b.Title}
+		for _, dep := range graph.DependsOnIDs(b.ID) {
+			if node, ok := graph.Nodes()[dep]; !ok || node.Status != "closed" {
+				blocker.BlockedBy = append(blocker.BlockedBy, dep)
+			}
+		}
+		out = append(out, blocker)
+	}
+	return out, nil
+}
+
+func standupDigestMessage(d store.StandupDigest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Standup digest for %s (%s)\n", d.Project, d.Date)
+	fmt.Fprintf(&b, "Dispatches: %d completed, %d failed. Cost: $%.2f\n", d.DispatchesCompleted, d.DispatchesFailed, d.CostUSD)
+	if len(d.Moves) == 0 {
+		b.WriteString("Beads moved: none\n")
+	} else {
+		fmt.Fprintf(&b, "Beads moved (%d):\n", len(d.Moves))
+		for _, m := range d.Moves {
+			fmt.Fprintf(&b, "- %s: %s -> %s\n", m.BeadID, m.From, m.To)
+		}
+	}
+	if len(d.Blockers) == 0 {
+		b.WriteString("Open blockers: none\n")
+	} else {
+		fmt.Fprintf(&b, "Open blockers (%d):\n", len(d.Blockers))
+		for _, bl := range d.Blockers {
+			fmt.Fprintf(&b, "- %s %s", bl.BeadID, bl.Title)
+			if len(bl.BlockedBy) > 0 {
+				fmt.Fprintf(&b, " (waiting on %s)", strings.Join(bl.BlockedBy, ", "))
+			}
+			b.WriteString("\n")
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
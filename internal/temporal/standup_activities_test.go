@@ -0,0 +1,75 @@
+package temporal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestStandupDigestActivity(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer st.Close()
+
+	now := time.Now().UTC()
+	for bead, status := range map[string]string{"cx-1": "completed", "cx-2": "completed", "cx-3": "failed"} {
+		id, err := st.RecordDispatch(bead, "cortex", "agent", "claude", "balanced", 1, "", "p", "", "", "")
+		require.NoError(t, err)
+		require.NoError(t, st.UpdateDispatchStatus(id, status, 0, 1))
+	}
+	require.NoError(t, st.UpsertBeadStage(&store.BeadStage{
+		Project: "cortex", BeadID: "cx-1", Workflow: "dev", CurrentStage: "review", TotalStages: 2,
+		StageHistory: []store.StageHistoryEntry{
+			{Stage: "implement", StartedAt: now.Add(-30 * time.Hour)},
+			{Stage: "review", StartedAt: now.Add(-time.Hour)},
+		},
+	}))
+
+	fakeBin := t.TempDir()
+	bd := `#!/bin/sh
+echo '[{"id":"cx-5","title":"Wire API","status":"open","issue_type":"task","priority":1,"depends_on":["cx-4"]},{"id":"cx-4","title":"Schema","status":"in_progress","issue_type":"task","priority":1},{"id":"cx-6","title":"Ready","status":"open","issue_type":"task","priority":2}]'
+`
+	require.NoError(t, os.WriteFile(filepath.Join(fakeBin, "bd"), []byte(bd), 0o755))
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	sender := &recordingSender{}
+	acts := &Activities{Store: st, Sender: sender}
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(acts.StandupDigestActivity)
+
+	val, err := env.ExecuteActivity(acts.StandupDigestActivity, StandupDigestRequest{
+		Window: 24 * time.Hour,
+		Projects: map[string]StandupProject{
+			"cortex": {BeadsDir: filepath.Join(t.TempDir(), ".beads"), Room: "!cortex"},
+			"quiet":  {},
+		},
+	})
+	require.NoError(t, err)
+	var res StandupDigestResult
+	require.NoError(t, val.Get(&res))
+	require.Equal(t, []string{"cortex", "quiet"}, res.Projects)
+	require.Equal(t, 1, res.Posted)
+
+	digests, err := st.ListStandupDigests("cortex", 1)
+	require.NoError(t, err)
+	require.Len(t, digests, 1)
+	d := digests[0]
+	require.Equal(t, res.Date, d.Date)
+	require.Equal(t, 2, d.DispatchesCompleted)
+	require.Equal(t, 1, d.DispatchesFailed)
+	require.Len(t, d.Moves, 1)
+	require.Equal(t, "review", d.Moves[0].To)
+	require.Equal(t, []store.Blocker{{BeadID: "cx-5", Title: "Wire API", BlockedBy: []string{"cx-4"}}}, d.Blockers)
+
+	require.Equal(t, []string{"!cortex"}, sender.rooms)
+	require.Contains(t, sender.messages[0], "2 completed, 1 failed")
+	require.Contains(t, sender.messages[0], "cx-1: implement -> review")
+	require.Contains(t, sender.messages[0], "cx-5 Wire API (waiting on cx-4)")
+}
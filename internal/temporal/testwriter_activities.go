@@ -0,0 +1,116 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.temporal.io/sdk/activity"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/git"
+)
+
+// TestWriterActivity asks an agent to write tests when the coder changed
+// code files but no test files since the project's base branch. Projects
+// without the stage, and changes that touch tests or no code, skip it.
+func (a *Activities) TestWriterActivity(ctx context.Context, req TaskRequest) (*TestWriterResult, error) {
+	project := a.Projects[req.Project]
+	tw := project.TestWriter
+	result := &TestWriterResult{}
+	if !tw.Enabled || req.WorkDir == "" {
+		return result, nil
+	}
+	logger := activity.GetLogger(ctx)
+
+	changed, err := git.ChangedFiles(req.WorkDir, project.BaseBranch)
+	if err != nil {
+		return nil, fmt.Errorf("test writer: %w", err)
+	}
+	result.Untested = untestedChanges(req.WorkDir, changed, tw)
+	if len(result.Untested) == 0 {
+		return result, nil
+	}
+
+	// As with autofix, the tests get a commit of their own only when the
+	// coder committed its work.
+	dirty, err := git.HasUncommittedChanges(req.WorkDir)
+	if err != nil {
+		return nil, err
+	}
+	before, err := git.WorkTreeID(req.WorkDir)
+	if err != nil {
+		return nil, err
+	}
+
+	agent := ResolveTierAgent(a.Tiers, tw.Tier)
+	cliResult, err := a.runAgent(ctx, agent, testWriterPrompt(req, project.BaseBranch, result.Untested), req.WorkDir)
+	cliResult = a.accountUsage(agent, "", cliResult)
+	a.observeProvider(ctx, agent, "", cliResult, err)
+	result.Agent, result.Tokens = agent, cliResult.Tokens
+	if err != nil {
+		logger.Warn("Test writer agent failed", "Agent", agent, "BeadID", req.BeadID, "error", err)
+	}
+
+	after, err := git.WorkTreeID(req.WorkDir)
+	if err != nil {
+		return nil, err
+	}
+	result.Changed = after != before
+	if result.Changed && !dirty {
+		if result.Committed, err = git.CommitAll(req.WorkDir, tw.CommitMessage); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Info("Test writer ran", "BeadID", req.BeadID, "Untested", len(result.Untested),
+		"Changed", result.Changed, "Committed", result.Committed, "Agent", agent)
+	return result, nil
+}
+
+// untestedChanges returns the changed code files when none of the changed
+// files is a test, and nothing otherwise. Deleted files do not count.
+func untestedChanges(workDir string, changed []string, tw config.ProjectTestWriter) []string {
+	var code []string
+	for _, file := range changed {
+		name := path.Base(filepath.ToSlash(file))
+		if matchesAny(name, tw.TestPatterns) {
+			return nil
+		}
+		if !matchesAny(name, tw.CodePatterns) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(workDir, file)); err == nil {
+			code = append(code, file)
+		}
+	}
+	return code
+}
+
+func matchesAny(name string, patterns []string) bool {
+	return slices.ContainsFunc(patterns, func(pattern string) bool {
+		ok, _ := path.Match(pattern, name)
+		return ok
+	})
+}
+
+// testWriterPrompt asks the agent for tests of the listed files and
+// nothing else.
+func testWriterPrompt(req TaskRequest, baseBranch string, untested []string) string {
+	var sb strings.Builder
+	sb.WriteString("You are writing tests only. ")
+	if req.Prompt != "" {
+		sb.WriteString(fmt.Sprintf("Another agent made this change for task %s:\n\n%s\n\n", req.BeadID, truncate(req.Prompt, 2000)))
+	}
+	sb.WriteString("These changed files came without any test changes:\n")
+	for _, file := range untested {
+		sb.WriteString("- " + file + "\n")
+	}
+	sb.WriteString(fmt.Sprintf("\nRead the diff against %s and add tests covering the new and changed behaviour, following the project's existing test layout and style. ", baseBranch))
+	sb.WriteString("Run the new tests and make sure they pass. Do not change the code under test, and do not commit.")
+	return sb.String()
+}
@@ -0,0 +1,109 @@
+package temporal
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+func TestTestWriterActivity(t *testing.T) {
+	workDir := t.TempDir()
+	gitRun := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = workDir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		return strings.TrimSpace(string(out))
+	}
+	gitRun("init", "-b", "master")
+	gitRun("config", "user.email", "test@example.com")
+	gitRun("config", "user.name", "Test User")
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "README.md"), []byte("# app\n"), 0o644))
+	gitRun("add", ".")
+	gitRun("commit", "-m", "initial")
+	gitRun("checkout", "-b", "feature")
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "add.go"), []byte("package app\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "NOTES.md"), []byte("notes\n"), 0o644))
+	gitRun("add", ".")
+	gitRun("commit", "-m", "coder work")
+
+	// The fake agent writes a test for the change.
+	fakeBin := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(fakeBin, "claude"),
+		[]byte("#!/bin/sh\necho 'package app' > add_test.go\n"), 0o755))
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	acts := &Activities{
+		Tiers: config.Tiers{Balanced: []string{"claude"}},
+		Projects: map[string]config.Project{
+			"proj": {BaseBranch: "master", TestWriter: config.ProjectTestWriter{
+				Enabled:       true,
+				Tier:          "balanced",
+				CodePatterns:  config.DefaultTestWriterCodePatterns,
+				TestPatterns:  config.DefaultTestWriterTestPatterns,
+				CommitMessage: "test: add tests for untested changes",
+			}},
+		},
+	}
+	run := func() TestWriterResult {
+		s := testsuite.WorkflowTestSuite{}
+		env := s.NewTestActivityEnvironment()
+		env.RegisterActivity(acts.TestWriterActivity)
+		val, err := env.ExecuteActivity(acts.TestWriterActivity, TaskRequest{BeadID: "cx-1", Project: "proj", WorkDir: workDir})
+		require.NoError(t, err)
+		var res TestWriterResult
+		require.NoError(t, val.Get(&res))
+		return res
+	}
+
+	// Code without tests: the agent writes them and they get a commit.
+	res := run()
+	require.Equal(t, []string{"add.go"}, res.Untested)
+	require.Equal(t, "claude", res.Agent)
+	require.True(t, res.Changed)
+	require.True(t, res.Committed)
+	require.Equal(t, "test: add tests for untested changes", gitRun("log", "-1", "--format=%s"))
+	require.Empty(t, gitRun("status", "--porcelain"))
+
+	// The change now has tests: the stage does nothing.
+	tested := run()
+	require.Empty(t, tested.Untested)
+	require.Empty(t, tested.Agent)
+	require.False(t, tested.Committed)
+}
+
+func TestTestWriterActivitySkipsDisabledProject(t *testing.T) {
+	acts := &Activities{Projects: map[string]config.Project{"proj": {BaseBranch: "main"}}}
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(acts.TestWriterActivity)
+	val, err := env.ExecuteActivity(acts.TestWriterActivity, TaskRequest{BeadID: "cx-1", Project: "proj", WorkDir: t.TempDir()})
+	require.NoError(t, err)
+	var res TestWriterResult
+	require.NoError(t, val.Get(&res))
+	require.Equal(t, TestWriterResult{}, res)
+}
+
+func TestUntestedChanges(t *testing.T) {
+	workDir := t.TempDir()
+	for _, f := range []string{"api.go", "web/app.ts", "docs.md"} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(workDir, f)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(workDir, f), nil, 0o644))
+	}
+	tw := config.ProjectTestWriter{
+		CodePatterns: config.DefaultTestWriterCodePatterns,
+		TestPatterns: config.DefaultTestWriterTestPatterns,
+	}
+
+	require.Equal(t, []string{"api.go", "web/app.ts"}, untestedChanges(workDir, []string{"api.go", "deleted.go", "docs.md", "web/app.ts"}, tw))
+	require.Empty(t, untestedChanges(workDir, []string{"api.go", "api_test.go"}, tw))
+	require.Empty(t, untestedChanges(workDir, []string{"web/app.ts", "web/app.spec.ts"}, tw))
+	require.Empty(t, untestedChanges(workDir, []string{"docs.md"}, tw))
+}
@@ -0,0 +1,118 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.temporal.io/sdk/activity"
+
+	"github.com/antigravity-dev/cortex/internal/beads"
+)
+
+// BacklogTriageActivity labels the open beads that add noise to the
+// dispatch pool. A bead that reads like an older one is labeled
+// triage:duplicate and linked discovered-from the original, proposing a
+// merge; an open bead untouched for StaleAfter is labeled triage:stale.
+// Labeled beads drop out of FilterUnblockedOpen until a human removes the
+// label. Each project that had beads labeled gets a summary in its room and
+// a backlog_triage health event.
+func (a *Activities) BacklogTriageActivity(ctx context.Context, req TriageRequest) (*TriageResult, error) {
+	names := make([]string, 0, len(req.Projects))
+	for name := range req.Projects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := &TriageResult{}
+	now := time.Now()
+	for _, name := range names {
+		project := req.Projects[name]
+		report := triageProject(ctx, name, project.BeadsDir, req, now)
+		a.reportTriage(ctx, project.Room, report)
+		result.Projects = append(result.Projects, report)
+	}
+	return result, nil
+}
+
+func triageProject(ctx context.Context, name, beadsDir string, req TriageRequest, now time.Time) TriageReport {
+	logger := activity.GetLogger(ctx)
+	report := TriageReport{Project: name}
+
+	list, err := beads.ListBeadsCtx(ctx, beadsDir)
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+		return report
+	}
+	byID := make(map[string]beads.Bead, len(list))
+	for _, b := range list {
+		byID[b.ID] = b
+	}
+	label := func(id, value string) bool {
+		b := byID[id]
+		if err := beads.SetLabelsCtx(ctx, beadsDir, id, append(append([]string{}, b.Labels...), value)); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", id, err))
+			return false
+		}
+		b.Labels = append(b.Labels, value)
+		byID[id] = b
+		return true
+	}
+
+	for _, pair := range beads.FindDuplicates(list, req.Similarity) {
+		if !label(pair.Duplicate, beads.LabelDuplicate) {
+			continue
+		}
+		if err := beads.AddDiscoveredFromCtx(ctx, beadsDir, pair.Duplicate, pair.Original); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", pair.Duplicate, err))
+		}
+		logger.Info("Backlog triage: duplicate", "Project", name, "Bead", pair.Duplicate, "Original", pair.Original, "Score", pair.Score)
+		report.Duplicates = append(report.Duplicates, pair)
+	}
+
+	if req.StaleAfter > 0 {
+		for _, b := range beads.FindStale(list, req.StaleAfter, now) {
+			if beads.Triaged(byID[b.ID]) || !label(b.ID, beads.LabelStale) {
+				continue
+			}
+			logger.Info("Backlog triage: stale", "Project", name, "Bead", b.ID, "UpdatedAt", b.UpdatedAt)
+			report.Stale = append(report.Stale, b.ID)
+		}
+	}
+	return report
+}
+
+func (a *Activities) reportTriage(ctx context.Context, room string, report TriageReport) {
+	if len(report.Duplicates) == 0 && len(report.Stale) == 0 && len(report.Errors) == 0 {
+		return
+	}
+	msg := triageMessage(report)
+	if a.Store != nil {
+		a.Store.RecordHealthEvent("backlog_triage", msg)
+	}
+	if a.Sender != nil && room != "" {
+		if err := a.Sender.SendMessage(ctx, room, msg); err != nil {
+			activity.GetLogger(ctx).Warn("Backlog triage: report failed", "Room", room, "error", err)
+		}
+	}
+}
+
+func triageMessage(r TriageReport) string {
+	msg := fmt.Sprintf("Backlog triage (%s): %d duplicate(s), %d stale", r.Project, len(r.Duplicates), len(r.Stale))
+	if len(r.Duplicates) > 0 {
+		parts := make([]string, 0, len(r.Duplicates))
+		for _, d := range r.Duplicates {
+			parts = append(parts, fmt.Sprintf("%s duplicates %s (%.0f%%)", d.Duplicate, d.Original, d.Score*100))
+		}
+		msg += "\nMerge candidates: " + strings.Join(parts, ", ")
+	}
+	if len(r.Stale) > 0 {
+		msg += "\nStale: " + strings.Join(r.Stale, ", ")
+	}
+	if len(r.Errors) > 0 {
+		msg += fmt.Sprintf("\n%d error(s): %s", len(r.Errors), strings.Join(r.Errors, "; "))
+	}
+	return msg
+}
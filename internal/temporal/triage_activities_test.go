@@ -0,0 +1,73 @@
+package temporal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/beads"
+	"github.com/antigravity-dev/cortex/internal/store"
+)
+
+func TestBacklogTriageActivity(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+
+	workDir := t.TempDir()
+	logPath := filepath.Join(workDir, "bd.log")
+	fakeBin := t.TempDir()
+	recent := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	old := time.Now().Add(-60 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	list := fmt.Sprintf(`[
+		{"id":"a","title":"Fix login redirect loop","status":"open","created_at":%[2]q,"updated_at":%[1]q},
+		{"id":"b","title":"Fix the login redirect loop","status":"open","labels":["bug"],"created_at":%[1]q,"updated_at":%[2]q},
+		{"id":"c","title":"Add billing export","status":"open","created_at":%[2]q,"updated_at":%[2]q},
+		{"id":"d","title":"Write changelog","status":"open","created_at":%[2]q,"updated_at":%[1]q}
+	]`, recent, old)
+	script := "#!/bin/sh\necho \"$@\" >> \"$BD_ARGS_LOG\"\nif [ \"$1\" = list ]; then cat <<'JSON'\n" + list + "\nJSON\nfi\n"
+	require.NoError(t, os.WriteFile(filepath.Join(fakeBin, "bd"), []byte(script), 0o755))
+	t.Setenv("BD_ARGS_LOG", logPath)
+	t.Setenv("PATH", fakeBin+":"+os.Getenv("PATH"))
+
+	sender := &recordingSender{}
+	acts := &Activities{Store: st, Sender: sender}
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(acts.BacklogTriageActivity)
+	val, err := env.ExecuteActivity(acts.BacklogTriageActivity, TriageRequest{
+		Similarity: 0.8,
+		StaleAfter: 30 * 24 * time.Hour,
+		Projects:   map[string]BacklogProject{"cortex": {BeadsDir: filepath.Join(workDir, ".beads"), Room: "!room"}},
+	})
+	require.NoError(t, err)
+	var result TriageResult
+	require.NoError(t, val.Get(&result))
+
+	require.Len(t, result.Projects, 1)
+	report := result.Projects[0]
+	require.Empty(t, report.Errors)
+	require.Equal(t, []beads.DuplicatePair{{Original: "a", Duplicate: "b", Score: 1}}, report.Duplicates)
+	require.Equal(t, []string{"c"}, report.Stale, "b is already labeled duplicate")
+
+	args, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	require.Contains(t, string(args), "update b --set-labels bug,triage:duplicate --silent")
+	require.Contains(t, string(args), "dep add b a --type discovered-from")
+	require.Contains(t, string(args), "update c --set-labels triage:stale --silent")
+	require.NotContains(t, string(args), "update a ")
+	require.NotContains(t, string(args), "update d ")
+
+	require.Len(t, sender.messages, 1)
+	require.Contains(t, sender.messages[0], "b duplicates a (100%)")
+	require.Contains(t, sender.messages[0], "Stale: c")
+	events, err := st.GetRecentHealthEvents(1)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "backlog_triage", events[0].EventType)
+}
@@ -1,5 +1,12 @@
 package temporal
 
+import (
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/beads"
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
 // TaskRequest is submitted via the API to start a workflow.
 type TaskRequest struct {
 	BeadID    string   `json:"bead_id"`
@@ -10,6 +17,36 @@ type TaskRequest struct {
 	WorkDir   string   `json:"work_dir"`
 	Provider  string   `json:"provider"`
 	DoDChecks []string `json:"dod_checks"` // e.g. ["go build ./cmd/cortex", "go test ./..."]
+	Mode      string   `json:"mode,omitempty"` // "pair" requests a pair session; cleared if none is reserved
+	Attempt   int      `json:"attempt,omitempty"` // 1-based execution attempt, set by the workflow before each execute
+	Workspace string   `json:"workspace,omitempty"` // shared project checkout when WorkDir is a pooled worktree
+	SpentUSD  float64  `json:"spent_usd,omitempty"` // dispatch spend so far, set by the workflow before each agent run
+
+	// Set by the API when the bead's stage belongs to a custom project role:
+	// the role's prompt template replaces the coder's.
+	Role string `json:"role,omitempty"`
+
+	// Set by a routing rule: a review must approve the change, so a review
+	// that fails to run blocks the attempt instead of passing it.
+	RequireReview bool `json:"require_review,omitempty"`
+
+	// Set by the API when it claimed the bead; the workflow renews the claim
+	// every third of ClaimTTL and releases it when it ends.
+	ClaimHolder string        `json:"claim_holder,omitempty"`
+	ClaimTTL    time.Duration `json:"claim_ttl,omitempty"`
+
+	Experiments []ExperimentAssignment `json:"experiments,omitempty"` // set by AssignExperimentsActivity
+}
+
+// ExperimentAssignment records which variant of an experiment a task runs.
+// Prompt and Agent are the variant's overrides for Role; empty keeps the default.
+type ExperimentAssignment struct {
+	RunID      int64  `json:"run_id"`
+	Experiment string `json:"experiment"`
+	Variant    string `json:"variant"`
+	Role       string `json:"role"`
+	Prompt     string `json:"prompt,omitempty"`
+	Agent      string `json:"agent,omitempty"`
 }
 
 // DefaultReviewer returns the cross-model reviewer for a given primary agent.
@@ -70,7 +107,7 @@ func (p *StructuredPlan) Validate() []string {
 }
 
 // TokenUsage tracks LLM token consumption from a single CLI invocation.
-// Populated by parsing --output-format json from the claude CLI.
+// Populated by parsing the claude CLI's JSON output.
 type TokenUsage struct {
 	InputTokens         int     `json:"input_tokens"`
 	OutputTokens        int     `json:"output_tokens"`
@@ -98,10 +135,13 @@ type ActivityTokenUsage struct {
 
 // ExecutionResult is returned by the execute activity.
 type ExecutionResult struct {
-	ExitCode int        `json:"exit_code"`
-	Output   string     `json:"output"`
-	Agent    string     `json:"agent"` // which agent executed
-	Tokens   TokenUsage `json:"tokens"`
+	ExitCode   int          `json:"exit_code"`
+	Output     string       `json:"output"`
+	Agent      string       `json:"agent"` // which agent executed
+	Tokens     TokenUsage   `json:"tokens"`
+	Confidence Confidence   `json:"confidence"`
+	Report     *AgentReport `json:"report,omitempty"` // nil when the agent emitted no completion block
+	ActivityID string       `json:"activity_id,omitempty"` // the activity the agent's output was streamed under
 }
 
 // ReviewResult is returned by the cross-model code review activity.
@@ -119,6 +159,28 @@ type DoDResult struct {
 	Passed        bool     `json:"passed"`
 	Checks        []CheckResult `json:"checks"`
 	Failures      []string `json:"failures"`
+	Findings      []SecurityFinding `json:"findings,omitempty"` // security findings the dispatch introduced
+	Coverage      *float64          `json:"coverage,omitempty"` // total coverage in percent, when the project measures it
+}
+
+// AutofixResult is the outcome of the lint and format fixes applied before
+// review.
+type AutofixResult struct {
+	Checks    []CheckResult `json:"checks,omitempty"` // fix commands as run
+	Changed   bool          `json:"changed"`          // the fixes changed the work tree
+	Committed bool          `json:"committed"`        // the fixes got a commit of their own
+	Agent     string        `json:"agent,omitempty"`  // fast-tier agent asked to fix what the commands could not
+	Tokens    TokenUsage    `json:"tokens"`
+}
+
+// TestWriterResult is the outcome of the test-writer stage run before
+// review.
+type TestWriterResult struct {
+	Untested  []string   `json:"untested,omitempty"` // changed code files that came without test changes
+	Changed   bool       `json:"changed"`            // the agent changed the work tree
+	Committed bool       `json:"committed"`          // the tests got a commit of their own
+	Agent     string     `json:"agent,omitempty"`    // agent asked to write the tests
+	Tokens    TokenUsage `json:"tokens"`
 }
 
 // CheckResult is the result of a single DoD check command.
@@ -128,6 +190,18 @@ type CheckResult struct {
 	Output   string  `json:"output"`
 	Passed   bool    `json:"passed"`
 	DurationMs int64 `json:"duration_ms"`
+	Killed     bool  `json:"killed,omitempty"` // still running when the activity timed out
+}
+
+// SecurityFinding is a result a security scanner reported in a file the
+// dispatch changed.
+type SecurityFinding struct {
+	Scanner  string `json:"scanner"`
+	RuleID   string `json:"rule_id"`
+	Severity string `json:"severity"` // low, medium, high or critical
+	Path     string `json:"path,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Message  string `json:"message"`
 }
 
 // OutcomeRecord is passed to the store recording activity.
@@ -135,18 +209,32 @@ type OutcomeRecord struct {
 	DispatchID     int64                 `json:"dispatch_id"`
 	BeadID         string                `json:"bead_id"`
 	Project        string                `json:"project"`
+	WorkDir        string                `json:"work_dir,omitempty"` // where artifacts are collected from
 	Agent          string                `json:"agent"`
 	Reviewer       string                `json:"reviewer"`
 	Provider       string                `json:"provider"`
-	Status         string                `json:"status"` // completed, failed, escalated
+	Status         string                `json:"status"` // completed, failed, escalated, pending_retry
 	ExitCode       int                   `json:"exit_code"`
 	DurationS      float64               `json:"duration_s"`
 	DoDPassed      bool                  `json:"dod_passed"`
 	DoDFailures    string                `json:"dod_failures"`
+	DoDChecks      []CheckResult         `json:"dod_checks,omitempty"`
+	DoDFindings    []SecurityFinding     `json:"dod_findings,omitempty"`
 	Handoffs       int                   `json:"handoffs"` // how many cross-model review cycles
 	FilesChanged   int                   `json:"files_changed"`
 	TotalTokens    TokenUsage            `json:"total_tokens"`
 	ActivityTokens []ActivityTokenUsage   `json:"activity_tokens,omitempty"`
+	Experiments    []ExperimentAssignment `json:"experiments,omitempty"`
+	Confidence     Confidence             `json:"confidence"`
+	Report         *AgentReport           `json:"report,omitempty"` // the latest execution's completion block
+	OutputActivity string                 `json:"output_activity,omitempty"` // the latest execution's activity, whose streamed output is kept
+	HumanReview    bool                   `json:"human_review"`     // passed DoD but held back from auto-close
+	Mode           string                 `json:"mode,omitempty"`
+	PR             *CreatePRResult        `json:"pr,omitempty"` // the PR opened for the bead's branch
+
+	// Set when the dispatch stopped for a reason the retry policy acts on.
+	FailureCategory string `json:"failure_category,omitempty"`
+	FailureSummary  string `json:"failure_summary,omitempty"`
 }
 
 // EscalationRequest is sent to the chief when DoD fails after retries.
@@ -184,7 +272,8 @@ type BacklogItem struct {
 // BacklogPresentation is the chief's groomed view of the backlog.
 type BacklogPresentation struct {
 	Items     []BacklogItem `json:"items"`
-	Rationale string        `json:"rationale"` // "here's what we think and why"
+	Rationale string        `json:"rationale"`         // "here's what we think and why"
+	ScanID    int64         `json:"scan_id,omitempty"` // persisted candidate scan, 0 if not recorded
 }
 
 // PlanningQuestion is a single clarifying question, asked one at a time.
@@ -307,6 +396,37 @@ type StrategicGroomRequest struct {
 	Tier     string `json:"tier"` // "premium" for strategic
 }
 
+// ChiefRequest is passed to ChiefWorkflow, a project's scheduled chief
+// dispatch.
+type ChiefRequest struct {
+	Project        string `json:"project"`
+	WorkDir        string `json:"work_dir"`
+	BeadsDir       string `json:"beads_dir"`
+	Agent          string `json:"agent"`
+	SprintCapacity int    `json:"sprint_capacity,omitempty"` // beads proposed for the sprint; 0 leaves it to the chief
+}
+
+// StrategicGroomControlRequest drives StrategicGroomControlWorkflow, the
+// long-running per-project groom scheduler.
+type StrategicGroomControlRequest struct {
+	Groom    StrategicGroomRequest `json:"groom"`
+	Schedule string                `json:"schedule"` // standard 5-field cron, UTC
+	State    *StrategicGroomState  `json:"state,omitempty"` // carried across continue-as-new
+}
+
+// StrategicGroomState is what the groom-status query returns.
+type StrategicGroomState struct {
+	Project     string    `json:"project"`
+	Schedule    string    `json:"schedule"`
+	Paused      bool      `json:"paused"`
+	Running     bool      `json:"running"`
+	NextRunAt   time.Time `json:"next_run_at,omitempty"` // zero while paused
+	LastRunAt   time.Time `json:"last_run_at,omitempty"`
+	LastTrigger string    `json:"last_trigger,omitempty"` // "schedule" or "manual"
+	LastError   string    `json:"last_error,omitempty"`
+	Runs        int       `json:"runs"`
+}
+
 // RepoMap is a compressed representation of the codebase for LLM context.
 // Generated by go list/go doc — keeps the full codebase under ~3k tokens.
 type RepoMap struct {
@@ -350,3 +470,433 @@ type MorningBriefing struct {
 	RecentLessons []Lesson        `json:"recent_lessons"`
 	Markdown      string          `json:"markdown"` // full rendered markdown
 }
+
+// FailureClusterReportRequest drives the weekly FailureClusterReportWorkflow.
+type FailureClusterReportRequest struct {
+	WindowDays int    `json:"window_days"` // default 7
+	Limit      int    `json:"limit"`       // top clusters to report, default 10
+	ReportDir  string `json:"report_dir"`  // where failure-clusters-<date>.md is written
+}
+
+// FailureClusterReportResult summarizes a generated failure cluster report.
+type FailureClusterReportResult struct {
+	Path     string `json:"path"`
+	Clusters int    `json:"clusters"`
+	Failures int    `json:"failures"`
+}
+
+// --- Provider Warmup Types ---
+
+// WarmupRequest drives ProviderWarmupWorkflow. On "startup" every listed
+// provider is pinged; on "idle" only providers inactive for IdleAfter are.
+type WarmupRequest struct {
+	Providers []string          `json:"providers"`
+	CLIs      map[string]string `json:"clis,omitempty"` // provider -> CLI binary; defaults to the provider name
+	Reason    string            `json:"reason"`         // startup, idle
+	IdleAfter time.Duration     `json:"idle_after"`
+	Timeout   time.Duration     `json:"timeout"`
+}
+
+// WarmupResult records a single warmup ping. Warmups are stored in their own
+// table and never count toward dispatch duration, failure, or cost metrics.
+type WarmupResult struct {
+	Provider  string  `json:"provider"`
+	DurationS float64 `json:"duration_s"`
+	Success   bool    `json:"success"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// --- Pull Request Types ---
+
+// CreatePRRequest asks CreatePRActivity to open a PR for a bead's branch.
+type CreatePRRequest struct {
+	BeadID     string        `json:"bead_id"`
+	Project    string        `json:"project"`
+	WorkDir    string        `json:"work_dir"`
+	Branch     string        `json:"branch"`
+	BaseBranch string        `json:"base_branch"`      // the project's base branch when empty
+	Checks     []CheckResult `json:"checks,omitempty"` // DoD results to report in the PR body
+	Draft      bool          `json:"draft,omitempty"`  // open as a draft while the coder iterates
+}
+
+// CreatePRResult is the opened pull request.
+type CreatePRResult struct {
+	URL    string `json:"url"`
+	Number int    `json:"number"`
+}
+
+// PostMergeRequest starts PostMergeWorkflow for a merged PR.
+type PostMergeRequest struct {
+	Project string `json:"project"`
+	BeadID  string `json:"bead_id"`
+	PR      int    `json:"pr"`
+	Commit  string `json:"commit"` // the merge commit on the base branch; empty checks the base branch without reverting
+}
+
+// PostMergeResult reports a merged PR's post-merge checks and whether the
+// merge was reverted.
+type PostMergeResult struct {
+	Passed    bool     `json:"passed"`
+	Failures  []string `json:"failures,omitempty"`
+	Revert    bool     `json:"revert"`               // the checks failed and the project reverts failed merges
+	DecidedBy string   `json:"decided_by,omitempty"` // who answered the revert prompt: signal or timeout
+	Reverted  bool     `json:"reverted"`
+}
+
+// --- Stalled Review Types ---
+
+// ReviewProject carries what the stalled review check needs per project.
+type ReviewProject struct {
+	Workspace string `json:"workspace"`
+	Room      string `json:"room"`
+}
+
+// StalledReviewRequest drives StalledReviewWorkflow.
+type StalledReviewRequest struct {
+	Threshold time.Duration            `json:"threshold"`
+	Reassign  bool                     `json:"reassign"`
+	Projects  map[string]ReviewProject `json:"projects"`
+}
+
+// StalledReview is a Cortex PR that waited past the threshold without review.
+type StalledReview struct {
+	Project    string  `json:"project"`
+	BeadID     string  `json:"bead_id"`
+	PRNumber   int     `json:"pr_number"`
+	PRURL      string  `json:"pr_url"`
+	WaitingH   float64 `json:"waiting_h"`
+	Reviewer   string  `json:"reviewer"`
+	Reassigned bool    `json:"reassigned"`
+}
+
+// StalledReviewResult summarizes one stalled review check.
+type StalledReviewResult struct {
+	Checked  int             `json:"checked"`
+	Reviewed int             `json:"reviewed"`
+	Closed   int             `json:"closed"`
+	Stalled  []StalledReview `json:"stalled"`
+}
+
+// ReviewThread is an unresolved review thread on a bead's pull request.
+type ReviewThread struct {
+	ID     string `json:"id"`
+	Path   string `json:"path"`
+	Line   int    `json:"line"`
+	Author string `json:"author"`
+	Body   string `json:"body"` // the thread's comments, one per line
+}
+
+// ReviewFeedback is what reviewers left unresolved on a bead's open PR, fed
+// to the coder when the bead is dispatched again.
+type ReviewFeedback struct {
+	Project  string         `json:"project"`
+	PRNumber int            `json:"pr_number"`
+	Threads  []ReviewThread `json:"threads,omitempty"`
+}
+
+// --- Standup Digest Types ---
+
+// StandupProject carries what the standup digest needs per project.
+type StandupProject struct {
+	BeadsDir string `json:"beads_dir"`
+	Room     string `json:"room"`
+}
+
+// StandupDigestRequest drives StandupDigestWorkflow.
+type StandupDigestRequest struct {
+	Window   time.Duration             `json:"window"`
+	Projects map[string]StandupProject `json:"projects"`
+}
+
+// StandupDigestResult summarizes one digest run.
+type StandupDigestResult struct {
+	Date     string   `json:"date"`
+	Projects []string `json:"projects"`
+	Posted   int      `json:"posted"`
+}
+
+// --- Stage SLA Types ---
+
+// StageLimit is a workflow stage's max time-in-stage.
+type StageLimit struct {
+	MaxDuration time.Duration `json:"max_duration"`
+	Escalate    bool          `json:"escalate"` // raise the tier of the bead's next dispatch on breach
+}
+
+// StageSLARequest drives StageSLAWorkflow. Limits are keyed by workflow,
+// then stage name; Projects maps each checked project to its room.
+type StageSLARequest struct {
+	Limits   map[string]map[string]StageLimit `json:"limits"`
+	Projects map[string]string                `json:"projects"`
+}
+
+// StageBreach is a bead that stayed in a stage past its max_duration.
+type StageBreach struct {
+	Project   string  `json:"project"`
+	BeadID    string  `json:"bead_id"`
+	Workflow  string  `json:"workflow"`
+	Stage     string  `json:"stage"`
+	InStageH  float64 `json:"in_stage_h"`
+	LimitH    float64 `json:"limit_h"`
+	Escalated bool    `json:"escalated"`
+}
+
+// StageSLAResult summarizes one stage SLA check.
+type StageSLAResult struct {
+	Checked  int           `json:"checked"`
+	Breaches []StageBreach `json:"breaches"`
+}
+
+// --- Epic Rollup Types ---
+
+// BacklogProject carries what the epic rollup and backlog triage need per
+// project.
+type BacklogProject struct {
+	BeadsDir string `json:"beads_dir"`
+	Room     string `json:"room"`
+}
+
+// EpicRollupRequest drives EpicRollupWorkflow. An epic is closed once the
+// closed share of its executable children reaches Threshold.
+type EpicRollupRequest struct {
+	Threshold float64                   `json:"threshold"`
+	Projects  map[string]BacklogProject `json:"projects"`
+}
+
+// EpicProgress is one open epic's child completion.
+type EpicProgress struct {
+	Project    string `json:"project"`
+	EpicID     string `json:"epic_id"`
+	Title      string `json:"title"`
+	Closed     int    `json:"closed"`
+	Total      int    `json:"total"` // executable (non-epic) children
+	AutoClosed bool   `json:"auto_closed"`
+}
+
+// EpicRollupResult summarizes one epic rollup.
+type EpicRollupResult struct {
+	Epics []EpicProgress `json:"epics"`
+}
+
+// --- Backlog Triage Types ---
+
+// TriageRequest drives BacklogTriageWorkflow. Projects maps each triaged
+// project to its beads dir and room.
+type TriageRequest struct {
+	Similarity float64                   `json:"similarity"`
+	StaleAfter time.Duration             `json:"stale_after"` // 0 skips the stale check
+	Projects   map[string]BacklogProject `json:"projects"`
+}
+
+// TriageReport is what the triage labeled in one project.
+type TriageReport struct {
+	Project    string                `json:"project"`
+	Duplicates []beads.DuplicatePair `json:"duplicates,omitempty"`
+	Stale      []string              `json:"stale,omitempty"`
+	Errors     []string              `json:"errors,omitempty"`
+}
+
+// TriageResult summarizes one backlog triage.
+type TriageResult struct {
+	Projects []TriageReport `json:"projects"`
+}
+
+// --- File Overlap Types ---
+
+// FileOverlapRequest drives FileOverlapWorkflow.
+type FileOverlapRequest struct {
+	Mode         string                    `json:"mode"` // "warn" or "serialize"
+	LookbackDays int                       `json:"lookback_days"`
+	Projects     map[string]JanitorProject `json:"projects"`
+}
+
+// FileOverlapReport is what the overlap check found in one project.
+type FileOverlapReport struct {
+	Project    string              `json:"project"`
+	Ready      int                 `json:"ready"`  // ready beads checked
+	Mapped     int                 `json:"mapped"` // ready beads with known files
+	Overlaps   []beads.FileOverlap `json:"overlaps,omitempty"`
+	Serialized []string            `json:"serialized,omitempty"` // beads made to wait on their overlap
+	Errors     []string            `json:"errors,omitempty"`
+}
+
+// FileOverlapResult summarizes one overlap check.
+type FileOverlapResult struct {
+	Projects []FileOverlapReport `json:"projects"`
+}
+
+// --- Branch Janitor Types ---
+
+// JanitorProject carries what the branch janitor and the file overlap check
+// need per project.
+type JanitorProject struct {
+	Workspace  string   `json:"workspace"`
+	BeadsDir   string   `json:"beads_dir"`
+	BaseBranch string   `json:"base_branch"`
+	Prefixes   []string `json:"prefixes"` // branch prefixes Cortex creates, e.g. "feat/"
+	Room       string   `json:"room"`
+}
+
+// BranchJanitorRequest drives BranchJanitorWorkflow.
+type BranchJanitorRequest struct {
+	GracePeriod  time.Duration             `json:"grace_period"`
+	Protected    []string                  `json:"protected"`
+	DeleteRemote bool                      `json:"delete_remote"`
+	DryRun       bool                      `json:"dry_run"`
+	Projects     map[string]JanitorProject `json:"projects"`
+}
+
+// JanitorBranch is a branch the janitor deleted (or would delete in a dry run).
+type JanitorBranch struct {
+	Branch string `json:"branch"`
+	BeadID string `json:"bead_id"`
+	Reason string `json:"reason"` // "bead closed" or "bead missing"
+}
+
+// BranchJanitorReport is the cleanup outcome for one project.
+type BranchJanitorReport struct {
+	Project string          `json:"project"`
+	Scanned int             `json:"scanned"` // Cortex branches considered
+	Kept    int             `json:"kept"`
+	Deleted []JanitorBranch `json:"deleted"`
+	Errors  []string        `json:"errors,omitempty"`
+	DryRun  bool            `json:"dry_run"`
+}
+
+// BranchJanitorResult summarizes one janitor run, one report per project.
+type BranchJanitorResult struct {
+	Projects []BranchJanitorReport `json:"projects"`
+}
+
+// --- Burn-in Report Types ---
+
+// BurnInReportRequest drives BurnInReportWorkflow.
+type BurnInReportRequest struct {
+	Window     time.Duration               `json:"window"`
+	ReportsDir string                      `json:"reports_dir"`
+	SLOs       map[string]config.BurnInSLO `json:"slos"` // gates per project
+	Room       string                      `json:"room"` // notified when a gate fails; empty skips it
+}
+
+// BurnInReportResult points at the artifacts one report run wrote.
+type BurnInReportResult struct {
+	Date         string   `json:"date"`
+	Pass         bool     `json:"pass"`
+	Failing      []string `json:"failing,omitempty"` // projects with a failing gate
+	JSONPath     string   `json:"json_path"`
+	MarkdownPath string   `json:"markdown_path"`
+}
+
+// --- Cost Report Types ---
+
+// CostReportRequest drives CostReportWorkflow.
+type CostReportRequest struct {
+	ReportsDir string `json:"reports_dir"`
+	Month      string `json:"month,omitempty"` // YYYY-MM; empty reports the previous month
+}
+
+// CostReportResult points at the artifacts one report run wrote.
+type CostReportResult struct {
+	Month        string  `json:"month"`
+	CostUSD      float64 `json:"cost_usd"`
+	Projects     int     `json:"projects"`
+	JSONPath     string  `json:"json_path"`
+	MarkdownPath string  `json:"markdown_path"`
+}
+
+// --- Rollout Completion Types ---
+
+// RolloutCompletionRequest drives RolloutCompletionWorkflow.
+type RolloutCompletionRequest struct {
+	Criteria  config.HealthRollout `json:"criteria"`
+	BeadsDir  string               `json:"beads_dir"`  // beads dir of the project holding the critical beads
+	BurnInDir string               `json:"burnin_dir"` // burn-in reports dir, read when burn-in is required
+}
+
+// --- Confidence Types ---
+
+// Confidence is the score (0-1) an agent reports in its completion block, or
+// failing that its CONFIDENCE footer. Reported is false when neither has one.
+type Confidence struct {
+	Score    float64 `json:"score"`
+	Reported bool    `json:"reported"`
+}
+
+// AgentReport is the completion block an agent ends its output with, as
+// asked by resultContract.
+type AgentReport struct {
+	FilesChanged []string  `json:"files_changed"`
+	TestsRun     []TestRun `json:"tests_run"`
+	Confidence   *float64  `json:"confidence,omitempty"`
+	FollowUps    []string  `json:"follow_ups"`
+}
+
+// TestRun is a test command an agent reports having run.
+type TestRun struct {
+	Command string `json:"command"`
+	Passed  bool   `json:"passed"`
+}
+
+// FollowUpRequest asks CreateFollowUpBeadsActivity to file the follow-ups
+// an agent reported for a bead.
+type FollowUpRequest struct {
+	BeadID    string   `json:"bead_id"`
+	Project   string   `json:"project"`
+	WorkDir   string   `json:"work_dir"`
+	Agent     string   `json:"agent"`
+	FollowUps []string `json:"follow_ups"`
+}
+
+// FollowUpResult lists the follow-up beads created, and the open beads that
+// already covered a follow-up.
+type FollowUpResult struct {
+	Created []string `json:"created,omitempty"`
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// CompletionRequest asks CompleteTaskActivity to close out a task that
+// passed review and DoD.
+type CompletionRequest struct {
+	BeadID     string     `json:"bead_id"`
+	Project    string     `json:"project"`
+	WorkDir    string     `json:"work_dir"`
+	Agent      string     `json:"agent"`
+	Confidence Confidence `json:"confidence"`
+}
+
+// CompletionResult reports how a passing task was closed out.
+type CompletionResult struct {
+	AutoClosed  bool   `json:"auto_closed"`
+	HumanReview bool   `json:"human_review"` // left open for a human to verify
+	Reason      string `json:"reason,omitempty"`
+}
+
+// --- Pair Mode Types ---
+
+// PairPlan is the outcome of ReservePairActivity. When Active, the task runs
+// as one pair session holding a provider reservation for each agent.
+type PairPlan struct {
+	Active           bool   `json:"active"`
+	CoderProvider    string `json:"coder_provider,omitempty"`
+	ReviewerProvider string `json:"reviewer_provider,omitempty"`
+	MaxTurns         int    `json:"max_turns"`
+	Reason           string `json:"reason,omitempty"` // why pair mode was not used
+}
+
+// PairTurn is one coder or reviewer turn of a pair session.
+type PairTurn struct {
+	Turn   int    `json:"turn"`
+	Role   string `json:"role"` // coder, reviewer
+	Agent  string `json:"agent"`
+	Output string `json:"output"`
+}
+
+// PairSessionResult is returned by PairSessionActivity. Execution and Review
+// hold the last coder and reviewer turns, with tokens summed over all turns
+// of each role.
+type PairSessionResult struct {
+	Execution ExecutionResult `json:"execution"`
+	Review    ReviewResult    `json:"review"`
+	Approved  bool            `json:"approved"`
+	Turns     []PairTurn      `json:"turns"`
+}
@@ -0,0 +1,48 @@
+package temporal
+
+import (
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/cost"
+)
+
+func tokenUsageFrom(u cost.TokenUsage) TokenUsage {
+	return TokenUsage{
+		InputTokens:         u.Input,
+		OutputTokens:        u.Output,
+		CacheReadTokens:     u.CacheRead,
+		CacheCreationTokens: u.CacheCreation,
+		CostUSD:             u.CostUSD,
+	}
+}
+
+// usageProvider returns the provider an agent run is billed to.
+func (a *Activities) usageProvider(agent, preferred string) (config.Provider, bool) {
+	names := agentProviders(a.Providers, agent, preferred)
+	if len(names) == 0 {
+		return config.Provider{}, false
+	}
+	return a.Providers[names[0]], true
+}
+
+// accountUsage settles the token usage of a finished run against its
+// provider. A provider with its own usage_format has the run's output parsed
+// in that format when the CLI's default found no report, and usage the CLI
+// did not price is priced at the provider's rates.
+func (a *Activities) accountUsage(agent, preferred string, result CLIResult) CLIResult {
+	p, ok := a.usageProvider(agent, preferred)
+	if !ok {
+		return result
+	}
+	if result.Tokens.InputTokens == 0 && result.Tokens.OutputTokens == 0 && p.UsageFormat != "" {
+		if usage, ok := cost.ParserFor(p.UsageFormat, agent)(result.Output); ok {
+			result.Tokens = tokenUsageFrom(usage)
+		}
+	}
+	if result.Tokens.CostUSD == 0 {
+		result.Tokens.CostUSD = cost.CalculateCost(cost.TokenUsage{
+			Input:  result.Tokens.InputTokens,
+			Output: result.Tokens.OutputTokens,
+		}, p.CostInputPerMtok, p.CostOutputPerMtok)
+	}
+	return result
+}
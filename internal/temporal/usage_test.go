@@ -0,0 +1,41 @@
+package temporal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+func TestParseAgentOutputReadsCodexFooter(t *testing.T) {
+	result := parseAgentOutput("codex", "implemented it\ntokens used: 4,000")
+	require.Equal(t, 4000, result.Tokens.InputTokens)
+	require.Contains(t, result.Output, "implemented it")
+}
+
+func TestAccountUsage(t *testing.T) {
+	acts := &Activities{Providers: map[string]config.Provider{
+		"codex":    {CostInputPerMtok: 2, CostOutputPerMtok: 8},
+		"gateway":  {CLI: "openclaw", UsageFormat: "openclaw", CostInputPerMtok: 1, CostOutputPerMtok: 1},
+		"reported": {CLI: "claude", CostInputPerMtok: 100},
+	}}
+
+	// Usage from the CLI's default parser is priced at the provider's rates.
+	result := acts.accountUsage("codex", "", parseAgentOutput("codex", "tokens used: 1000000"))
+	require.InDelta(t, 2.0, result.Tokens.CostUSD, 1e-9)
+
+	// A provider's usage_format is used when the CLI default found nothing,
+	// and a reported cost is kept.
+	result = acts.accountUsage("openclaw", "gateway", CLIResult{Output: "Tokens: 10 input, 20 output\nCost: $0.5"})
+	require.Equal(t, 10, result.Tokens.InputTokens)
+	require.Equal(t, 20, result.Tokens.OutputTokens)
+	require.InDelta(t, 0.5, result.Tokens.CostUSD, 1e-9)
+
+	result = acts.accountUsage("claude", "reported", CLIResult{Tokens: TokenUsage{InputTokens: 1000000, CostUSD: 0.3}})
+	require.InDelta(t, 0.3, result.Tokens.CostUSD, 1e-9)
+
+	// Unknown agents are left alone.
+	result = acts.accountUsage("aider", "", CLIResult{Tokens: TokenUsage{InputTokens: 5}})
+	require.Zero(t, result.Tokens.CostUSD)
+}
@@ -0,0 +1,97 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"go.temporal.io/sdk/activity"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/dispatch"
+)
+
+// warmupPrompt is deliberately trivial: the goal is to pay auth refresh and
+// model load costs, not to do work.
+const warmupPrompt = "Reply with the single word OK. Do not run any tools or modify any files."
+
+// ColdProvidersActivity filters req.Providers down to those needing a warmup ping.
+// Startup warmups ping everything; idle warmups skip providers used within IdleAfter.
+func (a *Activities) ColdProvidersActivity(ctx context.Context, req WarmupRequest) ([]string, error) {
+	logger := activity.GetLogger(ctx)
+
+	if req.Reason == "startup" || req.IdleAfter <= 0 || a.Store == nil {
+		return req.Providers, nil
+	}
+
+	now := time.Now().UTC()
+	var cold []string
+	for _, provider := range req.Providers {
+		lastActive, err := a.Store.GetProviderLastActive(provider)
+		if err != nil {
+			logger.Warn("Warmup: could not read provider activity, treating as cold", "Provider", provider, "error", err)
+			cold = append(cold, provider)
+			continue
+		}
+		if lastActive.IsZero() || now.Sub(lastActive) >= req.IdleAfter {
+			cold = append(cold, provider)
+		}
+	}
+	return cold, nil
+}
+
+// WarmupProviderActivity sends a trivial prompt through the provider's CLI and
+// records the latency. Failures are returned in the result, not as errors, so
+// a broken provider never fails the warmup workflow.
+func (a *Activities) WarmupProviderActivity(ctx context.Context, provider, cli, reason string) (*WarmupResult, error) {
+	logger := activity.GetLogger(ctx)
+	logger.Info("Warmup: pinging provider", "Provider", provider, "CLI", cli, "Reason", reason)
+
+	start := time.Now()
+	_, err := a.runAgent(ctx, cli, warmupPrompt, os.TempDir())
+	result := &WarmupResult{
+		Provider:  provider,
+		DurationS: time.Since(start).Seconds(),
+		Success:   err == nil,
+	}
+	if err != nil {
+		result.Error = truncate(strings.TrimSpace(err.Error()), 500)
+		logger.Warn("Warmup: provider ping failed", "Provider", provider, "DurationS", result.DurationS, "error", err)
+	} else {
+		logger.Info("Warmup: provider warm", "Provider", provider, "DurationS", result.DurationS)
+	}
+
+	if a.Store != nil {
+		if _, recErr := a.Store.RecordProviderWarmup(provider, reason, start, result.DurationS, result.Success, result.Error); recErr != nil {
+			logger.Error("Warmup: failed to record warmup", "Provider", provider, "error", recErr)
+		}
+	}
+	return result, nil
+}
+
+// preflightProbe returns the dispatch pre-flight probe cfg describes: its
+// command when one is set, run with CORTEX_PROVIDER naming the provider,
+// otherwise the warmup prompt sent through the provider's CLI.
+func preflightProbe(cfg config.DispatchPreflight) dispatch.ProbeFunc {
+	command := strings.TrimSpace(cfg.Command)
+	return func(ctx context.Context, name string, p config.Provider) error {
+		if command == "" {
+			cli := strings.TrimSpace(p.CLI)
+			if cli == "" {
+				cli = name
+			}
+			// Not streamed: the probe runs inside the dispatch it gates.
+			_, err := runCLI(ctx, nil, cli, cliCommand(cli, warmupPrompt, os.TempDir()))
+			return err
+		}
+		cmd := exec.CommandContext(ctx, "sh", "-c", command)
+		cmd.Env = append(os.Environ(), "CORTEX_PROVIDER="+name)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%w: %s", err, truncate(strings.TrimSpace(string(out)), 200))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,20 @@
+package temporal
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+func TestPreflightProbeCommand(t *testing.T) {
+	probe := preflightProbe(config.DispatchPreflight{Command: `test "$CORTEX_PROVIDER" = claude || { echo "gateway closed for $CORTEX_PROVIDER"; exit 1; }`})
+	if err := probe(context.Background(), "claude", config.Provider{}); err != nil {
+		t.Fatalf("probe claude: %v", err)
+	}
+	err := probe(context.Background(), "codex", config.Provider{})
+	if err == nil || !strings.Contains(err.Error(), "gateway closed for codex") {
+		t.Fatalf("probe codex = %v, want the command's output", err)
+	}
+}
@@ -1,29 +1,111 @@
 package temporal
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"path/filepath"
+	"sync/atomic"
+	"time"
 
-	"go.temporal.io/sdk/client"
 	"go.temporal.io/sdk/worker"
 
+	"github.com/antigravity-dev/cortex/internal/autoscale"
+	"github.com/antigravity-dev/cortex/internal/calendar"
 	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/dispatch"
+	"github.com/antigravity-dev/cortex/internal/email"
+	"github.com/antigravity-dev/cortex/internal/journal"
+	"github.com/antigravity-dev/cortex/internal/lease"
+	"github.com/antigravity-dev/cortex/internal/matrix"
+	"github.com/antigravity-dev/cortex/internal/notify"
 	"github.com/antigravity-dev/cortex/internal/store"
+	"github.com/antigravity-dev/cortex/internal/webhook"
 )
 
+// activeRateLimiter is the running worker's rate limiter, read by the API.
+var activeRateLimiter atomic.Pointer[dispatch.RateLimiter]
+
+// ProviderRateLimiter returns the running worker's rate limiter, or nil
+// before the worker has started or when it runs without a store.
+func ProviderRateLimiter() *dispatch.RateLimiter {
+	return activeRateLimiter.Load()
+}
+
 // StartWorker connects to Temporal and starts the cortex task queue worker.
-// The store and tiers are injected so activities can record outcomes and resolve agents.
-func StartWorker(st *store.Store, tiers config.Tiers) error {
-	c, err := client.Dial(client.Options{
-		HostPort: "127.0.0.1:7233",
-	})
+// The store and config are injected so activities can record outcomes, resolve
+// agents, and apply per-project prompt templates and experiments. Decisions
+// the activities make are recorded in jr, the claims the API takes on
+// beads are renewed and released through claims, and dispatches take their
+// coder and reviewer slots from slots; any of them may be nil.
+//
+// The worker stops polling when ctx is cancelled. In-flight activities are
+// left running so the caller can DrainAgents; Temporal only cancels them once
+// the drain window and interrupt grace have both passed.
+func StartWorker(ctx context.Context, st *store.Store, cfg *config.Config, jr *journal.Journal, claims *lease.Claims, slots *autoscale.Controller) error {
+	c, err := Dial(cfg.Temporal)
 	if err != nil {
 		return err
 	}
 	defer c.Close()
 
-	w := worker.New(c, "cortex-task-queue", worker.Options{})
+	w := worker.New(c, cfg.Temporal.TaskQueue, worker.Options{
+		WorkerStopTimeout: cfg.General.ShutdownDrain.Duration + drainInterruptGrace + 5*time.Second,
+	})
+
+	var sender matrix.Sender = matrix.NewOpenClawSender(nil, cfg.Reporter.MatrixBotAccount)
+	switch {
+	case cfg.Reporter.Channel == "email":
+		es := email.NewSender(cfg.Reporter.Email, cfg.EmailRecipients)
+		go es.Run(ctx, nil)
+		sender = es
+	case cfg.Matrix.E2EProxy != "":
+		// No OpenClaw fallback here: it would post plaintext into encrypted rooms.
+		sender = matrix.NewE2ESender(nil, cfg.Reporter.MatrixBotAccount, cfg.Matrix.E2EProxy)
+	}
+	if hooks, err := webhook.NewSender(cfg); err != nil {
+		log.Printf("Webhook reporter disabled: %v", err)
+	} else {
+		sender = webhook.Tee(sender, hooks)
+	}
+	throttle := notify.NewThrottle(cfg.Reporter.Throttle, sender)
+	go throttle.Run(ctx, nil)
+	sender = throttle
+
+	acts := &Activities{
+		Store:       st,
+		Tiers:       cfg.Tiers,
+		Projects:    cfg.Projects,
+		Experiments: cfg.Experiments,
+		Workflows:   cfg.Workflows,
+		Confidence:  cfg.Dispatch.Confidence,
+		Pair:        cfg.Dispatch.Pair,
+		Providers:   cfg.Providers,
+		Sender:      sender,
+		Artifacts:   cfg.Dispatch.Artifacts,
+		Journal:     jr,
+		Claims:      claims,
+		Holidays:    loadHolidays(ctx, cfg.Cadence),
+		Slots:       slots,
 
-	acts := &Activities{Store: st, Tiers: tiers}
+		General:       cfg.General,
+		Cadence:       cfg.Cadence,
+		ResponseCache: cfg.Dispatch.ResponseCache,
+		CostControl:   cfg.Dispatch.CostControl,
+		Approvals:     cfg.Dispatch.Approvals,
+		FollowUps:     cfg.Dispatch.FollowUps,
+		Git:           cfg.Dispatch.Git,
+	}
+	if cfg.Dispatch.Worktrees.Enabled {
+		acts.Worktrees = dispatch.NewWorktreePool(cfg.Dispatch.Worktrees)
+	}
+	if st != nil {
+		acts.RateLimiter = dispatch.NewRateLimiter(st, cfg.RateLimits)
+		acts.RateLimiter.SetCircuitBreakers(cfg.Dispatch.CircuitBreakers)
+		acts.RateLimiter.SetPreflight(cfg.Dispatch.Preflight, preflightProbe(cfg.Dispatch.Preflight))
+		activeRateLimiter.Store(acts.RateLimiter)
+		startAgentCheckpoints(st, cfg)
+	}
 
 	// --- Core Workflows ---
 	w.RegisterWorkflow(CortexAgentWorkflow)
@@ -33,15 +115,62 @@ func StartWorker(st *store.Store, tiers config.Tiers) error {
 	w.RegisterWorkflow(ContinuousLearnerWorkflow)
 	w.RegisterWorkflow(TacticalGroomWorkflow)
 	w.RegisterWorkflow(StrategicGroomWorkflow)
+	w.RegisterWorkflow(StrategicGroomControlWorkflow)
+	w.RegisterWorkflow(ChiefWorkflow)
+
+	// --- Failure Clustering ---
+	w.RegisterWorkflow(FailureClusterReportWorkflow)
+
+	// --- Provider Warmup ---
+	w.RegisterWorkflow(ProviderWarmupWorkflow)
+
+	// --- Stalled Reviews ---
+	w.RegisterWorkflow(StalledReviewWorkflow)
+	w.RegisterWorkflow(PostMergeWorkflow)
+	w.RegisterWorkflow(StageSLAWorkflow)
+	w.RegisterWorkflow(EpicRollupWorkflow)
+	w.RegisterWorkflow(BacklogTriageWorkflow)
+	w.RegisterWorkflow(FileOverlapWorkflow)
+	w.RegisterWorkflow(StandupDigestWorkflow)
+
+	// --- Branch Janitor ---
+	w.RegisterWorkflow(BranchJanitorWorkflow)
+
+	// --- Burn-in Reports ---
+	w.RegisterWorkflow(BurnInReportWorkflow)
+	w.RegisterWorkflow(CostReportWorkflow)
+
+	// --- Rollout Completion ---
+	w.RegisterWorkflow(RolloutCompletionWorkflow)
 
 	// --- Core Activities ---
+	w.RegisterActivity(acts.AssignExperimentsActivity)
 	w.RegisterActivity(acts.StructuredPlanActivity)
 	w.RegisterActivity(acts.ExecuteActivity)
+	w.RegisterActivity(acts.ReservePairActivity)
+	w.RegisterActivity(acts.PairSessionActivity)
+	w.RegisterActivity(acts.RequestApprovalActivity)
+	w.RegisterActivity(acts.CloseApprovalActivity)
+	w.RegisterActivity(acts.PremiumBudgetActivity)
+	w.RegisterActivity(acts.AcquireSlotActivity)
+	w.RegisterActivity(acts.ReleaseSlotActivity)
+	w.RegisterActivity(acts.AcquireWorktreeActivity)
+	w.RegisterActivity(acts.ReleaseWorktreeActivity)
+	w.RegisterActivity(acts.ClaimBeadActivity)
+	w.RegisterActivity(acts.RenewClaimActivity)
+	w.RegisterActivity(acts.ReleaseClaimActivity)
+	w.RegisterActivity(acts.SeedBeadStageActivity)
+	w.RegisterActivity(acts.AdvanceBeadStageActivity)
+	w.RegisterActivity(acts.AutofixActivity)
+	w.RegisterActivity(acts.TestWriterActivity)
 	w.RegisterActivity(acts.CodeReviewActivity)
 	w.RegisterActivity(acts.DoDVerifyActivity)
+	w.RegisterActivity(acts.CompleteTaskActivity)
+	w.RegisterActivity(acts.CreateFollowUpBeadsActivity)
 	w.RegisterActivity(acts.RecordOutcomeActivity)
 	w.RegisterActivity(acts.EscalateActivity)
 	w.RegisterActivity(acts.GroomBacklogActivity)
+	w.RegisterActivity(acts.RecordScanSelectionActivity)
 	w.RegisterActivity(acts.GenerateQuestionsActivity)
 	w.RegisterActivity(acts.SummarizePlanActivity)
 
@@ -57,7 +186,81 @@ func StartWorker(st *store.Store, tiers config.Tiers) error {
 	w.RegisterActivity(acts.GetBeadStateSummaryActivity)
 	w.RegisterActivity(acts.StrategicAnalysisActivity)
 	w.RegisterActivity(acts.GenerateMorningBriefingActivity)
+	w.RegisterActivity(acts.ChiefPromptActivity)
+	w.RegisterActivity(acts.SprintReviewActivity)
+	w.RegisterActivity(acts.NextWorkingDayActivity)
 
-	log.Println("Temporal Worker started on cortex-task-queue...")
-	return w.Run(worker.InterruptCh())
+	// --- Failure Clustering Activities ---
+	w.RegisterActivity(acts.FailureClusterReportActivity)
+
+	// --- Provider Warmup Activities ---
+	w.RegisterActivity(acts.ColdProvidersActivity)
+	w.RegisterActivity(acts.WarmupProviderActivity)
+
+	// --- Stalled Review Activities ---
+	w.RegisterActivity(acts.CheckStalledReviewsActivity)
+	w.RegisterActivity(acts.FetchReviewFeedbackActivity)
+	w.RegisterActivity(acts.ResolveReviewThreadsActivity)
+	w.RegisterActivity(acts.CreatePRActivity)
+	w.RegisterActivity(acts.PostMergeChecksActivity)
+	w.RegisterActivity(acts.RevertMergeActivity)
+	w.RegisterActivity(acts.CheckStageSLAActivity)
+	w.RegisterActivity(acts.EpicRollupActivity)
+	w.RegisterActivity(acts.BacklogTriageActivity)
+	w.RegisterActivity(acts.FileOverlapActivity)
+	w.RegisterActivity(acts.StandupDigestActivity)
+
+	// --- Branch Janitor Activities ---
+	w.RegisterActivity(acts.BranchJanitorActivity)
+
+	// --- Burn-in Report Activities ---
+	w.RegisterActivity(acts.BurnInReportActivity)
+	w.RegisterActivity(acts.CostReportActivity)
+
+	// --- Rollout Completion Activities ---
+	w.RegisterActivity(acts.RolloutCompletionActivity)
+
+	log.Printf("Temporal Worker started on %s...", cfg.Temporal.TaskQueue)
+	stop := make(chan interface{})
+	go func() {
+		<-ctx.Done()
+		close(stop)
+	}()
+	return w.Run(stop)
+}
+
+// startAgentCheckpoints enables agent run checkpoints beside the state DB and
+// reconciles those left by the previous worker: dead processes are cleared,
+// live ones wait for their activity's retry to re-adopt them.
+func startAgentCheckpoints(st *store.Store, cfg *config.Config) {
+	dir := filepath.Join(filepath.Dir(config.ExpandHome(cfg.General.StateDB)), "agent-runs")
+	cp, err := newAgentCheckpoints(st, dir)
+	if err != nil {
+		log.Printf("Agent checkpoints disabled: %v", err)
+		return
+	}
+	checkpoints = cp
+
+	res, err := cp.reconcile()
+	if err != nil {
+		log.Printf("Agent checkpoint reconcile failed: %v", err)
+		return
+	}
+	if res.Adoptable+res.Stale == 0 {
+		return
+	}
+	details := fmt.Sprintf("%d agent process(es) still running and awaiting re-adoption, %d stale checkpoint(s) cleared", res.Adoptable, res.Stale)
+	log.Printf("Restart reconcile: %s", details)
+	st.RecordHealthEvent("restart_reconcile", details)
+}
+
+// loadHolidays builds the cadence's holiday calendar. When the iCal feed
+// cannot be fetched, the static holidays list is used on its own.
+func loadHolidays(ctx context.Context, cadence config.Cadence) *calendar.Calendar {
+	cal, err := calendar.Load(ctx, cadence)
+	if err == nil {
+		return cal
+	}
+	log.Printf("Holiday calendar fetch failed, using the static holidays only: %v", err)
+	return calendar.Static(cadence)
 }
@@ -1,6 +1,7 @@
 package temporal
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -10,6 +11,8 @@ import (
 	"go.temporal.io/sdk/log"
 	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/workflow"
+
+	"github.com/antigravity-dev/cortex/internal/dispatch"
 )
 
 const (
@@ -31,11 +34,6 @@ func CortexAgentWorkflow(ctx workflow.Context, req TaskRequest) error {
 	startTime := workflow.Now(ctx)
 	logger := workflow.GetLogger(ctx)
 
-	// Assign reviewer if not specified
-	if req.Reviewer == "" {
-		req.Reviewer = DefaultReviewer(req.Agent)
-	}
-
 	// --- Activity options ---
 	planOpts := workflow.ActivityOptions{
 		StartToCloseTimeout: 5 * time.Minute,
@@ -44,7 +42,9 @@ func CortexAgentWorkflow(ctx workflow.Context, req TaskRequest) error {
 	execOpts := workflow.ActivityOptions{
 		StartToCloseTimeout: 15 * time.Minute,
 		HeartbeatTimeout:    30 * time.Second,
-		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 1}, // no auto-retry, we handle it
+		// One retry, so a run cut off by a worker restart re-adopts its agent
+		// process from the checkpoint; failed runs are retried by the loop below.
+		RetryPolicy: &temporal.RetryPolicy{MaximumAttempts: 2},
 	}
 	reviewOpts := workflow.ActivityOptions{
 		StartToCloseTimeout: 5 * time.Minute,
@@ -58,15 +58,78 @@ func CortexAgentWorkflow(ctx workflow.Context, req TaskRequest) error {
 		StartToCloseTimeout: 30 * time.Second,
 		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 3},
 	}
+	worktreeOpts := workflow.ActivityOptions{
+		StartToCloseTimeout: 5 * time.Minute,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 2},
+	}
 
 	var a *Activities
 
+	// ===== CLAIM =====
+	// A bead the API claimed stays claimed while the workflow runs, so other
+	// cortex instances sharing the project leave it alone.
+	if req.ClaimTTL > 0 {
+		claim := req
+		finished := false
+		workflow.Go(ctx, func(gctx workflow.Context) {
+			renewCtx := workflow.WithActivityOptions(gctx, recordOpts)
+			for {
+				if err := workflow.Sleep(gctx, claim.ClaimTTL/3); err != nil || finished {
+					return
+				}
+				if err := workflow.ExecuteActivity(renewCtx, a.RenewClaimActivity, claim).Get(gctx, nil); err != nil {
+					logger.Warn("Claim renewal failed", "error", err)
+				}
+			}
+		})
+		defer func() {
+			finished = true
+			releaseCtx, _ := workflow.NewDisconnectedContext(ctx)
+			releaseCtx = workflow.WithActivityOptions(releaseCtx, recordOpts)
+			if err := workflow.ExecuteActivity(releaseCtx, a.ReleaseClaimActivity, claim).Get(releaseCtx, nil); err != nil {
+				logger.Warn("Claim release failed", "error", err)
+			}
+		}()
+	}
+
+	// Assign A/B experiment variants before anything role-specific runs.
+	// Experiments are best-effort: failures fall back to the defaults.
+	assignCtx := workflow.WithActivityOptions(ctx, recordOpts)
+	var assignments []ExperimentAssignment
+	if err := workflow.ExecuteActivity(assignCtx, a.AssignExperimentsActivity, req).Get(ctx, &assignments); err != nil {
+		logger.Warn("Experiment assignment failed, using defaults", "error", err)
+	}
+	req.Experiments = assignments
+	plannerAgent := ""
+	for _, exp := range assignments {
+		if exp.Agent == "" {
+			continue
+		}
+		switch exp.Role {
+		case "planner":
+			plannerAgent = exp.Agent
+		case "coder":
+			req.Agent = exp.Agent
+		case "reviewer":
+			req.Reviewer = exp.Agent
+		}
+	}
+	planReq := req
+	if plannerAgent != "" {
+		planReq.Agent = plannerAgent
+	}
+
+	// Assign reviewer if not specified
+	if req.Reviewer == "" {
+		req.Reviewer = DefaultReviewer(req.Agent)
+	}
+
 	// ===== PHASE 1: PLAN =====
 	logger.Info("Phase 1: Generating structured plan")
 	planCtx := workflow.WithActivityOptions(ctx, planOpts)
 
 	var plan StructuredPlan
-	if err := workflow.ExecuteActivity(planCtx, a.StructuredPlanActivity, req).Get(ctx, &plan); err != nil {
+	if err := workflow.ExecuteActivity(planCtx, a.StructuredPlanActivity, planReq).Get(ctx, &plan); err != nil {
 		return fmt.Errorf("plan generation failed: %w", err)
 	}
 	if plan.TokenUsage.InputTokens > 0 || plan.TokenUsage.OutputTokens > 0 || plan.TokenUsage.CostUSD > 0 ||
@@ -91,121 +154,325 @@ func CortexAgentWorkflow(ctx workflow.Context, req TaskRequest) error {
 	// "Plan space is cheap, implementation is expensive."
 	logger.Info("Phase 2: Waiting for human approval")
 
-	signalChan := workflow.GetSignalChannel(ctx, "human-approval")
-	var signalVal string
-
 	currentAgent := req.Agent
 	currentReviewer := req.Reviewer
 	var allFailures []string
+	var lastFindings []SecurityFinding // security findings of the last DoD run
 	var totalTokens TokenUsage
 	var activityTokens []ActivityTokenUsage
 
 	// Helper: reset per-attempt token tracking with plan tokens as baseline.
 	planHasTokens := plan.TokenUsage.InputTokens > 0 || plan.TokenUsage.OutputTokens > 0 || plan.TokenUsage.CostUSD > 0 ||
 		plan.TokenUsage.CacheReadTokens > 0 || plan.TokenUsage.CacheCreationTokens > 0
+	// Spend of earlier attempts beyond the plan, so the dispatch cost cap
+	// covers the whole dispatch even though attempts report separately.
+	var earlierAttemptsUSD float64
 	resetAttemptTokens := func() {
+		if totalTokens.CostUSD > 0 {
+			earlierAttemptsUSD += totalTokens.CostUSD - plan.TokenUsage.CostUSD
+		}
 		totalTokens = TokenUsage{}
 		totalTokens.Add(plan.TokenUsage)
 		activityTokens = nil
 		if planHasTokens {
 			activityTokens = append(activityTokens, ActivityTokenUsage{
 				ActivityName: "plan",
-				Agent:        req.Agent,
+				Agent:        planReq.Agent,
 				Tokens:       plan.TokenUsage,
 			})
 		}
 	}
 	resetAttemptTokens()
 
-	signalChan.Receive(ctx, &signalVal)
-
-	if signalVal == "REJECTED" {
-		recordOutcome(ctx, recordOpts, a, req, "rejected", 0, 0, false, "Plan rejected by human", startTime, 0,
-			totalTokens, activityTokens)
+	// With chat approvals the plan is posted to the project room and a
+	// configured timeout takes the default action; otherwise the gate waits
+	// for the approve or reject endpoint.
+	approved, decidedBy := requestApproval(ctx, recordOpts, a, ApprovalPrompt{
+		Kind: "plan", Project: req.Project, BeadID: req.BeadID, Summary: plan.Summary,
+	})
+
+	if !approved {
+		reason := "Plan rejected by human"
+		if decidedBy == "timeout" {
+			reason = "Plan approval timed out and was denied by default"
+		}
+		recordOutcome(ctx, recordOpts, a, req, "rejected", 0, 0, false, reason, nil, nil, startTime, 0,
+			totalTokens, activityTokens, Confidence{}, nil, "", false, nil)
+		if decidedBy == "timeout" {
+			return fmt.Errorf("plan approval timed out")
+		}
 		return fmt.Errorf("plan rejected by human")
 	}
 
-	// ===== PHASE 3-6: EXECUTE → REVIEW → DOD LOOP =====
+	// A premium dispatch once today's spend has reached the daily cost cap
+	// runs only if a human overrides the budget.
+	var budget PremiumBudget
+	budgetCtx := workflow.WithActivityOptions(ctx, recordOpts)
+	if err := workflow.ExecuteActivity(budgetCtx, a.PremiumBudgetActivity, req).Get(ctx, &budget); err != nil {
+		logger.Warn("Premium budget check failed", "error", err)
+	} else if budget.OverBudget {
+		approved, decidedBy := requestApproval(ctx, recordOpts, a, ApprovalPrompt{
+			Kind: "budget", Project: req.Project, BeadID: req.BeadID,
+			Summary: fmt.Sprintf("Premium dispatch on %s: today's spend $%.2f has reached the $%.2f daily cap.",
+				req.Provider, budget.SpentUSD, budget.CapUSD),
+		})
+		if !approved {
+			reason := "Premium budget override denied"
+			if decidedBy == "timeout" {
+				reason = "Premium budget override timed out and was denied by default"
+			}
+			recordOutcome(ctx, recordOpts, a, req, "rejected", 0, 0, false, reason, nil, nil, startTime, 0,
+				totalTokens, activityTokens, Confidence{}, nil, "", false, nil)
+			return fmt.Errorf("premium budget override denied")
+		}
+	}
+
+	// ===== WORKSPACE =====
+	// With the worktree pool enabled the task gets its own checkout of its
+	// feature branch; bead commands and the CHUM loop keep using the shared
+	// workspace. The worktree is reclaimed however the workflow ends.
+	worktreeCtx := workflow.WithActivityOptions(ctx, worktreeOpts)
+	var lease *dispatch.WorktreeLease
+	if err := workflow.ExecuteActivity(worktreeCtx, a.AcquireWorktreeActivity, req).Get(ctx, &lease); err != nil {
+		logger.Warn("Worktree checkout failed, using the shared workspace", "error", err)
+	}
+	if lease != nil {
+		req.Workspace = req.WorkDir
+		req.WorkDir = lease.Path
+		defer func() {
+			releaseCtx, _ := workflow.NewDisconnectedContext(ctx)
+			releaseCtx = workflow.WithActivityOptions(releaseCtx, worktreeOpts)
+			if err := workflow.ExecuteActivity(releaseCtx, a.ReleaseWorktreeActivity, *lease).Get(releaseCtx, nil); err != nil {
+				logger.Warn("Worktree release failed", "Path", lease.Path, "error", err)
+			}
+		}()
+	}
+
+	// ===== PR REVIEW FEEDBACK =====
+	// A bead dispatched again after reviewers requested changes on its PR
+	// gets their unresolved threads in the coder's prompt.
+	feedbackCtx := workflow.WithActivityOptions(ctx, reviewOpts)
+	var feedback ReviewFeedback
+	if err := workflow.ExecuteActivity(feedbackCtx, a.FetchReviewFeedbackActivity, req).Get(ctx, &feedback); err != nil {
+		logger.Warn("PR review feedback lookup failed", "error", err)
+	}
+	plan.PreviousErrors = append(plan.PreviousErrors, reviewFeedbackErrors(feedback)...)
+
+	// ===== WORKFLOW STAGE =====
+	// A bead matching a configured workflow enters its first stage on its
+	// first dispatch, and moves on once a dispatch passes DoD.
+	stageCtx := workflow.WithActivityOptions(ctx, recordOpts)
+	var stage string
+	if err := workflow.ExecuteActivity(stageCtx, a.SeedBeadStageActivity, req).Get(ctx, &stage); err != nil {
+		logger.Warn("Workflow stage seeding failed", "error", err)
+	}
+
+	// ===== CONCURRENCY =====
+	// The dispatch holds a coder slot from its first execution to its end;
+	// it waits in the overflow queue while the coder limit is reached.
+	releaseCoder := acquireSlot(ctx, a, req, "coder")
+	defer releaseCoder()
+
+	// ===== PAIR MODE =====
+	// High-priority beads (or requests asking for it) run as one pair session
+	// with a provider reserved for each agent; otherwise the solo flow runs.
+	var pair PairPlan
+	reserveCtx := workflow.WithActivityOptions(ctx, recordOpts)
+	if err := workflow.ExecuteActivity(reserveCtx, a.ReservePairActivity, req).Get(ctx, &pair); err != nil {
+		logger.Warn("Pair reservation failed, using solo dispatch", "error", err)
+	}
+	req.Mode = ""
+	pairOpts := execOpts
+	// A retried pair session would restart from its first turn.
+	pairOpts.RetryPolicy = &temporal.RetryPolicy{MaximumAttempts: 1}
+	if pair.Active {
+		req.Mode = "pair"
+		if req.Provider == "" {
+			req.Provider = pair.CoderProvider
+		}
+		pairOpts.StartToCloseTimeout = time.Duration(pair.MaxTurns) * execOpts.StartToCloseTimeout
+		logger.Info("Pair mode", "Coder", req.Agent, "Reviewer", req.Reviewer, "MaxTurns", pair.MaxTurns)
+	}
+
+	// ===== PHASE 3-6: EXECUTE → AUTOFIX → REVIEW → DOD LOOP =====
+	autofixCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 10 * time.Minute,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 1},
+	})
+	testWriterCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: execOpts.StartToCloseTimeout,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 1},
+	})
 	handoffCount := 0
+	var confidence Confidence // from the latest execution, for calibration
+	var report *AgentReport   // the latest execution's completion block
+	var outputActivity string // the latest execution's activity ID
 
 	for attempt := 0; attempt < maxDoDRetries; attempt++ {
 		logger.Info("Execution attempt", "Attempt", attempt+1, "Agent", currentAgent)
+		req.Attempt = attempt + 1
 
 		// Reset token tracking to plan baseline for each attempt.
 		// Only the last attempt's costs are reported in the outcome.
 		resetAttemptTokens()
 
-		// --- EXECUTE ---
-		execCtx := workflow.WithActivityOptions(ctx, execOpts)
 		var execResult ExecutionResult
-		if err := workflow.ExecuteActivity(execCtx, a.ExecuteActivity, plan, req).Get(ctx, &execResult); err != nil {
-			allFailures = append(allFailures, fmt.Sprintf("Attempt %d execute error: %s", attempt+1, err.Error()))
-			continue
-		}
-		totalTokens.Add(execResult.Tokens)
-		activityTokens = append(activityTokens, ActivityTokenUsage{
-			ActivityName: "execute", Agent: execResult.Agent, Tokens: execResult.Tokens,
-		})
-
-		// --- CROSS-MODEL REVIEW LOOP ---
-		reviewPassed := false
-		for handoff := 0; handoff < maxHandoffs; handoff++ {
-			reviewCtx := workflow.WithActivityOptions(ctx, reviewOpts)
-			var review ReviewResult
-
-			// Override the agent for this execution so the reviewer field is correct
-			reviewReq := req
-			reviewReq.Reviewer = currentReviewer
-
-			if err := workflow.ExecuteActivity(reviewCtx, a.CodeReviewActivity, plan, execResult, reviewReq).Get(ctx, &review); err != nil {
-				logger.Warn("Review activity failed", "error", err)
-				reviewPassed = true // don't block on review infrastructure failures
-				break
+		if pair.Active {
+			// --- PAIR SESSION: coder and reviewer alternate in one dispatch ---
+			pairCtx := workflow.WithActivityOptions(ctx, pairOpts)
+			var session PairSessionResult
+			releaseReviewer := acquireSlot(ctx, a, req, "reviewer")
+			err := workflow.ExecuteActivity(pairCtx, a.PairSessionActivity, plan, req, pair.MaxTurns).Get(ctx, &session)
+			releaseReviewer()
+			if err != nil {
+				allFailures = append(allFailures, fmt.Sprintf("Attempt %d pair session error: %s", attempt+1, err.Error()))
+				continue
 			}
-
-			totalTokens.Add(review.Tokens)
+			execResult = session.Execution
+			confidence, report, outputActivity = execResult.Confidence, execResult.Report, execResult.ActivityID
+			totalTokens.Add(session.Execution.Tokens)
+			totalTokens.Add(session.Review.Tokens)
+			activityTokens = append(activityTokens,
+				ActivityTokenUsage{ActivityName: "execute", Agent: session.Execution.Agent, Tokens: session.Execution.Tokens},
+				ActivityTokenUsage{ActivityName: "review", Agent: session.Review.ReviewerAgent, Tokens: session.Review.Tokens},
+			)
+			if !session.Approved {
+				plan.PreviousErrors = append(plan.PreviousErrors,
+					fmt.Sprintf("Pair review by %s found issues: %s", session.Review.ReviewerAgent, strings.Join(session.Review.Issues, "; ")))
+				allFailures = append(allFailures, fmt.Sprintf("Attempt %d: pair session not approved after %d turns", attempt+1, len(session.Turns)))
+				continue
+			}
+		} else {
+			// --- EXECUTE ---
+			execCtx := workflow.WithActivityOptions(ctx, execOpts)
+			req.SpentUSD = earlierAttemptsUSD + totalTokens.CostUSD
+			if err := workflow.ExecuteActivity(execCtx, a.ExecuteActivity, plan, req).Get(ctx, &execResult); err != nil {
+				if isCostCapExceeded(err) {
+					return stopForCostCap(ctx, recordOpts, a, req, err, startTime, totalTokens, activityTokens)
+				}
+				allFailures = append(allFailures, fmt.Sprintf("Attempt %d execute error: %s", attempt+1, err.Error()))
+				continue
+			}
+			totalTokens.Add(execResult.Tokens)
 			activityTokens = append(activityTokens, ActivityTokenUsage{
-				ActivityName: "review", Agent: review.ReviewerAgent, Tokens: review.Tokens,
+				ActivityName: "execute", Agent: execResult.Agent, Tokens: execResult.Tokens,
 			})
-
-			if review.Approved {
-				logger.Info("Code review approved", "Reviewer", review.ReviewerAgent, "Handoff", handoff)
-				reviewPassed = true
-				break
+			confidence, report, outputActivity = execResult.Confidence, execResult.Report, execResult.ActivityID
+
+			// --- CROSS-MODEL REVIEW LOOP ---
+			reviewPassed := false
+			for handoff := 0; handoff < maxHandoffs; handoff++ {
+				// --- TEST WRITER ---
+				// Code changed without tests gets tests before review, and
+				// before autofix so the new tests are formatted too.
+				var tests TestWriterResult
+				if err := workflow.ExecuteActivity(testWriterCtx, a.TestWriterActivity, req).Get(ctx, &tests); err != nil {
+					logger.Warn("Test writer failed (non-fatal, proceeding to review)", "error", err)
+				} else if tests.Agent != "" {
+					totalTokens.Add(tests.Tokens)
+					activityTokens = append(activityTokens, ActivityTokenUsage{
+						ActivityName: "test-writer", Agent: tests.Agent, Tokens: tests.Tokens,
+					})
+				}
+
+				// --- AUTOFIX ---
+				// Formatter and linter fixes land before the reviewer looks,
+				// so review cycles are not spent on style nits.
+				var fixes AutofixResult
+				if err := workflow.ExecuteActivity(autofixCtx, a.AutofixActivity, req).Get(ctx, &fixes); err != nil {
+					logger.Warn("Autofix failed (non-fatal, proceeding to review)", "error", err)
+				} else if fixes.Agent != "" {
+					totalTokens.Add(fixes.Tokens)
+					activityTokens = append(activityTokens, ActivityTokenUsage{
+						ActivityName: "autofix", Agent: fixes.Agent, Tokens: fixes.Tokens,
+					})
+				}
+
+				reviewCtx := workflow.WithActivityOptions(ctx, reviewOpts)
+				var review ReviewResult
+
+				// Override the agent for this execution so the reviewer field is correct
+				reviewReq := req
+				reviewReq.Reviewer = currentReviewer
+				reviewReq.SpentUSD = earlierAttemptsUSD + totalTokens.CostUSD
+
+				releaseReviewer := acquireSlot(ctx, a, reviewReq, "reviewer")
+				err := workflow.ExecuteActivity(reviewCtx, a.CodeReviewActivity, plan, execResult, reviewReq).Get(ctx, &review)
+				releaseReviewer()
+				if err != nil {
+					if isCostCapExceeded(err) {
+						return stopForCostCap(ctx, recordOpts, a, reviewReq, err, startTime, totalTokens, activityTokens)
+					}
+					if req.RequireReview {
+						logger.Warn("Review activity failed, review required", "error", err)
+						allFailures = append(allFailures, fmt.Sprintf("Attempt %d review error: %s", attempt+1, err.Error()))
+						break
+					}
+					logger.Warn("Review activity failed", "error", err)
+					reviewPassed = true // don't block on review infrastructure failures
+					break
+				}
+
+				totalTokens.Add(review.Tokens)
+				activityTokens = append(activityTokens, ActivityTokenUsage{
+					ActivityName: "review", Agent: review.ReviewerAgent, Tokens: review.Tokens,
+				})
+
+				if review.Approved {
+					logger.Info("Code review approved", "Reviewer", review.ReviewerAgent, "Handoff", handoff)
+					reviewPassed = true
+					break
+				}
+
+				// Review failed — swap agents and re-execute with feedback
+				handoffCount++
+				logger.Info("Code review rejected, swapping agents",
+					"Reviewer", currentReviewer,
+					"Issues", strings.Join(review.Issues, "; "),
+					"Handoff", handoffCount,
+				)
+
+				// Feed review issues back into the plan
+				plan.PreviousErrors = append(plan.PreviousErrors,
+					fmt.Sprintf("Review by %s found issues: %s", review.ReviewerAgent, strings.Join(review.Issues, "; ")))
+
+				// Swap: the reviewer becomes the implementer, and vice versa
+				currentAgent, currentReviewer = currentReviewer, currentAgent
+				req.Agent = currentAgent
+
+				// Re-execute with the swapped agent
+				var reExecResult ExecutionResult
+				req.SpentUSD = earlierAttemptsUSD + totalTokens.CostUSD
+				if err := workflow.ExecuteActivity(execCtx, a.ExecuteActivity, plan, req).Get(ctx, &reExecResult); err != nil {
+					if isCostCapExceeded(err) {
+						return stopForCostCap(ctx, recordOpts, a, req, err, startTime, totalTokens, activityTokens)
+					}
+					allFailures = append(allFailures, fmt.Sprintf("Handoff %d execute error: %s", handoffCount, err.Error()))
+					break
+				}
+				totalTokens.Add(reExecResult.Tokens)
+				activityTokens = append(activityTokens, ActivityTokenUsage{
+					ActivityName: "execute", Agent: reExecResult.Agent, Tokens: reExecResult.Tokens,
+				})
+				execResult = reExecResult
+				confidence, report, outputActivity = execResult.Confidence, execResult.Report, execResult.ActivityID
 			}
 
-			// Review failed — swap agents and re-execute with feedback
-			handoffCount++
-			logger.Info("Code review rejected, swapping agents",
-				"Reviewer", currentReviewer,
-				"Issues", strings.Join(review.Issues, "; "),
-				"Handoff", handoffCount,
-			)
-
-			// Feed review issues back into the plan
-			plan.PreviousErrors = append(plan.PreviousErrors,
-				fmt.Sprintf("Review by %s found issues: %s", review.ReviewerAgent, strings.Join(review.Issues, "; ")))
-
-			// Swap: the reviewer becomes the implementer, and vice versa
-			currentAgent, currentReviewer = currentReviewer, currentAgent
-			req.Agent = currentAgent
-
-			// Re-execute with the swapped agent
-			var reExecResult ExecutionResult
-			if err := workflow.ExecuteActivity(execCtx, a.ExecuteActivity, plan, req).Get(ctx, &reExecResult); err != nil {
-				allFailures = append(allFailures, fmt.Sprintf("Handoff %d execute error: %s", handoffCount, err.Error()))
-				break
+			if !reviewPassed {
+				allFailures = append(allFailures, fmt.Sprintf("Attempt %d: review not passed after %d handoffs", attempt+1, handoffCount))
+				continue
 			}
-			totalTokens.Add(reExecResult.Tokens)
-			activityTokens = append(activityTokens, ActivityTokenUsage{
-				ActivityName: "execute", Agent: reExecResult.Agent, Tokens: reExecResult.Tokens,
-			})
-			execResult = reExecResult
 		}
 
-		if !reviewPassed {
-			allFailures = append(allFailures, fmt.Sprintf("Attempt %d: review not passed after %d handoffs", attempt+1, handoffCount))
-			continue
+		// The review that just passed covered the PR threads the coder was
+		// given, so they are marked addressed.
+		if len(feedback.Threads) > 0 {
+			var resolved int
+			if err := workflow.ExecuteActivity(feedbackCtx, a.ResolveReviewThreadsActivity, feedback).Get(ctx, &resolved); err != nil {
+				logger.Warn("Resolving PR review threads failed", "error", err)
+			}
+			logger.Info("PR review threads addressed", "PR", feedback.PRNumber, "Resolved", resolved)
+			feedback.Threads = nil
 		}
 
 		// --- SEMGREP PRE-FILTER ---
@@ -246,8 +513,53 @@ func CortexAgentWorkflow(ctx workflow.Context, req TaskRequest) error {
 				"TotalCacheCreationTokens", totalTokens.CacheCreationTokens,
 				"TotalCostUSD", totalTokens.CostUSD,
 			)
+			// ===== PULL REQUEST =====
+			// A feature branch checked out in a worktree is pushed and gets a
+			// PR, recorded with the dispatch so a draft PR can be marked ready
+			// when the bead enters stage:review.
+			var pr *CreatePRResult
+			if lease != nil && lease.Branch != "" {
+				prCtx := workflow.WithActivityOptions(ctx, reviewOpts)
+				if err := workflow.ExecuteActivity(prCtx, a.CreatePRActivity, CreatePRRequest{
+					BeadID:  req.BeadID,
+					Project: req.Project,
+					WorkDir: req.WorkDir,
+					Branch:  lease.Branch,
+					Checks:  dodResult.Checks,
+				}).Get(ctx, &pr); err != nil {
+					logger.Warn("PR creation failed", "Branch", lease.Branch, "error", err)
+					pr = nil
+				}
+			}
+
+			if stage != "" {
+				var next string
+				if err := workflow.ExecuteActivity(stageCtx, a.AdvanceBeadStageActivity, req).Get(ctx, &next); err != nil {
+					logger.Warn("Workflow stage advance failed", "Stage", stage, "error", err)
+				} else if next != "" {
+					logger.Info("Bead moved to the next stage", "From", stage, "To", next)
+				}
+			}
+
+			// ===== COMPLETE — auto-close gated on reported confidence =====
+			var completion CompletionResult
+			completeCtx := workflow.WithActivityOptions(ctx, recordOpts)
+			if err := workflow.ExecuteActivity(completeCtx, a.CompleteTaskActivity, CompletionRequest{
+				BeadID:     req.BeadID,
+				Project:    req.Project,
+				WorkDir:    req.sharedWorkspace(),
+				Agent:      execResult.Agent,
+				Confidence: confidence,
+			}).Get(ctx, &completion); err != nil {
+				logger.Warn("Task completion failed, bead left open", "error", err)
+			} else if completion.HumanReview {
+				logger.Info("Completion routed to human review", "Reason", completion.Reason)
+			}
+
 			recordOutcome(ctx, recordOpts, a, req, "completed", 0,
-				handoffCount, true, "", startTime, attempt+1, totalTokens, activityTokens)
+				handoffCount, true, "", dodResult.Checks, dodResult.Findings, startTime, attempt+1, totalTokens, activityTokens,
+				confidence, report, outputActivity, completion.HumanReview, pr)
+			fileFollowUps(ctx, recordOpts, a, req, execResult.Agent, report)
 
 			// ===== CHUM LOOP — spawn async learner + groomer =====
 			spawnCHUMWorkflows(ctx, logger, req, plan)
@@ -256,6 +568,7 @@ func CortexAgentWorkflow(ctx workflow.Context, req TaskRequest) error {
 		}
 
 		// DoD failed — feed failures back into plan
+		lastFindings = dodResult.Findings
 		failureMsg := strings.Join(dodResult.Failures, "; ")
 		allFailures = append(allFailures, fmt.Sprintf("Attempt %d DoD failed: %s", attempt+1, failureMsg))
 		plan.PreviousErrors = append(plan.PreviousErrors, "DoD check failures: "+failureMsg)
@@ -277,7 +590,9 @@ func CortexAgentWorkflow(ctx workflow.Context, req TaskRequest) error {
 	}).Get(ctx, nil)
 
 	recordOutcome(ctx, recordOpts, a, req, "escalated", 1,
-		handoffCount, false, strings.Join(allFailures, "\n"), startTime, maxDoDRetries, totalTokens, activityTokens)
+		handoffCount, false, strings.Join(allFailures, "\n"), nil, lastFindings, startTime, maxDoDRetries, totalTokens, activityTokens,
+		confidence, report, outputActivity, false, nil)
+	fileFollowUps(ctx, recordOpts, a, req, req.Agent, report)
 
 	return fmt.Errorf("task escalated after %d attempts: %s", maxDoDRetries, strings.Join(allFailures, "; "))
 }
@@ -285,8 +600,8 @@ func CortexAgentWorkflow(ctx workflow.Context, req TaskRequest) error {
 // recordOutcome is a helper to persist the workflow outcome via RecordOutcomeActivity.
 func recordOutcome(ctx workflow.Context, opts workflow.ActivityOptions, a *Activities,
 	req TaskRequest, status string, exitCode int, handoffs int,
-	dodPassed bool, dodFailures string, startTime time.Time, attempts int,
-	tokens TokenUsage, activityTokens []ActivityTokenUsage) {
+	dodPassed bool, dodFailures string, dodChecks []CheckResult, dodFindings []SecurityFinding, startTime time.Time, attempts int,
+	tokens TokenUsage, activityTokens []ActivityTokenUsage, confidence Confidence, report *AgentReport, outputActivity string, humanReview bool, pr *CreatePRResult) {
 	_ = attempts
 
 	recordCtx := workflow.WithActivityOptions(ctx, opts)
@@ -295,6 +610,7 @@ func recordOutcome(ctx workflow.Context, opts workflow.ActivityOptions, a *Activ
 	_ = workflow.ExecuteActivity(recordCtx, a.RecordOutcomeActivity, OutcomeRecord{
 		BeadID:         req.BeadID,
 		Project:        req.Project,
+		WorkDir:        req.WorkDir,
 		Agent:          req.Agent,
 		Reviewer:       req.Reviewer,
 		Provider:       req.Provider,
@@ -303,12 +619,73 @@ func recordOutcome(ctx workflow.Context, opts workflow.ActivityOptions, a *Activ
 		DurationS:      duration,
 		DoDPassed:      dodPassed,
 		DoDFailures:    dodFailures,
+		DoDChecks:      dodChecks,
+		DoDFindings:    dodFindings,
 		Handoffs:       handoffs,
 		TotalTokens:    tokens,
 		ActivityTokens: activityTokens,
+		Experiments:    req.Experiments,
+		Confidence:     confidence,
+		Report:         report,
+		OutputActivity: outputActivity,
+		HumanReview:    humanReview,
+		Mode:           req.Mode,
+		PR:             pr,
 	}).Get(ctx, nil)
 }
 
+// fileFollowUps turns the follow-ups in the agent's completion block into
+// beads. A failure is only logged; it never fails the task.
+func fileFollowUps(ctx workflow.Context, opts workflow.ActivityOptions, a *Activities, req TaskRequest, agent string, report *AgentReport) {
+	if report == nil || len(report.FollowUps) == 0 {
+		return
+	}
+	followCtx := workflow.WithActivityOptions(ctx, opts)
+	if err := workflow.ExecuteActivity(followCtx, a.CreateFollowUpBeadsActivity, FollowUpRequest{
+		BeadID:    req.BeadID,
+		Project:   req.Project,
+		WorkDir:   req.sharedWorkspace(),
+		Agent:     agent,
+		FollowUps: report.FollowUps,
+	}).Get(ctx, nil); err != nil {
+		workflow.GetLogger(ctx).Warn("Follow-up bead creation failed", "error", err)
+	}
+}
+
+// stopForCostCap ends a dispatch whose agent was stopped by a cost cap. It is
+// recorded as pending_retry with a cost_cap_exceeded diagnosis, so it can be
+// retried deliberately instead of escalated.
+func stopForCostCap(ctx workflow.Context, opts workflow.ActivityOptions, a *Activities,
+	req TaskRequest, capErr error, startTime time.Time, tokens TokenUsage, activityTokens []ActivityTokenUsage) error {
+	summary := capErr.Error()
+	var appErr *temporal.ApplicationError
+	if errors.As(capErr, &appErr) {
+		summary = appErr.Error()
+	}
+	workflow.GetLogger(ctx).Warn("Dispatch stopped by cost cap", "BeadID", req.BeadID, "Reason", summary)
+
+	recordCtx := workflow.WithActivityOptions(ctx, opts)
+	_ = workflow.ExecuteActivity(recordCtx, a.RecordOutcomeActivity, OutcomeRecord{
+		BeadID:          req.BeadID,
+		Project:         req.Project,
+		WorkDir:         req.WorkDir,
+		Agent:           req.Agent,
+		Reviewer:        req.Reviewer,
+		Provider:        req.Provider,
+		Status:          "pending_retry",
+		ExitCode:        1,
+		DurationS:       workflow.Now(ctx).Sub(startTime).Seconds(),
+		TotalTokens:     tokens,
+		ActivityTokens:  activityTokens,
+		Experiments:     req.Experiments,
+		Mode:            req.Mode,
+		FailureCategory: CostCapExceeded,
+		FailureSummary:  summary,
+	}).Get(ctx, nil)
+
+	return fmt.Errorf("dispatch stopped: %s", summary)
+}
+
 // spawnCHUMWorkflows fires off the ContinuousLearner and TacticalGroom as
 // detached child workflows. They run completely async — the parent returns
 // immediately and the children survive even after it completes.
@@ -321,7 +698,7 @@ func spawnCHUMWorkflows(ctx workflow.Context, logger log.Logger, req TaskRequest
 	learnerReq := LearnerRequest{
 		BeadID:         req.BeadID,
 		Project:        req.Project,
-		WorkDir:        req.WorkDir,
+		WorkDir:        req.sharedWorkspace(),
 		Agent:          req.Agent,
 		DoDPassed:      true,
 		FilesChanged:   plan.FilesToModify,
@@ -337,8 +714,8 @@ func spawnCHUMWorkflows(ctx workflow.Context, logger log.Logger, req TaskRequest
 	groomReq := TacticalGroomRequest{
 		BeadID:   req.BeadID,
 		Project:  req.Project,
-		WorkDir:  req.WorkDir,
-		BeadsDir: resolveBeadsDir(req.WorkDir),
+		WorkDir:  req.sharedWorkspace(),
+		BeadsDir: resolveBeadsDir(req.sharedWorkspace()),
 		Tier:     "fast",
 	}
 	groomOpts := chumOpts
@@ -362,6 +739,15 @@ func spawnCHUMWorkflows(ctx workflow.Context, logger log.Logger, req TaskRequest
 	}
 }
 
+// sharedWorkspace returns the project's shared checkout, which holds the
+// beads directory even when the task works in a pooled worktree.
+func (r TaskRequest) sharedWorkspace() string {
+	if r.Workspace != "" {
+		return r.Workspace
+	}
+	return r.WorkDir
+}
+
 // resolveBeadsDir derives the beads directory from workDir.
 // Convention: <workDir>/.beads
 func resolveBeadsDir(workDir string) string {
@@ -0,0 +1,29 @@
+package temporal
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// BurnInReportWorkflow generates the burn-in evidence report. Runs on a cron
+// schedule; a failed run is logged and the next run produces a fresh report.
+func BurnInReportWorkflow(ctx workflow.Context, req BurnInReportRequest) (*BurnInReportResult, error) {
+	logger := workflow.GetLogger(ctx)
+
+	actCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 5 * time.Minute,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 1},
+	})
+
+	var a *Activities
+	var result BurnInReportResult
+	if err := workflow.ExecuteActivity(actCtx, a.BurnInReportActivity, req).Get(ctx, &result); err != nil {
+		logger.Warn("BurnInReport: run failed", "error", err)
+		return nil, err
+	}
+
+	logger.Info("BurnInReport complete", "Date", result.Date, "Pass", result.Pass, "Failing", result.Failing)
+	return &result, nil
+}
@@ -0,0 +1,76 @@
+package temporal
+
+import (
+	"fmt"
+	"time"
+
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// ChiefWorkflowID is the workflow ID of a project's chief cron.
+func ChiefWorkflowID(project string) string {
+	return "chief-" + project
+}
+
+// ChiefWorkflow runs on a project's chief_schedule. It briefs the chief on
+// the backlog and the sprint's velocity and dispatches it as an ordinary
+// CortexAgentWorkflow, so the groom and sprint proposal wait for human
+// approval and go through review like any other task. The dispatch is detached: a run ends once it has
+// started and returns its workflow ID, which is also its bead ID. A run that
+// falls on a cadence holiday waits for the next working day.
+func ChiefWorkflow(ctx workflow.Context, req ChiefRequest) (string, error) {
+	logger := workflow.GetLogger(ctx)
+
+	actCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 2 * time.Minute,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 2},
+	})
+
+	var a *Activities
+	now := workflow.Now(ctx)
+	if runAt := nextWorkingTime(ctx, now); runAt.After(now) {
+		logger.Info("Chief: holiday, waiting for the next working day", "Project", req.Project, "RunAt", runAt)
+		if err := workflow.Sleep(ctx, runAt.Sub(now)); err != nil {
+			return "", err
+		}
+	}
+
+	var prompt string
+	if err := workflow.ExecuteActivity(actCtx, a.ChiefPromptActivity, req).Get(ctx, &prompt); err != nil {
+		logger.Warn("Chief: briefing failed", "Project", req.Project, "error", err)
+		return "", err
+	}
+	var review string
+	if err := workflow.ExecuteActivity(actCtx, a.SprintReviewActivity, req).Get(ctx, &review); err != nil {
+		logger.Warn("Chief: sprint review failed, briefing without velocity", "Project", req.Project, "error", err)
+	}
+	prompt += review
+
+	id := fmt.Sprintf("%s-%d", ChiefWorkflowID(req.Project), workflow.Now(ctx).Unix())
+	childCtx := workflow.WithChildOptions(ctx, workflow.ChildWorkflowOptions{
+		WorkflowID:        id,
+		ParentClosePolicy: enumspb.PARENT_CLOSE_POLICY_ABANDON,
+	})
+	task := TaskRequest{
+		BeadID:  id,
+		Project: req.Project,
+		Prompt:  prompt,
+		Agent:   req.Agent,
+		WorkDir: req.WorkDir,
+	}
+	task, err := claimChild(ctx, task)
+	if err != nil {
+		logger.Warn("Chief: bead not claimed, dispatch skipped", "Project", req.Project, "error", err)
+		return "", err
+	}
+	if err := workflow.ExecuteChildWorkflow(childCtx, CortexAgentWorkflow, task).GetChildWorkflowExecution().Get(ctx, nil); err != nil {
+		releaseChildClaim(ctx, task)
+		logger.Warn("Chief: dispatch failed", "Project", req.Project, "error", err)
+		return "", err
+	}
+
+	logger.Info("Chief dispatched", "Project", req.Project, "WorkflowID", id)
+	return id, nil
+}
@@ -0,0 +1,30 @@
+package temporal
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// CostReportWorkflow generates the monthly cost report. Runs on a cron
+// schedule; a failed run is logged and can be regenerated by rerunning it
+// with the month set.
+func CostReportWorkflow(ctx workflow.Context, req CostReportRequest) (*CostReportResult, error) {
+	logger := workflow.GetLogger(ctx)
+
+	actCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 5 * time.Minute,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 1},
+	})
+
+	var a *Activities
+	var result CostReportResult
+	if err := workflow.ExecuteActivity(actCtx, a.CostReportActivity, req).Get(ctx, &result); err != nil {
+		logger.Warn("CostReport: run failed", "error", err)
+		return nil, err
+	}
+
+	logger.Info("CostReport complete", "Month", result.Month, "CostUSD", result.CostUSD, "Projects", result.Projects)
+	return &result, nil
+}
@@ -0,0 +1,36 @@
+package temporal
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// EpicRollupWorkflow closes epics whose children are done and reports epic
+// progress. Runs on a cron schedule; failures are logged and retried on the
+// next run.
+func EpicRollupWorkflow(ctx workflow.Context, req EpicRollupRequest) (*EpicRollupResult, error) {
+	logger := workflow.GetLogger(ctx)
+
+	actCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 5 * time.Minute,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 1},
+	})
+
+	var a *Activities
+	var result EpicRollupResult
+	if err := workflow.ExecuteActivity(actCtx, a.EpicRollupActivity, req).Get(ctx, &result); err != nil {
+		logger.Warn("EpicRollup: rollup failed", "error", err)
+		return nil, err
+	}
+
+	closed := 0
+	for _, p := range result.Epics {
+		if p.AutoClosed {
+			closed++
+		}
+	}
+	logger.Info("EpicRollup complete", "Epics", len(result.Epics), "Closed", closed)
+	return &result, nil
+}
@@ -0,0 +1,35 @@
+package temporal
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// FileOverlapWorkflow flags ready beads that touch the same files across
+// projects. Runs on a cron schedule; failures are logged and retried on the
+// next run.
+func FileOverlapWorkflow(ctx workflow.Context, req FileOverlapRequest) (*FileOverlapResult, error) {
+	logger := workflow.GetLogger(ctx)
+
+	actCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 10 * time.Minute,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 1},
+	})
+
+	var a *Activities
+	var result FileOverlapResult
+	if err := workflow.ExecuteActivity(actCtx, a.FileOverlapActivity, req).Get(ctx, &result); err != nil {
+		logger.Warn("FileOverlap: check failed", "error", err)
+		return nil, err
+	}
+
+	overlaps, serialized := 0, 0
+	for _, r := range result.Projects {
+		overlaps += len(r.Overlaps)
+		serialized += len(r.Serialized)
+	}
+	logger.Info("FileOverlap complete", "Projects", len(result.Projects), "Overlaps", overlaps, "Serialized", serialized)
+	return &result, nil
+}
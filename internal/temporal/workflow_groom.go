@@ -4,10 +4,29 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/robfig/cron"
 	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/workflow"
 )
 
+// Signals and query of StrategicGroomControlWorkflow.
+const (
+	GroomTriggerSignal  = "groom-trigger"  // run a groom now, even while paused
+	GroomPauseSignal    = "groom-pause"    // skip scheduled runs until resumed
+	GroomResumeSignal   = "groom-resume"   // resume scheduled runs
+	GroomScheduleSignal = "groom-schedule" // replace the cron schedule (string payload)
+	GroomStatusQuery    = "groom-status"   // returns StrategicGroomState
+)
+
+// groomIterationsPerRun bounds the controller's history: after this many
+// wakeups it continues as new with its state.
+const groomIterationsPerRun = 100
+
+// StrategicGroomWorkflowID is the workflow ID of a project's groom controller.
+func StrategicGroomWorkflowID(project string) string {
+	return "strategic-groom-" + project
+}
+
 // TacticalGroomWorkflow runs after every bead completion to tidy the backlog.
 // Spawned as a fire-and-forget child workflow (ParentClosePolicy: ABANDON).
 // Uses fast/cheap LLM tier.
@@ -41,7 +60,130 @@ func TacticalGroomWorkflow(ctx workflow.Context, req TacticalGroomRequest) error
 	return nil
 }
 
-// StrategicGroomWorkflow runs daily at 5:00 AM via CronSchedule.
+// StrategicGroomControlWorkflow schedules a project's strategic grooms. It
+// sleeps until the next cron time and runs StrategicGroomWorkflow as a child.
+// Signals trigger a run at once, pause or resume scheduled runs, or replace
+// the schedule; the groom-status query reports its state. Unlike a Temporal
+// cron it answers queries and signals between runs. Scheduled runs that fall
+// on a cadence holiday move to the next working day; manual triggers still
+// run.
+func StrategicGroomControlWorkflow(ctx workflow.Context, req StrategicGroomControlRequest) error {
+	logger := workflow.GetLogger(ctx)
+
+	state := StrategicGroomState{Project: req.Groom.Project, Schedule: req.Schedule}
+	if req.State != nil {
+		state = *req.State
+	}
+	if err := workflow.SetQueryHandler(ctx, GroomStatusQuery, func() (StrategicGroomState, error) {
+		return state, nil
+	}); err != nil {
+		return err
+	}
+
+	triggerCh := workflow.GetSignalChannel(ctx, GroomTriggerSignal)
+	pauseCh := workflow.GetSignalChannel(ctx, GroomPauseSignal)
+	resumeCh := workflow.GetSignalChannel(ctx, GroomResumeSignal)
+	scheduleCh := workflow.GetSignalChannel(ctx, GroomScheduleSignal)
+
+	for i := 0; ; i++ {
+		// A pending run keeps its time across signals, so a run moved off a
+		// holiday is not lost when the controller wakes up before it.
+		now := workflow.Now(ctx).UTC()
+		if state.Paused {
+			state.NextRunAt = time.Time{}
+		} else if !state.NextRunAt.After(now) {
+			sched, err := cron.ParseStandard(state.Schedule)
+			if err != nil {
+				return fmt.Errorf("strategic groom schedule %q: %w", state.Schedule, err)
+			}
+			state.NextRunAt = nextWorkingTime(ctx, sched.Next(now)).UTC()
+		}
+
+		trigger := ""
+		timerCtx, cancelTimer := workflow.WithCancel(ctx)
+		sel := workflow.NewSelector(ctx)
+		if !state.Paused {
+			sel.AddFuture(workflow.NewTimer(timerCtx, state.NextRunAt.Sub(workflow.Now(ctx))), func(f workflow.Future) {
+				if f.Get(ctx, nil) == nil {
+					trigger = "schedule"
+				}
+			})
+		}
+		sel.AddReceive(triggerCh, func(c workflow.ReceiveChannel, _ bool) {
+			c.Receive(ctx, nil)
+			trigger = "manual"
+		})
+		sel.AddReceive(pauseCh, func(c workflow.ReceiveChannel, _ bool) {
+			c.Receive(ctx, nil)
+			state.Paused = true
+			logger.Info("StrategicGroom paused", "Project", state.Project)
+		})
+		sel.AddReceive(resumeCh, func(c workflow.ReceiveChannel, _ bool) {
+			c.Receive(ctx, nil)
+			state.Paused = false
+			logger.Info("StrategicGroom resumed", "Project", state.Project)
+		})
+		sel.AddReceive(scheduleCh, func(c workflow.ReceiveChannel, _ bool) {
+			var schedule string
+			c.Receive(ctx, &schedule)
+			if _, err := cron.ParseStandard(schedule); err != nil {
+				logger.Warn("StrategicGroom: ignoring invalid schedule", "Schedule", schedule, "error", err)
+				return
+			}
+			state.Schedule = schedule
+			state.NextRunAt = time.Time{}
+		})
+		sel.Select(ctx)
+		cancelTimer()
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if trigger != "" {
+			state.Running = true
+			state.LastTrigger = trigger
+			state.LastRunAt = workflow.Now(ctx).UTC()
+			childCtx := workflow.WithChildOptions(ctx, workflow.ChildWorkflowOptions{
+				WorkflowID: fmt.Sprintf("%s-run-%d", workflow.GetInfo(ctx).WorkflowExecution.ID, state.LastRunAt.Unix()),
+			})
+			err := workflow.ExecuteChildWorkflow(childCtx, StrategicGroomWorkflow, req.Groom).Get(ctx, nil)
+			state.Running = false
+			state.Runs++
+			state.LastError = ""
+			if err != nil {
+				logger.Warn("StrategicGroom run failed", "Project", state.Project, "error", err)
+				state.LastError = err.Error()
+			}
+		}
+
+		// Only continue as new with no signals buffered, so none are lost.
+		if i+1 >= groomIterationsPerRun &&
+			triggerCh.Len()+pauseCh.Len()+resumeCh.Len()+scheduleCh.Len() == 0 {
+			return workflow.NewContinueAsNewError(ctx, StrategicGroomControlWorkflow,
+				StrategicGroomControlRequest{Groom: req.Groom, Schedule: state.Schedule, State: &state})
+		}
+	}
+}
+
+// nextWorkingTime moves a run scheduled at t off cadence holidays. A failed
+// check keeps t.
+func nextWorkingTime(ctx workflow.Context, t time.Time) time.Time {
+	var a *Activities
+	actCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 2},
+	})
+	var shifted time.Time
+	if err := workflow.ExecuteActivity(actCtx, a.NextWorkingDayActivity, t).Get(ctx, &shifted); err != nil {
+		workflow.GetLogger(ctx).Warn("Holiday check failed, keeping the scheduled time", "error", err)
+		return t
+	}
+	return shifted
+}
+
+// StrategicGroomWorkflow runs one strategic groom. StrategicGroomControlWorkflow
+// starts it on the temporal.schedules.strategic_groom cron (daily at 5:00 AM
+// by default) or on demand.
 // Uses premium LLM tier for deep analysis.
 //
 // Pipeline: GenerateRepoMap -> GetBeadState -> StrategicAnalysis -> ApplyMutations -> MorningBriefing
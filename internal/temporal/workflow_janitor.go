@@ -0,0 +1,39 @@
+package temporal
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// BranchJanitorWorkflow deletes stale Cortex feature branches across all
+// projects. Runs on a cron schedule; failures are logged and retried on the
+// next run.
+func BranchJanitorWorkflow(ctx workflow.Context, req BranchJanitorRequest) (*BranchJanitorResult, error) {
+	logger := workflow.GetLogger(ctx)
+
+	actCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 15 * time.Minute,
+		HeartbeatTimeout:    5 * time.Minute,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 1},
+	})
+
+	var a *Activities
+	var result BranchJanitorResult
+	if err := workflow.ExecuteActivity(actCtx, a.BranchJanitorActivity, req).Get(ctx, &result); err != nil {
+		logger.Warn("BranchJanitor: run failed", "error", err)
+		return nil, err
+	}
+
+	for _, report := range result.Projects {
+		logger.Info("BranchJanitor project complete",
+			"Project", report.Project,
+			"Scanned", report.Scanned,
+			"Deleted", len(report.Deleted),
+			"Kept", report.Kept,
+			"Errors", len(report.Errors),
+		)
+	}
+	return &result, nil
+}
@@ -75,3 +75,24 @@ func ContinuousLearnerWorkflow(ctx workflow.Context, req LearnerRequest) error {
 	)
 	return nil
 }
+
+// FailureClusterReportWorkflow runs weekly on a cron schedule and writes the
+// top recurring failure clusters across all projects to a markdown report.
+func FailureClusterReportWorkflow(ctx workflow.Context, req FailureClusterReportRequest) error {
+	logger := workflow.GetLogger(ctx)
+
+	var a *Activities
+	reportCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 2 * time.Minute,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 2},
+	})
+
+	var result FailureClusterReportResult
+	if err := workflow.ExecuteActivity(reportCtx, a.FailureClusterReportActivity, req).Get(ctx, &result); err != nil {
+		logger.Warn("Failure cluster report failed (non-fatal)", "error", err)
+		return nil
+	}
+
+	logger.Info("FailureClusterReport complete", "Clusters", result.Clusters, "Failures", result.Failures, "Path", result.Path)
+	return nil
+}
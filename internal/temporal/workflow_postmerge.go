@@ -0,0 +1,54 @@
+package temporal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// PostMergeWorkflow runs a merged PR's post-merge checks. When they fail and
+// the project reverts failed merges, the revert is posted for approval and
+// the merge commit is reverted only once approved, or on timeout when the
+// default action is approve. Started by the forge webhook on a merge.
+func PostMergeWorkflow(ctx workflow.Context, req PostMergeRequest) (*PostMergeResult, error) {
+	logger := workflow.GetLogger(ctx)
+	gitCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Minute,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 1},
+	})
+	approvalOpts := workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Second,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 3},
+	}
+
+	var a *Activities
+	var result PostMergeResult
+	if err := workflow.ExecuteActivity(gitCtx, a.PostMergeChecksActivity, req).Get(ctx, &result); err != nil {
+		logger.Warn("PostMerge: checks failed to run", "Project", req.Project, "PR", req.PR, "error", err)
+		return nil, err
+	}
+	if result.Passed || !result.Revert {
+		logger.Info("PostMerge complete", "Project", req.Project, "PR", req.PR, "Passed", result.Passed)
+		return &result, nil
+	}
+
+	approved, decidedBy := requestApproval(ctx, approvalOpts, a, ApprovalPrompt{
+		Kind: "revert", Project: req.Project, BeadID: req.BeadID,
+		Summary: fmt.Sprintf("PR #%d failed its post-merge checks; revert merge commit %s?\n%s",
+			req.PR, req.Commit, strings.Join(result.Failures, "\n")),
+	})
+	result.DecidedBy = decidedBy
+	if !approved {
+		logger.Info("PostMerge: revert denied", "Project", req.Project, "PR", req.PR, "DecidedBy", decidedBy)
+		return &result, nil
+	}
+	if err := workflow.ExecuteActivity(gitCtx, a.RevertMergeActivity, req).Get(ctx, nil); err != nil {
+		logger.Warn("PostMerge: revert failed", "Project", req.Project, "PR", req.PR, "error", err)
+		return &result, err
+	}
+	result.Reverted = true
+	return &result, nil
+}
@@ -0,0 +1,39 @@
+package temporal
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// StalledReviewWorkflow checks Cortex PRs waiting on review, nudging the
+// project room for stalled ones. Runs on a cron schedule; failures are logged
+// and retried on the next run.
+func StalledReviewWorkflow(ctx workflow.Context, req StalledReviewRequest) (*StalledReviewResult, error) {
+	logger := workflow.GetLogger(ctx)
+
+	timeout := 5 * time.Minute
+	if req.Reassign {
+		timeout = 30 * time.Minute // agent reviews of stalled PRs run inline
+	}
+	actCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: timeout,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 1},
+	})
+
+	var a *Activities
+	var result StalledReviewResult
+	if err := workflow.ExecuteActivity(actCtx, a.CheckStalledReviewsActivity, req).Get(ctx, &result); err != nil {
+		logger.Warn("StalledReview: check failed", "error", err)
+		return nil, err
+	}
+
+	logger.Info("StalledReview complete",
+		"Checked", result.Checked,
+		"Reviewed", result.Reviewed,
+		"Closed", result.Closed,
+		"Stalled", len(result.Stalled),
+	)
+	return &result, nil
+}
@@ -0,0 +1,31 @@
+package temporal
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/antigravity-dev/cortex/internal/rollout"
+)
+
+// RolloutCompletionWorkflow evaluates rollout completion. Runs on a cron
+// schedule; a failed run is logged and the next run evaluates afresh.
+func RolloutCompletionWorkflow(ctx workflow.Context, req RolloutCompletionRequest) (*rollout.Result, error) {
+	logger := workflow.GetLogger(ctx)
+
+	actCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 5 * time.Minute,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 1},
+	})
+
+	var a *Activities
+	var result rollout.Result
+	if err := workflow.ExecuteActivity(actCtx, a.RolloutCompletionActivity, req).Get(ctx, &result); err != nil {
+		logger.Warn("RolloutCompletion: run failed", "error", err)
+		return nil, err
+	}
+
+	logger.Info("RolloutCompletion complete", "Complete", result.Complete, "Summary", result.Summary())
+	return &result, nil
+}
@@ -0,0 +1,30 @@
+package temporal
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// StageSLAWorkflow flags beads that stayed in a workflow stage longer than
+// the stage's max_duration. Runs on a cron schedule; failures are logged and
+// retried on the next run.
+func StageSLAWorkflow(ctx workflow.Context, req StageSLARequest) (*StageSLAResult, error) {
+	logger := workflow.GetLogger(ctx)
+
+	actCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 5 * time.Minute,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 1},
+	})
+
+	var a *Activities
+	var result StageSLAResult
+	if err := workflow.ExecuteActivity(actCtx, a.CheckStageSLAActivity, req).Get(ctx, &result); err != nil {
+		logger.Warn("StageSLA: check failed", "error", err)
+		return nil, err
+	}
+
+	logger.Info("StageSLA complete", "Checked", result.Checked, "Breaches", len(result.Breaches))
+	return &result, nil
+}
@@ -0,0 +1,29 @@
+package temporal
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// StandupDigestWorkflow posts each project's daily standup digest. Runs on a
+// cron schedule; a failed run is logged and the next day's run starts fresh.
+func StandupDigestWorkflow(ctx workflow.Context, req StandupDigestRequest) (*StandupDigestResult, error) {
+	logger := workflow.GetLogger(ctx)
+
+	actCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 5 * time.Minute,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 1},
+	})
+
+	var a *Activities
+	var result StandupDigestResult
+	if err := workflow.ExecuteActivity(actCtx, a.StandupDigestActivity, req).Get(ctx, &result); err != nil {
+		logger.Warn("StandupDigest: run failed", "error", err)
+		return nil, err
+	}
+
+	logger.Info("StandupDigest complete", "Date", result.Date, "Projects", len(result.Projects), "Posted", result.Posted)
+	return &result, nil
+}
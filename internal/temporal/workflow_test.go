@@ -1,18 +1,28 @@
 package temporal
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/testsuite"
+
+	"github.com/antigravity-dev/cortex/internal/beads"
+	"github.com/antigravity-dev/cortex/internal/calendar"
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/dispatch"
 )
 
 // stubActivities mocks all activities used by CortexAgentWorkflow for a clean
-// success path: plan → approve → execute → review(approved) → semgrep(pass) → dod(pass) → record.
+// success path: plan → approve → execute → autofix → review(approved) → semgrep(pass) → dod(pass) → record.
 func stubActivities(env *testsuite.TestWorkflowEnvironment) {
 	var a *Activities
 
+	env.OnActivity(a.AssignExperimentsActivity, mock.Anything, mock.Anything).Return([]ExperimentAssignment(nil), nil).Maybe()
+
 	env.OnActivity(a.StructuredPlanActivity, mock.Anything, mock.Anything).Return(&StructuredPlan{
 		Summary:            "Add widget endpoint",
 		Steps:              []PlanStep{{Description: "Create handler", File: "handler.go", Rationale: "API needs it"}},
@@ -37,7 +47,28 @@ func stubActivities(env *testsuite.TestWorkflowEnvironment) {
 
 	env.OnActivity(a.DoDVerifyActivity, mock.Anything, mock.Anything).Return(&DoDResult{
 		Passed: true,
+		Checks: []CheckResult{{Command: "go test ./...", Passed: true}},
 	}, nil)
+
+	env.OnActivity(a.CompleteTaskActivity, mock.Anything, mock.Anything).Return(&CompletionResult{}, nil).Maybe()
+	env.OnActivity(a.CreateFollowUpBeadsActivity, mock.Anything, mock.Anything).Return(&FollowUpResult{}, nil).Maybe()
+
+	env.OnActivity(a.ReservePairActivity, mock.Anything, mock.Anything).Return(&PairPlan{}, nil).Maybe()
+
+	env.OnActivity(a.FetchReviewFeedbackActivity, mock.Anything, mock.Anything).Return(&ReviewFeedback{}, nil).Maybe()
+
+	env.OnActivity(a.RequestApprovalActivity, mock.Anything, mock.Anything).Return(&ApprovalTicket{}, nil).Maybe()
+	env.OnActivity(a.PremiumBudgetActivity, mock.Anything, mock.Anything).Return(&PremiumBudget{}, nil).Maybe()
+	env.OnActivity(a.SeedBeadStageActivity, mock.Anything, mock.Anything).Return("", nil).Maybe()
+	env.OnActivity(a.AdvanceBeadStageActivity, mock.Anything, mock.Anything).Return("", nil).Maybe()
+	env.OnActivity(a.AcquireSlotActivity, mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Maybe()
+	env.OnActivity(a.ReleaseSlotActivity, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	env.OnActivity(a.AcquireWorktreeActivity, mock.Anything, mock.Anything).Return((*dispatch.WorktreeLease)(nil), nil).Maybe()
+	env.OnActivity(a.CreatePRActivity, mock.Anything, mock.Anything).Return(&CreatePRResult{}, nil).Maybe()
+
+	env.OnActivity(a.AutofixActivity, mock.Anything, mock.Anything).Return(&AutofixResult{}, nil).Maybe()
+	env.OnActivity(a.TestWriterActivity, mock.Anything, mock.Anything).Return(&TestWriterResult{}, nil).Maybe()
 }
 
 // TestCHUMChildWorkflowsSpawn verifies that CortexAgentWorkflow spawns
@@ -91,11 +122,113 @@ func TestCHUMChildWorkflowsSpawn(t *testing.T) {
 	require.Equal(t, 2, outcome.TotalTokens.CacheCreationTokens)
 	require.InDelta(t, 0.051, outcome.TotalTokens.CostUSD, 0.0001)
 	require.Len(t, outcome.ActivityTokens, 3)
+	require.Equal(t, []CheckResult{{Command: "go test ./...", Passed: true}}, outcome.DoDChecks)
 	require.Equal(t, "plan", outcome.ActivityTokens[0].ActivityName)
 	require.Equal(t, "execute", outcome.ActivityTokens[1].ActivityName)
 	require.Equal(t, "review", outcome.ActivityTokens[2].ActivityName)
 }
 
+// TestWorktreeLeaseUsedAndReleased verifies that a task granted a pooled
+// worktree executes there, keeps bead commands and CHUM children on the
+// shared workspace, opens a PR for its branch and hands the worktree
+// back when it finishes.
+func TestWorktreeLeaseUsedAndReleased(t *testing.T) {
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+	var a *Activities
+
+	lease := &dispatch.WorktreeLease{Project: "test-project", Slot: 1, Path: "/tmp/worktrees/test-project/1", Branch: "feat/wt", BeadID: "wt"}
+	env.OnActivity(a.AcquireWorktreeActivity, mock.Anything, mock.Anything).Return(lease, nil)
+	var execDir string
+	env.OnActivity(a.ExecuteActivity, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		execDir = args.Get(2).(TaskRequest).WorkDir
+	}).Return(&ExecutionResult{ExitCode: 0, Output: "implemented handler", Agent: "claude"}, nil)
+	var completion CompletionRequest
+	env.OnActivity(a.CompleteTaskActivity, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		completion = args.Get(1).(CompletionRequest)
+	}).Return(&CompletionResult{}, nil)
+	var released *dispatch.WorktreeLease
+	env.OnActivity(a.ReleaseWorktreeActivity, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		l := args.Get(1).(dispatch.WorktreeLease)
+		released = &l
+	}).Return(nil)
+	var prReq CreatePRRequest
+	env.OnActivity(a.CreatePRActivity, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		prReq = args.Get(1).(CreatePRRequest)
+	}).Return(&CreatePRResult{URL: "https://github.com/o/r/pull/7", Number: 7}, nil)
+	var outcome OutcomeRecord
+	env.OnActivity(a.RecordOutcomeActivity, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		outcome = args.Get(1).(OutcomeRecord)
+	}).Return(nil)
+	stubActivities(env)
+	env.OnWorkflow(ContinuousLearnerWorkflow, mock.Anything, mock.Anything).Return(nil)
+	env.OnWorkflow(TacticalGroomWorkflow, mock.Anything, mock.Anything).Return(nil)
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow("human-approval", "APPROVED")
+	}, 0)
+	env.ExecuteWorkflow(CortexAgentWorkflow, TaskRequest{
+		BeadID:  "wt",
+		Project: "test-project",
+		Prompt:  "add a widget endpoint",
+		Agent:   "claude",
+		WorkDir: "/tmp/test",
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Equal(t, lease.Path, execDir)
+	require.Equal(t, "/tmp/test", completion.WorkDir)
+	require.NotNil(t, released)
+	require.Equal(t, *lease, *released)
+
+	// The pushed branch gets a PR, recorded with the dispatch.
+	require.Equal(t, "feat/wt", prReq.Branch)
+	require.Equal(t, lease.Path, prReq.WorkDir)
+	require.Equal(t, &CreatePRResult{URL: "https://github.com/o/r/pull/7", Number: 7}, outcome.PR)
+}
+
+// TestClaimRenewedAndReleased verifies that a workflow started on a claimed
+// bead renews the claim while it waits and releases it when it ends.
+func TestClaimRenewedAndReleased(t *testing.T) {
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+	var a *Activities
+
+	renewals := 0
+	env.OnActivity(a.RenewClaimActivity, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		renewals++
+	}).Return(nil)
+	var released TaskRequest
+	env.OnActivity(a.ReleaseClaimActivity, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		released = args.Get(1).(TaskRequest)
+	}).Return(nil)
+	env.OnActivity(a.RecordOutcomeActivity, mock.Anything, mock.Anything).Return(nil)
+	stubActivities(env)
+	env.OnWorkflow(ContinuousLearnerWorkflow, mock.Anything, mock.Anything).Return(nil)
+	env.OnWorkflow(TacticalGroomWorkflow, mock.Anything, mock.Anything).Return(nil)
+
+	// Approve only after two renewal intervals have passed.
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow("human-approval", "APPROVED")
+	}, 7*time.Minute)
+	env.ExecuteWorkflow(CortexAgentWorkflow, TaskRequest{
+		BeadID:      "claimed",
+		Project:     "test-project",
+		Prompt:      "add a widget endpoint",
+		Agent:       "claude",
+		WorkDir:     "/tmp/test",
+		ClaimHolder: "host-a",
+		ClaimTTL:    9 * time.Minute,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.GreaterOrEqual(t, renewals, 2)
+	require.Equal(t, "claimed", released.BeadID)
+	require.Equal(t, "host-a", released.ClaimHolder)
+}
+
 // TestCHUMNotSpawnedOnFailure verifies that CHUM workflows are NOT spawned
 // when DoD fails and the workflow escalates.
 func TestCHUMNotSpawnedOnFailure(t *testing.T) {
@@ -104,6 +237,7 @@ func TestCHUMNotSpawnedOnFailure(t *testing.T) {
 
 	var a *Activities
 
+	env.OnActivity(a.AssignExperimentsActivity, mock.Anything, mock.Anything).Return([]ExperimentAssignment(nil), nil)
 	env.OnActivity(a.StructuredPlanActivity, mock.Anything, mock.Anything).Return(&StructuredPlan{
 		Summary:            "broken feature",
 		Steps:              []PlanStep{{Description: "break things", File: "main.go", Rationale: "chaos"}},
@@ -278,6 +412,130 @@ func TestStrategicGroomWorkflowPipeline(t *testing.T) {
 	env.AssertExpectations(t)
 }
 
+// TestStrategicGroomControlWorkflow drives the groom controller through a
+// pause, a manual trigger while paused, a schedule change and a resume.
+func TestStrategicGroomControlWorkflow(t *testing.T) {
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(StrategicGroomWorkflow)
+	env.OnWorkflow(StrategicGroomWorkflow, mock.Anything, mock.Anything).Return(nil)
+	var a *Activities
+	env.OnActivity(a.NextWorkingDayActivity, mock.Anything, mock.Anything).Return(func(_ context.Context, t time.Time) (time.Time, error) {
+		return t, nil
+	})
+
+	start := time.Date(2026, 3, 2, 4, 0, 0, 0, time.UTC)
+	env.SetStartTime(start)
+
+	status := func() StrategicGroomState {
+		t.Helper()
+		val, err := env.QueryWorkflow(GroomStatusQuery)
+		require.NoError(t, err)
+		var st StrategicGroomState
+		require.NoError(t, val.Get(&st))
+		return st
+	}
+
+	env.RegisterDelayedCallback(func() {
+		st := status()
+		require.False(t, st.Paused)
+		require.Equal(t, start.Add(time.Hour), st.NextRunAt)
+		env.SignalWorkflow(GroomPauseSignal, nil)
+	}, 30*time.Minute)
+	env.RegisterDelayedCallback(func() {
+		st := status()
+		require.True(t, st.Paused)
+		require.Zero(t, st.Runs, "paused controller must skip the 05:00 run")
+		require.True(t, st.NextRunAt.IsZero())
+		env.SignalWorkflow(GroomTriggerSignal, nil)
+	}, 2*time.Hour)
+	env.RegisterDelayedCallback(func() {
+		st := status()
+		require.Equal(t, 1, st.Runs)
+		require.Equal(t, "manual", st.LastTrigger)
+		require.True(t, st.Paused)
+		env.SignalWorkflow(GroomScheduleSignal, "not a cron")
+		env.SignalWorkflow(GroomScheduleSignal, "30 7 * * *")
+		env.SignalWorkflow(GroomResumeSignal, nil)
+	}, 2*time.Hour+30*time.Minute)
+	env.RegisterDelayedCallback(func() {
+		st := status()
+		require.Equal(t, "30 7 * * *", st.Schedule)
+		require.Equal(t, start.Add(3*time.Hour+30*time.Minute), st.NextRunAt)
+	}, 3*time.Hour)
+	env.RegisterDelayedCallback(func() {
+		st := status()
+		require.Equal(t, 2, st.Runs)
+		require.Equal(t, "schedule", st.LastTrigger)
+		require.Equal(t, start.Add(27*time.Hour+30*time.Minute), st.NextRunAt)
+		env.CancelWorkflow()
+	}, 4*time.Hour)
+
+	env.ExecuteWorkflow(StrategicGroomControlWorkflow, StrategicGroomControlRequest{
+		Groom:    StrategicGroomRequest{Project: "test-project"},
+		Schedule: "0 5 * * *",
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.Error(t, env.GetWorkflowError())
+	env.AssertNumberOfCalls(t, "StrategicGroomWorkflow", 2)
+}
+
+// TestStrategicGroomControlShiftsHolidays verifies that a scheduled groom on
+// a holiday moves to the next working day, and that a manual trigger in
+// between neither runs it early nor drops it.
+func TestStrategicGroomControlShiftsHolidays(t *testing.T) {
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(StrategicGroomWorkflow)
+	env.OnWorkflow(StrategicGroomWorkflow, mock.Anything, mock.Anything).Return(nil)
+	acts := &Activities{
+		Holidays: calendar.New([]calendar.Holiday{{Date: "2026-12-25"}}),
+		Cadence:  config.Cadence{Timezone: "Europe/Helsinki"},
+	}
+	env.RegisterActivity(acts.NextWorkingDayActivity)
+
+	// Friday 2026-12-25 04:00 UTC is already the holiday in Helsinki.
+	start := time.Date(2026, 12, 25, 4, 0, 0, 0, time.UTC)
+	env.SetStartTime(start)
+	monday := time.Date(2026, 12, 28, 5, 0, 0, 0, time.UTC)
+
+	status := func() StrategicGroomState {
+		t.Helper()
+		val, err := env.QueryWorkflow(GroomStatusQuery)
+		require.NoError(t, err)
+		var st StrategicGroomState
+		require.NoError(t, val.Get(&st))
+		return st
+	}
+	env.RegisterDelayedCallback(func() {
+		st := status()
+		require.Zero(t, st.Runs, "the Friday run falls on a holiday")
+		require.Equal(t, monday, st.NextRunAt)
+		env.SignalWorkflow(GroomTriggerSignal, nil)
+	}, 2*time.Hour)
+	env.RegisterDelayedCallback(func() {
+		st := status()
+		require.Equal(t, 1, st.Runs)
+		require.Equal(t, monday, st.NextRunAt, "manual trigger dropped the shifted run")
+	}, 3*time.Hour)
+	env.RegisterDelayedCallback(func() {
+		st := status()
+		require.Equal(t, 2, st.Runs)
+		require.Equal(t, "schedule", st.LastTrigger)
+		require.Equal(t, time.Date(2027, 1, 1, 5, 0, 0, 0, time.UTC), st.NextRunAt)
+		env.CancelWorkflow()
+	}, monday.Sub(start)+time.Hour)
+
+	env.ExecuteWorkflow(StrategicGroomControlWorkflow, StrategicGroomControlRequest{
+		Groom:    StrategicGroomRequest{Project: "test-project"},
+		Schedule: "0 5 * * 5",
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	env.AssertNumberOfCalls(t, "StrategicGroomWorkflow", 2)
+}
+
 // TestPlanRejected verifies that rejecting the plan short-circuits the workflow.
 func TestPlanRejected(t *testing.T) {
 	s := testsuite.WorkflowTestSuite{}
@@ -285,12 +543,14 @@ func TestPlanRejected(t *testing.T) {
 
 	var a *Activities
 
+	env.OnActivity(a.AssignExperimentsActivity, mock.Anything, mock.Anything).Return([]ExperimentAssignment(nil), nil)
 	env.OnActivity(a.StructuredPlanActivity, mock.Anything, mock.Anything).Return(&StructuredPlan{
 		Summary:            "risky refactor",
 		Steps:              []PlanStep{{Description: "rewrite everything", File: "main.go", Rationale: "yolo"}},
 		FilesToModify:      []string{"main.go"},
 		AcceptanceCriteria: []string{"nothing breaks"},
 	}, nil)
+	env.OnActivity(a.RequestApprovalActivity, mock.Anything, mock.Anything).Return(&ApprovalTicket{}, nil)
 
 	env.OnActivity(a.RecordOutcomeActivity, mock.Anything, mock.Anything).Return(nil)
 
@@ -314,4 +574,521 @@ func TestPlanRejected(t *testing.T) {
 	env.AssertActivityNotCalled(t, "ExecuteActivity", mock.Anything, mock.Anything, mock.Anything)
 }
 
+// TestExperimentVariantsApplied verifies assigned variants override the role
+// agents and are carried through to the recorded outcome.
+func TestExperimentVariantsApplied(t *testing.T) {
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+	var a *Activities
+
+	assignments := []ExperimentAssignment{
+		{RunID: 7, Experiment: "exec-agent", Variant: "codex", Role: "coder", Agent: "codex"},
+		{RunID: 8, Experiment: "plan-prompt", Variant: "terse", Role: "planner", Prompt: "/tmp/plan.tmpl"},
+	}
+	env.OnActivity(a.AssignExperimentsActivity, mock.Anything, mock.Anything).Return(assignments, nil)
+	env.OnWorkflow(ContinuousLearnerWorkflow, mock.Anything, mock.Anything).Return(nil)
+	env.OnWorkflow(TacticalGroomWorkflow, mock.Anything, mock.Anything).Return(nil)
+
+	var planReq, execReq TaskRequest
+	env.OnActivity(a.StructuredPlanActivity, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		planReq = args.Get(1).(TaskRequest)
+	}).Return(&StructuredPlan{Summary: "x", AcceptanceCriteria: []string{"y"}}, nil)
+	env.OnActivity(a.ExecuteActivity, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		execReq = args.Get(2).(TaskRequest)
+	}).Return(&ExecutionResult{Agent: "codex"}, nil)
+	stubActivities(env) // registered after the capturing mocks so those match first
+
+	var outcome OutcomeRecord
+	env.OnActivity(a.RecordOutcomeActivity, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		outcome = args.Get(1).(OutcomeRecord)
+	}).Return(nil)
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow("human-approval", "APPROVED")
+	}, 0)
+
+	env.ExecuteWorkflow(CortexAgentWorkflow, TaskRequest{
+		BeadID:  "test-bead-exp",
+		Project: "test-project",
+		Prompt:  "experiment",
+		Agent:   "claude",
+		WorkDir: "/tmp/test",
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Equal(t, "codex", planReq.Agent, "planner follows the coder agent when only its prompt varies")
+	require.Equal(t, "codex", execReq.Agent)
+	require.Equal(t, 1, execReq.Attempt)
+	require.Equal(t, "claude", execReq.Reviewer, "reviewer is cross-assigned from the variant agent")
+	require.Equal(t, assignments, outcome.Experiments)
+}
+
 func intPtr(i int) *int { return &i }
+
+func TestProviderWarmupWorkflowPingsColdProviders(t *testing.T) {
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+
+	var a *Activities
+
+	env.OnActivity(a.ColdProvidersActivity, mock.Anything, mock.Anything).Return([]string{"claude", "codex-spark"}, nil)
+	env.OnActivity(a.WarmupProviderActivity, mock.Anything, "claude", "claude", "idle").Return(&WarmupResult{
+		Provider: "claude", DurationS: 42, Success: true,
+	}, nil).Once()
+	env.OnActivity(a.WarmupProviderActivity, mock.Anything, "codex-spark", "codex", "idle").Return(&WarmupResult{
+		Provider: "codex-spark", DurationS: 3, Success: false, Error: "auth expired",
+	}, nil).Once()
+
+	env.ExecuteWorkflow(ProviderWarmupWorkflow, WarmupRequest{
+		Providers: []string{"claude", "codex-spark", "gemini"},
+		CLIs:      map[string]string{"codex-spark": "codex"},
+		Reason:    "idle",
+		IdleAfter: 2 * time.Hour,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+}
+
+func TestProviderWarmupWorkflowNoColdProviders(t *testing.T) {
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+
+	var a *Activities
+
+	env.OnActivity(a.ColdProvidersActivity, mock.Anything, mock.Anything).Return([]string{}, nil)
+
+	env.ExecuteWorkflow(ProviderWarmupWorkflow, WarmupRequest{Providers: []string{"claude"}, Reason: "idle"})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	env.AssertNotCalled(t, "WarmupProviderActivity", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFailureClusterReportWorkflow(t *testing.T) {
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+
+	var a *Activities
+
+	env.OnActivity(a.FailureClusterReportActivity, mock.Anything, mock.Anything).Return(&FailureClusterReportResult{
+		Path:     "/tmp/reports/failure-clusters-2026-02-16.md",
+		Clusters: 3,
+		Failures: 11,
+	}, nil)
+
+	env.ExecuteWorkflow(FailureClusterReportWorkflow, FailureClusterReportRequest{WindowDays: 7, Limit: 10})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+}
+
+func TestStalledReviewWorkflow(t *testing.T) {
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+	var a *Activities
+
+	env.OnActivity(a.CheckStalledReviewsActivity, mock.Anything, mock.Anything).Return(&StalledReviewResult{
+		Checked:  3,
+		Reviewed: 1,
+		Stalled:  []StalledReview{{Project: "cortex", PRNumber: 42, Reviewer: "codex"}},
+	}, nil)
+
+	env.ExecuteWorkflow(StalledReviewWorkflow, StalledReviewRequest{Threshold: 24 * time.Hour})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var result StalledReviewResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Equal(t, 3, result.Checked)
+	require.Len(t, result.Stalled, 1)
+}
+
+func TestEpicRollupWorkflow(t *testing.T) {
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+	var a *Activities
+
+	env.OnActivity(a.EpicRollupActivity, mock.Anything, mock.Anything).Return(&EpicRollupResult{
+		Epics: []EpicProgress{
+			{Project: "cortex", EpicID: "e1", Closed: 2, Total: 2, AutoClosed: true},
+			{Project: "cortex", EpicID: "e2", Closed: 1, Total: 3},
+		},
+	}, nil)
+
+	env.ExecuteWorkflow(EpicRollupWorkflow, EpicRollupRequest{Threshold: 1})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var result EpicRollupResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Len(t, result.Epics, 2)
+}
+
+func TestBacklogTriageWorkflow(t *testing.T) {
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+	var a *Activities
+
+	env.OnActivity(a.BacklogTriageActivity, mock.Anything, mock.Anything).Return(&TriageResult{
+		Projects: []TriageReport{{Project: "cortex", Stale: []string{"cx-9"}}},
+	}, nil)
+
+	env.ExecuteWorkflow(BacklogTriageWorkflow, TriageRequest{Similarity: 0.8})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var result TriageResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Equal(t, []string{"cx-9"}, result.Projects[0].Stale)
+}
+
+func TestFileOverlapWorkflow(t *testing.T) {
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+	var a *Activities
+
+	env.OnActivity(a.FileOverlapActivity, mock.Anything, mock.Anything).Return(&FileOverlapResult{
+		Projects: []FileOverlapReport{{
+			Project:  "cortex",
+			Overlaps: []beads.FileOverlap{{First: "cx-1", Second: "cx-2", Files: []string{"api.go"}}},
+		}},
+	}, nil)
+
+	env.ExecuteWorkflow(FileOverlapWorkflow, FileOverlapRequest{Mode: "warn"})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var result FileOverlapResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Equal(t, "cx-2", result.Projects[0].Overlaps[0].Second)
+}
+
+func TestBranchJanitorWorkflow(t *testing.T) {
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+	var a *Activities
+
+	env.OnActivity(a.BranchJanitorActivity, mock.Anything, mock.Anything).Return(&BranchJanitorResult{
+		Projects: []BranchJanitorReport{{
+			Project: "cortex", Scanned: 4, Kept: 3,
+			Deleted: []JanitorBranch{{Branch: "feat/cx-1", BeadID: "cx-1", Reason: "bead closed"}},
+		}},
+	}, nil)
+
+	env.ExecuteWorkflow(BranchJanitorWorkflow, BranchJanitorRequest{GracePeriod: 7 * 24 * time.Hour})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var result BranchJanitorResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Len(t, result.Projects, 1)
+	require.Len(t, result.Projects[0].Deleted, 1)
+}
+
+func TestChiefWorkflowDispatchesChief(t *testing.T) {
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+	var a *Activities
+
+	env.OnActivity(a.NextWorkingDayActivity, mock.Anything, mock.Anything).Return(func(_ context.Context, t time.Time) (time.Time, error) {
+		return t, nil
+	})
+	env.OnActivity(a.ChiefPromptActivity, mock.Anything, mock.Anything).Return("groom the backlog", nil)
+	env.OnActivity(a.SprintReviewActivity, mock.Anything, mock.Anything).Return("\n## Velocity", nil)
+	env.OnActivity(a.ClaimBeadActivity, mock.Anything, mock.Anything).Return(func(_ context.Context, req TaskRequest) (TaskRequest, error) {
+		req.ClaimHolder = "host-a"
+		return req, nil
+	})
+	env.RegisterWorkflow(CortexAgentWorkflow)
+	var task TaskRequest
+	env.OnWorkflow(CortexAgentWorkflow, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		task = args.Get(1).(TaskRequest)
+	}).Return(nil)
+
+	env.ExecuteWorkflow(ChiefWorkflow, ChiefRequest{Project: "cortex", WorkDir: "/tmp/cortex", Agent: "claude"})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var id string
+	require.NoError(t, env.GetWorkflowResult(&id))
+	require.Contains(t, id, "chief-cortex-")
+	require.Equal(t, id, task.BeadID)
+	require.Equal(t, "cortex", task.Project)
+	require.Equal(t, "groom the backlog\n## Velocity", task.Prompt)
+	require.Equal(t, "/tmp/cortex", task.WorkDir)
+	require.Equal(t, "host-a", task.ClaimHolder, "child dispatched without the bead claim")
+}
+
+func TestChiefWorkflowSkipsClaimedBead(t *testing.T) {
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+	var a *Activities
+
+	env.OnActivity(a.NextWorkingDayActivity, mock.Anything, mock.Anything).Return(func(_ context.Context, t time.Time) (time.Time, error) {
+		return t, nil
+	})
+	env.OnActivity(a.ChiefPromptActivity, mock.Anything, mock.Anything).Return("groom the backlog", nil)
+	env.OnActivity(a.SprintReviewActivity, mock.Anything, mock.Anything).Return("", nil)
+	env.OnActivity(a.ClaimBeadActivity, mock.Anything, mock.Anything).Return(TaskRequest{},
+		temporal.NewNonRetryableApplicationError("bead is claimed by another cortex instance", beadClaimedErrorType, nil))
+	env.RegisterWorkflow(CortexAgentWorkflow)
+	started := false
+	env.OnWorkflow(CortexAgentWorkflow, mock.Anything, mock.Anything).Run(func(mock.Arguments) { started = true }).Return(nil).Maybe()
+
+	env.ExecuteWorkflow(ChiefWorkflow, ChiefRequest{Project: "cortex", WorkDir: "/tmp/cortex", Agent: "claude"})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.ErrorContains(t, env.GetWorkflowError(), "claimed by another cortex instance")
+	require.False(t, started, "child dispatched on a bead another instance holds")
+}
+
+func TestChiefWorkflowWaitsOutHolidays(t *testing.T) {
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+	var a *Activities
+
+	start := time.Date(2026, 12, 25, 9, 0, 0, 0, time.UTC)
+	env.SetStartTime(start)
+	env.OnActivity(a.NextWorkingDayActivity, mock.Anything, mock.Anything).Return(func(_ context.Context, t time.Time) (time.Time, error) {
+		return t.AddDate(0, 0, 3), nil
+	})
+	var promptedAt time.Time
+	env.OnActivity(a.ChiefPromptActivity, mock.Anything, mock.Anything).Return(func(context.Context, ChiefRequest) (string, error) {
+		promptedAt = env.Now()
+		return "groom the backlog", nil
+	})
+	env.OnActivity(a.SprintReviewActivity, mock.Anything, mock.Anything).Return("", nil)
+	env.OnActivity(a.ClaimBeadActivity, mock.Anything, mock.Anything).Return(func(_ context.Context, req TaskRequest) (TaskRequest, error) {
+		return req, nil
+	})
+	env.RegisterWorkflow(CortexAgentWorkflow)
+	env.OnWorkflow(CortexAgentWorkflow, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(ChiefWorkflow, ChiefRequest{Project: "cortex", WorkDir: "/tmp/cortex", Agent: "claude"})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var id string
+	require.NoError(t, env.GetWorkflowResult(&id))
+	require.NotEmpty(t, id, "holiday run skipped instead of moved")
+	require.False(t, promptedAt.Before(start.AddDate(0, 0, 3)), "chief briefed on the holiday, at %v", promptedAt)
+}
+
+func TestBurnInReportWorkflow(t *testing.T) {
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+	var a *Activities
+
+	env.OnActivity(a.BurnInReportActivity, mock.Anything, mock.Anything).Return(&BurnInReportResult{
+		Date: "2026-03-08", Failing: []string{"cortex"}, JSONPath: "/r/burnin-2026-03-08.json",
+	}, nil)
+
+	env.ExecuteWorkflow(BurnInReportWorkflow, BurnInReportRequest{Window: 7 * 24 * time.Hour, ReportsDir: "/r"})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var result BurnInReportResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.False(t, result.Pass)
+	require.Equal(t, []string{"cortex"}, result.Failing)
+}
+
+func TestRequiredReviewBlocksOnReviewFailure(t *testing.T) {
+	run := func(requireReview bool) (*testsuite.TestWorkflowEnvironment, OutcomeRecord) {
+		s := testsuite.WorkflowTestSuite{}
+		env := s.NewTestWorkflowEnvironment()
+		var a *Activities
+
+		env.OnActivity(a.CodeReviewActivity, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil,
+			temporal.NewNonRetryableApplicationError("reviewer CLI missing", "ReviewFailed", nil))
+		stubActivities(env)
+		env.OnActivity(a.EscalateActivity, mock.Anything, mock.Anything).Return(nil).Maybe()
+		env.OnWorkflow(ContinuousLearnerWorkflow, mock.Anything, mock.Anything).Return(nil).Maybe()
+		env.OnWorkflow(TacticalGroomWorkflow, mock.Anything, mock.Anything).Return(nil).Maybe()
+		var outcome OutcomeRecord
+		env.OnActivity(a.RecordOutcomeActivity, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			outcome = args.Get(1).(OutcomeRecord)
+		}).Return(nil)
+		env.RegisterDelayedCallback(func() {
+			env.SignalWorkflow("human-approval", "APPROVED")
+		}, 0)
+
+		env.ExecuteWorkflow(CortexAgentWorkflow, TaskRequest{
+			BeadID: "b-1", Project: "p", Agent: "claude", WorkDir: "/tmp/test", RequireReview: requireReview,
+		})
+		require.True(t, env.IsWorkflowCompleted())
+		return env, outcome
+	}
+
+	env, outcome := run(false)
+	require.NoError(t, env.GetWorkflowError(), "a review that fails to run does not block by default")
+	require.Equal(t, "completed", outcome.Status)
+
+	env, outcome = run(true)
+	require.Error(t, env.GetWorkflowError())
+	require.Equal(t, "escalated", outcome.Status)
+	require.Contains(t, outcome.DoDFailures, "Attempt 1 review error")
+	require.Contains(t, outcome.DoDFailures, "reviewer CLI missing")
+	env.AssertActivityNotCalled(t, "DoDVerifyActivity", mock.Anything, mock.Anything)
+}
+
+func TestCostReportWorkflow(t *testing.T) {
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+	var a *Activities
+
+	env.OnActivity(a.CostReportActivity, mock.Anything, mock.Anything).Return(&CostReportResult{
+		Month: "2026-02", CostUSD: 12.5, Projects: 2, JSONPath: "/r/cost-2026-02.json",
+	}, nil)
+
+	env.ExecuteWorkflow(CostReportWorkflow, CostReportRequest{ReportsDir: "/r"})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var result CostReportResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Equal(t, "2026-02", result.Month)
+	require.Equal(t, 12.5, result.CostUSD)
+}
+
+func TestConfidenceGatesCompletion(t *testing.T) {
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+	var a *Activities
+
+	env.OnActivity(a.ExecuteActivity, mock.Anything, mock.Anything, mock.Anything).Return(&ExecutionResult{
+		Output: "done\nCONFIDENCE: 0.4", Agent: "claude", Confidence: Confidence{Score: 0.4, Reported: true},
+		Report: &AgentReport{FilesChanged: []string{"widget.go"}, FollowUps: []string{"widget docs"}},
+	}, nil)
+	var completion CompletionRequest
+	env.OnActivity(a.CompleteTaskActivity, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		completion = args.Get(1).(CompletionRequest)
+	}).Return(&CompletionResult{HumanReview: true, Reason: "confidence 0.40 is below 0.70"}, nil)
+	var outcome OutcomeRecord
+	env.OnActivity(a.RecordOutcomeActivity, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		outcome = args.Get(1).(OutcomeRecord)
+	}).Return(nil)
+	var followUps FollowUpRequest
+	env.OnActivity(a.CreateFollowUpBeadsActivity, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		followUps = args.Get(1).(FollowUpRequest)
+	}).Return(&FollowUpResult{Created: []string{"bead-conf-2"}}, nil)
+	stubActivities(env)
+	env.OnWorkflow(ContinuousLearnerWorkflow, mock.Anything, mock.Anything).Return(nil)
+	env.OnWorkflow(TacticalGroomWorkflow, mock.Anything, mock.Anything).Return(nil)
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow("human-approval", "APPROVED")
+	}, 0)
+
+	env.ExecuteWorkflow(CortexAgentWorkflow, TaskRequest{
+		BeadID:  "bead-conf",
+		Project: "test-project",
+		Prompt:  "add a widget endpoint",
+		Agent:   "claude",
+		WorkDir: "/tmp/test",
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Equal(t, "bead-conf", completion.BeadID)
+	require.Equal(t, Confidence{Score: 0.4, Reported: true}, completion.Confidence)
+	require.Equal(t, "completed", outcome.Status)
+	require.True(t, outcome.HumanReview)
+	require.Equal(t, 0.4, outcome.Confidence.Score)
+	require.NotNil(t, outcome.Report)
+	require.Equal(t, []string{"widget.go"}, outcome.Report.FilesChanged)
+	require.Equal(t, "bead-conf", followUps.BeadID)
+	require.Equal(t, []string{"widget docs"}, followUps.FollowUps)
+}
+
+func TestReviewFeedbackReachesCoderAndIsResolved(t *testing.T) {
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+	var a *Activities
+
+	feedback := &ReviewFeedback{Project: "test-project", PRNumber: 7, Threads: []ReviewThread{
+		{ID: "T1", Path: "main.go", Line: 12, Author: "alice", Body: "alice: Handle the error"},
+	}}
+	env.OnActivity(a.FetchReviewFeedbackActivity, mock.Anything, mock.Anything).Return(feedback, nil)
+	var execPlan StructuredPlan
+	env.OnActivity(a.ExecuteActivity, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		execPlan = args.Get(1).(StructuredPlan)
+	}).Return(&ExecutionResult{Agent: "claude"}, nil)
+	var resolved ReviewFeedback
+	env.OnActivity(a.ResolveReviewThreadsActivity, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		resolved = args.Get(1).(ReviewFeedback)
+	}).Return(1, nil).Once()
+	stubActivities(env)
+	env.OnActivity(a.RecordOutcomeActivity, mock.Anything, mock.Anything).Return(nil)
+	env.OnWorkflow(ContinuousLearnerWorkflow, mock.Anything, mock.Anything).Return(nil)
+	env.OnWorkflow(TacticalGroomWorkflow, mock.Anything, mock.Anything).Return(nil)
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow("human-approval", "APPROVED")
+	}, 0)
+
+	env.ExecuteWorkflow(CortexAgentWorkflow, TaskRequest{
+		BeadID:  "bead-fb",
+		Project: "test-project",
+		Prompt:  "address review",
+		Agent:   "claude",
+		WorkDir: "/tmp/test",
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Contains(t, execPlan.PreviousErrors, "Unresolved review thread on PR #7 at main.go:12:\nalice: Handle the error")
+	require.Equal(t, *feedback, resolved)
+	env.AssertExpectations(t)
+}
+
+func TestPairModeRunsSingleSession(t *testing.T) {
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+	var a *Activities
+
+	env.OnActivity(a.ReservePairActivity, mock.Anything, mock.Anything).Return(&PairPlan{
+		Active: true, CoderProvider: "claude-max20", ReviewerProvider: "codex", MaxTurns: 4,
+	}, nil)
+	var turns int
+	env.OnActivity(a.PairSessionActivity, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		turns = args.Get(3).(int)
+	}).Return(&PairSessionResult{
+		Execution: ExecutionResult{Agent: "claude", Tokens: TokenUsage{InputTokens: 1000, CostUSD: 0.03}},
+		Review:    ReviewResult{Approved: true, ReviewerAgent: "codex", Tokens: TokenUsage{InputTokens: 400, CostUSD: 0.01}},
+		Approved:  true,
+		Turns:     []PairTurn{{Turn: 1, Role: "coder"}, {Turn: 2, Role: "reviewer"}},
+	}, nil)
+	var outcome OutcomeRecord
+	env.OnActivity(a.RecordOutcomeActivity, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		outcome = args.Get(1).(OutcomeRecord)
+	}).Return(nil)
+	stubActivities(env)
+	env.OnWorkflow(ContinuousLearnerWorkflow, mock.Anything, mock.Anything).Return(nil)
+	env.OnWorkflow(TacticalGroomWorkflow, mock.Anything, mock.Anything).Return(nil)
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow("human-approval", "APPROVED")
+	}, 0)
+
+	env.ExecuteWorkflow(CortexAgentWorkflow, TaskRequest{
+		BeadID:  "bead-pair",
+		Project: "test-project",
+		Prompt:  "add a widget endpoint",
+		Agent:   "claude",
+		WorkDir: "/tmp/test",
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Equal(t, 4, turns)
+	env.AssertActivityNumberOfCalls(t, "ExecuteActivity", 0)
+	env.AssertActivityNumberOfCalls(t, "CodeReviewActivity", 0)
+	require.Equal(t, "pair", outcome.Mode)
+	require.Equal(t, "claude-max20", outcome.Provider)
+	require.Equal(t, 75+1000+400, outcome.TotalTokens.InputTokens)
+}
@@ -0,0 +1,34 @@
+package temporal
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// BacklogTriageWorkflow labels duplicate and stale beads across projects.
+// Runs on a cron schedule; failures are logged and retried on the next run.
+func BacklogTriageWorkflow(ctx workflow.Context, req TriageRequest) (*TriageResult, error) {
+	logger := workflow.GetLogger(ctx)
+
+	actCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 10 * time.Minute,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 1},
+	})
+
+	var a *Activities
+	var result TriageResult
+	if err := workflow.ExecuteActivity(actCtx, a.BacklogTriageActivity, req).Get(ctx, &result); err != nil {
+		logger.Warn("BacklogTriage: triage failed", "error", err)
+		return nil, err
+	}
+
+	duplicates, stale := 0, 0
+	for _, r := range result.Projects {
+		duplicates += len(r.Duplicates)
+		stale += len(r.Stale)
+	}
+	logger.Info("BacklogTriage complete", "Projects", len(result.Projects), "Duplicates", duplicates, "Stale", stale)
+	return &result, nil
+}
@@ -0,0 +1,69 @@
+package temporal
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// ProviderWarmupWorkflow pings provider CLIs so cold-start latency (auth
+// refresh, model load) is paid outside real dispatches. Started once at
+// cortex startup and on a cron schedule for idle providers.
+//
+// All steps are non-fatal. Warmup failure never blocks dispatching.
+func ProviderWarmupWorkflow(ctx workflow.Context, req WarmupRequest) error {
+	logger := workflow.GetLogger(ctx)
+
+	if req.Reason == "" {
+		req.Reason = "idle"
+	}
+	if req.Timeout <= 0 {
+		req.Timeout = 10 * time.Minute
+	}
+
+	var a *Activities
+
+	checkCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Second,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 2},
+	})
+	var cold []string
+	if err := workflow.ExecuteActivity(checkCtx, a.ColdProvidersActivity, req).Get(ctx, &cold); err != nil {
+		logger.Warn("Warmup: cold provider check failed (non-fatal)", "error", err)
+		return nil
+	}
+	if len(cold) == 0 {
+		return nil
+	}
+
+	pingCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: req.Timeout,
+		HeartbeatTimeout:    30 * time.Second,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 1},
+	})
+
+	futures := make([]workflow.Future, 0, len(cold))
+	for _, provider := range cold {
+		cli := req.CLIs[provider]
+		if cli == "" {
+			cli = provider
+		}
+		futures = append(futures, workflow.ExecuteActivity(pingCtx, a.WarmupProviderActivity, provider, cli, req.Reason))
+	}
+
+	warmed := 0
+	for i, fut := range futures {
+		var result WarmupResult
+		if err := fut.Get(ctx, &result); err != nil {
+			logger.Warn("Warmup: ping activity failed (non-fatal)", "Provider", cold[i], "error", err)
+			continue
+		}
+		if result.Success {
+			warmed++
+		}
+	}
+
+	logger.Info("ProviderWarmup complete", "Reason", req.Reason, "Pinged", len(cold), "Warmed", warmed)
+	return nil
+}
@@ -0,0 +1,52 @@
+package temporal
+
+import (
+	"context"
+	"errors"
+
+	"go.temporal.io/sdk/activity"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+	"github.com/antigravity-dev/cortex/internal/dispatch"
+)
+
+// AcquireWorktreeActivity checks the task's feature branch out into a pooled
+// worktree of its project. It returns nil, leaving the task in the shared
+// workspace, when the pool is disabled, the project does not use feature
+// branches (its agents commit straight to the base branch, which a second
+// checkout cannot share) or every worktree of the project is in use.
+func (a *Activities) AcquireWorktreeActivity(ctx context.Context, req TaskRequest) (*dispatch.WorktreeLease, error) {
+	logger := activity.GetLogger(ctx)
+	if a.Worktrees == nil {
+		return nil, nil
+	}
+	proj, ok := a.Projects[req.Project]
+	if !ok || !proj.UseBranches || req.WorkDir == "" {
+		return nil, nil
+	}
+
+	lease, err := a.Worktrees.Acquire(req.Project, config.ExpandHome(req.WorkDir), req.BeadID, proj.BranchPrefix+req.BeadID, proj.BaseBranch)
+	if errors.Is(err, dispatch.ErrWorktreePoolFull) {
+		logger.Warn("Worktree pool full, using the shared workspace", "Project", req.Project, "BeadID", req.BeadID)
+		a.journalSkip(ctx, "worktree", req, err.Error())
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Worktree acquired", "BeadID", req.BeadID, "Path", lease.Path, "Branch", lease.Branch)
+	return lease, nil
+}
+
+// ReleaseWorktreeActivity reclaims a worktree once its task has finished.
+// Work the agent committed stays on the branch; anything uncommitted is
+// discarded.
+func (a *Activities) ReleaseWorktreeActivity(ctx context.Context, lease dispatch.WorktreeLease) error {
+	if a.Worktrees == nil {
+		return nil
+	}
+	if err := a.Worktrees.Release(lease); err != nil {
+		activity.GetLogger(ctx).Warn("Worktree reset failed; it is reset again on next use", "Path", lease.Path, "error", err)
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+// Package webhook posts reporter messages to arbitrary HTTP endpoints as
+// signed JSON, for integrations such as Zapier or internal bots.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+// SignatureHeader carries "sha256=<hex HMAC of the body>" when the hook has a secret.
+const SignatureHeader = "X-Cortex-Signature"
+
+// MessageSender is the reporter interface shared with the Matrix and email senders.
+type MessageSender interface {
+	SendMessage(ctx context.Context, room, message string) error
+}
+
+// Event is the data a webhook template renders.
+type Event struct {
+	Project   string    `json:"project,omitempty"` // empty when the room is shared by several projects
+	Room      string    `json:"room"`
+	Message   string    `json:"message"`
+	Severity  string    `json:"severity,omitempty"` // set for routed health events
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type hook struct {
+	cfg  config.ReporterWebhook
+	tmpl *template.Template // nil sends Event as JSON
+}
+
+// Sender posts every message to the configured webhooks.
+type Sender struct {
+	hooks    []hook
+	projects map[string]string // room -> project, for rooms used by one project
+	client   *http.Client
+	now      func() time.Time
+}
+
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		out, err := json.Marshal(v)
+		return string(out), err
+	},
+}
+
+// NewSender builds a sender for the reporter's webhooks. It returns nil when
+// none are configured.
+func NewSender(cfg *config.Config) (*Sender, error) {
+	if len(cfg.Reporter.Webhooks) == 0 {
+		return nil, nil
+	}
+	s := &Sender{
+		projects: roomProjects(cfg),
+		client:   &http.Client{},
+		now:      time.Now,
+	}
+	for i, hc := range cfg.Reporter.Webhooks {
+		h := hook{cfg: hc}
+		if strings.TrimSpace(hc.Template) != "" {
+			tmpl, err := template.New(fmt.Sprintf("webhook-%d", i)).Funcs(templateFuncs).Parse(hc.Template)
+			if err != nil {
+				return nil, fmt.Errorf("webhook %s: parse template: %w", hc.URL, err)
+			}
+			h.tmpl = tmpl
+		}
+		s.hooks = append(s.hooks, h)
+	}
+	return s, nil
+}
+
+// roomProjects maps each reporting room to its project when exactly one
+// enabled project reports there.
+func roomProjects(cfg *config.Config) map[string]string {
+	byRoom := make(map[string][]string)
+	for name, project := range cfg.Projects {
+		if !project.Enabled {
+			continue
+		}
+		if room := cfg.ResolveRoom(name); room != "" {
+			byRoom[room] = append(byRoom[room], name)
+		}
+	}
+	out := make(map[string]string, len(byRoom))
+	for room, names := range byRoom {
+		if len(names) == 1 {
+			out[room] = names[0]
+		}
+	}
+	return out
+}
+
+// SendMessage posts the message to every hook that accepts its project.
+// Failures of individual hooks are joined into the returned error.
+func (s *Sender) SendMessage(ctx context.Context, room, message string) error {
+	return s.SendEvent(ctx, Event{
+		Project: s.projects[room],
+		Room:    room,
+		Message: message,
+	})
+}
+
+// SendEvent posts event to every hook that accepts its project, stamping it
+// with the current time when it has none.
+func (s *Sender) SendEvent(ctx context.Context, event Event) error {
+	event.Message = strings.TrimSpace(event.Message)
+	if event.Timestamp.IsZero() {
+		event.Timestamp = s.now().UTC()
+	}
+	var errs []error
+	for _, h := range s.hooks {
+		if len(h.cfg.Projects) > 0 && !slices.Contains(h.cfg.Projects, event.Project) {
+			continue
+		}
+		if err := s.post(ctx, h, event); err != nil {
+			errs = append(errs, fmt.Errorf("webhook %s: %w", h.cfg.URL, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Sender) post(ctx context.Context, h hook, event Event) error {
+	var body []byte
+	if h.tmpl == nil {
+		var err error
+		if body, err = json.Marshal(event); err != nil {
+			return fmt.Errorf("encode payload: %w", err)
+		}
+	} else {
+		var buf bytes.Buffer
+		if err := h.tmpl.Execute(&buf, event); err != nil {
+			return fmt.Errorf("render template: %w", err)
+		}
+		body = buf.Bytes()
+	}
+
+	if h.cfg.Timeout.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.cfg.Timeout.Duration)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.cfg.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(h.cfg.Secret, body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		out, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Sign returns the signature header value for body: "sha256=" followed by
+// the hex HMAC-SHA256 of body keyed with secret. Receivers recompute it to
+// verify a request came from Cortex.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Tee delivers to primary and then to the webhooks. A webhook failure never
+// stops primary delivery; both errors are returned joined.
+func Tee(primary MessageSender, hooks *Sender) MessageSender {
+	if hooks == nil {
+		return primary
+	}
+	return tee{primary: primary, hooks: hooks}
+}
+
+type tee struct {
+	primary MessageSender
+	hooks   *Sender
+}
+
+func (t tee) SendMessage(ctx context.Context, room, message string) error {
+	var errs []error
+	if t.primary != nil {
+		errs = append(errs, t.primary.SendMessage(ctx, room, message))
+	}
+	errs = append(errs, t.hooks.SendMessage(ctx, room, message))
+	return errors.Join(errs...)
+}
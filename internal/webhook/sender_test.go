@@ -0,0 +1,141 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/antigravity-dev/cortex/internal/config"
+)
+
+type received struct {
+	path      string
+	body      string
+	signature string
+}
+
+func testServer(t *testing.T, status int) (*httptest.Server, *[]received) {
+	t.Helper()
+	var (
+		mu   sync.Mutex
+		reqs []received
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		reqs = append(reqs, received{path: r.URL.Path, body: string(body), signature: r.Header.Get(SignatureHeader)})
+		mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &reqs
+}
+
+func testConfig(hooks ...config.ReporterWebhook) *config.Config {
+	return &config.Config{
+		Projects: map[string]config.Project{
+			"alpha": {Enabled: true, MatrixRoom: "!alpha"},
+			"beta":  {Enabled: true},
+			"gamma": {Enabled: true},
+		},
+		Reporter: config.Reporter{DefaultRoom: "!shared", Webhooks: hooks},
+	}
+}
+
+func TestSendMessageDefaultPayloadSigned(t *testing.T) {
+	srv, reqs := testServer(t, http.StatusOK)
+	s, err := NewSender(testConfig(config.ReporterWebhook{URL: srv.URL + "/hook", Secret: "s3cret"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.now = func() time.Time { return time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC) }
+
+	if err := s.SendMessage(context.Background(), "!alpha", " SLA breached "); err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	if len(*reqs) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(*reqs))
+	}
+	got := (*reqs)[0]
+	var event Event
+	if err := json.Unmarshal([]byte(got.body), &event); err != nil {
+		t.Fatalf("payload is not JSON: %v: %s", err, got.body)
+	}
+	if event.Project != "alpha" || event.Room != "!alpha" || event.Message != "SLA breached" || !event.Timestamp.Equal(s.now()) {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+	if got.signature != Sign("s3cret", []byte(got.body)) || !strings.HasPrefix(got.signature, "sha256=") {
+		t.Fatalf("unexpected signature %q", got.signature)
+	}
+
+	// The shared default room belongs to no single project.
+	if err := s.SendMessage(context.Background(), "!shared", "digest"); err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	if strings.Contains((*reqs)[1].body, `"project"`) {
+		t.Fatalf("expected no project for a shared room: %s", (*reqs)[1].body)
+	}
+}
+
+func TestSendMessageTemplateAndProjectFilter(t *testing.T) {
+	srv, reqs := testServer(t, http.StatusOK)
+	s, err := NewSender(testConfig(config.ReporterWebhook{
+		URL:      srv.URL,
+		Template: `{"text": {{json (printf "%s: %s" .Project .Message)}}}`,
+		Projects: []string{"alpha"},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.SendMessage(context.Background(), "!shared", "ignored"); err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	if err := s.SendMessage(context.Background(), "!alpha", `bead "a-1" closed`); err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	if len(*reqs) != 1 {
+		t.Fatalf("expected only the alpha report to be posted, got %d", len(*reqs))
+	}
+	if (*reqs)[0].body != `{"text": "alpha: bead \"a-1\" closed"}` || (*reqs)[0].signature != "" {
+		t.Fatalf("unexpected request: %+v", (*reqs)[0])
+	}
+}
+
+type recordingSender struct {
+	messages []string
+	err      error
+}
+
+func (r *recordingSender) SendMessage(_ context.Context, _ string, message string) error {
+	r.messages = append(r.messages, message)
+	return r.err
+}
+
+func TestTee(t *testing.T) {
+	srv, reqs := testServer(t, http.StatusBadGateway)
+	hooks, err := NewSender(testConfig(config.ReporterWebhook{URL: srv.URL}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	primary := &recordingSender{err: errors.New("matrix down")}
+
+	err = Tee(primary, hooks).SendMessage(context.Background(), "!alpha", "report")
+	if err == nil || !strings.Contains(err.Error(), "matrix down") || !strings.Contains(err.Error(), "status 502") {
+		t.Fatalf("expected both failures, got %v", err)
+	}
+	if len(primary.messages) != 1 || len(*reqs) != 1 {
+		t.Fatalf("expected delivery to both, got %d primary and %d webhook", len(primary.messages), len(*reqs))
+	}
+
+	if got := Tee(primary, nil); got != MessageSender(primary) {
+		t.Fatal("expected Tee without webhooks to return the primary sender")
+	}
+}
@@ -0,0 +1,79 @@
+#!/usr/bin/env bash
+set -euo pipefail
+
+VERSION="${VERSION:-$(cat VERSION 2>/dev/null || echo dev)}"
+COMMIT="${COMMIT:-$(git rev-parse --short HEAD 2>/dev/null || echo unknown)}"
+BUILD_TIME="${BUILD_TIME:-$(date -u +%Y-%m-%dT%H:%M:%SZ)}"
+DIST_DIR="${DIST_DIR:-build/dist}"
+RELEASE_PLATFORMS="${RELEASE_PLATFORMS:-linux/amd64 linux/arm64 darwin/amd64 darwin/arm64}"
+RELEASE_BINARIES="${RELEASE_BINARIES:-cortex cortexctl db-backup db-restore}"
+
+usage() {
+  cat <<'USAGE'
+Usage: scripts/release/build-artifacts.sh
+
+Cross-compiles release binaries for every platform in RELEASE_PLATFORMS and
+packages them as <name>_<version>_<os>_<arch>.tar.gz archives under DIST_DIR,
+with a SHA256SUMS file. Dashboards and DoD presets are embedded in the
+binaries (internal/assets), so archives need no extra files.
+
+Environment:
+  VERSION, COMMIT, BUILD_TIME   build metadata injected via -ldflags
+  DIST_DIR                      output directory (default build/dist)
+  RELEASE_PLATFORMS             space-separated os/arch list
+  RELEASE_BINARIES              space-separated cmd/<name> entries to build
+USAGE
+}
+
+log() {
+  printf '[build-artifacts] %s\n' "$*"
+}
+
+die() {
+  printf 'ERROR: %s\n' "$*" >&2
+  exit 1
+}
+
+if [[ "${1:-}" == "-h" || "${1:-}" == "--help" ]]; then
+  usage
+  exit 0
+fi
+
+for bin in $RELEASE_BINARIES; do
+  [[ -d "cmd/$bin" ]] || die "cmd/$bin does not exist"
+done
+
+rm -rf "$DIST_DIR"
+mkdir -p "$DIST_DIR"
+
+ldflags="-s -w -X main.version=${VERSION} -X main.commit=${COMMIT} -X main.buildTime=${BUILD_TIME}"
+
+for platform in $RELEASE_PLATFORMS; do
+  goos="${platform%/*}"
+  goarch="${platform#*/}"
+  stage="$DIST_DIR/stage/${goos}_${goarch}"
+  mkdir -p "$stage"
+
+  for bin in $RELEASE_BINARIES; do
+    log "building $bin for $goos/$goarch"
+    CGO_ENABLED=0 GOOS="$goos" GOARCH="$goarch" \
+      go build -trimpath -ldflags "$ldflags" -o "$stage/$bin" "./cmd/$bin/"
+  done
+
+  cp LICENSE README.md "$stage/"
+  archive="cortex_${VERSION}_${goos}_${goarch}.tar.gz"
+  tar -C "$stage" -czf "$DIST_DIR/$archive" .
+  log "wrote $DIST_DIR/$archive"
+done
+
+rm -rf "$DIST_DIR/stage"
+
+(
+  cd "$DIST_DIR"
+  if command -v sha256sum >/dev/null 2>&1; then
+    sha256sum ./*.tar.gz > SHA256SUMS
+  else
+    shasum -a 256 ./*.tar.gz > SHA256SUMS
+  fi
+)
+log "checksums written to $DIST_DIR/SHA256SUMS"